@@ -39,6 +39,7 @@ import (
 
 	frontproxyoptions "github.com/kcp-dev/kcp/cmd/kcp-front-proxy/options"
 	"github.com/kcp-dev/kcp/pkg/proxy"
+	"github.com/kcp-dev/kcp/pkg/server/shutdown"
 )
 
 func main() {
@@ -72,6 +73,10 @@ routed based on paths.`,
 			if err := options.Logs.ValidateAndApply(); err != nil {
 				return err
 			}
+			// write certificates from secrets before they are loaded
+			if err := options.CertificateSecrets.Sync(ctx); err != nil {
+				return err
+			}
 			if err := options.Complete(); err != nil {
 				return err
 			}
@@ -80,7 +85,7 @@ routed based on paths.`,
 			}
 
 			var handler http.Handler
-			handler, err := proxy.NewHandler(&options.Proxy)
+			handler, err := proxy.NewHandler(ctx, &options.Proxy)
 			if err != nil {
 				return err
 			}
@@ -96,14 +101,44 @@ routed based on paths.`,
 				return err
 			}
 
+			handler = proxy.WithWorkspaceScope(handler)
+
+			// the control plane API checks workspace scopes itself, its requests are not for paths under /clusters/
+			if options.Proxy.EnableControlPlaneAPI {
+				api, err := proxy.NewControlPlaneAPI(&options.Proxy)
+				if err != nil {
+					return err
+				}
+				handler = proxy.WithControlPlaneAPI(handler, api)
+			}
+
+			externalTokenAuth, tokenIssuer, err := options.Authentication.NewTokenExchange()
+			if err != nil {
+				return err
+			}
+			if tokenIssuer != nil {
+				handler = proxy.WithTokenExchange(handler, externalTokenAuth, tokenIssuer)
+			}
+
 			failedHandler := newUnauthorizedHandler()
 			handler = withOptionalClientCert(handler, failedHandler, authenticationInfo.Authenticator)
 
+			watchTerminator := shutdown.NewWatchTerminator()
+			handler = watchTerminator.WithWatchTermination(handler)
+
 			requestInfoFactory := newRequestInfoFactory()
 			handler = genericapifilters.WithRequestInfo(handler, requestInfoFactory)
 			handler = genericfilters.WithPanicRecovery(handler, requestInfoFactory)
 
-			doneCh, err := servingInfo.Serve(handler, time.Second*60, ctx.Done())
+			// terminate watches gradually before closing the listener
+			stopCh := make(chan struct{})
+			go func() {
+				<-ctx.Done()
+				watchTerminator.Terminate(options.ShutdownDelayDuration)
+				close(stopCh)
+			}()
+
+			doneCh, err := servingInfo.Serve(handler, time.Second*60, stopCh)
 			if err != nil {
 				return err
 			}
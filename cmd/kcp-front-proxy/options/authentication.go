@@ -18,23 +18,76 @@ package options
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/spf13/pflag"
 
+	"k8s.io/apiserver/pkg/authentication/authenticator"
 	"k8s.io/apiserver/pkg/authentication/request/x509"
 	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/apiserver/pkg/server/dynamiccertificates"
 	apiserveroptions "k8s.io/apiserver/pkg/server/options"
+	"k8s.io/apiserver/plugin/pkg/authenticator/token/oidc"
+	"k8s.io/client-go/util/keyutil"
+
+	"github.com/kcp-dev/kcp/pkg/authentication/exchange"
+	"github.com/kcp-dev/kcp/pkg/proxy"
+)
+
+const (
+	// BearerTokenModePassthrough forwards bearer tokens to the backends unchanged.
+	BearerTokenModePassthrough = "passthrough"
+	// BearerTokenModeResign validates bearer tokens of the OIDC issuer and replaces them
+	// with internal tokens signed by the front-proxy.
+	BearerTokenModeResign = "resign"
 )
 
 // Authentication wraps ClientCertAuthenticationOptions so we don't pull in
 // more auth machinery than we need with DelegatingAuthenticationOptions
 type Authentication struct {
 	ClientCert apiserveroptions.ClientCertAuthenticationOptions
+
+	// WorkspaceScopes restricts client certificates with workspace Organizational
+	// Units or URI SANs to these workspaces.
+	WorkspaceScopes bool
+
+	// BearerTokenMode is either "passthrough" or "resign".
+	BearerTokenMode string
+	OIDC            OIDC
+	TokenExchange   TokenExchange
+}
+
+// OIDC configures the identity provider whose tokens are exchanged in the resign mode.
+type OIDC struct {
+	IssuerURL      string
+	ClientID       string
+	CAFile         string
+	UsernameClaim  string
+	UsernamePrefix string
+	GroupsClaim    string
+	GroupsPrefix   string
+}
+
+// TokenExchange configures the internal tokens issued in the resign mode.
+type TokenExchange struct {
+	Issuer         string
+	SigningKeyFile string
+	Audiences      []string
+	Expiration     time.Duration
 }
 
 // NewAuthentication creates a default Authentication
 func NewAuthentication() *Authentication {
-	return &Authentication{}
+	return &Authentication{
+		BearerTokenMode: BearerTokenModePassthrough,
+		OIDC: OIDC{
+			UsernameClaim: "sub",
+		},
+		TokenExchange: TokenExchange{
+			Audiences:  []string{"kcp"},
+			Expiration: 5 * time.Minute,
+		},
+	}
 }
 
 // ApplyTo sets up the x509 Authenticator if the client-ca-file option was passed
@@ -47,7 +100,11 @@ func (c *Authentication) ApplyTo(authenticationInfo *genericapiserver.Authentica
 		if err = authenticationInfo.ApplyClientCert(clientCAProvider, servingInfo); err != nil {
 			return fmt.Errorf("unable to assign client CA provider: %w", err)
 		}
-		authenticationInfo.Authenticator = x509.NewDynamic(clientCAProvider.VerifyOptions, x509.CommonNameUserConversion)
+		var userConversion x509.UserConversion = x509.CommonNameUserConversion
+		if c.WorkspaceScopes {
+			userConversion = proxy.WorkspaceScopeUserConversion
+		}
+		authenticationInfo.Authenticator = x509.NewDynamic(clientCAProvider.VerifyOptions, userConversion)
 	}
 	return nil
 }
@@ -55,9 +112,91 @@ func (c *Authentication) ApplyTo(authenticationInfo *genericapiserver.Authentica
 // AddFlags delegates to ClientCertAuthenticationOptions
 func (c *Authentication) AddFlags(fs *pflag.FlagSet) {
 	c.ClientCert.AddFlags(fs)
+
+	fs.BoolVar(&c.WorkspaceScopes, "client-cert-workspace-scopes", c.WorkspaceScopes, ""+
+		"Restrict client certificates with Organizational Units or URI SANs of the form workspace:<logical cluster> "+
+		"to requests to these workspaces and the workspaces below them.")
+
+	fs.StringVar(&c.BearerTokenMode, "bearer-token-mode", c.BearerTokenMode, ""+
+		"How bearer tokens are forwarded to the backends: 'passthrough' forwards them unchanged, 'resign' validates tokens "+
+		"of --oidc-issuer-url and replaces them with internal tokens of --token-exchange-issuer, scoped to the workspace of the request.")
+
+	fs.StringVar(&c.OIDC.IssuerURL, "oidc-issuer-url", c.OIDC.IssuerURL, "The URL of the OpenID issuer whose tokens are exchanged with --bearer-token-mode=resign. Only the https scheme is accepted.")
+	fs.StringVar(&c.OIDC.ClientID, "oidc-client-id", c.OIDC.ClientID, "The client ID for the OpenID Connect client, must be set if oidc-issuer-url is set.")
+	fs.StringVar(&c.OIDC.CAFile, "oidc-ca-file", c.OIDC.CAFile, "If set, the OpenID server's certificate is verified by one of the authorities in the file, otherwise the host's root CA set is used.")
+	fs.StringVar(&c.OIDC.UsernameClaim, "oidc-username-claim", c.OIDC.UsernameClaim, "The OpenID claim to use as the user name.")
+	fs.StringVar(&c.OIDC.UsernamePrefix, "oidc-username-prefix", c.OIDC.UsernamePrefix, "If provided, all usernames are prefixed with this value. If not provided, username claims other than 'email' are prefixed by the issuer URL. To skip any prefixing, provide the value '-'.")
+	fs.StringVar(&c.OIDC.GroupsClaim, "oidc-groups-claim", c.OIDC.GroupsClaim, "If provided, the name of a custom OpenID Connect claim for specifying user groups.")
+	fs.StringVar(&c.OIDC.GroupsPrefix, "oidc-groups-prefix", c.OIDC.GroupsPrefix, "If provided, all groups are prefixed with this value.")
+
+	fs.StringVar(&c.TokenExchange.Issuer, "token-exchange-issuer", c.TokenExchange.Issuer, "Issuer of the internal tokens issued with --bearer-token-mode=resign. Shards must be started with the same --token-exchange-issuer.")
+	fs.StringVar(&c.TokenExchange.SigningKeyFile, "token-exchange-signing-key-file", c.TokenExchange.SigningKeyFile, "File holding the PEM encoded RSA or ECDSA private key the internal tokens are signed with.")
+	fs.StringSliceVar(&c.TokenExchange.Audiences, "token-exchange-audiences", c.TokenExchange.Audiences, "Audiences of the internal tokens.")
+	fs.DurationVar(&c.TokenExchange.Expiration, "token-exchange-expiration", c.TokenExchange.Expiration, "Lifetime of the internal tokens. They are issued per request, i.e. only have to outlive the start of the request.")
 }
 
-// Validate just completes the options pattern. Returns nil.
+// Validate checks the bearer token mode and its options.
 func (c *Authentication) Validate() []error {
-	return nil
+	var errs []error
+
+	switch c.BearerTokenMode {
+	case BearerTokenModePassthrough:
+	case BearerTokenModeResign:
+		if c.OIDC.IssuerURL == "" || c.OIDC.ClientID == "" {
+			errs = append(errs, fmt.Errorf("--oidc-issuer-url and --oidc-client-id are required with --bearer-token-mode=%s", BearerTokenModeResign))
+		}
+		if c.TokenExchange.Issuer == "" || c.TokenExchange.SigningKeyFile == "" {
+			errs = append(errs, fmt.Errorf("--token-exchange-issuer and --token-exchange-signing-key-file are required with --bearer-token-mode=%s", BearerTokenModeResign))
+		}
+		if len(c.TokenExchange.Audiences) == 0 {
+			errs = append(errs, fmt.Errorf("--token-exchange-audiences must not be empty"))
+		}
+		if c.TokenExchange.Expiration < time.Minute {
+			errs = append(errs, fmt.Errorf("--token-exchange-expiration must be at least 1m"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("--bearer-token-mode must be %q or %q", BearerTokenModePassthrough, BearerTokenModeResign))
+	}
+
+	return errs
+}
+
+// NewTokenExchange returns the authenticator of external tokens and the issuer of internal
+// tokens for the resign mode, or nils for the passthrough mode.
+func (c *Authentication) NewTokenExchange() (authenticator.Token, *exchange.Issuer, error) {
+	if c.BearerTokenMode != BearerTokenModeResign {
+		return nil, nil, nil
+	}
+
+	opts := oidc.Options{
+		IssuerURL:            c.OIDC.IssuerURL,
+		ClientID:             c.OIDC.ClientID,
+		UsernameClaim:        c.OIDC.UsernameClaim,
+		UsernamePrefix:       c.OIDC.UsernamePrefix,
+		GroupsClaim:          c.OIDC.GroupsClaim,
+		GroupsPrefix:         c.OIDC.GroupsPrefix,
+		SupportedSigningAlgs: []string{"RS256"},
+	}
+	if c.OIDC.CAFile != "" {
+		caContent, err := dynamiccertificates.NewDynamicCAContentFromFile("oidc-authenticator", c.OIDC.CAFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to load --oidc-ca-file: %w", err)
+		}
+		opts.CAContentProvider = caContent
+	}
+	external, err := oidc.New(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	signingKey, err := keyutil.PrivateKeyFromFile(c.TokenExchange.SigningKeyFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to load --token-exchange-signing-key-file: %w", err)
+	}
+	issuer, err := exchange.NewIssuer(c.TokenExchange.Issuer, c.TokenExchange.Audiences, signingKey, c.TokenExchange.Expiration)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return external, issuer, nil
 }
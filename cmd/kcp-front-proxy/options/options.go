@@ -17,8 +17,10 @@ limitations under the License.
 package options
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/spf13/pflag"
 
@@ -26,6 +28,7 @@ import (
 	"k8s.io/component-base/config"
 	"k8s.io/component-base/logs"
 
+	certsoptions "github.com/kcp-dev/kcp/pkg/certs/options"
 	proxyoptions "github.com/kcp-dev/kcp/pkg/proxy/options"
 )
 
@@ -35,7 +38,11 @@ type Options struct {
 	Proxy          proxyoptions.Options
 	Logs           *logs.Options
 
+	CertificateSecrets certsoptions.CertificateSecrets
+
 	RootDirectory string
+
+	ShutdownDelayDuration time.Duration
 }
 
 func NewOptions() *Options {
@@ -45,6 +52,8 @@ func NewOptions() *Options {
 		Proxy:          *proxyoptions.NewOptions(),
 		Logs:           logs.NewOptions(),
 
+		CertificateSecrets: *certsoptions.NewCertificateSecrets(),
+
 		RootDirectory: ".kcp",
 	}
 
@@ -64,8 +73,11 @@ func (o *Options) AddFlags(fs *pflag.FlagSet) {
 	o.Proxy.AddFlags(fs)
 
 	o.Logs.AddFlags(fs)
+	o.CertificateSecrets.AddFlags(fs)
 
 	fs.StringVar(&o.RootDirectory, "root-directory", o.RootDirectory, "Root directory.")
+	fs.DurationVar(&o.ShutdownDelayDuration, "shutdown-delay-duration", o.ShutdownDelayDuration, ""+
+		"Time over which watches are terminated gradually on shutdown, with a retriable error, before the proxy stops listening.")
 }
 
 func (o *Options) Complete() error {
@@ -101,6 +113,11 @@ func (o *Options) Validate() []error {
 	errs = append(errs, o.SecureServing.Validate()...)
 	errs = append(errs, o.Authentication.Validate()...)
 	errs = append(errs, o.Proxy.Validate()...)
+	errs = append(errs, o.CertificateSecrets.Validate()...)
+
+	if o.ShutdownDelayDuration < 0 {
+		errs = append(errs, fmt.Errorf("--shutdown-delay-duration must be non-negative"))
+	}
 
 	return errs
 }
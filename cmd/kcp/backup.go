@@ -0,0 +1,160 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/apimachinery/pkg/util/errors"
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/kcp-dev/kcp/pkg/backup"
+	backupoptions "github.com/kcp-dev/kcp/pkg/backup/options"
+	kcpclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
+	"github.com/kcp-dev/kcp/pkg/cmd/help"
+)
+
+func newBackupCommand() *cobra.Command {
+	o := backupoptions.NewOptions()
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Back up the etcd of all shards",
+		Long: help.Doc(`
+			Back up the etcd of all shards
+
+			Takes an etcd snapshot of every shard and stores it together with the
+			shard index in the backup directory or an S3 compatible bucket. The
+			snapshots are retaken if the shard index changes meanwhile.
+
+			With --interval, backups are repeated until the process is
+			terminated, and old backups beyond --retain are deleted.
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if errs := o.ValidateBackup(); len(errs) > 0 {
+				return errors.NewAggregate(errs)
+			}
+
+			shards, err := o.BackupShards()
+			if err != nil {
+				return err
+			}
+			kcpClusterClient, err := newKcpClusterClient(o.Kubeconfig)
+			if err != nil {
+				return err
+			}
+			store, err := o.Store()
+			if err != nil {
+				return err
+			}
+
+			c := backup.NewController(store, kcpClusterClient, shards, o.Interval, o.Retain)
+			ctx := genericapiserver.SetupSignalContext()
+			if o.Interval == 0 {
+				return c.RunOnce(ctx)
+			}
+			c.Start(ctx)
+			return nil
+		},
+	}
+	o.AddFlags(cmd.Flags())
+	o.AddBackupFlags(cmd.Flags())
+	return cmd
+}
+
+func newRestoreCommand() *cobra.Command {
+	o := backupoptions.NewOptions()
+	cmd := &cobra.Command{
+		Use:   "restore",
+		Short: "Restore a backup of all shards",
+		Long: help.Doc(`
+			Restore a backup of all shards
+
+			Restores the etcd snapshots of all shards of a backup into new etcd
+			data directories given with --etcd-data-directory, e.g. the embedded
+			etcd directories of stopped shards. Alternatively, --output-directory
+			downloads the snapshots, e.g. to restore them with "etcdutl snapshot
+			restore" into an external etcd cluster.
+
+			Shards can be renamed with --rename-shard. Once the restored shards
+			are running, run the command again with --kubeconfig of the root shard
+			to rewire the shard index to the new names.
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if errs := o.ValidateRestore(); len(errs) > 0 {
+				return errors.NewAggregate(errs)
+			}
+
+			ctx := genericapiserver.SetupSignalContext()
+			store, err := o.Store()
+			if err != nil {
+				return err
+			}
+
+			id := o.RestoreID
+			if id == "" {
+				ids, err := backup.ListBackups(ctx, store)
+				if err != nil {
+					return err
+				}
+				if len(ids) == 0 {
+					return fmt.Errorf("no complete backups found")
+				}
+				id = ids[len(ids)-1]
+			}
+
+			if o.RestoreDir != "" {
+				if _, err := backup.Restore(ctx, store, id, o.RestoreDir, o.RenameShards); err != nil {
+					return err
+				}
+			}
+
+			if len(o.EtcdDataDirectories) > 0 {
+				if _, err := backup.RestoreEtcd(ctx, store, id, o.EtcdDataDirectories, o.RenameShards, o.EtcdMember()); err != nil {
+					return err
+				}
+			}
+
+			if o.Kubeconfig != "" && o.RewireShards {
+				manifest, err := backup.GetManifest(ctx, store, id)
+				if err != nil {
+					return err
+				}
+				kcpClusterClient, err := newKcpClusterClient(o.Kubeconfig)
+				if err != nil {
+					return err
+				}
+				return backup.Rewire(ctx, kcpClusterClient, manifest, o.RenameShards)
+			}
+
+			return nil
+		},
+	}
+	o.AddFlags(cmd.Flags())
+	o.AddRestoreFlags(cmd.Flags())
+	return cmd
+}
+
+func newKcpClusterClient(kubeconfig string) (kcpclient.ClusterInterface, error) {
+	config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	return kcpclient.NewClusterForConfig(config)
+}
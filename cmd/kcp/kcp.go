@@ -88,6 +88,13 @@ func main() {
 				return err
 			}
 
+			ctx := genericapiserver.SetupSignalContext()
+
+			// write certificates from secrets before they are loaded
+			if err := serverOptions.CertificateSecrets.Sync(ctx); err != nil {
+				return err
+			}
+
 			completed, err := serverOptions.Complete()
 			if err != nil {
 				return err
@@ -102,7 +109,7 @@ func main() {
 				return err
 			}
 
-			return s.Run(genericapiserver.SetupSignalContext())
+			return s.Run(ctx)
 		},
 	}
 
@@ -136,6 +143,9 @@ func main() {
 	}
 	startCmd.AddCommand(startOptionsCmd)
 	cmd.AddCommand(startCmd)
+	cmd.AddCommand(newBackupCommand())
+	cmd.AddCommand(newRestoreCommand())
+	cmd.AddCommand(newPerfCommand())
 
 	setPartialUsageAndHelpFunc(startCmd, namedStartFlagSets, cols, []string{
 		"etcd-servers",
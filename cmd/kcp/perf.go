@@ -0,0 +1,106 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/spf13/cobra"
+
+	"k8s.io/apimachinery/pkg/util/errors"
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	kcpclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
+	"github.com/kcp-dev/kcp/pkg/cliplugins/helpers"
+	"github.com/kcp-dev/kcp/pkg/cmd/help"
+	"github.com/kcp-dev/kcp/pkg/perf"
+	perfoptions "github.com/kcp-dev/kcp/pkg/perf/options"
+)
+
+func newPerfCommand() *cobra.Command {
+	o := perfoptions.NewOptions()
+	cmd := &cobra.Command{
+		Use:   "perf",
+		Short: "Load test workspaces, bindings and objects",
+		Long: help.Doc(`
+			Load test workspaces, bindings and objects
+
+			Creates --workspaces workspaces, --bindings APIBindings to the
+			--api-export APIExports in them, and --objects ConfigMaps spread
+			over them. Then it updates random objects for --churn-duration and
+			lists the objects of every workspace --list-iterations times.
+
+			Reports the end-to-end latencies: until workspaces are Ready, until
+			bindings are Bound, of creating, updating and listing objects. The
+			workspaces are deleted at the end unless --keep is given.
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if errs := o.Validate(); len(errs) > 0 {
+				return errors.NewAggregate(errs)
+			}
+
+			config, err := clientcmd.BuildConfigFromFlags("", o.Kubeconfig)
+			if err != nil {
+				return err
+			}
+			u, current, err := helpers.ParseClusterURL(config.Host)
+			if err != nil {
+				return err
+			}
+			parent := current
+			if o.Parent != "" {
+				parent = logicalcluster.New(o.Parent)
+			}
+			config = rest.CopyConfig(config)
+			config.Host = u.String()
+			// the load test is limited by --concurrency, not by client-side throttling.
+			config.QPS = -1
+
+			kcpClusterClient, err := kcpclient.NewClusterForConfig(config)
+			if err != nil {
+				return err
+			}
+			kubeClusterClient, err := kubernetes.NewClusterForConfig(config)
+			if err != nil {
+				return err
+			}
+
+			ctx := genericapiserver.SetupSignalContext()
+			report, err := perf.NewRunner(o, parent, kcpClusterClient, kubeClusterClient).Run(ctx)
+			if report == nil {
+				return err
+			}
+			if o.Output == "json" {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(report); err != nil {
+					return err
+				}
+			} else if err := report.WriteText(os.Stdout); err != nil {
+				return err
+			}
+			return err
+		},
+	}
+	o.AddFlags(cmd.Flags())
+	return cmd
+}
@@ -18,12 +18,14 @@ package cmd
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/kcp-dev/logicalcluster"
 	"github.com/spf13/cobra"
 
 	"k8s.io/apimachinery/pkg/util/sets"
 	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog/v2"
 
@@ -64,6 +66,47 @@ func NewSyncerCommand() *cobra.Command {
 }
 
 func Run(options *synceroptions.Options, ctx context.Context) error {
+	// a simulated cluster has no kubeconfig.
+	var toConfig *rest.Config
+	if !options.Simulate {
+		var err error
+		toConfig, err = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+			&clientcmd.ClientConfigLoadingRules{ExplicitPath: options.ToKubeconfig},
+			&clientcmd.ConfigOverrides{
+				CurrentContext: options.ToContext,
+			}).ClientConfig()
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(options.Targets) > 0 {
+		var cfgs []*syncer.SyncerConfig
+		for _, target := range options.Targets {
+			kcpConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+				&clientcmd.ClientConfigLoadingRules{ExplicitPath: target.FromKubeconfig},
+				&clientcmd.ConfigOverrides{
+					CurrentContext: target.FromContext,
+				}).ClientConfig()
+			if err != nil {
+				return fmt.Errorf("failed to load kubeconfig of WorkloadCluster %s|%s: %w", target.FromCluster, target.WorkloadClusterName, err)
+			}
+			klog.Infof("Syncing the following resource types to WorkloadCluster %s|%s: %s", target.FromCluster, target.WorkloadClusterName, target.Resources)
+			cfgs = append(cfgs, &syncer.SyncerConfig{
+				UpstreamConfig:      kcpConfig,
+				DownstreamConfig:    toConfig,
+				ResourcesToSync:     sets.NewString(target.Resources...),
+				KCPClusterName:      logicalcluster.New(target.FromCluster),
+				WorkloadClusterName: target.WorkloadClusterName,
+				JournalDir:          options.JournalDir,
+				WorkloadIdentity:    options.WorkloadIdentity,
+				Simulated:           options.Simulate,
+			})
+		}
+		syncer.StartSyncers(ctx, cfgs, numThreads, options.APIImportPollInterval)
+		return nil
+	}
+
 	klog.Infof("Syncing the following resource types: %s", options.SyncedResourceTypes)
 
 	kcpConfigOverrides := &clientcmd.ConfigOverrides{
@@ -76,15 +119,6 @@ func Run(options *synceroptions.Options, ctx context.Context) error {
 		return err
 	}
 
-	toConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
-		&clientcmd.ClientConfigLoadingRules{ExplicitPath: options.ToKubeconfig},
-		&clientcmd.ConfigOverrides{
-			CurrentContext: options.ToContext,
-		}).ClientConfig()
-	if err != nil {
-		return err
-	}
-
 	if err := syncer.StartSyncer(
 		ctx,
 		&syncer.SyncerConfig{
@@ -93,6 +127,9 @@ func Run(options *synceroptions.Options, ctx context.Context) error {
 			ResourcesToSync:     sets.NewString(options.SyncedResourceTypes...),
 			KCPClusterName:      logicalcluster.New(options.FromClusterName),
 			WorkloadClusterName: options.PclusterID,
+			JournalDir:          options.JournalDir,
+			WorkloadIdentity:    options.WorkloadIdentity,
+			Simulated:           options.Simulate,
 		},
 		numThreads,
 		options.APIImportPollInterval,
@@ -19,12 +19,14 @@ package options
 import (
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"time"
 
 	"github.com/spf13/pflag"
 
 	"k8s.io/component-base/config"
 	"k8s.io/component-base/logs"
+	"sigs.k8s.io/yaml"
 
 	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
 )
@@ -39,7 +41,43 @@ type Options struct {
 	Logs                *logs.Options
 	SyncedResourceTypes []string
 
+	// TargetsConfig is the path of a file with the WorkloadClusters to sync to.
+	TargetsConfig string
+	// Targets are loaded from TargetsConfig on Complete.
+	Targets []Target
+
 	APIImportPollInterval time.Duration
+
+	// JournalDir is the directory of the journals of status updates that could
+	// not be written while kcp was unreachable.
+	JournalDir string
+
+	// WorkloadIdentity mounts the workload identity tokens of ServiceAccounts into the pods
+	// of deployments.
+	WorkloadIdentity bool
+
+	// Simulate syncs to an in-memory cluster instead of the -to cluster.
+	Simulate bool
+}
+
+// TargetsConfiguration is the content of the --targets-config file.
+type TargetsConfiguration struct {
+	Targets []Target `json:"targets"`
+}
+
+// Target is a WorkloadCluster in a kcp workspace that a syncer syncs to, with the
+// credentials to reach it.
+type Target struct {
+	// FromKubeconfig is the kubeconfig file for kcp.
+	FromKubeconfig string `json:"fromKubeconfig"`
+	// FromContext is the context to use in the kubeconfig file, instead of the current context.
+	FromContext string `json:"fromContext,omitempty"`
+	// FromCluster is the logical cluster of the WorkloadCluster.
+	FromCluster string `json:"fromCluster"`
+	// WorkloadClusterName is the name of the WorkloadCluster.
+	WorkloadClusterName string `json:"workloadClusterName"`
+	// Resources to be synchronized. They default to --resources.
+	Resources []string `json:"resources,omitempty"`
 }
 
 func NewOptions() *Options {
@@ -63,16 +101,51 @@ func (options *Options) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&options.PclusterID, "workload-cluster-name", options.PclusterID,
 		fmt.Sprintf("ID of the -to cluster. Resources with this ID set in the '%s' label will be synced.", workloadv1alpha1.InternalClusterResourceStateLabelPrefix+"<ClusterID>"))
 	fs.StringArrayVarP(&options.SyncedResourceTypes, "resources", "r", options.SyncedResourceTypes, "Resources to be synchronized in kcp.")
+	fs.StringVar(&options.TargetsConfig, "targets-config", options.TargetsConfig, "Path to a file with several WorkloadClusters to sync to, each with its own kubeconfig. "+
+		"Mutually exclusive with --from-kubeconfig, --from-context, --from-cluster and --workload-cluster-name.")
 	fs.DurationVar(&options.APIImportPollInterval, "api-import-poll-interval", options.APIImportPollInterval, "Polling interval for API import.")
+	fs.StringVar(&options.JournalDir, "journal-dir", options.JournalDir, "Directory to journal status updates in while kcp is unreachable, to write them when it is reachable again, "+
+		"also across restarts. If empty, they are retried with backoff.")
+	fs.BoolVar(&options.WorkloadIdentity, "workload-identity", options.WorkloadIdentity, "Mount the workload identity token of the ServiceAccount of deployments into their pods at "+
+		"/var/run/secrets/kcp.dev/workload-identity. Requires the KCPWorkloadIdentity feature gate of kcp.")
+	fs.BoolVar(&options.Simulate, "simulate", options.Simulate, "Sync to an in-memory cluster instead of the -to cluster, for development. Synced deployments and statefulsets "+
+		"become available right away, but nothing runs. APIs are not imported, they must exist in kcp already. Mutually exclusive with --to-kubeconfig and --to-context.")
 
 	options.Logs.AddFlags(fs)
 }
 
 func (options *Options) Complete() error {
+	if options.TargetsConfig == "" {
+		return nil
+	}
+
+	bs, err := ioutil.ReadFile(options.TargetsConfig)
+	if err != nil {
+		return err
+	}
+	var cfg TargetsConfiguration
+	if err := yaml.UnmarshalStrict(bs, &cfg); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", options.TargetsConfig, err)
+	}
+	for i := range cfg.Targets {
+		if len(cfg.Targets[i].Resources) == 0 {
+			cfg.Targets[i].Resources = options.SyncedResourceTypes
+		}
+	}
+	options.Targets = cfg.Targets
+
 	return nil
 }
 
 func (options *Options) Validate() error {
+	if options.Simulate && (options.ToKubeconfig != "" || options.ToContext != "") {
+		return errors.New("--simulate is mutually exclusive with --to-kubeconfig and --to-context")
+	}
+
+	if options.TargetsConfig != "" {
+		return options.validateTargets()
+	}
+
 	if options.FromClusterName == "" {
 		return errors.New("--from-cluster is required")
 	}
@@ -82,3 +155,36 @@ func (options *Options) Validate() error {
 
 	return nil
 }
+
+func (options *Options) validateTargets() error {
+	if options.FromKubeconfig != "" || options.FromContext != "" || options.FromClusterName != "" || options.PclusterID != "" {
+		return errors.New("--targets-config is mutually exclusive with --from-kubeconfig, --from-context, --from-cluster and --workload-cluster-name")
+	}
+	if len(options.Targets) == 0 {
+		return fmt.Errorf("%s has no targets", options.TargetsConfig)
+	}
+
+	// downstream objects are labelled by the WorkloadCluster name only. Hence,
+	// the names must be unique for the syncer to tell the targets apart.
+	seen := map[string]int{}
+	for i, target := range options.Targets {
+		if target.FromCluster == "" {
+			return fmt.Errorf("targets[%d].fromCluster is required", i)
+		}
+		if target.FromKubeconfig == "" {
+			return fmt.Errorf("targets[%d].fromKubeconfig is required", i)
+		}
+		if target.WorkloadClusterName == "" {
+			return fmt.Errorf("targets[%d].workloadClusterName is required", i)
+		}
+		if j, found := seen[target.WorkloadClusterName]; found {
+			if options.Targets[j].FromCluster == target.FromCluster {
+				return fmt.Errorf("targets[%d] is a duplicate of targets[%d]", i, j)
+			}
+			return fmt.Errorf("targets[%d].workloadClusterName %q clashes with targets[%d] in %s", i, target.WorkloadClusterName, j, options.Targets[j].FromCluster)
+		}
+		seen[target.WorkloadClusterName] = i
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompleteTargets(t *testing.T) {
+	tests := map[string]struct {
+		config  string
+		want    []Target
+		wantErr bool
+	}{
+		"targets with default resources": {
+			config: `
+targets:
+- fromKubeconfig: east.kubeconfig
+  fromCluster: root:org:east
+  workloadClusterName: east
+  resources: [deployments.apps, services]
+- fromKubeconfig: west.kubeconfig
+  fromContext: admin
+  fromCluster: root:org:west
+  workloadClusterName: west
+`,
+			want: []Target{
+				{FromKubeconfig: "east.kubeconfig", FromCluster: "root:org:east", WorkloadClusterName: "east", Resources: []string{"deployments.apps", "services"}},
+				{FromKubeconfig: "west.kubeconfig", FromContext: "admin", FromCluster: "root:org:west", WorkloadClusterName: "west", Resources: []string{"configmaps"}},
+			},
+		},
+		"unknown field": {
+			config: `
+targets:
+- fromKubeconfig: east.kubeconfig
+  cluster: root:org:east
+`,
+			wantErr: true,
+		},
+		"invalid yaml": {
+			config:  "targets: [",
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "targets.yaml")
+			require.NoError(t, ioutil.WriteFile(path, []byte(tt.config), 0600))
+
+			o := NewOptions()
+			o.SyncedResourceTypes = []string{"configmaps"}
+			o.TargetsConfig = path
+			err := o.Complete()
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, o.Targets)
+		})
+	}
+}
+
+func TestCompleteTargetsMissingFile(t *testing.T) {
+	o := NewOptions()
+	o.TargetsConfig = filepath.Join(t.TempDir(), "missing.yaml")
+	require.Error(t, o.Complete())
+}
+
+func TestValidateTargets(t *testing.T) {
+	east := Target{FromKubeconfig: "east.kubeconfig", FromCluster: "root:org:east", WorkloadClusterName: "east"}
+	west := Target{FromKubeconfig: "west.kubeconfig", FromCluster: "root:org:west", WorkloadClusterName: "west"}
+
+	tests := map[string]struct {
+		modify  func(o *Options)
+		targets []Target
+		wantErr string
+	}{
+		"valid": {
+			targets: []Target{east, west},
+		},
+		"no targets": {
+			wantErr: "has no targets",
+		},
+		"missing kubeconfig": {
+			targets: []Target{east, {FromCluster: "root:org:west", WorkloadClusterName: "west"}},
+			wantErr: "targets[1].fromKubeconfig is required",
+		},
+		"missing cluster": {
+			targets: []Target{{FromKubeconfig: "east.kubeconfig", WorkloadClusterName: "east"}},
+			wantErr: "targets[0].fromCluster is required",
+		},
+		"missing name": {
+			targets: []Target{{FromKubeconfig: "east.kubeconfig", FromCluster: "root:org:east"}},
+			wantErr: "targets[0].workloadClusterName is required",
+		},
+		"duplicate targets": {
+			targets: []Target{east, west, east},
+			wantErr: "targets[2] is a duplicate of targets[0]",
+		},
+		"clashing names": {
+			targets: []Target{east, {FromKubeconfig: "other.kubeconfig", FromCluster: "root:other", WorkloadClusterName: "east"}},
+			wantErr: `targets[1].workloadClusterName "east" clashes with targets[0] in root:org:east`,
+		},
+		"single target flags": {
+			modify:  func(o *Options) { o.FromClusterName = "root:org:east" },
+			targets: []Target{east},
+			wantErr: "mutually exclusive",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			o := NewOptions()
+			o.TargetsConfig = "targets.yaml"
+			o.Targets = tt.targets
+			if tt.modify != nil {
+				tt.modify(o)
+			}
+			err := o.Validate()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package command
 
 import (
+	"context"
 	"io"
 	"io/ioutil"
 	"net/url"
@@ -25,7 +26,9 @@ import (
 	"github.com/kcp-dev/logicalcluster"
 	"github.com/spf13/cobra"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
@@ -42,6 +45,7 @@ import (
 	"github.com/kcp-dev/kcp/cmd/virtual-workspaces/options"
 	kcpclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
 	kcpinformer "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
+	virtualauthentication "github.com/kcp-dev/kcp/pkg/virtual/framework/authentication"
 	virtualrootapiserver "github.com/kcp-dev/kcp/pkg/virtual/framework/rootapiserver"
 )
 
@@ -74,6 +78,18 @@ func NewCommand(errout io.Writer, stopCh <-chan struct{}) *cobra.Command {
 
 // Run takes the options, starts the API server and waits until stopCh is closed or initial listening fails.
 func Run(o *options.Options, stopCh <-chan struct{}) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-stopCh
+		cancel()
+	}()
+
+	// write certificates from secrets before they are loaded
+	if err := o.CertificateSecrets.Sync(ctx); err != nil {
+		return err
+	}
+
 	// parse kubeconfig
 	kubeConfig, err := readKubeConfig(o.KubeconfigFile)
 	if err != nil {
@@ -119,12 +135,27 @@ func Run(o *options.Options, stopCh <-chan struct{}) error {
 	metav1.AddToGroupVersion(scheme, schema.GroupVersion{Group: "", Version: "v1"})
 	codecs := serializer.NewCodecFactory(scheme)
 	recommendedConfig := genericapiserver.NewRecommendedConfig(codecs)
+	recommendedConfig.ShutdownDelayDuration = o.ShutdownDelayDuration
+	recommendedConfig.ShutdownSendRetryAfter = o.ShutdownSendRetryAfter
 	if err := o.SecureServing.ApplyTo(&recommendedConfig.Config.SecureServing); err != nil {
 		return err
 	}
+	if o.TokenCacheTTL > 0 {
+		// the token cache below replaces the one of the TokenReviews
+		o.Authentication.CacheTTL = 0
+	}
 	if err := o.Authentication.ApplyTo(&recommendedConfig.Authentication, recommendedConfig.SecureServing, recommendedConfig.OpenAPIConfig); err != nil {
 		return err
 	}
+	if o.TokenCacheTTL > 0 {
+		tokenSecretInformers := kubeinformers.NewSharedInformerFactoryWithOptions(wildcardKubeClient, 10*time.Minute, kubeinformers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("type", string(corev1.SecretTypeServiceAccountToken)).String()
+		}))
+		tokenCache := virtualauthentication.NewTokenCache(recommendedConfig.Authentication.Authenticator, o.TokenCacheTTL)
+		tokenCache.InvalidateOnRevocation(tokenSecretInformers.Core().V1().Secrets(), wildcardKubeInformers.Core().V1().ServiceAccounts())
+		recommendedConfig.Authentication.Authenticator = tokenCache
+		extraInformerStarts = append(extraInformerStarts, tokenSecretInformers.Start)
+	}
 	rootAPIServerConfig, err := virtualrootapiserver.NewRootAPIConfig(recommendedConfig, append(extraInformerStarts,
 		wildcardKubeInformers.Start,
 		wildcardKcpInformers.Start,
@@ -21,6 +21,7 @@ import (
 	"io"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -29,6 +30,7 @@ import (
 	genericapiserveroptions "k8s.io/apiserver/pkg/server/options"
 	"k8s.io/component-base/logs"
 
+	certsoptions "github.com/kcp-dev/kcp/pkg/certs/options"
 	virtualworkspacesoptions "github.com/kcp-dev/kcp/pkg/virtual/options"
 )
 
@@ -43,10 +45,17 @@ type Options struct {
 	KubeconfigFile string
 	RootPathPrefix string
 
+	ShutdownDelayDuration  time.Duration
+	ShutdownSendRetryAfter bool
+
+	TokenCacheTTL time.Duration
+
 	SecureServing  genericapiserveroptions.SecureServingOptions
 	Authentication genericapiserveroptions.DelegatingAuthenticationOptions
 	Logs           logs.Options
 
+	CertificateSecrets certsoptions.CertificateSecrets
+
 	VirtualWorkspaces virtualworkspacesoptions.Options
 }
 
@@ -56,10 +65,14 @@ func NewOptions() *Options {
 
 		RootPathPrefix: DefaultRootPathPrefix,
 
+		TokenCacheTTL: time.Minute,
+
 		SecureServing:  *genericapiserveroptions.NewSecureServingOptions(),
 		Authentication: *genericapiserveroptions.NewDelegatingAuthenticationOptions(),
 		Logs:           *logs.NewOptions(),
 
+		CertificateSecrets: *certsoptions.NewCertificateSecrets(),
+
 		VirtualWorkspaces: *virtualworkspacesoptions.NewOptions(),
 	}
 
@@ -75,10 +88,22 @@ func (o *Options) AddFlags(flags *pflag.FlagSet) {
 	o.Authentication.AddFlags(flags)
 	o.Logs.AddFlags(flags)
 	o.VirtualWorkspaces.AddFlags(flags)
+	o.CertificateSecrets.AddFlags(flags)
 
 	flags.StringVar(&o.KubeconfigFile, "kubeconfig", o.KubeconfigFile, ""+
 		"The kubeconfig file of the KCP instance that hosts workspaces.")
 	_ = cobra.MarkFlagRequired(flags, "kubeconfig")
+
+	flags.DurationVar(&o.ShutdownDelayDuration, "shutdown-delay-duration", o.ShutdownDelayDuration, ""+
+		"Time to delay the termination. During that time the server keeps serving requests, /readyz returns failure, "+
+		"and watches are terminated gradually with a retriable error for clients to move to other replicas.")
+	flags.BoolVar(&o.ShutdownSendRetryAfter, "shutdown-send-retry-after", o.ShutdownSendRetryAfter, ""+
+		"If true the server keeps listening until all non long running requests in flight have been drained, "+
+		"rejecting new requests with a 429 status code and a 'Retry-After' response header.")
+	flags.DurationVar(&o.TokenCacheTTL, "token-cache-ttl", o.TokenCacheTTL, ""+
+		"Time successful bearer token authentications are cached for. The cached tokens of deleted ServiceAccounts "+
+		"and service account token Secrets are invalidated right away. If non-zero, it replaces the cache of "+
+		"--authentication-token-webhook-cache-ttl, which does not know about revocations. 0 disables the cache.")
 }
 
 func (o *Options) Validate() error {
@@ -86,10 +111,17 @@ func (o *Options) Validate() error {
 	errs = append(errs, o.SecureServing.Validate()...)
 	errs = append(errs, o.Authentication.Validate()...)
 	errs = append(errs, o.VirtualWorkspaces.Validate()...)
+	errs = append(errs, o.CertificateSecrets.Validate()...)
 
 	if len(o.KubeconfigFile) == 0 {
 		errs = append(errs, fmt.Errorf("--kubeconfig is required for this command"))
 	}
+	if o.ShutdownDelayDuration < 0 {
+		errs = append(errs, fmt.Errorf("--shutdown-delay-duration must be non-negative"))
+	}
+	if o.TokenCacheTTL < 0 {
+		errs = append(errs, fmt.Errorf("--token-cache-ttl must be non-negative"))
+	}
 	if !strings.HasPrefix(o.RootPathPrefix, "/") {
 		errs = append(errs, fmt.Errorf("RootPathPrefix %q must start with /", o.RootPathPrefix))
 	}
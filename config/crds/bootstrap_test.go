@@ -57,3 +57,17 @@ func TestCreateFromFS(t *testing.T) {
 		})
 	}
 }
+
+func TestStorageVersion(t *testing.T) {
+	version, err := StorageVersion(metav1.GroupResource{Group: tenancy.GroupName, Resource: "clusterworkspaceshards"})
+	if err != nil {
+		t.Fatalf("StorageVersion() error = %v", err)
+	}
+	if version != "v1alpha1" {
+		t.Errorf("StorageVersion() = %q, want %q", version, "v1alpha1")
+	}
+
+	if _, err := StorageVersion(metav1.GroupResource{Group: tenancy.GroupName, Resource: "unknown"}); err == nil {
+		t.Errorf("StorageVersion() of an unknown CRD did not fail")
+	}
+}
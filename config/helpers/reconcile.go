@@ -0,0 +1,550 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	extensionsapiserver "k8s.io/apiextensions-apiserver/pkg/apiserver"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	apimachineryerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kubeyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// ManagedByLabelKey is set on the objects applied by a Reconciler to its name. Only
+	// objects with this label are pruned.
+	ManagedByLabelKey = "bootstrap.kcp.dev/managed-by"
+
+	inventoryNamespace = "default"
+	inventoryKey       = "objects"
+)
+
+var configMapsGVR = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+
+var (
+	driftedObjects = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Namespace:      "kcp",
+			Name:           "bootstrap_drifted_objects",
+			Help:           "Number of objects that differed from their manifests in the last reconciliation, including those not corrected because they are create-only.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"bootstrap"},
+	)
+
+	lastSuccess = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Namespace:      "kcp",
+			Name:           "bootstrap_last_success_timestamp_seconds",
+			Help:           "Unix time of the last reconciliation of the manifests without errors.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"bootstrap"},
+	)
+
+	registerMetrics sync.Once
+)
+
+// ObjectReference identifies an object of the manifests.
+type ObjectReference struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+}
+
+func (r ObjectReference) String() string {
+	if r.Namespace == "" {
+		return fmt.Sprintf("%s %s", r.Kind, r.Name)
+	}
+	return fmt.Sprintf("%s %s/%s", r.Kind, r.Namespace, r.Name)
+}
+
+func referenceOf(u *unstructured.Unstructured) ObjectReference {
+	return ObjectReference{
+		APIVersion: u.GetAPIVersion(),
+		Kind:       u.GetKind(),
+		Namespace:  u.GetNamespace(),
+		Name:       u.GetName(),
+	}
+}
+
+// Drift is an object that differs from its manifest.
+type Drift struct {
+	Object ObjectReference
+	// Fields are the paths of the fields that differ, e.g. spec.initializers.
+	Fields []string
+	// Corrected is false if the object was not updated because it is create-only.
+	Corrected bool
+}
+
+// ReconcileResult lists what a reconciliation changed and found.
+type ReconcileResult struct {
+	Created []ObjectReference
+	Updated []ObjectReference
+	Pruned  []ObjectReference
+	Drifts  []Drift
+}
+
+// Reconciler continuously converges the objects in a logical cluster to manifests:
+// missing objects are created, objects that differ from their manifests are updated
+// unless they carry the create-only annotation, and, with pruning, objects that were
+// applied before but are no longer in the manifests are deleted.
+//
+// The applied objects are recorded in the inventory, a ConfigMap named
+// kcp-bootstrap-<name> in the default namespace.
+type Reconciler struct {
+	name         string
+	manifests    []fs.FS
+	transformers []TransformFileFunc
+	prune        bool
+
+	client      dynamic.Interface
+	mapper      meta.RESTMapper
+	resetMapper func()
+}
+
+// NewReconciler returns a reconciler for the given manifests. Objects in later manifests
+// replace those with the same identity in earlier ones, e.g. to override embedded manifests
+// with those on disk.
+func NewReconciler(name string, discoveryClient discovery.DiscoveryInterface, dynamicClient dynamic.Interface, prune bool, manifests []fs.FS, opts ...Option) *Reconciler {
+	registerMetrics.Do(func() {
+		legacyregistry.MustRegister(driftedObjects)
+		legacyregistry.MustRegister(lastSuccess)
+	})
+
+	cache := memory.NewMemCacheClient(discoveryClient)
+	r := &Reconciler{
+		name:        name,
+		manifests:   manifests,
+		prune:       prune,
+		client:      dynamicClient,
+		mapper:      restmapper.NewDeferredDiscoveryRESTMapper(cache),
+		resetMapper: cache.Invalidate,
+	}
+	for _, opt := range opts {
+		r.transformers = append(r.transformers, opt.TransformFile)
+	}
+	return r
+}
+
+// Converge reconciles until the manifests are applied without errors. This is blocking,
+// i.e. it only returns (with error) when the context is closed or with nil when the
+// manifests are applied.
+func (r *Reconciler) Converge(ctx context.Context) error {
+	return wait.PollImmediateInfiniteWithContext(ctx, time.Second, func(ctx context.Context) (bool, error) {
+		if _, err := r.Reconcile(ctx); err != nil {
+			klog.Infof("Failed to bootstrap %s resources, retrying: %v", r.name, err)
+			// invalidate cache if resources not found
+			// xref: https://github.com/kcp-dev/kcp/issues/655
+			r.resetMapper()
+			return false, nil
+		}
+		return true, nil
+	})
+}
+
+// Start reconciles every period until the context is done. The first reconciliation is
+// after one period, i.e. Converge is expected to have been called before.
+func (r *Reconciler) Start(ctx context.Context, period time.Duration) {
+	klog.Infof("Starting %s bootstrap reconciler", r.name)
+	defer klog.Infof("Shutting down %s bootstrap reconciler", r.name)
+
+	select {
+	case <-ctx.Done():
+		return
+	case <-time.After(period):
+	}
+
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if _, err := r.Reconcile(ctx); err != nil {
+			klog.Errorf("Failed to reconcile %s bootstrap resources: %v", r.name, err)
+			r.resetMapper()
+		}
+	}, period)
+}
+
+// Reconcile applies the manifests once, prunes objects no longer in the manifests if
+// enabled, and reports objects that drifted from their manifests.
+func (r *Reconciler) Reconcile(ctx context.Context) (*ReconcileResult, error) {
+	desired, err := r.readManifests()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReconcileResult{}
+	var errs []error
+	desiredRefs := make(map[ObjectReference]bool, len(desired))
+	inventory := make([]ObjectReference, 0, len(desired))
+	for _, obj := range desired {
+		ref := referenceOf(obj)
+		desiredRefs[ref] = true
+		inventory = append(inventory, ref)
+		if err := r.apply(ctx, obj, result); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	previous, err := r.getInventory(ctx)
+	if err != nil {
+		return result, apimachineryerrors.NewAggregate(append(errs, err))
+	}
+	for i := len(previous) - 1; i >= 0; i-- {
+		ref := previous[i]
+		if desiredRefs[ref] {
+			continue
+		}
+		if r.prune {
+			pruned, err := r.pruneObject(ctx, ref)
+			if err == nil {
+				if pruned {
+					result.Pruned = append(result.Pruned, ref)
+				}
+				continue
+			}
+			errs = append(errs, err)
+		}
+		inventory = append(inventory, ref) // keep to prune later
+	}
+	if err := r.updateInventory(ctx, inventory); err != nil {
+		errs = append(errs, err)
+	}
+
+	driftedObjects.WithLabelValues(r.name).Set(float64(len(result.Drifts)))
+	for _, d := range result.Drifts {
+		if d.Corrected {
+			klog.Infof("Corrected drift of %s bootstrap resource %s in fields %s", r.name, d.Object, strings.Join(d.Fields, ", "))
+		} else {
+			klog.Warningf("Create-only %s bootstrap resource %s drifted in fields %s", r.name, d.Object, strings.Join(d.Fields, ", "))
+		}
+	}
+
+	if len(errs) > 0 {
+		return result, apimachineryerrors.NewAggregate(errs)
+	}
+	lastSuccess.WithLabelValues(r.name).SetToCurrentTime()
+	return result, nil
+}
+
+// apply creates the given object, or updates it if it drifted from the manifest.
+func (r *Reconciler) apply(ctx context.Context, obj *unstructured.Unstructured, result *ReconcileResult) error {
+	ref := referenceOf(obj)
+	gvk := obj.GroupVersionKind()
+	m, err := r.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return fmt.Errorf("could not get REST mapping for %s: %w", gvk, err)
+	}
+	client := r.client.Resource(m.Resource).Namespace(obj.GetNamespace())
+
+	existing, err := client.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		obj = obj.DeepCopy()
+		setManagedBy(obj, r.name)
+		if _, err := client.Create(ctx, obj, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("could not create %s: %w", ref, err)
+		}
+		klog.Infof("Bootstrapped %s %s", r.name, ref)
+		result.Created = append(result.Created, ref)
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("could not get %s: %w", ref, err)
+	}
+
+	_, createOnly := existing.GetAnnotations()[annotationCreateOnlyKey]
+	fields := driftedFields(obj.Object, existing.Object)
+	if len(fields) > 0 {
+		result.Drifts = append(result.Drifts, Drift{Object: ref, Fields: fields, Corrected: !createOnly})
+	}
+	if createOnly || (len(fields) == 0 && existing.GetLabels()[ManagedByLabelKey] == r.name) {
+		return nil
+	}
+
+	updated := existing.DeepCopy()
+	mergeInto(updated.Object, obj.Object)
+	setManagedBy(updated, r.name)
+	if _, err := client.Update(ctx, updated, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("could not update %s: %w", ref, err)
+	}
+	result.Updated = append(result.Updated, ref)
+	return nil
+}
+
+// pruneObject deletes the given object if it is managed by this reconciler. It returns
+// whether an object was deleted.
+func (r *Reconciler) pruneObject(ctx context.Context, ref ObjectReference) (bool, error) {
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return false, nil // cannot exist
+	}
+	m, err := r.mapper.RESTMapping(schema.GroupKind{Group: gv.Group, Kind: ref.Kind}, gv.Version)
+	if meta.IsNoMatchError(err) {
+		return false, nil // the resource is gone, and so is the object
+	} else if err != nil {
+		return false, fmt.Errorf("could not get REST mapping for %s: %w", ref, err)
+	}
+	client := r.client.Resource(m.Resource).Namespace(ref.Namespace)
+
+	existing, err := client.Get(ctx, ref.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("could not get %s: %w", ref, err)
+	}
+	if existing.GetLabels()[ManagedByLabelKey] != r.name {
+		klog.Infof("Not pruning %s bootstrap resource %s because it is not labeled %s=%s", r.name, ref, ManagedByLabelKey, r.name)
+		return false, nil
+	}
+
+	uid := existing.GetUID()
+	if err := client.Delete(ctx, ref.Name, metav1.DeleteOptions{Preconditions: &metav1.Preconditions{UID: &uid}}); err != nil && !apierrors.IsNotFound(err) {
+		return false, fmt.Errorf("could not prune %s: %w", ref, err)
+	}
+	klog.Infof("Pruned %s bootstrap resource %s", r.name, ref)
+	return true, nil
+}
+
+func (r *Reconciler) inventoryName() string {
+	return "kcp-bootstrap-" + r.name
+}
+
+func (r *Reconciler) getInventory(ctx context.Context) ([]ObjectReference, error) {
+	cm, err := r.client.Resource(configMapsGVR).Namespace(inventoryNamespace).Get(ctx, r.inventoryName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("could not get %s bootstrap inventory: %w", r.name, err)
+	}
+
+	raw, _, err := unstructured.NestedString(cm.Object, "data", inventoryKey)
+	if err != nil || raw == "" {
+		return nil, err
+	}
+	var refs []ObjectReference
+	if err := json.Unmarshal([]byte(raw), &refs); err != nil {
+		// a broken inventory is not fatal. We only lose pruning of objects removed meanwhile.
+		klog.Errorf("Ignoring invalid %s bootstrap inventory: %v", r.name, err)
+		return nil, nil
+	}
+	return refs, nil
+}
+
+func (r *Reconciler) updateInventory(ctx context.Context, refs []ObjectReference) error {
+	raw, err := json.Marshal(refs)
+	if err != nil {
+		return err
+	}
+
+	client := r.client.Resource(configMapsGVR).Namespace(inventoryNamespace)
+	cm, err := client.Get(ctx, r.inventoryName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		cm = &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]interface{}{
+				"name":      r.inventoryName(),
+				"namespace": inventoryNamespace,
+			},
+			"data": map[string]interface{}{
+				inventoryKey: string(raw),
+			},
+		}}
+		_, err := client.Create(ctx, cm, metav1.CreateOptions{})
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	if existing, _, _ := unstructured.NestedString(cm.Object, "data", inventoryKey); existing == string(raw) {
+		return nil
+	}
+	if err := unstructured.SetNestedField(cm.Object, string(raw), "data", inventoryKey); err != nil {
+		return err
+	}
+	_, err = client.Update(ctx, cm, metav1.UpdateOptions{})
+	return err
+}
+
+// readManifests reads the objects of all manifests in order.
+func (r *Reconciler) readManifests() ([]*unstructured.Unstructured, error) {
+	var objs []*unstructured.Unstructured
+	index := map[ObjectReference]int{}
+	for _, fsys := range r.manifests {
+		files, err := fs.ReadDir(fsys, ".")
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			if f.IsDir() || !(strings.HasSuffix(f.Name(), ".yaml") || strings.HasSuffix(f.Name(), ".yml")) {
+				continue
+			}
+			raw, err := fs.ReadFile(fsys, f.Name())
+			if err != nil {
+				return nil, fmt.Errorf("could not read %s: %w", f.Name(), err)
+			}
+			fileObjs, err := decodeManifest(raw, r.transformers)
+			if err != nil {
+				return nil, fmt.Errorf("could not decode %s: %w", f.Name(), err)
+			}
+			for _, obj := range fileObjs {
+				ref := referenceOf(obj)
+				if i, ok := index[ref]; ok {
+					objs[i] = obj
+					continue
+				}
+				index[ref] = len(objs)
+				objs = append(objs, obj)
+			}
+		}
+	}
+	return objs, nil
+}
+
+func decodeManifest(raw []byte, transformers []TransformFileFunc) ([]*unstructured.Unstructured, error) {
+	d := kubeyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(raw)))
+	var objs []*unstructured.Unstructured
+	for i := 1; ; i++ {
+		doc, err := d.Read()
+		if errors.Is(err, io.EOF) {
+			return objs, nil
+		} else if err != nil {
+			return nil, err
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+
+		for _, transformer := range transformers {
+			doc, err = transformer(doc)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		obj, _, err := extensionsapiserver.Codecs.UniversalDeserializer().Decode(doc, nil, &unstructured.Unstructured{})
+		if err != nil {
+			return nil, fmt.Errorf("doc %d: %w", i, err)
+		}
+		u, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			return nil, fmt.Errorf("doc %d: decoded into incorrect type, got %T, wanted %T", i, obj, &unstructured.Unstructured{})
+		}
+		objs = append(objs, u)
+	}
+}
+
+func setManagedBy(obj *unstructured.Unstructured, name string) {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[ManagedByLabelKey] = name
+	obj.SetLabels(labels)
+}
+
+// driftedFields returns the paths of the fields set in the manifest that differ in the
+// live object. Fields not set in the manifest, the status and metadata other than labels
+// and annotations are ignored.
+func driftedFields(desired, live map[string]interface{}) []string {
+	var fields []string
+	for _, k := range sortedKeys(desired) {
+		switch k {
+		case "apiVersion", "kind", "status":
+		case "metadata":
+			desiredMeta, _ := desired[k].(map[string]interface{})
+			liveMeta, _ := live[k].(map[string]interface{})
+			for _, mk := range []string{"labels", "annotations"} {
+				if v, ok := desiredMeta[mk]; ok {
+					fields = append(fields, diffFields(v, liveMeta[mk], "metadata."+mk)...)
+				}
+			}
+		default:
+			fields = append(fields, diffFields(desired[k], live[k], k)...)
+		}
+	}
+	return fields
+}
+
+func diffFields(desired, live interface{}, path string) []string {
+	desiredMap, ok := desired.(map[string]interface{})
+	if !ok {
+		if equality.Semantic.DeepEqual(desired, live) {
+			return nil
+		}
+		return []string{path}
+	}
+	liveMap, ok := live.(map[string]interface{})
+	if !ok {
+		return []string{path}
+	}
+
+	var fields []string
+	for _, k := range sortedKeys(desiredMap) {
+		fields = append(fields, diffFields(desiredMap[k], liveMap[k], path+"."+k)...)
+	}
+	return fields
+}
+
+// mergeInto sets the fields of src in dst, recursing into objects. Lists are replaced.
+func mergeInto(dst, src map[string]interface{}) {
+	for k, v := range src {
+		if k == "status" {
+			continue
+		}
+		srcMap, srcIsMap := v.(map[string]interface{})
+		dstMap, dstIsMap := dst[k].(map[string]interface{})
+		if srcIsMap && dstIsMap {
+			mergeInto(dstMap, srcMap)
+			continue
+		}
+		dst[k] = runtime.DeepCopyJSONValue(v)
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,226 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"context"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+const (
+	namespaceManifest = `apiVersion: v1
+kind: Namespace
+metadata:
+  name: default
+`
+	settingsManifest = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: default
+data:
+  mode: strict
+`
+	shardManifest = `apiVersion: v1
+kind: Secret
+metadata:
+  name: shard
+  namespace: default
+  annotations:
+    bootstrap.kcp.dev/create-only: ""
+stringData:
+  kubeconfig: SHARD_KUBECONFIG
+`
+)
+
+var secretsGVR = schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+
+func newTestReconciler(client *dynamicfake.FakeDynamicClient, manifests ...fs.FS) *Reconciler {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, meta.RESTScopeRoot)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Secret"}, meta.RESTScopeNamespace)
+	return &Reconciler{
+		name:         "root",
+		manifests:    manifests,
+		transformers: []TransformFileFunc{ReplaceOption("SHARD_KUBECONFIG", "a2NvbmZpZw==").TransformFile},
+		prune:        true,
+		client:       client,
+		mapper:       mapper,
+		resetMapper:  func() {},
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	ctx := context.Background()
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	manifests := fstest.MapFS{
+		"namespace.yaml": {Data: []byte(namespaceManifest)},
+		"settings.yaml":  {Data: []byte(settingsManifest)},
+		"shard.yaml":     {Data: []byte(shardManifest)},
+		"README.md":      {Data: []byte("not a manifest")},
+	}
+	r := newTestReconciler(client, manifests)
+
+	t.Log("Initial reconciliation creates everything")
+	result, err := r.Reconcile(ctx)
+	require.NoError(t, err)
+	require.Len(t, result.Created, 3)
+	require.Empty(t, result.Drifts)
+
+	settings, err := client.Resource(configMapsGVR).Namespace("default").Get(ctx, "settings", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "root", settings.GetLabels()[ManagedByLabelKey])
+
+	t.Log("Nothing changes without drift")
+	result, err = r.Reconcile(ctx)
+	require.NoError(t, err)
+	require.Equal(t, &ReconcileResult{}, result)
+
+	t.Log("Drift is corrected, but not for create-only objects")
+	require.NoError(t, unstructured.SetNestedField(settings.Object, "lax", "data", "mode"))
+	require.NoError(t, unstructured.SetNestedField(settings.Object, "kept", "data", "extra"))
+	_, err = client.Resource(configMapsGVR).Namespace("default").Update(ctx, settings, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	shard, err := client.Resource(secretsGVR).Namespace("default").Get(ctx, "shard", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.NoError(t, unstructured.SetNestedField(shard.Object, "changed", "stringData", "kubeconfig"))
+	_, err = client.Resource(secretsGVR).Namespace("default").Update(ctx, shard, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	result, err = r.Reconcile(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []Drift{
+		{Object: ObjectReference{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "settings"}, Fields: []string{"data.mode"}, Corrected: true},
+		{Object: ObjectReference{APIVersion: "v1", Kind: "Secret", Namespace: "default", Name: "shard"}, Fields: []string{"stringData.kubeconfig"}, Corrected: false},
+	}, result.Drifts)
+	require.Equal(t, []ObjectReference{{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "settings"}}, result.Updated)
+
+	settings, err = client.Resource(configMapsGVR).Namespace("default").Get(ctx, "settings", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"mode": "strict", "extra": "kept"}, settings.Object["data"])
+
+	t.Log("Objects removed from the manifests are pruned")
+	delete(manifests, "settings.yaml")
+	result, err = r.Reconcile(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []ObjectReference{{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "settings"}}, result.Pruned)
+	_, err = client.Resource(configMapsGVR).Namespace("default").Get(ctx, "settings", metav1.GetOptions{})
+	require.True(t, apierrors.IsNotFound(err), "expected settings to be pruned, got %v", err)
+
+	inventory, err := r.getInventory(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []ObjectReference{
+		{APIVersion: "v1", Kind: "Namespace", Name: "default"},
+		{APIVersion: "v1", Kind: "Secret", Namespace: "default", Name: "shard"},
+	}, inventory)
+}
+
+func TestReconcileDoesNotPruneUnmanaged(t *testing.T) {
+	ctx := context.Background()
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	manifests := fstest.MapFS{
+		"namespace.yaml": {Data: []byte(namespaceManifest)},
+		"settings.yaml":  {Data: []byte(settingsManifest)},
+	}
+	r := newTestReconciler(client, manifests)
+	_, err := r.Reconcile(ctx)
+	require.NoError(t, err)
+
+	settings, err := client.Resource(configMapsGVR).Namespace("default").Get(ctx, "settings", metav1.GetOptions{})
+	require.NoError(t, err)
+	settings.SetLabels(nil) // taken over by somebody else
+	_, err = client.Resource(configMapsGVR).Namespace("default").Update(ctx, settings, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	delete(manifests, "settings.yaml")
+	result, err := r.Reconcile(ctx)
+	require.NoError(t, err)
+	require.Empty(t, result.Pruned)
+	_, err = client.Resource(configMapsGVR).Namespace("default").Get(ctx, "settings", metav1.GetOptions{})
+	require.NoError(t, err)
+}
+
+func TestReconcileOverrides(t *testing.T) {
+	ctx := context.Background()
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	embedded := fstest.MapFS{
+		"namespace.yaml": {Data: []byte(namespaceManifest)},
+		"settings.yaml":  {Data: []byte(settingsManifest)},
+	}
+	onDisk := fstest.MapFS{
+		"settings.yml": {Data: []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: default
+data:
+  mode: custom
+`)},
+	}
+	r := newTestReconciler(client, embedded, onDisk)
+	result, err := r.Reconcile(ctx)
+	require.NoError(t, err)
+	require.Len(t, result.Created, 2)
+
+	settings, err := client.Resource(configMapsGVR).Namespace("default").Get(ctx, "settings", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"mode": "custom"}, settings.Object["data"])
+}
+
+func TestDriftedFields(t *testing.T) {
+	desired := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":   "settings",
+			"labels": map[string]interface{}{"a": "b"},
+		},
+		"spec": map[string]interface{}{
+			"list":   []interface{}{"x", "y"},
+			"nested": map[string]interface{}{"value": int64(1)},
+		},
+		"status": map[string]interface{}{"phase": "Ready"},
+	}
+	live := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":            "settings",
+			"resourceVersion": "42",
+			"labels":          map[string]interface{}{"a": "c", "other": "label"},
+		},
+		"spec": map[string]interface{}{
+			"list":    []interface{}{"x"},
+			"nested":  map[string]interface{}{"value": int64(1), "defaulted": true},
+			"ignored": "by the manifest",
+		},
+	}
+	require.Equal(t, []string{"metadata.labels.a", "spec.list"}, driftedFields(desired, live))
+}
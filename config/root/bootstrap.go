@@ -17,9 +17,10 @@ limitations under the License.
 package root
 
 import (
-	"context"
 	"embed"
 	"encoding/base64"
+	iofs "io/fs"
+	"os"
 
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
@@ -32,17 +33,22 @@ import (
 //go:embed *.yaml
 var fs embed.FS
 
-// Bootstrap creates resources in this package by continuously retrying the list.
-// This is blocking, i.e. it only returns (with error) when the context is closed or with nil when
-// the bootstrapping is successfully completed.
-func Bootstrap(ctx context.Context, rootDiscoveryClient discovery.DiscoveryInterface, rootDynamicClient dynamic.Interface, shardName string, kubeconfig clientcmdapi.Config) error {
+// NewReconciler returns a reconciler for the resources in this package and, if set, those in
+// the given directory, which replace resources of this package with the same identity. The
+// reconciler converges the root workspace to the resources, e.g. after an upgrade.
+func NewReconciler(rootDiscoveryClient discovery.DiscoveryInterface, rootDynamicClient dynamic.Interface, shardName string, kubeconfig clientcmdapi.Config, manifestsDir string, prune bool) (*confighelpers.Reconciler, error) {
 	kubeconfigRaw, err := clientcmd.Write(kubeconfig)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return confighelpers.Bootstrap(ctx, rootDiscoveryClient, rootDynamicClient, fs, confighelpers.ReplaceOption(
+	manifests := []iofs.FS{fs}
+	if manifestsDir != "" {
+		manifests = append(manifests, os.DirFS(manifestsDir))
+	}
+
+	return confighelpers.NewReconciler("root", rootDiscoveryClient, rootDynamicClient, prune, manifests, confighelpers.ReplaceOption(
 		"SHARD_NAME", shardName,
 		"SHARD_KUBECONFIG", base64.StdEncoding.EncodeToString(kubeconfigRaw),
-	))
+	)), nil
 }
@@ -57,17 +57,38 @@ func Bootstrap(ctx context.Context, crdClient apiextensionsclient.Interface, dis
 		{Group: tenancy.GroupName, Resource: "clusterworkspacetypes"},
 		{Group: tenancy.GroupName, Resource: "clusterworkspaceshards"},
 		{Group: tenancy.GroupName, Resource: "workspaces"},
+		{Group: tenancy.GroupName, Resource: "accessgrants"},
+		{Group: tenancy.GroupName, Resource: "denypolicies"},
+		{Group: tenancy.GroupName, Resource: "replications"},
+		{Group: tenancy.GroupName, Resource: "bulkworkspaceoperations"},
+		{Group: tenancy.GroupName, Resource: "policybundles"},
+		{Group: tenancy.GroupName, Resource: "homeworkspacepolicies"},
+		{Group: tenancy.GroupName, Resource: "objecttransfers"},
+		{Group: tenancy.GroupName, Resource: "virtualworkspaces"},
 		{Group: apiresource.GroupName, Resource: "apiresourceimports"},
 		{Group: apiresource.GroupName, Resource: "negotiatedapiresources"},
 		{Group: workload.GroupName, Resource: "workloadclusters"},
+		{Group: workload.GroupName, Resource: "workspaceworkloadusages"},
+		{Group: workload.GroupName, Resource: "dnszones"},
+		{Group: workload.GroupName, Resource: "dnsrecords"},
+		{Group: workload.GroupName, Resource: "certificates"},
 		{Group: apis.GroupName, Resource: "apiexports"},
 		{Group: apis.GroupName, Resource: "apibindings"},
 		{Group: apis.GroupName, Resource: "apiresourceschemas"},
+		{Group: apis.GroupName, Resource: "catalogentries"},
+		{Group: apis.GroupName, Resource: "sharedsecrets"},
+		{Group: apis.GroupName, Resource: "secretclaims"},
 	}
 
 	if utilfeature.DefaultFeatureGate.Enabled(kcpfeatures.LocationAPI) {
 		crds = append(crds, metav1.GroupResource{Group: scheduling.GroupName, Resource: "locations"})
 	}
+	if utilfeature.DefaultFeatureGate.Enabled(kcpfeatures.EventSubscriptions) {
+		crds = append(crds, metav1.GroupResource{Group: apis.GroupName, Resource: "eventsubscriptions"})
+	}
+	if utilfeature.DefaultFeatureGate.Enabled(kcpfeatures.TTLPolicies) {
+		crds = append(crds, metav1.GroupResource{Group: apis.GroupName, Resource: "ttlpolicies"})
+	}
 
 	if err := wait.PollImmediateInfiniteWithContext(ctx, time.Second, func(ctx context.Context) (bool, error) {
 		if err := configcrds.Create(ctx, crdClient.ApiextensionsV1().CustomResourceDefinitions(), crds...); err != nil {
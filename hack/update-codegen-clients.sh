@@ -24,13 +24,47 @@ export GOPATH=$(go env GOPATH)
 SCRIPT_ROOT=$(dirname "${BASH_SOURCE[0]}")/..
 CODEGEN_PKG=${CODEGEN_PKG:-$(cd "${SCRIPT_ROOT}"; go list -f '{{.Dir}}' -m k8s.io/code-generator)}
 
-bash "${CODEGEN_PKG}"/generate-groups.sh "deepcopy,client,informer,lister" \
+bash "${CODEGEN_PKG}"/generate-groups.sh "deepcopy,informer,lister" \
   github.com/kcp-dev/kcp/pkg/client github.com/kcp-dev/kcp/pkg/apis \
   "workload:v1alpha1 apiresource:v1alpha1 tenancy:v1alpha1 tenancy:v1beta1 apis:v1alpha1 scheduling:v1alpha1" \
   --go-header-file "${SCRIPT_ROOT}"/hack/boilerplate/boilerplate.generatego.txt \
   --output-base "${SCRIPT_ROOT}" \
   --trim-path-prefix github.com/kcp-dev/kcp
 
+# generate-groups.sh does not know about apply configurations, so run
+# applyconfiguration-gen and client-gen for the kcp APIs directly. Both are
+# pinned, such that the generated code is reproducible: they are built from
+# the code-generator version in go.mod, which must match the pin, into
+# binaries named after it, and never taken from $GOPATH/bin.
+CODE_GENERATOR_VER=v0.0.0-20220519184938-daf7ffdf437a
+GO_MOD_CODE_GENERATOR_VER=$(cd "${SCRIPT_ROOT}"; go list -m -f '{{if .Replace}}{{.Replace.Version}}{{else}}{{.Version}}{{end}}' k8s.io/code-generator)
+if [[ "${GO_MOD_CODE_GENERATOR_VER}" != "${CODE_GENERATOR_VER}" ]]; then
+  echo "k8s.io/code-generator ${GO_MOD_CODE_GENERATOR_VER} in go.mod does not match CODE_GENERATOR_VER=${CODE_GENERATOR_VER} in $0" >&2
+  exit 1
+fi
+TOOLS_DIR=$(cd "${SCRIPT_ROOT}"; pwd)/hack/tools
+APPLYCONFIGURATION_GEN=${TOOLS_DIR}/applyconfiguration-gen-${CODE_GENERATOR_VER}
+CLIENT_GEN=${TOOLS_DIR}/client-gen-${CODE_GENERATOR_VER}
+(cd "${SCRIPT_ROOT}"; go build -o "${APPLYCONFIGURATION_GEN}" k8s.io/code-generator/cmd/applyconfiguration-gen)
+(cd "${SCRIPT_ROOT}"; go build -o "${CLIENT_GEN}" k8s.io/code-generator/cmd/client-gen)
+
+"${APPLYCONFIGURATION_GEN}" \
+  --input-dirs github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1,github.com/kcp-dev/kcp/pkg/apis/apiresource/v1alpha1,github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1,github.com/kcp-dev/kcp/pkg/apis/tenancy/v1beta1,github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1,github.com/kcp-dev/kcp/pkg/apis/scheduling/v1alpha1 \
+  --output-package github.com/kcp-dev/kcp/pkg/client/applyconfiguration \
+  --go-header-file "${SCRIPT_ROOT}"/hack/boilerplate/boilerplate.generatego.txt \
+  --output-base "${SCRIPT_ROOT}" \
+  --trim-path-prefix github.com/kcp-dev/kcp
+
+"${CLIENT_GEN}" \
+  --clientset-name versioned \
+  --input-base github.com/kcp-dev/kcp/pkg/apis \
+  --input workload/v1alpha1,apiresource/v1alpha1,tenancy/v1alpha1,tenancy/v1beta1,apis/v1alpha1,scheduling/v1alpha1 \
+  --output-package github.com/kcp-dev/kcp/pkg/client/clientset \
+  --apply-configuration-package github.com/kcp-dev/kcp/pkg/client/applyconfiguration \
+  --go-header-file "${SCRIPT_ROOT}"/hack/boilerplate/boilerplate.generatego.txt \
+  --output-base "${SCRIPT_ROOT}" \
+  --trim-path-prefix github.com/kcp-dev/kcp
+
 bash "${CODEGEN_PKG}"/generate-groups.sh "deepcopy" \
   github.com/kcp-dev/kcp/third_party/conditions/client github.com/kcp-dev/kcp/third_party/conditions/apis \
   "conditions:v1alpha1" \
@@ -0,0 +1,30 @@
+#!/usr/bin/env bash
+
+# Copyright 2022 The KCP Authors.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+set -o errexit
+set -o nounset
+set -o pipefail
+set -o xtrace
+
+# Generates the Go code of the gRPC control plane API of the front-proxy. Needs protoc
+# (3.19), protoc-gen-go (v1.27.1) and protoc-gen-go-grpc (v1.2.0) in the PATH.
+
+cd "$(dirname "${BASH_SOURCE[0]}")/.."
+
+protoc \
+  --go_out=. --go_opt=paths=source_relative \
+  --go-grpc_out=. --go-grpc_opt=paths=source_relative \
+  pkg/proxy/controlplane/v1alpha1/controlplane.proto
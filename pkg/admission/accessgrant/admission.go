@@ -0,0 +1,225 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accessgrant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/genericcontrolplane"
+
+	"github.com/kcp-dev/kcp/pkg/admission/helpers"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/authorization/delegated"
+)
+
+const (
+	PluginName = "tenancy.kcp.dev/AccessGrant"
+)
+
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName,
+		func(_ io.Reader) (admission.Interface, error) {
+			return &accessGrantAdmission{
+				Handler:          admission.NewHandler(admission.Create, admission.Update),
+				createAuthorizer: delegated.NewDelegatedAuthorizer,
+			}, nil
+		})
+}
+
+// accessGrantAdmission prevents privilege escalation through AccessGrants, whose bindings are
+// created by kcp with its own privileges. Like for RoleBindings and ClusterRoleBindings, the
+// creator must have the verb `bind` on the granted role, or hold all of its rules in the
+// granted scope. Subjects, role and namespace of a grant are immutable.
+type accessGrantAdmission struct {
+	*admission.Handler
+	kubeClusterClient *kubernetes.Cluster
+
+	createAuthorizer delegated.DelegatedAuthorizerFactory
+	getClusterRole   func(ctx context.Context, clusterName logicalcluster.Name, name string) (*rbacv1.ClusterRole, error)
+	getRole          func(ctx context.Context, clusterName logicalcluster.Name, namespace, name string) (*rbacv1.Role, error)
+}
+
+// Ensure that the required admission interfaces are implemented.
+var _ = admission.ValidationInterface(&accessGrantAdmission{})
+var _ = admission.InitializationValidator(&accessGrantAdmission{})
+
+func (o *accessGrantAdmission) Validate(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
+	if a.GetResource().GroupResource() != tenancyv1alpha1.Resource("accessgrants") {
+		return nil
+	}
+	if a.GetSubresource() != "" {
+		return nil
+	}
+
+	grant, err := toAccessGrant(a.GetObject())
+	if err != nil {
+		return err
+	}
+
+	if a.GetOperation() == admission.Update {
+		old, err := toAccessGrant(a.GetOldObject())
+		if err != nil {
+			return err
+		}
+		var errs field.ErrorList
+		if !equality.Semantic.DeepEqual(grant.Spec.Subjects, old.Spec.Subjects) {
+			errs = append(errs, field.Invalid(field.NewPath("spec", "subjects"), grant.Spec.Subjects, "field is immutable"))
+		}
+		if grant.Spec.RoleRef != old.Spec.RoleRef {
+			errs = append(errs, field.Invalid(field.NewPath("spec", "roleRef"), grant.Spec.RoleRef, "field is immutable"))
+		}
+		if grant.Spec.Namespace != old.Spec.Namespace {
+			errs = append(errs, field.Invalid(field.NewPath("spec", "namespace"), grant.Spec.Namespace, "field is immutable"))
+		}
+		if len(errs) > 0 {
+			return admission.NewForbidden(a, errs.ToAggregate())
+		}
+		return nil
+	}
+
+	cluster, err := genericapirequest.ValidClusterFrom(ctx)
+	if err != nil {
+		return admission.NewForbidden(a, fmt.Errorf("error determining workspace: %w", err))
+	}
+
+	if err := o.checkEscalation(ctx, a.GetUserInfo(), cluster.Name, grant); err != nil {
+		return admission.NewForbidden(a, fmt.Errorf("unable to grant %s %q: %w", grant.Spec.RoleRef.Kind, grant.Spec.RoleRef.Name, err))
+	}
+
+	return nil
+}
+
+func toAccessGrant(obj runtime.Object) (*tenancyv1alpha1.AccessGrant, error) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("unexpected type %T", obj)
+	}
+	grant := &tenancyv1alpha1.AccessGrant{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, grant); err != nil {
+		return nil, fmt.Errorf("failed to convert unstructured to AccessGrant: %w", err)
+	}
+	return grant, nil
+}
+
+// checkEscalation mirrors the RBAC escalation check of bindings: the user must have the verb
+// `bind` on the role, or already hold all of its rules in the namespace of the grant.
+func (o *accessGrantAdmission) checkEscalation(ctx context.Context, user user.Info, clusterName logicalcluster.Name, grant *tenancyv1alpha1.AccessGrant) error {
+	roleRef := grant.Spec.RoleRef
+	if roleRef.APIGroup != rbacv1.GroupName {
+		return fmt.Errorf("roleRef.apiGroup must be %q", rbacv1.GroupName)
+	}
+	var resource string
+	switch roleRef.Kind {
+	case "ClusterRole":
+		resource = "clusterroles"
+	case "Role":
+		if grant.Spec.Namespace == "" {
+			return errors.New("a Role can only be granted in a namespace")
+		}
+		resource = "roles"
+	default:
+		return fmt.Errorf("roleRef.kind must be ClusterRole or Role, got %q", roleRef.Kind)
+	}
+
+	authz, err := o.createAuthorizer(clusterName, o.kubeClusterClient)
+	if err != nil {
+		// Logging a more specific error for the operator
+		klog.Errorf("error creating authorizer from delegating authorizer config: %v", err)
+		// Returning a less specific error to the end user
+		return errors.New("unable to authorize request")
+	}
+
+	bindAttr := authorizer.AttributesRecord{
+		User:            user,
+		Verb:            "bind",
+		Namespace:       grant.Spec.Namespace,
+		APIGroup:        rbacv1.GroupName,
+		APIVersion:      rbacv1.SchemeGroupVersion.Version,
+		Resource:        resource,
+		Name:            roleRef.Name,
+		ResourceRequest: true,
+	}
+	if decision, _, err := authz.Authorize(ctx, bindAttr); err != nil {
+		return fmt.Errorf("unable to determine access to %s: %w", resource, err)
+	} else if decision == authorizer.DecisionAllow {
+		return nil
+	}
+
+	var rules []rbacv1.PolicyRule
+	if roleRef.Kind == "ClusterRole" {
+		role, err := o.getClusterRole(ctx, clusterName, roleRef.Name)
+		if apierrors.IsNotFound(err) {
+			// ClusterRoles of the bootstrap policy are shared by all workspaces
+			role, err = o.getClusterRole(ctx, genericcontrolplane.LocalAdminCluster, roleRef.Name)
+		}
+		if err != nil {
+			return fmt.Errorf("missing verb='bind' permission on %s, and unable to get its rules: %w", resource, err)
+		}
+		rules = role.Rules
+	} else {
+		role, err := o.getRole(ctx, clusterName, grant.Spec.Namespace, roleRef.Name)
+		if err != nil {
+			return fmt.Errorf("missing verb='bind' permission on %s, and unable to get its rules: %w", resource, err)
+		}
+		rules = role.Rules
+	}
+
+	if err := helpers.ConfirmNoEscalation(ctx, authz, user, grant.Spec.Namespace, rules); err != nil {
+		return fmt.Errorf("missing verb='bind' permission on %s, and not holding all of its rules: %w", resource, err)
+	}
+
+	return nil
+}
+
+// ValidateInitialization ensures the required injected fields are set.
+func (o *accessGrantAdmission) ValidateInitialization() error {
+	if o.kubeClusterClient == nil {
+		return fmt.Errorf(PluginName + " plugin needs a Kubernetes ClusterInterface")
+	}
+
+	return nil
+}
+
+// SetKubeClusterClient is an admission plugin initializer function that injects a Kubernetes cluster client into
+// this admission plugin.
+func (o *accessGrantAdmission) SetKubeClusterClient(clusterClient *kubernetes.Cluster) {
+	o.kubeClusterClient = clusterClient
+	o.getClusterRole = func(ctx context.Context, clusterName logicalcluster.Name, name string) (*rbacv1.ClusterRole, error) {
+		return clusterClient.Cluster(clusterName).RbacV1().ClusterRoles().Get(ctx, name, metav1.GetOptions{})
+	}
+	o.getRole = func(ctx context.Context, clusterName logicalcluster.Name, namespace, name string) (*rbacv1.Role, error) {
+		return clusterClient.Cluster(clusterName).RbacV1().Roles(namespace).Get(ctx, name, metav1.GetOptions{})
+	}
+}
@@ -0,0 +1,240 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accessgrant
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kcp-dev/kcp/pkg/admission/helpers"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+func accessGrantAttr(op admission.Operation, grant, old *tenancyv1alpha1.AccessGrant) admission.Attributes {
+	var obj, oldObj runtime.Object
+	if grant != nil {
+		obj = helpers.ToUnstructuredOrDie(grant)
+	}
+	if old != nil {
+		oldObj = helpers.ToUnstructuredOrDie(old)
+	}
+	return admission.NewAttributesRecord(
+		obj,
+		oldObj,
+		tenancyv1alpha1.Kind("AccessGrant").WithVersion("v1alpha1"),
+		"",
+		"support",
+		tenancyv1alpha1.Resource("accessgrants").WithVersion("v1alpha1"),
+		"",
+		op,
+		&metav1.CreateOptions{},
+		false,
+		&user.DefaultInfo{Name: "alice"},
+	)
+}
+
+func newAccessGrant(kind, role, namespace string, subjects ...string) *tenancyv1alpha1.AccessGrant {
+	grant := &tenancyv1alpha1.AccessGrant{
+		ObjectMeta: metav1.ObjectMeta{Name: "support"},
+		Spec: tenancyv1alpha1.AccessGrantSpec{
+			RoleRef:   rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: kind, Name: role},
+			Namespace: namespace,
+			ExpiresAt: metav1.NewTime(time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)),
+		},
+	}
+	for _, s := range subjects {
+		grant.Spec.Subjects = append(grant.Spec.Subjects, rbacv1.Subject{Kind: rbacv1.UserKind, APIGroup: rbacv1.GroupName, Name: s})
+	}
+	return grant
+}
+
+func TestValidate(t *testing.T) {
+	viewRules := []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"configmaps", "pods/log"}, Verbs: []string{"get", "list"}}}
+	adminRules := []rbacv1.PolicyRule{{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}}}
+
+	clusterRoles := map[string]map[string]*rbacv1.ClusterRole{
+		"root:org": {
+			"view": {ObjectMeta: metav1.ObjectMeta{Name: "view"}, Rules: viewRules},
+		},
+		"system:admin": {
+			"cluster-admin": {ObjectMeta: metav1.ObjectMeta{Name: "cluster-admin"}, Rules: adminRules},
+		},
+	}
+	roles := map[string]*rbacv1.Role{
+		"default/editor": {ObjectMeta: metav1.ObjectMeta{Name: "editor", Namespace: "default"}, Rules: viewRules},
+	}
+
+	tests := []struct {
+		name           string
+		attr           admission.Attributes
+		allowed        []string
+		authzError     error
+		expectedErrors []string
+	}{
+		{
+			name:    "Create: passes with bind on the ClusterRole",
+			attr:    accessGrantAttr(admission.Create, newAccessGrant("ClusterRole", "cluster-admin", "", "bob"), nil),
+			allowed: []string{"bind clusterroles cluster-admin "},
+		},
+		{
+			name:    "Create: passes with bind on the Role",
+			attr:    accessGrantAttr(admission.Create, newAccessGrant("Role", "editor", "default", "bob"), nil),
+			allowed: []string{"bind roles editor default"},
+		},
+		{
+			name:    "Create: passes when holding all rules of the ClusterRole",
+			attr:    accessGrantAttr(admission.Create, newAccessGrant("ClusterRole", "view", "", "bob"), nil),
+			allowed: []string{"get configmaps  ", "list configmaps  ", "get pods/log  ", "list pods/log  "},
+		},
+		{
+			name:    "Create: passes when holding all rules of the ClusterRole in the namespace",
+			attr:    accessGrantAttr(admission.Create, newAccessGrant("ClusterRole", "view", "default", "bob"), nil),
+			allowed: []string{"get configmaps  default", "list configmaps  default", "get pods/log  default", "list pods/log  default"},
+		},
+		{
+			name:           "Create: fails when holding the rules only in another scope",
+			attr:           accessGrantAttr(admission.Create, newAccessGrant("ClusterRole", "view", "", "bob"), nil),
+			allowed:        []string{"get configmaps  default", "list configmaps  default", "get pods/log  default", "list pods/log  default"},
+			expectedErrors: []string{`missing verb='bind' permission on clusterroles, and not holding all of its rules: missing permission verb="get" resource="configmaps"`},
+		},
+		{
+			name:           "Create: fails when granting cluster-admin without bind",
+			attr:           accessGrantAttr(admission.Create, newAccessGrant("ClusterRole", "cluster-admin", "", "bob"), nil),
+			allowed:        []string{"get configmaps  ", "list configmaps  "},
+			expectedErrors: []string{`missing permission verb="*" resource="*.*"`},
+		},
+		{
+			name:           "Create: fails when holding only some rules of the Role",
+			attr:           accessGrantAttr(admission.Create, newAccessGrant("Role", "editor", "default", "bob"), nil),
+			allowed:        []string{"get configmaps  default", "list configmaps  default"},
+			expectedErrors: []string{`missing permission verb="get" resource="pods/log" namespace="default"`},
+		},
+		{
+			name:           "Create: fails for an unknown role without bind",
+			attr:           accessGrantAttr(admission.Create, newAccessGrant("ClusterRole", "unknown", "", "bob"), nil),
+			expectedErrors: []string{"missing verb='bind' permission on clusterroles, and unable to get its rules"},
+		},
+		{
+			name:           "Create: fails for a Role without namespace",
+			attr:           accessGrantAttr(admission.Create, newAccessGrant("Role", "editor", "", "bob"), nil),
+			expectedErrors: []string{"a Role can only be granted in a namespace"},
+		},
+		{
+			name:           "Create: fails when there's an error checking authorization",
+			attr:           accessGrantAttr(admission.Create, newAccessGrant("ClusterRole", "view", "", "bob"), nil),
+			authzError:     errors.New("some error here"),
+			expectedErrors: []string{"unable to determine access to clusterroles: some error here"},
+		},
+		{
+			name:           "Update: changed roleRef fails",
+			attr:           accessGrantAttr(admission.Update, newAccessGrant("ClusterRole", "cluster-admin", "", "bob"), newAccessGrant("ClusterRole", "view", "", "bob")),
+			expectedErrors: []string{"spec.roleRef: Invalid value"},
+		},
+		{
+			name:           "Update: changed subjects fail",
+			attr:           accessGrantAttr(admission.Update, newAccessGrant("ClusterRole", "view", "", "bob", "mallory"), newAccessGrant("ClusterRole", "view", "", "bob")),
+			expectedErrors: []string{"spec.subjects: Invalid value"},
+		},
+		{
+			name:           "Update: changed namespace fails",
+			attr:           accessGrantAttr(admission.Update, newAccessGrant("ClusterRole", "view", "", "bob"), newAccessGrant("ClusterRole", "view", "default", "bob")),
+			expectedErrors: []string{"spec.namespace: Invalid value"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o := &accessGrantAdmission{
+				Handler: admission.NewHandler(admission.Create, admission.Update),
+				createAuthorizer: func(clusterName logicalcluster.Name, client kubernetes.ClusterInterface) (authorizer.Authorizer, error) {
+					require.Equal(t, "root:org", clusterName.String())
+					return &fakeAuthorizer{allowed: tc.allowed, err: tc.authzError}, nil
+				},
+				getClusterRole: func(ctx context.Context, clusterName logicalcluster.Name, name string) (*rbacv1.ClusterRole, error) {
+					if role, ok := clusterRoles[clusterName.String()][name]; ok {
+						return role, nil
+					}
+					return nil, apierrors.NewNotFound(rbacv1.Resource("clusterroles"), name)
+				},
+				getRole: func(ctx context.Context, clusterName logicalcluster.Name, namespace, name string) (*rbacv1.Role, error) {
+					if role, ok := roles[namespace+"/"+name]; ok {
+						return role, nil
+					}
+					return nil, apierrors.NewNotFound(rbacv1.Resource("roles"), name)
+				},
+			}
+
+			ctx := request.WithCluster(context.Background(), request.Cluster{Name: logicalcluster.New("root:org")})
+
+			err := o.Validate(ctx, tc.attr, nil)
+
+			wantErr := len(tc.expectedErrors) > 0
+			require.Equal(t, wantErr, err != nil, "unexpected error: %v", err)
+			for _, expected := range tc.expectedErrors {
+				require.Contains(t, err.Error(), expected)
+			}
+		})
+	}
+}
+
+func TestValidateUpdateOfExpiry(t *testing.T) {
+	grant := newAccessGrant("ClusterRole", "cluster-admin", "", "bob")
+	old := grant.DeepCopy()
+	grant.Spec.ExpiresAt = metav1.NewTime(grant.Spec.ExpiresAt.Add(time.Hour))
+
+	o := &accessGrantAdmission{Handler: admission.NewHandler(admission.Create, admission.Update)}
+	ctx := request.WithCluster(context.Background(), request.Cluster{Name: logicalcluster.New("root:org")})
+	require.NoError(t, o.Validate(ctx, accessGrantAttr(admission.Update, grant, old), nil))
+}
+
+// fakeAuthorizer allows the requests listed as "<verb> <resource>[/<subresource>] <name> <namespace>".
+type fakeAuthorizer struct {
+	allowed []string
+	err     error
+}
+
+func (a *fakeAuthorizer) Authorize(ctx context.Context, attr authorizer.Attributes) (authorized authorizer.Decision, reason string, err error) {
+	if a.err != nil {
+		return authorizer.DecisionNoOpinion, "", a.err
+	}
+	resource := attr.GetResource()
+	if attr.GetSubresource() != "" {
+		resource += "/" + attr.GetSubresource()
+	}
+	key := attr.GetVerb() + " " + resource + " " + attr.GetName() + " " + attr.GetNamespace()
+	for _, allowed := range a.allowed {
+		if allowed == key {
+			return authorizer.DecisionAllow, "", nil
+		}
+	}
+	return authorizer.DecisionNoOpinion, "", nil
+}
@@ -18,24 +18,32 @@ package apibinding
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 
 	"github.com/kcp-dev/logicalcluster"
 
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsinformers "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/apiserver/pkg/admission"
 	"k8s.io/apiserver/pkg/authentication/user"
 	"k8s.io/apiserver/pkg/authorization/authorizer"
 	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/warning"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clusters"
 	"k8s.io/klog/v2"
 
+	kcpinitializers "github.com/kcp-dev/kcp/pkg/admission/initializers"
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
 	"github.com/kcp-dev/kcp/pkg/authorization/delegated"
+	kcpinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
 )
 
 const (
@@ -57,11 +65,82 @@ type apiBindingAdmission struct {
 	kubeClusterClient *kubernetes.Cluster
 
 	createAuthorizer delegated.DelegatedAuthorizerFactory
+
+	getAPIExport         func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error)
+	getAPIResourceSchema func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error)
+	listAPIBindings      func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error)
+	getCRD               func(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error)
+
+	kcpInformersSynced, crdInformerSynced func() bool
 }
 
 // Ensure that the required admission interfaces are implemented.
+var _ = admission.MutationInterface(&apiBindingAdmission{})
 var _ = admission.ValidationInterface(&apiBindingAdmission{})
 var _ = admission.InitializationValidator(&apiBindingAdmission{})
+var _ = kcpinitializers.WantsKcpInformers(&apiBindingAdmission{})
+var _ = kcpinitializers.WantsApiExtensionsInformers(&apiBindingAdmission{})
+
+// Admit reports on dry-run creation of APIBindings which resources would be bound, and which
+// conflicts with CRDs and other APIBindings in the workspace would prevent that. The report is
+// set as JSON in the apis.kcp.dev/dry-run-report annotation, and returned as warnings.
+func (o *apiBindingAdmission) Admit(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
+	if a.GetResource().GroupResource() != apisv1alpha1.Resource("apibindings") {
+		return nil
+	}
+	if a.GetOperation() != admission.Create || !a.IsDryRun() || a.GetSubresource() != "" {
+		return nil
+	}
+
+	u, ok := a.GetObject().(*unstructured.Unstructured)
+	if !ok {
+		return fmt.Errorf("unexpected type %T", a.GetObject())
+	}
+
+	apiBinding := &apisv1alpha1.APIBinding{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, apiBinding); err != nil {
+		return fmt.Errorf("failed to convert unstructured to APIBinding: %w", err)
+	}
+
+	// invalid references are rejected in validation
+	if apiBinding.Spec.Reference.Workspace == nil || apiBinding.Spec.Reference.Workspace.ExportName == "" {
+		return nil
+	}
+	cluster, err := genericapirequest.ValidClusterFrom(ctx)
+	if err != nil {
+		return admission.NewForbidden(a, fmt.Errorf("error determining workspace: %w", err))
+	}
+	org, hasParent := cluster.Name.Parent()
+	if !hasParent {
+		return nil
+	}
+	apiExportClusterName := org.Join(apiBinding.Spec.Reference.Workspace.WorkspaceName)
+
+	if !o.WaitForReady() {
+		return admission.NewForbidden(a, fmt.Errorf("not yet ready to handle request"))
+	}
+	report, err := o.dryRun(cluster.Name, apiBinding, apiExportClusterName)
+	if err != nil {
+		return admission.NewForbidden(a, fmt.Errorf("failed to compute dry-run report: %w", err))
+	}
+	bs, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	annotations := u.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[apisv1alpha1.AnnotationDryRunReportKey] = string(bs)
+	u.SetAnnotations(annotations)
+
+	for _, w := range report.warnings() {
+		warning.AddWarning(ctx, "", w)
+	}
+
+	return nil
+}
 
 // Validate validates the creation and updating of APIBinding resources. It also performs a SubjectAccessReview
 // making sure the user is allowed to use the 'bind' verb with the referenced APIExport.
@@ -127,6 +206,22 @@ func (o *apiBindingAdmission) Validate(ctx context.Context, a admission.Attribut
 		return admission.NewForbidden(a, fmt.Errorf("unable to %s APIImport: %w", action, err))
 	}
 
+	// Warn about CRDs in the workspace overlapping the bound resources. On dry-run, Admit reports them already.
+	if a.GetOperation() == admission.Create && !a.IsDryRun() {
+		if !o.WaitForReady() {
+			return admission.NewForbidden(a, fmt.Errorf("not yet ready to handle request"))
+		}
+		report, err := o.dryRun(cluster.Name, apiBinding, apiExportClusterName)
+		if err != nil {
+			return admission.NewForbidden(a, fmt.Errorf("failed to check for conflicts: %w", err))
+		}
+		for _, c := range report.Conflicts {
+			if c.Kind == "CustomResourceDefinition" {
+				warning.AddWarning(ctx, "", c.Message)
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -163,6 +258,12 @@ func (o *apiBindingAdmission) ValidateInitialization() error {
 	if o.kubeClusterClient == nil {
 		return fmt.Errorf(PluginName + " plugin needs a Kubernetes ClusterInterface")
 	}
+	if o.getAPIExport == nil {
+		return fmt.Errorf(PluginName + " plugin needs kcp informers")
+	}
+	if o.getCRD == nil {
+		return fmt.Errorf(PluginName + " plugin needs apiextensions informers")
+	}
 
 	return nil
 }
@@ -172,3 +273,50 @@ func (o *apiBindingAdmission) ValidateInitialization() error {
 func (o *apiBindingAdmission) SetKubeClusterClient(clusterClient *kubernetes.Cluster) {
 	o.kubeClusterClient = clusterClient
 }
+
+func (o *apiBindingAdmission) SetKcpInformers(informers kcpinformers.SharedInformerFactory) {
+	apiExportInformer := informers.Apis().V1alpha1().APIExports()
+	apiResourceSchemaInformer := informers.Apis().V1alpha1().APIResourceSchemas()
+	apiBindingInformer := informers.Apis().V1alpha1().APIBindings()
+	o.kcpInformersSynced = func() bool {
+		return apiExportInformer.Informer().HasSynced() &&
+			apiResourceSchemaInformer.Informer().HasSynced() &&
+			apiBindingInformer.Informer().HasSynced()
+	}
+	o.SetReadyFunc(o.informersSynced)
+
+	o.getAPIExport = func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error) {
+		return apiExportInformer.Lister().Get(clusters.ToClusterAwareKey(clusterName, name))
+	}
+	o.getAPIResourceSchema = func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error) {
+		return apiResourceSchemaInformer.Lister().Get(clusters.ToClusterAwareKey(clusterName, name))
+	}
+	o.listAPIBindings = func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
+		list, err := apiBindingInformer.Lister().List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+		var ret []*apisv1alpha1.APIBinding
+		for _, b := range list {
+			if logicalcluster.From(b) == clusterName {
+				ret = append(ret, b)
+			}
+		}
+		return ret, nil
+	}
+}
+
+func (o *apiBindingAdmission) SetApiExtensionsInformers(informers apiextensionsinformers.SharedInformerFactory) {
+	crdInformer := informers.Apiextensions().V1().CustomResourceDefinitions()
+	o.crdInformerSynced = crdInformer.Informer().HasSynced
+	o.SetReadyFunc(o.informersSynced)
+
+	o.getCRD = func(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error) {
+		return crdInformer.Lister().Get(clusters.ToClusterAwareKey(clusterName, name))
+	}
+}
+
+func (o *apiBindingAdmission) informersSynced() bool {
+	return (o.kcpInformersSynced == nil || o.kcpInformersSynced()) &&
+		(o.crdInformerSynced == nil || o.crdInformerSynced())
+}
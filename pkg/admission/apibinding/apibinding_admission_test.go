@@ -18,13 +18,17 @@ package apibinding
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"testing"
 
 	"github.com/kcp-dev/logicalcluster"
 	"github.com/stretchr/testify/require"
 
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apiserver/pkg/admission"
 	"k8s.io/apiserver/pkg/authentication/user"
 	"k8s.io/apiserver/pkg/authorization/authorizer"
@@ -33,6 +37,7 @@ import (
 
 	"github.com/kcp-dev/kcp/pkg/admission/helpers"
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	apibindingreconciler "github.com/kcp-dev/kcp/pkg/reconciler/apis/apibinding"
 )
 
 func createAttr(apiBinding *apisv1alpha1.APIBinding) admission.Attributes {
@@ -51,6 +56,22 @@ func createAttr(apiBinding *apisv1alpha1.APIBinding) admission.Attributes {
 	)
 }
 
+func dryRunCreateAttr(apiBinding *apisv1alpha1.APIBinding) admission.Attributes {
+	return admission.NewAttributesRecord(
+		helpers.ToUnstructuredOrDie(apiBinding),
+		nil,
+		apisv1alpha1.Kind("APIBinding").WithVersion("v1alpha1"),
+		"",
+		apiBinding.Name,
+		apisv1alpha1.Resource("apibindings").WithVersion("v1alpha1"),
+		"",
+		admission.Create,
+		&metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}},
+		true,
+		&user.DefaultInfo{},
+	)
+}
+
 func updateAttr(newAPIBinding, oldAPIBinding *apisv1alpha1.APIBinding) admission.Attributes {
 	return admission.NewAttributesRecord(
 		helpers.ToUnstructuredOrDie(newAPIBinding),
@@ -252,6 +273,9 @@ func TestValidate(t *testing.T) {
 						tc.authzError,
 					}, nil
 				},
+				getAPIExport: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error) {
+					return nil, apierrors.NewNotFound(apisv1alpha1.Resource("apiexports"), name)
+				},
 			}
 
 			ctx := request.WithCluster(context.Background(), request.Cluster{Name: logicalcluster.New("root:org")})
@@ -271,6 +295,196 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestAdmitDryRun(t *testing.T) {
+	export := &apisv1alpha1.APIExport{
+		ObjectMeta: metav1.ObjectMeta{ClusterName: "root:org:provider", Name: "export"},
+		Spec: apisv1alpha1.APIExportSpec{
+			LatestResourceSchemas: []string{"today.widgets.example.io", "today.gadgets.example.io"},
+		},
+		Status: apisv1alpha1.APIExportStatus{IdentityHash: "hash"},
+	}
+	schemas := map[string]*apisv1alpha1.APIResourceSchema{
+		"today.widgets.example.io": newSchema("widgets", "Widget", apiextensionsv1.NamespaceScoped),
+		"today.gadgets.example.io": newSchema("gadgets", "Gadget", apiextensionsv1.ClusterScoped),
+	}
+	widgets := DryRunResource{Group: "example.io", Resource: "widgets", Kind: "Widget", Scope: apiextensionsv1.NamespaceScoped, Versions: []string{"v1"}}
+	gadgets := DryRunResource{Group: "example.io", Resource: "gadgets", Kind: "Gadget", Scope: apiextensionsv1.ClusterScoped, Versions: []string{"v1"}}
+
+	tests := []struct {
+		name        string
+		attr        admission.Attributes
+		apiExport   *apisv1alpha1.APIExport
+		apiBindings []*apisv1alpha1.APIBinding
+		crds        map[string]*apiextensionsv1.CustomResourceDefinition
+		want        *DryRunReport
+	}{
+		{
+			name:      "no report without dry-run",
+			attr:      createAttr(newAPIBinding().withName("test").withWorkspaceReference("provider", "export").APIBinding),
+			apiExport: export,
+		},
+		{
+			name: "missing APIExport",
+			attr: dryRunCreateAttr(newAPIBinding().withName("test").withWorkspaceReference("provider", "export").APIBinding),
+			want: &DryRunReport{
+				APIExport: "root:org:provider|export",
+				Errors:    []string{"APIExport root:org:provider|export not found"},
+			},
+		},
+		{
+			name:      "resources without conflicts",
+			attr:      dryRunCreateAttr(newAPIBinding().withName("test").withWorkspaceReference("provider", "export").APIBinding),
+			apiExport: export,
+			want: &DryRunReport{
+				APIExport: "root:org:provider|export",
+				Resources: []DryRunResource{widgets, gadgets},
+			},
+		},
+		{
+			name:      "conflict with local CRD",
+			attr:      dryRunCreateAttr(newAPIBinding().withName("test").withWorkspaceReference("provider", "export").APIBinding),
+			apiExport: export,
+			crds: map[string]*apiextensionsv1.CustomResourceDefinition{
+				"root:org:ws|widgets.example.io": {},
+			},
+			want: &DryRunReport{
+				APIExport: "root:org:provider|export",
+				Resources: []DryRunResource{widgets, gadgets},
+				Conflicts: []DryRunConflict{{
+					Group:    "example.io",
+					Resource: "widgets",
+					Kind:     "CustomResourceDefinition",
+					Name:     "widgets.example.io",
+					Message:  "widgets.example.io is already defined by CustomResourceDefinition widgets.example.io in the workspace, which would not be served anymore. Delete the CustomResourceDefinition, or set spec.conflictPolicy to CRD to keep serving it",
+				}},
+			},
+		},
+		{
+			name:      "conflict with local CRD preferred by conflict policy",
+			attr:      dryRunCreateAttr(newAPIBinding().withName("test").withWorkspaceReference("provider", "export").withConflictPolicy(apisv1alpha1.CRDConflictPolicyCRD).APIBinding),
+			apiExport: export,
+			crds: map[string]*apiextensionsv1.CustomResourceDefinition{
+				"root:org:ws|gadgets.example.io": {},
+			},
+			want: &DryRunReport{
+				APIExport: "root:org:provider|export",
+				Resources: []DryRunResource{widgets, gadgets},
+				Conflicts: []DryRunConflict{{
+					Group:    "example.io",
+					Resource: "gadgets",
+					Kind:     "CustomResourceDefinition",
+					Name:     "gadgets.example.io",
+					Message:  "gadgets.example.io is already defined by CustomResourceDefinition gadgets.example.io in the workspace, hence the bound resource would not be served. Delete the CustomResourceDefinition, or set spec.conflictPolicy to APIBinding to serve the bound resource instead",
+				}},
+			},
+		},
+		{
+			name:      "conflict with names bound by other APIBinding",
+			attr:      dryRunCreateAttr(newAPIBinding().withName("test").withWorkspaceReference("provider", "export").APIBinding),
+			apiExport: export,
+			apiBindings: []*apisv1alpha1.APIBinding{
+				newAPIBinding().withName("other").withBoundResource("other.io", "gizmos", "uid-gizmos").APIBinding,
+				newAPIBinding().withName("test").withBoundResource("example.io", "widgets", "uid-widgets").APIBinding,
+			},
+			crds: map[string]*apiextensionsv1.CustomResourceDefinition{
+				apibindingreconciler.ShadowWorkspaceName.String() + "|uid-gizmos": {
+					Spec: apiextensionsv1.CustomResourceDefinitionSpec{Group: "other.io", Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "gizmos"}},
+					Status: apiextensionsv1.CustomResourceDefinitionStatus{
+						AcceptedNames: apiextensionsv1.CustomResourceDefinitionNames{Plural: "gizmos", Kind: "Gadget"},
+					},
+				},
+				apibindingreconciler.ShadowWorkspaceName.String() + "|uid-widgets": {
+					Spec: apiextensionsv1.CustomResourceDefinitionSpec{Group: "example.io", Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets"}},
+					Status: apiextensionsv1.CustomResourceDefinitionStatus{
+						AcceptedNames: apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets", Kind: "Widget"},
+					},
+				},
+			},
+			want: &DryRunReport{
+				APIExport: "root:org:provider|export",
+				Resources: []DryRunResource{widgets, gadgets},
+				Conflicts: []DryRunConflict{{
+					Group:    "example.io",
+					Resource: "gadgets",
+					Kind:     "APIBinding",
+					Name:     "other",
+					Message:  "names of gadgets.example.io conflict with gizmos.other.io bound by APIBinding other",
+				}},
+			},
+		},
+		{
+			name: "APIExport without identity",
+			attr: dryRunCreateAttr(newAPIBinding().withName("test").withWorkspaceReference("provider", "export").APIBinding),
+			apiExport: &apisv1alpha1.APIExport{
+				ObjectMeta: metav1.ObjectMeta{ClusterName: "root:org:provider", Name: "export"},
+			},
+			want: &DryRunReport{
+				APIExport: "root:org:provider|export",
+				Errors:    []string{"APIExport root:org:provider|export is missing status.identityHash"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o := &apiBindingAdmission{
+				Handler: admission.NewHandler(admission.Create, admission.Update),
+				getAPIExport: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error) {
+					if tc.apiExport == nil || logicalcluster.From(tc.apiExport) != clusterName || tc.apiExport.Name != name {
+						return nil, apierrors.NewNotFound(apisv1alpha1.Resource("apiexports"), name)
+					}
+					return tc.apiExport, nil
+				},
+				getAPIResourceSchema: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error) {
+					if schema, found := schemas[name]; found {
+						return schema, nil
+					}
+					return nil, apierrors.NewNotFound(apisv1alpha1.Resource("apiresourceschemas"), name)
+				},
+				listAPIBindings: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
+					return tc.apiBindings, nil
+				},
+				getCRD: func(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error) {
+					if crd, found := tc.crds[clusterName.String()+"|"+name]; found {
+						return crd, nil
+					}
+					return nil, apierrors.NewNotFound(apiextensionsv1.Resource("customresourcedefinitions"), name)
+				},
+			}
+
+			ctx := request.WithCluster(context.Background(), request.Cluster{Name: logicalcluster.New("root:org:ws")})
+
+			err := o.Admit(ctx, tc.attr, nil)
+			require.NoError(t, err)
+
+			annotations := tc.attr.GetObject().(*unstructured.Unstructured).GetAnnotations()
+			if tc.want == nil {
+				require.NotContains(t, annotations, apisv1alpha1.AnnotationDryRunReportKey)
+				return
+			}
+			require.Contains(t, annotations, apisv1alpha1.AnnotationDryRunReportKey)
+			var got DryRunReport
+			require.NoError(t, json.Unmarshal([]byte(annotations[apisv1alpha1.AnnotationDryRunReportKey]), &got))
+			require.Equal(t, tc.want, &got)
+		})
+	}
+}
+
+func newSchema(plural, kind string, scope apiextensionsv1.ResourceScope) *apisv1alpha1.APIResourceSchema {
+	return &apisv1alpha1.APIResourceSchema{
+		ObjectMeta: metav1.ObjectMeta{ClusterName: "root:org:provider", Name: "today." + plural + ".example.io"},
+		Spec: apisv1alpha1.APIResourceSchemaSpec{
+			Group: "example.io",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: plural, Kind: kind},
+			Scope: scope,
+			Versions: []apisv1alpha1.APIResourceVersion{
+				{Name: "v1", Served: true, Storage: true},
+				{Name: "v0", Served: false},
+			},
+		},
+	}
+}
+
 type fakeAuthorizer struct {
 	authorized authorizer.Decision
 	err        error
@@ -303,6 +517,20 @@ func (b *bindingBuilder) withWorkspaceReference(workspaceName, exportName string
 	return b
 }
 
+func (b *bindingBuilder) withBoundResource(group, resource, schemaUID string) *bindingBuilder {
+	b.Status.BoundResources = append(b.Status.BoundResources, apisv1alpha1.BoundAPIResource{
+		Group:    group,
+		Resource: resource,
+		Schema:   apisv1alpha1.BoundAPIResourceSchema{UID: schemaUID},
+	})
+	return b
+}
+
+func (b *bindingBuilder) withConflictPolicy(policy apisv1alpha1.CRDConflictPolicy) *bindingBuilder {
+	b.Spec.ConflictPolicy = policy
+	return b
+}
+
 func (b *bindingBuilder) withPhase(phase apisv1alpha1.APIBindingPhaseType) *bindingBuilder {
 	b.Status.Phase = phase
 	return b
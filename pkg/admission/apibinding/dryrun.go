@@ -0,0 +1,191 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apibinding
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	apibindingreconciler "github.com/kcp-dev/kcp/pkg/reconciler/apis/apibinding"
+)
+
+// DryRunReport describes what creating an APIBinding would do. It is set as JSON in the
+// apis.kcp.dev/dry-run-report annotation of APIBindings created with dry-run.
+type DryRunReport struct {
+	// APIExport is the referenced APIExport, as <workspace>|<name>.
+	APIExport string `json:"apiExport"`
+
+	// Resources are the resources that would appear in the workspace.
+	Resources []DryRunResource `json:"resources,omitempty"`
+
+	// Conflicts are the conflicts with CRDs and other APIBindings in the workspace.
+	Conflicts []DryRunConflict `json:"conflicts,omitempty"`
+
+	// Errors are problems that would prevent binding altogether, e.g. a missing APIExport.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// DryRunResource is a resource that would be bound.
+type DryRunResource struct {
+	Group    string                        `json:"group"`
+	Resource string                        `json:"resource"`
+	Kind     string                        `json:"kind"`
+	Scope    apiextensionsv1.ResourceScope `json:"scope"`
+	// Versions are the served versions.
+	Versions []string `json:"versions"`
+}
+
+// DryRunConflict is a conflict of a resource that would be bound with an existing object.
+type DryRunConflict struct {
+	Group    string `json:"group"`
+	Resource string `json:"resource"`
+	// Kind is the kind of the conflicting object, i.e. APIBinding or CustomResourceDefinition.
+	Kind string `json:"kind"`
+	// Name is the name of the conflicting object.
+	Name    string `json:"name"`
+	Message string `json:"message"`
+}
+
+// dryRun computes the report for creating apiBinding in clusterName, binding the APIExport in
+// apiExportClusterName. It only reads from informers, i.e. it does not change anything.
+func (o *apiBindingAdmission) dryRun(clusterName logicalcluster.Name, apiBinding *apisv1alpha1.APIBinding, apiExportClusterName logicalcluster.Name) (*DryRunReport, error) {
+	exportName := apiBinding.Spec.Reference.Workspace.ExportName
+	report := &DryRunReport{
+		APIExport: fmt.Sprintf("%s|%s", apiExportClusterName, exportName),
+	}
+
+	apiExport, err := o.getAPIExport(apiExportClusterName, exportName)
+	if apierrors.IsNotFound(err) {
+		report.Errors = append(report.Errors, fmt.Sprintf("APIExport %s not found", report.APIExport))
+		return report, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if apiExport.Status.IdentityHash == "" {
+		report.Errors = append(report.Errors, fmt.Sprintf("APIExport %s is missing status.identityHash", report.APIExport))
+	}
+
+	// the CRDs of the resources bound by other APIBindings, as in the naming conflict check of the APIBinding controller
+	apiBindings, err := o.listAPIBindings(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	boundCRDs := map[*apiextensionsv1.CustomResourceDefinition]string{}
+	for _, other := range apiBindings {
+		if other.Name == apiBinding.Name {
+			continue
+		}
+		for _, boundResource := range other.Status.BoundResources {
+			crd, err := o.getCRD(apibindingreconciler.ShadowWorkspaceName, boundResource.Schema.UID)
+			if apierrors.IsNotFound(err) {
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+			boundCRDs[crd] = other.Name
+		}
+	}
+
+	for _, schemaName := range apiExport.Spec.LatestResourceSchemas {
+		schema, err := o.getAPIResourceSchema(apiExportClusterName, schemaName)
+		if apierrors.IsNotFound(err) {
+			report.Errors = append(report.Errors, fmt.Sprintf("APIResourceSchema %s|%s of the APIExport not found", apiExportClusterName, schemaName))
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		resource := DryRunResource{
+			Group:    schema.Spec.Group,
+			Resource: schema.Spec.Names.Plural,
+			Kind:     schema.Spec.Names.Kind,
+			Scope:    schema.Spec.Scope,
+		}
+		for _, v := range schema.Spec.Versions {
+			if v.Served {
+				resource.Versions = append(resource.Versions, v.Name)
+			}
+		}
+		report.Resources = append(report.Resources, resource)
+
+		incoming := &apiextensionsv1.CustomResourceDefinition{
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group: schema.Spec.Group,
+				Names: schema.Spec.Names,
+			},
+		}
+		var conflicts []DryRunConflict
+		for crd, bindingName := range boundCRDs {
+			if apibindingreconciler.NamesConflict(crd, incoming) {
+				conflicts = append(conflicts, DryRunConflict{
+					Group:    resource.Group,
+					Resource: resource.Resource,
+					Kind:     "APIBinding",
+					Name:     bindingName,
+					Message:  fmt.Sprintf("names of %s.%s conflict with %s.%s bound by APIBinding %s", resource.Resource, resource.Group, crd.Spec.Names.Plural, crd.Spec.Group, bindingName),
+				})
+			}
+		}
+		sort.Slice(conflicts, func(i, j int) bool {
+			if conflicts[i].Name != conflicts[j].Name {
+				return conflicts[i].Name < conflicts[j].Name
+			}
+			return conflicts[i].Message < conflicts[j].Message
+		})
+		report.Conflicts = append(report.Conflicts, conflicts...)
+
+		crdName := apibindingreconciler.CRDNameForGroupResource(resource.Group, resource.Resource)
+		if _, err := o.getCRD(clusterName, crdName); err == nil {
+			message := fmt.Sprintf("%s.%s is already defined by CustomResourceDefinition %s in the workspace, which would not be served anymore. Delete the CustomResourceDefinition, or set spec.conflictPolicy to CRD to keep serving it", resource.Resource, resource.Group, crdName)
+			if apibindingreconciler.ConflictPolicy(apiBinding) == apisv1alpha1.CRDConflictPolicyCRD {
+				message = fmt.Sprintf("%s.%s is already defined by CustomResourceDefinition %s in the workspace, hence the bound resource would not be served. Delete the CustomResourceDefinition, or set spec.conflictPolicy to APIBinding to serve the bound resource instead", resource.Resource, resource.Group, crdName)
+			}
+			report.Conflicts = append(report.Conflicts, DryRunConflict{
+				Group:    resource.Group,
+				Resource: resource.Resource,
+				Kind:     "CustomResourceDefinition",
+				Name:     crdName,
+				Message:  message,
+			})
+		} else if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+	}
+
+	return report, nil
+}
+
+// warnings returns the human readable warnings for the report.
+func (r *DryRunReport) warnings() []string {
+	var ret []string
+	for _, res := range r.Resources {
+		ret = append(ret, fmt.Sprintf("would bind %s.%s (%s) in versions %v", res.Resource, res.Group, res.Scope, res.Versions))
+	}
+	for _, c := range r.Conflicts {
+		ret = append(ret, "conflict: "+c.Message)
+	}
+	for _, e := range r.Errors {
+		ret = append(ret, "error: "+e)
+	}
+	return ret
+}
@@ -0,0 +1,291 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiexportdefaults
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/admission/initializer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clusters"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	kcpinitializers "github.com/kcp-dev/kcp/pkg/admission/initializers"
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	kcpinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
+)
+
+const (
+	PluginName = "apis.kcp.dev/APIExportDefaults"
+
+	byWorkspaceIndex = "apiExportDefaults-byWorkspace"
+)
+
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName,
+		func(_ io.Reader) (admission.Interface, error) {
+			return &apiExportDefaults{
+				Handler: admission.NewHandler(admission.Create),
+			}, nil
+		})
+}
+
+// apiExportDefaults merges the defaults designated by an APIExport into new objects of
+// the resources bound from it.
+type apiExportDefaults struct {
+	*admission.Handler
+
+	listAPIBindings func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error)
+	getAPIExport    func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error)
+	getConfigMap    func(clusterName logicalcluster.Name, namespace, name string) (*corev1.ConfigMap, error)
+
+	kcpInformersSynced  func() bool
+	configMapsHasSynced func() bool
+}
+
+// Ensure that the required admission interfaces are implemented.
+var _ = admission.MutationInterface(&apiExportDefaults{})
+var _ = admission.InitializationValidator(&apiExportDefaults{})
+var _ = kcpinitializers.WantsKcpInformers(&apiExportDefaults{})
+var _ = initializer.WantsExternalKubeInformerFactory(&apiExportDefaults{})
+
+// Admit merges the defaults of the APIExport into objects of bound resources on creation.
+func (o *apiExportDefaults) Admit(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
+	if a.GetSubresource() != "" {
+		return nil
+	}
+	// bound resources are always served as unstructured objects
+	u, ok := a.GetObject().(*unstructured.Unstructured)
+	if !ok {
+		return nil
+	}
+
+	clusterName, err := genericapirequest.ClusterNameFrom(ctx)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	if _, hasParent := clusterName.Parent(); !hasParent {
+		// APIBindings in root are not possible (they can only point to sibling workspaces).
+		return nil
+	}
+
+	if !o.WaitForReady() {
+		return admission.NewForbidden(a, fmt.Errorf("not yet ready to handle request"))
+	}
+
+	exportClusterName, defaults, err := o.resourceDefaults(clusterName, a.GetResource().GroupResource())
+	if err != nil {
+		return admission.NewForbidden(a, err)
+	}
+	if defaults == nil {
+		return nil
+	}
+
+	values, err := o.defaultValues(exportClusterName, clusterName, defaults.ConfigMapRef)
+	if err != nil {
+		return admission.NewForbidden(a, err)
+	}
+	for _, v := range values {
+		mergeDefaults(u.Object, v)
+	}
+
+	return nil
+}
+
+// resourceDefaults returns the defaults of the given resource, and the logical cluster of the APIExport
+// designating them, or nil if the resource is not bound or has no defaults.
+func (o *apiExportDefaults) resourceDefaults(clusterName logicalcluster.Name, gr schema.GroupResource) (logicalcluster.Name, *apisv1alpha1.ResourceDefaults, error) {
+	bindings, err := o.listAPIBindings(clusterName)
+	if err != nil {
+		return logicalcluster.Name{}, nil, err
+	}
+
+	parentClusterName, _ := clusterName.Parent()
+	for _, binding := range bindings {
+		if binding.Status.BoundAPIExport == nil || binding.Status.BoundAPIExport.Workspace == nil {
+			continue
+		}
+		for _, br := range binding.Status.BoundResources {
+			if br.Group != gr.Group || br.Resource != gr.Resource {
+				continue
+			}
+
+			exportClusterName := parentClusterName.Join(binding.Status.BoundAPIExport.Workspace.WorkspaceName)
+			export, err := o.getAPIExport(exportClusterName, binding.Status.BoundAPIExport.Workspace.ExportName)
+			if apierrors.IsNotFound(err) {
+				return logicalcluster.Name{}, nil, nil
+			} else if err != nil {
+				return logicalcluster.Name{}, nil, err
+			}
+
+			for i := range export.Spec.Defaults {
+				if d := &export.Spec.Defaults[i]; d.Group == gr.Group && d.Resource == gr.Resource {
+					return exportClusterName, d, nil
+				}
+			}
+			return logicalcluster.Name{}, nil, nil
+		}
+	}
+
+	return logicalcluster.Name{}, nil, nil
+}
+
+// defaultValues returns the defaults for the given consuming workspace, followed by the
+// defaults for all workspaces. Missing ConfigMaps are skipped.
+func (o *apiExportDefaults) defaultValues(exportClusterName, clusterName logicalcluster.Name, ref apisv1alpha1.ConfigMapReference) ([]map[string]interface{}, error) {
+	names := []string{
+		WorkspaceConfigMapName(ref.Name, clusterName),
+		ref.Name,
+	}
+
+	var values []map[string]interface{}
+	for _, name := range names {
+		cm, err := o.getConfigMap(exportClusterName, ref.Namespace, name)
+		if apierrors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		raw, found := cm.Data[apisv1alpha1.DefaultsConfigMapKey]
+		if !found || strings.TrimSpace(raw) == "" {
+			continue
+		}
+		bs, err := yaml.YAMLToJSON([]byte(raw))
+		if err != nil {
+			return nil, fmt.Errorf("invalid defaults in ConfigMap %s/%s of APIExport workspace %s: %w", ref.Namespace, name, exportClusterName, err)
+		}
+		var value map[string]interface{}
+		if err := utiljson.Unmarshal(bs, &value); err != nil {
+			return nil, fmt.Errorf("invalid defaults in ConfigMap %s/%s of APIExport workspace %s: %w", ref.Namespace, name, exportClusterName, err)
+		}
+
+		// the identity of the object cannot be defaulted
+		delete(value, "apiVersion")
+		delete(value, "kind")
+		delete(value, "metadata")
+
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+// WorkspaceConfigMapName returns the name of the ConfigMap holding the defaults for the
+// given consuming workspace.
+func WorkspaceConfigMapName(name string, clusterName logicalcluster.Name) string {
+	return name + "." + strings.ReplaceAll(clusterName.String(), ":", ".")
+}
+
+// mergeDefaults sets the fields of defaults which are not set in obj. Nested objects are
+// merged recursively, all other values, including lists, are taken as a whole.
+func mergeDefaults(obj, defaults map[string]interface{}) {
+	for k, dv := range defaults {
+		ov, found := obj[k]
+		if !found {
+			obj[k] = runtime.DeepCopyJSONValue(dv)
+			continue
+		}
+		om, ok := ov.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if dm, ok := dv.(map[string]interface{}); ok {
+			mergeDefaults(om, dm)
+		}
+	}
+}
+
+// ValidateInitialization ensures the required injected fields are set.
+func (o *apiExportDefaults) ValidateInitialization() error {
+	if o.listAPIBindings == nil || o.getAPIExport == nil {
+		return fmt.Errorf(PluginName + " plugin needs kcp informers")
+	}
+	if o.getConfigMap == nil {
+		return fmt.Errorf(PluginName + " plugin needs a ConfigMap informer")
+	}
+	return nil
+}
+
+// SetKcpInformers implements the WantsKcpInformers interface.
+func (o *apiExportDefaults) SetKcpInformers(f kcpinformers.SharedInformerFactory) {
+	apiBindingsInformer := f.Apis().V1alpha1().APIBindings().Informer()
+	if _, found := apiBindingsInformer.GetIndexer().GetIndexers()[byWorkspaceIndex]; !found {
+		if err := apiBindingsInformer.AddIndexers(cache.Indexers{
+			byWorkspaceIndex: func(obj interface{}) ([]string, error) {
+				return []string{logicalcluster.From(obj.(metav1.Object)).String()}, nil
+			},
+		}); err != nil {
+			// nothing we can do here. But this should also never happen. We check for existence before.
+			klog.Errorf("failed to add indexer for APIBindings: %v", err)
+		}
+	}
+	apiBindingsIndexer := apiBindingsInformer.GetIndexer()
+	o.listAPIBindings = func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
+		objs, err := apiBindingsIndexer.ByIndex(byWorkspaceIndex, clusterName.String())
+		if err != nil {
+			return nil, err
+		}
+		bindings := make([]*apisv1alpha1.APIBinding, 0, len(objs))
+		for _, obj := range objs {
+			bindings = append(bindings, obj.(*apisv1alpha1.APIBinding))
+		}
+		return bindings, nil
+	}
+
+	apiExportLister := f.Apis().V1alpha1().APIExports().Lister()
+	o.getAPIExport = func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error) {
+		return apiExportLister.Get(clusters.ToClusterAwareKey(clusterName, name))
+	}
+
+	apiExportsInformer := f.Apis().V1alpha1().APIExports().Informer()
+	o.kcpInformersSynced = func() bool {
+		return apiBindingsInformer.HasSynced() && apiExportsInformer.HasSynced()
+	}
+	o.SetReadyFunc(o.hasSynced)
+}
+
+// SetExternalKubeInformerFactory implements the WantsExternalKubeInformerFactory interface.
+func (o *apiExportDefaults) SetExternalKubeInformerFactory(f informers.SharedInformerFactory) {
+	configMapLister := f.Core().V1().ConfigMaps().Lister()
+	o.getConfigMap = func(clusterName logicalcluster.Name, namespace, name string) (*corev1.ConfigMap, error) {
+		return configMapLister.ConfigMaps(namespace).Get(clusters.ToClusterAwareKey(clusterName, name))
+	}
+	o.configMapsHasSynced = f.Core().V1().ConfigMaps().Informer().HasSynced
+	o.SetReadyFunc(o.hasSynced)
+}
+
+func (o *apiExportDefaults) hasSynced() bool {
+	return o.kcpInformersSynced != nil && o.kcpInformersSynced() &&
+		o.configMapsHasSynced != nil && o.configMapsHasSynced()
+}
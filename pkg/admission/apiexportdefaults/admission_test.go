@@ -0,0 +1,209 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiexportdefaults
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+)
+
+func createAttr(resource string, obj map[string]interface{}) admission.Attributes {
+	u := &unstructured.Unstructured{Object: obj}
+	return admission.NewAttributesRecord(
+		u,
+		nil,
+		schema.GroupVersionKind{Group: "wildwest.dev", Version: "v1alpha1", Kind: "Cowboy"},
+		"default",
+		"test",
+		schema.GroupVersionResource{Group: "wildwest.dev", Version: "v1alpha1", Resource: resource},
+		"",
+		admission.Create,
+		&metav1.CreateOptions{},
+		false,
+		&user.DefaultInfo{},
+	)
+}
+
+func TestAdmit(t *testing.T) {
+	binding := &apisv1alpha1.APIBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "cowboys", ClusterName: "root:org:consumer"},
+		Status: apisv1alpha1.APIBindingStatus{
+			BoundAPIExport: &apisv1alpha1.ExportReference{
+				Workspace: &apisv1alpha1.WorkspaceExportReference{WorkspaceName: "provider", ExportName: "wildwest"},
+			},
+			BoundResources: []apisv1alpha1.BoundAPIResource{
+				{Group: "wildwest.dev", Resource: "cowboys"},
+				{Group: "wildwest.dev", Resource: "sheriffs"},
+			},
+		},
+	}
+	export := &apisv1alpha1.APIExport{
+		ObjectMeta: metav1.ObjectMeta{Name: "wildwest", ClusterName: "root:org:provider"},
+		Spec: apisv1alpha1.APIExportSpec{
+			Defaults: []apisv1alpha1.ResourceDefaults{
+				{Group: "wildwest.dev", Resource: "cowboys", ConfigMapRef: apisv1alpha1.ConfigMapReference{Namespace: "defaults", Name: "cowboys"}},
+			},
+		},
+	}
+	globalDefaults := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "defaults", Name: "cowboys"},
+		Data: map[string]string{
+			"defaults": "metadata:\n  labels:\n    tier: free\nspec:\n  tier: free\n  limits:\n    horses: 1\n    cows: 10\n  tags: [\"default\"]\n",
+		},
+	}
+	workspaceDefaults := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "defaults", Name: "cowboys.root.org.consumer"},
+		Data: map[string]string{
+			"defaults": `{"spec":{"tier":"gold","limits":{"horses":5}}}`,
+		},
+	}
+
+	tests := []struct {
+		name       string
+		cluster    string
+		resource   string
+		obj        map[string]interface{}
+		configMaps []*corev1.ConfigMap
+		want       map[string]interface{}
+		wantErr    bool
+	}{
+		{
+			name:       "defaults of all workspaces are merged",
+			cluster:    "root:org:consumer",
+			resource:   "cowboys",
+			obj:        map[string]interface{}{"spec": map[string]interface{}{"intent": "ride"}},
+			configMaps: []*corev1.ConfigMap{globalDefaults},
+			want: map[string]interface{}{"spec": map[string]interface{}{
+				"intent": "ride",
+				"tier":   "free",
+				"limits": map[string]interface{}{"horses": int64(1), "cows": int64(10)},
+				"tags":   []interface{}{"default"},
+			}},
+		},
+		{
+			name:       "defaults of the workspace take precedence",
+			cluster:    "root:org:consumer",
+			resource:   "cowboys",
+			obj:        map[string]interface{}{},
+			configMaps: []*corev1.ConfigMap{globalDefaults, workspaceDefaults},
+			want: map[string]interface{}{"spec": map[string]interface{}{
+				"tier":   "gold",
+				"limits": map[string]interface{}{"horses": int64(5), "cows": int64(10)},
+				"tags":   []interface{}{"default"},
+			}},
+		},
+		{
+			name:       "fields of the object take precedence",
+			cluster:    "root:org:consumer",
+			resource:   "cowboys",
+			obj:        map[string]interface{}{"spec": map[string]interface{}{"tier": "silver", "tags": []interface{}{}}},
+			configMaps: []*corev1.ConfigMap{globalDefaults},
+			want: map[string]interface{}{"spec": map[string]interface{}{
+				"tier":   "silver",
+				"limits": map[string]interface{}{"horses": int64(1), "cows": int64(10)},
+				"tags":   []interface{}{},
+			}},
+		},
+		{
+			name:       "bound resource without defaults is untouched",
+			cluster:    "root:org:consumer",
+			resource:   "sheriffs",
+			obj:        map[string]interface{}{"spec": map[string]interface{}{}},
+			configMaps: []*corev1.ConfigMap{globalDefaults},
+			want:       map[string]interface{}{"spec": map[string]interface{}{}},
+		},
+		{
+			name:       "resource in workspace without bindings is untouched",
+			cluster:    "root:org:other",
+			resource:   "cowboys",
+			obj:        map[string]interface{}{"spec": map[string]interface{}{}},
+			configMaps: []*corev1.ConfigMap{globalDefaults},
+			want:       map[string]interface{}{"spec": map[string]interface{}{}},
+		},
+		{
+			name:     "missing ConfigMaps are ignored",
+			cluster:  "root:org:consumer",
+			resource: "cowboys",
+			obj:      map[string]interface{}{"spec": map[string]interface{}{}},
+			want:     map[string]interface{}{"spec": map[string]interface{}{}},
+		},
+		{
+			name:     "invalid defaults are rejected",
+			cluster:  "root:org:consumer",
+			resource: "cowboys",
+			obj:      map[string]interface{}{},
+			configMaps: []*corev1.ConfigMap{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "defaults", Name: "cowboys"},
+				Data:       map[string]string{"defaults": "- not\n- an object"},
+			}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &apiExportDefaults{
+				Handler: admission.NewHandler(admission.Create),
+				listAPIBindings: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
+					if clusterName == logicalcluster.From(binding) {
+						return []*apisv1alpha1.APIBinding{binding}, nil
+					}
+					return nil, nil
+				},
+				getAPIExport: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error) {
+					if clusterName == logicalcluster.From(export) && name == export.Name {
+						return export, nil
+					}
+					return nil, apierrors.NewNotFound(apisv1alpha1.Resource("apiexports"), name)
+				},
+				getConfigMap: func(clusterName logicalcluster.Name, namespace, name string) (*corev1.ConfigMap, error) {
+					if clusterName == logicalcluster.From(export) {
+						for _, cm := range tt.configMaps {
+							if cm.Namespace == namespace && cm.Name == name {
+								return cm, nil
+							}
+						}
+					}
+					return nil, apierrors.NewNotFound(corev1.Resource("configmaps"), name)
+				},
+			}
+
+			ctx := request.WithCluster(context.Background(), request.Cluster{Name: logicalcluster.New(tt.cluster)})
+			a := createAttr(tt.resource, tt.obj)
+			err := o.Admit(ctx, a, nil)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, a.GetObject().(*unstructured.Unstructured).Object)
+		})
+	}
+}
@@ -0,0 +1,261 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiexportfinalizers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	kcpinitializers "github.com/kcp-dev/kcp/pkg/admission/initializers"
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/authorization/delegated"
+	kcpinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
+	"github.com/kcp-dev/kcp/pkg/finalizers"
+)
+
+const (
+	PluginName = "apis.kcp.dev/APIExportFinalizers"
+
+	byWorkspaceIndex = "apiExportFinalizers-byWorkspace"
+
+	// FinalizeVerb is the verb on an APIExport that allows adding and removing the finalizers
+	// of its domains on objects in the workspaces binding it.
+	FinalizeVerb = "finalize"
+)
+
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName,
+		func(_ io.Reader) (admission.Interface, error) {
+			return &apiExportFinalizers{
+				Handler:          admission.NewHandler(admission.Create, admission.Update),
+				createAuthorizer: delegated.NewDelegatedAuthorizer,
+			}, nil
+		})
+}
+
+// apiExportFinalizers protects the finalizers owned by APIExports. The domains of an APIExport
+// are the groups of the resources bound from it. Only users allowed to finalize the APIExport
+// can add or remove finalizers of these domains on objects of the binding workspace, such that
+// tenants cannot skip the cleanup of a provider by removing its finalizers, and providers
+// cannot wedge deletions with finalizers of other providers.
+type apiExportFinalizers struct {
+	*admission.Handler
+	kubeClusterClient *kubernetes.Cluster
+
+	createAuthorizer delegated.DelegatedAuthorizerFactory
+
+	listAPIBindings func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error)
+
+	kcpInformersSynced func() bool
+}
+
+// Ensure that the required admission interfaces are implemented.
+var _ = admission.ValidationInterface(&apiExportFinalizers{})
+var _ = admission.InitializationValidator(&apiExportFinalizers{})
+var _ = kcpinitializers.WantsKcpInformers(&apiExportFinalizers{})
+var _ = kcpinitializers.WantsKubeClusterClient(&apiExportFinalizers{})
+
+// exportReference identifies an APIExport by its logical cluster and name.
+type exportReference struct {
+	clusterName logicalcluster.Name
+	name        string
+}
+
+// Validate rejects objects whose finalizers of APIExport domains are added or removed by
+// users not allowed to finalize the APIExport.
+func (o *apiExportFinalizers) Validate(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
+	if a.GetObject() == nil {
+		return nil
+	}
+	obj, err := meta.Accessor(a.GetObject())
+	if err != nil {
+		return nil // not an object with metadata
+	}
+	var oldFinalizers []string
+	if a.GetOperation() == admission.Update && a.GetOldObject() != nil {
+		old, err := meta.Accessor(a.GetOldObject())
+		if err != nil {
+			return nil
+		}
+		oldFinalizers = old.GetFinalizers()
+	}
+	changed := finalizers.Changed(oldFinalizers, obj.GetFinalizers())
+	if len(changed) == 0 {
+		return nil
+	}
+
+	clusterName, err := genericapirequest.ClusterNameFrom(ctx)
+	if err != nil {
+		return admission.NewForbidden(a, err)
+	}
+	if _, hasParent := clusterName.Parent(); !hasParent {
+		// APIBindings in root are not possible (they can only point to sibling workspaces).
+		return nil
+	}
+
+	if !o.WaitForReady() {
+		return admission.NewForbidden(a, fmt.Errorf("not yet ready to handle request"))
+	}
+
+	owned, err := o.ownedFinalizers(clusterName, changed)
+	if err != nil {
+		return admission.NewForbidden(a, err)
+	}
+	exports := make([]exportReference, 0, len(owned))
+	for ref := range owned {
+		exports = append(exports, ref)
+	}
+	sort.Slice(exports, func(i, j int) bool {
+		return exports[i].clusterName.String()+"|"+exports[i].name < exports[j].clusterName.String()+"|"+exports[j].name
+	})
+	for _, ref := range exports {
+		if err := o.checkFinalizeAccess(ctx, a.GetUserInfo(), ref); err != nil {
+			return admission.NewForbidden(a, fmt.Errorf("finalizers %s of APIExport %s|%s cannot be added or removed: %w", strings.Join(owned[ref], ", "), ref.clusterName, ref.name, err))
+		}
+	}
+
+	return nil
+}
+
+// ownedFinalizers returns the given finalizers which are in a domain of an APIExport bound in
+// the workspace, by APIExport.
+func (o *apiExportFinalizers) ownedFinalizers(clusterName logicalcluster.Name, changed []string) (map[exportReference][]string, error) {
+	bindings, err := o.listAPIBindings(clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	parentClusterName, _ := clusterName.Parent()
+	owned := map[exportReference][]string{}
+	for _, finalizer := range changed {
+		for _, binding := range bindings {
+			if binding.Status.BoundAPIExport == nil || binding.Status.BoundAPIExport.Workspace == nil {
+				continue
+			}
+			if !ownsFinalizer(binding, finalizer) {
+				continue
+			}
+			ref := exportReference{
+				clusterName: parentClusterName.Join(binding.Status.BoundAPIExport.Workspace.WorkspaceName),
+				name:        binding.Status.BoundAPIExport.Workspace.ExportName,
+			}
+			owned[ref] = append(owned[ref], finalizer)
+		}
+	}
+	return owned, nil
+}
+
+// ownsFinalizer returns whether the finalizer is in the domain of a group bound by the binding.
+func ownsFinalizer(binding *apisv1alpha1.APIBinding, finalizer string) bool {
+	for _, br := range binding.Status.BoundResources {
+		if br.Group != "" && finalizers.InDomain(finalizer, br.Group) {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *apiExportFinalizers) checkFinalizeAccess(ctx context.Context, user user.Info, ref exportReference) error {
+	authz, err := o.createAuthorizer(ref.clusterName, o.kubeClusterClient)
+	if err != nil {
+		// Logging a more specific error for the operator
+		klog.Errorf("error creating authorizer from delegating authorizer config: %v", err)
+		// Returning a less specific error to the end user
+		return fmt.Errorf("unable to authorize request")
+	}
+
+	finalizeAttr := authorizer.AttributesRecord{
+		User:            user,
+		Verb:            FinalizeVerb,
+		APIGroup:        apisv1alpha1.SchemeGroupVersion.Group,
+		APIVersion:      apisv1alpha1.SchemeGroupVersion.Version,
+		Resource:        "apiexports",
+		Name:            ref.name,
+		ResourceRequest: true,
+	}
+
+	if decision, _, err := authz.Authorize(ctx, finalizeAttr); err != nil {
+		return fmt.Errorf("unable to determine access to apiexports: %w", err)
+	} else if decision != authorizer.DecisionAllow {
+		return fmt.Errorf("missing verb=%q permission on apiexports", FinalizeVerb)
+	}
+
+	return nil
+}
+
+// ValidateInitialization ensures the required injected fields are set.
+func (o *apiExportFinalizers) ValidateInitialization() error {
+	if o.kubeClusterClient == nil {
+		return fmt.Errorf(PluginName + " plugin needs a Kubernetes ClusterInterface")
+	}
+	if o.listAPIBindings == nil {
+		return fmt.Errorf(PluginName + " plugin needs kcp informers")
+	}
+	return nil
+}
+
+// SetKubeClusterClient is an admission plugin initializer function that injects a Kubernetes cluster client into
+// this admission plugin.
+func (o *apiExportFinalizers) SetKubeClusterClient(clusterClient *kubernetes.Cluster) {
+	o.kubeClusterClient = clusterClient
+}
+
+// SetKcpInformers implements the WantsKcpInformers interface.
+func (o *apiExportFinalizers) SetKcpInformers(f kcpinformers.SharedInformerFactory) {
+	apiBindingsInformer := f.Apis().V1alpha1().APIBindings().Informer()
+	if _, found := apiBindingsInformer.GetIndexer().GetIndexers()[byWorkspaceIndex]; !found {
+		if err := apiBindingsInformer.AddIndexers(cache.Indexers{
+			byWorkspaceIndex: func(obj interface{}) ([]string, error) {
+				return []string{logicalcluster.From(obj.(metav1.Object)).String()}, nil
+			},
+		}); err != nil {
+			// nothing we can do here. But this should also never happen. We check for existence before.
+			klog.Errorf("failed to add indexer for APIBindings: %v", err)
+		}
+	}
+	apiBindingsIndexer := apiBindingsInformer.GetIndexer()
+	o.listAPIBindings = func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
+		objs, err := apiBindingsIndexer.ByIndex(byWorkspaceIndex, clusterName.String())
+		if err != nil {
+			return nil, err
+		}
+		bindings := make([]*apisv1alpha1.APIBinding, 0, len(objs))
+		for _, obj := range objs {
+			bindings = append(bindings, obj.(*apisv1alpha1.APIBinding))
+		}
+		return bindings, nil
+	}
+	o.kcpInformersSynced = apiBindingsInformer.HasSynced
+	o.SetReadyFunc(func() bool {
+		return o.kcpInformersSynced()
+	})
+}
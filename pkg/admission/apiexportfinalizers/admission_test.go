@@ -0,0 +1,212 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiexportfinalizers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/kubernetes"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+)
+
+func cowboy(finalizers ...string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	u.SetAPIVersion("wildwest.dev/v1alpha1")
+	u.SetKind("Cowboy")
+	u.SetName("test")
+	u.SetFinalizers(finalizers)
+	return u
+}
+
+func createAttr(obj *unstructured.Unstructured) admission.Attributes {
+	return attr(obj, nil, admission.Create, &metav1.CreateOptions{})
+}
+
+func updateAttr(obj, old *unstructured.Unstructured) admission.Attributes {
+	return attr(obj, old, admission.Update, &metav1.UpdateOptions{})
+}
+
+func attr(obj, old *unstructured.Unstructured, op admission.Operation, opts runtime.Object) admission.Attributes {
+	var oldObj runtime.Object
+	if old != nil {
+		oldObj = old
+	}
+	return admission.NewAttributesRecord(
+		obj,
+		oldObj,
+		schema.GroupVersionKind{Group: "wildwest.dev", Version: "v1alpha1", Kind: "Cowboy"},
+		"default",
+		"test",
+		schema.GroupVersionResource{Group: "wildwest.dev", Version: "v1alpha1", Resource: "cowboys"},
+		"",
+		op,
+		opts,
+		false,
+		&user.DefaultInfo{Name: "user"},
+	)
+}
+
+func TestValidate(t *testing.T) {
+	binding := &apisv1alpha1.APIBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "wildwest", ClusterName: "root:org:consumer"},
+		Status: apisv1alpha1.APIBindingStatus{
+			BoundAPIExport: &apisv1alpha1.ExportReference{
+				Workspace: &apisv1alpha1.WorkspaceExportReference{WorkspaceName: "provider", ExportName: "wildwest"},
+			},
+			BoundResources: []apisv1alpha1.BoundAPIResource{
+				{Group: "wildwest.dev", Resource: "cowboys"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		cluster       string
+		attr          admission.Attributes
+		authzDecision authorizer.Decision
+		authzError    error
+		wantAuthz     bool
+		wantErr       bool
+	}{
+		{
+			name:      "no finalizers",
+			cluster:   "root:org:consumer",
+			attr:      createAttr(cowboy()),
+			wantAuthz: false,
+		},
+		{
+			name:      "unrelated finalizer is not checked",
+			cluster:   "root:org:consumer",
+			attr:      createAttr(cowboy("example.com/cleanup", "kubernetes")),
+			wantAuthz: false,
+		},
+		{
+			name:      "unchanged export finalizer is not checked",
+			cluster:   "root:org:consumer",
+			attr:      updateAttr(cowboy("wildwest.dev/cleanup", "example.com/other"), cowboy("wildwest.dev/cleanup")),
+			wantAuthz: false,
+		},
+		{
+			name:          "adding export finalizer with permission",
+			cluster:       "root:org:consumer",
+			attr:          createAttr(cowboy("wildwest.dev/cleanup")),
+			authzDecision: authorizer.DecisionAllow,
+			wantAuthz:     true,
+		},
+		{
+			name:          "adding export finalizer without permission",
+			cluster:       "root:org:consumer",
+			attr:          createAttr(cowboy("wildwest.dev/cleanup")),
+			authzDecision: authorizer.DecisionNoOpinion,
+			wantAuthz:     true,
+			wantErr:       true,
+		},
+		{
+			name:          "removing export finalizer without permission",
+			cluster:       "root:org:consumer",
+			attr:          updateAttr(cowboy(), cowboy("wildwest.dev/cleanup")),
+			authzDecision: authorizer.DecisionDeny,
+			wantAuthz:     true,
+			wantErr:       true,
+		},
+		{
+			name:          "subdomain finalizer without permission",
+			cluster:       "root:org:consumer",
+			attr:          createAttr(cowboy("billing.wildwest.dev/cleanup")),
+			authzDecision: authorizer.DecisionNoOpinion,
+			wantAuthz:     true,
+			wantErr:       true,
+		},
+		{
+			name:       "authorizer error",
+			cluster:    "root:org:consumer",
+			attr:       createAttr(cowboy("wildwest.dev/cleanup")),
+			authzError: errors.New("boom"),
+			wantAuthz:  true,
+			wantErr:    true,
+		},
+		{
+			name:      "workspace without bindings",
+			cluster:   "root:org:other",
+			attr:      createAttr(cowboy("wildwest.dev/cleanup")),
+			wantAuthz: false,
+		},
+		{
+			name:      "root workspace",
+			cluster:   "root",
+			attr:      createAttr(cowboy("wildwest.dev/cleanup")),
+			wantAuthz: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var authorizedIn []logicalcluster.Name
+			o := &apiExportFinalizers{
+				Handler: admission.NewHandler(admission.Create, admission.Update),
+				createAuthorizer: func(clusterName logicalcluster.Name, client kubernetes.ClusterInterface) (authorizer.Authorizer, error) {
+					authorizedIn = append(authorizedIn, clusterName)
+					return &fakeAuthorizer{
+						tt.authzDecision,
+						tt.authzError,
+					}, nil
+				},
+				listAPIBindings: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
+					if clusterName == logicalcluster.From(binding) {
+						return []*apisv1alpha1.APIBinding{binding}, nil
+					}
+					return nil, nil
+				},
+			}
+
+			ctx := request.WithCluster(context.Background(), request.Cluster{Name: logicalcluster.New(tt.cluster)})
+			err := o.Validate(ctx, tt.attr, nil)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			if tt.wantAuthz {
+				require.Equal(t, []logicalcluster.Name{logicalcluster.New("root:org:provider")}, authorizedIn)
+			} else {
+				require.Empty(t, authorizedIn)
+			}
+		})
+	}
+}
+
+type fakeAuthorizer struct {
+	authorized authorizer.Decision
+	err        error
+}
+
+func (a *fakeAuthorizer) Authorize(ctx context.Context, attr authorizer.Attributes) (authorized authorizer.Decision, reason string, err error) {
+	return a.authorized, "reason", a.err
+}
@@ -24,8 +24,11 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apiserver/pkg/admission"
+	genericfeatures "k8s.io/apiserver/pkg/features"
+	"k8s.io/apiserver/pkg/warning"
 
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/immutablefields"
 )
 
 const (
@@ -86,5 +89,14 @@ func (o *apiResourceSchemaValidation) Validate(ctx context.Context, a admission.
 		}
 	}
 
+	// markers are valid either way, such that stored schemas stay valid when the feature gate changes.
+	if !immutablefields.Enforced() {
+		for _, version := range schema.Spec.Versions {
+			if immutablefields.HasMarkers(version.Schema.Raw) {
+				warning.AddWarning(ctx, "", fmt.Sprintf("%s markers of version %s are not enforced, because the %s feature gate is disabled", immutablefields.Extension, version.Name, genericfeatures.CustomResourceValidationExpressions))
+			}
+		}
+	}
+
 	return nil
 }
@@ -22,16 +22,20 @@ import (
 	"testing"
 
 	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apiserver/pkg/admission"
 	"k8s.io/apiserver/pkg/authentication/user"
 	"k8s.io/apiserver/pkg/endpoints/request"
+	genericfeatures "k8s.io/apiserver/pkg/features"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/apiserver/pkg/warning"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
 	"sigs.k8s.io/yaml"
 
 	"github.com/kcp-dev/kcp/pkg/admission/helpers"
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
-	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
 )
 
 func createAttr(s *apisv1alpha1.APIResourceSchema) admission.Attributes {
@@ -50,18 +54,17 @@ func createAttr(s *apisv1alpha1.APIResourceSchema) admission.Attributes {
 	)
 }
 
-// nolint:deadcode,unused
 func updateAttr(s, old *apisv1alpha1.APIResourceSchema) admission.Attributes {
 	return admission.NewAttributesRecord(
 		helpers.ToUnstructuredOrDie(s),
 		helpers.ToUnstructuredOrDie(old),
-		tenancyv1alpha1.Kind("APIResourceSchema").WithVersion("v1alpha1"),
+		apisv1alpha1.Kind("APIResourceSchema").WithVersion("v1alpha1"),
 		"",
 		s.Name,
-		tenancyv1alpha1.Resource("apiresourceschemas").WithVersion("v1alpha1"),
+		apisv1alpha1.Resource("apiresourceschemas").WithVersion("v1alpha1"),
 		"",
 		admission.Update,
-		&metav1.CreateOptions{},
+		&metav1.UpdateOptions{},
 		false,
 		&user.DefaultInfo{},
 	)
@@ -205,6 +208,79 @@ spec:
 	}
 }
 
+type warningRecorder []string
+
+func (r *warningRecorder) AddWarning(_, text string) {
+	*r = append(*r, text)
+}
+
+func TestValidateImmutableFieldMarkers(t *testing.T) {
+	schema := unmarshalOrDie(`
+apiVersion: apis.kcp.sh/v1alpha1
+kind: APIResourceSchema
+metadata:
+  name: july.cowboys.wild.west
+spec:
+  group: wild.west
+  names:
+    plural: cowboys
+    singular: cowboy
+    kind: Cowboy
+    listKind: CowboyList
+  scope: Cluster
+  versions:
+  - name: v1
+    served: true
+    storage: true
+    schema:
+      type: object
+      properties:
+        spec:
+          type: object
+          required: ["horse"]
+          properties:
+            horse:
+              type: string
+              x-kcp-immutable: true
+            `)
+	o := &apiResourceSchemaValidation{
+		Handler: admission.NewHandler(admission.Create, admission.Update),
+	}
+	validate := func(attr admission.Attributes) ([]string, error) {
+		var warnings warningRecorder
+		ctx := request.WithCluster(context.Background(), request.Cluster{Name: logicalcluster.New("root:org")})
+		ctx = warning.WithWarningRecorder(ctx, &warnings)
+		err := o.Validate(ctx, attr, nil)
+		return warnings, err
+	}
+
+	t.Run("stored with the feature gate enabled", func(t *testing.T) {
+		defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, genericfeatures.CustomResourceValidationExpressions, true)()
+
+		warnings, err := validate(createAttr(schema))
+		require.NoError(t, err)
+		require.Empty(t, warnings)
+	})
+
+	t.Run("updated after the feature gate is disabled", func(t *testing.T) {
+		defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, genericfeatures.CustomResourceValidationExpressions, false)()
+
+		updated := schema.DeepCopy()
+		updated.Labels = map[string]string{"wild": "west"}
+		warnings, err := validate(updateAttr(updated, schema))
+		require.NoError(t, err, "stored schemas must stay valid")
+		require.Equal(t, []string{"x-kcp-immutable markers of version v1 are not enforced, because the CustomResourceValidationExpressions feature gate is disabled"}, warnings)
+	})
+
+	t.Run("created with the feature gate disabled", func(t *testing.T) {
+		defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, genericfeatures.CustomResourceValidationExpressions, false)()
+
+		warnings, err := validate(createAttr(schema))
+		require.NoError(t, err)
+		require.Len(t, warnings, 1)
+	})
+}
+
 func unmarshalOrDie(yml string) *apisv1alpha1.APIResourceSchema {
 	s := apisv1alpha1.APIResourceSchema{}
 	if err := yaml.Unmarshal([]byte(strings.ReplaceAll(yml, "\t", "    ")), &s); err != nil {
@@ -31,6 +31,8 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/immutablefields"
+	"github.com/kcp-dev/kcp/pkg/references"
 )
 
 var (
@@ -171,6 +173,8 @@ func ValidateAPIResourceVersion(version *apisv1alpha1.APIResourceVersion, fldPat
 			allErrs = append(allErrs, field.Invalid(fldPath.Child("schema"), string(version.Schema.Raw), fmt.Sprintf("invalid schema: %v", err)))
 		} else {
 			allErrs = append(allErrs, crdvalidation.ValidateCustomResourceDefinitionValidation(&crdSchemaInternal, statusEnabled, defaultValidationOpts, fldPath.Child("schema"))...)
+			allErrs = append(allErrs, immutablefields.Validate(version.Schema.Raw, fldPath.Child("schema"))...)
+			allErrs = append(allErrs, references.Validate(version.Schema.Raw, fldPath.Child("schema"))...)
 		}
 	}
 
@@ -0,0 +1,229 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bulkworkspaceoperation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/authorization/delegated"
+)
+
+const (
+	PluginName = "tenancy.kcp.dev/BulkWorkspaceOperation"
+
+	// maxDeniedNames is the number of workspaces listed when the operation is denied for some of them.
+	maxDeniedNames = 5
+)
+
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName,
+		func(_ io.Reader) (admission.Interface, error) {
+			return &bulkWorkspaceOperationAdmission{
+				Handler:          admission.NewHandler(admission.Create, admission.Update),
+				createAuthorizer: delegated.NewDelegatedAuthorizer,
+			}, nil
+		})
+}
+
+// bulkWorkspaceOperationAdmission checks that the creator of a BulkWorkspaceOperation is allowed
+// to execute the operation on the ClusterWorkspaces, as kcp executes it with its own privileges.
+// It also validates the spec, and keeps it immutable.
+type bulkWorkspaceOperationAdmission struct {
+	*admission.Handler
+	kubeClusterClient *kubernetes.Cluster
+
+	createAuthorizer delegated.DelegatedAuthorizerFactory
+}
+
+// Ensure that the required admission interfaces are implemented.
+var _ = admission.ValidationInterface(&bulkWorkspaceOperationAdmission{})
+var _ = admission.InitializationValidator(&bulkWorkspaceOperationAdmission{})
+
+func (o *bulkWorkspaceOperationAdmission) Validate(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
+	if a.GetResource().GroupResource() != tenancyv1alpha1.Resource("bulkworkspaceoperations") || a.GetSubresource() != "" {
+		return nil
+	}
+
+	op, err := toBulkWorkspaceOperation(a.GetObject())
+	if err != nil {
+		return err
+	}
+
+	if a.GetOperation() == admission.Update {
+		old, err := toBulkWorkspaceOperation(a.GetOldObject())
+		if err != nil {
+			return err
+		}
+		if !equality.Semantic.DeepEqual(op.Spec, old.Spec) {
+			return admission.NewForbidden(a, field.Invalid(field.NewPath("spec"), "", "field is immutable"))
+		}
+		return nil
+	}
+
+	if errs := validateSpec(&op.Spec, field.NewPath("spec")); len(errs) > 0 {
+		return admission.NewForbidden(a, errs.ToAggregate())
+	}
+
+	cluster, err := genericapirequest.ValidClusterFrom(ctx)
+	if err != nil {
+		return admission.NewForbidden(a, fmt.Errorf("error determining workspace: %w", err))
+	}
+	if err := o.checkAccess(ctx, a, cluster.Name, op); err != nil {
+		return admission.NewForbidden(a, fmt.Errorf("unable to %s workspaces: %w", strings.ToLower(string(op.Spec.Operation)), err))
+	}
+
+	return nil
+}
+
+func validateSpec(spec *tenancyv1alpha1.BulkWorkspaceOperationSpec, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	seen := make(map[string]bool, len(spec.Workspaces))
+	for i, name := range spec.Workspaces {
+		if seen[name] {
+			errs = append(errs, field.Duplicate(fldPath.Child("workspaces").Index(i), name))
+		}
+		seen[name] = true
+	}
+
+	if spec.Operation != tenancyv1alpha1.BulkWorkspaceOperationCreate && spec.Type != "" {
+		errs = append(errs, field.Forbidden(fldPath.Child("type"), "only valid for the Create operation"))
+	}
+	if spec.Operation == tenancyv1alpha1.BulkWorkspaceOperationDelete && len(spec.Labels) > 0 {
+		errs = append(errs, field.Forbidden(fldPath.Child("labels"), "not valid for the Delete operation"))
+	}
+	if spec.Operation != tenancyv1alpha1.BulkWorkspaceOperationLabel && len(spec.RemoveLabels) > 0 {
+		errs = append(errs, field.Forbidden(fldPath.Child("removeLabels"), "only valid for the Label operation"))
+	}
+	if spec.Operation == tenancyv1alpha1.BulkWorkspaceOperationLabel && len(spec.Labels) == 0 && len(spec.RemoveLabels) == 0 {
+		errs = append(errs, field.Required(fldPath.Child("labels"), "labels or removeLabels are required for the Label operation"))
+	}
+
+	return errs
+}
+
+func toBulkWorkspaceOperation(obj runtime.Object) (*tenancyv1alpha1.BulkWorkspaceOperation, error) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("unexpected type %T", obj)
+	}
+	op := &tenancyv1alpha1.BulkWorkspaceOperation{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, op); err != nil {
+		return nil, fmt.Errorf("failed to convert unstructured to BulkWorkspaceOperation: %w", err)
+	}
+	return op, nil
+}
+
+// checkAccess checks that the user can create ClusterWorkspaces of the type, or delete or update
+// each of the ClusterWorkspaces.
+func (o *bulkWorkspaceOperationAdmission) checkAccess(ctx context.Context, a admission.Attributes, clusterName logicalcluster.Name, op *tenancyv1alpha1.BulkWorkspaceOperation) error {
+	authz, err := o.createAuthorizer(clusterName, o.kubeClusterClient)
+	if err != nil {
+		// Logging a more specific error for the operator
+		klog.Errorf("error creating authorizer from delegating authorizer config: %v", err)
+		// Returning a less specific error to the end user
+		return errors.New("unable to authorize request")
+	}
+
+	authorize := func(attr authorizer.AttributesRecord) (bool, error) {
+		attr.User = a.GetUserInfo()
+		attr.APIGroup = tenancyv1alpha1.SchemeGroupVersion.Group
+		attr.APIVersion = tenancyv1alpha1.SchemeGroupVersion.Version
+		attr.ResourceRequest = true
+		decision, _, err := authz.Authorize(ctx, attr)
+		if err != nil {
+			return false, fmt.Errorf("unable to determine access to %s: %w", attr.Resource, err)
+		}
+		return decision == authorizer.DecisionAllow, nil
+	}
+
+	if op.Spec.Operation == tenancyv1alpha1.BulkWorkspaceOperationCreate {
+		if allowed, err := authorize(authorizer.AttributesRecord{Verb: "create", Resource: "clusterworkspaces"}); err != nil {
+			return err
+		} else if !allowed {
+			return errors.New("missing verb='create' permission on clusterworkspaces")
+		}
+
+		typ := op.Spec.Type
+		if typ == "" {
+			typ = "Universal"
+		}
+		typeName := strings.ToLower(typ)
+		if allowed, err := authorize(authorizer.AttributesRecord{Verb: "use", Resource: "clusterworkspacetypes", Name: typeName}); err != nil {
+			return err
+		} else if !allowed {
+			return fmt.Errorf("missing verb='use' permission on clusterworkspacetypes %q", typeName)
+		}
+		return nil
+	}
+
+	verb := "delete"
+	if op.Spec.Operation == tenancyv1alpha1.BulkWorkspaceOperationLabel {
+		verb = "update"
+	}
+	var denied []string
+	for _, name := range op.Spec.Workspaces {
+		allowed, err := authorize(authorizer.AttributesRecord{Verb: verb, Resource: "clusterworkspaces", Name: name})
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			denied = append(denied, name)
+		}
+	}
+	if len(denied) > 0 {
+		names := denied
+		if len(names) > maxDeniedNames {
+			names = append(names[:maxDeniedNames:maxDeniedNames], "...")
+		}
+		return fmt.Errorf("missing verb='%s' permission on %d clusterworkspaces: %s", verb, len(denied), strings.Join(names, ", "))
+	}
+
+	return nil
+}
+
+// ValidateInitialization ensures the required injected fields are set.
+func (o *bulkWorkspaceOperationAdmission) ValidateInitialization() error {
+	if o.kubeClusterClient == nil {
+		return fmt.Errorf(PluginName + " plugin needs a Kubernetes ClusterInterface")
+	}
+
+	return nil
+}
+
+// SetKubeClusterClient is an admission plugin initializer function that injects a Kubernetes cluster client into
+// this admission plugin.
+func (o *bulkWorkspaceOperationAdmission) SetKubeClusterClient(clusterClient *kubernetes.Cluster) {
+	o.kubeClusterClient = clusterClient
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bulkworkspaceoperation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kcp-dev/kcp/pkg/admission/helpers"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+func operationAttr(op admission.Operation, operation, old *tenancyv1alpha1.BulkWorkspaceOperation) admission.Attributes {
+	var obj, oldObj runtime.Object
+	if operation != nil {
+		obj = helpers.ToUnstructuredOrDie(operation)
+	}
+	if old != nil {
+		oldObj = helpers.ToUnstructuredOrDie(old)
+	}
+	return admission.NewAttributesRecord(
+		obj,
+		oldObj,
+		tenancyv1alpha1.Kind("BulkWorkspaceOperation").WithVersion("v1alpha1"),
+		"",
+		"onboarding",
+		tenancyv1alpha1.Resource("bulkworkspaceoperations").WithVersion("v1alpha1"),
+		"",
+		op,
+		&metav1.CreateOptions{},
+		false,
+		&user.DefaultInfo{},
+	)
+}
+
+func newOperation(operation tenancyv1alpha1.BulkWorkspaceOperationType, mutate func(*tenancyv1alpha1.BulkWorkspaceOperationSpec)) *tenancyv1alpha1.BulkWorkspaceOperation {
+	op := &tenancyv1alpha1.BulkWorkspaceOperation{
+		ObjectMeta: metav1.ObjectMeta{Name: "onboarding"},
+		Spec: tenancyv1alpha1.BulkWorkspaceOperationSpec{
+			Operation:  operation,
+			Workspaces: []string{"one", "two"},
+		},
+	}
+	if mutate != nil {
+		mutate(&op.Spec)
+	}
+	return op
+}
+
+func TestValidate(t *testing.T) {
+	withLabels := func(spec *tenancyv1alpha1.BulkWorkspaceOperationSpec) {
+		spec.Labels = map[string]string{"team": "a"}
+	}
+
+	tests := []struct {
+		name           string
+		attr           admission.Attributes
+		allowed        func(attr authorizer.Attributes) bool
+		authzError     error
+		expectedErrors []string
+		expectedChecks []string
+	}{
+		{
+			name:           "Create: passes when workspaces of the type can be created",
+			attr:           operationAttr(admission.Create, newOperation(tenancyv1alpha1.BulkWorkspaceOperationCreate, func(spec *tenancyv1alpha1.BulkWorkspaceOperationSpec) { spec.Type = "Team" }), nil),
+			allowed:        func(authorizer.Attributes) bool { return true },
+			expectedChecks: []string{"create clusterworkspaces", "use clusterworkspacetypes team"},
+		},
+		{
+			name:           "Create: fails without permission to create workspaces",
+			attr:           operationAttr(admission.Create, newOperation(tenancyv1alpha1.BulkWorkspaceOperationCreate, nil), nil),
+			allowed:        func(authorizer.Attributes) bool { return false },
+			expectedErrors: []string{"unable to create workspaces: missing verb='create' permission on clusterworkspaces"},
+			expectedChecks: []string{"create clusterworkspaces"},
+		},
+		{
+			name:           "Create: fails without permission to use the type",
+			attr:           operationAttr(admission.Create, newOperation(tenancyv1alpha1.BulkWorkspaceOperationCreate, nil), nil),
+			allowed:        func(attr authorizer.Attributes) bool { return attr.GetVerb() == "create" },
+			expectedErrors: []string{`missing verb='use' permission on clusterworkspacetypes "universal"`},
+			expectedChecks: []string{"create clusterworkspaces", "use clusterworkspacetypes universal"},
+		},
+		{
+			name:           "Create: fails when there's an error checking authorization",
+			attr:           operationAttr(admission.Create, newOperation(tenancyv1alpha1.BulkWorkspaceOperationCreate, nil), nil),
+			authzError:     errors.New("some error here"),
+			expectedErrors: []string{"unable to determine access to clusterworkspaces: some error here"},
+			expectedChecks: []string{"create clusterworkspaces"},
+		},
+		{
+			name:           "Delete: checks every workspace",
+			attr:           operationAttr(admission.Create, newOperation(tenancyv1alpha1.BulkWorkspaceOperationDelete, nil), nil),
+			allowed:        func(attr authorizer.Attributes) bool { return attr.GetName() == "one" },
+			expectedErrors: []string{"missing verb='delete' permission on 1 clusterworkspaces: two"},
+			expectedChecks: []string{"delete clusterworkspaces one", "delete clusterworkspaces two"},
+		},
+		{
+			name:           "Label: checks update of every workspace",
+			attr:           operationAttr(admission.Create, newOperation(tenancyv1alpha1.BulkWorkspaceOperationLabel, withLabels), nil),
+			allowed:        func(authorizer.Attributes) bool { return true },
+			expectedChecks: []string{"update clusterworkspaces one", "update clusterworkspaces two"},
+		},
+		{
+			name:           "Label: fails without labels",
+			attr:           operationAttr(admission.Create, newOperation(tenancyv1alpha1.BulkWorkspaceOperationLabel, nil), nil),
+			expectedErrors: []string{"spec.labels: Required value"},
+		},
+		{
+			name: "Delete: fails with invalid fields",
+			attr: operationAttr(admission.Create, newOperation(tenancyv1alpha1.BulkWorkspaceOperationDelete, func(spec *tenancyv1alpha1.BulkWorkspaceOperationSpec) {
+				spec.Workspaces = []string{"one", "one"}
+				spec.Type = "Team"
+				spec.Labels = map[string]string{"team": "a"}
+				spec.RemoveLabels = []string{"trial"}
+			}), nil),
+			expectedErrors: []string{
+				`spec.workspaces[1]: Duplicate value: "one"`,
+				"spec.type: Forbidden",
+				"spec.labels: Forbidden",
+				"spec.removeLabels: Forbidden",
+			},
+		},
+		{
+			name: "Update: status changes pass",
+			attr: operationAttr(admission.Update, newOperation(tenancyv1alpha1.BulkWorkspaceOperationLabel, withLabels), newOperation(tenancyv1alpha1.BulkWorkspaceOperationLabel, withLabels)),
+		},
+		{
+			name:           "Update: spec is immutable",
+			attr:           operationAttr(admission.Update, newOperation(tenancyv1alpha1.BulkWorkspaceOperationDelete, nil), newOperation(tenancyv1alpha1.BulkWorkspaceOperationLabel, withLabels)),
+			expectedErrors: []string{"field is immutable"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var checks []string
+			o := &bulkWorkspaceOperationAdmission{
+				Handler: admission.NewHandler(admission.Create, admission.Update),
+				createAuthorizer: func(clusterName logicalcluster.Name, client kubernetes.ClusterInterface) (authorizer.Authorizer, error) {
+					require.Equal(t, "root:org", clusterName.String())
+					return authorizer.AuthorizerFunc(func(ctx context.Context, attr authorizer.Attributes) (authorizer.Decision, string, error) {
+						check := attr.GetVerb() + " " + attr.GetResource()
+						if attr.GetName() != "" {
+							check += " " + attr.GetName()
+						}
+						checks = append(checks, check)
+						if tc.authzError != nil {
+							return authorizer.DecisionNoOpinion, "", tc.authzError
+						}
+						if tc.allowed != nil && tc.allowed(attr) {
+							return authorizer.DecisionAllow, "", nil
+						}
+						return authorizer.DecisionNoOpinion, "", nil
+					}), nil
+				},
+			}
+
+			ctx := request.WithCluster(context.Background(), request.Cluster{Name: logicalcluster.New("root:org")})
+
+			err := o.Validate(ctx, tc.attr, nil)
+
+			wantErr := len(tc.expectedErrors) > 0
+			require.Equal(t, wantErr, err != nil, "unexpected error: %v", err)
+			for _, expected := range tc.expectedErrors {
+				require.Contains(t, err.Error(), expected)
+			}
+			require.Equal(t, tc.expectedChecks, checks)
+		})
+	}
+}
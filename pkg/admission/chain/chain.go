@@ -0,0 +1,333 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chain
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/admission"
+)
+
+// DebugPath is where the DefaultChain serves its Report.
+const DebugPath = "/debug/kcp/admission-chain"
+
+const (
+	phaseAdmit    = "admit"
+	phaseValidate = "validate"
+)
+
+var operations = []admission.Operation{admission.Create, admission.Update, admission.Delete, admission.Connect}
+
+// DefaultChain records the admission plugins decorated by the kcp server.
+var DefaultChain = NewChain()
+
+// Chain records the active admission plugins in the order they run, and their calls,
+// rejections and latencies by resource, such that operators can tell which plugins
+// act on which requests.
+type Chain struct {
+	now func() time.Time
+
+	lock    sync.RWMutex
+	plugins []*plugin
+	byName  map[string]*plugin
+}
+
+// NewChain returns a Chain without plugins.
+func NewChain() *Chain {
+	return &Chain{
+		now:    time.Now,
+		byName: map[string]*plugin{},
+	}
+}
+
+type plugin struct {
+	name  string
+	chain *Chain
+
+	lock       sync.Mutex
+	handles    func(admission.Operation) bool
+	mutating   bool
+	validating bool
+	stats      map[statsKey]*stats
+}
+
+type statsKey struct {
+	phase     string
+	operation admission.Operation
+	resource  schema.GroupResource
+}
+
+type stats struct {
+	calls      int64
+	rejections int64
+	duration   time.Duration
+}
+
+// Decorate wraps an admission plugin such that its calls are recorded. It implements
+// admission.DecoratorFunc. Plugins are expected to be decorated in the order they run.
+// Decorating a plugin of the same name again, e.g. for another admission chain, replaces
+// the recorded plugin, but keeps its position and statistics.
+func (c *Chain) Decorate(handler admission.Interface, name string) admission.Interface {
+	mutator, mutating := handler.(admission.MutationInterface)
+	validator, validating := handler.(admission.ValidationInterface)
+
+	c.lock.Lock()
+	p, found := c.byName[name]
+	if !found {
+		p = &plugin{name: name, chain: c, stats: map[statsKey]*stats{}}
+		c.byName[name] = p
+		c.plugins = append(c.plugins, p)
+	}
+	c.lock.Unlock()
+
+	p.lock.Lock()
+	p.handles = handler.Handles
+	p.mutating = mutating
+	p.validating = validating
+	p.lock.Unlock()
+
+	switch {
+	case mutating && validating:
+		return &mutatingValidatingPlugin{Interface: handler, mutator: mutator, validator: validator, plugin: p}
+	case mutating:
+		return &mutatingPlugin{Interface: handler, mutator: mutator, plugin: p}
+	case validating:
+		return &validatingPlugin{Interface: handler, validator: validator, plugin: p}
+	default:
+		return handler
+	}
+}
+
+type mutatingPlugin struct {
+	admission.Interface
+	mutator admission.MutationInterface
+	plugin  *plugin
+}
+
+func (w *mutatingPlugin) Admit(ctx context.Context, a admission.Attributes, o admission.ObjectInterfaces) error {
+	start := w.plugin.chain.now()
+	err := w.mutator.Admit(ctx, a, o)
+	w.plugin.observe(phaseAdmit, a, w.plugin.chain.now().Sub(start), err)
+	return err
+}
+
+type validatingPlugin struct {
+	admission.Interface
+	validator admission.ValidationInterface
+	plugin    *plugin
+}
+
+func (w *validatingPlugin) Validate(ctx context.Context, a admission.Attributes, o admission.ObjectInterfaces) error {
+	start := w.plugin.chain.now()
+	err := w.validator.Validate(ctx, a, o)
+	w.plugin.observe(phaseValidate, a, w.plugin.chain.now().Sub(start), err)
+	return err
+}
+
+type mutatingValidatingPlugin struct {
+	admission.Interface
+	mutator   admission.MutationInterface
+	validator admission.ValidationInterface
+	plugin    *plugin
+}
+
+func (w *mutatingValidatingPlugin) Admit(ctx context.Context, a admission.Attributes, o admission.ObjectInterfaces) error {
+	return (&mutatingPlugin{Interface: w.Interface, mutator: w.mutator, plugin: w.plugin}).Admit(ctx, a, o)
+}
+
+func (w *mutatingValidatingPlugin) Validate(ctx context.Context, a admission.Attributes, o admission.ObjectInterfaces) error {
+	return (&validatingPlugin{Interface: w.Interface, validator: w.validator, plugin: w.plugin}).Validate(ctx, a, o)
+}
+
+func (p *plugin) observe(phase string, a admission.Attributes, duration time.Duration, err error) {
+	pluginDuration.WithLabelValues(p.name, phase, string(a.GetOperation())).Observe(duration.Seconds())
+	if err != nil {
+		pluginRejections.WithLabelValues(p.name, phase, string(a.GetOperation())).Inc()
+	}
+
+	key := statsKey{phase: phase, operation: a.GetOperation(), resource: a.GetResource().GroupResource()}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	s, found := p.stats[key]
+	if !found {
+		s = &stats{}
+		p.stats[key] = s
+	}
+	s.calls++
+	s.duration += duration
+	if err != nil {
+		s.rejections++
+	}
+}
+
+// Report is the active admission chain.
+type Report struct {
+	// Plugins are the plugins in the order they run. Mutating plugins run before
+	// validating plugins.
+	Plugins []PluginReport `json:"plugins"`
+}
+
+// PluginReport are the calls of an admission plugin.
+type PluginReport struct {
+	Name       string   `json:"name"`
+	Mutating   bool     `json:"mutating"`
+	Validating bool     `json:"validating"`
+	Operations []string `json:"operations"`
+	// Calls is the number of calls of the plugin, in both phases.
+	Calls      int64 `json:"calls"`
+	Rejections int64 `json:"rejections"`
+	// AverageDuration is the average latency of the calls.
+	AverageDuration Duration `json:"averageDuration"`
+	// Resources are the calls by resource, with the most called resource first.
+	Resources []ResourceReport `json:"resources,omitempty"`
+}
+
+// ResourceReport are the calls of an admission plugin for a resource.
+type ResourceReport struct {
+	Resource        string   `json:"resource"`
+	Calls           int64    `json:"calls"`
+	Rejections      int64    `json:"rejections"`
+	AverageDuration Duration `json:"averageDuration"`
+}
+
+// Duration is a duration serialized like "1.5ms".
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).Round(time.Microsecond).String())
+}
+
+// Report returns the plugins handling the given operation, all if empty, with their
+// calls for the given resource, all if empty.
+func (c *Chain) Report(operation admission.Operation, resource *schema.GroupResource) *Report {
+	c.lock.RLock()
+	plugins := append([]*plugin(nil), c.plugins...)
+	c.lock.RUnlock()
+
+	report := &Report{Plugins: []PluginReport{}}
+	for _, p := range plugins {
+		p.lock.Lock()
+		r := PluginReport{
+			Name:       p.name,
+			Mutating:   p.mutating,
+			Validating: p.validating,
+			Operations: []string{},
+		}
+		for _, op := range operations {
+			if p.handles(op) {
+				r.Operations = append(r.Operations, string(op))
+			}
+		}
+		if operation != "" && !p.handles(operation) {
+			p.lock.Unlock()
+			continue
+		}
+
+		var duration time.Duration
+		resources := map[schema.GroupResource]*ResourceReport{}
+		resourceDurations := map[schema.GroupResource]time.Duration{}
+		for key, s := range p.stats {
+			if (operation != "" && key.operation != operation) || (resource != nil && key.resource != *resource) {
+				continue
+			}
+			r.Calls += s.calls
+			r.Rejections += s.rejections
+			duration += s.duration
+
+			rr, found := resources[key.resource]
+			if !found {
+				rr = &ResourceReport{Resource: key.resource.String()}
+				resources[key.resource] = rr
+			}
+			rr.Calls += s.calls
+			rr.Rejections += s.rejections
+			resourceDurations[key.resource] += s.duration
+		}
+		p.lock.Unlock()
+
+		if r.Calls > 0 {
+			r.AverageDuration = Duration(duration / time.Duration(r.Calls))
+		}
+		for gr, rr := range resources {
+			rr.AverageDuration = Duration(resourceDurations[gr] / time.Duration(rr.Calls))
+			r.Resources = append(r.Resources, *rr)
+		}
+		sortResources(r.Resources)
+		report.Plugins = append(report.Plugins, r)
+	}
+
+	// the chain runs all mutating plugins first, then all validating plugins.
+	ordered := make([]PluginReport, 0, len(report.Plugins))
+	for _, r := range report.Plugins {
+		if r.Mutating {
+			ordered = append(ordered, r)
+		}
+	}
+	for _, r := range report.Plugins {
+		if !r.Mutating {
+			ordered = append(ordered, r)
+		}
+	}
+	report.Plugins = ordered
+
+	return report
+}
+
+func sortResources(resources []ResourceReport) {
+	sort.Slice(resources, func(i, j int) bool {
+		if resources[i].Calls != resources[j].Calls {
+			return resources[i].Calls > resources[j].Calls
+		}
+		return resources[i].Resource < resources[j].Resource
+	})
+}
+
+// ServeHTTP serves the Report as JSON. The operation query parameter, e.g. CREATE,
+// restricts the report to the plugins handling that operation, and the resource
+// parameter, e.g. deployments.apps, to the calls for that resource.
+func (c *Chain) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+
+	operation := admission.Operation(strings.ToUpper(query.Get("operation")))
+	if operation != "" {
+		valid := false
+		for _, op := range operations {
+			valid = valid || op == operation
+		}
+		if !valid {
+			http.Error(w, "invalid operation: "+string(operation), http.StatusBadRequest)
+			return
+		}
+	}
+	var resource *schema.GroupResource
+	if s := query.Get("resource"); s != "" {
+		gr := schema.ParseGroupResource(s)
+		resource = &gr
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(c.Report(operation, resource))
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/admission"
+)
+
+type fakeMutator struct {
+	*admission.Handler
+	err error
+}
+
+func (f *fakeMutator) Admit(ctx context.Context, a admission.Attributes, o admission.ObjectInterfaces) error {
+	return f.err
+}
+
+type fakeValidator struct {
+	*admission.Handler
+	err error
+}
+
+func (f *fakeValidator) Validate(ctx context.Context, a admission.Attributes, o admission.ObjectInterfaces) error {
+	return f.err
+}
+
+type fakeMutatorValidator struct {
+	fakeMutator
+}
+
+func (f *fakeMutatorValidator) Validate(ctx context.Context, a admission.Attributes, o admission.ObjectInterfaces) error {
+	return f.err
+}
+
+func attrs(op admission.Operation, gr schema.GroupResource) admission.Attributes {
+	return admission.NewAttributesRecord(nil, nil, schema.GroupVersionKind{}, "", "foo", gr.WithVersion("v1"), "", op, nil, false, nil)
+}
+
+func TestDecorate(t *testing.T) {
+	c := NewChain()
+
+	mutator := c.Decorate(&fakeMutator{Handler: admission.NewHandler(admission.Create)}, "mutator")
+	_, isValidator := mutator.(admission.ValidationInterface)
+	require.False(t, isValidator, "mutating-only plugin must not become validating")
+	_, isMutator := mutator.(admission.MutationInterface)
+	require.True(t, isMutator)
+
+	validator := c.Decorate(&fakeValidator{Handler: admission.NewHandler(admission.Update)}, "validator")
+	_, isMutator = validator.(admission.MutationInterface)
+	require.False(t, isMutator, "validating-only plugin must not become mutating")
+	require.True(t, validator.Handles(admission.Update))
+	require.False(t, validator.Handles(admission.Create))
+
+	both := c.Decorate(&fakeMutatorValidator{fakeMutator{Handler: admission.NewHandler(admission.Create)}}, "both")
+	_, isMutator = both.(admission.MutationInterface)
+	_, isValidator = both.(admission.ValidationInterface)
+	require.True(t, isMutator)
+	require.True(t, isValidator)
+
+	// decorating again keeps the position
+	c.Decorate(&fakeMutator{Handler: admission.NewHandler(admission.Create)}, "mutator")
+	require.Len(t, c.plugins, 3)
+	require.Equal(t, "mutator", c.plugins[0].name)
+}
+
+func TestReport(t *testing.T) {
+	deployments := schema.GroupResource{Group: "apps", Resource: "deployments"}
+	configmaps := schema.GroupResource{Resource: "configmaps"}
+
+	c := NewChain()
+	now := time.Unix(0, 0)
+	c.now = func() time.Time {
+		now = now.Add(time.Millisecond)
+		return now
+	}
+
+	validator := &fakeValidator{Handler: admission.NewHandler(admission.Create, admission.Update)}
+	v := c.Decorate(validator, "validator").(admission.ValidationInterface)
+	m := c.Decorate(&fakeMutator{Handler: admission.NewHandler(admission.Create)}, "mutator").(admission.MutationInterface)
+
+	ctx := context.Background()
+	require.NoError(t, m.Admit(ctx, attrs(admission.Create, deployments), nil))
+	require.NoError(t, v.Validate(ctx, attrs(admission.Create, deployments), nil))
+	require.NoError(t, v.Validate(ctx, attrs(admission.Update, configmaps), nil))
+	validator.err = errors.New("denied")
+	require.Error(t, v.Validate(ctx, attrs(admission.Create, deployments), nil))
+
+	r := c.Report("", nil)
+	require.Len(t, r.Plugins, 2)
+	require.Equal(t, "mutator", r.Plugins[0].Name, "mutating plugins run first")
+	require.Equal(t, "validator", r.Plugins[1].Name)
+	require.Equal(t, []string{"CREATE", "UPDATE"}, r.Plugins[1].Operations)
+	require.Equal(t, int64(3), r.Plugins[1].Calls)
+	require.Equal(t, int64(1), r.Plugins[1].Rejections)
+	require.Equal(t, Duration(time.Millisecond), r.Plugins[1].AverageDuration)
+	require.Equal(t, []ResourceReport{
+		{Resource: "deployments.apps", Calls: 2, Rejections: 1, AverageDuration: Duration(time.Millisecond)},
+		{Resource: "configmaps", Calls: 1, AverageDuration: Duration(time.Millisecond)},
+	}, r.Plugins[1].Resources)
+
+	r = c.Report(admission.Update, nil)
+	require.Len(t, r.Plugins, 1)
+	require.Equal(t, "validator", r.Plugins[0].Name)
+	require.Equal(t, int64(1), r.Plugins[0].Calls)
+
+	r = c.Report("", &configmaps)
+	require.Len(t, r.Plugins, 2)
+	require.Equal(t, int64(0), r.Plugins[0].Calls)
+	require.Equal(t, int64(1), r.Plugins[1].Calls)
+}
+
+func TestServeHTTP(t *testing.T) {
+	c := NewChain()
+	c.Decorate(&fakeValidator{Handler: admission.NewHandler(admission.Delete)}, "validator")
+
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest("GET", DebugPath+"?operation=delete&resource=deployments.apps", nil))
+	require.Equal(t, 200, rec.Code)
+	var r struct {
+		Plugins []struct {
+			Name string `json:"name"`
+		} `json:"plugins"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &r))
+	require.Len(t, r.Plugins, 1)
+	require.Equal(t, "validator", r.Plugins[0].Name)
+
+	rec = httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest("GET", DebugPath+"?operation=create", nil))
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &r))
+	require.Empty(t, r.Plugins)
+
+	rec = httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest("GET", DebugPath+"?operation=patch", nil))
+	require.Equal(t, 400, rec.Code)
+}
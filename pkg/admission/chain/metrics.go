@@ -0,0 +1,59 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chain
+
+import (
+	"sync"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+var (
+	// the resource is not a label to bound the cardinality, the debug endpoint
+	// reports the calls by resource.
+	pluginDuration = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Namespace:      "kcp",
+			Name:           "admission_plugin_duration_seconds",
+			Help:           "Latency of admission plugins, by plugin, phase (admit or validate) and operation.",
+			Buckets:        []float64{0.0001, 0.0005, 0.001, 0.005, 0.025, 0.1, 0.5, 2.5},
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"plugin", "phase", "operation"},
+	)
+
+	pluginRejections = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace:      "kcp",
+			Name:           "admission_plugin_rejections_total",
+			Help:           "Number of requests rejected by admission plugins, by plugin, phase (admit or validate) and operation.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"plugin", "phase", "operation"},
+	)
+
+	registerMetrics sync.Once
+)
+
+// RegisterMetrics registers the admission plugin metrics.
+func RegisterMetrics() {
+	registerMetrics.Do(func() {
+		legacyregistry.MustRegister(pluginDuration)
+		legacyregistry.MustRegister(pluginRejections)
+	})
+}
@@ -21,18 +21,26 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"regexp"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/apiserver/pkg/admission"
 	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
 
+	"github.com/kcp-dev/kcp/pkg/admission/freezewindows"
 	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/workspacetemplate"
 )
 
 // Validate ClusterWorkspaceTypes creation and updates for
 //  - "organization" type is only created in root workspace.
+//  - spec.namespaces has valid name patterns and default namespace names.
+//  - spec.freezeWindows have unique names and known time zones.
+//  - spec.template has a structural parameter schema and parseable manifests.
 
 const (
 	PluginName = "tenancy.kcp.dev/ClusterWorkspaceType"
@@ -76,5 +84,58 @@ func (o *clusterWorkspaceType) Validate(ctx context.Context, a admission.Attribu
 		return errors.New("organization type can only be created in root workspace")
 	}
 
+	if errs := validateNamespaces(cwt.Spec.Namespaces, field.NewPath("spec", "namespaces")); len(errs) > 0 {
+		return admission.NewForbidden(a, errs.ToAggregate())
+	}
+	if errs := validateFreezeWindows(cwt.Spec.FreezeWindows, field.NewPath("spec", "freezeWindows")); len(errs) > 0 {
+		return admission.NewForbidden(a, errs.ToAggregate())
+	}
+	if errs := workspacetemplate.Validate(cwt.Spec.Template, field.NewPath("spec", "template")); len(errs) > 0 {
+		return admission.NewForbidden(a, errs.ToAggregate())
+	}
+
 	return nil
 }
+
+func validateNamespaces(namespaces *tenancyv1alpha1.ClusterWorkspaceNamespaces, fldPath *field.Path) field.ErrorList {
+	if namespaces == nil {
+		return nil
+	}
+
+	var errs field.ErrorList
+	for i, pattern := range namespaces.AllowedNamePatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			errs = append(errs, field.Invalid(fldPath.Child("allowedNamePatterns").Index(i), pattern, err.Error()))
+		}
+	}
+	seen := map[string]bool{}
+	for i, ns := range namespaces.Defaults {
+		namePath := fldPath.Child("defaults").Index(i).Child("name")
+		for _, msg := range validation.IsDNS1123Label(ns.Name) {
+			errs = append(errs, field.Invalid(namePath, ns.Name, msg))
+		}
+		if seen[ns.Name] {
+			errs = append(errs, field.Duplicate(namePath, ns.Name))
+		}
+		seen[ns.Name] = true
+	}
+	if namespaces.Disabled && len(namespaces.Defaults) > 0 {
+		errs = append(errs, field.Forbidden(fldPath.Child("defaults"), "must be empty if namespaces are disabled"))
+	}
+	return errs
+}
+
+func validateFreezeWindows(windows []tenancyv1alpha1.FreezeWindow, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	seen := map[string]bool{}
+	for i, w := range windows {
+		if seen[w.Name] {
+			errs = append(errs, field.Duplicate(fldPath.Index(i).Child("name"), w.Name))
+		}
+		seen[w.Name] = true
+		if err := freezewindows.ValidateTimeZone(w.TimeZone); err != nil {
+			errs = append(errs, field.Invalid(fldPath.Index(i).Child("timeZone"), w.TimeZone, err.Error()))
+		}
+	}
+	return errs
+}
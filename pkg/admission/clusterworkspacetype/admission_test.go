@@ -23,6 +23,7 @@ import (
 	"github.com/kcp-dev/logicalcluster"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apiserver/pkg/admission"
 	"k8s.io/apiserver/pkg/authentication/user"
 	"k8s.io/apiserver/pkg/endpoints/request"
@@ -101,6 +102,165 @@ func TestValidate(t *testing.T) {
 			clusterName: logicalcluster.New("foo:bar"),
 			wantErr:     true,
 		},
+		{
+			name: "allow valid namespaces",
+			a: createAttr(&tenancyv1alpha1.ClusterWorkspaceType{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: tenancyv1alpha1.ClusterWorkspaceTypeSpec{
+					Namespaces: &tenancyv1alpha1.ClusterWorkspaceNamespaces{
+						AllowedNamePatterns: []string{"team-.*"},
+						Defaults:            []tenancyv1alpha1.DefaultNamespace{{Name: "team-default"}},
+					},
+				},
+			}),
+			clusterName: logicalcluster.New("foo:bar"),
+			wantErr:     false,
+		},
+		{
+			name: "deny invalid name pattern",
+			a: createAttr(&tenancyv1alpha1.ClusterWorkspaceType{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: tenancyv1alpha1.ClusterWorkspaceTypeSpec{
+					Namespaces: &tenancyv1alpha1.ClusterWorkspaceNamespaces{
+						AllowedNamePatterns: []string{"team-(.*"},
+					},
+				},
+			}),
+			clusterName: logicalcluster.New("foo:bar"),
+			wantErr:     true,
+		},
+		{
+			name: "deny invalid default namespace name",
+			a: createAttr(&tenancyv1alpha1.ClusterWorkspaceType{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: tenancyv1alpha1.ClusterWorkspaceTypeSpec{
+					Namespaces: &tenancyv1alpha1.ClusterWorkspaceNamespaces{
+						Defaults: []tenancyv1alpha1.DefaultNamespace{{Name: "Team_Default"}},
+					},
+				},
+			}),
+			clusterName: logicalcluster.New("foo:bar"),
+			wantErr:     true,
+		},
+		{
+			name: "deny default namespaces if namespaces are disabled",
+			a: createAttr(&tenancyv1alpha1.ClusterWorkspaceType{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: tenancyv1alpha1.ClusterWorkspaceTypeSpec{
+					Namespaces: &tenancyv1alpha1.ClusterWorkspaceNamespaces{
+						Disabled: true,
+						Defaults: []tenancyv1alpha1.DefaultNamespace{{Name: "default"}},
+					},
+				},
+			}),
+			clusterName: logicalcluster.New("foo:bar"),
+			wantErr:     true,
+		},
+		{
+			name: "allow valid freeze windows",
+			a: createAttr(&tenancyv1alpha1.ClusterWorkspaceType{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: tenancyv1alpha1.ClusterWorkspaceTypeSpec{
+					FreezeWindows: []tenancyv1alpha1.FreezeWindow{
+						{Name: "weekend", Days: []tenancyv1alpha1.FreezeWindowDay{"Saturday"}, Start: "00:00", End: "00:00", TimeZone: "Europe/Berlin"},
+						{Name: "nightly", Start: "22:00", End: "06:00"},
+					},
+				},
+			}),
+			clusterName: logicalcluster.New("foo:bar"),
+			wantErr:     false,
+		},
+		{
+			name: "deny unknown time zone of freeze window",
+			a: createAttr(&tenancyv1alpha1.ClusterWorkspaceType{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: tenancyv1alpha1.ClusterWorkspaceTypeSpec{
+					FreezeWindows: []tenancyv1alpha1.FreezeWindow{
+						{Name: "weekend", Start: "00:00", End: "00:00", TimeZone: "Mars/Olympus_Mons"},
+					},
+				},
+			}),
+			clusterName: logicalcluster.New("foo:bar"),
+			wantErr:     true,
+		},
+		{
+			name: "deny duplicate freeze window names",
+			a: createAttr(&tenancyv1alpha1.ClusterWorkspaceType{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: tenancyv1alpha1.ClusterWorkspaceTypeSpec{
+					FreezeWindows: []tenancyv1alpha1.FreezeWindow{
+						{Name: "release", Start: "08:00", End: "18:00"},
+						{Name: "release", Start: "20:00", End: "22:00"},
+					},
+				},
+			}),
+			clusterName: logicalcluster.New("foo:bar"),
+			wantErr:     true,
+		},
+		{
+			name: "allow valid template",
+			a: createAttr(&tenancyv1alpha1.ClusterWorkspaceType{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: tenancyv1alpha1.ClusterWorkspaceTypeSpec{
+					Template: &tenancyv1alpha1.ClusterWorkspaceTemplate{
+						ParameterSchema: &runtime.RawExtension{Raw: []byte(`{"type":"object","properties":{"env":{"type":"string"}}}`)},
+						Manifests: []tenancyv1alpha1.ClusterWorkspaceTemplateManifest{
+							{Name: "ns", Template: "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: {{ .Parameters.env }}\n"},
+						},
+					},
+				},
+			}),
+			clusterName: logicalcluster.New("foo:bar"),
+			wantErr:     false,
+		},
+		{
+			name: "deny template that does not parse",
+			a: createAttr(&tenancyv1alpha1.ClusterWorkspaceType{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: tenancyv1alpha1.ClusterWorkspaceTypeSpec{
+					Template: &tenancyv1alpha1.ClusterWorkspaceTemplate{
+						Manifests: []tenancyv1alpha1.ClusterWorkspaceTemplateManifest{
+							{Name: "ns", Template: "{{ .Parameters.env "},
+						},
+					},
+				},
+			}),
+			clusterName: logicalcluster.New("foo:bar"),
+			wantErr:     true,
+		},
+		{
+			name: "deny non-structural parameter schema",
+			a: createAttr(&tenancyv1alpha1.ClusterWorkspaceType{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: tenancyv1alpha1.ClusterWorkspaceTypeSpec{
+					Template: &tenancyv1alpha1.ClusterWorkspaceTemplate{
+						ParameterSchema: &runtime.RawExtension{Raw: []byte(`{"type":"object","properties":{"env":{}}}`)},
+					},
+				},
+			}),
+			clusterName: logicalcluster.New("foo:bar"),
+			wantErr:     true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -18,6 +18,7 @@ package clusterworkspacetypeexists
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -25,10 +26,12 @@ import (
 
 	"github.com/kcp-dev/logicalcluster"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/apiserver/pkg/admission"
 	"k8s.io/apiserver/pkg/authorization/authorizer"
 	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
@@ -40,6 +43,7 @@ import (
 	"github.com/kcp-dev/kcp/pkg/authorization/delegated"
 	kcpinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
 	tenancyv1alpha1lister "github.com/kcp-dev/kcp/pkg/client/listers/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/workspacetemplate"
 )
 
 const (
@@ -57,9 +61,10 @@ func Register(plugins *admission.Plugins) {
 }
 
 // clusterWorkspaceTypeExists  does the following
-// - it checks existence of ClusterWorkspaceType in the same workspace,
-// - it applies the ClusterWorkspaceType initializers to the ClusterWorkspace when it
-//   transitions to the Initializing state.
+//   - it checks existence of ClusterWorkspaceType in the same workspace,
+//   - it validates and defaults the template parameters of the ClusterWorkspace on creation,
+//   - it applies the ClusterWorkspaceType initializers to the ClusterWorkspace when it
+//     transitions to the Initializing state.
 type clusterWorkspaceTypeExists struct {
 	*admission.Handler
 	typeLister        tenancyv1alpha1lister.ClusterWorkspaceTypeLister
@@ -114,6 +119,9 @@ func (o *clusterWorkspaceTypeExists) Admit(ctx context.Context, a admission.Attr
 
 	if a.GetOperation() == admission.Create {
 		addAdditionalWorkspaceLabels(cwt, cw)
+		if err := defaultTemplateParameters(cwt, cw); err != nil {
+			return admission.NewForbidden(a, err)
+		}
 
 		return updateUnstructured(u, cw)
 	}
@@ -147,7 +155,7 @@ func (o *clusterWorkspaceTypeExists) Admit(ctx context.Context, a admission.Attr
 	for _, i := range cw.Status.Initializers {
 		existing.Insert(string(i))
 	}
-	for _, i := range cwt.Spec.Initializers {
+	for _, i := range typeInitializers(cwt) {
 		if !existing.Has(string(i)) {
 			cw.Status.Initializers = append(cw.Status.Initializers, i)
 		}
@@ -158,6 +166,7 @@ func (o *clusterWorkspaceTypeExists) Admit(ctx context.Context, a admission.Attr
 
 // Validate ensures that
 // - has a valid type
+// - has valid template parameters on creation, and they render
 // - has valid initializers when transitioning to initializing
 func (o *clusterWorkspaceTypeExists) Validate(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) (err error) {
 	if a.GetResource().GroupResource() != tenancyv1alpha1.Resource("clusterworkspaces") {
@@ -205,6 +214,9 @@ func (o *clusterWorkspaceTypeExists) Validate(ctx context.Context, a admission.A
 		if old.Spec.Type != cw.Spec.Type {
 			return admission.NewForbidden(a, errors.New("spec.type is immutable"))
 		}
+		if !equality.Semantic.DeepEqual(old.Spec.TemplateParameters, cw.Spec.TemplateParameters) {
+			return admission.NewForbidden(a, errors.New("spec.templateParameters is immutable"))
+		}
 	}
 
 	// check type on create and on state transition
@@ -213,8 +225,9 @@ func (o *clusterWorkspaceTypeExists) Validate(ctx context.Context, a admission.A
 	//		        the race either. So, ¯\_(ツ)_/¯. Chance is low. Object can be deleted, or a condition could should
 	//              show it failing.
 	var cwt *tenancyv1alpha1.ClusterWorkspaceType
+	var clusterName logicalcluster.Name
 	if (a.GetOperation() == admission.Update && transitioningToInitializing) || a.GetOperation() == admission.Create {
-		clusterName, err := genericapirequest.ClusterNameFrom(ctx)
+		clusterName, err = genericapirequest.ClusterNameFrom(ctx)
 		if err != nil {
 			return apierrors.NewInternalError(err)
 		}
@@ -222,6 +235,12 @@ func (o *clusterWorkspaceTypeExists) Validate(ctx context.Context, a admission.A
 		cwt, err = o.typeLister.Get(clusters.ToClusterAwareKey(clusterName, strings.ToLower(cw.Spec.Type)))
 		if err != nil && apierrors.IsNotFound(err) {
 			if cw.Spec.Type == "Universal" {
+				if a.GetOperation() == admission.Create {
+					// there is no template without a type
+					if _, errs := workspacetemplate.Parameters(nil, cw.Spec.TemplateParameters, field.NewPath("spec", "templateParameters")); len(errs) > 0 {
+						return admission.NewForbidden(a, errs.ToAggregate())
+					}
+				}
 				return nil // Universal is always valid
 			}
 			return admission.NewForbidden(a, fmt.Errorf("spec.type %q does not exist", cw.Spec.Type))
@@ -238,13 +257,30 @@ func (o *clusterWorkspaceTypeExists) Validate(ctx context.Context, a admission.A
 		for _, initializer := range cw.Status.Initializers {
 			existing.Insert(string(initializer))
 		}
-		for _, initializer := range cwt.Spec.Initializers {
+		for _, initializer := range typeInitializers(cwt) {
 			if !existing.Has(string(initializer)) {
 				return admission.NewForbidden(a, fmt.Errorf("spec.initializers %q does not exist", initializer))
 			}
 		}
 	}
 
+	// validate the template parameters, and render the template to surface errors
+	// before the workspace is initialized.
+	if a.GetOperation() == admission.Create {
+		params, errs := workspacetemplate.Parameters(cwt.Spec.Template, cw.Spec.TemplateParameters, field.NewPath("spec", "templateParameters"))
+		if len(errs) > 0 {
+			return admission.NewForbidden(a, errs.ToAggregate())
+		}
+		ws := workspacetemplate.Workspace{
+			Name: cw.Name,
+			Path: clusterName.Join(cw.Name).String(),
+			Type: cw.Spec.Type,
+		}
+		if _, err := workspacetemplate.Render(cwt.Spec.Template, ws, params); err != nil {
+			return admission.NewForbidden(a, fmt.Errorf("failed to render template of cluster workspace type %q: %w", cw.Spec.Type, err))
+		}
+	}
+
 	// verify that the type can be used by the given user
 	if a.GetOperation() == admission.Create {
 		authz, err := o.createAuthorizer(logicalcluster.From(cwt), o.kubeClusterClient)
@@ -297,6 +333,41 @@ func updateUnstructured(u *unstructured.Unstructured, cw *tenancyv1alpha1.Cluste
 	return nil
 }
 
+// typeInitializers returns the initializers of the given type, including those of
+// the system for features of the type.
+func typeInitializers(cwt *tenancyv1alpha1.ClusterWorkspaceType) []tenancyv1alpha1.ClusterWorkspaceInitializer {
+	initializers := cwt.Spec.Initializers
+	if cwt.Spec.Namespaces != nil && len(cwt.Spec.Namespaces.Defaults) > 0 {
+		initializers = append(initializers[:len(initializers):len(initializers)], tenancyv1alpha1.DefaultNamespacesInitializer)
+	}
+	if cwt.Spec.Template != nil && len(cwt.Spec.Template.Manifests) > 0 {
+		initializers = append(initializers[:len(initializers):len(initializers)], tenancyv1alpha1.TemplateInitializer)
+	}
+	return initializers
+}
+
+// defaultTemplateParameters applies the defaults of the parameter schema of the type
+// to the template parameters of the workspace.
+func defaultTemplateParameters(cwt *tenancyv1alpha1.ClusterWorkspaceType, cw *tenancyv1alpha1.ClusterWorkspace) error {
+	if cwt.Spec.Template == nil || cwt.Spec.Template.ParameterSchema == nil {
+		return nil // parameters are rejected in Validate
+	}
+
+	params, errs := workspacetemplate.Parameters(cwt.Spec.Template, cw.Spec.TemplateParameters, field.NewPath("spec", "templateParameters"))
+	if len(errs) > 0 {
+		return errs.ToAggregate()
+	}
+	if len(params) == 0 {
+		return nil
+	}
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	cw.Spec.TemplateParameters = &runtime.RawExtension{Raw: raw}
+	return nil
+}
+
 // addAdditionlWorkspaceLabels adds labels defined by the workspace
 // type to the workspace if they are not already present.
 func addAdditionalWorkspaceLabels(
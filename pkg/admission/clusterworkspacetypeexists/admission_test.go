@@ -73,6 +73,29 @@ func updateAttr(obj, old *tenancyv1alpha1.ClusterWorkspace) admission.Attributes
 	)
 }
 
+func templateType() *tenancyv1alpha1.ClusterWorkspaceType {
+	return &tenancyv1alpha1.ClusterWorkspaceType{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "root:org#$#foo",
+		},
+		Spec: tenancyv1alpha1.ClusterWorkspaceTypeSpec{
+			Template: &tenancyv1alpha1.ClusterWorkspaceTemplate{
+				ParameterSchema: &runtime.RawExtension{Raw: []byte(`{"type":"object","required":["env"],"properties":{
+					"env":{"type":"string","enum":["dev","prod"]},
+					"replicas":{"type":"integer","default":1}
+				}}`)},
+				Manifests: []tenancyv1alpha1.ClusterWorkspaceTemplateManifest{
+					{
+						Name:     "namespace",
+						When:     `{{ eq .Parameters.env "prod" }}`,
+						Template: "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: {{ .Workspace.Name }}-{{ .Parameters.owner }}\n",
+					},
+				},
+			},
+		},
+	}
+}
+
 func TestAdmit(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -305,6 +328,42 @@ func TestAdmit(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:  "defaults template parameters",
+			types: []*tenancyv1alpha1.ClusterWorkspaceType{templateType()},
+			a: createAttr(&tenancyv1alpha1.ClusterWorkspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: tenancyv1alpha1.ClusterWorkspaceSpec{
+					Type:               "Foo",
+					TemplateParameters: &runtime.RawExtension{Raw: []byte(`{"env":"dev"}`)},
+				},
+			}),
+			expectedObj: &tenancyv1alpha1.ClusterWorkspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: tenancyv1alpha1.ClusterWorkspaceSpec{
+					Type:               "Foo",
+					TemplateParameters: &runtime.RawExtension{Raw: []byte(`{"env":"dev","replicas":1}`)},
+				},
+			},
+		},
+		{
+			name:  "rejects invalid template parameters",
+			types: []*tenancyv1alpha1.ClusterWorkspaceType{templateType()},
+			a: createAttr(&tenancyv1alpha1.ClusterWorkspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: tenancyv1alpha1.ClusterWorkspaceSpec{
+					Type:               "Foo",
+					TemplateParameters: &runtime.RawExtension{Raw: []byte(`{"env":"dev","unknown":true}`)},
+				},
+			}),
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -546,6 +605,88 @@ func TestValidate(t *testing.T) {
 					},
 				}),
 		},
+		{
+			name:  "passes create with valid template parameters",
+			types: []*tenancyv1alpha1.ClusterWorkspaceType{templateType()},
+			attr: createAttr(&tenancyv1alpha1.ClusterWorkspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: tenancyv1alpha1.ClusterWorkspaceSpec{
+					Type:               "Foo",
+					TemplateParameters: &runtime.RawExtension{Raw: []byte(`{"env":"dev"}`)},
+				},
+			}),
+			authzDecision: authorizer.DecisionAllow,
+		},
+		{
+			name:  "fails create with template parameters violating the schema",
+			types: []*tenancyv1alpha1.ClusterWorkspaceType{templateType()},
+			attr: createAttr(&tenancyv1alpha1.ClusterWorkspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: tenancyv1alpha1.ClusterWorkspaceSpec{
+					Type:               "Foo",
+					TemplateParameters: &runtime.RawExtension{Raw: []byte(`{"env":"qa"}`)},
+				},
+			}),
+			authzDecision: authorizer.DecisionAllow,
+			wantErr:       true,
+		},
+		{
+			name:  "fails create if the template does not render",
+			types: []*tenancyv1alpha1.ClusterWorkspaceType{templateType()},
+			attr: createAttr(&tenancyv1alpha1.ClusterWorkspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: tenancyv1alpha1.ClusterWorkspaceSpec{
+					Type:               "Foo",
+					TemplateParameters: &runtime.RawExtension{Raw: []byte(`{"env":"prod"}`)}, // owner is missing
+				},
+			}),
+			authzDecision: authorizer.DecisionAllow,
+			wantErr:       true,
+		},
+		{
+			name: "fails create with template parameters for implicit Universal",
+			attr: createAttr(&tenancyv1alpha1.ClusterWorkspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: tenancyv1alpha1.ClusterWorkspaceSpec{
+					Type:               "Universal",
+					TemplateParameters: &runtime.RawExtension{Raw: []byte(`{"env":"dev"}`)},
+				},
+			}),
+			authzDecision: authorizer.DecisionAllow,
+			wantErr:       true,
+		},
+		{
+			name:  "fails on template parameter change",
+			types: []*tenancyv1alpha1.ClusterWorkspaceType{templateType()},
+			attr: updateAttr(
+				&tenancyv1alpha1.ClusterWorkspace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test",
+					},
+					Spec: tenancyv1alpha1.ClusterWorkspaceSpec{
+						Type:               "Foo",
+						TemplateParameters: &runtime.RawExtension{Raw: []byte(`{"env":"prod"}`)},
+					},
+				},
+				&tenancyv1alpha1.ClusterWorkspace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test",
+					},
+					Spec: tenancyv1alpha1.ClusterWorkspaceSpec{
+						Type:               "Foo",
+						TemplateParameters: &runtime.RawExtension{Raw: []byte(`{"env":"dev"}`)},
+					},
+				}),
+			wantErr: true,
+		},
 		{
 			name:  "ignores different resources",
 			types: nil,
@@ -619,3 +760,16 @@ type fakeAuthorizer struct {
 func (a *fakeAuthorizer) Authorize(ctx context.Context, attr authorizer.Attributes) (authorized authorizer.Decision, reason string, err error) {
 	return a.authorized, "reason", a.err
 }
+
+func TestTypeInitializers(t *testing.T) {
+	cwt := templateType()
+	cwt.Spec.Initializers = []tenancyv1alpha1.ClusterWorkspaceInitializer{"a"}
+	cwt.Spec.Namespaces = &tenancyv1alpha1.ClusterWorkspaceNamespaces{
+		Defaults: []tenancyv1alpha1.DefaultNamespace{{Name: "default"}},
+	}
+	require.Equal(t, []tenancyv1alpha1.ClusterWorkspaceInitializer{"a", tenancyv1alpha1.DefaultNamespacesInitializer, tenancyv1alpha1.TemplateInitializer}, typeInitializers(cwt))
+	require.Equal(t, []tenancyv1alpha1.ClusterWorkspaceInitializer{"a"}, cwt.Spec.Initializers, "type must not be mutated")
+
+	cwt.Spec.Template.Manifests = nil
+	require.Equal(t, []tenancyv1alpha1.ClusterWorkspaceInitializer{"a", tenancyv1alpha1.DefaultNamespacesInitializer}, typeInitializers(cwt))
+}
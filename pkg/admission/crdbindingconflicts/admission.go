@@ -0,0 +1,136 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crdbindingconflicts
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/warning"
+
+	kcpinitializers "github.com/kcp-dev/kcp/pkg/admission/initializers"
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	kcpinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apibinding"
+)
+
+const (
+	PluginName = "apis.kcp.dev/CRDBindingConflicts"
+)
+
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName,
+		func(_ io.Reader) (admission.Interface, error) {
+			return &crdBindingConflicts{
+				Handler: admission.NewHandler(admission.Create),
+			}, nil
+		})
+}
+
+// crdBindingConflicts detects CRDs created in a workspace that define the same group and resource as
+// a resource bound by an APIBinding. If the APIBinding takes precedence, the CRD would never be served
+// and it is rejected. Otherwise, a warning is returned that the CRD shadows the bound resource.
+type crdBindingConflicts struct {
+	*admission.Handler
+
+	listAPIBindings func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error)
+}
+
+// Ensure that the required admission interfaces are implemented.
+var _ = admission.ValidationInterface(&crdBindingConflicts{})
+var _ = admission.InitializationValidator(&crdBindingConflicts{})
+var _ = kcpinitializers.WantsKcpInformers(&crdBindingConflicts{})
+
+func (o *crdBindingConflicts) Validate(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
+	if a.GetResource().GroupResource() != apiextensions.Resource("customresourcedefinitions") {
+		return nil
+	}
+	if a.GetKind().GroupKind() != apiextensions.Kind("CustomResourceDefinition") {
+		return nil
+	}
+	crd, ok := a.GetObject().(*apiextensions.CustomResourceDefinition)
+	if !ok {
+		return fmt.Errorf("unexpected type %T", a.GetObject())
+	}
+
+	clusterName, err := request.ClusterNameFrom(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve cluster from context: %w", err)
+	}
+	if clusterName == apibinding.ShadowWorkspaceName {
+		return nil
+	}
+
+	if !o.WaitForReady() {
+		return admission.NewForbidden(a, fmt.Errorf("not yet ready to handle request"))
+	}
+
+	apiBindings, err := o.listAPIBindings(clusterName)
+	if err != nil {
+		return admission.NewForbidden(a, err)
+	}
+	for _, apiBinding := range apiBindings {
+		for _, boundResource := range apiBinding.Status.BoundResources {
+			if boundResource.Group != crd.Spec.Group || boundResource.Resource != crd.Spec.Names.Plural {
+				continue
+			}
+
+			if apibinding.ConflictPolicy(apiBinding) == apisv1alpha1.CRDConflictPolicyCRD {
+				warning.AddWarning(ctx, "", fmt.Sprintf("CustomResourceDefinition %s will be served instead of the resource bound by APIBinding %s, because of its conflictPolicy CRD", crd.Name, apiBinding.Name))
+				continue
+			}
+
+			return admission.NewForbidden(a, fmt.Errorf("%s.%s is bound by APIBinding %s, hence the CustomResourceDefinition would not be served. Choose another group, delete the APIBinding, or set its spec.conflictPolicy to CRD", boundResource.Resource, boundResource.Group, apiBinding.Name))
+		}
+	}
+
+	return nil
+}
+
+// ValidateInitialization ensures the required injected fields are set.
+func (o *crdBindingConflicts) ValidateInitialization() error {
+	if o.listAPIBindings == nil {
+		return fmt.Errorf(PluginName + " plugin needs an APIBindings lister")
+	}
+	return nil
+}
+
+func (o *crdBindingConflicts) SetKcpInformers(informers kcpinformers.SharedInformerFactory) {
+	apiBindingInformer := informers.Apis().V1alpha1().APIBindings()
+	o.SetReadyFunc(apiBindingInformer.Informer().HasSynced)
+
+	o.listAPIBindings = func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
+		list, err := apiBindingInformer.Lister().List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+		var ret []*apisv1alpha1.APIBinding
+		for _, b := range list {
+			if logicalcluster.From(b) == clusterName {
+				ret = append(ret, b)
+			}
+		}
+		return ret, nil
+	}
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crdbindingconflicts
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+)
+
+func createAttr(group, plural string) admission.Attributes {
+	return admission.NewAttributesRecord(
+		&apiextensions.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: plural + "." + group,
+			},
+			Spec: apiextensions.CustomResourceDefinitionSpec{
+				Group: group,
+				Names: apiextensions.CustomResourceDefinitionNames{Plural: plural},
+			},
+		},
+		nil,
+		apiextensionsv1.Kind("CustomResourceDefinition").WithVersion("v1"),
+		"",
+		plural+"."+group,
+		apiextensionsv1.Resource("customresourcedefinitions").WithVersion("v1"),
+		"",
+		admission.Create,
+		&metav1.CreateOptions{},
+		false,
+		&user.DefaultInfo{},
+	)
+}
+
+func newAPIBinding(clusterName, name string, policy apisv1alpha1.CRDConflictPolicy, group, resource string) *apisv1alpha1.APIBinding {
+	return &apisv1alpha1.APIBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			ClusterName: clusterName,
+			Name:        name,
+		},
+		Spec: apisv1alpha1.APIBindingSpec{
+			ConflictPolicy: policy,
+		},
+		Status: apisv1alpha1.APIBindingStatus{
+			BoundResources: []apisv1alpha1.BoundAPIResource{
+				{Group: group, Resource: resource},
+			},
+		},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		attr        admission.Attributes
+		clusterName string
+		apiBindings []*apisv1alpha1.APIBinding
+
+		wantErr string
+	}{
+		{
+			name:        "passes without APIBindings",
+			attr:        createAttr("example.io", "widgets"),
+			clusterName: "root:org:ws",
+		},
+		{
+			name:        "passes for other resource",
+			attr:        createAttr("example.io", "widgets"),
+			clusterName: "root:org:ws",
+			apiBindings: []*apisv1alpha1.APIBinding{
+				newAPIBinding("root:org:ws", "gadgets", "", "example.io", "gadgets"),
+				newAPIBinding("root:org:ws", "other-widgets", "", "other.io", "widgets"),
+			},
+		},
+		{
+			name:        "fails for bound resource by default",
+			attr:        createAttr("example.io", "widgets"),
+			clusterName: "root:org:ws",
+			apiBindings: []*apisv1alpha1.APIBinding{
+				newAPIBinding("root:org:ws", "widgets", "", "example.io", "widgets"),
+			},
+			wantErr: "widgets.example.io is bound by APIBinding widgets",
+		},
+		{
+			name:        "fails for bound resource with APIBinding policy",
+			attr:        createAttr("example.io", "widgets"),
+			clusterName: "root:org:ws",
+			apiBindings: []*apisv1alpha1.APIBinding{
+				newAPIBinding("root:org:ws", "widgets", apisv1alpha1.CRDConflictPolicyAPIBinding, "example.io", "widgets"),
+			},
+			wantErr: "set its spec.conflictPolicy to CRD",
+		},
+		{
+			name:        "passes for bound resource with CRD policy",
+			attr:        createAttr("example.io", "widgets"),
+			clusterName: "root:org:ws",
+			apiBindings: []*apisv1alpha1.APIBinding{
+				newAPIBinding("root:org:ws", "widgets", apisv1alpha1.CRDConflictPolicyCRD, "example.io", "widgets"),
+			},
+		},
+		{
+			name:        "passes in the bound CRDs workspace",
+			attr:        createAttr("example.io", "widgets"),
+			clusterName: "system:bound-crds",
+			apiBindings: []*apisv1alpha1.APIBinding{
+				newAPIBinding("system:bound-crds", "widgets", "", "example.io", "widgets"),
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o := &crdBindingConflicts{
+				Handler: admission.NewHandler(admission.Create),
+				listAPIBindings: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
+					var ret []*apisv1alpha1.APIBinding
+					for _, b := range tc.apiBindings {
+						if logicalcluster.From(b) == clusterName {
+							ret = append(ret, b)
+						}
+					}
+					return ret, nil
+				},
+			}
+			ctx := request.WithCluster(context.Background(), request.Cluster{Name: logicalcluster.New(tc.clusterName)})
+
+			err := o.Validate(ctx, tc.attr, nil)
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,265 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dnsrecord
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/admission"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	kcpinitializers "github.com/kcp-dev/kcp/pkg/admission/initializers"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+	kcpinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
+	"github.com/kcp-dev/kcp/pkg/reconciler/workload/dnsrecord"
+)
+
+const (
+	PluginName = "workload.kcp.dev/DNSRecord"
+)
+
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName,
+		func(_ io.Reader) (admission.Interface, error) {
+			return &dnsRecordAdmission{
+				Handler: admission.NewHandler(admission.Create, admission.Update),
+			}, nil
+		})
+}
+
+// dnsRecordAdmission validates the targets of DNSRecords, keeps their name and type immutable,
+// and rejects new DNSRecords for names that no DNSZone allows the workspace to claim, or that
+// are claimed by another DNSRecord already. It also keeps the domains of DNSZones unique
+// and immutable.
+//
+// The DNSRecord controller decides which DNSRecord owns a name in the end, as concurrent
+// requests are not serialized.
+type dnsRecordAdmission struct {
+	*admission.Handler
+
+	listZones         func() ([]*workloadv1alpha1.DNSZone, error)
+	listRecordsByName func(name string) ([]*workloadv1alpha1.DNSRecord, error)
+}
+
+// Ensure that the required admission interfaces are implemented.
+var _ = admission.ValidationInterface(&dnsRecordAdmission{})
+var _ = admission.InitializationValidator(&dnsRecordAdmission{})
+var _ = kcpinitializers.WantsKcpInformers(&dnsRecordAdmission{})
+
+func (o *dnsRecordAdmission) Validate(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
+	if a.GetSubresource() != "" {
+		return nil
+	}
+	switch a.GetResource().GroupResource() {
+	case workloadv1alpha1.Resource("dnsrecords"):
+		return o.validateDNSRecord(ctx, a)
+	case workloadv1alpha1.Resource("dnszones"):
+		return o.validateDNSZone(ctx, a)
+	}
+	return nil
+}
+
+func (o *dnsRecordAdmission) validateDNSRecord(ctx context.Context, a admission.Attributes) error {
+	record := &workloadv1alpha1.DNSRecord{}
+	if err := fromUnstructured(a.GetObject(), record); err != nil {
+		return err
+	}
+
+	errs := validateTargets(record.Spec.RecordType, record.Spec.Targets, field.NewPath("spec", "targets"))
+
+	if a.GetOperation() == admission.Update {
+		old := &workloadv1alpha1.DNSRecord{}
+		if err := fromUnstructured(a.GetOldObject(), old); err != nil {
+			return err
+		}
+		if record.Spec.Name != old.Spec.Name {
+			errs = append(errs, field.Invalid(field.NewPath("spec", "name"), record.Spec.Name, "field is immutable"))
+		}
+		if record.Spec.RecordType != old.Spec.RecordType {
+			errs = append(errs, field.Invalid(field.NewPath("spec", "recordType"), record.Spec.RecordType, "field is immutable"))
+		}
+	}
+	if len(errs) > 0 {
+		return admission.NewForbidden(a, errs.ToAggregate())
+	}
+	if a.GetOperation() != admission.Create {
+		return nil
+	}
+
+	clusterName, err := genericapirequest.ClusterNameFrom(ctx)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+
+	if !o.WaitForReady() {
+		return admission.NewForbidden(a, fmt.Errorf("not yet ready to handle request"))
+	}
+
+	zones, err := o.listZones()
+	if err != nil {
+		return admission.NewForbidden(a, err)
+	}
+	if dnsrecord.ZoneFor(zones, clusterName, record.Spec.Name) == nil {
+		return admission.NewForbidden(a, fmt.Errorf("no DNSZone allows the workspace to claim %q", record.Spec.Name))
+	}
+
+	claimants, err := o.listRecordsByName(record.Spec.Name)
+	if err != nil {
+		return admission.NewForbidden(a, err)
+	}
+	if dnsrecord.Owner(claimants, zones) != nil {
+		return admission.NewForbidden(a, fmt.Errorf("%q is claimed by another DNSRecord", record.Spec.Name))
+	}
+
+	return nil
+}
+
+// validateTargets validates the targets of a record of the given type.
+func validateTargets(recordType workloadv1alpha1.DNSRecordType, targets []string, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if recordType == workloadv1alpha1.DNSRecordTypeCNAME && len(targets) > 1 {
+		errs = append(errs, field.TooMany(fldPath, len(targets), 1))
+	}
+	for i, target := range targets {
+		switch recordType {
+		case workloadv1alpha1.DNSRecordTypeA, "":
+			if ip := net.ParseIP(target); ip == nil || ip.To4() == nil {
+				errs = append(errs, field.Invalid(fldPath.Index(i), target, "must be an IPv4 address"))
+			}
+		case workloadv1alpha1.DNSRecordTypeAAAA:
+			if ip := net.ParseIP(target); ip == nil || ip.To4() != nil {
+				errs = append(errs, field.Invalid(fldPath.Index(i), target, "must be an IPv6 address"))
+			}
+		case workloadv1alpha1.DNSRecordTypeCNAME:
+			for _, msg := range validation.IsDNS1123Subdomain(target) {
+				errs = append(errs, field.Invalid(fldPath.Index(i), target, msg))
+			}
+		}
+	}
+	return errs
+}
+
+func (o *dnsRecordAdmission) validateDNSZone(ctx context.Context, a admission.Attributes) error {
+	zone := &workloadv1alpha1.DNSZone{}
+	if err := fromUnstructured(a.GetObject(), zone); err != nil {
+		return err
+	}
+
+	if a.GetOperation() == admission.Update {
+		old := &workloadv1alpha1.DNSZone{}
+		if err := fromUnstructured(a.GetOldObject(), old); err != nil {
+			return err
+		}
+		if zone.Spec.Domain != old.Spec.Domain {
+			return admission.NewForbidden(a, field.Invalid(field.NewPath("spec", "domain"), zone.Spec.Domain, "field is immutable"))
+		}
+		return nil
+	}
+
+	clusterName, err := genericapirequest.ClusterNameFrom(ctx)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	if clusterName != tenancyv1alpha1.RootCluster {
+		return admission.NewForbidden(a, fmt.Errorf("DNSZones can only be created in the root workspace"))
+	}
+
+	if !o.WaitForReady() {
+		return admission.NewForbidden(a, fmt.Errorf("not yet ready to handle request"))
+	}
+
+	zones, err := o.listZones()
+	if err != nil {
+		return admission.NewForbidden(a, err)
+	}
+	for _, other := range zones {
+		if logicalcluster.From(other) == tenancyv1alpha1.RootCluster && other.Spec.Domain == zone.Spec.Domain {
+			return admission.NewForbidden(a, fmt.Errorf("domain %q is delegated by DNSZone %s already", zone.Spec.Domain, other.Name))
+		}
+	}
+
+	return nil
+}
+
+func fromUnstructured(obj runtime.Object, into interface{}) error {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return fmt.Errorf("unexpected type %T", obj)
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, into); err != nil {
+		return fmt.Errorf("failed to convert unstructured to %T: %w", into, err)
+	}
+	return nil
+}
+
+func (o *dnsRecordAdmission) ValidateInitialization() error {
+	if o.listZones == nil {
+		return fmt.Errorf(PluginName + " plugin needs a DNSZone lister")
+	}
+	if o.listRecordsByName == nil {
+		return fmt.Errorf(PluginName + " plugin needs a DNSRecord indexer")
+	}
+	return nil
+}
+
+func (o *dnsRecordAdmission) SetKcpInformers(informers kcpinformers.SharedInformerFactory) {
+	recordInformer := informers.Workload().V1alpha1().DNSRecords().Informer()
+	zoneInformer := informers.Workload().V1alpha1().DNSZones().Informer()
+	o.SetReadyFunc(func() bool {
+		return recordInformer.HasSynced() && zoneInformer.HasSynced()
+	})
+
+	if _, found := recordInformer.GetIndexer().GetIndexers()[dnsrecord.IndexDNSRecordsByName]; !found {
+		if err := recordInformer.AddIndexers(cache.Indexers{
+			dnsrecord.IndexDNSRecordsByName: dnsrecord.IndexDNSRecordsByNameFunc,
+		}); err != nil {
+			// nothing we can do here. But this should also never happen. We check for existence before.
+			klog.Errorf("failed to add indexer for DNSRecords: %v", err)
+		}
+	}
+	recordIndexer := recordInformer.GetIndexer()
+	o.listRecordsByName = func(name string) ([]*workloadv1alpha1.DNSRecord, error) {
+		objs, err := recordIndexer.ByIndex(dnsrecord.IndexDNSRecordsByName, name)
+		if err != nil {
+			return nil, err
+		}
+		records := make([]*workloadv1alpha1.DNSRecord, 0, len(objs))
+		for _, obj := range objs {
+			records = append(records, obj.(*workloadv1alpha1.DNSRecord))
+		}
+		return records, nil
+	}
+
+	zoneLister := informers.Workload().V1alpha1().DNSZones().Lister()
+	o.listZones = func() ([]*workloadv1alpha1.DNSZone, error) {
+		return zoneLister.List(labels.Everything())
+	}
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dnsrecord
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+)
+
+func attr(t *testing.T, op admission.Operation, resource string, obj, old runtime.Object) admission.Attributes {
+	toUnstructured := func(obj runtime.Object) runtime.Object {
+		if obj == nil {
+			return nil
+		}
+		raw, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		require.NoError(t, err)
+		return &unstructured.Unstructured{Object: raw}
+	}
+	var opts runtime.Object = &metav1.CreateOptions{}
+	if op == admission.Update {
+		opts = &metav1.UpdateOptions{}
+	}
+	return admission.NewAttributesRecord(
+		toUnstructured(obj),
+		toUnstructured(old),
+		workloadv1alpha1.SchemeGroupVersion.WithKind("DNSRecord"),
+		"default",
+		"shop",
+		workloadv1alpha1.SchemeGroupVersion.WithResource(resource),
+		"",
+		op,
+		opts,
+		false,
+		&user.DefaultInfo{},
+	)
+}
+
+func TestValidate(t *testing.T) {
+	earlier := metav1.NewTime(time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC))
+
+	record := func(name string, recordType workloadv1alpha1.DNSRecordType, targets ...string) *workloadv1alpha1.DNSRecord {
+		return &workloadv1alpha1.DNSRecord{
+			ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "default"},
+			Spec:       workloadv1alpha1.DNSRecordSpec{Name: name, RecordType: recordType, Targets: targets},
+		}
+	}
+	zone := func(domain string) *workloadv1alpha1.DNSZone {
+		return &workloadv1alpha1.DNSZone{
+			ObjectMeta: metav1.ObjectMeta{Name: "apps", ClusterName: "root", CreationTimestamp: earlier},
+			Spec:       workloadv1alpha1.DNSZoneSpec{Domain: domain, Provider: "webhook", Workspaces: []string{"root:org"}},
+		}
+	}
+	existing := record("taken.apps.example.com", workloadv1alpha1.DNSRecordTypeA, "10.0.0.2")
+	existing.ClusterName = "root:org:other"
+	existing.CreationTimestamp = earlier
+
+	tests := []struct {
+		name    string
+		cluster string
+		attr    admission.Attributes
+		wantErr bool
+	}{
+		{
+			name:    "record in a zone is admitted",
+			cluster: "root:org:ws",
+			attr:    attr(t, admission.Create, "dnsrecords", record("shop.apps.example.com", workloadv1alpha1.DNSRecordTypeA, "10.0.0.1"), nil),
+		},
+		{
+			name:    "record without zone is rejected",
+			cluster: "root:org:ws",
+			attr:    attr(t, admission.Create, "dnsrecords", record("shop.other.example.com", workloadv1alpha1.DNSRecordTypeA, "10.0.0.1"), nil),
+			wantErr: true,
+		},
+		{
+			name:    "record of a workspace outside of the zone is rejected",
+			cluster: "root:team:ws",
+			attr:    attr(t, admission.Create, "dnsrecords", record("shop.apps.example.com", workloadv1alpha1.DNSRecordTypeA, "10.0.0.1"), nil),
+			wantErr: true,
+		},
+		{
+			name:    "record of a claimed name is rejected",
+			cluster: "root:org:ws",
+			attr:    attr(t, admission.Create, "dnsrecords", record("taken.apps.example.com", workloadv1alpha1.DNSRecordTypeA, "10.0.0.1"), nil),
+			wantErr: true,
+		},
+		{
+			name:    "invalid IPv4 target is rejected",
+			cluster: "root:org:ws",
+			attr:    attr(t, admission.Create, "dnsrecords", record("shop.apps.example.com", workloadv1alpha1.DNSRecordTypeA, "fd00::1"), nil),
+			wantErr: true,
+		},
+		{
+			name:    "IPv6 target is admitted",
+			cluster: "root:org:ws",
+			attr:    attr(t, admission.Create, "dnsrecords", record("shop.apps.example.com", workloadv1alpha1.DNSRecordTypeAAAA, "fd00::1"), nil),
+		},
+		{
+			name:    "CNAME with several targets is rejected",
+			cluster: "root:org:ws",
+			attr:    attr(t, admission.Create, "dnsrecords", record("shop.apps.example.com", workloadv1alpha1.DNSRecordTypeCNAME, "a.example.com", "b.example.com"), nil),
+			wantErr: true,
+		},
+		{
+			name:    "TXT with any target is admitted",
+			cluster: "root:org:ws",
+			attr:    attr(t, admission.Create, "dnsrecords", record("shop.apps.example.com", workloadv1alpha1.DNSRecordTypeTXT, "v=spf1 -all"), nil),
+		},
+		{
+			name:    "update of targets is admitted",
+			cluster: "root:org:ws",
+			attr: attr(t, admission.Update, "dnsrecords",
+				record("taken.apps.example.com", workloadv1alpha1.DNSRecordTypeA, "10.0.0.3"),
+				record("taken.apps.example.com", workloadv1alpha1.DNSRecordTypeA, "10.0.0.2")),
+		},
+		{
+			name:    "update of the name is rejected",
+			cluster: "root:org:ws",
+			attr: attr(t, admission.Update, "dnsrecords",
+				record("other.apps.example.com", workloadv1alpha1.DNSRecordTypeA, "10.0.0.1"),
+				record("shop.apps.example.com", workloadv1alpha1.DNSRecordTypeA, "10.0.0.1")),
+			wantErr: true,
+		},
+		{
+			name:    "update of the type is rejected",
+			cluster: "root:org:ws",
+			attr: attr(t, admission.Update, "dnsrecords",
+				record("shop.apps.example.com", workloadv1alpha1.DNSRecordTypeCNAME, "shop.example.com"),
+				record("shop.apps.example.com", workloadv1alpha1.DNSRecordTypeA, "10.0.0.1")),
+			wantErr: true,
+		},
+		{
+			name:    "zone in root is admitted",
+			cluster: "root",
+			attr:    attr(t, admission.Create, "dnszones", zone("team.example.com"), nil),
+		},
+		{
+			name:    "zone outside of root is rejected",
+			cluster: "root:org",
+			attr:    attr(t, admission.Create, "dnszones", zone("team.example.com"), nil),
+			wantErr: true,
+		},
+		{
+			name:    "zone of a delegated domain is rejected",
+			cluster: "root",
+			attr:    attr(t, admission.Create, "dnszones", zone("apps.example.com"), nil),
+			wantErr: true,
+		},
+		{
+			name:    "update of the domain is rejected",
+			cluster: "root",
+			attr:    attr(t, admission.Update, "dnszones", zone("team.example.com"), zone("apps.example.com")),
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o := &dnsRecordAdmission{
+				Handler: admission.NewHandler(admission.Create, admission.Update),
+				listZones: func() ([]*workloadv1alpha1.DNSZone, error) {
+					return []*workloadv1alpha1.DNSZone{zone("apps.example.com")}, nil
+				},
+				listRecordsByName: func(name string) ([]*workloadv1alpha1.DNSRecord, error) {
+					if name == existing.Spec.Name {
+						return []*workloadv1alpha1.DNSRecord{existing}, nil
+					}
+					return nil, nil
+				},
+			}
+
+			ctx := request.WithCluster(context.Background(), request.Cluster{Name: logicalcluster.New(tc.cluster)})
+			err := o.Validate(ctx, tc.attr, nil)
+			if tc.wantErr {
+				require.Error(t, err)
+				require.True(t, apierrors.IsForbidden(err), "expected forbidden, got %v", err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,256 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package freezewindows
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	// time zones of freeze windows must be known without tzdata in the image
+	_ "time/tzdata"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/tools/clusters"
+
+	kcpinitializers "github.com/kcp-dev/kcp/pkg/admission/initializers"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	kcpinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
+)
+
+const (
+	PluginName = "tenancy.kcp.dev/FreezeWindows"
+)
+
+// reviewGroups are API groups whose create requests only review, but do not store anything.
+var reviewGroups = sets.NewString("authentication.k8s.io", "authorization.k8s.io")
+
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName,
+		func(_ io.Reader) (admission.Interface, error) {
+			return &freezeWindows{
+				Handler: admission.NewHandler(admission.Create, admission.Update, admission.Delete),
+				now:     time.Now,
+			}, nil
+		})
+}
+
+// freezeWindows enforces the freeze windows of the ClusterWorkspaceType of a workspace
+// on the writes in the workspace. During an active window, writes to the resources of
+// the window are rejected, or require the override annotation on the written object.
+// Members of system:masters, e.g. kcp itself, and of the break-glass group are exempted.
+type freezeWindows struct {
+	*admission.Handler
+
+	now func() time.Time
+
+	getClusterWorkspace     func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error)
+	getClusterWorkspaceType func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspaceType, error)
+}
+
+// Ensure that the required admission interfaces are implemented.
+var _ = admission.ValidationInterface(&freezeWindows{})
+var _ = admission.InitializationValidator(&freezeWindows{})
+var _ = kcpinitializers.WantsKcpInformers(&freezeWindows{})
+
+// Validate rejects writes during active freeze windows of the workspace type.
+func (o *freezeWindows) Validate(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
+	if a.GetOperation() == admission.Create && reviewGroups.Has(a.GetResource().Group) {
+		return nil
+	}
+	if userInfo := a.GetUserInfo(); userInfo != nil {
+		for _, group := range userInfo.GetGroups() {
+			if group == user.SystemPrivilegedGroup || group == tenancyv1alpha1.BreakGlassGroup {
+				return nil
+			}
+		}
+	}
+
+	clusterName, err := genericapirequest.ClusterNameFrom(ctx)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	parent, name := clusterName.Split()
+	if parent.Empty() {
+		return nil // root has no type
+	}
+
+	if !o.WaitForReady() {
+		return admission.NewForbidden(a, fmt.Errorf("not yet ready to handle request"))
+	}
+
+	windows, err := o.windows(parent, name)
+	if err != nil {
+		return admission.NewForbidden(a, err)
+	}
+
+	now := o.now()
+	for _, w := range windows {
+		if !matchesResource(w, a.GetResource().GroupResource()) {
+			continue
+		}
+		end, active, err := activeUntil(w, now)
+		if err != nil {
+			return admission.NewForbidden(a, fmt.Errorf("invalid freeze window %q: %w", w.Name, err))
+		}
+		if !active {
+			continue
+		}
+		if w.Enforcement == tenancyv1alpha1.FreezeWindowEnforcementRequireOverride {
+			if hasOverride(a) {
+				continue
+			}
+			return admission.NewForbidden(a, fmt.Errorf("freeze window %q is active until %s, set the %s annotation to override it", w.Name, end.Format(time.RFC3339), tenancyv1alpha1.FreezeWindowOverrideAnnotation))
+		}
+		return admission.NewForbidden(a, fmt.Errorf("freeze window %q is active until %s", w.Name, end.Format(time.RFC3339)))
+	}
+
+	return nil
+}
+
+// windows returns the freeze windows of the type of the given workspace.
+func (o *freezeWindows) windows(parent logicalcluster.Name, name string) ([]tenancyv1alpha1.FreezeWindow, error) {
+	ws, err := o.getClusterWorkspace(parent, name)
+	if apierrors.IsNotFound(err) {
+		return nil, nil // not a ClusterWorkspace based logical cluster
+	} else if err != nil {
+		return nil, err
+	}
+
+	cwt, err := o.getClusterWorkspaceType(parent, strings.ToLower(ws.Spec.Type))
+	if apierrors.IsNotFound(err) {
+		return nil, nil // e.g. Universal without an explicit type
+	} else if err != nil {
+		return nil, err
+	}
+
+	return cwt.Spec.FreezeWindows, nil
+}
+
+func matchesResource(w tenancyv1alpha1.FreezeWindow, gr schema.GroupResource) bool {
+	if len(w.Resources) == 0 {
+		return true
+	}
+	for _, r := range w.Resources {
+		if r.Group == gr.Group && r.Resource == gr.Resource {
+			return true
+		}
+	}
+	return false
+}
+
+// activeUntil returns whether the window is active at the given time, and when it ends.
+func activeUntil(w tenancyv1alpha1.FreezeWindow, now time.Time) (time.Time, bool, error) {
+	loc, err := location(w.TimeZone)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	start, err := time.Parse("15:04", w.Start)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid start: %w", err)
+	}
+	end, err := time.Parse("15:04", w.End)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid end: %w", err)
+	}
+	days := sets.NewString()
+	for _, d := range w.Days {
+		days.Insert(string(d))
+	}
+
+	now = now.In(loc)
+	// a window active now started today, or yesterday if it ends on the next day
+	for _, offset := range []int{0, -1} {
+		day := now.AddDate(0, 0, offset)
+		if days.Len() > 0 && !days.Has(day.Weekday().String()) {
+			continue
+		}
+		from := time.Date(day.Year(), day.Month(), day.Day(), start.Hour(), start.Minute(), 0, 0, loc)
+		until := time.Date(day.Year(), day.Month(), day.Day(), end.Hour(), end.Minute(), 0, 0, loc)
+		if !until.After(from) {
+			until = until.AddDate(0, 0, 1)
+		}
+		if !now.Before(from) && now.Before(until) {
+			return until, true, nil
+		}
+	}
+	return time.Time{}, false, nil
+}
+
+func location(timeZone string) (*time.Location, error) {
+	if timeZone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(timeZone)
+}
+
+// ValidateTimeZone returns an error if the time zone of a freeze window is unknown.
+func ValidateTimeZone(timeZone string) error {
+	_, err := location(timeZone)
+	return err
+}
+
+func hasOverride(a admission.Attributes) bool {
+	var obj runtime.Object
+	if a.GetOperation() == admission.Delete {
+		obj = a.GetOldObject()
+	} else {
+		obj = a.GetObject()
+	}
+	if obj == nil {
+		return false
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return false
+	}
+	return accessor.GetAnnotations()[tenancyv1alpha1.FreezeWindowOverrideAnnotation] != ""
+}
+
+func (o *freezeWindows) ValidateInitialization() error {
+	if o.getClusterWorkspace == nil {
+		return fmt.Errorf(PluginName + " plugin needs a ClusterWorkspace lister")
+	}
+	return nil
+}
+
+func (o *freezeWindows) SetKcpInformers(informers kcpinformers.SharedInformerFactory) {
+	workspacesReady := informers.Tenancy().V1alpha1().ClusterWorkspaces().Informer().HasSynced
+	typesReady := informers.Tenancy().V1alpha1().ClusterWorkspaceTypes().Informer().HasSynced
+	o.SetReadyFunc(func() bool {
+		return workspacesReady() && typesReady()
+	})
+
+	workspaceLister := informers.Tenancy().V1alpha1().ClusterWorkspaces().Lister()
+	o.getClusterWorkspace = func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error) {
+		return workspaceLister.Get(clusters.ToClusterAwareKey(clusterName, name))
+	}
+	typeLister := informers.Tenancy().V1alpha1().ClusterWorkspaceTypes().Lister()
+	o.getClusterWorkspaceType = func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspaceType, error) {
+		return typeLister.Get(clusters.ToClusterAwareKey(clusterName, name))
+	}
+}
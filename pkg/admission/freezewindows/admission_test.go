@@ -0,0 +1,264 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package freezewindows
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	"github.com/kcp-dev/kcp/pkg/admission/helpers"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+func deploymentAttr(op admission.Operation, annotations map[string]string, groups ...string) admission.Attributes {
+	obj := helpers.ToUnstructuredOrDie(&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", Annotations: annotations}})
+	var newObj, oldObj runtime.Object = obj, nil
+	if op == admission.Delete {
+		newObj, oldObj = nil, obj
+	}
+	return admission.NewAttributesRecord(
+		newObj,
+		oldObj,
+		appsv1.SchemeGroupVersion.WithKind("Deployment"),
+		"default",
+		"web",
+		appsv1.SchemeGroupVersion.WithResource("deployments"),
+		"",
+		op,
+		nil,
+		false,
+		&user.DefaultInfo{Name: "user", Groups: groups},
+	)
+}
+
+func configMapAttr() admission.Attributes {
+	return admission.NewAttributesRecord(
+		helpers.ToUnstructuredOrDie(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "default"}}),
+		nil,
+		corev1.SchemeGroupVersion.WithKind("ConfigMap"),
+		"default",
+		"cm",
+		corev1.SchemeGroupVersion.WithResource("configmaps"),
+		"",
+		admission.Create,
+		&metav1.CreateOptions{},
+		false,
+		&user.DefaultInfo{Name: "user"},
+	)
+}
+
+func TestValidate(t *testing.T) {
+	// a Saturday
+	saturday := func(hour, minute int) time.Time {
+		return time.Date(2022, 6, 4, hour, minute, 0, 0, time.UTC)
+	}
+	deployments := []tenancyv1alpha1.FreezeWindowResource{{Group: "apps", Resource: "deployments"}}
+	override := map[string]string{tenancyv1alpha1.FreezeWindowOverrideAnnotation: "INC-123"}
+
+	tests := []struct {
+		name    string
+		cluster string
+		windows []tenancyv1alpha1.FreezeWindow
+		now     time.Time
+		attr    admission.Attributes
+		wantErr bool
+	}{
+		{
+			name:    "writes without freeze windows are admitted",
+			cluster: "root:org:ws",
+			now:     saturday(12, 0),
+			attr:    deploymentAttr(admission.Create, nil),
+		},
+		{
+			name:    "writes during a window are rejected",
+			cluster: "root:org:ws",
+			windows: []tenancyv1alpha1.FreezeWindow{{Name: "nightly", Start: "22:00", End: "06:00"}},
+			now:     saturday(23, 0),
+			attr:    deploymentAttr(admission.Update, nil),
+			wantErr: true,
+		},
+		{
+			name:    "writes outside of a window are admitted",
+			cluster: "root:org:ws",
+			windows: []tenancyv1alpha1.FreezeWindow{{Name: "nightly", Start: "22:00", End: "06:00"}},
+			now:     saturday(12, 0),
+			attr:    deploymentAttr(admission.Update, nil),
+		},
+		{
+			name:    "window started on the day before is active",
+			cluster: "root:org:ws",
+			windows: []tenancyv1alpha1.FreezeWindow{{Name: "friday-night", Days: []tenancyv1alpha1.FreezeWindowDay{"Friday"}, Start: "22:00", End: "06:00"}},
+			now:     saturday(3, 0),
+			attr:    deploymentAttr(admission.Update, nil),
+			wantErr: true,
+		},
+		{
+			name:    "window starting on another day is not active",
+			cluster: "root:org:ws",
+			windows: []tenancyv1alpha1.FreezeWindow{{Name: "saturday-night", Days: []tenancyv1alpha1.FreezeWindowDay{"Saturday"}, Start: "22:00", End: "06:00"}},
+			now:     saturday(3, 0),
+			attr:    deploymentAttr(admission.Update, nil),
+		},
+		{
+			name:    "window in time zone is active",
+			cluster: "root:org:ws",
+			windows: []tenancyv1alpha1.FreezeWindow{{Name: "office-hours", Start: "09:00", End: "17:00", TimeZone: "America/New_York"}},
+			now:     saturday(14, 0), // 10:00 EDT
+			attr:    deploymentAttr(admission.Update, nil),
+			wantErr: true,
+		},
+		{
+			name:    "window in time zone is not yet active",
+			cluster: "root:org:ws",
+			windows: []tenancyv1alpha1.FreezeWindow{{Name: "office-hours", Start: "09:00", End: "17:00", TimeZone: "America/New_York"}},
+			now:     saturday(12, 0), // 08:00 EDT
+			attr:    deploymentAttr(admission.Update, nil),
+		},
+		{
+			name:    "writes to other resources are admitted",
+			cluster: "root:org:ws",
+			windows: []tenancyv1alpha1.FreezeWindow{{Name: "all-day", Resources: deployments, Start: "00:00", End: "00:00"}},
+			now:     saturday(12, 0),
+			attr:    configMapAttr(),
+		},
+		{
+			name:    "writes to restricted resources are rejected",
+			cluster: "root:org:ws",
+			windows: []tenancyv1alpha1.FreezeWindow{{Name: "all-day", Resources: deployments, Start: "00:00", End: "00:00"}},
+			now:     saturday(12, 0),
+			attr:    deploymentAttr(admission.Create, nil),
+			wantErr: true,
+		},
+		{
+			name:    "override annotation is ignored for rejecting windows",
+			cluster: "root:org:ws",
+			windows: []tenancyv1alpha1.FreezeWindow{{Name: "all-day", Start: "00:00", End: "00:00", Enforcement: tenancyv1alpha1.FreezeWindowEnforcementReject}},
+			now:     saturday(12, 0),
+			attr:    deploymentAttr(admission.Update, override),
+			wantErr: true,
+		},
+		{
+			name:    "writes without override annotation are rejected",
+			cluster: "root:org:ws",
+			windows: []tenancyv1alpha1.FreezeWindow{{Name: "all-day", Start: "00:00", End: "00:00", Enforcement: tenancyv1alpha1.FreezeWindowEnforcementRequireOverride}},
+			now:     saturday(12, 0),
+			attr:    deploymentAttr(admission.Update, nil),
+			wantErr: true,
+		},
+		{
+			name:    "writes with override annotation are admitted",
+			cluster: "root:org:ws",
+			windows: []tenancyv1alpha1.FreezeWindow{{Name: "all-day", Start: "00:00", End: "00:00", Enforcement: tenancyv1alpha1.FreezeWindowEnforcementRequireOverride}},
+			now:     saturday(12, 0),
+			attr:    deploymentAttr(admission.Update, override),
+		},
+		{
+			name:    "deletions of objects with override annotation are admitted",
+			cluster: "root:org:ws",
+			windows: []tenancyv1alpha1.FreezeWindow{{Name: "all-day", Start: "00:00", End: "00:00", Enforcement: tenancyv1alpha1.FreezeWindowEnforcementRequireOverride}},
+			now:     saturday(12, 0),
+			attr:    deploymentAttr(admission.Delete, override),
+		},
+		{
+			name:    "break-glass group is exempted",
+			cluster: "root:org:ws",
+			windows: []tenancyv1alpha1.FreezeWindow{{Name: "all-day", Start: "00:00", End: "00:00"}},
+			now:     saturday(12, 0),
+			attr:    deploymentAttr(admission.Update, nil, tenancyv1alpha1.BreakGlassGroup),
+		},
+		{
+			name:    "privileged users are exempted",
+			cluster: "root:org:ws",
+			windows: []tenancyv1alpha1.FreezeWindow{{Name: "all-day", Start: "00:00", End: "00:00"}},
+			now:     saturday(12, 0),
+			attr:    deploymentAttr(admission.Update, nil, user.SystemPrivilegedGroup),
+		},
+		{
+			name:    "root is not restricted",
+			cluster: "root",
+			windows: []tenancyv1alpha1.FreezeWindow{{Name: "all-day", Start: "00:00", End: "00:00"}},
+			now:     saturday(12, 0),
+			attr:    deploymentAttr(admission.Update, nil),
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o := &freezeWindows{
+				Handler: admission.NewHandler(admission.Create, admission.Update, admission.Delete),
+				now:     func() time.Time { return tc.now },
+				getClusterWorkspace: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error) {
+					require.Equal(t, "root:org", clusterName.String())
+					require.Equal(t, "ws", name)
+					return &tenancyv1alpha1.ClusterWorkspace{
+						ObjectMeta: metav1.ObjectMeta{Name: name, ClusterName: clusterName.String()},
+						Spec:       tenancyv1alpha1.ClusterWorkspaceSpec{Type: "Prod"},
+					}, nil
+				},
+				getClusterWorkspaceType: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspaceType, error) {
+					if name != "prod" {
+						return nil, apierrors.NewNotFound(tenancyv1alpha1.Resource("clusterworkspacetypes"), name)
+					}
+					return &tenancyv1alpha1.ClusterWorkspaceType{
+						ObjectMeta: metav1.ObjectMeta{Name: name, ClusterName: clusterName.String()},
+						Spec:       tenancyv1alpha1.ClusterWorkspaceTypeSpec{FreezeWindows: tc.windows},
+					}, nil
+				},
+			}
+
+			ctx := request.WithCluster(context.Background(), request.Cluster{Name: logicalcluster.New(tc.cluster)})
+			err := o.Validate(ctx, tc.attr, nil)
+			if tc.wantErr {
+				require.Error(t, err)
+				require.True(t, apierrors.IsForbidden(err), "expected forbidden, got %v", err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestActiveUntil(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	// the night of the switch to daylight saving time, 02:00 to 03:00 does not exist
+	w := tenancyv1alpha1.FreezeWindow{Name: "nightly", Start: "22:00", End: "06:00", TimeZone: "Europe/Berlin"}
+	end, active, err := activeUntil(w, time.Date(2022, 3, 27, 4, 0, 0, 0, berlin))
+	require.NoError(t, err)
+	require.True(t, active)
+	require.True(t, end.Equal(time.Date(2022, 3, 27, 4, 0, 0, 0, time.UTC)), "unexpected end %s", end)
+
+	_, active, err = activeUntil(w, time.Date(2022, 3, 27, 6, 0, 0, 0, berlin))
+	require.NoError(t, err)
+	require.False(t, active, "end is exclusive")
+
+	_, _, err = activeUntil(tenancyv1alpha1.FreezeWindow{Name: "invalid", Start: "22:00", End: "06:00", TimeZone: "Mars/Olympus_Mons"}, time.Now())
+	require.Error(t, err)
+}
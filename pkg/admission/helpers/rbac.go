@@ -0,0 +1,109 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+)
+
+// ConfirmNoEscalation returns an error unless the authorizer allows the user every request
+// the given rules grant in the namespace, or cluster-wide if the namespace is empty. It is
+// the counterpart of the RBAC escalation check for objects that kcp turns into roles or
+// bindings with its own privileges: nobody must be able to grant more than they hold.
+//
+// Wildcards in the rules are checked literally, i.e. a rule with verb "*" is only held by
+// users who are granted verb "*" themselves.
+func ConfirmNoEscalation(ctx context.Context, authz authorizer.Authorizer, user user.Info, namespace string, rules []rbacv1.PolicyRule) error {
+	for _, rule := range rules {
+		for _, attr := range ruleAttributes(user, namespace, rule) {
+			decision, _, err := authz.Authorize(ctx, attr)
+			if err != nil {
+				return fmt.Errorf("unable to determine access for %s: %w", describeAttributes(attr), err)
+			}
+			if decision != authorizer.DecisionAllow {
+				return fmt.Errorf("missing permission %s", describeAttributes(attr))
+			}
+		}
+	}
+	return nil
+}
+
+func ruleAttributes(user user.Info, namespace string, rule rbacv1.PolicyRule) []authorizer.AttributesRecord {
+	var attrs []authorizer.AttributesRecord
+	for _, verb := range rule.Verbs {
+		for _, path := range rule.NonResourceURLs {
+			attrs = append(attrs, authorizer.AttributesRecord{
+				User:            user,
+				Verb:            verb,
+				Path:            path,
+				ResourceRequest: false,
+			})
+		}
+		for _, group := range rule.APIGroups {
+			for _, resource := range rule.Resources {
+				var subresource string
+				if parts := strings.SplitN(resource, "/", 2); len(parts) == 2 {
+					resource, subresource = parts[0], parts[1]
+				}
+				names := rule.ResourceNames
+				if len(names) == 0 {
+					names = []string{""}
+				}
+				for _, name := range names {
+					attrs = append(attrs, authorizer.AttributesRecord{
+						User:            user,
+						Verb:            verb,
+						Namespace:       namespace,
+						APIGroup:        group,
+						Resource:        resource,
+						Subresource:     subresource,
+						Name:            name,
+						ResourceRequest: true,
+					})
+				}
+			}
+		}
+	}
+	return attrs
+}
+
+func describeAttributes(attr authorizer.AttributesRecord) string {
+	if !attr.ResourceRequest {
+		return fmt.Sprintf("verb=%q nonResourceURL=%q", attr.Verb, attr.Path)
+	}
+	resource := attr.Resource
+	if attr.Subresource != "" {
+		resource += "/" + attr.Subresource
+	}
+	if attr.APIGroup != "" {
+		resource += "." + attr.APIGroup
+	}
+	s := fmt.Sprintf("verb=%q resource=%q", attr.Verb, resource)
+	if attr.Name != "" {
+		s += fmt.Sprintf(" name=%q", attr.Name)
+	}
+	if attr.Namespace != "" {
+		s += fmt.Sprintf(" namespace=%q", attr.Namespace)
+	}
+	return s
+}
@@ -17,7 +17,9 @@ limitations under the License.
 package initializers
 
 import (
+	apiextensionsinformers "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions"
 	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 
 	kcpclientset "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
@@ -44,6 +46,26 @@ func (i *kcpInformersInitializer) Initialize(plugin admission.Interface) {
 	}
 }
 
+// NewApiExtensionsInformersInitializer returns an admission plugin initializer that injects
+// apiextensions shared informer factories into admission plugins.
+func NewApiExtensionsInformersInitializer(
+	apiExtensionsInformers apiextensionsinformers.SharedInformerFactory,
+) *apiExtensionsInformersInitializer {
+	return &apiExtensionsInformersInitializer{
+		apiExtensionsInformers: apiExtensionsInformers,
+	}
+}
+
+type apiExtensionsInformersInitializer struct {
+	apiExtensionsInformers apiextensionsinformers.SharedInformerFactory
+}
+
+func (i *apiExtensionsInformersInitializer) Initialize(plugin admission.Interface) {
+	if wants, ok := plugin.(WantsApiExtensionsInformers); ok {
+		wants.SetApiExtensionsInformers(i.apiExtensionsInformers)
+	}
+}
+
 // NewKubeClusterClientInitializer returns an admission plugin initializer that injects
 // a kube cluster client into admission plugins.
 func NewKubeClusterClientInitializer(
@@ -84,6 +106,26 @@ func (i *kcpClusterClientInitializer) Initialize(plugin admission.Interface) {
 	}
 }
 
+// NewDynamicClusterClientInitializer returns an admission plugin initializer that injects
+// a dynamic cluster client into admission plugins.
+func NewDynamicClusterClientInitializer(
+	dynamicClusterClient dynamic.ClusterInterface,
+) *dynamicClusterClientInitializer {
+	return &dynamicClusterClientInitializer{
+		dynamicClusterClient: dynamicClusterClient,
+	}
+}
+
+type dynamicClusterClientInitializer struct {
+	dynamicClusterClient dynamic.ClusterInterface
+}
+
+func (i *dynamicClusterClientInitializer) Initialize(plugin admission.Interface) {
+	if wants, ok := plugin.(WantsDynamicClusterClient); ok {
+		wants.SetDynamicClusterClient(i.dynamicClusterClient)
+	}
+}
+
 // NewExternalAddressInitializer returns an admission plugin initializer that injects
 // an external address provider into the admission plugin.
 func NewExternalAddressInitializer(
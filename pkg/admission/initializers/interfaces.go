@@ -17,6 +17,8 @@ limitations under the License.
 package initializers
 
 import (
+	apiextensionsinformers "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 
 	kcpclientset "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
@@ -29,6 +31,12 @@ type WantsKcpInformers interface {
 	SetKcpInformers(informers kcpinformers.SharedInformerFactory)
 }
 
+// WantsApiExtensionsInformers interface should be implemented by admission plugins
+// that want to have an apiextensions informer factory injected.
+type WantsApiExtensionsInformers interface {
+	SetApiExtensionsInformers(informers apiextensionsinformers.SharedInformerFactory)
+}
+
 // WantsKubeClusterClient interface should be implemented by admission plugins
 // that want to have a kube cluster client injected.
 type WantsKubeClusterClient interface {
@@ -41,6 +49,12 @@ type WantsKcpClusterClient interface {
 	SetKcpClusterClient(kubeClusterClient *kcpclientset.Cluster)
 }
 
+// WantsDynamicClusterClient interface should be implemented by admission plugins
+// that want to have a dynamic cluster client injected.
+type WantsDynamicClusterClient interface {
+	SetDynamicClusterClient(dynamicClusterClient dynamic.ClusterInterface)
+}
+
 // WantsExternalAddressProvider interface should be implemented by admission plugins
 // that want to have an external address provider injected.
 type WantsExternalAddressProvider interface {
@@ -0,0 +1,307 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package objecttransfer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/authorization/delegated"
+	objecttransferreconciler "github.com/kcp-dev/kcp/pkg/reconciler/tenancy/objecttransfer"
+)
+
+const (
+	PluginName = "tenancy.kcp.dev/ObjectTransfer"
+
+	// SourceAuditAnnotation is the audit annotation of the creation of an ObjectTransfer
+	// with the transferred object, in the format <cluster>|<resource>/[<namespace>/]<name>.
+	SourceAuditAnnotation = "tenancy.kcp.dev/object-transfer-source"
+	// DestinationAuditAnnotation is the audit annotation of the creation of an ObjectTransfer
+	// with the destination workspace.
+	DestinationAuditAnnotation = "tenancy.kcp.dev/object-transfer-destination"
+	// TransferredFromAuditAnnotation is the audit annotation of the creation of a transferred
+	// object in the destination workspace, with the logical cluster and UID of its source
+	// in the format <cluster>|<uid>.
+	TransferredFromAuditAnnotation = "tenancy.kcp.dev/transferred-from"
+)
+
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName,
+		func(_ io.Reader) (admission.Interface, error) {
+			return &objectTransferAdmission{
+				Handler:          admission.NewHandler(admission.Create, admission.Update),
+				createAuthorizer: delegated.NewDelegatedAuthorizer,
+			}, nil
+		})
+}
+
+// objectTransferAdmission checks that the creator of an ObjectTransfer is allowed to get and
+// delete the object in the source workspace and to create it in the destination workspace, as
+// kcp executes the transfer with its own privileges. For namespaces, this applies to every
+// resource whose objects are transferred with the namespace. It also validates the spec, and keeps it
+// immutable. Both the transfer and the creation of the transferred objects are recorded in
+// the audit log.
+type objectTransferAdmission struct {
+	*admission.Handler
+	kubeClusterClient *kubernetes.Cluster
+
+	createAuthorizer  delegated.DelegatedAuthorizerFactory
+	discoverResources func(clusterName logicalcluster.Name) ([]*metav1.APIResourceList, error)
+}
+
+// Ensure that the required admission interfaces are implemented.
+var _ = admission.ValidationInterface(&objectTransferAdmission{})
+var _ = admission.InitializationValidator(&objectTransferAdmission{})
+
+func (o *objectTransferAdmission) Validate(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
+	if a.GetResource().GroupResource() != tenancyv1alpha1.Resource("objecttransfers") {
+		if a.GetOperation() == admission.Create && a.GetSubresource() == "" {
+			return auditTransferredObject(a)
+		}
+		return nil
+	}
+	if a.GetSubresource() != "" {
+		return nil
+	}
+
+	transfer, err := toObjectTransfer(a.GetObject())
+	if err != nil {
+		return err
+	}
+
+	if a.GetOperation() == admission.Update {
+		old, err := toObjectTransfer(a.GetOldObject())
+		if err != nil {
+			return err
+		}
+		if !equality.Semantic.DeepEqual(transfer.Spec, old.Spec) {
+			return admission.NewForbidden(a, field.Invalid(field.NewPath("spec"), "", "field is immutable"))
+		}
+		return nil
+	}
+
+	cluster, err := genericapirequest.ValidClusterFrom(ctx)
+	if err != nil {
+		return admission.NewForbidden(a, fmt.Errorf("error determining workspace: %w", err))
+	}
+	if errs := validateSpec(&transfer.Spec, cluster.Name, field.NewPath("spec")); len(errs) > 0 {
+		return admission.NewForbidden(a, errs.ToAggregate())
+	}
+	if err := o.checkAccess(ctx, a, cluster.Name, transfer); err != nil {
+		return admission.NewForbidden(a, fmt.Errorf("unable to transfer %s: %w", objectDescription(&transfer.Spec), err))
+	}
+
+	if err := a.AddAnnotation(SourceAuditAnnotation, cluster.Name.String()+"|"+objectDescription(&transfer.Spec)); err != nil {
+		return apierrors.NewInternalError(fmt.Errorf("failed to add audit annotation %q: %w", SourceAuditAnnotation, err))
+	}
+	if err := a.AddAnnotation(DestinationAuditAnnotation, transfer.Spec.Destination); err != nil {
+		return apierrors.NewInternalError(fmt.Errorf("failed to add audit annotation %q: %w", DestinationAuditAnnotation, err))
+	}
+
+	return nil
+}
+
+func validateSpec(spec *tenancyv1alpha1.ObjectTransferSpec, clusterName logicalcluster.Name, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	if spec.Destination == clusterName.String() {
+		errs = append(errs, field.Invalid(fldPath.Child("destination"), spec.Destination, "must be different from the source workspace"))
+	}
+	if isNamespace(spec) && spec.Namespace != "" {
+		errs = append(errs, field.Forbidden(fldPath.Child("namespace"), "not valid for namespaces"))
+	}
+
+	return errs
+}
+
+// auditTransferredObject records the source of objects created by an ObjectTransfer in the audit log.
+func auditTransferredObject(a admission.Attributes) error {
+	obj, err := meta.Accessor(a.GetObject())
+	if err != nil {
+		return nil // not an object with metadata
+	}
+	lineage, found := obj.GetAnnotations()[tenancyv1alpha1.ObjectTransferUIDLineageAnnotation]
+	if !found || lineage == "" {
+		return nil
+	}
+	entries := strings.Split(lineage, ",")
+	if err := a.AddAnnotation(TransferredFromAuditAnnotation, entries[len(entries)-1]); err != nil {
+		return apierrors.NewInternalError(fmt.Errorf("failed to add audit annotation %q: %w", TransferredFromAuditAnnotation, err))
+	}
+	return nil
+}
+
+func toObjectTransfer(obj runtime.Object) (*tenancyv1alpha1.ObjectTransfer, error) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("unexpected type %T", obj)
+	}
+	transfer := &tenancyv1alpha1.ObjectTransfer{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, transfer); err != nil {
+		return nil, fmt.Errorf("failed to convert unstructured to ObjectTransfer: %w", err)
+	}
+	return transfer, nil
+}
+
+// checkAccess checks that the user can get and delete the object in the source workspace, and
+// create it in the destination workspace. The objects in a namespace are transferred with it,
+// so the same is checked in the namespace for every resource transferred with it.
+func (o *objectTransferAdmission) checkAccess(ctx context.Context, a admission.Attributes, clusterName logicalcluster.Name, transfer *tenancyv1alpha1.ObjectTransfer) error {
+	destination := logicalcluster.New(transfer.Spec.Destination)
+	sourceAuthz, err := o.authorizer(clusterName)
+	if err != nil {
+		return err
+	}
+	destinationAuthz, err := o.authorizer(destination)
+	if err != nil {
+		return err
+	}
+
+	authorize := func(authz authorizer.Authorizer, gvr schema.GroupVersionResource, verb, namespace, name string) (bool, error) {
+		attr := authorizer.AttributesRecord{
+			User:            a.GetUserInfo(),
+			Verb:            verb,
+			APIGroup:        gvr.Group,
+			APIVersion:      gvr.Version,
+			Resource:        gvr.Resource,
+			Namespace:       namespace,
+			Name:            name,
+			ResourceRequest: true,
+		}
+		decision, _, err := authz.Authorize(ctx, attr)
+		if err != nil {
+			return false, fmt.Errorf("unable to determine access to %s: %w", gvr.GroupResource(), err)
+		}
+		return decision == authorizer.DecisionAllow, nil
+	}
+
+	// checkResource checks the source for the named object, or for all objects of the resource if name is empty.
+	checkResource := func(gvr schema.GroupVersionResource, namespace, name string) error {
+		for _, verb := range []string{"get", "delete"} {
+			if allowed, err := authorize(sourceAuthz, gvr, verb, namespace, name); err != nil {
+				return err
+			} else if !allowed {
+				if name == "" {
+					return fmt.Errorf("missing verb='%s' permission on %s in namespace %q", verb, gvr.GroupResource(), namespace)
+				}
+				return fmt.Errorf("missing verb='%s' permission on %s %q", verb, gvr.GroupResource(), name)
+			}
+		}
+		if allowed, err := authorize(destinationAuthz, gvr, "create", namespace, ""); err != nil {
+			return err
+		} else if !allowed {
+			return fmt.Errorf("missing verb='create' permission on %s in workspace %q", gvr.GroupResource(), destination)
+		}
+		return nil
+	}
+
+	gvr := schema.GroupVersionResource{
+		Group:    transfer.Spec.Resource.Group,
+		Version:  transfer.Spec.Resource.Version,
+		Resource: transfer.Spec.Resource.Resource,
+	}
+	if err := checkResource(gvr, transfer.Spec.Namespace, transfer.Spec.Name); err != nil {
+		return err
+	}
+	if !isNamespace(&transfer.Spec) {
+		return nil
+	}
+
+	resourceLists, err := o.discoverResources(clusterName)
+	if err != nil {
+		// Logging a more specific error for the operator
+		klog.Errorf("error discovering the resources of workspace %s: %v", clusterName, err)
+		// Returning a less specific error to the end user
+		return errors.New("unable to determine the resources of the namespace")
+	}
+	gvrs, err := objecttransferreconciler.NamespacedResources(resourceLists)
+	if err != nil {
+		return err
+	}
+	for _, gvr := range gvrs {
+		if err := checkResource(gvr, transfer.Spec.Name, ""); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (o *objectTransferAdmission) authorizer(clusterName logicalcluster.Name) (authorizer.Authorizer, error) {
+	authz, err := o.createAuthorizer(clusterName, o.kubeClusterClient)
+	if err != nil {
+		// Logging a more specific error for the operator
+		klog.Errorf("error creating authorizer from delegating authorizer config: %v", err)
+		// Returning a less specific error to the end user
+		return nil, errors.New("unable to authorize request")
+	}
+	return authz, nil
+}
+
+// ValidateInitialization ensures the required injected fields are set.
+func (o *objectTransferAdmission) ValidateInitialization() error {
+	if o.kubeClusterClient == nil {
+		return fmt.Errorf(PluginName + " plugin needs a Kubernetes ClusterInterface")
+	}
+
+	return nil
+}
+
+// SetKubeClusterClient is an admission plugin initializer function that injects a Kubernetes cluster client into
+// this admission plugin.
+func (o *objectTransferAdmission) SetKubeClusterClient(clusterClient *kubernetes.Cluster) {
+	o.kubeClusterClient = clusterClient
+	o.discoverResources = func(clusterName logicalcluster.Name) ([]*metav1.APIResourceList, error) {
+		return clusterClient.Cluster(clusterName).Discovery().ServerPreferredNamespacedResources()
+	}
+}
+
+func isNamespace(spec *tenancyv1alpha1.ObjectTransferSpec) bool {
+	return spec.Resource.Group == "" && spec.Resource.Resource == "namespaces"
+}
+
+// objectDescription returns the transferred object as <resource>/[<namespace>/]<name>.
+func objectDescription(spec *tenancyv1alpha1.ObjectTransferSpec) string {
+	resource := spec.Resource.Resource
+	if spec.Resource.Group != "" {
+		resource += "." + spec.Resource.Group
+	}
+	if spec.Namespace == "" {
+		return resource + "/" + spec.Name
+	}
+	return resource + "/" + spec.Namespace + "/" + spec.Name
+}
@@ -0,0 +1,318 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package objecttransfer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/admission"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kcp-dev/kcp/pkg/admission/helpers"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+func transferAttr(op admission.Operation, transfer, old *tenancyv1alpha1.ObjectTransfer) admission.Attributes {
+	var obj, oldObj runtime.Object
+	if transfer != nil {
+		obj = helpers.ToUnstructuredOrDie(transfer)
+	}
+	if old != nil {
+		oldObj = helpers.ToUnstructuredOrDie(old)
+	}
+	return admission.NewAttributesRecord(
+		obj,
+		oldObj,
+		tenancyv1alpha1.Kind("ObjectTransfer").WithVersion("v1alpha1"),
+		"",
+		"move",
+		tenancyv1alpha1.Resource("objecttransfers").WithVersion("v1alpha1"),
+		"",
+		op,
+		&metav1.CreateOptions{},
+		false,
+		&user.DefaultInfo{},
+	)
+}
+
+func newTransfer(mutate func(*tenancyv1alpha1.ObjectTransferSpec)) *tenancyv1alpha1.ObjectTransfer {
+	transfer := &tenancyv1alpha1.ObjectTransfer{
+		ObjectMeta: metav1.ObjectMeta{Name: "move"},
+		Spec: tenancyv1alpha1.ObjectTransferSpec{
+			Resource:    tenancyv1alpha1.ReplicationResource{Version: "v1", Resource: "configmaps"},
+			Namespace:   "default",
+			Name:        "settings",
+			Destination: "root:org:team",
+		},
+	}
+	if mutate != nil {
+		mutate(&transfer.Spec)
+	}
+	return transfer
+}
+
+func TestValidate(t *testing.T) {
+	namespace := func(spec *tenancyv1alpha1.ObjectTransferSpec) {
+		spec.Resource.Resource = "namespaces"
+		spec.Namespace = ""
+		spec.Name = "web"
+	}
+
+	tests := []struct {
+		name            string
+		attr            admission.Attributes
+		allowed         func(clusterName logicalcluster.Name, attr authorizer.Attributes) bool
+		authzError      error
+		discoveryError  error
+		expectedErrors  []string
+		expectedChecks  []string
+		expectedAudited map[string]string
+	}{
+		{
+			name:           "Create: passes with permission to delete the source and to create in the destination",
+			attr:           transferAttr(admission.Create, newTransfer(nil), nil),
+			allowed:        func(logicalcluster.Name, authorizer.Attributes) bool { return true },
+			expectedChecks: []string{"root:org get configmaps default/settings", "root:org delete configmaps default/settings", "root:org:team create configmaps default"},
+			expectedAudited: map[string]string{
+				SourceAuditAnnotation:      "root:org|configmaps/default/settings",
+				DestinationAuditAnnotation: "root:org:team",
+			},
+		},
+		{
+			name:    "Create: namespaces are transferred as a whole",
+			attr:    transferAttr(admission.Create, newTransfer(namespace), nil),
+			allowed: func(logicalcluster.Name, authorizer.Attributes) bool { return true },
+			expectedChecks: []string{
+				"root:org get namespaces web", "root:org delete namespaces web", "root:org:team create namespaces",
+				"root:org get configmaps web", "root:org delete configmaps web", "root:org:team create configmaps web",
+				"root:org get secrets web", "root:org delete secrets web", "root:org:team create secrets web",
+				"root:org get rolebindings web", "root:org delete rolebindings web", "root:org:team create rolebindings web",
+			},
+			expectedAudited: map[string]string{
+				SourceAuditAnnotation:      "root:org|namespaces/web",
+				DestinationAuditAnnotation: "root:org:team",
+			},
+		},
+		{
+			name: "Create: fails without permission to get the source",
+			attr: transferAttr(admission.Create, newTransfer(nil), nil),
+			allowed: func(clusterName logicalcluster.Name, attr authorizer.Attributes) bool {
+				return clusterName == logicalcluster.New("root:org:team") || attr.GetVerb() == "delete"
+			},
+			expectedErrors: []string{`unable to transfer configmaps/default/settings: missing verb='get' permission on configmaps "settings"`},
+			expectedChecks: []string{"root:org get configmaps default/settings"},
+		},
+		{
+			name: "Create: fails without permission to delete the source",
+			attr: transferAttr(admission.Create, newTransfer(nil), nil),
+			allowed: func(clusterName logicalcluster.Name, attr authorizer.Attributes) bool {
+				return clusterName == logicalcluster.New("root:org:team") || attr.GetVerb() == "get"
+			},
+			expectedErrors: []string{`unable to transfer configmaps/default/settings: missing verb='delete' permission on configmaps "settings"`},
+			expectedChecks: []string{"root:org get configmaps default/settings", "root:org delete configmaps default/settings"},
+		},
+		{
+			name: "Create: fails for namespaces with secrets which cannot be read",
+			attr: transferAttr(admission.Create, newTransfer(namespace), nil),
+			allowed: func(clusterName logicalcluster.Name, attr authorizer.Attributes) bool {
+				return attr.GetResource() != "secrets" || attr.GetVerb() != "get"
+			},
+			expectedErrors: []string{`unable to transfer namespaces/web: missing verb='get' permission on secrets in namespace "web"`},
+			expectedChecks: []string{
+				"root:org get namespaces web", "root:org delete namespaces web", "root:org:team create namespaces",
+				"root:org get configmaps web", "root:org delete configmaps web", "root:org:team create configmaps web",
+				"root:org get secrets web",
+			},
+		},
+		{
+			name: "Create: fails for namespaces with rolebindings which cannot be created in the destination",
+			attr: transferAttr(admission.Create, newTransfer(namespace), nil),
+			allowed: func(clusterName logicalcluster.Name, attr authorizer.Attributes) bool {
+				return attr.GetResource() != "rolebindings" || clusterName == logicalcluster.New("root:org")
+			},
+			expectedErrors: []string{`missing verb='create' permission on rolebindings.rbac.authorization.k8s.io in workspace "root:org:team"`},
+			expectedChecks: []string{
+				"root:org get namespaces web", "root:org delete namespaces web", "root:org:team create namespaces",
+				"root:org get configmaps web", "root:org delete configmaps web", "root:org:team create configmaps web",
+				"root:org get secrets web", "root:org delete secrets web", "root:org:team create secrets web",
+				"root:org get rolebindings web", "root:org delete rolebindings web", "root:org:team create rolebindings web",
+			},
+		},
+		{
+			name:           "Create: fails for namespaces when discovery fails",
+			attr:           transferAttr(admission.Create, newTransfer(namespace), nil),
+			allowed:        func(logicalcluster.Name, authorizer.Attributes) bool { return true },
+			discoveryError: errors.New("discovery failed"),
+			expectedErrors: []string{"unable to determine the resources of the namespace"},
+			expectedChecks: []string{"root:org get namespaces web", "root:org delete namespaces web", "root:org:team create namespaces"},
+		},
+		{
+			name: "Create: fails without permission to create in the destination",
+			attr: transferAttr(admission.Create, newTransfer(nil), nil),
+			allowed: func(clusterName logicalcluster.Name, _ authorizer.Attributes) bool {
+				return clusterName == logicalcluster.New("root:org")
+			},
+			expectedErrors: []string{`missing verb='create' permission on configmaps in workspace "root:org:team"`},
+			expectedChecks: []string{"root:org get configmaps default/settings", "root:org delete configmaps default/settings", "root:org:team create configmaps default"},
+		},
+		{
+			name:           "Create: fails when there's an error checking authorization",
+			attr:           transferAttr(admission.Create, newTransfer(nil), nil),
+			authzError:     errors.New("some error here"),
+			expectedErrors: []string{"unable to determine access to configmaps: some error here"},
+			expectedChecks: []string{"root:org get configmaps default/settings"},
+		},
+		{
+			name: "Create: fails with invalid fields",
+			attr: transferAttr(admission.Create, newTransfer(func(spec *tenancyv1alpha1.ObjectTransferSpec) {
+				namespace(spec)
+				spec.Namespace = "default"
+				spec.Destination = "root:org"
+			}), nil),
+			expectedErrors: []string{
+				`spec.destination: Invalid value: "root:org": must be different from the source workspace`,
+				"spec.namespace: Forbidden",
+			},
+		},
+		{
+			name: "Update: status changes pass",
+			attr: transferAttr(admission.Update, newTransfer(nil), newTransfer(nil)),
+		},
+		{
+			name:           "Update: spec is immutable",
+			attr:           transferAttr(admission.Update, newTransfer(func(spec *tenancyv1alpha1.ObjectTransferSpec) { spec.Destination = "root:org:other" }), newTransfer(nil)),
+			expectedErrors: []string{"field is immutable"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var checks []string
+			o := &objectTransferAdmission{
+				Handler: admission.NewHandler(admission.Create, admission.Update),
+				createAuthorizer: func(clusterName logicalcluster.Name, client kubernetes.ClusterInterface) (authorizer.Authorizer, error) {
+					return authorizer.AuthorizerFunc(func(ctx context.Context, attr authorizer.Attributes) (authorizer.Decision, string, error) {
+						check := clusterName.String() + " " + attr.GetVerb() + " " + attr.GetResource()
+						if attr.GetNamespace() != "" && attr.GetName() != "" {
+							check += " " + attr.GetNamespace() + "/" + attr.GetName()
+						} else if attr.GetNamespace() != "" {
+							check += " " + attr.GetNamespace()
+						} else if attr.GetName() != "" {
+							check += " " + attr.GetName()
+						}
+						checks = append(checks, check)
+						if tc.authzError != nil {
+							return authorizer.DecisionNoOpinion, "", tc.authzError
+						}
+						if tc.allowed != nil && tc.allowed(clusterName, attr) {
+							return authorizer.DecisionAllow, "", nil
+						}
+						return authorizer.DecisionNoOpinion, "", nil
+					}), nil
+				},
+				discoverResources: func(clusterName logicalcluster.Name) ([]*metav1.APIResourceList, error) {
+					if tc.discoveryError != nil {
+						return nil, tc.discoveryError
+					}
+					verbs := metav1.Verbs{"get", "list", "create", "delete"}
+					return []*metav1.APIResourceList{
+						{GroupVersion: "v1", APIResources: []metav1.APIResource{
+							{Name: "configmaps", Namespaced: true, Verbs: verbs},
+							{Name: "events", Namespaced: true, Verbs: verbs},
+							{Name: "secrets", Namespaced: true, Verbs: verbs},
+							{Name: "pods/log", Namespaced: true, Verbs: metav1.Verbs{"get"}},
+						}},
+						{GroupVersion: "rbac.authorization.k8s.io/v1", APIResources: []metav1.APIResource{
+							{Name: "rolebindings", Namespaced: true, Verbs: verbs},
+						}},
+					}, nil
+				},
+			}
+
+			ctx := request.WithCluster(context.Background(), request.Cluster{Name: logicalcluster.New("root:org")})
+
+			err := o.Validate(ctx, tc.attr, nil)
+
+			wantErr := len(tc.expectedErrors) > 0
+			require.Equal(t, wantErr, err != nil, "unexpected error: %v", err)
+			for _, expected := range tc.expectedErrors {
+				require.Contains(t, err.Error(), expected)
+			}
+			require.Equal(t, tc.expectedChecks, checks)
+			if tc.expectedAudited != nil {
+				require.Equal(t, tc.expectedAudited, tc.attr.(admission.AnnotationsGetter).GetAnnotations(auditinternal.LevelMetadata))
+			}
+		})
+	}
+}
+
+func TestValidateTransferredObject(t *testing.T) {
+	tests := map[string]struct {
+		annotations map[string]string
+		want        map[string]string
+	}{
+		"objects without lineage are not annotated": {},
+		"the last source of the lineage is audited": {
+			annotations: map[string]string{tenancyv1alpha1.ObjectTransferUIDLineageAnnotation: "root:org:a|uid-1,root:org:b|uid-2"},
+			want:        map[string]string{TransferredFromAuditAnnotation: "root:org:b|uid-2"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			cm := &unstructured.Unstructured{Object: map[string]interface{}{"apiVersion": "v1", "kind": "ConfigMap"}}
+			cm.SetName("settings")
+			cm.SetNamespace("default")
+			cm.SetAnnotations(tc.annotations)
+			attr := admission.NewAttributesRecord(
+				cm,
+				nil,
+				schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"},
+				"default",
+				"settings",
+				schema.GroupVersionResource{Version: "v1", Resource: "configmaps"},
+				"",
+				admission.Create,
+				&metav1.CreateOptions{},
+				false,
+				&user.DefaultInfo{},
+			)
+
+			o := &objectTransferAdmission{Handler: admission.NewHandler(admission.Create, admission.Update)}
+			require.NoError(t, o.Validate(context.Background(), attr, nil))
+
+			got := attr.(admission.AnnotationsGetter).GetAnnotations(auditinternal.LevelMetadata)
+			if tc.want == nil {
+				require.Empty(t, got)
+			} else {
+				require.Equal(t, tc.want, got)
+			}
+		})
+	}
+}
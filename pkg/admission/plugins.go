@@ -17,6 +17,9 @@ limitations under the License.
 package admission
 
 import (
+	"fmt"
+	"sort"
+
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apiserver/pkg/admission"
 	"k8s.io/apiserver/pkg/admission/plugin/namespace/lifecycle"
@@ -39,17 +42,38 @@ import (
 	"k8s.io/kubernetes/plugin/pkg/admission/storage/storageclass/setdefault"
 	"k8s.io/kubernetes/plugin/pkg/admission/storage/storageobjectinuseprotection"
 
+	"github.com/kcp-dev/kcp/pkg/admission/accessgrant"
 	"github.com/kcp-dev/kcp/pkg/admission/apibinding"
+	"github.com/kcp-dev/kcp/pkg/admission/apiexportdefaults"
+	"github.com/kcp-dev/kcp/pkg/admission/apiexportfinalizers"
 	"github.com/kcp-dev/kcp/pkg/admission/apiresourceschema"
+	"github.com/kcp-dev/kcp/pkg/admission/bulkworkspaceoperation"
 	"github.com/kcp-dev/kcp/pkg/admission/clusterworkspace"
 	"github.com/kcp-dev/kcp/pkg/admission/clusterworkspaceshard"
 	"github.com/kcp-dev/kcp/pkg/admission/clusterworkspacetype"
 	"github.com/kcp-dev/kcp/pkg/admission/clusterworkspacetypeexists"
+	"github.com/kcp-dev/kcp/pkg/admission/crdbindingconflicts"
+	"github.com/kcp-dev/kcp/pkg/admission/dnsrecord"
+	"github.com/kcp-dev/kcp/pkg/admission/freezewindows"
 	kcpmutatingwebhook "github.com/kcp-dev/kcp/pkg/admission/mutatingwebhook"
 	workspacenamespacelifecycle "github.com/kcp-dev/kcp/pkg/admission/namespacelifecycle"
+	"github.com/kcp-dev/kcp/pkg/admission/objecttransfer"
+	"github.com/kcp-dev/kcp/pkg/admission/policybundle"
+	"github.com/kcp-dev/kcp/pkg/admission/referentialintegrity"
+	"github.com/kcp-dev/kcp/pkg/admission/replication"
 	"github.com/kcp-dev/kcp/pkg/admission/reservedcrdannotations"
 	"github.com/kcp-dev/kcp/pkg/admission/reservedcrdgroups"
+	"github.com/kcp-dev/kcp/pkg/admission/secretclaim"
+	"github.com/kcp-dev/kcp/pkg/admission/systemworkspaceprotection"
+	"github.com/kcp-dev/kcp/pkg/admission/ttlpolicy"
 	kcpvalidatingwebhook "github.com/kcp-dev/kcp/pkg/admission/validatingwebhook"
+	"github.com/kcp-dev/kcp/pkg/admission/workloadprovenance"
+	"github.com/kcp-dev/kcp/pkg/admission/workspacefreeze"
+	"github.com/kcp-dev/kcp/pkg/admission/workspaceimagepolicy"
+	"github.com/kcp-dev/kcp/pkg/admission/workspacelabelpropagation"
+	"github.com/kcp-dev/kcp/pkg/admission/workspacelimits"
+	"github.com/kcp-dev/kcp/pkg/admission/workspacenames"
+	"github.com/kcp-dev/kcp/pkg/admission/workspacenamespaces"
 )
 
 // AllOrderedPlugins is the list of all the plugins in order.
@@ -57,14 +81,35 @@ var AllOrderedPlugins = beforeWebhooks(kubeapiserveroptions.AllOrderedPlugins,
 	workspacenamespacelifecycle.PluginName,
 	apiresourceschema.PluginName,
 	clusterworkspace.PluginName,
+	workspacenames.PluginName,
 	clusterworkspaceshard.PluginName,
 	clusterworkspacetype.PluginName,
 	clusterworkspacetypeexists.PluginName,
 	apibinding.PluginName,
+	apiexportdefaults.PluginName,
+	apiexportfinalizers.PluginName,
+	secretclaim.PluginName,
+	dnsrecord.PluginName,
+	replication.PluginName,
+	ttlpolicy.PluginName,
+	accessgrant.PluginName,
+	policybundle.PluginName,
+	bulkworkspaceoperation.PluginName,
+	objecttransfer.PluginName,
+	referentialintegrity.PluginName,
 	kcpvalidatingwebhook.PluginName,
 	kcpmutatingwebhook.PluginName,
 	reservedcrdannotations.PluginName,
 	reservedcrdgroups.PluginName,
+	crdbindingconflicts.PluginName,
+	workspacelimits.PluginName,
+	workspacenamespaces.PluginName,
+	workspacelabelpropagation.PluginName,
+	workspaceimagepolicy.PluginName,
+	workloadprovenance.PluginName,
+	workspacefreeze.PluginName,
+	freezewindows.PluginName,
+	systemworkspaceprotection.PluginName,
 )
 
 func beforeWebhooks(recommended []string, plugins ...string) []string {
@@ -78,21 +123,73 @@ func beforeWebhooks(recommended []string, plugins ...string) []string {
 	return ret
 }
 
+// Reorder returns the ordered plugins with those listed in order moved into the given
+// relative order. The listed plugins take the positions they have among each other in
+// plugins, all other plugins keep their position.
+func Reorder(plugins []string, order []string) ([]string, error) {
+	index := make(map[string]int, len(plugins))
+	for i, name := range plugins {
+		index[name] = i
+	}
+
+	listed := sets.NewString()
+	positions := make([]int, 0, len(order))
+	for _, name := range order {
+		i, found := index[name]
+		if !found {
+			return nil, fmt.Errorf("unknown admission plugin %q", name)
+		}
+		if listed.Has(name) {
+			return nil, fmt.Errorf("admission plugin %q is listed twice", name)
+		}
+		listed.Insert(name)
+		positions = append(positions, i)
+	}
+	sort.Ints(positions)
+
+	ret := append([]string(nil), plugins...)
+	for i, name := range order {
+		ret[positions[i]] = name
+	}
+	return ret, nil
+}
+
 // RegisterAllKcpAdmissionPlugins registers all admission plugins.
 // The order of registration is irrelevant, see AllOrderedPlugins for execution order.
 func RegisterAllKcpAdmissionPlugins(plugins *admission.Plugins) {
 	kubeapiserveroptions.RegisterAllAdmissionPlugins(plugins)
 	clusterworkspace.Register(plugins)
+	workspacenames.Register(plugins)
 	clusterworkspaceshard.Register(plugins)
 	clusterworkspacetype.Register(plugins)
 	clusterworkspacetypeexists.Register(plugins)
 	apiresourceschema.Register(plugins)
 	apibinding.Register(plugins)
+	apiexportdefaults.Register(plugins)
+	apiexportfinalizers.Register(plugins)
+	secretclaim.Register(plugins)
+	dnsrecord.Register(plugins)
+	replication.Register(plugins)
+	ttlpolicy.Register(plugins)
+	accessgrant.Register(plugins)
+	policybundle.Register(plugins)
+	bulkworkspaceoperation.Register(plugins)
+	objecttransfer.Register(plugins)
+	referentialintegrity.Register(plugins)
 	workspacenamespacelifecycle.Register(plugins)
 	kcpvalidatingwebhook.Register(plugins)
 	kcpmutatingwebhook.Register(plugins)
 	reservedcrdannotations.Register(plugins)
 	reservedcrdgroups.Register(plugins)
+	crdbindingconflicts.Register(plugins)
+	workspacelimits.Register(plugins)
+	workspacenamespaces.Register(plugins)
+	workspacelabelpropagation.Register(plugins)
+	workspaceimagepolicy.Register(plugins)
+	workloadprovenance.Register(plugins)
+	workspacefreeze.Register(plugins)
+	freezewindows.Register(plugins)
+	systemworkspaceprotection.Register(plugins)
 }
 
 var defaultOnPluginsInKcp = sets.NewString(
@@ -104,15 +201,36 @@ var defaultOnPluginsInKcp = sets.NewString(
 
 	// KCP
 	clusterworkspace.PluginName,
+	workspacenames.PluginName,
 	clusterworkspaceshard.PluginName,
 	clusterworkspacetype.PluginName,
 	clusterworkspacetypeexists.PluginName,
 	apiresourceschema.PluginName,
 	apibinding.PluginName,
+	apiexportdefaults.PluginName,
+	apiexportfinalizers.PluginName,
+	secretclaim.PluginName,
+	dnsrecord.PluginName,
+	replication.PluginName,
+	ttlpolicy.PluginName,
+	accessgrant.PluginName,
+	policybundle.PluginName,
+	bulkworkspaceoperation.PluginName,
+	objecttransfer.PluginName,
+	referentialintegrity.PluginName,
 	kcpvalidatingwebhook.PluginName,
 	kcpmutatingwebhook.PluginName,
 	reservedcrdannotations.PluginName,
 	reservedcrdgroups.PluginName,
+	crdbindingconflicts.PluginName,
+	workspacelimits.PluginName,
+	workspacenamespaces.PluginName,
+	workspacelabelpropagation.PluginName,
+	workspaceimagepolicy.PluginName,
+	workloadprovenance.PluginName,
+	workspacefreeze.PluginName,
+	freezewindows.PluginName,
+	systemworkspaceprotection.PluginName,
 )
 
 // defaultOnKubePluginsInKube is a copy of kubeapiserveroptions.defaultOnKubePlugins.
@@ -19,6 +19,8 @@ package admission
 import (
 	"testing"
 
+	"github.com/stretchr/testify/require"
+
 	"k8s.io/apimachinery/pkg/util/sets"
 	kubeapiserveroptions "k8s.io/kubernetes/pkg/kubeapiserver/options"
 )
@@ -34,3 +36,33 @@ func TestPluginDrift(t *testing.T) {
 		t.Errorf("Default-on plugins got removed in kube. Remove in defaultOnKubePluginsInKube, and decide whether to remove from defaultOnPluginsInKcp: %v", goneInKube.List())
 	}
 }
+
+func TestReorder(t *testing.T) {
+	plugins := []string{"a", "b", "c", "d", "e"}
+
+	tests := []struct {
+		name    string
+		order   []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "no order", want: plugins},
+		{name: "swap", order: []string{"d", "b"}, want: []string{"a", "d", "c", "b", "e"}},
+		{name: "rotate", order: []string{"e", "a", "c"}, want: []string{"e", "b", "a", "d", "c"}},
+		{name: "single plugin keeps its position", order: []string{"c"}, want: plugins},
+		{name: "unknown plugin", order: []string{"a", "x"}, wantErr: true},
+		{name: "duplicate plugin", order: []string{"a", "a"}, wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Reorder(plugins, tc.order)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+	require.Equal(t, []string{"a", "b", "c", "d", "e"}, plugins, "input must not be modified")
+}
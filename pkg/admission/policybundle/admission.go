@@ -0,0 +1,264 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policybundle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/genericcontrolplane"
+
+	"github.com/kcp-dev/kcp/pkg/admission/helpers"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/authorization/delegated"
+)
+
+const (
+	PluginName = "tenancy.kcp.dev/PolicyBundle"
+)
+
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName,
+		func(_ io.Reader) (admission.Interface, error) {
+			return &policyBundleAdmission{
+				Handler:          admission.NewHandler(admission.Create, admission.Update),
+				createAuthorizer: delegated.NewDelegatedAuthorizer,
+			}, nil
+		})
+}
+
+// policyBundleAdmission prevents privilege escalation through PolicyBundles, whose ClusterRoles
+// and ClusterRoleBindings are created by kcp with its own privileges in all workspaces below the
+// bundle. Like for RBAC objects, the user must have the verb `escalate` on a bundled ClusterRole,
+// or hold all of its rules, and the verb `bind` on the ClusterRole of a bundled binding, or hold
+// all of its rules, in the workspace of the bundle. On update, only new and changed roles and
+// bindings are checked.
+type policyBundleAdmission struct {
+	*admission.Handler
+	kubeClusterClient *kubernetes.Cluster
+
+	createAuthorizer delegated.DelegatedAuthorizerFactory
+	getClusterRole   func(ctx context.Context, clusterName logicalcluster.Name, name string) (*rbacv1.ClusterRole, error)
+}
+
+// Ensure that the required admission interfaces are implemented.
+var _ = admission.ValidationInterface(&policyBundleAdmission{})
+var _ = admission.InitializationValidator(&policyBundleAdmission{})
+
+func (o *policyBundleAdmission) Validate(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
+	if a.GetResource().GroupResource() != tenancyv1alpha1.Resource("policybundles") {
+		return nil
+	}
+	if a.GetSubresource() != "" {
+		return nil
+	}
+
+	bundle, err := toPolicyBundle(a.GetObject())
+	if err != nil {
+		return err
+	}
+	var old *tenancyv1alpha1.PolicyBundle
+	if a.GetOperation() == admission.Update {
+		if old, err = toPolicyBundle(a.GetOldObject()); err != nil {
+			return err
+		}
+	}
+
+	roles, bindings := changedRBAC(bundle, old)
+	if len(roles) == 0 && len(bindings) == 0 {
+		return nil
+	}
+
+	cluster, err := genericapirequest.ValidClusterFrom(ctx)
+	if err != nil {
+		return admission.NewForbidden(a, fmt.Errorf("error determining workspace: %w", err))
+	}
+
+	authz, err := o.createAuthorizer(cluster.Name, o.kubeClusterClient)
+	if err != nil {
+		// Logging a more specific error for the operator
+		klog.Errorf("error creating authorizer from delegating authorizer config: %v", err)
+		// Returning a less specific error to the end user
+		return admission.NewForbidden(a, errors.New("unable to authorize request"))
+	}
+
+	bundledRoles := make(map[string][]rbacv1.PolicyRule, len(bundle.Spec.ClusterRoles))
+	for _, role := range bundle.Spec.ClusterRoles {
+		bundledRoles[role.Name] = role.Rules
+	}
+
+	var errs []error
+	for _, role := range roles {
+		if err := o.checkRole(ctx, authz, a.GetUserInfo(), role); err != nil {
+			errs = append(errs, field.Forbidden(field.NewPath("spec", "clusterRoles").Key(role.Name), err.Error()))
+		}
+	}
+	for _, binding := range bindings {
+		if err := o.checkBinding(ctx, authz, a.GetUserInfo(), cluster.Name, binding, bundledRoles); err != nil {
+			errs = append(errs, field.Forbidden(field.NewPath("spec", "clusterRoleBindings").Key(binding.Name), err.Error()))
+		}
+	}
+	if len(errs) > 0 {
+		return admission.NewForbidden(a, utilerrors.NewAggregate(errs))
+	}
+
+	return nil
+}
+
+func toPolicyBundle(obj runtime.Object) (*tenancyv1alpha1.PolicyBundle, error) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("unexpected type %T", obj)
+	}
+	bundle := &tenancyv1alpha1.PolicyBundle{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, bundle); err != nil {
+		return nil, fmt.Errorf("failed to convert unstructured to PolicyBundle: %w", err)
+	}
+	return bundle, nil
+}
+
+// changedRBAC returns the ClusterRoles and ClusterRoleBindings of the bundle which are not in
+// the old bundle with the same content. All of them are returned if old is nil.
+func changedRBAC(bundle, old *tenancyv1alpha1.PolicyBundle) ([]tenancyv1alpha1.PolicyBundleClusterRole, []tenancyv1alpha1.PolicyBundleClusterRoleBinding) {
+	oldRoles := map[string]tenancyv1alpha1.PolicyBundleClusterRole{}
+	oldBindings := map[string]tenancyv1alpha1.PolicyBundleClusterRoleBinding{}
+	if old != nil {
+		for _, role := range old.Spec.ClusterRoles {
+			oldRoles[role.Name] = role
+		}
+		for _, binding := range old.Spec.ClusterRoleBindings {
+			oldBindings[binding.Name] = binding
+		}
+	}
+
+	var roles []tenancyv1alpha1.PolicyBundleClusterRole
+	for _, role := range bundle.Spec.ClusterRoles {
+		if oldRole, found := oldRoles[role.Name]; !found || !equality.Semantic.DeepEqual(role, oldRole) {
+			roles = append(roles, role)
+		}
+	}
+	var bindings []tenancyv1alpha1.PolicyBundleClusterRoleBinding
+	for _, binding := range bundle.Spec.ClusterRoleBindings {
+		if oldBinding, found := oldBindings[binding.Name]; !found || !equality.Semantic.DeepEqual(binding, oldBinding) {
+			bindings = append(bindings, binding)
+		}
+	}
+	return roles, bindings
+}
+
+// checkRole mirrors the RBAC escalation check of ClusterRoles: the user must have the verb
+// `escalate` on the role, or already hold all of its rules.
+func (o *policyBundleAdmission) checkRole(ctx context.Context, authz authorizer.Authorizer, user user.Info, role tenancyv1alpha1.PolicyBundleClusterRole) error {
+	escalateAttr := authorizer.AttributesRecord{
+		User:            user,
+		Verb:            "escalate",
+		APIGroup:        rbacv1.GroupName,
+		APIVersion:      rbacv1.SchemeGroupVersion.Version,
+		Resource:        "clusterroles",
+		Name:            role.Name,
+		ResourceRequest: true,
+	}
+	if decision, _, err := authz.Authorize(ctx, escalateAttr); err != nil {
+		return fmt.Errorf("unable to determine access to clusterroles: %w", err)
+	} else if decision == authorizer.DecisionAllow {
+		return nil
+	}
+
+	if err := helpers.ConfirmNoEscalation(ctx, authz, user, "", role.Rules); err != nil {
+		return fmt.Errorf("missing verb='escalate' permission on clusterroles, and not holding all of its rules: %w", err)
+	}
+	return nil
+}
+
+// checkBinding mirrors the RBAC escalation check of ClusterRoleBindings: the user must have
+// the verb `bind` on the bound role, or already hold all of its rules. The rules of bundled
+// roles are taken from the bundle, those of other roles from the workspace of the bundle.
+func (o *policyBundleAdmission) checkBinding(ctx context.Context, authz authorizer.Authorizer, user user.Info, clusterName logicalcluster.Name, binding tenancyv1alpha1.PolicyBundleClusterRoleBinding, bundledRoles map[string][]rbacv1.PolicyRule) error {
+	roleRef := binding.RoleRef
+	if roleRef.APIGroup != rbacv1.GroupName || roleRef.Kind != "ClusterRole" {
+		return fmt.Errorf("roleRef must reference a ClusterRole of API group %q", rbacv1.GroupName)
+	}
+
+	bindAttr := authorizer.AttributesRecord{
+		User:            user,
+		Verb:            "bind",
+		APIGroup:        rbacv1.GroupName,
+		APIVersion:      rbacv1.SchemeGroupVersion.Version,
+		Resource:        "clusterroles",
+		Name:            roleRef.Name,
+		ResourceRequest: true,
+	}
+	if decision, _, err := authz.Authorize(ctx, bindAttr); err != nil {
+		return fmt.Errorf("unable to determine access to clusterroles: %w", err)
+	} else if decision == authorizer.DecisionAllow {
+		return nil
+	}
+
+	rules, found := bundledRoles[roleRef.Name]
+	if !found {
+		role, err := o.getClusterRole(ctx, clusterName, roleRef.Name)
+		if apierrors.IsNotFound(err) {
+			// ClusterRoles of the bootstrap policy are shared by all workspaces
+			role, err = o.getClusterRole(ctx, genericcontrolplane.LocalAdminCluster, roleRef.Name)
+		}
+		if err != nil {
+			return fmt.Errorf("missing verb='bind' permission on clusterroles, and unable to get its rules: %w", err)
+		}
+		rules = role.Rules
+	}
+
+	if err := helpers.ConfirmNoEscalation(ctx, authz, user, "", rules); err != nil {
+		return fmt.Errorf("missing verb='bind' permission on clusterroles, and not holding all of its rules: %w", err)
+	}
+	return nil
+}
+
+// ValidateInitialization ensures the required injected fields are set.
+func (o *policyBundleAdmission) ValidateInitialization() error {
+	if o.kubeClusterClient == nil {
+		return fmt.Errorf(PluginName + " plugin needs a Kubernetes ClusterInterface")
+	}
+
+	return nil
+}
+
+// SetKubeClusterClient is an admission plugin initializer function that injects a Kubernetes cluster client into
+// this admission plugin.
+func (o *policyBundleAdmission) SetKubeClusterClient(clusterClient *kubernetes.Cluster) {
+	o.kubeClusterClient = clusterClient
+	o.getClusterRole = func(ctx context.Context, clusterName logicalcluster.Name, name string) (*rbacv1.ClusterRole, error) {
+		return clusterClient.Cluster(clusterName).RbacV1().ClusterRoles().Get(ctx, name, metav1.GetOptions{})
+	}
+}
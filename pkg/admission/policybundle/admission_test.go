@@ -0,0 +1,255 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policybundle
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kcp-dev/kcp/pkg/admission/helpers"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+func policyBundleAttr(op admission.Operation, bundle, old *tenancyv1alpha1.PolicyBundle) admission.Attributes {
+	var obj, oldObj runtime.Object
+	if bundle != nil {
+		obj = helpers.ToUnstructuredOrDie(bundle)
+	}
+	if old != nil {
+		oldObj = helpers.ToUnstructuredOrDie(old)
+	}
+	return admission.NewAttributesRecord(
+		obj,
+		oldObj,
+		tenancyv1alpha1.Kind("PolicyBundle").WithVersion("v1alpha1"),
+		"",
+		"baseline",
+		tenancyv1alpha1.Resource("policybundles").WithVersion("v1alpha1"),
+		"",
+		op,
+		&metav1.CreateOptions{},
+		false,
+		&user.DefaultInfo{Name: "alice"},
+	)
+}
+
+type bundleOption func(*tenancyv1alpha1.PolicyBundle)
+
+func withClusterRole(name string, rules ...rbacv1.PolicyRule) bundleOption {
+	return func(bundle *tenancyv1alpha1.PolicyBundle) {
+		bundle.Spec.ClusterRoles = append(bundle.Spec.ClusterRoles, tenancyv1alpha1.PolicyBundleClusterRole{Name: name, Rules: rules})
+	}
+}
+
+func withClusterRoleBinding(name, role string, subjects ...string) bundleOption {
+	return func(bundle *tenancyv1alpha1.PolicyBundle) {
+		binding := tenancyv1alpha1.PolicyBundleClusterRoleBinding{
+			Name:    name,
+			RoleRef: rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: role},
+		}
+		for _, s := range subjects {
+			binding.Subjects = append(binding.Subjects, rbacv1.Subject{Kind: rbacv1.GroupKind, APIGroup: rbacv1.GroupName, Name: s})
+		}
+		bundle.Spec.ClusterRoleBindings = append(bundle.Spec.ClusterRoleBindings, binding)
+	}
+}
+
+func newPolicyBundle(opts ...bundleOption) *tenancyv1alpha1.PolicyBundle {
+	bundle := &tenancyv1alpha1.PolicyBundle{
+		ObjectMeta: metav1.ObjectMeta{Name: "baseline"},
+		Spec: tenancyv1alpha1.PolicyBundleSpec{
+			Enforcement: tenancyv1alpha1.PolicyBundleEnforce,
+			AdmissionPolicies: []tenancyv1alpha1.PolicyBundleAdmissionPolicy{
+				{Name: "no-workspace-deletion"},
+			},
+		},
+	}
+	for _, opt := range opts {
+		opt(bundle)
+	}
+	return bundle
+}
+
+func TestValidate(t *testing.T) {
+	viewRule := rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"configmaps"}, Verbs: []string{"get", "list"}}
+	adminRule := rbacv1.PolicyRule{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}}
+
+	clusterRoles := map[string]map[string]*rbacv1.ClusterRole{
+		"root:org": {
+			"view": {ObjectMeta: metav1.ObjectMeta{Name: "view"}, Rules: []rbacv1.PolicyRule{viewRule}},
+		},
+		"system:admin": {
+			"cluster-admin": {ObjectMeta: metav1.ObjectMeta{Name: "cluster-admin"}, Rules: []rbacv1.PolicyRule{adminRule}},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		attr           admission.Attributes
+		allowed        []string
+		authzError     error
+		expectedErrors []string
+	}{
+		{
+			name: "Create: passes without roles and bindings",
+			attr: policyBundleAttr(admission.Create, newPolicyBundle(), nil),
+		},
+		{
+			name:    "Create: passes with escalate on the ClusterRole",
+			attr:    policyBundleAttr(admission.Create, newPolicyBundle(withClusterRole("org-admin", adminRule)), nil),
+			allowed: []string{"escalate clusterroles org-admin"},
+		},
+		{
+			name:    "Create: passes when holding all rules of the ClusterRole",
+			attr:    policyBundleAttr(admission.Create, newPolicyBundle(withClusterRole("org-viewer", viewRule)), nil),
+			allowed: []string{"get configmaps ", "list configmaps "},
+		},
+		{
+			name:           "Create: fails when not holding all rules of the ClusterRole",
+			attr:           policyBundleAttr(admission.Create, newPolicyBundle(withClusterRole("org-admin", adminRule)), nil),
+			allowed:        []string{"get configmaps ", "list configmaps "},
+			expectedErrors: []string{`spec.clusterRoles[org-admin]: Forbidden: missing verb='escalate' permission on clusterroles, and not holding all of its rules: missing permission verb="*" resource="*.*"`},
+		},
+		{
+			name:    "Create: passes with bind on the ClusterRole of a binding",
+			attr:    policyBundleAttr(admission.Create, newPolicyBundle(withClusterRoleBinding("admins", "cluster-admin", "mallory")), nil),
+			allowed: []string{"bind clusterroles cluster-admin"},
+		},
+		{
+			name:    "Create: passes when holding all rules of the ClusterRole of a binding",
+			attr:    policyBundleAttr(admission.Create, newPolicyBundle(withClusterRoleBinding("viewers", "view", "org-viewers")), nil),
+			allowed: []string{"get configmaps ", "list configmaps "},
+		},
+		{
+			name:           "Create: fails when binding cluster-admin without bind",
+			attr:           policyBundleAttr(admission.Create, newPolicyBundle(withClusterRoleBinding("admins", "cluster-admin", "mallory")), nil),
+			allowed:        []string{"get configmaps ", "list configmaps "},
+			expectedErrors: []string{`spec.clusterRoleBindings[admins]: Forbidden: missing verb='bind' permission on clusterroles, and not holding all of its rules: missing permission verb="*" resource="*.*"`},
+		},
+		{
+			name:           "Create: fails when binding a bundled ClusterRole with escalate on it only",
+			attr:           policyBundleAttr(admission.Create, newPolicyBundle(withClusterRole("org-admin", adminRule), withClusterRoleBinding("admins", "org-admin", "mallory")), nil),
+			allowed:        []string{"escalate clusterroles org-admin"},
+			expectedErrors: []string{`spec.clusterRoleBindings[admins]: Forbidden: missing verb='bind' permission on clusterroles, and not holding all of its rules`},
+		},
+		{
+			name:    "Create: passes when binding a bundled ClusterRole with escalate and bind on it",
+			attr:    policyBundleAttr(admission.Create, newPolicyBundle(withClusterRole("org-admin", adminRule), withClusterRoleBinding("admins", "org-admin", "mallory")), nil),
+			allowed: []string{"escalate clusterroles org-admin", "bind clusterroles org-admin"},
+		},
+		{
+			name:           "Create: fails for an unknown ClusterRole without bind",
+			attr:           policyBundleAttr(admission.Create, newPolicyBundle(withClusterRoleBinding("unknowns", "unknown", "mallory")), nil),
+			expectedErrors: []string{"missing verb='bind' permission on clusterroles, and unable to get its rules"},
+		},
+		{
+			name:           "Create: fails when there's an error checking authorization",
+			attr:           policyBundleAttr(admission.Create, newPolicyBundle(withClusterRole("org-viewer", viewRule)), nil),
+			authzError:     errors.New("some error here"),
+			expectedErrors: []string{"unable to determine access to clusterroles: some error here"},
+		},
+		{
+			name: "Update: passes without changes of roles and bindings",
+			attr: policyBundleAttr(admission.Update,
+				newPolicyBundle(withClusterRole("org-admin", adminRule), withClusterRoleBinding("admins", "cluster-admin", "mallory")),
+				newPolicyBundle(withClusterRole("org-admin", adminRule), withClusterRoleBinding("admins", "cluster-admin", "mallory"))),
+		},
+		{
+			name: "Update: fails when adding a ClusterRole without holding its rules",
+			attr: policyBundleAttr(admission.Update,
+				newPolicyBundle(withClusterRole("org-viewer", viewRule), withClusterRole("org-admin", adminRule)),
+				newPolicyBundle(withClusterRole("org-viewer", viewRule))),
+			expectedErrors: []string{"spec.clusterRoles[org-admin]: Forbidden"},
+		},
+		{
+			name: "Update: fails when changing the rules of a ClusterRole without holding them",
+			attr: policyBundleAttr(admission.Update,
+				newPolicyBundle(withClusterRole("org-viewer", viewRule, adminRule)),
+				newPolicyBundle(withClusterRole("org-viewer", viewRule))),
+			allowed:        []string{"get configmaps ", "list configmaps "},
+			expectedErrors: []string{"spec.clusterRoles[org-viewer]: Forbidden"},
+		},
+		{
+			name: "Update: fails when adding subjects to a binding without bind",
+			attr: policyBundleAttr(admission.Update,
+				newPolicyBundle(withClusterRoleBinding("admins", "cluster-admin", "admins", "mallory")),
+				newPolicyBundle(withClusterRoleBinding("admins", "cluster-admin", "admins"))),
+			expectedErrors: []string{"spec.clusterRoleBindings[admins]: Forbidden"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o := &policyBundleAdmission{
+				Handler: admission.NewHandler(admission.Create, admission.Update),
+				createAuthorizer: func(clusterName logicalcluster.Name, client kubernetes.ClusterInterface) (authorizer.Authorizer, error) {
+					require.Equal(t, "root:org", clusterName.String())
+					return &fakeAuthorizer{allowed: tc.allowed, err: tc.authzError}, nil
+				},
+				getClusterRole: func(ctx context.Context, clusterName logicalcluster.Name, name string) (*rbacv1.ClusterRole, error) {
+					if role, ok := clusterRoles[clusterName.String()][name]; ok {
+						return role, nil
+					}
+					return nil, apierrors.NewNotFound(rbacv1.Resource("clusterroles"), name)
+				},
+			}
+
+			ctx := request.WithCluster(context.Background(), request.Cluster{Name: logicalcluster.New("root:org")})
+
+			err := o.Validate(ctx, tc.attr, nil)
+
+			wantErr := len(tc.expectedErrors) > 0
+			require.Equal(t, wantErr, err != nil, "unexpected error: %v", err)
+			for _, expected := range tc.expectedErrors {
+				require.Contains(t, err.Error(), expected)
+			}
+		})
+	}
+}
+
+// fakeAuthorizer allows the cluster-wide requests listed as "<verb> <resource> <name>".
+type fakeAuthorizer struct {
+	allowed []string
+	err     error
+}
+
+func (a *fakeAuthorizer) Authorize(ctx context.Context, attr authorizer.Attributes) (authorized authorizer.Decision, reason string, err error) {
+	if a.err != nil {
+		return authorizer.DecisionNoOpinion, "", a.err
+	}
+	key := attr.GetVerb() + " " + attr.GetResource() + " " + attr.GetName()
+	for _, allowed := range a.allowed {
+		if allowed == key {
+			return authorizer.DecisionAllow, "", nil
+		}
+	}
+	return authorizer.DecisionNoOpinion, "", nil
+}
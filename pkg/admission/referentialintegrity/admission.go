@@ -0,0 +1,308 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package referentialintegrity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clusters"
+	"k8s.io/klog/v2"
+
+	kcpinitializers "github.com/kcp-dev/kcp/pkg/admission/initializers"
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/authorization/delegated"
+	kcpinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
+	"github.com/kcp-dev/kcp/pkg/references"
+)
+
+const (
+	PluginName = "apis.kcp.dev/ReferentialIntegrity"
+
+	byWorkspaceIndex = "referentialIntegrity-byWorkspace"
+)
+
+var reClusterName = regexp.MustCompile(`^([a-z]([a-z0-9-]{0,61}[a-z0-9])?:)*[a-z]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName,
+		func(_ io.Reader) (admission.Interface, error) {
+			return &referentialIntegrity{
+				Handler:          admission.NewHandler(admission.Create, admission.Update),
+				createAuthorizer: delegated.NewDelegatedAuthorizer,
+			}, nil
+		})
+}
+
+// referentialIntegrity resolves the references marked with x-kcp-reference in the
+// APIResourceSchemas of bound resources. On creation, and for references changed on
+// update, the referenced object must exist, and the user must be allowed the declared
+// verb on it. The latter keeps users from probing for objects they cannot see.
+type referentialIntegrity struct {
+	*admission.Handler
+	kubeClusterClient *kubernetes.Cluster
+
+	createAuthorizer delegated.DelegatedAuthorizerFactory
+
+	listAPIBindings      func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error)
+	getAPIResourceSchema func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error)
+	getObject            func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, namespace, name string) error
+}
+
+// Ensure that the required admission interfaces are implemented.
+var _ = admission.ValidationInterface(&referentialIntegrity{})
+var _ = admission.InitializationValidator(&referentialIntegrity{})
+var _ = kcpinitializers.WantsKcpInformers(&referentialIntegrity{})
+var _ = kcpinitializers.WantsKubeClusterClient(&referentialIntegrity{})
+var _ = kcpinitializers.WantsDynamicClusterClient(&referentialIntegrity{})
+
+// Validate rejects objects of bound resources with references to objects that do not
+// exist, or that the user has no access to.
+func (o *referentialIntegrity) Validate(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
+	if a.GetSubresource() != "" {
+		return nil
+	}
+	u, ok := a.GetObject().(*unstructured.Unstructured)
+	if !ok {
+		return nil // bound resources are always unstructured
+	}
+
+	clusterName, err := genericapirequest.ClusterNameFrom(ctx)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+
+	if !o.WaitForReady() {
+		return admission.NewForbidden(a, fmt.Errorf("not yet ready to handle request"))
+	}
+
+	refs, err := o.references(clusterName, a.GetResource())
+	if err != nil {
+		return admission.NewForbidden(a, err)
+	}
+	if len(refs) == 0 {
+		return nil
+	}
+
+	var old *unstructured.Unstructured
+	if a.GetOperation() == admission.Update {
+		old, _ = a.GetOldObject().(*unstructured.Unstructured)
+	}
+
+	var errs field.ErrorList
+	for i := range refs {
+		ref := &refs[i]
+
+		// unchanged references are not resolved again, such that objects stay
+		// updatable when a referenced object goes away.
+		existing := sets.NewString()
+		if old != nil {
+			for _, v := range ref.Values(old.Object) {
+				existing.Insert(v.Workspace + "|" + v.Name)
+			}
+		}
+
+		for _, v := range ref.Values(u.Object) {
+			if existing.Has(v.Workspace + "|" + v.Name) {
+				continue
+			}
+			if err := o.resolve(ctx, a, clusterName, &ref.Target, v); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return admission.NewForbidden(a, errs.ToAggregate())
+	}
+
+	return nil
+}
+
+// references returns the references declared in the APIResourceSchema bound for the
+// given resource in the given workspace, or nil if the resource is not bound.
+func (o *referentialIntegrity) references(clusterName logicalcluster.Name, gvr schema.GroupVersionResource) ([]references.Reference, error) {
+	parent, hasParent := clusterName.Parent()
+	if !hasParent {
+		return nil, nil // APIBindings in root are not possible
+	}
+
+	bindings, err := o.listAPIBindings(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	for _, binding := range bindings {
+		if binding.Status.BoundAPIExport == nil || binding.Status.BoundAPIExport.Workspace == nil {
+			continue
+		}
+		for _, br := range binding.Status.BoundResources {
+			if br.Group != gvr.Group || br.Resource != gvr.Resource {
+				continue
+			}
+			exportClusterName := parent.Join(binding.Status.BoundAPIExport.Workspace.WorkspaceName)
+			apiResourceSchema, err := o.getAPIResourceSchema(exportClusterName, br.Schema.Name)
+			if apierrors.IsNotFound(err) {
+				klog.V(3).Infof("APIResourceSchema %s|%s bound by APIBinding %s|%s not found", exportClusterName, br.Schema.Name, clusterName, binding.Name)
+				return nil, nil
+			} else if err != nil {
+				return nil, err
+			}
+			for _, version := range apiResourceSchema.Spec.Versions {
+				if version.Name == gvr.Version {
+					return references.Extract(version.Schema.Raw)
+				}
+			}
+			return nil, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// resolve checks that the user is allowed the verb of the target on the referenced
+// object, and that it exists.
+func (o *referentialIntegrity) resolve(ctx context.Context, a admission.Attributes, clusterName logicalcluster.Name, target *references.Target, v references.Value) *field.Error {
+	if v.Workspace != "" {
+		if !reClusterName.MatchString(v.Workspace) {
+			return field.Invalid(v.Path, v.Name, fmt.Sprintf("invalid workspace %q of the reference", v.Workspace))
+		}
+		clusterName = logicalcluster.New(v.Workspace)
+	}
+	var namespace string
+	if target.Namespaced {
+		if a.GetNamespace() == "" {
+			return field.Invalid(v.Path, v.Name, "namespaced references cannot be resolved for cluster scoped objects")
+		}
+		namespace = a.GetNamespace()
+	}
+	gvr := schema.GroupVersionResource{Group: target.Group, Version: target.Version, Resource: target.Resource}
+	resource := gvr.GroupResource().String()
+
+	verb := target.Verb
+	if verb == "" {
+		verb = "get"
+	}
+	authz, err := o.createAuthorizer(clusterName, o.kubeClusterClient)
+	if err != nil {
+		// Logging a more specific error for the operator
+		klog.Errorf("error creating authorizer from delegating authorizer config: %v", err)
+		// Returning a less specific error to the end user
+		return field.InternalError(v.Path, errors.New("unable to authorize request"))
+	}
+	decision, _, err := authz.Authorize(ctx, authorizer.AttributesRecord{
+		User:            a.GetUserInfo(),
+		Verb:            verb,
+		APIGroup:        target.Group,
+		APIVersion:      target.Version,
+		Resource:        target.Resource,
+		Namespace:       namespace,
+		Name:            v.Name,
+		ResourceRequest: true,
+	})
+	if err != nil {
+		return field.InternalError(v.Path, fmt.Errorf("unable to determine access to %s: %w", resource, err))
+	}
+	if decision != authorizer.DecisionAllow {
+		return field.Forbidden(v.Path, fmt.Sprintf("missing verb=%q permission on %s %q in workspace %q", verb, resource, v.Name, clusterName))
+	}
+
+	if err := o.getObject(ctx, clusterName, gvr, namespace, v.Name); apierrors.IsNotFound(err) {
+		return field.Invalid(v.Path, v.Name, fmt.Sprintf("%s %q not found in workspace %q", resource, v.Name, clusterName))
+	} else if err != nil {
+		return field.InternalError(v.Path, fmt.Errorf("unable to resolve %s %q: %w", resource, v.Name, err))
+	}
+
+	return nil
+}
+
+// ValidateInitialization ensures the required injected fields are set.
+func (o *referentialIntegrity) ValidateInitialization() error {
+	if o.kubeClusterClient == nil {
+		return fmt.Errorf(PluginName + " plugin needs a Kubernetes ClusterInterface")
+	}
+	if o.listAPIBindings == nil {
+		return fmt.Errorf(PluginName + " plugin needs kcp informers")
+	}
+	if o.getObject == nil {
+		return fmt.Errorf(PluginName + " plugin needs a dynamic cluster client")
+	}
+	return nil
+}
+
+// SetKubeClusterClient is an admission plugin initializer function that injects a Kubernetes cluster client into
+// this admission plugin.
+func (o *referentialIntegrity) SetKubeClusterClient(clusterClient *kubernetes.Cluster) {
+	o.kubeClusterClient = clusterClient
+}
+
+func (o *referentialIntegrity) SetKcpInformers(informers kcpinformers.SharedInformerFactory) {
+	apiBindingInformer := informers.Apis().V1alpha1().APIBindings()
+	apiResourceSchemaInformer := informers.Apis().V1alpha1().APIResourceSchemas()
+	o.SetReadyFunc(func() bool {
+		return apiBindingInformer.Informer().HasSynced() && apiResourceSchemaInformer.Informer().HasSynced()
+	})
+
+	// every write to any resource looks up the bindings of its workspace.
+	if _, found := apiBindingInformer.Informer().GetIndexer().GetIndexers()[byWorkspaceIndex]; !found {
+		if err := apiBindingInformer.Informer().AddIndexers(cache.Indexers{
+			byWorkspaceIndex: func(obj interface{}) ([]string, error) {
+				return []string{logicalcluster.From(obj.(metav1.Object)).String()}, nil
+			},
+		}); err != nil {
+			// nothing we can do here. But this should also never happen. We check for existence before.
+			klog.Errorf("failed to add indexer for APIBindings: %v", err)
+		}
+	}
+	apiBindingIndexer := apiBindingInformer.Informer().GetIndexer()
+	o.listAPIBindings = func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
+		objs, err := apiBindingIndexer.ByIndex(byWorkspaceIndex, clusterName.String())
+		if err != nil {
+			return nil, err
+		}
+		ret := make([]*apisv1alpha1.APIBinding, 0, len(objs))
+		for _, obj := range objs {
+			ret = append(ret, obj.(*apisv1alpha1.APIBinding))
+		}
+		return ret, nil
+	}
+	o.getAPIResourceSchema = func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error) {
+		return apiResourceSchemaInformer.Lister().Get(clusters.ToClusterAwareKey(clusterName, name))
+	}
+}
+
+func (o *referentialIntegrity) SetDynamicClusterClient(dynamicClusterClient dynamic.ClusterInterface) {
+	o.getObject = func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, namespace, name string) error {
+		_, err := dynamicClusterClient.Cluster(clusterName).Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+		return err
+	}
+}
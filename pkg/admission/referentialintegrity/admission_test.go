@@ -0,0 +1,240 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package referentialintegrity
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/kubernetes"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+)
+
+const widgetSchema = `{"type":"object","properties":{"spec":{"type":"object","properties":{
+	"locationRef":{"type":"string","x-kcp-reference":{"group":"scheduling.kcp.dev","version":"v1alpha1","resource":"locations"}},
+	"secretRef":{"type":"string","x-kcp-reference":{"version":"v1","resource":"secrets","namespaced":true}},
+	"exportRef":{"type":"object","properties":{
+		"path":{"type":"string"},
+		"name":{"type":"string","x-kcp-reference":{"group":"apis.kcp.dev","version":"v1alpha1","resource":"apiexports","workspaceProperty":"path","verb":"bind"}}
+	}}
+}}}}`
+
+var widgetsGVR = schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+
+func widget(spec map[string]interface{}) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "example.com/v1",
+		"kind":       "Widget",
+		"spec":       spec,
+	}}
+	u.SetName("w")
+	u.SetNamespace("default")
+	return u
+}
+
+func widgetAttr(op admission.Operation, gvr schema.GroupVersionResource, obj, old *unstructured.Unstructured) admission.Attributes {
+	var oldObj runtime.Object
+	if old != nil {
+		oldObj = old
+	}
+	return admission.NewAttributesRecord(
+		obj,
+		oldObj,
+		schema.GroupVersionKind{Group: gvr.Group, Version: gvr.Version, Kind: "Widget"},
+		"default",
+		"w",
+		gvr,
+		"",
+		op,
+		&metav1.CreateOptions{},
+		false,
+		&user.DefaultInfo{Name: "alice"},
+	)
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name           string
+		attr           admission.Attributes
+		allowed        func(clusterName logicalcluster.Name, attr authorizer.Attributes) bool
+		authzError     error
+		existing       []string
+		expectedErrors []string
+		expectedChecks []string
+		expectedGets   []string
+	}{
+		{
+			name:           "Create: resources that are not bound are not checked",
+			attr:           widgetAttr(admission.Create, schema.GroupVersionResource{Group: "other.com", Version: "v1", Resource: "widgets"}, widget(map[string]interface{}{"locationRef": "us-east"}), nil),
+			expectedChecks: nil,
+		},
+		{
+			name:           "Create: passes when the referenced objects exist and are accessible",
+			attr:           widgetAttr(admission.Create, widgetsGVR, widget(map[string]interface{}{"locationRef": "us-east", "secretRef": "creds"}), nil),
+			allowed:        func(logicalcluster.Name, authorizer.Attributes) bool { return true },
+			existing:       []string{"root:org:consumer locations.scheduling.kcp.dev us-east", "root:org:consumer secrets default/creds"},
+			expectedChecks: []string{"root:org:consumer get locations us-east", "root:org:consumer get secrets default/creds"},
+			expectedGets:   []string{"root:org:consumer locations.scheduling.kcp.dev us-east", "root:org:consumer secrets default/creds"},
+		},
+		{
+			name:           "Create: fails when the referenced object does not exist",
+			attr:           widgetAttr(admission.Create, widgetsGVR, widget(map[string]interface{}{"locationRef": "us-west"}), nil),
+			allowed:        func(logicalcluster.Name, authorizer.Attributes) bool { return true },
+			expectedErrors: []string{`spec.locationRef: Invalid value: "us-west": locations.scheduling.kcp.dev "us-west" not found in workspace "root:org:consumer"`},
+			expectedChecks: []string{"root:org:consumer get locations us-west"},
+			expectedGets:   []string{"root:org:consumer locations.scheduling.kcp.dev us-west"},
+		},
+		{
+			name:           "Create: fails without access, without looking up the referenced object",
+			attr:           widgetAttr(admission.Create, widgetsGVR, widget(map[string]interface{}{"locationRef": "us-east"}), nil),
+			existing:       []string{"root:org:consumer locations.scheduling.kcp.dev us-east"},
+			expectedErrors: []string{`spec.locationRef: Forbidden: missing verb="get" permission on locations.scheduling.kcp.dev "us-east" in workspace "root:org:consumer"`},
+			expectedChecks: []string{"root:org:consumer get locations us-east"},
+		},
+		{
+			name:           "Create: fails when there's an error checking authorization",
+			attr:           widgetAttr(admission.Create, widgetsGVR, widget(map[string]interface{}{"locationRef": "us-east"}), nil),
+			authzError:     errors.New("some error here"),
+			expectedErrors: []string{"unable to determine access to locations.scheduling.kcp.dev: some error here"},
+			expectedChecks: []string{"root:org:consumer get locations us-east"},
+		},
+		{
+			name: "Create: references are resolved in designated workspaces with the declared verb",
+			attr: widgetAttr(admission.Create, widgetsGVR, widget(map[string]interface{}{"exportRef": map[string]interface{}{"path": "root:org:catalog", "name": "kubernetes"}}), nil),
+			allowed: func(clusterName logicalcluster.Name, attr authorizer.Attributes) bool {
+				return clusterName == logicalcluster.New("root:org:catalog") && attr.GetVerb() == "bind"
+			},
+			existing:       []string{"root:org:catalog apiexports.apis.kcp.dev kubernetes"},
+			expectedChecks: []string{"root:org:catalog bind apiexports kubernetes"},
+			expectedGets:   []string{"root:org:catalog apiexports.apis.kcp.dev kubernetes"},
+		},
+		{
+			name:           "Create: fails with an invalid designated workspace",
+			attr:           widgetAttr(admission.Create, widgetsGVR, widget(map[string]interface{}{"exportRef": map[string]interface{}{"path": "Root", "name": "kubernetes"}}), nil),
+			expectedErrors: []string{`spec.exportRef.name: Invalid value: "kubernetes": invalid workspace "Root" of the reference`},
+		},
+		{
+			name: "Update: unchanged references are not resolved again",
+			attr: widgetAttr(admission.Update, widgetsGVR,
+				widget(map[string]interface{}{"locationRef": "gone", "secretRef": "new"}),
+				widget(map[string]interface{}{"locationRef": "gone", "secretRef": "old"}),
+			),
+			allowed:        func(logicalcluster.Name, authorizer.Attributes) bool { return true },
+			existing:       []string{"root:org:consumer secrets default/new"},
+			expectedChecks: []string{"root:org:consumer get secrets default/new"},
+			expectedGets:   []string{"root:org:consumer secrets default/new"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var checks, gets []string
+			o := &referentialIntegrity{
+				Handler: admission.NewHandler(admission.Create, admission.Update),
+				createAuthorizer: func(clusterName logicalcluster.Name, client kubernetes.ClusterInterface) (authorizer.Authorizer, error) {
+					return authorizer.AuthorizerFunc(func(ctx context.Context, attr authorizer.Attributes) (authorizer.Decision, string, error) {
+						check := clusterName.String() + " " + attr.GetVerb() + " " + attr.GetResource() + " "
+						if attr.GetNamespace() != "" {
+							check += attr.GetNamespace() + "/"
+						}
+						checks = append(checks, check+attr.GetName())
+						if tc.authzError != nil {
+							return authorizer.DecisionNoOpinion, "", tc.authzError
+						}
+						if tc.allowed != nil && tc.allowed(clusterName, attr) {
+							return authorizer.DecisionAllow, "", nil
+						}
+						return authorizer.DecisionNoOpinion, "", nil
+					}), nil
+				},
+				listAPIBindings: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
+					if clusterName != logicalcluster.New("root:org:consumer") {
+						return nil, nil
+					}
+					return []*apisv1alpha1.APIBinding{{
+						ObjectMeta: metav1.ObjectMeta{Name: "widgets"},
+						Status: apisv1alpha1.APIBindingStatus{
+							BoundAPIExport: &apisv1alpha1.ExportReference{
+								Workspace: &apisv1alpha1.WorkspaceExportReference{WorkspaceName: "provider", ExportName: "widgets"},
+							},
+							BoundResources: []apisv1alpha1.BoundAPIResource{{
+								Group:    "example.com",
+								Resource: "widgets",
+								Schema:   apisv1alpha1.BoundAPIResourceSchema{Name: "today.widgets.example.com"},
+							}},
+						},
+					}}, nil
+				},
+				getAPIResourceSchema: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error) {
+					if clusterName != logicalcluster.New("root:org:provider") || name != "today.widgets.example.com" {
+						return nil, apierrors.NewNotFound(apisv1alpha1.Resource("apiresourceschemas"), name)
+					}
+					return &apisv1alpha1.APIResourceSchema{
+						ObjectMeta: metav1.ObjectMeta{Name: name},
+						Spec: apisv1alpha1.APIResourceSchemaSpec{
+							Group: "example.com",
+							Versions: []apisv1alpha1.APIResourceVersion{{
+								Name:   "v1",
+								Schema: runtime.RawExtension{Raw: []byte(widgetSchema)},
+							}},
+						},
+					}, nil
+				},
+				getObject: func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, namespace, name string) error {
+					get := clusterName.String() + " " + gvr.GroupResource().String() + " "
+					if namespace != "" {
+						get += namespace + "/"
+					}
+					get += name
+					gets = append(gets, get)
+					for _, e := range tc.existing {
+						if e == get {
+							return nil
+						}
+					}
+					return apierrors.NewNotFound(gvr.GroupResource(), name)
+				},
+			}
+
+			ctx := request.WithCluster(context.Background(), request.Cluster{Name: logicalcluster.New("root:org:consumer")})
+
+			err := o.Validate(ctx, tc.attr, nil)
+
+			wantErr := len(tc.expectedErrors) > 0
+			require.Equal(t, wantErr, err != nil, "unexpected error: %v", err)
+			for _, expected := range tc.expectedErrors {
+				require.Contains(t, err.Error(), expected)
+			}
+			require.Equal(t, tc.expectedChecks, checks)
+			require.Equal(t, tc.expectedGets, gets)
+		})
+	}
+}
@@ -0,0 +1,241 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replication
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/authorization/delegated"
+)
+
+const (
+	PluginName = "tenancy.kcp.dev/Replication"
+)
+
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName,
+		func(_ io.Reader) (admission.Interface, error) {
+			return &replicationAdmission{
+				Handler:          admission.NewHandler(admission.Create, admission.Update, admission.Delete),
+				createAuthorizer: delegated.NewDelegatedAuthorizer,
+			}, nil
+		})
+}
+
+// replicationAdmission checks that the creator of a Replication can list the replicated
+// objects, such that nobody can read objects through their replicas which they cannot
+// read in the source workspace. It records the creator in an annotation, for the controller
+// to replicate only into workspaces where the creator can create, update and delete the
+// resource. RBAC resources cannot be replicated, as kcp writes the replicas with its own
+// privileges, bypassing the escalation checks of RBAC. It also protects the replicas of all
+// resources from changes by anybody but members of system:masters, e.g. kcp itself.
+type replicationAdmission struct {
+	*admission.Handler
+	kubeClusterClient *kubernetes.Cluster
+
+	createAuthorizer delegated.DelegatedAuthorizerFactory
+}
+
+// Ensure that the required admission interfaces are implemented.
+var _ = admission.MutationInterface(&replicationAdmission{})
+var _ = admission.ValidationInterface(&replicationAdmission{})
+var _ = admission.InitializationValidator(&replicationAdmission{})
+
+// Admit records the creator of new Replications.
+func (o *replicationAdmission) Admit(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
+	if a.GetResource().GroupResource() != tenancyv1alpha1.Resource("replications") {
+		return nil
+	}
+	if a.GetOperation() != admission.Create || a.GetSubresource() != "" {
+		return nil
+	}
+
+	u, ok := a.GetObject().(*unstructured.Unstructured)
+	if !ok {
+		return fmt.Errorf("unexpected type %T", a.GetObject())
+	}
+	creator, err := delegated.EncodeUser(a.GetUserInfo())
+	if err != nil {
+		return apierrors.NewInternalError(fmt.Errorf("failed to encode the creator of the Replication: %w", err))
+	}
+	annotations := u.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[tenancyv1alpha1.ReplicationCreatorAnnotation] = creator
+	u.SetAnnotations(annotations)
+
+	return nil
+}
+
+func (o *replicationAdmission) Validate(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
+	if a.GetResource().GroupResource() == tenancyv1alpha1.Resource("replications") {
+		return o.validateReplication(ctx, a)
+	}
+	return o.validateReplica(a)
+}
+
+func (o *replicationAdmission) validateReplication(ctx context.Context, a admission.Attributes) error {
+	if a.GetOperation() == admission.Delete || a.GetSubresource() != "" {
+		return nil
+	}
+
+	replication, err := toReplication(a.GetObject())
+	if err != nil {
+		return err
+	}
+	if a.GetOperation() == admission.Update {
+		old, err := toReplication(a.GetOldObject())
+		if err != nil {
+			return err
+		}
+		// the target workspaces are authorized for the creator and the resource
+		var errs field.ErrorList
+		if creator := replication.Annotations[tenancyv1alpha1.ReplicationCreatorAnnotation]; creator != old.Annotations[tenancyv1alpha1.ReplicationCreatorAnnotation] {
+			errs = append(errs, field.Invalid(field.NewPath("metadata", "annotations").Key(tenancyv1alpha1.ReplicationCreatorAnnotation), creator, "field is immutable"))
+		}
+		if replication.Spec.Resource != old.Spec.Resource {
+			errs = append(errs, field.Invalid(field.NewPath("spec", "resource"), replication.Spec.Resource, "field is immutable"))
+		}
+		if replication.Spec.Namespace != old.Spec.Namespace {
+			errs = append(errs, field.Invalid(field.NewPath("spec", "namespace"), replication.Spec.Namespace, "field is immutable"))
+		}
+		if len(errs) > 0 {
+			return admission.NewForbidden(a, errs.ToAggregate())
+		}
+		return nil
+	}
+
+	if replication.Spec.Resource.Group == rbacv1.GroupName {
+		return admission.NewForbidden(a, field.Forbidden(field.NewPath("spec", "resource", "group"), "RBAC resources cannot be replicated, use a PolicyBundle instead"))
+	}
+
+	cluster, err := genericapirequest.ValidClusterFrom(ctx)
+	if err != nil {
+		return admission.NewForbidden(a, fmt.Errorf("error determining workspace: %w", err))
+	}
+
+	if err := o.checkSourceAccess(ctx, a.GetUserInfo(), cluster.Name, replication); err != nil {
+		return admission.NewForbidden(a, fmt.Errorf("unable to replicate %s: %w", replication.Spec.Resource.Resource, err))
+	}
+
+	return nil
+}
+
+func toReplication(obj runtime.Object) (*tenancyv1alpha1.Replication, error) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("unexpected type %T", obj)
+	}
+	replication := &tenancyv1alpha1.Replication{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, replication); err != nil {
+		return nil, fmt.Errorf("failed to convert unstructured to Replication: %w", err)
+	}
+	return replication, nil
+}
+
+func (o *replicationAdmission) checkSourceAccess(ctx context.Context, user user.Info, clusterName logicalcluster.Name, replication *tenancyv1alpha1.Replication) error {
+	authz, err := o.createAuthorizer(clusterName, o.kubeClusterClient)
+	if err != nil {
+		// Logging a more specific error for the operator
+		klog.Errorf("error creating authorizer from delegating authorizer config: %v", err)
+		// Returning a less specific error to the end user
+		return errors.New("unable to authorize request")
+	}
+
+	listAttr := authorizer.AttributesRecord{
+		User:            user,
+		Verb:            "list",
+		Namespace:       replication.Spec.Namespace,
+		APIGroup:        replication.Spec.Resource.Group,
+		APIVersion:      replication.Spec.Resource.Version,
+		Resource:        replication.Spec.Resource.Resource,
+		ResourceRequest: true,
+	}
+
+	if decision, _, err := authz.Authorize(ctx, listAttr); err != nil {
+		return fmt.Errorf("unable to determine access to %s: %w", replication.Spec.Resource.Resource, err)
+	} else if decision != authorizer.DecisionAllow {
+		return fmt.Errorf("missing verb='list' permission on %s", replication.Spec.Resource.Resource)
+	}
+
+	return nil
+}
+
+// validateReplica rejects changes of replicas, and objects pretending to be one. The status
+// is not replicated and can be changed.
+func (o *replicationAdmission) validateReplica(a admission.Attributes) error {
+	if a.GetSubresource() == "status" {
+		return nil
+	}
+	if userInfo := a.GetUserInfo(); userInfo != nil {
+		for _, group := range userInfo.GetGroups() {
+			if group == user.SystemPrivilegedGroup {
+				return nil
+			}
+		}
+	}
+
+	for _, obj := range []runtime.Object{a.GetObject(), a.GetOldObject()} {
+		if obj == nil {
+			continue
+		}
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			continue // e.g. subresources like pods/eviction
+		}
+		if _, ok := accessor.GetLabels()[tenancyv1alpha1.ReplicaLabel]; ok {
+			return admission.NewForbidden(a, fmt.Errorf("object is a read-only replica, managed by Replication %q", accessor.GetAnnotations()[tenancyv1alpha1.ReplicationAnnotation]))
+		}
+	}
+
+	return nil
+}
+
+// ValidateInitialization ensures the required injected fields are set.
+func (o *replicationAdmission) ValidateInitialization() error {
+	if o.kubeClusterClient == nil {
+		return fmt.Errorf(PluginName + " plugin needs a Kubernetes ClusterInterface")
+	}
+
+	return nil
+}
+
+// SetKubeClusterClient is an admission plugin initializer function that injects a Kubernetes cluster client into
+// this admission plugin.
+func (o *replicationAdmission) SetKubeClusterClient(clusterClient *kubernetes.Cluster) {
+	o.kubeClusterClient = clusterClient
+}
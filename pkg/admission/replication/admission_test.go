@@ -0,0 +1,274 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replication
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kcp-dev/kcp/pkg/admission/helpers"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/authorization/delegated"
+)
+
+func replicationAttr(op admission.Operation, replication, old *tenancyv1alpha1.Replication) admission.Attributes {
+	var obj, oldObj runtime.Object
+	if replication != nil {
+		obj = helpers.ToUnstructuredOrDie(replication)
+	}
+	if old != nil {
+		oldObj = helpers.ToUnstructuredOrDie(old)
+	}
+	return admission.NewAttributesRecord(
+		obj,
+		oldObj,
+		tenancyv1alpha1.Kind("Replication").WithVersion("v1alpha1"),
+		"",
+		"policies",
+		tenancyv1alpha1.Resource("replications").WithVersion("v1alpha1"),
+		"",
+		op,
+		&metav1.CreateOptions{},
+		false,
+		&user.DefaultInfo{},
+	)
+}
+
+func configMapAttr(op admission.Operation, subresource string, cm, old *corev1.ConfigMap, groups ...string) admission.Attributes {
+	var obj, oldObj runtime.Object
+	if cm != nil {
+		obj = cm
+	}
+	if old != nil {
+		oldObj = old
+	}
+	return admission.NewAttributesRecord(
+		obj,
+		oldObj,
+		corev1.SchemeGroupVersion.WithKind("ConfigMap"),
+		"default",
+		"quota",
+		corev1.SchemeGroupVersion.WithResource("configmaps"),
+		subresource,
+		op,
+		&metav1.CreateOptions{},
+		false,
+		&user.DefaultInfo{Groups: groups},
+	)
+}
+
+func newReplication(resource, namespace string, selector map[string]string) *tenancyv1alpha1.Replication {
+	return &tenancyv1alpha1.Replication{
+		ObjectMeta: metav1.ObjectMeta{Name: "policies"},
+		Spec: tenancyv1alpha1.ReplicationSpec{
+			Resource:  tenancyv1alpha1.ReplicationResource{Version: "v1", Resource: resource},
+			Namespace: namespace,
+			Selector:  metav1.LabelSelector{MatchLabels: selector},
+		},
+	}
+}
+
+func newConfigMap(labels map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "quota",
+			Namespace:   "default",
+			Labels:      labels,
+			Annotations: map[string]string{tenancyv1alpha1.ReplicationAnnotation: "root:org|policies"},
+		},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	replicaLabels := map[string]string{tenancyv1alpha1.ReplicaLabel: "true"}
+
+	tests := []struct {
+		name               string
+		attr               admission.Attributes
+		authzDecision      authorizer.Decision
+		authzError         error
+		expectedErrors     []string
+		expectedAuthorized string
+	}{
+		{
+			name:               "Create: passes when the source can be listed",
+			attr:               replicationAttr(admission.Create, newReplication("configmaps", "default", nil), nil),
+			authzDecision:      authorizer.DecisionAllow,
+			expectedAuthorized: "root:org",
+		},
+		{
+			name:               "Create: fails when the source cannot be listed",
+			attr:               replicationAttr(admission.Create, newReplication("secrets", "default", nil), nil),
+			authzDecision:      authorizer.DecisionDeny,
+			expectedErrors:     []string{"missing verb='list' permission on secrets"},
+			expectedAuthorized: "root:org",
+		},
+		{
+			name:               "Create: fails when there's an error checking authorization",
+			attr:               replicationAttr(admission.Create, newReplication("configmaps", "default", nil), nil),
+			authzError:         errors.New("some error here"),
+			expectedErrors:     []string{"unable to determine access to configmaps: some error here"},
+			expectedAuthorized: "root:org",
+		},
+		{
+			name: "Update: changed selector passes without check",
+			attr: replicationAttr(admission.Update, newReplication("configmaps", "default", map[string]string{"a": "b"}), newReplication("configmaps", "default", nil)),
+		},
+		{
+			name: "Create: RBAC resources fail",
+			attr: replicationAttr(admission.Create, func() *tenancyv1alpha1.Replication {
+				r := newReplication("clusterrolebindings", "", nil)
+				r.Spec.Resource.Group = "rbac.authorization.k8s.io"
+				return r
+			}(), nil),
+			authzDecision:  authorizer.DecisionAllow,
+			expectedErrors: []string{"RBAC resources cannot be replicated"},
+		},
+		{
+			name:           "Update: changed resource fails",
+			attr:           replicationAttr(admission.Update, newReplication("secrets", "default", nil), newReplication("configmaps", "default", nil)),
+			expectedErrors: []string{"spec.resource: Invalid value"},
+		},
+		{
+			name:           "Update: changed namespace fails",
+			attr:           replicationAttr(admission.Update, newReplication("configmaps", "kube-system", nil), newReplication("configmaps", "default", nil)),
+			expectedErrors: []string{"spec.namespace: Invalid value"},
+		},
+		{
+			name: "Update: changed creator fails",
+			attr: replicationAttr(admission.Update, func() *tenancyv1alpha1.Replication {
+				r := newReplication("configmaps", "default", nil)
+				r.Annotations = map[string]string{tenancyv1alpha1.ReplicationCreatorAnnotation: `{"username":"admin"}`}
+				return r
+			}(), newReplication("configmaps", "default", nil)),
+			expectedErrors: []string{"tenancy.kcp.dev/replication-creator"},
+		},
+		{
+			name: "Delete: passes",
+			attr: replicationAttr(admission.Delete, nil, newReplication("configmaps", "default", nil)),
+		},
+		{
+			name: "Replica: update of other objects passes",
+			attr: configMapAttr(admission.Update, "", newConfigMap(nil), newConfigMap(nil)),
+		},
+		{
+			name:           "Replica: update fails",
+			attr:           configMapAttr(admission.Update, "", newConfigMap(replicaLabels), newConfigMap(replicaLabels)),
+			expectedErrors: []string{`managed by Replication "root:org|policies"`},
+		},
+		{
+			name:           "Replica: removing the label fails",
+			attr:           configMapAttr(admission.Update, "", newConfigMap(nil), newConfigMap(replicaLabels)),
+			expectedErrors: []string{`managed by Replication "root:org|policies"`},
+		},
+		{
+			name:           "Replica: deletion fails",
+			attr:           configMapAttr(admission.Delete, "", nil, newConfigMap(replicaLabels)),
+			expectedErrors: []string{`managed by Replication "root:org|policies"`},
+		},
+		{
+			name:           "Replica: creation of fake replicas fails",
+			attr:           configMapAttr(admission.Create, "", newConfigMap(replicaLabels), nil),
+			expectedErrors: []string{`managed by Replication "root:org|policies"`},
+		},
+		{
+			name: "Replica: status update passes",
+			attr: configMapAttr(admission.Update, "status", newConfigMap(replicaLabels), newConfigMap(replicaLabels)),
+		},
+		{
+			name: "Replica: update by system:masters passes",
+			attr: configMapAttr(admission.Update, "", newConfigMap(replicaLabels), newConfigMap(replicaLabels), user.SystemPrivilegedGroup),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var authorized string
+			o := &replicationAdmission{
+				Handler: admission.NewHandler(admission.Create, admission.Update, admission.Delete),
+				createAuthorizer: func(clusterName logicalcluster.Name, client kubernetes.ClusterInterface) (authorizer.Authorizer, error) {
+					authorized = clusterName.String()
+					return &fakeAuthorizer{
+						tc.authzDecision,
+						tc.authzError,
+					}, nil
+				},
+			}
+
+			ctx := request.WithCluster(context.Background(), request.Cluster{Name: logicalcluster.New("root:org")})
+
+			err := o.Validate(ctx, tc.attr, nil)
+
+			wantErr := len(tc.expectedErrors) > 0
+			require.Equal(t, wantErr, err != nil, "unexpected error: %v", err)
+			for _, expected := range tc.expectedErrors {
+				require.Contains(t, err.Error(), expected)
+			}
+			require.Equal(t, tc.expectedAuthorized, authorized)
+		})
+	}
+}
+
+type fakeAuthorizer struct {
+	authorized authorizer.Decision
+	err        error
+}
+
+func (a *fakeAuthorizer) Authorize(ctx context.Context, attr authorizer.Attributes) (authorized authorizer.Decision, reason string, err error) {
+	return a.authorized, "reason", a.err
+}
+
+func TestAdmit(t *testing.T) {
+	replication := newReplication("configmaps", "default", nil)
+	replication.Annotations = map[string]string{tenancyv1alpha1.ReplicationCreatorAnnotation: `{"username":"admin","groups":["system:masters"]}`}
+	attr := admission.NewAttributesRecord(
+		helpers.ToUnstructuredOrDie(replication),
+		nil,
+		tenancyv1alpha1.Kind("Replication").WithVersion("v1alpha1"),
+		"",
+		"policies",
+		tenancyv1alpha1.Resource("replications").WithVersion("v1alpha1"),
+		"",
+		admission.Create,
+		&metav1.CreateOptions{},
+		false,
+		&user.DefaultInfo{Name: "alice", Groups: []string{"org-admins"}},
+	)
+
+	o := &replicationAdmission{Handler: admission.NewHandler(admission.Create, admission.Update, admission.Delete)}
+	require.NoError(t, o.Admit(context.Background(), attr, nil))
+
+	annotations := attr.GetObject().(*unstructured.Unstructured).GetAnnotations()
+	creator, err := delegated.DecodeUser(annotations[tenancyv1alpha1.ReplicationCreatorAnnotation])
+	require.NoError(t, err)
+	require.Equal(t, "alice", creator.GetName())
+	require.Equal(t, []string{"org-admins"}, creator.GetGroups())
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretclaim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/authorization/delegated"
+)
+
+const (
+	PluginName = "apis.kcp.dev/SecretClaim"
+)
+
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName,
+		func(_ io.Reader) (admission.Interface, error) {
+			return &secretClaimAdmission{
+				Handler:          admission.NewHandler(admission.Create, admission.Update, admission.Delete),
+				createAuthorizer: delegated.NewDelegatedAuthorizer,
+			}, nil
+		})
+}
+
+// secretClaimAdmission checks that the creator of a SecretClaim has the verb `claim` on the
+// claimed SharedSecret, and records the creator in an annotation, for the controller to copy
+// the secret only as long as the creator can claim it. The reference, secret name and creator
+// of SecretClaims are immutable. It also protects the copies of shared secrets from changes by anybody but members of
+// system:masters, e.g. kcp itself.
+type secretClaimAdmission struct {
+	*admission.Handler
+	kubeClusterClient *kubernetes.Cluster
+
+	createAuthorizer delegated.DelegatedAuthorizerFactory
+}
+
+// Ensure that the required admission interfaces are implemented.
+var _ = admission.MutationInterface(&secretClaimAdmission{})
+var _ = admission.ValidationInterface(&secretClaimAdmission{})
+var _ = admission.InitializationValidator(&secretClaimAdmission{})
+
+// Admit records the creator of new SecretClaims.
+func (o *secretClaimAdmission) Admit(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
+	if a.GetResource().GroupResource() != apisv1alpha1.Resource("secretclaims") {
+		return nil
+	}
+	if a.GetOperation() != admission.Create || a.GetSubresource() != "" {
+		return nil
+	}
+
+	u, ok := a.GetObject().(*unstructured.Unstructured)
+	if !ok {
+		return fmt.Errorf("unexpected type %T", a.GetObject())
+	}
+	creator, err := delegated.EncodeUser(a.GetUserInfo())
+	if err != nil {
+		return apierrors.NewInternalError(fmt.Errorf("failed to encode the creator of the SecretClaim: %w", err))
+	}
+	annotations := u.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[apisv1alpha1.SecretClaimCreatorAnnotation] = creator
+	u.SetAnnotations(annotations)
+
+	return nil
+}
+
+func (o *secretClaimAdmission) Validate(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
+	switch a.GetResource().GroupResource() {
+	case apisv1alpha1.Resource("secretclaims"):
+		return o.validateSecretClaim(ctx, a)
+	case corev1.Resource("secrets"):
+		return o.validateSecret(a)
+	}
+	return nil
+}
+
+func (o *secretClaimAdmission) validateSecretClaim(ctx context.Context, a admission.Attributes) error {
+	if a.GetOperation() == admission.Delete || a.GetSubresource() != "" {
+		return nil
+	}
+
+	claim, err := toSecretClaim(a.GetObject())
+	if err != nil {
+		return err
+	}
+
+	if a.GetOperation() == admission.Update {
+		old, err := toSecretClaim(a.GetOldObject())
+		if err != nil {
+			return err
+		}
+		var errs field.ErrorList
+		if creator := claim.Annotations[apisv1alpha1.SecretClaimCreatorAnnotation]; creator != old.Annotations[apisv1alpha1.SecretClaimCreatorAnnotation] {
+			errs = append(errs, field.Invalid(field.NewPath("metadata", "annotations").Key(apisv1alpha1.SecretClaimCreatorAnnotation), creator, "field is immutable"))
+		}
+		if claim.Spec.Reference != old.Spec.Reference {
+			errs = append(errs, field.Invalid(field.NewPath("spec", "reference"), claim.Spec.Reference, "field is immutable"))
+		}
+		if claim.Spec.SecretName != old.Spec.SecretName {
+			errs = append(errs, field.Invalid(field.NewPath("spec", "secretName"), claim.Spec.SecretName, "field is immutable"))
+		}
+		if len(errs) > 0 {
+			return admission.NewForbidden(a, errs.ToAggregate())
+		}
+		return nil
+	}
+
+	cluster, err := genericapirequest.ValidClusterFrom(ctx)
+	if err != nil {
+		return admission.NewForbidden(a, fmt.Errorf("error determining workspace: %w", err))
+	}
+	org, hasParent := cluster.Name.Parent()
+	if !hasParent {
+		return admission.NewForbidden(a, fmt.Errorf("%q is not a valid workspace name", cluster.Name))
+	}
+	sharedSecretClusterName := org.Join(claim.Spec.Reference.WorkspaceName)
+
+	if err := o.checkSharedSecretAccess(ctx, a.GetUserInfo(), sharedSecretClusterName, claim.Spec.Reference.SharedSecretName); err != nil {
+		return admission.NewForbidden(a, fmt.Errorf("unable to create SecretClaim: %w", err))
+	}
+
+	return nil
+}
+
+func toSecretClaim(obj runtime.Object) (*apisv1alpha1.SecretClaim, error) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("unexpected type %T", obj)
+	}
+	claim := &apisv1alpha1.SecretClaim{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, claim); err != nil {
+		return nil, fmt.Errorf("failed to convert unstructured to SecretClaim: %w", err)
+	}
+	return claim, nil
+}
+
+func (o *secretClaimAdmission) checkSharedSecretAccess(ctx context.Context, user user.Info, sharedSecretClusterName logicalcluster.Name, sharedSecretName string) error {
+	authz, err := o.createAuthorizer(sharedSecretClusterName, o.kubeClusterClient)
+	if err != nil {
+		// Logging a more specific error for the operator
+		klog.Errorf("error creating authorizer from delegating authorizer config: %v", err)
+		// Returning a less specific error to the end user
+		return errors.New("unable to authorize request")
+	}
+
+	claimAttr := authorizer.AttributesRecord{
+		User:            user,
+		Verb:            "claim",
+		APIGroup:        apisv1alpha1.SchemeGroupVersion.Group,
+		APIVersion:      apisv1alpha1.SchemeGroupVersion.Version,
+		Resource:        "sharedsecrets",
+		Name:            sharedSecretName,
+		ResourceRequest: true,
+	}
+
+	if decision, _, err := authz.Authorize(ctx, claimAttr); err != nil {
+		return fmt.Errorf("unable to determine access to sharedsecrets: %w", err)
+	} else if decision != authorizer.DecisionAllow {
+		return errors.New("missing verb='claim' permission on sharedsecrets")
+	}
+
+	return nil
+}
+
+// validateSecret rejects changes of copies of shared secrets, and secrets pretending to be one.
+func (o *secretClaimAdmission) validateSecret(a admission.Attributes) error {
+	if userInfo := a.GetUserInfo(); userInfo != nil {
+		for _, group := range userInfo.GetGroups() {
+			if group == user.SystemPrivilegedGroup {
+				return nil
+			}
+		}
+	}
+
+	for _, obj := range []runtime.Object{a.GetObject(), a.GetOldObject()} {
+		if obj == nil {
+			continue
+		}
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return fmt.Errorf("unexpected type %T", obj)
+		}
+		if claimName, ok := accessor.GetLabels()[apisv1alpha1.SecretClaimLabel]; ok {
+			return admission.NewForbidden(a, fmt.Errorf("secret is a read-only copy of a shared secret, managed by SecretClaim %q", claimName))
+		}
+	}
+
+	return nil
+}
+
+// ValidateInitialization ensures the required injected fields are set.
+func (o *secretClaimAdmission) ValidateInitialization() error {
+	if o.kubeClusterClient == nil {
+		return fmt.Errorf(PluginName + " plugin needs a Kubernetes ClusterInterface")
+	}
+
+	return nil
+}
+
+// SetKubeClusterClient is an admission plugin initializer function that injects a Kubernetes cluster client into
+// this admission plugin.
+func (o *secretClaimAdmission) SetKubeClusterClient(clusterClient *kubernetes.Cluster) {
+	o.kubeClusterClient = clusterClient
+}
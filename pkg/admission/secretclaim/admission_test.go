@@ -0,0 +1,254 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretclaim
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kcp-dev/kcp/pkg/admission/helpers"
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/authorization/delegated"
+)
+
+func claimAttr(op admission.Operation, claim, old *apisv1alpha1.SecretClaim) admission.Attributes {
+	var obj, oldObj runtime.Object
+	if claim != nil {
+		obj = helpers.ToUnstructuredOrDie(claim)
+	}
+	if old != nil {
+		oldObj = helpers.ToUnstructuredOrDie(old)
+	}
+	return admission.NewAttributesRecord(
+		obj,
+		oldObj,
+		apisv1alpha1.Kind("SecretClaim").WithVersion("v1alpha1"),
+		"default",
+		"registry",
+		apisv1alpha1.Resource("secretclaims").WithVersion("v1alpha1"),
+		"",
+		op,
+		&metav1.CreateOptions{},
+		false,
+		&user.DefaultInfo{},
+	)
+}
+
+func secretAttr(op admission.Operation, secret, old *corev1.Secret, groups ...string) admission.Attributes {
+	var obj, oldObj runtime.Object
+	if secret != nil {
+		obj = secret
+	}
+	if old != nil {
+		oldObj = old
+	}
+	return admission.NewAttributesRecord(
+		obj,
+		oldObj,
+		corev1.SchemeGroupVersion.WithKind("Secret"),
+		"default",
+		"registry",
+		corev1.SchemeGroupVersion.WithResource("secrets"),
+		"",
+		op,
+		&metav1.CreateOptions{},
+		false,
+		&user.DefaultInfo{Groups: groups},
+	)
+}
+
+func newSecretClaim(workspaceName, sharedSecretName, secretName string) *apisv1alpha1.SecretClaim {
+	return &apisv1alpha1.SecretClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "registry", Namespace: "default"},
+		Spec: apisv1alpha1.SecretClaimSpec{
+			Reference:  apisv1alpha1.SharedSecretReference{WorkspaceName: workspaceName, SharedSecretName: sharedSecretName},
+			SecretName: secretName,
+		},
+	}
+}
+
+func newSecret(labels map[string]string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "registry", Namespace: "default", Labels: labels},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	copyLabels := map[string]string{apisv1alpha1.SecretClaimLabel: "registry"}
+
+	tests := []struct {
+		name               string
+		attr               admission.Attributes
+		authzDecision      authorizer.Decision
+		authzError         error
+		expectedErrors     []string
+		expectedAuthorized string
+	}{
+		{
+			name:               "Create: passes when authorized",
+			attr:               claimAttr(admission.Create, newSecretClaim("provider", "registry", ""), nil),
+			authzDecision:      authorizer.DecisionAllow,
+			expectedAuthorized: "root:org:provider",
+		},
+		{
+			name:               "Create: fails when denied",
+			attr:               claimAttr(admission.Create, newSecretClaim("provider", "registry", ""), nil),
+			authzDecision:      authorizer.DecisionDeny,
+			expectedErrors:     []string{"missing verb='claim' permission on sharedsecrets"},
+			expectedAuthorized: "root:org:provider",
+		},
+		{
+			name:               "Create: fails when there's an error checking authorization",
+			attr:               claimAttr(admission.Create, newSecretClaim("provider", "registry", ""), nil),
+			authzError:         errors.New("some error here"),
+			expectedErrors:     []string{"unable to determine access to sharedsecrets: some error here"},
+			expectedAuthorized: "root:org:provider",
+		},
+		{
+			name: "Update: unchanged spec passes",
+			attr: claimAttr(admission.Update, newSecretClaim("provider", "registry", "pull"), newSecretClaim("provider", "registry", "pull")),
+		},
+		{
+			name:           "Update: changed reference fails",
+			attr:           claimAttr(admission.Update, newSecretClaim("other", "registry", ""), newSecretClaim("provider", "registry", "")),
+			expectedErrors: []string{"spec.reference: Invalid value"},
+		},
+		{
+			name:           "Update: changed secret name fails",
+			attr:           claimAttr(admission.Update, newSecretClaim("provider", "registry", "other"), newSecretClaim("provider", "registry", "")),
+			expectedErrors: []string{"spec.secretName: Invalid value"},
+		},
+		{
+			name: "Update: changed creator fails",
+			attr: claimAttr(admission.Update, func() *apisv1alpha1.SecretClaim {
+				claim := newSecretClaim("provider", "registry", "")
+				claim.Annotations = map[string]string{apisv1alpha1.SecretClaimCreatorAnnotation: `{"username":"admin"}`}
+				return claim
+			}(), newSecretClaim("provider", "registry", "")),
+			expectedErrors: []string{"apis.kcp.dev/secret-claim-creator"},
+		},
+		{
+			name: "Delete: passes",
+			attr: claimAttr(admission.Delete, nil, newSecretClaim("provider", "registry", "")),
+		},
+		{
+			name: "Secret: update of other secrets passes",
+			attr: secretAttr(admission.Update, newSecret(nil), newSecret(nil)),
+		},
+		{
+			name:           "Secret: update of copies fails",
+			attr:           secretAttr(admission.Update, newSecret(copyLabels), newSecret(copyLabels)),
+			expectedErrors: []string{`managed by SecretClaim "registry"`},
+		},
+		{
+			name:           "Secret: removing the label of copies fails",
+			attr:           secretAttr(admission.Update, newSecret(nil), newSecret(copyLabels)),
+			expectedErrors: []string{`managed by SecretClaim "registry"`},
+		},
+		{
+			name:           "Secret: deletion of copies fails",
+			attr:           secretAttr(admission.Delete, nil, newSecret(copyLabels)),
+			expectedErrors: []string{`managed by SecretClaim "registry"`},
+		},
+		{
+			name:           "Secret: creation of fake copies fails",
+			attr:           secretAttr(admission.Create, newSecret(copyLabels), nil),
+			expectedErrors: []string{`managed by SecretClaim "registry"`},
+		},
+		{
+			name: "Secret: update of copies by system:masters passes",
+			attr: secretAttr(admission.Update, newSecret(copyLabels), newSecret(copyLabels), user.SystemPrivilegedGroup),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var authorized string
+			o := &secretClaimAdmission{
+				Handler: admission.NewHandler(admission.Create, admission.Update, admission.Delete),
+				createAuthorizer: func(clusterName logicalcluster.Name, client kubernetes.ClusterInterface) (authorizer.Authorizer, error) {
+					authorized = clusterName.String()
+					return &fakeAuthorizer{
+						tc.authzDecision,
+						tc.authzError,
+					}, nil
+				},
+			}
+
+			ctx := request.WithCluster(context.Background(), request.Cluster{Name: logicalcluster.New("root:org:consumer")})
+
+			err := o.Validate(ctx, tc.attr, nil)
+
+			wantErr := len(tc.expectedErrors) > 0
+			require.Equal(t, wantErr, err != nil, "unexpected error: %v", err)
+			for _, expected := range tc.expectedErrors {
+				require.Contains(t, err.Error(), expected)
+			}
+			require.Equal(t, tc.expectedAuthorized, authorized)
+		})
+	}
+}
+
+func TestAdmit(t *testing.T) {
+	claim := newSecretClaim("provider", "registry", "")
+	claim.Annotations = map[string]string{apisv1alpha1.SecretClaimCreatorAnnotation: `{"username":"admin","groups":["system:masters"]}`}
+	attr := admission.NewAttributesRecord(
+		helpers.ToUnstructuredOrDie(claim),
+		nil,
+		apisv1alpha1.Kind("SecretClaim").WithVersion("v1alpha1"),
+		"default",
+		"registry",
+		apisv1alpha1.Resource("secretclaims").WithVersion("v1alpha1"),
+		"",
+		admission.Create,
+		&metav1.CreateOptions{},
+		false,
+		&user.DefaultInfo{Name: "alice", Groups: []string{"consumers"}},
+	)
+
+	o := &secretClaimAdmission{Handler: admission.NewHandler(admission.Create, admission.Update, admission.Delete)}
+	require.NoError(t, o.Admit(context.Background(), attr, nil))
+
+	annotations := attr.GetObject().(*unstructured.Unstructured).GetAnnotations()
+	creator, err := delegated.DecodeUser(annotations[apisv1alpha1.SecretClaimCreatorAnnotation])
+	require.NoError(t, err)
+	require.Equal(t, "alice", creator.GetName())
+	require.Equal(t, []string{"consumers"}, creator.GetGroups())
+}
+
+type fakeAuthorizer struct {
+	authorized authorizer.Decision
+	err        error
+}
+
+func (a *fakeAuthorizer) Authorize(ctx context.Context, attr authorizer.Attributes) (authorized authorizer.Decision, reason string, err error) {
+	return a.authorized, "reason", a.err
+}
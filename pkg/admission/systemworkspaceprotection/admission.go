@@ -0,0 +1,214 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package systemworkspaceprotection
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clusters"
+	"k8s.io/klog/v2"
+
+	kcpinitializers "github.com/kcp-dev/kcp/pkg/admission/initializers"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	kcpinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
+)
+
+const (
+	PluginName = "tenancy.kcp.dev/SystemWorkspaceProtection"
+
+	byWorkspaceIndex = "systemWorkspaceProtection-byWorkspace"
+)
+
+// reviewGroups are API groups whose create requests only review, but do not store anything.
+var reviewGroups = sets.NewString("authentication.k8s.io", "authorization.k8s.io")
+
+// reservedNames are names of child workspaces which would be confused with the root
+// workspace or with system workspaces.
+var reservedNames = sets.NewString("root", "system", "kcp")
+
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName,
+		func(_ io.Reader) (admission.Interface, error) {
+			return &systemWorkspaceProtection{
+				Handler: admission.NewHandler(admission.Create, admission.Update, admission.Delete),
+			}, nil
+		})
+}
+
+// systemWorkspaceProtection protects system workspaces and reserved logical clusters
+// from everybody but members of system:masters and of the system workspace admins group:
+// - writes in system:* logical clusters are rejected,
+// - writes in ClusterWorkspaces labeled as protected are rejected,
+// - objects labeled as protected cannot be updated or deleted,
+// - the protected label cannot be set,
+// - ClusterWorkspaces with reserved or confusing names cannot be created.
+//
+// Names are confusing if they look like a reserved name or like the name of a protected
+// sibling workspace, e.g. "systern".
+type systemWorkspaceProtection struct {
+	*admission.Handler
+
+	getClusterWorkspace   func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error)
+	listClusterWorkspaces func(clusterName logicalcluster.Name) ([]*tenancyv1alpha1.ClusterWorkspace, error)
+}
+
+// Ensure that the required admission interfaces are implemented.
+var _ = admission.ValidationInterface(&systemWorkspaceProtection{})
+var _ = admission.InitializationValidator(&systemWorkspaceProtection{})
+var _ = kcpinitializers.WantsKcpInformers(&systemWorkspaceProtection{})
+
+// Validate rejects writes of protected objects and in system workspaces, and the
+// creation of workspaces with reserved or confusing names.
+func (o *systemWorkspaceProtection) Validate(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
+	if a.GetOperation() == admission.Create && reviewGroups.Has(a.GetResource().Group) {
+		return nil
+	}
+	if userInfo := a.GetUserInfo(); userInfo != nil {
+		for _, group := range userInfo.GetGroups() {
+			if group == user.SystemPrivilegedGroup || group == tenancyv1alpha1.SystemWorkspaceAdminsGroup {
+				return nil
+			}
+		}
+	}
+
+	clusterName, err := genericapirequest.ClusterNameFrom(ctx)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	if clusterName.HasPrefix(logicalcluster.New("system")) {
+		return admission.NewForbidden(a, fmt.Errorf("system workspace %s is protected", clusterName))
+	}
+
+	if isProtected(a.GetOldObject()) {
+		return admission.NewForbidden(a, fmt.Errorf("%s %q is protected", a.GetResource().GroupResource(), a.GetName()))
+	}
+	if isProtected(a.GetObject()) && a.GetOperation() != admission.Delete {
+		return admission.NewForbidden(a, fmt.Errorf("only system workspace admins can set the %s label", tenancyv1alpha1.ProtectedLabel))
+	}
+
+	if !o.WaitForReady() {
+		return admission.NewForbidden(a, fmt.Errorf("not yet ready to handle request"))
+	}
+
+	if parent, name := clusterName.Split(); !parent.Empty() {
+		ws, err := o.getClusterWorkspace(parent, name)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return admission.NewForbidden(a, err)
+		}
+		if ws != nil && ws.Labels[tenancyv1alpha1.ProtectedLabel] == "true" {
+			return admission.NewForbidden(a, fmt.Errorf("system workspace %s is protected", clusterName))
+		}
+	}
+
+	if a.GetOperation() == admission.Create && a.GetResource().GroupResource() == tenancyv1alpha1.Resource("clusterworkspaces") && a.GetSubresource() == "" {
+		return o.validateName(a, clusterName)
+	}
+
+	return nil
+}
+
+// validateName rejects reserved names for new ClusterWorkspaces, and names which look
+// like a reserved name or like the name of a protected sibling.
+func (o *systemWorkspaceProtection) validateName(a admission.Attributes, clusterName logicalcluster.Name) error {
+	name := a.GetName()
+	if reservedNames.Has(name) || strings.HasPrefix(name, "system-") {
+		return admission.NewForbidden(a, fmt.Errorf("workspace name %q is reserved", name))
+	}
+
+	s := skeleton(name)
+	for _, reserved := range reservedNames.List() {
+		if s == skeleton(reserved) {
+			return admission.NewForbidden(a, fmt.Errorf("workspace name %q is confusable with the reserved name %q", name, reserved))
+		}
+	}
+
+	siblings, err := o.listClusterWorkspaces(clusterName)
+	if err != nil {
+		return admission.NewForbidden(a, err)
+	}
+	for _, sibling := range siblings {
+		if sibling.Name != name && isProtected(sibling) && skeleton(sibling.Name) == s {
+			return admission.NewForbidden(a, fmt.Errorf("workspace name %q is confusable with the system workspace %q", name, sibling.Name))
+		}
+	}
+
+	return nil
+}
+
+func isProtected(obj interface{}) bool {
+	if obj == nil {
+		return false
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return false
+	}
+	return accessor.GetLabels()[tenancyv1alpha1.ProtectedLabel] == "true"
+}
+
+func (o *systemWorkspaceProtection) ValidateInitialization() error {
+	if o.getClusterWorkspace == nil || o.listClusterWorkspaces == nil {
+		return fmt.Errorf(PluginName + " plugin needs a ClusterWorkspace lister")
+	}
+	return nil
+}
+
+func (o *systemWorkspaceProtection) SetKcpInformers(informers kcpinformers.SharedInformerFactory) {
+	workspaceInformer := informers.Tenancy().V1alpha1().ClusterWorkspaces().Informer()
+	o.SetReadyFunc(workspaceInformer.HasSynced)
+
+	workspaceLister := informers.Tenancy().V1alpha1().ClusterWorkspaces().Lister()
+	o.getClusterWorkspace = func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error) {
+		return workspaceLister.Get(clusters.ToClusterAwareKey(clusterName, name))
+	}
+
+	if _, found := workspaceInformer.GetIndexer().GetIndexers()[byWorkspaceIndex]; !found {
+		if err := workspaceInformer.AddIndexers(cache.Indexers{
+			byWorkspaceIndex: func(obj interface{}) ([]string, error) {
+				return []string{logicalcluster.From(obj.(metav1.Object)).String()}, nil
+			},
+		}); err != nil {
+			// nothing we can do here. But this should also never happen. We check for existence before.
+			klog.Errorf("failed to add indexer for ClusterWorkspaces: %v", err)
+		}
+	}
+	workspaceIndexer := workspaceInformer.GetIndexer()
+	o.listClusterWorkspaces = func(clusterName logicalcluster.Name) ([]*tenancyv1alpha1.ClusterWorkspace, error) {
+		objs, err := workspaceIndexer.ByIndex(byWorkspaceIndex, clusterName.String())
+		if err != nil {
+			return nil, err
+		}
+		workspaces := make([]*tenancyv1alpha1.ClusterWorkspace, 0, len(objs))
+		for _, obj := range objs {
+			workspaces = append(workspaces, obj.(*tenancyv1alpha1.ClusterWorkspace))
+		}
+		return workspaces, nil
+	}
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package systemworkspaceprotection
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+var (
+	configMaps        = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	clusterWorkspaces = tenancyv1alpha1.SchemeGroupVersion.WithResource("clusterworkspaces")
+)
+
+func workspace(name string, labels map[string]string) *tenancyv1alpha1.ClusterWorkspace {
+	return &tenancyv1alpha1.ClusterWorkspace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func attr(op admission.Operation, gvr schema.GroupVersionResource, obj, old runtime.Object, groups ...string) admission.Attributes {
+	var opts runtime.Object
+	name := ""
+	switch op {
+	case admission.Create:
+		opts = &metav1.CreateOptions{}
+	case admission.Update:
+		opts = &metav1.UpdateOptions{}
+	case admission.Delete:
+		opts = &metav1.DeleteOptions{}
+	}
+	for _, o := range []runtime.Object{obj, old} {
+		if m, ok := o.(metav1.Object); ok {
+			name = m.GetName()
+		}
+	}
+	return admission.NewAttributesRecord(obj, old, schema.GroupVersionKind{}, "", name, gvr, "", op, opts, false, &user.DefaultInfo{Name: "alice", Groups: groups})
+}
+
+func TestValidate(t *testing.T) {
+	protected := map[string]string{tenancyv1alpha1.ProtectedLabel: "true"}
+	configMap := func(labels map[string]string) *metav1.PartialObjectMetadata {
+		return &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "cm", Labels: labels}}
+	}
+
+	tests := []struct {
+		name       string
+		cluster    string
+		workspaces []*tenancyv1alpha1.ClusterWorkspace
+		attr       admission.Attributes
+		wantErr    bool
+	}{
+		{
+			name:    "write in a regular workspace is admitted",
+			cluster: "root:org:ws",
+			attr:    attr(admission.Create, configMaps, configMap(nil), nil),
+		},
+		{
+			name:    "write in a system logical cluster is rejected",
+			cluster: "system:admin",
+			attr:    attr(admission.Create, configMaps, configMap(nil), nil),
+			wantErr: true,
+		},
+		{
+			name:    "write in a system logical cluster by system workspace admins is admitted",
+			cluster: "system:admin",
+			attr:    attr(admission.Create, configMaps, configMap(nil), nil, tenancyv1alpha1.SystemWorkspaceAdminsGroup),
+		},
+		{
+			name:    "write in a system logical cluster by system:masters is admitted",
+			cluster: "system:admin",
+			attr:    attr(admission.Delete, configMaps, nil, configMap(nil), user.SystemPrivilegedGroup),
+		},
+		{
+			name:       "write in a protected workspace is rejected",
+			cluster:    "root:org:ws",
+			workspaces: []*tenancyv1alpha1.ClusterWorkspace{workspace("ws", protected)},
+			attr:       attr(admission.Create, configMaps, configMap(nil), nil),
+			wantErr:    true,
+		},
+		{
+			name:    "update of a protected object is rejected",
+			cluster: "root",
+			attr:    attr(admission.Update, configMaps, configMap(nil), configMap(protected)),
+			wantErr: true,
+		},
+		{
+			name:    "deletion of a protected object is rejected",
+			cluster: "root",
+			attr:    attr(admission.Delete, configMaps, nil, configMap(protected)),
+			wantErr: true,
+		},
+		{
+			name:    "setting the protected label is rejected",
+			cluster: "root:org",
+			attr:    attr(admission.Update, configMaps, configMap(protected), configMap(nil)),
+			wantErr: true,
+		},
+		{
+			name:    "deletion of a protected workspace is rejected",
+			cluster: "root",
+			attr:    attr(admission.Delete, clusterWorkspaces, nil, workspace("infra", protected)),
+			wantErr: true,
+		},
+		{
+			name:    "deletion of a protected workspace by system workspace admins is admitted",
+			cluster: "root",
+			attr:    attr(admission.Delete, clusterWorkspaces, nil, workspace("infra", protected), tenancyv1alpha1.SystemWorkspaceAdminsGroup),
+		},
+		{
+			name:    "workspace with a regular name is admitted",
+			cluster: "root:org",
+			attr:    attr(admission.Create, clusterWorkspaces, workspace("team", nil), nil),
+		},
+		{
+			name:    "workspace with a reserved name is rejected",
+			cluster: "root:org",
+			attr:    attr(admission.Create, clusterWorkspaces, workspace("system", nil), nil),
+			wantErr: true,
+		},
+		{
+			name:    "workspace with a reserved prefix is rejected",
+			cluster: "root",
+			attr:    attr(admission.Create, clusterWorkspaces, workspace("system-infra", nil), nil),
+			wantErr: true,
+		},
+		{
+			name:    "workspace with a name confusable with a reserved name is rejected",
+			cluster: "root",
+			attr:    attr(admission.Create, clusterWorkspaces, workspace("systern", nil), nil),
+			wantErr: true,
+		},
+		{
+			name:       "workspace with a name confusable with a protected sibling is rejected",
+			cluster:    "root:org",
+			workspaces: []*tenancyv1alpha1.ClusterWorkspace{workspace("platform", map[string]string{tenancyv1alpha1.ProtectedLabel: "true"})},
+			attr:       attr(admission.Create, clusterWorkspaces, workspace("platforrn", nil), nil),
+			wantErr:    true,
+		},
+		{
+			name:       "workspace with a name confusable with an unprotected sibling is admitted",
+			cluster:    "root:org",
+			workspaces: []*tenancyv1alpha1.ClusterWorkspace{workspace("platform", nil)},
+			attr:       attr(admission.Create, clusterWorkspaces, workspace("platforrn", nil), nil),
+		},
+		{
+			name:       "workspace with a name of a protected sibling in another workspace is admitted",
+			cluster:    "root:other",
+			workspaces: []*tenancyv1alpha1.ClusterWorkspace{workspace("platform", map[string]string{tenancyv1alpha1.ProtectedLabel: "true"})},
+			attr:       attr(admission.Create, clusterWorkspaces, workspace("platforrn", nil), nil),
+		},
+		{
+			name:       "workspace with a name differing from a protected sibling by a digit is admitted",
+			cluster:    "root:org",
+			workspaces: []*tenancyv1alpha1.ClusterWorkspace{workspace("teams", map[string]string{tenancyv1alpha1.ProtectedLabel: "true"})},
+			attr:       attr(admission.Create, clusterWorkspaces, workspace("team-5", nil), nil),
+		},
+		{
+			name:       "workspace with a name differing from a protected sibling by a dash is admitted",
+			cluster:    "root:org",
+			workspaces: []*tenancyv1alpha1.ClusterWorkspace{workspace("appl", map[string]string{tenancyv1alpha1.ProtectedLabel: "true"})},
+			attr:       attr(admission.Create, clusterWorkspaces, workspace("app-1", nil), nil),
+		},
+		{
+			name:    "workspace with digits looking like a reserved name is admitted",
+			cluster: "root",
+			attr:    attr(admission.Create, clusterWorkspaces, workspace("r00t", nil), nil),
+		},
+		{
+			name:    "workspace with a reserved name by system workspace admins is admitted",
+			cluster: "root",
+			attr:    attr(admission.Create, clusterWorkspaces, workspace("system", nil), nil, tenancyv1alpha1.SystemWorkspaceAdminsGroup),
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o := &systemWorkspaceProtection{
+				Handler: admission.NewHandler(admission.Create, admission.Update, admission.Delete),
+				getClusterWorkspace: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error) {
+					if clusterName.String() == "root:org" {
+						for _, ws := range tc.workspaces {
+							if ws.Name == name {
+								return ws, nil
+							}
+						}
+					}
+					return nil, apierrors.NewNotFound(tenancyv1alpha1.Resource("clusterworkspaces"), name)
+				},
+				listClusterWorkspaces: func(clusterName logicalcluster.Name) ([]*tenancyv1alpha1.ClusterWorkspace, error) {
+					if clusterName.String() == "root:org" {
+						return tc.workspaces, nil
+					}
+					return nil, nil
+				},
+			}
+
+			ctx := request.WithCluster(context.Background(), request.Cluster{Name: logicalcluster.New(tc.cluster)})
+			err := o.Validate(ctx, tc.attr, nil)
+			if tc.wantErr {
+				require.Error(t, err)
+				require.True(t, apierrors.IsForbidden(err), "expected forbidden, got %v", err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSkeleton(t *testing.T) {
+	tests := map[string]string{
+		"system":     "system",
+		"systern":    "system",
+		"vvorkspace": "workspace",
+		"clata":      "data",
+		"r00t":       "r00t",
+		"team-5":     "team-5",
+		"app-1":      "app-1",
+		"admin":      "admin",
+	}
+	for name, want := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, want, skeleton(name))
+		})
+	}
+}
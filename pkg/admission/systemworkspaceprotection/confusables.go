@@ -0,0 +1,32 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package systemworkspaceprotection
+
+import (
+	"strings"
+)
+
+// confusables are sequences of letters looking like a single letter.
+var confusables = strings.NewReplacer("rn", "m", "vv", "w", "cl", "d")
+
+// skeleton returns a normalized form of a workspace name, such that names looking alike
+// have the same skeleton. Workspace names are DNS labels, i.e. lower case ASCII letters,
+// digits and dashes. Digits and dashes are kept, as e.g. "team-5" and "teams" are different
+// names for users.
+func skeleton(name string) string {
+	return confusables.Replace(name)
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ttlpolicy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/authorization/delegated"
+)
+
+const (
+	PluginName = "apis.kcp.dev/TTLPolicy"
+)
+
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName,
+		func(_ io.Reader) (admission.Interface, error) {
+			return &ttlPolicyAdmission{
+				Handler:          admission.NewHandler(admission.Create, admission.Update),
+				createAuthorizer: delegated.NewDelegatedAuthorizer,
+			}, nil
+		})
+}
+
+// ttlPolicyAdmission checks that the user creating a TTLPolicy, or changing its resource, can
+// list and delete the resource in the workspace, such that nobody can have kcp delete objects
+// which they cannot delete themselves. It records the creator in an immutable annotation, for
+// the controller to delete objects only while the creator can still list and delete them.
+type ttlPolicyAdmission struct {
+	*admission.Handler
+	kubeClusterClient *kubernetes.Cluster
+
+	createAuthorizer delegated.DelegatedAuthorizerFactory
+}
+
+// Ensure that the required admission interfaces are implemented.
+var _ = admission.MutationInterface(&ttlPolicyAdmission{})
+var _ = admission.ValidationInterface(&ttlPolicyAdmission{})
+var _ = admission.InitializationValidator(&ttlPolicyAdmission{})
+
+// Admit records the creator of new TTLPolicies.
+func (o *ttlPolicyAdmission) Admit(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
+	if a.GetResource().GroupResource() != apisv1alpha1.Resource("ttlpolicies") {
+		return nil
+	}
+	if a.GetOperation() != admission.Create || a.GetSubresource() != "" {
+		return nil
+	}
+
+	u, ok := a.GetObject().(*unstructured.Unstructured)
+	if !ok {
+		return fmt.Errorf("unexpected type %T", a.GetObject())
+	}
+	creator, err := delegated.EncodeUser(a.GetUserInfo())
+	if err != nil {
+		return apierrors.NewInternalError(fmt.Errorf("failed to encode the creator of the TTLPolicy: %w", err))
+	}
+	annotations := u.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[apisv1alpha1.TTLPolicyCreatorAnnotation] = creator
+	u.SetAnnotations(annotations)
+
+	return nil
+}
+
+func (o *ttlPolicyAdmission) Validate(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
+	if a.GetResource().GroupResource() != apisv1alpha1.Resource("ttlpolicies") {
+		return nil
+	}
+	if a.GetSubresource() != "" {
+		return nil
+	}
+
+	policy, err := toTTLPolicy(a.GetObject())
+	if err != nil {
+		return err
+	}
+	if a.GetOperation() == admission.Update {
+		old, err := toTTLPolicy(a.GetOldObject())
+		if err != nil {
+			return err
+		}
+		if creator := policy.Annotations[apisv1alpha1.TTLPolicyCreatorAnnotation]; creator != old.Annotations[apisv1alpha1.TTLPolicyCreatorAnnotation] {
+			return admission.NewForbidden(a, field.Invalid(field.NewPath("metadata", "annotations").Key(apisv1alpha1.TTLPolicyCreatorAnnotation), creator, "field is immutable"))
+		}
+		if policy.Spec.Group == old.Spec.Group && policy.Spec.Resource == old.Spec.Resource {
+			return nil
+		}
+	}
+
+	cluster, err := genericapirequest.ValidClusterFrom(ctx)
+	if err != nil {
+		return admission.NewForbidden(a, fmt.Errorf("error determining workspace: %w", err))
+	}
+
+	gr := schema.GroupResource{Group: policy.Spec.Group, Resource: policy.Spec.Resource}
+	if err := o.checkAccess(ctx, a.GetUserInfo(), cluster.Name, gr); err != nil {
+		return admission.NewForbidden(a, fmt.Errorf("unable to expire %s: %w", gr, err))
+	}
+
+	return nil
+}
+
+func toTTLPolicy(obj runtime.Object) (*apisv1alpha1.TTLPolicy, error) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("unexpected type %T", obj)
+	}
+	policy := &apisv1alpha1.TTLPolicy{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, policy); err != nil {
+		return nil, fmt.Errorf("failed to convert unstructured to TTLPolicy: %w", err)
+	}
+	return policy, nil
+}
+
+// checkAccess checks that the user can list and delete the resource in all namespaces of the
+// workspace, as the controller does.
+func (o *ttlPolicyAdmission) checkAccess(ctx context.Context, user user.Info, clusterName logicalcluster.Name, gr schema.GroupResource) error {
+	authz, err := o.createAuthorizer(clusterName, o.kubeClusterClient)
+	if err != nil {
+		// Logging a more specific error for the operator
+		klog.Errorf("error creating authorizer from delegating authorizer config: %v", err)
+		// Returning a less specific error to the end user
+		return errors.New("unable to authorize request")
+	}
+
+	for _, verb := range []string{"list", "delete"} {
+		attr := authorizer.AttributesRecord{
+			User:            user,
+			Verb:            verb,
+			APIGroup:        gr.Group,
+			Resource:        gr.Resource,
+			ResourceRequest: true,
+		}
+		if decision, _, err := authz.Authorize(ctx, attr); err != nil {
+			return fmt.Errorf("unable to determine access to %s: %w", gr, err)
+		} else if decision != authorizer.DecisionAllow {
+			return fmt.Errorf("missing verb=%q permission on %s", verb, gr)
+		}
+	}
+
+	return nil
+}
+
+// ValidateInitialization ensures the required injected fields are set.
+func (o *ttlPolicyAdmission) ValidateInitialization() error {
+	if o.kubeClusterClient == nil {
+		return fmt.Errorf(PluginName + " plugin needs a Kubernetes ClusterInterface")
+	}
+
+	return nil
+}
+
+// SetKubeClusterClient is an admission plugin initializer function that injects a Kubernetes cluster client into
+// this admission plugin.
+func (o *ttlPolicyAdmission) SetKubeClusterClient(clusterClient *kubernetes.Cluster) {
+	o.kubeClusterClient = clusterClient
+}
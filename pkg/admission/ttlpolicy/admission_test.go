@@ -0,0 +1,196 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ttlpolicy
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kcp-dev/kcp/pkg/admission/helpers"
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/authorization/delegated"
+)
+
+func ttlPolicyAttr(op admission.Operation, policy, old *apisv1alpha1.TTLPolicy) admission.Attributes {
+	var obj, oldObj runtime.Object
+	if policy != nil {
+		obj = helpers.ToUnstructuredOrDie(policy)
+	}
+	if old != nil {
+		oldObj = helpers.ToUnstructuredOrDie(old)
+	}
+	return admission.NewAttributesRecord(
+		obj,
+		oldObj,
+		apisv1alpha1.Kind("TTLPolicy").WithVersion("v1alpha1"),
+		"",
+		"cleanup",
+		apisv1alpha1.Resource("ttlpolicies").WithVersion("v1alpha1"),
+		"",
+		op,
+		&metav1.CreateOptions{},
+		false,
+		&user.DefaultInfo{Name: "alice"},
+	)
+}
+
+func newTTLPolicy(group, resource string, ttl time.Duration) *apisv1alpha1.TTLPolicy {
+	return &apisv1alpha1.TTLPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "cleanup"},
+		Spec: apisv1alpha1.TTLPolicySpec{
+			Group:    group,
+			Resource: resource,
+			TTL:      metav1.Duration{Duration: ttl},
+		},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name               string
+		attr               admission.Attributes
+		allowedVerbs       []string
+		authzError         error
+		expectedErrors     []string
+		expectedAuthorized string
+	}{
+		{
+			name:               "Create: passes when the resource can be listed and deleted",
+			attr:               ttlPolicyAttr(admission.Create, newTTLPolicy("batch", "jobs", time.Hour), nil),
+			allowedVerbs:       []string{"list", "delete"},
+			expectedAuthorized: "root:org:ws",
+		},
+		{
+			name:               "Create: fails when the resource cannot be deleted",
+			attr:               ttlPolicyAttr(admission.Create, newTTLPolicy("batch", "jobs", time.Hour), nil),
+			allowedVerbs:       []string{"list"},
+			expectedErrors:     []string{`missing verb="delete" permission on jobs.batch`},
+			expectedAuthorized: "root:org:ws",
+		},
+		{
+			name:               "Create: fails when the resource cannot be listed",
+			attr:               ttlPolicyAttr(admission.Create, newTTLPolicy("", "secrets", time.Hour), nil),
+			allowedVerbs:       []string{"delete"},
+			expectedErrors:     []string{`missing verb="list" permission on secrets`},
+			expectedAuthorized: "root:org:ws",
+		},
+		{
+			name:               "Create: fails when there's an error checking authorization",
+			attr:               ttlPolicyAttr(admission.Create, newTTLPolicy("batch", "jobs", time.Hour), nil),
+			authzError:         errors.New("some error here"),
+			expectedErrors:     []string{"unable to determine access to jobs.batch: some error here"},
+			expectedAuthorized: "root:org:ws",
+		},
+		{
+			name: "Update: changed ttl passes without check",
+			attr: ttlPolicyAttr(admission.Update, newTTLPolicy("batch", "jobs", 2*time.Hour), newTTLPolicy("batch", "jobs", time.Hour)),
+		},
+		{
+			name:               "Update: changed resource fails when it cannot be deleted",
+			attr:               ttlPolicyAttr(admission.Update, newTTLPolicy("", "secrets", time.Hour), newTTLPolicy("batch", "jobs", time.Hour)),
+			allowedVerbs:       []string{"list"},
+			expectedErrors:     []string{`missing verb="delete" permission on secrets`},
+			expectedAuthorized: "root:org:ws",
+		},
+		{
+			name: "Update: changed creator fails",
+			attr: ttlPolicyAttr(admission.Update, func() *apisv1alpha1.TTLPolicy {
+				p := newTTLPolicy("batch", "jobs", time.Hour)
+				p.Annotations = map[string]string{apisv1alpha1.TTLPolicyCreatorAnnotation: `{"username":"admin"}`}
+				return p
+			}(), newTTLPolicy("batch", "jobs", time.Hour)),
+			expectedErrors: []string{"apis.kcp.dev/ttlpolicy-creator"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var authorized string
+			o := &ttlPolicyAdmission{
+				Handler: admission.NewHandler(admission.Create, admission.Update),
+				createAuthorizer: func(clusterName logicalcluster.Name, client kubernetes.ClusterInterface) (authorizer.Authorizer, error) {
+					authorized = clusterName.String()
+					return authorizer.AuthorizerFunc(func(ctx context.Context, attr authorizer.Attributes) (authorizer.Decision, string, error) {
+						require.Equal(t, "alice", attr.GetUser().GetName())
+						require.Empty(t, attr.GetNamespace())
+						if tc.authzError != nil {
+							return authorizer.DecisionNoOpinion, "", tc.authzError
+						}
+						for _, verb := range tc.allowedVerbs {
+							if verb == attr.GetVerb() {
+								return authorizer.DecisionAllow, "", nil
+							}
+						}
+						return authorizer.DecisionNoOpinion, "", nil
+					}), nil
+				},
+			}
+
+			ctx := request.WithCluster(context.Background(), request.Cluster{Name: logicalcluster.New("root:org:ws")})
+
+			err := o.Validate(ctx, tc.attr, nil)
+
+			wantErr := len(tc.expectedErrors) > 0
+			require.Equal(t, wantErr, err != nil, "unexpected error: %v", err)
+			for _, expected := range tc.expectedErrors {
+				require.Contains(t, err.Error(), expected)
+			}
+			require.Equal(t, tc.expectedAuthorized, authorized)
+		})
+	}
+}
+
+func TestAdmit(t *testing.T) {
+	policy := newTTLPolicy("batch", "jobs", time.Hour)
+	policy.Annotations = map[string]string{apisv1alpha1.TTLPolicyCreatorAnnotation: `{"username":"admin","groups":["system:masters"]}`}
+	attr := admission.NewAttributesRecord(
+		helpers.ToUnstructuredOrDie(policy),
+		nil,
+		apisv1alpha1.Kind("TTLPolicy").WithVersion("v1alpha1"),
+		"",
+		"cleanup",
+		apisv1alpha1.Resource("ttlpolicies").WithVersion("v1alpha1"),
+		"",
+		admission.Create,
+		&metav1.CreateOptions{},
+		false,
+		&user.DefaultInfo{Name: "alice", Groups: []string{"team-admins"}},
+	)
+
+	o := &ttlPolicyAdmission{Handler: admission.NewHandler(admission.Create, admission.Update)}
+	require.NoError(t, o.Admit(context.Background(), attr, nil))
+
+	annotations := attr.GetObject().(*unstructured.Unstructured).GetAnnotations()
+	creator, err := delegated.DecodeUser(annotations[apisv1alpha1.TTLPolicyCreatorAnnotation])
+	require.NoError(t, err)
+	require.Equal(t, "alice", creator.GetName())
+	require.Equal(t, []string{"team-admins"}, creator.GetGroups())
+}
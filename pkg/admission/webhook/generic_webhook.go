@@ -28,6 +28,7 @@ import (
 	"k8s.io/apiserver/pkg/admission/plugin/webhook"
 	"k8s.io/apiserver/pkg/admission/plugin/webhook/generic"
 	"k8s.io/apiserver/pkg/admission/plugin/webhook/rules"
+	"k8s.io/apiserver/pkg/authentication/user"
 	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
@@ -39,6 +40,11 @@ import (
 
 const byWorkspaceIndex = "webhookDispatcher-byWorkspace"
 
+// ClusterNameUserExtraKey is the key of the user extra in the AdmissionReview sent to webhooks
+// that holds the logical cluster of the request. Webhooks registered in an APIExport workspace are
+// called for the bound resources in all consuming workspaces, and use it to tell them apart.
+const ClusterNameUserExtraKey = "webhook.kcp.dev/cluster-name"
+
 var _ initializers.WantsKcpInformers = &WebhookDispatcher{}
 
 type WebhookDispatcher struct {
@@ -85,7 +91,32 @@ func (p *WebhookDispatcher) Dispatch(ctx context.Context, attr admission.Attribu
 		klog.V(3).Infof("restricting call to hooks in cluster: %v", lcluster)
 	}
 
-	return p.dispatcher.Dispatch(ctx, attr, o, whAccessor)
+	return p.dispatcher.Dispatch(ctx, &clusterAwareAttributes{Attributes: attr, clusterName: lcluster}, o, whAccessor)
+}
+
+// clusterAwareAttributes adds the logical cluster of the request to the user extra, such that
+// it ends up in the AdmissionReview.
+type clusterAwareAttributes struct {
+	admission.Attributes
+	clusterName logicalcluster.Name
+}
+
+func (a *clusterAwareAttributes) GetUserInfo() user.Info {
+	info := a.Attributes.GetUserInfo()
+	if info == nil {
+		return nil
+	}
+	extra := make(map[string][]string, len(info.GetExtra())+1)
+	for k, v := range info.GetExtra() {
+		extra[k] = v
+	}
+	extra[ClusterNameUserExtraKey] = []string{a.clusterName.String()}
+	return &user.DefaultInfo{
+		Name:   info.GetName(),
+		UID:    info.GetUID(),
+		Groups: info.GetGroups(),
+		Extra:  extra,
+	}
 }
 
 func (p *WebhookDispatcher) getAPIBindingWorkspace(attr admission.Attributes, clusterName logicalcluster.Name) (logicalcluster.Name, bool, error) {
@@ -78,6 +78,13 @@ func (d *validatingDispatcher) Dispatch(ctx context.Context, a admission.Attribu
 	if len(uidMatches) != len(d.hooks) {
 		return fmt.Errorf("hooks UID did not match expected")
 	}
+	clusterName, err := request.ClusterNameFrom(ctx)
+	if err != nil {
+		return err
+	}
+	if got := a.GetUserInfo().GetExtra()[ClusterNameUserExtraKey]; len(got) != 1 || got[0] != clusterName.String() {
+		return fmt.Errorf("expected cluster %q in user extra, got %v", clusterName, got)
+	}
 	return nil
 }
 
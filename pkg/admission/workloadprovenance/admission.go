@@ -0,0 +1,98 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workloadprovenance
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apiserver/pkg/admission"
+
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+)
+
+const (
+	PluginName = "workload.kcp.dev/Provenance"
+)
+
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName,
+		func(_ io.Reader) (admission.Interface, error) {
+			return &workloadProvenance{
+				Handler: admission.NewHandler(admission.Create, admission.Update),
+			}, nil
+		})
+}
+
+// workloadProvenance records the provenance annotations of objects, i.e. references to SBOMs,
+// attestations and other supply-chain metadata, as audit annotations of the requests writing
+// them, together with the workload clusters the objects are synced to. Supply-chain tooling can
+// then trace from the audit log what was shipped to which workload cluster, and when.
+type workloadProvenance struct {
+	*admission.Handler
+}
+
+// Ensure that the required admission interfaces are implemented.
+var _ = admission.ValidationInterface(&workloadProvenance{})
+
+// Validate adds the provenance annotations of the object as audit annotations.
+func (o *workloadProvenance) Validate(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
+	if a.GetObject() == nil {
+		return nil
+	}
+	obj, err := meta.Accessor(a.GetObject())
+	if err != nil {
+		return nil // not an object with metadata
+	}
+
+	found := false
+	for key, value := range obj.GetAnnotations() {
+		if !strings.HasPrefix(key, workloadv1alpha1.ProvenanceAnnotationPrefix) {
+			continue
+		}
+		if key == workloadv1alpha1.ProvenanceSourceAnnotation || key == workloadv1alpha1.ProvenanceClustersAuditAnnotation {
+			continue // set by kcp
+		}
+		if err := a.AddAnnotation(key, value); err != nil {
+			return apierrors.NewInternalError(fmt.Errorf("failed to add audit annotation %q: %w", key, err))
+		}
+		found = true
+	}
+	if !found {
+		return nil
+	}
+
+	var clusters []string
+	for key := range obj.GetLabels() {
+		if strings.HasPrefix(key, workloadv1alpha1.InternalClusterResourceStateLabelPrefix) {
+			clusters = append(clusters, strings.TrimPrefix(key, workloadv1alpha1.InternalClusterResourceStateLabelPrefix))
+		}
+	}
+	if len(clusters) > 0 {
+		sort.Strings(clusters)
+		if err := a.AddAnnotation(workloadv1alpha1.ProvenanceClustersAuditAnnotation, strings.Join(clusters, ",")); err != nil {
+			return apierrors.NewInternalError(fmt.Errorf("failed to add audit annotation %q: %w", workloadv1alpha1.ProvenanceClustersAuditAnnotation, err))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workloadprovenance
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/admission"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	"k8s.io/apiserver/pkg/authentication/user"
+
+	"github.com/kcp-dev/kcp/pkg/admission/helpers"
+)
+
+func createAttr(obj *appsv1.Deployment) admission.Attributes {
+	return admission.NewAttributesRecord(
+		helpers.ToUnstructuredOrDie(obj),
+		nil,
+		appsv1.SchemeGroupVersion.WithKind("Deployment"),
+		obj.Namespace,
+		obj.Name,
+		appsv1.SchemeGroupVersion.WithResource("deployments"),
+		"",
+		admission.Create,
+		&metav1.CreateOptions{},
+		false,
+		&user.DefaultInfo{Name: "user"},
+	)
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		labels      map[string]string
+		annotations map[string]string
+		want        map[string]string
+	}{
+		{
+			name:        "object without provenance",
+			labels:      map[string]string{"state.internal.workloads.kcp.dev/us-west1": "Sync"},
+			annotations: map[string]string{"a": "b"},
+		},
+		{
+			name: "provenance and workload clusters are recorded",
+			labels: map[string]string{
+				"state.internal.workloads.kcp.dev/us-west1": "Sync",
+				"state.internal.workloads.kcp.dev/eu-west1": "",
+			},
+			annotations: map[string]string{
+				"a":                                 "b",
+				"provenance.workloads.kcp.dev/sbom": "https://sbom.example.com/app",
+				"provenance.workloads.kcp.dev/attestation": "quay.io/org/app@sha256:abc",
+			},
+			want: map[string]string{
+				"provenance.workloads.kcp.dev/sbom":        "https://sbom.example.com/app",
+				"provenance.workloads.kcp.dev/attestation": "quay.io/org/app@sha256:abc",
+				"provenance.workloads.kcp.dev/clusters":    "eu-west1,us-west1",
+			},
+		},
+		{
+			name: "annotations reserved for kcp are ignored",
+			annotations: map[string]string{
+				"provenance.workloads.kcp.dev/sbom":     "https://sbom.example.com/app",
+				"provenance.workloads.kcp.dev/source":   "spoofed",
+				"provenance.workloads.kcp.dev/clusters": "spoofed",
+			},
+			want: map[string]string{
+				"provenance.workloads.kcp.dev/sbom": "https://sbom.example.com/app",
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o := &workloadProvenance{Handler: admission.NewHandler(admission.Create, admission.Update)}
+			attr := createAttr(&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+				Name:        "app",
+				Namespace:   "default",
+				Labels:      tc.labels,
+				Annotations: tc.annotations,
+			}})
+
+			err := o.Validate(context.Background(), attr, nil)
+			require.NoError(t, err)
+
+			got := attr.(admission.AnnotationsGetter).GetAnnotations(auditinternal.LevelMetadata)
+			if len(tc.want) == 0 {
+				require.Empty(t, got)
+			} else {
+				require.Equal(t, tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacefreeze
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/tools/clusters"
+	"sigs.k8s.io/yaml"
+
+	kcpinitializers "github.com/kcp-dev/kcp/pkg/admission/initializers"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	kcpinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
+)
+
+const (
+	PluginName = "tenancy.kcp.dev/WorkspaceFreeze"
+)
+
+// reviewGroups are API groups whose create requests only review, but do not store anything.
+var reviewGroups = sets.NewString("authentication.k8s.io", "authorization.k8s.io")
+
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName,
+		func(config io.Reader) (admission.Interface, error) {
+			cfg, err := loadConfiguration(config)
+			if err != nil {
+				return nil, err
+			}
+			return &workspaceFreeze{
+				Handler:          admission.NewHandler(admission.Create, admission.Update, admission.Delete, admission.Connect),
+				breakGlassGroups: sets.NewString(cfg.BreakGlassGroups...),
+			}, nil
+		})
+}
+
+// Configuration is the configuration of the plugin in the admission control configuration file.
+type Configuration struct {
+	// BreakGlassGroups are the groups whose members can write in read-only workspaces.
+	// They default to system:kcp:break-glass.
+	BreakGlassGroups []string `json:"breakGlassGroups,omitempty"`
+}
+
+func loadConfiguration(config io.Reader) (*Configuration, error) {
+	cfg := &Configuration{}
+	if config != nil {
+		bs, err := ioutil.ReadAll(config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s configuration: %w", PluginName, err)
+		}
+		if err := yaml.Unmarshal(bs, cfg); err != nil {
+			return nil, fmt.Errorf("failed to decode %s configuration: %w", PluginName, err)
+		}
+	}
+	if len(cfg.BreakGlassGroups) == 0 {
+		cfg.BreakGlassGroups = []string{tenancyv1alpha1.BreakGlassGroup}
+	}
+	for i, group := range cfg.BreakGlassGroups {
+		if group == "" {
+			return nil, fmt.Errorf("invalid %s configuration: breakGlassGroups[%d] must not be empty", PluginName, i)
+		}
+	}
+	return cfg, nil
+}
+
+// workspaceFreeze rejects all writes in workspaces with spec.readOnly set on their
+// ClusterWorkspace. Members of system:masters, e.g. kcp itself, and of the
+// configured break-glass groups are exempted.
+type workspaceFreeze struct {
+	*admission.Handler
+
+	breakGlassGroups sets.String
+
+	getClusterWorkspace func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error)
+}
+
+// Ensure that the required admission interfaces are implemented.
+var _ = admission.ValidationInterface(&workspaceFreeze{})
+var _ = admission.InitializationValidator(&workspaceFreeze{})
+var _ = kcpinitializers.WantsKcpInformers(&workspaceFreeze{})
+
+// Validate rejects writes in read-only workspaces.
+func (o *workspaceFreeze) Validate(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
+	if a.GetOperation() == admission.Create && reviewGroups.Has(a.GetResource().Group) {
+		return nil
+	}
+	if userInfo := a.GetUserInfo(); userInfo != nil {
+		for _, group := range userInfo.GetGroups() {
+			if group == user.SystemPrivilegedGroup || o.breakGlassGroups.Has(group) {
+				return nil
+			}
+		}
+	}
+
+	clusterName, err := genericapirequest.ClusterNameFrom(ctx)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	parent, name := clusterName.Split()
+	if parent.Empty() {
+		return nil // root cannot be frozen
+	}
+
+	if !o.WaitForReady() {
+		return admission.NewForbidden(a, fmt.Errorf("not yet ready to handle request"))
+	}
+
+	ws, err := o.getClusterWorkspace(parent, name)
+	if apierrors.IsNotFound(err) {
+		return nil // not a ClusterWorkspace based logical cluster
+	} else if err != nil {
+		return admission.NewForbidden(a, err)
+	}
+	if ws.Spec.ReadOnly {
+		return admission.NewForbidden(a, fmt.Errorf("workspace %s is read-only", clusterName))
+	}
+
+	return nil
+}
+
+func (o *workspaceFreeze) ValidateInitialization() error {
+	if o.getClusterWorkspace == nil {
+		return fmt.Errorf(PluginName + " plugin needs a ClusterWorkspace lister")
+	}
+	return nil
+}
+
+func (o *workspaceFreeze) SetKcpInformers(informers kcpinformers.SharedInformerFactory) {
+	o.SetReadyFunc(informers.Tenancy().V1alpha1().ClusterWorkspaces().Informer().HasSynced)
+
+	workspaceLister := informers.Tenancy().V1alpha1().ClusterWorkspaces().Lister()
+	o.getClusterWorkspace = func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error) {
+		return workspaceLister.Get(clusters.ToClusterAwareKey(clusterName, name))
+	}
+}
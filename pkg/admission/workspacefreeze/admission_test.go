@@ -0,0 +1,210 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacefreeze
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+func attr(op admission.Operation, gvr schema.GroupVersionResource, groups ...string) admission.Attributes {
+	return admission.NewAttributesRecord(
+		nil,
+		nil,
+		schema.GroupVersionKind{},
+		"default",
+		"name",
+		gvr,
+		"",
+		op,
+		nil,
+		false,
+		&user.DefaultInfo{Name: "user", Groups: groups},
+	)
+}
+
+func TestValidate(t *testing.T) {
+	configMaps := corev1.SchemeGroupVersion.WithResource("configmaps")
+
+	tests := []struct {
+		name     string
+		config   string
+		cluster  string
+		readOnly bool
+		attr     admission.Attributes
+		wantErr  bool
+	}{
+		{
+			name:    "writes in a workspace that is not read-only are admitted",
+			cluster: "root:org:ws",
+			attr:    attr(admission.Create, configMaps),
+		},
+		{
+			name:     "creation in a read-only workspace is rejected",
+			cluster:  "root:org:ws",
+			readOnly: true,
+			attr:     attr(admission.Create, configMaps),
+			wantErr:  true,
+		},
+		{
+			name:     "update in a read-only workspace is rejected",
+			cluster:  "root:org:ws",
+			readOnly: true,
+			attr:     attr(admission.Update, configMaps),
+			wantErr:  true,
+		},
+		{
+			name:     "deletion in a read-only workspace is rejected",
+			cluster:  "root:org:ws",
+			readOnly: true,
+			attr:     attr(admission.Delete, configMaps),
+			wantErr:  true,
+		},
+		{
+			name:     "break-glass group is exempted",
+			cluster:  "root:org:ws",
+			readOnly: true,
+			attr:     attr(admission.Update, configMaps, tenancyv1alpha1.BreakGlassGroup),
+		},
+		{
+			name:     "configured break-glass group is exempted",
+			config:   "breakGlassGroups: [incident-responders, auditors]",
+			cluster:  "root:org:ws",
+			readOnly: true,
+			attr:     attr(admission.Update, configMaps, "incident-responders"),
+		},
+		{
+			name:     "groups that are not configured are not exempted",
+			config:   "breakGlassGroups: [incident-responders]",
+			cluster:  "root:org:ws",
+			readOnly: true,
+			attr:     attr(admission.Update, configMaps, "developers"),
+			wantErr:  true,
+		},
+		{
+			name:     "default break-glass group is not exempted if others are configured",
+			config:   "breakGlassGroups: [incident-responders]",
+			cluster:  "root:org:ws",
+			readOnly: true,
+			attr:     attr(admission.Update, configMaps, tenancyv1alpha1.BreakGlassGroup),
+			wantErr:  true,
+		},
+		{
+			name:     "privileged users are exempted with configured break-glass groups",
+			config:   "breakGlassGroups: [incident-responders]",
+			cluster:  "root:org:ws",
+			readOnly: true,
+			attr:     attr(admission.Delete, configMaps, user.SystemPrivilegedGroup),
+		},
+		{
+			name:     "privileged users are exempted",
+			cluster:  "root:org:ws",
+			readOnly: true,
+			attr:     attr(admission.Delete, configMaps, user.SystemPrivilegedGroup),
+		},
+		{
+			name:     "access reviews are admitted",
+			cluster:  "root:org:ws",
+			readOnly: true,
+			attr:     attr(admission.Create, authorizationv1.SchemeGroupVersion.WithResource("selfsubjectaccessreviews")),
+		},
+		{
+			name:     "root is not frozen",
+			cluster:  "root",
+			readOnly: true,
+			attr:     attr(admission.Create, configMaps),
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var config io.Reader
+			if tc.config != "" {
+				config = strings.NewReader(tc.config)
+			}
+			cfg, err := loadConfiguration(config)
+			require.NoError(t, err)
+
+			o := &workspaceFreeze{
+				Handler:          admission.NewHandler(admission.Create, admission.Update, admission.Delete, admission.Connect),
+				breakGlassGroups: sets.NewString(cfg.BreakGlassGroups...),
+				getClusterWorkspace: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error) {
+					require.Equal(t, "root:org", clusterName.String())
+					require.Equal(t, "ws", name)
+					return &tenancyv1alpha1.ClusterWorkspace{
+						ObjectMeta: metav1.ObjectMeta{Name: name, ClusterName: clusterName.String()},
+						Spec:       tenancyv1alpha1.ClusterWorkspaceSpec{ReadOnly: tc.readOnly},
+					}, nil
+				},
+			}
+
+			ctx := request.WithCluster(context.Background(), request.Cluster{Name: logicalcluster.New(tc.cluster)})
+			err = o.Validate(ctx, tc.attr, nil)
+			if tc.wantErr {
+				require.Error(t, err)
+				require.True(t, apierrors.IsForbidden(err), "expected forbidden, got %v", err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestConfiguration(t *testing.T) {
+	tests := map[string]struct {
+		config  string
+		want    []string
+		wantErr bool
+	}{
+		"empty":       {want: []string{tenancyv1alpha1.BreakGlassGroup}},
+		"no groups":   {config: "breakGlassGroups: []", want: []string{tenancyv1alpha1.BreakGlassGroup}},
+		"groups":      {config: "breakGlassGroups: [incident-responders, auditors]", want: []string{"incident-responders", "auditors"}},
+		"empty group": {config: `breakGlassGroups: [""]`, wantErr: true},
+		"invalid":     {config: "breakGlassGroups: incident-responders", wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := loadConfiguration(strings.NewReader(tt.config))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, cfg.BreakGlassGroups)
+		})
+	}
+
+	cfg, err := loadConfiguration(nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{tenancyv1alpha1.BreakGlassGroup}, cfg.BreakGlassGroups)
+}
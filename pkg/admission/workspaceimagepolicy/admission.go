@@ -0,0 +1,239 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspaceimagepolicy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/admission"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/tools/clusters"
+
+	kcpinitializers "github.com/kcp-dev/kcp/pkg/admission/initializers"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	kcpinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
+)
+
+const (
+	PluginName = "tenancy.kcp.dev/WorkspaceImagePolicy"
+)
+
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName,
+		func(_ io.Reader) (admission.Interface, error) {
+			return &workspaceImagePolicy{
+				Handler: admission.NewHandler(admission.Create, admission.Update),
+			}, nil
+		})
+}
+
+// podSpecPaths are the paths of the pod specs in the workload resources.
+var podSpecPaths = map[schema.GroupResource][]string{
+	{Group: "", Resource: "pods"}:             {"spec"},
+	{Group: "apps", Resource: "deployments"}:  {"spec", "template", "spec"},
+	{Group: "apps", Resource: "replicasets"}:  {"spec", "template", "spec"},
+	{Group: "apps", Resource: "statefulsets"}: {"spec", "template", "spec"},
+	{Group: "apps", Resource: "daemonsets"}:   {"spec", "template", "spec"},
+	{Group: "batch", Resource: "jobs"}:        {"spec", "template", "spec"},
+	{Group: "batch", Resource: "cronjobs"}:    {"spec", "jobTemplate", "spec", "template", "spec"},
+}
+
+// workspaceImagePolicy rejects workloads with images not allowed by the image policy of their
+// ClusterWorkspace, or of its ClusterWorkspaceType, before they are synced to workload clusters.
+// On update, only images that were not in the old object are checked, such that existing
+// workloads can still be updated, e.g. by syncers, after the policy changed.
+type workspaceImagePolicy struct {
+	*admission.Handler
+
+	getClusterWorkspace     func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error)
+	getClusterWorkspaceType func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspaceType, error)
+}
+
+// Ensure that the required admission interfaces are implemented.
+var _ = admission.ValidationInterface(&workspaceImagePolicy{})
+var _ = admission.InitializationValidator(&workspaceImagePolicy{})
+var _ = kcpinitializers.WantsKcpInformers(&workspaceImagePolicy{})
+
+// Validate checks the images of workloads against the image policy of the workspace.
+func (o *workspaceImagePolicy) Validate(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
+	path, ok := podSpecPaths[a.GetResource().GroupResource()]
+	if !ok || a.GetSubresource() != "" {
+		return nil
+	}
+
+	clusterName, err := genericapirequest.ClusterNameFrom(ctx)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	parent, name := clusterName.Split()
+	if parent.Empty() {
+		return nil // root has no policy
+	}
+
+	if !o.WaitForReady() {
+		return admission.NewForbidden(a, fmt.Errorf("not yet ready to handle request"))
+	}
+
+	workspace, err := o.getClusterWorkspace(parent, name)
+	if apierrors.IsNotFound(err) {
+		return nil // not a ClusterWorkspace based logical cluster
+	} else if err != nil {
+		return admission.NewForbidden(a, err)
+	}
+	policy := workspace.Spec.ImagePolicy
+	if policy == nil {
+		cwt, err := o.getClusterWorkspaceType(parent, strings.ToLower(workspace.Spec.Type))
+		if err != nil && !apierrors.IsNotFound(err) {
+			return admission.NewForbidden(a, err)
+		} else if err == nil {
+			policy = cwt.Spec.ImagePolicy
+		}
+	}
+	if policy == nil {
+		return nil
+	}
+
+	images, err := podImages(a.GetObject(), path)
+	if err != nil {
+		return admission.NewForbidden(a, err)
+	}
+	oldImages := sets.NewString()
+	if a.GetOperation() == admission.Update && a.GetOldObject() != nil {
+		if oldImages, err = podImages(a.GetOldObject(), path); err != nil {
+			return admission.NewForbidden(a, err)
+		}
+	}
+
+	var errs []string
+	for _, image := range images.List() {
+		if oldImages.Has(image) {
+			continue
+		}
+		if err := Allowed(policy, image); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return admission.NewForbidden(a, fmt.Errorf("image policy of workspace %s violated: %s", clusterName, strings.Join(errs, ", ")))
+	}
+
+	return nil
+}
+
+// Allowed returns an error if the image is not allowed by the policy.
+func Allowed(policy *tenancyv1alpha1.ImagePolicy, image string) error {
+	repository, digest := parseImage(image)
+	if policy.RequireDigest && !digest {
+		return fmt.Errorf("image %q is not pinned by digest", image)
+	}
+	for _, allowed := range policy.AllowedImages {
+		if strings.HasSuffix(allowed, "*") {
+			if strings.HasPrefix(repository, strings.TrimSuffix(allowed, "*")) {
+				return nil
+			}
+		} else if repository == allowed {
+			return nil
+		}
+	}
+	return fmt.Errorf("image %q is not allowed", image)
+}
+
+// parseImage returns the fully qualified repository of the image, e.g. "docker.io/library/nginx"
+// for "nginx:1.23", and whether the image is pinned by digest.
+func parseImage(image string) (repository string, digest bool) {
+	repository = image
+	if i := strings.Index(repository, "@"); i >= 0 {
+		repository, digest = repository[:i], true
+	}
+	if i := strings.LastIndex(repository, ":"); i >= 0 && !strings.Contains(repository[i:], "/") {
+		repository = repository[:i]
+	}
+
+	parts := strings.SplitN(repository, "/", 2)
+	switch {
+	case len(parts) == 1:
+		repository = "docker.io/library/" + repository
+	case !strings.ContainsAny(parts[0], ".:") && parts[0] != "localhost":
+		repository = "docker.io/" + repository
+	}
+	return repository, digest
+}
+
+// podImages returns the images of all containers of the pod spec at the given path of the object.
+func podImages(obj runtime.Object, path []string) (sets.String, error) {
+	var content map[string]interface{}
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		content = u.Object
+	} else {
+		var err error
+		if content, err = runtime.DefaultUnstructuredConverter.ToUnstructured(obj); err != nil {
+			return nil, err
+		}
+	}
+
+	images := sets.NewString()
+	for _, field := range []string{"containers", "initContainers", "ephemeralContainers"} {
+		containers, _, err := unstructured.NestedSlice(content, append(append([]string{}, path...), field)...)
+		if err != nil {
+			return nil, err
+		}
+		for _, container := range containers {
+			c, ok := container.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if image, ok := c["image"].(string); ok && image != "" {
+				images.Insert(image)
+			}
+		}
+	}
+	return images, nil
+}
+
+func (o *workspaceImagePolicy) ValidateInitialization() error {
+	if o.getClusterWorkspace == nil {
+		return fmt.Errorf(PluginName + " plugin needs a ClusterWorkspace lister")
+	}
+	return nil
+}
+
+func (o *workspaceImagePolicy) SetKcpInformers(informers kcpinformers.SharedInformerFactory) {
+	workspacesReady := informers.Tenancy().V1alpha1().ClusterWorkspaces().Informer().HasSynced
+	typesReady := informers.Tenancy().V1alpha1().ClusterWorkspaceTypes().Informer().HasSynced
+	o.SetReadyFunc(func() bool {
+		return workspacesReady() && typesReady()
+	})
+
+	workspaceLister := informers.Tenancy().V1alpha1().ClusterWorkspaces().Lister()
+	o.getClusterWorkspace = func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error) {
+		return workspaceLister.Get(clusters.ToClusterAwareKey(clusterName, name))
+	}
+	typeLister := informers.Tenancy().V1alpha1().ClusterWorkspaceTypes().Lister()
+	o.getClusterWorkspaceType = func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspaceType, error) {
+		return typeLister.Get(clusters.ToClusterAwareKey(clusterName, name))
+	}
+}
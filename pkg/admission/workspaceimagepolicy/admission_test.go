@@ -0,0 +1,192 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspaceimagepolicy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	"github.com/kcp-dev/kcp/pkg/admission/helpers"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+func deployment(images ...string) *appsv1.Deployment {
+	d := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+	for _, image := range images {
+		d.Spec.Template.Spec.Containers = append(d.Spec.Template.Spec.Containers, corev1.Container{Name: "c", Image: image})
+	}
+	return d
+}
+
+func createAttr(obj, old *appsv1.Deployment) admission.Attributes {
+	op := admission.Create
+	var oldObj runtime.Object
+	if old != nil {
+		op = admission.Update
+		oldObj = helpers.ToUnstructuredOrDie(old)
+	}
+	return admission.NewAttributesRecord(
+		helpers.ToUnstructuredOrDie(obj),
+		oldObj,
+		appsv1.SchemeGroupVersion.WithKind("Deployment"),
+		obj.Namespace,
+		obj.Name,
+		appsv1.SchemeGroupVersion.WithResource("deployments"),
+		"",
+		op,
+		nil,
+		false,
+		&user.DefaultInfo{Name: "user"},
+	)
+}
+
+func TestValidate(t *testing.T) {
+	typePolicy := &tenancyv1alpha1.ImagePolicy{AllowedImages: []string{"quay.io/org/*", "docker.io/library/nginx"}}
+
+	tests := []struct {
+		name            string
+		cluster         string
+		workspacePolicy *tenancyv1alpha1.ImagePolicy
+		typePolicy      *tenancyv1alpha1.ImagePolicy
+		obj, old        *appsv1.Deployment
+		wantErr         string
+	}{
+		{
+			name:       "allowed images",
+			cluster:    "root:org:ws",
+			typePolicy: typePolicy,
+			obj:        deployment("quay.io/org/app:v1", "nginx:1.23"),
+		},
+		{
+			name:       "image not allowed",
+			cluster:    "root:org:ws",
+			typePolicy: typePolicy,
+			obj:        deployment("quay.io/org/app:v1", "quay.io/other/app"),
+			wantErr:    `image "quay.io/other/app" is not allowed`,
+		},
+		{
+			name:            "policy of the workspace replaces the one of the type",
+			cluster:         "root:org:ws",
+			workspacePolicy: &tenancyv1alpha1.ImagePolicy{AllowedImages: []string{"registry.example.com/*"}},
+			typePolicy:      typePolicy,
+			obj:             deployment("nginx"),
+			wantErr:         `image "nginx" is not allowed`,
+		},
+		{
+			name:            "digest required",
+			cluster:         "root:org:ws",
+			workspacePolicy: &tenancyv1alpha1.ImagePolicy{AllowedImages: []string{"quay.io/org/*"}, RequireDigest: true},
+			obj:             deployment("quay.io/org/app:v1"),
+			wantErr:         `image "quay.io/org/app:v1" is not pinned by digest`,
+		},
+		{
+			name:       "unchanged images are not checked on update",
+			cluster:    "root:org:ws",
+			typePolicy: typePolicy,
+			obj:        deployment("quay.io/other/app", "quay.io/org/app:v2"),
+			old:        deployment("quay.io/other/app", "quay.io/org/app:v1"),
+		},
+		{
+			name:       "new images are checked on update",
+			cluster:    "root:org:ws",
+			typePolicy: typePolicy,
+			obj:        deployment("quay.io/other/app:v2"),
+			old:        deployment("quay.io/other/app:v1"),
+			wantErr:    `image "quay.io/other/app:v2" is not allowed`,
+		},
+		{
+			name:    "workspace without policy",
+			cluster: "root:org:ws",
+			obj:     deployment("quay.io/other/app"),
+		},
+		{
+			name:       "root is not checked",
+			cluster:    "root",
+			typePolicy: typePolicy,
+			obj:        deployment("quay.io/other/app"),
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o := &workspaceImagePolicy{
+				Handler: admission.NewHandler(admission.Create, admission.Update),
+				getClusterWorkspace: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error) {
+					require.Equal(t, "root:org", clusterName.String())
+					require.Equal(t, "ws", name)
+					return &tenancyv1alpha1.ClusterWorkspace{
+						ObjectMeta: metav1.ObjectMeta{Name: name, ClusterName: clusterName.String()},
+						Spec:       tenancyv1alpha1.ClusterWorkspaceSpec{Type: "Team", ImagePolicy: tc.workspacePolicy},
+					}, nil
+				},
+				getClusterWorkspaceType: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspaceType, error) {
+					if name != "team" {
+						return nil, apierrors.NewNotFound(tenancyv1alpha1.Resource("clusterworkspacetypes"), name)
+					}
+					return &tenancyv1alpha1.ClusterWorkspaceType{
+						ObjectMeta: metav1.ObjectMeta{Name: name, ClusterName: clusterName.String()},
+						Spec:       tenancyv1alpha1.ClusterWorkspaceTypeSpec{ImagePolicy: tc.typePolicy},
+					}, nil
+				},
+			}
+
+			ctx := request.WithCluster(context.Background(), request.Cluster{Name: logicalcluster.New(tc.cluster)})
+			err := o.Validate(ctx, createAttr(tc.obj, tc.old), nil)
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				require.True(t, apierrors.IsForbidden(err))
+				require.Contains(t, err.Error(), tc.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestParseImage(t *testing.T) {
+	tests := []struct {
+		image          string
+		wantRepository string
+		wantDigest     bool
+	}{
+		{image: "nginx", wantRepository: "docker.io/library/nginx"},
+		{image: "nginx:1.23", wantRepository: "docker.io/library/nginx"},
+		{image: "org/app:v1", wantRepository: "docker.io/org/app"},
+		{image: "quay.io/org/app:v1", wantRepository: "quay.io/org/app"},
+		{image: "localhost/app", wantRepository: "localhost/app"},
+		{image: "registry:5000/app", wantRepository: "registry:5000/app"},
+		{image: "registry:5000/app:v1@sha256:abc", wantRepository: "registry:5000/app", wantDigest: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.image, func(t *testing.T) {
+			repository, digest := parseImage(tc.image)
+			require.Equal(t, tc.wantRepository, repository)
+			require.Equal(t, tc.wantDigest, digest)
+		})
+	}
+}
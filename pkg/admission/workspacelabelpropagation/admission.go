@@ -0,0 +1,138 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacelabelpropagation
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apiserver/pkg/admission"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/tools/clusters"
+
+	kcpinitializers "github.com/kcp-dev/kcp/pkg/admission/initializers"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	kcpinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
+	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/labelpropagation"
+)
+
+const (
+	PluginName = "tenancy.kcp.dev/WorkspaceLabelPropagation"
+)
+
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName,
+		func(_ io.Reader) (admission.Interface, error) {
+			return &workspaceLabelPropagation{
+				Handler: admission.NewHandler(admission.Create, admission.Update),
+			}, nil
+		})
+}
+
+// workspaceLabelPropagation sets the labels of a workspace selected by the label propagation
+// policy of its ClusterWorkspaceType, and the workload defaults of the workspace, on namespaces
+// created or updated in the workspace. This makes them visible from the start, and reverts
+// changes of propagated labels and workload defaults. Existing
+// namespaces are updated by the label propagation controller when the workspace or the policy
+// changes.
+type workspaceLabelPropagation struct {
+	*admission.Handler
+
+	getClusterWorkspace     func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error)
+	getClusterWorkspaceType func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspaceType, error)
+}
+
+// Ensure that the required admission interfaces are implemented.
+var _ = admission.MutationInterface(&workspaceLabelPropagation{})
+var _ = admission.InitializationValidator(&workspaceLabelPropagation{})
+var _ = kcpinitializers.WantsKcpInformers(&workspaceLabelPropagation{})
+
+// Admit sets the propagated labels on namespaces.
+func (o *workspaceLabelPropagation) Admit(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
+	if a.GetResource().GroupResource() != corev1.Resource("namespaces") || a.GetSubresource() != "" {
+		return nil
+	}
+
+	clusterName, err := genericapirequest.ClusterNameFrom(ctx)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	parent, name := clusterName.Split()
+	if parent.Empty() {
+		return nil // root has no type
+	}
+
+	if !o.WaitForReady() {
+		return admission.NewForbidden(a, fmt.Errorf("not yet ready to handle request"))
+	}
+
+	workspace, err := o.getClusterWorkspace(parent, name)
+	if apierrors.IsNotFound(err) {
+		return nil // not a ClusterWorkspace based logical cluster
+	} else if err != nil {
+		return admission.NewForbidden(a, err)
+	}
+	var policy *tenancyv1alpha1.ClusterWorkspaceLabelPropagation
+	cwt, err := o.getClusterWorkspaceType(parent, strings.ToLower(workspace.Spec.Type))
+	if err != nil && !apierrors.IsNotFound(err) {
+		return admission.NewForbidden(a, err)
+	} else if err == nil {
+		policy = cwt.Spec.LabelPropagation
+	}
+
+	ns, err := meta.Accessor(a.GetObject())
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	labelpropagation.Propagate(ns, workspace, policy)
+	if _, err := labelpropagation.PropagateWorkloadDefaults(ns, labelpropagation.WorkloadDefaultsFor(workspace, cwt)); err != nil {
+		return apierrors.NewInternalError(err)
+	}
+
+	return nil
+}
+
+func (o *workspaceLabelPropagation) ValidateInitialization() error {
+	if o.getClusterWorkspace == nil {
+		return fmt.Errorf(PluginName + " plugin needs a ClusterWorkspace lister")
+	}
+	return nil
+}
+
+func (o *workspaceLabelPropagation) SetKcpInformers(informers kcpinformers.SharedInformerFactory) {
+	workspacesReady := informers.Tenancy().V1alpha1().ClusterWorkspaces().Informer().HasSynced
+	typesReady := informers.Tenancy().V1alpha1().ClusterWorkspaceTypes().Informer().HasSynced
+	o.SetReadyFunc(func() bool {
+		return workspacesReady() && typesReady()
+	})
+
+	workspaceLister := informers.Tenancy().V1alpha1().ClusterWorkspaces().Lister()
+	o.getClusterWorkspace = func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error) {
+		return workspaceLister.Get(clusters.ToClusterAwareKey(clusterName, name))
+	}
+	typeLister := informers.Tenancy().V1alpha1().ClusterWorkspaceTypes().Lister()
+	o.getClusterWorkspaceType = func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspaceType, error) {
+		return typeLister.Get(clusters.ToClusterAwareKey(clusterName, name))
+	}
+}
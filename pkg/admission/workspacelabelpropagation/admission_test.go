@@ -0,0 +1,169 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacelabelpropagation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	"github.com/kcp-dev/kcp/pkg/admission/helpers"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+func createAttr(ns *corev1.Namespace) admission.Attributes {
+	return admission.NewAttributesRecord(
+		helpers.ToUnstructuredOrDie(ns),
+		nil,
+		corev1.SchemeGroupVersion.WithKind("Namespace"),
+		"",
+		ns.Name,
+		corev1.SchemeGroupVersion.WithResource("namespaces"),
+		"",
+		admission.Create,
+		&metav1.CreateOptions{},
+		false,
+		&user.DefaultInfo{Name: "user"},
+	)
+}
+
+func TestAdmit(t *testing.T) {
+	tests := []struct {
+		name            string
+		cluster         string
+		wsType          string
+		policy          *tenancyv1alpha1.ClusterWorkspaceLabelPropagation
+		defaults        *tenancyv1alpha1.WorkloadDefaults
+		ns              *corev1.Namespace
+		wantLabels      map[string]string
+		wantAnnotations map[string]string
+	}{
+		{
+			name:    "namespace in workspace without policy is unchanged",
+			cluster: "root:org:ws",
+			ns:      &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default", Labels: map[string]string{"a": "b"}}},
+			wantLabels: map[string]string{
+				"a": "b",
+			},
+		},
+		{
+			name:    "selected labels are propagated",
+			cluster: "root:org:ws",
+			policy:  &tenancyv1alpha1.ClusterWorkspaceLabelPropagation{Labels: []string{"cost-center", "missing"}},
+			ns:      &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default", Labels: map[string]string{"a": "b"}}},
+			wantLabels: map[string]string{
+				"a":           "b",
+				"cost-center": "42",
+			},
+			wantAnnotations: map[string]string{
+				tenancyv1alpha1.PropagatedLabelsAnnotation: "cost-center",
+			},
+		},
+		{
+			name:    "changed propagated labels are reverted",
+			cluster: "root:org:ws",
+			policy:  &tenancyv1alpha1.ClusterWorkspaceLabelPropagation{Labels: []string{"cost-center"}, Downstream: true},
+			ns: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:        "default",
+				Labels:      map[string]string{"cost-center": "0"},
+				Annotations: map[string]string{tenancyv1alpha1.PropagatedLabelsAnnotation: "cost-center"},
+			}},
+			wantLabels: map[string]string{
+				"cost-center": "42",
+			},
+			wantAnnotations: map[string]string{
+				tenancyv1alpha1.PropagatedLabelsAnnotation: "cost-center",
+				tenancyv1alpha1.DownstreamLabelsAnnotation: "cost-center",
+			},
+		},
+		{
+			name:     "changed workload defaults are reverted",
+			cluster:  "root:org:ws",
+			defaults: &tenancyv1alpha1.WorkloadDefaults{NodeSelector: map[string]string{"pool": "tenants"}},
+			ns: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:        "default",
+				Annotations: map[string]string{tenancyv1alpha1.WorkloadDefaultsAnnotation: `{}`},
+			}},
+			wantAnnotations: map[string]string{
+				tenancyv1alpha1.WorkloadDefaultsAnnotation: `{"nodeSelector":{"pool":"tenants"}}`,
+			},
+		},
+		{
+			name:    "workspace of unknown type is unchanged",
+			cluster: "root:org:ws",
+			wsType:  "Universal",
+			policy:  &tenancyv1alpha1.ClusterWorkspaceLabelPropagation{Labels: []string{"cost-center"}},
+			ns:      &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		},
+		{
+			name:    "root is unchanged",
+			cluster: "root",
+			policy:  &tenancyv1alpha1.ClusterWorkspaceLabelPropagation{Labels: []string{"cost-center"}},
+			ns:      &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.wsType == "" {
+				tc.wsType = "Team"
+			}
+			o := &workspaceLabelPropagation{
+				Handler: admission.NewHandler(admission.Create, admission.Update),
+				getClusterWorkspace: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error) {
+					require.Equal(t, "root:org", clusterName.String())
+					require.Equal(t, "ws", name)
+					return &tenancyv1alpha1.ClusterWorkspace{
+						ObjectMeta: metav1.ObjectMeta{Name: name, ClusterName: clusterName.String(), Labels: map[string]string{"cost-center": "42", "other": "x"}},
+						Spec:       tenancyv1alpha1.ClusterWorkspaceSpec{Type: tc.wsType},
+					}, nil
+				},
+				getClusterWorkspaceType: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspaceType, error) {
+					if name != "team" {
+						return nil, apierrors.NewNotFound(tenancyv1alpha1.Resource("clusterworkspacetypes"), name)
+					}
+					return &tenancyv1alpha1.ClusterWorkspaceType{
+						ObjectMeta: metav1.ObjectMeta{Name: name, ClusterName: clusterName.String()},
+						Spec:       tenancyv1alpha1.ClusterWorkspaceTypeSpec{LabelPropagation: tc.policy, WorkloadDefaults: tc.defaults},
+					}, nil
+				},
+			}
+
+			attr := createAttr(tc.ns)
+			ctx := request.WithCluster(context.Background(), request.Cluster{Name: logicalcluster.New(tc.cluster)})
+			err := o.Admit(ctx, attr, nil)
+			require.NoError(t, err)
+
+			got := attr.GetObject().(*unstructured.Unstructured)
+			wantLabels := tc.wantLabels
+			if wantLabels == nil {
+				wantLabels = tc.ns.Labels
+			}
+			require.Equal(t, wantLabels, got.GetLabels())
+			require.Equal(t, tc.wantAnnotations, got.GetAnnotations())
+		})
+	}
+}
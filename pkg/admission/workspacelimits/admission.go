@@ -0,0 +1,225 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacelimits
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/admission"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/clusters"
+
+	kcpinitializers "github.com/kcp-dev/kcp/pkg/admission/initializers"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	kcpinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
+)
+
+const (
+	PluginName = "tenancy.kcp.dev/WorkspaceLimits"
+)
+
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName,
+		func(_ io.Reader) (admission.Interface, error) {
+			return &workspaceLimits{
+				Handler: admission.NewHandler(admission.Create, admission.Update),
+			}, nil
+		})
+}
+
+// workspaceLimits enforces the limits of the ClusterWorkspaceType of a workspace and
+// of the workspace itself on the objects in the workspace:
+// - the size of objects,
+// - the size of the managed fields of objects,
+// - the number of objects per resource on creation.
+type workspaceLimits struct {
+	*admission.Handler
+
+	getClusterWorkspace     func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error)
+	getClusterWorkspaceType func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspaceType, error)
+	countObjects            func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource) (int64, error)
+}
+
+// Ensure that the required admission interfaces are implemented.
+var _ = admission.ValidationInterface(&workspaceLimits{})
+var _ = admission.InitializationValidator(&workspaceLimits{})
+var _ = kcpinitializers.WantsKcpInformers(&workspaceLimits{})
+var _ = kcpinitializers.WantsDynamicClusterClient(&workspaceLimits{})
+
+// Validate rejects objects exceeding the limits of the workspace or its type.
+func (o *workspaceLimits) Validate(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
+	if a.GetObject() == nil {
+		return nil
+	}
+	if a.GetOperation() == admission.Create && a.GetSubresource() != "" {
+		return nil // e.g. pods/binding, or tokens of service accounts, which are not stored
+	}
+
+	clusterName, err := genericapirequest.ClusterNameFrom(ctx)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	parent, name := clusterName.Split()
+	if parent.Empty() {
+		return nil // root has no type
+	}
+
+	if !o.WaitForReady() {
+		return admission.NewForbidden(a, fmt.Errorf("not yet ready to handle request"))
+	}
+
+	limits, err := o.limits(parent, name)
+	if err != nil {
+		return admission.NewForbidden(a, err)
+	}
+	if limits == nil {
+		return nil
+	}
+
+	if limits.MaxObjectSize != nil {
+		bs, err := json.Marshal(a.GetObject())
+		if err != nil {
+			return apierrors.NewInternalError(err)
+		}
+		if size := int64(len(bs)); size > *limits.MaxObjectSize {
+			return admission.NewForbidden(a, fmt.Errorf("object size of %d bytes exceeds the limit of %d bytes of the workspace", size, *limits.MaxObjectSize))
+		}
+	}
+
+	if limits.MaxManagedFieldsSize != nil {
+		if obj, err := meta.Accessor(a.GetObject()); err == nil {
+			bs, err := json.Marshal(obj.GetManagedFields())
+			if err != nil {
+				return apierrors.NewInternalError(err)
+			}
+			if size := int64(len(bs)); size > *limits.MaxManagedFieldsSize {
+				return admission.NewForbidden(a, fmt.Errorf("managed fields size of %d bytes exceeds the limit of %d bytes of the workspace", size, *limits.MaxManagedFieldsSize))
+			}
+		}
+	}
+
+	if limits.MaxObjectsPerResource != nil && a.GetOperation() == admission.Create {
+		count, err := o.countObjects(ctx, clusterName, a.GetResource())
+		if err != nil {
+			return admission.NewForbidden(a, fmt.Errorf("failed to count %s: %w", a.GetResource().GroupResource(), err))
+		}
+		if count >= *limits.MaxObjectsPerResource {
+			return admission.NewForbidden(a, fmt.Errorf("the workspace has reached its limit of %d %s", *limits.MaxObjectsPerResource, a.GetResource().GroupResource()))
+		}
+	}
+
+	return nil
+}
+
+// limits returns the limits of the given workspace merged with those of its type,
+// or nil if there are none.
+func (o *workspaceLimits) limits(parent logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspaceLimits, error) {
+	ws, err := o.getClusterWorkspace(parent, name)
+	if apierrors.IsNotFound(err) {
+		return nil, nil // not a ClusterWorkspace based logical cluster
+	} else if err != nil {
+		return nil, err
+	}
+
+	cwt, err := o.getClusterWorkspaceType(parent, strings.ToLower(ws.Spec.Type))
+	if apierrors.IsNotFound(err) {
+		return ws.Spec.Limits, nil // e.g. Universal without an explicit type
+	} else if err != nil {
+		return nil, err
+	}
+
+	return stricter(cwt.Spec.Limits, ws.Spec.Limits), nil
+}
+
+// stricter merges two limits, taking the smaller value for every limit set in both.
+func stricter(a, b *tenancyv1alpha1.ClusterWorkspaceLimits) *tenancyv1alpha1.ClusterWorkspaceLimits {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	return &tenancyv1alpha1.ClusterWorkspaceLimits{
+		MaxObjectSize:         min(a.MaxObjectSize, b.MaxObjectSize),
+		MaxManagedFieldsSize:  min(a.MaxManagedFieldsSize, b.MaxManagedFieldsSize),
+		MaxObjectsPerResource: min(a.MaxObjectsPerResource, b.MaxObjectsPerResource),
+	}
+}
+
+func min(a, b *int64) *int64 {
+	if a == nil {
+		return b
+	}
+	if b == nil || *a < *b {
+		return a
+	}
+	return b
+}
+
+func (o *workspaceLimits) ValidateInitialization() error {
+	if o.getClusterWorkspace == nil {
+		return fmt.Errorf(PluginName + " plugin needs a ClusterWorkspace lister")
+	}
+	if o.countObjects == nil {
+		return fmt.Errorf(PluginName + " plugin needs a dynamic cluster client")
+	}
+	return nil
+}
+
+func (o *workspaceLimits) SetKcpInformers(informers kcpinformers.SharedInformerFactory) {
+	workspacesReady := informers.Tenancy().V1alpha1().ClusterWorkspaces().Informer().HasSynced
+	typesReady := informers.Tenancy().V1alpha1().ClusterWorkspaceTypes().Informer().HasSynced
+	o.SetReadyFunc(func() bool {
+		return workspacesReady() && typesReady()
+	})
+
+	workspaceLister := informers.Tenancy().V1alpha1().ClusterWorkspaces().Lister()
+	o.getClusterWorkspace = func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error) {
+		return workspaceLister.Get(clusters.ToClusterAwareKey(clusterName, name))
+	}
+	typeLister := informers.Tenancy().V1alpha1().ClusterWorkspaceTypes().Lister()
+	o.getClusterWorkspaceType = func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspaceType, error) {
+		return typeLister.Get(clusters.ToClusterAwareKey(clusterName, name))
+	}
+}
+
+func (o *workspaceLimits) SetDynamicClusterClient(dynamicClusterClient dynamic.ClusterInterface) {
+	o.countObjects = func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource) (int64, error) {
+		// a list with limit returns the number of remaining objects, without
+		// reading them from etcd.
+		list, err := dynamicClusterClient.Cluster(clusterName).Resource(gvr).List(ctx, metav1.ListOptions{Limit: 1})
+		if err != nil {
+			return 0, err
+		}
+		count := int64(len(list.Items))
+		if remaining := list.GetRemainingItemCount(); remaining != nil {
+			count += *remaining
+		}
+		return count, nil
+	}
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacelimits
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+func attr(op admission.Operation, obj map[string]interface{}) admission.Attributes {
+	var opts runtime.Object = &metav1.CreateOptions{}
+	if op == admission.Update {
+		opts = &metav1.UpdateOptions{}
+	}
+	return admission.NewAttributesRecord(
+		&unstructured.Unstructured{Object: obj},
+		nil,
+		schema.GroupVersionKind{Group: "wildwest.dev", Version: "v1alpha1", Kind: "Cowboy"},
+		"default",
+		"test",
+		schema.GroupVersionResource{Group: "wildwest.dev", Version: "v1alpha1", Resource: "cowboys"},
+		"",
+		op,
+		opts,
+		false,
+		&user.DefaultInfo{},
+	)
+}
+
+func int64Ptr(i int64) *int64 {
+	return &i
+}
+
+func TestValidate(t *testing.T) {
+	small := map[string]interface{}{"spec": map[string]interface{}{"intent": "ride"}}
+	large := map[string]interface{}{"spec": map[string]interface{}{"intent": strings.Repeat("x", 200)}}
+	managed := map[string]interface{}{"metadata": map[string]interface{}{"managedFields": []interface{}{
+		map[string]interface{}{"manager": "kubectl", "operation": "Apply", "fieldsType": "FieldsV1", "fieldsV1": map[string]interface{}{"f:spec": map[string]interface{}{"f:intent": map[string]interface{}{}}}},
+	}}}
+	limits := &tenancyv1alpha1.ClusterWorkspaceLimits{
+		MaxObjectSize:         int64Ptr(100),
+		MaxObjectsPerResource: int64Ptr(3),
+		MaxManagedFieldsSize:  int64Ptr(50),
+	}
+
+	tests := []struct {
+		name     string
+		cluster  string
+		wsType   string
+		limits   *tenancyv1alpha1.ClusterWorkspaceLimits
+		wsLimits *tenancyv1alpha1.ClusterWorkspaceLimits
+		count    int64
+		attr     admission.Attributes
+		wantErr  bool
+	}{
+		{
+			name:    "object within limits is admitted",
+			cluster: "root:org:ws",
+			limits:  limits,
+			count:   2,
+			attr:    attr(admission.Create, small),
+		},
+		{
+			name:    "too large object is rejected",
+			cluster: "root:org:ws",
+			limits:  limits,
+			attr:    attr(admission.Update, large),
+			wantErr: true,
+		},
+		{
+			name:    "too large managed fields are rejected",
+			cluster: "root:org:ws",
+			limits:  &tenancyv1alpha1.ClusterWorkspaceLimits{MaxManagedFieldsSize: int64Ptr(50)},
+			attr:    attr(admission.Update, managed),
+			wantErr: true,
+		},
+		{
+			name:    "creation beyond the object limit is rejected",
+			cluster: "root:org:ws",
+			limits:  limits,
+			count:   3,
+			attr:    attr(admission.Create, small),
+			wantErr: true,
+		},
+		{
+			name:    "update at the object limit is admitted",
+			cluster: "root:org:ws",
+			limits:  limits,
+			count:   3,
+			attr:    attr(admission.Update, small),
+		},
+		{
+			name:    "workspace of type without limits is not limited",
+			cluster: "root:org:ws",
+			count:   100,
+			attr:    attr(admission.Create, large),
+		},
+		{
+			name:    "workspace of unknown type is not limited",
+			cluster: "root:org:ws",
+			wsType:  "Universal",
+			limits:  limits,
+			count:   100,
+			attr:    attr(admission.Create, large),
+		},
+		{
+			name:     "stricter workspace limits apply",
+			cluster:  "root:org:ws",
+			limits:   limits,
+			wsLimits: &tenancyv1alpha1.ClusterWorkspaceLimits{MaxObjectsPerResource: int64Ptr(2)},
+			count:    2,
+			attr:     attr(admission.Create, small),
+			wantErr:  true,
+		},
+		{
+			name:     "looser workspace limits do not apply",
+			cluster:  "root:org:ws",
+			limits:   limits,
+			wsLimits: &tenancyv1alpha1.ClusterWorkspaceLimits{MaxObjectSize: int64Ptr(1000)},
+			attr:     attr(admission.Update, large),
+			wantErr:  true,
+		},
+		{
+			name:     "workspace limits apply without type",
+			cluster:  "root:org:ws",
+			wsType:   "Universal",
+			wsLimits: &tenancyv1alpha1.ClusterWorkspaceLimits{MaxObjectSize: int64Ptr(100)},
+			attr:     attr(admission.Update, large),
+			wantErr:  true,
+		},
+		{
+			name:    "root is not limited",
+			cluster: "root",
+			limits:  limits,
+			count:   100,
+			attr:    attr(admission.Create, large),
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.wsType == "" {
+				tc.wsType = "Team"
+			}
+			o := &workspaceLimits{
+				Handler: admission.NewHandler(admission.Create, admission.Update),
+				getClusterWorkspace: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error) {
+					require.Equal(t, "root:org", clusterName.String())
+					require.Equal(t, "ws", name)
+					return &tenancyv1alpha1.ClusterWorkspace{
+						ObjectMeta: metav1.ObjectMeta{Name: name, ClusterName: clusterName.String()},
+						Spec:       tenancyv1alpha1.ClusterWorkspaceSpec{Type: tc.wsType, Limits: tc.wsLimits},
+					}, nil
+				},
+				getClusterWorkspaceType: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspaceType, error) {
+					if name != "team" {
+						return nil, apierrors.NewNotFound(tenancyv1alpha1.Resource("clusterworkspacetypes"), name)
+					}
+					return &tenancyv1alpha1.ClusterWorkspaceType{
+						ObjectMeta: metav1.ObjectMeta{Name: name, ClusterName: clusterName.String()},
+						Spec:       tenancyv1alpha1.ClusterWorkspaceTypeSpec{Limits: tc.limits},
+					}, nil
+				},
+				countObjects: func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource) (int64, error) {
+					require.Equal(t, "root:org:ws", clusterName.String())
+					return tc.count, nil
+				},
+			}
+
+			ctx := request.WithCluster(context.Background(), request.Cluster{Name: logicalcluster.New(tc.cluster)})
+			err := o.Validate(ctx, tc.attr, nil)
+			if tc.wantErr {
+				require.Error(t, err)
+				require.True(t, apierrors.IsForbidden(err), "expected forbidden, got %v", err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacenames
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apiserver/pkg/admission"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"sigs.k8s.io/yaml"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+const (
+	PluginName = "tenancy.kcp.dev/WorkspaceNames"
+)
+
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName,
+		func(config io.Reader) (admission.Interface, error) {
+			cfg, err := loadConfiguration(config)
+			if err != nil {
+				return nil, err
+			}
+			return newWorkspaceNames(cfg, registeredValidators())
+		})
+}
+
+// workspaceNames validates the paths of new workspaces against the rules of its
+// configuration and the registered validators.
+type workspaceNames struct {
+	*admission.Handler
+
+	validators []Validator
+}
+
+// Ensure that the required admission interfaces are implemented.
+var _ = admission.ValidationInterface(&workspaceNames{})
+
+func loadConfiguration(config io.Reader) (*Configuration, error) {
+	cfg := &Configuration{}
+	if config == nil {
+		return cfg, nil
+	}
+	bs, err := ioutil.ReadAll(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s configuration: %w", PluginName, err)
+	}
+	if err := yaml.Unmarshal(bs, cfg); err != nil {
+		return nil, fmt.Errorf("failed to decode %s configuration: %w", PluginName, err)
+	}
+	return cfg, nil
+}
+
+func newWorkspaceNames(cfg *Configuration, registered []Validator) (*workspaceNames, error) {
+	p := &workspaceNames{
+		Handler: admission.NewHandler(admission.Create),
+	}
+	for i, r := range cfg.Rules {
+		v, err := newRuleValidator(r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rule %d of %s configuration: %w", i, PluginName, err)
+		}
+		p.validators = append(p.validators, v)
+	}
+	p.validators = append(p.validators, registered...)
+	return p, nil
+}
+
+// Validate rejects new workspaces whose path violates a rule or a registered validator.
+func (o *workspaceNames) Validate(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
+	if a.GetResource().GroupResource() != tenancyv1alpha1.Resource("clusterworkspaces") {
+		return nil
+	}
+	if len(o.validators) == 0 {
+		return nil
+	}
+
+	u, ok := a.GetObject().(*unstructured.Unstructured)
+	if !ok {
+		return fmt.Errorf("unexpected type %T", a.GetObject())
+	}
+	ws := &tenancyv1alpha1.ClusterWorkspace{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, ws); err != nil {
+		return fmt.Errorf("failed to convert unstructured to ClusterWorkspace: %w", err)
+	}
+
+	clusterName, err := genericapirequest.ClusterNameFrom(ctx)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	path := clusterName.Join(ws.Name)
+
+	var errs []error
+	for _, v := range o.validators {
+		if err := v.ValidateWorkspacePath(path, ws); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return admission.NewForbidden(a, fmt.Errorf("workspace %s: %w", path, utilerrors.NewAggregate(errs)))
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacenames
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	"github.com/kcp-dev/kcp/pkg/admission/helpers"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+func createAttr(name, workspaceType string) admission.Attributes {
+	ws := &tenancyv1alpha1.ClusterWorkspace{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       tenancyv1alpha1.ClusterWorkspaceSpec{Type: workspaceType},
+	}
+	return admission.NewAttributesRecord(
+		helpers.ToUnstructuredOrDie(ws),
+		nil,
+		tenancyv1alpha1.Kind("ClusterWorkspace").WithVersion("v1alpha1"),
+		"",
+		ws.Name,
+		tenancyv1alpha1.Resource("clusterworkspaces").WithVersion("v1alpha1"),
+		"",
+		admission.Create,
+		&metav1.CreateOptions{},
+		false,
+		&user.DefaultInfo{},
+	)
+}
+
+const config = `
+rules:
+- reservedPrefixes: ["system-", "kcp-"]
+  maxNameLength: 20
+- parent: root
+  types: ["Organization"]
+  namePattern: "org-[a-z]+"
+  message: see https://example.com/naming
+- parent: root:org-a
+  maxDepth: 4
+`
+
+func TestValidate(t *testing.T) {
+	cfg, err := loadConfiguration(strings.NewReader(config))
+	require.NoError(t, err)
+
+	custom := ValidatorFunc(func(path logicalcluster.Name, ws *tenancyv1alpha1.ClusterWorkspace) error {
+		if strings.Contains(ws.Name, "legacy") {
+			return errors.New("legacy workspaces are not allowed")
+		}
+		return nil
+	})
+	plugin, err := newWorkspaceNames(cfg, []Validator{custom})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		cluster     string
+		a           admission.Attributes
+		wantErrs    []string
+		wantNoError bool
+	}{
+		{name: "valid organization", cluster: "root", a: createAttr("org-a", "Organization"), wantNoError: true},
+		{name: "invalid organization name", cluster: "root", a: createAttr("acme", "Organization"), wantErrs: []string{`name must match "org-[a-z]+": see https://example.com/naming`}},
+		{name: "pattern only applies to the type", cluster: "root", a: createAttr("acme", "Universal"), wantNoError: true},
+		{name: "pattern matches as a whole", cluster: "root", a: createAttr("org-a-b", "Organization"), wantErrs: []string{`name must match`}},
+		{name: "reserved prefix", cluster: "root:org-a", a: createAttr("system-team", "Universal"), wantErrs: []string{`names starting with "system-" are reserved`}},
+		{name: "name too long", cluster: "root:org-a", a: createAttr("a-very-long-team-name-indeed", "Universal"), wantErrs: []string{"name must not be longer than 20 characters"}},
+		{name: "depth within limit", cluster: "root:org-a:team", a: createAttr("project", "Universal"), wantNoError: true},
+		{name: "too deep", cluster: "root:org-a:team:project", a: createAttr("sub", "Universal"), wantErrs: []string{"workspaces must not be nested deeper than 4 levels"}},
+		{name: "depth limit only applies below parent", cluster: "root:org-b:team:project", a: createAttr("sub", "Universal"), wantNoError: true},
+		{name: "registered validator", cluster: "root:org-a", a: createAttr("legacy-team", "Universal"), wantErrs: []string{"legacy workspaces are not allowed"}},
+		{name: "all errors are reported", cluster: "root:org-a:team:project", a: createAttr("kcp-legacy", "Universal"), wantErrs: []string{`names starting with "kcp-" are reserved`, "workspaces must not be nested deeper than 4 levels", "legacy workspaces are not allowed"}},
+		{
+			name:    "other resources are ignored",
+			cluster: "root:org-a",
+			a: admission.NewAttributesRecord(
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "system-cm"}},
+				nil,
+				corev1.SchemeGroupVersion.WithKind("ConfigMap"),
+				"default",
+				"system-cm",
+				corev1.SchemeGroupVersion.WithResource("configmaps"),
+				"",
+				admission.Create,
+				&metav1.CreateOptions{},
+				false,
+				&user.DefaultInfo{},
+			),
+			wantNoError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := request.WithCluster(context.Background(), request.Cluster{Name: logicalcluster.New(tt.cluster)})
+			err := plugin.Validate(ctx, tt.a, nil)
+			if tt.wantNoError {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, want := range tt.wantErrs {
+				require.Contains(t, err.Error(), want)
+			}
+		})
+	}
+}
+
+func TestConfiguration(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{name: "no rules"},
+		{name: "invalid yaml", config: "rules: [", wantErr: "failed to decode"},
+		{name: "invalid pattern", config: "rules:\n- namePattern: '[a-'", wantErr: "invalid rule 0"},
+		{name: "negative depth", config: "rules:\n- {}\n- maxDepth: -1", wantErr: "invalid rule 1"},
+		{name: "negative length", config: "rules:\n- maxNameLength: -1", wantErr: "invalid rule 0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := loadConfiguration(strings.NewReader(tt.config))
+			if err == nil {
+				_, err = newWorkspaceNames(cfg, nil)
+			}
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+
+	cfg, err := loadConfiguration(nil)
+	require.NoError(t, err)
+	require.Empty(t, cfg.Rules)
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacenames
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+// Validator validates the path of a new workspace, i.e. the logical cluster of the
+// ClusterWorkspace in its parent joined with its name. Returned errors reject the
+// creation of the workspace and are shown to the user.
+type Validator interface {
+	ValidateWorkspacePath(path logicalcluster.Name, ws *tenancyv1alpha1.ClusterWorkspace) error
+}
+
+// ValidatorFunc is a function implementing Validator.
+type ValidatorFunc func(path logicalcluster.Name, ws *tenancyv1alpha1.ClusterWorkspace) error
+
+// ValidateWorkspacePath implements Validator.
+func (f ValidatorFunc) ValidateWorkspacePath(path logicalcluster.Name, ws *tenancyv1alpha1.ClusterWorkspace) error {
+	return f(path, ws)
+}
+
+var (
+	validatorsLock sync.Mutex
+	validators     []Validator
+)
+
+// RegisterValidator adds a validator to those of the plugin, e.g. for organization specific
+// rules that cannot be expressed in the configuration. It must be called before the admission
+// plugins are initialized, e.g. from an init function of a kcp build embedding this package.
+func RegisterValidator(v Validator) {
+	validatorsLock.Lock()
+	defer validatorsLock.Unlock()
+
+	validators = append(validators, v)
+}
+
+func registeredValidators() []Validator {
+	validatorsLock.Lock()
+	defer validatorsLock.Unlock()
+
+	return append([]Validator(nil), validators...)
+}
+
+// Configuration is the configuration of the plugin in the admission control configuration file.
+type Configuration struct {
+	// Rules are the rules new workspaces must satisfy. Every matching rule applies.
+	Rules []Rule `json:"rules,omitempty"`
+}
+
+// Rule restricts the names and paths of new workspaces.
+type Rule struct {
+	// Parent restricts the rule to the workspaces below the workspace with this path, e.g.
+	// root:org matches root:org:team and root:org:team:project, but not root:org itself.
+	// Empty matches all workspaces.
+	Parent string `json:"parent,omitempty"`
+
+	// Types restricts the rule to workspaces of these types, e.g. Organization. Empty
+	// matches all types.
+	Types []string `json:"types,omitempty"`
+
+	// NamePattern is a regular expression the names must match as a whole, e.g. "team-[a-z0-9-]+".
+	NamePattern string `json:"namePattern,omitempty"`
+
+	// ReservedPrefixes are prefixes names must not start with, e.g. "system-".
+	ReservedPrefixes []string `json:"reservedPrefixes,omitempty"`
+
+	// MaxDepth is the maximum number of segments of the workspace paths, e.g. 3 allows
+	// root:org:team, but not root:org:team:project. Zero means no limit.
+	MaxDepth int `json:"maxDepth,omitempty"`
+
+	// MaxNameLength is the maximum length of the names. Zero means no limit.
+	MaxNameLength int `json:"maxNameLength,omitempty"`
+
+	// Message is appended to the errors of the rule, e.g. to point to the naming conventions.
+	Message string `json:"message,omitempty"`
+}
+
+// ruleValidator is the Validator of a Rule.
+type ruleValidator struct {
+	Rule
+	parent  logicalcluster.Name
+	pattern *regexp.Regexp
+}
+
+func newRuleValidator(r Rule) (*ruleValidator, error) {
+	v := &ruleValidator{Rule: r, parent: logicalcluster.New(r.Parent)}
+	if r.NamePattern != "" {
+		pattern, err := regexp.Compile("^(?:" + r.NamePattern + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid namePattern %q: %w", r.NamePattern, err)
+		}
+		v.pattern = pattern
+	}
+	if r.MaxDepth < 0 {
+		return nil, fmt.Errorf("maxDepth must not be negative")
+	}
+	if r.MaxNameLength < 0 {
+		return nil, fmt.Errorf("maxNameLength must not be negative")
+	}
+	return v, nil
+}
+
+func (v *ruleValidator) matches(path logicalcluster.Name, ws *tenancyv1alpha1.ClusterWorkspace) bool {
+	if !v.parent.Empty() && !strings.HasPrefix(path.String(), v.parent.String()+":") {
+		return false
+	}
+	if len(v.Types) == 0 {
+		return true
+	}
+	for _, t := range v.Types {
+		if t == ws.Spec.Type {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateWorkspacePath implements Validator.
+func (v *ruleValidator) ValidateWorkspacePath(path logicalcluster.Name, ws *tenancyv1alpha1.ClusterWorkspace) error {
+	if !v.matches(path, ws) {
+		return nil
+	}
+
+	var reasons []string
+	if v.pattern != nil && !v.pattern.MatchString(ws.Name) {
+		reasons = append(reasons, fmt.Sprintf("name must match %q", v.NamePattern))
+	}
+	for _, prefix := range v.ReservedPrefixes {
+		if strings.HasPrefix(ws.Name, prefix) {
+			reasons = append(reasons, fmt.Sprintf("names starting with %q are reserved", prefix))
+		}
+	}
+	if v.MaxNameLength > 0 && len(ws.Name) > v.MaxNameLength {
+		reasons = append(reasons, fmt.Sprintf("name must not be longer than %d characters", v.MaxNameLength))
+	}
+	if depth := strings.Count(path.String(), ":") + 1; v.MaxDepth > 0 && depth > v.MaxDepth {
+		reasons = append(reasons, fmt.Sprintf("workspaces must not be nested deeper than %d levels", v.MaxDepth))
+	}
+	if len(reasons) == 0 {
+		return nil
+	}
+
+	msg := strings.Join(reasons, ", ")
+	if v.Message != "" {
+		msg += ": " + v.Message
+	}
+	return errors.New(msg)
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacenamespaces
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/tools/clusters"
+
+	kcpinitializers "github.com/kcp-dev/kcp/pkg/admission/initializers"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	kcpinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
+)
+
+const (
+	PluginName = "tenancy.kcp.dev/WorkspaceNamespaces"
+)
+
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName,
+		func(_ io.Reader) (admission.Interface, error) {
+			return &workspaceNamespaces{
+				Handler: admission.NewHandler(admission.Create),
+			}, nil
+		})
+}
+
+// workspaceNamespaces enforces the namespace controls of the ClusterWorkspaceType
+// of a workspace on the creation of namespaces in the workspace:
+// - namespaces are rejected if they are disabled,
+// - namespace names must match one of the allowed name patterns, if any.
+// Members of system:masters, e.g. kcp itself, are exempted.
+type workspaceNamespaces struct {
+	*admission.Handler
+
+	getClusterWorkspace     func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error)
+	getClusterWorkspaceType func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspaceType, error)
+}
+
+// Ensure that the required admission interfaces are implemented.
+var _ = admission.ValidationInterface(&workspaceNamespaces{})
+var _ = admission.InitializationValidator(&workspaceNamespaces{})
+var _ = kcpinitializers.WantsKcpInformers(&workspaceNamespaces{})
+
+// Validate rejects namespaces not allowed by the workspace type.
+func (o *workspaceNamespaces) Validate(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
+	if a.GetResource().GroupResource() != corev1.Resource("namespaces") || a.GetSubresource() != "" {
+		return nil
+	}
+	if userInfo := a.GetUserInfo(); userInfo != nil {
+		for _, group := range userInfo.GetGroups() {
+			if group == user.SystemPrivilegedGroup {
+				return nil
+			}
+		}
+	}
+
+	clusterName, err := genericapirequest.ClusterNameFrom(ctx)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	parent, name := clusterName.Split()
+	if parent.Empty() {
+		return nil // root has no type
+	}
+
+	if !o.WaitForReady() {
+		return admission.NewForbidden(a, fmt.Errorf("not yet ready to handle request"))
+	}
+
+	namespaces, err := o.namespaces(parent, name)
+	if err != nil {
+		return admission.NewForbidden(a, err)
+	}
+	if namespaces == nil {
+		return nil
+	}
+
+	if namespaces.Disabled {
+		return admission.NewForbidden(a, fmt.Errorf("namespaces are disabled in workspaces of this type"))
+	}
+	if len(namespaces.AllowedNamePatterns) == 0 {
+		return nil
+	}
+	for _, pattern := range namespaces.AllowedNamePatterns {
+		re, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			continue // rejected by validation of the type
+		}
+		if re.MatchString(a.GetName()) {
+			return nil
+		}
+	}
+	return admission.NewForbidden(a, fmt.Errorf("namespace name must match one of the patterns %q of the workspace type", namespaces.AllowedNamePatterns))
+}
+
+// namespaces returns the namespace controls of the type of the given workspace, or nil if there are none.
+func (o *workspaceNamespaces) namespaces(parent logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspaceNamespaces, error) {
+	ws, err := o.getClusterWorkspace(parent, name)
+	if apierrors.IsNotFound(err) {
+		return nil, nil // not a ClusterWorkspace based logical cluster
+	} else if err != nil {
+		return nil, err
+	}
+
+	cwt, err := o.getClusterWorkspaceType(parent, strings.ToLower(ws.Spec.Type))
+	if apierrors.IsNotFound(err) {
+		return nil, nil // e.g. Universal without an explicit type
+	} else if err != nil {
+		return nil, err
+	}
+
+	return cwt.Spec.Namespaces, nil
+}
+
+func (o *workspaceNamespaces) ValidateInitialization() error {
+	if o.getClusterWorkspace == nil {
+		return fmt.Errorf(PluginName + " plugin needs a ClusterWorkspace lister")
+	}
+	return nil
+}
+
+func (o *workspaceNamespaces) SetKcpInformers(informers kcpinformers.SharedInformerFactory) {
+	workspacesReady := informers.Tenancy().V1alpha1().ClusterWorkspaces().Informer().HasSynced
+	typesReady := informers.Tenancy().V1alpha1().ClusterWorkspaceTypes().Informer().HasSynced
+	o.SetReadyFunc(func() bool {
+		return workspacesReady() && typesReady()
+	})
+
+	workspaceLister := informers.Tenancy().V1alpha1().ClusterWorkspaces().Lister()
+	o.getClusterWorkspace = func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error) {
+		return workspaceLister.Get(clusters.ToClusterAwareKey(clusterName, name))
+	}
+	typeLister := informers.Tenancy().V1alpha1().ClusterWorkspaceTypes().Lister()
+	o.getClusterWorkspaceType = func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspaceType, error) {
+		return typeLister.Get(clusters.ToClusterAwareKey(clusterName, name))
+	}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacenamespaces
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	"github.com/kcp-dev/kcp/pkg/admission/helpers"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+func createAttr(name string, groups ...string) admission.Attributes {
+	return admission.NewAttributesRecord(
+		helpers.ToUnstructuredOrDie(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}),
+		nil,
+		corev1.SchemeGroupVersion.WithKind("Namespace"),
+		"",
+		name,
+		corev1.SchemeGroupVersion.WithResource("namespaces"),
+		"",
+		admission.Create,
+		&metav1.CreateOptions{},
+		false,
+		&user.DefaultInfo{Name: "user", Groups: groups},
+	)
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		cluster    string
+		wsType     string
+		namespaces *tenancyv1alpha1.ClusterWorkspaceNamespaces
+		attr       admission.Attributes
+		wantErr    bool
+	}{
+		{
+			name:    "namespace in workspace without controls is admitted",
+			cluster: "root:org:ws",
+			attr:    createAttr("anything"),
+		},
+		{
+			name:       "namespace in workspace with disabled namespaces is rejected",
+			cluster:    "root:org:ws",
+			namespaces: &tenancyv1alpha1.ClusterWorkspaceNamespaces{Disabled: true},
+			attr:       createAttr("default"),
+			wantErr:    true,
+		},
+		{
+			name:       "namespace matching a pattern is admitted",
+			cluster:    "root:org:ws",
+			namespaces: &tenancyv1alpha1.ClusterWorkspaceNamespaces{AllowedNamePatterns: []string{"default", "team-.*"}},
+			attr:       createAttr("team-a"),
+		},
+		{
+			name:       "namespace matching a pattern only partially is rejected",
+			cluster:    "root:org:ws",
+			namespaces: &tenancyv1alpha1.ClusterWorkspaceNamespaces{AllowedNamePatterns: []string{"team"}},
+			attr:       createAttr("my-team-a"),
+			wantErr:    true,
+		},
+		{
+			name:       "privileged users are exempted",
+			cluster:    "root:org:ws",
+			namespaces: &tenancyv1alpha1.ClusterWorkspaceNamespaces{Disabled: true},
+			attr:       createAttr("default", user.SystemPrivilegedGroup),
+		},
+		{
+			name:       "workspace of unknown type is not restricted",
+			cluster:    "root:org:ws",
+			wsType:     "Universal",
+			namespaces: &tenancyv1alpha1.ClusterWorkspaceNamespaces{Disabled: true},
+			attr:       createAttr("default"),
+		},
+		{
+			name:       "root is not restricted",
+			cluster:    "root",
+			namespaces: &tenancyv1alpha1.ClusterWorkspaceNamespaces{Disabled: true},
+			attr:       createAttr("default"),
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.wsType == "" {
+				tc.wsType = "Team"
+			}
+			o := &workspaceNamespaces{
+				Handler: admission.NewHandler(admission.Create),
+				getClusterWorkspace: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error) {
+					require.Equal(t, "root:org", clusterName.String())
+					require.Equal(t, "ws", name)
+					return &tenancyv1alpha1.ClusterWorkspace{
+						ObjectMeta: metav1.ObjectMeta{Name: name, ClusterName: clusterName.String()},
+						Spec:       tenancyv1alpha1.ClusterWorkspaceSpec{Type: tc.wsType},
+					}, nil
+				},
+				getClusterWorkspaceType: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspaceType, error) {
+					if name != "team" {
+						return nil, apierrors.NewNotFound(tenancyv1alpha1.Resource("clusterworkspacetypes"), name)
+					}
+					return &tenancyv1alpha1.ClusterWorkspaceType{
+						ObjectMeta: metav1.ObjectMeta{Name: name, ClusterName: clusterName.String()},
+						Spec:       tenancyv1alpha1.ClusterWorkspaceTypeSpec{Namespaces: tc.namespaces},
+					}, nil
+				},
+			}
+
+			ctx := request.WithCluster(context.Background(), request.Cluster{Name: logicalcluster.New(tc.cluster)})
+			err := o.Validate(ctx, tc.attr, nil)
+			if tc.wantErr {
+				require.Error(t, err)
+				require.True(t, apierrors.IsForbidden(err), "expected forbidden, got %v", err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
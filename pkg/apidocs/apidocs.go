@@ -0,0 +1,314 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apidocs
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/tools/clusters"
+	"sigs.k8s.io/yaml"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	apisinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/apis/v1alpha1"
+)
+
+// Path is where the Documentation serves the documentation of the APIs bound
+// in the workspace of the request.
+const Path = "/apidocs"
+
+// Documentation is the documentation of the APIs bound in a workspace.
+type Documentation struct {
+	Resources []ResourceDocumentation `json:"resources"`
+}
+
+// ResourceDocumentation documents a bound resource.
+type ResourceDocumentation struct {
+	Group    string                                        `json:"group"`
+	Resource string                                        `json:"resource"`
+	Names    apiextensionsv1.CustomResourceDefinitionNames `json:"names"`
+	Scope    apiextensionsv1.ResourceScope                 `json:"scope"`
+	// Export is the APIExport the resource is bound from, as <workspace>|<name>.
+	Export string `json:"export"`
+	// Description is the description of the resource supplied by the APIExport owner.
+	Description string                 `json:"description,omitempty"`
+	Versions    []VersionDocumentation `json:"versions"`
+	// Examples are supplied by the APIExport owner.
+	Examples []Example `json:"examples,omitempty"`
+	// RequiredClaims are the resources the provider needs access to in consuming workspaces,
+	// as published in the catalog.
+	RequiredClaims []metav1.GroupResource `json:"requiredClaims,omitempty"`
+}
+
+// VersionDocumentation documents a served version of a bound resource.
+type VersionDocumentation struct {
+	Name               string  `json:"name"`
+	Storage            bool    `json:"storage,omitempty"`
+	Deprecated         bool    `json:"deprecated,omitempty"`
+	DeprecationWarning string  `json:"deprecationWarning,omitempty"`
+	Description        string  `json:"description,omitempty"`
+	Fields             []Field `json:"fields,omitempty"`
+}
+
+// Field documents a field of a version, at any depth.
+type Field struct {
+	// Path is the path of the field in dot notation, e.g. "spec.replicas". The items of lists
+	// and the values of maps do not add a segment.
+	Path        string   `json:"path"`
+	Type        string   `json:"type"`
+	Required    bool     `json:"required,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Enum        []string `json:"enum,omitempty"`
+	Default     string   `json:"default,omitempty"`
+}
+
+// Example is an example object of a resource.
+type Example struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	// Manifest is the YAML of the example object.
+	Manifest string `json:"manifest"`
+}
+
+// resourceNotes is the documentation of a resource in the documentation ConfigMap of
+// an APIExport.
+type resourceNotes struct {
+	Description string    `json:"description,omitempty"`
+	Examples    []Example `json:"examples,omitempty"`
+}
+
+// Server serves the documentation of the APIs bound in the workspace of the request.
+// It is rendered from the bound APIResourceSchemas, the documentation ConfigMap of
+// the APIExport and its CatalogEntries.
+type Server struct {
+	listAPIBindings      func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error)
+	getAPIExport         func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error)
+	getAPIResourceSchema func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error)
+	listCatalogEntries   func(clusterName logicalcluster.Name) ([]*apisv1alpha1.CatalogEntry, error)
+	getConfigMap         func(clusterName logicalcluster.Name, namespace, name string) (*corev1.ConfigMap, error)
+}
+
+// NewServer returns a Server reading from the given informers.
+func NewServer(
+	apiBindingInformer apisinformers.APIBindingInformer,
+	apiExportInformer apisinformers.APIExportInformer,
+	apiResourceSchemaInformer apisinformers.APIResourceSchemaInformer,
+	catalogEntryInformer apisinformers.CatalogEntryInformer,
+	configMapInformer coreinformers.ConfigMapInformer,
+) *Server {
+	return &Server{
+		listAPIBindings: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
+			bindings, err := apiBindingInformer.Lister().List(labels.Everything())
+			if err != nil {
+				return nil, err
+			}
+			var ret []*apisv1alpha1.APIBinding
+			for _, b := range bindings {
+				if logicalcluster.From(b) == clusterName {
+					ret = append(ret, b)
+				}
+			}
+			return ret, nil
+		},
+		getAPIExport: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error) {
+			return apiExportInformer.Lister().Get(clusters.ToClusterAwareKey(clusterName, name))
+		},
+		getAPIResourceSchema: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error) {
+			return apiResourceSchemaInformer.Lister().Get(clusters.ToClusterAwareKey(clusterName, name))
+		},
+		listCatalogEntries: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.CatalogEntry, error) {
+			entries, err := catalogEntryInformer.Lister().List(labels.Everything())
+			if err != nil {
+				return nil, err
+			}
+			var ret []*apisv1alpha1.CatalogEntry
+			for _, e := range entries {
+				if logicalcluster.From(e) == clusterName {
+					ret = append(ret, e)
+				}
+			}
+			return ret, nil
+		},
+		getConfigMap: func(clusterName logicalcluster.Name, namespace, name string) (*corev1.ConfigMap, error) {
+			return configMapInformer.Lister().ConfigMaps(namespace).Get(clusters.ToClusterAwareKey(clusterName, name))
+		},
+	}
+}
+
+// Documentation returns the documentation of the resources bound in the given workspace, sorted
+// by group and resource. If gr is not empty, only the documentation of that resource is returned.
+func (s *Server) Documentation(clusterName logicalcluster.Name, gr schema.GroupResource) (*Documentation, error) {
+	bindings, err := s.listAPIBindings(clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &Documentation{Resources: []ResourceDocumentation{}}
+	parentClusterName, _ := clusterName.Parent()
+	for _, binding := range bindings {
+		if binding.Status.BoundAPIExport == nil || binding.Status.BoundAPIExport.Workspace == nil {
+			continue
+		}
+		exportClusterName := parentClusterName.Join(binding.Status.BoundAPIExport.Workspace.WorkspaceName)
+		exportName := binding.Status.BoundAPIExport.Workspace.ExportName
+
+		for _, br := range binding.Status.BoundResources {
+			if !gr.Empty() && (br.Group != gr.Group || br.Resource != gr.Resource) {
+				continue
+			}
+			rd, err := s.resourceDocumentation(exportClusterName, exportName, br)
+			if err != nil {
+				return nil, err
+			}
+			doc.Resources = append(doc.Resources, *rd)
+		}
+	}
+
+	sort.Slice(doc.Resources, func(i, j int) bool {
+		if doc.Resources[i].Group != doc.Resources[j].Group {
+			return doc.Resources[i].Group < doc.Resources[j].Group
+		}
+		return doc.Resources[i].Resource < doc.Resources[j].Resource
+	})
+
+	return doc, nil
+}
+
+// resourceDocumentation renders the documentation of a bound resource. A missing APIExport
+// or documentation ConfigMap only omits the documentation supplied by the APIExport owner.
+func (s *Server) resourceDocumentation(exportClusterName logicalcluster.Name, exportName string, br apisv1alpha1.BoundAPIResource) (*ResourceDocumentation, error) {
+	apiResourceSchema, err := s.getAPIResourceSchema(exportClusterName, br.Schema.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get APIResourceSchema %s|%s of %s.%s: %w", exportClusterName, br.Schema.Name, br.Resource, br.Group, err)
+	}
+
+	rd := &ResourceDocumentation{
+		Group:    br.Group,
+		Resource: br.Resource,
+		Names:    apiResourceSchema.Spec.Names,
+		Scope:    apiResourceSchema.Spec.Scope,
+		Export:   exportClusterName.String() + "|" + exportName,
+	}
+	for _, v := range apiResourceSchema.Spec.Versions {
+		if !v.Served {
+			continue
+		}
+		vd, err := versionDocumentation(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schema of version %s in APIResourceSchema %s|%s: %w", v.Name, exportClusterName, apiResourceSchema.Name, err)
+		}
+		rd.Versions = append(rd.Versions, *vd)
+	}
+
+	entries, err := s.listCatalogEntries(exportClusterName)
+	if err != nil {
+		return nil, err
+	}
+	claims := map[metav1.GroupResource]bool{}
+	for _, entry := range entries {
+		if entry.Spec.ExportName != exportName {
+			continue
+		}
+		for _, claim := range entry.Spec.RequiredClaims {
+			if !claims[claim] {
+				claims[claim] = true
+				rd.RequiredClaims = append(rd.RequiredClaims, claim)
+			}
+		}
+	}
+	sort.Slice(rd.RequiredClaims, func(i, j int) bool {
+		return rd.RequiredClaims[i].String() < rd.RequiredClaims[j].String()
+	})
+
+	export, err := s.getAPIExport(exportClusterName, exportName)
+	if apierrors.IsNotFound(err) {
+		return rd, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if export.Spec.Documentation == nil {
+		return rd, nil
+	}
+	ref := export.Spec.Documentation
+	cm, err := s.getConfigMap(exportClusterName, ref.Namespace, ref.Name)
+	if apierrors.IsNotFound(err) {
+		return rd, nil
+	} else if err != nil {
+		return nil, err
+	}
+	raw, found := cm.Data[ConfigMapKey(schema.GroupResource{Group: br.Group, Resource: br.Resource})]
+	if !found || strings.TrimSpace(raw) == "" {
+		return rd, nil
+	}
+	var notes resourceNotes
+	if err := yaml.Unmarshal([]byte(raw), &notes); err != nil {
+		return nil, fmt.Errorf("invalid documentation of %s.%s in ConfigMap %s/%s of APIExport workspace %s: %w", br.Resource, br.Group, ref.Namespace, ref.Name, exportClusterName, err)
+	}
+	rd.Description = notes.Description
+	rd.Examples = notes.Examples
+
+	return rd, nil
+}
+
+// ConfigMapKey returns the key of the documentation of a resource in the documentation
+// ConfigMap of an APIExport.
+func ConfigMapKey(gr schema.GroupResource) string {
+	return gr.String()
+}
+
+// ServeHTTP serves the documentation of the workspace of the request as JSON. The resource
+// query parameter restricts it to a resource, as <resource>[.<group>].
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	cluster := genericapirequest.ClusterFrom(req.Context())
+	if cluster == nil || cluster.Name.Empty() || cluster.Wildcard {
+		http.Error(w, "the API documentation is only available for a single workspace", http.StatusBadRequest)
+		return
+	}
+
+	var gr schema.GroupResource
+	if resource := req.URL.Query().Get("resource"); resource != "" {
+		gr = schema.ParseGroupResource(resource)
+	}
+
+	doc, err := s.Documentation(cluster.Name, gr)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !gr.Empty() && len(doc.Resources) == 0 {
+		http.Error(w, fmt.Sprintf("resource %q is not bound in workspace %s", gr, cluster.Name), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(doc)
+}
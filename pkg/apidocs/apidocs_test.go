@@ -0,0 +1,187 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apidocs
+
+import (
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+)
+
+const widgetsSchema = `{
+  "type": "object",
+  "description": "Widget is a widget.",
+  "properties": {
+    "spec": {
+      "type": "object",
+      "required": ["size"],
+      "properties": {
+        "size": {"type": "integer", "description": "size of the widget.", "default": 1},
+        "color": {"type": "string", "enum": ["red", "blue"]},
+        "port": {"x-kubernetes-int-or-string": true},
+        "labels": {"type": "object", "additionalProperties": {"type": "string"}},
+        "parts": {
+          "type": "array",
+          "items": {"type": "object", "properties": {"name": {"type": "string"}}}
+        }
+      }
+    }
+  }
+}`
+
+func TestDocumentation(t *testing.T) {
+	binding := &apisv1alpha1.APIBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "widgets", ClusterName: "root:org:team"},
+		Status: apisv1alpha1.APIBindingStatus{
+			BoundAPIExport: &apisv1alpha1.ExportReference{
+				Workspace: &apisv1alpha1.WorkspaceExportReference{WorkspaceName: "provider", ExportName: "widgets"},
+			},
+			BoundResources: []apisv1alpha1.BoundAPIResource{
+				{Group: "example.com", Resource: "widgets", Schema: apisv1alpha1.BoundAPIResourceSchema{Name: "v1.widgets.example.com"}},
+			},
+		},
+	}
+	apiResourceSchema := &apisv1alpha1.APIResourceSchema{
+		ObjectMeta: metav1.ObjectMeta{Name: "v1.widgets.example.com", ClusterName: "root:org:provider"},
+		Spec: apisv1alpha1.APIResourceSchemaSpec{
+			Group: "example.com",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets", Singular: "widget", Kind: "Widget"},
+			Scope: apiextensionsv1.NamespaceScoped,
+			Versions: []apisv1alpha1.APIResourceVersion{
+				{Name: "v1", Served: true, Storage: true, Schema: runtime.RawExtension{Raw: []byte(widgetsSchema)}},
+				{Name: "v0", Served: false},
+			},
+		},
+	}
+	entries := []*apisv1alpha1.CatalogEntry{
+		{Spec: apisv1alpha1.CatalogEntrySpec{ExportName: "widgets", RequiredClaims: []metav1.GroupResource{{Resource: "secrets"}, {Resource: "configmaps"}}}},
+		{Spec: apisv1alpha1.CatalogEntrySpec{ExportName: "widgets-lite", RequiredClaims: []metav1.GroupResource{{Resource: "pods"}}}},
+	}
+	docs := &corev1.ConfigMap{
+		Data: map[string]string{
+			"widgets.example.com": `
+description: Widgets are shipped within a day.
+examples:
+- title: A small widget
+  manifest: |
+    apiVersion: example.com/v1
+    kind: Widget
+    spec:
+      size: 1
+`,
+		},
+	}
+
+	tests := map[string]struct {
+		export    *apisv1alpha1.APIExport
+		configMap *corev1.ConfigMap
+		gr        schema.GroupResource
+		want      []ResourceDocumentation
+	}{
+		"without documentation of the export": {
+			export: &apisv1alpha1.APIExport{},
+			want: []ResourceDocumentation{{
+				Group:          "example.com",
+				Resource:       "widgets",
+				Names:          apiResourceSchema.Spec.Names,
+				Scope:          apiextensionsv1.NamespaceScoped,
+				Export:         "root:org:provider|widgets",
+				RequiredClaims: []metav1.GroupResource{{Resource: "configmaps"}, {Resource: "secrets"}},
+				Versions: []VersionDocumentation{{
+					Name:        "v1",
+					Storage:     true,
+					Description: "Widget is a widget.",
+					Fields: []Field{
+						{Path: "spec", Type: "Object"},
+						{Path: "spec.color", Type: "string", Enum: []string{"red", "blue"}},
+						{Path: "spec.labels", Type: "map[string]string"},
+						{Path: "spec.parts", Type: "[]Object"},
+						{Path: "spec.parts.name", Type: "string"},
+						{Path: "spec.port", Type: "IntOrString"},
+						{Path: "spec.size", Type: "integer", Required: true, Description: "size of the widget.", Default: "1"},
+					},
+				}},
+			}},
+		},
+		"with documentation of the export": {
+			export: &apisv1alpha1.APIExport{
+				Spec: apisv1alpha1.APIExportSpec{Documentation: &apisv1alpha1.ConfigMapReference{Namespace: "docs", Name: "widgets"}},
+			},
+			configMap: docs,
+			gr:        schema.GroupResource{Group: "example.com", Resource: "widgets"},
+		},
+		"other resource": {
+			export: &apisv1alpha1.APIExport{},
+			gr:     schema.GroupResource{Group: "example.com", Resource: "gadgets"},
+			want:   []ResourceDocumentation{},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := &Server{
+				listAPIBindings: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
+					require.Equal(t, "root:org:team", clusterName.String())
+					return []*apisv1alpha1.APIBinding{binding}, nil
+				},
+				getAPIExport: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error) {
+					require.Equal(t, "root:org:provider", clusterName.String())
+					require.Equal(t, "widgets", name)
+					return tt.export, nil
+				},
+				getAPIResourceSchema: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error) {
+					require.Equal(t, "root:org:provider", clusterName.String())
+					require.Equal(t, apiResourceSchema.Name, name)
+					return apiResourceSchema, nil
+				},
+				listCatalogEntries: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.CatalogEntry, error) {
+					return entries, nil
+				},
+				getConfigMap: func(clusterName logicalcluster.Name, namespace, name string) (*corev1.ConfigMap, error) {
+					if tt.configMap == nil {
+						return nil, apierrors.NewNotFound(corev1.Resource("configmaps"), name)
+					}
+					require.Equal(t, "docs", namespace)
+					return tt.configMap, nil
+				},
+			}
+
+			got, err := s.Documentation(logicalcluster.New("root:org:team"), tt.gr)
+			require.NoError(t, err)
+			if tt.configMap == nil {
+				require.Equal(t, tt.want, got.Resources)
+				return
+			}
+
+			require.Len(t, got.Resources, 1)
+			require.Equal(t, "Widgets are shipped within a day.", got.Resources[0].Description)
+			require.Equal(t, []Example{{
+				Title:    "A small widget",
+				Manifest: "apiVersion: example.com/v1\nkind: Widget\nspec:\n  size: 1\n",
+			}}, got.Resources[0].Examples)
+		})
+	}
+}
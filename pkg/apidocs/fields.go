@@ -0,0 +1,134 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apidocs
+
+import (
+	"encoding/json"
+	"sort"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+)
+
+// versionDocumentation flattens the OpenAPI schema of the version into its fields.
+func versionDocumentation(v apisv1alpha1.APIResourceVersion) (*VersionDocumentation, error) {
+	vd := &VersionDocumentation{
+		Name:       v.Name,
+		Storage:    v.Storage,
+		Deprecated: v.Deprecated,
+	}
+	if v.DeprecationWarning != nil {
+		vd.DeprecationWarning = *v.DeprecationWarning
+	}
+	if len(v.Schema.Raw) == 0 {
+		return vd, nil
+	}
+
+	var props apiextensionsv1.JSONSchemaProps
+	if err := json.Unmarshal(v.Schema.Raw, &props); err != nil {
+		return nil, err
+	}
+	vd.Description = props.Description
+	vd.Fields = fields("", &props, nil)
+
+	return vd, nil
+}
+
+// fields appends the fields of the object schema to ret, depth first and sorted
+// by name on each level.
+func fields(prefix string, props *apiextensionsv1.JSONSchemaProps, ret []Field) []Field {
+	names := make([]string, 0, len(props.Properties))
+	for name := range props.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	required := sets.NewString(props.Required...)
+
+	for _, name := range names {
+		child := props.Properties[name]
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+
+		f := Field{
+			Path:        path,
+			Type:        typeName(&child),
+			Required:    required.Has(name),
+			Description: child.Description,
+		}
+		for _, e := range child.Enum {
+			f.Enum = append(f.Enum, jsonString(e.Raw))
+		}
+		if child.Default != nil {
+			f.Default = jsonString(child.Default.Raw)
+		}
+		ret = append(ret, f)
+
+		if elem := elementSchema(&child); len(elem.Properties) > 0 {
+			ret = fields(path, elem, ret)
+		}
+	}
+
+	return ret
+}
+
+// elementSchema returns the schema of the items of lists and the values of maps, or
+// the schema itself for all other types.
+func elementSchema(props *apiextensionsv1.JSONSchemaProps) *apiextensionsv1.JSONSchemaProps {
+	for {
+		switch {
+		case props.Type == "array" && props.Items != nil && props.Items.Schema != nil:
+			props = props.Items.Schema
+		case props.Type == "object" && len(props.Properties) == 0 && props.AdditionalProperties != nil && props.AdditionalProperties.Schema != nil:
+			props = props.AdditionalProperties.Schema
+		default:
+			return props
+		}
+	}
+}
+
+// typeName names the type of a field the way kubectl explain does, e.g. "[]Object"
+// or "map[string]string".
+func typeName(props *apiextensionsv1.JSONSchemaProps) string {
+	switch {
+	case props.XIntOrString:
+		return "IntOrString"
+	case props.Type == "array":
+		if props.Items != nil && props.Items.Schema != nil {
+			return "[]" + typeName(props.Items.Schema)
+		}
+		return "[]Object"
+	case props.Type == "object" && len(props.Properties) == 0 && props.AdditionalProperties != nil && props.AdditionalProperties.Schema != nil:
+		return "map[string]" + typeName(props.AdditionalProperties.Schema)
+	case props.Type == "object", props.Type == "":
+		return "Object"
+	default:
+		return props.Type
+	}
+}
+
+// jsonString returns strings unquoted, and all other values as JSON.
+func jsonString(raw []byte) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	return string(raw)
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package apiexportidentity stores the identity keys of APIExports. The identity key of an
+// APIExport determines the etcd prefix its data are stored under, i.e. whoever knows it can
+// serve the APIs of the export. By default, the keys are stored in Secrets in the workspace of
+// the APIExport. Other backends, e.g. Vault, keep them out of kcp altogether.
+package apiexportidentity
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Backend stores identity keys. Keys are addressed by the logical cluster of the APIExport and
+// the reference in its spec.identity.secretRef.
+type Backend interface {
+	// Get returns the identity key, or a NotFound error if it does not exist.
+	Get(ctx context.Context, clusterName logicalcluster.Name, ref corev1.SecretReference) ([]byte, error)
+	// Create stores a new identity key. It returns an AlreadyExists error if a key exists already.
+	Create(ctx context.Context, clusterName logicalcluster.Name, ref corev1.SecretReference, key []byte) error
+	// AddRotationHandler registers a handler that is called when an identity key changed in the
+	// backend, e.g. after a rotation, such that the APIExports using it are verified again.
+	AddRotationHandler(handler RotationHandler)
+}
+
+// RotationHandler is called with the logical cluster and reference of a changed identity key.
+type RotationHandler func(clusterName logicalcluster.Name, ref corev1.SecretReference)
+
+var identitiesResource = schema.GroupResource{Group: "apis.kcp.dev", Resource: "identities"}
+
+func newNotFound(clusterName logicalcluster.Name, ref corev1.SecretReference) error {
+	return apierrors.NewNotFound(identitiesResource, refString(clusterName, ref))
+}
+
+func newAlreadyExists(clusterName logicalcluster.Name, ref corev1.SecretReference) error {
+	return apierrors.NewAlreadyExists(identitiesResource, refString(clusterName, ref))
+}
+
+func refString(clusterName logicalcluster.Name, ref corev1.SecretReference) string {
+	return fmt.Sprintf("%s|%s/%s", clusterName, ref.Namespace, ref.Name)
+}
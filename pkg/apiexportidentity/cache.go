@@ -0,0 +1,122 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiexportidentity
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/clock"
+)
+
+// NewCachingBackend returns a backend caching the keys of the delegate for the given TTL. When
+// a refreshed key differs from the cached one, the rotation handlers are called. Rotations
+// reported by the delegate invalidate the cache.
+func NewCachingBackend(delegate Backend, ttl time.Duration) Backend {
+	return newCachingBackend(delegate, ttl, clock.RealClock{})
+}
+
+func newCachingBackend(delegate Backend, ttl time.Duration, clock clock.Clock) *cachingBackend {
+	b := &cachingBackend{
+		delegate: delegate,
+		ttl:      ttl,
+		clock:    clock,
+		entries:  map[string]cacheEntry{},
+	}
+	delegate.AddRotationHandler(func(clusterName logicalcluster.Name, ref corev1.SecretReference) {
+		b.invalidate(clusterName, ref)
+		b.notify(clusterName, ref)
+	})
+	return b
+}
+
+type cachingBackend struct {
+	delegate Backend
+	ttl      time.Duration
+	clock    clock.Clock
+
+	lock     sync.RWMutex
+	entries  map[string]cacheEntry
+	handlers []RotationHandler
+}
+
+type cacheEntry struct {
+	key     []byte
+	expires time.Time
+}
+
+func (b *cachingBackend) Get(ctx context.Context, clusterName logicalcluster.Name, ref corev1.SecretReference) ([]byte, error) {
+	k := refString(clusterName, ref)
+
+	b.lock.RLock()
+	entry, found := b.entries[k]
+	b.lock.RUnlock()
+	if found && b.clock.Now().Before(entry.expires) {
+		return entry.key, nil
+	}
+
+	key, err := b.delegate.Get(ctx, clusterName, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	b.lock.Lock()
+	b.entries[k] = cacheEntry{key: key, expires: b.clock.Now().Add(b.ttl)}
+	b.lock.Unlock()
+
+	if found && !bytes.Equal(entry.key, key) {
+		b.notify(clusterName, ref)
+	}
+	return key, nil
+}
+
+func (b *cachingBackend) Create(ctx context.Context, clusterName logicalcluster.Name, ref corev1.SecretReference, key []byte) error {
+	if err := b.delegate.Create(ctx, clusterName, ref, key); err != nil {
+		return err
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.entries[refString(clusterName, ref)] = cacheEntry{key: key, expires: b.clock.Now().Add(b.ttl)}
+	return nil
+}
+
+func (b *cachingBackend) AddRotationHandler(handler RotationHandler) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.handlers = append(b.handlers, handler)
+}
+
+func (b *cachingBackend) invalidate(clusterName logicalcluster.Name, ref corev1.SecretReference) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	delete(b.entries, refString(clusterName, ref))
+}
+
+func (b *cachingBackend) notify(clusterName logicalcluster.Name, ref corev1.SecretReference) {
+	b.lock.RLock()
+	handlers := b.handlers
+	b.lock.RUnlock()
+	for _, handler := range handlers {
+		handler(clusterName, ref)
+	}
+}
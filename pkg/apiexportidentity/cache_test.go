@@ -0,0 +1,101 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiexportidentity
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/clock"
+)
+
+type fakeBackend struct {
+	keys     map[string][]byte
+	gets     int
+	handlers []RotationHandler
+}
+
+func (b *fakeBackend) Get(ctx context.Context, clusterName logicalcluster.Name, ref corev1.SecretReference) ([]byte, error) {
+	b.gets++
+	key, found := b.keys[refString(clusterName, ref)]
+	if !found {
+		return nil, newNotFound(clusterName, ref)
+	}
+	return key, nil
+}
+
+func (b *fakeBackend) Create(ctx context.Context, clusterName logicalcluster.Name, ref corev1.SecretReference, key []byte) error {
+	if _, found := b.keys[refString(clusterName, ref)]; found {
+		return newAlreadyExists(clusterName, ref)
+	}
+	b.keys[refString(clusterName, ref)] = key
+	return nil
+}
+
+func (b *fakeBackend) AddRotationHandler(handler RotationHandler) {
+	b.handlers = append(b.handlers, handler)
+}
+
+func TestCachingBackend(t *testing.T) {
+	ctx := context.Background()
+	clusterName := logicalcluster.New("root:org:ws")
+	ref := corev1.SecretReference{Namespace: "kcp-system", Name: "export"}
+
+	delegate := &fakeBackend{keys: map[string][]byte{}}
+	fakeClock := clock.NewFakeClock(time.Now())
+	b := newCachingBackend(delegate, time.Minute, fakeClock)
+
+	var rotated []string
+	b.AddRotationHandler(func(clusterName logicalcluster.Name, ref corev1.SecretReference) {
+		rotated = append(rotated, refString(clusterName, ref))
+	})
+
+	_, err := b.Get(ctx, clusterName, ref)
+	require.Error(t, err, "missing keys must not be cached")
+
+	require.NoError(t, b.Create(ctx, clusterName, ref, []byte("key-1")))
+	key, err := b.Get(ctx, clusterName, ref)
+	require.NoError(t, err)
+	require.Equal(t, "key-1", string(key))
+	require.Equal(t, 1, delegate.gets, "created key should have been served from the cache")
+
+	delegate.keys[refString(clusterName, ref)] = []byte("key-2")
+	key, err = b.Get(ctx, clusterName, ref)
+	require.NoError(t, err)
+	require.Equal(t, "key-1", string(key), "key should be cached until expiry")
+	require.Empty(t, rotated)
+
+	fakeClock.Step(2 * time.Minute)
+	key, err = b.Get(ctx, clusterName, ref)
+	require.NoError(t, err)
+	require.Equal(t, "key-2", string(key))
+	require.Equal(t, []string{refString(clusterName, ref)}, rotated, "changed key should have been reported")
+
+	delegate.keys[refString(clusterName, ref)] = []byte("key-3")
+	for _, handler := range delegate.handlers {
+		handler(clusterName, ref)
+	}
+	require.Len(t, rotated, 2, "rotation of the delegate should have been passed on")
+	key, err = b.Get(ctx, clusterName, ref)
+	require.NoError(t, err)
+	require.Equal(t, "key-3", string(key), "rotation of the delegate should have invalidated the cache")
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiexportidentity
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clusters"
+	"k8s.io/klog/v2"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+)
+
+// NewSecretBackend returns a backend storing identity keys in the "key" of Secrets in the
+// workspaces of the APIExports. The namespaces of the Secrets are created on demand.
+func NewSecretBackend(kubeClusterClient kubernetes.ClusterInterface, namespaceInformer coreinformers.NamespaceInformer, secretInformer coreinformers.SecretInformer) Backend {
+	return &secretBackend{
+		kubeClusterClient: kubeClusterClient,
+		namespaceLister:   namespaceInformer.Lister(),
+		secretLister:      secretInformer.Lister(),
+		secretInformer:    secretInformer.Informer(),
+	}
+}
+
+type secretBackend struct {
+	kubeClusterClient kubernetes.ClusterInterface
+	namespaceLister   corelisters.NamespaceLister
+	secretLister      corelisters.SecretLister
+	secretInformer    cache.SharedIndexInformer
+}
+
+func (b *secretBackend) Get(ctx context.Context, clusterName logicalcluster.Name, ref corev1.SecretReference) ([]byte, error) {
+	secret, err := b.secretLister.Secrets(ref.Namespace).Get(clusters.ToClusterAwareKey(clusterName, ref.Name))
+	if err != nil {
+		// In case the lister is slow to catch up, try a live read
+		secret, err = b.kubeClusterClient.Cluster(clusterName).CoreV1().Secrets(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	key := secret.Data[apisv1alpha1.SecretKeyAPIExportIdentity]
+	if len(key) == 0 {
+		return nil, fmt.Errorf("secret is missing data.%s", apisv1alpha1.SecretKeyAPIExportIdentity)
+	}
+	return key, nil
+}
+
+func (b *secretBackend) Create(ctx context.Context, clusterName logicalcluster.Name, ref corev1.SecretReference, key []byte) error {
+	if _, err := b.namespaceLister.Get(clusters.ToClusterAwareKey(clusterName, ref.Namespace)); apierrors.IsNotFound(err) {
+		ns := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: ref.Namespace,
+			},
+		}
+		if _, err := b.kubeClusterClient.Cluster(clusterName).CoreV1().Namespaces().Create(ctx, ns, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+			klog.Errorf("Error creating namespace %q in cluster %q for APIExport secret identities: %v", ref.Namespace, clusterName, err)
+			// Keep going - maybe things will work. If the secret creation fails, the caller will know.
+		}
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ref.Namespace,
+			Name:      ref.Name,
+		},
+		Data: map[string][]byte{
+			apisv1alpha1.SecretKeyAPIExportIdentity: key,
+		},
+	}
+	_, err := b.kubeClusterClient.Cluster(clusterName).CoreV1().Secrets(ref.Namespace).Create(ctx, secret, metav1.CreateOptions{})
+	return err
+}
+
+func (b *secretBackend) AddRotationHandler(handler RotationHandler) {
+	notify := func(obj interface{}) {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+		secret, ok := obj.(*corev1.Secret)
+		if !ok {
+			return
+		}
+		handler(logicalcluster.From(secret), corev1.SecretReference{Namespace: secret.Namespace, Name: secret.Name})
+	}
+	b.secretInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    notify,
+		UpdateFunc: func(_, obj interface{}) { notify(obj) },
+		DeleteFunc: notify,
+	})
+}
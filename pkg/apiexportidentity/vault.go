@@ -0,0 +1,176 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiexportidentity
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	corev1 "k8s.io/api/core/v1"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+)
+
+// VaultConfig configures a Vault backend.
+type VaultConfig struct {
+	// Address is the URL of the Vault server, e.g. https://vault.example.com:8200.
+	Address string
+	// TokenFile is a file holding the Vault token. It is read on every request such that the
+	// token can be renewed by an agent without restarting kcp.
+	TokenFile string
+	// Mount is the mount path of the KV version 2 secrets engine.
+	Mount string
+	// PathPrefix is the path under the mount the identity keys are stored under.
+	PathPrefix string
+	// Client is the HTTP client used to talk to Vault. Defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+// NewVaultBackend returns a backend storing identity keys in a Vault KV version 2 secrets
+// engine, at <mount>/<path prefix>/<logical cluster>/<namespace>/<name>.
+//
+// Vault does not notify about changed keys. Wrap the backend with NewCachingBackend to detect
+// changes when cached keys are refreshed.
+func NewVaultBackend(config VaultConfig) (Backend, error) {
+	if config.Address == "" {
+		return nil, fmt.Errorf("vault address is required")
+	}
+	if config.TokenFile == "" {
+		return nil, fmt.Errorf("vault token file is required")
+	}
+	if config.Mount == "" {
+		config.Mount = "secret"
+	}
+	if config.Client == nil {
+		config.Client = http.DefaultClient
+	}
+	return &vaultBackend{config: config}, nil
+}
+
+type vaultBackend struct {
+	config VaultConfig
+}
+
+type vaultKV struct {
+	Options map[string]interface{} `json:"options,omitempty"`
+	Data    map[string]string      `json:"data"`
+}
+
+type vaultResponse struct {
+	Data   vaultKV  `json:"data"`
+	Errors []string `json:"errors"`
+}
+
+func (b *vaultBackend) Get(ctx context.Context, clusterName logicalcluster.Name, ref corev1.SecretReference) ([]byte, error) {
+	resp, err := b.do(ctx, http.MethodGet, clusterName, ref, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, newNotFound(clusterName, ref)
+	}
+	var body vaultResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode vault response for %s: %w", refString(clusterName, ref), err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to read %s from vault: %d %s", refString(clusterName, ref), resp.StatusCode, strings.Join(body.Errors, ", "))
+	}
+
+	key := body.Data.Data[apisv1alpha1.SecretKeyAPIExportIdentity]
+	if key == "" {
+		return nil, fmt.Errorf("vault secret is missing data.%s", apisv1alpha1.SecretKeyAPIExportIdentity)
+	}
+	return []byte(key), nil
+}
+
+func (b *vaultBackend) Create(ctx context.Context, clusterName logicalcluster.Name, ref corev1.SecretReference, key []byte) error {
+	// cas=0 makes the write fail if the secret exists already
+	payload, err := json.Marshal(vaultKV{
+		Options: map[string]interface{}{"cas": 0},
+		Data:    map[string]string{apisv1alpha1.SecretKeyAPIExportIdentity: string(key)},
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := b.do(ctx, http.MethodPost, clusterName, ref, payload)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	var body vaultResponse
+	_ = json.NewDecoder(resp.Body).Decode(&body)
+	for _, msg := range body.Errors {
+		if strings.Contains(msg, "check-and-set") {
+			return newAlreadyExists(clusterName, ref)
+		}
+	}
+	return fmt.Errorf("failed to write %s to vault: %d %s", refString(clusterName, ref), resp.StatusCode, strings.Join(body.Errors, ", "))
+}
+
+func (b *vaultBackend) AddRotationHandler(handler RotationHandler) {
+	// Vault has no change notifications.
+}
+
+func (b *vaultBackend) do(ctx context.Context, method string, clusterName logicalcluster.Name, ref corev1.SecretReference, payload []byte) (*http.Response, error) {
+	token, err := os.ReadFile(b.config.TokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vault token: %w", err)
+	}
+
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, b.url(clusterName, ref), body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", strings.TrimSpace(string(token)))
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return b.config.Client.Do(req)
+}
+
+func (b *vaultBackend) url(clusterName logicalcluster.Name, ref corev1.SecretReference) string {
+	return strings.TrimSuffix(b.config.Address, "/") + "/v1/" + path.Join(
+		b.config.Mount,
+		"data",
+		b.config.PathPrefix,
+		url.PathEscape(clusterName.String()),
+		url.PathEscape(ref.Namespace),
+		url.PathEscape(ref.Name),
+	)
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiexportidentity
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// fakeVault implements the subset of the KV version 2 API used by the backend.
+type fakeVault struct {
+	lock    sync.Mutex
+	secrets map[string]map[string]string
+}
+
+func (v *fakeVault) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Header.Get("X-Vault-Token") != "s3cr3t" {
+		w.WriteHeader(http.StatusForbidden)
+		_ = json.NewEncoder(w).Encode(vaultResponse{Errors: []string{"permission denied"}})
+		return
+	}
+
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	switch req.Method {
+	case http.MethodGet:
+		data, found := v.secrets[req.URL.EscapedPath()]
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(vaultResponse{})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(vaultResponse{Data: vaultKV{Data: data}})
+	case http.MethodPost:
+		var body vaultKV
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if _, found := v.secrets[req.URL.EscapedPath()]; found && body.Options["cas"] == float64(0) {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(vaultResponse{Errors: []string{"check-and-set parameter did not match the current version"}})
+			return
+		}
+		v.secrets[req.URL.EscapedPath()] = body.Data
+		w.WriteHeader(http.StatusOK)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestVaultBackend(t *testing.T) {
+	ctx := context.Background()
+	vault := &fakeVault{secrets: map[string]map[string]string{}}
+	server := httptest.NewServer(vault)
+	defer server.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("s3cr3t\n"), 0600))
+
+	b, err := NewVaultBackend(VaultConfig{
+		Address:    server.URL,
+		TokenFile:  tokenFile,
+		Mount:      "kv",
+		PathPrefix: "kcp/identities",
+	})
+	require.NoError(t, err)
+
+	clusterName := logicalcluster.New("root:org:ws")
+	ref := corev1.SecretReference{Namespace: "kcp-system", Name: "export"}
+
+	_, err = b.Get(ctx, clusterName, ref)
+	require.True(t, apierrors.IsNotFound(err), "expected NotFound, got %v", err)
+
+	require.NoError(t, b.Create(ctx, clusterName, ref, []byte("key-1")))
+	require.Contains(t, vault.secrets, "/v1/kv/data/kcp/identities/root:org:ws/kcp-system/export")
+
+	key, err := b.Get(ctx, clusterName, ref)
+	require.NoError(t, err)
+	require.Equal(t, "key-1", string(key))
+
+	err = b.Create(ctx, clusterName, ref, []byte("key-2"))
+	require.True(t, apierrors.IsAlreadyExists(err), "expected AlreadyExists, got %v", err)
+
+	require.NoError(t, os.WriteFile(tokenFile, []byte("wrong"), 0600))
+	_, err = b.Get(ctx, clusterName, ref)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "permission denied")
+}
+
+func TestNewVaultBackendValidation(t *testing.T) {
+	_, err := NewVaultBackend(VaultConfig{TokenFile: "token"})
+	require.Error(t, err)
+	_, err = NewVaultBackend(VaultConfig{Address: "https://vault"})
+	require.Error(t, err)
+}
@@ -53,6 +53,21 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 
 		&APIResourceSchema{},
 		&APIResourceSchemaList{},
+
+		&CatalogEntry{},
+		&CatalogEntryList{},
+
+		&SharedSecret{},
+		&SharedSecretList{},
+
+		&SecretClaim{},
+		&SecretClaimList{},
+
+		&EventSubscription{},
+		&EventSubscriptionList{},
+
+		&TTLPolicy{},
+		&TTLPolicyList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
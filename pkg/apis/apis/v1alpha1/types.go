@@ -66,8 +66,55 @@ type APIBindingSpec struct {
 	// +required
 	// +kubebuilder:validation:Required
 	Reference ExportReference `json:"reference"`
+
+	// conflictPolicy decides which API is served when a CRD in the workspace defines the
+	// same group and resource as a resource bound by this APIBinding. With APIBinding, the
+	// bound resource is served and the CRD is not. With CRD, the CRD is served and the bound
+	// resource is not.
+	//
+	// +optional
+	// +kubebuilder:default:="APIBinding"
+	ConflictPolicy CRDConflictPolicy `json:"conflictPolicy,omitempty"`
+
+	// providerRateLimit limits the rate of the requests of the provider of the bound APIExport
+	// to this workspace, i.e. of requests like
+	// /clusters/<workspace>/apis/<group>/<version>/<resource>:<identity hash>. Requests for the
+	// bound resources without the identity hash count too, as those of the provider cannot be
+	// told apart from those of other clients. Requests above the rate are rejected with 429 Too
+	// Many Requests. It applies in addition to the limits kcp is started with.
+	//
+	// +optional
+	ProviderRateLimit *ProviderRateLimit `json:"providerRateLimit,omitempty"`
+}
+
+// ProviderRateLimit is a token bucket rate limit of the requests of an API provider.
+type ProviderRateLimit struct {
+	// qps is the number of requests allowed per second.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=1
+	QPS int32 `json:"qps"`
+
+	// burst is the number of requests allowed on top of qps at once. It defaults to qps.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Burst int32 `json:"burst,omitempty"`
 }
 
+// CRDConflictPolicy decides which API is served when a CRD in a workspace overlaps a bound resource.
+//
+// +kubebuilder:validation:Enum=APIBinding;CRD
+type CRDConflictPolicy string
+
+const (
+	// CRDConflictPolicyAPIBinding serves the bound resource instead of the overlapping CRD.
+	CRDConflictPolicyAPIBinding CRDConflictPolicy = "APIBinding"
+	// CRDConflictPolicyCRD serves the overlapping CRD instead of the bound resource.
+	CRDConflictPolicyCRD CRDConflictPolicy = "CRD"
+)
+
 // ExportReference describes a reference to an APIExport. Exactly one of the
 // fields must be set.
 type ExportReference struct {
@@ -138,6 +185,24 @@ type APIBindingStatus struct {
 	// +kubebuilder:validation:Enum="";Binding;Bound
 	Phase APIBindingPhaseType `json:"phase,omitempty"`
 
+	// schemaCompatibility is the result of validating the objects in the workspace against the latest
+	// resource schemas of the APIExport. The APIBinding is only upgraded to the latest schemas when all
+	// objects are valid according to them. It is unset when the bound schemas are up-to-date.
+	//
+	// +optional
+	SchemaCompatibility *SchemaCompatibilityReport `json:"schemaCompatibility,omitempty"`
+
+	// deprecatedVersionUsage reports the requests to deprecated versions of the bound resources
+	// in this workspace, i.e. whether removing a version breaks clients. Versions not requested
+	// for 30 days are removed.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=group
+	// +listMapKey=resource
+	// +listMapKey=version
+	DeprecatedVersionUsage []DeprecatedVersionUsage `json:"deprecatedVersionUsage,omitempty"`
+
 	// conditions is a list of conditions that apply to the APIBinding.
 	//
 	// +optional
@@ -172,8 +237,134 @@ const (
 	// NamingConflictsReason is a reason for the BindingUpToDate condition that at least one API coming in from the APIBinding
 	// has a naming conflict with other APIs.
 	NamingConflictsReason = "NamingConflicts"
+	// WaitingForSchemaCompatibilityReason is a reason for the BindingUpToDate condition that the objects in the
+	// workspace have not been validated against the latest resource schemas of the APIExport yet.
+	WaitingForSchemaCompatibilityReason = "WaitingForSchemaCompatibility"
+	// IncompatibleObjectsReason is a reason for the BindingUpToDate condition that objects in the workspace are
+	// invalid according to the latest resource schemas of the APIExport.
+	IncompatibleObjectsReason = "IncompatibleObjects"
+
+	// NoCRDConflicts is a condition for APIBinding that indicates that no CRD in the workspace defines the
+	// same group and resource as a bound resource.
+	NoCRDConflicts conditionsv1alpha1.ConditionType = "NoCRDConflicts"
+
+	// CRDShadowedReason is a reason for the NoCRDConflicts condition that CRDs in the workspace are not served
+	// because bound resources take precedence.
+	CRDShadowedReason = "CRDShadowed"
+	// ShadowedByCRDReason is a reason for the NoCRDConflicts condition that bound resources are not served
+	// because CRDs in the workspace take precedence.
+	ShadowedByCRDReason = "ShadowedByCRD"
 )
 
+// SchemaCompatibilityReport lists the objects of a workspace that are invalid according to
+// a set of APIResourceSchemas.
+type SchemaCompatibilityReport struct {
+	// schemaUIDs are the UIDs of the APIResourceSchemas the objects were validated against.
+	//
+	// +required
+	SchemaUIDs []string `json:"schemaUIDs"`
+
+	// checkedObjects is the number of objects validated.
+	//
+	// +required
+	CheckedObjects int64 `json:"checkedObjects"`
+
+	// invalidObjectCount is the number of invalid objects.
+	//
+	// +required
+	InvalidObjectCount int64 `json:"invalidObjectCount"`
+
+	// invalidObjects are the invalid objects, with the reasons they are invalid, e.g. missing
+	// required fields. At most 100 objects are listed.
+	//
+	// +optional
+	InvalidObjects []IncompatibleObject `json:"invalidObjects,omitempty"`
+}
+
+// DeprecatedVersionUsage reports the requests to a deprecated version of a bound resource.
+type DeprecatedVersionUsage struct {
+	// group is the group of the bound resource. Empty string for the core API group.
+	//
+	// +required
+	Group string `json:"group"`
+
+	// resource is the bound resource.
+	//
+	// +required
+	Resource string `json:"resource"`
+
+	// version is the deprecated version.
+	//
+	// +required
+	Version string `json:"version"`
+
+	// requests is the number of requests to the version.
+	//
+	// +required
+	Requests int64 `json:"requests"`
+
+	// lastRequestTime is the time of the last request to the version.
+	//
+	// +required
+	LastRequestTime metav1.Time `json:"lastRequestTime"`
+
+	// clients are the clients with the most requests to the version, at most 10.
+	//
+	// +optional
+	Clients []DeprecatedVersionClient `json:"clients,omitempty"`
+}
+
+// DeprecatedVersionClient is a client of a deprecated version of a bound resource.
+type DeprecatedVersionClient struct {
+	// user is the name of the user sending the requests.
+	//
+	// +required
+	User string `json:"user"`
+
+	// userAgent is the user agent of the client, e.g. "kubectl/v1.24.0 (linux/amd64)".
+	//
+	// +optional
+	UserAgent string `json:"userAgent,omitempty"`
+
+	// requests is the number of requests of the client.
+	//
+	// +required
+	Requests int64 `json:"requests"`
+
+	// lastRequestTime is the time of the last request of the client.
+	//
+	// +required
+	LastRequestTime metav1.Time `json:"lastRequestTime"`
+}
+
+// IncompatibleObject is an object that is invalid according to a new APIResourceSchema.
+type IncompatibleObject struct {
+	// group is the group of the object. Empty string for the core API group.
+	//
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// resource is the resource of the object.
+	//
+	// +required
+	Resource string `json:"resource"`
+
+	// namespace is the namespace of the object. Empty for cluster-scoped objects.
+	//
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// name is the name of the object.
+	//
+	// +required
+	Name string `json:"name"`
+
+	// errors are the validation errors of the object.
+	//
+	// +required
+	Errors []string `json:"errors"`
+}
+
 // These are annotations for bound CRDs
 const (
 	// AnnotationBoundCRDKey is the annotation key that indicates a CRD is for an APIExport (a "bound CRD").
@@ -190,6 +381,10 @@ const (
 	AnnotationAPIIdentityKey = "apis.kcp.dev/identity"
 )
 
+// AnnotationDryRunReportKey is the annotation key set on APIBindings returned from a dry-run creation, holding
+// a JSON report of the resources that would be bound and the conflicts that would prevent binding.
+const AnnotationDryRunReportKey = "apis.kcp.dev/dry-run-report"
+
 // BoundAPIResource describes a bound GroupVersionResource through an APIResourceSchema of an APIExport..
 type BoundAPIResource struct {
 	// group is the group of the bound API. Empty string for the core API group.
@@ -339,8 +534,136 @@ type APIExportSpec struct {
 	//
 	// +optional
 	Identity *Identity `json:"identity"`
+
+	// defaults designates ConfigMaps in the workspace of the APIExport holding default values
+	// for new objects of the exported resources. The defaults can be changed by the provider
+	// at any time without publishing new APIResourceSchemas. They only apply to objects created
+	// afterwards.
+	//
+	// +optional
+	Defaults []ResourceDefaults `json:"defaults,omitempty"`
+
+	// warnings are returned to clients of the exported resources in Warning headers, e.g. to
+	// announce the deprecation of a version or a field before its removal. Warnings for
+	// resources not exported by this APIExport are ignored.
+	//
+	// +optional
+	Warnings []APIWarning `json:"warnings,omitempty"`
+
+	// documentation references a ConfigMap in the workspace of the APIExport holding documentation
+	// of the exported resources, e.g. examples. It is served to consumers together with the field
+	// descriptions of the APIResourceSchemas. The "<resource>.<group>" key of the ConfigMap, or
+	// "<resource>" for the core group, holds a YAML object with a "description" and "examples"
+	// of the resource.
+	//
+	// +optional
+	Documentation *ConfigMapReference `json:"documentation,omitempty"`
+
+	// statusWriters restricts who can write the status subresource of the exported resources
+	// in the workspaces binding them. With Provider, only requests naming the resource with
+	// the identity of the APIExport, e.g. cowboys:<identityHash>, can write it, as the provider
+	// does. Other requests can only read it, independently of their RBAC permissions. With Any,
+	// access to the status subresource is authorized as usual.
+	//
+	// +optional
+	// +kubebuilder:default=Any
+	StatusWriters StatusWriters `json:"statusWriters,omitempty"`
+}
+
+// StatusWriters defines who can write the status subresource of exported resources.
+//
+// +kubebuilder:validation:Enum=Any;Provider
+type StatusWriters string
+
+const (
+	StatusWritersAny      StatusWriters = "Any"
+	StatusWritersProvider StatusWriters = "Provider"
+)
+
+// APIWarning is a warning returned to clients of an exported resource.
+type APIWarning struct {
+	// group is the API group of the resource. Empty string means the core API group.
+	//
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// resource is the name of the resource, in plural form.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Resource string `json:"resource"`
+
+	// version restricts the warning to requests to a version of the resource. If empty,
+	// the warning applies to all versions.
+	//
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// fieldPath restricts the warning to requests creating or updating objects which set
+	// the field, in dot notation, e.g. "spec.template". If empty, the warning applies to
+	// all requests.
+	//
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[^.]+(\.[^.]+)*$`
+	FieldPath string `json:"fieldPath,omitempty"`
+
+	// message is the text of the warning.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=256
+	Message string `json:"message"`
+}
+
+// ResourceDefaults designates the ConfigMaps holding default values for new objects of an exported resource.
+type ResourceDefaults struct {
+	// group is the API group of the resource. Empty string means the core API group.
+	//
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// resource is the name of the resource, in plural form.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Resource string `json:"resource"`
+
+	// configMapRef references a ConfigMap in the workspace of the APIExport. The "defaults"
+	// key of the ConfigMap holds a JSON or YAML object, which is merged into new objects in
+	// all consuming workspaces. Fields set in the new object take precedence.
+	//
+	// Defaults for a single consuming workspace are taken from a ConfigMap in the same namespace
+	// named "<name>.<workspace>", with the colons in the workspace replaced by dots, e.g.
+	// "tiers.root.org.team". They take precedence over the defaults of all workspaces.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	ConfigMapRef ConfigMapReference `json:"configMapRef"`
+}
+
+// ConfigMapReference references a ConfigMap in the same workspace.
+type ConfigMapReference struct {
+	// namespace is the namespace of the ConfigMap.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+
+	// name is the name of the ConfigMap.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
 }
 
+// DefaultsConfigMapKey is the key in a defaults ConfigMap holding the default values.
+const DefaultsConfigMapKey = "defaults"
+
 // Identity defines the identity of an APIExport, i.e. determines the etcd prefix
 // data of this APIExport are stored under.
 type Identity struct {
@@ -362,6 +685,58 @@ type APIExportStatus struct {
 	//
 	// +optional
 	Conditions conditionsv1alpha1.Conditions `json:"conditions,omitempty"`
+
+	// usage summarizes the objects of the exported resources across all workspaces
+	// binding the APIExport. It is updated periodically.
+	//
+	// +optional
+	Usage *APIExportUsage `json:"usage,omitempty"`
+}
+
+// APIExportUsage summarizes the objects of the exported resources across all workspaces
+// binding an APIExport.
+type APIExportUsage struct {
+	// bindings is the number of APIBindings bound to the APIExport.
+	//
+	// +required
+	Bindings int64 `json:"bindings"`
+
+	// resources lists the objects of every bound resource.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=group
+	// +listMapKey=resource
+	Resources []APIExportResourceUsage `json:"resources,omitempty"`
+
+	// lastUpdateTime is the time the usage was computed.
+	//
+	// +optional
+	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// APIExportResourceUsage summarizes the objects of an exported resource.
+type APIExportResourceUsage struct {
+	// group is the group of the resource. Empty string for the core API group.
+	//
+	// +required
+	Group string `json:"group"`
+
+	// resource is the resource name.
+	//
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	Resource string `json:"resource"`
+
+	// objects is the number of objects of the resource across all binding workspaces.
+	//
+	// +required
+	Objects int64 `json:"objects"`
+
+	// storageBytes is the size of these objects serialized as JSON, as they are stored.
+	//
+	// +required
+	StorageBytes int64 `json:"storageBytes"`
 }
 
 // APIExportList is a list of APIExport resources
@@ -459,7 +834,8 @@ type APIResourceVersion struct {
 	// +optional
 	DeprecationWarning *string `json:"deprecationWarning,omitempty"`
 	// schema describes the structural schema used for validation, pruning, and defaulting
-	// of this version of the custom resource.
+	// of this version of the custom resource. Properties marked with `x-kcp-immutable: true`
+	// cannot be changed on update.
 	//
 	// +required
 	// +kubebuilder:pruning:PreserveUnknownFields
@@ -488,3 +864,620 @@ type APIResourceSchemaList struct {
 
 	Items []APIResourceSchema `json:"items"`
 }
+
+// CatalogEntry publishes an APIExport of the same workspace in the service provider
+// catalog, together with the information consumers need to decide whether to bind
+// to it.
+//
+// The catalog of a workspace, served under /catalog, lists the CatalogEntries of all
+// workspaces in the same organization whose APIExport the user is allowed to bind to.
+//
+// +crd
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories=kcp
+// +kubebuilder:printcolumn:name="Export",type=string,JSONPath=`.spec.exportName`,description="The published APIExport"
+// +kubebuilder:printcolumn:name="Maturity",type=string,JSONPath=`.spec.maturity`,description="The maturity of the service"
+type CatalogEntry struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec holds the desired state.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Spec CatalogEntrySpec `json:"spec"`
+
+	// Status communicates the observed state.
+	//
+	// +optional
+	Status CatalogEntryStatus `json:"status,omitempty"`
+}
+
+func (in *CatalogEntry) GetConditions() conditionsv1alpha1.Conditions {
+	return in.Status.Conditions
+}
+
+func (in *CatalogEntry) SetConditions(conditions conditionsv1alpha1.Conditions) {
+	in.Status.Conditions = conditions
+}
+
+// CatalogEntryMaturity is the maturity of a service published in the catalog.
+//
+// +kubebuilder:validation:Enum=Alpha;Beta;Stable
+type CatalogEntryMaturity string
+
+const (
+	CatalogEntryMaturityAlpha  CatalogEntryMaturity = "Alpha"
+	CatalogEntryMaturityBeta   CatalogEntryMaturity = "Beta"
+	CatalogEntryMaturityStable CatalogEntryMaturity = "Stable"
+)
+
+// CatalogEntrySpec defines the desired state of CatalogEntry.
+type CatalogEntrySpec struct {
+	// exportName is the name of the published APIExport in the same workspace.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	ExportName string `json:"exportName"`
+
+	// displayName is the human readable name of the service.
+	//
+	// +optional
+	DisplayName string `json:"displayName,omitempty"`
+
+	// description describes the service to consumers.
+	//
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// icon is shown for the service by user interfaces.
+	//
+	// +optional
+	Icon *CatalogEntryIcon `json:"icon,omitempty"`
+
+	// maturity is the maturity of the service.
+	//
+	// +optional
+	// +kubebuilder:default:=Alpha
+	Maturity CatalogEntryMaturity `json:"maturity,omitempty"`
+
+	// requiredClaims are the resources in consuming workspaces the provider needs
+	// access to, e.g. secrets or configmaps. They are informational for consumers.
+	//
+	// +optional
+	RequiredClaims []metav1.GroupResource `json:"requiredClaims,omitempty"`
+}
+
+// CatalogEntryIcon is an icon of a service.
+type CatalogEntryIcon struct {
+	// mediaType is the media type of the icon, e.g. image/svg+xml or image/png.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	MediaType string `json:"mediaType"`
+
+	// data is the icon.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Data []byte `json:"data"`
+}
+
+// CatalogEntryStatus defines the observed state of CatalogEntry.
+type CatalogEntryStatus struct {
+	// resources are the resources provided by the APIExport, according to its
+	// latest resource schemas.
+	//
+	// +optional
+	Resources []metav1.GroupResource `json:"resources,omitempty"`
+
+	// conditions is a list of conditions that apply to the CatalogEntry.
+	//
+	// +optional
+	Conditions conditionsv1alpha1.Conditions `json:"conditions,omitempty"`
+}
+
+// CatalogEntryList is a list of CatalogEntry resources
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type CatalogEntryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []CatalogEntry `json:"items"`
+}
+
+// SharedSecret publishes a secret of the same workspace to the other workspaces of
+// the organization. Consumers claim read-only copies of it with SecretClaims. When
+// the secret changes, e.g. because it is rotated, the copies are updated.
+//
+// The SecretClaims of a SharedSecret can be audited through the sharedsecrets
+// virtual workspace.
+//
+// +crd
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories=kcp
+// +kubebuilder:printcolumn:name="Namespace",type=string,JSONPath=`.spec.secretRef.namespace`,description="The namespace of the shared secret"
+// +kubebuilder:printcolumn:name="Secret",type=string,JSONPath=`.spec.secretRef.name`,description="The shared secret"
+type SharedSecret struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec holds the desired state.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Spec SharedSecretSpec `json:"spec"`
+
+	// Status communicates the observed state.
+	//
+	// +optional
+	Status SharedSecretStatus `json:"status,omitempty"`
+}
+
+func (in *SharedSecret) GetConditions() conditionsv1alpha1.Conditions {
+	return in.Status.Conditions
+}
+
+func (in *SharedSecret) SetConditions(conditions conditionsv1alpha1.Conditions) {
+	in.Status.Conditions = conditions
+}
+
+// SharedSecretSpec defines the desired state of SharedSecret.
+type SharedSecretSpec struct {
+	// secretRef references the shared secret in the same workspace.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	SecretRef SecretReference `json:"secretRef"`
+
+	// description describes the secret to consumers.
+	//
+	// +optional
+	Description string `json:"description,omitempty"`
+}
+
+// SecretReference references a secret in a namespace.
+type SecretReference struct {
+	// namespace of the secret.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+
+	// name of the secret.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// SharedSecretStatus defines the observed state of SharedSecret.
+type SharedSecretStatus struct {
+	// conditions is a list of conditions that apply to the SharedSecret.
+	//
+	// +optional
+	Conditions conditionsv1alpha1.Conditions `json:"conditions,omitempty"`
+}
+
+// SharedSecretList is a list of SharedSecret resources
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type SharedSecretList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []SharedSecret `json:"items"`
+}
+
+// SecretClaim claims a read-only copy of a SharedSecret of another workspace in the
+// same organization. The copy is created in the namespace of the SecretClaim, and is
+// kept up-to-date with the shared secret.
+//
+// The creator of the SecretClaim needs to have access to the SharedSecret with the
+// verb `claim`.
+//
+// +crd
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Namespaced,categories=kcp
+// +kubebuilder:printcolumn:name="Workspace",type=string,JSONPath=`.spec.reference.name`,description="The workspace of the shared secret"
+// +kubebuilder:printcolumn:name="SharedSecret",type=string,JSONPath=`.spec.reference.sharedSecretName`,description="The claimed shared secret"
+// +kubebuilder:printcolumn:name="Synced",type=string,JSONPath=`.status.conditions[?(@.type=="SecretSynced")].status`,description="Whether the copy is up-to-date"
+type SecretClaim struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec holds the desired state.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Spec SecretClaimSpec `json:"spec"`
+
+	// Status communicates the observed state.
+	//
+	// +optional
+	Status SecretClaimStatus `json:"status,omitempty"`
+}
+
+func (in *SecretClaim) GetConditions() conditionsv1alpha1.Conditions {
+	return in.Status.Conditions
+}
+
+func (in *SecretClaim) SetConditions(conditions conditionsv1alpha1.Conditions) {
+	in.Status.Conditions = conditions
+}
+
+// SecretClaimSpec defines the desired state of SecretClaim.
+type SecretClaimSpec struct {
+	// reference identifies the claimed SharedSecret. It is immutable.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Reference SharedSecretReference `json:"reference"`
+
+	// secretName is the name of the copy in the namespace of the SecretClaim. It
+	// defaults to the name of the SecretClaim. It is immutable.
+	//
+	// +optional
+	// +kubebuilder:validation:Pattern:="^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$"
+	SecretName string `json:"secretName,omitempty"`
+}
+
+// SharedSecretReference references a SharedSecret in a workspace of the same
+// organization.
+type SharedSecretReference struct {
+	// name is a workspace name in the same organization.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kube:validation:MinLength=1
+	// +kubebuilder:validation:Pattern:="^[a-z0-9]([-a-z0-9]*[a-z0-9])?$"
+	WorkspaceName string `json:"name"`
+
+	// sharedSecretName is the name of the SharedSecret.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kube:validation:MinLength=1
+	SharedSecretName string `json:"sharedSecretName"`
+}
+
+// SecretClaimStatus defines the observed state of SecretClaim.
+type SecretClaimStatus struct {
+	// sourceResourceVersion is the resourceVersion of the shared secret the copy
+	// was last synced from.
+	//
+	// +optional
+	SourceResourceVersion string `json:"sourceResourceVersion,omitempty"`
+
+	// lastSyncTime is when the copy was last changed.
+	//
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// conditions is a list of conditions that apply to the SecretClaim.
+	//
+	// +optional
+	Conditions conditionsv1alpha1.Conditions `json:"conditions,omitempty"`
+}
+
+// SecretClaimList is a list of SecretClaim resources
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type SecretClaimList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []SecretClaim `json:"items"`
+}
+
+const (
+	// SecretSynced is a condition for SecretClaim that the copy of the shared secret is up-to-date.
+	SecretSynced conditionsv1alpha1.ConditionType = "SecretSynced"
+
+	// SecretNotFoundReason is a reason for the SecretSynced condition that the secret
+	// referenced by the SharedSecret does not exist.
+	SecretNotFoundReason = "SecretNotFound"
+
+	// SharedSecretNotFoundReason is a reason for the SecretSynced condition that the claimed
+	// SharedSecret does not exist.
+	SharedSecretNotFoundReason = "SharedSecretNotFound"
+	// SecretConflictReason is a reason for the SecretSynced condition that a secret with the
+	// name of the copy exists that does not belong to the SecretClaim.
+	SecretConflictReason = "SecretConflict"
+	// ClaimForbiddenReason is a reason for the SecretSynced condition that the creator of the
+	// SecretClaim is not allowed to claim the SharedSecret (anymore).
+	ClaimForbiddenReason = "ClaimForbidden"
+
+	// SecretClaimLabel is set on copies of shared secrets to the name of their SecretClaim.
+	// Secrets with this label can only be changed by kcp.
+	SecretClaimLabel = "apis.kcp.dev/secret-claim"
+	// SharedSecretAnnotation is set on copies of shared secrets to the logical cluster and
+	// name of the SharedSecret, in the format <cluster>|<name>.
+	SharedSecretAnnotation = "apis.kcp.dev/shared-secret"
+	// SecretClaimCreatorAnnotation is set on SecretClaims by admission to the user who created
+	// them, as JSON. The shared secret is only copied while this user has the verb `claim` on
+	// the SharedSecret.
+	SecretClaimCreatorAnnotation = "apis.kcp.dev/secret-claim-creator"
+)
+
+// EventSubscription emits CloudEvents when objects of bound resources in the same
+// workspace are created, updated or deleted, e.g. to trigger serverless functions on
+// control-plane changes. The events are emitted by kcp, not by a controller of the
+// workspace owner.
+//
+// Only resources bound by an APIBinding of the workspace emit events. The events carry
+// the metadata of the object, not the object itself.
+//
+// +crd
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories=kcp
+// +kubebuilder:printcolumn:name="Sink",type=string,JSONPath=`.spec.sink.url`,description="The receiver of the events"
+// +kubebuilder:printcolumn:name="Bound",type=string,JSONPath=`.status.conditions[?(@.type=="ResourcesBound")].status`,description="Whether all resources are bound"
+type EventSubscription struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec holds the desired state.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Spec EventSubscriptionSpec `json:"spec"`
+
+	// Status communicates the observed state.
+	//
+	// +optional
+	Status EventSubscriptionStatus `json:"status,omitempty"`
+}
+
+func (in *EventSubscription) GetConditions() conditionsv1alpha1.Conditions {
+	return in.Status.Conditions
+}
+
+func (in *EventSubscription) SetConditions(conditions conditionsv1alpha1.Conditions) {
+	in.Status.Conditions = conditions
+}
+
+// EventSubscriptionSpec defines the desired state of EventSubscription.
+type EventSubscriptionSpec struct {
+	// resources are the bound resources whose objects emit events.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	Resources []EventSubscriptionResource `json:"resources"`
+
+	// sink is the receiver of the events.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Sink EventSubscriptionSink `json:"sink"`
+}
+
+// EventSubscriptionResource selects a bound resource and the operations on its objects
+// that emit events.
+type EventSubscriptionResource struct {
+	// group is the API group of the resource.
+	//
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// resource is the lower-case plural name of the resource.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Resource string `json:"resource"`
+
+	// operations are the operations that emit events. All operations do if empty.
+	//
+	// +optional
+	Operations []EventSubscriptionOperation `json:"operations,omitempty"`
+}
+
+// EventSubscriptionOperation is an operation on an object.
+//
+// +kubebuilder:validation:Enum=Create;Update;Delete
+type EventSubscriptionOperation string
+
+const (
+	EventSubscriptionCreate EventSubscriptionOperation = "Create"
+	EventSubscriptionUpdate EventSubscriptionOperation = "Update"
+	EventSubscriptionDelete EventSubscriptionOperation = "Delete"
+)
+
+// EventSubscriptionSink is a CloudEvents receiver.
+type EventSubscriptionSink struct {
+	// url is the http or https URL of a receiver accepting CloudEvents in structured
+	// mode, e.g. of a Knative broker.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern:="^https?://"
+	URL string `json:"url"`
+}
+
+// EventSubscriptionStatus defines the observed state of EventSubscription.
+type EventSubscriptionStatus struct {
+	// conditions is a list of conditions that apply to the EventSubscription.
+	//
+	// +optional
+	Conditions conditionsv1alpha1.Conditions `json:"conditions,omitempty"`
+}
+
+// EventSubscriptionList is a list of EventSubscription resources
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type EventSubscriptionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []EventSubscription `json:"items"`
+}
+
+const (
+	// EventSubscriptionResourcesBound is a condition for EventSubscription that all selected
+	// resources are bound in the workspace, and hence emit events.
+	EventSubscriptionResourcesBound conditionsv1alpha1.ConditionType = "ResourcesBound"
+
+	// ResourceNotBoundReason is a reason for the ResourcesBound condition that a selected
+	// resource is not bound by an APIBinding of the workspace.
+	ResourceNotBoundReason = "ResourceNotBound"
+)
+
+// TTLPolicy garbage-collects objects of a resource in the same workspace a given time
+// after they were created or completed, e.g. temporary claims or finished jobs. The
+// objects are deleted centrally by kcp instead of by cron jobs of every tenant.
+//
+// +crd
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories=kcp
+// +kubebuilder:printcolumn:name="Resource",type=string,JSONPath=`.spec.resource`,description="The resource whose objects expire"
+// +kubebuilder:printcolumn:name="TTL",type=string,JSONPath=`.spec.ttl`,description="The time to live of the objects"
+// +kubebuilder:printcolumn:name="Deleted",type=integer,JSONPath=`.status.deletedObjects`,description="The number of deleted objects"
+type TTLPolicy struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec holds the desired state.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Spec TTLPolicySpec `json:"spec"`
+
+	// Status communicates the observed state.
+	//
+	// +optional
+	Status TTLPolicyStatus `json:"status,omitempty"`
+}
+
+func (in *TTLPolicy) GetConditions() conditionsv1alpha1.Conditions {
+	return in.Status.Conditions
+}
+
+func (in *TTLPolicy) SetConditions(conditions conditionsv1alpha1.Conditions) {
+	in.Status.Conditions = conditions
+}
+
+// TTLPolicySpec defines the desired state of TTLPolicy.
+type TTLPolicySpec struct {
+	// group is the API group of the resource.
+	//
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// resource is the lower-case plural name of the resource whose objects expire.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Resource string `json:"resource"`
+
+	// selector restricts the expiring objects by labels. All objects of the resource
+	// expire if unset.
+	//
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// ttl is the time after the start of the object lifetime after which an object is deleted.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	TTL metav1.Duration `json:"ttl"`
+
+	// after is the start of the object lifetime. With Creation, objects expire after
+	// their creation timestamp. With Completion, objects expire only after one of the
+	// completionConditions became True, counted from its last transition time.
+	//
+	// +optional
+	// +kubebuilder:default=Creation
+	After TTLPolicyStart `json:"after,omitempty"`
+
+	// completionConditions are the types of the status conditions marking an object as
+	// completed. Defaults to Complete and Failed, as set by Jobs.
+	//
+	// +optional
+	CompletionConditions []string `json:"completionConditions,omitempty"`
+}
+
+// TTLPolicyStart is the start of the lifetime of an object.
+//
+// +kubebuilder:validation:Enum=Creation;Completion
+type TTLPolicyStart string
+
+const (
+	TTLPolicyAfterCreation   TTLPolicyStart = "Creation"
+	TTLPolicyAfterCompletion TTLPolicyStart = "Completion"
+)
+
+// TTLPolicyStatus defines the observed state of TTLPolicy.
+type TTLPolicyStatus struct {
+	// lastSweepTime is the last time the objects of the resource were checked for expiry.
+	//
+	// +optional
+	LastSweepTime *metav1.Time `json:"lastSweepTime,omitempty"`
+
+	// deletedObjects is the number of objects deleted by this policy.
+	//
+	// +optional
+	DeletedObjects int64 `json:"deletedObjects,omitempty"`
+
+	// conditions is a list of conditions that apply to the TTLPolicy.
+	//
+	// +optional
+	Conditions conditionsv1alpha1.Conditions `json:"conditions,omitempty"`
+}
+
+// TTLPolicyList is a list of TTLPolicy resources
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type TTLPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []TTLPolicy `json:"items"`
+}
+
+const (
+	// TTLPolicyResourceResolved is a condition for TTLPolicy that the resource is served
+	// in the workspace, and hence its objects are garbage-collected.
+	TTLPolicyResourceResolved conditionsv1alpha1.ConditionType = "ResourceResolved"
+
+	// ResourceNotServedReason is a reason for the ResourceResolved condition that the
+	// resource is not served in the workspace.
+	ResourceNotServedReason = "ResourceNotServed"
+
+	// InvalidSelectorReason is a reason for the ResourceResolved condition that the
+	// selector cannot be parsed.
+	InvalidSelectorReason = "InvalidSelector"
+
+	// TTLPolicyForbiddenReason is a reason for the ResourceResolved condition that the creator
+	// of the TTLPolicy is not allowed to list and delete the resource (anymore).
+	TTLPolicyForbiddenReason = "Forbidden"
+
+	// TTLPolicyCreatorAnnotation is set on TTLPolicies by admission to the user who created
+	// them, as JSON. Objects are only deleted while this user can list and delete them.
+	TTLPolicyCreatorAnnotation = "apis.kcp.dev/ttlpolicy-creator"
+)
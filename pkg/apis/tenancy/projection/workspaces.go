@@ -24,6 +24,9 @@ import (
 func ProjectClusterWorkspaceToWorkspace(from *v1alpha1.ClusterWorkspace, to *v1beta1.Workspace) {
 	to.ObjectMeta = from.ObjectMeta
 	to.Spec.Type = from.Spec.Type
+	to.Spec.Expiration = from.Spec.Expiration
 	to.Status.URL = from.Status.BaseURL
 	to.Status.Phase = from.Status.Phase
+	to.Status.Conditions = from.Status.Conditions
+	to.Status.InitializerStatuses = from.Status.InitializerStatuses
 }
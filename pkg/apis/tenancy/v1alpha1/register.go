@@ -51,6 +51,22 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&ClusterWorkspaceTypeList{},
 		&ClusterWorkspaceShard{},
 		&ClusterWorkspaceShardList{},
+		&AccessGrant{},
+		&AccessGrantList{},
+		&DenyPolicy{},
+		&DenyPolicyList{},
+		&Replication{},
+		&ReplicationList{},
+		&VirtualWorkspace{},
+		&VirtualWorkspaceList{},
+		&BulkWorkspaceOperation{},
+		&BulkWorkspaceOperationList{},
+		&PolicyBundle{},
+		&PolicyBundleList{},
+		&HomeWorkspacePolicy{},
+		&HomeWorkspacePolicyList{},
+		&ObjectTransfer{},
+		&ObjectTransferList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
@@ -20,7 +20,9 @@ import (
 	"github.com/kcp-dev/logicalcluster"
 
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 
 	conditionsv1alpha1 "github.com/kcp-dev/kcp/third_party/conditions/apis/conditions/v1alpha1"
 	"github.com/kcp-dev/kcp/third_party/conditions/util/conditions"
@@ -67,8 +69,26 @@ func (in *ClusterWorkspace) GetConditions() conditionsv1alpha1.Conditions {
 var _ conditions.Getter = &ClusterWorkspace{}
 var _ conditions.Setter = &ClusterWorkspace{}
 
+// BreakGlassGroup is the group whose members can write in read-only workspaces.
+const BreakGlassGroup = "system:kcp:break-glass"
+
+const (
+	// SystemWorkspaceAdminsGroup is the group whose members, in addition to system:masters,
+	// can modify protected objects and write in system workspaces.
+	SystemWorkspaceAdminsGroup = "system:kcp:system-workspace-admins"
+
+	// ProtectedLabel set to "true" protects an object from being updated or deleted by
+	// anybody but members of system:masters and SystemWorkspaceAdminsGroup. ClusterWorkspaces
+	// with this label are system workspaces, whose content is protected as a whole.
+	ProtectedLabel = "tenancy.kcp.dev/protected"
+)
+
 // ClusterWorkspaceSpec holds the desired state of the ClusterWorkspace.
 type ClusterWorkspaceSpec struct {
+	// readOnly freezes the workspace, e.g. during an incident, a migration or a legal hold.
+	// All writes in the workspace are rejected, except by members of system:masters and
+	// of the system:kcp:break-glass group.
+	//
 	// +optional
 	ReadOnly bool `json:"readOnly,omitempty"`
 
@@ -86,6 +106,55 @@ type ClusterWorkspaceSpec struct {
 	// +kubebuilder:default:="Universal"
 	// +kubebuilder:validation:Pattern=`^[A-Z][a-zA-Z0-9]+$`
 	Type string `json:"type,omitempty"`
+
+	// limits restrict the objects stored in the workspace in addition to the limits
+	// of its ClusterWorkspaceType. For every limit set in both, the stricter one applies.
+	//
+	// +optional
+	Limits *ClusterWorkspaceLimits `json:"limits,omitempty"`
+
+	// workloadDefaults are applied to the pods of workloads synced from the workspace
+	// to workload clusters. If set, they replace those of the ClusterWorkspaceType.
+	//
+	// +optional
+	WorkloadDefaults *WorkloadDefaults `json:"workloadDefaults,omitempty"`
+
+	// imagePolicy restricts the images of workloads in the workspace. If set, it replaces
+	// the one of the ClusterWorkspaceType.
+	//
+	// +optional
+	ImagePolicy *ImagePolicy `json:"imagePolicy,omitempty"`
+
+	// templateParameters are the parameters passed to the template of the
+	// ClusterWorkspaceType. They are validated against and defaulted from the
+	// parameterSchema of the template on creation, and are immutable.
+	//
+	// +optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +structType=atomic
+	TemplateParameters *runtime.RawExtension `json:"templateParameters,omitempty"`
+
+	// expiration schedules the deletion of the workspace, e.g. of a pull request preview
+	// or training environment. Before it expires, the workspace is marked with the
+	// WorkspaceExpiring condition. The expiration can be changed or removed to postpone
+	// or cancel the deletion.
+	//
+	// +optional
+	Expiration *WorkspaceExpiration `json:"expiration,omitempty"`
+}
+
+// WorkspaceExpiration defines when a workspace expires and is deleted with its content.
+// If both ttl and timestamp are set, the earlier one applies.
+type WorkspaceExpiration struct {
+	// ttl is the time to live of the workspace after its creation, e.g. "72h".
+	//
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+
+	// timestamp is the point in time the workspace expires.
+	//
+	// +optional
+	Timestamp *metav1.Time `json:"timestamp,omitempty"`
 }
 
 // ClusterWorkspaceType specifies behaviour of workspaces of this type.
@@ -117,6 +186,329 @@ type ClusterWorkspaceTypeSpec struct {
 	//
 	// +optional
 	AdditionalWorkspaceLabels map[string]string `json:"additionalWorkspaceLabels,omitempty"`
+
+	// maxConcurrentInitializations is the maximum number of workspaces of this type
+	// initializing at the same time. Further workspaces stay in the Scheduling phase
+	// until one of the initializing workspaces of this type is ready.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxConcurrentInitializations *int32 `json:"maxConcurrentInitializations,omitempty"`
+
+	// limits restrict the size and the number of objects in workspaces of this type,
+	// protecting the storage from pathological tenants. They are enforced at admission.
+	//
+	// +optional
+	Limits *ClusterWorkspaceLimits `json:"limits,omitempty"`
+
+	// namespaces controls the namespaces of workspaces of this type. They are enforced
+	// at admission for everybody but privileged users.
+	//
+	// +optional
+	Namespaces *ClusterWorkspaceNamespaces `json:"namespaces,omitempty"`
+
+	// freezeWindows are recurring time windows during which writes in workspaces of
+	// this type are restricted, e.g. during maintenance or release freezes. They are
+	// enforced at admission for everybody but privileged users and the break-glass group.
+	//
+	// +optional
+	FreezeWindows []FreezeWindow `json:"freezeWindows,omitempty"`
+
+	// labelPropagation propagates labels of ClusterWorkspaces of this type onto the
+	// namespaces in the workspaces, e.g. a cost center or an environment.
+	//
+	// +optional
+	LabelPropagation *ClusterWorkspaceLabelPropagation `json:"labelPropagation,omitempty"`
+
+	// workloadDefaults are applied to the pods of workloads synced from workspaces of
+	// this type to workload clusters, e.g. to steer them onto dedicated node pools.
+	//
+	// +optional
+	WorkloadDefaults *WorkloadDefaults `json:"workloadDefaults,omitempty"`
+
+	// imagePolicy restricts the images of workloads in workspaces of this type. It is
+	// enforced at admission, before the workloads are synced to workload clusters.
+	//
+	// +optional
+	ImagePolicy *ImagePolicy `json:"imagePolicy,omitempty"`
+
+	// template renders seed manifests into new workspaces of this type during their
+	// initialization, parameterized by the templateParameters of the workspaces.
+	//
+	// +optional
+	Template *ClusterWorkspaceTemplate `json:"template,omitempty"`
+}
+
+// ClusterWorkspaceTemplate describes the parameters of workspaces of a type and the
+// manifests rendered from them.
+type ClusterWorkspaceTemplate struct {
+	// parameterSchema is an OpenAPI v3 schema of type object. The templateParameters
+	// of new workspaces are validated against it and defaulted from it. If empty,
+	// workspaces must not have parameters.
+	//
+	// +optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +structType=atomic
+	ParameterSchema *runtime.RawExtension `json:"parameterSchema,omitempty"`
+
+	// manifests are rendered and created in the order given during the
+	// initialization of new workspaces.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Manifests []ClusterWorkspaceTemplateManifest `json:"manifests,omitempty"`
+}
+
+// ClusterWorkspaceTemplateManifest is a Go text/template rendering to one or more YAML
+// documents. The templates are executed with .Parameters holding the templateParameters,
+// and .Workspace holding the .Name, .Path and .Type of the workspace. Referencing a
+// missing key is an error.
+type ClusterWorkspaceTemplateManifest struct {
+	// name identifies the manifest.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// when is a template rendering to "true" or "false". If it renders to "false",
+	// the manifest is skipped. If empty, the manifest is always rendered.
+	//
+	// +optional
+	When string `json:"when,omitempty"`
+
+	// template renders to the YAML documents of the objects to create.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Template string `json:"template"`
+}
+
+// ImagePolicy restricts the container images of workloads, i.e. pods, deployments, replicasets,
+// statefulsets, daemonsets, jobs and cronjobs.
+type ImagePolicy struct {
+	// allowedImages are the allowed image repositories, e.g. "quay.io/org/app". An entry ending
+	// with "*" allows all repositories with the given prefix, e.g. "quay.io/org/*". Images from
+	// Docker Hub are matched with their full name, e.g. "docker.io/library/nginx" for "nginx".
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	AllowedImages []string `json:"allowedImages"`
+
+	// requireDigest requires images to be pinned by digest, e.g. "quay.io/org/app@sha256:...".
+	//
+	// +optional
+	RequireDigest bool `json:"requireDigest,omitempty"`
+}
+
+// WorkloadDefaults are injected by syncers into the pod templates of workloads, i.e. pods,
+// deployments, replicasets, statefulsets, daemonsets, jobs and cronjobs, as they are synced
+// downstream. Tenants cannot opt out of them.
+type WorkloadDefaults struct {
+	// nodeSelector is merged into the node selector of the pods. Its entries take precedence
+	// over those of the workload.
+	//
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// tolerations are added to the tolerations of the pods, unless they exist already.
+	//
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// runtimeClassName is set on pods that do not specify a runtime class.
+	//
+	// +optional
+	RuntimeClassName string `json:"runtimeClassName,omitempty"`
+}
+
+// ClusterWorkspaceLabelPropagation selects the labels propagated from a ClusterWorkspace
+// onto the namespaces in the workspace.
+type ClusterWorkspaceLabelPropagation struct {
+	// labels are the keys of the propagated labels. Labels with these keys are owned by
+	// the ClusterWorkspace, i.e. they are removed from the namespaces when they are removed
+	// from the ClusterWorkspace, and cannot be changed on the namespaces.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	Labels []string `json:"labels"`
+
+	// downstream also propagates the labels onto the resources synced to workload clusters.
+	//
+	// +optional
+	Downstream bool `json:"downstream,omitempty"`
+}
+
+const (
+	// PropagatedLabelsAnnotation is set on namespaces to the comma separated keys of the
+	// labels propagated from their ClusterWorkspace.
+	PropagatedLabelsAnnotation = "tenancy.kcp.dev/propagated-labels"
+	// DownstreamLabelsAnnotation is set on namespaces to the comma separated keys of their
+	// labels which syncers set on the resources of the namespace in workload clusters.
+	DownstreamLabelsAnnotation = "tenancy.kcp.dev/downstream-labels"
+	// WorkloadDefaultsAnnotation is set on namespaces to the JSON encoded WorkloadDefaults
+	// of their workspace, which syncers apply to the pods synced from the namespace.
+	WorkloadDefaultsAnnotation = "tenancy.kcp.dev/workload-defaults"
+)
+
+// DefaultNamespacesInitializer is set on ClusterWorkspaces of types with default namespaces,
+// and is removed when the namespaces are created.
+const DefaultNamespacesInitializer ClusterWorkspaceInitializer = "system:default-namespaces"
+
+// TemplateInitializer is set on ClusterWorkspaces of types with template manifests,
+// and is removed when the rendered objects are created.
+const TemplateInitializer ClusterWorkspaceInitializer = "system:template"
+
+// ClusterWorkspaceNamespaces controls the namespaces of a workspace.
+type ClusterWorkspaceNamespaces struct {
+	// disabled prohibits the creation of namespaces. The default namespaces are
+	// created nevertheless.
+	//
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+
+	// allowedNamePatterns are regular expressions of which one must match the whole
+	// name of a new namespace. If empty, all names are allowed.
+	//
+	// +optional
+	AllowedNamePatterns []string `json:"allowedNamePatterns,omitempty"`
+
+	// defaults are namespaces created during the initialization of the workspace.
+	//
+	// +optional
+	Defaults []DefaultNamespace `json:"defaults,omitempty"`
+}
+
+// DefaultNamespace is a namespace created in new workspaces.
+type DefaultNamespace struct {
+	// name is the name of the namespace.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// labels are set on the namespace.
+	//
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// ClusterWorkspaceLimits restricts the objects stored in a workspace.
+type ClusterWorkspaceLimits struct {
+	// maxObjectSize is the maximum size in bytes of an object serialized as JSON.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxObjectSize *int64 `json:"maxObjectSize,omitempty"`
+
+	// maxObjectsPerResource is the maximum number of objects of each resource,
+	// across all namespaces of the workspace.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxObjectsPerResource *int64 `json:"maxObjectsPerResource,omitempty"`
+
+	// maxManagedFieldsSize is the maximum size in bytes of the managed fields of an
+	// object serialized as JSON.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxManagedFieldsSize *int64 `json:"maxManagedFieldsSize,omitempty"`
+}
+
+// FreezeWindowEnforcement defines how writes during a freeze window are treated.
+//
+// +kubebuilder:validation:Enum=Reject;RequireOverride
+type FreezeWindowEnforcement string
+
+const (
+	// FreezeWindowEnforcementReject rejects all writes during the window.
+	FreezeWindowEnforcementReject FreezeWindowEnforcement = "Reject"
+	// FreezeWindowEnforcementRequireOverride rejects writes during the window unless the
+	// written object carries the FreezeWindowOverrideAnnotation.
+	FreezeWindowEnforcementRequireOverride FreezeWindowEnforcement = "RequireOverride"
+)
+
+// FreezeWindowOverrideAnnotation lets writes of an object pass freeze windows with the
+// RequireOverride enforcement. Its value documents why, e.g. an incident reference.
+// For deletions, the annotation must be set on the deleted object.
+const FreezeWindowOverrideAnnotation = "tenancy.kcp.dev/freeze-window-override"
+
+// FreezeWindow is a recurring time window during which writes are restricted.
+type FreezeWindow struct {
+	// name identifies the window in the errors of rejected requests.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// resources are the resources whose writes are restricted. If empty, writes to all
+	// resources are restricted.
+	//
+	// +optional
+	Resources []FreezeWindowResource `json:"resources,omitempty"`
+
+	// days are the days of the week the window starts on, e.g. Saturday. If empty, the
+	// window starts on every day.
+	//
+	// +optional
+	Days []FreezeWindowDay `json:"days,omitempty"`
+
+	// start is the time of day the window starts at, in the format HH:MM.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
+
+	// end is the time of day the window ends at, in the format HH:MM. If it is not after
+	// start, the window ends on the next day.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	End string `json:"end"`
+
+	// timeZone is the IANA time zone of start and end, e.g. Europe/Berlin. Windows follow
+	// daylight saving time changes of the time zone.
+	//
+	// +optional
+	// +kubebuilder:default:="UTC"
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// enforcement defines how writes during the window are treated. With Reject, they
+	// are rejected. With RequireOverride, they are rejected unless the written object
+	// carries the tenancy.kcp.dev/freeze-window-override annotation.
+	//
+	// +optional
+	// +kubebuilder:default:="Reject"
+	Enforcement FreezeWindowEnforcement `json:"enforcement,omitempty"`
+}
+
+// FreezeWindowDay is a day of the week.
+//
+// +kubebuilder:validation:Enum=Monday;Tuesday;Wednesday;Thursday;Friday;Saturday;Sunday
+type FreezeWindowDay string
+
+// FreezeWindowResource identifies a resource by its group and plural name.
+type FreezeWindowResource struct {
+	// group is the API group of the resource. Empty for the core group.
+	//
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// resource is the plural lower-case name of the resource, e.g. deployments.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Resource string `json:"resource"`
 }
 
 // ClusterWorkspaceTypeList is a list of cluster workspace types
@@ -173,6 +565,34 @@ type ClusterWorkspaceStatus struct {
 	//
 	// +optional
 	Initializers []ClusterWorkspaceInitializer `json:"initializers,omitempty"`
+
+	// initializerStatuses records the progress of every initializer the workspace
+	// entered the "Initializing" phase with. Other than initializers, entries are
+	// not removed when the initializer finishes, but are marked as completed.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	InitializerStatuses []ClusterWorkspaceInitializerStatus `json:"initializerStatuses,omitempty"`
+}
+
+// ClusterWorkspaceInitializerStatus records the progress of one initializer of a workspace.
+type ClusterWorkspaceInitializerStatus struct {
+	// name is the initializer.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Name ClusterWorkspaceInitializer `json:"name"`
+
+	// completed is true when the initializer has been removed from the workspace.
+	//
+	// +required
+	Completed bool `json:"completed"`
+
+	// completionTime is the time when the initializer was observed as removed.
+	//
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
 }
 
 // These are valid conditions of workspace.
@@ -197,6 +617,60 @@ const (
 
 	// WorkspaceContentDeleted represents the status that all resources in the workspace is deleted.
 	WorkspaceContentDeleted conditionsv1alpha1.ConditionType = "WorkspaceContentDeleted"
+
+	// WorkspaceHibernated represents the status that the workspace has been idle, and its synced
+	// workloads are scaled down. The condition is removed when the workspace is accessed again.
+	WorkspaceHibernated conditionsv1alpha1.ConditionType = "WorkspaceHibernated"
+	// WorkspaceHibernatedReasonIdle reason in WorkspaceHibernated condition means that there was no API
+	// activity in the workspace for the configured idle timeout.
+	WorkspaceHibernatedReasonIdle = "Idle"
+
+	// WorkspaceExpiring represents the status that the workspace expires within the warning
+	// period, and will be deleted with its content when the expiration is reached.
+	WorkspaceExpiring conditionsv1alpha1.ConditionType = "WorkspaceExpiring"
+	// WorkspaceExpiringReasonScheduled reason in WorkspaceExpiring condition means that the
+	// expiration of the workspace is near.
+	WorkspaceExpiringReasonScheduled = "ExpirationScheduled"
+
+	// WorkspaceInitializationAdmitted represents the status of the scheduled workspace in the
+	// initialization queue. The workspace moves to the Initializing phase when it is admitted.
+	WorkspaceInitializationAdmitted conditionsv1alpha1.ConditionType = "WorkspaceInitializationAdmitted"
+	// WorkspaceInitializationReasonQueued reason in WorkspaceInitializationAdmitted condition means
+	// that the maximum number of workspaces are initializing on the shard, or other workspaces
+	// are ahead in the initialization queue.
+	WorkspaceInitializationReasonQueued = "Queued"
+	// WorkspaceInitializationReasonTypeConcurrencyLimit reason in WorkspaceInitializationAdmitted
+	// condition means that the maximum number of workspaces of the same ClusterWorkspaceType are
+	// initializing.
+	WorkspaceInitializationReasonTypeConcurrencyLimit = "TypeConcurrencyLimit"
+
+	// WorkspaceSchedulingReady summarizes the WorkspaceScheduled, WorkspaceShardValid and
+	// WorkspaceInitializationAdmitted conditions. It is true when the workspace is placed on
+	// a valid shard and has been admitted for initialization. When false, reason and message
+	// are those of the first failing condition.
+	WorkspaceSchedulingReady conditionsv1alpha1.ConditionType = "SchedulingReady"
+	// WorkspaceSchedulingReasonPending reason in SchedulingReady condition means that the
+	// workspace has not been scheduled yet.
+	WorkspaceSchedulingReasonPending = "SchedulingPending"
+
+	// WorkspaceInitializersComplete represents the status of the initializers of the workspace.
+	// It is true when all initializers have been removed. The progress of the individual
+	// initializers is recorded in status.initializerStatuses.
+	WorkspaceInitializersComplete conditionsv1alpha1.ConditionType = "InitializersComplete"
+	// WorkspaceInitializersReasonNotStarted reason in InitializersComplete condition means
+	// that the workspace has not entered the Initializing phase yet.
+	WorkspaceInitializersReasonNotStarted = "NotStarted"
+	// WorkspaceInitializersReasonPending reason in InitializersComplete condition means that
+	// some initializers have not finished yet. The message lists them.
+	WorkspaceInitializersReasonPending = "InitializersPending"
+
+	// WorkspaceAPIBindingsReady represents the status of the APIBindings in the workspace.
+	// It is true when all APIBindings in the workspace are bound. The condition is only
+	// maintained for workspaces on the shard running the workspace scheduler.
+	WorkspaceAPIBindingsReady conditionsv1alpha1.ConditionType = "APIBindingsReady"
+	// WorkspaceAPIBindingsReasonPending reason in APIBindingsReady condition means that
+	// some APIBindings are not bound yet. The message lists them.
+	WorkspaceAPIBindingsReasonPending = "APIBindingsPending"
 )
 
 // ClusterWorkspaceLocation specifies workspace placement information, including current, desired (target), and
@@ -235,6 +709,7 @@ type ClusterWorkspaceList struct {
 // +kubebuilder:resource:scope=Cluster,categories=kcp
 // +kubebuilder:printcolumn:name="URL",type=string,JSONPath=`.spec.baseURL`,description="Type URL to directly connect to the shard"
 // +kubebuilder:printcolumn:name="External URL",type=string,JSONPath=`.spec.externalURL`,description="The URL exposed in workspaces created on that shard"
+// +kubebuilder:printcolumn:name="Version",type=string,JSONPath=`.status.versionInfo.version`,description="The kcp version the shard runs"
 type ClusterWorkspaceShard struct {
 	metav1.TypeMeta `json:",inline"`
 	// +optional
@@ -301,6 +776,64 @@ type ClusterWorkspaceShardStatus struct {
 	// Current processing state of the ClusterWorkspaceShard.
 	// +optional
 	Conditions conditionsv1alpha1.Conditions `json:"conditions,omitempty"`
+
+	// versionInfo is published by the shard itself. It is used by the shards and the
+	// front-proxy to cooperate while shards of different versions run side by side,
+	// e.g. during a rolling upgrade.
+	//
+	// +optional
+	VersionInfo *ShardVersionInfo `json:"versionInfo,omitempty"`
+}
+
+// ShardVersionInfo describes the version of a shard and what it supports.
+type ShardVersionInfo struct {
+	// version is the kcp version the shard runs, e.g. v0.5.0.
+	//
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// features are the kcp features enabled on the shard. Features affecting more than
+	// one shard are only used when they are enabled on all shards.
+	//
+	// +optional
+	// +listType=set
+	Features []string `json:"features,omitempty"`
+
+	// resources are the system resources served by the shard.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=group
+	// +listMapKey=resource
+	Resources []ShardResource `json:"resources,omitempty"`
+}
+
+// ShardResource describes the versions of a system resource served by a shard.
+type ShardResource struct {
+	// group is the API group of the resource.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Group string `json:"group"`
+
+	// resource is the plural name of the resource.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Resource string `json:"resource"`
+
+	// versions are the versions the shard serves, i.e. which it can read objects in.
+	//
+	// +optional
+	// +listType=set
+	Versions []string `json:"versions,omitempty"`
+
+	// storageVersion is the version the shard writes objects in.
+	//
+	// +optional
+	StorageVersion string `json:"storageVersion,omitempty"`
 }
 
 // ClusterWorkspaceShardList is a list of workspace shards
@@ -313,6 +846,1097 @@ type ClusterWorkspaceShardList struct {
 	Items []ClusterWorkspaceShard `json:"items"`
 }
 
+// AccessGrant grants subjects a role in the workspace it lives in until it expires. kcp creates
+// the corresponding RoleBinding or ClusterRoleBinding and removes it again when the grant
+// expires or is deleted. It is meant for just-in-time elevated access, e.g. of support
+// engineers into tenant workspaces.
+//
+// +crd
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories=kcp
+// +kubebuilder:printcolumn:name="Role",type=string,JSONPath=`.spec.roleRef.name`,description="The granted role"
+// +kubebuilder:printcolumn:name="Expires",type=string,JSONPath=`.spec.expiresAt`,description="When the grant expires"
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`,description="The current phase (e.g. Active, Expired)"
+type AccessGrant struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +required
+	// +kubebuilder:validation:Required
+	Spec AccessGrantSpec `json:"spec"`
+
+	// +optional
+	Status AccessGrantStatus `json:"status,omitempty"`
+}
+
+func (in *AccessGrant) SetConditions(c conditionsv1alpha1.Conditions) {
+	in.Status.Conditions = c
+}
+
+func (in *AccessGrant) GetConditions() conditionsv1alpha1.Conditions {
+	return in.Status.Conditions
+}
+
+var _ conditions.Getter = &AccessGrant{}
+var _ conditions.Setter = &AccessGrant{}
+
+// AccessGrantSpec holds the desired state of the AccessGrant.
+type AccessGrantSpec struct {
+	// subjects are the users, groups and service accounts the role is granted to.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	Subjects []rbacv1.Subject `json:"subjects"`
+
+	// roleRef references the granted role. It must be a ClusterRole, or a Role in the
+	// namespace given by spec.namespace.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	RoleRef rbacv1.RoleRef `json:"roleRef"`
+
+	// namespace restricts the grant to the given namespace by creating a RoleBinding.
+	// If empty, a ClusterRoleBinding is created.
+	//
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// expiresAt is the time after which the binding is removed.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	ExpiresAt metav1.Time `json:"expiresAt"`
+
+	// reason documents why the access is granted, e.g. a support ticket reference.
+	// It is recorded in the audit log.
+	//
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// AccessGrantPhaseType is the type of the current phase of an AccessGrant.
+//
+// +kubebuilder:validation:Enum=Active;Expired
+type AccessGrantPhaseType string
+
+const (
+	// AccessGrantPhaseActive means the binding of the grant exists.
+	AccessGrantPhaseActive AccessGrantPhaseType = "Active"
+	// AccessGrantPhaseExpired means the grant has expired and its binding has been removed.
+	AccessGrantPhaseExpired AccessGrantPhaseType = "Expired"
+)
+
+// AccessGrantStatus communicates the observed state of the AccessGrant.
+type AccessGrantStatus struct {
+	// phase is the current phase of the grant.
+	//
+	// +optional
+	Phase AccessGrantPhaseType `json:"phase,omitempty"`
+
+	// bindingName is the name of the RoleBinding or ClusterRoleBinding created for this grant.
+	//
+	// +optional
+	BindingName string `json:"bindingName,omitempty"`
+
+	// Current processing state of the AccessGrant.
+	// +optional
+	Conditions conditionsv1alpha1.Conditions `json:"conditions,omitempty"`
+}
+
+// These are valid conditions of AccessGrant.
+const (
+	// AccessGrantBound means that the binding of the grant has been created.
+	AccessGrantBound conditionsv1alpha1.ConditionType = "Bound"
+
+	// AccessGrantBindingFailedReason is a reason for the Bound condition that the binding could not be created.
+	AccessGrantBindingFailedReason = "BindingFailed"
+	// AccessGrantExpiredReason is a reason for the Bound condition that the grant has expired.
+	AccessGrantExpiredReason = "Expired"
+)
+
+// AccessGrantList is a list of AccessGrant resources
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type AccessGrantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []AccessGrant `json:"items"`
+}
+
+// DenyPolicy prohibits requests in the workspace it lives in and in all workspaces below it.
+// Deny policies are evaluated before RBAC, i.e. a request matching a policy is rejected even
+// if RBAC allows it, unless the requesting user is exempted by the policy. This allows to
+// express organization-wide prohibitions like "nobody but the break-glass group may delete
+// workspaces of type Prod".
+//
+// +crd
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:scope=Cluster,categories=kcp
+type DenyPolicy struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +required
+	// +kubebuilder:validation:Required
+	Spec DenyPolicySpec `json:"spec"`
+}
+
+// DenyPolicySpec holds the desired state of the DenyPolicy.
+type DenyPolicySpec struct {
+	// rules are the requests denied by this policy. A request is denied if it matches
+	// any of the rules. The rules are interpreted like the rules of a ClusterRole.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	Rules []rbacv1.PolicyRule `json:"rules"`
+
+	// clusterWorkspaceTypes restricts the policy to requests for clusterworkspaces
+	// and workspaces of the given types. Requests without a name, e.g. list or
+	// create, do not match if this is set.
+	//
+	// +optional
+	ClusterWorkspaceTypes []string `json:"clusterWorkspaceTypes,omitempty"`
+
+	// exemptUsers are the names of users the policy does not apply to.
+	//
+	// +optional
+	ExemptUsers []string `json:"exemptUsers,omitempty"`
+
+	// exemptGroups are the groups whose members the policy does not apply to,
+	// e.g. a break-glass group.
+	//
+	// +optional
+	ExemptGroups []string `json:"exemptGroups,omitempty"`
+}
+
+// DenyPolicyList is a list of DenyPolicy resources
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type DenyPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []DenyPolicy `json:"items"`
+}
+
+// Replication mirrors the objects of a resource selected by labels from the workspace it
+// lives in into its child workspaces, and keeps them in sync. The replicas are read-only in
+// the target workspaces. This allows to distribute organization-wide configuration and
+// policies, e.g. ConfigMaps, ClusterRoles or DenyPolicies.
+//
+// +crd
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:scope=Cluster,categories=kcp
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Resource",type=string,JSONPath=`.spec.resource.resource`,description="Resource of the replicated objects"
+// +kubebuilder:printcolumn:name="Workspaces",type=integer,JSONPath=`.status.replicatedWorkspaces`,description="Number of workspaces the objects are replicated to"
+// +kubebuilder:printcolumn:name="Objects",type=integer,JSONPath=`.status.replicatedObjects`,description="Number of replicated objects"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type Replication struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +required
+	// +kubebuilder:validation:Required
+	Spec ReplicationSpec `json:"spec"`
+
+	// +optional
+	Status ReplicationStatus `json:"status,omitempty"`
+}
+
+func (in *Replication) SetConditions(c conditionsv1alpha1.Conditions) {
+	in.Status.Conditions = c
+}
+
+func (in *Replication) GetConditions() conditionsv1alpha1.Conditions {
+	return in.Status.Conditions
+}
+
+var _ conditions.Getter = &Replication{}
+var _ conditions.Setter = &Replication{}
+
+// ReplicationSpec holds the desired state of the Replication.
+type ReplicationSpec struct {
+	// resource is the resource of the replicated objects.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Resource ReplicationResource `json:"resource"`
+
+	// namespace restricts the replicated objects of a namespaced resource to the given
+	// namespace. If empty, objects of all namespaces are replicated. Replicas are created
+	// in the namespace of their source, which must exist in the target workspaces.
+	//
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// selector selects the replicated objects by their labels. An empty selector
+	// selects all objects.
+	//
+	// +optional
+	Selector metav1.LabelSelector `json:"selector,omitempty"`
+
+	// workspaceSelector selects the target workspaces among the child workspaces by the
+	// labels of their ClusterWorkspace. If empty, objects are replicated into all child
+	// workspaces. Only workspaces in the Ready phase are targeted.
+	//
+	// +optional
+	WorkspaceSelector *metav1.LabelSelector `json:"workspaceSelector,omitempty"`
+}
+
+// ReplicationResource identifies a resource by its group, version and plural name.
+type ReplicationResource struct {
+	// group is the API group of the resource. Empty for the core group.
+	//
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// version is the API version of the resource.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Version string `json:"version"`
+
+	// resource is the plural lower-case name of the resource, e.g. configmaps.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Resource string `json:"resource"`
+}
+
+// ReplicationStatus communicates the observed state of the Replication.
+type ReplicationStatus struct {
+	// targetWorkspaces are the names of the child workspaces the objects are replicated
+	// to. Replicas in workspaces that are not targeted anymore are removed.
+	//
+	// +optional
+	TargetWorkspaces []string `json:"targetWorkspaces,omitempty"`
+
+	// replicatedWorkspaces is the number of target workspaces all objects were replicated to.
+	//
+	// +optional
+	ReplicatedWorkspaces int32 `json:"replicatedWorkspaces,omitempty"`
+
+	// replicatedObjects is the number of selected objects in the source workspace.
+	//
+	// +optional
+	ReplicatedObjects int32 `json:"replicatedObjects,omitempty"`
+
+	// Current processing state of the Replication.
+	// +optional
+	Conditions conditionsv1alpha1.Conditions `json:"conditions,omitempty"`
+}
+
+// These are valid conditions of Replication.
+const (
+	// ReplicationReplicated means that the selected objects are replicated into all target workspaces.
+	ReplicationReplicated conditionsv1alpha1.ConditionType = "Replicated"
+
+	// ReplicationInvalidSelectorReason is a reason for the Replicated condition that a selector is invalid.
+	ReplicationInvalidSelectorReason = "InvalidSelector"
+	// ReplicationSourceFailedReason is a reason for the Replicated condition that the source objects could not be listed.
+	ReplicationSourceFailedReason = "SourceFailed"
+	// ReplicationFailedReason is a reason for the Replicated condition that replicas could not be
+	// created, updated or deleted in some target workspaces.
+	ReplicationFailedReason = "ReplicationFailed"
+	// ReplicationConflictReason is a reason for the Replicated condition that objects with the names
+	// of replicas exist in target workspaces that are no replicas of the Replication.
+	ReplicationConflictReason = "ReplicaConflict"
+	// ReplicationForbiddenReason is a reason for the Replicated condition that the creator of the
+	// Replication is not allowed to create, update and delete the resource in some target workspaces.
+	ReplicationForbiddenReason = "Forbidden"
+)
+
+const (
+	// ReplicaLabel is set on the replicas of a Replication. Objects with this label can
+	// only be changed by kcp.
+	ReplicaLabel = "tenancy.kcp.dev/replica"
+	// ReplicationAnnotation is set on the replicas of a Replication to the logical cluster
+	// and name of the Replication, in the format <cluster>|<name>.
+	ReplicationAnnotation = "tenancy.kcp.dev/replication"
+	// ReplicationCreatorAnnotation is set on Replications by admission to the user who created
+	// them, as JSON. Objects are only replicated into workspaces where this user can create,
+	// update and delete them.
+	ReplicationCreatorAnnotation = "tenancy.kcp.dev/replication-creator"
+)
+
+// ReplicationList is a list of Replication resources
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ReplicationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []Replication `json:"items"`
+}
+
+// VirtualWorkspace registers a virtual workspace server that runs outside of kcp with the
+// front-proxy. The front-proxy forwards the requests under /services/<name>/ to the server,
+// such that third parties can ship virtual workspaces as separate deployments.
+//
+// VirtualWorkspaces live in the root workspace.
+//
+// +crd
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:scope=Cluster,categories=kcp
+// +kubebuilder:printcolumn:name="URL",type=string,JSONPath=`.spec.url`,description="The URL of the virtual workspace server"
+type VirtualWorkspace struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +optional
+	Spec VirtualWorkspaceSpec `json:"spec,omitempty"`
+}
+
+// VirtualWorkspaceSpec holds the desired state of the VirtualWorkspace.
+type VirtualWorkspaceSpec struct {
+	// url is the address of the virtual workspace server, e.g. https://my-virtual-workspace:6443.
+	// The front-proxy forwards the requests under /services/<name>/ to the same path on this
+	// address, authenticating with its proxy client certificate.
+	//
+	// +kubebuilder:validation:Format=uri
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Pattern=`^https://`
+	// +required
+	URL string `json:"url"`
+
+	// caBundle is a PEM encoded CA bundle which is used to verify the serving certificate
+	// of the virtual workspace server.
+	//
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	CABundle []byte `json:"caBundle"`
+}
+
+const (
+	// VirtualWorkspacePathPrefix is the prefix of the paths served by virtual workspaces.
+	// A VirtualWorkspace serves the paths under VirtualWorkspacePathPrefix + <name> + "/".
+	VirtualWorkspacePathPrefix = "/services/"
+)
+
+// VirtualWorkspaceList is a list of VirtualWorkspace resources
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type VirtualWorkspaceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []VirtualWorkspace `json:"items"`
+}
+
+// BulkWorkspaceOperation creates, deletes or labels many child workspaces of the workspace
+// it lives in with one request, e.g. when an organization provisions hundreds of workspaces
+// during onboarding. kcp executes the operation once for every workspace, and reports the
+// result per workspace in the status, i.e. failures for some workspaces do not stop the
+// operation for the others.
+//
+// The creator of a BulkWorkspaceOperation must be allowed to execute the operation on the
+// ClusterWorkspaces, which is checked on creation. The spec is immutable.
+//
+// +crd
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:scope=Cluster,categories=kcp
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Operation",type=string,JSONPath=`.spec.operation`,description="The operation executed on the workspaces"
+// +kubebuilder:printcolumn:name="Succeeded",type=integer,JSONPath=`.status.succeeded`,description="Number of workspaces the operation succeeded for"
+// +kubebuilder:printcolumn:name="Failed",type=integer,JSONPath=`.status.failed`,description="Number of workspaces the operation failed for"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type BulkWorkspaceOperation struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +required
+	// +kubebuilder:validation:Required
+	Spec BulkWorkspaceOperationSpec `json:"spec"`
+
+	// +optional
+	Status BulkWorkspaceOperationStatus `json:"status,omitempty"`
+}
+
+func (in *BulkWorkspaceOperation) SetConditions(c conditionsv1alpha1.Conditions) {
+	in.Status.Conditions = c
+}
+
+func (in *BulkWorkspaceOperation) GetConditions() conditionsv1alpha1.Conditions {
+	return in.Status.Conditions
+}
+
+var _ conditions.Getter = &BulkWorkspaceOperation{}
+var _ conditions.Setter = &BulkWorkspaceOperation{}
+
+// BulkWorkspaceOperationType is the operation of a BulkWorkspaceOperation.
+//
+// +kubebuilder:validation:Enum=Create;Delete;Label
+type BulkWorkspaceOperationType string
+
+const (
+	// BulkWorkspaceOperationCreate creates ClusterWorkspaces of the given type with the given labels.
+	BulkWorkspaceOperationCreate BulkWorkspaceOperationType = "Create"
+	// BulkWorkspaceOperationDelete deletes ClusterWorkspaces.
+	BulkWorkspaceOperationDelete BulkWorkspaceOperationType = "Delete"
+	// BulkWorkspaceOperationLabel sets and removes labels of ClusterWorkspaces.
+	BulkWorkspaceOperationLabel BulkWorkspaceOperationType = "Label"
+)
+
+// BulkWorkspaceOperationSpec holds the desired state of the BulkWorkspaceOperation.
+type BulkWorkspaceOperationSpec struct {
+	// operation is executed for every workspace, one of Create, Delete or Label.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Operation BulkWorkspaceOperationType `json:"operation"`
+
+	// workspaces are the names of the ClusterWorkspaces the operation is executed for,
+	// in order.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=1000
+	Workspaces []string `json:"workspaces"`
+
+	// type is the type of the created workspaces. Only valid for the Create operation.
+	//
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[A-Z][a-zA-Z0-9]+$`
+	Type string `json:"type,omitempty"`
+
+	// labels are set on the workspaces by the Create and Label operations.
+	//
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// removeLabels are the keys of the labels removed from the workspaces by the Label
+	// operation.
+	//
+	// +optional
+	RemoveLabels []string `json:"removeLabels,omitempty"`
+}
+
+// BulkWorkspaceOperationStatus communicates the observed state of the BulkWorkspaceOperation.
+type BulkWorkspaceOperationStatus struct {
+	// succeeded is the number of workspaces the operation succeeded for.
+	//
+	// +optional
+	Succeeded int32 `json:"succeeded,omitempty"`
+
+	// failed is the number of workspaces the operation failed for.
+	//
+	// +optional
+	Failed int32 `json:"failed,omitempty"`
+
+	// results are the results of the operation for the workspaces it was executed for so
+	// far, in the order of spec.workspaces.
+	//
+	// +optional
+	Results []BulkWorkspaceOperationResult `json:"results,omitempty"`
+
+	// Current processing state of the BulkWorkspaceOperation.
+	// +optional
+	Conditions conditionsv1alpha1.Conditions `json:"conditions,omitempty"`
+}
+
+// BulkWorkspaceOperationResult is the result of a BulkWorkspaceOperation for a workspace.
+type BulkWorkspaceOperationResult struct {
+	// name is the name of the ClusterWorkspace.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// error is the reason the operation failed for the workspace. Empty if it succeeded.
+	//
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// These are valid conditions of BulkWorkspaceOperation.
+const (
+	// BulkWorkspaceOperationSucceeded means that the operation was executed for all workspaces
+	// and succeeded for all of them.
+	BulkWorkspaceOperationSucceeded conditionsv1alpha1.ConditionType = "Succeeded"
+
+	// BulkWorkspaceOperationInProgressReason is a reason for the Succeeded condition that the
+	// operation was not executed for all workspaces yet.
+	BulkWorkspaceOperationInProgressReason = "InProgress"
+	// BulkWorkspaceOperationFailedReason is a reason for the Succeeded condition that the
+	// operation was executed for all workspaces, but failed for some of them.
+	BulkWorkspaceOperationFailedReason = "Failed"
+)
+
+// BulkWorkspaceOperationAnnotation is set on the workspaces created by a BulkWorkspaceOperation
+// to its UID.
+const BulkWorkspaceOperationAnnotation = "tenancy.kcp.dev/bulk-workspace-operation"
+
+// BulkWorkspaceOperationList is a list of BulkWorkspaceOperation resources
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type BulkWorkspaceOperationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []BulkWorkspaceOperation `json:"items"`
+}
+
+// PolicyBundle distributes admission policies, RBAC baselines and quota defaults from the
+// workspace it lives in, usually an organization workspace, into all workspaces below it, and
+// keeps them in place. Objects which drifted from the bundle, e.g. because they were changed
+// or deleted in a workspace, are detected and restored. The compliance of every workspace
+// with the bundle is reported in the status.
+//
+// +crd
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:scope=Cluster,categories=kcp
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Enforcement",type=string,JSONPath=`.spec.enforcement`,description="Whether drift is restored or only reported"
+// +kubebuilder:printcolumn:name="Workspaces",type=integer,JSONPath=`.status.targetedWorkspaces`,description="Number of workspaces the bundle applies to"
+// +kubebuilder:printcolumn:name="Compliant",type=integer,JSONPath=`.status.compliantWorkspaces`,description="Number of workspaces complying with the bundle"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type PolicyBundle struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +required
+	// +kubebuilder:validation:Required
+	Spec PolicyBundleSpec `json:"spec"`
+
+	// +optional
+	Status PolicyBundleStatus `json:"status,omitempty"`
+}
+
+func (in *PolicyBundle) SetConditions(c conditionsv1alpha1.Conditions) {
+	in.Status.Conditions = c
+}
+
+func (in *PolicyBundle) GetConditions() conditionsv1alpha1.Conditions {
+	return in.Status.Conditions
+}
+
+var _ conditions.Getter = &PolicyBundle{}
+var _ conditions.Setter = &PolicyBundle{}
+
+// PolicyBundleEnforcement defines how drift from a PolicyBundle is handled.
+//
+// +kubebuilder:validation:Enum=Enforce;Audit
+type PolicyBundleEnforcement string
+
+const (
+	// PolicyBundleEnforce creates the objects of the bundle and restores them on drift.
+	PolicyBundleEnforce PolicyBundleEnforcement = "Enforce"
+	// PolicyBundleAudit only reports missing and drifted objects, without changing them.
+	PolicyBundleAudit PolicyBundleEnforcement = "Audit"
+)
+
+// PolicyBundleSpec holds the desired state of the PolicyBundle.
+type PolicyBundleSpec struct {
+	// enforcement defines whether missing and drifted objects are restored, or only reported.
+	//
+	// +optional
+	// +kubebuilder:default=Enforce
+	Enforcement PolicyBundleEnforcement `json:"enforcement,omitempty"`
+
+	// workspaceSelector selects the target workspaces among the workspaces below the workspace
+	// of the bundle by the labels of their ClusterWorkspace. If empty, all of them are targeted.
+	// Only workspaces in the Ready phase are targeted.
+	//
+	// +optional
+	WorkspaceSelector *metav1.LabelSelector `json:"workspaceSelector,omitempty"`
+
+	// admissionPolicies are created as DenyPolicies in the target workspaces.
+	//
+	// +optional
+	AdmissionPolicies []PolicyBundleAdmissionPolicy `json:"admissionPolicies,omitempty"`
+
+	// clusterRoles are created in the target workspaces.
+	//
+	// +optional
+	ClusterRoles []PolicyBundleClusterRole `json:"clusterRoles,omitempty"`
+
+	// clusterRoleBindings are created in the target workspaces.
+	//
+	// +optional
+	ClusterRoleBindings []PolicyBundleClusterRoleBinding `json:"clusterRoleBindings,omitempty"`
+
+	// resourceQuotas are created in every namespace of the target workspaces.
+	//
+	// +optional
+	ResourceQuotas []PolicyBundleResourceQuota `json:"resourceQuotas,omitempty"`
+}
+
+// PolicyBundleAdmissionPolicy is a DenyPolicy of a PolicyBundle.
+type PolicyBundleAdmissionPolicy struct {
+	// name is the name of the DenyPolicy.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// spec is the spec of the DenyPolicy.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Spec DenyPolicySpec `json:"spec"`
+}
+
+// PolicyBundleClusterRole is a ClusterRole of a PolicyBundle.
+type PolicyBundleClusterRole struct {
+	// name is the name of the ClusterRole.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// rules are the rules of the ClusterRole.
+	//
+	// +optional
+	Rules []rbacv1.PolicyRule `json:"rules,omitempty"`
+}
+
+// PolicyBundleClusterRoleBinding is a ClusterRoleBinding of a PolicyBundle.
+type PolicyBundleClusterRoleBinding struct {
+	// name is the name of the ClusterRoleBinding.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// roleRef references the bound ClusterRole.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	RoleRef rbacv1.RoleRef `json:"roleRef"`
+
+	// subjects are the subjects the role is bound to.
+	//
+	// +optional
+	Subjects []rbacv1.Subject `json:"subjects,omitempty"`
+}
+
+// PolicyBundleResourceQuota is a ResourceQuota of a PolicyBundle.
+type PolicyBundleResourceQuota struct {
+	// name is the name of the ResourceQuota.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// hard is the set of enforced hard limits for each named resource.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Hard corev1.ResourceList `json:"hard"`
+}
+
+// PolicyBundleStatus communicates the observed state of the PolicyBundle.
+type PolicyBundleStatus struct {
+	// targetedWorkspaces is the number of workspaces the bundle applies to.
+	//
+	// +optional
+	TargetedWorkspaces int32 `json:"targetedWorkspaces,omitempty"`
+
+	// compliantWorkspaces is the number of targeted workspaces complying with the bundle.
+	//
+	// +optional
+	CompliantWorkspaces int32 `json:"compliantWorkspaces,omitempty"`
+
+	// workspaces is the compliance report of the targeted workspaces, ordered by workspace.
+	// Objects in workspaces which are not targeted anymore are removed.
+	//
+	// +optional
+	Workspaces []PolicyBundleWorkspaceStatus `json:"workspaces,omitempty"`
+
+	// Current processing state of the PolicyBundle.
+	// +optional
+	Conditions conditionsv1alpha1.Conditions `json:"conditions,omitempty"`
+}
+
+// PolicyBundleWorkspaceStatus reports the compliance of a workspace with a PolicyBundle.
+type PolicyBundleWorkspaceStatus struct {
+	// workspace is the logical cluster name of the workspace, e.g. root:org:team.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Workspace string `json:"workspace"`
+
+	// compliant is true if all objects of the bundle exist as defined in the workspace.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Compliant bool `json:"compliant"`
+
+	// drifted are the objects found missing or different from the bundle, as <resource>/<name> or
+	// <resource>/<namespace>/<name>. With Enforce, these are the objects restored on the last drift.
+	// With Audit, these are the current differences.
+	//
+	// +optional
+	Drifted []string `json:"drifted,omitempty"`
+
+	// lastDriftTime is the time drift was last found in the workspace.
+	//
+	// +optional
+	LastDriftTime *metav1.Time `json:"lastDriftTime,omitempty"`
+
+	// observedGeneration is the generation of the bundle last applied to the workspace.
+	//
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// conflicts are objects existing in the workspace with names of objects of the bundle, which
+	// are not managed by the bundle. They are not changed.
+	//
+	// +optional
+	Conflicts []string `json:"conflicts,omitempty"`
+
+	// message is the reason the bundle could not be checked or enforced in the workspace.
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// These are valid conditions of PolicyBundle.
+const (
+	// PolicyBundleCompliant means that all targeted workspaces comply with the bundle.
+	PolicyBundleCompliant conditionsv1alpha1.ConditionType = "Compliant"
+
+	// PolicyBundleInvalidSelectorReason is a reason for the Compliant condition that the workspace selector is invalid.
+	PolicyBundleInvalidSelectorReason = "InvalidSelector"
+	// PolicyBundleNonCompliantReason is a reason for the Compliant condition that some targeted
+	// workspaces do not comply with the bundle.
+	PolicyBundleNonCompliantReason = "NonCompliant"
+)
+
+const (
+	// PolicyBundleManagedLabel is set on the objects created by PolicyBundles.
+	PolicyBundleManagedLabel = "tenancy.kcp.dev/policy-bundle-managed"
+	// PolicyBundleAnnotation is set on the objects created by a PolicyBundle to the logical
+	// cluster and name of the PolicyBundle, in the format <cluster>|<name>.
+	PolicyBundleAnnotation = "tenancy.kcp.dev/policy-bundle"
+)
+
+// PolicyBundleList is a list of PolicyBundle resources
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type PolicyBundleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []PolicyBundle `json:"items"`
+}
+
+// HomeWorkspacePolicy provisions personal home workspaces for users on their first
+// request to kcp. Home workspaces are created below spec.homeRoot, in bucket workspaces
+// named after a hash of the user name, such that no workspace gets too many children.
+// The owner becomes admin of their home workspace.
+//
+// HomeWorkspacePolicies are only respected in the root workspace. If several policies
+// match a user, the one with the highest priority applies, and then the first by name.
+//
+// +crd
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:scope=Cluster,categories=kcp
+// +kubebuilder:printcolumn:name="Home Root",type=string,JSONPath=`.spec.homeRoot`,description="Workspace containing the home workspaces"
+// +kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.workspaceType`,description="Type of the home workspaces"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=`.metadata.creationTimestamp`
+type HomeWorkspacePolicy struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +required
+	// +kubebuilder:validation:Required
+	Spec HomeWorkspacePolicySpec `json:"spec"`
+}
+
+// HomeWorkspacePolicySpec holds the desired state of the HomeWorkspacePolicy.
+type HomeWorkspacePolicySpec struct {
+	// groups selects the users the policy applies to by their groups, e.g.
+	// system:authenticated for all users. Users with a name starting with
+	// "system:", e.g. service accounts, never get a home workspace.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	Groups []string `json:"groups"`
+
+	// priority orders the policies matching a user, higher first.
+	//
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+
+	// homeRoot is the path of the workspace containing the bucket workspaces with
+	// the home workspaces. It is created with its parents if it does not exist.
+	//
+	// +optional
+	// +kubebuilder:default="root:users"
+	// +kubebuilder:validation:Pattern=`^root(:[a-z]([a-z0-9-]{0,61}[a-z0-9])?)*$`
+	HomeRoot string `json:"homeRoot,omitempty"`
+
+	// bucketLevels is the number of bucket workspaces between the home root and
+	// the home workspaces.
+	//
+	// +optional
+	// +kubebuilder:default=2
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=5
+	BucketLevels int32 `json:"bucketLevels,omitempty"`
+
+	// bucketSize is the number of hex characters of the hash of the user name used
+	// for the name of each bucket workspace. Every bucket workspace has at most
+	// 16^bucketSize children.
+	//
+	// +optional
+	// +kubebuilder:default=2
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=4
+	BucketSize int32 `json:"bucketSize,omitempty"`
+
+	// workspaceType is the type of the home workspaces. Types other than Universal
+	// must exist as ClusterWorkspaceType in the home root workspace, and are copied
+	// into the bucket workspaces containing home workspaces.
+	//
+	// +optional
+	// +kubebuilder:default="Universal"
+	// +kubebuilder:validation:Pattern=`^[A-Z][a-zA-Z0-9]+$`
+	WorkspaceType string `json:"workspaceType,omitempty"`
+
+	// limits restrict the objects stored in every home workspace, in addition to the
+	// limits of the workspace type.
+	//
+	// +optional
+	Limits *ClusterWorkspaceLimits `json:"limits,omitempty"`
+
+	// reapAfter is the duration after the last request of the owner after which an
+	// unused home workspace is deleted with all its content. Home workspaces are
+	// never deleted if unset.
+	//
+	// +optional
+	ReapAfter *metav1.Duration `json:"reapAfter,omitempty"`
+}
+
+const (
+	// HomeWorkspaceLabel is set to "true" on the ClusterWorkspaces of home workspaces.
+	HomeWorkspaceLabel = "tenancy.kcp.dev/home"
+	// HomeWorkspaceOwnerAnnotation is the user name of the owner of a home workspace.
+	HomeWorkspaceOwnerAnnotation = "tenancy.kcp.dev/home-owner"
+	// HomeWorkspacePolicyAnnotation is the name of the HomeWorkspacePolicy a home
+	// workspace was created by.
+	HomeWorkspacePolicyAnnotation = "tenancy.kcp.dev/home-policy"
+	// HomeWorkspaceLastActivityAnnotation is the time of the last request of the owner
+	// of a home workspace in RFC3339 format. It is updated at most every hour.
+	HomeWorkspaceLastActivityAnnotation = "tenancy.kcp.dev/home-last-activity"
+)
+
+// HomeWorkspacePolicyList is a list of HomeWorkspacePolicy resources
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type HomeWorkspacePolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []HomeWorkspacePolicy `json:"items"`
+}
+
+// ObjectTransfer moves an object, or a namespace with all objects in it, from the workspace
+// it lives in to another workspace, e.g. when teams reorganize their workspaces. kcp creates
+// the objects in the destination workspace, and then deletes them in the source workspace.
+//
+// UIDs cannot be kept across workspaces. Instead, the logical cluster and UID of the source
+// object are appended to the tenancy.kcp.dev/uid-lineage annotation of the new object, such
+// that its history can be followed across transfers. Owner references between transferred
+// objects are updated to the new UIDs, owner references to other objects are removed.
+//
+// The creator of an ObjectTransfer must be allowed to delete the object in the source workspace
+// and to create it in the destination workspace, which is checked on creation. The spec is
+// immutable.
+//
+// +crd
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:scope=Cluster,categories=kcp
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Resource",type=string,JSONPath=`.spec.resource.resource`,description="Resource of the transferred object"
+// +kubebuilder:printcolumn:name="Object",type=string,JSONPath=`.spec.name`,description="Name of the transferred object"
+// +kubebuilder:printcolumn:name="Destination",type=string,JSONPath=`.spec.destination`,description="Workspace the object is transferred to"
+// +kubebuilder:printcolumn:name="Objects",type=integer,JSONPath=`.status.transferredObjects`,description="Number of transferred objects"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type ObjectTransfer struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +required
+	// +kubebuilder:validation:Required
+	Spec ObjectTransferSpec `json:"spec"`
+
+	// +optional
+	Status ObjectTransferStatus `json:"status,omitempty"`
+}
+
+func (in *ObjectTransfer) SetConditions(c conditionsv1alpha1.Conditions) {
+	in.Status.Conditions = c
+}
+
+func (in *ObjectTransfer) GetConditions() conditionsv1alpha1.Conditions {
+	return in.Status.Conditions
+}
+
+var _ conditions.Getter = &ObjectTransfer{}
+var _ conditions.Setter = &ObjectTransfer{}
+
+// ObjectTransferSpec holds the desired state of the ObjectTransfer.
+type ObjectTransferSpec struct {
+	// resource is the resource of the transferred object. For namespaces, i.e. version v1
+	// and resource namespaces, the namespace is transferred with all objects in it.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Resource ReplicationResource `json:"resource"`
+
+	// namespace is the namespace of the transferred object of a namespaced resource.
+	// The namespace must exist in the destination workspace.
+	//
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// name is the name of the transferred object.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// destination is the path of the workspace the object is transferred to, e.g.
+	// root:org:team. It must be served by the same shard as the source workspace.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^root(:[a-z0-9]([-a-z0-9]*[a-z0-9])?)+$`
+	Destination string `json:"destination"`
+}
+
+// ObjectTransferStatus communicates the observed state of the ObjectTransfer.
+type ObjectTransferStatus struct {
+	// transferredObjects is the number of objects transferred to the destination workspace.
+	//
+	// +optional
+	TransferredObjects int32 `json:"transferredObjects,omitempty"`
+
+	// objects are the transferred objects with their old and new UIDs, at most 1000.
+	//
+	// +optional
+	Objects []TransferredObject `json:"objects,omitempty"`
+
+	// completionTime is the time the transfer completed.
+	//
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Current processing state of the ObjectTransfer.
+	// +optional
+	Conditions conditionsv1alpha1.Conditions `json:"conditions,omitempty"`
+}
+
+// TransferredObject is an object transferred by an ObjectTransfer.
+type TransferredObject struct {
+	// group is the API group of the object. Empty for the core group.
+	//
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// resource is the plural lower-case name of the resource of the object.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Resource string `json:"resource"`
+
+	// namespace is the namespace of the object. Empty for cluster-scoped objects.
+	//
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// name is the name of the object.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// sourceUID is the UID of the object in the source workspace.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	SourceUID string `json:"sourceUID"`
+
+	// destinationUID is the UID of the object in the destination workspace.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	DestinationUID string `json:"destinationUID"`
+}
+
+// These are valid conditions of ObjectTransfer.
+const (
+	// ObjectTransferTransferred means that the objects were created in the destination
+	// workspace and deleted in the source workspace.
+	ObjectTransferTransferred conditionsv1alpha1.ConditionType = "Transferred"
+
+	// ObjectTransferSourceNotFoundReason is a reason for the Transferred condition that the
+	// object does not exist in the source workspace.
+	ObjectTransferSourceNotFoundReason = "SourceNotFound"
+	// ObjectTransferConflictReason is a reason for the Transferred condition that objects with
+	// the same names exist in the destination workspace.
+	ObjectTransferConflictReason = "Conflict"
+	// ObjectTransferRejectedReason is a reason for the Transferred condition that the destination
+	// workspace rejected objects, e.g. because the resource is not served there, or admission
+	// denied them. The objects created so far are removed from the destination workspace.
+	ObjectTransferRejectedReason = "Rejected"
+)
+
+// ObjectTransferUIDLineageAnnotation is set on transferred objects to the comma separated
+// logical clusters and UIDs the object had before, in the format <cluster>|<uid>, oldest first.
+const ObjectTransferUIDLineageAnnotation = "tenancy.kcp.dev/uid-lineage"
+
+// ObjectTransferList is a list of ObjectTransfer resources
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ObjectTransferList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ObjectTransfer `json:"items"`
+}
+
 const (
 	// ClusterWorkspacePhaseLabel holds the ClusterWorkspace.Status.Phase value, and is enforced to match
 	// by a mutating admission webhook.
@@ -20,6 +20,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/third_party/conditions/apis/conditions/v1alpha1"
 )
 
 // Workspace defines a generic Kubernetes-cluster-like endpoint, with standard Kubernetes
@@ -66,6 +67,13 @@ type WorkspaceSpec struct {
 	// +kubebuilder:default:="Universal"
 	// +kubebuilder:validation:Pattern=`^[A-Z][a-zA-Z0-9]+$`
 	Type string `json:"type,omitempty"`
+
+	// expiration schedules the deletion of the workspace, e.g. of a pull request preview
+	// or training environment. Before it expires, the workspace is marked with the
+	// WorkspaceExpiring condition.
+	//
+	// +optional
+	Expiration *v1alpha1.WorkspaceExpiration `json:"expiration,omitempty"`
 }
 
 // WorkspaceStatus communicates the observed state of the Workspace.
@@ -80,6 +88,20 @@ type WorkspaceStatus struct {
 
 	// Phase of the workspace (Initializing / Active / Terminating). This field is ALPHA.
 	Phase v1alpha1.ClusterWorkspacePhaseType `json:"phase,omitempty"`
+
+	// conditions of the workspace, e.g. SchedulingReady, InitializersComplete,
+	// APIBindingsReady and WorkspaceContentDeleted. This field is ALPHA.
+	//
+	// +optional
+	Conditions conditionsv1alpha1.Conditions `json:"conditions,omitempty"`
+
+	// initializerStatuses records the progress of the initializers of the workspace.
+	// This field is ALPHA.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	InitializerStatuses []v1alpha1.ClusterWorkspaceInitializerStatus `json:"initializerStatuses,omitempty"`
 }
 
 // WorkspaceList is a list of Workspaces
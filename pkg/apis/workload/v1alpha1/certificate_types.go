@@ -0,0 +1,138 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/third_party/conditions/apis/conditions/v1alpha1"
+)
+
+// Certificate claims a TLS certificate for DNS names of the workspace, e.g. for ingresses
+// synced to workload clusters. kcp issues the certificate centrally through ACME, and
+// keeps it in a secret of type kubernetes.io/tls in the namespace of the Certificate.
+//
+// Certificates are only issued for names claimed by accepted DNSRecords of the workspace.
+//
+// +crd
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Namespaced,categories=kcp
+// +kubebuilder:printcolumn:name="Secret",type="string",JSONPath=`.spec.secretName`,description="The secret holding the certificate"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=`.status.conditions[?(@.type=="Ready")].status`,description="Whether the secret holds a valid certificate"
+// +kubebuilder:printcolumn:name="Expires",type="date",JSONPath=`.status.notAfter`,description="When the certificate expires"
+type Certificate struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec holds the desired state.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Spec CertificateSpec `json:"spec"`
+
+	// Status communicates the observed state.
+	//
+	// +optional
+	Status CertificateStatus `json:"status,omitempty"`
+}
+
+func (in *Certificate) GetConditions() conditionsv1alpha1.Conditions {
+	return in.Status.Conditions
+}
+
+func (in *Certificate) SetConditions(conditions conditionsv1alpha1.Conditions) {
+	in.Status.Conditions = conditions
+}
+
+// CertificateSpec defines the desired state of Certificate.
+type CertificateSpec struct {
+	// dnsNames are the DNS names of the certificate. Each must be claimed by an
+	// accepted DNSRecord of the workspace.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=100
+	DNSNames []string `json:"dnsNames"`
+
+	// secretName is the name of the secret in the namespace of the Certificate that
+	// the certificate and its private key are written to, with the keys tls.crt and
+	// tls.key. The secret is deleted with the Certificate.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	SecretName string `json:"secretName"`
+}
+
+// CertificateStatus defines the observed state of Certificate.
+type CertificateStatus struct {
+	// notBefore is the start of the validity of the issued certificate.
+	//
+	// +optional
+	NotBefore *metav1.Time `json:"notBefore,omitempty"`
+
+	// notAfter is the end of the validity of the issued certificate.
+	//
+	// +optional
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+
+	// renewalTime is when the certificate is renewed, after two thirds of its validity.
+	//
+	// +optional
+	RenewalTime *metav1.Time `json:"renewalTime,omitempty"`
+
+	// conditions is a list of conditions that apply to the Certificate.
+	//
+	// +optional
+	Conditions conditionsv1alpha1.Conditions `json:"conditions,omitempty"`
+}
+
+// CertificateList is a list of Certificate resources
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type CertificateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []Certificate `json:"items"`
+}
+
+const (
+	// CertificateReady is a condition for Certificate that its secret holds a valid certificate
+	// for the DNS names.
+	CertificateReady conditionsv1alpha1.ConditionType = "Ready"
+
+	// CertificateDNSNameNotClaimedReason is a reason for the Ready condition that a DNS name
+	// is not claimed by an accepted DNSRecord of the workspace.
+	CertificateDNSNameNotClaimedReason = "DNSNameNotClaimed"
+	// CertificateSecretConflictReason is a reason for the Ready condition that a secret of the
+	// name exists that does not belong to the Certificate.
+	CertificateSecretConflictReason = "SecretConflict"
+	// CertificateIssuerNotConfiguredReason is a reason for the Ready condition that kcp is not
+	// configured to issue certificates.
+	CertificateIssuerNotConfiguredReason = "IssuerNotConfigured"
+	// CertificateIssuingFailedReason is a reason for the Ready condition that the certificate
+	// could not be issued.
+	CertificateIssuingFailedReason = "IssuingFailed"
+
+	// CertificateLabel is set on the secrets of Certificates to the name of their Certificate.
+	CertificateLabel = "workload.kcp.dev/certificate"
+)
@@ -0,0 +1,224 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/third_party/conditions/apis/conditions/v1alpha1"
+)
+
+// DNSZone delegates a DNS domain of the platform to workspaces. Workspaces claim names
+// under the domain with DNSRecords, which kcp programs through the DNS provider of the zone.
+//
+// DNSZones are only honored in the root workspace, and their domains are unique.
+//
+// +crd
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:scope=Cluster,categories=kcp
+// +kubebuilder:printcolumn:name="Domain",type="string",JSONPath=`.spec.domain`,description="The delegated domain"
+// +kubebuilder:printcolumn:name="Provider",type="string",JSONPath=`.spec.provider`,description="The DNS provider programming the records"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=`.metadata.creationTimestamp`
+type DNSZone struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec holds the desired state.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Spec DNSZoneSpec `json:"spec"`
+}
+
+// DNSZoneSpec defines the desired state of DNSZone.
+type DNSZoneSpec struct {
+	// domain is the delegated DNS domain, e.g. apps.example.com. Names are claimed
+	// below the domain. Zones of subdomains take precedence. It is immutable.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern:="^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$"
+	Domain string `json:"domain"`
+
+	// provider is the name of the DNS provider of kcp programming the records of the zone,
+	// as configured with --dns-provider-webhook.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Provider string `json:"provider"`
+
+	// workspaces are the logical cluster names of the workspaces, e.g. root:org, that
+	// can claim names in the zone, including the workspaces below them. All workspaces
+	// can claim names if empty.
+	//
+	// +optional
+	Workspaces []string `json:"workspaces,omitempty"`
+}
+
+// DNSZoneList is a list of DNSZone resources
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type DNSZoneList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []DNSZone `json:"items"`
+}
+
+// DNSRecord claims a DNS name under a DNSZone for the workspace, and points it to
+// its targets, e.g. the load balancer of an ingress synced to a workload cluster.
+//
+// Names are unique across all workspaces: the oldest DNSRecord of a name is
+// accepted, others are rejected with the Accepted condition being false.
+//
+// +crd
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Namespaced,categories=kcp
+// +kubebuilder:printcolumn:name="Name",type="string",JSONPath=`.spec.name`,description="The claimed DNS name"
+// +kubebuilder:printcolumn:name="Type",type="string",JSONPath=`.spec.recordType`,description="The type of the record"
+// +kubebuilder:printcolumn:name="Zone",type="string",JSONPath=`.status.zone`,description="The DNSZone of the name"
+// +kubebuilder:printcolumn:name="Accepted",type="string",JSONPath=`.status.conditions[?(@.type=="Accepted")].status`,description="Whether the name is claimed by the record"
+// +kubebuilder:printcolumn:name="Programmed",type="string",JSONPath=`.status.conditions[?(@.type=="Programmed")].status`,description="Whether the record is programmed in the DNS provider"
+type DNSRecord struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec holds the desired state.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Spec DNSRecordSpec `json:"spec"`
+
+	// Status communicates the observed state.
+	//
+	// +optional
+	Status DNSRecordStatus `json:"status,omitempty"`
+}
+
+func (in *DNSRecord) GetConditions() conditionsv1alpha1.Conditions {
+	return in.Status.Conditions
+}
+
+func (in *DNSRecord) SetConditions(conditions conditionsv1alpha1.Conditions) {
+	in.Status.Conditions = conditions
+}
+
+// DNSRecordType is the type of a DNS record.
+//
+// +kubebuilder:validation:Enum=A;AAAA;CNAME;TXT
+type DNSRecordType string
+
+const (
+	DNSRecordTypeA     DNSRecordType = "A"
+	DNSRecordTypeAAAA  DNSRecordType = "AAAA"
+	DNSRecordTypeCNAME DNSRecordType = "CNAME"
+	DNSRecordTypeTXT   DNSRecordType = "TXT"
+)
+
+// DNSRecordSpec defines the desired state of DNSRecord.
+type DNSRecordSpec struct {
+	// name is the fully qualified DNS name, below the domain of a DNSZone the
+	// workspace can claim names in. It is immutable.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern:="^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$"
+	Name string `json:"name"`
+
+	// recordType is the type of the record. It is immutable.
+	//
+	// +optional
+	// +kubebuilder:default=A
+	RecordType DNSRecordType `json:"recordType,omitempty"`
+
+	// targets are the values of the record: IPv4 addresses for A records, IPv6 addresses
+	// for AAAA records, a single DNS name for CNAME records, and text for TXT records.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	Targets []string `json:"targets"`
+
+	// ttl is the time to live of the record in seconds.
+	//
+	// +optional
+	// +kubebuilder:default=300
+	// +kubebuilder:validation:Minimum=1
+	TTL int64 `json:"ttl,omitempty"`
+}
+
+// DNSRecordStatus defines the observed state of DNSRecord.
+type DNSRecordStatus struct {
+	// zone is the name of the DNSZone the record is programmed in.
+	//
+	// +optional
+	Zone string `json:"zone,omitempty"`
+
+	// conditions is a list of conditions that apply to the DNSRecord.
+	//
+	// +optional
+	Conditions conditionsv1alpha1.Conditions `json:"conditions,omitempty"`
+}
+
+// DNSRecordList is a list of DNSRecord resources
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type DNSRecordList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []DNSRecord `json:"items"`
+}
+
+const (
+	// DNSRecordAccepted is a condition for DNSRecord that the name is claimed by the record.
+	DNSRecordAccepted conditionsv1alpha1.ConditionType = "Accepted"
+
+	// DNSZoneNotFoundReason is a reason for the Accepted condition that there is no DNSZone
+	// for the name the workspace can claim names in.
+	DNSZoneNotFoundReason = "ZoneNotFound"
+	// DNSNameConflictReason is a reason for the Accepted condition that the name is claimed
+	// by an older DNSRecord.
+	DNSNameConflictReason = "NameConflict"
+
+	// DNSRecordProgrammed is a condition for DNSRecord that the record is programmed in the
+	// DNS provider of its zone.
+	DNSRecordProgrammed conditionsv1alpha1.ConditionType = "Programmed"
+
+	// DNSRecordNotAcceptedReason is a reason for the Programmed condition that the record
+	// is not accepted.
+	DNSRecordNotAcceptedReason = "NotAccepted"
+	// DNSProviderNotFoundReason is a reason for the Programmed condition that the DNS
+	// provider of the zone is not configured.
+	DNSProviderNotFoundReason = "ProviderNotFound"
+	// DNSProviderErrorReason is a reason for the Programmed condition that the DNS provider
+	// failed to program the record.
+	DNSProviderErrorReason = "ProviderError"
+
+	// DNSRecordFinalizer is set on programmed DNSRecords to remove the record from the DNS
+	// provider before the DNSRecord is deleted.
+	DNSRecordFinalizer = "workload.kcp.dev/dns-record"
+)
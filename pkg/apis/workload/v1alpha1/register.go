@@ -45,8 +45,16 @@ var (
 // Adds the list of known types to Scheme.
 func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
+		&Certificate{},
+		&CertificateList{},
+		&DNSRecord{},
+		&DNSRecordList{},
+		&DNSZone{},
+		&DNSZoneList{},
 		&WorkloadCluster{},
 		&WorkloadClusterList{},
+		&WorkspaceWorkloadUsage{},
+		&WorkspaceWorkloadUsageList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
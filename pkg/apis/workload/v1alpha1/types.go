@@ -108,4 +108,26 @@ const (
 	// InternalDownstreamClusterLabel is a label with the upstream cluster name applied on the downstream cluster
 	// instead of state.internal.workloads.kcp.dev/<workload-cluster-name> which is used upstream.
 	InternalDownstreamClusterLabel = "internal.workloads.kcp.dev/cluster"
+
+	// ProvenanceAnnotationPrefix is the prefix of the annotations
+	//
+	//   provenance.workloads.kcp.dev/<name>
+	//
+	// on upstream resources referencing supply-chain metadata of the workload, e.g.
+	// provenance.workloads.kcp.dev/sbom or provenance.workloads.kcp.dev/attestation. The values
+	// are opaque references, e.g. URLs or OCI digests. They are recorded as audit annotations of
+	// the requests writing the resources, and syncers copy them onto the downstream resources.
+	ProvenanceAnnotationPrefix = "provenance.workloads.kcp.dev/"
+
+	// ProvenanceSourceAnnotation is set by syncers on downstream resources with provenance
+	// annotations, identifying the upstream resource they were synced from. It overrides
+	// upstream values.
+	//
+	// The format is JSON, with the logical cluster, namespace, name, uid and generation of the
+	// upstream resource.
+	ProvenanceSourceAnnotation = ProvenanceAnnotationPrefix + "source"
+
+	// ProvenanceClustersAuditAnnotation is the audit annotation listing the comma separated
+	// workload clusters a resource with provenance annotations is synced to.
+	ProvenanceClustersAuditAnnotation = ProvenanceAnnotationPrefix + "clusters"
 )
@@ -0,0 +1,61 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// WorkloadIdentityLabel is set on the workload identity secrets of ServiceAccounts to the
+	// name of their ServiceAccount.
+	WorkloadIdentityLabel = "workload.kcp.dev/workload-identity"
+
+	// WorkloadIdentityExpirationAnnotation is set on workload identity secrets to the RFC3339
+	// expiration time of their token.
+	WorkloadIdentityExpirationAnnotation = "workload.kcp.dev/workload-identity-expiration"
+
+	// WorkloadIdentityAudiencesAnnotation is set on workload identity secrets to the comma
+	// separated audiences of their token.
+	WorkloadIdentityAudiencesAnnotation = "workload.kcp.dev/workload-identity-audiences"
+
+	// WorkloadIdentityTokenKey is the key of the token in workload identity secrets.
+	WorkloadIdentityTokenKey = "token"
+
+	// WorkloadIdentityNamespaceKey is the key of the namespace of the ServiceAccount in
+	// workload identity secrets.
+	WorkloadIdentityNamespaceKey = "namespace"
+
+	workloadIdentitySecretPrefix = "kcp-identity-"
+)
+
+// WorkloadIdentitySecretName returns the name of the secret holding the workload identity
+// token of the ServiceAccount of the given name. Names too long for a secret are shortened
+// with a hash.
+func WorkloadIdentitySecretName(serviceAccount string) string {
+	name := workloadIdentitySecretPrefix + serviceAccount
+	if len(name) <= validation.DNS1123SubdomainMaxLength {
+		return name
+	}
+	hash := sha256.Sum256([]byte(serviceAccount))
+	suffix := "-" + hex.EncodeToString(hash[:])[:16]
+	return strings.TrimRight(name[:validation.DNS1123SubdomainMaxLength-len(suffix)], ".-") + suffix
+}
@@ -65,6 +65,62 @@ type WorkloadClusterSpec struct {
 	// will be unassigned from the cluster.
 	// By default, workloads scheduled to the cluster are not evicted.
 	EvictAfter *metav1.Time `json:"evictAfter,omitempty"`
+
+	// NamespaceNaming controls how the syncer names the namespaces on the
+	// workload cluster. By default, namespace names are opaque hashes.
+	// +optional
+	NamespaceNaming *NamespaceNaming `json:"namespaceNaming,omitempty"`
+}
+
+// NamespaceNamingStrategy is a strategy to name the namespaces on a workload cluster.
+//
+// +kubebuilder:validation:Enum=Hash;Readable;Template;Verbatim
+type NamespaceNamingStrategy string
+
+const (
+	// NamespaceNamingHash names namespaces "kcp<hash>", where the hash is computed
+	// from the logical cluster and the name of the upstream namespace.
+	NamespaceNamingHash NamespaceNamingStrategy = "Hash"
+
+	// NamespaceNamingReadable names namespaces "<prefix>-<workspace path>-<namespace>",
+	// with the colons of the workspace path replaced by dashes. Names longer than 63
+	// characters are truncated and get a hash suffix.
+	NamespaceNamingReadable NamespaceNamingStrategy = "Readable"
+
+	// NamespaceNamingTemplate names namespaces by a Go template.
+	NamespaceNamingTemplate NamespaceNamingStrategy = "Template"
+
+	// NamespaceNamingVerbatim uses the names of the upstream namespaces. Namespaces
+	// on the workload cluster that belong to another workspace, or that are not
+	// created by the syncer, are not synced to.
+	NamespaceNamingVerbatim NamespaceNamingStrategy = "Verbatim"
+)
+
+// NamespaceNaming configures the names of namespaces on a workload cluster.
+type NamespaceNaming struct {
+	// strategy is the naming strategy.
+	//
+	// +kubebuilder:default=Hash
+	// +optional
+	Strategy NamespaceNamingStrategy `json:"strategy,omitempty"`
+
+	// prefix is the prefix of namespace names with the Readable strategy. It
+	// defaults to "kcp".
+	//
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=20
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// template is the Go template of namespace names with the Template strategy,
+	// e.g. "{{ .WorkspaceName }}-{{ .Namespace }}-{{ .Hash }}". The template can
+	// use .Workspace (the workspace path, e.g. root:org:ws), .WorkspaceName (the
+	// last segment of the path), .Namespace and .Hash (a short hash of the
+	// workspace path and namespace). Results that are no valid namespace names
+	// are not synced to.
+	//
+	// +optional
+	Template string `json:"template,omitempty"`
 }
 
 // WorkloadClusterStatus communicates the observed state of the WorkloadCluster (from the controller).
@@ -0,0 +1,160 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WorkspaceWorkloadUsage reports the resources requested, limited and used by the workloads
+// of a workspace on a workload cluster.
+//
+// The syncer of a WorkloadCluster reports the usage of every workspace with workloads on the
+// workload cluster in the workspace of the WorkloadCluster, labeled with WorkloadClusterLabel.
+// kcp copies these reports into the workspaces of the workloads, named like the WorkloadCluster,
+// such that tenants can see their consumption per location without access to the workload cluster.
+//
+// +crd
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories=kcp
+// +kubebuilder:printcolumn:name="Workload Cluster",type="string",JSONPath=`.status.workloadCluster.name`
+// +kubebuilder:printcolumn:name="Pods",type="integer",JSONPath=`.status.pods`
+// +kubebuilder:printcolumn:name="CPU Requests",type="string",JSONPath=`.status.requests.cpu`
+// +kubebuilder:printcolumn:name="Memory Requests",type="string",JSONPath=`.status.requests.memory`
+// +kubebuilder:printcolumn:name="CPU Usage",type="string",JSONPath=`.status.usage.cpu`
+// +kubebuilder:printcolumn:name="Memory Usage",type="string",JSONPath=`.status.usage.memory`
+// +kubebuilder:printcolumn:name="Updated",type="date",JSONPath=`.status.lastUpdateTime`
+type WorkspaceWorkloadUsage struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Status communicates the observed usage.
+	// +optional
+	Status WorkspaceWorkloadUsageStatus `json:"status,omitempty"`
+}
+
+// WorkspaceWorkloadUsageStatus communicates the usage of the workloads of a workspace on a workload cluster.
+type WorkspaceWorkloadUsageStatus struct {
+	// workloadCluster is the WorkloadCluster running the workloads.
+	//
+	// +optional
+	WorkloadCluster WorkloadClusterReference `json:"workloadCluster,omitempty"`
+
+	// workspace is the logical cluster of the workloads.
+	//
+	// +optional
+	Workspace string `json:"workspace,omitempty"`
+
+	// pods is the number of pods of the workloads which are not terminated.
+	//
+	// +optional
+	Pods int32 `json:"pods,omitempty"`
+
+	// requests is the sum of the resource requests of the pods.
+	//
+	// +optional
+	Requests corev1.ResourceList `json:"requests,omitempty"`
+
+	// limits is the sum of the resource limits of the pods. Pods without a limit for
+	// a resource are not included.
+	//
+	// +optional
+	Limits corev1.ResourceList `json:"limits,omitempty"`
+
+	// usage is the sum of the actual resource usage of the pods, as reported by metrics-server.
+	// It is not set if metrics-server is not available on the workload cluster.
+	//
+	// +optional
+	Usage corev1.ResourceList `json:"usage,omitempty"`
+
+	// namespaces holds the usage of each namespace with pods.
+	//
+	// +optional
+	Namespaces []NamespaceWorkloadUsage `json:"namespaces,omitempty"`
+
+	// lastUpdateTime is the time the usage was computed by the syncer.
+	//
+	// +optional
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// WorkloadClusterReference references a WorkloadCluster in a workspace.
+type WorkloadClusterReference struct {
+	// path is the logical cluster of the WorkloadCluster.
+	//
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// name is the name of the WorkloadCluster.
+	//
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
+// NamespaceWorkloadUsage is the usage of the workloads in a namespace of a workspace.
+type NamespaceWorkloadUsage struct {
+	// name is the name of the namespace in the workspace.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// pods is the number of pods in the namespace which are not terminated.
+	//
+	// +optional
+	Pods int32 `json:"pods,omitempty"`
+
+	// requests is the sum of the resource requests of the pods in the namespace.
+	//
+	// +optional
+	Requests corev1.ResourceList `json:"requests,omitempty"`
+
+	// limits is the sum of the resource limits of the pods in the namespace.
+	//
+	// +optional
+	Limits corev1.ResourceList `json:"limits,omitempty"`
+
+	// usage is the sum of the actual resource usage of the pods in the namespace.
+	//
+	// +optional
+	Usage corev1.ResourceList `json:"usage,omitempty"`
+}
+
+// WorkspaceWorkloadUsageList is a list of WorkspaceWorkloadUsage resources
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type WorkspaceWorkloadUsageList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []WorkspaceWorkloadUsage `json:"items"`
+}
+
+const (
+	// WorkloadClusterLabel is set on the WorkspaceWorkloadUsage reports of a syncer to the
+	// name of its WorkloadCluster.
+	WorkloadClusterLabel = "workload.kcp.dev/workload-cluster"
+
+	// WorkloadUsageReportAnnotation is set on copies of WorkspaceWorkloadUsage reports to the
+	// cluster aware key of the report they are copied from.
+	WorkloadUsageReportAnnotation = "workload.kcp.dev/usage-report"
+)
@@ -0,0 +1,112 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exchange
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gopkg.in/square/go-jose.v2/jwt"
+
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+// NewAuthenticator returns an authenticator of internal tokens issued by the given issuer for
+// one of the given audiences, signed by one of the given public keys. Tokens of other issuers
+// are not authenticated, such that other authenticators can be tried. Tokens scoped to a
+// logical cluster are rejected for requests to other logical clusters.
+func NewAuthenticator(issuer string, audiences []string, publicKeys []interface{}) authenticator.Token {
+	return &tokenAuthenticator{
+		issuer:     issuer,
+		audiences:  audiences,
+		publicKeys: publicKeys,
+		now:        time.Now,
+	}
+}
+
+type tokenAuthenticator struct {
+	issuer     string
+	audiences  []string
+	publicKeys []interface{}
+	now        func() time.Time
+}
+
+func (a *tokenAuthenticator) AuthenticateToken(ctx context.Context, tokenData string) (*authenticator.Response, bool, error) {
+	token, err := jwt.ParseSigned(tokenData)
+	if err != nil {
+		// not a JWT
+		return nil, false, nil
+	}
+
+	var public jwt.Claims
+	if err := token.UnsafeClaimsWithoutVerification(&public); err != nil {
+		return nil, false, nil
+	}
+	if public.Issuer != a.issuer {
+		return nil, false, nil
+	}
+
+	var private privateClaims
+	verified := false
+	for _, key := range a.publicKeys {
+		if err := token.Claims(key, &public, &private); err == nil {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, false, fmt.Errorf("exchanged token signature is invalid")
+	}
+
+	if err := public.Validate(jwt.Expected{Issuer: a.issuer, Time: a.now()}); err != nil {
+		return nil, false, fmt.Errorf("exchanged token is invalid: %w", err)
+	}
+	if !a.hasAudience(public.Audience) {
+		return nil, false, fmt.Errorf("exchanged token audiences %v do not include one of %v", []string(public.Audience), a.audiences)
+	}
+	if private.Cluster != "" {
+		if cluster := request.ClusterFrom(ctx); cluster == nil || cluster.Name.String() != private.Cluster {
+			return nil, false, fmt.Errorf("exchanged token is scoped to workspace %q", private.Cluster)
+		}
+	}
+
+	extra := map[string][]string{}
+	for k, v := range private.Extra {
+		extra[k] = v
+	}
+	extra[ExchangeIDExtraKey] = []string{public.ID}
+
+	return &authenticator.Response{
+		User: &user.DefaultInfo{
+			Name:   public.Subject,
+			Groups: private.Groups,
+			Extra:  extra,
+		},
+	}, true, nil
+}
+
+func (a *tokenAuthenticator) hasAudience(audiences jwt.Audience) bool {
+	for _, aud := range a.audiences {
+		if audiences.Contains(aud) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package exchange issues and validates the short-lived internal tokens the front-proxy
+// exchanges the bearer tokens of external identity providers for. Shards only have to
+// trust the front-proxy instead of every identity provider, and the internal tokens are
+// scoped to the workspace of the request they were issued for.
+package exchange
+
+import (
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+	"gopkg.in/square/go-jose.v2/jwt"
+
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/kubernetes/pkg/serviceaccount"
+)
+
+const (
+	// ExchangeIDExtraKey is the key of the user extra holding the ID of the token exchange
+	// a user was authenticated by. The front-proxy logs the same ID when issuing the token,
+	// such that audit events of shards can be correlated with the exchange.
+	ExchangeIDExtraKey = "authentication.kcp.dev/token-exchange-id"
+)
+
+// privateClaims are the kcp specific claims of internal tokens.
+type privateClaims struct {
+	Groups []string            `json:"kcp.dev/groups,omitempty"`
+	Extra  map[string][]string `json:"kcp.dev/extra,omitempty"`
+	// Cluster is the logical cluster the token is valid for. Tokens issued for requests
+	// outside of /clusters/ are not scoped.
+	Cluster string `json:"kcp.dev/cluster,omitempty"`
+}
+
+// Issuer issues internal tokens.
+type Issuer struct {
+	generator  serviceaccount.TokenGenerator
+	audiences  []string
+	expiration time.Duration
+	now        func() time.Time
+}
+
+// NewIssuer returns an issuer signing tokens for the given audiences with the given RSA or
+// ECDSA private key.
+func NewIssuer(issuer string, audiences []string, signingKey interface{}, expiration time.Duration) (*Issuer, error) {
+	generator, err := serviceaccount.JWTTokenGenerator(issuer, signingKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Issuer{
+		generator:  generator,
+		audiences:  audiences,
+		expiration: expiration,
+		now:        time.Now,
+	}, nil
+}
+
+// Expiration returns the lifetime of the issued tokens.
+func (i *Issuer) Expiration() time.Duration {
+	return i.expiration
+}
+
+// Issue returns a token for the given user, scoped to the given logical cluster unless it
+// is empty, and the ID of the exchange.
+func (i *Issuer) Issue(u user.Info, cluster logicalcluster.Name) (token, id string, err error) {
+	now := i.now()
+	id = string(uuid.NewUUID())
+	token, err = i.generator.GenerateToken(&jwt.Claims{
+		Subject:   u.GetName(),
+		Audience:  jwt.Audience(i.audiences),
+		ID:        id,
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+		Expiry:    jwt.NewNumericDate(now.Add(i.expiration)),
+	}, &privateClaims{
+		Groups:  u.GetGroups(),
+		Extra:   u.GetExtra(),
+		Cluster: cluster.String(),
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return token, id, nil
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exchange
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+func TestExchange(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	alice := &user.DefaultInfo{
+		Name:   "oidc:alice",
+		Groups: []string{"oidc:team-a"},
+		Extra:  map[string][]string{"foo": {"bar"}},
+	}
+
+	tests := map[string]struct {
+		issuer        string
+		audiences     []string
+		signingKey    *rsa.PrivateKey
+		issuedAt      time.Time
+		scope         logicalcluster.Name
+		requestTarget string
+
+		wantOK    bool
+		wantError bool
+	}{
+		"scoped token for the workspace of the request": {
+			scope:         logicalcluster.New("root:org:ws"),
+			requestTarget: "root:org:ws",
+			wantOK:        true,
+		},
+		"unscoped token": {
+			requestTarget: "root:org:other",
+			wantOK:        true,
+		},
+		"scoped token for another workspace": {
+			scope:         logicalcluster.New("root:org:ws"),
+			requestTarget: "root:org:other",
+			wantError:     true,
+		},
+		"scoped token for a workspace below": {
+			scope:         logicalcluster.New("root:org"),
+			requestTarget: "root:org:ws",
+			wantError:     true,
+		},
+		"scoped token without cluster in request": {
+			scope:     logicalcluster.New("root:org:ws"),
+			wantError: true,
+		},
+		"token of another issuer is left to other authenticators": {
+			issuer:        "https://idp.example.com",
+			requestTarget: "root:org:ws",
+		},
+		"token signed with another key": {
+			signingKey:    otherKey,
+			requestTarget: "root:org:ws",
+			wantError:     true,
+		},
+		"expired token": {
+			issuedAt:      time.Now().Add(-time.Hour),
+			requestTarget: "root:org:ws",
+			wantError:     true,
+		},
+		"token for another audience": {
+			audiences:     []string{"other"},
+			requestTarget: "root:org:ws",
+			wantError:     true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			issuerName := "https://front-proxy.kcp.example.com"
+			if tc.issuer != "" {
+				issuerName = tc.issuer
+			}
+			audiences := []string{"kcp"}
+			if tc.audiences != nil {
+				audiences = tc.audiences
+			}
+			signingKey := key
+			if tc.signingKey != nil {
+				signingKey = tc.signingKey
+			}
+
+			issuer, err := NewIssuer(issuerName, audiences, signingKey, 2*time.Minute)
+			require.NoError(t, err)
+			if !tc.issuedAt.IsZero() {
+				issuer.now = func() time.Time { return tc.issuedAt }
+			}
+			token, id, err := issuer.Issue(alice, tc.scope)
+			require.NoError(t, err)
+			require.NotEmpty(t, id)
+
+			ctx := context.Background()
+			if tc.requestTarget != "" {
+				ctx = request.WithCluster(ctx, request.Cluster{Name: logicalcluster.New(tc.requestTarget)})
+			}
+
+			auth := NewAuthenticator("https://front-proxy.kcp.example.com", []string{"kcp"}, []interface{}{&key.PublicKey})
+			resp, ok, err := auth.AuthenticateToken(ctx, token)
+			if tc.wantError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tc.wantOK, ok)
+			if !tc.wantOK {
+				return
+			}
+
+			require.Equal(t, "oidc:alice", resp.User.GetName())
+			require.Equal(t, []string{"oidc:team-a"}, resp.User.GetGroups())
+			require.Equal(t, map[string][]string{
+				"foo":              {"bar"},
+				ExchangeIDExtraKey: {id},
+			}, resp.User.GetExtra())
+		})
+	}
+}
+
+func TestAuthenticateNonJWT(t *testing.T) {
+	auth := NewAuthenticator("https://front-proxy.kcp.example.com", []string{"kcp"}, nil)
+	_, ok, err := auth.AuthenticateToken(context.Background(), "not-a-jwt")
+	require.NoError(t, err)
+	require.False(t, ok)
+}
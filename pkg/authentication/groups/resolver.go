@@ -0,0 +1,120 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package groups
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/klog/v2"
+)
+
+// Resolver resolves the groups of a user from an external provider like LDAP or SCIM,
+// such that group membership does not have to be embedded in tokens.
+type Resolver interface {
+	// Groups returns the groups of the given user. Unknown users have no groups.
+	Groups(ctx context.Context, userName string) ([]string, error)
+}
+
+// NewCachingResolver returns a resolver that caches the groups returned by the delegate
+// for the given time-to-live. The cache is per process, i.e. per shard. If the delegate
+// fails, stale groups are returned if the user has been resolved before.
+func NewCachingResolver(delegate Resolver, ttl time.Duration, maxSize int) Resolver {
+	return &cachingResolver{
+		delegate: delegate,
+		ttl:      ttl,
+		cache:    cache.NewLRUExpireCache(maxSize),
+		stale:    cache.NewLRUExpireCache(maxSize),
+	}
+}
+
+type cachingResolver struct {
+	delegate Resolver
+	ttl      time.Duration
+
+	cache *cache.LRUExpireCache
+	// stale keeps the last known groups for much longer than the ttl, to bridge
+	// outages of the provider.
+	stale *cache.LRUExpireCache
+}
+
+func (r *cachingResolver) Groups(ctx context.Context, userName string) ([]string, error) {
+	if groups, ok := r.cache.Get(userName); ok {
+		return groups.([]string), nil
+	}
+
+	groups, err := r.delegate.Groups(ctx, userName)
+	if err != nil {
+		if groups, ok := r.stale.Get(userName); ok {
+			klog.V(2).Infof("Failed to resolve groups of user %q, using stale groups: %v", userName, err)
+			return groups.([]string), nil
+		}
+		return nil, err
+	}
+
+	r.cache.Add(userName, groups, r.ttl)
+	r.stale.Add(userName, groups, 10*r.ttl)
+
+	return groups, nil
+}
+
+// WithResolvedGroups returns an authenticator that adds the groups resolved by the resolver
+// to the users authenticated by the delegate. Groups starting with "system:" are never added.
+// Service accounts and other system users are not resolved. If the resolver fails, the user is
+// authenticated without the resolved groups.
+func WithResolvedGroups(delegate authenticator.Request, resolver Resolver, groupPrefix string) authenticator.Request {
+	return authenticator.RequestFunc(func(req *http.Request) (*authenticator.Response, bool, error) {
+		resp, ok, err := delegate.AuthenticateRequest(req)
+		if err != nil || !ok || resp == nil || resp.User == nil {
+			return resp, ok, err
+		}
+		if strings.HasPrefix(resp.User.GetName(), "system:") {
+			return resp, ok, err
+		}
+
+		resolved, err := resolver.Groups(req.Context(), resp.User.GetName())
+		if err != nil {
+			klog.Errorf("Failed to resolve groups of user %q: %v", resp.User.GetName(), err)
+			return resp, ok, nil
+		}
+
+		groups := sets.NewString(resp.User.GetGroups()...)
+		userGroups := append([]string(nil), resp.User.GetGroups()...)
+		for _, g := range resolved {
+			g = groupPrefix + g
+			if strings.HasPrefix(g, "system:") || groups.Has(g) {
+				continue
+			}
+			groups.Insert(g)
+			userGroups = append(userGroups, g)
+		}
+
+		resp.User = &user.DefaultInfo{
+			Name:   resp.User.GetName(),
+			UID:    resp.User.GetUID(),
+			Groups: userGroups,
+			Extra:  resp.User.GetExtra(),
+		}
+		return resp, ok, nil
+	})
+}
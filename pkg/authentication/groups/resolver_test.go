@@ -0,0 +1,123 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package groups
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/user"
+)
+
+type fakeResolver struct {
+	groups map[string][]string
+	err    error
+	calls  int
+}
+
+func (r *fakeResolver) Groups(ctx context.Context, userName string) ([]string, error) {
+	r.calls++
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.groups[userName], nil
+}
+
+func TestCachingResolver(t *testing.T) {
+	delegate := &fakeResolver{groups: map[string][]string{"alice": {"team-a"}}}
+	r := NewCachingResolver(delegate, time.Minute, 10)
+
+	groups, err := r.Groups(context.Background(), "alice")
+	require.NoError(t, err)
+	require.Equal(t, []string{"team-a"}, groups)
+
+	groups, err = r.Groups(context.Background(), "alice")
+	require.NoError(t, err)
+	require.Equal(t, []string{"team-a"}, groups)
+	require.Equal(t, 1, delegate.calls, "expected the second lookup to be cached")
+
+	delegate.err = errors.New("provider down")
+	_, err = r.Groups(context.Background(), "bob")
+	require.Error(t, err)
+}
+
+func TestWithResolvedGroups(t *testing.T) {
+	delegate := authenticator.RequestFunc(func(req *http.Request) (*authenticator.Response, bool, error) {
+		name := req.Header.Get("X-User")
+		return &authenticator.Response{User: &user.DefaultInfo{Name: name, Groups: []string{"system:authenticated"}}}, true, nil
+	})
+	resolver := &fakeResolver{groups: map[string][]string{
+		"alice":                 {"team-a", "system:masters"},
+		"system:serviceaccount": {"team-b"},
+	}}
+	a := WithResolvedGroups(delegate, resolver, "scim:")
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-User", "alice")
+	resp, ok, err := a.AuthenticateRequest(req)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, []string{"system:authenticated", "scim:team-a", "scim:system:masters"}, resp.User.GetGroups())
+
+	a = WithResolvedGroups(delegate, resolver, "")
+	resp, _, err = a.AuthenticateRequest(req)
+	require.NoError(t, err)
+	require.Equal(t, []string{"system:authenticated", "team-a"}, resp.User.GetGroups(), "system groups must never be resolved")
+
+	req.Header.Set("X-User", "system:serviceaccount")
+	resp, _, err = a.AuthenticateRequest(req)
+	require.NoError(t, err)
+	require.Equal(t, []string{"system:authenticated"}, resp.User.GetGroups())
+
+	resolver.err = errors.New("provider down")
+	req.Header.Set("X-User", "alice")
+	resp, ok, err = a.AuthenticateRequest(req)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, []string{"system:authenticated"}, resp.User.GetGroups())
+}
+
+func TestSCIMResolver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		require.Equal(t, "/scim/v2/Users", req.URL.Path)
+		require.Equal(t, "Bearer secret", req.Header.Get("Authorization"))
+		if req.URL.Query().Get("filter") != `userName eq "alice"` {
+			w.Write([]byte(`{"totalResults":0,"Resources":[]}`)) // nolint:errcheck
+			return
+		}
+		w.Write([]byte(`{"totalResults":1,"Resources":[{"userName":"alice","groups":[{"value":"1","display":"team-a"},{"value":"2"}]}]}`)) // nolint:errcheck
+	}))
+	defer server.Close()
+
+	r, err := NewSCIMResolver(server.URL+"/scim/v2/", "secret", time.Second)
+	require.NoError(t, err)
+
+	groups, err := r.Groups(context.Background(), "alice")
+	require.NoError(t, err)
+	require.Equal(t, []string{"team-a", "2"}, groups)
+
+	groups, err = r.Groups(context.Background(), "bob")
+	require.NoError(t, err)
+	require.Empty(t, groups)
+}
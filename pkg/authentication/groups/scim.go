@@ -0,0 +1,117 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package groups
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// NewSCIMResolver returns a resolver looking up the groups of users in a SCIM 2.0 service provider
+// (RFC 7644) at the given base URL, e.g. https://idp.example.com/scim/v2. The user is looked up
+// by userName, and the display names of the groups of the user resource are returned.
+func NewSCIMResolver(baseURL, bearerToken string, timeout time.Duration) (Resolver, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("SCIM URL %q must be a http or https URL", baseURL)
+	}
+
+	return &scimResolver{
+		usersURL:    strings.TrimSuffix(baseURL, "/") + "/Users",
+		bearerToken: bearerToken,
+		client:      &http.Client{Timeout: timeout},
+	}, nil
+}
+
+type scimResolver struct {
+	usersURL    string
+	bearerToken string
+	client      *http.Client
+}
+
+type scimListResponse struct {
+	TotalResults int        `json:"totalResults"`
+	Resources    []scimUser `json:"Resources"`
+}
+
+type scimUser struct {
+	UserName string      `json:"userName"`
+	Groups   []scimGroup `json:"groups"`
+}
+
+type scimGroup struct {
+	Value   string `json:"value"`
+	Display string `json:"display"`
+}
+
+func (r *scimResolver) Groups(ctx context.Context, userName string) ([]string, error) {
+	query := url.Values{
+		"filter":     []string{fmt.Sprintf("userName eq %q", userName)},
+		"attributes": []string{"userName,groups"},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.usersURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/scim+json")
+	if r.bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+r.bearerToken)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("SCIM user lookup for %q failed with status %d: %s", userName, resp.StatusCode, string(body))
+	}
+
+	var list scimListResponse
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, fmt.Errorf("failed to decode SCIM user lookup for %q: %w", userName, err)
+	}
+
+	var groups []string
+	for _, u := range list.Resources {
+		if u.UserName != userName {
+			continue
+		}
+		for _, g := range u.Groups {
+			if g.Display != "" {
+				groups = append(groups, g.Display)
+			} else if g.Value != "" {
+				groups = append(groups, g.Value)
+			}
+		}
+	}
+	return groups, nil
+}
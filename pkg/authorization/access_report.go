@@ -0,0 +1,463 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authorization
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/authentication/serviceaccount"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	clientgoinformers "k8s.io/client-go/informers"
+	rbacinformers "k8s.io/client-go/informers/rbac/v1"
+	"k8s.io/kubernetes/pkg/genericcontrolplane"
+	"k8s.io/kubernetes/plugin/pkg/auth/authorizer/rbac"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/authorization/bootstrap"
+	tenancyinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/tenancy/v1alpha1"
+	rbacwrapper "github.com/kcp-dev/kcp/pkg/virtual/framework/wrappers/rbac"
+	tenancywrapper "github.com/kcp-dev/kcp/pkg/virtual/framework/wrappers/tenancy"
+)
+
+// AccessReportPath is where the AccessReporter serves the access report of
+// the workspace of the request.
+const AccessReportPath = "/access-report"
+
+// AccessReport lists who has which permissions in a workspace, following
+// the workspace content, deny policy, local policy and bootstrap policy
+// authorizers.
+type AccessReport struct {
+	Workspace string `json:"workspace"`
+
+	// WorkspaceAccess are the subjects granted access to the workspace, and
+	// the groups they get inside of it.
+	WorkspaceAccess []WorkspaceAccess `json:"workspaceAccess"`
+
+	// Subjects are the subjects bound to roles in the workspace or in the
+	// bootstrap policy, with the rules granted to them. Subjects only get
+	// these rules if they have access to the workspace.
+	Subjects []SubjectPermissions `json:"subjects"`
+}
+
+// WorkspaceAccess is a subject granted access to a workspace.
+type WorkspaceAccess struct {
+	Subject rbacv1.Subject `json:"subject"`
+	// Verbs are the verbs granted on clusterworkspaces/content in the parent
+	// workspace, i.e. admin and access.
+	Verbs []string `json:"verbs,omitempty"`
+	// Groups are the groups the subject gets inside of the workspace.
+	Groups []string `json:"groups"`
+	// Reason explains access not granted through verbs, e.g. for the root
+	// workspace or the service accounts of the workspace.
+	Reason string `json:"reason,omitempty"`
+}
+
+// SubjectPermissions are the permissions of a subject in a workspace.
+type SubjectPermissions struct {
+	Subject     rbacv1.Subject `json:"subject"`
+	Permissions []Permission   `json:"permissions"`
+}
+
+// Permission are the rules a binding grants.
+type Permission struct {
+	// Namespace is the namespace the rules apply to, empty for all of them.
+	Namespace string `json:"namespace,omitempty"`
+	// Binding is the binding granting the rules, e.g. "ClusterRoleBinding admins",
+	// prefixed with "bootstrap" for bindings of the bootstrap policy.
+	Binding string `json:"binding"`
+	// Role is the role referenced by the binding, e.g. "ClusterRole admin".
+	Role  string              `json:"role"`
+	Rules []rbacv1.PolicyRule `json:"rules"`
+	// Error is set if the rules cannot be resolved, e.g. because the role
+	// does not exist.
+	Error string `json:"error,omitempty"`
+	// MayBeDeniedBy are the DenyPolicies of the workspace and its ancestors,
+	// as <workspace>/<name>, which deny some of the rules to the subject, or
+	// to the subject unless it is in one of their exempt groups.
+	MayBeDeniedBy []string `json:"mayBeDeniedBy,omitempty"`
+}
+
+// AccessReporter computes AccessReports from the RBAC and DenyPolicy
+// informers of the authorizers.
+type AccessReporter struct {
+	informers        rbacinformers.Interface
+	listDenyPolicies func(clusterName logicalcluster.Name) ([]*tenancyv1alpha1.DenyPolicy, error)
+}
+
+// NewAccessReporter returns an AccessReporter using the RBAC informers of
+// versionedInformers and the DenyPolicy informer.
+func NewAccessReporter(versionedInformers clientgoinformers.SharedInformerFactory, denyPolicyInformer tenancyinformers.DenyPolicyInformer) *AccessReporter {
+	return &AccessReporter{
+		informers: versionedInformers.Rbac().V1(),
+		listDenyPolicies: func(clusterName logicalcluster.Name) ([]*tenancyv1alpha1.DenyPolicy, error) {
+			return tenancywrapper.FilterDenyPolicyInformer(clusterName, denyPolicyInformer).Lister().List(labels.Everything())
+		},
+	}
+}
+
+// Report returns the access report of the given workspace.
+func (r *AccessReporter) Report(workspace logicalcluster.Name) (*AccessReport, error) {
+	workspaceAccess, err := r.workspaceAccess(workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	// like the local authorizer, roles are looked up in the workspace and the bootstrap policy
+	local := rbacwrapper.FilterInformers(workspace, r.informers)
+	bootstrapPolicy := rbacwrapper.FilterInformers(genericcontrolplane.LocalAdminCluster, r.informers)
+	subjects := map[rbacv1.Subject][]Permission{}
+	if err := addPermissions(subjects, "",
+		local,
+		rbacwrapper.MergedRoleInformer(local.Roles(), bootstrapPolicy.Roles()),
+		rbacwrapper.MergedClusterRoleInformer(local.ClusterRoles(), bootstrapPolicy.ClusterRoles()),
+	); err != nil {
+		return nil, err
+	}
+	if workspace != genericcontrolplane.LocalAdminCluster {
+		if err := addPermissions(subjects, "bootstrap ", bootstrapPolicy, bootstrapPolicy.Roles(), bootstrapPolicy.ClusterRoles()); err != nil {
+			return nil, err
+		}
+	}
+
+	// like the deny policy authorizer, policies of the workspace and its ancestors apply
+	var denyPolicies []workspaceDenyPolicy
+	for clusterName, ok := workspace, true; ok; clusterName, ok = clusterName.Parent() {
+		policies, err := r.listDenyPolicies(clusterName)
+		if err != nil {
+			return nil, err
+		}
+		for _, policy := range policies {
+			denyPolicies = append(denyPolicies, workspaceDenyPolicy{workspace: clusterName, policy: policy})
+		}
+	}
+
+	report := &AccessReport{
+		Workspace:       workspace.String(),
+		WorkspaceAccess: workspaceAccess,
+		Subjects:        make([]SubjectPermissions, 0, len(subjects)),
+	}
+	for subject, permissions := range subjects {
+		for i := range permissions {
+			permissions[i].MayBeDeniedBy = mayBeDeniedBy(subject, permissions[i].Rules, denyPolicies)
+		}
+		report.Subjects = append(report.Subjects, SubjectPermissions{Subject: subject, Permissions: permissions})
+	}
+	sort.Slice(report.Subjects, func(i, j int) bool {
+		return subjectLess(report.Subjects[i].Subject, report.Subjects[j].Subject)
+	})
+	return report, nil
+}
+
+// workspaceAccess follows the workspace content authorizer.
+func (r *AccessReporter) workspaceAccess(workspace logicalcluster.Name) ([]WorkspaceAccess, error) {
+	if workspace == tenancyv1alpha1.RootCluster {
+		return []WorkspaceAccess{{
+			Subject: rbacv1.Subject{Kind: rbacv1.GroupKind, APIGroup: rbacv1.GroupName, Name: user.AllAuthenticated},
+			Groups:  []string{bootstrap.SystemKcpClusterWorkspaceAccessGroup},
+			Reason:  "everybody authenticated has access to the root workspace",
+		}}, nil
+	}
+	parent, hasParent := workspace.Parent()
+	if !hasParent {
+		return nil, nil
+	}
+
+	parentInformers := rbacwrapper.FilterInformers(parent, r.informers)
+	parentEvaluator := rbac.NewSubjectAccessEvaluator(
+		&rbac.RoleGetter{Lister: parentInformers.Roles().Lister()},
+		&rbac.RoleBindingLister{Lister: parentInformers.RoleBindings().Lister()},
+		&rbac.ClusterRoleGetter{Lister: parentInformers.ClusterRoles().Lister()},
+		&rbac.ClusterRoleBindingLister{Lister: parentInformers.ClusterRoleBindings().Lister()},
+		"",
+	)
+
+	access := map[rbacv1.Subject]*WorkspaceAccess{}
+	var subjects []rbacv1.Subject
+	for _, verb := range []string{"admin", "access"} {
+		allowed, err := parentEvaluator.AllowedSubjects(authorizer.AttributesRecord{
+			Verb:            verb,
+			APIGroup:        tenancyv1alpha1.SchemeGroupVersion.Group,
+			APIVersion:      tenancyv1alpha1.SchemeGroupVersion.Version,
+			Resource:        "clusterworkspaces",
+			Subresource:     "content",
+			Name:            workspace.Base(),
+			ResourceRequest: true,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, subject := range allowed {
+			if subject.Kind == rbacv1.GroupKind && subject.Name == user.SystemPrivilegedGroup {
+				// always allowed by the subject locator, but granted by the privileged group authorizer, not by workspace content
+				continue
+			}
+			a, ok := access[subject]
+			if !ok {
+				a = &WorkspaceAccess{Subject: subject}
+				access[subject] = a
+				subjects = append(subjects, subject)
+			}
+			a.Verbs = append(a.Verbs, verb)
+			if verb == "admin" {
+				a.Groups = append(a.Groups, bootstrap.SystemKcpClusterWorkspaceAccessGroup, bootstrap.SystemKcpClusterWorkspaceAdminGroup)
+			} else if len(a.Groups) == 0 {
+				a.Groups = append(a.Groups, bootstrap.SystemKcpClusterWorkspaceAccessGroup)
+			}
+		}
+	}
+	sort.Slice(subjects, func(i, j int) bool { return subjectLess(subjects[i], subjects[j]) })
+
+	result := []WorkspaceAccess{{
+		Subject: rbacv1.Subject{Kind: rbacv1.GroupKind, APIGroup: rbacv1.GroupName, Name: serviceaccount.AllServiceAccountsGroup},
+		Groups:  []string{bootstrap.SystemKcpClusterWorkspaceAccessGroup},
+		Reason:  "service accounts of the workspace have access to it",
+	}}
+	for _, subject := range subjects {
+		result = append(result, *access[subject])
+	}
+	return result, nil
+}
+
+func addPermissions(subjects map[rbacv1.Subject][]Permission, bindingPrefix string, bindings rbacinformers.Interface, roles rbacinformers.RoleInformer, clusterRoles rbacinformers.ClusterRoleInformer) error {
+	clusterRoleBindings, err := bindings.ClusterRoleBindings().Lister().List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	for _, binding := range clusterRoleBindings {
+		permission := Permission{
+			Binding: bindingPrefix + "ClusterRoleBinding " + binding.Name,
+			Role:    binding.RoleRef.Kind + " " + binding.RoleRef.Name,
+		}
+		if role, err := clusterRoles.Lister().Get(binding.RoleRef.Name); err != nil {
+			permission.Error = err.Error()
+		} else {
+			permission.Rules = role.Rules
+		}
+		for _, subject := range binding.Subjects {
+			subjects[subject] = append(subjects[subject], permission)
+		}
+	}
+
+	roleBindings, err := bindings.RoleBindings().Lister().List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	for _, binding := range roleBindings {
+		permission := Permission{
+			Namespace: binding.Namespace,
+			Binding:   bindingPrefix + "RoleBinding " + binding.Namespace + "/" + binding.Name,
+			Role:      binding.RoleRef.Kind + " " + binding.RoleRef.Name,
+		}
+		switch binding.RoleRef.Kind {
+		case "ClusterRole":
+			if role, err := clusterRoles.Lister().Get(binding.RoleRef.Name); err != nil {
+				permission.Error = err.Error()
+			} else {
+				permission.Rules = role.Rules
+			}
+		default:
+			if role, err := roles.Lister().Roles(binding.Namespace).Get(binding.RoleRef.Name); err != nil {
+				permission.Error = err.Error()
+			} else {
+				permission.Rules = role.Rules
+			}
+		}
+		for _, subject := range binding.Subjects {
+			if subject.Kind == rbacv1.ServiceAccountKind && subject.Namespace == "" {
+				// like RBAC, service accounts default to the namespace of the binding
+				subject.Namespace = binding.Namespace
+			}
+			subjects[subject] = append(subjects[subject], permission)
+		}
+	}
+	return nil
+}
+
+type workspaceDenyPolicy struct {
+	workspace logicalcluster.Name
+	policy    *tenancyv1alpha1.DenyPolicy
+}
+
+// mayBeDeniedBy returns the deny policies with a rule overlapping with one of the
+// rules, which the subject is not exempt from. The groups of users and service
+// accounts are not known, hence they are exempt only by name.
+func mayBeDeniedBy(subject rbacv1.Subject, rules []rbacv1.PolicyRule, policies []workspaceDenyPolicy) []string {
+	var names []string
+	for _, p := range policies {
+		switch subject.Kind {
+		case rbacv1.UserKind:
+			if sets.NewString(p.policy.Spec.ExemptUsers...).Has(subject.Name) {
+				continue
+			}
+		case rbacv1.ServiceAccountKind:
+			if sets.NewString(p.policy.Spec.ExemptUsers...).Has(serviceaccount.MakeUsername(subject.Namespace, subject.Name)) {
+				continue
+			}
+		case rbacv1.GroupKind:
+			if sets.NewString(p.policy.Spec.ExemptGroups...).Has(subject.Name) {
+				continue
+			}
+		}
+		if anyRulesOverlap(rules, p.policy.Spec.Rules) {
+			names = append(names, p.workspace.String()+"/"+p.policy.Name)
+		}
+	}
+	return names
+}
+
+// anyRulesOverlap returns true if some request is matched by one of the granted and by
+// one of the denied rules.
+func anyRulesOverlap(granted, denied []rbacv1.PolicyRule) bool {
+	for _, g := range granted {
+		for _, d := range denied {
+			if rulesOverlap(g, d) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func rulesOverlap(a, b rbacv1.PolicyRule) bool {
+	if !valuesOverlap(a.Verbs, b.Verbs, rbacv1.VerbAll) {
+		return false
+	}
+	if len(a.NonResourceURLs) > 0 && len(b.NonResourceURLs) > 0 && anyPatternsOverlap(a.NonResourceURLs, b.NonResourceURLs) {
+		return true
+	}
+	if len(a.Resources) == 0 || len(b.Resources) == 0 {
+		return false
+	}
+	if !valuesOverlap(a.APIGroups, b.APIGroups, rbacv1.APIGroupAll) || !anyPatternsOverlap(a.Resources, b.Resources) {
+		return false
+	}
+	return len(a.ResourceNames) == 0 || len(b.ResourceNames) == 0 || valuesOverlap(a.ResourceNames, b.ResourceNames, "")
+}
+
+// valuesOverlap returns true if a and b have a value in common, or one of them has
+// the value all, unless it is empty.
+func valuesOverlap(a, b []string, all string) bool {
+	as, bs := sets.NewString(a...), sets.NewString(b...)
+	if all != "" && (as.Has(all) || bs.Has(all)) {
+		return true
+	}
+	return as.HasAny(b...)
+}
+
+// anyPatternsOverlap returns true if a resource or non-resource URL is matched by a
+// pattern of a and of b. Patterns are "*", and resources like "*/status" and "pods/*",
+// or URLs like "/apis/*".
+func anyPatternsOverlap(a, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if patternMatches(x, y) || patternMatches(y, x) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func patternMatches(pattern, value string) bool {
+	switch {
+	case pattern == "*" || pattern == value:
+		return true
+	case strings.HasPrefix(pattern, "*/"):
+		return strings.HasSuffix(value, pattern[1:])
+	case strings.HasSuffix(pattern, "*"):
+		return strings.HasPrefix(value, strings.TrimSuffix(pattern, "*"))
+	}
+	return false
+}
+
+func subjectLess(a, b rbacv1.Subject) bool {
+	if a.Kind != b.Kind {
+		return a.Kind < b.Kind
+	}
+	if a.Namespace != b.Namespace {
+		return a.Namespace < b.Namespace
+	}
+	return a.Name < b.Name
+}
+
+// ServeHTTP serves the AccessReport of the workspace of the request as JSON,
+// or with format=csv as one CSV row per subject and rule.
+func (r *AccessReporter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	cluster := genericapirequest.ClusterFrom(req.Context())
+	if cluster == nil || cluster.Name.Empty() || cluster.Wildcard {
+		http.Error(w, "the access report is only available for a single workspace", http.StatusBadRequest)
+		return
+	}
+	report, err := r.Report(cluster.Name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	switch format := req.URL.Query().Get("format"); format {
+	case "", "json":
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(report)
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv")
+		_ = writeAccessReportCSV(w, report)
+	default:
+		http.Error(w, fmt.Sprintf("unsupported format %q, must be json or csv", format), http.StatusBadRequest)
+	}
+}
+
+func writeAccessReportCSV(w http.ResponseWriter, report *AccessReport) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"workspace", "subjectKind", "subjectNamespace", "subjectName", "namespace", "binding", "role", "verbs", "apiGroups", "resources", "resourceNames", "nonResourceURLs", "error", "mayBeDeniedBy"})
+	for _, s := range report.Subjects {
+		for _, p := range s.Permissions {
+			prefix := []string{report.Workspace, s.Subject.Kind, s.Subject.Namespace, s.Subject.Name, p.Namespace, p.Binding, p.Role}
+			deniedBy := strings.Join(p.MayBeDeniedBy, " ")
+			if len(p.Rules) == 0 {
+				_ = cw.Write(append(prefix, "", "", "", "", "", p.Error, deniedBy))
+				continue
+			}
+			for _, rule := range p.Rules {
+				_ = cw.Write(append(prefix,
+					strings.Join(rule.Verbs, " "),
+					strings.Join(rule.APIGroups, " "),
+					strings.Join(rule.Resources, " "),
+					strings.Join(rule.ResourceNames, " "),
+					strings.Join(rule.NonResourceURLs, " "),
+					p.Error,
+					deniedBy,
+				))
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
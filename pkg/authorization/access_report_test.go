@@ -0,0 +1,218 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authorization
+
+import (
+	"context"
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	clientgoinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kubernetes/pkg/genericcontrolplane"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/authorization/bootstrap"
+)
+
+func newTestAccessReporter(informers clientgoinformers.SharedInformerFactory, denyPolicies ...*tenancyv1alpha1.DenyPolicy) *AccessReporter {
+	return &AccessReporter{
+		informers: informers.Rbac().V1(),
+		listDenyPolicies: func(clusterName logicalcluster.Name) ([]*tenancyv1alpha1.DenyPolicy, error) {
+			var policies []*tenancyv1alpha1.DenyPolicy
+			for _, p := range denyPolicies {
+				if p.ClusterName == clusterName.String() {
+					policies = append(policies, p)
+				}
+			}
+			return policies, nil
+		},
+	}
+}
+
+func TestAccessReport(t *testing.T) {
+	informers := clientgoinformers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0)
+	rbacInformers := informers.Rbac().V1()
+	add := func(obj interface{}) {
+		var err error
+		switch obj := obj.(type) {
+		case *rbacv1.ClusterRole:
+			err = rbacInformers.ClusterRoles().Informer().GetIndexer().Add(obj)
+		case *rbacv1.ClusterRoleBinding:
+			err = rbacInformers.ClusterRoleBindings().Informer().GetIndexer().Add(obj)
+		case *rbacv1.Role:
+			err = rbacInformers.Roles().Informer().GetIndexer().Add(obj)
+		case *rbacv1.RoleBinding:
+			err = rbacInformers.RoleBindings().Informer().GetIndexer().Add(obj)
+		}
+		require.NoError(t, err)
+	}
+	meta := func(cluster logicalcluster.Name, namespace, name string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{ClusterName: cluster.String(), Namespace: namespace, Name: name}
+	}
+	user := func(name string) rbacv1.Subject {
+		return rbacv1.Subject{Kind: rbacv1.UserKind, APIGroup: rbacv1.GroupName, Name: name}
+	}
+	group := func(name string) rbacv1.Subject {
+		return rbacv1.Subject{Kind: rbacv1.GroupKind, APIGroup: rbacv1.GroupName, Name: name}
+	}
+
+	parent, workspace := logicalcluster.New("root:org"), logicalcluster.New("root:org:ws")
+	adminCluster := genericcontrolplane.LocalAdminCluster
+	readConfigMaps := []rbacv1.PolicyRule{{Verbs: []string{"get", "list"}, APIGroups: []string{""}, Resources: []string{"configmaps"}}}
+
+	// bootstrap policy
+	add(&rbacv1.ClusterRole{ObjectMeta: meta(adminCluster, "", "cluster-admin"), Rules: []rbacv1.PolicyRule{{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}}}})
+	add(&rbacv1.ClusterRoleBinding{
+		ObjectMeta: meta(adminCluster, "", "system:kcp:clusterworkspace:admin"),
+		Subjects:   []rbacv1.Subject{group(bootstrap.SystemKcpClusterWorkspaceAdminGroup)},
+		RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "cluster-admin"},
+	})
+
+	// the parent grants adam admin and eve access to ws
+	add(&rbacv1.ClusterRole{ObjectMeta: meta(parent, "", "ws-admin"), Rules: []rbacv1.PolicyRule{{Verbs: []string{"admin"}, APIGroups: []string{"tenancy.kcp.dev"}, Resources: []string{"clusterworkspaces/content"}, ResourceNames: []string{"ws"}}}})
+	add(&rbacv1.ClusterRole{ObjectMeta: meta(parent, "", "ws-access"), Rules: []rbacv1.PolicyRule{{Verbs: []string{"access"}, APIGroups: []string{"tenancy.kcp.dev"}, Resources: []string{"clusterworkspaces/content"}, ResourceNames: []string{"ws"}}}})
+	add(&rbacv1.ClusterRoleBinding{ObjectMeta: meta(parent, "", "adam"), Subjects: []rbacv1.Subject{user("adam")}, RoleRef: rbacv1.RoleRef{Kind: "ClusterRole", Name: "ws-admin"}})
+	add(&rbacv1.ClusterRoleBinding{ObjectMeta: meta(parent, "", "eve"), Subjects: []rbacv1.Subject{user("eve")}, RoleRef: rbacv1.RoleRef{Kind: "ClusterRole", Name: "ws-access"}})
+
+	// the workspace binds eve to a local role, a bootstrap cluster role and a missing role
+	add(&rbacv1.Role{ObjectMeta: meta(workspace, "default", "read-configmaps"), Rules: readConfigMaps})
+	add(&rbacv1.RoleBinding{ObjectMeta: meta(workspace, "default", "eve"), Subjects: []rbacv1.Subject{user("eve")}, RoleRef: rbacv1.RoleRef{Kind: "Role", Name: "read-configmaps"}})
+	add(&rbacv1.ClusterRoleBinding{ObjectMeta: meta(workspace, "", "eve-admin"), Subjects: []rbacv1.Subject{user("eve")}, RoleRef: rbacv1.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"}})
+	add(&rbacv1.ClusterRoleBinding{ObjectMeta: meta(workspace, "", "eve-missing"), Subjects: []rbacv1.Subject{user("eve")}, RoleRef: rbacv1.RoleRef{Kind: "ClusterRole", Name: "missing"}})
+	add(&rbacv1.RoleBinding{ObjectMeta: meta(workspace, "default", "default-sa"), Subjects: []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: "default"}}, RoleRef: rbacv1.RoleRef{Kind: "Role", Name: "read-configmaps"}})
+
+	// bindings in other workspaces do not show up
+	add(&rbacv1.ClusterRoleBinding{ObjectMeta: meta(logicalcluster.New("root:org:other"), "", "mallory"), Subjects: []rbacv1.Subject{user("mallory")}, RoleRef: rbacv1.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"}})
+
+	// the parent denies reading secrets to everybody but workspace admins, another workspace denies everything
+	denyPolicies := []*tenancyv1alpha1.DenyPolicy{
+		{
+			ObjectMeta: meta(parent, "", "no-secrets"),
+			Spec: tenancyv1alpha1.DenyPolicySpec{
+				Rules:        []rbacv1.PolicyRule{{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"secrets"}}},
+				ExemptGroups: []string{bootstrap.SystemKcpClusterWorkspaceAdminGroup},
+			},
+		},
+		{
+			ObjectMeta: meta(logicalcluster.New("root:org:other"), "", "nothing"),
+			Spec:       tenancyv1alpha1.DenyPolicySpec{Rules: []rbacv1.PolicyRule{{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}}}},
+		},
+	}
+
+	report, err := newTestAccessReporter(informers, denyPolicies...).Report(workspace)
+	require.NoError(t, err)
+
+	require.Equal(t, "root:org:ws", report.Workspace)
+	require.Equal(t, []WorkspaceAccess{
+		{Subject: group("system:serviceaccounts"), Groups: []string{bootstrap.SystemKcpClusterWorkspaceAccessGroup}, Reason: "service accounts of the workspace have access to it"},
+		{Subject: user("adam"), Verbs: []string{"admin"}, Groups: []string{bootstrap.SystemKcpClusterWorkspaceAccessGroup, bootstrap.SystemKcpClusterWorkspaceAdminGroup}},
+		{Subject: user("eve"), Verbs: []string{"access"}, Groups: []string{bootstrap.SystemKcpClusterWorkspaceAccessGroup}},
+	}, report.WorkspaceAccess)
+
+	subjects := map[string][]Permission{}
+	for _, s := range report.Subjects {
+		subjects[s.Subject.Kind+" "+s.Subject.Namespace+"/"+s.Subject.Name] = s.Permissions
+	}
+	require.Len(t, subjects, 3, "unexpected subjects: %v", subjects)
+	require.Len(t, subjects["Group /"+bootstrap.SystemKcpClusterWorkspaceAdminGroup], 1)
+	require.Equal(t, "bootstrap ClusterRoleBinding system:kcp:clusterworkspace:admin", subjects["Group /"+bootstrap.SystemKcpClusterWorkspaceAdminGroup][0].Binding)
+	require.Empty(t, subjects["Group /"+bootstrap.SystemKcpClusterWorkspaceAdminGroup][0].MayBeDeniedBy, "exempt groups should not be denied")
+	require.Equal(t, []Permission{{Namespace: "default", Binding: "RoleBinding default/default-sa", Role: "Role read-configmaps", Rules: readConfigMaps}}, subjects["ServiceAccount default/default"])
+
+	eve := subjects["User /eve"]
+	require.Len(t, eve, 3)
+	byBinding := map[string]Permission{}
+	for _, p := range eve {
+		byBinding[p.Binding] = p
+	}
+	require.Equal(t, "*", byBinding["ClusterRoleBinding eve-admin"].Rules[0].Verbs[0], "cluster roles of the bootstrap policy can be bound in the workspace")
+	require.Equal(t, []string{"root:org/no-secrets"}, byBinding["ClusterRoleBinding eve-admin"].MayBeDeniedBy)
+	require.NotEmpty(t, byBinding["ClusterRoleBinding eve-missing"].Error)
+	require.Equal(t, readConfigMaps, byBinding["RoleBinding default/eve"].Rules)
+
+	rootReport, err := newTestAccessReporter(informers).Report(logicalcluster.New("root"))
+	require.NoError(t, err)
+	require.Len(t, rootReport.WorkspaceAccess, 1)
+	require.Equal(t, group("system:authenticated"), rootReport.WorkspaceAccess[0].Subject)
+}
+
+func TestRulesOverlap(t *testing.T) {
+	tests := map[string]struct {
+		a, b rbacv1.PolicyRule
+		want bool
+	}{
+		"same resource":          {a: rbacv1.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"secrets"}}, b: rbacv1.PolicyRule{Verbs: []string{"get", "list"}, APIGroups: []string{""}, Resources: []string{"secrets"}}, want: true},
+		"other verb":             {a: rbacv1.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"secrets"}}, b: rbacv1.PolicyRule{Verbs: []string{"delete"}, APIGroups: []string{""}, Resources: []string{"secrets"}}},
+		"other group":            {a: rbacv1.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"secrets"}}, b: rbacv1.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{"apps"}, Resources: []string{"secrets"}}},
+		"wildcards":              {a: rbacv1.PolicyRule{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}}, b: rbacv1.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"secrets"}}, want: true},
+		"subresource wildcard":   {a: rbacv1.PolicyRule{Verbs: []string{"create"}, APIGroups: []string{""}, Resources: []string{"pods/*"}}, b: rbacv1.PolicyRule{Verbs: []string{"create"}, APIGroups: []string{""}, Resources: []string{"pods/exec"}}, want: true},
+		"resource without sub":   {a: rbacv1.PolicyRule{Verbs: []string{"create"}, APIGroups: []string{""}, Resources: []string{"pods"}}, b: rbacv1.PolicyRule{Verbs: []string{"create"}, APIGroups: []string{""}, Resources: []string{"pods/exec"}}},
+		"other resource names":   {a: rbacv1.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"secrets"}, ResourceNames: []string{"a"}}, b: rbacv1.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"secrets"}, ResourceNames: []string{"b"}}},
+		"non-resource URLs":      {a: rbacv1.PolicyRule{Verbs: []string{"get"}, NonResourceURLs: []string{"/metrics"}}, b: rbacv1.PolicyRule{Verbs: []string{"get"}, NonResourceURLs: []string{"*"}}, want: true},
+		"resource and URL rules": {a: rbacv1.PolicyRule{Verbs: []string{"get"}, NonResourceURLs: []string{"*"}}, b: rbacv1.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{"*"}, Resources: []string{"*"}}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tt.want, rulesOverlap(tt.a, tt.b))
+			require.Equal(t, tt.want, rulesOverlap(tt.b, tt.a))
+		})
+	}
+}
+
+func TestAccessReportServeHTTP(t *testing.T) {
+	informers := clientgoinformers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0)
+	require.NoError(t, informers.Rbac().V1().ClusterRoles().Informer().GetIndexer().Add(&rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{ClusterName: "root:org", Name: "view"},
+		Rules:      []rbacv1.PolicyRule{{Verbs: []string{"get", "list"}, APIGroups: []string{""}, Resources: []string{"pods", "configmaps"}}},
+	}))
+	require.NoError(t, informers.Rbac().V1().ClusterRoleBindings().Informer().GetIndexer().Add(&rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{ClusterName: "root:org", Name: "viewers"},
+		Subjects:   []rbacv1.Subject{{Kind: rbacv1.GroupKind, APIGroup: rbacv1.GroupName, Name: "viewers"}},
+		RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "view"},
+	}))
+	reporter := newTestAccessReporter(informers)
+
+	serve := func(ctx context.Context, url string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		reporter.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil).WithContext(ctx))
+		return rec
+	}
+	orgCtx := genericapirequest.WithCluster(context.Background(), genericapirequest.Cluster{Name: logicalcluster.New("root:org")})
+
+	rec := serve(orgCtx, AccessReportPath+"?format=csv")
+	require.Equal(t, http.StatusOK, rec.Code)
+	rows, err := csv.NewReader(rec.Body).ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, [][]string{
+		{"workspace", "subjectKind", "subjectNamespace", "subjectName", "namespace", "binding", "role", "verbs", "apiGroups", "resources", "resourceNames", "nonResourceURLs", "error", "mayBeDeniedBy"},
+		{"root:org", "Group", "", "viewers", "", "ClusterRoleBinding viewers", "ClusterRole view", "get list", "", "pods configmaps", "", "", "", ""},
+	}, rows)
+
+	require.Equal(t, http.StatusOK, serve(orgCtx, AccessReportPath).Code)
+	require.Equal(t, http.StatusBadRequest, serve(orgCtx, AccessReportPath+"?format=xml").Code)
+	wildcardCtx := genericapirequest.WithCluster(context.Background(), genericapirequest.Cluster{Name: logicalcluster.Wildcard, Wildcard: true})
+	require.Equal(t, http.StatusBadRequest, serve(wildcardCtx, AccessReportPath).Code)
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package delegated
+
+import (
+	"encoding/json"
+	"errors"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apiserver/pkg/authentication/user"
+)
+
+// EncodeUser returns the user as JSON. Admission plugins record the creator of objects this
+// way in an annotation, for controllers which act on behalf of the creator to authorize them
+// again later.
+func EncodeUser(info user.Info) (string, error) {
+	u := authenticationv1.UserInfo{
+		Username: info.GetName(),
+		UID:      info.GetUID(),
+		Groups:   info.GetGroups(),
+	}
+	if extra := info.GetExtra(); len(extra) > 0 {
+		u.Extra = make(map[string]authenticationv1.ExtraValue, len(extra))
+		for k, v := range extra {
+			u.Extra[k] = v
+		}
+	}
+	bs, err := json.Marshal(u)
+	if err != nil {
+		return "", err
+	}
+	return string(bs), nil
+}
+
+// DecodeUser returns the user encoded by EncodeUser.
+func DecodeUser(s string) (user.Info, error) {
+	var u authenticationv1.UserInfo
+	if err := json.Unmarshal([]byte(s), &u); err != nil {
+		return nil, err
+	}
+	if u.Username == "" {
+		return nil, errors.New("user name is empty")
+	}
+	info := &user.DefaultInfo{
+		Name:   u.Username,
+		UID:    u.UID,
+		Groups: u.Groups,
+	}
+	if len(u.Extra) > 0 {
+		info.Extra = make(map[string][]string, len(u.Extra))
+		for k, v := range u.Extra {
+			info.Extra[k] = v
+		}
+	}
+	return info, nil
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authorization
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/tools/clusters"
+	"k8s.io/kubernetes/plugin/pkg/auth/authorizer/rbac"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	tenancyinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/tenancy/v1alpha1"
+	tenancyv1 "github.com/kcp-dev/kcp/pkg/client/listers/tenancy/v1alpha1"
+	tenancywrapper "github.com/kcp-dev/kcp/pkg/virtual/framework/wrappers/tenancy"
+)
+
+// NewDenyPolicyAuthorizer returns an authorizer that denies requests matching a DenyPolicy in the
+// request workspace or in any of its ancestors. It never allows a request, but returns NoOpinion
+// for everything not denied, such that it can be put in front of the RBAC authorizers in a union.
+// Requests are denied if the policies cannot be evaluated, as the union would continue with the
+// RBAC authorizers otherwise.
+func NewDenyPolicyAuthorizer(denyPolicyInformer tenancyinformers.DenyPolicyInformer, clusterWorkspaceLister tenancyv1.ClusterWorkspaceLister) authorizer.Authorizer {
+	return &denyPolicyAuthorizer{
+		listDenyPolicies: func(clusterName logicalcluster.Name) ([]*tenancyv1alpha1.DenyPolicy, error) {
+			return tenancywrapper.FilterDenyPolicyInformer(clusterName, denyPolicyInformer).Lister().List(labels.Everything())
+		},
+		getClusterWorkspace: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error) {
+			return clusterWorkspaceLister.Get(clusters.ToClusterAwareKey(clusterName, name))
+		},
+	}
+}
+
+type denyPolicyAuthorizer struct {
+	listDenyPolicies    func(clusterName logicalcluster.Name) ([]*tenancyv1alpha1.DenyPolicy, error)
+	getClusterWorkspace func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error)
+}
+
+func (a *denyPolicyAuthorizer) Authorize(ctx context.Context, attr authorizer.Attributes) (authorized authorizer.Decision, reason string, err error) {
+	cluster, err := genericapirequest.ValidClusterFrom(ctx)
+	if err != nil {
+		return authorizer.DecisionNoOpinion, "", err
+	}
+	if cluster == nil || cluster.Name.Empty() {
+		return authorizer.DecisionNoOpinion, "", nil
+	}
+
+	for clusterName, ok := cluster.Name, true; ok; clusterName, ok = clusterName.Parent() {
+		policies, err := a.listDenyPolicies(clusterName)
+		if err != nil {
+			return authorizer.DecisionDeny, fmt.Sprintf("unable to list DenyPolicies of workspace %q", clusterName), err
+		}
+		for _, policy := range policies {
+			matches, err := a.matches(cluster.Name, policy, attr)
+			if err != nil {
+				return authorizer.DecisionDeny, fmt.Sprintf("unable to evaluate DenyPolicy %q in workspace %q", policy.Name, clusterName), err
+			}
+			if matches {
+				return authorizer.DecisionDeny, fmt.Sprintf("denied by DenyPolicy %q in workspace %q", policy.Name, clusterName), nil
+			}
+		}
+	}
+
+	return authorizer.DecisionNoOpinion, "", nil
+}
+
+// matches returns true if the policy applies to the user of the request and one of
+// its rules matches the request.
+func (a *denyPolicyAuthorizer) matches(clusterName logicalcluster.Name, policy *tenancyv1alpha1.DenyPolicy, attr authorizer.Attributes) (bool, error) {
+	if sets.NewString(policy.Spec.ExemptUsers...).Has(attr.GetUser().GetName()) {
+		return false, nil
+	}
+	if sets.NewString(policy.Spec.ExemptGroups...).HasAny(attr.GetUser().GetGroups()...) {
+		return false, nil
+	}
+
+	ruleMatches := false
+	for i := range policy.Spec.Rules {
+		if rbac.RuleAllows(attr, &policy.Spec.Rules[i]) {
+			ruleMatches = true
+			break
+		}
+	}
+	if !ruleMatches {
+		return false, nil
+	}
+
+	if len(policy.Spec.ClusterWorkspaceTypes) == 0 {
+		return true, nil
+	}
+	return a.matchesClusterWorkspaceType(clusterName, policy.Spec.ClusterWorkspaceTypes, attr)
+}
+
+// matchesClusterWorkspaceType returns true if the request is for a named clusterworkspace or
+// workspace of one of the given types. Types are compared case-insensitively.
+func (a *denyPolicyAuthorizer) matchesClusterWorkspaceType(clusterName logicalcluster.Name, types []string, attr authorizer.Attributes) (bool, error) {
+	if !attr.IsResourceRequest() || attr.GetAPIGroup() != tenancyv1alpha1.SchemeGroupVersion.Group || attr.GetName() == "" {
+		return false, nil
+	}
+	if attr.GetResource() != "clusterworkspaces" && attr.GetResource() != "workspaces" {
+		return false, nil
+	}
+
+	ws, err := a.getClusterWorkspace(clusterName, attr.GetName())
+	if errors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	for _, t := range types {
+		if strings.EqualFold(t, ws.Spec.Type) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
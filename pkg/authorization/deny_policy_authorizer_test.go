@@ -0,0 +1,185 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authorization
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+func TestDenyPolicyAuthorizer(t *testing.T) {
+	denyProdDeletion := &tenancyv1alpha1.DenyPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "no-prod-deletion", ClusterName: "root:org"},
+		Spec: tenancyv1alpha1.DenyPolicySpec{
+			Rules: []rbacv1.PolicyRule{{
+				Verbs:     []string{"delete"},
+				APIGroups: []string{tenancyv1alpha1.SchemeGroupVersion.Group},
+				Resources: []string{"clusterworkspaces", "workspaces"},
+			}},
+			ClusterWorkspaceTypes: []string{"prod"},
+			ExemptGroups:          []string{"break-glass"},
+		},
+	}
+	denySecrets := &tenancyv1alpha1.DenyPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "no-secrets", ClusterName: "root:org:team"},
+		Spec: tenancyv1alpha1.DenyPolicySpec{
+			Rules: []rbacv1.PolicyRule{{
+				Verbs:     []string{"*"},
+				APIGroups: []string{""},
+				Resources: []string{"secrets"},
+			}},
+			ExemptUsers: []string{"admin"},
+		},
+	}
+	policies := map[logicalcluster.Name][]*tenancyv1alpha1.DenyPolicy{
+		logicalcluster.New("root:org"):      {denyProdDeletion},
+		logicalcluster.New("root:org:team"): {denySecrets},
+	}
+	workspaces := map[string]*tenancyv1alpha1.ClusterWorkspace{
+		"root:org:team|prod": {Spec: tenancyv1alpha1.ClusterWorkspaceSpec{Type: "Prod"}},
+		"root:org:team|dev":  {Spec: tenancyv1alpha1.ClusterWorkspaceSpec{Type: "Universal"}},
+	}
+
+	a := &denyPolicyAuthorizer{
+		listDenyPolicies: func(clusterName logicalcluster.Name) ([]*tenancyv1alpha1.DenyPolicy, error) {
+			return policies[clusterName], nil
+		},
+		getClusterWorkspace: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error) {
+			if ws, ok := workspaces[clusterName.String()+"|"+name]; ok {
+				return ws, nil
+			}
+			return nil, errors.NewNotFound(tenancyv1alpha1.Resource("clusterworkspaces"), name)
+		},
+	}
+
+	tests := map[string]struct {
+		cluster  string
+		user     *user.DefaultInfo
+		attr     authorizer.AttributesRecord
+		wantDeny bool
+	}{
+		"deleting a prod workspace is denied": {
+			cluster:  "root:org:team",
+			user:     &user.DefaultInfo{Name: "alice"},
+			attr:     authorizer.AttributesRecord{Verb: "delete", APIGroup: "tenancy.kcp.dev", Resource: "clusterworkspaces", Name: "prod", ResourceRequest: true},
+			wantDeny: true,
+		},
+		"deleting a prod workspace as break-glass member is not denied": {
+			cluster: "root:org:team",
+			user:    &user.DefaultInfo{Name: "alice", Groups: []string{"break-glass"}},
+			attr:    authorizer.AttributesRecord{Verb: "delete", APIGroup: "tenancy.kcp.dev", Resource: "clusterworkspaces", Name: "prod", ResourceRequest: true},
+		},
+		"deleting a workspace of another type is not denied": {
+			cluster: "root:org:team",
+			user:    &user.DefaultInfo{Name: "alice"},
+			attr:    authorizer.AttributesRecord{Verb: "delete", APIGroup: "tenancy.kcp.dev", Resource: "workspaces", Name: "dev", ResourceRequest: true},
+		},
+		"getting a prod workspace is not denied": {
+			cluster: "root:org:team",
+			user:    &user.DefaultInfo{Name: "alice"},
+			attr:    authorizer.AttributesRecord{Verb: "get", APIGroup: "tenancy.kcp.dev", Resource: "clusterworkspaces", Name: "prod", ResourceRequest: true},
+		},
+		"secrets in a descendant workspace are denied": {
+			cluster:  "root:org:team:ws",
+			user:     &user.DefaultInfo{Name: "alice"},
+			attr:     authorizer.AttributesRecord{Verb: "list", Resource: "secrets", ResourceRequest: true},
+			wantDeny: true,
+		},
+		"secrets are not denied for exempt users": {
+			cluster: "root:org:team:ws",
+			user:    &user.DefaultInfo{Name: "admin"},
+			attr:    authorizer.AttributesRecord{Verb: "list", Resource: "secrets", ResourceRequest: true},
+		},
+		"secrets in a sibling workspace are not denied": {
+			cluster: "root:org:other",
+			user:    &user.DefaultInfo{Name: "alice"},
+			attr:    authorizer.AttributesRecord{Verb: "list", Resource: "secrets", ResourceRequest: true},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := genericapirequest.WithCluster(context.Background(), genericapirequest.Cluster{Name: logicalcluster.New(tt.cluster)})
+			tt.attr.User = tt.user
+
+			dec, _, err := a.Authorize(ctx, tt.attr)
+			require.NoError(t, err)
+			if tt.wantDeny {
+				require.Equal(t, authorizer.DecisionDeny, dec)
+			} else {
+				require.Equal(t, authorizer.DecisionNoOpinion, dec)
+			}
+		})
+	}
+}
+
+func TestDenyPolicyAuthorizerFailsClosed(t *testing.T) {
+	denyProdDeletion := &tenancyv1alpha1.DenyPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "no-prod-deletion", ClusterName: "root:org"},
+		Spec: tenancyv1alpha1.DenyPolicySpec{
+			Rules: []rbacv1.PolicyRule{{
+				Verbs:     []string{"delete"},
+				APIGroups: []string{tenancyv1alpha1.SchemeGroupVersion.Group},
+				Resources: []string{"clusterworkspaces"},
+			}},
+			ClusterWorkspaceTypes: []string{"prod"},
+		},
+	}
+
+	tests := map[string]struct {
+		listErr error
+		getErr  error
+	}{
+		"listing policies fails":  {listErr: fmt.Errorf("cache not synced")},
+		"getting workspace fails": {getErr: fmt.Errorf("cache not synced")},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			a := &denyPolicyAuthorizer{
+				listDenyPolicies: func(clusterName logicalcluster.Name) ([]*tenancyv1alpha1.DenyPolicy, error) {
+					if tt.listErr != nil {
+						return nil, tt.listErr
+					}
+					return []*tenancyv1alpha1.DenyPolicy{denyProdDeletion}, nil
+				},
+				getClusterWorkspace: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error) {
+					return nil, tt.getErr
+				},
+			}
+
+			ctx := genericapirequest.WithCluster(context.Background(), genericapirequest.Cluster{Name: logicalcluster.New("root:org:team")})
+			dec, _, err := a.Authorize(ctx, authorizer.AttributesRecord{
+				User: &user.DefaultInfo{Name: "alice"},
+				Verb: "delete", APIGroup: "tenancy.kcp.dev", Resource: "clusterworkspaces", Name: "prod", ResourceRequest: true,
+			})
+			require.Error(t, err)
+			require.Equal(t, authorizer.DecisionDeny, dec)
+		})
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authorization
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clusters"
+	"k8s.io/klog/v2"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	apisinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/informer"
+)
+
+var statusWriteVerbs = sets.NewString("update", "patch")
+
+// NewStatusFencingAuthorizer returns an authorizer that denies writes to the status subresource
+// of resources bound from an APIExport whose status is only written by the provider. Providers
+// write with the identity of the APIExport in the resource of the request, e.g. cowboys:<identity>.
+// Like the DenyPolicy authorizer, it never allows a request, but returns NoOpinion for
+// everything not denied.
+func NewStatusFencingAuthorizer(apiBindingInformer apisinformers.APIBindingInformer, apiExportInformer apisinformers.APIExportInformer) authorizer.Authorizer {
+	if _, found := apiBindingInformer.Informer().GetIndexer().GetIndexers()[informer.ByLogicalClusterIndexName]; !found {
+		if err := apiBindingInformer.Informer().AddIndexers(cache.Indexers{
+			informer.ByLogicalClusterIndexName: informer.IndexByLogicalCluster,
+		}); err != nil {
+			// nothing we can do here. But this should also never happen. We check for existence before.
+			klog.Errorf("failed to add indexer for APIBindings: %v", err)
+		}
+	}
+	apiBindingIndexer := apiBindingInformer.Informer().GetIndexer()
+	apiExportLister := apiExportInformer.Lister()
+
+	return &statusFencingAuthorizer{
+		listAPIBindings: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
+			objs, err := apiBindingIndexer.ByIndex(informer.ByLogicalClusterIndexName, clusterName.String())
+			if err != nil {
+				return nil, err
+			}
+			bindings := make([]*apisv1alpha1.APIBinding, 0, len(objs))
+			for _, obj := range objs {
+				bindings = append(bindings, obj.(*apisv1alpha1.APIBinding))
+			}
+			return bindings, nil
+		},
+		getAPIExport: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error) {
+			return apiExportLister.Get(clusters.ToClusterAwareKey(clusterName, name))
+		},
+	}
+}
+
+type statusFencingAuthorizer struct {
+	listAPIBindings func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error)
+	getAPIExport    func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error)
+}
+
+func (a *statusFencingAuthorizer) Authorize(ctx context.Context, attr authorizer.Attributes) (authorized authorizer.Decision, reason string, err error) {
+	if !attr.IsResourceRequest() || attr.GetSubresource() != "status" || !statusWriteVerbs.Has(attr.GetVerb()) {
+		return authorizer.DecisionNoOpinion, "", nil
+	}
+	if sets.NewString(attr.GetUser().GetGroups()...).Has(user.SystemPrivilegedGroup) {
+		return authorizer.DecisionNoOpinion, "", nil
+	}
+
+	cluster, err := genericapirequest.ValidClusterFrom(ctx)
+	if err != nil {
+		return authorizer.DecisionNoOpinion, "", err
+	}
+	if cluster == nil || cluster.Name.Empty() {
+		return authorizer.DecisionNoOpinion, "", nil
+	}
+	parentClusterName, hasParent := cluster.Name.Parent()
+	if !hasParent {
+		// APIBindings in root are not possible (they can only point to sibling workspaces).
+		return authorizer.DecisionNoOpinion, "", nil
+	}
+
+	// the identity is only stripped from the resource after authorization, by WithResourceIdentity.
+	resource, identity := attr.GetResource(), ""
+	if i := strings.Index(resource, ":"); i >= 0 {
+		resource, identity = resource[:i], resource[i+1:]
+	}
+
+	bindings, err := a.listAPIBindings(cluster.Name)
+	if err != nil {
+		return authorizer.DecisionNoOpinion, "", err
+	}
+	for _, binding := range bindings {
+		if binding.Status.BoundAPIExport == nil || binding.Status.BoundAPIExport.Workspace == nil {
+			continue
+		}
+		if !bindsResource(binding, attr.GetAPIGroup(), resource) {
+			continue
+		}
+
+		ref := binding.Status.BoundAPIExport.Workspace
+		exportClusterName := parentClusterName.Join(ref.WorkspaceName)
+		export, err := a.getAPIExport(exportClusterName, ref.ExportName)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return authorizer.DecisionNoOpinion, "", err
+		}
+		if export.Spec.StatusWriters != apisv1alpha1.StatusWritersProvider {
+			continue
+		}
+		if identity != "" && identity == export.Status.IdentityHash {
+			continue // the provider, left to RBAC
+		}
+		return authorizer.DecisionDeny, fmt.Sprintf("status is only written by the provider of APIExport %s|%s", exportClusterName, ref.ExportName), nil
+	}
+
+	return authorizer.DecisionNoOpinion, "", nil
+}
+
+// bindsResource returns whether the binding binds the resource of the given group.
+func bindsResource(binding *apisv1alpha1.APIBinding, group, resource string) bool {
+	for _, br := range binding.Status.BoundResources {
+		if br.Group == group && br.Resource == resource {
+			return true
+		}
+	}
+	return false
+}
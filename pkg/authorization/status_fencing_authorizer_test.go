@@ -0,0 +1,152 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authorization
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+)
+
+func TestStatusFencingAuthorizer(t *testing.T) {
+	binding := func(name, exportWorkspace, export string, group, resource string) *apisv1alpha1.APIBinding {
+		return &apisv1alpha1.APIBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: name, ClusterName: "root:org:consumer"},
+			Status: apisv1alpha1.APIBindingStatus{
+				BoundAPIExport: &apisv1alpha1.ExportReference{
+					Workspace: &apisv1alpha1.WorkspaceExportReference{WorkspaceName: exportWorkspace, ExportName: export},
+				},
+				BoundResources: []apisv1alpha1.BoundAPIResource{{Group: group, Resource: resource}},
+			},
+		}
+	}
+	bindings := map[logicalcluster.Name][]*apisv1alpha1.APIBinding{
+		logicalcluster.New("root:org:consumer"): {
+			binding("wildwest", "provider", "wildwest", "wildwest.dev", "cowboys"),
+			binding("farm", "provider", "farm", "farm.dev", "cows"),
+		},
+	}
+	exports := map[string]*apisv1alpha1.APIExport{
+		"root:org:provider|wildwest": {
+			Spec:   apisv1alpha1.APIExportSpec{StatusWriters: apisv1alpha1.StatusWritersProvider},
+			Status: apisv1alpha1.APIExportStatus{IdentityHash: "wildwest-identity"},
+		},
+		"root:org:provider|farm": {Spec: apisv1alpha1.APIExportSpec{StatusWriters: apisv1alpha1.StatusWritersAny}},
+	}
+
+	a := &statusFencingAuthorizer{
+		listAPIBindings: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
+			return bindings[clusterName], nil
+		},
+		getAPIExport: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error) {
+			if export, ok := exports[clusterName.String()+"|"+name]; ok {
+				return export, nil
+			}
+			return nil, errors.NewNotFound(apisv1alpha1.Resource("apiexports"), name)
+		},
+	}
+
+	tests := map[string]struct {
+		cluster  string
+		user     *user.DefaultInfo
+		attr     authorizer.AttributesRecord
+		wantDeny bool
+	}{
+		"updating fenced status is denied": {
+			cluster:  "root:org:consumer",
+			user:     &user.DefaultInfo{Name: "alice"},
+			attr:     authorizer.AttributesRecord{Verb: "update", APIGroup: "wildwest.dev", Resource: "cowboys", Subresource: "status", Name: "lucky", ResourceRequest: true},
+			wantDeny: true,
+		},
+		"patching fenced status is denied": {
+			cluster:  "root:org:consumer",
+			user:     &user.DefaultInfo{Name: "alice"},
+			attr:     authorizer.AttributesRecord{Verb: "patch", APIGroup: "wildwest.dev", Resource: "cowboys", Subresource: "status", Name: "lucky", ResourceRequest: true},
+			wantDeny: true,
+		},
+		"reading fenced status is not denied": {
+			cluster: "root:org:consumer",
+			user:    &user.DefaultInfo{Name: "alice"},
+			attr:    authorizer.AttributesRecord{Verb: "get", APIGroup: "wildwest.dev", Resource: "cowboys", Subresource: "status", Name: "lucky", ResourceRequest: true},
+		},
+		"updating the main resource is not denied": {
+			cluster: "root:org:consumer",
+			user:    &user.DefaultInfo{Name: "alice"},
+			attr:    authorizer.AttributesRecord{Verb: "update", APIGroup: "wildwest.dev", Resource: "cowboys", Name: "lucky", ResourceRequest: true},
+		},
+		"updating fenced status with the identity of the export is not denied": {
+			cluster: "root:org:consumer",
+			user:    &user.DefaultInfo{Name: "provider-controller"},
+			attr:    authorizer.AttributesRecord{Verb: "update", APIGroup: "wildwest.dev", Resource: "cowboys:wildwest-identity", Subresource: "status", Name: "lucky", ResourceRequest: true},
+		},
+		"patching fenced status with the identity of the export is not denied": {
+			cluster: "root:org:consumer",
+			user:    &user.DefaultInfo{Name: "provider-controller"},
+			attr:    authorizer.AttributesRecord{Verb: "patch", APIGroup: "wildwest.dev", Resource: "cowboys:wildwest-identity", Subresource: "status", Name: "lucky", ResourceRequest: true},
+		},
+		"updating fenced status with another identity is denied": {
+			cluster:  "root:org:consumer",
+			user:     &user.DefaultInfo{Name: "alice"},
+			attr:     authorizer.AttributesRecord{Verb: "update", APIGroup: "wildwest.dev", Resource: "cowboys:other-identity", Subresource: "status", Name: "lucky", ResourceRequest: true},
+			wantDeny: true,
+		},
+		"updating fenced status by system:masters is not denied": {
+			cluster: "root:org:consumer",
+			user:    &user.DefaultInfo{Name: "loopback", Groups: []string{user.SystemPrivilegedGroup}},
+			attr:    authorizer.AttributesRecord{Verb: "update", APIGroup: "wildwest.dev", Resource: "cowboys", Subresource: "status", Name: "lucky", ResourceRequest: true},
+		},
+		"updating status of an export without fencing is not denied": {
+			cluster: "root:org:consumer",
+			user:    &user.DefaultInfo{Name: "alice"},
+			attr:    authorizer.AttributesRecord{Verb: "update", APIGroup: "farm.dev", Resource: "cows", Subresource: "status", Name: "bella", ResourceRequest: true},
+		},
+		"updating status of an unbound resource is not denied": {
+			cluster: "root:org:consumer",
+			user:    &user.DefaultInfo{Name: "alice"},
+			attr:    authorizer.AttributesRecord{Verb: "update", APIGroup: "apps", Resource: "deployments", Subresource: "status", Name: "web", ResourceRequest: true},
+		},
+		"updating status in another workspace is not denied": {
+			cluster: "root:org:other",
+			user:    &user.DefaultInfo{Name: "alice"},
+			attr:    authorizer.AttributesRecord{Verb: "update", APIGroup: "wildwest.dev", Resource: "cowboys", Subresource: "status", Name: "lucky", ResourceRequest: true},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := genericapirequest.WithCluster(context.Background(), genericapirequest.Cluster{Name: logicalcluster.New(tt.cluster)})
+			tt.attr.User = tt.user
+
+			dec, _, err := a.Authorize(ctx, tt.attr)
+			require.NoError(t, err)
+			if tt.wantDeny {
+				require.Equal(t, authorizer.DecisionDeny, dec)
+			} else {
+				require.Equal(t, authorizer.DecisionNoOpinion, dec)
+			}
+		})
+	}
+}
@@ -0,0 +1,258 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/kcp-dev/logicalcluster"
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	kcpclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
+)
+
+const manifestName = "manifest.json"
+
+// indexAttempts is how often snapshots are retaken when the shard index
+// changes while the shards are snapshotted.
+const indexAttempts = 3
+
+// Shard is a kcp shard to back up, together with the etcd it stores its data in.
+type Shard struct {
+	// Name is the name of the ClusterWorkspaceShard.
+	Name string
+	// Etcd is the client configuration of the shard's etcd.
+	Etcd clientv3.Config
+}
+
+// Manifest describes a backup of all shards. It is written only after the
+// snapshots of all shards have been uploaded, i.e. a backup without manifest
+// is incomplete and must not be restored.
+type Manifest struct {
+	// ID identifies the backup within the object store.
+	ID string `json:"id"`
+	// Timestamp is the time the backup was started.
+	Timestamp metav1.Time `json:"timestamp"`
+	// Completed is the time the last snapshot was taken. The snapshots of the
+	// shards are taken between Timestamp and Completed.
+	Completed metav1.Time `json:"completed"`
+	// Shards holds one snapshot per shard.
+	Shards []ShardSnapshot `json:"shards"`
+
+	// ClusterWorkspaceShards are the shard objects in the root workspace at backup time.
+	ClusterWorkspaceShards []tenancyv1alpha1.ClusterWorkspaceShard `json:"clusterWorkspaceShards,omitempty"`
+	// Locations maps logical cluster names to the shard they are scheduled to.
+	Locations map[string]string `json:"locations,omitempty"`
+}
+
+// ShardSnapshot is the etcd snapshot of one shard.
+type ShardSnapshot struct {
+	// Name is the name of the shard.
+	Name string `json:"name"`
+	// Revision is the etcd revision the snapshot was taken at.
+	Revision int64 `json:"revision"`
+	// Key is the object store key of the snapshot.
+	Key string `json:"key"`
+}
+
+// Backup snapshots the etcd of all shards concurrently and uploads the
+// snapshots to store under the given id, together with the shard index, i.e.
+// the ClusterWorkspaceShards and the placement of all workspaces read through
+// kcpClusterClient.
+//
+// The index is read before and after the snapshots. If it changes in between,
+// e.g. because a workspace was scheduled, the snapshots are retaken, such that
+// the index of the manifest matches the snapshots. Each snapshot is a
+// consistent point-in-time copy of its shard, but the snapshots of different
+// shards are taken at slightly different times.
+func Backup(ctx context.Context, store ObjectStore, kcpClusterClient kcpclient.ClusterInterface, shards []Shard, id string) (*Manifest, error) {
+	for attempt := 1; ; attempt++ {
+		manifest := &Manifest{
+			ID:        id,
+			Timestamp: metav1.Now(),
+		}
+
+		before, err := readIndex(ctx, kcpClusterClient)
+		if err != nil {
+			return nil, err
+		}
+
+		snapshots := make([]ShardSnapshot, len(shards))
+		errs := make([]error, len(shards))
+		var wg sync.WaitGroup
+		for i := range shards {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				snapshots[i], errs[i] = snapshotShard(ctx, store, id, shards[i])
+			}(i)
+		}
+		wg.Wait()
+		if err := utilerrors.NewAggregate(errs); err != nil {
+			return nil, err
+		}
+		manifest.Shards = snapshots
+		manifest.Completed = metav1.Now()
+
+		after, err := readIndex(ctx, kcpClusterClient)
+		if err != nil {
+			return nil, err
+		}
+		if !before.equal(after) {
+			if attempt == indexAttempts {
+				return nil, fmt.Errorf("shard index kept changing during %d attempts to back up all shards", indexAttempts)
+			}
+			klog.Infof("Shard index changed during backup %q, retaking snapshots", id)
+			continue
+		}
+		manifest.ClusterWorkspaceShards = after.shards
+		manifest.Locations = after.locations
+
+		bs, err := json.MarshalIndent(manifest, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		if err := store.Put(ctx, path.Join(id, manifestName), bytes.NewReader(bs)); err != nil {
+			return nil, fmt.Errorf("failed to upload backup manifest: %w", err)
+		}
+
+		klog.Infof("Finished backup %q of %d shards", id, len(shards))
+		return manifest, nil
+	}
+}
+
+// index is the shard index, i.e. the shards and the placement of workspaces.
+type index struct {
+	shards    []tenancyv1alpha1.ClusterWorkspaceShard
+	locations map[string]string
+}
+
+func readIndex(ctx context.Context, kcpClusterClient kcpclient.ClusterInterface) (*index, error) {
+	shardList, err := kcpClusterClient.Cluster(tenancyv1alpha1.RootCluster).TenancyV1alpha1().ClusterWorkspaceShards().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ClusterWorkspaceShards: %w", err)
+	}
+
+	workspaces, err := kcpClusterClient.Cluster(logicalcluster.Wildcard).TenancyV1alpha1().ClusterWorkspaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ClusterWorkspaces: %w", err)
+	}
+	locations := map[string]string{}
+	for _, ws := range workspaces.Items {
+		if ws.Status.Location.Current == "" {
+			continue
+		}
+		locations[logicalcluster.From(&ws).Join(ws.Name).String()] = ws.Status.Location.Current
+	}
+
+	return &index{shards: shardList.Items, locations: locations}, nil
+}
+
+// equal compares the shard specs and the workspace placements. Status changes
+// of shards are ignored.
+func (i *index) equal(other *index) bool {
+	if len(i.shards) != len(other.shards) || !reflect.DeepEqual(i.locations, other.locations) {
+		return false
+	}
+	specs := map[string]tenancyv1alpha1.ClusterWorkspaceShardSpec{}
+	for _, s := range i.shards {
+		specs[s.Name] = s.Spec
+	}
+	for _, s := range other.shards {
+		spec, ok := specs[s.Name]
+		if !ok || !reflect.DeepEqual(spec, s.Spec) {
+			return false
+		}
+	}
+	return true
+}
+
+func snapshotShard(ctx context.Context, store ObjectStore, id string, shard Shard) (ShardSnapshot, error) {
+	cfg := shard.Etcd
+	cfg.Context = ctx
+	client, err := clientv3.New(cfg)
+	if err != nil {
+		return ShardSnapshot{}, fmt.Errorf("failed to connect to etcd of shard %q: %w", shard.Name, err)
+	}
+	defer client.Close()
+
+	// the snapshot includes at least this revision
+	status, err := client.Status(ctx, cfg.Endpoints[0])
+	if err != nil {
+		return ShardSnapshot{}, fmt.Errorf("failed to get etcd status of shard %q: %w", shard.Name, err)
+	}
+
+	rc, err := client.Snapshot(ctx)
+	if err != nil {
+		return ShardSnapshot{}, fmt.Errorf("failed to snapshot etcd of shard %q: %w", shard.Name, err)
+	}
+	defer rc.Close()
+
+	key := path.Join(id, "shards", shard.Name+".db")
+	if err := store.Put(ctx, key, rc); err != nil {
+		return ShardSnapshot{}, fmt.Errorf("failed to upload snapshot of shard %q: %w", shard.Name, err)
+	}
+
+	klog.V(2).Infof("Uploaded snapshot of shard %q at revision %d to %s", shard.Name, status.Header.Revision, key)
+	return ShardSnapshot{
+		Name:     shard.Name,
+		Revision: status.Header.Revision,
+		Key:      key,
+	}, nil
+}
+
+// ListBackups returns the IDs of all complete backups in store.
+func ListBackups(ctx context.Context, store ObjectStore) ([]string, error) {
+	keys, err := store.List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, key := range keys {
+		if strings.HasSuffix(key, "/"+manifestName) {
+			ids = append(ids, strings.TrimSuffix(key, "/"+manifestName))
+		}
+	}
+	return ids, nil
+}
+
+// GetManifest returns the manifest of the backup with the given id.
+func GetManifest(ctx context.Context, store ObjectStore, id string) (*Manifest, error) {
+	rc, err := store.Get(ctx, path.Join(id, manifestName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get manifest of backup %q: %w", id, err)
+	}
+	defer rc.Close()
+
+	var manifest Manifest
+	if err := json.NewDecoder(rc).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("failed to decode manifest of backup %q: %w", id, err)
+	}
+	return &manifest, nil
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	kcpclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
+	kcpfakeclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/fake"
+)
+
+// fakeClusterClient ignores the logical cluster.
+type fakeClusterClient struct {
+	*kcpfakeclient.Clientset
+}
+
+func (c fakeClusterClient) Cluster(logicalcluster.Name) kcpclient.Interface {
+	return c.Clientset
+}
+
+func TestFileStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewFileStore(t.TempDir())
+
+	require.NoError(t, store.Put(ctx, "b/shards/root.db", strings.NewReader("root")))
+	require.NoError(t, store.Put(ctx, "a/manifest.json", strings.NewReader("{}")))
+	require.NoError(t, store.Put(ctx, "b/manifest.json", strings.NewReader("{}")))
+	require.Error(t, store.Put(ctx, "../escape", strings.NewReader("")))
+
+	keys, err := store.List(ctx, "b/")
+	require.NoError(t, err)
+	require.Equal(t, []string{"b/manifest.json", "b/shards/root.db"}, keys)
+
+	rc, err := store.Get(ctx, "b/shards/root.db")
+	require.NoError(t, err)
+	defer rc.Close()
+	bs, err := ioutil.ReadAll(rc)
+	require.NoError(t, err)
+	require.Equal(t, "root", string(bs))
+
+	ids, err := ListBackups(ctx, store)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, ids)
+}
+
+func TestRestore(t *testing.T) {
+	ctx := context.Background()
+	store := NewFileStore(t.TempDir())
+
+	manifest := &Manifest{
+		ID: "backup",
+		Shards: []ShardSnapshot{
+			{Name: "root", Revision: 42, Key: "backup/shards/root.db"},
+			{Name: "shard-1", Revision: 23, Key: "backup/shards/shard-1.db"},
+		},
+		ClusterWorkspaceShards: []tenancyv1alpha1.ClusterWorkspaceShard{
+			{ObjectMeta: metav1.ObjectMeta{Name: "root"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "shard-1"}, Spec: tenancyv1alpha1.ClusterWorkspaceShardSpec{BaseURL: "https://shard-1"}},
+		},
+		Locations: map[string]string{
+			"root:org":  "root",
+			"root:team": "shard-1",
+		},
+	}
+	bs, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	require.NoError(t, store.Put(ctx, "backup/shards/root.db", strings.NewReader("root")))
+	require.NoError(t, store.Put(ctx, "backup/shards/shard-1.db", strings.NewReader("shard-1")))
+	require.NoError(t, store.Put(ctx, "backup/manifest.json", bytes.NewReader(bs)))
+
+	renames := map[string]string{"shard-1": "shard-a"}
+	dir := t.TempDir()
+	_, err = Restore(ctx, store, "backup", dir, renames)
+	require.NoError(t, err)
+	bs, err = ioutil.ReadFile(filepath.Join(dir, "shard-a.db"))
+	require.NoError(t, err)
+	require.Equal(t, "shard-1", string(bs))
+	_, err = ioutil.ReadFile(filepath.Join(dir, "root.db"))
+	require.NoError(t, err)
+
+	client := kcpfakeclient.NewSimpleClientset(
+		&manifest.ClusterWorkspaceShards[0],
+		&manifest.ClusterWorkspaceShards[1],
+		&tenancyv1alpha1.ClusterWorkspace{
+			ObjectMeta: metav1.ObjectMeta{Name: "team", ClusterName: "root"},
+			Status:     tenancyv1alpha1.ClusterWorkspaceStatus{Location: tenancyv1alpha1.ClusterWorkspaceLocation{Current: "shard-1"}},
+		},
+	)
+	require.NoError(t, Rewire(ctx, fakeClusterClient{client}, manifest, renames))
+
+	// the old shard must only be deleted once no workspace is placed onto it anymore
+	moved, deleted := -1, -1
+	for i, action := range client.Actions() {
+		switch {
+		case action.Matches("update", "clusterworkspaces") && action.GetSubresource() == "status":
+			moved = i
+		case action.Matches("delete", "clusterworkspaceshards"):
+			deleted = i
+		}
+	}
+	require.NotEqual(t, -1, moved, "workspace not moved")
+	require.NotEqual(t, -1, deleted, "old shard not deleted")
+	require.Less(t, moved, deleted, "old shard deleted before the workspace was moved")
+
+	shards, err := client.TenancyV1alpha1().ClusterWorkspaceShards().List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	var names []string
+	for _, s := range shards.Items {
+		names = append(names, s.Name)
+	}
+	require.ElementsMatch(t, []string{"root", "shard-a"}, names)
+
+	ws, err := client.TenancyV1alpha1().ClusterWorkspaces().Get(ctx, "team", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "shard-a", ws.Status.Location.Current)
+}
+
+func TestPrune(t *testing.T) {
+	ctx := context.Background()
+	store := NewFileStore(t.TempDir())
+	for _, key := range []string{
+		"20220101-000000/manifest.json", "20220101-000000/shards/root.db",
+		"20220102-000000/shards/root.db", // incomplete
+		"20220103-000000/manifest.json", "20220103-000000/shards/root.db",
+		"20220104-000000/manifest.json", "20220104-000000/shards/root.db",
+		"20220105-000000/shards/root.db", // in progress
+	} {
+		require.NoError(t, store.Put(ctx, key, strings.NewReader("data")))
+	}
+
+	c := NewController(store, nil, nil, 0, 2)
+	require.NoError(t, c.prune(ctx, "20220105-000000"))
+
+	keys, err := store.List(ctx, "")
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"20220103-000000/manifest.json", "20220103-000000/shards/root.db",
+		"20220104-000000/manifest.json", "20220104-000000/shards/root.db",
+		"20220105-000000/shards/root.db",
+	}, keys)
+}
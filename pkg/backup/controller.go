@@ -0,0 +1,130 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	kcpclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
+)
+
+const ControllerName = "kcp-backup"
+
+// NewController returns a controller backing up all shards every interval,
+// keeping the latest retain backups, or all if retain is zero.
+func NewController(store ObjectStore, kcpClusterClient kcpclient.ClusterInterface, shards []Shard, interval time.Duration, retain int) *Controller {
+	return &Controller{
+		store:            store,
+		kcpClusterClient: kcpClusterClient,
+		shards:           shards,
+		interval:         interval,
+		retain:           retain,
+		now:              time.Now,
+	}
+}
+
+// Controller takes backups of all shards periodically, and prunes old and
+// incomplete backups.
+type Controller struct {
+	store            ObjectStore
+	kcpClusterClient kcpclient.ClusterInterface
+	shards           []Shard
+	interval         time.Duration
+	retain           int
+	now              func() time.Time
+}
+
+// Start runs backups until ctx is done. Failed backups are retried with the
+// next interval.
+func (c *Controller) Start(ctx context.Context) {
+	klog.Infof("Starting %s controller", ControllerName)
+	defer klog.Infof("Shutting down %s controller", ControllerName)
+
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := c.RunOnce(ctx); err != nil {
+			klog.Errorf("Backup failed: %v", err)
+		}
+	}, c.interval)
+}
+
+// RunOnce takes one backup and prunes old backups afterwards.
+func (c *Controller) RunOnce(ctx context.Context) error {
+	// IDs sort by time, which is what pruning relies on
+	id := c.now().UTC().Format("20060102-150405")
+	if _, err := Backup(ctx, c.store, c.kcpClusterClient, c.shards, id); err != nil {
+		return err
+	}
+	return c.prune(ctx, id)
+}
+
+// prune deletes incomplete backups older than current, and the oldest
+// complete backups beyond the retention.
+func (c *Controller) prune(ctx context.Context, current string) error {
+	keys, err := c.store.List(ctx, "")
+	if err != nil {
+		return err
+	}
+	ids := map[string]bool{} // id -> complete
+	for _, key := range keys {
+		id := strings.SplitN(key, "/", 2)[0]
+		ids[id] = ids[id] || strings.HasSuffix(key, "/"+manifestName)
+	}
+
+	var complete []string
+	var errs []error
+	for id, isComplete := range ids {
+		if isComplete {
+			complete = append(complete, id)
+		} else if id < current {
+			errs = append(errs, DeleteBackup(ctx, c.store, id))
+		}
+	}
+	if c.retain > 0 && len(complete) > c.retain {
+		sort.Strings(complete)
+		for _, id := range complete[:len(complete)-c.retain] {
+			errs = append(errs, DeleteBackup(ctx, c.store, id))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// DeleteBackup deletes the backup with the given id. The manifest is deleted
+// first, such that a partially deleted backup counts as incomplete.
+func DeleteBackup(ctx context.Context, store ObjectStore, id string) error {
+	if err := store.Delete(ctx, path.Join(id, manifestName)); err != nil {
+		return err
+	}
+	keys, err := store.List(ctx, id+"/")
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := store.Delete(ctx, key); err != nil {
+			return err
+		}
+	}
+	klog.V(2).Infof("Deleted backup %q", id)
+	return nil
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package backup implements backups of all shards of a kcp installation.
+//
+// A backup consists of one etcd snapshot per shard and a manifest. The
+// manifest holds the shard index, i.e. the ClusterWorkspaceShards and the
+// placement of every workspace, and is uploaded last to mark the backup as
+// complete. Backups are stored in an ObjectStore, e.g. a local directory or
+// an S3 or GCS bucket:
+//
+//	<id>/manifest.json
+//	<id>/shards/<shard>.db
+//
+// Every shard snapshot is a point-in-time snapshot of that shard's etcd.
+// Across shards, the snapshots are taken concurrently, but not atomically:
+// writes between Manifest.Timestamp and Manifest.Completed may be contained
+// in some shard snapshots and not in others. The shard index is read before
+// and after the snapshots, and the snapshots are retaken if it changed, so
+// the manifest always matches the workspace placement of the snapshots.
+//
+// On restore, the shard snapshots are restored into new etcd data
+// directories, or downloaded to be restored with etcdutl into external etcd
+// clusters. Shards can be renamed on restore, in which case Rewire updates
+// the shard index to the new names.
+//
+// The Controller takes backups periodically and deletes old and incomplete
+// ones.
+package backup
@@ -0,0 +1,188 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/client/pkg/v3/fileutil"
+	"go.etcd.io/etcd/client/pkg/v3/types"
+	"go.etcd.io/etcd/raft/v3/raftpb"
+	"go.etcd.io/etcd/server/v3/etcdserver"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/membership"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/snap"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/v2store"
+	"go.etcd.io/etcd/server/v3/etcdserver/cindex"
+	"go.etcd.io/etcd/server/v3/mvcc/backend"
+	"go.etcd.io/etcd/server/v3/wal"
+	"go.etcd.io/etcd/server/v3/wal/walpb"
+	"go.uber.org/zap"
+)
+
+// EtcdMember is the single etcd member a shard snapshot is restored into.
+type EtcdMember struct {
+	// Name is the etcd member name, "default" for the embedded etcd of kcp.
+	Name string
+	// PeerURL is the advertised peer URL of the member, https://localhost:2380
+	// for the embedded etcd of kcp.
+	PeerURL string
+}
+
+// restoreSnapshot restores the etcd snapshot file into a new data directory of
+// a single member cluster, like "etcdutl snapshot restore" does: the snapshot
+// database is verified against its hash, the old membership is removed, and
+// a WAL and raft snapshot bootstrapping the new member are written. The data
+// directory must not exist or be empty.
+//
+// This follows go.etcd.io/etcd/etcdutl/v3/snapshot, which is not a dependency.
+func restoreSnapshot(snapshotPath, dataDir string, member EtcdMember) error {
+	lg := zap.NewNop()
+
+	peerURLs, err := types.NewURLs([]string{member.PeerURL})
+	if err != nil {
+		return err
+	}
+	initialCluster, err := types.NewURLsMap(member.Name + "=" + member.PeerURL)
+	if err != nil {
+		return err
+	}
+	cl, err := membership.NewClusterFromURLsMap(lg, "etcd-cluster", initialCluster)
+	if err != nil {
+		return err
+	}
+	if len(cl.Members()) != 1 || cl.Members()[0].PeerURLs[0] != peerURLs[0].String() {
+		return fmt.Errorf("invalid etcd member %s=%s", member.Name, member.PeerURL)
+	}
+
+	if fileutil.Exist(dataDir) && !fileutil.DirEmpty(dataDir) {
+		return fmt.Errorf("etcd data directory %q is not empty", dataDir)
+	}
+	snapDir := filepath.Join(dataDir, "member", "snap")
+	walDir := filepath.Join(dataDir, "member", "wal")
+	dbPath := filepath.Join(snapDir, "db")
+
+	if err := copyAndVerifySnapshot(snapshotPath, snapDir, dbPath); err != nil {
+		return err
+	}
+
+	be := backend.NewDefaultBackend(dbPath)
+	defer be.Close()
+	if err := membership.TrimMembershipFromBackend(lg, be); err != nil {
+		return err
+	}
+
+	// add the new member to the v2 store and the backend
+	st := v2store.New(etcdserver.StoreClusterPrefix, etcdserver.StoreKeysPrefix)
+	cl.SetStore(st)
+	cl.SetBackend(be)
+	m := cl.Members()[0]
+	cl.AddMember(m, true)
+
+	if err := fileutil.CreateDirAll(walDir); err != nil {
+		return err
+	}
+	metadata, err := (&etcdserverpb.Metadata{NodeID: uint64(m.ID), ClusterID: uint64(cl.ID())}).Marshal()
+	if err != nil {
+		return err
+	}
+	w, err := wal.Create(lg, walDir, metadata)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	memberContext, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	cc, err := (&raftpb.ConfChange{Type: raftpb.ConfChangeAddNode, NodeID: uint64(m.ID), Context: memberContext}).Marshal()
+	if err != nil {
+		return err
+	}
+	commit, term := uint64(1), uint64(1)
+	hardState := raftpb.HardState{Term: term, Vote: uint64(m.ID), Commit: commit}
+	if err := w.Save(hardState, []raftpb.Entry{{Type: raftpb.EntryConfChange, Term: term, Index: commit, Data: cc}}); err != nil {
+		return err
+	}
+
+	storeData, err := st.Save()
+	if err != nil {
+		return err
+	}
+	confState := raftpb.ConfState{Voters: []uint64{uint64(m.ID)}}
+	if err := snap.New(lg, snapDir).SaveSnap(raftpb.Snapshot{
+		Data:     storeData,
+		Metadata: raftpb.SnapshotMetadata{Index: commit, Term: term, ConfState: confState},
+	}); err != nil {
+		return err
+	}
+	if err := w.SaveSnapshot(walpb.Snapshot{Index: commit, Term: term, ConfState: &confState}); err != nil {
+		return err
+	}
+
+	// entries up to the consistent index count as applied
+	cindex.UpdateConsistentIndex(be.BatchTx(), commit, term, false)
+	return nil
+}
+
+// copyAndVerifySnapshot copies the snapshot to dbPath, verifying and
+// stripping the sha256 hash appended to snapshots taken through the etcd API.
+func copyAndVerifySnapshot(snapshotPath, snapDir, dbPath string) error {
+	src, err := os.Open(snapshotPath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+	// bolt databases are a multiple of 512 bytes, snapshots have a hash appended
+	if info.Size()%512 != sha256.Size {
+		return fmt.Errorf("etcd snapshot %s has no integrity hash", snapshotPath)
+	}
+
+	if err := fileutil.CreateDirAll(snapDir); err != nil {
+		return err
+	}
+	db, err := os.OpenFile(dbPath, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(db, h), io.LimitReader(src, info.Size()-sha256.Size)); err != nil {
+		return err
+	}
+	expected := make([]byte, sha256.Size)
+	if _, err := io.ReadFull(src, expected); err != nil {
+		return err
+	}
+	if actual := h.Sum(nil); !bytes.Equal(expected, actual) {
+		return fmt.Errorf("etcd snapshot %s is corrupt: expected sha256 %x, got %x", snapshotPath, expected, actual)
+	}
+	return db.Sync()
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/server/v3/embed"
+)
+
+func freePort(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	return fmt.Sprintf("%d", l.Addr().(*net.TCPAddr).Port)
+}
+
+// startEtcd starts a single member etcd in dataDir and returns a client.
+func startEtcd(t *testing.T, dataDir, name, peerURL string) *clientv3.Client {
+	cfg := embed.NewConfig()
+	cfg.Name = name
+	cfg.Dir = dataDir
+	cfg.LogLevel = "error"
+	peer, err := url.Parse(peerURL)
+	require.NoError(t, err)
+	client, err := url.Parse("http://127.0.0.1:" + freePort(t))
+	require.NoError(t, err)
+	cfg.LPUrls, cfg.APUrls = []url.URL{*peer}, []url.URL{*peer}
+	cfg.LCUrls, cfg.ACUrls = []url.URL{*client}, []url.URL{*client}
+	cfg.InitialCluster = cfg.InitialClusterFromName(name)
+
+	e, err := embed.StartEtcd(cfg)
+	require.NoError(t, err)
+	t.Cleanup(e.Close)
+	select {
+	case <-e.Server.ReadyNotify():
+	case <-time.After(30 * time.Second):
+		t.Fatal("etcd did not become ready")
+	}
+
+	c, err := clientv3.New(clientv3.Config{Endpoints: []string{client.String()}, DialTimeout: 5 * time.Second})
+	require.NoError(t, err)
+	t.Cleanup(func() { c.Close() }) // nolint:errcheck
+	return c
+}
+
+func TestRestoreSnapshot(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	t.Log("Taking a snapshot of a running etcd")
+	source := startEtcd(t, filepath.Join(t.TempDir(), "etcd"), "source", "http://127.0.0.1:"+freePort(t))
+	_, err := source.Put(ctx, "/registry/key", "value")
+	require.NoError(t, err)
+	rc, err := source.Snapshot(ctx)
+	require.NoError(t, err)
+	snapshotPath := filepath.Join(t.TempDir(), "snapshot.db")
+	f, err := os.Create(snapshotPath)
+	require.NoError(t, err)
+	_, err = io.Copy(f, rc)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	require.NoError(t, rc.Close())
+
+	t.Log("Restoring into a new data directory with another member")
+	member := EtcdMember{Name: "default", PeerURL: "http://127.0.0.1:" + freePort(t)}
+	dataDir := filepath.Join(t.TempDir(), "restored")
+	require.NoError(t, restoreSnapshot(snapshotPath, dataDir, member))
+	require.Error(t, restoreSnapshot(snapshotPath, dataDir, member), "restoring into a non-empty directory should fail")
+
+	t.Log("Starting etcd on the restored data directory")
+	restored := startEtcd(t, dataDir, member.Name, member.PeerURL)
+	resp, err := restored.Get(ctx, "/registry/key")
+	require.NoError(t, err)
+	require.Len(t, resp.Kvs, 1)
+	require.Equal(t, "value", string(resp.Kvs[0].Value))
+
+	members, err := restored.MemberList(ctx)
+	require.NoError(t, err)
+	require.Len(t, members.Members, 1)
+	require.Equal(t, []string{member.PeerURL}, members.Members[0].PeerURLs)
+}
+
+func TestCopyAndVerifySnapshotCorrupt(t *testing.T) {
+	snapshotPath := filepath.Join(t.TempDir(), "snapshot.db")
+	require.NoError(t, ioutil.WriteFile(snapshotPath, make([]byte, 512+32), 0600))
+	dir := t.TempDir()
+	require.Error(t, copyAndVerifySnapshot(snapshotPath, dir, filepath.Join(dir, "db")))
+}
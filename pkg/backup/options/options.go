@@ -0,0 +1,200 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	"go.etcd.io/etcd/client/pkg/v3/transport"
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"github.com/kcp-dev/kcp/pkg/backup"
+)
+
+type Options struct {
+	Kubeconfig      string
+	BackupDirectory string
+
+	// S3 compatible bucket, credentials are read from AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and
+	// AWS_SESSION_TOKEN, or from AWS_ROLE_ARN and AWS_WEB_IDENTITY_TOKEN_FILE.
+	S3Endpoint string
+	S3Bucket   string
+	S3Prefix   string
+	S3Region   string
+
+	// Shards maps shard names to etcd client URLs.
+	Shards       map[string]string
+	EtcdCertFile string
+	EtcdKeyFile  string
+	EtcdCAFile   string
+	EtcdTimeout  time.Duration
+	Interval     time.Duration
+	Retain       int
+	RestoreID    string
+	RestoreDir   string
+	RenameShards map[string]string
+	RewireShards bool
+
+	// EtcdDataDirectories maps shard names to etcd data directories to restore into.
+	EtcdDataDirectories map[string]string
+	EtcdName            string
+	EtcdPeerURL         string
+}
+
+func NewOptions() *Options {
+	return &Options{
+		Shards:       map[string]string{},
+		RenameShards: map[string]string{},
+		EtcdTimeout:  10 * time.Second,
+		RewireShards: true,
+		S3Endpoint:   "https://s3.amazonaws.com",
+		S3Region:     "us-east-1",
+
+		EtcdDataDirectories: map[string]string{},
+		EtcdName:            "default",
+		EtcdPeerURL:         "https://localhost:2380",
+	}
+}
+
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Kubeconfig, "kubeconfig", o.Kubeconfig, "Kubeconfig of the root shard with admin credentials to read the shard index on backup and to rewire it on restore")
+	fs.StringVar(&o.BackupDirectory, "backup-directory", o.BackupDirectory, "Directory to store backups in. Mutually exclusive with --s3-bucket.")
+	fs.StringVar(&o.S3Bucket, "s3-bucket", o.S3Bucket, "S3 compatible bucket to store backups in, e.g. on AWS S3 or Google Cloud Storage. Credentials are read from AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN, or from AWS_ROLE_ARN and AWS_WEB_IDENTITY_TOKEN_FILE for IAM roles for service accounts.")
+	fs.StringVar(&o.S3Endpoint, "s3-endpoint", o.S3Endpoint, "Endpoint of the S3 compatible service, e.g. https://storage.googleapis.com for Google Cloud Storage with HMAC keys")
+	fs.StringVar(&o.S3Prefix, "s3-prefix", o.S3Prefix, "Prefix of the backup objects in the bucket, e.g. kcp/")
+	fs.StringVar(&o.S3Region, "s3-region", o.S3Region, "Region of the bucket. Google Cloud Storage accepts auto.")
+}
+
+func (o *Options) AddBackupFlags(fs *pflag.FlagSet) {
+	fs.StringToStringVar(&o.Shards, "shard", o.Shards, "Shard name and semicolon separated etcd client URLs of the shard, e.g. root=https://localhost:2379. Can be repeated, one per shard.")
+	fs.StringVar(&o.EtcdCertFile, "etcd-certfile", o.EtcdCertFile, "SSL certification file used to secure etcd communication")
+	fs.StringVar(&o.EtcdKeyFile, "etcd-keyfile", o.EtcdKeyFile, "SSL key file used to secure etcd communication")
+	fs.StringVar(&o.EtcdCAFile, "etcd-cafile", o.EtcdCAFile, "SSL Certificate Authority file used to secure etcd communication")
+	fs.DurationVar(&o.EtcdTimeout, "etcd-dial-timeout", o.EtcdTimeout, "Timeout to connect to etcd")
+	fs.DurationVar(&o.Interval, "interval", o.Interval, "Interval to repeat backups in. If zero, a single backup is taken.")
+	fs.IntVar(&o.Retain, "retain", o.Retain, "Number of complete backups to keep, deleting older ones after each backup. If zero, all backups are kept.")
+}
+
+func (o *Options) AddRestoreFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.RestoreID, "backup-id", o.RestoreID, "ID of the backup to restore. Defaults to the latest backup.")
+	fs.StringVar(&o.RestoreDir, "output-directory", o.RestoreDir, "Directory to download the shard snapshots to")
+	fs.StringToStringVar(&o.RenameShards, "rename-shard", o.RenameShards, "Old and new name of a shard that is renamed on restore, e.g. shard-1=shard-a")
+	fs.BoolVar(&o.RewireShards, "rewire", o.RewireShards, "Rewire the shard index of the restored and running shards to the renamed shards")
+	fs.StringToStringVar(&o.EtcdDataDirectories, "etcd-data-directory", o.EtcdDataDirectories, "Shard name (after renames) and the etcd data directory to restore its snapshot into, e.g. root=.kcp/etcd-server. The directory must not exist or be empty. Can be repeated, one per shard.")
+	fs.StringVar(&o.EtcdName, "etcd-name", o.EtcdName, "Member name of the single member etcd clusters restored into --etcd-data-directory")
+	fs.StringVar(&o.EtcdPeerURL, "etcd-peer-url", o.EtcdPeerURL, "Peer URL of the single member etcd clusters restored into --etcd-data-directory")
+}
+
+func (o *Options) Validate() []error {
+	var errs []error
+
+	if o.BackupDirectory == "" && o.S3Bucket == "" {
+		errs = append(errs, fmt.Errorf("--backup-directory or --s3-bucket is required"))
+	}
+	if o.BackupDirectory != "" && o.S3Bucket != "" {
+		errs = append(errs, fmt.Errorf("--backup-directory and --s3-bucket are mutually exclusive"))
+	}
+	if o.S3Prefix != "" && !strings.HasSuffix(o.S3Prefix, "/") {
+		errs = append(errs, fmt.Errorf("--s3-prefix must end with /"))
+	}
+
+	return errs
+}
+
+func (o *Options) ValidateBackup() []error {
+	errs := o.Validate()
+
+	if o.Kubeconfig == "" {
+		errs = append(errs, fmt.Errorf("--kubeconfig is required"))
+	}
+	if len(o.Shards) == 0 {
+		errs = append(errs, fmt.Errorf("at least one --shard is required"))
+	}
+	if o.Retain < 0 {
+		errs = append(errs, fmt.Errorf("--retain must be non-negative"))
+	}
+
+	return errs
+}
+
+func (o *Options) ValidateRestore() []error {
+	errs := o.Validate()
+
+	if o.RestoreDir == "" && len(o.EtcdDataDirectories) == 0 && o.Kubeconfig == "" {
+		errs = append(errs, fmt.Errorf("--output-directory to download the snapshots, --etcd-data-directory to restore them, or --kubeconfig to rewire the shard index is required"))
+	}
+
+	return errs
+}
+
+// Store returns the object store backups are stored in.
+func (o *Options) Store() (backup.ObjectStore, error) {
+	if o.S3Bucket == "" {
+		return backup.NewFileStore(o.BackupDirectory), nil
+	}
+	return backup.NewS3Store(backup.S3Config{
+		Endpoint:        o.S3Endpoint,
+		Bucket:          o.S3Bucket,
+		Prefix:          o.S3Prefix,
+		Region:          o.S3Region,
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+
+		RoleARN:              os.Getenv("AWS_ROLE_ARN"),
+		WebIdentityTokenFile: os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE"),
+		RoleSessionName:      os.Getenv("AWS_ROLE_SESSION_NAME"),
+	})
+}
+
+// EtcdMember returns the etcd member restored into --etcd-data-directory.
+func (o *Options) EtcdMember() backup.EtcdMember {
+	return backup.EtcdMember{Name: o.EtcdName, PeerURL: o.EtcdPeerURL}
+}
+
+// BackupShards returns the shards to back up with their etcd client configuration.
+func (o *Options) BackupShards() ([]backup.Shard, error) {
+	tlsInfo := transport.TLSInfo{
+		CertFile:      o.EtcdCertFile,
+		KeyFile:       o.EtcdKeyFile,
+		TrustedCAFile: o.EtcdCAFile,
+	}
+	tlsConfig, err := tlsInfo.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	if tlsInfo.Empty() && tlsInfo.TrustedCAFile == "" {
+		tlsConfig = nil
+	}
+
+	var shards []backup.Shard
+	for name, endpoints := range o.Shards {
+		shards = append(shards, backup.Shard{
+			Name: name,
+			Etcd: clientv3.Config{
+				Endpoints:   strings.Split(endpoints, ";"),
+				TLS:         tlsConfig,
+				DialTimeout: o.EtcdTimeout,
+			},
+		})
+	}
+	return shards, nil
+}
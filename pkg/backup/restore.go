@@ -0,0 +1,203 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	kcpclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
+)
+
+// Restore downloads the shard snapshots of the backup with the given id into
+// dir as <shard>.db files, using the new shard name for shards in renames.
+// The snapshot files can be restored into etcd data directories with
+// "etcdutl snapshot restore", e.g. for an external etcd cluster. Use
+// RestoreEtcd to restore directly into the data directories of single member
+// etcd clusters, like the embedded etcd of kcp.
+func Restore(ctx context.Context, store ObjectStore, id, dir string, renames map[string]string) (*Manifest, error) {
+	manifest, err := GetManifest(ctx, store, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	for _, s := range manifest.Shards {
+		name := s.Name
+		if newName, ok := renames[name]; ok {
+			name = newName
+		}
+		if err := download(ctx, store, s.Key, filepath.Join(dir, name+".db")); err != nil {
+			return nil, fmt.Errorf("failed to download snapshot of shard %q: %w", s.Name, err)
+		}
+		klog.Infof("Downloaded snapshot of shard %q at revision %d to %s", s.Name, s.Revision, filepath.Join(dir, name+".db"))
+	}
+
+	return manifest, nil
+}
+
+// RestoreEtcd restores the shard snapshots of the backup with the given id
+// into new etcd data directories of single member clusters. dataDirs maps the
+// shard names, after renames, to the data directories. Shards without a data
+// directory are skipped. The data directories must not exist or be empty,
+// and the shards must be stopped.
+func RestoreEtcd(ctx context.Context, store ObjectStore, id string, dataDirs map[string]string, renames map[string]string, member EtcdMember) (*Manifest, error) {
+	manifest, err := GetManifest(ctx, store, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range manifest.Shards {
+		name := s.Name
+		if newName, ok := renames[name]; ok {
+			name = newName
+		}
+		dataDir, ok := dataDirs[name]
+		if !ok {
+			klog.Infof("Skipping shard %q without etcd data directory", name)
+			continue
+		}
+
+		if err := restoreShard(ctx, store, s, dataDir, member); err != nil {
+			return nil, fmt.Errorf("failed to restore snapshot of shard %q: %w", s.Name, err)
+		}
+		klog.Infof("Restored snapshot of shard %q at revision %d into %s", s.Name, s.Revision, dataDir)
+	}
+
+	return manifest, nil
+}
+
+func restoreShard(ctx context.Context, store ObjectStore, s ShardSnapshot, dataDir string, member EtcdMember) error {
+	tmpDir, err := ioutil.TempDir("", "kcp-restore-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir) // nolint:errcheck
+
+	snapshot := filepath.Join(tmpDir, "snapshot.db")
+	if err := download(ctx, store, s.Key, snapshot); err != nil {
+		return err
+	}
+	return restoreSnapshot(snapshot, dataDir, member)
+}
+
+func download(ctx context.Context, store ObjectStore, key, filename string) error {
+	rc, err := store.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, rc); err != nil {
+		f.Close() // nolint:errcheck
+		return err
+	}
+	return f.Close()
+}
+
+// Rewire updates the shard index of restored shards for renamed shards: the
+// ClusterWorkspaceShard objects of the manifest are recreated under their
+// new name, workspaces placed onto a renamed shard are moved to the new name,
+// and only then the old ClusterWorkspaceShards are deleted. Deleting them
+// earlier would make the workspace controller unschedule or invalidate the
+// workspaces still placed onto them. Rewire is idempotent and can be
+// repeated after failures.
+func Rewire(ctx context.Context, kcpClusterClient kcpclient.ClusterInterface, manifest *Manifest, renames map[string]string) error {
+	if len(renames) == 0 {
+		return nil
+	}
+
+	shardClient := kcpClusterClient.Cluster(tenancyv1alpha1.RootCluster).TenancyV1alpha1().ClusterWorkspaceShards()
+	for _, shard := range manifest.ClusterWorkspaceShards {
+		newName, ok := renames[shard.Name]
+		if !ok {
+			continue
+		}
+		renamed := &tenancyv1alpha1.ClusterWorkspaceShard{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        newName,
+				Labels:      shard.Labels,
+				Annotations: shard.Annotations,
+			},
+			Spec: shard.Spec,
+		}
+		if _, err := shardClient.Create(ctx, renamed, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create ClusterWorkspaceShard %q: %w", newName, err)
+		}
+	}
+
+	clusters := make([]string, 0, len(manifest.Locations))
+	for cluster := range manifest.Locations {
+		clusters = append(clusters, cluster)
+	}
+	sort.Strings(clusters)
+	for _, cluster := range clusters {
+		newName, ok := renames[manifest.Locations[cluster]]
+		if !ok {
+			continue
+		}
+		parent, name := logicalcluster.New(cluster).Split()
+		workspaceClient := kcpClusterClient.Cluster(parent).TenancyV1alpha1().ClusterWorkspaces()
+		ws, err := workspaceClient.Get(ctx, name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		if ws.Status.Location.Current == newName {
+			continue
+		}
+		if _, ok := renames[ws.Status.Location.Target]; ok {
+			ws.Status.Location.Target = newName
+		}
+		ws.Status.Location.Current = newName
+		if _, err := workspaceClient.UpdateStatus(ctx, ws, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to move workspace %q to shard %q: %w", cluster, newName, err)
+		}
+	}
+
+	for _, shard := range manifest.ClusterWorkspaceShards {
+		newName, ok := renames[shard.Name]
+		if !ok {
+			continue
+		}
+		if err := shardClient.Delete(ctx, shard.Name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete ClusterWorkspaceShard %q: %w", shard.Name, err)
+		}
+		klog.Infof("Renamed ClusterWorkspaceShard %q to %q", shard.Name, newName)
+	}
+
+	return nil
+}
@@ -0,0 +1,594 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// S3Config configures an S3 compatible object store, e.g. AWS S3, or Google
+// Cloud Storage through its XML API with HMAC keys.
+type S3Config struct {
+	// Endpoint is the base URL of the service, e.g. https://s3.eu-west-1.amazonaws.com
+	// or https://storage.googleapis.com. Buckets are addressed path-style.
+	Endpoint string
+	// Bucket is the name of the bucket.
+	Bucket string
+	// Prefix is prepended to all keys, e.g. "kcp/".
+	Prefix string
+	// Region is the region of the bucket, used for request signing. Google Cloud
+	// Storage accepts "auto".
+	Region string
+
+	AccessKeyID     string
+	SecretAccessKey string
+	// SessionToken is the session token of temporary credentials, e.g. of an
+	// assumed role.
+	SessionToken string
+
+	// RoleARN and WebIdentityTokenFile configure web identity federation, e.g.
+	// IAM roles for service accounts on EKS. If set, temporary credentials are
+	// requested from STS with the token in the file, and refreshed before they
+	// expire. The static keys are ignored then.
+	RoleARN              string
+	WebIdentityTokenFile string
+	// RoleSessionName defaults to "kcp-backup".
+	RoleSessionName string
+	// STSEndpoint defaults to https://sts.amazonaws.com.
+	STSEndpoint string
+
+	// Client defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+const (
+	// s3PartSize is the size of the parts of multipart uploads. Objects up to that
+	// size are uploaded with a single request.
+	s3PartSize = 64 << 20
+	// s3MaxParts is the maximum number of parts of a multipart upload, limiting
+	// objects to 640 GiB.
+	s3MaxParts = 10000
+)
+
+// NewS3Store returns an ObjectStore storing objects in an S3 compatible bucket.
+// Objects larger than 64 MiB are uploaded in parts, up to 640 GiB.
+func NewS3Store(cfg S3Config) (ObjectStore, error) {
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint %q: %w", cfg.Endpoint, err)
+	}
+	if endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid endpoint %q: scheme and host required", cfg.Endpoint)
+	}
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("bucket required")
+	}
+	if cfg.Client == nil {
+		cfg.Client = http.DefaultClient
+	}
+
+	s := &s3Store{cfg: cfg, endpoint: endpoint, now: time.Now, partSize: s3PartSize}
+	switch {
+	case cfg.RoleARN != "" && cfg.WebIdentityTokenFile != "":
+		if cfg.RoleSessionName == "" {
+			cfg.RoleSessionName = "kcp-backup"
+		}
+		if cfg.STSEndpoint == "" {
+			cfg.STSEndpoint = "https://sts.amazonaws.com"
+		}
+		w := &webIdentityCredentials{cfg: cfg, now: func() time.Time { return s.now() }}
+		s.credentials = w.get
+	case cfg.RoleARN != "" || cfg.WebIdentityTokenFile != "":
+		return nil, fmt.Errorf("role ARN and web identity token file must be set together")
+	default:
+		s.credentials = func(context.Context) (s3Credentials, error) {
+			return s3Credentials{AccessKeyID: cfg.AccessKeyID, SecretAccessKey: cfg.SecretAccessKey, SessionToken: cfg.SessionToken}, nil
+		}
+	}
+	return s, nil
+}
+
+type s3Store struct {
+	cfg         S3Config
+	endpoint    *url.URL
+	now         func() time.Time
+	partSize    int64
+	credentials func(ctx context.Context) (s3Credentials, error)
+}
+
+type s3Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+var _ ObjectStore = &s3Store{}
+
+const emptySHA256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+func (s *s3Store) Put(ctx context.Context, key string, r io.Reader) error {
+	// spool to disk part by part, every request needs the length and hash of its content
+	f, err := ioutil.TempFile("", "kcp-backup-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name()) // nolint:errcheck
+	defer f.Close()
+
+	br := bufio.NewReader(r)
+	size, hash, err := spool(f, br, s.partSize)
+	if err != nil {
+		return err
+	}
+	if done, err := drained(br); err != nil {
+		return err
+	} else if done {
+		_, err := s.putObject(ctx, key, nil, f, size, hash)
+		return err
+	}
+
+	uploadID, err := s.createMultipartUpload(ctx, key)
+	if err != nil {
+		return err
+	}
+	if err := s.uploadParts(ctx, key, uploadID, f, br, size, hash); err != nil {
+		if abortErr := s.abortMultipartUpload(ctx, key, uploadID); abortErr != nil {
+			return fmt.Errorf("%w, and failed to abort the upload: %v", err, abortErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// spool copies up to limit bytes of r into f, and returns their size and hash
+// with f rewound.
+func spool(f *os.File, r io.Reader, limit int64) (int64, string, error) {
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return 0, "", err
+	}
+	if err := f.Truncate(0); err != nil {
+		return 0, "", err
+	}
+	h := sha256.New()
+	size, err := io.CopyN(io.MultiWriter(f, h), r, limit)
+	if err != nil && err != io.EOF {
+		return 0, "", err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return 0, "", err
+	}
+	return size, hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func drained(r *bufio.Reader) (bool, error) {
+	if _, err := r.Peek(1); err == io.EOF {
+		return true, nil
+	} else if err != nil {
+		return false, err
+	}
+	return false, nil
+}
+
+// putObject uploads the content of f as the object of the key, or as a part of a
+// multipart upload with the partNumber and uploadId query, and returns its ETag.
+func (s *s3Store) putObject(ctx context.Context, key string, query url.Values, f *os.File, size int64, hash string) (string, error) {
+	req, err := s.newRequest(ctx, http.MethodPut, key, query, ioutil.NopCloser(f), hash)
+	if err != nil {
+		return "", err
+	}
+	req.ContentLength = size
+	resp, err := s.do(req, http.StatusOK)
+	if err != nil {
+		return "", err
+	}
+	return resp.Header.Get("ETag"), resp.Body.Close()
+}
+
+type initiateMultipartUploadResult struct {
+	UploadID string `xml:"UploadId"`
+}
+
+type completeMultipartUpload struct {
+	XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+	Parts   []completedPart `xml:"Part"`
+}
+
+type completedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+func (s *s3Store) createMultipartUpload(ctx context.Context, key string) (string, error) {
+	req, err := s.newRequest(ctx, http.MethodPost, key, url.Values{"uploads": {""}}, nil, emptySHA256)
+	if err != nil {
+		return "", err
+	}
+	resp, err := s.do(req, http.StatusOK)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	var result initiateMultipartUploadResult
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode multipart upload of %s: %w", key, err)
+	}
+	if result.UploadID == "" {
+		return "", fmt.Errorf("no upload ID for multipart upload of %s", key)
+	}
+	return result.UploadID, nil
+}
+
+// uploadParts uploads the first part spooled into f, and the rest of r, and
+// completes the multipart upload.
+func (s *s3Store) uploadParts(ctx context.Context, key, uploadID string, f *os.File, r *bufio.Reader, size int64, hash string) error {
+	complete := completeMultipartUpload{}
+	for partNumber := 1; ; partNumber++ {
+		if partNumber > s3MaxParts {
+			return fmt.Errorf("%s exceeds %d parts of %d bytes", key, s3MaxParts, s.partSize)
+		}
+		if partNumber > 1 {
+			var err error
+			if size, hash, err = spool(f, r, s.partSize); err != nil {
+				return err
+			}
+		}
+		query := url.Values{"partNumber": {strconv.Itoa(partNumber)}, "uploadId": {uploadID}}
+		etag, err := s.putObject(ctx, key, query, f, size, hash)
+		if err != nil {
+			return err
+		}
+		complete.Parts = append(complete.Parts, completedPart{PartNumber: partNumber, ETag: etag})
+
+		if done, err := drained(r); err != nil {
+			return err
+		} else if done {
+			break
+		}
+	}
+
+	body, err := xml.Marshal(complete)
+	if err != nil {
+		return err
+	}
+	req, err := s.newRequest(ctx, http.MethodPost, key, url.Values{"uploadId": {uploadID}}, ioutil.NopCloser(bytes.NewReader(body)), hexSHA256(string(body)))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = int64(len(body))
+	resp, err := s.do(req, http.StatusOK)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// errors of completing an upload can come with 200 OK
+	var result struct {
+		XMLName xml.Name
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode completion of multipart upload of %s: %w", key, err)
+	}
+	if result.XMLName.Local == "Error" {
+		return fmt.Errorf("failed to complete multipart upload of %s: %s: %s", key, result.Code, result.Message)
+	}
+	return nil
+}
+
+func (s *s3Store) abortMultipartUpload(ctx context.Context, key, uploadID string) error {
+	req, err := s.newRequest(ctx, http.MethodDelete, key, url.Values{"uploadId": {uploadID}}, nil, emptySHA256)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(req, http.StatusNoContent, http.StatusOK, http.StatusNotFound)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (s *s3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil, nil, emptySHA256)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.do(req, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (s *s3Store) Delete(ctx context.Context, key string) error {
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil, nil, emptySHA256)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(req, http.StatusNoContent, http.StatusOK, http.StatusNotFound)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+type listBucketResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+func (s *s3Store) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {s.cfg.Prefix + prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		req, err := s.newRequest(ctx, http.MethodGet, "", query, nil, emptySHA256)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := s.do(req, http.StatusOK)
+		if err != nil {
+			return nil, err
+		}
+		var result listBucketResult
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close() // nolint:errcheck
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode bucket listing: %w", err)
+		}
+
+		for _, c := range result.Contents {
+			keys = append(keys, strings.TrimPrefix(c.Key, s.cfg.Prefix))
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			break
+		}
+		token = result.NextContinuationToken
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// newRequest returns a signed request for the given key, or for the bucket if
+// key is empty.
+func (s *s3Store) newRequest(ctx context.Context, method, key string, query url.Values, body io.ReadCloser, payloadHash string) (*http.Request, error) {
+	u := *s.endpoint
+	p := strings.TrimSuffix(u.Path, "/") + "/" + s.cfg.Bucket
+	if key != "" {
+		p += "/" + s.cfg.Prefix + key
+	}
+	u.Path = p
+	u.RawPath = uriEncode(p, false)
+	u.RawQuery = canonicalQuery(query)
+
+	creds, err := s.credentials(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credentials: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+	signV4(req, creds.AccessKeyID, creds.SecretAccessKey, s.cfg.Region, "s3", payloadHash, s.now())
+	return req, nil
+}
+
+func (s *s3Store) do(req *http.Request, expected ...int) (*http.Response, error) {
+	resp, err := s.cfg.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	for _, code := range expected {
+		if resp.StatusCode == code {
+			return resp, nil
+		}
+	}
+	defer resp.Body.Close()
+	msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+	return nil, fmt.Errorf("%s %s failed with %s: %s", req.Method, req.URL.Path, resp.Status, strings.TrimSpace(string(msg)))
+}
+
+// webIdentityCredentials are temporary credentials of a role, assumed with a web
+// identity token, e.g. of a Kubernetes service account.
+type webIdentityCredentials struct {
+	cfg S3Config
+	now func() time.Time
+
+	lock       sync.Mutex
+	creds      s3Credentials
+	expiration time.Time
+}
+
+// webIdentityRefresh is how long before their expiration credentials are refreshed.
+const webIdentityRefresh = 5 * time.Minute
+
+type assumeRoleWithWebIdentityResponse struct {
+	Credentials struct {
+		AccessKeyID     string    `xml:"AccessKeyId"`
+		SecretAccessKey string    `xml:"SecretAccessKey"`
+		SessionToken    string    `xml:"SessionToken"`
+		Expiration      time.Time `xml:"Expiration"`
+	} `xml:"AssumeRoleWithWebIdentityResult>Credentials"`
+}
+
+func (w *webIdentityCredentials) get(ctx context.Context) (s3Credentials, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.now().Before(w.expiration.Add(-webIdentityRefresh)) {
+		return w.creds, nil
+	}
+
+	// the token is rotated, read it every time
+	token, err := ioutil.ReadFile(w.cfg.WebIdentityTokenFile)
+	if err != nil {
+		return s3Credentials{}, err
+	}
+	form := url.Values{
+		"Action":           {"AssumeRoleWithWebIdentity"},
+		"Version":          {"2011-06-15"},
+		"RoleArn":          {w.cfg.RoleARN},
+		"RoleSessionName":  {w.cfg.RoleSessionName},
+		"WebIdentityToken": {strings.TrimSpace(string(token))},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.cfg.STSEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return s3Credentials{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := w.cfg.Client.Do(req)
+	if err != nil {
+		return s3Credentials{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return s3Credentials{}, fmt.Errorf("assuming role %s failed with %s: %s", w.cfg.RoleARN, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	var result assumeRoleWithWebIdentityResponse
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return s3Credentials{}, fmt.Errorf("failed to decode credentials of role %s: %w", w.cfg.RoleARN, err)
+	}
+
+	w.creds = s3Credentials{
+		AccessKeyID:     result.Credentials.AccessKeyID,
+		SecretAccessKey: result.Credentials.SecretAccessKey,
+		SessionToken:    result.Credentials.SessionToken,
+	}
+	w.expiration = result.Credentials.Expiration
+	return w.creds, nil
+}
+
+// signV4 adds AWS signature version 4 headers to req, signing the host, the
+// x-amz-* headers and all headers already set on req.
+func signV4(req *http.Request, accessKeyID, secretAccessKey, region, service, payloadHash string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hexSHA256(canonicalRequest),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+secretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", accessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data)) // nolint:errcheck
+	return h.Sum(nil)
+}
+
+func hexSHA256(data string) string {
+	h := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(h[:])
+}
+
+// canonicalQuery encodes query sorted by key, as required for signing.
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		for _, v := range query[k] {
+			parts = append(parts, uriEncode(k, true)+"="+uriEncode(v, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// uriEncode percent-encodes everything but unreserved characters, and slashes
+// unless encodeSlash is set.
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
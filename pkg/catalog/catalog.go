@@ -0,0 +1,146 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	apisinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/apis/v1alpha1"
+)
+
+// Path is where the Catalog serves the service provider catalog of the
+// workspace of the request.
+const Path = "/catalog"
+
+// Catalog lists the CatalogEntries of the workspaces in the same organization
+// as the workspace of the request, i.e. of all workspaces an APIBinding in the
+// workspace can reference. Entries are only listed if the user is allowed to
+// bind to their APIExport.
+type Catalog struct {
+	listCatalogEntries func() ([]*apisv1alpha1.CatalogEntry, error)
+	authorizer         authorizer.Authorizer
+}
+
+// NewCatalog returns a Catalog serving the CatalogEntries of the informer,
+// filtered by the given authorizer.
+func NewCatalog(catalogEntryInformer apisinformers.CatalogEntryInformer, authz authorizer.Authorizer) *Catalog {
+	return &Catalog{
+		listCatalogEntries: func() ([]*apisv1alpha1.CatalogEntry, error) {
+			return catalogEntryInformer.Lister().List(labels.Everything())
+		},
+		authorizer: authz,
+	}
+}
+
+// Entries returns the CatalogEntries visible to the user in the given workspace,
+// sorted by workspace and name.
+func (c *Catalog) Entries(ctx context.Context, workspace logicalcluster.Name, u user.Info) ([]apisv1alpha1.CatalogEntry, error) {
+	org, hasParent := workspace.Parent()
+	if !hasParent {
+		return nil, fmt.Errorf("%q has no catalog", workspace)
+	}
+
+	entries, err := c.listCatalogEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	ret := []apisv1alpha1.CatalogEntry{}
+	for _, entry := range entries {
+		providerClusterName := logicalcluster.From(entry)
+		if parent, ok := providerClusterName.Parent(); !ok || parent != org {
+			continue
+		}
+
+		allowed, err := c.canBind(ctx, providerClusterName, entry.Spec.ExportName, u)
+		if err != nil {
+			return nil, err
+		}
+		if allowed {
+			ret = append(ret, *entry)
+		}
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		if ci, cj := logicalcluster.From(&ret[i]), logicalcluster.From(&ret[j]); ci != cj {
+			return ci.String() < cj.String()
+		}
+		return ret[i].Name < ret[j].Name
+	})
+
+	return ret, nil
+}
+
+// canBind checks the same permission the APIBinding admission requires to bind
+// to the APIExport.
+func (c *Catalog) canBind(ctx context.Context, clusterName logicalcluster.Name, exportName string, u user.Info) (bool, error) {
+	ctx = genericapirequest.WithCluster(ctx, genericapirequest.Cluster{Name: clusterName})
+	decision, _, err := c.authorizer.Authorize(ctx, authorizer.AttributesRecord{
+		User:            u,
+		Verb:            "bind",
+		APIGroup:        apisv1alpha1.SchemeGroupVersion.Group,
+		APIVersion:      apisv1alpha1.SchemeGroupVersion.Version,
+		Resource:        "apiexports",
+		Name:            exportName,
+		ResourceRequest: true,
+	})
+	if err != nil {
+		return false, fmt.Errorf("unable to determine access to apiexport %s|%s: %w", clusterName, exportName, err)
+	}
+	return decision == authorizer.DecisionAllow, nil
+}
+
+// ServeHTTP serves the catalog of the workspace of the request as CatalogEntryList.
+func (c *Catalog) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	cluster := genericapirequest.ClusterFrom(req.Context())
+	if cluster == nil || cluster.Name.Empty() || cluster.Wildcard {
+		http.Error(w, "the catalog is only available for a single workspace", http.StatusBadRequest)
+		return
+	}
+	u, ok := genericapirequest.UserFrom(req.Context())
+	if !ok {
+		http.Error(w, "no user found for request", http.StatusUnauthorized)
+		return
+	}
+
+	entries, err := c.Entries(req.Context(), cluster.Name, u)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	list := &apisv1alpha1.CatalogEntryList{Items: entries}
+	list.APIVersion = apisv1alpha1.SchemeGroupVersion.String()
+	list.Kind = "CatalogEntryList"
+
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(list)
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package catalog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+)
+
+func TestEntries(t *testing.T) {
+	entry := func(clusterName, name string) *apisv1alpha1.CatalogEntry {
+		return &apisv1alpha1.CatalogEntry{
+			ObjectMeta: metav1.ObjectMeta{Name: name, ClusterName: clusterName},
+			Spec:       apisv1alpha1.CatalogEntrySpec{ExportName: name},
+		}
+	}
+	entries := []*apisv1alpha1.CatalogEntry{
+		entry("root:org:provider2", "widgets"),
+		entry("root:org:provider1", "gadgets"),
+		entry("root:org:provider1", "secret-sauce"),
+		entry("root:other:provider", "others"),
+		entry("root:org:team:nested", "nested"),
+	}
+
+	c := &Catalog{
+		listCatalogEntries: func() ([]*apisv1alpha1.CatalogEntry, error) {
+			return entries, nil
+		},
+		authorizer: authorizer.AuthorizerFunc(func(ctx context.Context, attr authorizer.Attributes) (authorizer.Decision, string, error) {
+			cluster := genericapirequest.ClusterFrom(ctx)
+			require.NotNil(t, cluster)
+			require.Equal(t, "bind", attr.GetVerb())
+			require.Equal(t, "apiexports", attr.GetResource())
+			if attr.GetName() == "secret-sauce" && attr.GetUser().GetName() != "chef" {
+				return authorizer.DecisionNoOpinion, "", nil
+			}
+			return authorizer.DecisionAllow, "", nil
+		}),
+	}
+
+	tests := map[string]struct {
+		workspace string
+		user      string
+		want      []string
+	}{
+		"lists entries of sibling workspaces the user can bind to": {
+			workspace: "root:org:team",
+			user:      "alice",
+			want:      []string{"root:org:provider1|gadgets", "root:org:provider2|widgets"},
+		},
+		"lists entries the user is allowed to bind to": {
+			workspace: "root:org:team",
+			user:      "chef",
+			want:      []string{"root:org:provider1|gadgets", "root:org:provider1|secret-sauce", "root:org:provider2|widgets"},
+		},
+		"lists entries of the organization of the workspace only": {
+			workspace: "root:other:team",
+			user:      "alice",
+			want:      []string{"root:other:provider|others"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := c.Entries(context.Background(), logicalcluster.New(tt.workspace), &user.DefaultInfo{Name: tt.user})
+			require.NoError(t, err)
+
+			names := make([]string, 0, len(got))
+			for i := range got {
+				names = append(names, logicalcluster.From(&got[i]).String()+"|"+got[i].Name)
+			}
+			require.Equal(t, tt.want, names)
+		})
+	}
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificates
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/acme"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kcp-dev/kcp/pkg/dns"
+)
+
+// ACMEIssuer issues certificates through an ACME server, e.g. Let's Encrypt, solving
+// DNS-01 challenges with TXT records programmed through the DNS providers of the domains.
+type ACMEIssuer struct {
+	client *acme.Client
+	email  string
+
+	// propagationDelay is waited for after programming the challenge records, for DNS
+	// providers that publish records eventually.
+	propagationDelay time.Duration
+
+	lock       sync.Mutex
+	registered bool
+}
+
+// NewACMEIssuer returns an issuer for the ACME server of the directory URL, using the
+// account of the key. The account is registered with the email, if any, on first use.
+func NewACMEIssuer(directoryURL string, key crypto.Signer, email string, propagationDelay time.Duration) *ACMEIssuer {
+	return &ACMEIssuer{
+		client: &acme.Client{
+			Key:          key,
+			DirectoryURL: directoryURL,
+			UserAgent:    "kcp",
+		},
+		email:            email,
+		propagationDelay: propagationDelay,
+	}
+}
+
+func (i *ACMEIssuer) register(ctx context.Context) error {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	if i.registered {
+		return nil
+	}
+
+	account := &acme.Account{}
+	if i.email != "" {
+		account.Contact = []string{"mailto:" + i.email}
+	}
+	if _, err := i.client.Register(ctx, account, acme.AcceptTOS); err != nil && !errors.Is(err, acme.ErrAccountAlreadyExists) {
+		return fmt.Errorf("failed to register ACME account: %w", err)
+	}
+	i.registered = true
+	return nil
+}
+
+func (i *ACMEIssuer) Issue(ctx context.Context, csr []byte, domains []Domain) ([][]byte, error) {
+	if err := i.register(ctx); err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]Domain, len(domains))
+	names := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		byName[domain.Name] = domain
+		names = append(names, domain.Name)
+	}
+
+	order, err := i.client.AuthorizeOrder(ctx, acme.DomainIDs(names...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ACME order: %w", err)
+	}
+
+	var pending []*acme.Authorization
+	for _, u := range order.AuthzURLs {
+		authz, err := i.client.GetAuthorization(ctx, u)
+		if err != nil {
+			return nil, err
+		}
+		if authz.Status != acme.StatusValid {
+			pending = append(pending, authz)
+		}
+	}
+
+	// program all challenge records first, and remove them when done, successful or not
+	var challenges []*acme.Challenge
+	for _, authz := range pending {
+		domain, found := byName[authz.Identifier.Value]
+		if !found {
+			return nil, fmt.Errorf("unexpected ACME authorization for %q", authz.Identifier.Value)
+		}
+		var challenge *acme.Challenge
+		for _, c := range authz.Challenges {
+			if c.Type == "dns-01" {
+				challenge = c
+				break
+			}
+		}
+		if challenge == nil {
+			return nil, fmt.Errorf("no dns-01 challenge offered for %q", domain.Name)
+		}
+
+		value, err := i.client.DNS01ChallengeRecord(challenge.Token)
+		if err != nil {
+			return nil, err
+		}
+		record := dns.Record{
+			Zone:    domain.Zone,
+			Name:    "_acme-challenge." + domain.Name,
+			Type:    "TXT",
+			Targets: []string{value},
+			TTL:     60,
+		}
+		if err := domain.Provider.Ensure(ctx, record); err != nil {
+			return nil, fmt.Errorf("failed to program challenge record %q: %w", record.Name, err)
+		}
+		defer func() {
+			if err := domain.Provider.Delete(context.Background(), record); err != nil {
+				klog.Warningf("Failed to remove challenge record %q: %v", record.Name, err)
+			}
+		}()
+		challenges = append(challenges, challenge)
+	}
+
+	if len(challenges) > 0 && i.propagationDelay > 0 {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(i.propagationDelay):
+		}
+	}
+
+	for j, challenge := range challenges {
+		if _, err := i.client.Accept(ctx, challenge); err != nil {
+			return nil, fmt.Errorf("failed to accept challenge for %q: %w", pending[j].Identifier.Value, err)
+		}
+	}
+	for _, authz := range pending {
+		if _, err := i.client.WaitAuthorization(ctx, authz.URI); err != nil {
+			return nil, fmt.Errorf("failed to authorize %q: %w", authz.Identifier.Value, err)
+		}
+	}
+
+	order, err = i.client.WaitOrder(ctx, order.URI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wait for ACME order: %w", err)
+	}
+	der, _, err := i.client.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to finalize ACME order: %w", err)
+	}
+	return der, nil
+}
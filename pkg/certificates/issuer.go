@@ -0,0 +1,41 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificates
+
+import (
+	"context"
+
+	"github.com/kcp-dev/kcp/pkg/dns"
+)
+
+// Domain is a DNS name of a certificate, with the zone and the DNS provider in which
+// challenges for the name are solved.
+type Domain struct {
+	// Name is the DNS name, e.g. shop.apps.example.com.
+	Name string
+	// Zone is the domain of the DNSZone of the name, e.g. apps.example.com.
+	Zone string
+	// Provider programs the records of the zone.
+	Provider dns.Provider
+}
+
+// Issuer issues certificates for DNS names.
+type Issuer interface {
+	// Issue returns the DER encoded certificate chain, leaf first, for the DER encoded
+	// certificate request of the domains.
+	Issue(ctx context.Context, csr []byte, domains []Domain) ([][]byte, error)
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/kcp-dev/kcp/pkg/certs"
+)
+
+// CertificateSecrets configures secrets whose keys are mirrored into files,
+// such that certificate flags can point to them and pick up rotations done
+// through the secrets.
+type CertificateSecrets struct {
+	Secrets        []string
+	KubeconfigFile string
+}
+
+func NewCertificateSecrets() *CertificateSecrets {
+	return &CertificateSecrets{}
+}
+
+func (o *CertificateSecrets) AddFlags(fs *pflag.FlagSet) {
+	fs.StringSliceVar(&o.Secrets, "certificate-secret", o.Secrets, ""+
+		"A secret of the form <namespace>/<name>=<directory>, whose keys (e.g. tls.crt, tls.key, ca.crt) are written into the directory "+
+		"before start and kept up to date. Point certificate and CA flags to these files to rotate them through the secret.")
+	fs.StringVar(&o.KubeconfigFile, "certificate-secret-kubeconfig", o.KubeconfigFile, ""+
+		"Kubeconfig of the cluster holding the --certificate-secret secrets. In-cluster configuration is used if empty.")
+}
+
+func (o *CertificateSecrets) Validate() []error {
+	var errs []error
+
+	for _, s := range o.Secrets {
+		if _, err := certs.ParseSecretDirectory(s); err != nil {
+			errs = append(errs, fmt.Errorf("invalid --certificate-secret: %w", err))
+		}
+	}
+
+	return errs
+}
+
+// Sync writes the secrets into their directories, and keeps them up to date
+// until ctx is done. It is a no-op if no secrets are configured.
+func (o *CertificateSecrets) Sync(ctx context.Context) error {
+	if len(o.Secrets) == 0 {
+		return nil
+	}
+
+	secrets := make([]certs.SecretDirectory, 0, len(o.Secrets))
+	for _, s := range o.Secrets {
+		secret, err := certs.ParseSecretDirectory(s)
+		if err != nil {
+			return err
+		}
+		secrets = append(secrets, secret)
+	}
+
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = o.KubeconfigFile
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, nil).ClientConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load --certificate-secret-kubeconfig: %w", err)
+	}
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	return certs.SyncSecrets(ctx, client, secrets)
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// SecretDirectory is a Secret whose keys are mirrored into files of Directory,
+// e.g. tls.crt, tls.key and ca.crt.
+type SecretDirectory struct {
+	Namespace string
+	Name      string
+	Directory string
+}
+
+// ParseSecretDirectory parses <namespace>/<name>=<directory>.
+func ParseSecretDirectory(s string) (SecretDirectory, error) {
+	parts := strings.SplitN(s, "=", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return SecretDirectory{}, fmt.Errorf("%q must be of the form <namespace>/<name>=<directory>", s)
+	}
+	secret := strings.SplitN(parts[0], "/", 2)
+	if len(secret) != 2 || secret[0] == "" || secret[1] == "" {
+		return SecretDirectory{}, fmt.Errorf("%q must be of the form <namespace>/<name>=<directory>", s)
+	}
+	return SecretDirectory{Namespace: secret[0], Name: secret[1], Directory: parts[1]}, nil
+}
+
+func (s SecretDirectory) String() string {
+	return fmt.Sprintf("%s/%s=%s", s.Namespace, s.Name, s.Directory)
+}
+
+// SyncSecrets writes the keys of the given secrets into their directories and
+// returns once all of them have been written. The files are kept up to date
+// in the background until ctx is done.
+//
+// Certificate and CA files of the servers are watched on disk, hence pointing
+// them to these directories rotates certificates stored in secrets without
+// restart. Files are replaced atomically.
+func SyncSecrets(ctx context.Context, client kubernetes.Interface, secrets []SecretDirectory) error {
+	for _, s := range secrets {
+		if err := os.MkdirAll(s.Directory, 0700); err != nil {
+			return err
+		}
+
+		s := s
+		written := make(chan struct{})
+		var once sync.Once
+		lw := cache.NewListWatchFromClient(client.CoreV1().RESTClient(), "secrets", s.Namespace, fields.OneTermEqualSelector("metadata.name", s.Name))
+		_, informer := cache.NewInformer(lw, &corev1.Secret{}, 0, cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				if err := writeSecret(s.Directory, obj.(*corev1.Secret)); err != nil {
+					klog.Errorf("Failed to write secret %s: %v", s, err)
+					return
+				}
+				once.Do(func() { close(written) })
+			},
+			UpdateFunc: func(_, obj interface{}) {
+				if err := writeSecret(s.Directory, obj.(*corev1.Secret)); err != nil {
+					klog.Errorf("Failed to write secret %s: %v", s, err)
+				}
+			},
+		})
+		go informer.Run(ctx.Done())
+
+		if !cache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+			return ctx.Err()
+		}
+		select {
+		case <-written:
+		default:
+			return fmt.Errorf("failed to write secret %s: not found or invalid", s)
+		}
+	}
+	return nil
+}
+
+func writeSecret(dir string, secret *corev1.Secret) error {
+	for key, value := range secret.Data {
+		path := filepath.Join(dir, key)
+		if existing, err := ioutil.ReadFile(path); err == nil && bytes.Equal(existing, value) {
+			continue
+		}
+
+		tmp, err := ioutil.TempFile(dir, "."+key)
+		if err != nil {
+			return err
+		}
+		if _, err := tmp.Write(value); err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+			return err
+		}
+		if err := tmp.Close(); err != nil {
+			os.Remove(tmp.Name())
+			return err
+		}
+		if err := os.Rename(tmp.Name(), path); err != nil {
+			os.Remove(tmp.Name())
+			return err
+		}
+		klog.V(2).Infof("Updated %s from secret %s/%s", path, secret.Namespace, secret.Name)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certs
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseSecretDirectory(t *testing.T) {
+	s, err := ParseSecretDirectory("kcp/serving-cert=/etc/kcp/tls")
+	require.NoError(t, err)
+	require.Equal(t, SecretDirectory{Namespace: "kcp", Name: "serving-cert", Directory: "/etc/kcp/tls"}, s)
+
+	for _, invalid := range []string{"", "kcp/serving-cert", "serving-cert=/etc/kcp/tls", "/serving-cert=/etc", "kcp/=/etc", "kcp/serving-cert="} {
+		_, err := ParseSecretDirectory(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestWriteSecret(t *testing.T) {
+	dir := t.TempDir()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kcp", Name: "serving-cert"},
+		Data: map[string][]byte{
+			"tls.crt": []byte("cert"),
+			"tls.key": []byte("key"),
+		},
+	}
+	require.NoError(t, writeSecret(dir, secret))
+
+	secret.Data["tls.crt"] = []byte("rotated")
+	require.NoError(t, writeSecret(dir, secret))
+
+	crt, err := ioutil.ReadFile(filepath.Join(dir, "tls.crt"))
+	require.NoError(t, err)
+	require.Equal(t, "rotated", string(crt))
+	key, err := ioutil.ReadFile(filepath.Join(dir, "tls.key"))
+	require.NoError(t, err)
+	require.Equal(t, "key", string(key))
+
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 2, "no temporary files should be left behind")
+}
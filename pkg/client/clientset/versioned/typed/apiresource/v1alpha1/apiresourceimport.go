@@ -20,6 +20,8 @@ package v1alpha1
 
 import (
 	"context"
+	json "encoding/json"
+	"fmt"
 	"time"
 
 	logicalcluster "github.com/kcp-dev/logicalcluster"
@@ -30,6 +32,7 @@ import (
 	rest "k8s.io/client-go/rest"
 
 	v1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apiresource/v1alpha1"
+	apiresourcev1alpha1 "github.com/kcp-dev/kcp/pkg/client/applyconfiguration/apiresource/v1alpha1"
 	scheme "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/scheme"
 )
 
@@ -50,6 +53,8 @@ type APIResourceImportInterface interface {
 	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.APIResourceImportList, error)
 	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
 	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.APIResourceImport, err error)
+	Apply(ctx context.Context, aPIResourceImport *apiresourcev1alpha1.APIResourceImportApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.APIResourceImport, err error)
+	ApplyStatus(ctx context.Context, aPIResourceImport *apiresourcev1alpha1.APIResourceImportApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.APIResourceImport, err error)
 	APIResourceImportExpansion
 }
 
@@ -196,3 +201,59 @@ func (c *aPIResourceImports) Patch(ctx context.Context, name string, pt types.Pa
 		Into(result)
 	return
 }
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied aPIResourceImport.
+func (c *aPIResourceImports) Apply(ctx context.Context, aPIResourceImport *apiresourcev1alpha1.APIResourceImportApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.APIResourceImport, err error) {
+	if aPIResourceImport == nil {
+		return nil, fmt.Errorf("aPIResourceImport provided to Apply must not be nil")
+	}
+	patchOpts := opts.ToPatchOptions()
+	data, err := json.Marshal(aPIResourceImport)
+	if err != nil {
+		return nil, err
+	}
+	name := aPIResourceImport.Name
+	if name == nil {
+		return nil, fmt.Errorf("aPIResourceImport.Name must be provided to Apply")
+	}
+	result = &v1alpha1.APIResourceImport{}
+	err = c.client.Patch(types.ApplyPatchType).
+		Cluster(c.cluster).
+		Resource("apiresourceimports").
+		Name(*name).
+		VersionedParams(&patchOpts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// ApplyStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating ApplyStatus().
+func (c *aPIResourceImports) ApplyStatus(ctx context.Context, aPIResourceImport *apiresourcev1alpha1.APIResourceImportApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.APIResourceImport, err error) {
+	if aPIResourceImport == nil {
+		return nil, fmt.Errorf("aPIResourceImport provided to Apply must not be nil")
+	}
+	patchOpts := opts.ToPatchOptions()
+	data, err := json.Marshal(aPIResourceImport)
+	if err != nil {
+		return nil, err
+	}
+
+	name := aPIResourceImport.Name
+	if name == nil {
+		return nil, fmt.Errorf("aPIResourceImport.Name must be provided to Apply")
+	}
+
+	result = &v1alpha1.APIResourceImport{}
+	err = c.client.Patch(types.ApplyPatchType).
+		Cluster(c.cluster).
+		Resource("apiresourceimports").
+		Name(*name).
+		SubResource("status").
+		VersionedParams(&patchOpts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
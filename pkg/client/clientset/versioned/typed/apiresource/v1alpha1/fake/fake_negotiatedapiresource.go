@@ -20,6 +20,8 @@ package fake
 
 import (
 	"context"
+	json "encoding/json"
+	"fmt"
 
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	labels "k8s.io/apimachinery/pkg/labels"
@@ -29,6 +31,7 @@ import (
 	testing "k8s.io/client-go/testing"
 
 	v1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apiresource/v1alpha1"
+	applyconfigurationapiresourcev1alpha1 "github.com/kcp-dev/kcp/pkg/client/applyconfiguration/apiresource/v1alpha1"
 )
 
 // FakeNegotiatedAPIResources implements NegotiatedAPIResourceInterface
@@ -132,3 +135,46 @@ func (c *FakeNegotiatedAPIResources) Patch(ctx context.Context, name string, pt
 	}
 	return obj.(*v1alpha1.NegotiatedAPIResource), err
 }
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied negotiatedAPIResource.
+func (c *FakeNegotiatedAPIResources) Apply(ctx context.Context, negotiatedAPIResource *applyconfigurationapiresourcev1alpha1.NegotiatedAPIResourceApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.NegotiatedAPIResource, err error) {
+	if negotiatedAPIResource == nil {
+		return nil, fmt.Errorf("negotiatedAPIResource provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(negotiatedAPIResource)
+	if err != nil {
+		return nil, err
+	}
+	name := negotiatedAPIResource.Name
+	if name == nil {
+		return nil, fmt.Errorf("negotiatedAPIResource.Name must be provided to Apply")
+	}
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(negotiatedapiresourcesResource, *name, types.ApplyPatchType, data), &v1alpha1.NegotiatedAPIResource{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.NegotiatedAPIResource), err
+}
+
+// ApplyStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating ApplyStatus().
+func (c *FakeNegotiatedAPIResources) ApplyStatus(ctx context.Context, negotiatedAPIResource *applyconfigurationapiresourcev1alpha1.NegotiatedAPIResourceApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.NegotiatedAPIResource, err error) {
+	if negotiatedAPIResource == nil {
+		return nil, fmt.Errorf("negotiatedAPIResource provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(negotiatedAPIResource)
+	if err != nil {
+		return nil, err
+	}
+	name := negotiatedAPIResource.Name
+	if name == nil {
+		return nil, fmt.Errorf("negotiatedAPIResource.Name must be provided to Apply")
+	}
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(negotiatedapiresourcesResource, *name, types.ApplyPatchType, data, "status"), &v1alpha1.NegotiatedAPIResource{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.NegotiatedAPIResource), err
+}
@@ -20,6 +20,8 @@ package v1alpha1
 
 import (
 	"context"
+	json "encoding/json"
+	"fmt"
 	"time"
 
 	logicalcluster "github.com/kcp-dev/logicalcluster"
@@ -30,6 +32,7 @@ import (
 	rest "k8s.io/client-go/rest"
 
 	v1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apiresource/v1alpha1"
+	apiresourcev1alpha1 "github.com/kcp-dev/kcp/pkg/client/applyconfiguration/apiresource/v1alpha1"
 	scheme "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/scheme"
 )
 
@@ -50,6 +53,8 @@ type NegotiatedAPIResourceInterface interface {
 	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.NegotiatedAPIResourceList, error)
 	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
 	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.NegotiatedAPIResource, err error)
+	Apply(ctx context.Context, negotiatedAPIResource *apiresourcev1alpha1.NegotiatedAPIResourceApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.NegotiatedAPIResource, err error)
+	ApplyStatus(ctx context.Context, negotiatedAPIResource *apiresourcev1alpha1.NegotiatedAPIResourceApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.NegotiatedAPIResource, err error)
 	NegotiatedAPIResourceExpansion
 }
 
@@ -196,3 +201,59 @@ func (c *negotiatedAPIResources) Patch(ctx context.Context, name string, pt type
 		Into(result)
 	return
 }
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied negotiatedAPIResource.
+func (c *negotiatedAPIResources) Apply(ctx context.Context, negotiatedAPIResource *apiresourcev1alpha1.NegotiatedAPIResourceApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.NegotiatedAPIResource, err error) {
+	if negotiatedAPIResource == nil {
+		return nil, fmt.Errorf("negotiatedAPIResource provided to Apply must not be nil")
+	}
+	patchOpts := opts.ToPatchOptions()
+	data, err := json.Marshal(negotiatedAPIResource)
+	if err != nil {
+		return nil, err
+	}
+	name := negotiatedAPIResource.Name
+	if name == nil {
+		return nil, fmt.Errorf("negotiatedAPIResource.Name must be provided to Apply")
+	}
+	result = &v1alpha1.NegotiatedAPIResource{}
+	err = c.client.Patch(types.ApplyPatchType).
+		Cluster(c.cluster).
+		Resource("negotiatedapiresources").
+		Name(*name).
+		VersionedParams(&patchOpts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// ApplyStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating ApplyStatus().
+func (c *negotiatedAPIResources) ApplyStatus(ctx context.Context, negotiatedAPIResource *apiresourcev1alpha1.NegotiatedAPIResourceApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.NegotiatedAPIResource, err error) {
+	if negotiatedAPIResource == nil {
+		return nil, fmt.Errorf("negotiatedAPIResource provided to Apply must not be nil")
+	}
+	patchOpts := opts.ToPatchOptions()
+	data, err := json.Marshal(negotiatedAPIResource)
+	if err != nil {
+		return nil, err
+	}
+
+	name := negotiatedAPIResource.Name
+	if name == nil {
+		return nil, fmt.Errorf("negotiatedAPIResource.Name must be provided to Apply")
+	}
+
+	result = &v1alpha1.NegotiatedAPIResource{}
+	err = c.client.Patch(types.ApplyPatchType).
+		Cluster(c.cluster).
+		Resource("negotiatedapiresources").
+		Name(*name).
+		SubResource("status").
+		VersionedParams(&patchOpts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
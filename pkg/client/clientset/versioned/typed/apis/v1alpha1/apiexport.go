@@ -20,6 +20,8 @@ package v1alpha1
 
 import (
 	"context"
+	json "encoding/json"
+	"fmt"
 	"time"
 
 	logicalcluster "github.com/kcp-dev/logicalcluster"
@@ -30,6 +32,7 @@ import (
 	rest "k8s.io/client-go/rest"
 
 	v1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/client/applyconfiguration/apis/v1alpha1"
 	scheme "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/scheme"
 )
 
@@ -50,6 +53,8 @@ type APIExportInterface interface {
 	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.APIExportList, error)
 	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
 	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.APIExport, err error)
+	Apply(ctx context.Context, aPIExport *apisv1alpha1.APIExportApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.APIExport, err error)
+	ApplyStatus(ctx context.Context, aPIExport *apisv1alpha1.APIExportApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.APIExport, err error)
 	APIExportExpansion
 }
 
@@ -196,3 +201,59 @@ func (c *aPIExports) Patch(ctx context.Context, name string, pt types.PatchType,
 		Into(result)
 	return
 }
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied aPIExport.
+func (c *aPIExports) Apply(ctx context.Context, aPIExport *apisv1alpha1.APIExportApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.APIExport, err error) {
+	if aPIExport == nil {
+		return nil, fmt.Errorf("aPIExport provided to Apply must not be nil")
+	}
+	patchOpts := opts.ToPatchOptions()
+	data, err := json.Marshal(aPIExport)
+	if err != nil {
+		return nil, err
+	}
+	name := aPIExport.Name
+	if name == nil {
+		return nil, fmt.Errorf("aPIExport.Name must be provided to Apply")
+	}
+	result = &v1alpha1.APIExport{}
+	err = c.client.Patch(types.ApplyPatchType).
+		Cluster(c.cluster).
+		Resource("apiexports").
+		Name(*name).
+		VersionedParams(&patchOpts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// ApplyStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating ApplyStatus().
+func (c *aPIExports) ApplyStatus(ctx context.Context, aPIExport *apisv1alpha1.APIExportApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.APIExport, err error) {
+	if aPIExport == nil {
+		return nil, fmt.Errorf("aPIExport provided to Apply must not be nil")
+	}
+	patchOpts := opts.ToPatchOptions()
+	data, err := json.Marshal(aPIExport)
+	if err != nil {
+		return nil, err
+	}
+
+	name := aPIExport.Name
+	if name == nil {
+		return nil, fmt.Errorf("aPIExport.Name must be provided to Apply")
+	}
+
+	result = &v1alpha1.APIExport{}
+	err = c.client.Patch(types.ApplyPatchType).
+		Cluster(c.cluster).
+		Resource("apiexports").
+		Name(*name).
+		SubResource("status").
+		VersionedParams(&patchOpts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
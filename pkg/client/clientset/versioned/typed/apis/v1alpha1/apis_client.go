@@ -33,6 +33,11 @@ type ApisV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	APIBindingsGetter
 	APIExportsGetter
+	CatalogEntriesGetter
+	SharedSecretsGetter
+	SecretClaimsGetter
+	EventSubscriptionsGetter
+	TTLPoliciesGetter
 	APIResourceSchemasGetter
 }
 
@@ -50,6 +55,26 @@ func (c *ApisV1alpha1Client) APIExports() APIExportInterface {
 	return newAPIExports(c)
 }
 
+func (c *ApisV1alpha1Client) CatalogEntries() CatalogEntryInterface {
+	return newCatalogEntries(c)
+}
+
+func (c *ApisV1alpha1Client) SharedSecrets() SharedSecretInterface {
+	return newSharedSecrets(c)
+}
+
+func (c *ApisV1alpha1Client) SecretClaims(namespace string) SecretClaimInterface {
+	return newSecretClaims(c, namespace)
+}
+
+func (c *ApisV1alpha1Client) EventSubscriptions() EventSubscriptionInterface {
+	return newEventSubscriptions(c)
+}
+
+func (c *ApisV1alpha1Client) TTLPolicies() TTLPolicyInterface {
+	return newTTLPolicies(c)
+}
+
 func (c *ApisV1alpha1Client) APIResourceSchemas() APIResourceSchemaInterface {
 	return newAPIResourceSchemas(c)
 }
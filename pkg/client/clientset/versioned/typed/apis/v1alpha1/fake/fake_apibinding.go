@@ -20,6 +20,8 @@ package fake
 
 import (
 	"context"
+	json "encoding/json"
+	"fmt"
 
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	labels "k8s.io/apimachinery/pkg/labels"
@@ -29,6 +31,7 @@ import (
 	testing "k8s.io/client-go/testing"
 
 	v1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	applyconfigurationapisv1alpha1 "github.com/kcp-dev/kcp/pkg/client/applyconfiguration/apis/v1alpha1"
 )
 
 // FakeAPIBindings implements APIBindingInterface
@@ -132,3 +135,46 @@ func (c *FakeAPIBindings) Patch(ctx context.Context, name string, pt types.Patch
 	}
 	return obj.(*v1alpha1.APIBinding), err
 }
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied aPIBinding.
+func (c *FakeAPIBindings) Apply(ctx context.Context, aPIBinding *applyconfigurationapisv1alpha1.APIBindingApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.APIBinding, err error) {
+	if aPIBinding == nil {
+		return nil, fmt.Errorf("aPIBinding provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(aPIBinding)
+	if err != nil {
+		return nil, err
+	}
+	name := aPIBinding.Name
+	if name == nil {
+		return nil, fmt.Errorf("aPIBinding.Name must be provided to Apply")
+	}
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(apibindingsResource, *name, types.ApplyPatchType, data), &v1alpha1.APIBinding{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.APIBinding), err
+}
+
+// ApplyStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating ApplyStatus().
+func (c *FakeAPIBindings) ApplyStatus(ctx context.Context, aPIBinding *applyconfigurationapisv1alpha1.APIBindingApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.APIBinding, err error) {
+	if aPIBinding == nil {
+		return nil, fmt.Errorf("aPIBinding provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(aPIBinding)
+	if err != nil {
+		return nil, err
+	}
+	name := aPIBinding.Name
+	if name == nil {
+		return nil, fmt.Errorf("aPIBinding.Name must be provided to Apply")
+	}
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(apibindingsResource, *name, types.ApplyPatchType, data, "status"), &v1alpha1.APIBinding{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.APIBinding), err
+}
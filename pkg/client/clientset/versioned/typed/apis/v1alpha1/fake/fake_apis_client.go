@@ -37,6 +37,26 @@ func (c *FakeApisV1alpha1) APIExports() v1alpha1.APIExportInterface {
 	return &FakeAPIExports{c}
 }
 
+func (c *FakeApisV1alpha1) CatalogEntries() v1alpha1.CatalogEntryInterface {
+	return &FakeCatalogEntries{c}
+}
+
+func (c *FakeApisV1alpha1) SharedSecrets() v1alpha1.SharedSecretInterface {
+	return &FakeSharedSecrets{c}
+}
+
+func (c *FakeApisV1alpha1) SecretClaims(namespace string) v1alpha1.SecretClaimInterface {
+	return &FakeSecretClaims{c, namespace}
+}
+
+func (c *FakeApisV1alpha1) EventSubscriptions() v1alpha1.EventSubscriptionInterface {
+	return &FakeEventSubscriptions{c}
+}
+
+func (c *FakeApisV1alpha1) TTLPolicies() v1alpha1.TTLPolicyInterface {
+	return &FakeTTLPolicies{c}
+}
+
 func (c *FakeApisV1alpha1) APIResourceSchemas() v1alpha1.APIResourceSchemaInterface {
 	return &FakeAPIResourceSchemas{c}
 }
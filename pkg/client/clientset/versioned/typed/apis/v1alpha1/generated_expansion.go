@@ -22,4 +22,14 @@ type APIBindingExpansion interface{}
 
 type APIExportExpansion interface{}
 
+type CatalogEntryExpansion interface{}
+
+type SharedSecretExpansion interface{}
+
+type SecretClaimExpansion interface{}
+
+type EventSubscriptionExpansion interface{}
+
+type TTLPolicyExpansion interface{}
+
 type APIResourceSchemaExpansion interface{}
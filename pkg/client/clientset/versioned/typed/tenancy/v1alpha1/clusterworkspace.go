@@ -20,6 +20,8 @@ package v1alpha1
 
 import (
 	"context"
+	json "encoding/json"
+	"fmt"
 	"time"
 
 	logicalcluster "github.com/kcp-dev/logicalcluster"
@@ -30,6 +32,7 @@ import (
 	rest "k8s.io/client-go/rest"
 
 	v1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/client/applyconfiguration/tenancy/v1alpha1"
 	scheme "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/scheme"
 )
 
@@ -50,6 +53,8 @@ type ClusterWorkspaceInterface interface {
 	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.ClusterWorkspaceList, error)
 	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
 	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.ClusterWorkspace, err error)
+	Apply(ctx context.Context, clusterWorkspace *tenancyv1alpha1.ClusterWorkspaceApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.ClusterWorkspace, err error)
+	ApplyStatus(ctx context.Context, clusterWorkspace *tenancyv1alpha1.ClusterWorkspaceApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.ClusterWorkspace, err error)
 	ClusterWorkspaceExpansion
 }
 
@@ -196,3 +201,59 @@ func (c *clusterWorkspaces) Patch(ctx context.Context, name string, pt types.Pat
 		Into(result)
 	return
 }
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied clusterWorkspace.
+func (c *clusterWorkspaces) Apply(ctx context.Context, clusterWorkspace *tenancyv1alpha1.ClusterWorkspaceApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.ClusterWorkspace, err error) {
+	if clusterWorkspace == nil {
+		return nil, fmt.Errorf("clusterWorkspace provided to Apply must not be nil")
+	}
+	patchOpts := opts.ToPatchOptions()
+	data, err := json.Marshal(clusterWorkspace)
+	if err != nil {
+		return nil, err
+	}
+	name := clusterWorkspace.Name
+	if name == nil {
+		return nil, fmt.Errorf("clusterWorkspace.Name must be provided to Apply")
+	}
+	result = &v1alpha1.ClusterWorkspace{}
+	err = c.client.Patch(types.ApplyPatchType).
+		Cluster(c.cluster).
+		Resource("clusterworkspaces").
+		Name(*name).
+		VersionedParams(&patchOpts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// ApplyStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating ApplyStatus().
+func (c *clusterWorkspaces) ApplyStatus(ctx context.Context, clusterWorkspace *tenancyv1alpha1.ClusterWorkspaceApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.ClusterWorkspace, err error) {
+	if clusterWorkspace == nil {
+		return nil, fmt.Errorf("clusterWorkspace provided to Apply must not be nil")
+	}
+	patchOpts := opts.ToPatchOptions()
+	data, err := json.Marshal(clusterWorkspace)
+	if err != nil {
+		return nil, err
+	}
+
+	name := clusterWorkspace.Name
+	if name == nil {
+		return nil, fmt.Errorf("clusterWorkspace.Name must be provided to Apply")
+	}
+
+	result = &v1alpha1.ClusterWorkspace{}
+	err = c.client.Patch(types.ApplyPatchType).
+		Cluster(c.cluster).
+		Resource("clusterworkspaces").
+		Name(*name).
+		SubResource("status").
+		VersionedParams(&patchOpts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -20,6 +20,8 @@ package v1alpha1
 
 import (
 	"context"
+	json "encoding/json"
+	"fmt"
 	"time"
 
 	logicalcluster "github.com/kcp-dev/logicalcluster"
@@ -30,6 +32,7 @@ import (
 	rest "k8s.io/client-go/rest"
 
 	v1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/client/applyconfiguration/tenancy/v1alpha1"
 	scheme "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/scheme"
 )
 
@@ -50,6 +53,8 @@ type ClusterWorkspaceShardInterface interface {
 	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.ClusterWorkspaceShardList, error)
 	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
 	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.ClusterWorkspaceShard, err error)
+	Apply(ctx context.Context, clusterWorkspaceShard *tenancyv1alpha1.ClusterWorkspaceShardApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.ClusterWorkspaceShard, err error)
+	ApplyStatus(ctx context.Context, clusterWorkspaceShard *tenancyv1alpha1.ClusterWorkspaceShardApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.ClusterWorkspaceShard, err error)
 	ClusterWorkspaceShardExpansion
 }
 
@@ -196,3 +201,59 @@ func (c *clusterWorkspaceShards) Patch(ctx context.Context, name string, pt type
 		Into(result)
 	return
 }
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied clusterWorkspaceShard.
+func (c *clusterWorkspaceShards) Apply(ctx context.Context, clusterWorkspaceShard *tenancyv1alpha1.ClusterWorkspaceShardApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.ClusterWorkspaceShard, err error) {
+	if clusterWorkspaceShard == nil {
+		return nil, fmt.Errorf("clusterWorkspaceShard provided to Apply must not be nil")
+	}
+	patchOpts := opts.ToPatchOptions()
+	data, err := json.Marshal(clusterWorkspaceShard)
+	if err != nil {
+		return nil, err
+	}
+	name := clusterWorkspaceShard.Name
+	if name == nil {
+		return nil, fmt.Errorf("clusterWorkspaceShard.Name must be provided to Apply")
+	}
+	result = &v1alpha1.ClusterWorkspaceShard{}
+	err = c.client.Patch(types.ApplyPatchType).
+		Cluster(c.cluster).
+		Resource("clusterworkspaceshards").
+		Name(*name).
+		VersionedParams(&patchOpts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// ApplyStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating ApplyStatus().
+func (c *clusterWorkspaceShards) ApplyStatus(ctx context.Context, clusterWorkspaceShard *tenancyv1alpha1.ClusterWorkspaceShardApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.ClusterWorkspaceShard, err error) {
+	if clusterWorkspaceShard == nil {
+		return nil, fmt.Errorf("clusterWorkspaceShard provided to Apply must not be nil")
+	}
+	patchOpts := opts.ToPatchOptions()
+	data, err := json.Marshal(clusterWorkspaceShard)
+	if err != nil {
+		return nil, err
+	}
+
+	name := clusterWorkspaceShard.Name
+	if name == nil {
+		return nil, fmt.Errorf("clusterWorkspaceShard.Name must be provided to Apply")
+	}
+
+	result = &v1alpha1.ClusterWorkspaceShard{}
+	err = c.client.Patch(types.ApplyPatchType).
+		Cluster(c.cluster).
+		Resource("clusterworkspaceshards").
+		Name(*name).
+		SubResource("status").
+		VersionedParams(&patchOpts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -20,6 +20,8 @@ package v1alpha1
 
 import (
 	"context"
+	json "encoding/json"
+	"fmt"
 	"time"
 
 	logicalcluster "github.com/kcp-dev/logicalcluster"
@@ -30,6 +32,7 @@ import (
 	rest "k8s.io/client-go/rest"
 
 	v1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/client/applyconfiguration/tenancy/v1alpha1"
 	scheme "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/scheme"
 )
 
@@ -49,6 +52,7 @@ type ClusterWorkspaceTypeInterface interface {
 	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.ClusterWorkspaceTypeList, error)
 	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
 	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.ClusterWorkspaceType, err error)
+	Apply(ctx context.Context, clusterWorkspaceType *tenancyv1alpha1.ClusterWorkspaceTypeApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.ClusterWorkspaceType, err error)
 	ClusterWorkspaceTypeExpansion
 }
 
@@ -179,3 +183,29 @@ func (c *clusterWorkspaceTypes) Patch(ctx context.Context, name string, pt types
 		Into(result)
 	return
 }
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied clusterWorkspaceType.
+func (c *clusterWorkspaceTypes) Apply(ctx context.Context, clusterWorkspaceType *tenancyv1alpha1.ClusterWorkspaceTypeApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.ClusterWorkspaceType, err error) {
+	if clusterWorkspaceType == nil {
+		return nil, fmt.Errorf("clusterWorkspaceType provided to Apply must not be nil")
+	}
+	patchOpts := opts.ToPatchOptions()
+	data, err := json.Marshal(clusterWorkspaceType)
+	if err != nil {
+		return nil, err
+	}
+	name := clusterWorkspaceType.Name
+	if name == nil {
+		return nil, fmt.Errorf("clusterWorkspaceType.Name must be provided to Apply")
+	}
+	result = &v1alpha1.ClusterWorkspaceType{}
+	err = c.client.Patch(types.ApplyPatchType).
+		Cluster(c.cluster).
+		Resource("clusterworkspacetypes").
+		Name(*name).
+		VersionedParams(&patchOpts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -20,6 +20,8 @@ package fake
 
 import (
 	"context"
+	json "encoding/json"
+	"fmt"
 
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	labels "k8s.io/apimachinery/pkg/labels"
@@ -29,6 +31,7 @@ import (
 	testing "k8s.io/client-go/testing"
 
 	v1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	applyconfigurationtenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/client/applyconfiguration/tenancy/v1alpha1"
 )
 
 // FakeClusterWorkspaces implements ClusterWorkspaceInterface
@@ -132,3 +135,46 @@ func (c *FakeClusterWorkspaces) Patch(ctx context.Context, name string, pt types
 	}
 	return obj.(*v1alpha1.ClusterWorkspace), err
 }
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied clusterWorkspace.
+func (c *FakeClusterWorkspaces) Apply(ctx context.Context, clusterWorkspace *applyconfigurationtenancyv1alpha1.ClusterWorkspaceApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.ClusterWorkspace, err error) {
+	if clusterWorkspace == nil {
+		return nil, fmt.Errorf("clusterWorkspace provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(clusterWorkspace)
+	if err != nil {
+		return nil, err
+	}
+	name := clusterWorkspace.Name
+	if name == nil {
+		return nil, fmt.Errorf("clusterWorkspace.Name must be provided to Apply")
+	}
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(clusterworkspacesResource, *name, types.ApplyPatchType, data), &v1alpha1.ClusterWorkspace{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ClusterWorkspace), err
+}
+
+// ApplyStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating ApplyStatus().
+func (c *FakeClusterWorkspaces) ApplyStatus(ctx context.Context, clusterWorkspace *applyconfigurationtenancyv1alpha1.ClusterWorkspaceApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.ClusterWorkspace, err error) {
+	if clusterWorkspace == nil {
+		return nil, fmt.Errorf("clusterWorkspace provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(clusterWorkspace)
+	if err != nil {
+		return nil, err
+	}
+	name := clusterWorkspace.Name
+	if name == nil {
+		return nil, fmt.Errorf("clusterWorkspace.Name must be provided to Apply")
+	}
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(clusterworkspacesResource, *name, types.ApplyPatchType, data, "status"), &v1alpha1.ClusterWorkspace{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ClusterWorkspace), err
+}
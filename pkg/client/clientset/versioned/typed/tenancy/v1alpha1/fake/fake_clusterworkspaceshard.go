@@ -20,6 +20,8 @@ package fake
 
 import (
 	"context"
+	json "encoding/json"
+	"fmt"
 
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	labels "k8s.io/apimachinery/pkg/labels"
@@ -29,6 +31,7 @@ import (
 	testing "k8s.io/client-go/testing"
 
 	v1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	applyconfigurationtenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/client/applyconfiguration/tenancy/v1alpha1"
 )
 
 // FakeClusterWorkspaceShards implements ClusterWorkspaceShardInterface
@@ -132,3 +135,46 @@ func (c *FakeClusterWorkspaceShards) Patch(ctx context.Context, name string, pt
 	}
 	return obj.(*v1alpha1.ClusterWorkspaceShard), err
 }
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied clusterWorkspaceShard.
+func (c *FakeClusterWorkspaceShards) Apply(ctx context.Context, clusterWorkspaceShard *applyconfigurationtenancyv1alpha1.ClusterWorkspaceShardApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.ClusterWorkspaceShard, err error) {
+	if clusterWorkspaceShard == nil {
+		return nil, fmt.Errorf("clusterWorkspaceShard provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(clusterWorkspaceShard)
+	if err != nil {
+		return nil, err
+	}
+	name := clusterWorkspaceShard.Name
+	if name == nil {
+		return nil, fmt.Errorf("clusterWorkspaceShard.Name must be provided to Apply")
+	}
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(clusterworkspaceshardsResource, *name, types.ApplyPatchType, data), &v1alpha1.ClusterWorkspaceShard{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ClusterWorkspaceShard), err
+}
+
+// ApplyStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating ApplyStatus().
+func (c *FakeClusterWorkspaceShards) ApplyStatus(ctx context.Context, clusterWorkspaceShard *applyconfigurationtenancyv1alpha1.ClusterWorkspaceShardApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.ClusterWorkspaceShard, err error) {
+	if clusterWorkspaceShard == nil {
+		return nil, fmt.Errorf("clusterWorkspaceShard provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(clusterWorkspaceShard)
+	if err != nil {
+		return nil, err
+	}
+	name := clusterWorkspaceShard.Name
+	if name == nil {
+		return nil, fmt.Errorf("clusterWorkspaceShard.Name must be provided to Apply")
+	}
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(clusterworkspaceshardsResource, *name, types.ApplyPatchType, data, "status"), &v1alpha1.ClusterWorkspaceShard{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ClusterWorkspaceShard), err
+}
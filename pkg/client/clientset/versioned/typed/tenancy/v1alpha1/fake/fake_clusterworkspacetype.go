@@ -20,6 +20,8 @@ package fake
 
 import (
 	"context"
+	json "encoding/json"
+	"fmt"
 
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	labels "k8s.io/apimachinery/pkg/labels"
@@ -29,6 +31,7 @@ import (
 	testing "k8s.io/client-go/testing"
 
 	v1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	applyconfigurationtenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/client/applyconfiguration/tenancy/v1alpha1"
 )
 
 // FakeClusterWorkspaceTypes implements ClusterWorkspaceTypeInterface
@@ -121,3 +124,24 @@ func (c *FakeClusterWorkspaceTypes) Patch(ctx context.Context, name string, pt t
 	}
 	return obj.(*v1alpha1.ClusterWorkspaceType), err
 }
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied clusterWorkspaceType.
+func (c *FakeClusterWorkspaceTypes) Apply(ctx context.Context, clusterWorkspaceType *applyconfigurationtenancyv1alpha1.ClusterWorkspaceTypeApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.ClusterWorkspaceType, err error) {
+	if clusterWorkspaceType == nil {
+		return nil, fmt.Errorf("clusterWorkspaceType provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(clusterWorkspaceType)
+	if err != nil {
+		return nil, err
+	}
+	name := clusterWorkspaceType.Name
+	if name == nil {
+		return nil, fmt.Errorf("clusterWorkspaceType.Name must be provided to Apply")
+	}
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(clusterworkspacetypesResource, *name, types.ApplyPatchType, data), &v1alpha1.ClusterWorkspaceType{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ClusterWorkspaceType), err
+}
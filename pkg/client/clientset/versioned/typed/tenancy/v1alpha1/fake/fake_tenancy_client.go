@@ -29,6 +29,38 @@ type FakeTenancyV1alpha1 struct {
 	*testing.Fake
 }
 
+func (c *FakeTenancyV1alpha1) AccessGrants() v1alpha1.AccessGrantInterface {
+	return &FakeAccessGrants{c}
+}
+
+func (c *FakeTenancyV1alpha1) DenyPolicies() v1alpha1.DenyPolicyInterface {
+	return &FakeDenyPolicies{c}
+}
+
+func (c *FakeTenancyV1alpha1) HomeWorkspacePolicies() v1alpha1.HomeWorkspacePolicyInterface {
+	return &FakeHomeWorkspacePolicies{c}
+}
+
+func (c *FakeTenancyV1alpha1) ObjectTransfers() v1alpha1.ObjectTransferInterface {
+	return &FakeObjectTransfers{c}
+}
+
+func (c *FakeTenancyV1alpha1) Replications() v1alpha1.ReplicationInterface {
+	return &FakeReplications{c}
+}
+
+func (c *FakeTenancyV1alpha1) PolicyBundles() v1alpha1.PolicyBundleInterface {
+	return &FakePolicyBundles{c}
+}
+
+func (c *FakeTenancyV1alpha1) BulkWorkspaceOperations() v1alpha1.BulkWorkspaceOperationInterface {
+	return &FakeBulkWorkspaceOperations{c}
+}
+
+func (c *FakeTenancyV1alpha1) VirtualWorkspaces() v1alpha1.VirtualWorkspaceInterface {
+	return &FakeVirtualWorkspaces{c}
+}
+
 func (c *FakeTenancyV1alpha1) ClusterWorkspaces() v1alpha1.ClusterWorkspaceInterface {
 	return &FakeClusterWorkspaces{c}
 }
@@ -18,6 +18,22 @@ limitations under the License.
 
 package v1alpha1
 
+type AccessGrantExpansion interface{}
+
+type DenyPolicyExpansion interface{}
+
+type HomeWorkspacePolicyExpansion interface{}
+
+type ObjectTransferExpansion interface{}
+
+type ReplicationExpansion interface{}
+
+type PolicyBundleExpansion interface{}
+
+type BulkWorkspaceOperationExpansion interface{}
+
+type VirtualWorkspaceExpansion interface{}
+
 type ClusterWorkspaceExpansion interface{}
 
 type ClusterWorkspaceShardExpansion interface{}
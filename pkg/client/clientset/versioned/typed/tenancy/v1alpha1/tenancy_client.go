@@ -31,6 +31,14 @@ import (
 
 type TenancyV1alpha1Interface interface {
 	RESTClient() rest.Interface
+	AccessGrantsGetter
+	DenyPoliciesGetter
+	HomeWorkspacePoliciesGetter
+	ObjectTransfersGetter
+	ReplicationsGetter
+	PolicyBundlesGetter
+	BulkWorkspaceOperationsGetter
+	VirtualWorkspacesGetter
 	ClusterWorkspacesGetter
 	ClusterWorkspaceShardsGetter
 	ClusterWorkspaceTypesGetter
@@ -42,6 +50,38 @@ type TenancyV1alpha1Client struct {
 	cluster    logicalcluster.Name
 }
 
+func (c *TenancyV1alpha1Client) AccessGrants() AccessGrantInterface {
+	return newAccessGrants(c)
+}
+
+func (c *TenancyV1alpha1Client) DenyPolicies() DenyPolicyInterface {
+	return newDenyPolicies(c)
+}
+
+func (c *TenancyV1alpha1Client) HomeWorkspacePolicies() HomeWorkspacePolicyInterface {
+	return newHomeWorkspacePolicies(c)
+}
+
+func (c *TenancyV1alpha1Client) ObjectTransfers() ObjectTransferInterface {
+	return newObjectTransfers(c)
+}
+
+func (c *TenancyV1alpha1Client) Replications() ReplicationInterface {
+	return newReplications(c)
+}
+
+func (c *TenancyV1alpha1Client) PolicyBundles() PolicyBundleInterface {
+	return newPolicyBundles(c)
+}
+
+func (c *TenancyV1alpha1Client) BulkWorkspaceOperations() BulkWorkspaceOperationInterface {
+	return newBulkWorkspaceOperations(c)
+}
+
+func (c *TenancyV1alpha1Client) VirtualWorkspaces() VirtualWorkspaceInterface {
+	return newVirtualWorkspaces(c)
+}
+
 func (c *TenancyV1alpha1Client) ClusterWorkspaces() ClusterWorkspaceInterface {
 	return newClusterWorkspaces(c)
 }
@@ -20,6 +20,8 @@ package fake
 
 import (
 	"context"
+	json "encoding/json"
+	"fmt"
 
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	labels "k8s.io/apimachinery/pkg/labels"
@@ -29,6 +31,7 @@ import (
 	testing "k8s.io/client-go/testing"
 
 	v1beta1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1beta1"
+	applyconfigurationtenancyv1beta1 "github.com/kcp-dev/kcp/pkg/client/applyconfiguration/tenancy/v1beta1"
 )
 
 // FakeWorkspaces implements WorkspaceInterface
@@ -132,3 +135,46 @@ func (c *FakeWorkspaces) Patch(ctx context.Context, name string, pt types.PatchT
 	}
 	return obj.(*v1beta1.Workspace), err
 }
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied workspace.
+func (c *FakeWorkspaces) Apply(ctx context.Context, workspace *applyconfigurationtenancyv1beta1.WorkspaceApplyConfiguration, opts v1.ApplyOptions) (result *v1beta1.Workspace, err error) {
+	if workspace == nil {
+		return nil, fmt.Errorf("workspace provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(workspace)
+	if err != nil {
+		return nil, err
+	}
+	name := workspace.Name
+	if name == nil {
+		return nil, fmt.Errorf("workspace.Name must be provided to Apply")
+	}
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(workspacesResource, *name, types.ApplyPatchType, data), &v1beta1.Workspace{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.Workspace), err
+}
+
+// ApplyStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating ApplyStatus().
+func (c *FakeWorkspaces) ApplyStatus(ctx context.Context, workspace *applyconfigurationtenancyv1beta1.WorkspaceApplyConfiguration, opts v1.ApplyOptions) (result *v1beta1.Workspace, err error) {
+	if workspace == nil {
+		return nil, fmt.Errorf("workspace provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(workspace)
+	if err != nil {
+		return nil, err
+	}
+	name := workspace.Name
+	if name == nil {
+		return nil, fmt.Errorf("workspace.Name must be provided to Apply")
+	}
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(workspacesResource, *name, types.ApplyPatchType, data, "status"), &v1beta1.Workspace{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.Workspace), err
+}
@@ -29,10 +29,26 @@ type FakeWorkloadV1alpha1 struct {
 	*testing.Fake
 }
 
+func (c *FakeWorkloadV1alpha1) Certificates(namespace string) v1alpha1.CertificateInterface {
+	return &FakeCertificates{c, namespace}
+}
+
+func (c *FakeWorkloadV1alpha1) DNSRecords(namespace string) v1alpha1.DNSRecordInterface {
+	return &FakeDNSRecords{c, namespace}
+}
+
+func (c *FakeWorkloadV1alpha1) DNSZones() v1alpha1.DNSZoneInterface {
+	return &FakeDNSZones{c}
+}
+
 func (c *FakeWorkloadV1alpha1) WorkloadClusters() v1alpha1.WorkloadClusterInterface {
 	return &FakeWorkloadClusters{c}
 }
 
+func (c *FakeWorkloadV1alpha1) WorkspaceWorkloadUsages() v1alpha1.WorkspaceWorkloadUsageInterface {
+	return &FakeWorkspaceWorkloadUsages{c}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeWorkloadV1alpha1) RESTClient() rest.Interface {
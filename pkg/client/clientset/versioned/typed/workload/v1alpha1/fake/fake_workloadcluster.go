@@ -20,6 +20,8 @@ package fake
 
 import (
 	"context"
+	json "encoding/json"
+	"fmt"
 
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	labels "k8s.io/apimachinery/pkg/labels"
@@ -29,6 +31,7 @@ import (
 	testing "k8s.io/client-go/testing"
 
 	v1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+	applyconfigurationworkloadv1alpha1 "github.com/kcp-dev/kcp/pkg/client/applyconfiguration/workload/v1alpha1"
 )
 
 // FakeWorkloadClusters implements WorkloadClusterInterface
@@ -132,3 +135,46 @@ func (c *FakeWorkloadClusters) Patch(ctx context.Context, name string, pt types.
 	}
 	return obj.(*v1alpha1.WorkloadCluster), err
 }
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied workloadCluster.
+func (c *FakeWorkloadClusters) Apply(ctx context.Context, workloadCluster *applyconfigurationworkloadv1alpha1.WorkloadClusterApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.WorkloadCluster, err error) {
+	if workloadCluster == nil {
+		return nil, fmt.Errorf("workloadCluster provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(workloadCluster)
+	if err != nil {
+		return nil, err
+	}
+	name := workloadCluster.Name
+	if name == nil {
+		return nil, fmt.Errorf("workloadCluster.Name must be provided to Apply")
+	}
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(workloadclustersResource, *name, types.ApplyPatchType, data), &v1alpha1.WorkloadCluster{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.WorkloadCluster), err
+}
+
+// ApplyStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating ApplyStatus().
+func (c *FakeWorkloadClusters) ApplyStatus(ctx context.Context, workloadCluster *applyconfigurationworkloadv1alpha1.WorkloadClusterApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.WorkloadCluster, err error) {
+	if workloadCluster == nil {
+		return nil, fmt.Errorf("workloadCluster provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(workloadCluster)
+	if err != nil {
+		return nil, err
+	}
+	name := workloadCluster.Name
+	if name == nil {
+		return nil, fmt.Errorf("workloadCluster.Name must be provided to Apply")
+	}
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(workloadclustersResource, *name, types.ApplyPatchType, data, "status"), &v1alpha1.WorkloadCluster{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.WorkloadCluster), err
+}
@@ -18,4 +18,12 @@ limitations under the License.
 
 package v1alpha1
 
+type CertificateExpansion interface{}
+
+type DNSRecordExpansion interface{}
+
+type DNSZoneExpansion interface{}
+
 type WorkloadClusterExpansion interface{}
+
+type WorkspaceWorkloadUsageExpansion interface{}
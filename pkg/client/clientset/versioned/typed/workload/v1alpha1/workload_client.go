@@ -31,7 +31,11 @@ import (
 
 type WorkloadV1alpha1Interface interface {
 	RESTClient() rest.Interface
+	CertificatesGetter
+	DNSRecordsGetter
+	DNSZonesGetter
 	WorkloadClustersGetter
+	WorkspaceWorkloadUsagesGetter
 }
 
 // WorkloadV1alpha1Client is used to interact with features provided by the workload.kcp.dev group.
@@ -40,10 +44,26 @@ type WorkloadV1alpha1Client struct {
 	cluster    logicalcluster.Name
 }
 
+func (c *WorkloadV1alpha1Client) Certificates(namespace string) CertificateInterface {
+	return newCertificates(c, namespace)
+}
+
+func (c *WorkloadV1alpha1Client) DNSRecords(namespace string) DNSRecordInterface {
+	return newDNSRecords(c, namespace)
+}
+
+func (c *WorkloadV1alpha1Client) DNSZones() DNSZoneInterface {
+	return newDNSZones(c)
+}
+
 func (c *WorkloadV1alpha1Client) WorkloadClusters() WorkloadClusterInterface {
 	return newWorkloadClusters(c)
 }
 
+func (c *WorkloadV1alpha1Client) WorkspaceWorkloadUsages() WorkspaceWorkloadUsageInterface {
+	return newWorkspaceWorkloadUsages(c)
+}
+
 // NewForConfig creates a new WorkloadV1alpha1Client for the given config.
 // NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
 // where httpClient was generated with rest.HTTPClientFor(c).
@@ -20,6 +20,8 @@ package v1alpha1
 
 import (
 	"context"
+	json "encoding/json"
+	"fmt"
 	"time"
 
 	logicalcluster "github.com/kcp-dev/logicalcluster"
@@ -30,6 +32,7 @@ import (
 	rest "k8s.io/client-go/rest"
 
 	v1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/client/applyconfiguration/workload/v1alpha1"
 	scheme "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/scheme"
 )
 
@@ -50,6 +53,8 @@ type WorkloadClusterInterface interface {
 	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.WorkloadClusterList, error)
 	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
 	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.WorkloadCluster, err error)
+	Apply(ctx context.Context, workloadCluster *workloadv1alpha1.WorkloadClusterApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.WorkloadCluster, err error)
+	ApplyStatus(ctx context.Context, workloadCluster *workloadv1alpha1.WorkloadClusterApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.WorkloadCluster, err error)
 	WorkloadClusterExpansion
 }
 
@@ -196,3 +201,59 @@ func (c *workloadClusters) Patch(ctx context.Context, name string, pt types.Patc
 		Into(result)
 	return
 }
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied workloadCluster.
+func (c *workloadClusters) Apply(ctx context.Context, workloadCluster *workloadv1alpha1.WorkloadClusterApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.WorkloadCluster, err error) {
+	if workloadCluster == nil {
+		return nil, fmt.Errorf("workloadCluster provided to Apply must not be nil")
+	}
+	patchOpts := opts.ToPatchOptions()
+	data, err := json.Marshal(workloadCluster)
+	if err != nil {
+		return nil, err
+	}
+	name := workloadCluster.Name
+	if name == nil {
+		return nil, fmt.Errorf("workloadCluster.Name must be provided to Apply")
+	}
+	result = &v1alpha1.WorkloadCluster{}
+	err = c.client.Patch(types.ApplyPatchType).
+		Cluster(c.cluster).
+		Resource("workloadclusters").
+		Name(*name).
+		VersionedParams(&patchOpts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// ApplyStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating ApplyStatus().
+func (c *workloadClusters) ApplyStatus(ctx context.Context, workloadCluster *workloadv1alpha1.WorkloadClusterApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.WorkloadCluster, err error) {
+	if workloadCluster == nil {
+		return nil, fmt.Errorf("workloadCluster provided to Apply must not be nil")
+	}
+	patchOpts := opts.ToPatchOptions()
+	data, err := json.Marshal(workloadCluster)
+	if err != nil {
+		return nil, err
+	}
+
+	name := workloadCluster.Name
+	if name == nil {
+		return nil, fmt.Errorf("workloadCluster.Name must be provided to Apply")
+	}
+
+	result = &v1alpha1.WorkloadCluster{}
+	err = c.client.Patch(types.ApplyPatchType).
+		Cluster(c.cluster).
+		Resource("workloadclusters").
+		Name(*name).
+		SubResource("status").
+		VersionedParams(&patchOpts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterfake
+
+import (
+	"encoding/json"
+
+	jsonpatch "github.com/evanphx/json-patch"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clienttesting "k8s.io/client-go/testing"
+
+	"github.com/kcp-dev/kcp/pkg/client/clientset/versioned/scheme"
+)
+
+// applyReactor handles apply patches, which the object tracker of the fake clientsets
+// rejects. This is not server-side apply: a missing object is created from the apply
+// configuration, an existing one gets the apply configuration merged in as JSON merge
+// patch. Field ownership is not tracked, i.e. lists are replaced, fields are never
+// removed and there are no conflicts.
+func applyReactor(tracker clienttesting.ObjectTracker) clienttesting.ReactionFunc {
+	return func(action clienttesting.Action) (bool, runtime.Object, error) {
+		patch, ok := action.(clienttesting.PatchAction)
+		if !ok || patch.GetPatchType() != types.ApplyPatchType {
+			return false, nil, nil
+		}
+
+		gvr, ns, name := patch.GetResource(), patch.GetNamespace(), patch.GetName()
+		obj, err := newApplied(patch.GetPatch())
+		if err != nil {
+			return true, nil, err
+		}
+
+		existing, err := tracker.Get(gvr, ns, name)
+		if apierrors.IsNotFound(err) {
+			if err := json.Unmarshal(patch.GetPatch(), obj); err != nil {
+				return true, nil, err
+			}
+			if err := tracker.Create(gvr, obj, ns); err != nil {
+				return true, nil, err
+			}
+			return true, obj, nil
+		} else if err != nil {
+			return true, nil, err
+		}
+
+		old, err := json.Marshal(existing)
+		if err != nil {
+			return true, nil, err
+		}
+		modified, err := jsonpatch.MergePatch(old, patch.GetPatch())
+		if err != nil {
+			return true, nil, err
+		}
+		if err := json.Unmarshal(modified, obj); err != nil {
+			return true, nil, err
+		}
+		if err := tracker.Update(gvr, obj, ns); err != nil {
+			return true, nil, err
+		}
+		return true, obj, nil
+	}
+}
+
+// newApplied returns an empty object of the kind of the given apply configuration.
+func newApplied(data []byte) (runtime.Object, error) {
+	var typeMeta metav1.TypeMeta
+	if err := json.Unmarshal(data, &typeMeta); err != nil {
+		return nil, err
+	}
+	return scheme.Scheme.New(typeMeta.GroupVersionKind())
+}
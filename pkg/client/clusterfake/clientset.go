@@ -0,0 +1,148 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package clusterfake provides a fake of the cluster-aware kcp clientset with a fake
+// clientset per logical cluster, for unit tests of code written against
+// versioned.ClusterInterface.
+//
+// Apply patches are accepted, but they are NOT server-side apply: they are merged as
+// JSON merge patch, without field ownership. Lists are replaced instead of merged by
+// key, fields dropped from an apply configuration are kept, and conflicts between
+// field managers are never reported. Tests relying on server-side apply semantics
+// need a real API server, e.g. an e2e test.
+package clusterfake
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	clienttesting "k8s.io/client-go/testing"
+
+	"github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
+	"github.com/kcp-dev/kcp/pkg/client/clientset/versioned/fake"
+)
+
+// ClusterReactionFunc is a reaction of the fake clientset of the given logical cluster.
+type ClusterReactionFunc func(clusterName logicalcluster.Name, action clienttesting.Action) (handled bool, ret runtime.Object, err error)
+
+type reactor struct {
+	verb     string
+	resource string
+	reaction ClusterReactionFunc
+}
+
+// ClusterClientset implements versioned.ClusterInterface with a fake clientset per logical
+// cluster. Clientsets are created empty on first use. Unlike fake.Clientset, they
+// accept apply patches, but only approximate them by a JSON merge patch, which is
+// not server-side apply, see the package documentation.
+type ClusterClientset struct {
+	lock     sync.Mutex
+	clients  map[logicalcluster.Name]*fake.Clientset
+	reactors []reactor
+}
+
+var _ versioned.ClusterInterface = &ClusterClientset{}
+
+// NewSimpleClusterClientset returns a ClusterClientset with the given objects added to the
+// fake clientsets of their logical clusters, i.e. of their metadata.clusterName. It panics
+// for objects without logical cluster.
+func NewSimpleClusterClientset(objects ...runtime.Object) *ClusterClientset {
+	byCluster := map[logicalcluster.Name][]runtime.Object{}
+	for _, obj := range objects {
+		metaObj, err := meta.Accessor(obj)
+		if err != nil {
+			panic(err)
+		}
+		clusterName := logicalcluster.From(metaObj)
+		if clusterName.Empty() {
+			panic(fmt.Sprintf("%T %q has no logical cluster", obj, metaObj.GetName()))
+		}
+		byCluster[clusterName] = append(byCluster[clusterName], obj)
+	}
+
+	c := &ClusterClientset{clients: map[logicalcluster.Name]*fake.Clientset{}}
+	for clusterName, objs := range byCluster {
+		c.clients[clusterName] = newClientset(objs...)
+	}
+	return c
+}
+
+// Cluster returns the fake clientset of the given logical cluster.
+func (c *ClusterClientset) Cluster(clusterName logicalcluster.Name) versioned.Interface {
+	return c.Clientset(clusterName)
+}
+
+// Clientset returns the fake clientset of the given logical cluster, e.g. to inspect its
+// actions or tracker.
+func (c *ClusterClientset) Clientset(clusterName logicalcluster.Name) *fake.Clientset {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if client, ok := c.clients[clusterName]; ok {
+		return client
+	}
+	client := newClientset()
+	for _, r := range c.reactors {
+		client.PrependReactor(r.verb, r.resource, bind(clusterName, r.reaction))
+	}
+	c.clients[clusterName] = client
+	return client
+}
+
+// Clusters returns the logical clusters that have a clientset, sorted.
+func (c *ClusterClientset) Clusters() []logicalcluster.Name {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	ret := make([]logicalcluster.Name, 0, len(c.clients))
+	for clusterName := range c.clients {
+		ret = append(ret, clusterName)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].String() < ret[j].String() })
+	return ret
+}
+
+// PrependReactor adds a reactor to the fake clientsets of all logical clusters, including
+// those created later. Like with PrependReactor of a single clientset, reactors added
+// later run first.
+func (c *ClusterClientset) PrependReactor(verb, resource string, reaction ClusterReactionFunc) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.reactors = append(c.reactors, reactor{verb: verb, resource: resource, reaction: reaction})
+	for clusterName, client := range c.clients {
+		client.PrependReactor(verb, resource, bind(clusterName, reaction))
+	}
+}
+
+// newClientset returns a fake clientset that accepts apply patches, see applyReactor, on top of the
+// reactors of fake.NewSimpleClientset.
+func newClientset(objects ...runtime.Object) *fake.Clientset {
+	client := fake.NewSimpleClientset(objects...)
+	client.PrependReactor("patch", "*", applyReactor(client.Tracker()))
+	return client
+}
+
+func bind(clusterName logicalcluster.Name, reaction ClusterReactionFunc) clienttesting.ReactionFunc {
+	return func(action clienttesting.Action) (bool, runtime.Object, error) {
+		return reaction(clusterName, action)
+	}
+}
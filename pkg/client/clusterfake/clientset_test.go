@@ -0,0 +1,117 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterfake
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clienttesting "k8s.io/client-go/testing"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	applyconfigurationapisv1alpha1 "github.com/kcp-dev/kcp/pkg/client/applyconfiguration/apis/v1alpha1"
+)
+
+func TestNewSimpleClusterClientset(t *testing.T) {
+	ctx := context.Background()
+	org := logicalcluster.New("root:org")
+	team := logicalcluster.New("root:org:team")
+
+	c := NewSimpleClusterClientset(
+		&tenancyv1alpha1.ClusterWorkspace{ObjectMeta: metav1.ObjectMeta{ClusterName: org.String(), Name: "team"}},
+		&apisv1alpha1.APIBinding{ObjectMeta: metav1.ObjectMeta{ClusterName: team.String(), Name: "kubernetes"}},
+	)
+	require.Equal(t, []logicalcluster.Name{org, team}, c.Clusters())
+
+	_, err := c.Cluster(org).TenancyV1alpha1().ClusterWorkspaces().Get(ctx, "team", metav1.GetOptions{})
+	require.NoError(t, err)
+	_, err = c.Cluster(team).TenancyV1alpha1().ClusterWorkspaces().Get(ctx, "team", metav1.GetOptions{})
+	require.True(t, apierrors.IsNotFound(err), "objects are only visible in their logical cluster")
+	_, err = c.Cluster(team).ApisV1alpha1().APIBindings().Get(ctx, "kubernetes", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	other := logicalcluster.New("root:other")
+	_, err = c.Cluster(other).ApisV1alpha1().APIBindings().Create(ctx, &apisv1alpha1.APIBinding{ObjectMeta: metav1.ObjectMeta{Name: "new"}}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	require.Equal(t, []logicalcluster.Name{org, team, other}, c.Clusters(), "clusters are sorted")
+	require.Len(t, c.Clientset(other).Actions(), 1)
+
+	require.Panics(t, func() {
+		NewSimpleClusterClientset(&apisv1alpha1.APIBinding{ObjectMeta: metav1.ObjectMeta{Name: "no-cluster"}})
+	})
+}
+
+func TestPrependReactor(t *testing.T) {
+	ctx := context.Background()
+	existing := logicalcluster.New("root:existing")
+	c := NewSimpleClusterClientset(&apisv1alpha1.APIBinding{ObjectMeta: metav1.ObjectMeta{ClusterName: existing.String(), Name: "kubernetes"}})
+
+	var seen []logicalcluster.Name
+	c.PrependReactor("create", "apibindings", func(clusterName logicalcluster.Name, action clienttesting.Action) (bool, runtime.Object, error) {
+		seen = append(seen, clusterName)
+		binding := action.(clienttesting.CreateAction).GetObject().(*apisv1alpha1.APIBinding)
+		binding.Status.Phase = apisv1alpha1.APIBindingPhaseBound
+		return false, nil, nil
+	})
+
+	later := logicalcluster.New("root:later")
+	for _, clusterName := range []logicalcluster.Name{existing, later} {
+		created, err := c.Cluster(clusterName).ApisV1alpha1().APIBindings().Create(ctx, &apisv1alpha1.APIBinding{ObjectMeta: metav1.ObjectMeta{Name: "new"}}, metav1.CreateOptions{})
+		require.NoError(t, err)
+		require.Equal(t, apisv1alpha1.APIBindingPhaseBound, created.Status.Phase)
+	}
+	require.Equal(t, []logicalcluster.Name{existing, later}, seen)
+}
+
+func TestApply(t *testing.T) {
+	ctx := context.Background()
+	team := logicalcluster.New("root:org:team")
+	c := NewSimpleClusterClientset(&apisv1alpha1.APIBinding{
+		ObjectMeta: metav1.ObjectMeta{ClusterName: team.String(), Name: "existing", Labels: map[string]string{"a": "b"}},
+	})
+	bindings := c.Cluster(team).ApisV1alpha1().APIBindings()
+
+	created, err := bindings.Apply(ctx, applyconfigurationapisv1alpha1.APIBinding("new").
+		WithSpec(applyconfigurationapisv1alpha1.APIBindingSpec().
+			WithReference(applyconfigurationapisv1alpha1.ExportReference().
+				WithWorkspace(applyconfigurationapisv1alpha1.WorkspaceExportReference().WithExportName("kubernetes")))),
+		metav1.ApplyOptions{FieldManager: "test"})
+	require.NoError(t, err, "apply creates missing objects")
+	require.Equal(t, "kubernetes", created.Spec.Reference.Workspace.ExportName)
+	_, err = bindings.Get(ctx, "new", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	updated, err := bindings.Apply(ctx, applyconfigurationapisv1alpha1.APIBinding("existing").WithLabels(map[string]string{"c": "d"}), metav1.ApplyOptions{FieldManager: "test"})
+	require.NoError(t, err, "apply updates existing objects")
+	require.Equal(t, map[string]string{"a": "b", "c": "d"}, updated.Labels)
+
+	updated, err = bindings.ApplyStatus(ctx, applyconfigurationapisv1alpha1.APIBinding("existing").
+		WithStatus(applyconfigurationapisv1alpha1.APIBindingStatus().WithPhase(apisv1alpha1.APIBindingPhaseBound)),
+		metav1.ApplyOptions{FieldManager: "test"})
+	require.NoError(t, err)
+	require.Equal(t, apisv1alpha1.APIBindingPhaseBound, updated.Status.Phase)
+
+	_, err = c.Cluster(logicalcluster.New("root:other")).ApisV1alpha1().APIBindings().Get(ctx, "new", metav1.GetOptions{})
+	require.True(t, apierrors.IsNotFound(err), "applied objects are only visible in their logical cluster")
+}
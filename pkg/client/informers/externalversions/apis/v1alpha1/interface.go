@@ -28,6 +28,16 @@ type Interface interface {
 	APIBindings() APIBindingInformer
 	// APIExports returns a APIExportInformer.
 	APIExports() APIExportInformer
+	// CatalogEntries returns a CatalogEntryInformer.
+	CatalogEntries() CatalogEntryInformer
+	// SharedSecrets returns a SharedSecretInformer.
+	SharedSecrets() SharedSecretInformer
+	// SecretClaims returns a SecretClaimInformer.
+	SecretClaims() SecretClaimInformer
+	// EventSubscriptions returns a EventSubscriptionInformer.
+	EventSubscriptions() EventSubscriptionInformer
+	// TTLPolicies returns a TTLPolicyInformer.
+	TTLPolicies() TTLPolicyInformer
 	// APIResourceSchemas returns a APIResourceSchemaInformer.
 	APIResourceSchemas() APIResourceSchemaInformer
 }
@@ -53,6 +63,31 @@ func (v *version) APIExports() APIExportInformer {
 	return &aPIExportInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
 
+// CatalogEntries returns a CatalogEntryInformer.
+func (v *version) CatalogEntries() CatalogEntryInformer {
+	return &catalogEntryInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// SharedSecrets returns a SharedSecretInformer.
+func (v *version) SharedSecrets() SharedSecretInformer {
+	return &sharedSecretInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// SecretClaims returns a SecretClaimInformer.
+func (v *version) SecretClaims() SecretClaimInformer {
+	return &secretClaimInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// EventSubscriptions returns a EventSubscriptionInformer.
+func (v *version) EventSubscriptions() EventSubscriptionInformer {
+	return &eventSubscriptionInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// TTLPolicies returns a TTLPolicyInformer.
+func (v *version) TTLPolicies() TTLPolicyInformer {
+	return &tTLPolicyInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // APIResourceSchemas returns a APIResourceSchemaInformer.
 func (v *version) APIResourceSchemas() APIResourceSchemaInformer {
 	return &aPIResourceSchemaInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
@@ -69,6 +69,16 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Apis().V1alpha1().APIBindings().Informer()}, nil
 	case apisv1alpha1.SchemeGroupVersion.WithResource("apiexports"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Apis().V1alpha1().APIExports().Informer()}, nil
+	case apisv1alpha1.SchemeGroupVersion.WithResource("catalogentries"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Apis().V1alpha1().CatalogEntries().Informer()}, nil
+	case apisv1alpha1.SchemeGroupVersion.WithResource("sharedsecrets"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Apis().V1alpha1().SharedSecrets().Informer()}, nil
+	case apisv1alpha1.SchemeGroupVersion.WithResource("secretclaims"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Apis().V1alpha1().SecretClaims().Informer()}, nil
+	case apisv1alpha1.SchemeGroupVersion.WithResource("eventsubscriptions"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Apis().V1alpha1().EventSubscriptions().Informer()}, nil
+	case apisv1alpha1.SchemeGroupVersion.WithResource("ttlpolicies"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Apis().V1alpha1().TTLPolicies().Informer()}, nil
 	case apisv1alpha1.SchemeGroupVersion.WithResource("apiresourceschemas"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Apis().V1alpha1().APIResourceSchemas().Informer()}, nil
 
@@ -77,6 +87,22 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Scheduling().V1alpha1().Locations().Informer()}, nil
 
 		// Group=tenancy.kcp.dev, Version=v1alpha1
+	case tenancyv1alpha1.SchemeGroupVersion.WithResource("accessgrants"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Tenancy().V1alpha1().AccessGrants().Informer()}, nil
+	case tenancyv1alpha1.SchemeGroupVersion.WithResource("denypolicies"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Tenancy().V1alpha1().DenyPolicies().Informer()}, nil
+	case tenancyv1alpha1.SchemeGroupVersion.WithResource("homeworkspacepolicies"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Tenancy().V1alpha1().HomeWorkspacePolicies().Informer()}, nil
+	case tenancyv1alpha1.SchemeGroupVersion.WithResource("objecttransfers"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Tenancy().V1alpha1().ObjectTransfers().Informer()}, nil
+	case tenancyv1alpha1.SchemeGroupVersion.WithResource("replications"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Tenancy().V1alpha1().Replications().Informer()}, nil
+	case tenancyv1alpha1.SchemeGroupVersion.WithResource("policybundles"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Tenancy().V1alpha1().PolicyBundles().Informer()}, nil
+	case tenancyv1alpha1.SchemeGroupVersion.WithResource("bulkworkspaceoperations"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Tenancy().V1alpha1().BulkWorkspaceOperations().Informer()}, nil
+	case tenancyv1alpha1.SchemeGroupVersion.WithResource("virtualworkspaces"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Tenancy().V1alpha1().VirtualWorkspaces().Informer()}, nil
 	case tenancyv1alpha1.SchemeGroupVersion.WithResource("clusterworkspaces"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Tenancy().V1alpha1().ClusterWorkspaces().Informer()}, nil
 	case tenancyv1alpha1.SchemeGroupVersion.WithResource("clusterworkspaceshards"):
@@ -89,8 +115,16 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Tenancy().V1beta1().Workspaces().Informer()}, nil
 
 		// Group=workload.kcp.dev, Version=v1alpha1
+	case workloadv1alpha1.SchemeGroupVersion.WithResource("certificates"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Workload().V1alpha1().Certificates().Informer()}, nil
+	case workloadv1alpha1.SchemeGroupVersion.WithResource("dnsrecords"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Workload().V1alpha1().DNSRecords().Informer()}, nil
+	case workloadv1alpha1.SchemeGroupVersion.WithResource("dnszones"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Workload().V1alpha1().DNSZones().Informer()}, nil
 	case workloadv1alpha1.SchemeGroupVersion.WithResource("workloadclusters"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Workload().V1alpha1().WorkloadClusters().Informer()}, nil
+	case workloadv1alpha1.SchemeGroupVersion.WithResource("workspaceworkloadusages"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Workload().V1alpha1().WorkspaceWorkloadUsages().Informer()}, nil
 
 	}
 
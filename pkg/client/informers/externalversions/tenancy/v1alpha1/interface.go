@@ -24,6 +24,22 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// AccessGrants returns a AccessGrantInformer.
+	AccessGrants() AccessGrantInformer
+	// DenyPolicies returns a DenyPolicyInformer.
+	DenyPolicies() DenyPolicyInformer
+	// HomeWorkspacePolicies returns a HomeWorkspacePolicyInformer.
+	HomeWorkspacePolicies() HomeWorkspacePolicyInformer
+	// ObjectTransfers returns a ObjectTransferInformer.
+	ObjectTransfers() ObjectTransferInformer
+	// Replications returns a ReplicationInformer.
+	Replications() ReplicationInformer
+	// PolicyBundles returns a PolicyBundleInformer.
+	PolicyBundles() PolicyBundleInformer
+	// BulkWorkspaceOperations returns a BulkWorkspaceOperationInformer.
+	BulkWorkspaceOperations() BulkWorkspaceOperationInformer
+	// VirtualWorkspaces returns a VirtualWorkspaceInformer.
+	VirtualWorkspaces() VirtualWorkspaceInformer
 	// ClusterWorkspaces returns a ClusterWorkspaceInformer.
 	ClusterWorkspaces() ClusterWorkspaceInformer
 	// ClusterWorkspaceShards returns a ClusterWorkspaceShardInformer.
@@ -43,6 +59,46 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// AccessGrants returns a AccessGrantInformer.
+func (v *version) AccessGrants() AccessGrantInformer {
+	return &accessGrantInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// DenyPolicies returns a DenyPolicyInformer.
+func (v *version) DenyPolicies() DenyPolicyInformer {
+	return &denyPolicyInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// HomeWorkspacePolicies returns a HomeWorkspacePolicyInformer.
+func (v *version) HomeWorkspacePolicies() HomeWorkspacePolicyInformer {
+	return &homeWorkspacePolicyInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// ObjectTransfers returns a ObjectTransferInformer.
+func (v *version) ObjectTransfers() ObjectTransferInformer {
+	return &objectTransferInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// Replications returns a ReplicationInformer.
+func (v *version) Replications() ReplicationInformer {
+	return &replicationInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// PolicyBundles returns a PolicyBundleInformer.
+func (v *version) PolicyBundles() PolicyBundleInformer {
+	return &policyBundleInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// BulkWorkspaceOperations returns a BulkWorkspaceOperationInformer.
+func (v *version) BulkWorkspaceOperations() BulkWorkspaceOperationInformer {
+	return &bulkWorkspaceOperationInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// VirtualWorkspaces returns a VirtualWorkspaceInformer.
+func (v *version) VirtualWorkspaces() VirtualWorkspaceInformer {
+	return &virtualWorkspaceInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // ClusterWorkspaces returns a ClusterWorkspaceInformer.
 func (v *version) ClusterWorkspaces() ClusterWorkspaceInformer {
 	return &clusterWorkspaceInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
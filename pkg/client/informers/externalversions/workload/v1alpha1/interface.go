@@ -24,8 +24,16 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// Certificates returns a CertificateInformer.
+	Certificates() CertificateInformer
+	// DNSRecords returns a DNSRecordInformer.
+	DNSRecords() DNSRecordInformer
+	// DNSZones returns a DNSZoneInformer.
+	DNSZones() DNSZoneInformer
 	// WorkloadClusters returns a WorkloadClusterInformer.
 	WorkloadClusters() WorkloadClusterInformer
+	// WorkspaceWorkloadUsages returns a WorkspaceWorkloadUsageInformer.
+	WorkspaceWorkloadUsages() WorkspaceWorkloadUsageInformer
 }
 
 type version struct {
@@ -39,7 +47,27 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// Certificates returns a CertificateInformer.
+func (v *version) Certificates() CertificateInformer {
+	return &certificateInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// DNSRecords returns a DNSRecordInformer.
+func (v *version) DNSRecords() DNSRecordInformer {
+	return &dNSRecordInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// DNSZones returns a DNSZoneInformer.
+func (v *version) DNSZones() DNSZoneInformer {
+	return &dNSZoneInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // WorkloadClusters returns a WorkloadClusterInformer.
 func (v *version) WorkloadClusters() WorkloadClusterInformer {
 	return &workloadClusterInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
+
+// WorkspaceWorkloadUsages returns a WorkspaceWorkloadUsageInformer.
+func (v *version) WorkspaceWorkloadUsages() WorkspaceWorkloadUsageInformer {
+	return &workspaceWorkloadUsageInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
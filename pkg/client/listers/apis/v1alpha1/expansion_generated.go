@@ -26,6 +26,30 @@ type APIBindingListerExpansion interface{}
 // APIExportLister.
 type APIExportListerExpansion interface{}
 
+// CatalogEntryListerExpansion allows custom methods to be added to
+// CatalogEntryLister.
+type CatalogEntryListerExpansion interface{}
+
+// SharedSecretListerExpansion allows custom methods to be added to
+// SharedSecretLister.
+type SharedSecretListerExpansion interface{}
+
+// SecretClaimListerExpansion allows custom methods to be added to
+// SecretClaimLister.
+type SecretClaimListerExpansion interface{}
+
+// SecretClaimNamespaceListerExpansion allows custom methods to be added to
+// SecretClaimNamespaceLister.
+type SecretClaimNamespaceListerExpansion interface{}
+
+// EventSubscriptionListerExpansion allows custom methods to be added to
+// EventSubscriptionLister.
+type EventSubscriptionListerExpansion interface{}
+
+// TTLPolicyListerExpansion allows custom methods to be added to
+// TTLPolicyLister.
+type TTLPolicyListerExpansion interface{}
+
 // APIResourceSchemaListerExpansion allows custom methods to be added to
 // APIResourceSchemaLister.
 type APIResourceSchemaListerExpansion interface{}
@@ -18,6 +18,38 @@ limitations under the License.
 
 package v1alpha1
 
+// AccessGrantListerExpansion allows custom methods to be added to
+// AccessGrantLister.
+type AccessGrantListerExpansion interface{}
+
+// DenyPolicyListerExpansion allows custom methods to be added to
+// DenyPolicyLister.
+type DenyPolicyListerExpansion interface{}
+
+// HomeWorkspacePolicyListerExpansion allows custom methods to be added to
+// HomeWorkspacePolicyLister.
+type HomeWorkspacePolicyListerExpansion interface{}
+
+// ObjectTransferListerExpansion allows custom methods to be added to
+// ObjectTransferLister.
+type ObjectTransferListerExpansion interface{}
+
+// ReplicationListerExpansion allows custom methods to be added to
+// ReplicationLister.
+type ReplicationListerExpansion interface{}
+
+// PolicyBundleListerExpansion allows custom methods to be added to
+// PolicyBundleLister.
+type PolicyBundleListerExpansion interface{}
+
+// BulkWorkspaceOperationListerExpansion allows custom methods to be added to
+// BulkWorkspaceOperationLister.
+type BulkWorkspaceOperationListerExpansion interface{}
+
+// VirtualWorkspaceListerExpansion allows custom methods to be added to
+// VirtualWorkspaceLister.
+type VirtualWorkspaceListerExpansion interface{}
+
 // ClusterWorkspaceListerExpansion allows custom methods to be added to
 // ClusterWorkspaceLister.
 type ClusterWorkspaceListerExpansion interface{}
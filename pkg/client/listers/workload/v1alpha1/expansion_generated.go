@@ -18,6 +18,30 @@ limitations under the License.
 
 package v1alpha1
 
+// CertificateListerExpansion allows custom methods to be added to
+// CertificateLister.
+type CertificateListerExpansion interface{}
+
+// CertificateNamespaceListerExpansion allows custom methods to be added to
+// CertificateNamespaceLister.
+type CertificateNamespaceListerExpansion interface{}
+
+// DNSRecordListerExpansion allows custom methods to be added to
+// DNSRecordLister.
+type DNSRecordListerExpansion interface{}
+
+// DNSRecordNamespaceListerExpansion allows custom methods to be added to
+// DNSRecordNamespaceLister.
+type DNSRecordNamespaceListerExpansion interface{}
+
+// DNSZoneListerExpansion allows custom methods to be added to
+// DNSZoneLister.
+type DNSZoneListerExpansion interface{}
+
 // WorkloadClusterListerExpansion allows custom methods to be added to
 // WorkloadClusterLister.
 type WorkloadClusterListerExpansion interface{}
+
+// WorkspaceWorkloadUsageListerExpansion allows custom methods to be added to
+// WorkspaceWorkloadUsageLister.
+type WorkspaceWorkloadUsageListerExpansion interface{}
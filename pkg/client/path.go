@@ -0,0 +1,63 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package client contains helpers to construct kcp clientsets for workspaces.
+package client
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/client-go/rest"
+
+	"github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
+)
+
+var reClusterName = regexp.MustCompile(`^([a-z]([a-z0-9-]{0,61}[a-z0-9])?:)*[a-z]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// ParsePath returns the logical cluster of the workspace with the given path, e.g.
+// root:org:team.
+func ParsePath(path string) (logicalcluster.Name, error) {
+	if !reClusterName.MatchString(path) {
+		return logicalcluster.Name{}, fmt.Errorf("invalid workspace path %q", path)
+	}
+	return logicalcluster.New(path), nil
+}
+
+// ForPath returns the clientset of the workspace with the given path.
+func ForPath(client versioned.ClusterInterface, path string) (versioned.Interface, error) {
+	clusterName, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	return client.Cluster(clusterName), nil
+}
+
+// NewForConfigAndPath creates a clientset for the workspace with the given path. The
+// config must point to the kcp server, not to a workspace.
+func NewForConfigAndPath(config *rest.Config, path string) (versioned.Interface, error) {
+	clusterName, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	client, err := versioned.NewClusterForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return client.Cluster(clusterName), nil
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/client/clusterfake"
+)
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    logicalcluster.Name
+		wantErr bool
+	}{
+		{path: "root", want: logicalcluster.New("root")},
+		{path: "root:org:team-1", want: logicalcluster.New("root:org:team-1")},
+		{path: "", wantErr: true},
+		{path: "root:", wantErr: true},
+		{path: ":root", wantErr: true},
+		{path: "root::org", wantErr: true},
+		{path: "root:Org", wantErr: true},
+		{path: "root:org-", wantErr: true},
+		{path: "/clusters/root:org", wantErr: true},
+		{path: "*", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := ParsePath(tt.path)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestForPath(t *testing.T) {
+	team := logicalcluster.New("root:org:team")
+	c := clusterfake.NewSimpleClusterClientset(&tenancyv1alpha1.ClusterWorkspace{ObjectMeta: metav1.ObjectMeta{ClusterName: team.String(), Name: "child"}})
+
+	client, err := ForPath(c, "root:org:team")
+	require.NoError(t, err)
+	_, err = client.TenancyV1alpha1().ClusterWorkspaces().Get(context.Background(), "child", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	_, err = ForPath(c, "root:org:")
+	require.Error(t, err)
+}
+
+func TestNewForConfigAndPath(t *testing.T) {
+	_, err := NewForConfigAndPath(&rest.Config{Host: "https://localhost:6443"}, "root:org")
+	require.NoError(t, err)
+
+	_, err = NewForConfigAndPath(&rest.Config{Host: "https://localhost:6443"}, "root:Org")
+	require.Error(t, err)
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/kcp-dev/kcp/pkg/cliplugins/explain/plugin"
+)
+
+var (
+	explainExample = `
+	# Get the documentation of a resource bound in the current workspace and its fields.
+	%[1]s explain widgets
+
+	# Get the documentation of a field of a bound resource.
+	%[1]s explain widgets.spec.parts --api-version example.com/v1
+
+	# Get the fields of all levels, examples and claim requirements supplied by the API provider.
+	%[1]s explain widgets --extended
+`
+)
+
+// New provides a cobra command explaining bound APIs.
+func New(streams genericclioptions.IOStreams) (*cobra.Command, error) {
+	opts := plugin.NewOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:          "explain <resource>[.<field>...] [--api-version <group>/<version>] [--extended]",
+		Short:        "Get the documentation of APIs bound in a workspace",
+		Example:      fmt.Sprintf(explainExample, "kubectl kcp"),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return c.Help()
+			}
+			if err := opts.Validate(); err != nil {
+				return err
+			}
+			config, err := plugin.NewConfig(opts)
+			if err != nil {
+				return err
+			}
+
+			return config.Explain(c.Context(), args[0])
+		},
+	}
+	opts.BindFlags(cmd)
+
+	return cmd, nil
+}
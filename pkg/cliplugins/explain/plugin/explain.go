@@ -0,0 +1,232 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/kcp-dev/kcp/pkg/apidocs"
+)
+
+// Explain writes the documentation of a bound resource, or of a field of it, given as
+// <resource>[.<field>...]. The resource is matched against the plural, singular, kind
+// and short names of the bound resources.
+func (c *Config) Explain(ctx context.Context, path string) error {
+	config, err := clientcmd.NewDefaultClientConfig(*c.startingConfig, c.overrides).ClientConfig()
+	if err != nil {
+		return err
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	raw, err := discoveryClient.RESTClient().Get().AbsPath(apidocs.Path).Do(ctx).Raw()
+	if err != nil {
+		return fmt.Errorf("failed to get the API documentation: %w", err)
+	}
+	var doc apidocs.Documentation
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return fmt.Errorf("failed to decode the API documentation: %w", err)
+	}
+
+	parts := strings.Split(path, ".")
+	rd, err := findResource(doc.Resources, parts[0], c.groupVersion.Group)
+	if err != nil {
+		return err
+	}
+	vd, err := findVersion(rd, c.groupVersion.Version)
+	if err != nil {
+		return err
+	}
+
+	return render(c.Out, rd, vd, strings.Join(parts[1:], "."), c.extended)
+}
+
+// findResource returns the bound resource with the given name, restricted to the group if not empty.
+func findResource(resources []apidocs.ResourceDocumentation, name, group string) (*apidocs.ResourceDocumentation, error) {
+	var found []*apidocs.ResourceDocumentation
+	for i := range resources {
+		rd := &resources[i]
+		if group != "" && rd.Group != group {
+			continue
+		}
+		names := append([]string{rd.Names.Plural, rd.Names.Singular, rd.Names.Kind}, rd.Names.ShortNames...)
+		for _, n := range names {
+			if strings.EqualFold(n, name) {
+				found = append(found, rd)
+				break
+			}
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("no bound resource %q found in the current workspace", name)
+	case 1:
+		return found[0], nil
+	default:
+		groups := make([]string, 0, len(found))
+		for _, rd := range found {
+			groups = append(groups, schema.GroupResource{Group: rd.Group, Resource: rd.Resource}.String())
+		}
+		return nil, fmt.Errorf("%q is ambiguous, select the group with --api-version: %s", name, strings.Join(groups, ", "))
+	}
+}
+
+// findVersion returns the given version, or the storage version if empty.
+func findVersion(rd *apidocs.ResourceDocumentation, version string) (*apidocs.VersionDocumentation, error) {
+	for i := range rd.Versions {
+		if vd := &rd.Versions[i]; (version == "" && vd.Storage) || (version != "" && vd.Name == version) {
+			return vd, nil
+		}
+	}
+	if version == "" && len(rd.Versions) > 0 {
+		return &rd.Versions[0], nil
+	}
+	return nil, fmt.Errorf("version %q of %s is not served", version, schema.GroupResource{Group: rd.Group, Resource: rd.Resource})
+}
+
+// render writes the documentation in the format of kubectl explain. Extended documentation
+// lists the fields of all levels below the given field, followed by the documentation of the
+// APIExport owner and the claim requirements.
+func render(w io.Writer, rd *apidocs.ResourceDocumentation, vd *apidocs.VersionDocumentation, fieldPath string, extended bool) error {
+	var field *apidocs.Field
+	if fieldPath != "" {
+		for i := range vd.Fields {
+			if vd.Fields[i].Path == fieldPath {
+				field = &vd.Fields[i]
+				break
+			}
+		}
+		if field == nil {
+			return fmt.Errorf("field %q does not exist in %s", fieldPath, schema.GroupResource{Group: rd.Group, Resource: rd.Resource})
+		}
+	}
+
+	fmt.Fprintf(w, "GROUP:      %s\n", rd.Group)
+	fmt.Fprintf(w, "KIND:       %s\n", rd.Names.Kind)
+	fmt.Fprintf(w, "VERSION:    %s\n", vd.Name)
+	fmt.Fprintf(w, "EXPORT:     %s\n", rd.Export)
+	fmt.Fprintln(w)
+
+	description := vd.Description
+	if field != nil {
+		fmt.Fprintf(w, "FIELD:      %s <%s>\n\n", field.Path, field.Type)
+		description = field.Description
+	}
+	fmt.Fprintln(w, "DESCRIPTION:")
+	if description == "" {
+		description = "<empty>"
+	}
+	writeIndented(w, 5, description)
+	if field != nil {
+		if len(field.Enum) > 0 {
+			fmt.Fprintf(w, "\n     Allowed values: %s\n", strings.Join(field.Enum, ", "))
+		}
+		if field.Default != "" {
+			fmt.Fprintf(w, "\n     Default: %s\n", field.Default)
+		}
+	}
+	if field == nil && vd.Deprecated {
+		fmt.Fprintln(w)
+		writeIndented(w, 5, "DEPRECATED: "+vd.DeprecationWarning)
+	}
+
+	prefix := ""
+	if field != nil {
+		prefix = field.Path + "."
+	}
+	var children []apidocs.Field
+	for _, f := range vd.Fields {
+		if !strings.HasPrefix(f.Path, prefix) {
+			continue
+		}
+		if rel := strings.TrimPrefix(f.Path, prefix); extended || !strings.Contains(rel, ".") {
+			f.Path = rel
+			children = append(children, f)
+		}
+	}
+	if len(children) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "FIELDS:")
+		for i, f := range children {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			required := ""
+			if f.Required {
+				required = " -required-"
+			}
+			fmt.Fprintf(w, "   %s\t<%s>%s\n", f.Path, f.Type, required)
+			if f.Description != "" {
+				writeIndented(w, 5, f.Description)
+			}
+		}
+	}
+
+	if !extended {
+		return nil
+	}
+	if rd.Description != "" {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "NOTES:")
+		writeIndented(w, 5, rd.Description)
+	}
+	if len(rd.Examples) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "EXAMPLES:")
+		for i, e := range rd.Examples {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			fmt.Fprintf(w, "   # %s\n", e.Title)
+			if e.Description != "" {
+				writeIndented(w, 3, "# "+strings.ReplaceAll(strings.TrimSpace(e.Description), "\n", "\n# "))
+			}
+			writeIndented(w, 3, e.Manifest)
+		}
+	}
+	if len(rd.RequiredClaims) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "REQUIRED CLAIMS:")
+		for _, claim := range rd.RequiredClaims {
+			fmt.Fprintf(w, "   %s\n", schema.GroupResource{Group: claim.Group, Resource: claim.Resource})
+		}
+	}
+
+	return nil
+}
+
+// writeIndented writes the text with each line indented by the given number of spaces.
+func writeIndented(w io.Writer, indent int, text string) {
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		if line == "" {
+			fmt.Fprintln(w)
+			continue
+		}
+		fmt.Fprintf(w, "%s%s\n", strings.Repeat(" ", indent), line)
+	}
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kcp-dev/kcp/pkg/apidocs"
+)
+
+func TestFindResource(t *testing.T) {
+	resources := []apidocs.ResourceDocumentation{
+		{Group: "example.com", Resource: "widgets", Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets", Singular: "widget", Kind: "Widget", ShortNames: []string{"wd"}}},
+		{Group: "example.com", Resource: "gadgets", Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "gadgets", Singular: "gadget", Kind: "Gadget"}},
+		{Group: "other.io", Resource: "gadgets", Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "gadgets", Singular: "gadget", Kind: "Gadget"}},
+	}
+
+	tests := map[string]struct {
+		name    string
+		group   string
+		want    string
+		wantErr bool
+	}{
+		"plural":           {name: "widgets", want: "widgets.example.com"},
+		"kind":             {name: "widget", want: "widgets.example.com"},
+		"case insensitive": {name: "Widget", want: "widgets.example.com"},
+		"short name":       {name: "wd", want: "widgets.example.com"},
+		"ambiguous":        {name: "gadgets", wantErr: true},
+		"group":            {name: "gadgets", group: "other.io", want: "gadgets.other.io"},
+		"not bound":        {name: "gizmos", wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rd, err := findResource(resources, tt.name, tt.group)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, rd.Resource+"."+rd.Group)
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	rd := &apidocs.ResourceDocumentation{
+		Group:       "example.com",
+		Resource:    "widgets",
+		Names:       apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets", Singular: "widget", Kind: "Widget"},
+		Export:      "root:org:provider|widgets",
+		Description: "Widgets are shipped within a day.",
+		Examples: []apidocs.Example{
+			{Title: "A small widget", Manifest: "kind: Widget\nspec:\n  size: 1\n"},
+		},
+		RequiredClaims: []metav1.GroupResource{{Resource: "secrets"}},
+	}
+	vd := &apidocs.VersionDocumentation{
+		Name:        "v1",
+		Description: "Widget is a widget.",
+		Fields: []apidocs.Field{
+			{Path: "spec", Type: "Object"},
+			{Path: "spec.parts", Type: "[]Object"},
+			{Path: "spec.parts.name", Type: "string"},
+			{Path: "spec.size", Type: "integer", Required: true, Description: "size of the widget."},
+		},
+	}
+
+	tests := map[string]struct {
+		fieldPath string
+		extended  bool
+		want      string
+		wantErr   bool
+	}{
+		"field": {
+			fieldPath: "spec",
+			want: `GROUP:      example.com
+KIND:       Widget
+VERSION:    v1
+EXPORT:     root:org:provider|widgets
+
+FIELD:      spec <Object>
+
+DESCRIPTION:
+     <empty>
+
+FIELDS:
+   parts	<[]Object>
+
+   size	<integer> -required-
+     size of the widget.
+`,
+		},
+		"extended": {
+			extended: true,
+			want: `GROUP:      example.com
+KIND:       Widget
+VERSION:    v1
+EXPORT:     root:org:provider|widgets
+
+DESCRIPTION:
+     Widget is a widget.
+
+FIELDS:
+   spec	<Object>
+
+   spec.parts	<[]Object>
+
+   spec.parts.name	<string>
+
+   spec.size	<integer> -required-
+     size of the widget.
+
+NOTES:
+     Widgets are shipped within a day.
+
+EXAMPLES:
+   # A small widget
+   kind: Widget
+   spec:
+     size: 1
+
+REQUIRED CLAIMS:
+   secrets
+`,
+		},
+		"unknown field": {
+			fieldPath: "spec.color",
+			wantErr:   true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := render(&buf, rd, vd, tt.fieldPath, tt.extended)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, buf.String())
+		})
+	}
+}
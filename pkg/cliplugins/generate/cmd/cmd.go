@@ -0,0 +1,72 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/kcp-dev/kcp/pkg/cliplugins/generate/plugin"
+)
+
+var (
+	generateClientExample = `
+	# Generate typed cluster-aware clients for the APIs bound in the current workspace.
+	%[1]s generate client --output-dir ./pkg/client
+`
+)
+
+// New provides a cobra command for code generation.
+func New(streams genericclioptions.IOStreams) (*cobra.Command, error) {
+	opts := plugin.NewOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:              "generate",
+		Short:            "Generates code for APIs bound in a workspace",
+		SilenceUsage:     true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	opts.BindFlags(cmd)
+
+	clientCmd := &cobra.Command{
+		Use:          "client --output-dir <dir>",
+		Short:        "Generate typed cluster-aware clients, informers and listers for bound APIs",
+		Example:      fmt.Sprintf(generateClientExample, "kubectl kcp"),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := opts.Validate(); err != nil {
+				return err
+			}
+			config, err := plugin.NewConfig(opts)
+			if err != nil {
+				return err
+			}
+
+			return config.GenerateClient(c.Context())
+		},
+	}
+
+	cmd.AddCommand(clientCmd)
+
+	return cmd, nil
+}
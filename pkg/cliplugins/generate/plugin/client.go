@@ -0,0 +1,166 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+
+	kcpclientset "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
+)
+
+// GenerateClient generates typed clients, informers and listers for all APIs
+// bound in the current workspace and writes them to the output directory.
+func (c *Config) GenerateClient(ctx context.Context) error {
+	config, err := clientcmd.NewDefaultClientConfig(*c.startingConfig, c.overrides).ClientConfig()
+	if err != nil {
+		return err
+	}
+
+	kcpClient, err := kcpclientset.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("failed to create kcp client: %w", err)
+	}
+	bindings, err := kcpClient.ApisV1alpha1().APIBindings().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list APIBindings: %w", err)
+	}
+	bound := sets.NewString()
+	for _, binding := range bindings.Items {
+		for _, r := range binding.Status.BoundResources {
+			bound.Insert(schema.GroupResource{Group: r.Group, Resource: r.Resource}.String())
+		}
+	}
+	if bound.Len() == 0 {
+		return fmt.Errorf("no bound APIs found in the current workspace")
+	}
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return err
+	}
+	_, resourceLists, err := discoveryClient.ServerGroupsAndResources()
+	if err != nil && len(resourceLists) == 0 {
+		return fmt.Errorf("failed to discover APIs: %w", err)
+	}
+
+	raw, err := discoveryClient.RESTClient().Get().AbsPath("/openapi/v2").Do(ctx).Raw()
+	if err != nil {
+		return fmt.Errorf("failed to get OpenAPI: %w", err)
+	}
+	var swagger spec.Swagger
+	if err := json.Unmarshal(raw, &swagger); err != nil {
+		return fmt.Errorf("failed to decode OpenAPI: %w", err)
+	}
+	definitionsByGVK := definitionNamesByGVK(swagger.Definitions)
+
+	resources, err := boundResources(resourceLists, bound, definitionsByGVK)
+	if err != nil {
+		return err
+	}
+
+	files, err := Generate(resources, swagger.Definitions)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		filename := filepath.Join(c.outputDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filename, files[name], 0644); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.Out, "Wrote %s\n", filename)
+	}
+
+	return nil
+}
+
+// boundResources returns the served versions of the bound group resources.
+func boundResources(resourceLists []*metav1.APIResourceList, bound sets.String, definitionsByGVK map[schema.GroupVersionKind]string) ([]Resource, error) {
+	var resources []Resource
+	for _, list := range resourceLists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			return nil, err
+		}
+		subresources := sets.NewString()
+		for _, r := range list.APIResources {
+			subresources.Insert(r.Name)
+		}
+		for _, r := range list.APIResources {
+			if strings.Contains(r.Name, "/") || !bound.Has(schema.GroupResource{Group: gv.Group, Resource: r.Name}.String()) {
+				continue
+			}
+			gvk := gv.WithKind(r.Kind)
+			definition, ok := definitionsByGVK[gvk]
+			if !ok {
+				return nil, fmt.Errorf("no OpenAPI definition found for %s", gvk)
+			}
+			resources = append(resources, Resource{
+				GroupVersionKind: gvk,
+				Resource:         r.Name,
+				Namespaced:       r.Namespaced,
+				HasStatus:        subresources.Has(r.Name + "/status"),
+				Definition:       definition,
+			})
+		}
+	}
+	return resources, nil
+}
+
+// definitionNamesByGVK indexes OpenAPI definitions by their x-kubernetes-group-version-kind extension.
+func definitionNamesByGVK(definitions spec.Definitions) map[schema.GroupVersionKind]string {
+	ret := map[schema.GroupVersionKind]string{}
+	for name, def := range definitions {
+		gvks, ok := def.Extensions["x-kubernetes-group-version-kind"].([]interface{})
+		if !ok {
+			continue
+		}
+		for _, item := range gvks {
+			m, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			group, _ := m["group"].(string)
+			version, _ := m["version"].(string)
+			kind, _ := m["kind"].(string)
+			ret[schema.GroupVersionKind{Group: group, Version: version, Kind: kind}] = name
+		}
+	}
+	return ret
+}
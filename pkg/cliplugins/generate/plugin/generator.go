@@ -0,0 +1,398 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// Resource is a bound API resource to generate a client for.
+type Resource struct {
+	schema.GroupVersionKind
+
+	// Resource is the plural resource name.
+	Resource   string
+	Namespaced bool
+	HasStatus  bool
+
+	// Definition is the name of the OpenAPI definition of the kind.
+	Definition string
+}
+
+// wellKnownTypes maps OpenAPI definitions to Go types and their import.
+var wellKnownTypes = map[string][2]string{
+	"io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta":    {"metav1.ObjectMeta", `metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"`},
+	"io.k8s.apimachinery.pkg.apis.meta.v1.ListMeta":      {"metav1.ListMeta", `metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"`},
+	"io.k8s.apimachinery.pkg.apis.meta.v1.Time":          {"metav1.Time", `metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"`},
+	"io.k8s.apimachinery.pkg.apis.meta.v1.MicroTime":     {"metav1.MicroTime", `metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"`},
+	"io.k8s.apimachinery.pkg.apis.meta.v1.Duration":      {"metav1.Duration", `metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"`},
+	"io.k8s.apimachinery.pkg.apis.meta.v1.LabelSelector": {"metav1.LabelSelector", `metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"`},
+	"io.k8s.apimachinery.pkg.apis.meta.v1.Condition":     {"metav1.Condition", `metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"`},
+	"io.k8s.apimachinery.pkg.util.intstr.IntOrString":    {"intstr.IntOrString", `"k8s.io/apimachinery/pkg/util/intstr"`},
+	"io.k8s.apimachinery.pkg.api.resource.Quantity":      {"resource.Quantity", `"k8s.io/apimachinery/pkg/api/resource"`},
+}
+
+// Generate returns the Go sources of typed, cluster-aware clients, informers
+// and listers for the given resources, keyed by file path relative to the
+// output directory. Every group version becomes a package <group>/<version>,
+// where <group> is the first DNS label of the group, or the whole group if
+// the first label is shared with another group.
+func Generate(resources []Resource, definitions spec.Definitions) (map[string][]byte, error) {
+	byGroupVersion := map[schema.GroupVersion][]Resource{}
+	groups := sets.NewString()
+	for _, r := range resources {
+		gv := r.GroupVersion()
+		byGroupVersion[gv] = append(byGroupVersion[gv], r)
+		groups.Insert(gv.Group)
+	}
+	packages, err := groupPackageNames(groups.List())
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string][]byte{}
+	for gv, rs := range byGroupVersion {
+		sort.Slice(rs, func(i, j int) bool { return rs[i].Kind < rs[j].Kind })
+		dir := path.Join(packages[gv.Group], gv.Version)
+
+		g := &typeGenerator{
+			definitions: definitions,
+			imports:     sets.NewString(),
+			generated:   map[string]string{},
+			types:       map[string]string{},
+		}
+		for _, r := range rs {
+			if err := g.kindType(r); err != nil {
+				return nil, err
+			}
+		}
+		src, err := g.source(gv.Version)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate types for %s: %w", gv, err)
+		}
+		files[path.Join(dir, "types.go")] = src
+
+		data := struct {
+			Package   string
+			Group     string
+			Version   string
+			Resources []Resource
+		}{gv.Version, gv.Group, gv.Version, rs}
+		if files[path.Join(dir, "register.go")], err = render(registerTemplate, data); err != nil {
+			return nil, err
+		}
+		for _, r := range rs {
+			data := struct {
+				Package string
+				Resource
+			}{gv.Version, r}
+			if files[path.Join(dir, strings.ToLower(r.Kind)+"_client.go")], err = render(clientTemplate, data); err != nil {
+				return nil, err
+			}
+			if files[path.Join(dir, strings.ToLower(r.Kind)+"_informer.go")], err = render(informerTemplate, data); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return files, nil
+}
+
+// groupPackageNames maps groups to package names. Groups get the first DNS
+// label of the group, "core" for the core group, unless that is ambiguous,
+// e.g. for apps.example.com and apps.other.io. Then the whole group is used.
+func groupPackageNames(groups []string) (map[string]string, error) {
+	short := map[string][]string{}
+	for _, group := range groups {
+		name := "core"
+		if group != "" {
+			name = packageName(strings.SplitN(group, ".", 2)[0])
+		}
+		short[name] = append(short[name], group)
+	}
+
+	packages := map[string]string{}
+	owners := map[string]string{}
+	for name, groups := range short {
+		for _, group := range groups {
+			pkg := name
+			if len(groups) > 1 {
+				pkg = packageName(group)
+			}
+			if other, ok := owners[pkg]; ok {
+				return nil, fmt.Errorf("groups %q and %q both map to package %q", other, group, pkg)
+			}
+			owners[pkg] = group
+			packages[group] = pkg
+		}
+	}
+	return packages, nil
+}
+
+// packageName returns a lower-case Go package name for s.
+func packageName(s string) string {
+	return strings.ToLower(goName(s))
+}
+
+// goName converts a JSON field name into an exported Go identifier.
+func goName(s string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	name := b.String()
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "X" + name
+	}
+	return name
+}
+
+type typeGenerator struct {
+	definitions spec.Definitions
+	imports     sets.String
+	// generated maps definition names to generated type names.
+	generated map[string]string
+	// types maps generated type names to their source.
+	types map[string]string
+}
+
+func (g *typeGenerator) kindType(r Resource) error {
+	def, ok := g.definitions[r.Definition]
+	if !ok {
+		return fmt.Errorf("OpenAPI definition %q of %s not found", r.Definition, r.GroupVersionKind)
+	}
+	g.imports.Insert(`metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"`)
+
+	var b strings.Builder
+	writeComment(&b, "", def.Description, r.Kind+" is the Schema for the "+r.Resource+" API.")
+	fmt.Fprintf(&b, "type %s struct {\n", r.Kind)
+	b.WriteString("\tmetav1.TypeMeta   `json:\",inline\"`\n")
+	b.WriteString("\tmetav1.ObjectMeta `json:\"metadata,omitempty\"`\n\n")
+	if err := g.fields(&b, r.Kind, def, sets.NewString("apiVersion", "kind", "metadata")); err != nil {
+		return err
+	}
+	b.WriteString("}\n\n")
+	fmt.Fprintf(&b, "// %sList is a list of %s.\n", r.Kind, r.Kind)
+	fmt.Fprintf(&b, "type %sList struct {\n", r.Kind)
+	b.WriteString("\tmetav1.TypeMeta `json:\",inline\"`\n")
+	b.WriteString("\tmetav1.ListMeta `json:\"metadata,omitempty\"`\n\n")
+	fmt.Fprintf(&b, "\tItems []%s `json:\"items\"`\n", r.Kind)
+	b.WriteString("}\n")
+
+	g.generated[r.Definition] = r.Kind
+	g.types[r.Kind] = b.String()
+	return nil
+}
+
+func (g *typeGenerator) fields(b *strings.Builder, parent string, s spec.Schema, skip sets.String) error {
+	required := sets.NewString(s.Required...)
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		if !skip.Has(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		prop := s.Properties[name]
+		fieldName := goName(name)
+		typ, err := g.goType(parent+fieldName, prop)
+		if err != nil {
+			return fmt.Errorf("field %s.%s: %w", parent, name, err)
+		}
+		tag := name
+		if !required.Has(name) {
+			tag += ",omitempty"
+		}
+		writeComment(b, "\t", prop.Description, "")
+		fmt.Fprintf(b, "\t%s %s `json:\"%s\"`\n", fieldName, typ, tag)
+	}
+	return nil
+}
+
+func (g *typeGenerator) goType(name string, s spec.Schema) (string, error) {
+	if ref := s.Ref.String(); ref != "" {
+		return g.refType(strings.TrimPrefix(ref, "#/definitions/"))
+	}
+	if len(s.AllOf) == 1 {
+		return g.goType(name, s.AllOf[0])
+	}
+	if v, ok := s.Extensions.GetBool("x-kubernetes-int-or-string"); ok && v {
+		g.imports.Insert(`"k8s.io/apimachinery/pkg/util/intstr"`)
+		return "intstr.IntOrString", nil
+	}
+
+	typ := ""
+	if len(s.Type) > 0 {
+		typ = s.Type[0]
+	} else if len(s.Properties) > 0 {
+		typ = "object"
+	}
+
+	switch typ {
+	case "string":
+		switch s.Format {
+		case "date-time":
+			g.imports.Insert(`metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"`)
+			return "metav1.Time", nil
+		case "byte":
+			return "[]byte", nil
+		}
+		return "string", nil
+	case "integer":
+		if s.Format == "int32" {
+			return "int32", nil
+		}
+		return "int64", nil
+	case "number":
+		return "float64", nil
+	case "boolean":
+		return "bool", nil
+	case "array":
+		if s.Items == nil || s.Items.Schema == nil {
+			return "", fmt.Errorf("array without items schema")
+		}
+		elem, err := g.goType(name+"Item", *s.Items.Schema)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + elem, nil
+	case "object":
+		if len(s.Properties) > 0 {
+			return g.structType(name, s)
+		}
+		if s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil {
+			elem, err := g.goType(name+"Value", *s.AdditionalProperties.Schema)
+			if err != nil {
+				return "", err
+			}
+			return "map[string]" + elem, nil
+		}
+	}
+
+	// arbitrary JSON, e.g. x-kubernetes-preserve-unknown-fields
+	g.imports.Insert(`"k8s.io/apimachinery/pkg/runtime"`)
+	return "runtime.RawExtension", nil
+}
+
+func (g *typeGenerator) refType(def string) (string, error) {
+	if wk, ok := wellKnownTypes[def]; ok {
+		g.imports.Insert(wk[1])
+		return wk[0], nil
+	}
+	if name, ok := g.generated[def]; ok {
+		return name, nil
+	}
+	s, ok := g.definitions[def]
+	if !ok {
+		return "", fmt.Errorf("OpenAPI definition %q not found", def)
+	}
+	parts := strings.Split(def, ".")
+	name := goName(parts[len(parts)-1])
+	g.generated[def] = name
+	if _, err := g.structType(name, s); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+func (g *typeGenerator) structType(name string, s spec.Schema) (string, error) {
+	if _, ok := g.types[name]; ok {
+		return name, nil
+	}
+	// reserve the name before recursing into the fields
+	g.types[name] = ""
+
+	var b strings.Builder
+	writeComment(&b, "", s.Description, name+" is generated from the OpenAPI schema.")
+	fmt.Fprintf(&b, "type %s struct {\n", name)
+	if err := g.fields(&b, name, s, sets.NewString()); err != nil {
+		return "", err
+	}
+	b.WriteString("}\n")
+	g.types[name] = b.String()
+	return name, nil
+}
+
+func (g *typeGenerator) source(pkg string) ([]byte, error) {
+	names := make([]string, 0, len(g.types))
+	for name := range g.types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b bytes.Buffer
+	b.WriteString(header)
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	if g.imports.Len() > 0 {
+		b.WriteString("import (\n")
+		for _, imp := range g.imports.List() {
+			fmt.Fprintf(&b, "\t%s\n", imp)
+		}
+		b.WriteString(")\n\n")
+	}
+	for _, name := range names {
+		b.WriteString(g.types[name])
+		b.WriteString("\n")
+	}
+	return format.Source(b.Bytes())
+}
+
+func writeComment(b *strings.Builder, indent, description, fallback string) {
+	if description == "" {
+		description = fallback
+	}
+	if description == "" {
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSpace(description), "\n") {
+		fmt.Fprintf(b, "%s// %s\n", indent, strings.TrimRight(line, " "))
+	}
+}
+
+func render(tmpl *template.Template, data interface{}) ([]byte, error) {
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format %s: %w", tmpl.Name(), err)
+	}
+	return src, nil
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+const widgetOpenAPI = `{
+  "io.example.widgets.v1.Widget": {
+    "description": "Widget is a widget.",
+    "type": "object",
+    "properties": {
+      "apiVersion": {"type": "string"},
+      "kind": {"type": "string"},
+      "metadata": {"$ref": "#/definitions/io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"},
+      "spec": {
+        "type": "object",
+        "required": ["size"],
+        "properties": {
+          "size": {"type": "integer", "format": "int32"},
+          "port": {"x-kubernetes-int-or-string": true},
+          "labels": {"type": "object", "additionalProperties": {"type": "string"}},
+          "parts": {"type": "array", "items": {"type": "object", "properties": {"part-name": {"type": "string"}}}},
+          "config": {"type": "object", "x-kubernetes-preserve-unknown-fields": true}
+        }
+      },
+      "status": {
+        "type": "object",
+        "properties": {
+          "lastUpdated": {"type": "string", "format": "date-time"}
+        }
+      }
+    },
+    "x-kubernetes-group-version-kind": [{"group": "widgets.example.io", "version": "v1", "kind": "Widget"}]
+  }
+}`
+
+func TestGenerate(t *testing.T) {
+	var definitions spec.Definitions
+	require.NoError(t, json.Unmarshal([]byte(widgetOpenAPI), &definitions))
+
+	byGVK := definitionNamesByGVK(definitions)
+	gvk := schema.GroupVersionKind{Group: "widgets.example.io", Version: "v1", Kind: "Widget"}
+	require.Equal(t, "io.example.widgets.v1.Widget", byGVK[gvk])
+
+	files, err := Generate([]Resource{{
+		GroupVersionKind: gvk,
+		Resource:         "widgets",
+		Namespaced:       true,
+		HasStatus:        true,
+		Definition:       byGVK[gvk],
+	}}, definitions)
+	require.NoError(t, err)
+
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	require.ElementsMatch(t, []string{
+		"widgets/v1/types.go",
+		"widgets/v1/register.go",
+		"widgets/v1/widget_client.go",
+		"widgets/v1/widget_informer.go",
+	}, names)
+
+	types := normalizeSpaces(string(files["widgets/v1/types.go"]))
+	require.Contains(t, types, "package v1")
+	require.Contains(t, types, "// Widget is a widget.\ntype Widget struct {")
+	require.Contains(t, types, "Spec WidgetSpec `json:\"spec,omitempty\"`")
+	require.Contains(t, types, "Size int32 `json:\"size\"`")
+	require.Contains(t, types, "Port intstr.IntOrString `json:\"port,omitempty\"`")
+	require.Contains(t, types, "Labels map[string]string `json:\"labels,omitempty\"`")
+	require.Contains(t, types, "Parts []WidgetSpecPartsItem `json:\"parts,omitempty\"`")
+	require.Contains(t, types, "Config runtime.RawExtension `json:\"config,omitempty\"`")
+	require.Contains(t, types, "PartName string `json:\"part-name,omitempty\"`")
+	require.Contains(t, types, "LastUpdated metav1.Time `json:\"lastUpdated,omitempty\"`")
+	require.Contains(t, types, "type WidgetList struct {")
+
+	client := string(files["widgets/v1/widget_client.go"])
+	require.Contains(t, client, "func (c *WidgetNamespaceClient) Namespace(namespace string) *WidgetClient {")
+	require.Contains(t, client, "func (c *WidgetClient) UpdateStatus(")
+
+	informer := string(files["widgets/v1/widget_informer.go"])
+	require.Contains(t, informer, "func (l *WidgetLister) Get(cluster logicalcluster.Name, namespace, name string) (*Widget, error) {")
+	require.Contains(t, informer, "&Widget{},")
+
+	buildGenerated(t, files)
+}
+
+func TestGenerateGroupCollision(t *testing.T) {
+	var definitions spec.Definitions
+	require.NoError(t, json.Unmarshal([]byte(widgetOpenAPI), &definitions))
+
+	var resources []Resource
+	for _, group := range []string{"widgets.example.io", "widgets.other.io", "gadgets.example.io"} {
+		resources = append(resources, Resource{
+			GroupVersionKind: schema.GroupVersionKind{Group: group, Version: "v1", Kind: "Widget"},
+			Resource:         "widgets",
+			Definition:       "io.example.widgets.v1.Widget",
+		})
+	}
+	files, err := Generate(resources, definitions)
+	require.NoError(t, err)
+
+	dirs := map[string]string{}
+	for name, src := range files {
+		if strings.HasSuffix(name, "/register.go") {
+			group := regexp.MustCompile(`Group: "([^"]+)"`).FindStringSubmatch(string(src))[1]
+			dirs[group] = filepath.Dir(name)
+		}
+	}
+	require.Equal(t, map[string]string{
+		"widgets.example.io": "widgetsexampleio/v1",
+		"widgets.other.io":   "widgetsotherio/v1",
+		"gadgets.example.io": "gadgets/v1",
+	}, dirs)
+
+	_, err = groupPackageNames([]string{"a-b.io", "ab.io", "a.io", "a.com"})
+	require.Error(t, err, "a-b.io and ab.io both map to abio")
+
+	buildGenerated(t, files)
+}
+
+// buildGenerated compiles the generated files in a temporary module with the
+// dependencies of this repository.
+func buildGenerated(t *testing.T, files map[string][]byte) {
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go not found in PATH")
+	}
+	out, err := exec.Command(goBin, "env", "GOMOD").Output()
+	require.NoError(t, err)
+	goMod := strings.TrimSpace(string(out))
+	if goMod == "" || goMod == os.DevNull {
+		t.Skip("not running in a module")
+	}
+
+	dir := t.TempDir()
+	bs, err := ioutil.ReadFile(goMod)
+	require.NoError(t, err)
+	bs = regexp.MustCompile(`(?m)^module .*$`).ReplaceAll(bs, []byte("module example.com/generated"))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "go.mod"), bs, 0644))
+	bs, err = ioutil.ReadFile(filepath.Join(filepath.Dir(goMod), "go.sum"))
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "go.sum"), bs, 0644))
+
+	for name, src := range files {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0755))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), src, 0644))
+	}
+
+	cmd := exec.Command(goBin, "vet", "./...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOPROXY=off", "GOWORK=off")
+	out, err = cmd.CombinedOutput()
+	require.NoError(t, err, "generated code does not compile:\n%s", out)
+}
+
+func normalizeSpaces(s string) string {
+	return regexp.MustCompile("[ \t]+").ReplaceAllString(s, " ")
+}
+
+func TestGoName(t *testing.T) {
+	for in, expected := range map[string]string{
+		"spec":        "Spec",
+		"part-name":   "PartName",
+		"x.y_z":       "XYZ",
+		"2fa":         "X2fa",
+		"alreadyCaml": "AlreadyCaml",
+	} {
+		require.Equal(t, expected, goName(in), in)
+	}
+}
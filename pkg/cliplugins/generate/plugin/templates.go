@@ -0,0 +1,321 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"text/template"
+)
+
+const header = "// Code generated by kubectl kcp generate client. DO NOT EDIT.\n\n"
+
+var registerTemplate = template.Must(template.New("register").Parse(header + `package {{.Package}}
+
+import (
+	"encoding/json"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// SchemeGroupVersion is the group version of the generated types.
+var SchemeGroupVersion = schema.GroupVersion{Group: "{{.Group}}", Version: "{{.Version}}"}
+
+var (
+{{- range .Resources}}
+	// {{.Kind}}Resource is the group version resource of {{.Kind}}.
+	{{.Kind}}Resource = SchemeGroupVersion.WithResource("{{.Resource}}")
+{{- end}}
+)
+
+func fromUnstructured(u *unstructured.Unstructured, obj interface{}) error {
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), obj)
+}
+
+func toUnstructured(obj interface{}) (*unstructured.Unstructured, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	return &unstructured.Unstructured{Object: content}, nil
+}
+
+// deepCopyInto copies in into out through JSON, the generated types are plain
+// JSON types.
+func deepCopyInto(in, out interface{}) {
+	bs, err := json.Marshal(in)
+	if err != nil {
+		panic(err)
+	}
+	if err := json.Unmarshal(bs, out); err != nil {
+		panic(err)
+	}
+}
+{{range .Resources}}
+// DeepCopy returns a deep copy of the {{.Kind}}.
+func (in *{{.Kind}}) DeepCopy() *{{.Kind}} {
+	if in == nil {
+		return nil
+	}
+	out := &{{.Kind}}{}
+	deepCopyInto(in, out)
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *{{.Kind}}) DeepCopyObject() runtime.Object {
+	return in.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the {{.Kind}}List.
+func (in *{{.Kind}}List) DeepCopy() *{{.Kind}}List {
+	if in == nil {
+		return nil
+	}
+	out := &{{.Kind}}List{}
+	deepCopyInto(in, out)
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *{{.Kind}}List) DeepCopyObject() runtime.Object {
+	return in.DeepCopy()
+}
+{{end}}`))
+
+var clientTemplate = template.Must(template.New("client").Parse(header + `package {{.Package}}
+
+import (
+	"context"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+)
+
+// {{.Kind}}ClusterClient is a cluster-aware client for {{.Resource}}.
+type {{.Kind}}ClusterClient struct {
+	client dynamic.ClusterInterface
+}
+
+// New{{.Kind}}ClusterClient returns a cluster-aware client for {{.Resource}}.
+func New{{.Kind}}ClusterClient(client dynamic.ClusterInterface) *{{.Kind}}ClusterClient {
+	return &{{.Kind}}ClusterClient{client: client}
+}
+{{if .Namespaced}}
+// Cluster returns a client for {{.Resource}} in the given logical cluster.
+func (c *{{.Kind}}ClusterClient) Cluster(cluster logicalcluster.Name) *{{.Kind}}NamespaceClient {
+	return &{{.Kind}}NamespaceClient{client: c.client.Cluster(cluster).Resource({{.Kind}}Resource)}
+}
+
+// {{.Kind}}NamespaceClient is a client for {{.Resource}} in one logical cluster.
+type {{.Kind}}NamespaceClient struct {
+	client dynamic.NamespaceableResourceInterface
+}
+
+// Namespace returns a client for {{.Resource}} in the given namespace.
+func (c *{{.Kind}}NamespaceClient) Namespace(namespace string) *{{.Kind}}Client {
+	return &{{.Kind}}Client{client: c.client.Namespace(namespace)}
+}
+{{else}}
+// Cluster returns a client for {{.Resource}} in the given logical cluster.
+func (c *{{.Kind}}ClusterClient) Cluster(cluster logicalcluster.Name) *{{.Kind}}Client {
+	return &{{.Kind}}Client{client: c.client.Cluster(cluster).Resource({{.Kind}}Resource)}
+}
+{{end}}
+// {{.Kind}}Client is a typed client for {{.Resource}}.
+type {{.Kind}}Client struct {
+	client dynamic.ResourceInterface
+}
+
+func (c *{{.Kind}}Client) Get(ctx context.Context, name string, opts metav1.GetOptions) (*{{.Kind}}, error) {
+	u, err := c.client.Get(ctx, name, opts)
+	if err != nil {
+		return nil, err
+	}
+	obj := &{{.Kind}}{}
+	return obj, fromUnstructured(u, obj)
+}
+
+func (c *{{.Kind}}Client) List(ctx context.Context, opts metav1.ListOptions) (*{{.Kind}}List, error) {
+	u, err := c.client.List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	list := &{{.Kind}}List{}
+	return list, from{{.Kind}}List(u, list)
+}
+
+func (c *{{.Kind}}Client) Create(ctx context.Context, obj *{{.Kind}}, opts metav1.CreateOptions) (*{{.Kind}}, error) {
+	return c.write(obj, func(u *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+		return c.client.Create(ctx, u, opts)
+	})
+}
+
+func (c *{{.Kind}}Client) Update(ctx context.Context, obj *{{.Kind}}, opts metav1.UpdateOptions) (*{{.Kind}}, error) {
+	return c.write(obj, func(u *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+		return c.client.Update(ctx, u, opts)
+	})
+}
+{{if .HasStatus}}
+func (c *{{.Kind}}Client) UpdateStatus(ctx context.Context, obj *{{.Kind}}, opts metav1.UpdateOptions) (*{{.Kind}}, error) {
+	return c.write(obj, func(u *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+		return c.client.UpdateStatus(ctx, u, opts)
+	})
+}
+{{end}}
+func (c *{{.Kind}}Client) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	return c.client.Delete(ctx, name, opts)
+}
+
+func (c *{{.Kind}}Client) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	return c.client.Watch(ctx, opts)
+}
+
+func (c *{{.Kind}}Client) write(obj *{{.Kind}}, fn func(*unstructured.Unstructured) (*unstructured.Unstructured, error)) (*{{.Kind}}, error) {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.String(), "{{.Kind}}"
+	u, err := toUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	if u, err = fn(u); err != nil {
+		return nil, err
+	}
+	result := &{{.Kind}}{}
+	return result, fromUnstructured(u, result)
+}
+
+func from{{.Kind}}List(u *unstructured.UnstructuredList, list *{{.Kind}}List) error {
+	list.APIVersion, list.Kind = SchemeGroupVersion.String(), "{{.Kind}}List"
+	list.ResourceVersion = u.GetResourceVersion()
+	list.Continue = u.GetContinue()
+	list.Items = make([]{{.Kind}}, len(u.Items))
+	for i := range u.Items {
+		if err := fromUnstructured(&u.Items[i], &list.Items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+`))
+
+var informerTemplate = template.Must(template.New("informer").Parse(header + `package {{.Package}}
+
+import (
+	"context"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clusters"
+)
+
+// New{{.Kind}}Informer returns an informer for {{.Resource}} in all logical clusters,
+// caching typed *{{.Kind}} objects.
+func New{{.Kind}}Informer(client dynamic.ClusterInterface, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	resource := client.Cluster(logicalcluster.Wildcard).Resource({{.Kind}}Resource)
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				u, err := resource.List(context.TODO(), options)
+				if err != nil {
+					return nil, err
+				}
+				list := &{{.Kind}}List{}
+				return list, from{{.Kind}}List(u, list)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				w, err := resource.Watch(context.TODO(), options)
+				if err != nil {
+					return nil, err
+				}
+				return watch.Filter(w, from{{.Kind}}Event), nil
+			},
+		},
+		&{{.Kind}}{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+// from{{.Kind}}Event converts the unstructured object of a watch event into a
+// *{{.Kind}}, or into a *metav1.Status for error events.
+func from{{.Kind}}Event(e watch.Event) (watch.Event, bool) {
+	u, ok := e.Object.(*unstructured.Unstructured)
+	if !ok {
+		return e, true
+	}
+	if e.Type == watch.Error {
+		status := &metav1.Status{}
+		if err := fromUnstructured(u, status); err != nil {
+			return watch.Event{Type: watch.Error, Object: &errors.NewInternalError(err).ErrStatus}, true
+		}
+		return watch.Event{Type: watch.Error, Object: status}, true
+	}
+	obj := &{{.Kind}}{}
+	if err := fromUnstructured(u, obj); err != nil {
+		return watch.Event{Type: watch.Error, Object: &errors.NewInternalError(err).ErrStatus}, true
+	}
+	return watch.Event{Type: e.Type, Object: obj}, true
+}
+
+// {{.Kind}}Lister lists {{.Resource}} from the indexer of a {{.Kind}} informer.
+// The returned objects are shared with the cache and must not be mutated.
+type {{.Kind}}Lister struct {
+	indexer cache.Indexer
+}
+
+// New{{.Kind}}Lister returns a lister for {{.Resource}}.
+func New{{.Kind}}Lister(indexer cache.Indexer) *{{.Kind}}Lister {
+	return &{{.Kind}}Lister{indexer: indexer}
+}
+
+// List lists {{.Resource}} in all logical clusters matching the selector.
+func (l *{{.Kind}}Lister) List(selector labels.Selector) (ret []*{{.Kind}}, err error) {
+	err = cache.ListAll(l.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*{{.Kind}}))
+	})
+	return ret, err
+}
+
+// Get returns the {{.Kind}} with the given name{{if .Namespaced}} and namespace{{end}} in the given logical cluster.
+func (l *{{.Kind}}Lister) Get(cluster logicalcluster.Name, {{if .Namespaced}}namespace, {{end}}name string) (*{{.Kind}}, error) {
+	key := clusters.ToClusterAwareKey(cluster, name)
+{{- if .Namespaced}}
+	key = namespace + "/" + key
+{{- end}}
+	item, exists, err := l.indexer.GetByKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound({{.Kind}}Resource.GroupResource(), name)
+	}
+	return item.(*{{.Kind}}), nil
+}
+`))
@@ -26,6 +26,7 @@ import (
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 
 	"github.com/kcp-dev/kcp/pkg/cliplugins/workload/plugin"
+	"github.com/kcp-dev/kcp/pkg/syncer/shared"
 )
 
 var (
@@ -109,7 +110,7 @@ func New(streams genericclioptions.IOStreams) (*cobra.Command, error) {
 			}
 
 			workloadClusterName := args[0]
-			if len(workloadClusterName)+len(plugin.SyncerAuthResourcePrefix) > plugin.MaxSyncerAuthResourceName {
+			if len(workloadClusterName)+len(shared.SyncerAuthResourcePrefix) > plugin.MaxSyncerAuthResourceName {
 				return fmt.Errorf("the maximum length of the workload-cluster-name is %d", plugin.MaxSyncerAuthResourceName)
 			}
 
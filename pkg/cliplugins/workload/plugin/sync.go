@@ -45,6 +45,7 @@ import (
 	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
 	kcpclientset "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
 	"github.com/kcp-dev/kcp/pkg/cliplugins/helpers"
+	"github.com/kcp-dev/kcp/pkg/syncer/shared"
 )
 
 //go:embed *.yaml
@@ -58,9 +59,6 @@ const (
 	// The name of the key for the upstream config in the pcluster secret.
 	SyncerSecretConfigKey = "kubeconfig"
 
-	// The prefix for syncer-supporting auth resources in kcp.
-	SyncerAuthResourcePrefix = "syncer-"
-
 	// Max length of service account name (cluster role has no limit)
 	MaxSyncerAuthResourceName = 254
 
@@ -174,7 +172,7 @@ func enableSyncerForWorkspace(ctx context.Context, config *rest.Config, workload
 
 	// Create a service account for the syncer with the necessary permissions. It will
 	// be owned by the workload cluster to ensure cleanup.
-	authResourceName := SyncerAuthResourcePrefix + workloadClusterName
+	authResourceName := shared.SyncerAuthResourcePrefix + workloadClusterName
 	sa, err := kubeClient.CoreV1().ServiceAccounts(namespace).Get(ctx, authResourceName, metav1.GetOptions{})
 
 	switch {
@@ -50,8 +50,17 @@ rules:
   - namespaces
   verbs:
   - "create"
+  - "get"
   - "list"
   - "watch"
+- apiGroups:
+  - ""
+  - "metrics.k8s.io"
+  resources:
+  - pods
+  verbs:
+  - "get"
+  - "list"
 - apiGroups:
   - "apiextensions.k8s.io"
   resources:
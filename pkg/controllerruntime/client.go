@@ -0,0 +1,247 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllerruntime
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clusters"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// NewClusterAwareClient is a cluster.NewClientFunc returning a client that
+//
+//   - reads from the cluster-aware cache, scoped to the logical cluster encoded
+//     in the object key (see NewRequest) or else stored in the context with
+//     WithCluster. Lists without a logical cluster span all logical clusters.
+//   - writes to the logical cluster stored in the context, or else to the one
+//     of the object.
+//
+// Objects of the types in uncachedObjects are read from the server.
+func NewClusterAwareClient(cache cache.Cache, config *rest.Config, options client.Options, uncachedObjects ...client.Object) (client.Client, error) {
+	uncached := map[schema.GroupVersionKind]bool{}
+	for _, obj := range uncachedObjects {
+		gvk, err := apiutil.GVKForObject(obj, options.Scheme)
+		if err != nil {
+			return nil, err
+		}
+		uncached[gvk] = true
+	}
+
+	return &clusterAwareClient{
+		reader:   cache,
+		config:   config,
+		options:  options,
+		uncached: uncached,
+		clients:  map[logicalcluster.Name]client.Client{},
+	}, nil
+}
+
+type clusterAwareClient struct {
+	reader   client.Reader
+	config   *rest.Config
+	options  client.Options
+	uncached map[schema.GroupVersionKind]bool
+
+	lock    sync.Mutex
+	clients map[logicalcluster.Name]client.Client
+}
+
+var _ client.Client = &clusterAwareClient{}
+
+func (c *clusterAwareClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	cluster, name := clusters.SplitClusterAwareKey(key.Name)
+	if cluster.Empty() {
+		cluster, _ = ClusterFrom(ctx)
+	}
+	if cluster.Empty() {
+		return fmt.Errorf("no logical cluster given to get %s", key)
+	}
+
+	if c.isUncached(obj) {
+		cl, err := c.clusterClient(cluster)
+		if err != nil {
+			return err
+		}
+		return cl.Get(ctx, client.ObjectKey{Namespace: key.Namespace, Name: name}, obj)
+	}
+
+	return c.reader.Get(ctx, client.ObjectKey{Namespace: key.Namespace, Name: clusters.ToClusterAwareKey(cluster, name)}, obj)
+}
+
+func (c *clusterAwareClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	cluster, scoped := ClusterFrom(ctx)
+
+	if c.isUncached(list) {
+		if !scoped {
+			cluster = logicalcluster.Wildcard
+		}
+		cl, err := c.clusterClient(cluster)
+		if err != nil {
+			return err
+		}
+		return cl.List(ctx, list, opts...)
+	}
+
+	if err := c.reader.List(ctx, list, opts...); err != nil {
+		return err
+	}
+	if !scoped {
+		return nil
+	}
+
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return err
+	}
+	filtered := make([]runtime.Object, 0, len(items))
+	for _, item := range items {
+		metaObj, err := meta.Accessor(item)
+		if err != nil {
+			return err
+		}
+		if logicalcluster.From(metaObj) == cluster {
+			filtered = append(filtered, item)
+		}
+	}
+	return meta.SetList(list, filtered)
+}
+
+func (c *clusterAwareClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	cl, err := c.clientFor(ctx, obj)
+	if err != nil {
+		return err
+	}
+	return cl.Create(ctx, obj, opts...)
+}
+
+func (c *clusterAwareClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	cl, err := c.clientFor(ctx, obj)
+	if err != nil {
+		return err
+	}
+	return cl.Delete(ctx, obj, opts...)
+}
+
+func (c *clusterAwareClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	cl, err := c.clientFor(ctx, obj)
+	if err != nil {
+		return err
+	}
+	return cl.Update(ctx, obj, opts...)
+}
+
+func (c *clusterAwareClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	cl, err := c.clientFor(ctx, obj)
+	if err != nil {
+		return err
+	}
+	return cl.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *clusterAwareClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	cl, err := c.clientFor(ctx, obj)
+	if err != nil {
+		return err
+	}
+	return cl.DeleteAllOf(ctx, obj, opts...)
+}
+
+func (c *clusterAwareClient) Status() client.StatusWriter {
+	return &clusterAwareStatusWriter{client: c}
+}
+
+func (c *clusterAwareClient) Scheme() *runtime.Scheme {
+	return c.options.Scheme
+}
+
+func (c *clusterAwareClient) RESTMapper() meta.RESTMapper {
+	return c.options.Mapper
+}
+
+// clientFor returns the client for the logical cluster stored in ctx, or else
+// for the one of obj.
+func (c *clusterAwareClient) clientFor(ctx context.Context, obj client.Object) (client.Client, error) {
+	cluster, ok := ClusterFrom(ctx)
+	if !ok {
+		cluster = logicalcluster.From(obj)
+	}
+	if cluster.Empty() {
+		return nil, fmt.Errorf("no logical cluster given for %T %s/%s", obj, obj.GetNamespace(), obj.GetName())
+	}
+	return c.clusterClient(cluster)
+}
+
+func (c *clusterAwareClient) clusterClient(cluster logicalcluster.Name) (client.Client, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if cl, ok := c.clients[cluster]; ok {
+		return cl, nil
+	}
+	cl, err := client.New(ConfigForCluster(c.config, cluster), c.options)
+	if err != nil {
+		return nil, err
+	}
+	c.clients[cluster] = cl
+	return cl, nil
+}
+
+func (c *clusterAwareClient) isUncached(obj runtime.Object) bool {
+	if len(c.uncached) == 0 {
+		return false
+	}
+	gvk, err := apiutil.GVKForObject(obj, c.options.Scheme)
+	if err != nil {
+		return false
+	}
+	if _, isList := obj.(client.ObjectList); isList {
+		gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	}
+	return c.uncached[gvk]
+}
+
+type clusterAwareStatusWriter struct {
+	client *clusterAwareClient
+}
+
+func (w *clusterAwareStatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	cl, err := w.client.clientFor(ctx, obj)
+	if err != nil {
+		return err
+	}
+	return cl.Status().Update(ctx, obj, opts...)
+}
+
+func (w *clusterAwareStatusWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	cl, err := w.client.clientFor(ctx, obj)
+	if err != nil {
+		return err
+	}
+	return cl.Status().Patch(ctx, obj, patch, opts...)
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllerruntime
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func configMap(cluster, namespace, name string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{ClusterName: cluster, Namespace: namespace, Name: name}}
+}
+
+// fakeReader stores objects by cluster-aware key, like the wildcard cache.
+type fakeReader struct {
+	objects map[client.ObjectKey]*corev1.ConfigMap
+}
+
+func (r *fakeReader) Get(_ context.Context, key client.ObjectKey, obj client.Object) error {
+	cm, ok := r.objects[key]
+	if !ok {
+		return apierrors.NewNotFound(corev1.Resource("configmaps"), key.Name)
+	}
+	cm.DeepCopyInto(obj.(*corev1.ConfigMap))
+	return nil
+}
+
+func (r *fakeReader) List(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+	cms := list.(*corev1.ConfigMapList)
+	for _, cm := range r.objects {
+		cms.Items = append(cms.Items, *cm.DeepCopy())
+	}
+	return nil
+}
+
+func TestRequestRoundTrip(t *testing.T) {
+	req := NewRequest(logicalcluster.New("root:org:ws"), "default", "foo")
+	require.Equal(t, types.NamespacedName{Namespace: "default", Name: "root:org:ws#$#foo"}, req.NamespacedName)
+
+	clusterReq := ClusterFromRequest(req)
+	require.Equal(t, logicalcluster.New("root:org:ws"), clusterReq.ClusterName)
+	require.Equal(t, types.NamespacedName{Namespace: "default", Name: "foo"}, clusterReq.NamespacedName)
+
+	var got Request
+	var gotCluster logicalcluster.Name
+	r := NewReconciler(ReconcilerFunc(func(ctx context.Context, req Request) (reconcile.Result, error) {
+		got = req
+		gotCluster, _ = ClusterFrom(ctx)
+		return reconcile.Result{}, nil
+	}))
+	_, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, clusterReq, got)
+	require.Equal(t, logicalcluster.New("root:org:ws"), gotCluster)
+}
+
+func TestEnqueueRequestForObject(t *testing.T) {
+	q := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer q.ShutDown()
+
+	h := &EnqueueRequestForObject{}
+	h.Create(event.CreateEvent{Object: configMap("root:a", "default", "foo")}, q)
+	h.Update(event.UpdateEvent{ObjectOld: configMap("root:b", "", "bar"), ObjectNew: configMap("root:b", "", "bar")}, q)
+
+	require.Equal(t, 2, q.Len())
+	item, _ := q.Get()
+	require.Equal(t, NewRequest(logicalcluster.New("root:a"), "default", "foo"), item)
+	item, _ = q.Get()
+	require.Equal(t, NewRequest(logicalcluster.New("root:b"), "", "bar"), item)
+}
+
+func TestConfigForCluster(t *testing.T) {
+	for _, host := range []string{"https://kcp:6443", "https://kcp:6443/", "https://kcp:6443/clusters/root:org"} {
+		cfg := ConfigForCluster(&rest.Config{Host: host}, logicalcluster.Wildcard)
+		require.Equal(t, "https://kcp:6443/clusters/*", cfg.Host, "host %q", host)
+	}
+	cfg := ConfigForCluster(&rest.Config{Host: "https://kcp:6443/services/apiexport/root:org/widgets"}, logicalcluster.New("root:org:ws"))
+	require.Equal(t, "https://kcp:6443/services/apiexport/root:org/widgets/clusters/root:org:ws", cfg.Host)
+}
+
+func TestClusterAwareClientReads(t *testing.T) {
+	reader := &fakeReader{objects: map[client.ObjectKey]*corev1.ConfigMap{
+		{Namespace: "default", Name: "root:a#$#foo"}: configMap("root:a", "default", "foo"),
+		{Namespace: "default", Name: "root:b#$#foo"}: configMap("root:b", "default", "foo"),
+	}}
+	c := &clusterAwareClient{reader: reader, options: client.Options{Scheme: runtime.NewScheme()}}
+
+	var cm corev1.ConfigMap
+	err := c.Get(context.Background(), NewRequest(logicalcluster.New("root:b"), "default", "foo").NamespacedName, &cm)
+	require.NoError(t, err)
+	require.Equal(t, "root:b", cm.ClusterName)
+
+	ctx := WithCluster(context.Background(), logicalcluster.New("root:a"))
+	err = c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "foo"}, &cm)
+	require.NoError(t, err)
+	require.Equal(t, "root:a", cm.ClusterName)
+
+	err = c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "foo"}, &cm)
+	require.Error(t, err, "get without logical cluster should fail")
+
+	var all corev1.ConfigMapList
+	require.NoError(t, c.List(context.Background(), &all))
+	require.Len(t, all.Items, 2)
+
+	var scoped corev1.ConfigMapList
+	require.NoError(t, c.List(ctx, &scoped))
+	require.Len(t, scoped.Items, 1)
+	require.Equal(t, "root:a", scoped.Items[0].ClusterName)
+
+	err = c.Create(context.Background(), &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "bar"}})
+	require.Error(t, err, "create without logical cluster should fail")
+}
+
+func TestAdmissionHandler(t *testing.T) {
+	raw, err := json.Marshal(configMap("root:org:ws", "default", "foo"))
+	require.NoError(t, err)
+
+	var got logicalcluster.Name
+	h := NewAdmissionHandler(admission.HandlerFunc(func(ctx context.Context, req admission.Request) admission.Response {
+		got, _ = ClusterFrom(ctx)
+		return admission.Allowed("")
+	}))
+
+	resp := h.Handle(context.Background(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Operation: admissionv1.Delete,
+		OldObject: runtime.RawExtension{Raw: raw},
+	}})
+	require.True(t, resp.Allowed)
+	require.Equal(t, logicalcluster.New("root:org:ws"), got)
+
+	resp = h.Handle(context.Background(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Object: runtime.RawExtension{Raw: []byte(`{"metadata":{"name":"foo"}}`)},
+	}})
+	require.False(t, resp.Allowed)
+}
@@ -0,0 +1,38 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package controllerruntime lets controller-runtime managers reconcile objects
+// across logical clusters.
+//
+// NewClusterAwareManager builds a manager whose caches watch all logical
+// clusters behind an endpoint through a wildcard request, e.g. a virtual
+// workspace or a kcp shard. Objects in the cache are keyed by
+// logical cluster, namespace and name. Reconcile requests carry the logical
+// cluster encoded in their name (see NewRequest and ClusterFromRequest), and
+// the manager client reads from the cluster-aware cache and sends writes to
+// the logical cluster of the object, or the one stored in the context with
+// WithCluster.
+//
+// A typical setup is:
+//
+//	mgr, err := kcpctrl.NewClusterAwareManager(cfg, ctrl.Options{Scheme: scheme})
+//	_, err = kcpctrl.NewController("widgets", mgr, &v1.Widget{}, reconciler, controller.Options{})
+//	err = mgr.Start(ctx)
+//
+// where reconciler implements Reconciler and gets a Request with the logical
+// cluster split from the object key. The builder from controller-runtime
+// enqueues requests without logical cluster and cannot be used.
+package controllerruntime
@@ -0,0 +1,76 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllerruntime
+
+import (
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// NewClusterAwareManager returns a manager whose caches watch all logical
+// clusters behind cfg.Host, and whose client is cluster-aware (see
+// NewClusterAwareClient). cfg may point at a virtual workspace, e.g. the
+// syncer virtual workspace, or at a shard, with or without a /clusters/<name>
+// suffix. API discovery is done against cfg as given.
+func NewClusterAwareManager(cfg *rest.Config, options manager.Options) (manager.Manager, error) {
+	if options.MapperProvider == nil {
+		options.MapperProvider = func(*rest.Config) (meta.RESTMapper, error) {
+			return apiutil.NewDynamicRESTMapper(cfg)
+		}
+	}
+	if options.NewClient == nil {
+		options.NewClient = NewClusterAwareClient
+	}
+	return manager.New(ConfigForCluster(cfg, logicalcluster.Wildcard), options)
+}
+
+// ConfigForCluster returns a copy of cfg scoped to the given logical cluster,
+// replacing any /clusters/<name> suffix of the host.
+func ConfigForCluster(cfg *rest.Config, cluster logicalcluster.Name) *rest.Config {
+	cfg = rest.CopyConfig(cfg)
+	host := cfg.Host
+	if i := strings.Index(host, "/clusters/"); i >= 0 {
+		host = host[:i]
+	}
+	cfg.Host = strings.TrimSuffix(host, "/") + "/clusters/" + cluster.String()
+	return cfg
+}
+
+// NewController creates a controller reconciling objects of the type of
+// forType in all logical clusters, and registers it with mgr. More watches can
+// be added to the returned controller with EnqueueRequestForObject or with
+// handler.EnqueueRequestsFromMapFunc and RequestForObject.
+func NewController(name string, mgr manager.Manager, forType client.Object, r Reconciler, options controller.Options) (controller.Controller, error) {
+	options.Reconciler = NewReconciler(r)
+	c, err := controller.New(name, mgr, options)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.Watch(&source.Kind{Type: forType}, &EnqueueRequestForObject{}); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
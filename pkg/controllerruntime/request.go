@@ -0,0 +1,136 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllerruntime
+
+import (
+	"context"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clusters"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Request is a reconcile request for an object in a logical cluster.
+type Request struct {
+	ClusterName logicalcluster.Name
+	types.NamespacedName
+}
+
+// NewRequest returns a reconcile.Request for the given object, with the logical
+// cluster encoded in the name the same way cluster-aware cache keys are.
+func NewRequest(cluster logicalcluster.Name, namespace, name string) reconcile.Request {
+	return reconcile.Request{NamespacedName: types.NamespacedName{
+		Namespace: namespace,
+		Name:      clusters.ToClusterAwareKey(cluster, name),
+	}}
+}
+
+// RequestForObject returns the reconcile.Request for obj.
+func RequestForObject(obj client.Object) reconcile.Request {
+	return NewRequest(logicalcluster.From(obj), obj.GetNamespace(), obj.GetName())
+}
+
+// ClusterFromRequest splits the logical cluster from a request created with
+// NewRequest.
+func ClusterFromRequest(req reconcile.Request) Request {
+	cluster, name := clusters.SplitClusterAwareKey(req.Name)
+	return Request{
+		ClusterName:    cluster,
+		NamespacedName: types.NamespacedName{Namespace: req.Namespace, Name: name},
+	}
+}
+
+type clusterKey int
+
+const clusterContextKey clusterKey = iota
+
+// WithCluster returns a context scoping client calls to the given logical cluster.
+func WithCluster(ctx context.Context, cluster logicalcluster.Name) context.Context {
+	return context.WithValue(ctx, clusterContextKey, cluster)
+}
+
+// ClusterFrom returns the logical cluster stored in ctx by WithCluster.
+func ClusterFrom(ctx context.Context) (logicalcluster.Name, bool) {
+	cluster, ok := ctx.Value(clusterContextKey).(logicalcluster.Name)
+	return cluster, ok && !cluster.Empty()
+}
+
+// Reconciler reconciles an object in a logical cluster.
+type Reconciler interface {
+	Reconcile(ctx context.Context, req Request) (reconcile.Result, error)
+}
+
+// ReconcilerFunc is a function implementing Reconciler.
+type ReconcilerFunc func(ctx context.Context, req Request) (reconcile.Result, error)
+
+// Reconcile implements Reconciler.
+func (f ReconcilerFunc) Reconcile(ctx context.Context, req Request) (reconcile.Result, error) {
+	return f(ctx, req)
+}
+
+// NewReconciler adapts r to a reconcile.Reconciler. The logical cluster of each
+// request is split from the object name and stored in the context, so that the
+// cluster-aware client is scoped to it.
+func NewReconciler(r Reconciler) reconcile.Reconciler {
+	return reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		clusterReq := ClusterFromRequest(req)
+		return r.Reconcile(WithCluster(ctx, clusterReq.ClusterName), clusterReq)
+	})
+}
+
+// EnqueueRequestForObject enqueues a cluster-aware request for the object of
+// each event. It replaces handler.EnqueueRequestForObject for controllers
+// reconciling across logical clusters.
+type EnqueueRequestForObject struct{}
+
+var _ handler.EventHandler = &EnqueueRequestForObject{}
+
+// Create implements handler.EventHandler.
+func (e *EnqueueRequestForObject) Create(evt event.CreateEvent, q workqueue.RateLimitingInterface) {
+	if evt.Object != nil {
+		q.Add(RequestForObject(evt.Object))
+	}
+}
+
+// Update implements handler.EventHandler.
+func (e *EnqueueRequestForObject) Update(evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	if evt.ObjectNew != nil {
+		q.Add(RequestForObject(evt.ObjectNew))
+	} else if evt.ObjectOld != nil {
+		q.Add(RequestForObject(evt.ObjectOld))
+	}
+}
+
+// Delete implements handler.EventHandler.
+func (e *EnqueueRequestForObject) Delete(evt event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	if evt.Object != nil {
+		q.Add(RequestForObject(evt.Object))
+	}
+}
+
+// Generic implements handler.EventHandler.
+func (e *EnqueueRequestForObject) Generic(evt event.GenericEvent, q workqueue.RateLimitingInterface) {
+	if evt.Object != nil {
+		q.Add(RequestForObject(evt.Object))
+	}
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllerruntime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// ClusterFromAdmissionRequest returns the logical cluster of the object, or
+// for deletions of the old object, of an admission request.
+func ClusterFromAdmissionRequest(req admission.Request) (logicalcluster.Name, error) {
+	raw := req.Object.Raw
+	if len(raw) == 0 {
+		raw = req.OldObject.Raw
+	}
+	if len(raw) == 0 {
+		return logicalcluster.Name{}, fmt.Errorf("admission request %s carries no object", req.UID)
+	}
+
+	var obj metav1.PartialObjectMetadata
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return logicalcluster.Name{}, err
+	}
+	cluster := logicalcluster.From(&obj)
+	if cluster.Empty() {
+		return logicalcluster.Name{}, fmt.Errorf("admission request %s object has no logical cluster", req.UID)
+	}
+	return cluster, nil
+}
+
+// NewAdmissionHandler wraps h such that the logical cluster of the admitted
+// object is stored in the context with WithCluster. Requests without a
+// logical cluster are denied.
+func NewAdmissionHandler(h admission.Handler) admission.Handler {
+	return admission.HandlerFunc(func(ctx context.Context, req admission.Request) admission.Response {
+		cluster, err := ClusterFromAdmissionRequest(req)
+		if err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		return h.Handle(WithCluster(ctx, cluster), req)
+	})
+}
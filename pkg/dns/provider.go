@@ -0,0 +1,46 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"context"
+)
+
+// Record is a DNS record to be programmed in a zone.
+type Record struct {
+	// Zone is the domain of the DNSZone, e.g. apps.example.com.
+	Zone string `json:"zone"`
+	// Name is the fully qualified name of the record, below Zone.
+	Name string `json:"name"`
+	// Type is the record type, e.g. A, AAAA, CNAME or TXT.
+	Type string `json:"type"`
+	// Targets are the values of the record.
+	Targets []string `json:"targets"`
+	// TTL is the time to live in seconds.
+	TTL int64 `json:"ttl"`
+}
+
+// Provider programs DNS records, e.g. in a cloud DNS service. Implementations must be
+// idempotent: Ensure creates or replaces the record of the name and type, and Delete
+// succeeds if the record does not exist.
+type Provider interface {
+	Ensure(ctx context.Context, record Record) error
+	Delete(ctx context.Context, record Record) error
+}
+
+// Providers maps the provider names referenced by DNSZones to providers.
+type Providers map[string]Provider
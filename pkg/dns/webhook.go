@@ -0,0 +1,86 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Operation is the operation a WebhookProvider requests.
+type Operation string
+
+const (
+	OperationEnsure Operation = "Ensure"
+	OperationDelete Operation = "Delete"
+)
+
+// WebhookProvider delegates programming records to an HTTP endpoint, e.g. a small adapter
+// to the API of a DNS service. It posts the operation and the record as JSON to the URL:
+//
+//	{"operation": "Ensure", "record": {"zone": "apps.example.com", "name": "shop.apps.example.com", ...}}
+//
+// Any 2xx response is success.
+type WebhookProvider struct {
+	URL    string
+	Client *http.Client
+}
+
+var _ Provider = &WebhookProvider{}
+
+// Ensure implements Provider.
+func (p *WebhookProvider) Ensure(ctx context.Context, record Record) error {
+	return p.post(ctx, OperationEnsure, record)
+}
+
+// Delete implements Provider.
+func (p *WebhookProvider) Delete(ctx context.Context, record Record) error {
+	return p.post(ctx, OperationDelete, record)
+}
+
+func (p *WebhookProvider) post(ctx context.Context, op Operation, record Record) error {
+	bs, err := json.Marshal(struct {
+		Operation Operation `json:"operation"`
+		Record    Record    `json:"record"`
+	}{op, record})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.URL, bytes.NewReader(bs))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s from %s for %s of %s %s", resp.Status, p.URL, op, record.Type, record.Name)
+	}
+	return nil
+}
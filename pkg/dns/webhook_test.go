@@ -0,0 +1,58 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookProvider(t *testing.T) {
+	type request struct {
+		Operation Operation `json:"operation"`
+		Record    Record    `json:"record"`
+	}
+	var got []request
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		var req request
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		got = append(got, req)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	p := &WebhookProvider{URL: server.URL, Client: server.Client()}
+	record := Record{Zone: "apps.example.com", Name: "shop.apps.example.com", Type: "A", Targets: []string{"10.0.0.1"}, TTL: 300}
+
+	require.NoError(t, p.Ensure(context.Background(), record))
+	require.NoError(t, p.Delete(context.Background(), record))
+	require.Equal(t, []request{
+		{Operation: OperationEnsure, Record: record},
+		{Operation: OperationDelete, Record: record},
+	}, got)
+
+	status = http.StatusBadGateway
+	require.Error(t, p.Ensure(context.Background(), record))
+}
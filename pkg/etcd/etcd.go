@@ -33,17 +33,38 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
 	"go.etcd.io/etcd/client/pkg/v3/fileutil"
+	clientv3 "go.etcd.io/etcd/client/v3"
 	"go.etcd.io/etcd/server/v3/embed"
 	"go.etcd.io/etcd/server/v3/wal"
 
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
 )
 
 type Server struct {
 	Dir string
+
+	// Name is the etcd member name. It defaults to the etcd default name.
+	Name string
+	// PeerHost is the host name other members use to reach this member. If it is
+	// empty, the member only listens on localhost and runs as a single node cluster.
+	PeerHost string
+	// ClientHost is the host name other members use to reach the client port of
+	// this member, e.g. when joining the cluster through it. If it is empty, the
+	// client port only listens on localhost.
+	ClientHost string
+	// InitialCluster is the etcd initial cluster string, e.g. "kcp-0=https://kcp-0:2380,kcp-1=https://kcp-1:2380".
+	InitialCluster string
+	// ClusterState is either "new" or "existing".
+	ClusterState string
+	// JoinEndpoints are client URLs of an existing cluster. If set, the member adds itself
+	// as a learner through them before starting, and promotes itself once it has caught up.
+	JoinEndpoints []string
 }
 
 type ClientInfo struct {
@@ -67,21 +88,46 @@ func (s *Server) Run(ctx context.Context, peerPort, clientPort string, walSizeBy
 
 	cfg.Dir = s.Dir
 	cfg.AuthToken = ""
+	if s.Name != "" {
+		cfg.Name = s.Name
+	}
+
+	hosts := []string{"localhost"}
+	peerListenHost, peerAdvertiseHost := "localhost", "localhost"
+	if s.PeerHost != "" {
+		hosts = append(hosts, s.PeerHost)
+		peerListenHost, peerAdvertiseHost = "0.0.0.0", s.PeerHost
+	}
+	clientListenHost, clientAdvertiseHost := "localhost", "localhost"
+	if s.ClientHost != "" {
+		if s.ClientHost != s.PeerHost {
+			hosts = append(hosts, s.ClientHost)
+		}
+		clientListenHost, clientAdvertiseHost = "0.0.0.0", s.ClientHost
+	}
 
-	cfg.LPUrls = []url.URL{{Scheme: "https", Host: "localhost:" + peerPort}}
-	cfg.APUrls = []url.URL{{Scheme: "https", Host: "localhost:" + peerPort}}
-	cfg.LCUrls = []url.URL{{Scheme: "https", Host: "localhost:" + clientPort}}
-	cfg.ACUrls = []url.URL{{Scheme: "https", Host: "localhost:" + clientPort}}
+	cfg.LPUrls = []url.URL{{Scheme: "https", Host: peerListenHost + ":" + peerPort}}
+	cfg.APUrls = []url.URL{{Scheme: "https", Host: peerAdvertiseHost + ":" + peerPort}}
+	cfg.LCUrls = []url.URL{{Scheme: "https", Host: clientListenHost + ":" + clientPort}}
+	cfg.ACUrls = []url.URL{{Scheme: "https", Host: clientAdvertiseHost + ":" + clientPort}}
 	cfg.InitialCluster = cfg.InitialClusterFromName(cfg.Name)
+	if s.InitialCluster != "" {
+		cfg.InitialCluster = s.InitialCluster
+	}
+	if s.ClusterState != "" {
+		cfg.ClusterState = s.ClusterState
+	}
 
 	if err := fileutil.TouchDirAll(cfg.Dir); err != nil {
 		return ClientInfo{}, err
 	}
 
-	if err := generateClientAndServerCerts([]string{"localhost"}, filepath.Join(cfg.Dir, "secrets")); err != nil {
+	if err := generateClientAndServerCerts(hosts, filepath.Join(cfg.Dir, "secrets")); err != nil {
 		return ClientInfo{}, err
 	}
-	cfg.PeerTLSInfo.ServerName = "localhost"
+	if s.PeerHost == "" {
+		cfg.PeerTLSInfo.ServerName = "localhost"
+	}
 	cfg.PeerTLSInfo.CertFile = filepath.Join(cfg.Dir, "secrets", "peer", "cert.pem")
 	cfg.PeerTLSInfo.KeyFile = filepath.Join(cfg.Dir, "secrets", "peer", "key.pem")
 	cfg.PeerTLSInfo.TrustedCAFile = filepath.Join(cfg.Dir, "secrets", "ca", "cert.pem")
@@ -97,6 +143,22 @@ func (s *Server) Run(ctx context.Context, peerPort, clientPort string, walSizeBy
 		cfg.UnsafeNoFsync = true
 	}
 
+	clientConfig, err := cfg.ClientTLSInfo.ClientConfig()
+	if err != nil {
+		return ClientInfo{}, err
+	}
+
+	// only join on first start, afterwards the member is known to the cluster and its data dir
+	joining := len(s.JoinEndpoints) > 0 && !wal.Exist(filepath.Join(cfg.Dir, "member", "wal"))
+	if joining {
+		initialCluster, err := s.addAsLearner(ctx, clientConfig, cfg.Name, cfg.APUrls[0].String())
+		if err != nil {
+			return ClientInfo{}, err
+		}
+		cfg.InitialCluster = initialCluster
+		cfg.ClusterState = embed.ClusterStateFlagExisting
+	}
+
 	e, err := embed.StartEtcd(cfg)
 	if err != nil {
 		return ClientInfo{}, err
@@ -107,13 +169,11 @@ func (s *Server) Run(ctx context.Context, peerPort, clientPort string, walSizeBy
 		e.Close()
 	}()
 
-	clientConfig, err := cfg.ClientTLSInfo.ClientConfig()
-	if err != nil {
-		return ClientInfo{}, err
-	}
-
 	select {
 	case <-e.Server.ReadyNotify():
+		if e.Server.IsLearner() {
+			go s.promoteLearner(ctx, clientConfig, uint64(e.Server.ID()))
+		}
 		return ClientInfo{
 			Endpoints:     []string{cfg.ACUrls[0].String()},
 			TLS:           clientConfig,
@@ -129,6 +189,99 @@ func (s *Server) Run(ctx context.Context, peerPort, clientPort string, walSizeBy
 	}
 }
 
+// addAsLearner registers this member as a learner with the existing cluster
+// and returns the initial cluster string to start the member with. If a
+// previous start has already added the learner, but failed before writing
+// its data, the existing learner is reused.
+func (s *Server) addAsLearner(ctx context.Context, tlsConfig *tls.Config, name, peerURL string) (string, error) {
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   s.JoinEndpoints,
+		TLS:         tlsConfig,
+		DialTimeout: 10 * time.Second,
+		Context:     ctx,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to etcd cluster %v: %w", s.JoinEndpoints, err)
+	}
+	defer client.Close()
+
+	list, err := client.MemberList(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to list members of etcd cluster %v: %w", s.JoinEndpoints, err)
+	}
+	if existing := memberByPeerURL(list.Members, peerURL); existing != nil {
+		if existing.Name != "" {
+			// the member has started before, but its data is gone
+			return "", fmt.Errorf("embedded etcd member %q with peer URL %s (ID %x) is already part of the cluster, but has no data in its directory. Remove it from the cluster first", existing.Name, peerURL, existing.ID)
+		}
+		klog.Infof("Reusing embedded etcd learner %x with peer URL %s of a previous start", existing.ID, peerURL)
+		return initialCluster(list.Members, existing.ID, name), nil
+	}
+
+	klog.Infof("Adding embedded etcd member %q with peer URL %s as learner to %v", name, peerURL, s.JoinEndpoints)
+	resp, err := client.MemberAddAsLearner(ctx, []string{peerURL})
+	if err != nil {
+		return "", fmt.Errorf("failed to add embedded etcd member %q as learner: %w", name, err)
+	}
+	return initialCluster(resp.Members, resp.Member.ID, name), nil
+}
+
+// memberByPeerURL returns the member with the given peer URL, or nil.
+func memberByPeerURL(members []*etcdserverpb.Member, peerURL string) *etcdserverpb.Member {
+	for _, m := range members {
+		for _, u := range m.PeerURLs {
+			if u == peerURL {
+				return m
+			}
+		}
+	}
+	return nil
+}
+
+// initialCluster returns the etcd initial cluster string of members, naming
+// the member with the given ID, which has not started yet, as name.
+func initialCluster(members []*etcdserverpb.Member, id uint64, name string) string {
+	var initialCluster []string
+	for _, m := range members {
+		memberName := m.Name
+		if m.ID == id {
+			memberName = name
+		}
+		for _, u := range m.PeerURLs {
+			initialCluster = append(initialCluster, memberName+"="+u)
+		}
+	}
+	return strings.Join(initialCluster, ",")
+}
+
+// promoteLearner promotes the learner member with the given ID to a voting
+// member. Etcd refuses the promotion until the learner has caught up with the
+// leader, hence it is retried until it succeeds or ctx is done.
+func (s *Server) promoteLearner(ctx context.Context, tlsConfig *tls.Config, id uint64) {
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   s.JoinEndpoints,
+		TLS:         tlsConfig,
+		DialTimeout: 10 * time.Second,
+		Context:     ctx,
+	})
+	if err != nil {
+		klog.Errorf("Failed to connect to etcd cluster %v to promote learner %x: %v", s.JoinEndpoints, id, err)
+		return
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if _, err := client.MemberPromote(ctx, id); err != nil {
+			klog.V(2).Infof("Embedded etcd learner %x not promoted yet: %v", id, err)
+			return
+		}
+		klog.Infof("Promoted embedded etcd learner %x to voting member", id)
+		cancel()
+	}, 5*time.Second)
+}
+
 func generateClientAndServerCerts(hosts []string, dir string) error {
 	serialNumberLimit := new(big.Int).Lsh(big.NewInt(1), 128)
 	serialNumber, err := rand.Int(rand.Reader, serialNumberLimit)
@@ -182,10 +335,25 @@ func generateClientAndServerCerts(hosts []string, dir string) error {
 		BasicConstraintsValid: true,
 	}
 
-	caKey, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	// Reuse an existing CA, e.g. one shared by all members of an embedded etcd cluster.
+	caKey, caCert, err := loadCA(dir)
 	if err != nil {
 		return err
 	}
+	if caKey != nil {
+		caTemplate = caCert
+	} else {
+		caKey, err = ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+		if err != nil {
+			return err
+		}
+		if err := ecPrivateKeyToFile(caKey, filepath.Join(dir, "ca", "key.pem")); err != nil {
+			return err
+		}
+		if err := certToFile(caTemplate, caTemplate, &caKey.PublicKey, caKey, filepath.Join(dir, "ca", "cert.pem")); err != nil {
+			return err
+		}
+	}
 
 	serverKey, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
 	if err != nil {
@@ -197,13 +365,6 @@ func generateClientAndServerCerts(hosts []string, dir string) error {
 		return err
 	}
 
-	if err := ecPrivateKeyToFile(caKey, filepath.Join(dir, "ca", "key.pem")); err != nil {
-		return err
-	}
-	if err := certToFile(caTemplate, caTemplate, &caKey.PublicKey, caKey, filepath.Join(dir, "ca", "cert.pem")); err != nil {
-		return err
-	}
-
 	if err := ecPrivateKeyToFile(serverKey, filepath.Join(dir, "peer", "key.pem")); err != nil {
 		return err
 	}
@@ -221,6 +382,40 @@ func generateClientAndServerCerts(hosts []string, dir string) error {
 	return nil
 }
 
+// loadCA returns the CA key and certificate in dir, or nil if there is none.
+func loadCA(dir string) (*ecdsa.PrivateKey, *x509.Certificate, error) {
+	keyPEM, err := ioutil.ReadFile(filepath.Join(dir, "ca", "key.pem"))
+	if os.IsNotExist(err) {
+		return nil, nil, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+	certPEM, err := ioutil.ReadFile(filepath.Join(dir, "ca", "cert.pem"))
+	if os.IsNotExist(err) {
+		return nil, nil, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	keyBlock, _ := pem.Decode(keyPEM)
+	if keyBlock == nil {
+		return nil, nil, fmt.Errorf("failed to decode etcd CA key in %s", dir)
+	}
+	key, err := x509.ParseECPrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	certBlock, _ := pem.Decode(certPEM)
+	if certBlock == nil {
+		return nil, nil, fmt.Errorf("failed to decode etcd CA certificate in %s", dir)
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return key, cert, nil
+}
+
 func certToFile(template *x509.Certificate, parent *x509.Certificate, publicKey *ecdsa.PublicKey, privateKey *ecdsa.PrivateKey, path string) error {
 	b, err := x509.CreateCertificate(rand.Reader, template, parent, publicKey, privateKey)
 	if err != nil {
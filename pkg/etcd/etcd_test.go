@@ -0,0 +1,99 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+)
+
+func readCert(t *testing.T, path string) *x509.Certificate {
+	bs, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	block, _ := pem.Decode(bs)
+	require.NotNil(t, block, "no PEM in %s", path)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+	return cert
+}
+
+func copyFile(t *testing.T, from, to string) {
+	bs, err := ioutil.ReadFile(from)
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Dir(to), 0700))
+	require.NoError(t, ioutil.WriteFile(to, bs, 0600))
+}
+
+func TestGenerateClientAndServerCertsReusesCA(t *testing.T) {
+	dir := t.TempDir()
+
+	key, cert, err := loadCA(dir)
+	require.NoError(t, err)
+	require.Nil(t, key, "no CA expected yet")
+	require.Nil(t, cert, "no CA expected yet")
+
+	require.NoError(t, generateClientAndServerCerts([]string{"localhost", "kcp-0"}, dir))
+	ca := readCert(t, filepath.Join(dir, "ca", "cert.pem"))
+
+	t.Log("Regenerating on restart keeps the CA")
+	require.NoError(t, generateClientAndServerCerts([]string{"localhost", "kcp-0"}, dir))
+	require.Equal(t, ca.Raw, readCert(t, filepath.Join(dir, "ca", "cert.pem")).Raw)
+
+	t.Log("Another member sharing the CA gets certificates trusted by the first")
+	otherDir := t.TempDir()
+	copyFile(t, filepath.Join(dir, "ca", "cert.pem"), filepath.Join(otherDir, "ca", "cert.pem"))
+	copyFile(t, filepath.Join(dir, "ca", "key.pem"), filepath.Join(otherDir, "ca", "key.pem"))
+	require.NoError(t, generateClientAndServerCerts([]string{"localhost", "kcp-1"}, otherDir))
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	peer := readCert(t, filepath.Join(otherDir, "peer", "cert.pem"))
+	_, err = peer.Verify(x509.VerifyOptions{Roots: roots, DNSName: "kcp-1", KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}})
+	require.NoError(t, err)
+	client := readCert(t, filepath.Join(otherDir, "client", "cert.pem"))
+	_, err = client.Verify(x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}})
+	require.NoError(t, err)
+}
+
+func TestLoadCAInvalid(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "ca"), 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "ca", "key.pem"), []byte("garbage"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "ca", "cert.pem"), []byte("garbage"), 0600))
+
+	_, _, err := loadCA(dir)
+	require.Error(t, err)
+}
+
+func TestInitialCluster(t *testing.T) {
+	members := []*etcdserverpb.Member{
+		{ID: 1, Name: "kcp-0", PeerURLs: []string{"https://kcp-0:2380"}},
+		{ID: 2, Name: "", PeerURLs: []string{"https://kcp-1:2380"}, IsLearner: true},
+	}
+
+	require.Equal(t, "kcp-0=https://kcp-0:2380,kcp-1=https://kcp-1:2380", initialCluster(members, 2, "kcp-1"))
+
+	require.Equal(t, members[1], memberByPeerURL(members, "https://kcp-1:2380"))
+	require.Nil(t, memberByPeerURL(members, "https://kcp-2:2380"))
+}
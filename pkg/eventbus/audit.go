@@ -0,0 +1,82 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventbus
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+	"k8s.io/apiserver/pkg/audit"
+	"k8s.io/klog/v2"
+)
+
+// auditBackend publishes the audit events of requests to the bus, as audit.k8s.io/v1 Events.
+type auditBackend struct {
+	bus *Bus
+}
+
+var _ audit.Backend = &auditBackend{}
+
+// NewAuditBackend returns an audit backend publishing audit events of the given bus. Which
+// requests are audited at which level is up to the audit policy.
+func NewAuditBackend(bus *Bus) audit.Backend {
+	return &auditBackend{bus: bus}
+}
+
+func (b *auditBackend) ProcessEvents(events ...*auditinternal.Event) bool {
+	for _, ev := range events {
+		workspace := workspaceOf(ev.RequestURI)
+		if !b.bus.Wants(AuditEventType, workspace) {
+			continue
+		}
+		bs, err := runtime.Encode(audit.Codecs.LegacyCodec(auditv1.SchemeGroupVersion), ev)
+		if err != nil {
+			klog.Errorf("Failed to encode audit event %s: %v", ev.AuditID, err)
+			continue
+		}
+		b.bus.Publish(AuditEventType, workspace, ev.RequestURI, bs)
+	}
+	return true
+}
+
+// Run does nothing, as the bus is started by the server.
+func (b *auditBackend) Run(stopCh <-chan struct{}) error {
+	return nil
+}
+
+// Shutdown does nothing, as the bus drains its buffers on its own.
+func (b *auditBackend) Shutdown() {
+}
+
+func (b *auditBackend) String() string {
+	return "eventbus"
+}
+
+// workspaceOf returns the logical cluster of a request URI of the form /clusters/<name>/...,
+// or an empty string.
+func workspaceOf(requestURI string) string {
+	if !strings.HasPrefix(requestURI, "/clusters/") {
+		return ""
+	}
+	name := strings.TrimPrefix(requestURI, "/clusters/")
+	if i := strings.IndexAny(name, "/?"); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
@@ -0,0 +1,323 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// SpecVersion is the CloudEvents version of the events.
+	SpecVersion = "1.0"
+
+	// AuditEventType is the type of events carrying an audit.k8s.io/v1 Event of a request.
+	AuditEventType = "dev.kcp.audit.event"
+	// WorkspaceCreatedEventType is the type of events of created ClusterWorkspaces.
+	WorkspaceCreatedEventType = "dev.kcp.workspace.created"
+	// WorkspacePhaseChangedEventType is the type of events of ClusterWorkspaces whose phase changed.
+	WorkspacePhaseChangedEventType = "dev.kcp.workspace.phasechanged"
+	// WorkspaceDeletedEventType is the type of events of deleted ClusterWorkspaces.
+	WorkspaceDeletedEventType = "dev.kcp.workspace.deleted"
+	// WorkspaceExpiringEventType is the type of events of ClusterWorkspaces about to expire.
+	WorkspaceExpiringEventType = "dev.kcp.workspace.expiring"
+	// APIBindingCreatedEventType is the type of events of created APIBindings.
+	APIBindingCreatedEventType = "dev.kcp.apibinding.created"
+	// APIBindingChangedEventType is the type of events of APIBindings whose reference, phase or
+	// bound resources changed.
+	APIBindingChangedEventType = "dev.kcp.apibinding.changed"
+	// APIBindingDeletedEventType is the type of events of deleted APIBindings.
+	APIBindingDeletedEventType = "dev.kcp.apibinding.deleted"
+	// ResourceCreatedEventType is the type of events of created objects of bound resources.
+	ResourceCreatedEventType = "dev.kcp.resource.created"
+	// ResourceUpdatedEventType is the type of events of updated objects of bound resources.
+	ResourceUpdatedEventType = "dev.kcp.resource.updated"
+	// ResourceDeletedEventType is the type of events of deleted objects of bound resources.
+	ResourceDeletedEventType = "dev.kcp.resource.deleted"
+
+	defaultBufferSize    = 1000
+	defaultBatchSize     = 100
+	defaultFlushInterval = time.Second
+	sendAttempts         = 3
+)
+
+// Event is a CloudEvent in structured JSON mode.
+type Event struct {
+	SpecVersion     string    `json:"specversion"`
+	ID              string    `json:"id"`
+	Source          string    `json:"source"`
+	Type            string    `json:"type"`
+	Subject         string    `json:"subject,omitempty"`
+	Time            time.Time `json:"time"`
+	DataContentType string    `json:"datacontenttype,omitempty"`
+	// Workspace is the logical cluster the event happened in, as the kcpworkspace extension
+	// attribute.
+	Workspace string          `json:"kcpworkspace,omitempty"`
+	Data      json.RawMessage `json:"data,omitempty"`
+}
+
+// Sink delivers events to an event bus.
+type Sink interface {
+	// Name identifies the sink in logs and metrics.
+	Name() string
+	// Send delivers the events. They are retried if it fails.
+	Send(ctx context.Context, events []Event) error
+}
+
+// Filter selects the events of a sink. Empty fields select all.
+type Filter struct {
+	// Types are path.Match patterns of event types, e.g. dev.kcp.workspace.*.
+	Types []string
+	// Workspaces are the logical clusters whose events are selected, including those of
+	// their descendants, e.g. root:acme for an organization.
+	Workspaces []string
+}
+
+// Matches returns whether the event of the given type and workspace is selected.
+func (f Filter) Matches(eventType, workspace string) bool {
+	if len(f.Types) > 0 {
+		matched := false
+		for _, pattern := range f.Types {
+			if ok, _ := path.Match(pattern, eventType); ok {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(f.Workspaces) > 0 {
+		for _, ws := range f.Workspaces {
+			if workspace == ws || strings.HasPrefix(workspace, ws+":") {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// SinkConfig configures the delivery of events to a sink.
+type SinkConfig struct {
+	Sink   Sink
+	Filter Filter
+	// BufferSize is the number of events buffered for the sink. Events are dropped while the
+	// buffer is full. Defaults to 1000.
+	BufferSize int
+	// BatchSize is the maximum number of events sent at once. Defaults to 100.
+	BatchSize int
+}
+
+// Bus publishes events to sinks asynchronously. Publishing never blocks: each sink has a
+// buffer, and events are dropped for a sink while its buffer is full.
+type Bus struct {
+	source string
+	sinks  []*sinkQueue
+	now    func() time.Time
+}
+
+type sinkQueue struct {
+	SinkConfig
+	events chan Event
+}
+
+// NewBus returns a bus publishing events of the given source, e.g. the name of the shard, to
+// the sinks.
+func NewBus(source string, sinks []SinkConfig) *Bus {
+	b := &Bus{
+		source: source,
+		now:    time.Now,
+	}
+	for _, cfg := range sinks {
+		if cfg.BufferSize <= 0 {
+			cfg.BufferSize = defaultBufferSize
+		}
+		if cfg.BatchSize <= 0 {
+			cfg.BatchSize = defaultBatchSize
+		}
+		b.sinks = append(b.sinks, &sinkQueue{SinkConfig: cfg, events: make(chan Event, cfg.BufferSize)})
+	}
+	return b
+}
+
+// Wants returns whether any sink selects events of the given type and workspace, for
+// publishers to skip building unwanted events.
+func (b *Bus) Wants(eventType, workspace string) bool {
+	if b == nil {
+		return false
+	}
+	for _, s := range b.sinks {
+		if s.Filter.Matches(eventType, workspace) {
+			return true
+		}
+	}
+	return false
+}
+
+// Publish queues an event with the given data, marshalled to JSON, for all sinks selecting it.
+func (b *Bus) Publish(eventType, workspace, subject string, data interface{}) {
+	if !b.Wants(eventType, workspace) {
+		return
+	}
+
+	var raw json.RawMessage
+	switch d := data.(type) {
+	case json.RawMessage:
+		raw = d
+	case []byte:
+		raw = d
+	default:
+		bs, err := json.Marshal(data)
+		if err != nil {
+			klog.Errorf("Failed to marshal data of %s event of %s: %v", eventType, subject, err)
+			return
+		}
+		raw = bs
+	}
+
+	b.publish(Event{
+		SpecVersion:     SpecVersion,
+		ID:              string(uuid.NewUUID()),
+		Source:          b.source,
+		Type:            eventType,
+		Subject:         subject,
+		Time:            b.now().UTC(),
+		DataContentType: "application/json",
+		Workspace:       workspace,
+		Data:            raw,
+	})
+}
+
+func (b *Bus) publish(event Event) {
+	for _, s := range b.sinks {
+		if !s.Filter.Matches(event.Type, event.Workspace) {
+			continue
+		}
+		select {
+		case s.events <- event:
+		default:
+			droppedEvents.WithLabelValues(s.Sink.Name(), "buffer_full").Inc()
+		}
+	}
+}
+
+// Start delivers the queued events to the sinks until ctx is done. Then the events left in
+// the buffers are delivered for up to the given drain timeout.
+func (b *Bus) Start(ctx context.Context, drainTimeout time.Duration) {
+	var wg sync.WaitGroup
+	for _, s := range b.sinks {
+		wg.Add(1)
+		go func(s *sinkQueue) {
+			defer wg.Done()
+			s.run(ctx, drainTimeout)
+		}(s)
+	}
+	wg.Wait()
+}
+
+func (s *sinkQueue) run(ctx context.Context, drainTimeout time.Duration) {
+	klog.Infof("Starting event sink %s", s.Sink.Name())
+	defer klog.Infof("Stopped event sink %s", s.Sink.Name())
+
+	// sends outlive ctx by the drain timeout, such that the events in flight and in the
+	// buffer are delivered on shutdown.
+	sendCtx, cancelSend := context.WithCancel(context.Background())
+	defer cancelSend()
+	go func() {
+		select {
+		case <-ctx.Done():
+			time.AfterFunc(drainTimeout, cancelSend)
+		case <-sendCtx.Done():
+		}
+	}()
+
+	ticker := time.NewTicker(defaultFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]Event, 0, s.BatchSize)
+	for {
+		select {
+		case event := <-s.events:
+			batch = append(batch, event)
+			if len(batch) < s.BatchSize {
+				continue
+			}
+		case <-ticker.C:
+			if len(batch) == 0 {
+				continue
+			}
+		case <-ctx.Done():
+			for {
+				drained := false
+				for !drained && len(batch) < s.BatchSize {
+					select {
+					case event := <-s.events:
+						batch = append(batch, event)
+					default:
+						drained = true
+					}
+				}
+				if len(batch) == 0 || sendCtx.Err() != nil {
+					return
+				}
+				s.send(sendCtx, batch)
+				batch = batch[:0]
+			}
+		}
+
+		s.send(sendCtx, batch)
+		batch = batch[:0]
+	}
+}
+
+// send delivers the batch with retries, and drops it if all attempts fail.
+func (s *sinkQueue) send(ctx context.Context, batch []Event) {
+	var err error
+	delay := 500 * time.Millisecond
+	for attempt := 1; attempt <= sendAttempts; attempt++ {
+		if err = s.Sink.Send(ctx, batch); err == nil {
+			sentEvents.WithLabelValues(s.Sink.Name()).Add(float64(len(batch)))
+			return
+		}
+		if attempt == sendAttempts || !sleep(ctx, delay) {
+			break
+		}
+		delay *= 2
+	}
+	klog.Errorf("Dropping %d events after failing to send them to event sink %s: %v", len(batch), s.Sink.Name(), err)
+	droppedEvents.WithLabelValues(s.Sink.Name(), "send_failed").Add(float64(len(batch)))
+}
+
+// sleep waits for the duration, and returns false if ctx is done before.
+func sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSink struct {
+	name string
+	err  error
+
+	lock    sync.Mutex
+	batches [][]Event
+	calls   int
+}
+
+func (s *fakeSink) Name() string { return s.name }
+
+func (s *fakeSink) Send(_ context.Context, events []Event) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.calls++
+	if s.err != nil {
+		return s.err
+	}
+	s.batches = append(s.batches, append([]Event(nil), events...))
+	return nil
+}
+
+func (s *fakeSink) events() []Event {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	var events []Event
+	for _, batch := range s.batches {
+		events = append(events, batch...)
+	}
+	return events
+}
+
+func TestFilterMatches(t *testing.T) {
+	tests := map[string]struct {
+		filter    Filter
+		eventType string
+		workspace string
+		want      bool
+	}{
+		"empty filter":                    {eventType: AuditEventType, workspace: "root:org", want: true},
+		"type pattern":                    {filter: Filter{Types: []string{"dev.kcp.workspace.*"}}, eventType: WorkspaceCreatedEventType, want: true},
+		"other type":                      {filter: Filter{Types: []string{"dev.kcp.workspace.*"}}, eventType: AuditEventType, want: false},
+		"workspace":                       {filter: Filter{Workspaces: []string{"root:org"}}, eventType: AuditEventType, workspace: "root:org", want: true},
+		"descendant workspace":            {filter: Filter{Workspaces: []string{"root:org"}}, eventType: AuditEventType, workspace: "root:org:team", want: true},
+		"workspace with same prefix":      {filter: Filter{Workspaces: []string{"root:org"}}, eventType: AuditEventType, workspace: "root:organization", want: false},
+		"no workspace":                    {filter: Filter{Workspaces: []string{"root:org"}}, eventType: AuditEventType, want: false},
+		"type and workspace":              {filter: Filter{Types: []string{AuditEventType}, Workspaces: []string{"root"}}, eventType: AuditEventType, workspace: "root:org", want: true},
+		"type matches but workspace does": {filter: Filter{Types: []string{AuditEventType}, Workspaces: []string{"root:a"}}, eventType: AuditEventType, workspace: "root:b", want: false},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.filter.Matches(tt.eventType, tt.workspace))
+		})
+	}
+}
+
+func TestBus(t *testing.T) {
+	workspaces := &fakeSink{name: "workspaces"}
+	audit := &fakeSink{name: "audit"}
+	bus := NewBus("kcp-shard", []SinkConfig{
+		{Sink: workspaces, Filter: Filter{Types: []string{"dev.kcp.workspace.*"}}, BatchSize: 2},
+		{Sink: audit, Filter: Filter{Types: []string{AuditEventType}, Workspaces: []string{"root:org"}}},
+	})
+
+	require.True(t, bus.Wants(WorkspaceCreatedEventType, "root:other"))
+	require.True(t, bus.Wants(AuditEventType, "root:org:team"))
+	require.False(t, bus.Wants(AuditEventType, "root:other"))
+
+	bus.Publish(WorkspaceCreatedEventType, "root:org:a", "root:org:a", map[string]string{"workspace": "root:org:a"})
+	bus.Publish(WorkspaceCreatedEventType, "root:org:b", "root:org:b", map[string]string{"workspace": "root:org:b"})
+	bus.Publish(WorkspaceDeletedEventType, "root:org:a", "root:org:a", map[string]string{"workspace": "root:org:a"})
+	bus.Publish(AuditEventType, "root:org:a", "/clusters/root:org:a/api/v1/configmaps", []byte(`{"kind":"Event"}`))
+	bus.Publish(AuditEventType, "root:other", "/clusters/root:other/api/v1/configmaps", []byte(`{"kind":"Event"}`))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	// a stopped bus drains its buffers.
+	bus.Start(ctx, time.Minute)
+
+	got := workspaces.events()
+	require.Len(t, got, 3)
+	require.Equal(t, WorkspaceCreatedEventType, got[0].Type)
+	require.Equal(t, "root:org:a", got[0].Subject)
+	require.Equal(t, "root:org:a", got[0].Workspace)
+	require.Equal(t, "kcp-shard", got[0].Source)
+	require.Equal(t, SpecVersion, got[0].SpecVersion)
+	require.NotEmpty(t, got[0].ID)
+	require.JSONEq(t, `{"workspace":"root:org:a"}`, string(got[0].Data))
+	require.Equal(t, WorkspaceDeletedEventType, got[2].Type)
+	require.Len(t, workspaces.batches, 2, "batches must not exceed the batch size")
+
+	got = audit.events()
+	require.Len(t, got, 1)
+	require.JSONEq(t, `{"kind":"Event"}`, string(got[0].Data))
+}
+
+func TestBusDropsWhenBufferIsFull(t *testing.T) {
+	sink := &fakeSink{name: "small"}
+	bus := NewBus("kcp", []SinkConfig{{Sink: sink, BufferSize: 2}})
+	for i := 0; i < 5; i++ {
+		bus.Publish(WorkspaceCreatedEventType, "root:org", "root:org", nil)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	bus.Start(ctx, time.Minute)
+	require.Len(t, sink.events(), 2)
+}
+
+func TestBusRetriesAndDrops(t *testing.T) {
+	sink := &fakeSink{name: "broken", err: errors.New("unavailable")}
+	bus := NewBus("kcp", []SinkConfig{{Sink: sink}})
+	bus.Publish(WorkspaceCreatedEventType, "root:org", "root:org", nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	bus.Start(ctx, time.Minute)
+	require.Equal(t, sendAttempts, sink.calls)
+	require.Empty(t, sink.events())
+}
+
+func TestWorkspaceOf(t *testing.T) {
+	require.Equal(t, "root:org", workspaceOf("/clusters/root:org/api/v1/namespaces"))
+	require.Equal(t, "root:org", workspaceOf("/clusters/root:org?watch=true"))
+	require.Equal(t, "", workspaceOf("/api/v1/namespaces"))
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventbus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// CloudEventsSink posts events to a CloudEvents HTTP receiver, in structured JSON mode.
+type CloudEventsSink struct {
+	SinkName string
+	URL      string
+	// Batch sends the events of a batch in one request, in batched JSON mode. Otherwise, an
+	// event is sent per request.
+	Batch bool
+	// Headers are added to every request, e.g. for authentication.
+	Headers map[string]string
+	Client  *http.Client
+}
+
+var _ Sink = &CloudEventsSink{}
+
+func (s *CloudEventsSink) Name() string {
+	return s.SinkName
+}
+
+func (s *CloudEventsSink) Send(ctx context.Context, events []Event) error {
+	if s.Batch {
+		return s.post(ctx, "application/cloudevents-batch+json", events)
+	}
+	for _, event := range events {
+		if err := s.post(ctx, "application/cloudevents+json", event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *CloudEventsSink) post(ctx context.Context, contentType string, body interface{}) error {
+	return postJSON(ctx, s.Client, s.URL, contentType, s.Headers, body)
+}
+
+// KafkaRESTSink produces events to a Kafka topic through a Kafka REST proxy speaking the v2
+// API, e.g. the Confluent REST Proxy or Strimzi Kafka Bridge. Events are keyed by workspace,
+// such that the events of a workspace keep their order.
+type KafkaRESTSink struct {
+	SinkName string
+	// URL is the base URL of the REST proxy.
+	URL   string
+	Topic string
+	// Headers are added to every request, e.g. for authentication.
+	Headers map[string]string
+	Client  *http.Client
+}
+
+var _ Sink = &KafkaRESTSink{}
+
+type kafkaRecords struct {
+	Records []kafkaRecord `json:"records"`
+}
+
+type kafkaRecord struct {
+	Key   string `json:"key,omitempty"`
+	Value Event  `json:"value"`
+}
+
+func (s *KafkaRESTSink) Name() string {
+	return s.SinkName
+}
+
+func (s *KafkaRESTSink) Send(ctx context.Context, events []Event) error {
+	records := kafkaRecords{Records: make([]kafkaRecord, 0, len(events))}
+	for _, event := range events {
+		records.Records = append(records.Records, kafkaRecord{Key: event.Workspace, Value: event})
+	}
+	u := strings.TrimSuffix(s.URL, "/") + "/topics/" + url.PathEscape(s.Topic)
+	return postJSON(ctx, s.Client, u, "application/vnd.kafka.json.v2+json", s.Headers, records)
+}
+
+func postJSON(ctx context.Context, client *http.Client, url, contentType string, headers map[string]string, body interface{}) error {
+	bs, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(bs))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("POST %s failed with %s: %s", url, resp.Status, bytes.TrimSpace(msg))
+	}
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	return nil
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloudEventsSink(t *testing.T) {
+	events := []Event{
+		{SpecVersion: SpecVersion, ID: "1", Source: "kcp", Type: WorkspaceCreatedEventType, Workspace: "root:org:a"},
+		{SpecVersion: SpecVersion, ID: "2", Source: "kcp", Type: WorkspaceCreatedEventType, Workspace: "root:org:b"},
+	}
+
+	for _, batch := range []bool{true, false} {
+		var requests []*http.Request
+		var bodies [][]byte
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := ioutil.ReadAll(r.Body)
+			require.NoError(t, err)
+			requests = append(requests, r)
+			bodies = append(bodies, body)
+			w.WriteHeader(http.StatusAccepted)
+		}))
+
+		sink := &CloudEventsSink{SinkName: "receiver", URL: server.URL + "/events", Batch: batch, Headers: map[string]string{"Authorization": "Bearer secret"}, Client: server.Client()}
+		require.NoError(t, sink.Send(context.Background(), events))
+		server.Close()
+
+		if batch {
+			require.Len(t, requests, 1)
+			require.Equal(t, "application/cloudevents-batch+json", requests[0].Header.Get("Content-Type"))
+			var got []Event
+			require.NoError(t, json.Unmarshal(bodies[0], &got))
+			require.Len(t, got, 2)
+		} else {
+			require.Len(t, requests, 2)
+			require.Equal(t, "application/cloudevents+json", requests[1].Header.Get("Content-Type"))
+			var got Event
+			require.NoError(t, json.Unmarshal(bodies[1], &got))
+			require.Equal(t, "2", got.ID)
+			require.Equal(t, "root:org:b", got.Workspace)
+		}
+		require.Equal(t, "/events", requests[0].URL.Path)
+		require.Equal(t, "Bearer secret", requests[0].Header.Get("Authorization"))
+	}
+}
+
+func TestCloudEventsSinkFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "overloaded", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	sink := &CloudEventsSink{SinkName: "receiver", URL: server.URL, Client: server.Client()}
+	err := sink.Send(context.Background(), []Event{{ID: "1"}})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "overloaded")
+}
+
+func TestKafkaRESTSink(t *testing.T) {
+	var got kafkaRecords
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/topics/kcp-events", r.URL.Path)
+		require.Equal(t, "application/vnd.kafka.json.v2+json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sink := &KafkaRESTSink{SinkName: "kafka", URL: server.URL + "/", Topic: "kcp-events", Client: server.Client()}
+	require.NoError(t, sink.Send(context.Background(), []Event{
+		{ID: "1", Type: AuditEventType, Workspace: "root:org"},
+		{ID: "2", Type: APIBindingCreatedEventType, Workspace: "root:org:team"},
+	}))
+
+	require.Len(t, got.Records, 2)
+	require.Equal(t, "root:org", got.Records[0].Key)
+	require.Equal(t, "1", got.Records[0].Value.ID)
+	require.Equal(t, "root:org:team", got.Records[1].Key)
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventbus
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/client-go/tools/cache"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	apisinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/apis/v1alpha1"
+	tenancyinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/third_party/conditions/util/conditions"
+)
+
+// WorkspaceEventData is the data of workspace lifecycle events.
+type WorkspaceEventData struct {
+	// Workspace is the logical cluster of the workspace.
+	Workspace string                                    `json:"workspace"`
+	Type      string                                    `json:"type,omitempty"`
+	Phase     tenancyv1alpha1.ClusterWorkspacePhaseType `json:"phase,omitempty"`
+	OldPhase  tenancyv1alpha1.ClusterWorkspacePhaseType `json:"oldPhase,omitempty"`
+	URL       string                                    `json:"url,omitempty"`
+
+	Expiration *tenancyv1alpha1.WorkspaceExpiration `json:"expiration,omitempty"`
+}
+
+// APIBindingEventData is the data of APIBinding lifecycle events.
+type APIBindingEventData struct {
+	Name           string                           `json:"name"`
+	Reference      apisv1alpha1.ExportReference     `json:"reference"`
+	Phase          apisv1alpha1.APIBindingPhaseType `json:"phase,omitempty"`
+	BoundResources []apisv1alpha1.BoundAPIResource  `json:"boundResources,omitempty"`
+}
+
+// AddLifecycleEventHandlers publishes lifecycle events of ClusterWorkspaces and APIBindings to
+// the bus. Objects created before the given time, e.g. those listed initially by the informers,
+// do not cause created events.
+func AddLifecycleEventHandlers(bus *Bus, since time.Time, workspaceInformer tenancyinformers.ClusterWorkspaceInformer, apiBindingInformer apisinformers.APIBindingInformer) {
+	workspaceInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			ws, ok := obj.(*tenancyv1alpha1.ClusterWorkspace)
+			if !ok || ws.CreationTimestamp.Time.Before(since) {
+				return
+			}
+			publishWorkspaceEvent(bus, WorkspaceCreatedEventType, ws, "")
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldWS, ok := oldObj.(*tenancyv1alpha1.ClusterWorkspace)
+			if !ok {
+				return
+			}
+			newWS, ok := newObj.(*tenancyv1alpha1.ClusterWorkspace)
+			if !ok {
+				return
+			}
+			if oldWS.Status.Phase != newWS.Status.Phase {
+				publishWorkspaceEvent(bus, WorkspacePhaseChangedEventType, newWS, oldWS.Status.Phase)
+			}
+			if !conditions.IsTrue(oldWS, tenancyv1alpha1.WorkspaceExpiring) && conditions.IsTrue(newWS, tenancyv1alpha1.WorkspaceExpiring) {
+				publishWorkspaceEvent(bus, WorkspaceExpiringEventType, newWS, "")
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			ws, ok := obj.(*tenancyv1alpha1.ClusterWorkspace)
+			if !ok {
+				return
+			}
+			publishWorkspaceEvent(bus, WorkspaceDeletedEventType, ws, "")
+		},
+	})
+
+	apiBindingInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			binding, ok := obj.(*apisv1alpha1.APIBinding)
+			if !ok || binding.CreationTimestamp.Time.Before(since) {
+				return
+			}
+			publishAPIBindingEvent(bus, APIBindingCreatedEventType, binding)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldBinding, ok := oldObj.(*apisv1alpha1.APIBinding)
+			if !ok {
+				return
+			}
+			newBinding, ok := newObj.(*apisv1alpha1.APIBinding)
+			if !ok || !apiBindingChanged(oldBinding, newBinding) {
+				return
+			}
+			publishAPIBindingEvent(bus, APIBindingChangedEventType, newBinding)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			binding, ok := obj.(*apisv1alpha1.APIBinding)
+			if !ok {
+				return
+			}
+			publishAPIBindingEvent(bus, APIBindingDeletedEventType, binding)
+		},
+	})
+}
+
+func publishWorkspaceEvent(bus *Bus, eventType string, ws *tenancyv1alpha1.ClusterWorkspace, oldPhase tenancyv1alpha1.ClusterWorkspacePhaseType) {
+	workspace := logicalcluster.From(ws).Join(ws.Name).String()
+	// the events of a workspace belong to the workspace itself, such that filters for a
+	// sub-tree select the creation of its root.
+	bus.Publish(eventType, workspace, workspace, WorkspaceEventData{
+		Workspace: workspace,
+		Type:      ws.Spec.Type,
+		Phase:     ws.Status.Phase,
+		OldPhase:  oldPhase,
+		URL:       ws.Status.BaseURL,
+
+		Expiration: ws.Spec.Expiration,
+	})
+}
+
+func publishAPIBindingEvent(bus *Bus, eventType string, binding *apisv1alpha1.APIBinding) {
+	bus.Publish(eventType, logicalcluster.From(binding).String(), binding.Name, APIBindingEventData{
+		Name:           binding.Name,
+		Reference:      binding.Spec.Reference,
+		Phase:          binding.Status.Phase,
+		BoundResources: binding.Status.BoundResources,
+	})
+}
+
+// apiBindingChanged returns whether the reference, the phase or the bound resources of the
+// binding changed.
+func apiBindingChanged(old, new *apisv1alpha1.APIBinding) bool {
+	return !reflect.DeepEqual(old.Spec.Reference, new.Spec.Reference) ||
+		old.Status.Phase != new.Status.Phase ||
+		!reflect.DeepEqual(old.Status.BoundResources, new.Status.BoundResources)
+}
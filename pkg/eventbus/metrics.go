@@ -0,0 +1,55 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventbus
+
+import (
+	"sync"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+var (
+	sentEvents = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace:      "kcp",
+			Name:           "event_sink_sent_events_total",
+			Help:           "Events delivered to an event sink, by sink.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"sink"},
+	)
+	droppedEvents = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace:      "kcp",
+			Name:           "event_sink_dropped_events_total",
+			Help:           "Events dropped for an event sink, by sink and reason (buffer_full or send_failed).",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"sink", "reason"},
+	)
+
+	registerMetrics sync.Once
+)
+
+// RegisterMetrics registers the event bus metrics.
+func RegisterMetrics() {
+	registerMetrics.Do(func() {
+		legacyregistry.MustRegister(sentEvents)
+		legacyregistry.MustRegister(droppedEvents)
+	})
+}
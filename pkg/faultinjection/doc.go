@@ -0,0 +1,22 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package faultinjection injects faults into a kcp server and the front-proxy in
+// developer mode, such that resilience behaviors like retries and failover can be
+// tested deterministically: storage operations are delayed or failed, watch
+// events are dropped, and the front-proxy is partitioned from shards, on demand
+// through debug endpoints.
+package faultinjection
@@ -0,0 +1,280 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package faultinjection
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// DebugPath is where the Injector serves its rules.
+const DebugPath = "/debug/kcp/faults"
+
+// Verbs are the storage operations rules can inject faults into.
+var Verbs = sets.NewString("get", "list", "create", "update", "delete", "watch")
+
+// Rule describes a fault injected into the storage operations it matches.
+type Rule struct {
+	// Name identifies the rule.
+	Name string `json:"name"`
+	// Resource is the resource in the form <resource>[.<group>] the rule matches.
+	// All resources are matched if empty.
+	Resource string `json:"resource,omitempty"`
+	// Verbs are the storage operations the rule matches, out of get, list, create,
+	// update, delete and watch. All are matched if empty.
+	Verbs []string `json:"verbs,omitempty"`
+	// Cluster is the logical cluster the rule matches. All are matched if empty.
+	Cluster string `json:"cluster,omitempty"`
+
+	// Delay delays the matching operations.
+	Delay metav1.Duration `json:"delay,omitempty"`
+	// Code fails the matching operations with an error of this HTTP status code
+	// after the delay, e.g. 500, 503 or 504.
+	Code int `json:"code,omitempty"`
+	// DropWatchEvents drops the events of matching watches instead of delaying or
+	// failing the watch requests. Each dropped event counts against Count.
+	DropWatchEvents bool `json:"dropWatchEvents,omitempty"`
+
+	// Count is how often the rule injects a fault before it is exhausted. It is
+	// unlimited if zero.
+	Count int `json:"count,omitempty"`
+	// Injected is how often the rule injected a fault.
+	Injected int `json:"injected"`
+}
+
+// Validate returns the errors of a rule.
+func (r *Rule) Validate() []error {
+	var errs []error
+	if r.Name == "" {
+		errs = append(errs, fmt.Errorf("name is required"))
+	}
+	for _, verb := range r.Verbs {
+		if !Verbs.Has(verb) {
+			errs = append(errs, fmt.Errorf("unsupported verb %q, expected one of %s", verb, strings.Join(Verbs.List(), ", ")))
+		}
+	}
+	if r.Delay.Duration < 0 {
+		errs = append(errs, fmt.Errorf("delay must be non-negative"))
+	}
+	if r.Code != 0 && (r.Code < 400 || r.Code > 599) {
+		errs = append(errs, fmt.Errorf("code must be a HTTP error status code between 400 and 599"))
+	}
+	if r.DropWatchEvents {
+		if len(r.Verbs) > 0 && !(len(r.Verbs) == 1 && r.Verbs[0] == "watch") {
+			errs = append(errs, fmt.Errorf("dropWatchEvents only applies to the watch verb"))
+		}
+		if r.Code != 0 || r.Delay.Duration != 0 {
+			errs = append(errs, fmt.Errorf("dropWatchEvents cannot be combined with delay or code"))
+		}
+	} else if r.Code == 0 && r.Delay.Duration == 0 {
+		errs = append(errs, fmt.Errorf("one of delay, code or dropWatchEvents is required"))
+	}
+	if r.Count < 0 {
+		errs = append(errs, fmt.Errorf("count must be non-negative"))
+	}
+	return errs
+}
+
+func (r *Rule) matches(cluster logicalcluster.Name, resource schema.GroupResource, verb string) bool {
+	if r.Count > 0 && r.Injected >= r.Count {
+		return false
+	}
+	if r.Resource != "" && schema.ParseGroupResource(r.Resource) != resource {
+		return false
+	}
+	if len(r.Verbs) > 0 && !sets.NewString(r.Verbs...).Has(verb) {
+		return false
+	}
+	if r.Cluster != "" && r.Cluster != cluster.String() {
+		return false
+	}
+	return true
+}
+
+// Injector injects faults into storage operations according to its rules.
+// Rules are consulted in the order they were added, the first matching one
+// applies.
+type Injector struct {
+	// sleep waits for the given duration or until ctx is done.
+	sleep func(ctx context.Context, d time.Duration) error
+
+	lock  sync.Mutex
+	rules []*Rule
+}
+
+// NewInjector returns an Injector without rules.
+func NewInjector() *Injector {
+	return &Injector{
+		sleep: func(ctx context.Context, d time.Duration) error {
+			t := time.NewTimer(d)
+			defer t.Stop()
+			select {
+			case <-t.C:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	}
+}
+
+// Rules returns a copy of the rules.
+func (i *Injector) Rules() []Rule {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	rules := make([]Rule, 0, len(i.rules))
+	for _, r := range i.rules {
+		rules = append(rules, *r)
+	}
+	return rules
+}
+
+// AddRule adds a rule, replacing the rule of the same name.
+func (i *Injector) AddRule(rule Rule) error {
+	if errs := rule.Validate(); len(errs) > 0 {
+		msgs := make([]string, 0, len(errs))
+		for _, err := range errs {
+			msgs = append(msgs, err.Error())
+		}
+		return fmt.Errorf("invalid rule: %s", strings.Join(msgs, ", "))
+	}
+	rule.Injected = 0
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	for n, r := range i.rules {
+		if r.Name == rule.Name {
+			i.rules[n] = &rule
+			return nil
+		}
+	}
+	i.rules = append(i.rules, &rule)
+	return nil
+}
+
+// DeleteRule deletes the rule of the given name. All rules are deleted if the
+// name is empty.
+func (i *Injector) DeleteRule(name string) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	if name == "" {
+		i.rules = nil
+		return
+	}
+	for n, r := range i.rules {
+		if r.Name == name {
+			i.rules = append(i.rules[:n], i.rules[n+1:]...)
+			return
+		}
+	}
+}
+
+// Inject delays or fails a storage operation if a rule matches it. The returned
+// error is the injected one.
+func (i *Injector) Inject(ctx context.Context, cluster logicalcluster.Name, resource schema.GroupResource, verb string) error {
+	i.lock.Lock()
+	var rule *Rule
+	for _, r := range i.rules {
+		if !r.DropWatchEvents && r.matches(cluster, resource, verb) {
+			r.Injected++
+			rule = r
+			break
+		}
+	}
+	var delay time.Duration
+	var code int
+	var name string
+	if rule != nil {
+		delay, code, name = rule.Delay.Duration, rule.Code, rule.Name
+	}
+	i.lock.Unlock()
+
+	if rule == nil {
+		return nil
+	}
+
+	if delay > 0 {
+		injectedFaults.WithLabelValues("delay").Inc()
+		if err := i.sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+	if code != 0 {
+		injectedFaults.WithLabelValues("error").Inc()
+		return apierrors.NewGenericServerResponse(code, verb, resource, "", fmt.Sprintf("fault injected by rule %q", name), 0, true)
+	}
+	return nil
+}
+
+// DropWatchEvent returns whether an event of a watch is to be dropped.
+func (i *Injector) DropWatchEvent(cluster logicalcluster.Name, resource schema.GroupResource) bool {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	for _, r := range i.rules {
+		if r.DropWatchEvents && r.matches(cluster, resource, "watch") {
+			r.Injected++
+			injectedFaults.WithLabelValues("drop").Inc()
+			return true
+		}
+	}
+	return false
+}
+
+// ServeHTTP lists the rules on GET, adds the rule in the body on POST, and
+// deletes the rule given by the name parameter, or all rules without it, on DELETE.
+func (i *Injector) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		var rule Rule
+		if err := json.NewDecoder(req.Body).Decode(&rule); err != nil {
+			http.Error(w, fmt.Sprintf("invalid rule: %v", err), http.StatusBadRequest)
+			return
+		}
+		if err := i.AddRule(rule); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	case http.MethodDelete:
+		i.DeleteRule(req.URL.Query().Get("name"))
+	default:
+		w.Header().Set("Allow", "GET, POST, DELETE")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(i.Rules()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package faultinjection
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestRuleValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    Rule
+		wantErr string
+	}{
+		{name: "delay", rule: Rule{Name: "a", Delay: metav1.Duration{Duration: time.Second}}},
+		{name: "error", rule: Rule{Name: "a", Resource: "deployments.apps", Verbs: []string{"create"}, Code: 503, Count: 1}},
+		{name: "drop", rule: Rule{Name: "a", Verbs: []string{"watch"}, DropWatchEvents: true}},
+		{name: "no name", rule: Rule{Code: 500}, wantErr: "name is required"},
+		{name: "no fault", rule: Rule{Name: "a"}, wantErr: "one of delay, code or dropWatchEvents is required"},
+		{name: "unknown verb", rule: Rule{Name: "a", Verbs: []string{"patch"}, Code: 500}, wantErr: `unsupported verb "patch"`},
+		{name: "invalid code", rule: Rule{Name: "a", Code: 200}, wantErr: "code must be a HTTP error status code"},
+		{name: "drop on list", rule: Rule{Name: "a", Verbs: []string{"list"}, DropWatchEvents: true}, wantErr: "dropWatchEvents only applies to the watch verb"},
+		{name: "drop with code", rule: Rule{Name: "a", DropWatchEvents: true, Code: 500}, wantErr: "dropWatchEvents cannot be combined with delay or code"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.rule.Validate()
+			if tt.wantErr == "" {
+				require.Empty(t, errs)
+				return
+			}
+			require.Len(t, errs, 1)
+			require.Contains(t, errs[0].Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestInject(t *testing.T) {
+	deployments := schema.GroupResource{Group: "apps", Resource: "deployments"}
+	configMaps := schema.GroupResource{Resource: "configmaps"}
+	cluster := logicalcluster.New("root:org:ws")
+
+	injector := NewInjector()
+	var slept []time.Duration
+	injector.sleep = func(ctx context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		return nil
+	}
+
+	require.NoError(t, injector.AddRule(Rule{Name: "fail-create", Resource: "deployments.apps", Verbs: []string{"create"}, Code: 503, Count: 2}))
+	require.NoError(t, injector.AddRule(Rule{Name: "slow", Cluster: "root:org:ws", Delay: metav1.Duration{Duration: time.Second}}))
+	require.NoError(t, injector.AddRule(Rule{Name: "drop", Resource: "configmaps", DropWatchEvents: true, Count: 1}))
+
+	t.Log("The first matching rule applies until it is exhausted")
+	for n := 0; n < 2; n++ {
+		err := injector.Inject(context.Background(), cluster, deployments, "create")
+		require.True(t, apierrors.IsServiceUnavailable(err), "unexpected error: %v", err)
+		require.Contains(t, err.Error(), `fault injected by rule "fail-create"`)
+	}
+	require.Empty(t, slept)
+	require.NoError(t, injector.Inject(context.Background(), cluster, deployments, "create"))
+	require.Equal(t, []time.Duration{time.Second}, slept)
+
+	t.Log("Rules not matching the cluster do not apply")
+	require.NoError(t, injector.Inject(context.Background(), logicalcluster.New("root:other"), deployments, "get"))
+	require.Len(t, slept, 1)
+
+	t.Log("Watch events are dropped until the rule is exhausted")
+	require.False(t, injector.DropWatchEvent(cluster, deployments))
+	require.True(t, injector.DropWatchEvent(cluster, configMaps))
+	require.False(t, injector.DropWatchEvent(cluster, configMaps))
+
+	rules := injector.Rules()
+	require.Len(t, rules, 3)
+	require.Equal(t, 2, rules[0].Injected)
+	require.Equal(t, 1, rules[1].Injected)
+	require.Equal(t, 1, rules[2].Injected)
+
+	t.Log("Replacing a rule resets its count")
+	require.NoError(t, injector.AddRule(Rule{Name: "fail-create", Resource: "deployments.apps", Verbs: []string{"create"}, Code: 500, Count: 1}))
+	err := injector.Inject(context.Background(), cluster, deployments, "create")
+	require.True(t, apierrors.IsInternalError(err), "unexpected error: %v", err)
+
+	injector.DeleteRule("slow")
+	require.Len(t, injector.Rules(), 2)
+	injector.DeleteRule("")
+	require.Empty(t, injector.Rules())
+}
+
+func TestInjectDelayCancelled(t *testing.T) {
+	injector := NewInjector()
+	require.NoError(t, injector.AddRule(Rule{Name: "slow", Delay: metav1.Duration{Duration: time.Hour}, Code: 500}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := injector.Inject(ctx, logicalcluster.New("root"), schema.GroupResource{Resource: "configmaps"}, "get")
+	require.Equal(t, context.Canceled, err)
+}
+
+func TestInjectorServeHTTP(t *testing.T) {
+	injector := NewInjector()
+
+	serve := func(method, url, body string) (int, []Rule) {
+		w := httptest.NewRecorder()
+		injector.ServeHTTP(w, httptest.NewRequest(method, url, strings.NewReader(body)))
+		var rules []Rule
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &rules))
+		}
+		return w.Code, rules
+	}
+
+	code, rules := serve(http.MethodPost, DebugPath, `{"name":"a","verbs":["get"],"delay":"2s"}`)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, []Rule{{Name: "a", Verbs: []string{"get"}, Delay: metav1.Duration{Duration: 2 * time.Second}}}, rules)
+
+	code, _ = serve(http.MethodPost, DebugPath, `{"name":"b"}`)
+	require.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = serve(http.MethodPost, DebugPath, `{"name":"b","code":503}`)
+	require.Equal(t, http.StatusOK, code)
+
+	code, rules = serve(http.MethodDelete, DebugPath+"?name=a", "")
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, rules, 1)
+	require.Equal(t, "b", rules[0].Name)
+
+	code, rules = serve(http.MethodGet, DebugPath, "")
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, rules, 1)
+
+	code, _ = serve(http.MethodPut, DebugPath, "")
+	require.Equal(t, http.StatusMethodNotAllowed, code)
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package faultinjection
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// PartitionsDebugPath is where Partitions serves the partitioned backends.
+const PartitionsDebugPath = "/debug/kcp/partitions"
+
+// Partitions is the set of backends, e.g. shards, a proxy is partitioned from.
+// Requests to partitioned backends fail as if the backend was unreachable.
+type Partitions struct {
+	lock     sync.RWMutex
+	backends sets.String
+}
+
+// NewPartitions returns Partitions without partitioned backends.
+func NewPartitions() *Partitions {
+	return &Partitions{backends: sets.NewString()}
+}
+
+// Partition partitions the proxy from a backend.
+func (p *Partitions) Partition(backend string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.backends.Insert(backend)
+}
+
+// Heal heals the partition from a backend. All partitions are healed if the
+// backend is empty.
+func (p *Partitions) Heal(backend string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if backend == "" {
+		p.backends = sets.NewString()
+		return
+	}
+	p.backends.Delete(backend)
+}
+
+// Partitioned returns whether the proxy is partitioned from a backend.
+func (p *Partitions) Partitioned(backend string) bool {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.backends.Has(backend)
+}
+
+// Backends returns the partitioned backends in sorted order.
+func (p *Partitions) Backends() []string {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.backends.List()
+}
+
+// WrapTransport fails the requests of a transport to a backend while the proxy is
+// partitioned from it.
+func (p *Partitions) WrapTransport(backend string, rt http.RoundTripper) http.RoundTripper {
+	return &partitionedTransport{partitions: p, backend: backend, delegate: rt}
+}
+
+type partitionedTransport struct {
+	partitions *Partitions
+	backend    string
+	delegate   http.RoundTripper
+}
+
+func (t *partitionedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.partitions.Partitioned(t.backend) {
+		injectedFaults.WithLabelValues("partition").Inc()
+		return nil, fmt.Errorf("fault injected: partitioned from backend %s", t.backend)
+	}
+	return t.delegate.RoundTrip(req)
+}
+
+// ServeHTTP lists the partitioned backends on GET, partitions the proxy from the
+// backend given by the backend parameter on POST, and heals the partition from it,
+// or all partitions without it, on DELETE.
+func (p *Partitions) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	backend := req.URL.Query().Get("backend")
+	switch req.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		if backend == "" {
+			http.Error(w, "backend parameter is required", http.StatusBadRequest)
+			return
+		}
+		p.Partition(backend)
+	case http.MethodDelete:
+		p.Heal(backend)
+	default:
+		w.Header().Set("Allow", "GET, POST, DELETE")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(p.Backends()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package faultinjection
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartitions(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}))
+	defer backend.Close()
+
+	partitions := NewPartitions()
+	client := &http.Client{Transport: partitions.WrapTransport(backend.URL, http.DefaultTransport)}
+
+	resp, err := client.Get(backend.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	serve := func(method, url string) (int, []string) {
+		w := httptest.NewRecorder()
+		partitions.ServeHTTP(w, httptest.NewRequest(method, url, nil))
+		var backends []string
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &backends))
+		}
+		return w.Code, backends
+	}
+
+	code, backends := serve(http.MethodPost, PartitionsDebugPath+"?backend="+backend.URL)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, []string{backend.URL}, backends)
+
+	_, err = client.Get(backend.URL)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "partitioned from backend "+backend.URL)
+
+	code, _ = serve(http.MethodPost, PartitionsDebugPath)
+	require.Equal(t, http.StatusBadRequest, code)
+
+	code, backends = serve(http.MethodDelete, PartitionsDebugPath)
+	require.Equal(t, http.StatusOK, code)
+	require.Empty(t, backends)
+
+	resp, err = client.Get(backend.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package faultinjection
+
+import (
+	"context"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/generic"
+	"k8s.io/apiserver/pkg/storage"
+	"k8s.io/apiserver/pkg/storage/storagebackend"
+	"k8s.io/apiserver/pkg/storage/storagebackend/factory"
+	"k8s.io/client-go/tools/cache"
+)
+
+// WithFaultInjection wraps a RESTOptionsGetter such that the storage operations of
+// all resources are subject to the rules of the injector.
+func WithFaultInjection(delegate generic.RESTOptionsGetter, injector *Injector) generic.RESTOptionsGetter {
+	if injector == nil {
+		return delegate
+	}
+	return &faultInjectingRESTOptionsGetter{
+		delegate: delegate,
+		injector: injector,
+	}
+}
+
+type faultInjectingRESTOptionsGetter struct {
+	delegate generic.RESTOptionsGetter
+	injector *Injector
+}
+
+func (g *faultInjectingRESTOptionsGetter) GetRESTOptions(resource schema.GroupResource) (generic.RESTOptions, error) {
+	options, err := g.delegate.GetRESTOptions(resource)
+	if err != nil {
+		return options, err
+	}
+	if options.Decorator == nil {
+		return options, nil
+	}
+
+	decorator := options.Decorator
+	options.Decorator = func(
+		config *storagebackend.ConfigForResource,
+		resourcePrefix string,
+		keyFunc func(obj runtime.Object) (string, error),
+		newFunc func() runtime.Object,
+		newListFunc func() runtime.Object,
+		getAttrsFunc storage.AttrFunc,
+		trigger storage.IndexerFuncs,
+		indexers *cache.Indexers,
+	) (storage.Interface, factory.DestroyFunc, error) {
+		s, destroy, err := decorator(config, resourcePrefix, keyFunc, newFunc, newListFunc, getAttrsFunc, trigger, indexers)
+		if err != nil {
+			return s, destroy, err
+		}
+		return &faultyStorage{Interface: s, resource: resource, injector: g.injector}, destroy, nil
+	}
+	return options, nil
+}
+
+// faultyStorage injects faults into the operations of a storage.Interface.
+type faultyStorage struct {
+	storage.Interface
+	resource schema.GroupResource
+	injector *Injector
+}
+
+func (s *faultyStorage) inject(ctx context.Context, verb string) error {
+	return s.injector.Inject(ctx, clusterFrom(ctx), s.resource, verb)
+}
+
+func (s *faultyStorage) Create(ctx context.Context, key string, obj, out runtime.Object, ttl uint64) error {
+	if err := s.inject(ctx, "create"); err != nil {
+		return err
+	}
+	return s.Interface.Create(ctx, key, obj, out, ttl)
+}
+
+func (s *faultyStorage) Delete(ctx context.Context, key string, out runtime.Object, preconditions *storage.Preconditions, validateDeletion storage.ValidateObjectFunc, cachedExistingObject runtime.Object) error {
+	if err := s.inject(ctx, "delete"); err != nil {
+		return err
+	}
+	return s.Interface.Delete(ctx, key, out, preconditions, validateDeletion, cachedExistingObject)
+}
+
+func (s *faultyStorage) Watch(ctx context.Context, key string, opts storage.ListOptions) (watch.Interface, error) {
+	if err := s.inject(ctx, "watch"); err != nil {
+		return nil, err
+	}
+	w, err := s.Interface.Watch(ctx, key, opts)
+	if err != nil {
+		return nil, err
+	}
+	return s.dropping(ctx, w), nil
+}
+
+func (s *faultyStorage) WatchList(ctx context.Context, key string, opts storage.ListOptions) (watch.Interface, error) {
+	if err := s.inject(ctx, "watch"); err != nil {
+		return nil, err
+	}
+	w, err := s.Interface.WatchList(ctx, key, opts)
+	if err != nil {
+		return nil, err
+	}
+	return s.dropping(ctx, w), nil
+}
+
+func (s *faultyStorage) Get(ctx context.Context, key string, opts storage.GetOptions, objPtr runtime.Object) error {
+	if err := s.inject(ctx, "get"); err != nil {
+		return err
+	}
+	return s.Interface.Get(ctx, key, opts, objPtr)
+}
+
+func (s *faultyStorage) GetToList(ctx context.Context, key string, opts storage.ListOptions, listObj runtime.Object) error {
+	if err := s.inject(ctx, "list"); err != nil {
+		return err
+	}
+	return s.Interface.GetToList(ctx, key, opts, listObj)
+}
+
+func (s *faultyStorage) List(ctx context.Context, key string, opts storage.ListOptions, listObj runtime.Object) error {
+	if err := s.inject(ctx, "list"); err != nil {
+		return err
+	}
+	return s.Interface.List(ctx, key, opts, listObj)
+}
+
+func (s *faultyStorage) GuaranteedUpdate(ctx context.Context, key string, ptrToType runtime.Object, ignoreNotFound bool, preconditions *storage.Preconditions, tryUpdate storage.UpdateFunc, cachedExistingObject runtime.Object) error {
+	if err := s.inject(ctx, "update"); err != nil {
+		return err
+	}
+	return s.Interface.GuaranteedUpdate(ctx, key, ptrToType, ignoreNotFound, preconditions, tryUpdate, cachedExistingObject)
+}
+
+// dropping drops the events of a watch matched by rules dropping watch events.
+// Bookmarks and errors are never dropped.
+func (s *faultyStorage) dropping(ctx context.Context, w watch.Interface) watch.Interface {
+	cluster := clusterFrom(ctx)
+	return watch.Filter(w, func(in watch.Event) (watch.Event, bool) {
+		switch in.Type {
+		case watch.Bookmark, watch.Error:
+			return in, true
+		}
+		return in, !s.injector.DropWatchEvent(cluster, s.resource)
+	})
+}
+
+func clusterFrom(ctx context.Context) logicalcluster.Name {
+	if cluster := genericapirequest.ClusterFrom(ctx); cluster != nil {
+		return cluster.Name
+	}
+	return logicalcluster.Name{}
+}
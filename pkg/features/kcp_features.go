@@ -41,6 +41,41 @@ const (
 	//
 	// Enable the scheduling.kcp.dev/v1alpha1 API group, and related controllers.
 	LocationAPI featuregate.Feature = "KCPLocationAPI"
+
+	// owner: @rgolangh
+	// alpha: v0.5
+	//
+	// Serve the OpenAPI v2 documents of workspaces from a cache shared by all workspaces of
+	// a shard, building the schema of every CRD version once for equal content.
+	OpenAPICache featuregate.Feature = "KCPOpenAPICache"
+
+	// owner: @rgolangh
+	// alpha: v0.5
+	//
+	// Compress responses of shards and virtual workspaces with gzip for clients accepting it,
+	// including watch streams.
+	ResponseCompression featuregate.Feature = "KCPResponseCompression"
+
+	// owner: @rgolangh
+	// alpha: v0.5
+	//
+	// Keep short-lived tokens of ServiceAccounts synced to workload clusters in secrets that are
+	// synced along, for pods to authenticate with their workspace identity.
+	WorkloadIdentity featuregate.Feature = "KCPWorkloadIdentity"
+
+	// owner: @rgolangh
+	// alpha: v0.5
+	//
+	// Enable the EventSubscription API of workspaces, emitting CloudEvents for objects of bound
+	// resources that are created, updated or deleted.
+	EventSubscriptions featuregate.Feature = "KCPEventSubscriptions"
+
+	// owner: @rgolangh
+	// alpha: v0.5
+	//
+	// Enable the TTLPolicy API of workspaces, garbage-collecting objects of a resource a given
+	// time after their creation or completion.
+	TTLPolicies featuregate.Feature = "KCPTTLPolicies"
 )
 
 func init() {
@@ -55,6 +90,18 @@ func KnownFeatures() []string {
 	return features
 }
 
+// Enabled returns the known generic control plane features that are enabled, sorted.
+func Enabled() []string {
+	var features []string
+	for k := range defaultGenericControlPlaneFeatureGates {
+		if utilfeature.DefaultFeatureGate.Enabled(k) {
+			features = append(features, string(k))
+		}
+	}
+	sort.Strings(features)
+	return features
+}
+
 // NewFlagValue returns a wrapper to be used for a pflag flag value.
 func NewFlagValue() pflag.Value {
 	return &kcpFeatureGate{
@@ -83,7 +130,12 @@ func (f *kcpFeatureGate) Type() string {
 // in the generic control plane code. To add a new feature, define a key for it above and add it
 // here. The features will be available throughout Kubernetes binaries.
 var defaultGenericControlPlaneFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
-	LocationAPI: {Default: false, PreRelease: featuregate.Alpha},
+	LocationAPI:         {Default: false, PreRelease: featuregate.Alpha},
+	OpenAPICache:        {Default: false, PreRelease: featuregate.Alpha},
+	ResponseCompression: {Default: false, PreRelease: featuregate.Alpha},
+	WorkloadIdentity:    {Default: false, PreRelease: featuregate.Alpha},
+	EventSubscriptions:  {Default: false, PreRelease: featuregate.Alpha},
+	TTLPolicies:         {Default: false, PreRelease: featuregate.Alpha},
 
 	// inherited features from generic apiserver, relisted here to get a conflict if it is changed
 	// unintentionally on either side:
@@ -96,4 +148,8 @@ var defaultGenericControlPlaneFeatureGates = map[featuregate.Feature]featuregate
 	genericfeatures.ServerSideApply:         {Default: true, PreRelease: featuregate.GA},
 	genericfeatures.APIPriorityAndFairness:  {Default: true, PreRelease: featuregate.Beta},
 	genericfeatures.WarningHeaders:          {Default: true, PreRelease: featuregate.GA, LockToDefault: true}, // remove in 1.24
+
+	// validation rules of CRDs are opt-in, as upstream. Transition rules generated for x-kcp-immutable
+	// markers of APIResourceSchemas need them.
+	genericfeatures.CustomResourceValidationExpressions: {Default: false, PreRelease: featuregate.Alpha},
 }
@@ -0,0 +1,49 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package finalizers provides helpers to reason about the finalizers of objects and the
+// domains owning them.
+package finalizers
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// Changed returns the finalizers that are added or removed in new compared to old, sorted.
+func Changed(old, new []string) []string {
+	oldSet, newSet := sets.NewString(old...), sets.NewString(new...)
+	return oldSet.Difference(newSet).Union(newSet.Difference(oldSet)).List()
+}
+
+// Domain returns the domain prefix of a finalizer, e.g. "wildwest.dev" for
+// "wildwest.dev/cleanup", or the empty string if it has none.
+func Domain(finalizer string) string {
+	i := strings.Index(finalizer, "/")
+	if i < 0 {
+		return ""
+	}
+	return finalizer[:i]
+}
+
+// InDomain returns whether the finalizer is prefixed with the domain or one of its
+// subdomains, e.g. "wildwest.dev/cleanup" and "cowboys.wildwest.dev/cleanup" are in
+// the domain "wildwest.dev".
+func InDomain(finalizer, domain string) bool {
+	d := Domain(finalizer)
+	return d != "" && (d == domain || strings.HasSuffix(d, "."+domain))
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package finalizers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChanged(t *testing.T) {
+	tests := map[string]struct {
+		old, new []string
+		want     []string
+	}{
+		"unchanged":         {old: []string{"a/x", "b/y"}, new: []string{"b/y", "a/x"}, want: []string{}},
+		"added and removed": {old: []string{"a/x", "b/y"}, new: []string{"c/z", "a/x"}, want: []string{"b/y", "c/z"}},
+		"created":           {new: []string{"b/y", "a/x"}, want: []string{"a/x", "b/y"}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tt.want, Changed(tt.old, tt.new))
+		})
+	}
+}
+
+func TestInDomain(t *testing.T) {
+	tests := []struct {
+		finalizer, domain string
+		want              bool
+	}{
+		{finalizer: "wildwest.dev/cleanup", domain: "wildwest.dev", want: true},
+		{finalizer: "cowboys.wildwest.dev/cleanup", domain: "wildwest.dev", want: true},
+		{finalizer: "notwildwest.dev/cleanup", domain: "wildwest.dev"},
+		{finalizer: "wildwest.dev", domain: "wildwest.dev"},
+		{finalizer: "kubernetes", domain: "wildwest.dev"},
+		{finalizer: "wildwest.dev.evil.com/cleanup", domain: "wildwest.dev"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.finalizer, func(t *testing.T) {
+			require.Equal(t, tt.want, InDomain(tt.finalizer, tt.domain))
+		})
+	}
+}
@@ -0,0 +1,26 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package immutablefields implements the x-kcp-immutable marker of APIResourceSchemas.
+//
+// API authors mark properties of the schema of an APIResourceSchema as immutable with
+//
+//	x-kcp-immutable: true
+//
+// The marker is translated into CEL transition rules of the CRD bound in consuming
+// workspaces, such that updates changing, adding or removing the value of the property
+// are rejected by the apiserver, without a webhook of the provider.
+package immutablefields
@@ -0,0 +1,210 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package immutablefields
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"k8s.io/apiextensions-apiserver/third_party/forked/celopenapi/model"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	genericfeatures "k8s.io/apiserver/pkg/features"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+)
+
+// Extension is the OpenAPI extension marking a property as immutable.
+const Extension = "x-kcp-immutable"
+
+const validationsExtension = "x-kubernetes-validations"
+
+// rootProperties are the properties of the root of the schema that cannot be marked.
+var rootProperties = sets.NewString("apiVersion", "kind", "metadata")
+
+// Validate checks the markers in the given JSON schema. Only properties of objects which
+// are themselves the root or properties can be marked, because transition rules need
+// the old value of the property, e.g. not items of lists. Optional properties must have
+// a name that can be accessed in CEL. Whether the markers are enforced does not matter,
+// such that schemas stay valid when the CustomResourceValidationExpressions feature gate
+// changes.
+func Validate(raw []byte, fldPath *field.Path) field.ErrorList {
+	var schema map[string]interface{}
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		return field.ErrorList{field.Invalid(fldPath, string(raw), fmt.Sprintf("invalid JSON: %v", err))}
+	}
+	if _, found := schema[Extension]; found {
+		return field.ErrorList{field.Forbidden(fldPath.Child(Extension), "cannot mark the whole object as immutable")}
+	}
+	return validate(schema, fldPath, true, true)
+}
+
+func validate(schema map[string]interface{}, fldPath *field.Path, correlatable, root bool) field.ErrorList {
+	var allErrs field.ErrorList
+
+	required := requiredProperties(schema)
+	properties, _ := schema["properties"].(map[string]interface{})
+	for _, name := range sortedKeys(properties) {
+		prop, ok := properties[name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		propPath := fldPath.Child("properties").Key(name)
+		if v, found := prop[Extension]; found {
+			immutable, ok := v.(bool)
+			switch {
+			case !ok:
+				allErrs = append(allErrs, field.Invalid(propPath.Child(Extension), v, "must be a boolean"))
+			case !immutable:
+			case !correlatable:
+				allErrs = append(allErrs, field.Forbidden(propPath.Child(Extension), "can only be set on properties of objects that are themselves properties, not below items or additionalProperties"))
+			case root && rootProperties.Has(name):
+				allErrs = append(allErrs, field.Forbidden(propPath.Child(Extension), fmt.Sprintf("cannot be set on %s", name)))
+			case !required.Has(name):
+				if _, ok := model.Escape(name); !ok {
+					allErrs = append(allErrs, field.Forbidden(propPath.Child(Extension), "cannot be set on optional properties whose name cannot be accessed in CEL"))
+				}
+			}
+		}
+		allErrs = append(allErrs, validate(prop, propPath, correlatable, false)...)
+	}
+
+	for _, key := range []string{"items", "additionalProperties", "not"} {
+		if sub, ok := schema[key].(map[string]interface{}); ok {
+			allErrs = append(allErrs, validate(sub, fldPath.Child(key), false, false)...)
+		}
+	}
+	for _, key := range []string{"allOf", "anyOf", "oneOf"} {
+		subs, _ := schema[key].([]interface{})
+		for i, s := range subs {
+			if sub, ok := s.(map[string]interface{}); ok {
+				allErrs = append(allErrs, validate(sub, fldPath.Child(key).Index(i), false, false)...)
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// Enforced returns whether markers are enforced. The transition rules they are replaced
+// with are only enforced with the CustomResourceValidationExpressions feature gate.
+func Enforced() bool {
+	return utilfeature.DefaultFeatureGate.Enabled(genericfeatures.CustomResourceValidationExpressions)
+}
+
+// HasMarkers returns whether the given JSON schema marks any property as immutable.
+func HasMarkers(raw []byte) bool {
+	var schema map[string]interface{}
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		return false
+	}
+	return hasMarkers(schema)
+}
+
+func hasMarkers(schema map[string]interface{}) bool {
+	properties, _ := schema["properties"].(map[string]interface{})
+	for _, v := range properties {
+		prop, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if immutable, _ := prop[Extension].(bool); immutable || hasMarkers(prop) {
+			return true
+		}
+	}
+	return false
+}
+
+// ToValidationRules replaces the markers in the given JSON schema with CEL transition rules.
+// A marked property gets the rule that its value does not change. Its parent gets the rule
+// that an optional property is neither added nor removed.
+func ToValidationRules(raw []byte) ([]byte, error) {
+	var schema map[string]interface{}
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		return nil, err
+	}
+	if !toValidationRules(schema) {
+		return raw, nil
+	}
+	return json.Marshal(schema)
+}
+
+// toValidationRules replaces the markers below the given schema, and returns whether
+// any were found.
+func toValidationRules(schema map[string]interface{}) bool {
+	changed := false
+
+	required := requiredProperties(schema)
+	properties, _ := schema["properties"].(map[string]interface{})
+	for _, name := range sortedKeys(properties) {
+		prop, ok := properties[name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if toValidationRules(prop) {
+			changed = true
+		}
+
+		v, found := prop[Extension]
+		if !found {
+			continue
+		}
+		delete(prop, Extension)
+		changed = true
+		if immutable, ok := v.(bool); !ok || !immutable {
+			continue
+		}
+
+		appendRule(prop, "self == oldSelf", "is immutable")
+		if required.Has(name) {
+			continue
+		}
+		if escaped, ok := model.Escape(name); ok {
+			appendRule(schema, fmt.Sprintf("has(self.%s) == has(oldSelf.%s)", escaped, escaped), fmt.Sprintf("%s is immutable", name))
+		}
+	}
+
+	return changed
+}
+
+func appendRule(schema map[string]interface{}, rule, message string) {
+	rules, _ := schema[validationsExtension].([]interface{})
+	schema[validationsExtension] = append(rules, map[string]interface{}{
+		"rule":    rule,
+		"message": message,
+	})
+}
+
+func requiredProperties(schema map[string]interface{}) sets.String {
+	required := sets.NewString()
+	names, _ := schema["required"].([]interface{})
+	for _, name := range names {
+		if s, ok := name.(string); ok {
+			required.Insert(s)
+		}
+	}
+	return required
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package immutablefields
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	genericfeatures "k8s.io/apiserver/pkg/features"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+)
+
+func TestValidate(t *testing.T) {
+	defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, genericfeatures.CustomResourceValidationExpressions, true)()
+
+	tests := map[string]struct {
+		schema  string
+		wantErr []string
+	}{
+		"no markers": {
+			schema: `{"type":"object","properties":{"spec":{"type":"object"}}}`,
+		},
+		"nested properties": {
+			schema: `{"type":"object","properties":{"spec":{"type":"object","required":["class"],"properties":{
+				"class":{"type":"string","x-kcp-immutable":true},
+				"storage":{"type":"object","x-kcp-immutable":true,"properties":{"size":{"type":"string","x-kcp-immutable":true}}},
+				"mutable":{"type":"string","x-kcp-immutable":false}
+			}}}}`,
+		},
+		"whole object": {
+			schema:  `{"type":"object","x-kcp-immutable":true}`,
+			wantErr: []string{`schema.x-kcp-immutable: Forbidden: cannot mark the whole object as immutable`},
+		},
+		"not a boolean": {
+			schema:  `{"type":"object","properties":{"spec":{"type":"object","x-kcp-immutable":"yes"}}}`,
+			wantErr: []string{`schema.properties[spec].x-kcp-immutable: Invalid value: "yes": must be a boolean`},
+		},
+		"metadata": {
+			schema:  `{"type":"object","properties":{"metadata":{"type":"object","x-kcp-immutable":true}}}`,
+			wantErr: []string{`schema.properties[metadata].x-kcp-immutable: Forbidden: cannot be set on metadata`},
+		},
+		"list items": {
+			schema: `{"type":"object","properties":{"spec":{"type":"object","properties":{"ports":{"type":"array","items":{"type":"object","properties":{
+				"port":{"type":"integer","x-kcp-immutable":true}
+			}}}}}}}`,
+			wantErr: []string{`schema.properties[spec].properties[ports].items.properties[port].x-kcp-immutable: Forbidden: can only be set on properties of objects that are themselves properties, not below items or additionalProperties`},
+		},
+		"additional properties": {
+			schema: `{"type":"object","properties":{"spec":{"type":"object","additionalProperties":{"type":"object","properties":{
+				"name":{"type":"string","x-kcp-immutable":true}
+			}}}}}`,
+			wantErr: []string{`schema.properties[spec].additionalProperties.properties[name].x-kcp-immutable: Forbidden: can only be set on properties of objects that are themselves properties, not below items or additionalProperties`},
+		},
+		"optional property not accessible in CEL": {
+			schema: `{"type":"object","properties":{"spec":{"type":"object","required":["required@name"],"properties":{
+				"optional@name":{"type":"string","x-kcp-immutable":true},
+				"required@name":{"type":"string","x-kcp-immutable":true}
+			}}}}`,
+			wantErr: []string{`schema.properties[spec].properties[optional@name].x-kcp-immutable: Forbidden: cannot be set on optional properties whose name cannot be accessed in CEL`},
+		},
+		"invalid JSON": {
+			schema:  `{`,
+			wantErr: []string{`schema: Invalid value: "{": invalid JSON: unexpected end of JSON input`},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			errs := Validate([]byte(tt.schema), field.NewPath("schema"))
+			var got []string
+			for _, err := range errs {
+				got = append(got, err.Error())
+			}
+			require.Equal(t, tt.wantErr, got)
+		})
+	}
+}
+
+func TestValidateWithoutValidationExpressions(t *testing.T) {
+	defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, genericfeatures.CustomResourceValidationExpressions, false)()
+
+	raw := []byte(`{"type":"object","properties":{"spec":{"type":"object","properties":{
+		"class":{"type":"string","x-kcp-immutable":true},
+		"mutable":{"type":"string","x-kcp-immutable":false}
+	}}}}`)
+	require.Empty(t, Validate(raw, field.NewPath("schema")), "markers are valid whether they are enforced or not")
+	require.False(t, Enforced())
+}
+
+func TestHasMarkers(t *testing.T) {
+	tests := map[string]struct {
+		schema string
+		want   bool
+	}{
+		"no markers":         {schema: `{"type":"object","properties":{"spec":{"type":"object"}}}`},
+		"only mutable":       {schema: `{"type":"object","properties":{"spec":{"type":"object","x-kcp-immutable":false}}}`},
+		"nested marker":      {schema: `{"type":"object","properties":{"spec":{"type":"object","properties":{"class":{"type":"string","x-kcp-immutable":true}}}}}`, want: true},
+		"invalid JSON":       {schema: `{`},
+		"property of spec":   {schema: `{"type":"object","properties":{"spec":{"type":"object","x-kcp-immutable":true}}}`, want: true},
+		"not a boolean":      {schema: `{"type":"object","properties":{"spec":{"type":"object","x-kcp-immutable":"yes"}}}`},
+		"marked and mutable": {schema: `{"type":"object","properties":{"a":{"x-kcp-immutable":false},"b":{"x-kcp-immutable":true}}}`, want: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tt.want, HasMarkers([]byte(tt.schema)))
+		})
+	}
+}
+
+func TestToValidationRules(t *testing.T) {
+	tests := map[string]struct {
+		schema string
+		want   string
+	}{
+		"no markers": {
+			schema: `{"type":"object","properties":{"spec":{"type":"object"}}}`,
+			want:   `{"type":"object","properties":{"spec":{"type":"object"}}}`,
+		},
+		"required property": {
+			schema: `{"type":"object","properties":{"spec":{"type":"object","required":["class"],"properties":{"class":{"type":"string","x-kcp-immutable":true}}}}}`,
+			want: `{"type":"object","properties":{"spec":{"type":"object","required":["class"],"properties":{"class":{"type":"string","x-kubernetes-validations":[
+				{"rule":"self == oldSelf","message":"is immutable"}
+			]}}}}}`,
+		},
+		"optional property": {
+			schema: `{"type":"object","properties":{"spec":{"type":"object","properties":{"storage-class":{"type":"string","x-kcp-immutable":true}}}}}`,
+			want: `{"type":"object","properties":{"spec":{"type":"object","properties":{"storage-class":{"type":"string","x-kubernetes-validations":[
+				{"rule":"self == oldSelf","message":"is immutable"}
+			]}},"x-kubernetes-validations":[
+				{"rule":"has(self.storage__dash__class) == has(oldSelf.storage__dash__class)","message":"storage-class is immutable"}
+			]}}}`,
+		},
+		"existing rules": {
+			schema: `{"type":"object","properties":{"spec":{"type":"object","required":["size"],"properties":{"size":{"type":"integer","x-kcp-immutable":true,"x-kubernetes-validations":[
+				{"rule":"self > 0","message":"must be positive"}
+			]}}}}}`,
+			want: `{"type":"object","properties":{"spec":{"type":"object","required":["size"],"properties":{"size":{"type":"integer","x-kubernetes-validations":[
+				{"rule":"self > 0","message":"must be positive"},
+				{"rule":"self == oldSelf","message":"is immutable"}
+			]}}}}}`,
+		},
+		"not immutable": {
+			schema: `{"type":"object","properties":{"spec":{"type":"object","x-kcp-immutable":false}}}`,
+			want:   `{"type":"object","properties":{"spec":{"type":"object"}}}`,
+		},
+		"nested properties": {
+			schema: `{"type":"object","properties":{"spec":{"type":"object","required":["storage"],"properties":{"storage":{"type":"object","x-kcp-immutable":true,"required":["size"],"properties":{
+				"size":{"type":"string","x-kcp-immutable":true}
+			}}}}}}`,
+			want: `{"type":"object","properties":{"spec":{"type":"object","required":["storage"],"properties":{"storage":{"type":"object","required":["size"],"properties":{
+				"size":{"type":"string","x-kubernetes-validations":[{"rule":"self == oldSelf","message":"is immutable"}]}
+			},"x-kubernetes-validations":[{"rule":"self == oldSelf","message":"is immutable"}]}}}}}`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ToValidationRules([]byte(tt.schema))
+			require.NoError(t, err)
+			require.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
@@ -0,0 +1,613 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package informer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clusters"
+	"k8s.io/klog/v2"
+)
+
+// ByLogicalClusterIndexName is the name of the index the sharded informers
+// maintain on the logical cluster name of their objects.
+const ByLogicalClusterIndexName = "kcp-logical-cluster"
+
+// IndexByLogicalCluster indexes objects by their logical cluster name.
+func IndexByLogicalCluster(obj interface{}) ([]string, error) {
+	metaObj, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+	return []string{logicalcluster.From(metaObj).String()}, nil
+}
+
+// ShardedDynamicSharedInformerFactory creates informers that watch a resource
+// in all logical clusters of all shards through wildcard requests, and merge
+// the per-shard caches into a single view. A single shard is enough when
+// watching a cache server that already aggregates all shards. Shards can be
+// added and removed at any time, e.g. when ClusterWorkspaceShards change.
+type ShardedDynamicSharedInformerFactory struct {
+	resyncPeriod time.Duration
+
+	lock      sync.Mutex
+	shards    map[string]dynamic.ClusterInterface
+	informers map[schema.GroupVersionResource]*ShardedInformer
+	started   map[schema.GroupVersionResource]bool
+}
+
+// NewShardedDynamicSharedInformerFactory returns a factory watching the given
+// shards, keyed by shard name.
+func NewShardedDynamicSharedInformerFactory(shards map[string]dynamic.ClusterInterface, resyncPeriod time.Duration) *ShardedDynamicSharedInformerFactory {
+	f := &ShardedDynamicSharedInformerFactory{
+		shards:       map[string]dynamic.ClusterInterface{},
+		resyncPeriod: resyncPeriod,
+		informers:    map[schema.GroupVersionResource]*ShardedInformer{},
+		started:      map[schema.GroupVersionResource]bool{},
+	}
+	for name, client := range shards {
+		f.shards[name] = client
+	}
+	return f
+}
+
+// NewShardedDynamicSharedInformerFactoryForConfigs builds a cluster-aware
+// dynamic client for each shard config, e.g. as returned by
+// sharding.ClientLoader.Clients.
+func NewShardedDynamicSharedInformerFactoryForConfigs(configs map[string]*rest.Config, resyncPeriod time.Duration) (*ShardedDynamicSharedInformerFactory, error) {
+	shards := make(map[string]dynamic.ClusterInterface, len(configs))
+	for name, cfg := range configs {
+		client, err := dynamic.NewClusterForConfig(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create dynamic client for shard %q: %w", name, err)
+		}
+		shards[name] = client
+	}
+	return NewShardedDynamicSharedInformerFactory(shards, resyncPeriod), nil
+}
+
+// AddShard adds a shard to all informers, replacing a shard of the same name.
+// Informers that are already running start watching the shard right away, and
+// report not synced until the shard has synced.
+func (f *ShardedDynamicSharedInformerFactory) AddShard(name string, client dynamic.ClusterInterface) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.shards[name] = client
+	for gvr, inf := range f.informers {
+		inf.addShard(name, f.newListWatch(client, gvr))
+	}
+}
+
+// RemoveShard stops watching the shard. Objects only known from that shard
+// are deleted from the merged view, duplicates on other shards take over.
+func (f *ShardedDynamicSharedInformerFactory) RemoveShard(name string) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	delete(f.shards, name)
+	for _, inf := range f.informers {
+		inf.removeShard(name)
+	}
+}
+
+// ForResource returns the ShardedInformer for gvr, creating it if needed.
+func (f *ShardedDynamicSharedInformerFactory) ForResource(gvr schema.GroupVersionResource) *ShardedInformer {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if inf, ok := f.informers[gvr]; ok {
+		return inf
+	}
+
+	inf := newShardedInformer(gvr, f.resyncPeriod)
+	for name, client := range f.shards {
+		inf.addShard(name, f.newListWatch(client, gvr))
+	}
+	f.informers[gvr] = inf
+
+	return inf
+}
+
+func (f *ShardedDynamicSharedInformerFactory) newListWatch(client dynamic.ClusterInterface, gvr schema.GroupVersionResource) cache.ListerWatcher {
+	resource := client.Cluster(logicalcluster.Wildcard).Resource(gvr)
+	return &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			return resource.List(context.TODO(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			return resource.Watch(context.TODO(), options)
+		},
+	}
+}
+
+// Start starts all informers requested so far that are not running yet.
+func (f *ShardedDynamicSharedInformerFactory) Start(stopCh <-chan struct{}) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for gvr, inf := range f.informers {
+		if !f.started[gvr] {
+			go inf.Run(stopCh)
+			f.started[gvr] = true
+		}
+	}
+}
+
+// WaitForCacheSync waits until all started informers have synced on every shard.
+func (f *ShardedDynamicSharedInformerFactory) WaitForCacheSync(stopCh <-chan struct{}) map[schema.GroupVersionResource]bool {
+	f.lock.Lock()
+	informers := map[schema.GroupVersionResource]*ShardedInformer{}
+	for gvr, inf := range f.informers {
+		if f.started[gvr] {
+			informers[gvr] = inf
+		}
+	}
+	f.lock.Unlock()
+
+	res := map[schema.GroupVersionResource]bool{}
+	for gvr, inf := range informers {
+		res[gvr] = cache.WaitForCacheSync(stopCh, inf.HasSynced)
+	}
+	return res
+}
+
+// ShardedInformer merges the wildcard informers of all shards for one
+// resource. Objects are deduplicated by logical cluster, namespace and name:
+// if the same object is seen on more than one shard, e.g. while a workspace
+// is being moved, the first shard it was seen on wins until that shard
+// drops it.
+//
+// The shard informers update the merged indexer synchronously while
+// processing their events, so once a shard has synced, the merged indexer
+// holds all objects of its initial list. Event handlers are called
+// asynchronously, each from its own goroutine, like for shared informers.
+type ShardedInformer struct {
+	gvr          schema.GroupVersionResource
+	resyncPeriod time.Duration
+
+	// indexer holds the merged view.
+	indexer cache.Indexer
+
+	lock sync.Mutex
+	// stopCh is set once the informer runs.
+	stopCh <-chan struct{}
+	shards map[string]*shardInformer
+	// owners maps an object key to the shard whose copy is in the indexer.
+	owners map[string]string
+	// copies holds the latest copy of each object, by key and shard.
+	copies    map[string]map[string]interface{}
+	listeners []*listener
+}
+
+type shardInformer struct {
+	controller cache.Controller
+	stopCh     chan struct{}
+}
+
+func newShardedInformer(gvr schema.GroupVersionResource, resyncPeriod time.Duration) *ShardedInformer {
+	return &ShardedInformer{
+		gvr:          gvr,
+		resyncPeriod: resyncPeriod,
+		indexer: cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{
+			cache.NamespaceIndex:      cache.MetaNamespaceIndexFunc,
+			ByLogicalClusterIndexName: IndexByLogicalCluster,
+		}),
+		shards: map[string]*shardInformer{},
+		owners: map[string]string{},
+		copies: map[string]map[string]interface{}{},
+	}
+}
+
+// addShard starts watching a shard, replacing a shard of the same name.
+func (i *ShardedInformer) addShard(name string, lw cache.ListerWatcher) {
+	i.removeShard(name)
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	shard := &shardInformer{stopCh: make(chan struct{})}
+	_, shard.controller = cache.NewInformer(lw, &unstructured.Unstructured{}, i.resyncPeriod, cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { i.upsert(shard, name, obj) },
+		UpdateFunc: func(_, obj interface{}) { i.upsert(shard, name, obj) },
+		DeleteFunc: func(obj interface{}) { i.delete(shard, name, obj) },
+	})
+	i.shards[name] = shard
+	if i.stopCh != nil {
+		i.runShard(shard)
+	}
+}
+
+// removeShard stops watching a shard and drops its objects.
+func (i *ShardedInformer) removeShard(name string) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	shard, ok := i.shards[name]
+	if !ok {
+		return
+	}
+	close(shard.stopCh)
+	delete(i.shards, name)
+
+	keys := make([]string, 0, len(i.copies))
+	for key, copies := range i.copies {
+		if _, ok := copies[name]; ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		i.deleteLocked(name, key)
+	}
+}
+
+// runShard runs the shard informer until the shard is removed or the sharded
+// informer stops. It must be called with i.lock held.
+func (i *ShardedInformer) runShard(shard *shardInformer) {
+	stopCh := make(chan struct{})
+	go func() {
+		defer close(stopCh)
+		select {
+		case <-i.stopCh:
+		case <-shard.stopCh:
+		}
+	}()
+	go shard.controller.Run(stopCh)
+}
+
+// Run runs the informers of all shards until stopCh is closed.
+func (i *ShardedInformer) Run(stopCh <-chan struct{}) {
+	i.lock.Lock()
+	if i.stopCh != nil {
+		i.lock.Unlock()
+		klog.Warningf("Sharded informer for %s already running", i.gvr)
+		return
+	}
+	i.stopCh = stopCh
+	for _, shard := range i.shards {
+		i.runShard(shard)
+	}
+	for _, l := range i.listeners {
+		go l.run(stopCh)
+	}
+	i.lock.Unlock()
+
+	<-stopCh
+}
+
+// HasSynced returns true once the informers of all shards have synced, and
+// with that the merged indexer contains the initial objects of all shards.
+func (i *ShardedInformer) HasSynced() bool {
+	i.lock.Lock()
+	controllers := make([]cache.Controller, 0, len(i.shards))
+	for _, shard := range i.shards {
+		controllers = append(controllers, shard.controller)
+	}
+	running := i.stopCh != nil
+	i.lock.Unlock()
+
+	if !running {
+		return false
+	}
+	// not under i.lock, the controllers hold their queue lock while calling
+	// upsert and delete, which take i.lock.
+	for _, c := range controllers {
+		if !c.HasSynced() {
+			return false
+		}
+	}
+	return true
+}
+
+// GetIndexer returns the merged indexer. Keys are cluster-aware, and the
+// ByLogicalClusterIndexName and cache.NamespaceIndex indexes are maintained.
+func (i *ShardedInformer) GetIndexer() cache.Indexer {
+	return i.indexer
+}
+
+// Lister returns a lister on the merged view.
+func (i *ShardedInformer) Lister() *ShardedLister {
+	return &ShardedLister{indexer: i.indexer, gvr: i.gvr}
+}
+
+// AddEventHandler registers a handler for events on the merged view. The
+// handler is first called with an add for every object already known.
+func (i *ShardedInformer) AddEventHandler(handler cache.ResourceEventHandler) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	l := newListener(handler)
+	for _, obj := range i.indexer.List() {
+		obj := obj
+		l.add(func(h cache.ResourceEventHandler) { h.OnAdd(obj) })
+	}
+	i.listeners = append(i.listeners, l)
+	if i.stopCh != nil {
+		go l.run(i.stopCh)
+	}
+}
+
+func (i *ShardedInformer) upsert(shard *shardInformer, name string, obj interface{}) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		klog.Errorf("Failed to get key for %s object: %v", i.gvr, err)
+		return
+	}
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	if i.shards[name] != shard {
+		// late event of a removed or replaced shard
+		return
+	}
+
+	if i.copies[key] == nil {
+		i.copies[key] = map[string]interface{}{}
+	}
+	i.copies[key][name] = obj
+
+	owner, found := i.owners[key]
+	if found && owner != name {
+		klog.V(4).Infof("Ignoring %s %s from shard %q, already served by shard %q", i.gvr, key, name, owner)
+		return
+	}
+	i.owners[key] = name
+
+	old, exists, _ := i.indexer.GetByKey(key)
+	if err := i.indexer.Update(obj); err != nil {
+		klog.Errorf("Failed to update %s %s: %v", i.gvr, key, err)
+		return
+	}
+	if exists {
+		i.notify(func(h cache.ResourceEventHandler) { h.OnUpdate(old, obj) })
+	} else {
+		i.notify(func(h cache.ResourceEventHandler) { h.OnAdd(obj) })
+	}
+}
+
+func (i *ShardedInformer) delete(shard *shardInformer, name string, obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		klog.Errorf("Failed to get key for %s object: %v", i.gvr, err)
+		return
+	}
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	if i.shards[name] != shard {
+		return
+	}
+	i.deleteLocked(name, key)
+}
+
+func (i *ShardedInformer) deleteLocked(shard, key string) {
+	delete(i.copies[key], shard)
+	if i.owners[key] != shard {
+		return
+	}
+
+	// hand over to another shard still serving the object, if any
+	if len(i.copies[key]) > 0 {
+		candidates := make([]string, 0, len(i.copies[key]))
+		for name := range i.copies[key] {
+			candidates = append(candidates, name)
+		}
+		sort.Strings(candidates)
+		next := i.copies[key][candidates[0]]
+
+		old, _, _ := i.indexer.GetByKey(key)
+		if err := i.indexer.Update(next); err != nil {
+			klog.Errorf("Failed to update %s %s: %v", i.gvr, key, err)
+			return
+		}
+		i.owners[key] = candidates[0]
+		i.notify(func(h cache.ResourceEventHandler) { h.OnUpdate(old, next) })
+		return
+	}
+
+	delete(i.owners, key)
+	delete(i.copies, key)
+	old, exists, _ := i.indexer.GetByKey(key)
+	if !exists {
+		return
+	}
+	if err := i.indexer.Delete(old); err != nil {
+		klog.Errorf("Failed to delete %s %s: %v", i.gvr, key, err)
+		return
+	}
+	i.notify(func(h cache.ResourceEventHandler) { h.OnDelete(old) })
+}
+
+// notify queues a notification for all handlers. It must be called with
+// i.lock held, which keeps the notifications in the order of the indexer
+// updates.
+func (i *ShardedInformer) notify(n func(h cache.ResourceEventHandler)) {
+	for _, l := range i.listeners {
+		l.add(n)
+	}
+}
+
+// listener delivers notifications to one handler from its own goroutine,
+// buffering without bound like the listeners of client-go shared informers,
+// such that slow handlers block neither the shards nor other handlers.
+type listener struct {
+	handler cache.ResourceEventHandler
+
+	lock    sync.Mutex
+	cond    *sync.Cond
+	pending []func(h cache.ResourceEventHandler)
+	stopped bool
+}
+
+func newListener(handler cache.ResourceEventHandler) *listener {
+	l := &listener{handler: handler}
+	l.cond = sync.NewCond(&l.lock)
+	return l
+}
+
+func (l *listener) add(n func(h cache.ResourceEventHandler)) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.pending = append(l.pending, n)
+	l.cond.Signal()
+}
+
+func (l *listener) run(stopCh <-chan struct{}) {
+	go func() {
+		<-stopCh
+		l.lock.Lock()
+		defer l.lock.Unlock()
+		l.stopped = true
+		l.cond.Signal()
+	}()
+
+	for {
+		l.lock.Lock()
+		for len(l.pending) == 0 && !l.stopped {
+			l.cond.Wait()
+		}
+		if l.stopped {
+			l.lock.Unlock()
+			return
+		}
+		n := l.pending[0]
+		l.pending[0] = nil
+		l.pending = l.pending[1:]
+		l.lock.Unlock()
+
+		func() {
+			defer utilruntime.HandleCrash()
+			n(l.handler)
+		}()
+	}
+}
+
+// ShardedLister lists objects of the merged view across all logical clusters.
+type ShardedLister struct {
+	indexer cache.Indexer
+	gvr     schema.GroupVersionResource
+}
+
+// List lists all objects in all logical clusters.
+func (l *ShardedLister) List(selector labels.Selector) (ret []*unstructured.Unstructured, err error) {
+	err = cache.ListAll(l.indexer, selector, func(obj interface{}) {
+		ret = append(ret, obj.(*unstructured.Unstructured))
+	})
+	return ret, err
+}
+
+// Cluster scopes the lister to one logical cluster.
+func (l *ShardedLister) Cluster(cluster logicalcluster.Name) *ClusterLister {
+	return &ClusterLister{indexer: l.indexer, gvr: l.gvr, cluster: cluster}
+}
+
+// ClusterLister lists objects of one logical cluster.
+type ClusterLister struct {
+	indexer cache.Indexer
+	gvr     schema.GroupVersionResource
+	cluster logicalcluster.Name
+}
+
+// List lists all objects in the logical cluster.
+func (l *ClusterLister) List(selector labels.Selector) ([]*unstructured.Unstructured, error) {
+	return l.list(metav1.NamespaceAll, selector)
+}
+
+// Get returns a cluster-scoped object of the logical cluster by name.
+func (l *ClusterLister) Get(name string) (*unstructured.Unstructured, error) {
+	return l.get(metav1.NamespaceNone, name)
+}
+
+// Namespace scopes the lister to one namespace of the logical cluster.
+func (l *ClusterLister) Namespace(namespace string) *NamespaceLister {
+	return &NamespaceLister{parent: l, namespace: namespace}
+}
+
+func (l *ClusterLister) list(namespace string, selector labels.Selector) ([]*unstructured.Unstructured, error) {
+	objs, err := l.indexer.ByIndex(ByLogicalClusterIndexName, l.cluster.String())
+	if err != nil {
+		return nil, err
+	}
+	var ret []*unstructured.Unstructured
+	for _, obj := range objs {
+		u := obj.(*unstructured.Unstructured)
+		if namespace != metav1.NamespaceAll && u.GetNamespace() != namespace {
+			continue
+		}
+		if selector.Matches(labels.Set(u.GetLabels())) {
+			ret = append(ret, u)
+		}
+	}
+	return ret, nil
+}
+
+func (l *ClusterLister) get(namespace, name string) (*unstructured.Unstructured, error) {
+	key := clusterAwareKey(l.cluster, namespace, name)
+	obj, exists, err := l.indexer.GetByKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, apierrors.NewNotFound(l.gvr.GroupResource(), name)
+	}
+	return obj.(*unstructured.Unstructured), nil
+}
+
+// NamespaceLister lists objects of one namespace of a logical cluster.
+type NamespaceLister struct {
+	parent    *ClusterLister
+	namespace string
+}
+
+// List lists all objects in the namespace.
+func (l *NamespaceLister) List(selector labels.Selector) ([]*unstructured.Unstructured, error) {
+	return l.parent.list(l.namespace, selector)
+}
+
+// Get returns an object of the namespace by name.
+func (l *NamespaceLister) Get(name string) (*unstructured.Unstructured, error) {
+	return l.parent.get(l.namespace, name)
+}
+
+func clusterAwareKey(cluster logicalcluster.Name, namespace, name string) string {
+	key := clusters.ToClusterAwareKey(cluster, name)
+	if namespace != "" {
+		return namespace + "/" + key
+	}
+	return key
+}
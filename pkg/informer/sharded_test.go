@@ -0,0 +1,189 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package informer
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+func newObj(cluster, namespace, name, value string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion("v1")
+	u.SetKind("ConfigMap")
+	u.SetClusterName(cluster)
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	u.SetLabels(map[string]string{"value": value})
+	return u
+}
+
+type recordingHandler struct {
+	lock   sync.Mutex
+	events []string
+}
+
+func (h *recordingHandler) record(event string, obj interface{}) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.events = append(h.events, event+":"+obj.(*unstructured.Unstructured).GetLabels()["value"])
+}
+
+func (h *recordingHandler) OnAdd(obj interface{})       { h.record("add", obj) }
+func (h *recordingHandler) OnUpdate(_, obj interface{}) { h.record("update", obj) }
+func (h *recordingHandler) OnDelete(obj interface{})    { h.record("delete", obj) }
+
+func (h *recordingHandler) Events() []string {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return append([]string(nil), h.events...)
+}
+
+// idleController is a shard informer fed by the test instead of a watch.
+type idleController struct{}
+
+func (idleController) Run(stopCh <-chan struct{})      { <-stopCh }
+func (idleController) HasSynced() bool                 { return true }
+func (idleController) LastSyncResourceVersion() string { return "" }
+
+func TestShardedInformerMerge(t *testing.T) {
+	inf := newShardedInformer(schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, 0)
+	alpha, beta := &shardInformer{controller: idleController{}}, &shardInformer{controller: idleController{}}
+	inf.shards = map[string]*shardInformer{"alpha": alpha, "beta": beta}
+	h := &recordingHandler{}
+	inf.AddEventHandler(h)
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go inf.Run(stopCh)
+
+	inf.upsert(alpha, "alpha", newObj("root:org:a", "default", "cm", "a1"))
+	inf.upsert(beta, "beta", newObj("root:org:b", "default", "cm", "b1"))
+	inf.upsert(beta, "beta", newObj("root:org:a", "default", "cm", "a-dup"))
+	inf.upsert(alpha, "alpha", newObj("root:org:a", "default", "cm", "a2"))
+	inf.upsert(&shardInformer{}, "alpha", newObj("root:org:c", "default", "cm", "stale"))
+
+	lister := inf.Lister()
+	all, err := lister.List(labels.Everything())
+	require.NoError(t, err)
+	require.Len(t, all, 2, "objects should be deduplicated across shards, and events of replaced shards ignored")
+
+	obj, err := lister.Cluster(logicalcluster.New("root:org:a")).Namespace("default").Get("cm")
+	require.NoError(t, err)
+	require.Equal(t, "a2", obj.GetLabels()["value"], "first shard should win")
+
+	list, err := lister.Cluster(logicalcluster.New("root:org:b")).List(labels.Everything())
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, "b1", list[0].GetLabels()["value"])
+
+	list, err = lister.Cluster(logicalcluster.New("root:org:b")).Namespace("other").List(labels.Everything())
+	require.NoError(t, err)
+	require.Empty(t, list)
+
+	// the owning shard drops the object, the duplicate takes over
+	inf.delete(alpha, "alpha", newObj("root:org:a", "default", "cm", "a2"))
+	obj, err = lister.Cluster(logicalcluster.New("root:org:a")).Namespace("default").Get("cm")
+	require.NoError(t, err)
+	require.Equal(t, "a-dup", obj.GetLabels()["value"])
+
+	inf.delete(beta, "beta", cache.DeletedFinalStateUnknown{
+		Key: clusterAwareKey(logicalcluster.New("root:org:a"), "default", "cm"),
+		Obj: newObj("root:org:a", "default", "cm", "a-dup"),
+	})
+	_, err = lister.Cluster(logicalcluster.New("root:org:a")).Namespace("default").Get("cm")
+	require.True(t, apierrors.IsNotFound(err), "expected not found, got %v", err)
+
+	expected := []string{"add:a1", "add:b1", "update:a2", "update:a-dup", "delete:a-dup"}
+	require.Eventually(t, func() bool { return reflect.DeepEqual(expected, h.Events()) }, wait.ForeverTestTimeout, 10*time.Millisecond, "got %v", h.Events())
+
+	late := &recordingHandler{}
+	inf.AddEventHandler(late)
+	require.Eventually(t, func() bool { return reflect.DeepEqual([]string{"add:b1"}, late.Events()) }, wait.ForeverTestTimeout, 10*time.Millisecond, "got %v", late.Events())
+}
+
+// fakeClusterClient ignores the logical cluster.
+type fakeClusterClient struct {
+	dynamic.Interface
+}
+
+func (c fakeClusterClient) Cluster(logicalcluster.Name) dynamic.Interface {
+	return c.Interface
+}
+
+func newFakeShard(objs ...runtime.Object) dynamic.ClusterInterface {
+	gvr := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	return fakeClusterClient{dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{gvr: "ConfigMapList"}, objs...)}
+}
+
+func TestShardedDynamicSharedInformerFactory(t *testing.T) {
+	gvr := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	f := NewShardedDynamicSharedInformerFactory(map[string]dynamic.ClusterInterface{
+		"alpha": newFakeShard(newObj("root:org:a", "default", "a", "a"), newObj("root:org:d", "default", "dup", "dup-alpha")),
+		"beta":  newFakeShard(newObj("root:org:b", "default", "b", "b"), newObj("root:org:d", "default", "dup", "dup-beta")),
+	}, 0)
+	inf := f.ForResource(gvr)
+	lister := inf.Lister()
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	require.False(t, inf.HasSynced(), "not synced before running")
+	f.Start(stopCh)
+	require.Equal(t, map[schema.GroupVersionResource]bool{gvr: true}, f.WaitForCacheSync(stopCh))
+
+	t.Log("Once synced, the merged view holds the initial objects of all shards")
+	all, err := lister.List(labels.Everything())
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+
+	t.Log("Adding a shard to a running informer")
+	f.AddShard("gamma", newFakeShard(newObj("root:org:c", "default", "c", "c")))
+	require.True(t, cache.WaitForCacheSync(stopCh, inf.HasSynced))
+	_, err = lister.Cluster(logicalcluster.New("root:org:c")).Namespace("default").Get("c")
+	require.NoError(t, err)
+
+	t.Log("Removing a shard drops its objects, duplicates on other shards take over")
+	dup, err := lister.Cluster(logicalcluster.New("root:org:d")).Namespace("default").Get("dup")
+	require.NoError(t, err)
+	owner := dup.GetLabels()["value"]
+	removed, other := "alpha", "dup-beta"
+	if owner == "dup-beta" {
+		removed, other = "beta", "dup-alpha"
+	}
+	f.RemoveShard(removed)
+	dup, err = lister.Cluster(logicalcluster.New("root:org:d")).Namespace("default").Get("dup")
+	require.NoError(t, err)
+	require.Equal(t, other, dup.GetLabels()["value"])
+	all, err = lister.List(labels.Everything())
+	require.NoError(t, err)
+	require.Len(t, all, 3, "objects of the removed shard should be gone")
+}
@@ -0,0 +1,210 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package leaderelection elects leaders per logical cluster, for controllers running in several
+// instances. Of all instances, one reconciles the objects of a logical cluster at a time, and the
+// logical clusters are spread across the instances.
+package leaderelection
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	coordinationv1client "k8s.io/client-go/kubernetes/typed/coordination/v1"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/klog/v2"
+)
+
+// GarbageCollectLabel is set on the leases of elections. Leases with the label that expired
+// longer ago than the garbage collection TTL are deleted, e.g. when all instances of a controller
+// are gone.
+const GarbageCollectLabel = "coordination.kcp.dev/garbage-collect"
+
+// Config configures the elections of an Elector.
+type Config struct {
+	// Namespace is the namespace of the leases in every logical cluster. It must exist.
+	Namespace string
+	// Name is the name of the leases, usually the name of the controller.
+	Name string
+	// Identity identifies the instance, e.g. by host name and a random suffix.
+	Identity string
+
+	// LeaseDuration, RenewDeadline and RetryPeriod are as for client-go leader election.
+	LeaseDuration time.Duration
+	RenewDeadline time.Duration
+	RetryPeriod   time.Duration
+
+	// OnStartedLeading is called when the instance becomes the leader of the logical cluster.
+	// The context is cancelled when it stops leading.
+	OnStartedLeading func(ctx context.Context, clusterName logicalcluster.Name)
+	// OnStoppedLeading is called when the instance stops leading the logical cluster.
+	OnStoppedLeading func(clusterName logicalcluster.Name)
+}
+
+// Elector runs a leader election per logical cluster, with a lease in each logical cluster.
+type Elector struct {
+	client kubernetes.ClusterInterface
+	config Config
+
+	lock      sync.Mutex
+	elections map[logicalcluster.Name]*election
+}
+
+type election struct {
+	elector *leaderelection.LeaderElector
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// New returns an Elector, defaulting the namespace to "default" and the durations to those of
+// kube-controller-manager.
+func New(client kubernetes.ClusterInterface, config Config) (*Elector, error) {
+	if config.Namespace == "" {
+		config.Namespace = metav1.NamespaceDefault
+	}
+	if config.LeaseDuration == 0 {
+		config.LeaseDuration = 15 * time.Second
+	}
+	if config.RenewDeadline == 0 {
+		config.RenewDeadline = 10 * time.Second
+	}
+	if config.RetryPeriod == 0 {
+		config.RetryPeriod = 2 * time.Second
+	}
+	if config.Name == "" {
+		return nil, fmt.Errorf("lease name must be set")
+	}
+	if config.Identity == "" {
+		return nil, fmt.Errorf("identity must be set")
+	}
+
+	return &Elector{
+		client:    client,
+		config:    config,
+		elections: map[logicalcluster.Name]*election{},
+	}, nil
+}
+
+// Ensure runs the election of the logical cluster until Stop is called for it or ctx is done.
+// It is a no-op if the election runs already.
+func (e *Elector) Ensure(ctx context.Context, clusterName logicalcluster.Name) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if _, ok := e.elections[clusterName]; ok {
+		return nil
+	}
+
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock: &resourcelock.LeaseLock{
+			LeaseMeta: metav1.ObjectMeta{Namespace: e.config.Namespace, Name: e.config.Name},
+			Client:    &labelingLeasesGetter{delegate: e.client.Cluster(clusterName).CoordinationV1()},
+			LockConfig: resourcelock.ResourceLockConfig{
+				Identity: e.config.Identity,
+			},
+		},
+		LeaseDuration:   e.config.LeaseDuration,
+		RenewDeadline:   e.config.RenewDeadline,
+		RetryPeriod:     e.config.RetryPeriod,
+		ReleaseOnCancel: true,
+		Name:            fmt.Sprintf("%s|%s", clusterName, e.config.Name),
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				klog.V(2).Infof("%s started leading %s|%s/%s", e.config.Identity, clusterName, e.config.Namespace, e.config.Name)
+				if e.config.OnStartedLeading != nil {
+					e.config.OnStartedLeading(ctx, clusterName)
+				}
+			},
+			OnStoppedLeading: func() {
+				klog.V(2).Infof("%s stopped leading %s|%s/%s", e.config.Identity, clusterName, e.config.Namespace, e.config.Name)
+				if e.config.OnStoppedLeading != nil {
+					e.config.OnStoppedLeading(clusterName)
+				}
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	el := &election{elector: elector, cancel: cancel, done: make(chan struct{})}
+	e.elections[clusterName] = el
+	go func() {
+		defer close(el.done)
+		// Run returns when leadership is lost, the instance campaigns again until stopped
+		for ctx.Err() == nil {
+			elector.Run(ctx)
+		}
+	}()
+
+	return nil
+}
+
+// Stop stops the election of the logical cluster, releasing the lease if the instance is the
+// leader, e.g. when the logical cluster is gone. It waits until the election has ended.
+func (e *Elector) Stop(clusterName logicalcluster.Name) {
+	e.lock.Lock()
+	el, ok := e.elections[clusterName]
+	delete(e.elections, clusterName)
+	e.lock.Unlock()
+
+	if !ok {
+		return
+	}
+	el.cancel()
+	<-el.done
+}
+
+// IsLeader returns whether the instance is the leader of the logical cluster. Controllers
+// skip the objects of logical clusters they do not lead.
+func (e *Elector) IsLeader(clusterName logicalcluster.Name) bool {
+	e.lock.Lock()
+	el, ok := e.elections[clusterName]
+	e.lock.Unlock()
+
+	return ok && el.elector.IsLeader()
+}
+
+// labelingLeasesGetter sets the GarbageCollectLabel on the leases created by elections.
+type labelingLeasesGetter struct {
+	delegate coordinationv1client.LeasesGetter
+}
+
+func (g *labelingLeasesGetter) Leases(namespace string) coordinationv1client.LeaseInterface {
+	return &labelingLeases{LeaseInterface: g.delegate.Leases(namespace)}
+}
+
+type labelingLeases struct {
+	coordinationv1client.LeaseInterface
+}
+
+func (l *labelingLeases) Create(ctx context.Context, lease *coordinationv1.Lease, opts metav1.CreateOptions) (*coordinationv1.Lease, error) {
+	lease = lease.DeepCopy()
+	if lease.Labels == nil {
+		lease.Labels = map[string]string{}
+	}
+	lease.Labels[GarbageCollectLabel] = "true"
+	return l.LeaseInterface.Create(ctx, lease, opts)
+}
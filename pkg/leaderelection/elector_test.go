@@ -0,0 +1,55 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package leaderelection
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNew(t *testing.T) {
+	e, err := New(nil, Config{Name: "controller", Identity: "instance-1"})
+	require.NoError(t, err)
+	require.Equal(t, "default", e.config.Namespace)
+	require.Equal(t, 15*time.Second, e.config.LeaseDuration)
+
+	_, err = New(nil, Config{Identity: "instance-1"})
+	require.Error(t, err, "lease name is required")
+	_, err = New(nil, Config{Name: "controller"})
+	require.Error(t, err, "identity is required")
+}
+
+func TestLabelingLeasesGetter(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	getter := &labelingLeasesGetter{delegate: client.CoordinationV1()}
+
+	lease := &coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{Name: "controller", Namespace: "default"}}
+	_, err := getter.Leases("default").Create(context.Background(), lease, metav1.CreateOptions{})
+	require.NoError(t, err)
+	require.Nil(t, lease.Labels, "the lease of the caller is not mutated")
+
+	created, err := client.CoordinationV1().Leases("default").Get(context.Background(), "controller", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{GarbageCollectLabel: "true"}, created.Labels)
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metering
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+// CSVExporter appends records to a CSV file per day in a directory.
+type CSVExporter struct {
+	Directory string
+}
+
+var csvHeader = []string{"workspace", "start", "end", "requests", "objects", "synced_resources"}
+
+// Export implements Exporter.
+func (e *CSVExporter) Export(_ context.Context, records []Record) error {
+	byFile := map[string][]Record{}
+	var files []string
+	for _, r := range records {
+		name := filepath.Join(e.Directory, "usage-"+r.Start.UTC().Format("2006-01-02")+".csv")
+		if _, ok := byFile[name]; !ok {
+			files = append(files, name)
+		}
+		byFile[name] = append(byFile[name], r)
+	}
+
+	for _, name := range files {
+		if err := appendCSV(name, byFile[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func appendCSV(name string, records []Record) error {
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	w := csv.NewWriter(f)
+	if info.Size() == 0 {
+		if err := w.Write(csvHeader); err != nil {
+			return err
+		}
+	}
+	for _, r := range records {
+		if err := w.Write([]string{
+			r.Workspace,
+			r.Start.UTC().Format(time.RFC3339),
+			r.End.UTC().Format(time.RFC3339),
+			strconv.FormatInt(r.Requests, 10),
+			strconv.FormatInt(r.Objects, 10),
+			strconv.FormatInt(r.SyncedResources, 10),
+		}); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// RemoteExporter posts records as JSON to a URL:
+//
+//	{"records": [{"workspace": "root:org:ws", "start": "...", ...}]}
+type RemoteExporter struct {
+	URL    string
+	Client *http.Client
+}
+
+// Export implements Exporter.
+func (e *RemoteExporter) Export(ctx context.Context, records []Record) error {
+	bs, err := json.Marshal(struct {
+		Records []Record `json:"records"`
+	}{records})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.URL, bytes.NewReader(bs))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := e.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s from %s", resp.Status, e.URL)
+	}
+	return nil
+}
+
+var (
+	workspaceRequests = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Namespace:      "kcp",
+			Subsystem:      "metering",
+			Name:           "workspace_requests",
+			Help:           "Number of API requests served for a workspace in the last finished metering period.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"workspace"},
+	)
+	workspaceObjects = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Namespace:      "kcp",
+			Subsystem:      "metering",
+			Name:           "workspace_objects",
+			Help:           "Highest number of objects of a workspace sampled in the last finished metering period.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"workspace"},
+	)
+	workspaceSyncedResources = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Namespace:      "kcp",
+			Subsystem:      "metering",
+			Name:           "workspace_synced_resources",
+			Help:           "Highest number of objects of a workspace synced to workload clusters sampled in the last finished metering period.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"workspace"},
+	)
+
+	registerMetrics sync.Once
+)
+
+// PrometheusExporter exposes the records of the last finished period as metrics,
+// labeled by workspace.
+type PrometheusExporter struct{}
+
+// NewPrometheusExporter registers the metering metrics and returns a PrometheusExporter.
+func NewPrometheusExporter() *PrometheusExporter {
+	registerMetrics.Do(func() {
+		legacyregistry.MustRegister(workspaceRequests)
+		legacyregistry.MustRegister(workspaceObjects)
+		legacyregistry.MustRegister(workspaceSyncedResources)
+	})
+	return &PrometheusExporter{}
+}
+
+// Export implements Exporter.
+func (e *PrometheusExporter) Export(_ context.Context, records []Record) error {
+	// only the latest period is exposed, without workspaces gone since
+	var latest time.Time
+	for _, r := range records {
+		if r.End.After(latest) {
+			latest = r.End
+		}
+	}
+
+	workspaceRequests.Reset()
+	workspaceObjects.Reset()
+	workspaceSyncedResources.Reset()
+	for _, r := range records {
+		if !r.End.Equal(latest) {
+			continue
+		}
+		workspaceRequests.WithLabelValues(r.Workspace).Set(float64(r.Requests))
+		workspaceObjects.WithLabelValues(r.Workspace).Set(float64(r.Objects))
+		workspaceSyncedResources.WithLabelValues(r.Workspace).Set(float64(r.SyncedResources))
+	}
+	return nil
+}
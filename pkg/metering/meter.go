@@ -0,0 +1,222 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metering
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/klog/v2"
+)
+
+// Period is the period usage is aggregated over.
+const Period = time.Hour
+
+// maxPendingRecords is the number of records kept per exporter while exporting fails.
+// Beyond that, the oldest records are dropped.
+const maxPendingRecords = 100000
+
+// Record is the usage of a workspace in a period.
+type Record struct {
+	// Workspace is the logical cluster of the workspace.
+	Workspace string `json:"workspace"`
+	// Start is the beginning of the period, inclusive.
+	Start time.Time `json:"start"`
+	// End is the end of the period, exclusive.
+	End time.Time `json:"end"`
+	// Requests is the number of API requests served for the workspace.
+	Requests int64 `json:"requests"`
+	// Objects is the highest number of objects sampled.
+	Objects int64 `json:"objects"`
+	// SyncedResources is the highest number of objects sampled that are synced to
+	// workload clusters.
+	SyncedResources int64 `json:"syncedResources"`
+}
+
+// Sample is the sampled usage of a workspace at a point in time.
+type Sample struct {
+	Objects         int64
+	SyncedResources int64
+}
+
+// Sampler returns the current usage of all workspaces.
+type Sampler func() (map[logicalcluster.Name]Sample, error)
+
+// Exporter exports usage records, e.g. for chargeback.
+type Exporter interface {
+	Export(ctx context.Context, records []Record) error
+}
+
+// Meter aggregates the usage of workspaces into records per Period, and
+// hands them to exporters when the period is over.
+type Meter struct {
+	now func() time.Time
+
+	lock    sync.Mutex
+	start   time.Time
+	records map[logicalcluster.Name]*Record
+	done    []Record
+
+	exportLock sync.Mutex
+	exporters  []*exporterQueue
+}
+
+type exporterQueue struct {
+	Exporter
+	pending []Record
+}
+
+// NewMeter returns a Meter exporting to the given exporters.
+func NewMeter(exporters ...Exporter) *Meter {
+	m := &Meter{
+		now:     time.Now,
+		records: map[logicalcluster.Name]*Record{},
+	}
+	m.start = m.now()
+	for _, e := range exporters {
+		m.exporters = append(m.exporters, &exporterQueue{Exporter: e})
+	}
+	return m
+}
+
+// WithRequestCounting counts the requests served by handler per logical cluster.
+// It must run after the cluster of the request is known.
+func (m *Meter) WithRequestCounting(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if cluster := apirequest.ClusterFrom(req.Context()); cluster != nil && !cluster.Name.Empty() && !cluster.Wildcard {
+			m.lock.Lock()
+			m.rotateLocked()
+			m.recordLocked(cluster.Name).Requests++
+			m.lock.Unlock()
+		}
+		handler.ServeHTTP(w, req)
+	})
+}
+
+// Run samples the usage every interval and exports the records of finished periods
+// until ctx is done. Then the records of the current period are exported up to now.
+func (m *Meter) Run(ctx context.Context, sample Sampler, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			m.lock.Lock()
+			m.closeLocked(m.now())
+			m.lock.Unlock()
+
+			// the context is gone, but the exporters should get a chance to finish
+			exportCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			m.export(exportCtx)
+			cancel()
+			return
+		case <-ticker.C:
+			samples, err := sample()
+			if err != nil {
+				klog.Errorf("failed to sample workspace usage: %v", err)
+			} else {
+				m.record(samples)
+			}
+			m.export(ctx)
+		}
+	}
+}
+
+// record adds the samples to the records of the current period.
+func (m *Meter) record(samples map[logicalcluster.Name]Sample) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.rotateLocked()
+	for cluster, s := range samples {
+		r := m.recordLocked(cluster)
+		if s.Objects > r.Objects {
+			r.Objects = s.Objects
+		}
+		if s.SyncedResources > r.SyncedResources {
+			r.SyncedResources = s.SyncedResources
+		}
+	}
+}
+
+func (m *Meter) recordLocked(cluster logicalcluster.Name) *Record {
+	r, ok := m.records[cluster]
+	if !ok {
+		r = &Record{Workspace: cluster.String(), Start: m.start}
+		m.records[cluster] = r
+	}
+	return r
+}
+
+// rotateLocked closes the current period if it is over.
+func (m *Meter) rotateLocked() {
+	end := m.start.Truncate(Period).Add(Period)
+	if m.now().Before(end) {
+		return
+	}
+	m.closeLocked(end)
+	m.start = m.now().Truncate(Period)
+}
+
+// closeLocked moves the records of the current period, ending at end, to the done records.
+func (m *Meter) closeLocked(end time.Time) {
+	records := make([]Record, 0, len(m.records))
+	for _, r := range m.records {
+		r.End = end
+		records = append(records, *r)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Workspace < records[j].Workspace })
+
+	m.done = append(m.done, records...)
+	m.records = map[logicalcluster.Name]*Record{}
+	m.start = end
+}
+
+// export hands the done records to all exporters. Records failing to be exported
+// are retried on the next call.
+func (m *Meter) export(ctx context.Context) {
+	m.lock.Lock()
+	done := m.done
+	m.done = nil
+	m.lock.Unlock()
+
+	m.exportLock.Lock()
+	defer m.exportLock.Unlock()
+
+	for _, e := range m.exporters {
+		e.pending = append(e.pending, done...)
+		if len(e.pending) == 0 {
+			continue
+		}
+		if len(e.pending) > maxPendingRecords {
+			klog.Errorf("dropping %d usage records failed to be exported to %T", len(e.pending)-maxPendingRecords, e.Exporter)
+			e.pending = e.pending[len(e.pending)-maxPendingRecords:]
+		}
+		if err := e.Export(ctx, e.pending); err != nil {
+			klog.Errorf("failed to export %d usage records to %T: %v", len(e.pending), e.Exporter, err)
+			continue
+		}
+		e.pending = nil
+	}
+}
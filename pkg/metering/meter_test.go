@@ -0,0 +1,149 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metering
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/tools/cache"
+)
+
+type fakeExporter struct {
+	err     error
+	records [][]Record
+}
+
+func (e *fakeExporter) Export(_ context.Context, records []Record) error {
+	if e.err != nil {
+		return e.err
+	}
+	e.records = append(e.records, records)
+	return nil
+}
+
+func TestMeter(t *testing.T) {
+	start := time.Date(2022, 6, 1, 10, 30, 0, 0, time.UTC)
+	now := start
+	e := &fakeExporter{}
+	m := NewMeter(e)
+	m.now = func() time.Time { return now }
+	m.start = now
+
+	request := func(cluster string) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req = req.WithContext(apirequest.WithCluster(req.Context(), apirequest.Cluster{Name: logicalcluster.New(cluster)}))
+		m.WithRequestCounting(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})).ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	request("root:org:a")
+	request("root:org:a")
+	m.record(map[logicalcluster.Name]Sample{
+		logicalcluster.New("root:org:a"): {Objects: 10, SyncedResources: 2},
+		logicalcluster.New("root:org:b"): {Objects: 3},
+	})
+	now = now.Add(20 * time.Minute)
+	m.record(map[logicalcluster.Name]Sample{
+		logicalcluster.New("root:org:a"): {Objects: 5, SyncedResources: 4},
+	})
+	m.export(context.Background())
+	require.Empty(t, e.records, "nothing to export before the end of the period")
+
+	now = time.Date(2022, 6, 1, 11, 5, 0, 0, time.UTC)
+	request("root:org:b")
+	m.export(context.Background())
+	require.Equal(t, [][]Record{{
+		{Workspace: "root:org:a", Start: start, End: time.Date(2022, 6, 1, 11, 0, 0, 0, time.UTC), Requests: 2, Objects: 10, SyncedResources: 4},
+		{Workspace: "root:org:b", Start: start, End: time.Date(2022, 6, 1, 11, 0, 0, 0, time.UTC), Objects: 3},
+	}}, e.records)
+
+	// failing exports are retried
+	e.err = errors.New("unavailable")
+	now = time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	m.record(nil)
+	m.export(context.Background())
+	e.err = nil
+	m.export(context.Background())
+	require.Len(t, e.records, 2)
+	require.Equal(t, []Record{
+		{Workspace: "root:org:b", Start: time.Date(2022, 6, 1, 11, 0, 0, 0, time.UTC), End: now, Requests: 1},
+	}, e.records[1])
+}
+
+func TestWithRequestCountingSkipsWildcards(t *testing.T) {
+	m := NewMeter()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req = req.WithContext(apirequest.WithCluster(req.Context(), apirequest.Cluster{Name: logicalcluster.Wildcard, Wildcard: true}))
+	m.WithRequestCounting(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})).ServeHTTP(httptest.NewRecorder(), req)
+	require.Empty(t, m.records)
+}
+
+func TestSampleObjects(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, obj := range []*metav1.PartialObjectMetadata{
+		{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "default", ClusterName: "root:org:a"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "default", ClusterName: "root:org:a", Labels: map[string]string{"state.internal.workloads.kcp.dev/us-east1": "Sync"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "c", Namespace: "default", ClusterName: "root:org:a", Labels: map[string]string{"state.internal.workloads.kcp.dev/us-east1": ""}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "d", Namespace: "default", ClusterName: "root:org:b"}},
+	} {
+		require.NoError(t, indexer.Add(obj))
+	}
+	gr := schema.GroupResource{Group: "apps", Resource: "deployments"}
+	listers := map[schema.GroupVersionResource]cache.GenericLister{
+		gr.WithVersion("v1"):      cache.NewGenericLister(indexer, gr),
+		gr.WithVersion("v1beta1"): cache.NewGenericLister(indexer, gr),
+	}
+
+	samples, err := SampleObjects(listers)
+	require.NoError(t, err)
+	require.Equal(t, map[logicalcluster.Name]Sample{
+		logicalcluster.New("root:org:a"): {Objects: 3, SyncedResources: 1},
+		logicalcluster.New("root:org:b"): {Objects: 1},
+	}, samples)
+}
+
+func TestCSVExporter(t *testing.T) {
+	dir := t.TempDir()
+	e := &CSVExporter{Directory: dir}
+	start := time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	require.NoError(t, e.Export(context.Background(), []Record{
+		{Workspace: "root:org:a", Start: start, End: start.Add(time.Hour), Requests: 2, Objects: 10, SyncedResources: 4},
+	}))
+	require.NoError(t, e.Export(context.Background(), []Record{
+		{Workspace: "root:org:b", Start: start.Add(time.Hour), End: start.Add(2 * time.Hour), Objects: 3},
+	}))
+
+	bs, err := ioutil.ReadFile(filepath.Join(dir, "usage-2022-06-01.csv"))
+	require.NoError(t, err)
+	require.Equal(t, `workspace,start,end,requests,objects,synced_resources
+root:org:a,2022-06-01T10:00:00Z,2022-06-01T11:00:00Z,2,10,4
+root:org:b,2022-06-01T11:00:00Z,2022-06-01T12:00:00Z,0,3,0
+`, string(bs))
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metering
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+)
+
+// SampleObjects counts the objects of the given listers by logical cluster. Objects
+// with a resource state label of a workload cluster count as synced resources.
+//
+// If listers hold multiple versions of the same resource, only one of them is counted.
+func SampleObjects(listers map[schema.GroupVersionResource]cache.GenericLister) (map[logicalcluster.Name]Sample, error) {
+	gvrs := make([]schema.GroupVersionResource, 0, len(listers))
+	for gvr := range listers {
+		gvrs = append(gvrs, gvr)
+	}
+	sort.Slice(gvrs, func(i, j int) bool { return gvrs[i].String() < gvrs[j].String() })
+
+	samples := map[logicalcluster.Name]Sample{}
+	seen := map[schema.GroupResource]bool{}
+	for _, gvr := range gvrs {
+		if seen[gvr.GroupResource()] {
+			continue
+		}
+		seen[gvr.GroupResource()] = true
+
+		objs, err := listers[gvr].List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range objs {
+			m, err := meta.Accessor(obj)
+			if err != nil {
+				return nil, err
+			}
+			cluster := logicalcluster.From(m)
+			s := samples[cluster]
+			s.Objects++
+			if isSynced(m.GetLabels()) {
+				s.SyncedResources++
+			}
+			samples[cluster] = s
+		}
+	}
+
+	return samples, nil
+}
+
+func isSynced(ls map[string]string) bool {
+	for k, v := range ls {
+		if strings.HasPrefix(k, workloadv1alpha1.InternalClusterResourceStateLabelPrefix) && v == string(workloadv1alpha1.ResourceStateSync) {
+			return true
+		}
+	}
+	return false
+}
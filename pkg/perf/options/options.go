@@ -0,0 +1,137 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+type Options struct {
+	Kubeconfig string
+	// Parent is the workspace, e.g. an organization, the workspaces are created in.
+	// It defaults to the workspace of the kubeconfig.
+	Parent string
+	Prefix string
+
+	Workspaces    int
+	WorkspaceType string
+	// APIExports are bound in the workspaces, in the form <workspace>:<name> with a
+	// workspace of the same organization.
+	APIExports []string
+	Bindings   int
+	Objects    int
+
+	ChurnDuration  time.Duration
+	ChurnQPS       float64
+	ListIterations int
+
+	Concurrency  int
+	PollInterval time.Duration
+	Timeout      time.Duration
+
+	Output string
+	Keep   bool
+}
+
+func NewOptions() *Options {
+	return &Options{
+		Prefix:         "perf",
+		Workspaces:     10,
+		WorkspaceType:  "Universal",
+		Objects:        100,
+		ChurnQPS:       10,
+		ListIterations: 10,
+		Concurrency:    10,
+		PollInterval:   100 * time.Millisecond,
+		Timeout:        5 * time.Minute,
+		Output:         "text",
+	}
+}
+
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Kubeconfig, "kubeconfig", o.Kubeconfig, "Kubeconfig of kcp. The workspaces are created in its current workspace by default.")
+	fs.StringVar(&o.Parent, "parent", o.Parent, "Workspace to create the workspaces in, e.g. root:perf. Defaults to the workspace of --kubeconfig.")
+	fs.StringVar(&o.Prefix, "prefix", o.Prefix, "Prefix of the names of the created workspaces and objects.")
+	fs.IntVar(&o.Workspaces, "workspaces", o.Workspaces, "Number of workspaces to create.")
+	fs.StringVar(&o.WorkspaceType, "workspace-type", o.WorkspaceType, "Type of the created workspaces.")
+	fs.StringSliceVar(&o.APIExports, "api-export", o.APIExports, "APIExport to bind, in the form <workspace>:<name> with a workspace of the same organization as the created workspaces. Can be repeated.")
+	fs.IntVar(&o.Bindings, "bindings", o.Bindings, "Number of APIBindings to create, spread over the workspaces and APIExports. At most one per workspace and APIExport.")
+	fs.IntVar(&o.Objects, "objects", o.Objects, "Number of ConfigMaps to create, spread over the workspaces.")
+	fs.DurationVar(&o.ChurnDuration, "churn-duration", o.ChurnDuration, "How long to update random objects after they are created.")
+	fs.Float64Var(&o.ChurnQPS, "churn-qps", o.ChurnQPS, "Updates per second during --churn-duration.")
+	fs.IntVar(&o.ListIterations, "list-iterations", o.ListIterations, "Number of times the objects of each workspace are listed.")
+	fs.IntVar(&o.Concurrency, "concurrency", o.Concurrency, "Number of concurrent requests.")
+	fs.DurationVar(&o.PollInterval, "poll-interval", o.PollInterval, "Interval of polling workspaces and bindings for readiness. Bounds the precision of the ready latencies.")
+	fs.DurationVar(&o.Timeout, "timeout", o.Timeout, "How long to wait for a workspace or binding to become ready.")
+	fs.StringVarP(&o.Output, "output", "o", o.Output, "Format of the report, text or json.")
+	fs.BoolVar(&o.Keep, "keep", o.Keep, "Keep the created workspaces instead of deleting them at the end.")
+}
+
+func (o *Options) Validate() []error {
+	var errs []error
+
+	if o.Kubeconfig == "" {
+		errs = append(errs, fmt.Errorf("--kubeconfig is required"))
+	}
+	if o.Prefix == "" {
+		errs = append(errs, fmt.Errorf("--prefix is required"))
+	}
+	if o.Workspaces <= 0 {
+		errs = append(errs, fmt.Errorf("--workspaces must be positive"))
+	}
+	for _, export := range o.APIExports {
+		if parts := strings.SplitN(export, ":", 2); len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			errs = append(errs, fmt.Errorf("invalid --api-export %q, expected <workspace>:<name>", export))
+		}
+	}
+	if o.Bindings < 0 {
+		errs = append(errs, fmt.Errorf("--bindings must be non-negative"))
+	}
+	if o.Bindings > o.Workspaces*len(o.APIExports) {
+		errs = append(errs, fmt.Errorf("--bindings must be at most --workspaces times the number of --api-export"))
+	}
+	if o.Objects < 0 {
+		errs = append(errs, fmt.Errorf("--objects must be non-negative"))
+	}
+	if o.ChurnDuration < 0 {
+		errs = append(errs, fmt.Errorf("--churn-duration must be non-negative"))
+	}
+	if o.ChurnDuration > 0 && (o.ChurnQPS <= 0 || o.Objects == 0) {
+		errs = append(errs, fmt.Errorf("--churn-duration requires a positive --churn-qps and --objects"))
+	}
+	if o.ListIterations < 0 {
+		errs = append(errs, fmt.Errorf("--list-iterations must be non-negative"))
+	}
+	if o.Concurrency <= 0 {
+		errs = append(errs, fmt.Errorf("--concurrency must be positive"))
+	}
+	if o.PollInterval <= 0 {
+		errs = append(errs, fmt.Errorf("--poll-interval must be positive"))
+	}
+	if o.Timeout <= 0 {
+		errs = append(errs, fmt.Errorf("--timeout must be positive"))
+	}
+	if o.Output != "text" && o.Output != "json" {
+		errs = append(errs, fmt.Errorf("--output must be text or json"))
+	}
+
+	return errs
+}
@@ -0,0 +1,340 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package perf
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	kcpclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
+	perfoptions "github.com/kcp-dev/kcp/pkg/perf/options"
+)
+
+const (
+	// Namespace is where the objects are created in the workspaces.
+	Namespace = "kcp-perf"
+
+	// the latencies measured, in this order.
+	workspaceReadyLatency = "workspace-ready"
+	bindingReadyLatency   = "binding-ready"
+	objectCreateLatency   = "object-create"
+	objectUpdateLatency   = "object-update"
+	listLatency           = "list"
+)
+
+// Runner runs a load test: it creates workspaces, binds APIExports in them and
+// creates, updates and lists objects in them, measuring the end-to-end latencies.
+type Runner struct {
+	options           *perfoptions.Options
+	parent            logicalcluster.Name
+	kcpClusterClient  kcpclient.ClusterInterface
+	kubeClusterClient kubernetes.ClusterInterface
+
+	now func() time.Time
+
+	recorders map[string]*recorder
+}
+
+// NewRunner returns a Runner creating the workspaces in parent.
+func NewRunner(o *perfoptions.Options, parent logicalcluster.Name, kcpClusterClient kcpclient.ClusterInterface, kubeClusterClient kubernetes.ClusterInterface) *Runner {
+	return &Runner{
+		options:           o,
+		parent:            parent,
+		kcpClusterClient:  kcpClusterClient,
+		kubeClusterClient: kubeClusterClient,
+		now:               time.Now,
+		recorders: map[string]*recorder{
+			workspaceReadyLatency: {},
+			bindingReadyLatency:   {},
+			objectCreateLatency:   {},
+			objectUpdateLatency:   {},
+			listLatency:           {},
+		},
+	}
+}
+
+// Run runs the load test. The created workspaces are deleted at the end unless
+// they are to be kept.
+func (r *Runner) Run(ctx context.Context) (*Report, error) {
+	started := r.now()
+
+	names := make([]string, r.options.Workspaces)
+	for i := range names {
+		names[i] = fmt.Sprintf("%s-%d", r.options.Prefix, i)
+	}
+	if !r.options.Keep {
+		defer r.cleanup(names)
+	}
+
+	klog.Infof("Creating %d workspaces in %s", len(names), r.parent)
+	ready := make([]bool, len(names))
+	r.parallel(ctx, len(names), func(ctx context.Context, i int) {
+		err := r.createWorkspace(ctx, names[i])
+		if err != nil {
+			klog.Errorf("Failed to create workspace %s|%s: %v", r.parent, names[i], err)
+			return
+		}
+		ready[i] = true
+	})
+	var workspaces []logicalcluster.Name
+	for i, name := range names {
+		if ready[i] {
+			workspaces = append(workspaces, r.parent.Join(name))
+		}
+	}
+	if len(workspaces) == 0 {
+		return nil, fmt.Errorf("none of the %d workspaces became ready", len(names))
+	}
+
+	// binding i binds export i / workspaces in workspace i % workspaces, such that
+	// every workspace binds every export at most once.
+	bindings := r.options.Bindings
+	if max := len(workspaces) * len(r.options.APIExports); bindings > max {
+		bindings = max
+	}
+	klog.Infof("Creating %d APIBindings", bindings)
+	r.parallel(ctx, bindings, func(ctx context.Context, i int) {
+		export := r.options.APIExports[i/len(workspaces)]
+		if err := r.createBinding(ctx, workspaces[i%len(workspaces)], export); err != nil {
+			klog.Errorf("Failed to bind %s in %s: %v", export, workspaces[i%len(workspaces)], err)
+		}
+	})
+
+	klog.Infof("Creating %d objects", r.options.Objects)
+	r.parallel(ctx, len(workspaces), func(ctx context.Context, i int) {
+		r.createNamespace(ctx, workspaces[i])
+	})
+	r.parallel(ctx, r.options.Objects, func(ctx context.Context, i int) {
+		r.createObject(ctx, workspaces[i%len(workspaces)], r.objectName(i))
+	})
+
+	if r.options.ChurnDuration > 0 {
+		klog.Infof("Updating objects for %s", r.options.ChurnDuration)
+		r.churn(ctx, workspaces)
+	}
+
+	klog.Infof("Listing objects %d times per workspace", r.options.ListIterations)
+	r.parallel(ctx, len(workspaces)*r.options.ListIterations, func(ctx context.Context, i int) {
+		r.list(ctx, workspaces[i%len(workspaces)])
+	})
+
+	report := &Report{
+		Started:    metav1.NewTime(started),
+		Duration:   metav1.Duration{Duration: r.now().Sub(started)},
+		Workspaces: len(names),
+		Bindings:   bindings,
+		Objects:    r.options.Objects,
+	}
+	for _, name := range []string{workspaceReadyLatency, bindingReadyLatency, objectCreateLatency, objectUpdateLatency, listLatency} {
+		report.Latencies = append(report.Latencies, r.recorders[name].stats(name))
+	}
+	return report, ctx.Err()
+}
+
+// parallel calls fn for 0 <= i < n with the configured concurrency, until ctx is done.
+func (r *Runner) parallel(ctx context.Context, n int, fn func(ctx context.Context, i int)) {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, r.options.Concurrency)
+	for i := 0; i < n; i++ {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		case sem <- struct{}{}:
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			fn(ctx, i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+func (r *Runner) createWorkspace(ctx context.Context, name string) error {
+	client := r.kcpClusterClient.Cluster(r.parent).TenancyV1alpha1().ClusterWorkspaces()
+
+	start := r.now()
+	_, err := client.Create(ctx, &tenancyv1alpha1.ClusterWorkspace{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       tenancyv1alpha1.ClusterWorkspaceSpec{Type: r.options.WorkspaceType},
+	}, metav1.CreateOptions{})
+	if err == nil {
+		err = r.poll(ctx, func(ctx context.Context) (bool, error) {
+			ws, err := client.Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			return ws.Status.Phase == tenancyv1alpha1.ClusterWorkspacePhaseReady, nil
+		})
+	}
+	r.recorders[workspaceReadyLatency].observe(r.now().Sub(start), err)
+	return err
+}
+
+func (r *Runner) createBinding(ctx context.Context, workspace logicalcluster.Name, export string) error {
+	parts := strings.SplitN(export, ":", 2)
+	name := parts[0] + "-" + parts[1]
+	client := r.kcpClusterClient.Cluster(workspace).ApisV1alpha1().APIBindings()
+
+	start := r.now()
+	_, err := client.Create(ctx, &apisv1alpha1.APIBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: apisv1alpha1.APIBindingSpec{
+			Reference: apisv1alpha1.ExportReference{
+				Workspace: &apisv1alpha1.WorkspaceExportReference{
+					WorkspaceName: parts[0],
+					ExportName:    parts[1],
+				},
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err == nil {
+		err = r.poll(ctx, func(ctx context.Context) (bool, error) {
+			binding, err := client.Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			return binding.Status.Phase == apisv1alpha1.APIBindingPhaseBound, nil
+		})
+	}
+	r.recorders[bindingReadyLatency].observe(r.now().Sub(start), err)
+	return err
+}
+
+// poll polls condition until it is met, fails, or the timeout passes.
+func (r *Runner) poll(ctx context.Context, condition wait.ConditionWithContextFunc) error {
+	ctx, cancel := context.WithTimeout(ctx, r.options.Timeout)
+	defer cancel()
+	return wait.PollImmediateUntilWithContext(ctx, r.options.PollInterval, condition)
+}
+
+func (r *Runner) createNamespace(ctx context.Context, workspace logicalcluster.Name) {
+	_, err := r.kubeClusterClient.Cluster(workspace).CoreV1().Namespaces().Create(ctx, &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: Namespace},
+	}, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		klog.Errorf("Failed to create namespace %s in %s: %v", Namespace, workspace, err)
+	}
+}
+
+func (r *Runner) objectName(i int) string {
+	return fmt.Sprintf("%s-%d", r.options.Prefix, i)
+}
+
+func (r *Runner) createObject(ctx context.Context, workspace logicalcluster.Name, name string) {
+	start := r.now()
+	_, err := r.kubeClusterClient.Cluster(workspace).CoreV1().ConfigMaps(Namespace).Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Data:       map[string]string{"generation": "0"},
+	}, metav1.CreateOptions{})
+	r.recorders[objectCreateLatency].observe(r.now().Sub(start), err)
+	if err != nil {
+		klog.V(2).Infof("Failed to create ConfigMap %s|%s/%s: %v", workspace, Namespace, name, err)
+	}
+}
+
+// churn updates random objects at the configured rate for the configured duration.
+func (r *Runner) churn(ctx context.Context, workspaces []logicalcluster.Name) {
+	ctx, cancel := context.WithTimeout(ctx, r.options.ChurnDuration)
+	defer cancel()
+
+	updates := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < r.options.Concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range updates {
+				r.updateObject(ctx, workspaces[i%len(workspaces)], r.objectName(i))
+			}
+		}()
+	}
+
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / r.options.ChurnQPS))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			close(updates)
+			wg.Wait()
+			return
+		case <-ticker.C:
+		}
+		select {
+		case updates <- rand.Intn(r.options.Objects):
+		default:
+			// all workers are busy, the server is slower than the rate.
+		}
+	}
+}
+
+func (r *Runner) updateObject(ctx context.Context, workspace logicalcluster.Name, name string) {
+	client := r.kubeClusterClient.Cluster(workspace).CoreV1().ConfigMaps(Namespace)
+
+	start := r.now()
+	cm, err := client.Get(ctx, name, metav1.GetOptions{})
+	if err == nil {
+		generation, _ := strconv.Atoi(cm.Data["generation"])
+		cm.Data = map[string]string{"generation": strconv.Itoa(generation + 1)}
+		_, err = client.Update(ctx, cm, metav1.UpdateOptions{})
+	}
+	if ctx.Err() != nil {
+		// interrupted by the end of the churn, not a failure.
+		return
+	}
+	r.recorders[objectUpdateLatency].observe(r.now().Sub(start), err)
+}
+
+func (r *Runner) list(ctx context.Context, workspace logicalcluster.Name) {
+	start := r.now()
+	_, err := r.kubeClusterClient.Cluster(workspace).CoreV1().ConfigMaps(Namespace).List(ctx, metav1.ListOptions{})
+	r.recorders[listLatency].observe(r.now().Sub(start), err)
+}
+
+// cleanup deletes the workspaces, also if the load test was interrupted.
+func (r *Runner) cleanup(names []string) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.options.Timeout)
+	defer cancel()
+
+	klog.Infof("Deleting %d workspaces in %s", len(names), r.parent)
+	r.parallel(ctx, len(names), func(ctx context.Context, i int) {
+		err := r.kcpClusterClient.Cluster(r.parent).TenancyV1alpha1().ClusterWorkspaces().Delete(ctx, names[i], metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			klog.Errorf("Failed to delete workspace %s|%s: %v", r.parent, names[i], err)
+		}
+	})
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package perf
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/client/clusterfake"
+	perfoptions "github.com/kcp-dev/kcp/pkg/perf/options"
+)
+
+// fakeKubeClusterClient has a fake clientset per logical cluster.
+type fakeKubeClusterClient struct {
+	lock    sync.Mutex
+	clients map[logicalcluster.Name]*kubefake.Clientset
+}
+
+func (c *fakeKubeClusterClient) Cluster(cluster logicalcluster.Name) kubernetes.Interface {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if client, ok := c.clients[cluster]; ok {
+		return client
+	}
+	client := kubefake.NewSimpleClientset()
+	c.clients[cluster] = client
+	return client
+}
+
+func TestRunner(t *testing.T) {
+	kcpClient := clusterfake.NewSimpleClusterClientset()
+	// workspaces and bindings become ready right away.
+	kcpClient.PrependReactor("create", "*", func(_ logicalcluster.Name, action clienttesting.Action) (bool, runtime.Object, error) {
+		switch obj := action.(clienttesting.CreateAction).GetObject().(type) {
+		case *tenancyv1alpha1.ClusterWorkspace:
+			obj.Status.Phase = tenancyv1alpha1.ClusterWorkspacePhaseReady
+		case *apisv1alpha1.APIBinding:
+			obj.Status.Phase = apisv1alpha1.APIBindingPhaseBound
+		}
+		return false, nil, nil
+	})
+	kubeClient := &fakeKubeClusterClient{clients: map[logicalcluster.Name]*kubefake.Clientset{}}
+
+	o := perfoptions.NewOptions()
+	o.Workspaces = 3
+	o.APIExports = []string{"providers:widgets", "providers:gadgets"}
+	o.Bindings = 4
+	o.Objects = 6
+	o.ChurnDuration = 100 * time.Millisecond
+	o.ChurnQPS = 100
+	o.ListIterations = 2
+	o.Concurrency = 2
+	o.PollInterval = time.Millisecond
+	o.Timeout = time.Second
+
+	r := NewRunner(o, logicalcluster.New("root:perf"), kcpClient, kubeClient)
+	report, err := r.Run(context.Background())
+	require.NoError(t, err)
+
+	require.Equal(t, 3, report.Workspaces)
+	require.Equal(t, 4, report.Bindings)
+	require.Equal(t, 6, report.Objects)
+
+	counts := map[string]int{}
+	for _, s := range report.Latencies {
+		require.Zero(t, s.Errors, "unexpected errors of %s", s.Name)
+		counts[s.Name] = s.Count
+	}
+	require.Equal(t, 3, counts[workspaceReadyLatency])
+	require.Equal(t, 4, counts[bindingReadyLatency])
+	require.Equal(t, 6, counts[objectCreateLatency])
+	require.NotZero(t, counts[objectUpdateLatency])
+	require.Equal(t, 6, counts[listLatency])
+
+	bindings, err := kcpClient.Cluster(logicalcluster.New("root:perf:perf-0")).ApisV1alpha1().APIBindings().List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	var names []string
+	for _, b := range bindings.Items {
+		names = append(names, b.Name)
+	}
+	require.ElementsMatch(t, []string{"providers-widgets", "providers-gadgets"}, names)
+
+	configMaps, err := kubeClient.Cluster(logicalcluster.New("root:perf:perf-1")).CoreV1().ConfigMaps(Namespace).List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, configMaps.Items, 2)
+
+	t.Log("The workspaces are deleted at the end")
+	workspaces, err := kcpClient.Cluster(logicalcluster.New("root:perf")).TenancyV1alpha1().ClusterWorkspaces().List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Empty(t, workspaces.Items)
+}
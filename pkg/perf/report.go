@@ -0,0 +1,134 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package perf
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Report is the result of a load test.
+type Report struct {
+	// Started is when the load test started.
+	Started metav1.Time `json:"started"`
+	// Duration is how long the load test took, without cleanup.
+	Duration metav1.Duration `json:"duration"`
+
+	Workspaces int `json:"workspaces"`
+	Bindings   int `json:"bindings"`
+	Objects    int `json:"objects"`
+
+	// Latencies are the statistics of the measured latencies, in the order they
+	// were measured.
+	Latencies []Stats `json:"latencies"`
+}
+
+// Stats are the statistics of a latency.
+type Stats struct {
+	Name string `json:"name"`
+	// Count is the number of successful samples.
+	Count int `json:"count"`
+	// Errors is the number of failed attempts, which are not sampled.
+	Errors int `json:"errors"`
+
+	Min  metav1.Duration `json:"min"`
+	Mean metav1.Duration `json:"mean"`
+	P50  metav1.Duration `json:"p50"`
+	P90  metav1.Duration `json:"p90"`
+	P99  metav1.Duration `json:"p99"`
+	Max  metav1.Duration `json:"max"`
+}
+
+// WriteText writes the report as a table.
+func (r *Report) WriteText(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "Started %s, took %s: %d workspaces, %d bindings, %d objects\n\n",
+		r.Started.UTC().Format(time.RFC3339), r.Duration.Duration.Round(time.Millisecond), r.Workspaces, r.Bindings, r.Objects); err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "LATENCY\tCOUNT\tERRORS\tMIN\tMEAN\tP50\tP90\tP99\tMAX")
+	for _, s := range r.Latencies {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%s\t%s\t%s\t%s\t%s\n", s.Name, s.Count, s.Errors,
+			round(s.Min), round(s.Mean), round(s.P50), round(s.P90), round(s.P99), round(s.Max))
+	}
+	return tw.Flush()
+}
+
+func round(d metav1.Duration) time.Duration {
+	return d.Duration.Round(time.Millisecond / 10)
+}
+
+// recorder records the samples of a latency.
+type recorder struct {
+	lock    sync.Mutex
+	samples []time.Duration
+	errors  int
+}
+
+func (r *recorder) observe(d time.Duration, err error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if err != nil {
+		r.errors++
+		return
+	}
+	r.samples = append(r.samples, d)
+}
+
+func (r *recorder) stats(name string) Stats {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	s := Stats{Name: name, Count: len(r.samples), Errors: r.errors}
+	if len(r.samples) == 0 {
+		return s
+	}
+
+	sorted := make([]time.Duration, len(r.samples))
+	copy(sorted, r.samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var sum time.Duration
+	for _, d := range sorted {
+		sum += d
+	}
+	s.Min = metav1.Duration{Duration: sorted[0]}
+	s.Mean = metav1.Duration{Duration: sum / time.Duration(len(sorted))}
+	s.P50 = metav1.Duration{Duration: percentile(sorted, 50)}
+	s.P90 = metav1.Duration{Duration: percentile(sorted, 90)}
+	s.P99 = metav1.Duration{Duration: percentile(sorted, 99)}
+	s.Max = metav1.Duration{Duration: sorted[len(sorted)-1]}
+	return s
+}
+
+// percentile returns the nearest-rank percentile of sorted samples.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
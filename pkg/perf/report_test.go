@@ -0,0 +1,82 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package perf
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRecorderStats(t *testing.T) {
+	r := &recorder{}
+	require.Equal(t, Stats{Name: "empty"}, r.stats("empty"))
+
+	for i := 100; i >= 1; i-- {
+		r.observe(time.Duration(i)*time.Millisecond, nil)
+	}
+	r.observe(time.Hour, errors.New("timeout"))
+
+	require.Equal(t, Stats{
+		Name:   "list",
+		Count:  100,
+		Errors: 1,
+		Min:    metav1.Duration{Duration: time.Millisecond},
+		Mean:   metav1.Duration{Duration: 50500 * time.Microsecond},
+		P50:    metav1.Duration{Duration: 50 * time.Millisecond},
+		P90:    metav1.Duration{Duration: 90 * time.Millisecond},
+		P99:    metav1.Duration{Duration: 99 * time.Millisecond},
+		Max:    metav1.Duration{Duration: 100 * time.Millisecond},
+	}, r.stats("list"))
+}
+
+func TestPercentile(t *testing.T) {
+	samples := []time.Duration{1, 2, 3}
+	require.Equal(t, time.Duration(1), percentile(samples, 0))
+	require.Equal(t, time.Duration(2), percentile(samples, 50))
+	require.Equal(t, time.Duration(3), percentile(samples, 99))
+	require.Equal(t, time.Duration(3), percentile(samples, 100))
+}
+
+func TestWriteText(t *testing.T) {
+	report := &Report{
+		Started:    metav1.NewTime(time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)),
+		Duration:   metav1.Duration{Duration: 83 * time.Second},
+		Workspaces: 10,
+		Objects:    100,
+		Latencies: []Stats{
+			{Name: "workspace-ready", Count: 10, Min: metav1.Duration{Duration: time.Second}, Mean: metav1.Duration{Duration: 1500 * time.Millisecond},
+				P50: metav1.Duration{Duration: 1400 * time.Millisecond}, P90: metav1.Duration{Duration: 2 * time.Second},
+				P99: metav1.Duration{Duration: 2 * time.Second}, Max: metav1.Duration{Duration: 2 * time.Second}},
+			{Name: "binding-ready"},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteText(&buf))
+	require.Equal(t, `Started 2022-06-01T12:00:00Z, took 1m23s: 10 workspaces, 0 bindings, 100 objects
+
+LATENCY          COUNT  ERRORS  MIN  MEAN  P50   P90  P99  MAX
+workspace-ready  10     0       1s   1.5s  1.4s  2s   2s   2s
+binding-ready    0      0       0s   0s    0s    0s   0s   0s
+`, buf.String())
+}
@@ -0,0 +1,458 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preflight
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"sigs.k8s.io/yaml"
+)
+
+// Path is where the Validator serves the pre-flight validation of manifest bundles
+// for the workspace of the request.
+const Path = "/preflight"
+
+const (
+	// maxBundleBytes is the maximum size of a bundle.
+	maxBundleBytes = 3 * 1024 * 1024
+
+	// fieldManager is the field manager of the dry-run applies of existing objects.
+	fieldManager = "kcp-preflight"
+)
+
+// These are the results of the validation of an object.
+const (
+	// ResultValid means that the object would be created or updated.
+	ResultValid = "Valid"
+	// ResultNotValidated means that the object is in a namespace created by the bundle. Only
+	// the permission to create it was checked.
+	ResultNotValidated = "NotValidated"
+	// ResultInvalid means that the document could not be decoded, or that the object does not
+	// match the schema of its resource, or that admission rejected it.
+	ResultInvalid = "Invalid"
+	// ResultUnknownResource means that the kind of the object is not served in the workspace,
+	// e.g. because no APIBinding provides it.
+	ResultUnknownResource = "UnknownResource"
+	// ResultForbidden means that the user is not allowed to create or update the object.
+	ResultForbidden = "Forbidden"
+	// ResultQuotaExceeded means that the object exceeds a ResourceQuota of its namespace.
+	ResultQuotaExceeded = "QuotaExceeded"
+	// ResultError means that the validation failed for other reasons, e.g. an unavailable
+	// admission webhook. Retrying might succeed.
+	ResultError = "Error"
+)
+
+// Report is the result of the pre-flight validation of a bundle.
+type Report struct {
+	// Valid is true if all objects are Valid or NotValidated, and no ResourceQuota is exceeded
+	// by the objects of the bundle together.
+	Valid bool `json:"valid"`
+	// Objects are the results of the objects of the bundle, in order.
+	Objects []ObjectReport `json:"objects"`
+	// Quotas are the ResourceQuotas limiting the number of objects the bundle creates.
+	Quotas []QuotaReport `json:"quotas,omitempty"`
+}
+
+// ObjectReport is the result of the validation of an object of the bundle.
+type ObjectReport struct {
+	// Document is the 1-based position of the YAML document of the object in the bundle.
+	// The items of a List share the document.
+	Document   int    `json:"document"`
+	APIVersion string `json:"apiVersion,omitempty"`
+	Kind       string `json:"kind,omitempty"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name,omitempty"`
+	// Verb is create for new objects and update for existing ones.
+	Verb    string `json:"verb,omitempty"`
+	Result  string `json:"result"`
+	Message string `json:"message,omitempty"`
+}
+
+// QuotaReport compares the number of objects the bundle creates with a ResourceQuota.
+type QuotaReport struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Resource is the name of the limited resource in the ResourceQuota, e.g. count/deployments.apps.
+	Resource  string `json:"resource"`
+	Hard      int64  `json:"hard"`
+	Used      int64  `json:"used"`
+	Requested int64  `json:"requested"`
+	Exceeded  bool   `json:"exceeded"`
+}
+
+// Validator validates the objects of manifest bundles by dry-run requests on behalf of the
+// user, i.e. against the schemas bound in the workspace, admission including quota, and the
+// permissions of the user, without applying them.
+type Validator struct {
+	newRESTMapper func(clusterName logicalcluster.Name) meta.RESTMapper
+	newClient     func(clusterName logicalcluster.Name, u user.Info) (dynamic.Interface, error)
+	listQuotas    func(clusterName logicalcluster.Name, namespace string) ([]*corev1.ResourceQuota, error)
+	authorizer    authorizer.Authorizer
+}
+
+// NewValidator returns a Validator sending dry-run requests with the given loopback config,
+// impersonating the user.
+func NewValidator(config *rest.Config, kubeClusterClient kubernetes.ClusterInterface, quotaInformer coreinformers.ResourceQuotaInformer, authz authorizer.Authorizer) *Validator {
+	quotaLister := quotaInformer.Lister()
+	return &Validator{
+		newRESTMapper: func(clusterName logicalcluster.Name) meta.RESTMapper {
+			return restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(kubeClusterClient.Cluster(clusterName).Discovery()))
+		},
+		newClient: func(clusterName logicalcluster.Name, u user.Info) (dynamic.Interface, error) {
+			impersonatingConfig := rest.CopyConfig(config)
+			impersonatingConfig.Impersonate = rest.ImpersonationConfig{
+				UserName: u.GetName(),
+				Groups:   u.GetGroups(),
+				Extra:    u.GetExtra(),
+			}
+			client, err := dynamic.NewClusterForConfig(impersonatingConfig)
+			if err != nil {
+				return nil, err
+			}
+			return client.Cluster(clusterName), nil
+		},
+		listQuotas: func(clusterName logicalcluster.Name, namespace string) ([]*corev1.ResourceQuota, error) {
+			quotas, err := quotaLister.ResourceQuotas(namespace).List(labels.Everything())
+			if err != nil {
+				return nil, err
+			}
+			var ret []*corev1.ResourceQuota
+			for _, quota := range quotas {
+				if logicalcluster.From(quota) == clusterName {
+					ret = append(ret, quota)
+				}
+			}
+			return ret, nil
+		},
+		authorizer: authz,
+	}
+}
+
+// document is an object of a bundle, or the error decoding it.
+type document struct {
+	index int
+	obj   *unstructured.Unstructured
+	err   error
+}
+
+// Validate validates the objects of the bundle, a multi-document YAML or JSON stream, in the
+// given workspace on behalf of the user.
+func (v *Validator) Validate(ctx context.Context, clusterName logicalcluster.Name, u user.Info, bundle []byte) (*Report, error) {
+	documents := decodeBundle(bundle)
+
+	client, err := v.newClient(clusterName, u)
+	if err != nil {
+		return nil, err
+	}
+	mapper := v.newRESTMapper(clusterName)
+
+	createdNamespaces := sets.NewString()
+	for _, doc := range documents {
+		if doc.err == nil && doc.obj.GroupVersionKind() == corev1.SchemeGroupVersion.WithKind("Namespace") {
+			createdNamespaces.Insert(doc.obj.GetName())
+		}
+	}
+
+	report := &Report{Valid: true, Objects: []ObjectReport{}}
+	created := map[string]map[schema.GroupResource]int64{}
+	for _, doc := range documents {
+		result, gr := v.validateObject(ctx, clusterName, u, client, mapper, doc, createdNamespaces)
+		if result.Result != ResultValid && result.Result != ResultNotValidated {
+			report.Valid = false
+		}
+		if result.Verb == "create" && result.Result != ResultInvalid && result.Result != ResultForbidden && result.Result != ResultUnknownResource && result.Namespace != "" {
+			if created[result.Namespace] == nil {
+				created[result.Namespace] = map[schema.GroupResource]int64{}
+			}
+			created[result.Namespace][gr]++
+		}
+		report.Objects = append(report.Objects, result)
+	}
+
+	for _, namespace := range sets.StringKeySet(created).List() {
+		quotas, err := v.listQuotas(clusterName, namespace)
+		if err != nil {
+			return nil, err
+		}
+		for _, quota := range quotas {
+			report.Quotas = append(report.Quotas, checkQuota(quota, created[namespace])...)
+		}
+	}
+	for _, quota := range report.Quotas {
+		if quota.Exceeded {
+			report.Valid = false
+		}
+	}
+
+	return report, nil
+}
+
+// validateObject validates the object of the document by a dry-run creation, or a dry-run
+// apply if it exists. It returns the result and the resource of the object.
+func (v *Validator) validateObject(ctx context.Context, clusterName logicalcluster.Name, u user.Info, client dynamic.Interface, mapper meta.RESTMapper, doc document, createdNamespaces sets.String) (ObjectReport, schema.GroupResource) {
+	result := ObjectReport{Document: doc.index}
+	if doc.err != nil {
+		result.Result = ResultInvalid
+		result.Message = doc.err.Error()
+		return result, schema.GroupResource{}
+	}
+
+	obj := doc.obj
+	gvk := obj.GroupVersionKind()
+	result.APIVersion = obj.GetAPIVersion()
+	result.Kind = obj.GetKind()
+	result.Name = obj.GetName()
+	if gvk.Kind == "" || gvk.Version == "" {
+		result.Result = ResultInvalid
+		result.Message = "apiVersion and kind are required"
+		return result, schema.GroupResource{}
+	}
+
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		result.Namespace = obj.GetNamespace()
+		result.Result = ResultUnknownResource
+		result.Message = fmt.Sprintf("kind %q of %q is not served in workspace %s", gvk.Kind, gvk.GroupVersion(), clusterName)
+		return result, schema.GroupResource{}
+	}
+	gr := mapping.Resource.GroupResource()
+
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		if obj.GetNamespace() == "" {
+			obj.SetNamespace(metav1.NamespaceDefault)
+		}
+	} else {
+		obj.SetNamespace("")
+	}
+	result.Namespace = obj.GetNamespace()
+	resourceClient := client.Resource(mapping.Resource).Namespace(obj.GetNamespace())
+
+	result.Verb = "create"
+	_, err = resourceClient.Create(ctx, obj, metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}, FieldManager: fieldManager})
+	if apierrors.IsAlreadyExists(err) && obj.GetName() != "" {
+		result.Verb = "update"
+		var data []byte
+		if data, err = json.Marshal(obj); err == nil {
+			force := true
+			_, err = resourceClient.Patch(ctx, obj.GetName(), types.ApplyPatchType, data, metav1.PatchOptions{DryRun: []string{metav1.DryRunAll}, FieldManager: fieldManager, Force: &force})
+		}
+	}
+	if apierrors.IsNotFound(err) && createdNamespaces.Has(obj.GetNamespace()) {
+		// the namespace does not exist yet, hence only the permissions can be checked.
+		return v.checkCreate(ctx, clusterName, u, mapping.Resource, result), gr
+	}
+
+	switch {
+	case err == nil:
+		result.Result = ResultValid
+	case apierrors.IsForbidden(err) && strings.Contains(err.Error(), "exceeded quota"):
+		result.Result = ResultQuotaExceeded
+	case apierrors.IsForbidden(err):
+		result.Result = ResultForbidden
+	case apierrors.IsInvalid(err), apierrors.IsBadRequest(err), apierrors.IsNotFound(err), apierrors.IsConflict(err), apierrors.IsUnsupportedMediaType(err):
+		result.Result = ResultInvalid
+	default:
+		result.Result = ResultError
+	}
+	if err != nil {
+		result.Message = err.Error()
+	}
+	return result, gr
+}
+
+// checkCreate checks the permission of the user to create the object.
+func (v *Validator) checkCreate(ctx context.Context, clusterName logicalcluster.Name, u user.Info, gvr schema.GroupVersionResource, result ObjectReport) ObjectReport {
+	ctx = genericapirequest.WithCluster(ctx, genericapirequest.Cluster{Name: clusterName})
+	decision, reason, err := v.authorizer.Authorize(ctx, authorizer.AttributesRecord{
+		User:            u,
+		Verb:            "create",
+		Namespace:       result.Namespace,
+		APIGroup:        gvr.Group,
+		APIVersion:      gvr.Version,
+		Resource:        gvr.Resource,
+		Name:            result.Name,
+		ResourceRequest: true,
+	})
+	switch {
+	case err != nil:
+		result.Result = ResultError
+		result.Message = fmt.Sprintf("unable to determine access to %s: %v", gvr.GroupResource(), err)
+	case decision != authorizer.DecisionAllow:
+		result.Result = ResultForbidden
+		result.Message = fmt.Sprintf("user %q cannot create %s in namespace %q", u.GetName(), gvr.GroupResource(), result.Namespace)
+		if reason != "" {
+			result.Message += ": " + reason
+		}
+	default:
+		result.Result = ResultNotValidated
+		result.Message = fmt.Sprintf("namespace %q is created by the bundle, only the permission to create the object was checked", result.Namespace)
+	}
+	return result
+}
+
+// checkQuota compares the hard object count limits of the ResourceQuota with the objects
+// created in its namespace.
+func checkQuota(quota *corev1.ResourceQuota, created map[schema.GroupResource]int64) []QuotaReport {
+	var ret []QuotaReport
+	for _, name := range sortedResourceNames(quota.Spec.Hard) {
+		gr, ok := countedResource(name)
+		if !ok || created[gr] == 0 {
+			continue
+		}
+		hard := quota.Spec.Hard[name]
+		used := quota.Status.Used[name]
+		report := QuotaReport{
+			Namespace: quota.Namespace,
+			Name:      quota.Name,
+			Resource:  string(name),
+			Hard:      hard.Value(),
+			Used:      used.Value(),
+			Requested: created[gr],
+		}
+		report.Exceeded = report.Used+report.Requested > report.Hard
+		ret = append(ret, report)
+	}
+	return ret
+}
+
+// countedResource returns the resource whose objects are counted by the quota resource name.
+func countedResource(name corev1.ResourceName) (schema.GroupResource, bool) {
+	if strings.HasPrefix(string(name), "count/") {
+		return schema.ParseGroupResource(strings.TrimPrefix(string(name), "count/")), true
+	}
+	switch name {
+	case corev1.ResourcePods, corev1.ResourceServices, corev1.ResourceConfigMaps, corev1.ResourceSecrets,
+		corev1.ResourcePersistentVolumeClaims, corev1.ResourceReplicationControllers, corev1.ResourceQuotas:
+		return schema.GroupResource{Resource: string(name)}, true
+	}
+	return schema.GroupResource{}, false
+}
+
+func sortedResourceNames(list corev1.ResourceList) []corev1.ResourceName {
+	names := make([]string, 0, len(list))
+	for name := range list {
+		names = append(names, string(name))
+	}
+	ret := make([]corev1.ResourceName, 0, len(names))
+	for _, name := range sets.NewString(names...).List() {
+		ret = append(ret, corev1.ResourceName(name))
+	}
+	return ret
+}
+
+// decodeBundle splits the bundle into its YAML documents, and the items of Lists. Documents
+// after one which cannot be read are dropped.
+func decodeBundle(bundle []byte) []document {
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(bundle)))
+
+	var documents []document
+	for index := 1; ; index++ {
+		data, err := reader.Read()
+		if err == io.EOF {
+			return documents
+		} else if err != nil {
+			return append(documents, document{index: index, err: err})
+		}
+
+		obj := map[string]interface{}{}
+		if err := yaml.Unmarshal(data, &obj); err != nil {
+			documents = append(documents, document{index: index, err: err})
+			continue
+		}
+		if len(obj) == 0 {
+			continue // empty document, e.g. only comments
+		}
+
+		u := &unstructured.Unstructured{Object: obj}
+		if !u.IsList() {
+			documents = append(documents, document{index: index, obj: u})
+			continue
+		}
+		if err := u.EachListItem(func(item runtime.Object) error {
+			documents = append(documents, document{index: index, obj: item.(*unstructured.Unstructured)})
+			return nil
+		}); err != nil {
+			documents = append(documents, document{index: index, err: err})
+		}
+	}
+}
+
+// ServeHTTP validates the bundle in the body of POST requests in the workspace of the request,
+// and returns the Report as JSON.
+func (v *Validator) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "bundles must be POSTed", http.StatusMethodNotAllowed)
+		return
+	}
+	cluster := genericapirequest.ClusterFrom(req.Context())
+	if cluster == nil || cluster.Name.Empty() || cluster.Wildcard {
+		http.Error(w, "pre-flight validation is only available for a single workspace", http.StatusBadRequest)
+		return
+	}
+	u, ok := genericapirequest.UserFrom(req.Context())
+	if !ok {
+		http.Error(w, "no user found for request", http.StatusUnauthorized)
+		return
+	}
+
+	bundle, err := ioutil.ReadAll(http.MaxBytesReader(w, req.Body, maxBundleBytes))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read bundle: %v", err), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	report, err := v.Validate(req.Context(), cluster.Name, u, bundle)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(report)
+}
@@ -0,0 +1,228 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+const bundle = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: a
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: existing
+  namespace: default
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: token
+---
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: w
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: new
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: b
+  namespace: new
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+---
+this: is: not yaml
+---
+# only a comment
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: c
+`
+
+func TestValidate(t *testing.T) {
+	existing := &unstructured.Unstructured{Object: map[string]interface{}{"apiVersion": "v1", "kind": "ConfigMap"}}
+	existing.SetNamespace("default")
+	existing.SetName("existing")
+
+	client := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		{Version: "v1", Resource: "configmaps"}:                 "ConfigMapList",
+		{Version: "v1", Resource: "secrets"}:                    "SecretList",
+		{Version: "v1", Resource: "namespaces"}:                 "NamespaceList",
+		{Group: "apps", Version: "v1", Resource: "deployments"}: "DeploymentList",
+	}, existing)
+	client.PrependReactor("create", "secrets", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "secrets"}, "token", fmt.Errorf("not allowed"))
+	})
+	client.PrependReactor("create", "deployments", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewInvalid(schema.GroupKind{Group: "apps", Kind: "Deployment"}, "web", field.ErrorList{field.Required(field.NewPath("spec", "selector"), "")})
+	})
+	client.PrependReactor("create", "configmaps", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		if action.GetNamespace() == "new" {
+			return true, nil, apierrors.NewNotFound(schema.GroupResource{Resource: "namespaces"}, "new")
+		}
+		return false, nil, nil
+	})
+	var applied []string
+	client.PrependReactor("patch", "configmaps", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		patch := action.(clienttesting.PatchAction)
+		require.Equal(t, types.ApplyPatchType, patch.GetPatchType())
+		applied = append(applied, patch.GetNamespace()+"/"+patch.GetName())
+		return true, nil, nil
+	})
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Secret"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, meta.RESTScopeRoot)
+	mapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+
+	var authorized []string
+	v := &Validator{
+		newRESTMapper: func(clusterName logicalcluster.Name) meta.RESTMapper {
+			require.Equal(t, "root:org:team", clusterName.String())
+			return mapper
+		},
+		newClient: func(clusterName logicalcluster.Name, u user.Info) (dynamic.Interface, error) {
+			require.Equal(t, "root:org:team", clusterName.String())
+			require.Equal(t, "ci", u.GetName())
+			return client, nil
+		},
+		listQuotas: func(clusterName logicalcluster.Name, namespace string) ([]*corev1.ResourceQuota, error) {
+			if namespace != "default" {
+				return nil, nil
+			}
+			return []*corev1.ResourceQuota{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "objects"},
+				Spec: corev1.ResourceQuotaSpec{Hard: corev1.ResourceList{
+					"count/configmaps":       resource.MustParse("2"),
+					"count/deployments.apps": resource.MustParse("5"),
+					corev1.ResourcePods:      resource.MustParse("10"),
+				}},
+				Status: corev1.ResourceQuotaStatus{Used: corev1.ResourceList{
+					"count/configmaps": resource.MustParse("1"),
+				}},
+			}}, nil
+		},
+		authorizer: authorizer.AuthorizerFunc(func(ctx context.Context, attr authorizer.Attributes) (authorizer.Decision, string, error) {
+			authorized = append(authorized, attr.GetVerb()+" "+attr.GetResource()+" "+attr.GetNamespace()+"/"+attr.GetName())
+			return authorizer.DecisionAllow, "", nil
+		}),
+	}
+
+	report, err := v.Validate(context.Background(), logicalcluster.New("root:org:team"), &user.DefaultInfo{Name: "ci"}, []byte(bundle))
+	require.NoError(t, err)
+
+	var got []string
+	for _, obj := range report.Objects {
+		got = append(got, fmt.Sprintf("%d %s %s/%s %s %s", obj.Document, obj.Kind, obj.Namespace, obj.Name, obj.Verb, obj.Result))
+	}
+	require.Equal(t, []string{
+		"1 ConfigMap default/a create Valid",
+		"2 ConfigMap default/existing update Valid",
+		"3 Secret default/token create Forbidden",
+		"4 Widget /w  UnknownResource",
+		"5 Namespace /new create Valid",
+		"6 ConfigMap new/b create NotValidated",
+		"7 Deployment default/web create Invalid",
+		"8  /  Invalid",
+		"10 ConfigMap default/c create Valid",
+	}, got)
+	require.Equal(t, []string{"default/existing"}, applied)
+	require.Equal(t, []string{"create configmaps new/b"}, authorized)
+
+	require.Equal(t, []QuotaReport{
+		{Namespace: "default", Name: "objects", Resource: "count/configmaps", Hard: 2, Used: 1, Requested: 2, Exceeded: true},
+	}, report.Quotas)
+	require.False(t, report.Valid)
+}
+
+func TestValidateValid(t *testing.T) {
+	client := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		{Version: "v1", Resource: "configmaps"}: "ConfigMapList",
+	})
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	v := &Validator{
+		newRESTMapper: func(logicalcluster.Name) meta.RESTMapper { return mapper },
+		newClient: func(logicalcluster.Name, user.Info) (dynamic.Interface, error) {
+			return client, nil
+		},
+		listQuotas: func(logicalcluster.Name, string) ([]*corev1.ResourceQuota, error) { return nil, nil },
+	}
+
+	report, err := v.Validate(context.Background(), logicalcluster.New("root:org:team"), &user.DefaultInfo{Name: "ci"}, []byte(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "a", "namespace": "apps"}}`))
+	require.NoError(t, err)
+	require.True(t, report.Valid)
+	require.Equal(t, []ObjectReport{{Document: 1, APIVersion: "v1", Kind: "ConfigMap", Namespace: "apps", Name: "a", Verb: "create", Result: ResultValid}}, report.Objects)
+}
+
+func TestServeHTTP(t *testing.T) {
+	v := &Validator{}
+	serve := func(ctx context.Context, method string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, Path, strings.NewReader("")).WithContext(ctx)
+		rec := httptest.NewRecorder()
+		v.ServeHTTP(rec, req)
+		return rec
+	}
+
+	ctx := genericapirequest.WithUser(context.Background(), &user.DefaultInfo{Name: "ci"})
+	require.Equal(t, http.StatusMethodNotAllowed, serve(genericapirequest.WithCluster(ctx, genericapirequest.Cluster{Name: logicalcluster.New("root:org")}), http.MethodGet).Code)
+	require.Equal(t, http.StatusBadRequest, serve(genericapirequest.WithCluster(ctx, genericapirequest.Cluster{Name: logicalcluster.Wildcard, Wildcard: true}), http.MethodPost).Code)
+	require.Equal(t, http.StatusBadRequest, serve(ctx, http.MethodPost).Code)
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/klog/v2"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+// DefaultCanaryHeader is the request header selecting the build of a virtual workspace
+// with a canary, if the mapping does not name another one.
+const DefaultCanaryHeader = "X-Kcp-Canary"
+
+// CanaryBackend describes a second build of a virtual workspace that serves a share of
+// the requests of a path mapping, e.g. to try out a new version against live traffic.
+type CanaryBackend struct {
+	Backend         string `json:"backend"`
+	BackendServerCA string `json:"backend_server_ca"`
+	ProxyClientCert string `json:"proxy_client_cert"`
+	ProxyClientKey  string `json:"proxy_client_key"`
+	// Weight is the percentage of users whose requests are forwarded to the canary.
+	// Users are assigned by a hash of their name, i.e. all requests of a user go to
+	// the same build as long as the weight does not change.
+	Weight int `json:"weight,omitempty"`
+	// Header is the name of the request header with which clients select the build,
+	// "true" for the canary and "false" for the stable backend, regardless of the weight.
+	// Defaults to X-Kcp-Canary.
+	Header string `json:"header,omitempty"`
+}
+
+func (c *CanaryBackend) validate(path string) error {
+	if !strings.HasPrefix(path, tenancyv1alpha1.VirtualWorkspacePathPrefix) {
+		return fmt.Errorf("path mapping %q has a canary, which is only supported for paths under %s", path, tenancyv1alpha1.VirtualWorkspacePathPrefix)
+	}
+	if c.Backend == "" {
+		return fmt.Errorf("canary of path mapping %q has no backend", path)
+	}
+	if c.Weight < 0 || c.Weight > 100 {
+		return fmt.Errorf("canary of path mapping %q has weight %d, which is not between 0 and 100", path, c.Weight)
+	}
+	return nil
+}
+
+// canaryRouter forwards requests either to the stable or the canary backend of a path
+// mapping.
+type canaryRouter struct {
+	path           string
+	stable, canary http.Handler
+	weight         int
+	header         string
+}
+
+func newCanaryRouter(path string, c *CanaryBackend, stable, canary http.Handler) *canaryRouter {
+	header := c.Header
+	if header == "" {
+		header = DefaultCanaryHeader
+	}
+	return &canaryRouter{
+		path:   path,
+		stable: stable,
+		canary: canary,
+		weight: c.Weight,
+		header: header,
+	}
+}
+
+func (r *canaryRouter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if r.toCanary(req) {
+		klog.V(6).Infof("Forwarding %s %s to the canary of %s", req.Method, req.URL.Path, r.path)
+		r.canary.ServeHTTP(w, req)
+		return
+	}
+	r.stable.ServeHTTP(w, req)
+}
+
+func (r *canaryRouter) toCanary(req *http.Request) bool {
+	if v := req.Header.Get(r.header); v != "" {
+		// the header is meant for the proxy only
+		req.Header.Del(r.header)
+		if canary, err := strconv.ParseBool(v); err == nil {
+			return canary
+		}
+	}
+
+	if r.weight <= 0 {
+		return false
+	}
+	if r.weight >= 100 {
+		return true
+	}
+	u, ok := request.UserFrom(req.Context())
+	if !ok || u.GetName() == "" {
+		return rand.Intn(100) < r.weight // nolint:gosec
+	}
+	return canaryBucket(u.GetName()) < r.weight
+}
+
+// canaryBucket maps a user name to one of 100 buckets.
+func canaryBucket(name string) int {
+	h := fnv.New32a()
+	h.Write([]byte(name)) // nolint:errcheck
+	return int(h.Sum32() % 100)
+}
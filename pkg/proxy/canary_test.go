@@ -0,0 +1,119 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+func TestCanaryBackendValidate(t *testing.T) {
+	tests := map[string]struct {
+		path    string
+		canary  CanaryBackend
+		wantErr bool
+	}{
+		"valid":            {path: "/services/", canary: CanaryBackend{Backend: "https://canary:6444", Weight: 10}},
+		"not a vw path":    {path: "/", canary: CanaryBackend{Backend: "https://canary:6443", Weight: 10}, wantErr: true},
+		"no backend":       {path: "/services/", canary: CanaryBackend{Weight: 10}, wantErr: true},
+		"negative weight":  {path: "/services/", canary: CanaryBackend{Backend: "https://canary:6444", Weight: -1}, wantErr: true},
+		"weight above 100": {path: "/services/", canary: CanaryBackend{Backend: "https://canary:6444", Weight: 101}, wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tt.canary.validate(tt.path)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCanaryRouter(t *testing.T) {
+	backend := func(name string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			require.Empty(t, req.Header.Get(DefaultCanaryHeader), "the canary header must not be forwarded")
+			w.Header().Set("Backend", name)
+		})
+	}
+	serve := func(r *canaryRouter, userName, header string) string {
+		req := httptest.NewRequest(http.MethodGet, "/services/syncer/root/apis", nil)
+		if userName != "" {
+			req = req.WithContext(request.WithUser(req.Context(), &user.DefaultInfo{Name: userName}))
+		}
+		if header != "" {
+			req.Header.Set(DefaultCanaryHeader, header)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Header().Get("Backend")
+	}
+
+	tests := map[string]struct {
+		weight int
+		user   string
+		header string
+		want   string
+	}{
+		"weight 0":                  {weight: 0, user: "alice", want: "stable"},
+		"weight 100":                {weight: 100, user: "alice", want: "canary"},
+		"header selects canary":     {weight: 0, user: "alice", header: "true", want: "canary"},
+		"header selects stable":     {weight: 100, user: "alice", header: "false", want: "stable"},
+		"invalid header is ignored": {weight: 100, user: "alice", header: "maybe", want: "canary"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := newCanaryRouter("/services/", &CanaryBackend{Weight: tt.weight}, backend("stable"), backend("canary"))
+			require.Equal(t, tt.want, serve(r, tt.user, tt.header))
+		})
+	}
+
+	t.Run("users stick to a build", func(t *testing.T) {
+		r := newCanaryRouter("/services/", &CanaryBackend{Weight: 30}, backend("stable"), backend("canary"))
+		canaries := 0
+		for i := 0; i < 1000; i++ {
+			name := fmt.Sprintf("user-%d", i)
+			got := serve(r, name, "")
+			for j := 0; j < 3; j++ {
+				require.Equal(t, got, serve(r, name, ""), "user %s switched builds", name)
+			}
+			if got == "canary" {
+				canaries++
+			}
+		}
+		require.InDelta(t, 300, canaries, 60)
+	})
+
+	t.Run("custom header", func(t *testing.T) {
+		r := newCanaryRouter("/services/", &CanaryBackend{Header: "X-Build"}, backend("stable"), backend("canary"))
+		req := httptest.NewRequest(http.MethodGet, "/services/syncer/root/apis", nil)
+		req.Header.Set("X-Build", "true")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		require.Equal(t, "canary", w.Header().Get("Backend"))
+		require.Empty(t, req.Header.Get("X-Build"))
+	})
+}
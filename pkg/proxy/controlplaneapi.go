@@ -0,0 +1,358 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/transport"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	kcpclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
+	controlplanev1alpha1 "github.com/kcp-dev/kcp/pkg/proxy/controlplane/v1alpha1"
+	proxyoptions "github.com/kcp-dev/kcp/pkg/proxy/options"
+)
+
+// ControlPlaneServiceName is the name of the gRPC service of the control plane API.
+const ControlPlaneServiceName = "kcp.proxy.v1alpha1.ControlPlane"
+
+// controlPlaneAPI serves a narrow gRPC surface for programmatic clients like portals, for
+// which REST and discovery are too heavy: resolving workspaces, listing their APIBindings
+// and streaming their events. The service is defined in
+// pkg/proxy/controlplane/v1alpha1/controlplane.proto. Requests are made on behalf of the
+// user authenticated by the proxy, by impersonation.
+//
+// The requests go to the root shard only, as the proxy does not know the credentials for
+// other shards. Workspaces on other shards are resolved if their parent is on the root
+// shard, but their bindings and events are not served.
+type controlPlaneAPI struct {
+	controlplanev1alpha1.UnimplementedControlPlaneServer
+
+	// rootShard is the name of the ClusterWorkspaceShard the requests go to.
+	rootShard string
+
+	getWorkspace func(ctx context.Context, parent logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error)
+	listBindings func(ctx context.Context, cluster logicalcluster.Name) ([]apisv1alpha1.APIBinding, error)
+	watchEvents  func(ctx context.Context, cluster logicalcluster.Name) (watch.Interface, error)
+}
+
+var _ controlplanev1alpha1.ControlPlaneServer = &controlPlaneAPI{}
+
+// NewControlPlaneAPI returns the handler of the gRPC control plane API, which makes requests
+// with the root kubeconfig, impersonating the users authenticated by the proxy.
+func NewControlPlaneAPI(o *proxyoptions.Options) (http.Handler, error) {
+	config, err := clientcmd.BuildConfigFromFlags("", o.RootKubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load root kubeconfig %q: %w", o.RootKubeconfig, err)
+	}
+	config, err = impersonatingConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	// the clients share one transport for all users, which are impersonated per request
+	kcpClusterClient, err := kcpclient.NewClusterForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	kubeClusterClient, err := kubernetes.NewClusterForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	api := &controlPlaneAPI{
+		rootShard: o.RootShardName,
+		getWorkspace: func(ctx context.Context, parent logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error) {
+			return kcpClusterClient.Cluster(parent).TenancyV1alpha1().ClusterWorkspaces().Get(ctx, name, metav1.GetOptions{})
+		},
+		listBindings: func(ctx context.Context, cluster logicalcluster.Name) ([]apisv1alpha1.APIBinding, error) {
+			bindings, err := kcpClusterClient.Cluster(cluster).ApisV1alpha1().APIBindings().List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			return bindings.Items, nil
+		},
+		watchEvents: func(ctx context.Context, cluster logicalcluster.Name) (watch.Interface, error) {
+			return kubeClusterClient.Cluster(cluster).CoreV1().Events(metav1.NamespaceAll).Watch(ctx, metav1.ListOptions{})
+		},
+	}
+
+	server := grpc.NewServer()
+	controlplanev1alpha1.RegisterControlPlaneServer(server, api)
+	return server, nil
+}
+
+// impersonatingConfig returns a copy of the given config that impersonates the user in the
+// context of each request.
+func impersonatingConfig(config *rest.Config) (*rest.Config, error) {
+	if config.Impersonate.UserName != "" || config.Impersonate.UID != "" || len(config.Impersonate.Groups) > 0 || len(config.Impersonate.Extra) > 0 {
+		return nil, fmt.Errorf("the root kubeconfig must not impersonate")
+	}
+	config = rest.CopyConfig(config)
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &impersonatingRoundTripper{delegate: rt}
+	})
+	return config, nil
+}
+
+// impersonatingRoundTripper sets the impersonation headers for the user in the request
+// context, so that requests of all users can share one client and transport.
+type impersonatingRoundTripper struct {
+	delegate http.RoundTripper
+}
+
+func (rt *impersonatingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	u, ok := request.UserFrom(req.Context())
+	if !ok {
+		return nil, fmt.Errorf("no user to impersonate for %s %s", req.Method, req.URL.Path)
+	}
+	return transport.NewImpersonatingRoundTripper(transport.ImpersonationConfig{
+		UserName: u.GetName(),
+		UID:      u.GetUID(),
+		Groups:   u.GetGroups(),
+		Extra:    u.GetExtra(),
+	}, rt.delegate).RoundTrip(req)
+}
+
+// WithControlPlaneAPI serves gRPC requests with the given control plane API handler, and
+// passes all other requests to the given handler.
+func WithControlPlaneAPI(handler, api http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.ProtoMajor == 2 && strings.HasPrefix(req.Header.Get("Content-Type"), "application/grpc") {
+			api.ServeHTTP(w, req)
+			return
+		}
+		handler.ServeHTTP(w, req)
+	})
+}
+
+// ResolveWorkspace returns the logical cluster, phase, type, shard and URL of the given
+// workspace, e.g. root:org:team.
+func (a *controlPlaneAPI) ResolveWorkspace(ctx context.Context, req *controlplanev1alpha1.ResolveWorkspaceRequest) (*controlplanev1alpha1.Workspace, error) {
+	cluster, err := authorizeWorkspace(ctx, req.GetWorkspace())
+	if err != nil {
+		return nil, err
+	}
+
+	if _, hasParent := cluster.Parent(); !hasParent {
+		return nil, status.Errorf(codes.InvalidArgument, "workspace %q has no parent workspace", cluster)
+	}
+	ws, err := a.getWorkspaceOfRootShard(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	return &controlplanev1alpha1.Workspace{
+		Cluster: cluster.String(),
+		Phase:   string(ws.Status.Phase),
+		Type:    ws.Spec.Type,
+		Shard:   ws.Status.Location.Current,
+		Url:     ws.Status.BaseURL,
+	}, nil
+}
+
+// ListBindings returns the APIBindings of the given workspace with their exports, phases and
+// bound resources.
+func (a *controlPlaneAPI) ListBindings(ctx context.Context, req *controlplanev1alpha1.ListBindingsRequest) (*controlplanev1alpha1.ListBindingsResponse, error) {
+	cluster, err := authorizeWorkspace(ctx, req.GetWorkspace())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.requireRootShard(ctx, cluster); err != nil {
+		return nil, err
+	}
+
+	bindings, err := a.listBindings(ctx, cluster)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &controlplanev1alpha1.ListBindingsResponse{Bindings: make([]*controlplanev1alpha1.APIBinding, 0, len(bindings))}
+	for _, binding := range bindings {
+		var export string
+		if ref := binding.Spec.Reference.Workspace; ref != nil {
+			export = ref.WorkspaceName + ":" + ref.ExportName
+		}
+		resources := make([]string, 0, len(binding.Status.BoundResources))
+		for _, r := range binding.Status.BoundResources {
+			resources = append(resources, strings.TrimSuffix(r.Resource+"."+r.Group, "."))
+		}
+		resp.Bindings = append(resp.Bindings, &controlplanev1alpha1.APIBinding{
+			Name:           binding.Name,
+			Export:         export,
+			Phase:          string(binding.Status.Phase),
+			BoundResources: resources,
+		})
+	}
+	return resp, nil
+}
+
+// StreamEvents streams the core Events of all namespaces of the given workspace until the
+// client cancels or the backend ends the watch.
+func (a *controlPlaneAPI) StreamEvents(req *controlplanev1alpha1.StreamEventsRequest, stream controlplanev1alpha1.ControlPlane_StreamEventsServer) error {
+	ctx := stream.Context()
+	cluster, err := authorizeWorkspace(ctx, req.GetWorkspace())
+	if err != nil {
+		return err
+	}
+
+	if err := a.requireRootShard(ctx, cluster); err != nil {
+		return err
+	}
+
+	w, err := a.watchEvents(ctx, cluster)
+	if err != nil {
+		return toStatus(err)
+	}
+	defer w.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return status.Error(codes.Canceled, ctx.Err().Error())
+		case e, ok := <-w.ResultChan():
+			if !ok {
+				return nil
+			}
+			if e.Type == watch.Error {
+				return toStatus(apierrors.FromObject(e.Object))
+			}
+			event, ok := e.Object.(*corev1.Event)
+			if !ok {
+				continue
+			}
+			if err := stream.Send(&controlplanev1alpha1.Event{
+				Type:      string(e.Type),
+				Namespace: event.Namespace,
+				Name:      event.Name,
+				EventType: event.Type,
+				Reason:    event.Reason,
+				Message:   event.Message,
+				InvolvedObject: &controlplanev1alpha1.ObjectReference{
+					ApiVersion: event.InvolvedObject.APIVersion,
+					Kind:       event.InvolvedObject.Kind,
+					Namespace:  event.InvolvedObject.Namespace,
+					Name:       event.InvolvedObject.Name,
+				},
+			}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// getWorkspaceOfRootShard returns the ClusterWorkspace of the given logical cluster, which
+// must have a parent. The ancestors of the workspace but the root workspace are looked up
+// from the top, and must be on the root shard, as their children could not be looked up
+// otherwise.
+func (a *controlPlaneAPI) getWorkspaceOfRootShard(ctx context.Context, cluster logicalcluster.Name) (*tenancyv1alpha1.ClusterWorkspace, error) {
+	var path []logicalcluster.Name
+	for c, ok := cluster, true; ok; c, ok = c.Parent() {
+		path = append(path, c)
+	}
+
+	var ws *tenancyv1alpha1.ClusterWorkspace
+	for i := len(path) - 2; i >= 0; i-- {
+		parent, name := path[i].Split()
+		var err error
+		if ws, err = a.getWorkspace(ctx, parent, name); err != nil {
+			return nil, toStatus(err)
+		}
+		if i > 0 {
+			if err := a.checkShard(path[i], ws); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return ws, nil
+}
+
+// requireRootShard returns an error unless the given logical cluster is on the root shard.
+func (a *controlPlaneAPI) requireRootShard(ctx context.Context, cluster logicalcluster.Name) error {
+	if _, hasParent := cluster.Parent(); !hasParent {
+		// the root workspace
+		return nil
+	}
+	ws, err := a.getWorkspaceOfRootShard(ctx, cluster)
+	if err != nil {
+		return err
+	}
+	return a.checkShard(cluster, ws)
+}
+
+func (a *controlPlaneAPI) checkShard(cluster logicalcluster.Name, ws *tenancyv1alpha1.ClusterWorkspace) error {
+	if shard := ws.Status.Location.Current; shard != "" && shard != a.rootShard {
+		return status.Errorf(codes.FailedPrecondition, "workspace %q is on shard %q, only workspaces on shard %q are served", cluster, shard, a.rootShard)
+	}
+	return nil
+}
+
+// authorizeWorkspace returns the logical cluster of the given workspace, if the user of the
+// request is authenticated and, for users scoped to workspaces by their client certificate,
+// the workspace is in their scope. Everything else is authorized by the backend.
+func authorizeWorkspace(ctx context.Context, workspace string) (logicalcluster.Name, error) {
+	u, ok := request.UserFrom(ctx)
+	if !ok {
+		return logicalcluster.Name{}, status.Error(codes.Unauthenticated, "a client certificate is required")
+	}
+	if !reClusterName.MatchString(workspace) {
+		return logicalcluster.Name{}, status.Errorf(codes.InvalidArgument, "invalid workspace %q", workspace)
+	}
+	cluster := logicalcluster.New(workspace)
+	if scopes, scoped := u.GetExtra()[WorkspaceScopeExtraKey]; scoped && !inWorkspaceScope(cluster, scopes) {
+		return logicalcluster.Name{}, status.Errorf(codes.PermissionDenied, "user %q is restricted to the workspaces %s by its client certificate", u.GetName(), strings.Join(scopes, ", "))
+	}
+	return cluster, nil
+}
+
+// toStatus converts errors of the backend to gRPC status errors.
+func toStatus(err error) error {
+	code := codes.Internal
+	switch {
+	case apierrors.IsNotFound(err):
+		code = codes.NotFound
+	case apierrors.IsForbidden(err):
+		code = codes.PermissionDenied
+	case apierrors.IsUnauthorized(err):
+		code = codes.Unauthenticated
+	case apierrors.IsBadRequest(err), apierrors.IsInvalid(err):
+		code = codes.InvalidArgument
+	case apierrors.IsTooManyRequests(err):
+		code = codes.ResourceExhausted
+	case apierrors.IsServiceUnavailable(err), apierrors.IsTimeout(err), apierrors.IsServerTimeout(err):
+		code = codes.Unavailable
+	}
+	return status.Error(code, err.Error())
+}
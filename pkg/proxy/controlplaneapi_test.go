@@ -0,0 +1,420 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/rest"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	controlplanev1alpha1 "github.com/kcp-dev/kcp/pkg/proxy/controlplane/v1alpha1"
+)
+
+// testWorkspaces are the workspaces by parent and name. root:org:team is on another shard
+// than the root shard, and so is root:remote.
+var testWorkspaces = map[string]*tenancyv1alpha1.ClusterWorkspace{
+	"root|org": {
+		ObjectMeta: metav1.ObjectMeta{Name: "org"},
+		Status:     tenancyv1alpha1.ClusterWorkspaceStatus{Location: tenancyv1alpha1.ClusterWorkspaceLocation{Current: "root"}},
+	},
+	"root:org|team": {
+		ObjectMeta: metav1.ObjectMeta{Name: "team"},
+		Spec:       tenancyv1alpha1.ClusterWorkspaceSpec{Type: "Universal"},
+		Status: tenancyv1alpha1.ClusterWorkspaceStatus{
+			Phase:    tenancyv1alpha1.ClusterWorkspacePhaseReady,
+			BaseURL:  "https://shard-1/clusters/root:org:team",
+			Location: tenancyv1alpha1.ClusterWorkspaceLocation{Current: "shard-1"},
+		},
+	},
+	"root:org|local": {
+		ObjectMeta: metav1.ObjectMeta{Name: "local"},
+		Status:     tenancyv1alpha1.ClusterWorkspaceStatus{Location: tenancyv1alpha1.ClusterWorkspaceLocation{Current: "root"}},
+	},
+	"root|remote": {
+		ObjectMeta: metav1.ObjectMeta{Name: "remote"},
+		Status:     tenancyv1alpha1.ClusterWorkspaceStatus{Location: tenancyv1alpha1.ClusterWorkspaceLocation{Current: "shard-2"}},
+	},
+}
+
+var testWorkspaceAPI = &controlPlaneAPI{
+	rootShard: "root",
+	getWorkspace: func(ctx context.Context, parent logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error) {
+		ws, ok := testWorkspaces[parent.String()+"|"+name]
+		if !ok {
+			return nil, apierrors.NewNotFound(tenancyv1alpha1.Resource("clusterworkspaces"), name)
+		}
+		if u, _ := request.UserFrom(ctx); u.GetName() != "alice" {
+			return nil, apierrors.NewForbidden(tenancyv1alpha1.Resource("clusterworkspaces"), name, nil)
+		}
+		return ws, nil
+	},
+}
+
+var testTeamWorkspace = &controlplanev1alpha1.Workspace{
+	Cluster: "root:org:team",
+	Phase:   "Ready",
+	Type:    "Universal",
+	Shard:   "shard-1",
+	Url:     "https://shard-1/clusters/root:org:team",
+}
+
+func TestControlPlaneAPIResolveWorkspace(t *testing.T) {
+	alice := &user.DefaultInfo{Name: "alice"}
+	tests := map[string]struct {
+		user      user.Info
+		workspace string
+		wantCode  codes.Code
+		want      *controlplanev1alpha1.Workspace
+	}{
+		"resolved":              {user: alice, workspace: "root:org:team", want: testTeamWorkspace},
+		"unauthenticated":       {workspace: "root:org:team", wantCode: codes.Unauthenticated},
+		"invalid workspace":     {user: alice, workspace: "root:Org", wantCode: codes.InvalidArgument},
+		"wildcard":              {user: alice, workspace: "*", wantCode: codes.InvalidArgument},
+		"root":                  {user: alice, workspace: "root", wantCode: codes.InvalidArgument},
+		"not found":             {user: alice, workspace: "root:org:other", wantCode: codes.NotFound},
+		"parent on other shard": {user: alice, workspace: "root:remote:team", wantCode: codes.FailedPrecondition},
+		"forbidden":             {user: &user.DefaultInfo{Name: "bob"}, workspace: "root:org:team", wantCode: codes.PermissionDenied},
+		"in scope": {
+			user:      &user.DefaultInfo{Name: "alice", Extra: map[string][]string{WorkspaceScopeExtraKey: {"root:org"}}},
+			workspace: "root:org:team",
+			want:      testTeamWorkspace,
+		},
+		"out of scope": {
+			user:      &user.DefaultInfo{Name: "alice", Extra: map[string][]string{WorkspaceScopeExtraKey: {"root:other"}}},
+			workspace: "root:org:team",
+			wantCode:  codes.PermissionDenied,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.user != nil {
+				ctx = request.WithUser(ctx, tt.user)
+			}
+			got, err := testWorkspaceAPI.ResolveWorkspace(ctx, &controlplanev1alpha1.ResolveWorkspaceRequest{Workspace: tt.workspace})
+			if tt.wantCode != codes.OK {
+				require.Equal(t, tt.wantCode, status.Code(err), "unexpected error: %v", err)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, proto.Equal(tt.want, got), "unexpected workspace: %v", got)
+		})
+	}
+}
+
+func TestControlPlaneAPIListBindings(t *testing.T) {
+	api := &controlPlaneAPI{
+		rootShard:    testWorkspaceAPI.rootShard,
+		getWorkspace: testWorkspaceAPI.getWorkspace,
+		listBindings: func(ctx context.Context, cluster logicalcluster.Name) ([]apisv1alpha1.APIBinding, error) {
+			require.Equal(t, logicalcluster.New("root:org:local"), cluster)
+			return []apisv1alpha1.APIBinding{{
+				ObjectMeta: metav1.ObjectMeta{Name: "widgets"},
+				Spec: apisv1alpha1.APIBindingSpec{Reference: apisv1alpha1.ExportReference{
+					Workspace: &apisv1alpha1.WorkspaceExportReference{WorkspaceName: "providers", ExportName: "widgets"},
+				}},
+				Status: apisv1alpha1.APIBindingStatus{
+					Phase: apisv1alpha1.APIBindingPhaseBound,
+					BoundResources: []apisv1alpha1.BoundAPIResource{
+						{Group: "widgets.example.com", Resource: "widgets"},
+						{Resource: "gadgets"},
+					},
+				},
+			}}, nil
+		},
+	}
+
+	ctx := request.WithUser(context.Background(), &user.DefaultInfo{Name: "alice"})
+	got, err := api.ListBindings(ctx, &controlplanev1alpha1.ListBindingsRequest{Workspace: "root:org:local"})
+	require.NoError(t, err)
+	want := &controlplanev1alpha1.ListBindingsResponse{
+		Bindings: []*controlplanev1alpha1.APIBinding{{
+			Name:           "widgets",
+			Export:         "providers:widgets",
+			Phase:          "Bound",
+			BoundResources: []string{"widgets.widgets.example.com", "gadgets"},
+		}},
+	}
+	require.True(t, proto.Equal(want, got), "unexpected bindings: %v", got)
+
+	_, err = api.ListBindings(ctx, &controlplanev1alpha1.ListBindingsRequest{Workspace: "root:org:team"})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err), "workspaces on other shards should not be served: %v", err)
+}
+
+type fakeEventStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	events []*controlplanev1alpha1.Event
+}
+
+func (s *fakeEventStream) Context() context.Context { return s.ctx }
+
+func (s *fakeEventStream) Send(event *controlplanev1alpha1.Event) error {
+	s.events = append(s.events, event)
+	return nil
+}
+
+func TestControlPlaneAPIStreamEvents(t *testing.T) {
+	w := watch.NewFake()
+	api := &controlPlaneAPI{
+		rootShard:    testWorkspaceAPI.rootShard,
+		getWorkspace: testWorkspaceAPI.getWorkspace,
+		watchEvents: func(ctx context.Context, cluster logicalcluster.Name) (watch.Interface, error) {
+			return w, nil
+		},
+	}
+	go func() {
+		w.Add(&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Namespace: "default", Name: "widget.1"},
+			InvolvedObject: corev1.ObjectReference{APIVersion: "example.com/v1", Kind: "Widget", Namespace: "default", Name: "widget"},
+			Type:           corev1.EventTypeWarning,
+			Reason:         "Failed",
+			Message:        "it broke",
+		})
+		w.Stop()
+	}()
+
+	stream := &fakeEventStream{ctx: request.WithUser(context.Background(), &user.DefaultInfo{Name: "alice"})}
+	require.NoError(t, api.StreamEvents(&controlplanev1alpha1.StreamEventsRequest{Workspace: "root:org:local"}, stream))
+	require.Len(t, stream.events, 1)
+	want := &controlplanev1alpha1.Event{
+		Type:      "ADDED",
+		Namespace: "default",
+		Name:      "widget.1",
+		EventType: "Warning",
+		Reason:    "Failed",
+		Message:   "it broke",
+		InvolvedObject: &controlplanev1alpha1.ObjectReference{
+			ApiVersion: "example.com/v1",
+			Kind:       "Widget",
+			Namespace:  "default",
+			Name:       "widget",
+		},
+	}
+	require.True(t, proto.Equal(want, stream.events[0]), "unexpected event: %v", stream.events[0])
+}
+
+func TestControlPlaneAPIStreamEventsOfOtherShard(t *testing.T) {
+	api := &controlPlaneAPI{
+		rootShard:    testWorkspaceAPI.rootShard,
+		getWorkspace: testWorkspaceAPI.getWorkspace,
+		watchEvents: func(ctx context.Context, cluster logicalcluster.Name) (watch.Interface, error) {
+			t.Fatalf("events of %s should not have been watched", cluster)
+			return nil, nil
+		},
+	}
+
+	stream := &fakeEventStream{ctx: request.WithUser(context.Background(), &user.DefaultInfo{Name: "alice"})}
+	err := api.StreamEvents(&controlplanev1alpha1.StreamEventsRequest{Workspace: "root:org:team"}, stream)
+	require.Equal(t, codes.FailedPrecondition, status.Code(err), "unexpected error: %v", err)
+}
+
+func TestControlPlaneAPIStreamEventsError(t *testing.T) {
+	w := watch.NewFake()
+	api := &controlPlaneAPI{
+		rootShard:    testWorkspaceAPI.rootShard,
+		getWorkspace: testWorkspaceAPI.getWorkspace,
+		watchEvents: func(ctx context.Context, cluster logicalcluster.Name) (watch.Interface, error) {
+			return w, nil
+		},
+	}
+	go w.Error(&apierrors.NewResourceExpired("too old").ErrStatus)
+
+	stream := &fakeEventStream{ctx: request.WithUser(context.Background(), &user.DefaultInfo{Name: "alice"})}
+	err := api.StreamEvents(&controlplanev1alpha1.StreamEventsRequest{Workspace: "root:org:local"}, stream)
+	require.Error(t, err)
+	require.Empty(t, stream.events)
+}
+
+func TestToStatus(t *testing.T) {
+	gr := schema.GroupResource{Resource: "widgets"}
+	tests := map[string]struct {
+		err  error
+		want codes.Code
+	}{
+		"not found":    {err: apierrors.NewNotFound(gr, "a"), want: codes.NotFound},
+		"forbidden":    {err: apierrors.NewForbidden(gr, "a", nil), want: codes.PermissionDenied},
+		"unauthorized": {err: apierrors.NewUnauthorized("no"), want: codes.Unauthenticated},
+		"bad request":  {err: apierrors.NewBadRequest("no"), want: codes.InvalidArgument},
+		"throttled":    {err: apierrors.NewTooManyRequests("slow down", 1), want: codes.ResourceExhausted},
+		"unavailable":  {err: apierrors.NewServiceUnavailable("later"), want: codes.Unavailable},
+		"other":        {err: apierrors.NewInternalError(nil), want: codes.Internal},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tt.want, status.Code(toStatus(tt.err)))
+		})
+	}
+}
+
+func TestWithControlPlaneAPI(t *testing.T) {
+	api := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) { w.WriteHeader(http.StatusTeapot) })
+	rest := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) { w.WriteHeader(http.StatusOK) })
+	handler := WithControlPlaneAPI(rest, api)
+
+	tests := map[string]struct {
+		protoMajor  int
+		contentType string
+		want        int
+	}{
+		"grpc":         {protoMajor: 2, contentType: "application/grpc", want: http.StatusTeapot},
+		"grpc+proto":   {protoMajor: 2, contentType: "application/grpc+proto", want: http.StatusTeapot},
+		"http/2 json":  {protoMajor: 2, contentType: "application/json", want: http.StatusOK},
+		"http/1 grpc":  {protoMajor: 1, contentType: "application/grpc", want: http.StatusOK},
+		"http/1 plain": {protoMajor: 1, want: http.StatusOK},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/"+ControlPlaneServiceName+"/ResolveWorkspace", nil)
+			req.ProtoMajor = tt.protoMajor
+			req.Header.Set("Content-Type", tt.contentType)
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+			require.Equal(t, tt.want, rw.Code)
+		})
+	}
+}
+
+type userStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *userStream) Context() context.Context { return s.ctx }
+
+// TestControlPlaneAPIClient calls the API through gRPC with the generated client.
+func TestControlPlaneAPIClient(t *testing.T) {
+	api := &controlPlaneAPI{
+		rootShard:    testWorkspaceAPI.rootShard,
+		getWorkspace: testWorkspaceAPI.getWorkspace,
+		watchEvents: func(ctx context.Context, cluster logicalcluster.Name) (watch.Interface, error) {
+			w := watch.NewFake()
+			go func() {
+				w.Add(&corev1.Event{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "widget.1"}, Reason: "Failed"})
+				w.Stop()
+			}()
+			return w, nil
+		},
+	}
+
+	// the proxy authenticates users before the gRPC server, which is replaced by interceptors here
+	withUser := func(ctx context.Context) context.Context {
+		return request.WithUser(ctx, &user.DefaultInfo{Name: "alice"})
+	}
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			return handler(withUser(ctx), req)
+		}),
+		grpc.StreamInterceptor(func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			return handler(srv, &userStream{ServerStream: ss, ctx: withUser(ss.Context())})
+		}),
+	)
+	controlplanev1alpha1.RegisterControlPlaneServer(server, api)
+	lis := bufconn.Listen(1024 * 1024)
+	go server.Serve(lis) // nolint: errcheck
+	defer server.Stop()
+
+	conn, err := grpc.Dial("bufnet", grpc.WithInsecure(), grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+		return lis.Dial()
+	}))
+	require.NoError(t, err)
+	defer conn.Close()
+	client := controlplanev1alpha1.NewControlPlaneClient(conn)
+
+	got, err := client.ResolveWorkspace(context.Background(), &controlplanev1alpha1.ResolveWorkspaceRequest{Workspace: "root:org:team"})
+	require.NoError(t, err)
+	require.True(t, proto.Equal(testTeamWorkspace, got), "unexpected workspace: %v", got)
+
+	_, err = client.ResolveWorkspace(context.Background(), &controlplanev1alpha1.ResolveWorkspaceRequest{Workspace: "root:org:other"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	stream, err := client.StreamEvents(context.Background(), &controlplanev1alpha1.StreamEventsRequest{Workspace: "root:org:local"})
+	require.NoError(t, err)
+	event, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, "widget.1", event.GetName())
+	require.Equal(t, "Failed", event.GetReason())
+	_, err = stream.Recv()
+	require.Equal(t, io.EOF, err, "the stream ends with the watch")
+}
+
+func TestImpersonatingConfig(t *testing.T) {
+	type impersonation struct {
+		user, uid string
+		groups    []string
+		scope     []string
+	}
+	requests := make(chan impersonation, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests <- impersonation{
+			user:   req.Header.Get("Impersonate-User"),
+			uid:    req.Header.Get("Impersonate-Uid"),
+			groups: req.Header.Values("Impersonate-Group"),
+			scope:  req.Header.Values("Impersonate-Extra-authentication.kcp.dev%2Fworkspace-scope"),
+		}
+	}))
+	defer server.Close()
+
+	config, err := impersonatingConfig(&rest.Config{Host: server.URL})
+	require.NoError(t, err)
+	rt, err := rest.TransportFor(config)
+	require.NoError(t, err)
+
+	users := []*user.DefaultInfo{
+		{Name: "alice", UID: "1", Groups: []string{"a", "b"}},
+		{Name: "bob", Extra: map[string][]string{WorkspaceScopeExtraKey: {"root:org"}}},
+	}
+	for _, u := range users {
+		req, err := http.NewRequestWithContext(request.WithUser(context.Background(), u), http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+		resp, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+	require.Equal(t, impersonation{user: "alice", uid: "1", groups: []string{"a", "b"}}, <-requests)
+	require.Equal(t, impersonation{user: "bob", scope: []string{"root:org"}}, <-requests, "the transport is shared, the headers are not")
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	_, err = rt.RoundTrip(req) // nolint: bodyclose
+	require.Error(t, err, "requests without user are not sent")
+
+	_, err = impersonatingConfig(&rest.Config{Host: server.URL, Impersonate: rest.ImpersonationConfig{UserName: "admin"}})
+	require.Error(t, err)
+}
@@ -19,6 +19,10 @@ limitations under the License.
 // headers. The proxy terminates client TLS and communicates with API servers
 // via mTLS. Traffic is routed based on paths.
 //
+// The proxy client certificate, key and backend server CA are watched on
+// disk and reloaded without restart, so they can be rotated in place. See
+// docs/certificate-rotation.md for the rotation of the other certificates.
+//
 // An example configuration:
 //
 //  - path: /services/
@@ -31,5 +35,8 @@ limitations under the License.
 //    backend_server_ca: certs/kcp-ca-cert.pem
 //    proxy_client_cert: certs/proxy-client-cert.pem
 //    proxy_client_key: certs/proxy-client-key.pem
+//
+// Mappings under /services/ can name a canary backend, a second build of the
+// virtual workspace server serving a share of the users, see CanaryBackend.
 
 package proxy
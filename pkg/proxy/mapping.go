@@ -17,13 +17,18 @@ limitations under the License.
 package proxy
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/yaml"
 
+	"github.com/kcp-dev/kcp/pkg/faultinjection"
 	proxyoptions "github.com/kcp-dev/kcp/pkg/proxy/options"
 )
 
@@ -38,9 +43,15 @@ type PathMapping struct {
 	ProxyClientKey  string `json:"proxy_client_key"`
 	UserHeader      string `json:"user_header,omitempty"`
 	GroupHeader     string `json:"group_header,omitempty"`
+	// Shard is the name of the ClusterWorkspaceShard of the backend. If set, requests for
+	// APIs that only upgraded shards serve are not forwarded while the shard is not upgraded.
+	Shard string `json:"shard,omitempty"`
+	// Canary is a second build of the virtual workspace served under Path, which serves
+	// a share of the requests. Only supported for paths under /services/.
+	Canary *CanaryBackend `json:"canary,omitempty"`
 }
 
-func NewHandler(o *proxyoptions.Options) (http.Handler, error) {
+func NewHandler(ctx context.Context, o *proxyoptions.Options) (http.Handler, error) {
 	mappingData, err := ioutil.ReadFile(o.MappingFile)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read mapping file %q: %w", o.MappingFile, err)
@@ -51,13 +62,40 @@ func NewHandler(o *proxyoptions.Options) (http.Handler, error) {
 		return nil, fmt.Errorf("failed to unmarshal mapping file %q: %w", o.MappingFile, err)
 	}
 
+	var partitions *faultinjection.Partitions
+	if o.EnableFaultInjection {
+		klog.Warningf("Fault injection is enabled, the proxy can be partitioned from backends through %s", faultinjection.PartitionsDebugPath)
+		partitions = faultinjection.NewPartitions()
+		faultinjection.RegisterMetrics()
+	}
+
+	for _, m := range mapping {
+		if m.Canary == nil {
+			continue
+		}
+		if err := m.Canary.validate(m.Path); err != nil {
+			return nil, err
+		}
+	}
+
+	var shardAPIs *shardAPIGate
+	for _, m := range mapping {
+		if m.Shard == "" {
+			continue
+		}
+		if o.RootKubeconfig == "" {
+			return nil, fmt.Errorf("path mapping %q names shard %q, which requires --root-kubeconfig", m.Path, m.Shard)
+		}
+		if shardAPIs == nil {
+			if shardAPIs, err = newShardAPIGate(ctx, o); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	mux := http.NewServeMux()
 	for _, m := range mapping {
 		klog.V(2).Infof("Adding mapping %v", m)
-		proxy, err := NewReverseProxy(m.Backend, m.ProxyClientCert, m.ProxyClientKey, m.BackendServerCA)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create path mapping for path %q: %w", m.Path, err)
-		}
 		userHeader := "X-Remote-User"
 		groupHeader := "X-Remote-Group"
 		if m.UserHeader != "" {
@@ -66,8 +104,74 @@ func NewHandler(o *proxyoptions.Options) (http.Handler, error) {
 		if m.GroupHeader != "" {
 			groupHeader = m.GroupHeader
 		}
-		mux.Handle(m.Path, http.HandlerFunc(ProxyHandler(proxy, userHeader, groupHeader)))
+		newBackendHandler := func(backend, clientCert, clientKey, serverCA string) (http.Handler, error) {
+			proxy, err := NewReverseProxy(ctx, backend, clientCert, clientKey, serverCA)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create path mapping for path %q: %w", m.Path, err)
+			}
+			if partitions != nil {
+				proxy.proxy.Transport = partitions.WrapTransport(backend, proxy.proxy.Transport)
+			}
+			return http.HandlerFunc(ProxyHandler(proxy, userHeader, groupHeader)), nil
+		}
+
+		handler, err := newBackendHandler(m.Backend, m.ProxyClientCert, m.ProxyClientKey, m.BackendServerCA)
+		if err != nil {
+			return nil, err
+		}
+		if c := m.Canary; c != nil {
+			canary, err := newBackendHandler(c.Backend, c.ProxyClientCert, c.ProxyClientKey, c.BackendServerCA)
+			if err != nil {
+				return nil, err
+			}
+			klog.V(2).Infof("Forwarding %d%% of the users of %s to the canary %s", c.Weight, m.Path, c.Backend)
+			handler = newCanaryRouter(m.Path, c, handler, canary)
+		}
+		if m.Shard != "" {
+			handler = shardAPIs.WithShard(m.Shard, handler)
+		}
+		mux.Handle(m.Path, handler)
+	}
+
+	if partitions != nil {
+		mux.Handle(faultinjection.PartitionsDebugPath, withPrivilegedUser(partitions))
+	}
+
+	var handler http.Handler = mux
+	if o.RootKubeconfig != "" {
+		if handler, err = newVirtualWorkspaceRouter(ctx, mux, mapping, o); err != nil {
+			return nil, err
+		}
+	}
+
+	if o.SessionRecordingFile != "" {
+		store, err := NewFileRecordStore(o.SessionRecordingFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open session recording file %q: %w", o.SessionRecordingFile, err)
+		}
+		go func() {
+			<-ctx.Done()
+			store.Close() // nolint:errcheck
+		}()
+		handler = WithSessionRecording(handler, store, RecordingPolicy{
+			Groups:       o.SessionRecordingGroups,
+			Resources:    o.SessionRecordingResources,
+			MaxBodyBytes: o.SessionRecordingMaxBodyBytes,
+		})
 	}
 
-	return mux, nil
+	return handler, nil
+}
+
+// withPrivilegedUser only serves members of system:masters, as the proxy does not
+// authorize requests itself.
+func withPrivilegedUser(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		u, ok := request.UserFrom(req.Context())
+		if !ok || !sets.NewString(u.GetGroups()...).Has(user.SystemPrivilegedGroup) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		handler.ServeHTTP(w, req)
+	})
 }
@@ -24,15 +24,43 @@ import (
 
 type Options struct {
 	MappingFile string
+
+	RootKubeconfig                 string
+	VirtualWorkspaceClientCertFile string
+	VirtualWorkspaceClientKeyFile  string
+
+	EnableFaultInjection bool
+
+	EnableControlPlaneAPI bool
+	RootShardName         string
+
+	SessionRecordingFile         string
+	SessionRecordingGroups       []string
+	SessionRecordingResources    []string
+	SessionRecordingMaxBodyBytes int
 }
 
 func NewOptions() *Options {
-	o := &Options{}
+	o := &Options{
+		SessionRecordingGroups:       []string{"system:masters"},
+		SessionRecordingMaxBodyBytes: 64 * 1024,
+		RootShardName:                "root",
+	}
 	return o
 }
 
 func (o *Options) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&o.MappingFile, "mapping-file", o.MappingFile, "Config file mapping paths to backends")
+	fs.StringVar(&o.RootKubeconfig, "root-kubeconfig", o.RootKubeconfig, "Kubeconfig of the root workspace. If set, requests under /services/<name>/ are forwarded to the servers of the VirtualWorkspaces registered there.")
+	fs.StringVar(&o.VirtualWorkspaceClientCertFile, "virtual-workspace-client-cert-file", o.VirtualWorkspaceClientCertFile, "Client certificate the proxy authenticates with at the servers of VirtualWorkspaces.")
+	fs.StringVar(&o.VirtualWorkspaceClientKeyFile, "virtual-workspace-client-key-file", o.VirtualWorkspaceClientKeyFile, "Private key of --virtual-workspace-client-cert-file.")
+	fs.StringVar(&o.SessionRecordingFile, "session-recording-file", o.SessionRecordingFile, "Append-only file exec, attach and port-forward sessions and privileged write requests are recorded to. Nothing is recorded if empty.")
+	fs.StringSliceVar(&o.SessionRecordingGroups, "session-recording-groups", o.SessionRecordingGroups, "Groups of users authenticated by client certificate whose write requests are recorded.")
+	fs.StringSliceVar(&o.SessionRecordingResources, "session-recording-resources", o.SessionRecordingResources, "Resources of the form <resource>[.<group>], e.g. secrets or clusterrolebindings.rbac.authorization.k8s.io, whose write requests are recorded.")
+	fs.IntVar(&o.SessionRecordingMaxBodyBytes, "session-recording-max-body-bytes", o.SessionRecordingMaxBodyBytes, "Size up to which the bodies of recorded write requests are recorded.")
+	fs.BoolVar(&o.EnableControlPlaneAPI, "enable-control-plane-api", o.EnableControlPlaneAPI, "Serve the gRPC control plane API, resolving workspaces, listing their APIBindings and streaming their events on behalf of users authenticated by client certificate. Requires --root-kubeconfig, whose user must be allowed to impersonate.")
+	fs.StringVar(&o.RootShardName, "root-shard-name", o.RootShardName, "Name of the ClusterWorkspaceShard --root-kubeconfig points to. The control plane API only serves the workspaces on that shard.")
+	fs.BoolVar(&o.EnableFaultInjection, "enable-fault-injection", o.EnableFaultInjection, "Developer mode: serve /debug/kcp/partitions to partition the proxy from backends on demand, for resilience testing. Only members of system:masters can use it. Never enable in production.")
 }
 
 func (o *Options) Complete() error {
@@ -45,6 +73,18 @@ func (o *Options) Validate() []error {
 	if o.MappingFile == "" {
 		errs = append(errs, fmt.Errorf("--mapping-file is required"))
 	}
+	if o.RootKubeconfig != "" && (o.VirtualWorkspaceClientCertFile == "" || o.VirtualWorkspaceClientKeyFile == "") {
+		errs = append(errs, fmt.Errorf("--virtual-workspace-client-cert-file and --virtual-workspace-client-key-file are required with --root-kubeconfig"))
+	}
+	if o.EnableControlPlaneAPI && o.RootKubeconfig == "" {
+		errs = append(errs, fmt.Errorf("--root-kubeconfig is required with --enable-control-plane-api"))
+	}
+	if o.EnableControlPlaneAPI && o.RootShardName == "" {
+		errs = append(errs, fmt.Errorf("--root-shard-name must not be empty with --enable-control-plane-api"))
+	}
+	if o.SessionRecordingMaxBodyBytes < 0 {
+		errs = append(errs, fmt.Errorf("--session-recording-max-body-bytes must not be negative"))
+	}
 
 	return errs
 }
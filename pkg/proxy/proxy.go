@@ -17,9 +17,7 @@ limitations under the License.
 package proxy
 
 import (
-	"crypto/tls"
-	"crypto/x509"
-	"io/ioutil"
+	"context"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
@@ -38,35 +36,41 @@ type KCPProxy struct {
 // NewReverseProxy returns a new reverse proxy where backend is the backend URL to
 // connect to, clientCert is the proxy's client cert to use to connect to it,
 // clientKeyFile is the proxy's client private key file, and caFile is the CA
-// the proxy uses to verify the backend server's cert.
-func NewReverseProxy(backend, clientCert, clientKeyFile, caFile string) (*KCPProxy, error) {
+// the proxy uses to verify the backend server's cert. The client cert, key and
+// CA files are watched and reloaded on change until ctx is done.
+func NewReverseProxy(ctx context.Context, backend, clientCert, clientKeyFile, caFile string) (*KCPProxy, error) {
 	target, err := url.Parse(backend)
 	if err != nil {
 		return nil, err
 	}
 
-	caCert, err := ioutil.ReadFile(caFile)
+	transport, err := newDynamicTransport(ctx, clientCert, clientKeyFile, caFile)
 	if err != nil {
 		return nil, err
 	}
+	return newReverseProxy(target, transport), nil
+}
 
-	caCertPool := x509.NewCertPool()
-	caCertPool.AppendCertsFromPEM(caCert)
-
-	cert, err := tls.LoadX509KeyPair(clientCert, clientKeyFile)
+// NewReverseProxyWithCABundle is like NewReverseProxy, but verifies the backend
+// server's cert with the given PEM encoded CA bundle.
+func NewReverseProxyWithCABundle(ctx context.Context, backend, clientCert, clientKeyFile string, caBundle []byte) (*KCPProxy, error) {
+	target, err := url.Parse(backend)
 	if err != nil {
 		return nil, err
 	}
 
-	transport := http.DefaultTransport.(*http.Transport).Clone()
-	transport.TLSClientConfig = &tls.Config{
-		Certificates: []tls.Certificate{cert},
-		RootCAs:      caCertPool,
+	transport, err := newDynamicTransportWithCABundle(ctx, clientCert, clientKeyFile, caBundle)
+	if err != nil {
+		return nil, err
 	}
+	return newReverseProxy(target, transport), nil
+}
+
+func newReverseProxy(target *url.URL, transport http.RoundTripper) *KCPProxy {
 	proxy := httputil.NewSingleHostReverseProxy(target)
 	proxy.Transport = transport
 
-	return &KCPProxy{proxy: proxy, backend: backend}, nil
+	return &KCPProxy{proxy: proxy, backend: target.String()}
 }
 
 // ProxyHandler extracts the CN as a user name and Organizations as groups from
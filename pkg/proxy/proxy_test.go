@@ -0,0 +1,94 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	certutil "k8s.io/client-go/util/cert"
+
+	"github.com/kcp-dev/kcp/pkg/server/shutdown"
+)
+
+func TestReverseProxyWatchTermination(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the backend streams one event and then keeps the watch open
+	certPEM, keyPEM, err := certutil.GenerateSelfSignedCertKey("localhost", []net.IP{net.ParseIP("127.0.0.1")}, nil)
+	require.NoError(t, err)
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+	backend := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(&metav1.WatchEvent{ // nolint:errcheck
+			Type:   string(watch.Added),
+			Object: runtime.RawExtension{Raw: []byte(`{"kind":"ConfigMap","apiVersion":"v1","metadata":{"name":"a","resourceVersion":"1"}}`)},
+		})
+		w.(http.Flusher).Flush()
+		<-req.Context().Done()
+	}))
+	backend.TLS = &tls.Config{Certificates: []tls.Certificate{cert}}
+	backend.StartTLS()
+	defer backend.Close()
+
+	dir := t.TempDir()
+	writeClientCert(t, dir, "proxy")
+	writeFile(t, filepath.Join(dir, "ca.crt"), certPEM)
+	p, err := NewReverseProxy(ctx, backend.URL, filepath.Join(dir, "client.crt"), filepath.Join(dir, "client.key"), filepath.Join(dir, "ca.crt"))
+	require.NoError(t, err)
+
+	terminator := shutdown.NewWatchTerminator()
+	frontProxy := httptest.NewServer(terminator.WithWatchTermination(http.HandlerFunc(ProxyHandler(p, "X-Remote-User", "X-Remote-Group"))))
+	defer frontProxy.Close()
+
+	resp, err := http.Get(frontProxy.URL + "/clusters/root/api/v1/configmaps?watch=true")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	decoder := json.NewDecoder(resp.Body)
+
+	var event metav1.WatchEvent
+	require.NoError(t, decoder.Decode(&event))
+	require.Equal(t, string(watch.Added), event.Type)
+
+	terminator.Terminate(time.Second)
+
+	require.NoError(t, decoder.Decode(&event), "the proxied watch should end with an error event instead of an aborted stream")
+	require.Equal(t, string(watch.Error), event.Type)
+	var status metav1.Status
+	require.NoError(t, json.Unmarshal(event.Object.Raw, &status))
+	require.Equal(t, metav1.StatusReasonTooManyRequests, status.Reason)
+	_, err = ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return terminator.Active() == 0 }, wait.ForeverTestTimeout, 10*time.Millisecond)
+}
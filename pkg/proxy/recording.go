@@ -0,0 +1,280 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/klog/v2"
+)
+
+var sessionSubresources = sets.NewString("exec", "attach", "portforward")
+
+// RecordingPolicy selects the write requests recorded in addition to all exec, attach
+// and port-forward sessions.
+type RecordingPolicy struct {
+	// Groups of which the write requests of members are recorded.
+	Groups []string
+	// Resources of the form <resource>[.<group>] whose write requests are recorded.
+	Resources []string
+	// MaxBodyBytes is the size up to which request bodies are recorded.
+	MaxBodyBytes int
+}
+
+// RecordStore stores records in order.
+type RecordStore interface {
+	Append(record *Record) error
+}
+
+// WithSessionRecording records exec, attach and port-forward sessions, including the raw
+// bytes of their streams, and the write requests selected by the policy to the store.
+// Requests fail if they cannot be recorded.
+func WithSessionRecording(handler http.Handler, store RecordStore, policy RecordingPolicy) http.Handler {
+	groups := sets.NewString(policy.Groups...)
+	resources := sets.NewString(policy.Resources...)
+	infoFactory := newRecordingRequestInfoFactory()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		record := newRecord(req, infoFactory)
+
+		switch {
+		case record.Resource == "pods" && sessionSubresources.Has(record.Subresource):
+			record.Type = RecordTypeSessionStart
+			record.SessionID = string(uuid.NewUUID())
+			if err := store.Append(record); err != nil {
+				klog.Errorf("Failed to record session of %s %s: %v", req.Method, req.URL.Path, err)
+				http.Error(w, "session recording failed", http.StatusServiceUnavailable)
+				return
+			}
+
+			rw := &recordingResponseWriter{ResponseWriter: w, store: store, session: record}
+			handler.ServeHTTP(rw, req)
+
+			end := *record
+			end.Time = time.Now()
+			end.Type = RecordTypeSessionEnd
+			end.Status = rw.status
+			if err := store.Append(&end); err != nil {
+				klog.Errorf("Failed to record end of session %s: %v", record.SessionID, err)
+			}
+
+		case isWrite(req.Method) && (resources.Has(record.Resource) || hasAnyGroup(req, groups)):
+			record.Type = RecordTypeRequest
+			if req.Body != nil {
+				// only the recorded head of the body is buffered, the rest is streamed
+				// to the backend
+				body, err := ioutil.ReadAll(io.LimitReader(req.Body, int64(policy.MaxBodyBytes)+1))
+				if err != nil {
+					http.Error(w, "failed to read request body", http.StatusBadRequest)
+					return
+				}
+				req.Body = &readCloser{Reader: io.MultiReader(bytes.NewReader(body), req.Body), Closer: req.Body}
+				if len(body) > policy.MaxBodyBytes {
+					body, record.BodyTruncated = body[:policy.MaxBodyBytes], true
+				}
+				if len(body) > 0 {
+					record.Body = body
+				}
+			}
+
+			rw := &recordingResponseWriter{ResponseWriter: w}
+			handler.ServeHTTP(rw, req)
+
+			record.Status = rw.status
+			if err := store.Append(record); err != nil {
+				// the request has been served, i.e. it can only be logged
+				klog.Errorf("Failed to record %s %s of user %q: %v", req.Method, req.URL.Path, record.User, err)
+			}
+
+		default:
+			handler.ServeHTTP(w, req)
+		}
+	})
+}
+
+// readCloser reads from the reader and closes the closer.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+func newRecord(req *http.Request, infoFactory *request.RequestInfoFactory) *Record {
+	record := &Record{
+		Time: time.Now(),
+		Path: req.URL.Path,
+	}
+	if u, ok := request.UserFrom(req.Context()); ok {
+		record.User = u.GetName()
+		record.Groups = u.GetGroups()
+	} else if _, ok := bearerToken(req); ok {
+		record.Authorization = "bearer"
+	}
+
+	// the request info factory does not know about /clusters/<logical cluster>
+	path := req.URL.Path
+	if cluster, ok := clusterFromPath(path); ok {
+		record.Workspace = cluster.String()
+		path = strings.TrimPrefix(path, "/clusters/"+cluster.String())
+	}
+	shallow := req.Clone(req.Context())
+	shallow.URL.Path = path
+	info, err := infoFactory.NewRequestInfo(shallow)
+	if err != nil || !info.IsResourceRequest {
+		return record
+	}
+	record.Verb = info.Verb
+	record.Resource = info.Resource
+	if info.APIGroup != "" {
+		record.Resource += "." + info.APIGroup
+	}
+	record.Subresource = info.Subresource
+	record.Namespace = info.Namespace
+	record.Name = info.Name
+	return record
+}
+
+func newRecordingRequestInfoFactory() *request.RequestInfoFactory {
+	return &request.RequestInfoFactory{
+		APIPrefixes:          sets.NewString("api", "apis"),
+		GrouplessAPIPrefixes: sets.NewString("api"),
+	}
+}
+
+func isWrite(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func hasAnyGroup(req *http.Request, groups sets.String) bool {
+	u, ok := request.UserFrom(req.Context())
+	return ok && groups.HasAny(u.GetGroups()...)
+}
+
+// recordingResponseWriter captures the status of the response and, for sessions, the
+// streams of upgraded connections.
+type recordingResponseWriter struct {
+	http.ResponseWriter
+	status int
+
+	store   RecordStore
+	session *Record
+}
+
+func (w *recordingResponseWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *recordingResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *recordingResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *recordingResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hj, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer does not support hijacking")
+	}
+	conn, brw, err := hj.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+	if w.status == 0 {
+		w.status = http.StatusSwitchingProtocols
+	}
+	if w.session == nil {
+		return conn, brw, nil
+	}
+	return &recordingConn{Conn: conn, store: w.store, session: w.session}, brw, nil
+}
+
+// recordingConn records the raw bytes read from and written to the client.
+type recordingConn struct {
+	net.Conn
+	store   RecordStore
+	session *Record
+
+	lock   sync.Mutex
+	failed bool
+}
+
+func (c *recordingConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if n > 0 {
+		if recErr := c.record(RecordDirectionIn, b[:n]); recErr != nil {
+			return n, recErr
+		}
+	}
+	return n, err
+}
+
+func (c *recordingConn) Write(b []byte) (int, error) {
+	if err := c.record(RecordDirectionOut, b); err != nil {
+		return 0, err
+	}
+	return c.Conn.Write(b)
+}
+
+// record stores a chunk of a stream. The session is terminated if that fails, as it
+// would continue unrecorded otherwise.
+func (c *recordingConn) record(direction string, data []byte) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.failed {
+		return io.ErrClosedPipe
+	}
+
+	record := Record{
+		Time:      time.Now(),
+		Type:      RecordTypeSessionData,
+		SessionID: c.session.SessionID,
+		Direction: direction,
+		Data:      append([]byte(nil), data...),
+	}
+	if err := c.store.Append(&record); err != nil {
+		klog.Errorf("Failed to record session %s, terminating it: %v", c.session.SessionID, err)
+		c.failed = true
+		c.Conn.Close() // nolint:errcheck
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+type memoryRecordStore struct {
+	lock    sync.Mutex
+	records []Record
+}
+
+func (s *memoryRecordStore) Append(record *Record) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.records = append(s.records, *record)
+	return nil
+}
+
+func (s *memoryRecordStore) list() []Record {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]Record(nil), s.records...)
+}
+
+func TestFileRecordStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "recordings.jsonl")
+
+	store, err := NewFileRecordStore(path)
+	require.NoError(t, err)
+	require.NoError(t, store.Append(&Record{Type: RecordTypeRequest, User: "alice"}))
+	require.NoError(t, store.Append(&Record{Type: RecordTypeRequest, User: "bob"}))
+	require.NoError(t, store.Close())
+
+	// the chain continues across restarts
+	store, err = NewFileRecordStore(path)
+	require.NoError(t, err)
+	require.NoError(t, store.Append(&Record{Type: RecordTypeRequest, User: "carol"}))
+	require.NoError(t, store.Close())
+
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	n, err := VerifyRecordChain(bytes.NewReader(data))
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+
+	tampered := bytes.Replace(data, []byte(`"user":"bob"`), []byte(`"user":"eve"`), 1)
+	_, err = VerifyRecordChain(bytes.NewReader(tampered))
+	require.Error(t, err, "modified record should have been detected")
+
+	lines := strings.SplitAfter(string(data), "\n")
+	_, err = VerifyRecordChain(strings.NewReader(lines[0] + lines[2]))
+	require.Error(t, err, "removed record should have been detected")
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestFileRecordStoreConcurrentAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "recordings.jsonl")
+
+	store, err := NewFileRecordStore(path)
+	require.NoError(t, err)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			require.NoError(t, store.Append(&Record{Type: RecordTypeRequest, User: "alice"}))
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, store.written, store.synced, "all records should have been synced")
+	require.NoError(t, store.Close())
+
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	n, err := VerifyRecordChain(bytes.NewReader(data))
+	require.NoError(t, err)
+	require.Equal(t, 20, n)
+}
+
+func TestWithSessionRecordingWrites(t *testing.T) {
+	admin := &user.DefaultInfo{Name: "admin", Groups: []string{"system:masters"}}
+	alice := &user.DefaultInfo{Name: "alice", Groups: []string{"dev"}}
+
+	tests := map[string]struct {
+		user   user.Info
+		bearer bool
+		method string
+		path   string
+		body   string
+
+		want *Record
+	}{
+		"read of privileged user": {
+			user: admin, method: http.MethodGet, path: "/clusters/root:org/api/v1/namespaces/default/configmaps",
+		},
+		"write of unprivileged user": {
+			user: alice, method: http.MethodPost, path: "/clusters/root:org/api/v1/namespaces/default/configmaps", body: "{}",
+		},
+		"write of privileged user": {
+			user: admin, method: http.MethodPost, path: "/clusters/root:org/api/v1/namespaces/default/configmaps", body: `{"kind":"ConfigMap"}`,
+			want: &Record{
+				Type: RecordTypeRequest, User: "admin", Groups: []string{"system:masters"}, Workspace: "root:org",
+				Verb: "create", Path: "/clusters/root:org/api/v1/namespaces/default/configmaps", Resource: "configmaps", Namespace: "default",
+				Body: []byte(`{"kind":"Con`), BodyTruncated: true, Status: http.StatusCreated,
+			},
+		},
+		"write to recorded resource with bearer token": {
+			bearer: true, method: http.MethodDelete, path: "/clusters/root:org/apis/rbac.authorization.k8s.io/v1/clusterrolebindings/admin",
+			want: &Record{
+				Type: RecordTypeRequest, Authorization: "bearer", Workspace: "root:org",
+				Verb: "delete", Path: "/clusters/root:org/apis/rbac.authorization.k8s.io/v1/clusterrolebindings/admin",
+				Resource: "clusterrolebindings.rbac.authorization.k8s.io", Name: "admin", Status: http.StatusCreated,
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			store := &memoryRecordStore{}
+			var forwardedBody string
+			handler := WithSessionRecording(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				body, _ := ioutil.ReadAll(req.Body)
+				forwardedBody = string(body)
+				w.WriteHeader(http.StatusCreated)
+			}), store, RecordingPolicy{
+				Groups:       []string{"system:masters"},
+				Resources:    []string{"secrets", "clusterrolebindings.rbac.authorization.k8s.io"},
+				MaxBodyBytes: 12,
+			})
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.user != nil {
+				req = req.WithContext(request.WithUser(req.Context(), tt.user))
+			}
+			if tt.bearer {
+				req.Header.Set("Authorization", "Bearer token")
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			require.Equal(t, tt.body, forwardedBody, "body should have been forwarded unchanged")
+
+			records := store.list()
+			if tt.want == nil {
+				require.Empty(t, records)
+				return
+			}
+			require.Len(t, records, 1)
+			records[0].Time = tt.want.Time
+			require.Equal(t, *tt.want, records[0])
+		})
+	}
+}
+
+func TestWithSessionRecordingSession(t *testing.T) {
+	store := &memoryRecordStore{}
+	backend := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		conn, brw, err := w.(http.Hijacker).Hijack()
+		require.NoError(t, err)
+		defer conn.Close()
+		brw.WriteString("HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: test\r\n\r\n") // nolint:errcheck
+		require.NoError(t, brw.Flush())
+
+		buf := make([]byte, 4)
+		_, err = conn.Read(buf)
+		require.NoError(t, err)
+		_, err = conn.Write([]byte("pong"))
+		require.NoError(t, err)
+	})
+	server := httptest.NewServer(WithSessionRecording(backend, store, RecordingPolicy{}))
+	defer server.Close()
+
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("POST /clusters/root:org/api/v1/namespaces/default/pods/web/exec?command=sh HTTP/1.1\r\nHost: kcp\r\nConnection: Upgrade\r\nUpgrade: test\r\n\r\n"))
+	require.NoError(t, err)
+
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+	_, err = conn.Write([]byte("ping"))
+	require.NoError(t, err)
+	pong := make([]byte, 4)
+	_, err = r.Read(pong)
+	require.NoError(t, err)
+	require.Equal(t, "pong", string(pong))
+
+	require.Eventually(t, func() bool {
+		records := store.list()
+		return len(records) > 0 && records[len(records)-1].Type == RecordTypeSessionEnd
+	}, wait.ForeverTestTimeout, 10*time.Millisecond)
+
+	records := store.list()
+	require.Len(t, records, 4)
+	start := records[0]
+	require.Equal(t, RecordTypeSessionStart, start.Type)
+	require.Equal(t, "root:org", start.Workspace)
+	require.Equal(t, "pods", start.Resource)
+	require.Equal(t, "exec", start.Subresource)
+	require.Equal(t, "web", start.Name)
+	require.NotEmpty(t, start.SessionID)
+
+	require.Equal(t, Record{Type: RecordTypeSessionData, SessionID: start.SessionID, Direction: RecordDirectionIn, Data: []byte("ping")}, withoutTime(records[1]))
+	require.Equal(t, Record{Type: RecordTypeSessionData, SessionID: start.SessionID, Direction: RecordDirectionOut, Data: []byte("pong")}, withoutTime(records[2]))
+	require.Equal(t, http.StatusSwitchingProtocols, records[3].Status)
+}
+
+func withoutTime(r Record) Record {
+	r.Time = time.Time{}
+	return r
+}
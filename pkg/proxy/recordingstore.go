@@ -0,0 +1,213 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	RecordTypeSessionStart = "SessionStart"
+	RecordTypeSessionData  = "SessionData"
+	RecordTypeSessionEnd   = "SessionEnd"
+	RecordTypeRequest      = "Request"
+
+	// RecordDirectionIn is data sent by the client, RecordDirectionOut data sent to it.
+	RecordDirectionIn  = "in"
+	RecordDirectionOut = "out"
+)
+
+// Record is an entry of the session recording store.
+type Record struct {
+	Time time.Time `json:"time"`
+	Type string    `json:"type"`
+	// SessionID identifies the records of an exec, attach or port-forward session.
+	SessionID string `json:"sessionID,omitempty"`
+
+	User   string   `json:"user,omitempty"`
+	Groups []string `json:"groups,omitempty"`
+	// Authorization is "bearer" if the user was not authenticated by the proxy, but by
+	// a bearer token at the backend.
+	Authorization string `json:"authorization,omitempty"`
+
+	Workspace   string `json:"workspace,omitempty"`
+	Verb        string `json:"verb,omitempty"`
+	Path        string `json:"path,omitempty"`
+	Resource    string `json:"resource,omitempty"`
+	Subresource string `json:"subresource,omitempty"`
+	Namespace   string `json:"namespace,omitempty"`
+	Name        string `json:"name,omitempty"`
+
+	// Direction and Data hold the raw bytes of a stream of a session.
+	Direction string `json:"direction,omitempty"`
+	Data      []byte `json:"data,omitempty"`
+
+	// Body holds the request body of a write request, up to the configured size.
+	Body          []byte `json:"body,omitempty"`
+	BodyTruncated bool   `json:"bodyTruncated,omitempty"`
+	Status        int    `json:"status,omitempty"`
+
+	// PreviousHash is the hex encoded SHA-256 hash of the previous line of the store,
+	// such that removed or modified records are detected.
+	PreviousHash string `json:"previousHash"`
+}
+
+// FileRecordStore appends records as JSON lines to a file, chained by the hashes of the
+// previous lines.
+//
+// Records other than session data are synced to disk before Append returns. Appends
+// waiting for a sync at the same time share one, i.e. with concurrent requests the syncs
+// are batched, and the throughput is bounded by the sync latency of the disk times the
+// number of concurrent appends, not by the sync latency alone.
+type FileRecordStore struct {
+	lock     sync.Mutex
+	file     *os.File
+	lastHash string
+	// written is the number of records written to the file.
+	written uint64
+
+	syncLock sync.Mutex
+	// synced is the number of records synced to disk. It is guarded by syncLock.
+	synced uint64
+}
+
+// NewFileRecordStore opens the store at the given path, creating it if it does not exist,
+// and continues the hash chain of the existing records.
+func NewFileRecordStore(path string) (*FileRecordStore, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	lastHash := ""
+	r := bufio.NewReader(file)
+	for {
+		line, err := r.ReadBytes('\n')
+		if len(line) > 0 && line[len(line)-1] == '\n' {
+			lastHash = hashLine(line)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			file.Close() // nolint:errcheck
+			return nil, fmt.Errorf("failed to read session recording store %q: %w", path, err)
+		}
+	}
+
+	return &FileRecordStore{file: file, lastHash: lastHash}, nil
+}
+
+// Append writes the record, syncing it to disk unless it is session data.
+func (s *FileRecordStore) Append(record *Record) error {
+	written, err := s.write(record)
+	if err != nil {
+		return err
+	}
+	if record.Type == RecordTypeSessionData {
+		return nil
+	}
+	return s.sync(written)
+}
+
+// write writes the record and returns the number of records written including it.
+func (s *FileRecordStore) write(record *Record) (uint64, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	record.PreviousHash = s.lastHash
+	line, err := json.Marshal(record)
+	if err != nil {
+		return 0, err
+	}
+	line = append(line, '\n')
+	if _, err := s.file.Write(line); err != nil {
+		return 0, err
+	}
+	s.lastHash = hashLine(line)
+	s.written++
+	return s.written, nil
+}
+
+// sync syncs the file to disk unless the first n records have been synced already by
+// another Append. The sync covers all records written before it starts, such that
+// appends waiting for it meanwhile return without syncing again.
+func (s *FileRecordStore) sync(n uint64) error {
+	s.syncLock.Lock()
+	defer s.syncLock.Unlock()
+
+	if s.synced >= n {
+		return nil
+	}
+	s.lock.Lock()
+	written := s.written
+	s.lock.Unlock()
+	if err := s.file.Sync(); err != nil {
+		return err
+	}
+	s.synced = written
+	return nil
+}
+
+// Close closes the store.
+func (s *FileRecordStore) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.file.Close()
+}
+
+// VerifyRecordChain checks the hash chain of the records read from r, and returns the
+// number of records.
+func VerifyRecordChain(r io.Reader) (int, error) {
+	br := bufio.NewReader(r)
+	lastHash := ""
+	n := 0
+	for {
+		line, err := br.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			n++
+			var record Record
+			if err := json.Unmarshal(line, &record); err != nil {
+				return n, fmt.Errorf("record %d is invalid: %w", n, err)
+			}
+			if record.PreviousHash != lastHash {
+				return n, fmt.Errorf("record %d does not follow the previous record: hash %q, expected %q", n, record.PreviousHash, lastHash)
+			}
+			lastHash = hashLine(line)
+		}
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+	}
+}
+
+func hashLine(line []byte) string {
+	sum := sha256.Sum256(line)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	x509request "k8s.io/apiserver/pkg/authentication/request/x509"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+const (
+	// WorkspaceScopeExtraKey is the key of the user extra holding the workspaces
+	// a client certificate is scoped to.
+	WorkspaceScopeExtraKey = "authentication.kcp.dev/workspace-scope"
+
+	// workspaceScopePrefix prefixes the Organizational Units and the URI SANs of
+	// client certificates scoping them to a workspace, e.g. "workspace:root:org:team".
+	workspaceScopePrefix = "workspace:"
+)
+
+var (
+	reClusterName = regexp.MustCompile(`^([a-z]([a-z0-9-]{0,61}[a-z0-9])?:)*[a-z]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+	errorScheme = runtime.NewScheme()
+	errorCodecs = serializer.NewCodecFactory(errorScheme)
+)
+
+func init() {
+	metav1.AddToGroupVersion(errorScheme, schema.GroupVersion{Group: "", Version: "v1"})
+}
+
+// WorkspaceScopeUserConversion converts client certificates to users like
+// x509.CommonNameUserConversion, and records the workspaces the certificate is
+// scoped to in the WorkspaceScopeExtraKey extra. A certificate is scoped to the
+// workspaces of its Organizational Units and URI SANs of the form
+// "workspace:<logical cluster>", e.g. "workspace:root:org:team", and to all
+// workspaces below them.
+var WorkspaceScopeUserConversion = x509request.UserConversionFunc(func(chain []*x509.Certificate) (*authenticator.Response, bool, error) {
+	resp, ok, err := x509request.CommonNameUserConversion.User(chain)
+	if err != nil || !ok {
+		return resp, ok, err
+	}
+
+	scopes, err := workspaceScopes(chain[0])
+	if err != nil {
+		return nil, false, err
+	}
+	if len(scopes) == 0 {
+		return resp, true, nil
+	}
+
+	extra := map[string][]string{}
+	for k, v := range resp.User.GetExtra() {
+		extra[k] = v
+	}
+	extra[WorkspaceScopeExtraKey] = scopes
+	resp.User = &user.DefaultInfo{
+		Name:   resp.User.GetName(),
+		UID:    resp.User.GetUID(),
+		Groups: resp.User.GetGroups(),
+		Extra:  extra,
+	}
+	return resp, true, nil
+})
+
+func workspaceScopes(cert *x509.Certificate) ([]string, error) {
+	var scopes []string
+	for _, ou := range cert.Subject.OrganizationalUnit {
+		if strings.HasPrefix(ou, workspaceScopePrefix) {
+			scopes = append(scopes, strings.TrimPrefix(ou, workspaceScopePrefix))
+		}
+	}
+	for _, uri := range cert.URIs {
+		if uri.Scheme+":" == workspaceScopePrefix {
+			scopes = append(scopes, uri.Opaque)
+		}
+	}
+	for _, scope := range scopes {
+		if !reClusterName.MatchString(scope) {
+			return nil, fmt.Errorf("invalid workspace scope %q in client certificate", scope)
+		}
+	}
+	return scopes, nil
+}
+
+// WithWorkspaceScope rejects the requests of users scoped to workspaces by
+// their client certificate that are not for one of these workspaces or a
+// workspace below them. Scoped users can only make requests under /clusters/.
+func WithWorkspaceScope(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		u, ok := request.UserFrom(req.Context())
+		if !ok {
+			handler.ServeHTTP(w, req)
+			return
+		}
+		scopes, scoped := u.GetExtra()[WorkspaceScopeExtraKey]
+		if !scoped {
+			handler.ServeHTTP(w, req)
+			return
+		}
+
+		cluster, ok := clusterFromPath(req.URL.Path)
+		if !ok || !inWorkspaceScope(cluster, scopes) {
+			responsewriters.ErrorNegotiated(
+				apierrors.NewForbidden(schema.GroupResource{}, "", fmt.Errorf("user %q is restricted to the workspaces %s by its client certificate", u.GetName(), strings.Join(scopes, ", "))),
+				errorCodecs, schema.GroupVersion{}, w, req,
+			)
+			return
+		}
+
+		handler.ServeHTTP(w, req)
+	})
+}
+
+func clusterFromPath(path string) (logicalcluster.Name, bool) {
+	if !strings.HasPrefix(path, "/clusters/") {
+		return logicalcluster.Name{}, false
+	}
+	name := strings.SplitN(strings.TrimPrefix(path, "/clusters/"), "/", 2)[0]
+	if !reClusterName.MatchString(name) {
+		// e.g. the wildcard cluster
+		return logicalcluster.Name{}, false
+	}
+	return logicalcluster.New(name), true
+}
+
+func inWorkspaceScope(cluster logicalcluster.Name, scopes []string) bool {
+	for _, scope := range scopes {
+		if cluster.String() == scope || strings.HasPrefix(cluster.String(), scope+":") {
+			return true
+		}
+	}
+	return false
+}
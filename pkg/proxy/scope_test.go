@@ -0,0 +1,111 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+func TestWorkspaceScopeUserConversion(t *testing.T) {
+	tests := map[string]struct {
+		ous       []string
+		uris      []string
+		wantScope []string
+		wantErr   bool
+	}{
+		"unscoped":            {ous: []string{"team"}},
+		"organizational unit": {ous: []string{"team", "workspace:root:org:team"}, wantScope: []string{"root:org:team"}},
+		"uri san":             {uris: []string{"workspace:root:org:team"}, wantScope: []string{"root:org:team"}},
+		"both":                {ous: []string{"workspace:root:org:a"}, uris: []string{"workspace:root:org:b", "https://example.com"}, wantScope: []string{"root:org:a", "root:org:b"}},
+		"wildcard":            {ous: []string{"workspace:*"}, wantErr: true},
+		"invalid":             {uris: []string{"workspace:root:Org"}, wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cert := &x509.Certificate{Subject: pkix.Name{CommonName: "robot", Organization: []string{"robots"}, OrganizationalUnit: tt.ous}}
+			for _, s := range tt.uris {
+				u, err := url.Parse(s)
+				require.NoError(t, err)
+				cert.URIs = append(cert.URIs, u)
+			}
+
+			resp, ok, err := WorkspaceScopeUserConversion.User([]*x509.Certificate{cert})
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, ok)
+			require.Equal(t, "robot", resp.User.GetName())
+			require.Equal(t, []string{"robots"}, resp.User.GetGroups())
+			require.Equal(t, tt.wantScope, resp.User.GetExtra()[WorkspaceScopeExtraKey])
+		})
+	}
+}
+
+func TestWithWorkspaceScope(t *testing.T) {
+	tests := map[string]struct {
+		user        user.Info
+		path        string
+		wantAllowed bool
+	}{
+		"anonymous":           {path: "/api/v1/namespaces", wantAllowed: true},
+		"unscoped user":       {user: &user.DefaultInfo{Name: "alice"}, path: "/clusters/root:other/api", wantAllowed: true},
+		"scoped workspace":    {user: scoped("root:org:team"), path: "/clusters/root:org:team/api/v1/namespaces", wantAllowed: true},
+		"scoped descendant":   {user: scoped("root:org:team"), path: "/clusters/root:org:team:dev/api", wantAllowed: true},
+		"second scope":        {user: scoped("root:org:a", "root:org:b"), path: "/clusters/root:org:b", wantAllowed: true},
+		"sibling prefix":      {user: scoped("root:org:team"), path: "/clusters/root:org:teams/api"},
+		"parent":              {user: scoped("root:org:team"), path: "/clusters/root:org/api"},
+		"wildcard":            {user: scoped("root:org:team"), path: "/clusters/*/api/v1/namespaces"},
+		"outside of clusters": {user: scoped("root:org:team"), path: "/api/v1/namespaces"},
+		"virtual workspace":   {user: scoped("root:org:team"), path: "/services/workspaces/root:org:team/all/apis"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			called := false
+			handler := WithWorkspaceScope(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+				called = true
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.user != nil {
+				req = req.WithContext(request.WithUser(req.Context(), tt.user))
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			require.Equal(t, tt.wantAllowed, called)
+			if !tt.wantAllowed {
+				require.Equal(t, http.StatusForbidden, w.Code)
+			}
+		})
+	}
+}
+
+func scoped(scopes ...string) user.Info {
+	return &user.DefaultInfo{Name: "robot", Extra: map[string][]string{WorkspaceScopeExtraKey: scopes}}
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	kcpinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
+	tenancylister "github.com/kcp-dev/kcp/pkg/client/listers/tenancy/v1alpha1"
+	proxyoptions "github.com/kcp-dev/kcp/pkg/proxy/options"
+	"github.com/kcp-dev/kcp/pkg/shardversion"
+)
+
+// shardAPIRetryAfterSeconds is how long clients are asked to wait before retrying requests
+// for APIs of shards that are not upgraded yet.
+const shardAPIRetryAfterSeconds = 10
+
+// shardAPIGate rejects the requests for API group versions a shard does not serve, while
+// other shards do, i.e. for new APIs during a rolling upgrade. Requests for other APIs, and
+// all requests while the shard has not published its version info, are passed on.
+type shardAPIGate struct {
+	lister tenancylister.ClusterWorkspaceShardLister
+
+	lock        sync.RWMutex
+	negotiation *shardversion.Negotiation
+}
+
+// newShardAPIGate returns a gate for the ClusterWorkspaceShards in the root workspace, which
+// are watched until ctx is done.
+func newShardAPIGate(ctx context.Context, o *proxyoptions.Options) (*shardAPIGate, error) {
+	client, err := newRootKcpClient(o)
+	if err != nil {
+		return nil, err
+	}
+
+	informers := kcpinformers.NewSharedInformerFactoryWithOptions(client, resyncPeriod)
+	shards := informers.Tenancy().V1alpha1().ClusterWorkspaceShards()
+	g := &shardAPIGate{
+		lister:      shards.Lister(),
+		negotiation: shardversion.Negotiate(nil),
+	}
+	shards.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { g.update() },
+		UpdateFunc: func(_, obj interface{}) { g.update() },
+		DeleteFunc: func(obj interface{}) { g.update() },
+	})
+	informers.Start(ctx.Done())
+
+	return g, nil
+}
+
+func (g *shardAPIGate) update() {
+	shards, err := g.lister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Failed to list ClusterWorkspaceShards: %v", err)
+		return
+	}
+	n := shardversion.Negotiate(shards)
+
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.negotiation = n
+}
+
+// WithShard gates the requests of the given handler, which forwards to the given shard.
+func (g *shardAPIGate) WithShard(shard string, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		gv, ok := groupVersionFromPath(req.URL.Path)
+		if !ok {
+			handler.ServeHTTP(w, req)
+			return
+		}
+
+		g.lock.RLock()
+		n := g.negotiation
+		g.lock.RUnlock()
+
+		if servedBy := n.ServedBy(gv); n.Published(shard) && servedBy.Len() > 0 && !servedBy.Has(shard) {
+			responsewriters.ErrorNegotiated(
+				&apierrors.StatusError{ErrStatus: metav1.Status{
+					Status:  metav1.StatusFailure,
+					Code:    http.StatusServiceUnavailable,
+					Reason:  metav1.StatusReasonServiceUnavailable,
+					Message: fmt.Sprintf("%s is not served by shard %q yet, it is only served by upgraded shards", gv.String(), shard),
+					Details: &metav1.StatusDetails{RetryAfterSeconds: shardAPIRetryAfterSeconds},
+				}},
+				errorCodecs, schema.GroupVersion{}, w, req,
+			)
+			return
+		}
+
+		handler.ServeHTTP(w, req)
+	})
+}
+
+// groupVersionFromPath returns the group version of paths like
+// [/clusters/<name>]/apis/<group>/<version>/...
+func groupVersionFromPath(path string) (schema.GroupVersion, bool) {
+	if strings.HasPrefix(path, "/clusters/") {
+		parts := strings.SplitN(strings.TrimPrefix(path, "/clusters/"), "/", 2)
+		if len(parts) < 2 {
+			return schema.GroupVersion{}, false
+		}
+		path = "/" + parts[1]
+	}
+	if !strings.HasPrefix(path, "/apis/") {
+		return schema.GroupVersion{}, false
+	}
+	parts := strings.SplitN(strings.TrimPrefix(path, "/apis/"), "/", 3)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return schema.GroupVersion{}, false
+	}
+	return schema.GroupVersion{Group: parts[0], Version: parts[1]}, true
+}
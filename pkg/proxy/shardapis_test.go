@@ -0,0 +1,95 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/shardversion"
+)
+
+func TestGroupVersionFromPath(t *testing.T) {
+	tests := map[string]struct {
+		want schema.GroupVersion
+		ok   bool
+	}{
+		"/clusters/root:org/apis/tenancy.kcp.dev/v1beta1/clusterworkspaces": {want: schema.GroupVersion{Group: "tenancy.kcp.dev", Version: "v1beta1"}, ok: true},
+		"/apis/apps/v1/namespaces/default/deployments":                      {want: schema.GroupVersion{Group: "apps", Version: "v1"}, ok: true},
+		"/clusters/*/apis/apps/v1":                                          {want: schema.GroupVersion{Group: "apps", Version: "v1"}, ok: true},
+		"/clusters/root:org/apis/apps":                                      {},
+		"/clusters/root:org/api/v1/configmaps":                              {},
+		"/apis":                                                             {},
+		"/clusters/root":                                                    {},
+		"/healthz":                                                          {},
+	}
+	for path, tt := range tests {
+		t.Run(path, func(t *testing.T) {
+			got, ok := groupVersionFromPath(path)
+			require.Equal(t, tt.ok, ok)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestShardAPIGate(t *testing.T) {
+	shard := func(name string, versions ...string) *tenancyv1alpha1.ClusterWorkspaceShard {
+		s := &tenancyv1alpha1.ClusterWorkspaceShard{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if versions != nil {
+			s.Status.VersionInfo = &tenancyv1alpha1.ShardVersionInfo{
+				Resources: []tenancyv1alpha1.ShardResource{{Group: "tenancy.kcp.dev", Resource: "widgets", Versions: versions}},
+			}
+		}
+		return s
+	}
+	g := &shardAPIGate{negotiation: shardversion.Negotiate([]*tenancyv1alpha1.ClusterWorkspaceShard{
+		shard("old", "v1alpha1"),
+		shard("new", "v1alpha1", "v1beta1"),
+		shard("unpublished"),
+	})}
+	backend := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		shard, path    string
+		wantCode       int
+		wantRetryAfter string
+	}{
+		{shard: "old", path: "/clusters/root/apis/tenancy.kcp.dev/v1alpha1/widgets", wantCode: http.StatusOK},
+		{shard: "old", path: "/clusters/root/apis/tenancy.kcp.dev/v1beta1/widgets", wantCode: http.StatusServiceUnavailable, wantRetryAfter: "10"},
+		{shard: "new", path: "/clusters/root/apis/tenancy.kcp.dev/v1beta1/widgets", wantCode: http.StatusOK},
+		{shard: "unpublished", path: "/clusters/root/apis/tenancy.kcp.dev/v1beta1/widgets", wantCode: http.StatusOK},
+		{shard: "old", path: "/clusters/root/apis/apps/v1/deployments", wantCode: http.StatusOK},
+		{shard: "old", path: "/clusters/root/api/v1/configmaps", wantCode: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.shard+tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			g.WithShard(tt.shard, backend).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			require.Equal(t, tt.wantCode, rec.Code)
+			require.Equal(t, tt.wantRetryAfter, rec.Header().Get("Retry-After"))
+		})
+	}
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/klog/v2"
+
+	"github.com/kcp-dev/kcp/pkg/authentication/exchange"
+)
+
+// WithTokenExchange validates the bearer tokens of requests with the given authenticator of
+// an external identity provider, and replaces them with internal tokens of the issuer for
+// the authenticated user, scoped to the workspace of the request. Requests of users
+// authenticated by client certificate and tokens the authenticator does not recognize, e.g.
+// ServiceAccount tokens of kcp, are passed through unchanged. Tokens the authenticator
+// rejects are rejected, and so are requests with valid tokens outside of a workspace, as
+// their internal tokens would be valid for all workspaces.
+//
+// Internal tokens are reused for the requests of the same user to the same workspace until
+// shortly before they expire.
+func WithTokenExchange(handler http.Handler, external authenticator.Token, issuer *exchange.Issuer) http.Handler {
+	tokens := newExchangedTokenCache(issuer)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if _, ok := request.UserFrom(req.Context()); ok {
+			handler.ServeHTTP(w, req)
+			return
+		}
+		token, ok := bearerToken(req)
+		if !ok {
+			handler.ServeHTTP(w, req)
+			return
+		}
+
+		resp, ok, err := external.AuthenticateToken(req.Context(), token)
+		if err != nil {
+			klog.V(4).Infof("Rejecting bearer token of %s %s: %v", req.Method, req.URL.Path, err)
+			responsewriters.ErrorNegotiated(apierrors.NewUnauthorized("invalid bearer token"), errorCodecs, schema.GroupVersion{}, w, req)
+			return
+		}
+		if !ok {
+			handler.ServeHTTP(w, req)
+			return
+		}
+
+		cluster, ok := clusterFromPath(req.URL.Path)
+		if !ok {
+			responsewriters.ErrorNegotiated(
+				apierrors.NewForbidden(schema.GroupResource{}, "", fmt.Errorf("user %q can only access paths under /clusters/<workspace>", resp.User.GetName())),
+				errorCodecs, schema.GroupVersion{}, w, req,
+			)
+			return
+		}
+		internal, id, issued, err := tokens.get(resp.User, cluster)
+		if err != nil {
+			responsewriters.InternalError(w, req, err)
+			return
+		}
+		if issued {
+			klog.V(4).InfoS("Exchanged bearer token", "exchangeID", id, "user", resp.User.GetName(), "groups", resp.User.GetGroups(), "workspace", cluster.String(), "method", req.Method, "path", req.URL.Path)
+		}
+
+		req.Header.Set("Authorization", "Bearer "+internal)
+		handler.ServeHTTP(w, req)
+	})
+}
+
+func bearerToken(req *http.Request) (string, bool) {
+	parts := strings.SplitN(strings.TrimSpace(req.Header.Get("Authorization")), " ", 3)
+	if len(parts) < 2 || strings.ToLower(parts[0]) != "bearer" || parts[1] == "" {
+		return "", false
+	}
+	return parts[1], true
+}
+
+// exchangedTokenCache holds the internal tokens issued per user and workspace. Expired
+// tokens are removed every expiration period.
+type exchangedTokenCache struct {
+	issuer *exchange.Issuer
+	now    func() time.Time
+
+	lock      sync.Mutex
+	tokens    map[string]*exchangedToken
+	lastSweep time.Time
+}
+
+type exchangedToken struct {
+	token   string
+	id      string
+	renewAt time.Time
+	expiry  time.Time
+}
+
+func newExchangedTokenCache(issuer *exchange.Issuer) *exchangedTokenCache {
+	return &exchangedTokenCache{
+		issuer: issuer,
+		now:    time.Now,
+		tokens: map[string]*exchangedToken{},
+	}
+}
+
+// get returns the internal token of the user for the workspace, and the ID of its exchange.
+// A token is issued if there is none which is valid for another fifth of its lifetime, which
+// is reported by issued.
+func (c *exchangedTokenCache) get(u user.Info, cluster logicalcluster.Name) (token, id string, issued bool, err error) {
+	key, err := exchangedTokenKey(u, cluster)
+	if err != nil {
+		return "", "", false, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := c.now()
+	expiration := c.issuer.Expiration()
+	if now.Sub(c.lastSweep) > expiration {
+		for k, t := range c.tokens {
+			if !now.Before(t.expiry) {
+				delete(c.tokens, k)
+			}
+		}
+		c.lastSweep = now
+	}
+
+	if t, ok := c.tokens[key]; ok && now.Before(t.renewAt) {
+		return t.token, t.id, false, nil
+	}
+
+	token, id, err = c.issuer.Issue(u, cluster)
+	if err != nil {
+		return "", "", false, err
+	}
+	c.tokens[key] = &exchangedToken{
+		token:   token,
+		id:      id,
+		renewAt: now.Add(expiration - expiration/5),
+		expiry:  now.Add(expiration),
+	}
+	return token, id, true, nil
+}
+
+// exchangedTokenKey returns the key of everything an internal token is issued for.
+func exchangedTokenKey(u user.Info, cluster logicalcluster.Name) (string, error) {
+	key, err := json.Marshal(struct {
+		Name    string              `json:"name"`
+		UID     string              `json:"uid"`
+		Groups  []string            `json:"groups"`
+		Extra   map[string][]string `json:"extra"`
+		Cluster string              `json:"cluster"`
+	}{u.GetName(), u.GetUID(), u.GetGroups(), u.GetExtra(), cluster.String()})
+	if err != nil {
+		return "", err
+	}
+	return string(key), nil
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	"github.com/kcp-dev/kcp/pkg/authentication/exchange"
+)
+
+func TestWithTokenExchange(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	issuer, err := exchange.NewIssuer("https://front-proxy", []string{"kcp"}, key, time.Minute)
+	require.NoError(t, err)
+	internalAuth := exchange.NewAuthenticator("https://front-proxy", []string{"kcp"}, []interface{}{&key.PublicKey})
+
+	external := authenticator.TokenFunc(func(ctx context.Context, token string) (*authenticator.Response, bool, error) {
+		switch token {
+		case "valid":
+			return &authenticator.Response{User: &user.DefaultInfo{Name: "oidc:alice", Groups: []string{"oidc:dev"}}}, true, nil
+		case "invalid":
+			return nil, false, errors.New("expired")
+		default:
+			return nil, false, nil
+		}
+	})
+
+	tests := map[string]struct {
+		user          user.Info
+		authorization string
+		path          string
+
+		wantStatus    int
+		wantUnchanged bool
+	}{
+		"no token":                {path: "/clusters/root:org/api", wantUnchanged: true},
+		"basic auth":              {authorization: "Basic Zm9vOmJhcg==", path: "/clusters/root:org/api", wantUnchanged: true},
+		"unknown token":           {authorization: "Bearer sa-token", path: "/clusters/root:org/api", wantUnchanged: true},
+		"client certificate user": {user: &user.DefaultInfo{Name: "robot"}, authorization: "Bearer valid", path: "/clusters/root:org/api", wantUnchanged: true},
+		"invalid token":           {authorization: "Bearer invalid", path: "/clusters/root:org/api", wantStatus: http.StatusUnauthorized},
+		"exchanged scoped token":  {authorization: "Bearer valid", path: "/clusters/root:org:ws/api/v1/namespaces"},
+		"outside of workspaces":   {authorization: "bearer valid", path: "/api/v1/namespaces", wantStatus: http.StatusForbidden},
+		"wildcard":                {authorization: "Bearer valid", path: "/clusters/*/api/v1/namespaces", wantStatus: http.StatusForbidden},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var forwarded string
+			handler := WithTokenExchange(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				forwarded = req.Header.Get("Authorization")
+			}), external, issuer)
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			if tt.user != nil {
+				req = req.WithContext(request.WithUser(req.Context(), tt.user))
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if tt.wantStatus != 0 {
+				require.Equal(t, tt.wantStatus, w.Code)
+				return
+			}
+			require.Equal(t, http.StatusOK, w.Code)
+			if tt.wantUnchanged {
+				require.Equal(t, tt.authorization, forwarded)
+				return
+			}
+
+			token, ok := bearerToken(&http.Request{Header: http.Header{"Authorization": []string{forwarded}}})
+			require.True(t, ok)
+			require.NotEqual(t, "valid", token)
+
+			// the internal token is valid for the workspace of the request only
+			ctx := request.WithCluster(context.Background(), request.Cluster{Name: logicalcluster.New("root:org:ws")})
+			resp, ok, err := internalAuth.AuthenticateToken(ctx, token)
+			require.NoError(t, err)
+			require.True(t, ok)
+			require.Equal(t, "oidc:alice", resp.User.GetName())
+			require.Equal(t, []string{"oidc:dev"}, resp.User.GetGroups())
+
+			ctx = request.WithCluster(context.Background(), request.Cluster{Name: logicalcluster.New("root:other")})
+			_, _, err = internalAuth.AuthenticateToken(ctx, token)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestExchangedTokenCache(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	issuer, err := exchange.NewIssuer("https://front-proxy", []string{"kcp"}, key, 5*time.Minute)
+	require.NoError(t, err)
+
+	now := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	cache := newExchangedTokenCache(issuer)
+	cache.now = func() time.Time { return now }
+
+	alice := &user.DefaultInfo{Name: "oidc:alice", Groups: []string{"oidc:dev"}}
+	ws := logicalcluster.New("root:org:ws")
+
+	token, id, issued, err := cache.get(alice, ws)
+	require.NoError(t, err)
+	require.True(t, issued)
+
+	now = now.Add(time.Minute)
+	cached, cachedID, issued, err := cache.get(alice, ws)
+	require.NoError(t, err)
+	require.False(t, issued, "token should have been reused")
+	require.Equal(t, token, cached)
+	require.Equal(t, id, cachedID)
+
+	other, _, issued, err := cache.get(alice, logicalcluster.New("root:org:other"))
+	require.NoError(t, err)
+	require.True(t, issued, "token should have been issued for another workspace")
+	require.NotEqual(t, token, other)
+
+	_, _, issued, err = cache.get(&user.DefaultInfo{Name: "oidc:alice", Groups: []string{"oidc:dev", "oidc:admins"}}, ws)
+	require.NoError(t, err)
+	require.True(t, issued, "token should have been issued for other groups")
+
+	// renewed shortly before expiry
+	now = now.Add(3 * time.Minute)
+	renewed, _, issued, err := cache.get(alice, ws)
+	require.NoError(t, err)
+	require.True(t, issued, "token should have been renewed")
+	require.NotEqual(t, token, renewed)
+
+	// expired tokens are removed
+	now = now.Add(10 * time.Minute)
+	_, _, _, err = cache.get(alice, ws)
+	require.NoError(t, err)
+	require.Len(t, cache.tokens, 1)
+}
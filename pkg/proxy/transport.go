@@ -0,0 +1,151 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/apiserver/pkg/server/dynamiccertificates"
+	"k8s.io/klog/v2"
+)
+
+// dynamicTransport is a http.RoundTripper that rebuilds its underlying
+// transport whenever the proxy client certificate or the backend server CA
+// change on disk. In-flight requests finish on the old transport, whose idle
+// connections are closed after the swap.
+type dynamicTransport struct {
+	clientCert *dynamiccertificates.DynamicCertKeyPairContent
+	serverCA   dynamiccertificates.CAContentProvider
+
+	lock      sync.RWMutex
+	transport *http.Transport
+	// upgradeTransport serves upgrade requests, e.g. websocket watches and SPDY
+	// streams, which are only possible with HTTP/1.1.
+	upgradeTransport *http.Transport
+}
+
+var _ http.RoundTripper = &dynamicTransport{}
+var _ dynamiccertificates.Listener = &dynamicTransport{}
+
+// newDynamicTransport loads the client cert/key pair and the CA bundle, and
+// starts watching the files for changes until ctx is done.
+func newDynamicTransport(ctx context.Context, clientCertFile, clientKeyFile, caFile string) (*dynamicTransport, error) {
+	serverCA, err := dynamiccertificates.NewDynamicCAContentFromFile("backend-server-ca", caFile)
+	if err != nil {
+		return nil, err
+	}
+	t, err := newTransport(ctx, clientCertFile, clientKeyFile, serverCA)
+	if err != nil {
+		return nil, err
+	}
+
+	serverCA.AddListener(t)
+	go serverCA.Run(1, ctx.Done())
+
+	return t, nil
+}
+
+// newDynamicTransportWithCABundle is like newDynamicTransport, but with a fixed
+// CA bundle.
+func newDynamicTransportWithCABundle(ctx context.Context, clientCertFile, clientKeyFile string, caBundle []byte) (*dynamicTransport, error) {
+	serverCA, err := dynamiccertificates.NewStaticCAContent("backend-server-ca", caBundle)
+	if err != nil {
+		return nil, err
+	}
+	return newTransport(ctx, clientCertFile, clientKeyFile, serverCA)
+}
+
+func newTransport(ctx context.Context, clientCertFile, clientKeyFile string, serverCA dynamiccertificates.CAContentProvider) (*dynamicTransport, error) {
+	clientCert, err := dynamiccertificates.NewDynamicServingContentFromFiles("proxy-client-cert", clientCertFile, clientKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &dynamicTransport{
+		clientCert: clientCert,
+		serverCA:   serverCA,
+	}
+	if err := t.rebuild(); err != nil {
+		return nil, err
+	}
+
+	clientCert.AddListener(t)
+	go clientCert.Run(1, ctx.Done())
+
+	return t, nil
+}
+
+// Enqueue is called by the dynamic certificate providers on change.
+func (t *dynamicTransport) Enqueue() {
+	if err := t.rebuild(); err != nil {
+		klog.Errorf("Failed to reload proxy client certificates (%s, %s): %v", t.clientCert.Name(), t.serverCA.Name(), err)
+	}
+}
+
+func (t *dynamicTransport) rebuild() error {
+	certPEM, keyPEM := t.clientCert.CurrentCertKeyContent()
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return fmt.Errorf("invalid client certificate: %w", err)
+	}
+
+	caCertPool := x509.NewCertPool()
+	if !caCertPool.AppendCertsFromPEM(t.serverCA.CurrentCABundleContent()) {
+		return fmt.Errorf("no valid certificates found in backend server CA")
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      caCertPool,
+	}
+
+	upgradeTransport := transport.Clone()
+	upgradeTransport.ForceAttemptHTTP2 = false
+	upgradeTransport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	upgradeTransport.TLSClientConfig.NextProtos = []string{"http/1.1"}
+
+	t.lock.Lock()
+	old, oldUpgrade := t.transport, t.upgradeTransport
+	t.transport, t.upgradeTransport = transport, upgradeTransport
+	t.lock.Unlock()
+
+	if old != nil {
+		klog.V(2).Infof("Reloaded proxy client certificates (%s, %s)", t.clientCert.Name(), t.serverCA.Name())
+		old.CloseIdleConnections()
+		oldUpgrade.CloseIdleConnections()
+	}
+
+	return nil
+}
+
+func (t *dynamicTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.lock.RLock()
+	transport := t.transport
+	if httpstream.IsUpgradeRequest(req) {
+		transport = t.upgradeTransport
+	}
+	t.lock.RUnlock()
+
+	return transport.RoundTrip(req)
+}
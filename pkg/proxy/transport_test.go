@@ -0,0 +1,165 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"context"
+	"crypto/tls"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	certutil "k8s.io/client-go/util/cert"
+)
+
+// newTLSServer returns a server whose certificate is signed by its own CA,
+// returned as caPEM. It echoes the CN of the client certificate, which
+// GenerateSelfSignedCertKey suffixes with @<timestamp>.
+func newTLSServer(t *testing.T) (server *httptest.Server, caPEM []byte) {
+	certPEM, keyPEM, err := certutil.GenerateSelfSignedCertKey("localhost", []net.IP{net.ParseIP("127.0.0.1")}, nil)
+	require.NoError(t, err)
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+
+	server = httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if len(req.TLS.PeerCertificates) > 0 {
+			w.Write([]byte(req.TLS.PeerCertificates[0].Subject.CommonName)) // nolint:errcheck
+		}
+	}))
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequestClientCert,
+	}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	// the self-signed cert bundle ends with the CA
+	return server, certPEM
+}
+
+func writeClientCert(t *testing.T, dir, cn string) {
+	certPEM, keyPEM, err := certutil.GenerateSelfSignedCertKey(cn, nil, nil)
+	require.NoError(t, err)
+	writeFile(t, filepath.Join(dir, "client.crt"), certPEM)
+	writeFile(t, filepath.Join(dir, "client.key"), keyPEM)
+}
+
+// writeFile replaces path atomically, such that a reload never sees a partial file.
+func writeFile(t *testing.T, path string, data []byte) {
+	require.NoError(t, ioutil.WriteFile(path+".tmp", data, 0600))
+	require.NoError(t, os.Rename(path+".tmp", path))
+}
+
+func get(transport http.RoundTripper, url string) (string, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	return string(body), err
+}
+
+func TestDynamicTransportReload(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	oldServer, oldCA := newTLSServer(t)
+	newServer, newCA := newTLSServer(t)
+
+	dir := t.TempDir()
+	writeClientCert(t, dir, "old-client")
+	writeFile(t, filepath.Join(dir, "ca.crt"), oldCA)
+
+	transport, err := newDynamicTransport(ctx, filepath.Join(dir, "client.crt"), filepath.Join(dir, "client.key"), filepath.Join(dir, "ca.crt"))
+	require.NoError(t, err)
+
+	cn, err := get(transport, oldServer.URL)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(cn, "old-client@"), "unexpected client CN %q", cn)
+	_, err = get(transport, newServer.URL)
+	require.Error(t, err, "server certificate of another CA should not be trusted")
+
+	t.Log("Rotating the backend CA")
+	writeFile(t, filepath.Join(dir, "ca.crt"), newCA)
+	require.Eventually(t, func() bool {
+		_, err := get(transport, newServer.URL)
+		return err == nil
+	}, wait.ForeverTestTimeout, 100*time.Millisecond, "new CA should be picked up")
+	_, err = get(transport, oldServer.URL)
+	require.Error(t, err, "old CA should not be trusted anymore")
+
+	t.Log("Rotating the client certificate")
+	writeClientCert(t, dir, "new-client")
+	require.Eventually(t, func() bool {
+		cn, err := get(transport, newServer.URL)
+		return err == nil && strings.HasPrefix(cn, "new-client@")
+	}, wait.ForeverTestTimeout, 100*time.Millisecond, "new client certificate should be picked up")
+}
+
+func TestDynamicTransportUpgradesUseHTTP1(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	certPEM, keyPEM, err := certutil.GenerateSelfSignedCertKey("localhost", []net.IP{net.ParseIP("127.0.0.1")}, nil)
+	require.NoError(t, err)
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte(req.Proto)) // nolint:errcheck
+	}))
+	server.EnableHTTP2 = true
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{cert}}
+	server.StartTLS()
+	defer server.Close()
+
+	dir := t.TempDir()
+	writeClientCert(t, dir, "proxy")
+	writeFile(t, filepath.Join(dir, "ca.crt"), certPEM)
+	transport, err := newDynamicTransport(ctx, filepath.Join(dir, "client.crt"), filepath.Join(dir, "client.key"), filepath.Join(dir, "ca.crt"))
+	require.NoError(t, err)
+
+	proto, err := get(transport, server.URL)
+	require.NoError(t, err)
+	require.Equal(t, "HTTP/2.0", proto)
+
+	for _, protocol := range []string{"websocket", "SPDY/3.1"} {
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+		req.Header.Set("Connection", "Upgrade")
+		req.Header.Set("Upgrade", protocol)
+		resp, err := transport.RoundTrip(req)
+		require.NoError(t, err)
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		require.NoError(t, err)
+		require.Equal(t, "HTTP/1.1", string(body), "upgrade to %s must not use HTTP/2", protocol)
+	}
+}
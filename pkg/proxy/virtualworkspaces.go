@@ -0,0 +1,187 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	kcpclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
+	kcpinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
+	proxyoptions "github.com/kcp-dev/kcp/pkg/proxy/options"
+)
+
+const resyncPeriod = 10 * time.Hour
+
+// virtualWorkspaceRouter forwards the requests under /services/<name>/ to the
+// server of the VirtualWorkspace <name>, and all other requests to the delegate.
+// Paths of the delegate more specific than /services/, i.e. of virtual
+// workspaces in the mapping file, take precedence.
+type virtualWorkspaceRouter struct {
+	ctx      context.Context
+	delegate http.Handler
+	reserved []string
+
+	clientCertFile, clientKeyFile string
+
+	lock     sync.RWMutex
+	backends map[string]*virtualWorkspaceBackend
+}
+
+type virtualWorkspaceBackend struct {
+	spec    tenancyv1alpha1.VirtualWorkspaceSpec
+	handler http.Handler
+	// cancel stops watching the client certificate of the backend.
+	cancel context.CancelFunc
+}
+
+// newVirtualWorkspaceRouter returns a router for the VirtualWorkspaces in the root workspace,
+// which are watched until ctx is done.
+func newVirtualWorkspaceRouter(ctx context.Context, delegate http.Handler, mapping []PathMapping, o *proxyoptions.Options) (*virtualWorkspaceRouter, error) {
+	client, err := newRootKcpClient(o)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &virtualWorkspaceRouter{
+		ctx:            ctx,
+		delegate:       delegate,
+		clientCertFile: o.VirtualWorkspaceClientCertFile,
+		clientKeyFile:  o.VirtualWorkspaceClientKeyFile,
+		backends:       map[string]*virtualWorkspaceBackend{},
+	}
+	for _, m := range mapping {
+		if strings.HasPrefix(m.Path, tenancyv1alpha1.VirtualWorkspacePathPrefix) && m.Path != tenancyv1alpha1.VirtualWorkspacePathPrefix {
+			r.reserved = append(r.reserved, m.Path)
+		}
+	}
+
+	informers := kcpinformers.NewSharedInformerFactoryWithOptions(client, resyncPeriod)
+	informers.Tenancy().V1alpha1().VirtualWorkspaces().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { r.set(obj.(*tenancyv1alpha1.VirtualWorkspace)) },
+		UpdateFunc: func(_, obj interface{}) { r.set(obj.(*tenancyv1alpha1.VirtualWorkspace)) },
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if vw, ok := obj.(*tenancyv1alpha1.VirtualWorkspace); ok {
+				r.remove(vw.Name)
+			}
+		},
+	})
+	informers.Start(ctx.Done())
+
+	return r, nil
+}
+
+func newRootKcpClient(o *proxyoptions.Options) (kcpclient.Interface, error) {
+	config, err := clientcmd.BuildConfigFromFlags("", o.RootKubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load root kubeconfig %q: %w", o.RootKubeconfig, err)
+	}
+	return kcpclient.NewForConfig(config)
+}
+
+func (r *virtualWorkspaceRouter) set(vw *tenancyv1alpha1.VirtualWorkspace) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	old, ok := r.backends[vw.Name]
+	if ok && equality.Semantic.DeepEqual(old.spec, vw.Spec) {
+		return
+	}
+	if ok {
+		old.cancel()
+		delete(r.backends, vw.Name)
+	}
+
+	ctx, cancel := context.WithCancel(r.ctx)
+	p, err := NewReverseProxyWithCABundle(ctx, vw.Spec.URL, r.clientCertFile, r.clientKeyFile, vw.Spec.CABundle)
+	if err != nil {
+		cancel()
+		klog.Errorf("Failed to forward to VirtualWorkspace %q: %v", vw.Name, err)
+		return
+	}
+
+	klog.V(2).Infof("Forwarding %s%s/ to %s", tenancyv1alpha1.VirtualWorkspacePathPrefix, vw.Name, vw.Spec.URL)
+	r.backends[vw.Name] = &virtualWorkspaceBackend{
+		spec:    *vw.Spec.DeepCopy(),
+		handler: http.HandlerFunc(ProxyHandler(p, "X-Remote-User", "X-Remote-Group")),
+		cancel:  cancel,
+	}
+}
+
+func (r *virtualWorkspaceRouter) remove(name string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if old, ok := r.backends[name]; ok {
+		klog.V(2).Infof("Stopped forwarding %s%s/", tenancyv1alpha1.VirtualWorkspacePathPrefix, name)
+		old.cancel()
+		delete(r.backends, name)
+	}
+}
+
+func (r *virtualWorkspaceRouter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if name := virtualWorkspaceName(req.URL.Path); name != "" && !r.isReserved(req.URL.Path) {
+		r.lock.RLock()
+		backend, ok := r.backends[name]
+		r.lock.RUnlock()
+
+		if ok {
+			backend.handler.ServeHTTP(w, req)
+			return
+		}
+	}
+
+	r.delegate.ServeHTTP(w, req)
+}
+
+// isReserved returns whether the path is matched by a path of the mapping file
+// under /services/, following the rules of http.ServeMux.
+func (r *virtualWorkspaceRouter) isReserved(path string) bool {
+	for _, reserved := range r.reserved {
+		if path == reserved || (strings.HasSuffix(reserved, "/") && strings.HasPrefix(path, reserved)) {
+			return true
+		}
+	}
+	return false
+}
+
+// virtualWorkspaceName returns the name of the virtual workspace of paths like
+// /services/<name>/..., or an empty string for other paths.
+func virtualWorkspaceName(path string) string {
+	if !strings.HasPrefix(path, tenancyv1alpha1.VirtualWorkspacePathPrefix) {
+		return ""
+	}
+	rest := strings.TrimPrefix(path, tenancyv1alpha1.VirtualWorkspacePathPrefix)
+	i := strings.Index(rest, "/")
+	if i <= 0 {
+		return ""
+	}
+	return rest[:i]
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+func TestVirtualWorkspaceRouter(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dir := t.TempDir()
+	writeClientCert(t, dir, "front-proxy")
+	server, caPEM := newTLSServer(t)
+
+	r := &virtualWorkspaceRouter{
+		ctx: ctx,
+		delegate: http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.Write([]byte("delegate")) // nolint:errcheck
+		}),
+		reserved:       []string{"/services/workspaces/"},
+		clientCertFile: filepath.Join(dir, "client.crt"),
+		clientKeyFile:  filepath.Join(dir, "client.key"),
+		backends:       map[string]*virtualWorkspaceBackend{},
+	}
+	serve := func(path string) string {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		body, err := ioutil.ReadAll(rec.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+	register := func(name, url string) {
+		r.set(&tenancyv1alpha1.VirtualWorkspace{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       tenancyv1alpha1.VirtualWorkspaceSpec{URL: url, CABundle: caPEM},
+		})
+	}
+
+	require.Equal(t, "delegate", serve("/services/foo/clusters/root/api"), "unregistered")
+
+	register("foo", server.URL)
+	register("workspaces", server.URL)
+	require.Equal(t, "front-proxy", serve("/services/foo/clusters/root/api"), "forwarded with the client certificate")
+	require.Equal(t, "delegate", serve("/services/foo"), "not under the prefix")
+	require.Equal(t, "delegate", serve("/clusters/root/api"))
+	require.Equal(t, "delegate", serve("/services/workspaces/root/personal"), "reserved by the mapping file")
+
+	register("foo", "https://127.0.0.1:1")
+	require.NotEqual(t, "front-proxy", serve("/services/foo/clusters/root/api"), "updated")
+
+	r.remove("foo")
+	require.Equal(t, "delegate", serve("/services/foo/clusters/root/api"), "removed")
+}
+
+func TestVirtualWorkspaceName(t *testing.T) {
+	for path, name := range map[string]string{
+		"/services/foo/clusters/root": "foo",
+		"/services/foo/":              "foo",
+		"/services/foo":               "",
+		"/services//":                 "",
+		"/clusters/root":              "",
+	} {
+		require.Equal(t, name, virtualWorkspaceName(path), path)
+	}
+}
@@ -45,6 +45,7 @@ import (
 	kcpclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
 	apisinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/apis/v1alpha1"
 	apislisters "github.com/kcp-dev/kcp/pkg/client/listers/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/reconciler/latency"
 )
 
 const (
@@ -64,7 +65,7 @@ func NewController(
 	apiResourceSchemaInformer apisinformers.APIResourceSchemaInformer,
 	crdInformer apiextensionsinformers.CustomResourceDefinitionInformer,
 ) (*controller, error) {
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+	queue := latency.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
 
 	c := &controller{
 		queue:            queue,
@@ -120,7 +121,7 @@ func NewController(
 	})
 
 	if err := apiBindingInformer.Informer().AddIndexers(cache.Indexers{
-		indexAPIBindingsByWorkspaceExport:       indexAPIBindingsByWorkspaceExportFunc,
+		IndexAPIBindingsByWorkspaceExport:       indexAPIBindingsByWorkspaceExportFunc,
 		IndexAPIBindingsByIdentityGroupResource: indexAPIBindingsByIdentityGroupResourceFunc,
 	}); err != nil {
 		return nil, err
@@ -155,6 +156,25 @@ func NewController(
 		},
 	})
 
+	// CRDs in workspaces can overlap bound resources
+	crdInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj interface{}) bool {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			crd, ok := obj.(*apiextensionsv1.CustomResourceDefinition)
+			if !ok {
+				return false
+			}
+
+			return logicalcluster.From(crd) != ShadowWorkspaceName
+		},
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { c.enqueueLocalCRD(obj) },
+			DeleteFunc: func(obj interface{}) { c.enqueueLocalCRD(obj) },
+		},
+	})
+
 	apiResourceSchemaInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    func(obj interface{}) { c.enqueueAPIResourceSchema(obj) },
 		UpdateFunc: func(_, obj interface{}) { c.enqueueAPIResourceSchema(obj) },
@@ -224,7 +244,7 @@ func (c *controller) enqueueAPIExport(obj interface{}) {
 	}
 
 	klog.V(2).Infof("Mapping APIExport %q", key)
-	bindingsForExport, err := c.apiBindingsIndexer.ByIndex(indexAPIBindingsByWorkspaceExport, key)
+	bindingsForExport, err := c.apiBindingsIndexer.ByIndex(IndexAPIBindingsByWorkspaceExport, key)
 	if err != nil {
 		runtime.HandleError(err)
 		return
@@ -336,6 +356,7 @@ func (c *controller) process(ctx context.Context, key string) error {
 	obj = obj.DeepCopy()
 
 	reconcileErr := c.reconcile(ctx, obj)
+	c.reconcileCRDConflicts(obj)
 
 	// Regardless of whether reconcile returned an error or not, always try to patch status if needed. Return the
 	// reconciliation error at the end.
@@ -26,7 +26,7 @@ import (
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
 )
 
-const indexAPIBindingsByWorkspaceExport = "apiBindingsByWorkspaceExport"
+const IndexAPIBindingsByWorkspaceExport = "apiBindingsByWorkspaceExport"
 
 // indexAPIBindingsByWorkspaceExportFunc is an index function that maps an APIBinding to the key for its
 // spec.reference.workspace.
@@ -32,6 +32,7 @@ import (
 	"k8s.io/klog/v2"
 
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/immutablefields"
 	conditionsv1alpha1 "github.com/kcp-dev/kcp/third_party/conditions/apis/conditions/v1alpha1"
 	"github.com/kcp-dev/kcp/third_party/conditions/util/conditions"
 )
@@ -435,6 +436,31 @@ func (c *controller) reconcileBound(ctx context.Context, apiBinding *apisv1alpha
 	}
 
 	if apiExportLatestResourceSchemasChanged(apiBinding, exportedSchemas) {
+		// don't strand objects which are invalid according to the new schemas
+		report := apiBinding.Status.SchemaCompatibility
+		if report == nil || !sets.NewString(report.SchemaUIDs...).Equal(schemaUIDs(exportedSchemas)) {
+			conditions.MarkFalse(
+				apiBinding,
+				apisv1alpha1.BindingUpToDate,
+				apisv1alpha1.WaitingForSchemaCompatibilityReason,
+				conditionsv1alpha1.ConditionSeverityInfo,
+				"Waiting for the objects to be validated against the latest resource schemas of the APIExport",
+			)
+			return nil
+		}
+		if report.InvalidObjectCount > 0 {
+			conditions.MarkFalse(
+				apiBinding,
+				apisv1alpha1.BindingUpToDate,
+				apisv1alpha1.IncompatibleObjectsReason,
+				conditionsv1alpha1.ConditionSeverityError,
+				"%d of %d objects are invalid according to the latest resource schemas of the APIExport, see status.schemaCompatibility",
+				report.InvalidObjectCount,
+				report.CheckedObjects,
+			)
+			return nil
+		}
+
 		klog.V(4).Infof("APIBinding %s|%s needs rebinding because the APIExport's latestResourceSchemas has changed", apiBinding.ClusterName, apiBinding.Name)
 
 		apiBinding.Status.Phase = apisv1alpha1.APIBindingPhaseBinding
@@ -472,8 +498,13 @@ func generateCRD(schema *apisv1alpha1.APIResourceSchema) (*apiextensionsv1.Custo
 			AdditionalPrinterColumns: version.AdditionalPrinterColumns,
 		}
 
+		// immutable fields are enforced by transition rules of the bound CRD.
+		raw, err := immutablefields.ToValidationRules(version.Schema.Raw)
+		if err != nil {
+			return nil, err
+		}
 		var validation apiextensionsv1.CustomResourceValidation
-		if err := json.Unmarshal(version.Schema.Raw, &validation.OpenAPIV3Schema); err != nil {
+		if err := json.Unmarshal(raw, &validation.OpenAPIV3Schema); err != nil {
 			return nil, err
 		}
 		crdVersion.Schema = &validation
@@ -506,10 +537,7 @@ func referencedAPIExportChanged(apiBinding *apisv1alpha1.APIBinding) bool {
 }
 
 func apiExportLatestResourceSchemasChanged(apiBinding *apisv1alpha1.APIBinding, exportedSchemas []*apisv1alpha1.APIResourceSchema) bool {
-	exportedSchemaUIDs := sets.NewString()
-	for _, exportedSchema := range exportedSchemas {
-		exportedSchemaUIDs.Insert(string(exportedSchema.UID))
-	}
+	exportedSchemaUIDs := schemaUIDs(exportedSchemas)
 
 	boundSchemaUIDs := sets.NewString()
 	for _, boundResource := range apiBinding.Status.BoundResources {
@@ -518,3 +546,11 @@ func apiExportLatestResourceSchemasChanged(apiBinding *apisv1alpha1.APIBinding,
 
 	return !exportedSchemaUIDs.Equal(boundSchemaUIDs)
 }
+
+func schemaUIDs(schemas []*apisv1alpha1.APIResourceSchema) sets.String {
+	uids := sets.NewString()
+	for _, schema := range schemas {
+		uids.Insert(string(schema.UID))
+	}
+	return uids
+}
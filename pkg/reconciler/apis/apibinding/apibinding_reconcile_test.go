@@ -631,6 +631,7 @@ func TestReconcileBound(t *testing.T) {
 		wantBound             bool
 		wantError             bool
 		wantAPIExportNotFound bool
+		wantNotUpToDateReason string
 	}{
 		"bound becomes binding when referenced export changes": {
 			apiBinding: bound.DeepCopy().
@@ -639,7 +640,7 @@ func TestReconcileBound(t *testing.T) {
 			wantBinding: true,
 		},
 		"bound becomes binding when export changes what it's exporting": {
-			apiBinding: bound.Build(),
+			apiBinding: bound.DeepCopy().WithSchemaCompatibility(0, "uid1", "uid3").Build(),
 			apiExport: &apisv1alpha1.APIExport{
 				Spec: apisv1alpha1.APIExportSpec{
 					LatestResourceSchemas: []string{"someresources", "moreresources"},
@@ -662,7 +663,7 @@ func TestReconcileBound(t *testing.T) {
 			wantBinding: true,
 		},
 		"bound becomes binding when bound APIResourceSchema UID changes": {
-			apiBinding: bound.Build(),
+			apiBinding: bound.DeepCopy().WithSchemaCompatibility(0, "uid1", "newuid").Build(),
 			apiExport: &apisv1alpha1.APIExport{
 				Spec: apisv1alpha1.APIExportSpec{
 					LatestResourceSchemas: []string{"someresources", "otherresources"},
@@ -684,6 +685,54 @@ func TestReconcileBound(t *testing.T) {
 			},
 			wantBinding: true,
 		},
+		"bound stays bound until objects are validated against the new schemas": {
+			apiBinding: bound.DeepCopy().WithSchemaCompatibility(0, "uid1", "uid2").Build(),
+			apiExport: &apisv1alpha1.APIExport{
+				Spec: apisv1alpha1.APIExportSpec{
+					LatestResourceSchemas: []string{"someresources", "otherresources"},
+				},
+			},
+			apiResourceSchemas: map[string]*apisv1alpha1.APIResourceSchema{
+				"someresources": {
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "someresources",
+						UID:  "uid1",
+					},
+				},
+				"otherresources": {
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "otherresources",
+						UID:  "newuid",
+					},
+				},
+			},
+			wantBound:             true,
+			wantNotUpToDateReason: apisv1alpha1.WaitingForSchemaCompatibilityReason,
+		},
+		"bound stays bound when objects are invalid according to the new schemas": {
+			apiBinding: bound.DeepCopy().WithSchemaCompatibility(3, "uid1", "newuid").Build(),
+			apiExport: &apisv1alpha1.APIExport{
+				Spec: apisv1alpha1.APIExportSpec{
+					LatestResourceSchemas: []string{"someresources", "otherresources"},
+				},
+			},
+			apiResourceSchemas: map[string]*apisv1alpha1.APIResourceSchema{
+				"someresources": {
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "someresources",
+						UID:  "uid1",
+					},
+				},
+				"otherresources": {
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "otherresources",
+						UID:  "newuid",
+					},
+				},
+			},
+			wantBound:             true,
+			wantNotUpToDateReason: apisv1alpha1.IncompatibleObjectsReason,
+		},
 		"APIExportValid warning condition set when error getting previously bound APIExport": {
 			apiBinding:            bound.Build(),
 			getAPIExportError:     apierrors.NewNotFound(schema.GroupResource{}, "foo"),
@@ -727,6 +776,14 @@ func TestReconcileBound(t *testing.T) {
 					Reason:   apisv1alpha1.APIExportNotFoundReason,
 				})
 			}
+
+			if tc.wantNotUpToDateReason != "" {
+				requireConditionMatches(t, tc.apiBinding, &conditionsv1alpha1.Condition{
+					Type:   apisv1alpha1.BindingUpToDate,
+					Status: corev1.ConditionFalse,
+					Reason: tc.wantNotUpToDateReason,
+				})
+			}
 		})
 	}
 }
@@ -945,6 +1002,38 @@ func TestCRDFromAPIResourceSchema(t *testing.T) {
 	}
 }
 
+func TestCRDFromAPIResourceSchemaImmutableFields(t *testing.T) {
+	schema := &apisv1alpha1.APIResourceSchema{
+		ObjectMeta: metav1.ObjectMeta{ClusterName: "my-cluster", Name: "my-name", UID: types.UID("my-uuid")},
+		Spec: apisv1alpha1.APIResourceSchemaSpec{
+			Group: "my-group",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets", Singular: "widget", Kind: "Widget", ListKind: "WidgetList"},
+			Scope: apiextensionsv1.NamespaceScoped,
+			Versions: []apisv1alpha1.APIResourceVersion{
+				{
+					Name:    "v1",
+					Served:  true,
+					Storage: true,
+					Schema: runtime.RawExtension{
+						Raw: []byte(`{"type":"object","properties":{"spec":{"type":"object","properties":{"class":{"type":"string","x-kcp-immutable":true}}}}}`),
+					},
+				},
+			},
+		},
+	}
+
+	got, err := generateCRD(schema)
+	require.NoError(t, err)
+
+	spec := got.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["spec"]
+	require.Equal(t, apiextensionsv1.ValidationRules{
+		{Rule: "has(self.class) == has(oldSelf.class)", Message: "class is immutable"},
+	}, spec.XValidations)
+	require.Equal(t, apiextensionsv1.ValidationRules{
+		{Rule: "self == oldSelf", Message: "is immutable"},
+	}, spec.Properties["class"].XValidations)
+}
+
 // TODO(ncdc): this is a modified copy from apibinding admission. Unify these into a reusable package.
 type bindingBuilder struct {
 	apisv1alpha1.APIBinding
@@ -1009,6 +1098,15 @@ func (b *bindingBuilder) WithBoundResources(boundResources ...apisv1alpha1.Bound
 	return b
 }
 
+func (b *bindingBuilder) WithSchemaCompatibility(invalidObjects int64, schemaUIDs ...string) *bindingBuilder {
+	b.Status.SchemaCompatibility = &apisv1alpha1.SchemaCompatibilityReport{
+		SchemaUIDs:         schemaUIDs,
+		CheckedObjects:     10,
+		InvalidObjectCount: invalidObjects,
+	}
+	return b
+}
+
 type boundAPIResourceBuilder struct {
 	apisv1alpha1.BoundAPIResource
 }
@@ -0,0 +1,119 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apibinding
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/third_party/conditions/util/conditions"
+)
+
+// CRDNameForGroupResource returns the name of a CRD in a workspace defining group and resource.
+func CRDNameForGroupResource(group, resource string) string {
+	if group == "" {
+		return resource + ".core"
+	}
+	return resource + "." + group
+}
+
+// ConflictPolicy returns the policy of the APIBinding for CRDs in its workspace overlapping its bound resources.
+func ConflictPolicy(apiBinding *apisv1alpha1.APIBinding) apisv1alpha1.CRDConflictPolicy {
+	if apiBinding.Spec.ConflictPolicy == "" {
+		return apisv1alpha1.CRDConflictPolicyAPIBinding
+	}
+	return apiBinding.Spec.ConflictPolicy
+}
+
+// reconcileCRDConflicts sets the NoCRDConflicts condition, depending on whether CRDs in the workspace
+// define the same group and resource as one of the bound resources.
+func (c *controller) reconcileCRDConflicts(apiBinding *apisv1alpha1.APIBinding) {
+	clusterName := logicalcluster.From(apiBinding)
+
+	var overlapping []string
+	for _, boundResource := range apiBinding.Status.BoundResources {
+		name := CRDNameForGroupResource(boundResource.Group, boundResource.Resource)
+		if _, err := c.getCRD(clusterName, name); err != nil {
+			if !apierrors.IsNotFound(err) {
+				klog.Errorf("Error getting CRD %s|%s for APIBinding %s|%s: %v", clusterName, name, clusterName, apiBinding.Name, err)
+			}
+			continue
+		}
+		overlapping = append(overlapping, name)
+	}
+
+	if len(overlapping) == 0 {
+		conditions.MarkTrue(apiBinding, apisv1alpha1.NoCRDConflicts)
+		return
+	}
+
+	sort.Strings(overlapping)
+	names := strings.Join(overlapping, ", ")
+
+	switch ConflictPolicy(apiBinding) {
+	case apisv1alpha1.CRDConflictPolicyCRD:
+		conditions.MarkFalse(
+			apiBinding,
+			apisv1alpha1.NoCRDConflicts,
+			apisv1alpha1.ShadowedByCRDReason,
+			conditionsv1alpha1.ConditionSeverityError,
+			"Bound resources %s are not served because CRDs in the workspace define them. Delete the CRDs, or set spec.conflictPolicy to APIBinding to serve the bound resources instead",
+			names,
+		)
+	default:
+		conditions.MarkFalse(
+			apiBinding,
+			apisv1alpha1.NoCRDConflicts,
+			apisv1alpha1.CRDShadowedReason,
+			conditionsv1alpha1.ConditionSeverityWarning,
+			"CRDs %s in the workspace are not served because this APIBinding binds the same resources. Delete the CRDs, or set spec.conflictPolicy to CRD to serve them instead",
+			names,
+		)
+	}
+}
+
+// enqueueLocalCRD enqueues the APIBindings in the workspace of a CRD that is not a bound CRD.
+func (c *controller) enqueueLocalCRD(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	crd, ok := obj.(*apiextensionsv1.CustomResourceDefinition)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("obj is supposed to be a CustomResourceDefinition, but is %T", obj))
+		return
+	}
+
+	apiBindings, err := c.listAPIBindings(logicalcluster.From(crd))
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	for _, apiBinding := range apiBindings {
+		c.enqueueAPIBinding(apiBinding)
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apibinding
+
+import (
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/third_party/conditions/apis/conditions/v1alpha1"
+)
+
+func TestReconcileCRDConflicts(t *testing.T) {
+	tests := map[string]struct {
+		apiBinding *apisv1alpha1.APIBinding
+		localCRDs  []string
+		want       *conditionsv1alpha1.Condition
+	}{
+		"no bound resources": {
+			apiBinding: binding.Build(),
+			localCRDs:  []string{"someresources.mygroup"},
+			want:       &conditionsv1alpha1.Condition{Type: apisv1alpha1.NoCRDConflicts, Status: corev1.ConditionTrue},
+		},
+		"no overlapping CRDs": {
+			apiBinding: bound.Build(),
+			localCRDs:  []string{"someresources.othergroup"},
+			want:       &conditionsv1alpha1.Condition{Type: apisv1alpha1.NoCRDConflicts, Status: corev1.ConditionTrue},
+		},
+		"overlapping CRDs are shadowed by default": {
+			apiBinding: bound.Build(),
+			localCRDs:  []string{"someresources.mygroup", "otherresources.anothergroup"},
+			want: &conditionsv1alpha1.Condition{
+				Type:     apisv1alpha1.NoCRDConflicts,
+				Status:   corev1.ConditionFalse,
+				Severity: conditionsv1alpha1.ConditionSeverityWarning,
+				Reason:   apisv1alpha1.CRDShadowedReason,
+				Message:  "CRDs otherresources.anothergroup, someresources.mygroup in the workspace are not served",
+			},
+		},
+		"bound resources are shadowed with CRD policy": {
+			apiBinding: bound.DeepCopy().WithConflictPolicy(apisv1alpha1.CRDConflictPolicyCRD).Build(),
+			localCRDs:  []string{"someresources.mygroup"},
+			want: &conditionsv1alpha1.Condition{
+				Type:     apisv1alpha1.NoCRDConflicts,
+				Status:   corev1.ConditionFalse,
+				Severity: conditionsv1alpha1.ConditionSeverityError,
+				Reason:   apisv1alpha1.ShadowedByCRDReason,
+				Message:  "Bound resources someresources.mygroup are not served",
+			},
+		},
+		"CRDs of other workspaces are ignored": {
+			apiBinding: bound.Build(),
+			localCRDs:  []string{"org:other|someresources.mygroup"},
+			want:       &conditionsv1alpha1.Condition{Type: apisv1alpha1.NoCRDConflicts, Status: corev1.ConditionTrue},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &controller{
+				getCRD: func(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error) {
+					for _, crd := range tc.localCRDs {
+						if (crd == name && clusterName.String() == "org:ws") || crd == clusterName.String()+"|"+name {
+							return &apiextensionsv1.CustomResourceDefinition{}, nil
+						}
+					}
+					return nil, apierrors.NewNotFound(apiextensionsv1.Resource("customresourcedefinitions"), name)
+				},
+			}
+
+			c.reconcileCRDConflicts(tc.apiBinding)
+
+			requireConditionMatches(t, tc.apiBinding, tc.want)
+		})
+	}
+}
+
+func TestCRDNameForGroupResource(t *testing.T) {
+	require.Equal(t, "widgets.example.io", CRDNameForGroupResource("example.io", "widgets"))
+	require.Equal(t, "pods.core", CRDNameForGroupResource("", "pods"))
+}
+
+func (b *bindingBuilder) WithConflictPolicy(policy apisv1alpha1.CRDConflictPolicy) *bindingBuilder {
+	b.Spec.ConflictPolicy = policy
+	return b
+}
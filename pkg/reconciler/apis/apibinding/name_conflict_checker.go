@@ -96,7 +96,7 @@ func (ncc *nameConflictChecker) checkForConflicts(crd *apiextensionsv1.CustomRes
 	}
 
 	for _, boundCRD := range ncc.boundCRDs {
-		if namesConflict(boundCRD, crd) {
+		if NamesConflict(boundCRD, crd) {
 			conflict := ncc.crdToBinding[boundCRD.Name]
 			return fmt.Errorf("naming conflict with APIBinding %s", conflict.Name)
 		}
@@ -105,7 +105,8 @@ func (ncc *nameConflictChecker) checkForConflicts(crd *apiextensionsv1.CustomRes
 	return nil
 }
 
-func namesConflict(existing, incoming *apiextensionsv1.CustomResourceDefinition) bool {
+// NamesConflict returns whether the names of incoming collide with the accepted names of existing.
+func NamesConflict(existing, incoming *apiextensionsv1.CustomResourceDefinition) bool {
 	existingNames := sets.NewString()
 	existingNames.Insert(existing.Status.AcceptedNames.Plural)
 	existingNames.Insert(existing.Status.AcceptedNames.Singular)
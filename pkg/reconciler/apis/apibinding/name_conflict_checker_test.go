@@ -151,23 +151,23 @@ func TestNamesConflict(t *testing.T) {
 	names := []string{"a", "b", "c", "d"}
 	for _, v := range names {
 		t.Run(fmt.Sprintf("plural-%s", v), func(t *testing.T) {
-			require.True(t, namesConflict(existing, crdWithNames(apiextensionsv1.CustomResourceDefinitionNames{Plural: v})))
+			require.True(t, NamesConflict(existing, crdWithNames(apiextensionsv1.CustomResourceDefinitionNames{Plural: v})))
 		})
 		t.Run(fmt.Sprintf("singular-%s", v), func(t *testing.T) {
-			require.True(t, namesConflict(existing, crdWithNames(apiextensionsv1.CustomResourceDefinitionNames{Singular: v})))
+			require.True(t, NamesConflict(existing, crdWithNames(apiextensionsv1.CustomResourceDefinitionNames{Singular: v})))
 		})
 		t.Run(fmt.Sprintf("shortnames-%s", v), func(t *testing.T) {
-			require.True(t, namesConflict(existing, crdWithNames(apiextensionsv1.CustomResourceDefinitionNames{ShortNames: []string{v}})))
+			require.True(t, NamesConflict(existing, crdWithNames(apiextensionsv1.CustomResourceDefinitionNames{ShortNames: []string{v}})))
 		})
 	}
 
 	kinds := []string{"e", "f"}
 	for _, v := range kinds {
 		t.Run(fmt.Sprintf("kind-%s", v), func(t *testing.T) {
-			require.True(t, namesConflict(existing, crdWithNames(apiextensionsv1.CustomResourceDefinitionNames{Kind: v})))
+			require.True(t, NamesConflict(existing, crdWithNames(apiextensionsv1.CustomResourceDefinitionNames{Kind: v})))
 		})
 		t.Run(fmt.Sprintf("listkind-%s", v), func(t *testing.T) {
-			require.True(t, namesConflict(existing, crdWithNames(apiextensionsv1.CustomResourceDefinitionNames{ListKind: v})))
+			require.True(t, NamesConflict(existing, crdWithNames(apiextensionsv1.CustomResourceDefinitionNames{ListKind: v})))
 		})
 	}
 }
@@ -33,18 +33,17 @@ import (
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
-	coreinformers "k8s.io/client-go/informers/core/v1"
-	"k8s.io/client-go/kubernetes"
-	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clusters"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 
+	"github.com/kcp-dev/kcp/pkg/apiexportidentity"
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
 	kcpclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
 	apisinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/apis/v1alpha1"
 	apislisters "github.com/kcp-dev/kcp/pkg/client/listers/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/reconciler/latency"
 )
 
 const (
@@ -61,35 +60,20 @@ func NewController(
 	kcpClusterClient kcpclient.ClusterInterface,
 	apiExportInformer apisinformers.APIExportInformer,
 	apiResourceSchemaInformer apisinformers.APIResourceSchemaInformer,
-	kubeClusterClient kubernetes.ClusterInterface,
-	namespaceInformer coreinformers.NamespaceInformer,
-	secretInformer coreinformers.SecretInformer,
+	identityBackend apiexportidentity.Backend,
 ) (*controller, error) {
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+	queue := latency.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
 
 	c := &controller{
 		queue:             queue,
 		kcpClusterClient:  kcpClusterClient,
 		apiExportLister:   apiExportInformer.Lister(),
 		apiExportIndexer:  apiExportInformer.Informer().GetIndexer(),
-		kubeClusterClient: kubeClusterClient,
-		getNamespace: func(clusterName logicalcluster.Name, name string) (*corev1.Namespace, error) {
-			return namespaceInformer.Lister().Get(clusters.ToClusterAwareKey(clusterName, name))
-		},
-		createNamespace: func(ctx context.Context, clusterName logicalcluster.Name, ns *corev1.Namespace) error {
-			_, err := kubeClusterClient.Cluster(clusterName).CoreV1().Namespaces().Create(ctx, ns, metav1.CreateOptions{})
-			return err
-		},
-		secretLister:    secretInformer.Lister(),
-		secretNamespace: defaultIdentitySecretNamespace,
-		createSecret: func(ctx context.Context, clusterName logicalcluster.Name, secret *corev1.Secret) error {
-			_, err := kubeClusterClient.Cluster(clusterName).CoreV1().Secrets(secret.Namespace).Create(ctx, secret, metav1.CreateOptions{})
-			return err
-		},
+		secretNamespace:   defaultIdentitySecretNamespace,
+		getIdentityKey:    identityBackend.Get,
+		createIdentityKey: identityBackend.Create,
 	}
 
-	c.getSecret = c.readThroughGetSecret
-
 	if err := apiExportInformer.Informer().AddIndexers(
 		cache.Indexers{
 			IndexAPIExportByIdentity: func(obj interface{}) ([]string, error) {
@@ -132,22 +116,12 @@ func NewController(
 		},
 	})
 
-	secretInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc: func(obj interface{}) {
-			c.enqueueSecret(obj)
-		},
-		UpdateFunc: func(_, newObj interface{}) {
-			c.enqueueSecret(newObj)
-		},
-		DeleteFunc: func(obj interface{}) {
-			c.enqueueSecret(obj)
-		},
-	})
+	identityBackend.AddRotationHandler(c.enqueueIdentityKey)
 
 	return c, nil
 }
 
-// controller reconciles APIExports. It ensures an export's identity secret exists and is valid.
+// controller reconciles APIExports. It ensures an export's identity key exists and is valid.
 type controller struct {
 	queue workqueue.RateLimitingInterface
 
@@ -155,16 +129,10 @@ type controller struct {
 	apiExportLister  apislisters.APIExportLister
 	apiExportIndexer cache.Indexer
 
-	kubeClusterClient kubernetes.ClusterInterface
-
-	getNamespace    func(clusterName logicalcluster.Name, name string) (*corev1.Namespace, error)
-	createNamespace func(ctx context.Context, clusterName logicalcluster.Name, ns *corev1.Namespace) error
-
-	secretLister    corelisters.SecretLister
 	secretNamespace string
 
-	getSecret    func(ctx context.Context, clusterName logicalcluster.Name, ns, name string) (*corev1.Secret, error)
-	createSecret func(ctx context.Context, clusterName logicalcluster.Name, secret *corev1.Secret) error
+	getIdentityKey    func(ctx context.Context, clusterName logicalcluster.Name, ref corev1.SecretReference) ([]byte, error)
+	createIdentityKey func(ctx context.Context, clusterName logicalcluster.Name, ref corev1.SecretReference, key []byte) error
 }
 
 // enqueueAPIBinding enqueues an APIExport .
@@ -179,12 +147,10 @@ func (c *controller) enqueueAPIExport(obj interface{}) {
 	c.queue.Add(key)
 }
 
-func (c *controller) enqueueSecret(obj interface{}) {
-	secretKey, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
-	if err != nil {
-		runtime.HandleError(err)
-		return
-	}
+// enqueueIdentityKey enqueues the APIExports referencing a changed identity key.
+func (c *controller) enqueueIdentityKey(clusterName logicalcluster.Name, ref corev1.SecretReference) {
+	// TODO(ncdc): use future shared key func if we ever create one
+	secretKey := ref.Namespace + "/" + clusters.ToClusterAwareKey(clusterName, ref.Name)
 
 	apiExportKeys, err := c.apiExportIndexer.IndexKeys(indexAPIExportBySecret, secretKey)
 	if err != nil {
@@ -193,7 +159,7 @@ func (c *controller) enqueueSecret(obj interface{}) {
 	}
 
 	for _, key := range apiExportKeys {
-		klog.V(2).Infof("Queueing APIExport %q via identity secret %s", key, secretKey)
+		klog.V(2).Infof("Queueing APIExport %q via identity key %s", key, secretKey)
 		c.queue.Add(key)
 	}
 }
@@ -326,18 +292,3 @@ func (c *controller) patchIfNeeded(ctx context.Context, old, obj *apisv1alpha1.A
 	_, err = c.kcpClusterClient.Cluster(clusterName).ApisV1alpha1().APIExports().Patch(ctx, obj.Name, types.MergePatchType, patchBytes, metav1.PatchOptions{}, subresources...)
 	return err
 }
-
-func (c *controller) readThroughGetSecret(ctx context.Context, clusterName logicalcluster.Name, ns, name string) (*corev1.Secret, error) {
-	secret, err := c.secretLister.Secrets(ns).Get(clusters.ToClusterAwareKey(clusterName, name))
-	if err == nil {
-		return secret, nil
-	}
-
-	// In case the lister is slow to catch up, try a live read
-	secret, err = c.kubeClusterClient.Cluster(clusterName).CoreV1().Secrets(ns).Get(ctx, name, metav1.GetOptions{})
-	if err != nil {
-		return nil, err
-	}
-
-	return secret, nil
-}
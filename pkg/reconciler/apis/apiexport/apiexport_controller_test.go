@@ -88,6 +88,13 @@ func TestReconcile(t *testing.T) {
 
 			wantVerifyFailure: true,
 		},
+		"identity verification fails when key is missing": {
+			secretRefSet: true,
+			secretExists: true,
+			keyMissing:   true,
+
+			wantVerifyFailure: true,
+		},
 		"identity verification fails when hash from secret's key differs with APIExport's hash": {
 			secretRefSet:                         true,
 			secretExists:                         true,
@@ -117,32 +124,24 @@ func TestReconcile(t *testing.T) {
 			someOtherKey := "def"
 
 			c := &controller{
-				getNamespace: func(clusterName logicalcluster.Name, name string) (*corev1.Namespace, error) {
-					return &corev1.Namespace{}, nil
-				},
-				createNamespace: func(ctx context.Context, clusterName logicalcluster.Name, ns *corev1.Namespace) error {
-					return nil
-				},
 				secretNamespace: "default-ns",
-				getSecret: func(ctx context.Context, clusterName logicalcluster.Name, ns, name string) (*corev1.Secret, error) {
+				getIdentityKey: func(ctx context.Context, clusterName logicalcluster.Name, ref corev1.SecretReference) ([]byte, error) {
 					if tc.secretExists {
-						secret := &corev1.Secret{
-							Data: map[string][]byte{},
+						if tc.keyMissing {
+							return nil, nil
 						}
-						if !tc.keyMissing {
-							if tc.secretHashDoesntMatchAPIExportStatus {
-								secret.Data[apisv1alpha1.SecretKeyAPIExportIdentity] = []byte(someOtherKey)
-							} else {
-								secret.Data[apisv1alpha1.SecretKeyAPIExportIdentity] = []byte(expectedKey)
-							}
+						if tc.secretHashDoesntMatchAPIExportStatus {
+							return []byte(someOtherKey), nil
 						}
-						return secret, nil
+						return []byte(expectedKey), nil
 					}
 
-					return nil, apierrors.NewNotFound(corev1.Resource("secrets"), name)
+					return nil, apierrors.NewNotFound(corev1.Resource("secrets"), ref.Name)
 				},
-				createSecret: func(ctx context.Context, clusterName logicalcluster.Name, secret *corev1.Secret) error {
+				createIdentityKey: func(ctx context.Context, clusterName logicalcluster.Name, ref corev1.SecretReference, key []byte) error {
 					createSecretCalled = true
+					require.Equal(t, corev1.SecretReference{Namespace: "default-ns", Name: "my-export"}, ref)
+					require.NotEmpty(t, key)
 					return tc.createSecretError
 				},
 			}
@@ -27,9 +27,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/util/keyutil"
-	"k8s.io/klog/v2"
 
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
 	conditionsv1alpha1 "github.com/kcp-dev/kcp/third_party/conditions/apis/conditions/v1alpha1"
@@ -45,25 +43,28 @@ func (c *controller) reconcile(ctx context.Context, apiExport *apisv1alpha1.APIE
 	clusterName := logicalcluster.From(apiExport)
 
 	if identity.SecretRef == nil {
-		c.ensureSecretNamespaceExists(ctx, clusterName)
+		ref := corev1.SecretReference{
+			Namespace: c.secretNamespace,
+			Name:      apiExport.Name,
+		}
 
-		// See if the generated secret already exists (for whatever reason)
-		_, err := c.getSecret(ctx, clusterName, c.secretNamespace, apiExport.Name)
+		// See if the generated key already exists (for whatever reason)
+		_, err := c.getIdentityKey(ctx, clusterName, ref)
 		if err != nil && !errors.IsNotFound(err) {
-			return fmt.Errorf("error checking if APIExport %s|%s identity secret %s|%s/%s exists: %w",
+			return fmt.Errorf("error checking if APIExport %s|%s identity key %s|%s/%s exists: %w",
 				clusterName, apiExport.Name,
-				clusterName, c.secretNamespace, apiExport.Name,
+				clusterName, ref.Namespace, ref.Name,
 				err,
 			)
 		}
 		if errors.IsNotFound(err) {
-			if err := c.createIdentitySecret(ctx, clusterName, apiExport.Name); err != nil {
+			if err := c.createIdentity(ctx, clusterName, ref); err != nil {
 				conditions.MarkFalse(
 					apiExport,
 					apisv1alpha1.APIExportIdentityValid,
 					apisv1alpha1.IdentityGenerationFailedReason,
 					conditionsv1alpha1.ConditionSeverityError,
-					"Error creating identity secret: %v",
+					"Error creating identity key: %v",
 					err,
 				)
 
@@ -71,10 +72,7 @@ func (c *controller) reconcile(ctx context.Context, apiExport *apisv1alpha1.APIE
 			}
 		}
 
-		identity.SecretRef = &corev1.SecretReference{
-			Namespace: c.secretNamespace,
-			Name:      apiExport.Name,
-		}
+		identity.SecretRef = &ref
 
 		apiExport.Spec.Identity = identity
 
@@ -96,21 +94,7 @@ func (c *controller) reconcile(ctx context.Context, apiExport *apisv1alpha1.APIE
 	return nil
 }
 
-func (c *controller) ensureSecretNamespaceExists(ctx context.Context, clusterName logicalcluster.Name) {
-	if _, err := c.getNamespace(clusterName, c.secretNamespace); errors.IsNotFound(err) {
-		ns := &corev1.Namespace{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: c.secretNamespace,
-			},
-		}
-		if err := c.createNamespace(ctx, clusterName, ns); err != nil && !errors.IsAlreadyExists(err) {
-			klog.Errorf("Error creating namespace %q in cluster %q for APIExport secret identities: %v", c.secretNamespace, clusterName, err)
-			// Keep going - maybe things will work. If the secret creation fails, we'll make sure to set a condition.
-		}
-	}
-}
-
-func (c *controller) generateIdentitySecret(apiExportName string) (*corev1.Secret, error) {
+func generateIdentityKey() ([]byte, error) {
 	privateKey, err := rsa.GenerateKey(cryptorand.Reader, 4096)
 	if err != nil {
 		return nil, fmt.Errorf("error generating private key: %w", err)
@@ -121,41 +105,25 @@ func (c *controller) generateIdentitySecret(apiExportName string) (*corev1.Secre
 		return nil, fmt.Errorf("error encoding private key: %w", err)
 	}
 
-	secret := &corev1.Secret{
-		ObjectMeta: metav1.ObjectMeta{
-			Namespace: c.secretNamespace,
-			Name:      apiExportName,
-		},
-		Data: map[string][]byte{
-			apisv1alpha1.SecretKeyAPIExportIdentity: encoded,
-		},
-	}
-
-	return secret, nil
+	return encoded, nil
 }
 
-func (c *controller) createIdentitySecret(ctx context.Context, clusterName logicalcluster.Name, apiExportName string) error {
-	secret, err := c.generateIdentitySecret(apiExportName)
+func (c *controller) createIdentity(ctx context.Context, clusterName logicalcluster.Name, ref corev1.SecretReference) error {
+	key, err := generateIdentityKey()
 	if err != nil {
 		return err
 	}
 
-	if err := c.createSecret(ctx, clusterName, secret); err != nil {
-		return err
-	}
-
-	return nil
+	return c.createIdentityKey(ctx, clusterName, ref, key)
 }
 
 func (c *controller) updateOrVerifyIdentitySecretHash(ctx context.Context, clusterName logicalcluster.Name, apiExport *apisv1alpha1.APIExport) error {
-	secret, err := c.getSecret(ctx, clusterName, apiExport.Spec.Identity.SecretRef.Namespace, apiExport.Spec.Identity.SecretRef.Name)
+	key, err := c.getIdentityKey(ctx, clusterName, *apiExport.Spec.Identity.SecretRef)
 	if err != nil {
 		return err
 	}
-
-	key := secret.Data[apisv1alpha1.SecretKeyAPIExportIdentity]
 	if len(key) == 0 {
-		return fmt.Errorf("secret is missing data.%s", apisv1alpha1.SecretKeyAPIExportIdentity)
+		return fmt.Errorf("identity key %s/%s is empty", apiExport.Spec.Identity.SecretRef.Namespace, apiExport.Spec.Identity.SecretRef.Name)
 	}
 
 	hashBytes := sha256.Sum256(key)
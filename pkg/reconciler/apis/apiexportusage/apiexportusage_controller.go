@@ -0,0 +1,227 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiexportusage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/kcp-dev/logicalcluster"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsinformers "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clusters"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	kcpclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
+	apisinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/apis/v1alpha1"
+	apislisters "github.com/kcp-dev/kcp/pkg/client/listers/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apibinding"
+	"github.com/kcp-dev/kcp/pkg/reconciler/latency"
+)
+
+const controllerName = "kcp-apiexport-usage"
+
+// NewController returns a new controller that periodically counts the objects of the
+// resources of every APIExport across all workspaces binding it, and reports them in
+// the APIExport status and as metrics.
+//
+// The APIBinding informer must have the apibinding.IndexAPIBindingsByWorkspaceExport index.
+func NewController(
+	kcpClusterClient kcpclient.ClusterInterface,
+	dynamicClusterClient dynamic.ClusterInterface,
+	apiBindingInformer apisinformers.APIBindingInformer,
+	apiExportInformer apisinformers.APIExportInformer,
+	crdInformer apiextensionsinformers.CustomResourceDefinitionInformer,
+	interval time.Duration,
+) (*controller, error) {
+	queue := latency.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+
+	c := &controller{
+		queue:            queue,
+		interval:         interval,
+		now:              time.Now,
+		kcpClusterClient: kcpClusterClient,
+		apiExportsLister: apiExportInformer.Lister(),
+		getAPIBindings: func(apiExportKey string) ([]*apisv1alpha1.APIBinding, error) {
+			objs, err := apiBindingInformer.Informer().GetIndexer().ByIndex(apibinding.IndexAPIBindingsByWorkspaceExport, apiExportKey)
+			if err != nil {
+				return nil, err
+			}
+			bindings := make([]*apisv1alpha1.APIBinding, 0, len(objs))
+			for _, obj := range objs {
+				bindings = append(bindings, obj.(*apisv1alpha1.APIBinding))
+			}
+			return bindings, nil
+		},
+		getCRD: func(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error) {
+			return crdInformer.Lister().Get(clusters.ToClusterAwareKey(clusterName, name))
+		},
+		listObjects: func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, continueToken string) (*unstructured.UnstructuredList, error) {
+			return dynamicClusterClient.Cluster(clusterName).Resource(gvr).List(ctx, metav1.ListOptions{Limit: 500, Continue: continueToken})
+		},
+		metrics: newUsageMetrics(),
+	}
+
+	apiExportInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) { c.enqueueAPIExport(obj) },
+		DeleteFunc: func(obj interface{}) {
+			if key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj); err == nil {
+				c.metrics.forget(key)
+			}
+		},
+	})
+
+	return c, nil
+}
+
+// controller reconciles the status.usage of APIExports. Every APIExport is counted when it
+// is added, and then again after every interval. Changes of APIBindings are picked up on the
+// next count, as counting lists all bound objects.
+type controller struct {
+	queue    workqueue.RateLimitingInterface
+	interval time.Duration
+	now      func() time.Time
+
+	kcpClusterClient kcpclient.ClusterInterface
+
+	apiExportsLister apislisters.APIExportLister
+
+	getAPIBindings func(apiExportKey string) ([]*apisv1alpha1.APIBinding, error)
+	getCRD         func(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error)
+	listObjects    func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, continueToken string) (*unstructured.UnstructuredList, error)
+
+	metrics *usageMetrics
+}
+
+func (c *controller) enqueueAPIExport(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	klog.V(2).Infof("Queueing APIExport %q", key)
+	c.queue.Add(key)
+}
+
+// Start starts the controller, which stops when ctx.Done() is closed.
+func (c *controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	klog.Infof("Starting %s controller", controllerName)
+	defer klog.Infof("Shutting down %s controller", controllerName)
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+
+	<-ctx.Done()
+}
+
+func (c *controller) startWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	if err := c.process(ctx, key); err != nil {
+		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", controllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	c.queue.AddAfter(key, c.interval)
+	return true
+}
+
+func (c *controller) process(ctx context.Context, key string) error {
+	obj, err := c.apiExportsLister.Get(key)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			c.metrics.forget(key)
+			return nil // object deleted before we handled it
+		}
+		return err
+	}
+	old := obj
+	obj = obj.DeepCopy()
+
+	if err := c.reconcile(ctx, key, obj); err != nil {
+		return err
+	}
+	c.metrics.update(key, obj.Status.Usage)
+
+	// If the object being reconciled changed as a result, update it.
+	if !equality.Semantic.DeepEqual(old.Status.Usage, obj.Status.Usage) {
+		clusterName := logicalcluster.From(obj)
+
+		oldData, err := json.Marshal(apisv1alpha1.APIExport{
+			Status: apisv1alpha1.APIExportStatus{Usage: old.Status.Usage},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to Marshal old data for APIExport %s|%s: %w", clusterName, obj.Name, err)
+		}
+
+		newData, err := json.Marshal(apisv1alpha1.APIExport{
+			ObjectMeta: metav1.ObjectMeta{
+				UID:             obj.UID,
+				ResourceVersion: obj.ResourceVersion,
+			}, // to ensure they appear in the patch as preconditions
+			Status: apisv1alpha1.APIExportStatus{Usage: obj.Status.Usage},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to Marshal new data for APIExport %s|%s: %w", clusterName, obj.Name, err)
+		}
+
+		patchBytes, err := jsonpatch.CreateMergePatch(oldData, newData)
+		if err != nil {
+			return fmt.Errorf("failed to create patch for APIExport %s|%s: %w", clusterName, obj.Name, err)
+		}
+		_, uerr := c.kcpClusterClient.Cluster(clusterName).ApisV1alpha1().APIExports().Patch(ctx, obj.Name, types.MergePatchType, patchBytes, metav1.PatchOptions{}, "status")
+		return uerr
+	}
+
+	return nil
+}
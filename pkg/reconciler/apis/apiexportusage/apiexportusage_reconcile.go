@@ -0,0 +1,119 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiexportusage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apibinding"
+)
+
+func (c *controller) reconcile(ctx context.Context, key string, apiExport *apisv1alpha1.APIExport) error {
+	apiBindings, err := c.getAPIBindings(key)
+	if err != nil {
+		return err
+	}
+
+	usage := &apisv1alpha1.APIExportUsage{
+		LastUpdateTime: metav1.NewTime(c.now()),
+	}
+	resources := map[schema.GroupResource]*apisv1alpha1.APIExportResourceUsage{}
+	for _, apiBinding := range apiBindings {
+		if apiBinding.Status.Phase != apisv1alpha1.APIBindingPhaseBound {
+			continue
+		}
+		usage.Bindings++
+
+		for _, bound := range apiBinding.Status.BoundResources {
+			gr := schema.GroupResource{Group: bound.Group, Resource: bound.Resource}
+			r, ok := resources[gr]
+			if !ok {
+				r = &apisv1alpha1.APIExportResourceUsage{Group: bound.Group, Resource: bound.Resource}
+				resources[gr] = r
+			}
+			if err := c.countObjects(ctx, apiBinding, bound, r); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, r := range resources {
+		usage.Resources = append(usage.Resources, *r)
+	}
+	sort.Slice(usage.Resources, func(i, j int) bool {
+		if usage.Resources[i].Group != usage.Resources[j].Group {
+			return usage.Resources[i].Group < usage.Resources[j].Group
+		}
+		return usage.Resources[i].Resource < usage.Resources[j].Resource
+	})
+	apiExport.Status.Usage = usage
+
+	return nil
+}
+
+// countObjects adds the number and size of the objects of the bound resource in the
+// workspace of the APIBinding to the usage.
+func (c *controller) countObjects(ctx context.Context, apiBinding *apisv1alpha1.APIBinding, bound apisv1alpha1.BoundAPIResource, usage *apisv1alpha1.APIExportResourceUsage) error {
+	// bound CRDs are named after the UID of their schema. They have no conversion, so objects
+	// are returned as they are stored in any version.
+	crd, err := c.getCRD(apibinding.ShadowWorkspaceName, bound.Schema.UID)
+	if errors.IsNotFound(err) {
+		return nil // not served, so there is nothing stored
+	} else if err != nil {
+		return err
+	}
+	var version string
+	for _, v := range crd.Spec.Versions {
+		if v.Storage {
+			version = v.Name
+		}
+	}
+
+	clusterName := logicalcluster.From(apiBinding)
+	gvr := schema.GroupVersionResource{Group: bound.Group, Version: version, Resource: bound.Resource}
+	continueToken := ""
+	for {
+		list, err := c.listObjects(ctx, clusterName, gvr, continueToken)
+		if err != nil {
+			return fmt.Errorf("failed to list %s in %s: %w", gvr, clusterName, err)
+		}
+
+		for i := range list.Items {
+			bs, err := json.Marshal(list.Items[i].Object)
+			if err != nil {
+				return err
+			}
+			usage.Objects++
+			usage.StorageBytes += int64(len(bs))
+		}
+
+		continueToken = list.GetContinue()
+		if continueToken == "" {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiexportusage
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apibinding"
+)
+
+func TestReconcile(t *testing.T) {
+	now := time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	binding := func(cluster string, phase apisv1alpha1.APIBindingPhaseType, resources ...string) *apisv1alpha1.APIBinding {
+		b := &apisv1alpha1.APIBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "widgets", ClusterName: cluster},
+			Status:     apisv1alpha1.APIBindingStatus{Phase: phase},
+		}
+		for _, resource := range resources {
+			b.Status.BoundResources = append(b.Status.BoundResources, apisv1alpha1.BoundAPIResource{
+				Group:    "example.io",
+				Resource: resource,
+				Schema:   apisv1alpha1.BoundAPIResourceSchema{UID: "uid-" + resource},
+			})
+		}
+		return b
+	}
+	widget := func(name string) unstructured.Unstructured {
+		return unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "example.io/v1",
+			"kind":       "Widget",
+			"metadata":   map[string]interface{}{"name": name, "namespace": "default"},
+		}}
+	}
+	size := func(objs ...unstructured.Unstructured) int64 {
+		var n int64
+		for _, obj := range objs {
+			bs, err := json.Marshal(obj.Object)
+			require.NoError(t, err)
+			n += int64(len(bs))
+		}
+		return n
+	}
+
+	objects := map[string][]unstructured.Unstructured{
+		"org:a|widgets": {widget("a"), widget("b"), widget("c")},
+		"org:b|widgets": {widget("d")},
+		"org:b|gadgets": {widget("e")},
+	}
+
+	c := &controller{
+		now: func() time.Time { return now },
+		getAPIBindings: func(apiExportKey string) ([]*apisv1alpha1.APIBinding, error) {
+			return []*apisv1alpha1.APIBinding{
+				binding("org:a", apisv1alpha1.APIBindingPhaseBound, "widgets"),
+				binding("org:b", apisv1alpha1.APIBindingPhaseBound, "widgets", "gadgets", "unserved"),
+				binding("org:c", apisv1alpha1.APIBindingPhaseBinding, "widgets"),
+			}, nil
+		},
+		getCRD: func(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error) {
+			require.Equal(t, apibinding.ShadowWorkspaceName, clusterName)
+			if name == "uid-unserved" {
+				return nil, errors.NewNotFound(apiextensionsv1.Resource("customresourcedefinitions"), name)
+			}
+			return &apiextensionsv1.CustomResourceDefinition{
+				Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+					Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1beta1"}, {Name: "v1", Storage: true}},
+				},
+			}, nil
+		},
+		listObjects: func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, continueToken string) (*unstructured.UnstructuredList, error) {
+			require.Equal(t, "v1", gvr.Version)
+			items := objects[clusterName.String()+"|"+gvr.Resource]
+			list := &unstructured.UnstructuredList{}
+			// page through the objects one by one
+			if continueToken == "" {
+				continueToken = "0"
+			}
+			i := int(continueToken[0] - '0')
+			if i < len(items) {
+				list.Items = items[i : i+1]
+				if i+1 < len(items) {
+					list.SetContinue(string(rune('0' + i + 1)))
+				}
+			}
+			return list, nil
+		},
+	}
+
+	apiExport := &apisv1alpha1.APIExport{ObjectMeta: metav1.ObjectMeta{Name: "widgets", ClusterName: "org:provider"}}
+	require.NoError(t, c.reconcile(context.Background(), "org:provider|widgets", apiExport))
+	require.Equal(t, &apisv1alpha1.APIExportUsage{
+		Bindings: 2,
+		Resources: []apisv1alpha1.APIExportResourceUsage{
+			{Group: "example.io", Resource: "gadgets", Objects: 1, StorageBytes: size(widget("e"))},
+			{Group: "example.io", Resource: "unserved"},
+			{Group: "example.io", Resource: "widgets", Objects: 4, StorageBytes: size(widget("a"), widget("b"), widget("c"), widget("d"))},
+		},
+		LastUpdateTime: metav1.NewTime(now),
+	}, apiExport.Status.Usage)
+}
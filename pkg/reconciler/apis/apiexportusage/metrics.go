@@ -0,0 +1,131 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiexportusage
+
+import (
+	"fmt"
+	"sync"
+
+	"k8s.io/client-go/tools/clusters"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+)
+
+var (
+	apiExportBindings = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Namespace:      "kcp",
+			Name:           "apiexport_bindings",
+			Help:           "Number of APIBindings bound to an APIExport.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"apiexport"},
+	)
+
+	apiExportObjects = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Namespace:      "kcp",
+			Name:           "apiexport_objects",
+			Help:           "Number of objects of an exported resource across all workspaces binding the APIExport.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"apiexport", "group", "resource"},
+	)
+
+	apiExportStorageBytes = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Namespace:      "kcp",
+			Name:           "apiexport_storage_bytes",
+			Help:           "Size in bytes of the objects of an exported resource across all workspaces binding the APIExport, serialized as JSON.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"apiexport", "group", "resource"},
+	)
+
+	registerMetrics sync.Once
+)
+
+// usageMetrics reports the usage of APIExports by their key, and removes the series of resources and APIExports that are gone.
+type usageMetrics struct {
+	lock     sync.Mutex
+	reported map[string][]apisv1alpha1.APIExportResourceUsage
+}
+
+func newUsageMetrics() *usageMetrics {
+	registerMetrics.Do(func() {
+		legacyregistry.MustRegister(apiExportBindings)
+		legacyregistry.MustRegister(apiExportObjects)
+		legacyregistry.MustRegister(apiExportStorageBytes)
+	})
+	return &usageMetrics{
+		reported: map[string][]apisv1alpha1.APIExportResourceUsage{},
+	}
+}
+
+func (m *usageMetrics) update(key string, usage *apisv1alpha1.APIExportUsage) {
+	if usage == nil {
+		m.forget(key)
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.deleteResources(key, func(r apisv1alpha1.APIExportResourceUsage) bool {
+		for _, current := range usage.Resources {
+			if current.Group == r.Group && current.Resource == r.Resource {
+				return false
+			}
+		}
+		return true
+	})
+
+	label := exportLabel(key)
+	apiExportBindings.WithLabelValues(label).Set(float64(usage.Bindings))
+	for _, r := range usage.Resources {
+		apiExportObjects.WithLabelValues(label, r.Group, r.Resource).Set(float64(r.Objects))
+		apiExportStorageBytes.WithLabelValues(label, r.Group, r.Resource).Set(float64(r.StorageBytes))
+	}
+	m.reported[key] = usage.Resources
+}
+
+func (m *usageMetrics) forget(key string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.deleteResources(key, func(apisv1alpha1.APIExportResourceUsage) bool { return true })
+	apiExportBindings.Delete(map[string]string{"apiexport": exportLabel(key)})
+	delete(m.reported, key)
+}
+
+func (m *usageMetrics) deleteResources(key string, gone func(apisv1alpha1.APIExportResourceUsage) bool) {
+	for _, r := range m.reported[key] {
+		if gone(r) {
+			labels := map[string]string{"apiexport": exportLabel(key), "group": r.Group, "resource": r.Resource}
+			apiExportObjects.Delete(labels)
+			apiExportStorageBytes.Delete(labels)
+		}
+	}
+}
+
+// exportLabel returns the <cluster>|<name> label value of the APIExport with the key.
+func exportLabel(key string) string {
+	clusterName, name := clusters.SplitClusterAwareKey(key)
+	return fmt.Sprintf("%s|%s", clusterName, name)
+}
@@ -0,0 +1,242 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package catalogentry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clusters"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	kcpclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
+	apisinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/apis/v1alpha1"
+	apislisters "github.com/kcp-dev/kcp/pkg/client/listers/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/reconciler/latency"
+)
+
+const (
+	controllerName = "kcp-catalogentry"
+
+	indexCatalogEntriesByExport = "byExport"
+)
+
+// NewController returns a new controller that reflects the resources of the APIExport
+// of a CatalogEntry in its status.
+func NewController(
+	kcpClusterClient kcpclient.ClusterInterface,
+	catalogEntryInformer apisinformers.CatalogEntryInformer,
+	apiExportInformer apisinformers.APIExportInformer,
+	apiResourceSchemaInformer apisinformers.APIResourceSchemaInformer,
+) (*controller, error) {
+	queue := latency.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+
+	c := &controller{
+		queue:               queue,
+		kcpClusterClient:    kcpClusterClient,
+		catalogEntryLister:  catalogEntryInformer.Lister(),
+		catalogEntryIndexer: catalogEntryInformer.Informer().GetIndexer(),
+		getAPIExport: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error) {
+			return apiExportInformer.Lister().Get(clusters.ToClusterAwareKey(clusterName, name))
+		},
+		getAPIResourceSchema: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error) {
+			return apiResourceSchemaInformer.Lister().Get(clusters.ToClusterAwareKey(clusterName, name))
+		},
+	}
+
+	if err := catalogEntryInformer.Informer().AddIndexers(cache.Indexers{
+		indexCatalogEntriesByExport: indexCatalogEntriesByExportFunc,
+	}); err != nil {
+		return nil, err
+	}
+
+	catalogEntryInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueueCatalogEntry(obj) },
+		UpdateFunc: func(_, obj interface{}) { c.enqueueCatalogEntry(obj) },
+		DeleteFunc: func(obj interface{}) { c.enqueueCatalogEntry(obj) },
+	})
+
+	apiExportInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueueAPIExport(obj) },
+		UpdateFunc: func(_, obj interface{}) { c.enqueueAPIExport(obj) },
+		DeleteFunc: func(obj interface{}) { c.enqueueAPIExport(obj) },
+	})
+
+	return c, nil
+}
+
+func indexCatalogEntriesByExportFunc(obj interface{}) ([]string, error) {
+	entry, ok := obj.(*apisv1alpha1.CatalogEntry)
+	if !ok {
+		return []string{}, fmt.Errorf("obj is supposed to be a CatalogEntry, but is %T", obj)
+	}
+	return []string{clusters.ToClusterAwareKey(logicalcluster.From(entry), entry.Spec.ExportName)}, nil
+}
+
+// controller reconciles the status of CatalogEntries.
+type controller struct {
+	queue workqueue.RateLimitingInterface
+
+	kcpClusterClient    kcpclient.ClusterInterface
+	catalogEntryLister  apislisters.CatalogEntryLister
+	catalogEntryIndexer cache.Indexer
+
+	getAPIExport         func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error)
+	getAPIResourceSchema func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error)
+}
+
+func (c *controller) enqueueCatalogEntry(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	klog.V(2).Infof("Queueing CatalogEntry %q", key)
+	c.queue.Add(key)
+}
+
+// enqueueAPIExport enqueues all CatalogEntries publishing the APIExport.
+func (c *controller) enqueueAPIExport(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	export, ok := obj.(*apisv1alpha1.APIExport)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("obj is supposed to be a APIExport, but is %T", obj))
+		return
+	}
+
+	exportKey := clusters.ToClusterAwareKey(logicalcluster.From(export), export.Name)
+	entries, err := c.catalogEntryIndexer.ByIndex(indexCatalogEntriesByExport, exportKey)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	for _, obj := range entries {
+		key, err := cache.MetaNamespaceKeyFunc(obj)
+		if err != nil {
+			runtime.HandleError(err)
+			continue
+		}
+		klog.V(2).Infof("Queueing CatalogEntry %q because of APIExport %s", key, exportKey)
+		c.queue.Add(key)
+	}
+}
+
+// Start starts the controller, which stops when ctx.Done() is closed.
+func (c *controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	klog.Infof("Starting %s controller", controllerName)
+	defer klog.Infof("Shutting down %s controller", controllerName)
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+
+	<-ctx.Done()
+}
+
+func (c *controller) startWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	if err := c.process(ctx, key); err != nil {
+		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", controllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *controller) process(ctx context.Context, key string) error {
+	obj, err := c.catalogEntryLister.Get(key)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil // object deleted before we handled it
+		}
+		return err
+	}
+	old := obj
+	obj = obj.DeepCopy()
+
+	if err := c.reconcile(ctx, obj); err != nil {
+		return err
+	}
+
+	// If the object being reconciled changed as a result, update it.
+	if !equality.Semantic.DeepEqual(old.Status, obj.Status) {
+		clusterName := logicalcluster.From(obj)
+
+		oldData, err := json.Marshal(apisv1alpha1.CatalogEntry{
+			Status: old.Status,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to Marshal old data for CatalogEntry %s|%s: %w", clusterName, obj.Name, err)
+		}
+
+		newData, err := json.Marshal(apisv1alpha1.CatalogEntry{
+			ObjectMeta: metav1.ObjectMeta{
+				UID:             obj.UID,
+				ResourceVersion: obj.ResourceVersion,
+			}, // to ensure they appear in the patch as preconditions
+			Status: obj.Status,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to Marshal new data for CatalogEntry %s|%s: %w", clusterName, obj.Name, err)
+		}
+
+		patchBytes, err := jsonpatch.CreateMergePatch(oldData, newData)
+		if err != nil {
+			return fmt.Errorf("failed to create patch for CatalogEntry %s|%s: %w", clusterName, obj.Name, err)
+		}
+		_, uerr := c.kcpClusterClient.Cluster(clusterName).ApisV1alpha1().CatalogEntries().Patch(ctx, obj.Name, types.MergePatchType, patchBytes, metav1.PatchOptions{}, "status")
+		return uerr
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package catalogentry
+
+import (
+	"context"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/third_party/conditions/util/conditions"
+)
+
+func (c *controller) reconcile(ctx context.Context, entry *apisv1alpha1.CatalogEntry) error {
+	clusterName := logicalcluster.From(entry)
+
+	export, err := c.getAPIExport(clusterName, entry.Spec.ExportName)
+	if errors.IsNotFound(err) {
+		entry.Status.Resources = nil
+		conditions.MarkFalse(
+			entry,
+			apisv1alpha1.APIExportValid,
+			apisv1alpha1.APIExportNotFoundReason,
+			conditionsv1alpha1.ConditionSeverityError,
+			"APIExport %s|%s not found",
+			clusterName,
+			entry.Spec.ExportName,
+		)
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	resources := make([]metav1.GroupResource, 0, len(export.Spec.LatestResourceSchemas))
+	for _, schemaName := range export.Spec.LatestResourceSchemas {
+		schema, err := c.getAPIResourceSchema(clusterName, schemaName)
+		if errors.IsNotFound(err) {
+			conditions.MarkFalse(
+				entry,
+				apisv1alpha1.APIExportValid,
+				apisv1alpha1.InternalErrorReason,
+				conditionsv1alpha1.ConditionSeverityError,
+				"APIResourceSchema %s|%s of APIExport %s not found",
+				clusterName,
+				schemaName,
+				export.Name,
+			)
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		resources = append(resources, metav1.GroupResource{
+			Group:    schema.Spec.Group,
+			Resource: schema.Spec.Names.Plural,
+		})
+	}
+
+	entry.Status.Resources = resources
+	conditions.MarkTrue(entry, apisv1alpha1.APIExportValid)
+
+	return nil
+}
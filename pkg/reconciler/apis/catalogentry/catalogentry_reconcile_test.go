@@ -0,0 +1,104 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package catalogentry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/third_party/conditions/util/conditions"
+)
+
+func TestReconcile(t *testing.T) {
+	entry := &apisv1alpha1.CatalogEntry{
+		ObjectMeta: metav1.ObjectMeta{Name: "widgets", ClusterName: "root:org:provider"},
+		Spec:       apisv1alpha1.CatalogEntrySpec{ExportName: "widgets"},
+	}
+	export := &apisv1alpha1.APIExport{
+		ObjectMeta: metav1.ObjectMeta{Name: "widgets", ClusterName: "root:org:provider"},
+		Spec: apisv1alpha1.APIExportSpec{
+			LatestResourceSchemas: []string{"today.widgets.example.io", "today.gadgets.example.io"},
+		},
+	}
+	schemas := map[string]*apisv1alpha1.APIResourceSchema{
+		"today.widgets.example.io": {Spec: apisv1alpha1.APIResourceSchemaSpec{Group: "example.io", Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets"}}},
+		"today.gadgets.example.io": {Spec: apisv1alpha1.APIResourceSchemaSpec{Group: "example.io", Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "gadgets"}}},
+	}
+
+	tests := map[string]struct {
+		export        *apisv1alpha1.APIExport
+		schemas       map[string]*apisv1alpha1.APIResourceSchema
+		wantValid     bool
+		wantReason    string
+		wantResources []metav1.GroupResource
+	}{
+		"export found": {
+			export:    export,
+			schemas:   schemas,
+			wantValid: true,
+			wantResources: []metav1.GroupResource{
+				{Group: "example.io", Resource: "widgets"},
+				{Group: "example.io", Resource: "gadgets"},
+			},
+		},
+		"export not found": {
+			wantReason: apisv1alpha1.APIExportNotFoundReason,
+		},
+		"schema not found": {
+			export:     export,
+			schemas:    map[string]*apisv1alpha1.APIResourceSchema{},
+			wantReason: apisv1alpha1.InternalErrorReason,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &controller{
+				getAPIExport: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error) {
+					if tt.export == nil {
+						return nil, errors.NewNotFound(apisv1alpha1.Resource("apiexports"), name)
+					}
+					require.Equal(t, "root:org:provider", clusterName.String())
+					return tt.export, nil
+				},
+				getAPIResourceSchema: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error) {
+					if schema, ok := tt.schemas[name]; ok {
+						return schema, nil
+					}
+					return nil, errors.NewNotFound(apisv1alpha1.Resource("apiresourceschemas"), name)
+				},
+			}
+
+			obj := entry.DeepCopy()
+			err := c.reconcile(context.Background(), obj)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.wantValid, conditions.IsTrue(obj, apisv1alpha1.APIExportValid))
+			if !tt.wantValid {
+				require.Equal(t, tt.wantReason, conditions.GetReason(obj, apisv1alpha1.APIExportValid))
+			}
+			require.Equal(t, tt.wantResources, obj.Status.Resources)
+		})
+	}
+}
@@ -0,0 +1,275 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deprecatedusage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/kcp-dev/logicalcluster"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsinformers "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clusters"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	kcpclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
+	apisinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/apis/v1alpha1"
+	apislisters "github.com/kcp-dev/kcp/pkg/client/listers/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apibinding"
+	"github.com/kcp-dev/kcp/pkg/reconciler/latency"
+)
+
+const (
+	controllerName = "kcp-deprecated-api-usage"
+
+	byWorkspaceGroupResourceIndex = "deprecatedAPIUsage-byWorkspaceGroupResource"
+
+	// flushInterval is how long requests are recorded before they are added to the
+	// status of an APIBinding.
+	flushInterval = time.Minute
+
+	// pruneInterval is how often the versions not requested within the retention are
+	// removed from the status of an APIBinding.
+	pruneInterval = time.Hour
+)
+
+// NewController returns a new controller that reports the requests to deprecated versions
+// of bound resources, as recorded by the tracker, in the status of the APIBindings binding
+// them. The queue is keyed by APIBinding.
+func NewController(
+	kcpClusterClient kcpclient.ClusterInterface,
+	apiBindingInformer apisinformers.APIBindingInformer,
+	crdInformer apiextensionsinformers.CustomResourceDefinitionInformer,
+	tracker *UsageTracker,
+) (*controller, error) {
+	queue := latency.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+
+	c := &controller{
+		queue:              queue,
+		now:                time.Now,
+		kcpClusterClient:   kcpClusterClient,
+		apiBindingsLister:  apiBindingInformer.Lister(),
+		tracker:            tracker,
+		apiBindingsIndexer: apiBindingInformer.Informer().GetIndexer(),
+		getCRD: func(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error) {
+			return crdInformer.Lister().Get(clusters.ToClusterAwareKey(clusterName, name))
+		},
+	}
+
+	if err := apiBindingInformer.Informer().AddIndexers(cache.Indexers{
+		byWorkspaceGroupResourceIndex: indexByWorkspaceGroupResource,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to add indexer for APIBinding: %w", err)
+	}
+
+	// bindings reporting usage are revisited to prune it, also after restarts.
+	apiBindingInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if binding, ok := obj.(*apisv1alpha1.APIBinding); ok && len(binding.Status.DeprecatedVersionUsage) > 0 {
+				c.enqueueAPIBinding(obj)
+			}
+		},
+	})
+
+	tracker.setLookupFunc(c.deprecatedBinding)
+	tracker.setNotifyFunc(func(bindingKey string) {
+		klog.V(4).Infof("Queueing APIBinding %q because of requests to deprecated versions", bindingKey)
+		queue.AddAfter(bindingKey, flushInterval)
+	})
+
+	return c, nil
+}
+
+// controller reconciles the status.deprecatedVersionUsage of APIBindings.
+type controller struct {
+	queue workqueue.RateLimitingInterface
+	now   func() time.Time
+
+	kcpClusterClient kcpclient.ClusterInterface
+
+	apiBindingsLister  apislisters.APIBindingLister
+	apiBindingsIndexer cache.Indexer
+	getCRD             func(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error)
+
+	tracker *UsageTracker
+}
+
+func indexByWorkspaceGroupResource(obj interface{}) ([]string, error) {
+	binding, ok := obj.(*apisv1alpha1.APIBinding)
+	if !ok {
+		return nil, fmt.Errorf("obj is supposed to be an APIBinding, but is %T", obj)
+	}
+
+	keys := make([]string, 0, len(binding.Status.BoundResources))
+	for _, r := range binding.Status.BoundResources {
+		keys = append(keys, workspaceGroupResourceKey(logicalcluster.From(binding), schema.GroupResource{Group: r.Group, Resource: r.Resource}))
+	}
+	return keys, nil
+}
+
+func workspaceGroupResourceKey(clusterName logicalcluster.Name, gr schema.GroupResource) string {
+	return clusters.ToClusterAwareKey(clusterName, gr.String())
+}
+
+// deprecatedBinding returns the key of the APIBinding binding the resource in the logical
+// cluster, and whether the version is deprecated.
+func (c *controller) deprecatedBinding(clusterName logicalcluster.Name, gvr schema.GroupVersionResource) (string, bool) {
+	objs, err := c.apiBindingsIndexer.ByIndex(byWorkspaceGroupResourceIndex, workspaceGroupResourceKey(clusterName, gvr.GroupResource()))
+	if err != nil || len(objs) == 0 {
+		return "", false
+	}
+
+	for _, obj := range objs {
+		binding := obj.(*apisv1alpha1.APIBinding)
+		for _, r := range binding.Status.BoundResources {
+			if r.Group != gvr.Group || r.Resource != gvr.Resource {
+				continue
+			}
+			crd, err := c.getCRD(apibinding.ShadowWorkspaceName, r.Schema.UID)
+			if err != nil {
+				continue
+			}
+			for _, v := range crd.Spec.Versions {
+				if v.Name == gvr.Version && v.Deprecated {
+					key, err := cache.MetaNamespaceKeyFunc(binding)
+					if err != nil {
+						return "", false
+					}
+					return key, true
+				}
+			}
+		}
+	}
+	return "", false
+}
+
+func (c *controller) enqueueAPIBinding(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	klog.V(2).Infof("Queueing APIBinding %q", key)
+	c.queue.Add(key)
+}
+
+// Start starts the controller, which stops when ctx.Done() is closed.
+func (c *controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	klog.Infof("Starting %s controller", controllerName)
+	defer klog.Infof("Shutting down %s controller", controllerName)
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+
+	<-ctx.Done()
+}
+
+func (c *controller) startWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	if err := c.process(ctx, key); err != nil {
+		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", controllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *controller) process(ctx context.Context, key string) error {
+	recorded := c.tracker.take(key)
+
+	obj, err := c.apiBindingsLister.Get(key)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil // object deleted before we handled it
+		}
+		c.tracker.restore(key, recorded)
+		return err
+	}
+
+	old := obj.Status.DeprecatedVersionUsage
+	usage := mergeUsage(old, recorded, c.now())
+	if len(usage) > 0 {
+		c.queue.AddAfter(key, pruneInterval)
+	}
+	if equality.Semantic.DeepEqual(old, usage) {
+		return nil
+	}
+
+	clusterName := logicalcluster.From(obj)
+	oldData, err := json.Marshal(apisv1alpha1.APIBinding{
+		Status: apisv1alpha1.APIBindingStatus{DeprecatedVersionUsage: old},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to Marshal old data for APIBinding %s|%s: %w", clusterName, obj.Name, err)
+	}
+
+	newData, err := json.Marshal(apisv1alpha1.APIBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			UID:             obj.UID,
+			ResourceVersion: obj.ResourceVersion,
+		}, // to ensure they appear in the patch as preconditions
+		Status: apisv1alpha1.APIBindingStatus{DeprecatedVersionUsage: usage},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to Marshal new data for APIBinding %s|%s: %w", clusterName, obj.Name, err)
+	}
+
+	patchBytes, err := jsonpatch.CreateMergePatch(oldData, newData)
+	if err != nil {
+		return fmt.Errorf("failed to create patch for APIBinding %s|%s: %w", clusterName, obj.Name, err)
+	}
+	if _, err := c.kcpClusterClient.Cluster(clusterName).ApisV1alpha1().APIBindings().Patch(ctx, obj.Name, types.MergePatchType, patchBytes, metav1.PatchOptions{}, "status"); err != nil {
+		c.tracker.restore(key, recorded)
+		return err
+	}
+	return nil
+}
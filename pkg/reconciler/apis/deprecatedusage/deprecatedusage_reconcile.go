@@ -0,0 +1,122 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deprecatedusage
+
+import (
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+)
+
+const (
+	// retention is how long a version is reported after its last request.
+	retention = 30 * 24 * time.Hour
+
+	// maxClients is the number of clients reported per version.
+	maxClients = 10
+)
+
+// mergeUsage adds the recorded requests to the reported usage, and removes the versions
+// not requested within the retention. The result is sorted, with the clients of every
+// version sorted by their requests, most first.
+func mergeUsage(reported []apisv1alpha1.DeprecatedVersionUsage, recorded map[usageKey]*usage, now time.Time) []apisv1alpha1.DeprecatedVersionUsage {
+	type versionKey struct{ group, resource, version string }
+	type clientKey struct{ user, userAgent string }
+
+	versions := map[versionKey]*apisv1alpha1.DeprecatedVersionUsage{}
+	clients := map[versionKey]map[clientKey]*apisv1alpha1.DeprecatedVersionClient{}
+	for i := range reported {
+		v := reported[i].DeepCopy()
+		vk := versionKey{v.Group, v.Resource, v.Version}
+		versions[vk] = v
+		clients[vk] = map[clientKey]*apisv1alpha1.DeprecatedVersionClient{}
+		for j := range v.Clients {
+			c := v.Clients[j]
+			clients[vk][clientKey{c.User, c.UserAgent}] = &c
+		}
+	}
+
+	for k, u := range recorded {
+		vk := versionKey{k.group, k.resource, k.version}
+		v, ok := versions[vk]
+		if !ok {
+			v = &apisv1alpha1.DeprecatedVersionUsage{Group: k.group, Resource: k.resource, Version: k.version}
+			versions[vk] = v
+			clients[vk] = map[clientKey]*apisv1alpha1.DeprecatedVersionClient{}
+		}
+		v.Requests += u.requests
+		if u.last.After(v.LastRequestTime.Time) {
+			v.LastRequestTime = metav1.NewTime(u.last)
+		}
+
+		if k.user == "" && k.userAgent == "" {
+			continue // beyond the clients tracked per binding
+		}
+		ck := clientKey{k.user, k.userAgent}
+		c, ok := clients[vk][ck]
+		if !ok {
+			c = &apisv1alpha1.DeprecatedVersionClient{User: k.user, UserAgent: k.userAgent}
+			clients[vk][ck] = c
+		}
+		c.Requests += u.requests
+		if u.last.After(c.LastRequestTime.Time) {
+			c.LastRequestTime = metav1.NewTime(u.last)
+		}
+	}
+
+	var ret []apisv1alpha1.DeprecatedVersionUsage
+	for vk, v := range versions {
+		if now.Sub(v.LastRequestTime.Time) > retention {
+			continue
+		}
+
+		v.Clients = nil
+		for _, c := range clients[vk] {
+			v.Clients = append(v.Clients, *c)
+		}
+		sort.Slice(v.Clients, func(i, j int) bool {
+			a, b := v.Clients[i], v.Clients[j]
+			if a.Requests != b.Requests {
+				return a.Requests > b.Requests
+			}
+			if a.User != b.User {
+				return a.User < b.User
+			}
+			return a.UserAgent < b.UserAgent
+		})
+		if len(v.Clients) > maxClients {
+			v.Clients = v.Clients[:maxClients]
+		}
+
+		ret = append(ret, *v)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		a, b := ret[i], ret[j]
+		if a.Group != b.Group {
+			return a.Group < b.Group
+		}
+		if a.Resource != b.Resource {
+			return a.Resource < b.Resource
+		}
+		return a.Version < b.Version
+	})
+
+	return ret
+}
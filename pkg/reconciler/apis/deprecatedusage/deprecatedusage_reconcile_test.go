@@ -0,0 +1,131 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deprecatedusage
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+)
+
+func TestMergeUsage(t *testing.T) {
+	now := time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)
+	earlier := now.Add(-time.Hour)
+	expired := now.Add(-retention - time.Hour)
+
+	key := func(version, user, userAgent string) usageKey {
+		return usageKey{group: "example.io", resource: "widgets", version: version, user: user, userAgent: userAgent}
+	}
+
+	tests := map[string]struct {
+		reported []apisv1alpha1.DeprecatedVersionUsage
+		recorded map[usageKey]*usage
+		want     []apisv1alpha1.DeprecatedVersionUsage
+	}{
+		"nothing": {},
+		"first requests": {
+			recorded: map[usageKey]*usage{
+				key("v1beta1", "alice", "kubectl"):  {requests: 2, last: now},
+				key("v1beta1", "bob", "controller"): {requests: 5, last: earlier},
+				key("v1alpha1", "alice", "kubectl"): {requests: 1, last: earlier},
+				key("v1alpha1", "", ""):             {requests: 4, last: now},
+			},
+			want: []apisv1alpha1.DeprecatedVersionUsage{
+				{
+					Group: "example.io", Resource: "widgets", Version: "v1alpha1", Requests: 5, LastRequestTime: metav1.NewTime(now),
+					Clients: []apisv1alpha1.DeprecatedVersionClient{
+						{User: "alice", UserAgent: "kubectl", Requests: 1, LastRequestTime: metav1.NewTime(earlier)},
+					},
+				},
+				{
+					Group: "example.io", Resource: "widgets", Version: "v1beta1", Requests: 7, LastRequestTime: metav1.NewTime(now),
+					Clients: []apisv1alpha1.DeprecatedVersionClient{
+						{User: "bob", UserAgent: "controller", Requests: 5, LastRequestTime: metav1.NewTime(earlier)},
+						{User: "alice", UserAgent: "kubectl", Requests: 2, LastRequestTime: metav1.NewTime(now)},
+					},
+				},
+			},
+		},
+		"requests are added": {
+			reported: []apisv1alpha1.DeprecatedVersionUsage{
+				{
+					Group: "example.io", Resource: "widgets", Version: "v1beta1", Requests: 10, LastRequestTime: metav1.NewTime(earlier),
+					Clients: []apisv1alpha1.DeprecatedVersionClient{
+						{User: "alice", UserAgent: "kubectl", Requests: 10, LastRequestTime: metav1.NewTime(earlier)},
+					},
+				},
+			},
+			recorded: map[usageKey]*usage{
+				key("v1beta1", "alice", "kubectl"): {requests: 1, last: now},
+			},
+			want: []apisv1alpha1.DeprecatedVersionUsage{
+				{
+					Group: "example.io", Resource: "widgets", Version: "v1beta1", Requests: 11, LastRequestTime: metav1.NewTime(now),
+					Clients: []apisv1alpha1.DeprecatedVersionClient{
+						{User: "alice", UserAgent: "kubectl", Requests: 11, LastRequestTime: metav1.NewTime(now)},
+					},
+				},
+			},
+		},
+		"expired versions are removed": {
+			reported: []apisv1alpha1.DeprecatedVersionUsage{
+				{Group: "example.io", Resource: "widgets", Version: "v1alpha1", Requests: 3, LastRequestTime: metav1.NewTime(expired)},
+				{Group: "example.io", Resource: "widgets", Version: "v1beta1", Requests: 3, LastRequestTime: metav1.NewTime(earlier)},
+			},
+			want: []apisv1alpha1.DeprecatedVersionUsage{
+				{Group: "example.io", Resource: "widgets", Version: "v1beta1", Requests: 3, LastRequestTime: metav1.NewTime(earlier)},
+			},
+		},
+		"expired versions requested again are kept": {
+			reported: []apisv1alpha1.DeprecatedVersionUsage{
+				{Group: "example.io", Resource: "widgets", Version: "v1alpha1", Requests: 3, LastRequestTime: metav1.NewTime(expired)},
+			},
+			recorded: map[usageKey]*usage{
+				key("v1alpha1", "", ""): {requests: 1, last: now},
+			},
+			want: []apisv1alpha1.DeprecatedVersionUsage{
+				{Group: "example.io", Resource: "widgets", Version: "v1alpha1", Requests: 4, LastRequestTime: metav1.NewTime(now)},
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tt.want, mergeUsage(tt.reported, tt.recorded, now))
+		})
+	}
+}
+
+func TestMergeUsageLimitsClients(t *testing.T) {
+	now := time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	recorded := map[usageKey]*usage{}
+	for i := 0; i < maxClients+5; i++ {
+		recorded[usageKey{group: "example.io", resource: "widgets", version: "v1alpha1", user: fmt.Sprintf("user-%02d", i)}] = &usage{requests: int64(i + 1), last: now}
+	}
+
+	got := mergeUsage(nil, recorded, now)
+	require.Len(t, got, 1)
+	require.Len(t, got[0].Clients, maxClients)
+	require.Equal(t, "user-14", got[0].Clients[0].User)
+	require.Equal(t, int64(120), got[0].Requests)
+}
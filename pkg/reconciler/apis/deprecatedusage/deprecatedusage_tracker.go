@@ -0,0 +1,176 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deprecatedusage
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+)
+
+const (
+	// maxClientsPerBinding is the number of distinct clients recorded per APIBinding
+	// between two updates of its status. Requests of further clients are only counted
+	// for the version.
+	maxClientsPerBinding = 100
+
+	// maxUserAgentLength is the length user agents are truncated to.
+	maxUserAgentLength = 256
+)
+
+// usageKey identifies the requests of a client to a deprecated version. User and
+// userAgent are empty for requests of clients beyond maxClientsPerBinding.
+type usageKey struct {
+	group, resource, version string
+	user, userAgent          string
+}
+
+// usage is the number of requests of a client to a deprecated version.
+type usage struct {
+	requests int64
+	last     time.Time
+}
+
+// UsageTracker records the requests to deprecated versions of bound resources by the
+// APIBinding binding them, and notifies the controller when there are new requests for
+// an APIBinding.
+type UsageTracker struct {
+	now func() time.Time
+
+	lock   sync.RWMutex
+	usage  map[string]map[usageKey]*usage
+	lookup func(clusterName logicalcluster.Name, gvr schema.GroupVersionResource) (bindingKey string, deprecated bool)
+	notify func(bindingKey string)
+}
+
+// NewUsageTracker returns a UsageTracker without any requests. Nothing is recorded
+// until the controller is started.
+func NewUsageTracker() *UsageTracker {
+	registerMetrics()
+	return &UsageTracker{
+		now:   time.Now,
+		usage: map[string]map[usageKey]*usage{},
+	}
+}
+
+// WithUsageTracking records the requests served by handler to deprecated versions of
+// bound resources. It must run after the authentication filter and the request info
+// is known.
+func (t *UsageTracker) WithUsageTracking(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		t.track(req)
+		handler.ServeHTTP(w, req)
+	})
+}
+
+func (t *UsageTracker) track(req *http.Request) {
+	info, ok := apirequest.RequestInfoFrom(req.Context())
+	if !ok || !info.IsResourceRequest {
+		return
+	}
+	cluster := apirequest.ClusterFrom(req.Context())
+	if cluster == nil || cluster.Name.Empty() || cluster.Wildcard {
+		return
+	}
+
+	t.lock.RLock()
+	lookup := t.lookup
+	t.lock.RUnlock()
+	if lookup == nil {
+		return
+	}
+	gvr := schema.GroupVersionResource{Group: info.APIGroup, Version: info.APIVersion, Resource: info.Resource}
+	bindingKey, deprecated := lookup(cluster.Name, gvr)
+	if !deprecated {
+		return
+	}
+
+	key := usageKey{group: gvr.Group, resource: gvr.Resource, version: gvr.Version}
+	if u, ok := apirequest.UserFrom(req.Context()); ok {
+		key.user = u.GetName()
+	}
+	key.userAgent = req.UserAgent()
+	if len(key.userAgent) > maxUserAgentLength {
+		key.userAgent = key.userAgent[:maxUserAgentLength]
+	}
+	deprecatedVersionRequests.WithLabelValues(gvr.Group, gvr.Resource, gvr.Version).Inc()
+
+	t.record(bindingKey, key, 1, t.now())
+}
+
+func (t *UsageTracker) record(bindingKey string, key usageKey, requests int64, last time.Time) {
+	t.lock.Lock()
+	recorded, found := t.usage[bindingKey]
+	if !found {
+		recorded = map[usageKey]*usage{}
+		t.usage[bindingKey] = recorded
+	}
+	if _, ok := recorded[key]; !ok && len(recorded) >= maxClientsPerBinding {
+		key.user, key.userAgent = "", ""
+	}
+	u, ok := recorded[key]
+	if !ok {
+		u = &usage{}
+		recorded[key] = u
+	}
+	u.requests += requests
+	if last.After(u.last) {
+		u.last = last
+	}
+	notify := t.notify
+	t.lock.Unlock()
+
+	if !found && notify != nil {
+		notify(bindingKey)
+	}
+}
+
+// take returns the requests recorded for the APIBinding with the key, and forgets them.
+func (t *UsageTracker) take(bindingKey string) map[usageKey]*usage {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	recorded := t.usage[bindingKey]
+	delete(t.usage, bindingKey)
+	return recorded
+}
+
+// restore records the taken requests again, e.g. after failing to persist them.
+func (t *UsageTracker) restore(bindingKey string, recorded map[usageKey]*usage) {
+	for key, u := range recorded {
+		t.record(bindingKey, key, u.requests, u.last)
+	}
+}
+
+func (t *UsageTracker) setLookupFunc(lookup func(clusterName logicalcluster.Name, gvr schema.GroupVersionResource) (string, bool)) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.lookup = lookup
+}
+
+func (t *UsageTracker) setNotifyFunc(notify func(bindingKey string)) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.notify = notify
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deprecatedusage
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/authentication/user"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/tools/clusters"
+)
+
+func TestUsageTracker(t *testing.T) {
+	now := time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	tracker := NewUsageTracker()
+	tracker.now = func() time.Time { return now }
+
+	var notified []string
+	tracker.setNotifyFunc(func(bindingKey string) { notified = append(notified, bindingKey) })
+
+	send := func(cluster string, info *apirequest.RequestInfo, userName, userAgent string) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		ctx := apirequest.WithRequestInfo(req.Context(), info)
+		if cluster != "" {
+			ctx = apirequest.WithCluster(ctx, apirequest.Cluster{Name: logicalcluster.New(cluster)})
+		}
+		if userName != "" {
+			ctx = apirequest.WithUser(ctx, &user.DefaultInfo{Name: userName})
+		}
+		req = req.WithContext(ctx)
+		req.Header.Set("User-Agent", userAgent)
+
+		served := false
+		tracker.WithUsageTracking(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) { served = true })).ServeHTTP(httptest.NewRecorder(), req)
+		require.True(t, served)
+	}
+	widgets := func(version string) *apirequest.RequestInfo {
+		return &apirequest.RequestInfo{IsResourceRequest: true, Verb: "list", APIGroup: "example.io", APIVersion: version, Resource: "widgets"}
+	}
+
+	// nothing is recorded without lookup
+	send("root:org", widgets("v1alpha1"), "alice", "kubectl")
+	require.Empty(t, tracker.take("root:org|widgets"))
+
+	tracker.setLookupFunc(func(clusterName logicalcluster.Name, gvr schema.GroupVersionResource) (string, bool) {
+		if gvr.GroupResource() != (schema.GroupResource{Group: "example.io", Resource: "widgets"}) || gvr.Version != "v1alpha1" {
+			return "", false
+		}
+		return clusters.ToClusterAwareKey(clusterName, "widgets"), true
+	})
+
+	send("root:org", widgets("v1alpha1"), "alice", "kubectl")
+	send("root:org", widgets("v1alpha1"), "alice", "kubectl")
+	send("root:org", widgets("v1alpha1"), "bob", strings.Repeat("x", 300))
+	send("root:org", widgets("v1"), "alice", "kubectl")
+	send("root:org", &apirequest.RequestInfo{IsResourceRequest: false, Path: "/apis/example.io/v1alpha1"}, "alice", "kubectl")
+	send("", widgets("v1alpha1"), "alice", "kubectl")
+	send("root:other", widgets("v1alpha1"), "alice", "kubectl")
+
+	require.Equal(t, []string{"root:org|widgets", "root:other|widgets"}, notified)
+	require.Equal(t, map[usageKey]*usage{
+		{group: "example.io", resource: "widgets", version: "v1alpha1", user: "alice", userAgent: "kubectl"}:              {requests: 2, last: now},
+		{group: "example.io", resource: "widgets", version: "v1alpha1", user: "bob", userAgent: strings.Repeat("x", 256)}: {requests: 1, last: now},
+	}, tracker.take("root:org|widgets"))
+	require.Empty(t, tracker.take("root:org|widgets"))
+
+	// clients beyond the limit are only counted for the version
+	for i := 0; i < maxClientsPerBinding+5; i++ {
+		send("root:org", widgets("v1alpha1"), fmt.Sprintf("user-%d", i), "kubectl")
+	}
+	recorded := tracker.take("root:org|widgets")
+	require.Len(t, recorded, maxClientsPerBinding+1)
+	require.Equal(t, &usage{requests: 5, last: now}, recorded[usageKey{group: "example.io", resource: "widgets", version: "v1alpha1"}])
+
+	// restored requests are added to new ones
+	send("root:org", widgets("v1alpha1"), "alice", "kubectl")
+	tracker.restore("root:org|widgets", map[usageKey]*usage{
+		{group: "example.io", resource: "widgets", version: "v1alpha1", user: "alice", userAgent: "kubectl"}: {requests: 3, last: now.Add(-time.Minute)},
+	})
+	require.Equal(t, map[usageKey]*usage{
+		{group: "example.io", resource: "widgets", version: "v1alpha1", user: "alice", userAgent: "kubectl"}: {requests: 4, last: now},
+	}, tracker.take("root:org|widgets"))
+}
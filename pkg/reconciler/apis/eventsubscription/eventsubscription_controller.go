@@ -0,0 +1,408 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventsubscription
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clusters"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	kcpclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
+	apisinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/apis/v1alpha1"
+	tenancyinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/tenancy/v1alpha1"
+	apislisters "github.com/kcp-dev/kcp/pkg/client/listers/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/eventbus"
+	"github.com/kcp-dev/kcp/pkg/informer"
+	"github.com/kcp-dev/kcp/pkg/reconciler/latency"
+)
+
+const (
+	controllerName = "kcp-eventsubscription"
+
+	byWorkspace = controllerName + "-byWorkspace"
+
+	// sinkName is the name of the sinks of all subscriptions in metrics, to keep their
+	// cardinality independent of the number of subscriptions.
+	sinkName = "eventsubscription"
+
+	// drainTimeout is how long the events of a removed or changed subscription are still delivered.
+	drainTimeout = 5 * time.Second
+)
+
+type clusterDiscovery interface {
+	WithCluster(name logicalcluster.Name) discovery.DiscoveryInterface
+}
+
+// NewController returns a new controller that emits CloudEvents for the objects of bound
+// resources selected by EventSubscriptions. All objects of all workspaces are watched by one
+// set of metadata informers, such that the cost does not grow with the number of subscriptions.
+func NewController(
+	kcpClusterClient kcpclient.ClusterInterface,
+	metadataClusterClient dynamic.ClusterInterface,
+	clusterDiscoveryClient clusterDiscovery,
+	workspaceInformer tenancyinformers.ClusterWorkspaceInformer,
+	eventSubscriptionInformer apisinformers.EventSubscriptionInformer,
+	apiBindingInformer apisinformers.APIBindingInformer,
+	source string,
+	pollInterval time.Duration,
+) (*controller, error) {
+	queue := latency.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	c := &controller{
+		queue:                    queue,
+		kcpClusterClient:         kcpClusterClient,
+		eventSubscriptionLister:  eventSubscriptionInformer.Lister(),
+		eventSubscriptionIndexer: eventSubscriptionInformer.Informer().GetIndexer(),
+		source:                   source,
+		since:                    time.Now(),
+		subscriptions:            map[logicalcluster.Name]map[string]*subscription{},
+		listAPIBindings: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
+			objs, err := apiBindingInformer.Informer().GetIndexer().ByIndex(byWorkspace, clusterName.String())
+			if err != nil {
+				return nil, err
+			}
+			bindings := make([]*apisv1alpha1.APIBinding, 0, len(objs))
+			for _, obj := range objs {
+				bindings = append(bindings, obj.(*apisv1alpha1.APIBinding))
+			}
+			return bindings, nil
+		},
+		newSink: func(url string) eventbus.Sink {
+			return &eventbus.CloudEventsSink{SinkName: sinkName, URL: url, Client: client}
+		},
+	}
+
+	for _, inf := range []cache.SharedIndexInformer{eventSubscriptionInformer.Informer(), apiBindingInformer.Informer()} {
+		if _, found := inf.GetIndexer().GetIndexers()[byWorkspace]; found {
+			continue
+		}
+		if err := inf.AddIndexers(cache.Indexers{byWorkspace: indexByWorkspace}); err != nil {
+			return nil, err
+		}
+	}
+
+	eventSubscriptionInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueueEventSubscription(obj) },
+		UpdateFunc: func(_, obj interface{}) { c.enqueueEventSubscription(obj) },
+		DeleteFunc: func(obj interface{}) { c.enqueueEventSubscription(obj) },
+	})
+
+	apiBindingInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueueAPIBinding(obj) },
+		UpdateFunc: func(_, obj interface{}) { c.enqueueAPIBinding(obj) },
+		DeleteFunc: func(obj interface{}) { c.enqueueAPIBinding(obj) },
+	})
+
+	c.ddsif = informer.NewDynamicDiscoverySharedInformerFactory(
+		workspaceInformer.Lister(),
+		clusterDiscoveryClient,
+		metadataClusterClient.Cluster(logicalcluster.Wildcard),
+		func(obj interface{}) bool { return true },
+		informer.GVREventHandlerFuncs{
+			AddFunc: func(gvr schema.GroupVersionResource, obj interface{}) {
+				// objects listed initially are not announced as created.
+				if metaObj, ok := obj.(metav1.Object); ok && metaObj.GetCreationTimestamp().Time.Before(c.since) {
+					return
+				}
+				c.dispatch(gvr, apisv1alpha1.EventSubscriptionCreate, obj)
+			},
+			UpdateFunc: func(gvr schema.GroupVersionResource, oldObj, newObj interface{}) {
+				// skip resyncs.
+				oldMeta, ok := oldObj.(metav1.Object)
+				if !ok {
+					return
+				}
+				newMeta, ok := newObj.(metav1.Object)
+				if !ok || oldMeta.GetResourceVersion() == newMeta.GetResourceVersion() {
+					return
+				}
+				c.dispatch(gvr, apisv1alpha1.EventSubscriptionUpdate, newObj)
+			},
+			DeleteFunc: func(gvr schema.GroupVersionResource, obj interface{}) {
+				c.dispatch(gvr, apisv1alpha1.EventSubscriptionDelete, obj)
+			},
+		},
+		pollInterval,
+	)
+
+	return c, nil
+}
+
+// subscription is the active state of an EventSubscription.
+type subscription struct {
+	url string
+	// resources are the operations that emit events, by bound resource.
+	resources map[schema.GroupResource]sets.String
+	bus       *eventbus.Bus
+	stop      context.CancelFunc
+}
+
+// controller reconciles EventSubscriptions, and emits their events.
+type controller struct {
+	queue workqueue.RateLimitingInterface
+
+	kcpClusterClient         kcpclient.ClusterInterface
+	eventSubscriptionLister  apislisters.EventSubscriptionLister
+	eventSubscriptionIndexer cache.Indexer
+	ddsif                    informer.DynamicDiscoverySharedInformerFactory
+
+	source string
+	// since is when the controller was created. Objects created before are not announced.
+	since time.Time
+
+	lock          sync.RWMutex
+	subscriptions map[logicalcluster.Name]map[string]*subscription
+
+	listAPIBindings func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error)
+	newSink         func(url string) eventbus.Sink
+}
+
+func (c *controller) enqueueEventSubscription(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	klog.V(2).Infof("Queueing EventSubscription %q", key)
+	c.queue.Add(key)
+}
+
+// enqueueAPIBinding enqueues the EventSubscriptions of the workspace of the APIBinding, whose
+// bound resources might have changed.
+func (c *controller) enqueueAPIBinding(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	binding, ok := obj.(*apisv1alpha1.APIBinding)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("obj is supposed to be an APIBinding, but is %T", obj))
+		return
+	}
+
+	subscriptions, err := c.eventSubscriptionIndexer.ByIndex(byWorkspace, logicalcluster.From(binding).String())
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	for _, obj := range subscriptions {
+		key, err := cache.MetaNamespaceKeyFunc(obj)
+		if err != nil {
+			runtime.HandleError(err)
+			continue
+		}
+		klog.V(2).Infof("Queueing EventSubscription %q because of APIBinding %s|%s", key, logicalcluster.From(binding), binding.Name)
+		c.queue.Add(key)
+	}
+}
+
+// dispatch publishes the event of the operation on the object to the subscriptions of its
+// workspace selecting it.
+func (c *controller) dispatch(gvr schema.GroupVersionResource, op apisv1alpha1.EventSubscriptionOperation, obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	metaObj, ok := obj.(metav1.Object)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("obj is supposed to be a metav1.Object, but is %T", obj))
+		return
+	}
+	clusterName := logicalcluster.From(metaObj)
+
+	var buses []*eventbus.Bus
+	c.lock.RLock()
+	for _, s := range c.subscriptions[clusterName] {
+		if s.resources[gvr.GroupResource()].Has(string(op)) {
+			buses = append(buses, s.bus)
+		}
+	}
+	c.lock.RUnlock()
+	if len(buses) == 0 {
+		return
+	}
+
+	data := eventbus.NewResourceEventData(gvr, metaObj)
+	subject := eventbus.ResourceEventSubject(metaObj)
+	for _, bus := range buses {
+		bus.Publish(eventTypes[op], clusterName.String(), subject, data)
+	}
+}
+
+var eventTypes = map[apisv1alpha1.EventSubscriptionOperation]string{
+	apisv1alpha1.EventSubscriptionCreate: eventbus.ResourceCreatedEventType,
+	apisv1alpha1.EventSubscriptionUpdate: eventbus.ResourceUpdatedEventType,
+	apisv1alpha1.EventSubscriptionDelete: eventbus.ResourceDeletedEventType,
+}
+
+// activate makes the subscription emit events of the given resources to the URL. The bus of
+// the subscription is replaced if the URL changed.
+func (c *controller) activate(ctx context.Context, clusterName logicalcluster.Name, name, url string, resources map[schema.GroupResource]sets.String) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	subscriptions := c.subscriptions[clusterName]
+	if subscriptions == nil {
+		subscriptions = map[string]*subscription{}
+		c.subscriptions[clusterName] = subscriptions
+	}
+
+	if existing, ok := subscriptions[name]; ok && existing.url == url {
+		subscriptions[name] = &subscription{url: url, resources: resources, bus: existing.bus, stop: existing.stop}
+		return
+	} else if ok {
+		existing.stop()
+	}
+
+	klog.Infof("Emitting events of EventSubscription %s|%s to %s", clusterName, name, url)
+	bus := eventbus.NewBus(c.source, []eventbus.SinkConfig{{Sink: c.newSink(url)}})
+	busCtx, stop := context.WithCancel(ctx)
+	go bus.Start(busCtx, drainTimeout)
+	subscriptions[name] = &subscription{url: url, resources: resources, bus: bus, stop: stop}
+}
+
+// deactivate stops emitting the events of a deleted subscription.
+func (c *controller) deactivate(clusterName logicalcluster.Name, name string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	existing, ok := c.subscriptions[clusterName][name]
+	if !ok {
+		return
+	}
+	klog.Infof("Stopping events of deleted EventSubscription %s|%s", clusterName, name)
+	existing.stop()
+	delete(c.subscriptions[clusterName], name)
+	if len(c.subscriptions[clusterName]) == 0 {
+		delete(c.subscriptions, clusterName)
+	}
+}
+
+// Start starts the controller, which stops when ctx.Done() is closed.
+func (c *controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	klog.Infof("Starting %s controller", controllerName)
+	defer klog.Infof("Shutting down %s controller", controllerName)
+
+	c.ddsif.Start(ctx)
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+
+	<-ctx.Done()
+}
+
+func (c *controller) startWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	if err := c.process(ctx, key); err != nil {
+		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", controllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *controller) process(ctx context.Context, key string) error {
+	obj, err := c.eventSubscriptionLister.Get(key)
+	if errors.IsNotFound(err) {
+		clusterName, name := clusters.SplitClusterAwareKey(key)
+		c.deactivate(clusterName, name)
+		return nil
+	} else if err != nil {
+		return err
+	}
+	old := obj
+	obj = obj.DeepCopy()
+
+	resources, err := c.reconcile(obj)
+	if err != nil {
+		return err
+	}
+	clusterName := logicalcluster.From(obj)
+	c.activate(ctx, clusterName, obj.Name, obj.Spec.Sink.URL, resources)
+
+	// If the object being reconciled changed as a result, update it.
+	if !equality.Semantic.DeepEqual(old.Status, obj.Status) {
+		oldData, err := json.Marshal(apisv1alpha1.EventSubscription{
+			Status: old.Status,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to Marshal old data for EventSubscription %s|%s: %w", clusterName, obj.Name, err)
+		}
+
+		newData, err := json.Marshal(apisv1alpha1.EventSubscription{
+			ObjectMeta: metav1.ObjectMeta{
+				UID:             obj.UID,
+				ResourceVersion: obj.ResourceVersion,
+			}, // to ensure they appear in the patch as preconditions
+			Status: obj.Status,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to Marshal new data for EventSubscription %s|%s: %w", clusterName, obj.Name, err)
+		}
+
+		patchBytes, err := jsonpatch.CreateMergePatch(oldData, newData)
+		if err != nil {
+			return fmt.Errorf("failed to create patch for EventSubscription %s|%s: %w", clusterName, obj.Name, err)
+		}
+		_, uerr := c.kcpClusterClient.Cluster(clusterName).ApisV1alpha1().EventSubscriptions().Patch(ctx, obj.Name, types.MergePatchType, patchBytes, metav1.PatchOptions{}, "status")
+		return uerr
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventsubscription
+
+import (
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/third_party/conditions/util/conditions"
+)
+
+var allOperations = []string{
+	string(apisv1alpha1.EventSubscriptionCreate),
+	string(apisv1alpha1.EventSubscriptionUpdate),
+	string(apisv1alpha1.EventSubscriptionDelete),
+}
+
+// reconcile updates the ResourcesBound condition of the subscription, and returns the
+// operations that emit events by selected resource that is bound in the workspace.
+func (c *controller) reconcile(sub *apisv1alpha1.EventSubscription) (map[schema.GroupResource]sets.String, error) {
+	bindings, err := c.listAPIBindings(logicalcluster.From(sub))
+	if err != nil {
+		return nil, err
+	}
+	bound := map[schema.GroupResource]bool{}
+	for _, binding := range bindings {
+		for _, r := range binding.Status.BoundResources {
+			bound[schema.GroupResource{Group: r.Group, Resource: r.Resource}] = true
+		}
+	}
+
+	resources := map[schema.GroupResource]sets.String{}
+	notBound := sets.NewString()
+	for _, r := range sub.Spec.Resources {
+		gr := schema.GroupResource{Group: r.Group, Resource: r.Resource}
+		if !bound[gr] {
+			notBound.Insert(gr.String())
+			continue
+		}
+		ops := resources[gr]
+		if ops == nil {
+			ops = sets.NewString()
+			resources[gr] = ops
+		}
+		if len(r.Operations) == 0 {
+			ops.Insert(allOperations...)
+		}
+		for _, op := range r.Operations {
+			ops.Insert(string(op))
+		}
+	}
+
+	if notBound.Len() > 0 {
+		conditions.MarkFalse(
+			sub,
+			apisv1alpha1.EventSubscriptionResourcesBound,
+			apisv1alpha1.ResourceNotBoundReason,
+			conditionsv1alpha1.ConditionSeverityWarning,
+			"Resources are not bound by an APIBinding and emit no events: %s",
+			strings.Join(notBound.List(), ", "),
+		)
+	} else {
+		conditions.MarkTrue(sub, apisv1alpha1.EventSubscriptionResourcesBound)
+	}
+
+	return resources, nil
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventsubscription
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/eventbus"
+	"github.com/kcp-dev/kcp/third_party/conditions/util/conditions"
+)
+
+func TestReconcile(t *testing.T) {
+	bindings := []*apisv1alpha1.APIBinding{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "widgets", ClusterName: "root:org:ws"},
+			Status: apisv1alpha1.APIBindingStatus{
+				BoundResources: []apisv1alpha1.BoundAPIResource{
+					{Group: "example.com", Resource: "widgets"},
+					{Group: "example.com", Resource: "gadgets"},
+				},
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		resources     []apisv1alpha1.EventSubscriptionResource
+		wantResources map[schema.GroupResource]sets.String
+		wantBound     bool
+		wantMessage   string
+	}{
+		"all operations": {
+			resources: []apisv1alpha1.EventSubscriptionResource{{Group: "example.com", Resource: "widgets"}},
+			wantResources: map[schema.GroupResource]sets.String{
+				{Group: "example.com", Resource: "widgets"}: sets.NewString("Create", "Update", "Delete"),
+			},
+			wantBound: true,
+		},
+		"selected operations": {
+			resources: []apisv1alpha1.EventSubscriptionResource{
+				{Group: "example.com", Resource: "widgets", Operations: []apisv1alpha1.EventSubscriptionOperation{apisv1alpha1.EventSubscriptionCreate}},
+				{Group: "example.com", Resource: "widgets", Operations: []apisv1alpha1.EventSubscriptionOperation{apisv1alpha1.EventSubscriptionDelete}},
+				{Group: "example.com", Resource: "gadgets", Operations: []apisv1alpha1.EventSubscriptionOperation{apisv1alpha1.EventSubscriptionUpdate}},
+			},
+			wantResources: map[schema.GroupResource]sets.String{
+				{Group: "example.com", Resource: "widgets"}: sets.NewString("Create", "Delete"),
+				{Group: "example.com", Resource: "gadgets"}: sets.NewString("Update"),
+			},
+			wantBound: true,
+		},
+		"unbound resources": {
+			resources: []apisv1alpha1.EventSubscriptionResource{
+				{Group: "example.com", Resource: "widgets"},
+				{Resource: "secrets"},
+				{Group: "other.com", Resource: "widgets"},
+			},
+			wantResources: map[schema.GroupResource]sets.String{
+				{Group: "example.com", Resource: "widgets"}: sets.NewString("Create", "Update", "Delete"),
+			},
+			wantMessage: "Resources are not bound by an APIBinding and emit no events: secrets, widgets.other.com",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &controller{
+				listAPIBindings: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
+					require.Equal(t, "root:org:ws", clusterName.String())
+					return bindings, nil
+				},
+			}
+			sub := &apisv1alpha1.EventSubscription{
+				ObjectMeta: metav1.ObjectMeta{Name: "automation", ClusterName: "root:org:ws"},
+				Spec: apisv1alpha1.EventSubscriptionSpec{
+					Resources: tt.resources,
+					Sink:      apisv1alpha1.EventSubscriptionSink{URL: "https://broker.example.com"},
+				},
+			}
+
+			resources, err := c.reconcile(sub)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantResources, resources)
+			require.Equal(t, tt.wantBound, conditions.IsTrue(sub, apisv1alpha1.EventSubscriptionResourcesBound))
+			if !tt.wantBound {
+				require.Equal(t, apisv1alpha1.ResourceNotBoundReason, conditions.GetReason(sub, apisv1alpha1.EventSubscriptionResourcesBound))
+				require.Equal(t, tt.wantMessage, conditions.GetMessage(sub, apisv1alpha1.EventSubscriptionResourcesBound))
+			}
+		})
+	}
+}
+
+type recordingSink struct {
+	lock   sync.Mutex
+	events []eventbus.Event
+}
+
+func (s *recordingSink) Name() string { return sinkName }
+
+func (s *recordingSink) Send(_ context.Context, events []eventbus.Event) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.events = append(s.events, events...)
+	return nil
+}
+
+func TestDispatch(t *testing.T) {
+	widgets := schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+	gadgets := schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "gadgets"}
+
+	allSink, createSink := &recordingSink{}, &recordingSink{}
+	allBus := eventbus.NewBus("https://kcp.example.com", []eventbus.SinkConfig{{Sink: allSink}})
+	createBus := eventbus.NewBus("https://kcp.example.com", []eventbus.SinkConfig{{Sink: createSink}})
+	c := &controller{
+		subscriptions: map[logicalcluster.Name]map[string]*subscription{
+			logicalcluster.New("root:org:ws"): {
+				"all": {
+					resources: map[schema.GroupResource]sets.String{widgets.GroupResource(): sets.NewString(allOperations...)},
+					bus:       allBus,
+				},
+				"create": {
+					resources: map[schema.GroupResource]sets.String{
+						widgets.GroupResource(): sets.NewString("Create"),
+						gadgets.GroupResource(): sets.NewString("Create"),
+					},
+					bus: createBus,
+				},
+			},
+		},
+	}
+
+	object := func(cluster, namespace, name string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetClusterName(cluster)
+		obj.SetNamespace(namespace)
+		obj.SetName(name)
+		obj.SetUID("uid-" + name)
+		obj.SetResourceVersion("42")
+		obj.SetLabels(map[string]string{"app": "shop"})
+		return obj
+	}
+
+	c.dispatch(widgets, apisv1alpha1.EventSubscriptionCreate, object("root:org:ws", "default", "a"))
+	c.dispatch(widgets, apisv1alpha1.EventSubscriptionUpdate, object("root:org:ws", "default", "a"))
+	c.dispatch(gadgets, apisv1alpha1.EventSubscriptionUpdate, object("root:org:ws", "default", "b"))
+	c.dispatch(gadgets, apisv1alpha1.EventSubscriptionCreate, object("root:org:ws", "", "c"))
+	c.dispatch(widgets, apisv1alpha1.EventSubscriptionCreate, object("root:org:other", "default", "d"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	allBus.Start(ctx, time.Minute)
+	createBus.Start(ctx, time.Minute)
+
+	require.Len(t, allSink.events, 2)
+	require.Equal(t, eventbus.ResourceCreatedEventType, allSink.events[0].Type)
+	require.Equal(t, eventbus.ResourceUpdatedEventType, allSink.events[1].Type)
+	require.Equal(t, "root:org:ws", allSink.events[0].Workspace)
+	require.Equal(t, "default/a", allSink.events[0].Subject)
+	var data eventbus.ResourceEventData
+	require.NoError(t, json.Unmarshal(allSink.events[0].Data, &data))
+	require.Equal(t, eventbus.ResourceEventData{
+		Group:           "example.com",
+		Version:         "v1",
+		Resource:        "widgets",
+		Namespace:       "default",
+		Name:            "a",
+		UID:             "uid-a",
+		ResourceVersion: "42",
+		Labels:          map[string]string{"app": "shop"},
+	}, data)
+
+	require.Len(t, createSink.events, 2)
+	require.Equal(t, "default/a", createSink.events[0].Subject)
+	require.Equal(t, "c", createSink.events[1].Subject)
+}
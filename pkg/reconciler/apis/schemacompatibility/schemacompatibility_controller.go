@@ -0,0 +1,243 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemacompatibility
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/kcp-dev/logicalcluster"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsinformers "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clusters"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	kcpclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
+	apisinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/apis/v1alpha1"
+	apislisters "github.com/kcp-dev/kcp/pkg/client/listers/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apibinding"
+	"github.com/kcp-dev/kcp/pkg/reconciler/latency"
+)
+
+const (
+	controllerName = "kcp-schemacompatibility"
+
+	// recheckInterval is how often objects are validated again while some are invalid,
+	// such that fixed objects unblock the upgrade.
+	recheckInterval = time.Minute
+)
+
+// NewController returns a new controller that validates the objects bound through an APIBinding
+// against the latest resource schemas of its APIExport, and reports the result in the
+// APIBinding status before the APIBinding controller upgrades the binding to them.
+//
+// The APIBinding informer must have the apibinding.IndexAPIBindingsByWorkspaceExport index.
+func NewController(
+	kcpClusterClient kcpclient.ClusterInterface,
+	dynamicClusterClient dynamic.ClusterInterface,
+	apiBindingInformer apisinformers.APIBindingInformer,
+	apiExportInformer apisinformers.APIExportInformer,
+	apiResourceSchemaInformer apisinformers.APIResourceSchemaInformer,
+	crdInformer apiextensionsinformers.CustomResourceDefinitionInformer,
+) (*controller, error) {
+	queue := latency.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+
+	c := &controller{
+		queue: queue,
+		enqueueAfter: func(binding *apisv1alpha1.APIBinding, duration time.Duration) {
+			queue.AddAfter(clusters.ToClusterAwareKey(logicalcluster.From(binding), binding.Name), duration)
+		},
+		kcpClusterClient:   kcpClusterClient,
+		apiBindingsLister:  apiBindingInformer.Lister(),
+		apiBindingsIndexer: apiBindingInformer.Informer().GetIndexer(),
+		getAPIExport: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error) {
+			return apiExportInformer.Lister().Get(clusters.ToClusterAwareKey(clusterName, name))
+		},
+		getAPIResourceSchema: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error) {
+			return apiResourceSchemaInformer.Lister().Get(clusters.ToClusterAwareKey(clusterName, name))
+		},
+		getCRD: func(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error) {
+			return crdInformer.Lister().Get(clusters.ToClusterAwareKey(clusterName, name))
+		},
+		listObjects: func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, continueToken string) (*unstructured.UnstructuredList, error) {
+			return dynamicClusterClient.Cluster(clusterName).Resource(gvr).List(ctx, metav1.ListOptions{Limit: 500, Continue: continueToken})
+		},
+	}
+
+	apiBindingInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueueAPIBinding(obj) },
+		UpdateFunc: func(_, obj interface{}) { c.enqueueAPIBinding(obj) },
+	})
+
+	apiExportInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueueAPIExport(obj) },
+		UpdateFunc: func(_, obj interface{}) { c.enqueueAPIExport(obj) },
+	})
+
+	return c, nil
+}
+
+// controller reconciles the status.schemaCompatibility of APIBindings.
+type controller struct {
+	queue        workqueue.RateLimitingInterface
+	enqueueAfter func(*apisv1alpha1.APIBinding, time.Duration)
+
+	kcpClusterClient kcpclient.ClusterInterface
+
+	apiBindingsLister  apislisters.APIBindingLister
+	apiBindingsIndexer cache.Indexer
+
+	getAPIExport         func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error)
+	getAPIResourceSchema func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error)
+	getCRD               func(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error)
+	listObjects          func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, continueToken string) (*unstructured.UnstructuredList, error)
+}
+
+func (c *controller) enqueueAPIBinding(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	klog.V(2).Infof("Queueing APIBinding %q", key)
+	c.queue.Add(key)
+}
+
+// enqueueAPIExport maps an APIExport to the APIBindings referencing it.
+func (c *controller) enqueueAPIExport(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	bindingsForExport, err := c.apiBindingsIndexer.ByIndex(apibinding.IndexAPIBindingsByWorkspaceExport, key)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	for _, apiBinding := range bindingsForExport {
+		c.enqueueAPIBinding(apiBinding)
+	}
+}
+
+// Start starts the controller, which stops when ctx.Done() is closed.
+func (c *controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	klog.Infof("Starting %s controller", controllerName)
+	defer klog.Infof("Shutting down %s controller", controllerName)
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+
+	<-ctx.Done()
+}
+
+func (c *controller) startWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	if err := c.process(ctx, key); err != nil {
+		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", controllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *controller) process(ctx context.Context, key string) error {
+	obj, err := c.apiBindingsLister.Get(key)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil // object deleted before we handled it
+		}
+		return err
+	}
+	old := obj
+	obj = obj.DeepCopy()
+
+	if err := c.reconcile(ctx, obj); err != nil {
+		return err
+	}
+
+	// If the object being reconciled changed as a result, update it.
+	if !equality.Semantic.DeepEqual(old.Status.SchemaCompatibility, obj.Status.SchemaCompatibility) {
+		clusterName := logicalcluster.From(obj)
+
+		oldData, err := json.Marshal(apisv1alpha1.APIBinding{
+			Status: apisv1alpha1.APIBindingStatus{SchemaCompatibility: old.Status.SchemaCompatibility},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to Marshal old data for APIBinding %s|%s: %w", clusterName, obj.Name, err)
+		}
+
+		newData, err := json.Marshal(apisv1alpha1.APIBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				UID:             obj.UID,
+				ResourceVersion: obj.ResourceVersion,
+			}, // to ensure they appear in the patch as preconditions
+			Status: apisv1alpha1.APIBindingStatus{SchemaCompatibility: obj.Status.SchemaCompatibility},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to Marshal new data for APIBinding %s|%s: %w", clusterName, obj.Name, err)
+		}
+
+		patchBytes, err := jsonpatch.CreateMergePatch(oldData, newData)
+		if err != nil {
+			return fmt.Errorf("failed to create patch for APIBinding %s|%s: %w", clusterName, obj.Name, err)
+		}
+		_, uerr := c.kcpClusterClient.Cluster(clusterName).ApisV1alpha1().APIBindings().Patch(ctx, obj.Name, types.MergePatchType, patchBytes, metav1.PatchOptions{}, "status")
+		return uerr
+	}
+
+	return nil
+}
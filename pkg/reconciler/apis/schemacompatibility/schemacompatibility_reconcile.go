@@ -0,0 +1,203 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemacompatibility
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	apiextensionsinternal "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiservervalidation "k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apibinding"
+)
+
+// maxReportedObjects is the maximum number of invalid objects listed in a report.
+const maxReportedObjects = 100
+
+func (c *controller) reconcile(ctx context.Context, apiBinding *apisv1alpha1.APIBinding) error {
+	if apiBinding.Status.Phase != apisv1alpha1.APIBindingPhaseBound || apiBinding.Spec.Reference.Workspace == nil {
+		return nil
+	}
+
+	parent, hasParent := logicalcluster.From(apiBinding).Parent()
+	if !hasParent {
+		return nil
+	}
+	apiExportClusterName := parent.Join(apiBinding.Spec.Reference.Workspace.WorkspaceName)
+
+	apiExport, err := c.getAPIExport(apiExportClusterName, apiBinding.Spec.Reference.Workspace.ExportName)
+	if errors.IsNotFound(err) {
+		return nil // the APIBinding controller reports that
+	} else if err != nil {
+		return err
+	}
+
+	var latestSchemas []*apisv1alpha1.APIResourceSchema
+	latestUIDs := sets.NewString()
+	for _, schemaName := range apiExport.Spec.LatestResourceSchemas {
+		s, err := c.getAPIResourceSchema(apiExportClusterName, schemaName)
+		if errors.IsNotFound(err) {
+			return nil // the APIBinding controller reports that
+		} else if err != nil {
+			return err
+		}
+		latestSchemas = append(latestSchemas, s)
+		latestUIDs.Insert(string(s.UID))
+	}
+
+	boundUIDs := sets.NewString()
+	for _, r := range apiBinding.Status.BoundResources {
+		boundUIDs.Insert(r.Schema.UID)
+	}
+	if latestUIDs.Equal(boundUIDs) {
+		apiBinding.Status.SchemaCompatibility = nil
+		return nil
+	}
+
+	report := &apisv1alpha1.SchemaCompatibilityReport{
+		SchemaUIDs:     latestUIDs.List(),
+		InvalidObjects: []apisv1alpha1.IncompatibleObject{},
+	}
+	for _, s := range latestSchemas {
+		if err := c.validateObjects(ctx, apiBinding, s, report); err != nil {
+			return err
+		}
+	}
+	if len(report.InvalidObjects) == 0 {
+		report.InvalidObjects = nil
+	}
+	apiBinding.Status.SchemaCompatibility = report
+
+	if report.InvalidObjectCount > 0 {
+		klog.V(2).Infof("APIBinding %s|%s has %d objects invalid according to the latest resource schemas of its APIExport", logicalcluster.From(apiBinding), apiBinding.Name, report.InvalidObjectCount)
+		c.enqueueAfter(apiBinding, recheckInterval)
+	}
+
+	return nil
+}
+
+// validateObjects validates the stored objects of the resource of the given schema against the
+// storage version of the schema, and adds the result to the report.
+func (c *controller) validateObjects(ctx context.Context, apiBinding *apisv1alpha1.APIBinding, latest *apisv1alpha1.APIResourceSchema, report *apisv1alpha1.SchemaCompatibilityReport) error {
+	var bound *apisv1alpha1.BoundAPIResource
+	for i := range apiBinding.Status.BoundResources {
+		r := &apiBinding.Status.BoundResources[i]
+		if r.Group == latest.Spec.Group && r.Resource == latest.Spec.Names.Plural {
+			bound = r
+			break
+		}
+	}
+	if bound == nil || bound.Schema.UID == string(latest.UID) {
+		return nil // new resources have no objects, and unchanged ones are compatible
+	}
+
+	// bound CRDs are named after the UID of their schema. They have no conversion, so objects
+	// are stored the same way in all versions, and listing in the storage version returns them
+	// unchanged.
+	crd, err := c.getCRD(apibinding.ShadowWorkspaceName, bound.Schema.UID)
+	if errors.IsNotFound(err) {
+		return nil // not served, so there is nothing stored to check
+	} else if err != nil {
+		return err
+	}
+	var boundVersion string
+	for _, v := range crd.Spec.Versions {
+		if v.Storage {
+			boundVersion = v.Name
+		}
+	}
+
+	validator, err := storageVersionValidator(latest)
+	if err != nil {
+		return err
+	}
+
+	gvr := schema.GroupVersionResource{Group: bound.Group, Version: boundVersion, Resource: bound.Resource}
+	continueToken := ""
+	for {
+		list, err := c.listObjects(ctx, logicalcluster.From(apiBinding), gvr, continueToken)
+		if err != nil {
+			return fmt.Errorf("failed to list %s in %s: %w", gvr, logicalcluster.From(apiBinding), err)
+		}
+
+		for i := range list.Items {
+			obj := &list.Items[i]
+			report.CheckedObjects++
+
+			errs := apiservervalidation.ValidateCustomResource(nil, obj.UnstructuredContent(), validator)
+			if len(errs) == 0 {
+				continue
+			}
+
+			report.InvalidObjectCount++
+			if len(report.InvalidObjects) >= maxReportedObjects {
+				continue
+			}
+			invalid := apisv1alpha1.IncompatibleObject{
+				Group:     bound.Group,
+				Resource:  bound.Resource,
+				Namespace: obj.GetNamespace(),
+				Name:      obj.GetName(),
+			}
+			for _, err := range errs {
+				invalid.Errors = append(invalid.Errors, err.Error())
+			}
+			report.InvalidObjects = append(report.InvalidObjects, invalid)
+		}
+
+		continueToken = list.GetContinue()
+		if continueToken == "" {
+			return nil
+		}
+	}
+}
+
+// storageVersionValidator returns a validator for the storage version of the schema.
+func storageVersionValidator(s *apisv1alpha1.APIResourceSchema) (*validate.SchemaValidator, error) {
+	for _, version := range s.Spec.Versions {
+		if !version.Storage {
+			continue
+		}
+
+		var v1Schema apiextensionsv1.JSONSchemaProps
+		if err := json.Unmarshal(version.Schema.Raw, &v1Schema); err != nil {
+			return nil, fmt.Errorf("failed to decode schema of APIResourceSchema %s|%s version %s: %w", logicalcluster.From(s), s.Name, version.Name, err)
+		}
+		internalSchema := &apiextensionsinternal.JSONSchemaProps{}
+		if err := apiextensionsv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(&v1Schema, internalSchema, nil); err != nil {
+			return nil, fmt.Errorf("failed converting APIResourceSchema %s|%s version %s to internal version: %w", logicalcluster.From(s), s.Name, version.Name, err)
+		}
+		validator, _, err := apiservervalidation.NewSchemaValidator(&apiextensionsinternal.CustomResourceValidation{OpenAPIV3Schema: internalSchema})
+		if err != nil {
+			return nil, err
+		}
+		return validator, nil
+	}
+
+	return nil, fmt.Errorf("APIResourceSchema %s|%s has no storage version", logicalcluster.From(s), s.Name)
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemacompatibility
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apibinding"
+)
+
+func TestReconcile(t *testing.T) {
+	widgetsSchema := func(name, uid, openAPISchema string) *apisv1alpha1.APIResourceSchema {
+		return &apisv1alpha1.APIResourceSchema{
+			ObjectMeta: metav1.ObjectMeta{Name: name, UID: types.UID(uid), ClusterName: "org:provider"},
+			Spec: apisv1alpha1.APIResourceSchemaSpec{
+				Group: "example.io",
+				Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets"},
+				Versions: []apisv1alpha1.APIResourceVersion{{
+					Name:    "v1",
+					Served:  true,
+					Storage: true,
+					Schema:  runtime.RawExtension{Raw: []byte(openAPISchema)},
+				}},
+			},
+		}
+	}
+	today := widgetsSchema("today.widgets.example.io", "uid-today", `{"type":"object","properties":{"spec":{"type":"object","properties":{"size":{"type":"string"}}}}}`)
+	tomorrow := widgetsSchema("tomorrow.widgets.example.io", "uid-tomorrow", `{"type":"object","properties":{"spec":{"type":"object","required":["size"],"properties":{"size":{"type":"string","enum":["S","M","L"]}}}}}`)
+	schemas := map[string]*apisv1alpha1.APIResourceSchema{today.Name: today, tomorrow.Name: tomorrow}
+
+	binding := func(report *apisv1alpha1.SchemaCompatibilityReport) *apisv1alpha1.APIBinding {
+		return &apisv1alpha1.APIBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "widgets", ClusterName: "org:consumer"},
+			Spec: apisv1alpha1.APIBindingSpec{
+				Reference: apisv1alpha1.ExportReference{
+					Workspace: &apisv1alpha1.WorkspaceExportReference{WorkspaceName: "provider", ExportName: "widgets"},
+				},
+			},
+			Status: apisv1alpha1.APIBindingStatus{
+				Phase: apisv1alpha1.APIBindingPhaseBound,
+				BoundResources: []apisv1alpha1.BoundAPIResource{{
+					Group:    "example.io",
+					Resource: "widgets",
+					Schema:   apisv1alpha1.BoundAPIResourceSchema{Name: today.Name, UID: string(today.UID)},
+				}},
+				SchemaCompatibility: report,
+			},
+		}
+	}
+	widget := func(name string, spec map[string]interface{}) unstructured.Unstructured {
+		return unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "example.io/v1",
+			"kind":       "Widget",
+			"metadata":   map[string]interface{}{"name": name, "namespace": "default"},
+			"spec":       spec,
+		}}
+	}
+
+	tests := map[string]struct {
+		binding     *apisv1alpha1.APIBinding
+		latest      string
+		objects     []unstructured.Unstructured
+		want        *apisv1alpha1.SchemaCompatibilityReport
+		wantRequeue bool
+	}{
+		"up-to-date binding has no report": {
+			binding: binding(&apisv1alpha1.SchemaCompatibilityReport{SchemaUIDs: []string{"old"}}),
+			latest:  today.Name,
+		},
+		"valid objects": {
+			binding: binding(nil),
+			latest:  tomorrow.Name,
+			objects: []unstructured.Unstructured{
+				widget("a", map[string]interface{}{"size": "S"}),
+				widget("b", map[string]interface{}{"size": "L"}),
+			},
+			want: &apisv1alpha1.SchemaCompatibilityReport{
+				SchemaUIDs:     []string{string(tomorrow.UID)},
+				CheckedObjects: 2,
+			},
+		},
+		"invalid objects": {
+			binding: binding(nil),
+			latest:  tomorrow.Name,
+			objects: []unstructured.Unstructured{
+				widget("a", map[string]interface{}{"size": "S"}),
+				widget("b", map[string]interface{}{}),
+				widget("c", map[string]interface{}{"size": "XXL"}),
+			},
+			want: &apisv1alpha1.SchemaCompatibilityReport{
+				SchemaUIDs:         []string{string(tomorrow.UID)},
+				CheckedObjects:     3,
+				InvalidObjectCount: 2,
+				InvalidObjects: []apisv1alpha1.IncompatibleObject{
+					{Group: "example.io", Resource: "widgets", Namespace: "default", Name: "b"},
+					{Group: "example.io", Resource: "widgets", Namespace: "default", Name: "c"},
+				},
+			},
+			wantRequeue: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			requeued := false
+			c := &controller{
+				enqueueAfter: func(_ *apisv1alpha1.APIBinding, duration time.Duration) {
+					require.Equal(t, recheckInterval, duration)
+					requeued = true
+				},
+				getAPIExport: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error) {
+					require.Equal(t, "org:provider", clusterName.String())
+					return &apisv1alpha1.APIExport{Spec: apisv1alpha1.APIExportSpec{LatestResourceSchemas: []string{tt.latest}}}, nil
+				},
+				getAPIResourceSchema: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error) {
+					if s, ok := schemas[name]; ok {
+						return s, nil
+					}
+					return nil, errors.NewNotFound(apisv1alpha1.Resource("apiresourceschemas"), name)
+				},
+				getCRD: func(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error) {
+					require.Equal(t, apibinding.ShadowWorkspaceName, clusterName)
+					require.Equal(t, string(today.UID), name)
+					return &apiextensionsv1.CustomResourceDefinition{Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+						Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1", Storage: true}},
+					}}, nil
+				},
+				listObjects: func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, continueToken string) (*unstructured.UnstructuredList, error) {
+					require.Equal(t, "org:consumer", clusterName.String())
+					require.Equal(t, schema.GroupVersionResource{Group: "example.io", Version: "v1", Resource: "widgets"}, gvr)
+					return &unstructured.UnstructuredList{Items: tt.objects}, nil
+				},
+			}
+
+			err := c.reconcile(context.Background(), tt.binding)
+			require.NoError(t, err)
+
+			got := tt.binding.Status.SchemaCompatibility
+			if got != nil {
+				for i := range got.InvalidObjects {
+					require.Len(t, got.InvalidObjects[i].Errors, 1)
+					require.Contains(t, got.InvalidObjects[i].Errors[0], "spec.size")
+					got.InvalidObjects[i].Errors = nil
+				}
+			}
+			require.Equal(t, tt.want, got)
+			require.Equal(t, tt.wantRequeue, requeued)
+		})
+	}
+}
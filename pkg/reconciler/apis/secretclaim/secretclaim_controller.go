@@ -0,0 +1,351 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretclaim
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/kcp-dev/logicalcluster"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clusters"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/authorization/delegated"
+	kcpclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
+	apisinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/apis/v1alpha1"
+	apislisters "github.com/kcp-dev/kcp/pkg/client/listers/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/reconciler/latency"
+)
+
+const (
+	controllerName = "kcp-secretclaim"
+
+	// resyncPeriod is how often the creator of a SecretClaim is checked to still have access
+	// to the claimed SharedSecret. SecretClaims are not informed about changes of RBAC.
+	resyncPeriod = time.Minute
+)
+
+// NewController returns a new controller that copies the secrets of claimed SharedSecrets
+// into the namespaces of their SecretClaims, and keeps the copies up-to-date.
+func NewController(
+	kubeClusterClient kubernetes.ClusterInterface,
+	kcpClusterClient kcpclient.ClusterInterface,
+	secretClaimInformer apisinformers.SecretClaimInformer,
+	sharedSecretInformer apisinformers.SharedSecretInformer,
+	secretInformer coreinformers.SecretInformer,
+) (*controller, error) {
+	queue := latency.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+
+	c := &controller{
+		queue: queue,
+		enqueueAfter: func(claim *apisv1alpha1.SecretClaim, duration time.Duration) {
+			key, err := cache.MetaNamespaceKeyFunc(claim)
+			if err != nil {
+				runtime.HandleError(err)
+				return
+			}
+			queue.AddAfter(key, duration)
+		},
+		kcpClusterClient:    kcpClusterClient,
+		secretClaimLister:   secretClaimInformer.Lister(),
+		secretClaimIndexer:  secretClaimInformer.Informer().GetIndexer(),
+		sharedSecretIndexer: sharedSecretInformer.Informer().GetIndexer(),
+		secretLister:        secretInformer.Lister(),
+		now:                 time.Now,
+		createAuthorizer: func(clusterName logicalcluster.Name) (authorizer.Authorizer, error) {
+			return delegated.NewDelegatedAuthorizer(clusterName, kubeClusterClient)
+		},
+		getSharedSecret: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.SharedSecret, error) {
+			return sharedSecretInformer.Lister().Get(clusters.ToClusterAwareKey(clusterName, name))
+		},
+		getSecret: func(clusterName logicalcluster.Name, namespace, name string) (*corev1.Secret, error) {
+			return secretInformer.Lister().Secrets(namespace).Get(clusters.ToClusterAwareKey(clusterName, name))
+		},
+		createSecret: func(ctx context.Context, clusterName logicalcluster.Name, secret *corev1.Secret) error {
+			_, err := kubeClusterClient.Cluster(clusterName).CoreV1().Secrets(secret.Namespace).Create(ctx, secret, metav1.CreateOptions{})
+			return err
+		},
+		updateSecret: func(ctx context.Context, clusterName logicalcluster.Name, secret *corev1.Secret) error {
+			_, err := kubeClusterClient.Cluster(clusterName).CoreV1().Secrets(secret.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
+			return err
+		},
+		deleteSecret: func(ctx context.Context, clusterName logicalcluster.Name, namespace, name string) error {
+			return kubeClusterClient.Cluster(clusterName).CoreV1().Secrets(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+		},
+	}
+
+	if _, found := secretClaimInformer.Informer().GetIndexer().GetIndexers()[IndexSecretClaimsBySharedSecret]; !found {
+		if err := secretClaimInformer.Informer().AddIndexers(cache.Indexers{
+			IndexSecretClaimsBySharedSecret: IndexSecretClaimsBySharedSecretFunc,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	if err := sharedSecretInformer.Informer().AddIndexers(cache.Indexers{
+		indexSharedSecretsBySecret: indexSharedSecretsBySecretFunc,
+	}); err != nil {
+		return nil, err
+	}
+
+	secretClaimInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueueSecretClaim(obj) },
+		UpdateFunc: func(_, obj interface{}) { c.enqueueSecretClaim(obj) },
+		DeleteFunc: func(obj interface{}) { c.enqueueSecretClaim(obj) },
+	})
+
+	sharedSecretInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueueSharedSecret(obj) },
+		UpdateFunc: func(_, obj interface{}) { c.enqueueSharedSecret(obj) },
+		DeleteFunc: func(obj interface{}) { c.enqueueSharedSecret(obj) },
+	})
+
+	secretInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueueSecret(obj) },
+		UpdateFunc: func(_, obj interface{}) { c.enqueueSecret(obj) },
+		DeleteFunc: func(obj interface{}) { c.enqueueSecret(obj) },
+	})
+
+	return c, nil
+}
+
+// controller reconciles the copies of claimed SharedSecrets.
+type controller struct {
+	queue        workqueue.RateLimitingInterface
+	enqueueAfter func(claim *apisv1alpha1.SecretClaim, duration time.Duration)
+
+	kcpClusterClient    kcpclient.ClusterInterface
+	secretClaimLister   apislisters.SecretClaimLister
+	secretClaimIndexer  cache.Indexer
+	sharedSecretIndexer cache.Indexer
+	secretLister        corelisters.SecretLister
+
+	now func() time.Time
+
+	createAuthorizer func(clusterName logicalcluster.Name) (authorizer.Authorizer, error)
+	getSharedSecret  func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.SharedSecret, error)
+	getSecret        func(clusterName logicalcluster.Name, namespace, name string) (*corev1.Secret, error)
+	createSecret     func(ctx context.Context, clusterName logicalcluster.Name, secret *corev1.Secret) error
+	updateSecret     func(ctx context.Context, clusterName logicalcluster.Name, secret *corev1.Secret) error
+	deleteSecret     func(ctx context.Context, clusterName logicalcluster.Name, namespace, name string) error
+}
+
+func (c *controller) enqueueSecretClaim(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	klog.V(2).Infof("Queueing SecretClaim %q", key)
+	c.queue.Add(key)
+}
+
+// enqueueSharedSecret enqueues all SecretClaims of the SharedSecret.
+func (c *controller) enqueueSharedSecret(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	sharedSecret, ok := obj.(*apisv1alpha1.SharedSecret)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("obj is supposed to be a SharedSecret, but is %T", obj))
+		return
+	}
+
+	c.enqueueClaimsOf(clusters.ToClusterAwareKey(logicalcluster.From(sharedSecret), sharedSecret.Name))
+}
+
+func (c *controller) enqueueClaimsOf(sharedSecretKey string) {
+	claims, err := c.secretClaimIndexer.ByIndex(IndexSecretClaimsBySharedSecret, sharedSecretKey)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	for _, obj := range claims {
+		key, err := cache.MetaNamespaceKeyFunc(obj)
+		if err != nil {
+			runtime.HandleError(err)
+			continue
+		}
+		klog.V(2).Infof("Queueing SecretClaim %q because of SharedSecret %s", key, sharedSecretKey)
+		c.queue.Add(key)
+	}
+}
+
+// enqueueSecret enqueues the SecretClaims of the SharedSecrets sharing the secret, and the
+// SecretClaim owning the secret if it is a copy.
+func (c *controller) enqueueSecret(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("obj is supposed to be a Secret, but is %T", obj))
+		return
+	}
+
+	if claimName, ok := secret.Labels[apisv1alpha1.SecretClaimLabel]; ok {
+		key := secretKey(logicalcluster.From(secret), secret.Namespace, claimName)
+		klog.V(2).Infof("Queueing SecretClaim %q because of its copy %s", key, secretKeyFor(secret))
+		c.queue.Add(key)
+	}
+
+	sharedSecrets, err := c.sharedSecretIndexer.ByIndex(indexSharedSecretsBySecret, secretKeyFor(secret))
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	for _, obj := range sharedSecrets {
+		sharedSecret := obj.(*apisv1alpha1.SharedSecret)
+		c.enqueueClaimsOf(clusters.ToClusterAwareKey(logicalcluster.From(sharedSecret), sharedSecret.Name))
+	}
+}
+
+// Start starts the controller, which stops when ctx.Done() is closed.
+func (c *controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	klog.Infof("Starting %s controller", controllerName)
+	defer klog.Infof("Shutting down %s controller", controllerName)
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+
+	<-ctx.Done()
+}
+
+func (c *controller) startWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	if err := c.process(ctx, key); err != nil {
+		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", controllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *controller) process(ctx context.Context, key string) error {
+	namespace, clusterAwareName, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		runtime.HandleError(err)
+		return nil
+	}
+
+	obj, err := c.secretClaimLister.SecretClaims(namespace).Get(clusterAwareName)
+	if errors.IsNotFound(err) {
+		clusterName, name := clusters.SplitClusterAwareKey(clusterAwareName)
+		return c.deleteCopies(ctx, clusterName, namespace, name)
+	} else if err != nil {
+		return err
+	}
+	old := obj
+	obj = obj.DeepCopy()
+
+	if err := c.reconcile(ctx, obj); err != nil {
+		return err
+	}
+
+	// If the object being reconciled changed as a result, update it.
+	if !equality.Semantic.DeepEqual(old.Status, obj.Status) {
+		clusterName := logicalcluster.From(obj)
+
+		oldData, err := json.Marshal(apisv1alpha1.SecretClaim{
+			Status: old.Status,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to Marshal old data for SecretClaim %s|%s/%s: %w", clusterName, obj.Namespace, obj.Name, err)
+		}
+
+		newData, err := json.Marshal(apisv1alpha1.SecretClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				UID:             obj.UID,
+				ResourceVersion: obj.ResourceVersion,
+			}, // to ensure they appear in the patch as preconditions
+			Status: obj.Status,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to Marshal new data for SecretClaim %s|%s/%s: %w", clusterName, obj.Namespace, obj.Name, err)
+		}
+
+		patchBytes, err := jsonpatch.CreateMergePatch(oldData, newData)
+		if err != nil {
+			return fmt.Errorf("failed to create patch for SecretClaim %s|%s/%s: %w", clusterName, obj.Namespace, obj.Name, err)
+		}
+		if _, err := c.kcpClusterClient.Cluster(clusterName).ApisV1alpha1().SecretClaims(obj.Namespace).Patch(ctx, obj.Name, types.MergePatchType, patchBytes, metav1.PatchOptions{}, "status"); err != nil {
+			return err
+		}
+	}
+
+	// access to the SharedSecret can be revoked at any time, check it regularly.
+	c.enqueueAfter(obj, resyncPeriod)
+
+	return nil
+}
+
+// deleteCopies deletes the copies of a deleted SecretClaim.
+func (c *controller) deleteCopies(ctx context.Context, clusterName logicalcluster.Name, namespace, claimName string) error {
+	secrets, err := c.secretLister.Secrets(namespace).List(labels.SelectorFromSet(labels.Set{apisv1alpha1.SecretClaimLabel: claimName}))
+	if err != nil {
+		return err
+	}
+	for _, secret := range secrets {
+		if logicalcluster.From(secret) != clusterName {
+			continue
+		}
+		klog.Infof("Deleting secret %s|%s/%s of deleted SecretClaim %s", clusterName, namespace, secret.Name, claimName)
+		if err := c.deleteSecret(ctx, clusterName, namespace, secret.Name); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
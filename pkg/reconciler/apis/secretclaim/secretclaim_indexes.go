@@ -0,0 +1,77 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretclaim
+
+import (
+	"fmt"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/clusters"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+)
+
+const IndexSecretClaimsBySharedSecret = "secretClaimsBySharedSecret"
+
+// IndexSecretClaimsBySharedSecretFunc is an index function that maps a SecretClaim to the key
+// of the claimed SharedSecret.
+func IndexSecretClaimsBySharedSecretFunc(obj interface{}) ([]string, error) {
+	claim, ok := obj.(*apisv1alpha1.SecretClaim)
+	if !ok {
+		return []string{}, fmt.Errorf("obj is supposed to be a SecretClaim, but is %T", obj)
+	}
+
+	sharedSecretClusterName, ok := SharedSecretClusterName(claim)
+	if !ok {
+		return []string{}, fmt.Errorf("a SecretClaim in %s cannot reference a workspace", logicalcluster.From(claim))
+	}
+	return []string{clusters.ToClusterAwareKey(sharedSecretClusterName, claim.Spec.Reference.SharedSecretName)}, nil
+}
+
+// SharedSecretClusterName returns the logical cluster of the SharedSecret claimed by the
+// SecretClaim, and false if the SecretClaim is not in a workspace of an organization.
+func SharedSecretClusterName(claim *apisv1alpha1.SecretClaim) (logicalcluster.Name, bool) {
+	parent, hasParent := logicalcluster.From(claim).Parent()
+	if !hasParent {
+		return logicalcluster.Name{}, false
+	}
+	return parent.Join(claim.Spec.Reference.WorkspaceName), true
+}
+
+const indexSharedSecretsBySecret = "sharedSecretsBySecret"
+
+// indexSharedSecretsBySecretFunc is an index function that maps a SharedSecret to the key of
+// the shared secret.
+func indexSharedSecretsBySecretFunc(obj interface{}) ([]string, error) {
+	sharedSecret, ok := obj.(*apisv1alpha1.SharedSecret)
+	if !ok {
+		return []string{}, fmt.Errorf("obj is supposed to be a SharedSecret, but is %T", obj)
+	}
+
+	return []string{secretKey(logicalcluster.From(sharedSecret), sharedSecret.Spec.SecretRef.Namespace, sharedSecret.Spec.SecretRef.Name)}, nil
+}
+
+func secretKey(clusterName logicalcluster.Name, namespace, name string) string {
+	return namespace + "/" + clusters.ToClusterAwareKey(clusterName, name)
+}
+
+// secretKeyFor returns the key of the secret as used by indexSharedSecretsBySecretFunc.
+func secretKeyFor(secret *corev1.Secret) string {
+	return secretKey(logicalcluster.From(secret), secret.Namespace, secret.Name)
+}
@@ -0,0 +1,241 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretclaim
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/client-go/tools/clusters"
+	"k8s.io/klog/v2"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/authorization/delegated"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/third_party/conditions/util/conditions"
+)
+
+func (c *controller) reconcile(ctx context.Context, claim *apisv1alpha1.SecretClaim) error {
+	clusterName := logicalcluster.From(claim)
+
+	sharedSecretClusterName, ok := SharedSecretClusterName(claim)
+	if !ok {
+		conditions.MarkFalse(
+			claim,
+			apisv1alpha1.SecretSynced,
+			apisv1alpha1.InternalErrorReason,
+			conditionsv1alpha1.ConditionSeverityError,
+			"%s is not a workspace of an organization",
+			clusterName,
+		)
+		return nil
+	}
+
+	// kcp copies the secret with its own privileges. Only copy it as long as the creator of
+	// the claim could claim it, and remove the copy when that access is revoked.
+	if allowed, reason, err := c.canClaim(ctx, claim, sharedSecretClusterName); err != nil {
+		return err
+	} else if !allowed {
+		klog.V(2).Infof("Not copying SharedSecret %s|%s for SecretClaim %s|%s/%s: %s", sharedSecretClusterName, claim.Spec.Reference.SharedSecretName, clusterName, claim.Namespace, claim.Name, reason)
+		if err := c.deleteCopy(ctx, claim); err != nil {
+			return err
+		}
+		conditions.MarkFalse(
+			claim,
+			apisv1alpha1.SecretSynced,
+			apisv1alpha1.ClaimForbiddenReason,
+			conditionsv1alpha1.ConditionSeverityError,
+			"The creator of the SecretClaim may not claim SharedSecret %s|%s: %s",
+			sharedSecretClusterName,
+			claim.Spec.Reference.SharedSecretName,
+			reason,
+		)
+		return nil
+	}
+
+	sharedSecret, err := c.getSharedSecret(sharedSecretClusterName, claim.Spec.Reference.SharedSecretName)
+	if errors.IsNotFound(err) {
+		conditions.MarkFalse(
+			claim,
+			apisv1alpha1.SecretSynced,
+			apisv1alpha1.SharedSecretNotFoundReason,
+			conditionsv1alpha1.ConditionSeverityError,
+			"SharedSecret %s|%s not found",
+			sharedSecretClusterName,
+			claim.Spec.Reference.SharedSecretName,
+		)
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	source, err := c.getSecret(sharedSecretClusterName, sharedSecret.Spec.SecretRef.Namespace, sharedSecret.Spec.SecretRef.Name)
+	if errors.IsNotFound(err) {
+		conditions.MarkFalse(
+			claim,
+			apisv1alpha1.SecretSynced,
+			apisv1alpha1.SecretNotFoundReason,
+			conditionsv1alpha1.ConditionSeverityError,
+			"Secret %s|%s/%s of SharedSecret %s not found",
+			sharedSecretClusterName,
+			sharedSecret.Spec.SecretRef.Namespace,
+			sharedSecret.Spec.SecretRef.Name,
+			sharedSecret.Name,
+		)
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	desired := copyOf(claim, sharedSecret, source)
+	existing, err := c.getSecret(clusterName, claim.Namespace, desired.Name)
+	switch {
+	case errors.IsNotFound(err):
+		klog.Infof("Creating secret %s|%s/%s for SecretClaim %s from SharedSecret %s|%s", clusterName, desired.Namespace, desired.Name, claim.Name, sharedSecretClusterName, sharedSecret.Name)
+		if err := c.createSecret(ctx, clusterName, desired); err != nil {
+			return err
+		}
+		claim.Status.LastSyncTime = &metav1.Time{Time: c.now()}
+	case err != nil:
+		return err
+	case existing.Labels[apisv1alpha1.SecretClaimLabel] != claim.Name:
+		conditions.MarkFalse(
+			claim,
+			apisv1alpha1.SecretSynced,
+			apisv1alpha1.SecretConflictReason,
+			conditionsv1alpha1.ConditionSeverityError,
+			"Secret %s already exists and does not belong to the SecretClaim",
+			desired.Name,
+		)
+		return nil
+	case existing.Type != desired.Type:
+		// the type of secrets is immutable
+		klog.Infof("Recreating secret %s|%s/%s for SecretClaim %s because the type of the shared secret changed", clusterName, desired.Namespace, desired.Name, claim.Name)
+		if err := c.deleteSecret(ctx, clusterName, existing.Namespace, existing.Name); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		if err := c.createSecret(ctx, clusterName, desired); err != nil {
+			return err
+		}
+		claim.Status.LastSyncTime = &metav1.Time{Time: c.now()}
+	case !equality.Semantic.DeepEqual(existing.Data, desired.Data) || existing.Annotations[apisv1alpha1.SharedSecretAnnotation] != desired.Annotations[apisv1alpha1.SharedSecretAnnotation]:
+		klog.Infof("Updating secret %s|%s/%s for SecretClaim %s from SharedSecret %s|%s", clusterName, desired.Namespace, desired.Name, claim.Name, sharedSecretClusterName, sharedSecret.Name)
+		updated := existing.DeepCopy()
+		updated.Data = desired.Data
+		if updated.Annotations == nil {
+			updated.Annotations = map[string]string{}
+		}
+		updated.Annotations[apisv1alpha1.SharedSecretAnnotation] = desired.Annotations[apisv1alpha1.SharedSecretAnnotation]
+		if err := c.updateSecret(ctx, clusterName, updated); err != nil {
+			return err
+		}
+		claim.Status.LastSyncTime = &metav1.Time{Time: c.now()}
+	}
+
+	claim.Status.SourceResourceVersion = source.ResourceVersion
+	conditions.MarkTrue(claim, apisv1alpha1.SecretSynced)
+
+	return nil
+}
+
+// canClaim returns whether the creator of the SecretClaim has the verb `claim` on the
+// SharedSecret, and the reason if not.
+func (c *controller) canClaim(ctx context.Context, claim *apisv1alpha1.SecretClaim, sharedSecretClusterName logicalcluster.Name) (bool, string, error) {
+	creator, err := delegated.DecodeUser(claim.Annotations[apisv1alpha1.SecretClaimCreatorAnnotation])
+	if err != nil {
+		return false, fmt.Sprintf("invalid %s annotation: %v", apisv1alpha1.SecretClaimCreatorAnnotation, err), nil
+	}
+	authz, err := c.createAuthorizer(sharedSecretClusterName)
+	if err != nil {
+		return false, "", err
+	}
+
+	claimAttr := authorizer.AttributesRecord{
+		User:            creator,
+		Verb:            "claim",
+		APIGroup:        apisv1alpha1.SchemeGroupVersion.Group,
+		APIVersion:      apisv1alpha1.SchemeGroupVersion.Version,
+		Resource:        "sharedsecrets",
+		Name:            claim.Spec.Reference.SharedSecretName,
+		ResourceRequest: true,
+	}
+	decision, _, err := authz.Authorize(ctx, claimAttr)
+	if err != nil {
+		return false, "", fmt.Errorf("unable to determine access to sharedsecrets: %w", err)
+	}
+	if decision != authorizer.DecisionAllow {
+		return false, fmt.Sprintf("missing verb='claim' permission on sharedsecrets for user %q", creator.GetName()), nil
+	}
+	return true, "", nil
+}
+
+// deleteCopy deletes the copy of the shared secret of the SecretClaim, if it exists.
+func (c *controller) deleteCopy(ctx context.Context, claim *apisv1alpha1.SecretClaim) error {
+	clusterName := logicalcluster.From(claim)
+	existing, err := c.getSecret(clusterName, claim.Namespace, copyName(claim))
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if existing.Labels[apisv1alpha1.SecretClaimLabel] != claim.Name {
+		return nil
+	}
+	klog.Infof("Deleting secret %s|%s/%s of SecretClaim %s without access to its SharedSecret", clusterName, existing.Namespace, existing.Name, claim.Name)
+	if err := c.deleteSecret(ctx, clusterName, existing.Namespace, existing.Name); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// copyName returns the name of the copy of the shared secret for the SecretClaim.
+func copyName(claim *apisv1alpha1.SecretClaim) string {
+	if claim.Spec.SecretName != "" {
+		return claim.Spec.SecretName
+	}
+	return claim.Name
+}
+
+// copyOf returns the copy of the shared secret for the SecretClaim.
+func copyOf(claim *apisv1alpha1.SecretClaim, sharedSecret *apisv1alpha1.SharedSecret, source *corev1.Secret) *corev1.Secret {
+	data := make(map[string][]byte, len(source.Data))
+	for k, v := range source.Data {
+		data[k] = append([]byte(nil), v...)
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      copyName(claim),
+			Namespace: claim.Namespace,
+			Labels: map[string]string{
+				apisv1alpha1.SecretClaimLabel: claim.Name,
+			},
+			Annotations: map[string]string{
+				apisv1alpha1.SharedSecretAnnotation: clusters.ToClusterAwareKey(logicalcluster.From(sharedSecret), sharedSecret.Name),
+			},
+		},
+		Type: source.Type,
+		Data: data,
+	}
+}
@@ -0,0 +1,235 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretclaim
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/third_party/conditions/util/conditions"
+)
+
+func TestReconcile(t *testing.T) {
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	claim := &apisv1alpha1.SecretClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "registry",
+			Namespace:   "default",
+			ClusterName: "root:org:consumer",
+			Annotations: map[string]string{apisv1alpha1.SecretClaimCreatorAnnotation: `{"username":"alice"}`},
+		},
+		Spec: apisv1alpha1.SecretClaimSpec{
+			Reference: apisv1alpha1.SharedSecretReference{WorkspaceName: "provider", SharedSecretName: "registry"},
+		},
+	}
+	sharedSecret := &apisv1alpha1.SharedSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "registry", ClusterName: "root:org:provider"},
+		Spec: apisv1alpha1.SharedSecretSpec{
+			SecretRef: apisv1alpha1.SecretReference{Namespace: "credentials", Name: "pull-secret"},
+		},
+	}
+	source := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "pull-secret", Namespace: "credentials", ClusterName: "root:org:provider", ResourceVersion: "42"},
+		Type:       corev1.SecretTypeDockerConfigJson,
+		Data:       map[string][]byte{corev1.DockerConfigJsonKey: []byte(`{"auths":{}}`)},
+	}
+	copied := func(data string, typ corev1.SecretType, owner string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "registry",
+				Namespace:   "default",
+				ClusterName: "root:org:consumer",
+				Labels:      map[string]string{apisv1alpha1.SecretClaimLabel: owner},
+				Annotations: map[string]string{apisv1alpha1.SharedSecretAnnotation: "root:org:provider|registry"},
+			},
+			Type: typ,
+			Data: map[string][]byte{corev1.DockerConfigJsonKey: []byte(data)},
+		}
+	}
+
+	tests := map[string]struct {
+		noCreator    bool
+		forbidden    bool
+		sharedSecret *apisv1alpha1.SharedSecret
+		source       *corev1.Secret
+		existing     *corev1.Secret
+		wantSynced   bool
+		wantReason   string
+		wantCreated  bool
+		wantUpdated  bool
+		wantDeleted  bool
+	}{
+		"copy created": {
+			sharedSecret: sharedSecret,
+			source:       source,
+			wantSynced:   true,
+			wantCreated:  true,
+		},
+		"copy up-to-date": {
+			sharedSecret: sharedSecret,
+			source:       source,
+			existing:     copied(`{"auths":{}}`, corev1.SecretTypeDockerConfigJson, "registry"),
+			wantSynced:   true,
+		},
+		"rotated secret updates copy": {
+			sharedSecret: sharedSecret,
+			source:       source,
+			existing:     copied(`{"auths":{"old":{}}}`, corev1.SecretTypeDockerConfigJson, "registry"),
+			wantSynced:   true,
+			wantUpdated:  true,
+		},
+		"changed type recreates copy": {
+			sharedSecret: sharedSecret,
+			source:       source,
+			existing:     copied(`{"auths":{}}`, corev1.SecretTypeOpaque, "registry"),
+			wantSynced:   true,
+			wantDeleted:  true,
+			wantCreated:  true,
+		},
+		"foreign secret": {
+			sharedSecret: sharedSecret,
+			source:       source,
+			existing:     copied(`{"auths":{}}`, corev1.SecretTypeDockerConfigJson, "other"),
+			wantReason:   apisv1alpha1.SecretConflictReason,
+		},
+		"shared secret not found": {
+			wantReason: apisv1alpha1.SharedSecretNotFoundReason,
+		},
+		"secret not found": {
+			sharedSecret: sharedSecret,
+			wantReason:   apisv1alpha1.SecretNotFoundReason,
+		},
+		"revoked access deletes copy": {
+			forbidden:    true,
+			sharedSecret: sharedSecret,
+			source:       source,
+			existing:     copied(`{"auths":{}}`, corev1.SecretTypeDockerConfigJson, "registry"),
+			wantReason:   apisv1alpha1.ClaimForbiddenReason,
+			wantDeleted:  true,
+		},
+		"revoked access keeps foreign secret": {
+			forbidden:    true,
+			sharedSecret: sharedSecret,
+			source:       source,
+			existing:     copied(`{"auths":{}}`, corev1.SecretTypeDockerConfigJson, "other"),
+			wantReason:   apisv1alpha1.ClaimForbiddenReason,
+		},
+		"unknown creator does not create copy": {
+			noCreator:    true,
+			sharedSecret: sharedSecret,
+			source:       source,
+			wantReason:   apisv1alpha1.ClaimForbiddenReason,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var created, updated *corev1.Secret
+			var deleted bool
+			c := &controller{
+				now: func() time.Time { return now },
+				createAuthorizer: func(clusterName logicalcluster.Name) (authorizer.Authorizer, error) {
+					require.Equal(t, "root:org:provider", clusterName.String())
+					return authorizer.AuthorizerFunc(func(ctx context.Context, attr authorizer.Attributes) (authorizer.Decision, string, error) {
+						require.Equal(t, "alice", attr.GetUser().GetName())
+						require.Equal(t, "claim", attr.GetVerb())
+						require.Equal(t, "registry", attr.GetName())
+						if tt.forbidden {
+							return authorizer.DecisionNoOpinion, "", nil
+						}
+						return authorizer.DecisionAllow, "", nil
+					}), nil
+				},
+				getSharedSecret: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.SharedSecret, error) {
+					require.Equal(t, "root:org:provider", clusterName.String())
+					if tt.sharedSecret == nil {
+						return nil, errors.NewNotFound(apisv1alpha1.Resource("sharedsecrets"), name)
+					}
+					return tt.sharedSecret, nil
+				},
+				getSecret: func(clusterName logicalcluster.Name, namespace, name string) (*corev1.Secret, error) {
+					switch clusterName.String() {
+					case "root:org:provider":
+						if tt.source != nil {
+							return tt.source, nil
+						}
+					case "root:org:consumer":
+						if tt.existing != nil {
+							return tt.existing, nil
+						}
+					}
+					return nil, errors.NewNotFound(corev1.Resource("secrets"), name)
+				},
+				createSecret: func(ctx context.Context, clusterName logicalcluster.Name, secret *corev1.Secret) error {
+					require.Equal(t, "root:org:consumer", clusterName.String())
+					created = secret
+					return nil
+				},
+				updateSecret: func(ctx context.Context, clusterName logicalcluster.Name, secret *corev1.Secret) error {
+					require.Equal(t, "root:org:consumer", clusterName.String())
+					updated = secret
+					return nil
+				},
+				deleteSecret: func(ctx context.Context, clusterName logicalcluster.Name, namespace, name string) error {
+					require.Equal(t, "root:org:consumer", clusterName.String())
+					deleted = true
+					return nil
+				},
+			}
+
+			obj := claim.DeepCopy()
+			if tt.noCreator {
+				obj.Annotations = nil
+			}
+			err := c.reconcile(context.Background(), obj)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.wantSynced, conditions.IsTrue(obj, apisv1alpha1.SecretSynced))
+			if !tt.wantSynced {
+				require.Equal(t, tt.wantReason, conditions.GetReason(obj, apisv1alpha1.SecretSynced))
+			} else {
+				require.Equal(t, "42", obj.Status.SourceResourceVersion)
+			}
+			require.Equal(t, tt.wantCreated, created != nil, "created")
+			require.Equal(t, tt.wantUpdated, updated != nil, "updated")
+			require.Equal(t, tt.wantDeleted, deleted, "deleted")
+
+			want := copied(`{"auths":{}}`, corev1.SecretTypeDockerConfigJson, "registry")
+			want.ClusterName = ""
+			if created != nil {
+				require.Equal(t, want, created)
+			}
+			if updated != nil {
+				require.Equal(t, want.Data, updated.Data)
+			}
+			if created != nil || updated != nil {
+				require.Equal(t, &metav1.Time{Time: now}, obj.Status.LastSyncTime)
+			} else {
+				require.Nil(t, obj.Status.LastSyncTime)
+			}
+		})
+	}
+}
@@ -0,0 +1,262 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ttlpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/authorization/delegated"
+	kcpclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
+	apisinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/apis/v1alpha1"
+	apislisters "github.com/kcp-dev/kcp/pkg/client/listers/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/reconciler/latency"
+)
+
+const (
+	controllerName = "kcp-ttlpolicy"
+
+	// resyncPeriod is the longest time between two sweeps of a policy, such that objects
+	// created or completed after a sweep are found.
+	resyncPeriod = 5 * time.Minute
+)
+
+type clusterDiscovery interface {
+	WithCluster(name logicalcluster.Name) discovery.DiscoveryInterface
+}
+
+// NewController returns a new controller that deletes the objects selected by TTLPolicies
+// after their time to live. Every policy is swept when it changes, when the next selected
+// object expires, and at least every resyncPeriod. Objects are only deleted while the creator
+// of the policy can list and delete them.
+func NewController(
+	kubeClusterClient kubernetes.ClusterInterface,
+	kcpClusterClient kcpclient.ClusterInterface,
+	dynamicClusterClient dynamic.ClusterInterface,
+	clusterDiscoveryClient clusterDiscovery,
+	ttlPolicyInformer apisinformers.TTLPolicyInformer,
+) (*controller, error) {
+	queue := latency.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+
+	c := &controller{
+		queue:            queue,
+		kcpClusterClient: kcpClusterClient,
+		ttlPolicyLister:  ttlPolicyInformer.Lister(),
+		now:              time.Now,
+		createAuthorizer: func(clusterName logicalcluster.Name) (authorizer.Authorizer, error) {
+			return delegated.NewDelegatedAuthorizer(clusterName, kubeClusterClient)
+		},
+		resolveResource: func(clusterName logicalcluster.Name, gr schema.GroupResource) (schema.GroupVersionResource, error) {
+			return resolveResource(clusterDiscoveryClient.WithCluster(clusterName), gr)
+		},
+		listObjects: func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, selector labels.Selector) ([]unstructured.Unstructured, error) {
+			list, err := dynamicClusterClient.Cluster(clusterName).Resource(gvr).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		},
+		deleteObject: func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, namespace, name string, uid types.UID) error {
+			background := metav1.DeletePropagationBackground
+			return dynamicClusterClient.Cluster(clusterName).Resource(gvr).Namespace(namespace).Delete(ctx, name, metav1.DeleteOptions{
+				// never delete an object that was recreated under the same name.
+				Preconditions:     &metav1.Preconditions{UID: &uid},
+				PropagationPolicy: &background,
+			})
+		},
+	}
+
+	ttlPolicyInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) { c.enqueue(obj) },
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			// status updates of sweeps must not trigger another sweep.
+			oldPolicy, ok := oldObj.(*apisv1alpha1.TTLPolicy)
+			if !ok {
+				return
+			}
+			newPolicy, ok := newObj.(*apisv1alpha1.TTLPolicy)
+			if !ok || oldPolicy.Generation == newPolicy.Generation {
+				return
+			}
+			c.enqueue(newObj)
+		},
+	})
+
+	return c, nil
+}
+
+// controller reconciles TTLPolicies, deleting the expired objects they select.
+type controller struct {
+	queue workqueue.RateLimitingInterface
+
+	kcpClusterClient kcpclient.ClusterInterface
+	ttlPolicyLister  apislisters.TTLPolicyLister
+
+	now              func() time.Time
+	createAuthorizer func(clusterName logicalcluster.Name) (authorizer.Authorizer, error)
+	resolveResource  func(clusterName logicalcluster.Name, gr schema.GroupResource) (schema.GroupVersionResource, error)
+	listObjects      func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, selector labels.Selector) ([]unstructured.Unstructured, error)
+	deleteObject     func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, namespace, name string, uid types.UID) error
+}
+
+func (c *controller) enqueue(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	klog.V(2).Infof("Queueing TTLPolicy %q", key)
+	c.queue.Add(key)
+}
+
+// resolveResource returns the preferred version of the resource served in the workspace of
+// the discovery client, or a NotFound error if it is not served.
+func resolveResource(discoveryClient discovery.DiscoveryInterface, gr schema.GroupResource) (schema.GroupVersionResource, error) {
+	groups, err := discoveryClient.ServerGroups()
+	if err != nil {
+		return schema.GroupVersionResource{}, err
+	}
+	for _, group := range groups.Groups {
+		if group.Name != gr.Group {
+			continue
+		}
+		resources, err := discoveryClient.ServerResourcesForGroupVersion(group.PreferredVersion.GroupVersion)
+		if err != nil {
+			return schema.GroupVersionResource{}, err
+		}
+		for _, r := range resources.APIResources {
+			if r.Name == gr.Resource {
+				return gr.WithVersion(group.PreferredVersion.Version), nil
+			}
+		}
+	}
+	return schema.GroupVersionResource{}, errors.NewNotFound(gr, "")
+}
+
+// Start starts the controller, which stops when ctx.Done() is closed.
+func (c *controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	klog.Infof("Starting %s controller", controllerName)
+	defer klog.Infof("Shutting down %s controller", controllerName)
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+
+	<-ctx.Done()
+}
+
+func (c *controller) startWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	requeueAfter, err := c.process(ctx, key)
+	if err != nil {
+		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", controllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	if requeueAfter > 0 {
+		c.queue.AddAfter(key, requeueAfter)
+	}
+	return true
+}
+
+func (c *controller) process(ctx context.Context, key string) (time.Duration, error) {
+	obj, err := c.ttlPolicyLister.Get(key)
+	if errors.IsNotFound(err) {
+		return 0, nil // deleted policies are not swept anymore
+	} else if err != nil {
+		return 0, err
+	}
+	old := obj
+	obj = obj.DeepCopy()
+
+	requeueAfter, reconcileErr := c.reconcile(ctx, obj)
+
+	// If the object being reconciled changed as a result, update it.
+	if !equality.Semantic.DeepEqual(old.Status, obj.Status) {
+		clusterName := logicalcluster.From(obj)
+		oldData, err := json.Marshal(apisv1alpha1.TTLPolicy{
+			Status: old.Status,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to Marshal old data for TTLPolicy %s|%s: %w", clusterName, obj.Name, err)
+		}
+
+		newData, err := json.Marshal(apisv1alpha1.TTLPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				UID:             obj.UID,
+				ResourceVersion: obj.ResourceVersion,
+			}, // to ensure they appear in the patch as preconditions
+			Status: obj.Status,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to Marshal new data for TTLPolicy %s|%s: %w", clusterName, obj.Name, err)
+		}
+
+		patchBytes, err := jsonpatch.CreateMergePatch(oldData, newData)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create patch for TTLPolicy %s|%s: %w", clusterName, obj.Name, err)
+		}
+		if _, err := c.kcpClusterClient.Cluster(clusterName).ApisV1alpha1().TTLPolicies().Patch(ctx, obj.Name, types.MergePatchType, patchBytes, metav1.PatchOptions{}, "status"); err != nil {
+			return 0, err
+		}
+	}
+
+	return requeueAfter, reconcileErr
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ttlpolicy
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/klog/v2"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/authorization/delegated"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/third_party/conditions/util/conditions"
+)
+
+// defaultCompletionConditions are the condition types marking an object as completed if the
+// policy does not name any, as set by Jobs.
+var defaultCompletionConditions = []string{"Complete", "Failed"}
+
+// reconcile deletes the expired objects selected by the policy, and updates its status. It
+// returns when the policy has to be swept again.
+func (c *controller) reconcile(ctx context.Context, policy *apisv1alpha1.TTLPolicy) (time.Duration, error) {
+	clusterName := logicalcluster.From(policy)
+
+	selector := labels.Everything()
+	if policy.Spec.Selector != nil {
+		var err error
+		if selector, err = metav1.LabelSelectorAsSelector(policy.Spec.Selector); err != nil {
+			conditions.MarkFalse(
+				policy,
+				apisv1alpha1.TTLPolicyResourceResolved,
+				apisv1alpha1.InvalidSelectorReason,
+				conditionsv1alpha1.ConditionSeverityError,
+				"Invalid selector: %v",
+				err,
+			)
+			return 0, nil // a spec change will trigger another sweep
+		}
+	}
+
+	gr := schema.GroupResource{Group: policy.Spec.Group, Resource: policy.Spec.Resource}
+	gvr, err := c.resolveResource(clusterName, gr)
+	if errors.IsNotFound(err) {
+		conditions.MarkFalse(
+			policy,
+			apisv1alpha1.TTLPolicyResourceResolved,
+			apisv1alpha1.ResourceNotServedReason,
+			conditionsv1alpha1.ConditionSeverityWarning,
+			"Resource %s is not served in the workspace",
+			gr,
+		)
+		return resyncPeriod, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	// kcp deletes the objects with its own privileges. Only delete them while the creator
+	// could do that, as permissions may have been revoked since the policy was created.
+	if err := c.checkCreatorAccess(ctx, policy, gvr); err != nil {
+		conditions.MarkFalse(
+			policy,
+			apisv1alpha1.TTLPolicyResourceResolved,
+			apisv1alpha1.TTLPolicyForbiddenReason,
+			conditionsv1alpha1.ConditionSeverityError,
+			"Not deleting %s: %v",
+			gr,
+			err,
+		)
+		return resyncPeriod, nil
+	}
+	conditions.MarkTrue(policy, apisv1alpha1.TTLPolicyResourceResolved)
+
+	objs, err := c.listObjects(ctx, clusterName, gvr, selector)
+	if err != nil {
+		return 0, err
+	}
+
+	now := c.now()
+	requeueAfter := resyncPeriod
+	var errs []error
+	for i := range objs {
+		obj := &objs[i]
+		if obj.GetDeletionTimestamp() != nil {
+			continue
+		}
+		start, ok := lifetimeStart(policy, obj)
+		if !ok {
+			continue
+		}
+		if remaining := start.Add(policy.Spec.TTL.Duration).Sub(now); remaining > 0 {
+			if remaining < requeueAfter {
+				requeueAfter = remaining
+			}
+			continue
+		}
+
+		klog.V(2).Infof("Deleting expired %s %s|%s/%s of TTLPolicy %s", gr, clusterName, obj.GetNamespace(), obj.GetName(), policy.Name)
+		if err := c.deleteObject(ctx, clusterName, gvr, obj.GetNamespace(), obj.GetName(), obj.GetUID()); errors.IsNotFound(err) || errors.IsConflict(err) {
+			continue // deleted or recreated in the meantime
+		} else if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		policy.Status.DeletedObjects++
+	}
+
+	sweepTime := metav1.NewTime(now)
+	policy.Status.LastSweepTime = &sweepTime
+
+	return requeueAfter, utilerrors.NewAggregate(errs)
+}
+
+// checkCreatorAccess checks that the creator of the policy can list and delete the resource
+// in all namespaces of the workspace.
+func (c *controller) checkCreatorAccess(ctx context.Context, policy *apisv1alpha1.TTLPolicy, gvr schema.GroupVersionResource) error {
+	creator, err := delegated.DecodeUser(policy.Annotations[apisv1alpha1.TTLPolicyCreatorAnnotation])
+	if err != nil {
+		return fmt.Errorf("invalid %s annotation: %w", apisv1alpha1.TTLPolicyCreatorAnnotation, err)
+	}
+	authz, err := c.createAuthorizer(logicalcluster.From(policy))
+	if err != nil {
+		return err
+	}
+
+	for _, verb := range []string{"list", "delete"} {
+		attr := authorizer.AttributesRecord{
+			User:            creator,
+			Verb:            verb,
+			APIGroup:        gvr.Group,
+			APIVersion:      gvr.Version,
+			Resource:        gvr.Resource,
+			ResourceRequest: true,
+		}
+		if decision, _, err := authz.Authorize(ctx, attr); err != nil {
+			return fmt.Errorf("unable to determine access to %s: %w", gvr.GroupResource(), err)
+		} else if decision != authorizer.DecisionAllow {
+			return fmt.Errorf("missing verb=%q permission on %s for user %q", verb, gvr.GroupResource(), creator.GetName())
+		}
+	}
+	return nil
+}
+
+// lifetimeStart returns when the lifetime of the object started according to the policy, or
+// false if it has not started yet because the object is not completed.
+func lifetimeStart(policy *apisv1alpha1.TTLPolicy, obj *unstructured.Unstructured) (time.Time, bool) {
+	if policy.Spec.After != apisv1alpha1.TTLPolicyAfterCompletion {
+		return obj.GetCreationTimestamp().Time, true
+	}
+
+	completionTypes := sets.NewString(policy.Spec.CompletionConditions...)
+	if completionTypes.Len() == 0 {
+		completionTypes.Insert(defaultCompletionConditions...)
+	}
+
+	conds, _, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if err != nil {
+		return time.Time{}, false
+	}
+	var completed time.Time
+	found := false
+	for _, cond := range conds {
+		m, ok := cond.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		condType, _ := m["type"].(string)
+		status, _ := m["status"].(string)
+		if status != string(metav1.ConditionTrue) || !completionTypes.Has(condType) {
+			continue
+		}
+		transition, _ := m["lastTransitionTime"].(string)
+		t, err := time.Parse(time.RFC3339, transition)
+		if err != nil {
+			continue
+		}
+		if !found || t.Before(completed) {
+			completed, found = t, true
+		}
+	}
+	return completed, found
+}
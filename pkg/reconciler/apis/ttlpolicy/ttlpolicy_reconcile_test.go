@@ -0,0 +1,239 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ttlpolicy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/third_party/conditions/util/conditions"
+)
+
+var now = time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func newObject(name string, created time.Time, conds ...map[string]interface{}) unstructured.Unstructured {
+	obj := unstructured.Unstructured{Object: map[string]interface{}{}}
+	obj.SetNamespace("default")
+	obj.SetName(name)
+	obj.SetUID(types.UID(name + "-uid"))
+	obj.SetCreationTimestamp(metav1.NewTime(created))
+	if len(conds) > 0 {
+		items := make([]interface{}, 0, len(conds))
+		for _, c := range conds {
+			items = append(items, c)
+		}
+		obj.Object["status"] = map[string]interface{}{"conditions": items}
+	}
+	return obj
+}
+
+func condition(condType, status string, transition time.Time) map[string]interface{} {
+	return map[string]interface{}{"type": condType, "status": status, "lastTransitionTime": transition.Format(time.RFC3339)}
+}
+
+func TestReconcile(t *testing.T) {
+	terminating := newObject("terminating", now.Add(-2*time.Hour))
+	terminating.SetDeletionTimestamp(&metav1.Time{Time: now})
+
+	tests := map[string]struct {
+		spec             apisv1alpha1.TTLPolicySpec
+		notServed        bool
+		forbiddenVerbs   []string
+		noCreator        bool
+		objects          []unstructured.Unstructured
+		deleteErr        map[string]error
+		wantDeleted      []string
+		wantRequeueAfter time.Duration
+		wantReason       string
+		wantErr          bool
+	}{
+		"expired after creation": {
+			spec: apisv1alpha1.TTLPolicySpec{Group: "batch", Resource: "jobs", TTL: metav1.Duration{Duration: time.Hour}},
+			objects: []unstructured.Unstructured{
+				newObject("old", now.Add(-2*time.Hour)),
+				newObject("exact", now.Add(-time.Hour)),
+				newObject("young", now.Add(-50*time.Minute)),
+				terminating,
+			},
+			wantDeleted:      []string{"old", "exact"},
+			wantRequeueAfter: 10 * time.Minute,
+		},
+		"nothing expires before resync": {
+			spec:             apisv1alpha1.TTLPolicySpec{Group: "batch", Resource: "jobs", TTL: metav1.Duration{Duration: 24 * time.Hour}},
+			objects:          []unstructured.Unstructured{newObject("young", now.Add(-time.Hour))},
+			wantRequeueAfter: resyncPeriod,
+		},
+		"expired after default completion conditions": {
+			spec: apisv1alpha1.TTLPolicySpec{Group: "batch", Resource: "jobs", TTL: metav1.Duration{Duration: time.Hour}, After: apisv1alpha1.TTLPolicyAfterCompletion},
+			objects: []unstructured.Unstructured{
+				newObject("complete", now.Add(-48*time.Hour), condition("Complete", "True", now.Add(-2*time.Hour))),
+				newObject("failed", now.Add(-48*time.Hour), condition("Failed", "True", now.Add(-90*time.Minute))),
+				newObject("running", now.Add(-48*time.Hour), condition("Complete", "False", now.Add(-2*time.Hour))),
+				newObject("no-conditions", now.Add(-48*time.Hour)),
+				newObject("recently-complete", now.Add(-48*time.Hour), condition("Complete", "True", now.Add(-58*time.Minute))),
+			},
+			wantDeleted:      []string{"complete", "failed"},
+			wantRequeueAfter: 2 * time.Minute,
+		},
+		"expired after custom completion conditions": {
+			spec: apisv1alpha1.TTLPolicySpec{Resource: "claims", TTL: metav1.Duration{Duration: time.Hour}, After: apisv1alpha1.TTLPolicyAfterCompletion, CompletionConditions: []string{"Released"}},
+			objects: []unstructured.Unstructured{
+				newObject("released", now.Add(-48*time.Hour), condition("Released", "True", now.Add(-2*time.Hour))),
+				newObject("complete", now.Add(-48*time.Hour), condition("Complete", "True", now.Add(-2*time.Hour))),
+			},
+			wantDeleted:      []string{"released"},
+			wantRequeueAfter: resyncPeriod,
+		},
+		"objects deleted or recreated in the meantime": {
+			spec: apisv1alpha1.TTLPolicySpec{Group: "batch", Resource: "jobs", TTL: metav1.Duration{Duration: time.Hour}},
+			objects: []unstructured.Unstructured{
+				newObject("gone", now.Add(-2*time.Hour)),
+				newObject("recreated", now.Add(-2*time.Hour)),
+				newObject("old", now.Add(-2*time.Hour)),
+			},
+			deleteErr: map[string]error{
+				"gone":      errors.NewNotFound(schema.GroupResource{Group: "batch", Resource: "jobs"}, "gone"),
+				"recreated": errors.NewConflict(schema.GroupResource{Group: "batch", Resource: "jobs"}, "recreated", nil),
+			},
+			wantDeleted:      []string{"old"},
+			wantRequeueAfter: resyncPeriod,
+		},
+		"failed deletion": {
+			spec:             apisv1alpha1.TTLPolicySpec{Group: "batch", Resource: "jobs", TTL: metav1.Duration{Duration: time.Hour}},
+			objects:          []unstructured.Unstructured{newObject("old", now.Add(-2*time.Hour))},
+			deleteErr:        map[string]error{"old": errors.NewInternalError(nil)},
+			wantRequeueAfter: resyncPeriod,
+			wantErr:          true,
+		},
+		"resource not served": {
+			spec:             apisv1alpha1.TTLPolicySpec{Group: "example.com", Resource: "widgets", TTL: metav1.Duration{Duration: time.Hour}},
+			notServed:        true,
+			wantRequeueAfter: resyncPeriod,
+			wantReason:       apisv1alpha1.ResourceNotServedReason,
+		},
+		"creator may not delete the resource": {
+			spec:             apisv1alpha1.TTLPolicySpec{Group: "batch", Resource: "jobs", TTL: metav1.Duration{Duration: time.Hour}},
+			forbiddenVerbs:   []string{"delete"},
+			objects:          []unstructured.Unstructured{newObject("old", now.Add(-2*time.Hour))},
+			wantRequeueAfter: resyncPeriod,
+			wantReason:       apisv1alpha1.TTLPolicyForbiddenReason,
+		},
+		"creator may not list the resource": {
+			spec:             apisv1alpha1.TTLPolicySpec{Group: "batch", Resource: "jobs", TTL: metav1.Duration{Duration: time.Hour}},
+			forbiddenVerbs:   []string{"list"},
+			objects:          []unstructured.Unstructured{newObject("old", now.Add(-2*time.Hour))},
+			wantRequeueAfter: resyncPeriod,
+			wantReason:       apisv1alpha1.TTLPolicyForbiddenReason,
+		},
+		"no known creator": {
+			spec:             apisv1alpha1.TTLPolicySpec{Group: "batch", Resource: "jobs", TTL: metav1.Duration{Duration: time.Hour}},
+			noCreator:        true,
+			objects:          []unstructured.Unstructured{newObject("old", now.Add(-2*time.Hour))},
+			wantRequeueAfter: resyncPeriod,
+			wantReason:       apisv1alpha1.TTLPolicyForbiddenReason,
+		},
+		"invalid selector": {
+			spec: apisv1alpha1.TTLPolicySpec{Group: "batch", Resource: "jobs", TTL: metav1.Duration{Duration: time.Hour}, Selector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "temporary", Operator: "Bogus"}},
+			}},
+			wantReason: apisv1alpha1.InvalidSelectorReason,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var deleted []string
+			c := &controller{
+				now: func() time.Time { return now },
+				createAuthorizer: func(clusterName logicalcluster.Name) (authorizer.Authorizer, error) {
+					require.Equal(t, "root:org:ws", clusterName.String())
+					return authorizer.AuthorizerFunc(func(ctx context.Context, attr authorizer.Attributes) (authorizer.Decision, string, error) {
+						require.Equal(t, "alice", attr.GetUser().GetName())
+						require.Equal(t, tt.spec.Resource, attr.GetResource())
+						for _, verb := range tt.forbiddenVerbs {
+							if verb == attr.GetVerb() {
+								return authorizer.DecisionNoOpinion, "", nil
+							}
+						}
+						return authorizer.DecisionAllow, "", nil
+					}), nil
+				},
+				resolveResource: func(clusterName logicalcluster.Name, gr schema.GroupResource) (schema.GroupVersionResource, error) {
+					require.Equal(t, "root:org:ws", clusterName.String())
+					if tt.notServed {
+						return schema.GroupVersionResource{}, errors.NewNotFound(gr, "")
+					}
+					return gr.WithVersion("v1"), nil
+				},
+				listObjects: func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, selector labels.Selector) ([]unstructured.Unstructured, error) {
+					return tt.objects, nil
+				},
+				deleteObject: func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, namespace, name string, uid types.UID) error {
+					require.Equal(t, types.UID(name+"-uid"), uid)
+					if err := tt.deleteErr[name]; err != nil {
+						return err
+					}
+					deleted = append(deleted, name)
+					return nil
+				},
+			}
+			policy := &apisv1alpha1.TTLPolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "cleanup",
+					ClusterName: "root:org:ws",
+					Annotations: map[string]string{apisv1alpha1.TTLPolicyCreatorAnnotation: `{"username":"alice"}`},
+				},
+				Spec:   tt.spec,
+				Status: apisv1alpha1.TTLPolicyStatus{DeletedObjects: 3},
+			}
+			if tt.noCreator {
+				policy.Annotations = nil
+			}
+
+			requeueAfter, err := c.reconcile(context.Background(), policy)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantRequeueAfter, requeueAfter)
+			require.Equal(t, tt.wantDeleted, deleted)
+			require.Equal(t, int64(3+len(tt.wantDeleted)), policy.Status.DeletedObjects)
+
+			if tt.wantReason != "" {
+				require.True(t, conditions.IsFalse(policy, apisv1alpha1.TTLPolicyResourceResolved))
+				require.Equal(t, tt.wantReason, conditions.GetReason(policy, apisv1alpha1.TTLPolicyResourceResolved))
+				require.Nil(t, policy.Status.LastSweepTime)
+				return
+			}
+			require.True(t, conditions.IsTrue(policy, apisv1alpha1.TTLPolicyResourceResolved))
+			require.Equal(t, now, policy.Status.LastSweepTime.Time)
+		})
+	}
+}
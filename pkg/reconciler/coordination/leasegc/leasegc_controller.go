@@ -0,0 +1,187 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package leasegc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coordinationinformers "k8s.io/client-go/informers/coordination/v1"
+	"k8s.io/client-go/kubernetes"
+	coordinationlisters "k8s.io/client-go/listers/coordination/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	"github.com/kcp-dev/kcp/pkg/leaderelection"
+	"github.com/kcp-dev/kcp/pkg/reconciler/latency"
+)
+
+const controllerName = "kcp-lease-gc"
+
+// NewController returns a new controller that deletes the leases of logical cluster elections,
+// i.e. with the leaderelection.GarbageCollectLabel, that expired longer than the TTL ago. These
+// are left behind when all instances of a controller are gone.
+func NewController(
+	kubeClusterClient kubernetes.ClusterInterface,
+	leaseInformer coordinationinformers.LeaseInformer,
+	ttl time.Duration,
+) (*controller, error) {
+	queue := latency.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+
+	c := &controller{
+		queue:       queue,
+		ttl:         ttl,
+		now:         time.Now,
+		leaseLister: leaseInformer.Lister(),
+		deleteLease: func(ctx context.Context, lease *coordinationv1.Lease) error {
+			return kubeClusterClient.Cluster(logicalcluster.From(lease)).CoordinationV1().Leases(lease.Namespace).Delete(ctx, lease.Name, metav1.DeleteOptions{
+				// a lease renewed in the meantime is kept
+				Preconditions: &metav1.Preconditions{UID: &lease.UID, ResourceVersion: &lease.ResourceVersion},
+			})
+		},
+	}
+
+	leaseInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj interface{}) bool {
+			lease, ok := obj.(*coordinationv1.Lease)
+			return ok && lease.Labels[leaderelection.GarbageCollectLabel] == "true"
+		},
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { c.enqueue(obj) },
+			UpdateFunc: func(_, obj interface{}) { c.enqueue(obj) },
+		},
+	})
+
+	return c, nil
+}
+
+// controller deletes expired leases of logical cluster elections.
+type controller struct {
+	queue workqueue.RateLimitingInterface
+	ttl   time.Duration
+	now   func() time.Time
+
+	leaseLister coordinationlisters.LeaseLister
+	deleteLease func(ctx context.Context, lease *coordinationv1.Lease) error
+}
+
+func (c *controller) enqueue(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	klog.V(4).Infof("Queueing Lease %q", key)
+	c.queue.Add(key)
+}
+
+// Start starts the controller, which stops when ctx.Done() is closed.
+func (c *controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	klog.Infof("Starting %s controller", controllerName)
+	defer klog.Infof("Shutting down %s controller", controllerName)
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+
+	<-ctx.Done()
+}
+
+func (c *controller) startWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	if err := c.process(ctx, key); err != nil {
+		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", controllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *controller) process(ctx context.Context, key string) error {
+	namespace, clusterAwareName, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		runtime.HandleError(err)
+		return nil
+	}
+	lease, err := c.leaseLister.Leases(namespace).Get(clusterAwareName)
+	if errors.IsNotFound(err) {
+		return nil // object deleted before we handled it
+	} else if err != nil {
+		return err
+	}
+
+	if lease.Labels[leaderelection.GarbageCollectLabel] != "true" {
+		return nil
+	}
+	if remaining := garbageCollectionTime(lease, c.ttl).Sub(c.now()); remaining > 0 {
+		// renewals update the lease and enqueue it again, so checking once it is due is enough
+		c.queue.AddAfter(key, remaining)
+		return nil
+	}
+
+	klog.V(2).Infof("Deleting Lease %s|%s/%s expired longer than %s ago", logicalcluster.From(lease), lease.Namespace, lease.Name, c.ttl)
+	if err := c.deleteLease(ctx, lease); err != nil && !errors.IsNotFound(err) && !errors.IsConflict(err) {
+		return err
+	}
+	return nil
+}
+
+// garbageCollectionTime returns when the lease is due for deletion, i.e. the TTL after it expired.
+// A lease without renewals expires relative to its acquisition or creation.
+func garbageCollectionTime(lease *coordinationv1.Lease, ttl time.Duration) time.Time {
+	last := lease.CreationTimestamp.Time
+	if lease.Spec.AcquireTime != nil {
+		last = lease.Spec.AcquireTime.Time
+	}
+	if lease.Spec.RenewTime != nil {
+		last = lease.Spec.RenewTime.Time
+	}
+	var duration time.Duration
+	if lease.Spec.LeaseDurationSeconds != nil {
+		duration = time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second
+	}
+	return last.Add(duration).Add(ttl)
+}
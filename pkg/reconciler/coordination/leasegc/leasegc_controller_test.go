@@ -0,0 +1,104 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package leasegc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	coordinationlisters "k8s.io/client-go/listers/coordination/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/pointer"
+
+	"github.com/kcp-dev/kcp/pkg/leaderelection"
+)
+
+func TestProcess(t *testing.T) {
+	now := time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)
+	lease := func(labeled bool, renewed time.Time) *coordinationv1.Lease {
+		l := &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Name: "controller", Namespace: "default", ClusterName: "root:org:ws"},
+			Spec: coordinationv1.LeaseSpec{
+				LeaseDurationSeconds: pointer.Int32Ptr(15),
+				RenewTime:            &metav1.MicroTime{Time: renewed},
+			},
+		}
+		if labeled {
+			l.Labels = map[string]string{leaderelection.GarbageCollectLabel: "true"}
+		}
+		return l
+	}
+
+	tests := map[string]struct {
+		lease       *coordinationv1.Lease
+		wantDeleted bool
+	}{
+		"held lease is kept": {
+			lease: lease(true, now.Add(-time.Minute)),
+		},
+		"lease expired longer than the TTL ago is deleted": {
+			lease:       lease(true, now.Add(-time.Hour-15*time.Second)),
+			wantDeleted: true,
+		},
+		"lease without label is kept": {
+			lease: lease(false, now.Add(-24*time.Hour)),
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			require.NoError(t, indexer.Add(tt.lease))
+			key, err := cache.MetaNamespaceKeyFunc(tt.lease)
+			require.NoError(t, err)
+
+			deleted := false
+			c := &controller{
+				queue:       workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+				ttl:         time.Hour,
+				now:         func() time.Time { return now },
+				leaseLister: coordinationlisters.NewLeaseLister(indexer),
+				deleteLease: func(ctx context.Context, lease *coordinationv1.Lease) error {
+					deleted = true
+					return nil
+				},
+			}
+			require.NoError(t, c.process(context.Background(), key))
+			require.Equal(t, tt.wantDeleted, deleted)
+			// the delayed key is not ready before its time
+			require.Equal(t, 0, c.queue.Len())
+		})
+	}
+}
+
+func TestGarbageCollectionTime(t *testing.T) {
+	created := time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)
+	lease := &coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)}}
+	require.Equal(t, created.Add(time.Hour), garbageCollectionTime(lease, time.Hour))
+
+	lease.Spec.LeaseDurationSeconds = pointer.Int32Ptr(15)
+	lease.Spec.AcquireTime = &metav1.MicroTime{Time: created.Add(time.Minute)}
+	require.Equal(t, created.Add(time.Minute+15*time.Second+time.Hour), garbageCollectionTime(lease, time.Hour))
+
+	lease.Spec.RenewTime = &metav1.MicroTime{Time: created.Add(2 * time.Minute)}
+	require.Equal(t, created.Add(2*time.Minute+15*time.Second+time.Hour), garbageCollectionTime(lease, time.Hour))
+}
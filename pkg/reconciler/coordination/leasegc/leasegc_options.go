@@ -0,0 +1,46 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package leasegc
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+func DefaultOptions() *Options {
+	return &Options{
+		TTL: time.Hour,
+	}
+}
+
+func BindOptions(o *Options, fs *pflag.FlagSet) *Options {
+	fs.DurationVar(&o.TTL, "lease-gc-ttl", o.TTL, "Amount of time after their expiry after which the leases of per-workspace leader elections are deleted. 0 disables deletion.")
+	return o
+}
+
+type Options struct {
+	TTL time.Duration
+}
+
+func (o *Options) Validate() error {
+	if o.TTL < 0 {
+		return fmt.Errorf("--lease-gc-ttl must be >=0 (%s)", o.TTL)
+	}
+	return nil
+}
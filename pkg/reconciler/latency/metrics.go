@@ -0,0 +1,48 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package latency
+
+import (
+	"sync"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+var (
+	// the logical cluster itself is not a label to bound the cardinality,
+	// the debug endpoint reports the latencies by logical cluster.
+	reconcileLatency = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Namespace:      "kcp",
+			Name:           "reconcile_latency_seconds",
+			Help:           "Time from the first enqueuing of an object until it is reconciled successfully, by controller.",
+			Buckets:        buckets,
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"controller"},
+	)
+
+	registerMetrics sync.Once
+)
+
+// RegisterMetrics registers the reconcile latency metrics.
+func RegisterMetrics() {
+	registerMetrics.Do(func() {
+		legacyregistry.MustRegister(reconcileLatency)
+	})
+}
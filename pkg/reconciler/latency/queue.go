@@ -0,0 +1,164 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package latency
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clusters"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/kcp-dev/kcp/pkg/reconciler/priority"
+)
+
+// NewNamedRateLimitingQueue is like workqueue.NewNamedRateLimitingQueue, but it
+// records the reconcile latency of the items in DefaultTracker, and it prioritizes
+// the items of the root workspace and of system logical clusters over those of
+// tenants.
+func NewNamedRateLimitingQueue(rateLimiter workqueue.RateLimiter, name string) workqueue.RateLimitingInterface {
+	return NewNamedPriorityRateLimitingQueue(rateLimiter, name, priority.ByLogicalCluster)
+}
+
+// NewNamedPriorityRateLimitingQueue is like NewNamedRateLimitingQueue, but with a
+// custom classification of the items into priority classes.
+func NewNamedPriorityRateLimitingQueue(rateLimiter workqueue.RateLimiter, name string, classify priority.Classifier) workqueue.RateLimitingInterface {
+	return DefaultTracker.NewQueue(priority.NewNamedRateLimitingQueue(rateLimiter, name, classify), name)
+}
+
+// queue records the time from the first enqueuing of an item until it is done
+// without being requeued with rate limiting, i.e. until it is reconciled
+// successfully.
+type queue struct {
+	workqueue.RateLimitingInterface
+
+	name    string
+	tracker *Tracker
+
+	lock sync.Mutex
+	// queued are the times since which the queued items wait to be reconciled.
+	queued map[interface{}]time.Time
+	// processing are the times since which the items being processed wait to be
+	// reconciled.
+	processing map[interface{}]time.Time
+	// failed are the items being processed that were requeued with rate limiting.
+	failed map[interface{}]bool
+}
+
+func (q *queue) Add(item interface{}) {
+	q.enqueue(item, q.tracker.now())
+	q.RateLimitingInterface.Add(item)
+}
+
+func (q *queue) AddAfter(item interface{}, duration time.Duration) {
+	// the item is not waiting before it is due, e.g. for periodic resyncs
+	q.enqueue(item, q.tracker.now().Add(duration))
+	q.RateLimitingInterface.AddAfter(item, duration)
+}
+
+func (q *queue) AddRateLimited(item interface{}) {
+	q.lock.Lock()
+	if _, ok := q.processing[item]; ok {
+		q.failed[item] = true
+	} else {
+		q.enqueueLocked(item, q.tracker.now())
+	}
+	q.lock.Unlock()
+
+	q.RateLimitingInterface.AddRateLimited(item)
+}
+
+func (q *queue) enqueue(item interface{}, since time.Time) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	q.enqueueLocked(item, since)
+}
+
+func (q *queue) enqueueLocked(item interface{}, since time.Time) {
+	if queued, ok := q.queued[item]; !ok || since.Before(queued) {
+		q.queued[item] = since
+	}
+}
+
+func (q *queue) Get() (interface{}, bool) {
+	item, shutdown := q.RateLimitingInterface.Get()
+	if shutdown {
+		return item, shutdown
+	}
+
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	now := q.tracker.now()
+	since, ok := q.queued[item]
+	if !ok || since.After(now) {
+		since = now
+	}
+	delete(q.queued, item)
+	q.processing[item] = since
+
+	return item, shutdown
+}
+
+func (q *queue) Done(item interface{}) {
+	q.lock.Lock()
+	since, ok := q.processing[item]
+	delete(q.processing, item)
+	if ok && q.failed[item] {
+		// still waiting to be reconciled
+		delete(q.failed, item)
+		q.enqueueLocked(item, since)
+	} else if ok {
+		q.tracker.observe(q.name, clusterOf(item), q.tracker.now().Sub(since))
+	}
+	q.lock.Unlock()
+
+	q.RateLimitingInterface.Done(item)
+}
+
+// waiting calls f with the logical cluster of every item waiting to be
+// reconciled and the time since which it waits.
+func (q *queue) waiting(f func(cluster logicalcluster.Name, since time.Time)) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	for _, items := range []map[interface{}]time.Time{q.queued, q.processing} {
+		for item, since := range items {
+			f(clusterOf(item), since)
+		}
+	}
+}
+
+// clusterOf returns the logical cluster of a cluster-aware key like
+// "<namespace>/<cluster>|<name>", or an empty name for other items.
+func clusterOf(item interface{}) logicalcluster.Name {
+	key, ok := item.(string)
+	if !ok {
+		return logicalcluster.Name{}
+	}
+	_, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil || !strings.Contains(name, "|") {
+		return logicalcluster.Name{}
+	}
+	cluster, _ := clusters.SplitClusterAwareKey(name)
+	return cluster
+}
@@ -0,0 +1,313 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package latency
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
+// DebugPath is where the DefaultTracker serves its Report.
+const DebugPath = "/debug/kcp/reconcile-slo"
+
+const (
+	// defaultTarget is the reconcile latency target of a Report by default.
+	defaultTarget = time.Minute
+	// defaultLimit is the number of logical clusters listed per controller by default.
+	defaultLimit = 100
+)
+
+// buckets are the upper bounds of the reconcile latencies in seconds, both of
+// the metric and of the latencies kept by logical cluster.
+var buckets = []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600, 1800, 3600}
+
+// DefaultTracker tracks the queues of NewNamedRateLimitingQueue.
+var DefaultTracker = NewTracker()
+
+// Tracker tracks the reconcile latencies of controllers by logical cluster,
+// such that operators can tell whether the objects of a tenant are reconciled
+// within a target time.
+type Tracker struct {
+	now func() time.Time
+
+	lock   sync.Mutex
+	queues []*queue
+	stats  map[statsKey]*stats
+}
+
+type statsKey struct {
+	controller string
+	cluster    logicalcluster.Name
+}
+
+type stats struct {
+	count int64
+	// buckets count the latencies up to the bound of the same index in buckets,
+	// and the last one those above all bounds.
+	buckets []int64
+	max     time.Duration
+}
+
+// NewTracker returns a Tracker without queues.
+func NewTracker() *Tracker {
+	return &Tracker{
+		now:   time.Now,
+		stats: map[statsKey]*stats{},
+	}
+}
+
+// NewQueue wraps the queue of the named controller such that the Tracker
+// records the latencies of its items. Items are reconciled when they are done
+// without being requeued with rate limiting. The logical cluster of an item is
+// known for cluster-aware keys.
+func (t *Tracker) NewQueue(q workqueue.RateLimitingInterface, name string) workqueue.RateLimitingInterface {
+	wrapped := &queue{
+		RateLimitingInterface: q,
+		name:                  name,
+		tracker:               t,
+		queued:                map[interface{}]time.Time{},
+		processing:            map[interface{}]time.Time{},
+		failed:                map[interface{}]bool{},
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.queues = append(t.queues, wrapped)
+
+	return wrapped
+}
+
+func (t *Tracker) observe(controller string, cluster logicalcluster.Name, latency time.Duration) {
+	if latency < 0 {
+		latency = 0
+	}
+	reconcileLatency.WithLabelValues(controller).Observe(latency.Seconds())
+
+	if cluster.Empty() {
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	key := statsKey{controller: controller, cluster: cluster}
+	s, ok := t.stats[key]
+	if !ok {
+		s = &stats{buckets: make([]int64, len(buckets)+1)}
+		t.stats[key] = s
+	}
+	s.count++
+	s.buckets[sort.SearchFloat64s(buckets, latency.Seconds())]++
+	if latency > s.max {
+		s.max = latency
+	}
+}
+
+// Report are the reconcile latencies by controller and logical cluster since
+// the start of the server.
+type Report struct {
+	// Target is the latency target the reconciles are measured against. It is
+	// rounded down to the bucket bounds the latencies are kept in.
+	Target Duration `json:"target"`
+	// Controllers are the reports of the controllers, ordered by name.
+	Controllers []ControllerReport `json:"controllers"`
+}
+
+// ControllerReport are the reconcile latencies of a controller.
+type ControllerReport struct {
+	Name string `json:"name"`
+	// Reconciles is the number of reconciles of the listed logical clusters.
+	Reconciles int64 `json:"reconciles"`
+	// WithinTarget is the number of the reconciles within the target.
+	WithinTarget int64 `json:"withinTarget"`
+	// Clusters are the logical clusters with the lowest ratio of reconciles
+	// within the target first.
+	Clusters []ClusterReport `json:"clusters"`
+}
+
+// ClusterReport are the reconcile latencies of a controller for a logical cluster.
+type ClusterReport struct {
+	Name         string `json:"name"`
+	Reconciles   int64  `json:"reconciles"`
+	WithinTarget int64  `json:"withinTarget"`
+	// Ratio is the ratio of reconciles within the target, 1 without reconciles.
+	Ratio float64 `json:"ratio"`
+	// P50 and P99 are the upper bounds of the buckets of the percentiles.
+	P50 Duration `json:"p50"`
+	P99 Duration `json:"p99"`
+	Max Duration `json:"max"`
+	// Waiting is the number of items queued or being processed.
+	Waiting int `json:"waiting,omitempty"`
+	// OldestWaiting is the time the oldest waiting item waits for.
+	OldestWaiting Duration `json:"oldestWaiting,omitempty"`
+}
+
+// Duration is a duration serialized like "1m2.5s".
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).Round(time.Millisecond).String())
+}
+
+// Report returns the reconcile latencies by controller of the limit logical
+// clusters with the lowest ratio of reconciles within target. A non-positive
+// limit lists all of them. A non-empty cluster restricts the report to that
+// logical cluster.
+func (t *Tracker) Report(target time.Duration, cluster logicalcluster.Name, limit int) *Report {
+	// the reconciles within target are those in the buckets up to it
+	withinBuckets := sort.SearchFloat64s(buckets, target.Seconds())
+	if withinBuckets < len(buckets) && buckets[withinBuckets] == target.Seconds() {
+		withinBuckets++
+	}
+	effectiveTarget := time.Duration(0)
+	if withinBuckets > 0 {
+		effectiveTarget = time.Duration(buckets[withinBuckets-1] * float64(time.Second))
+	}
+
+	now := t.now()
+	controllers := map[string]map[logicalcluster.Name]*ClusterReport{}
+	clusterReport := func(controller string, name logicalcluster.Name) *ClusterReport {
+		clusters, ok := controllers[controller]
+		if !ok {
+			clusters = map[logicalcluster.Name]*ClusterReport{}
+			controllers[controller] = clusters
+		}
+		r, ok := clusters[name]
+		if !ok {
+			r = &ClusterReport{Name: name.String(), Ratio: 1}
+			clusters[name] = r
+		}
+		return r
+	}
+
+	t.lock.Lock()
+	queues := append([]*queue(nil), t.queues...)
+	for key, s := range t.stats {
+		if !cluster.Empty() && key.cluster != cluster {
+			continue
+		}
+		r := clusterReport(key.controller, key.cluster)
+		r.Reconciles = s.count
+		for _, count := range s.buckets[:withinBuckets] {
+			r.WithinTarget += count
+		}
+		r.Ratio = float64(r.WithinTarget) / float64(r.Reconciles)
+		r.P50 = percentile(s, 0.5)
+		r.P99 = percentile(s, 0.99)
+		r.Max = Duration(s.max)
+	}
+	t.lock.Unlock()
+
+	for _, q := range queues {
+		q.waiting(func(name logicalcluster.Name, since time.Time) {
+			if name.Empty() || (!cluster.Empty() && name != cluster) {
+				return
+			}
+			r := clusterReport(q.name, name)
+			r.Waiting++
+			if age := Duration(now.Sub(since)); age > r.OldestWaiting {
+				r.OldestWaiting = age
+			}
+		})
+	}
+
+	report := &Report{Target: Duration(effectiveTarget), Controllers: []ControllerReport{}}
+	for name, clusters := range controllers {
+		c := ControllerReport{Name: name, Clusters: make([]ClusterReport, 0, len(clusters))}
+		for _, r := range clusters {
+			c.Clusters = append(c.Clusters, *r)
+		}
+		sort.Slice(c.Clusters, func(i, j int) bool {
+			if c.Clusters[i].Ratio != c.Clusters[j].Ratio {
+				return c.Clusters[i].Ratio < c.Clusters[j].Ratio
+			}
+			if c.Clusters[i].OldestWaiting != c.Clusters[j].OldestWaiting {
+				return c.Clusters[i].OldestWaiting > c.Clusters[j].OldestWaiting
+			}
+			return c.Clusters[i].Name < c.Clusters[j].Name
+		})
+		if limit > 0 && len(c.Clusters) > limit {
+			c.Clusters = c.Clusters[:limit]
+		}
+		for _, r := range c.Clusters {
+			c.Reconciles += r.Reconciles
+			c.WithinTarget += r.WithinTarget
+		}
+		report.Controllers = append(report.Controllers, c)
+	}
+	sort.Slice(report.Controllers, func(i, j int) bool {
+		return report.Controllers[i].Name < report.Controllers[j].Name
+	})
+
+	return report
+}
+
+// percentile returns the upper bound of the bucket of the q-th percentile, or
+// the maximum latency if that is lower or above all bounds.
+func percentile(s *stats, q float64) Duration {
+	rank := int64(math.Ceil(q * float64(s.count)))
+	var cumulative int64
+	for i, count := range s.buckets[:len(buckets)] {
+		cumulative += count
+		if cumulative >= rank {
+			if bound := time.Duration(buckets[i] * float64(time.Second)); bound < s.max {
+				return Duration(bound)
+			}
+			break
+		}
+	}
+	return Duration(s.max)
+}
+
+// ServeHTTP serves the Report as JSON. The target query parameter sets the
+// latency target, e.g. "30s", the cluster parameter restricts the report to a
+// logical cluster, and the limit parameter sets the number of logical clusters
+// listed per controller, 0 for all of them.
+func (t *Tracker) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+
+	target := defaultTarget
+	if s := query.Get("target"); s != "" {
+		var err error
+		if target, err = time.ParseDuration(s); err != nil {
+			http.Error(w, "invalid target: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	limit := defaultLimit
+	if s := query.Get("limit"); s != "" {
+		var err error
+		if limit, err = strconv.Atoi(s); err != nil {
+			http.Error(w, "invalid limit: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(t.Report(target, logicalcluster.New(query.Get("cluster")), limit))
+}
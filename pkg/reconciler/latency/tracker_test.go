@@ -0,0 +1,133 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package latency
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
+func TestQueue(t *testing.T) {
+	now := time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)
+	tracker := NewTracker()
+	tracker.now = func() time.Time { return now }
+	q := tracker.NewQueue(workqueue.NewRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(0, 0)), "test")
+	defer q.ShutDown()
+
+	reconcile := func(requeue bool) {
+		item, _ := q.Get()
+		now = now.Add(time.Second)
+		if requeue {
+			q.AddRateLimited(item)
+		} else {
+			q.Forget(item)
+		}
+		q.Done(item)
+	}
+
+	// waits from the first enqueuing
+	q.Add("root:org:a|foo")
+	now = now.Add(10 * time.Second)
+	q.Add("root:org:a|foo")
+	reconcile(false)
+
+	// waits across failed reconciles
+	q.Add("default/root:org:b|foo")
+	reconcile(true)
+	now = now.Add(time.Minute)
+	reconcile(true)
+	reconcile(false)
+
+	// does not wait before being due
+	q.AddAfter("root:org:a|foo", time.Hour)
+	now = now.Add(time.Hour)
+	q.Add("root:org:a|foo")
+	reconcile(false)
+
+	// other keys have no logical cluster
+	q.Add("foo")
+	reconcile(false)
+
+	// waiting items
+	q.Add("root:org:b|bar")
+	now = now.Add(5 * time.Minute)
+
+	report := tracker.Report(30*time.Second, logicalcluster.Name{}, 0)
+	require.Equal(t, &Report{
+		Target: Duration(30 * time.Second),
+		Controllers: []ControllerReport{{
+			Name:         "test",
+			Reconciles:   3,
+			WithinTarget: 2,
+			Clusters: []ClusterReport{
+				{Name: "root:org:b", Reconciles: 1, Ratio: 0, P50: Duration(63 * time.Second), P99: Duration(63 * time.Second), Max: Duration(63 * time.Second), Waiting: 1, OldestWaiting: Duration(5 * time.Minute)},
+				{Name: "root:org:a", Reconciles: 2, WithinTarget: 2, Ratio: 1, P50: Duration(time.Second), P99: Duration(11 * time.Second), Max: Duration(11 * time.Second)},
+			},
+		}},
+	}, report)
+}
+
+func TestReport(t *testing.T) {
+	tracker := NewTracker()
+	for _, latency := range []time.Duration{time.Second, 2 * time.Second, 20 * time.Second, time.Hour} {
+		tracker.observe("a", logicalcluster.New("root:org:a"), latency)
+	}
+	tracker.observe("b", logicalcluster.New("root:org:a"), time.Second)
+	tracker.observe("b", logicalcluster.New("root:org:b"), time.Second)
+	tracker.observe("b", logicalcluster.New("root:org:c"), time.Minute)
+
+	t.Run("target is rounded down to bucket bounds", func(t *testing.T) {
+		report := tracker.Report(15*time.Second, logicalcluster.New("root:org:a"), 0)
+		require.Equal(t, Duration(10*time.Second), report.Target)
+		require.Len(t, report.Controllers, 2)
+		require.Equal(t, []ClusterReport{
+			{Name: "root:org:a", Reconciles: 4, WithinTarget: 2, Ratio: 0.5, P50: Duration(2500 * time.Millisecond), P99: Duration(time.Hour), Max: Duration(time.Hour)},
+		}, report.Controllers[0].Clusters)
+	})
+
+	t.Run("limit lists the worst clusters", func(t *testing.T) {
+		report := tracker.Report(time.Minute, logicalcluster.Name{}, 1)
+		require.Equal(t, ControllerReport{
+			Name:       "b",
+			Reconciles: 1,
+			Clusters: []ClusterReport{
+				{Name: "root:org:a", Reconciles: 1, WithinTarget: 1, Ratio: 1, P50: Duration(time.Second), P99: Duration(time.Second), Max: Duration(time.Second)},
+			},
+			WithinTarget: 1,
+		}, report.Controllers[1])
+	})
+
+	t.Run("served as JSON", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		tracker.ServeHTTP(rec, httptest.NewRequest("GET", DebugPath+"?target=1s&cluster=root:org:c", nil))
+		var report map[string]interface{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+		require.Equal(t, "1s", report["target"])
+		require.Len(t, report["controllers"], 1)
+
+		rec = httptest.NewRecorder()
+		tracker.ServeHTTP(rec, httptest.NewRequest("GET", DebugPath+"?target=soon", nil))
+		require.Equal(t, 400, rec.Code)
+	})
+}
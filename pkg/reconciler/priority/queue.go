@@ -0,0 +1,219 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package priority
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clusters"
+	"k8s.io/client-go/util/workqueue"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+// Class is the priority class of the items of a queue.
+type Class int
+
+const (
+	// Critical items are those of the platform, e.g. shards, APIExports and
+	// ClusterWorkspaceTypes in the root workspace.
+	Critical Class = iota
+	// Normal items are those of tenants.
+	Normal
+
+	numClasses = 2
+)
+
+func (c Class) String() string {
+	if c == Critical {
+		return "critical"
+	}
+	return "normal"
+}
+
+// Classifier returns the priority class of a queue item. It must return the
+// same class for the same item.
+type Classifier func(item interface{}) Class
+
+// maxConsecutiveCritical is the number of critical items handed out in a row
+// while normal items are waiting. Then a normal item is handed out, such that
+// tenants are not starved by a flood of critical items.
+const maxConsecutiveCritical = 10
+
+// ByLogicalCluster classifies cluster-aware keys like "<namespace>/<cluster>|<name>"
+// of the root workspace and of system logical clusters as critical, and all other
+// items as normal.
+func ByLogicalCluster(item interface{}) Class {
+	key, ok := item.(string)
+	if !ok {
+		return Normal
+	}
+	_, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil || !strings.Contains(name, "|") {
+		return Normal
+	}
+	cluster, _ := clusters.SplitClusterAwareKey(name)
+	if cluster == tenancyv1alpha1.RootCluster || cluster.HasPrefix(logicalcluster.New("system")) {
+		return Critical
+	}
+	return Normal
+}
+
+// NewNamedRateLimitingQueue is like workqueue.NewNamedRateLimitingQueue, but it
+// keeps a separate queue for every priority class. The critical queue is named
+// "<name>-critical", the normal queue "<name>", such that the workqueue metrics
+// report them separately. Critical items are handed out first, but at least every
+// (maxConsecutiveCritical+1)th item is a normal one if any is waiting.
+func NewNamedRateLimitingQueue(rateLimiter workqueue.RateLimiter, name string, classify Classifier) workqueue.RateLimitingInterface {
+	q := &queue{
+		name:     name,
+		classify: classify,
+	}
+	q.queues[Critical] = workqueue.NewNamed(name + "-critical")
+	q.queues[Normal] = workqueue.NewNamed(name)
+	q.cond = sync.NewCond(&q.lock)
+
+	return &rateLimitingQueue{
+		DelayingInterface: workqueue.NewDelayingQueueWithCustomQueue(q, name),
+		rateLimiter:       rateLimiter,
+	}
+}
+
+// queue hands out the items of its queues by priority. An item is always in the
+// queue of its class, hence the queues take care of the deduplication.
+type queue struct {
+	name     string
+	classify Classifier
+
+	queues [numClasses]workqueue.Interface
+
+	// lock serializes Get, such that the queue chosen by it is not emptied by a
+	// concurrent Get before its item is taken.
+	lock sync.Mutex
+	// cond is signaled when items might have been added to the queues, or on shutdown.
+	cond                *sync.Cond
+	consecutiveCritical int
+	shuttingDown        bool
+}
+
+var _ workqueue.Interface = &queue{}
+
+func (q *queue) Add(item interface{}) {
+	q.queues[q.classify(item)].Add(item)
+	q.signal()
+}
+
+func (q *queue) Len() int {
+	return q.queues[Critical].Len() + q.queues[Normal].Len()
+}
+
+func (q *queue) Get() (interface{}, bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	for q.Len() == 0 && !q.shuttingDown {
+		q.cond.Wait()
+	}
+	if q.Len() == 0 {
+		return nil, true
+	}
+
+	class := Normal
+	if q.queues[Critical].Len() > 0 {
+		if q.queues[Normal].Len() == 0 || q.consecutiveCritical < maxConsecutiveCritical {
+			class = Critical
+		} else {
+			starvationProtections.WithLabelValues(q.name).Inc()
+		}
+	}
+	if class == Critical {
+		q.consecutiveCritical++
+	} else {
+		q.consecutiveCritical = 0
+	}
+
+	// does not block as only Get takes items from the queues
+	return q.queues[class].Get()
+}
+
+func (q *queue) Done(item interface{}) {
+	// the item is added back to the queue if it was added while being processed
+	q.queues[q.classify(item)].Done(item)
+	q.signal()
+}
+
+func (q *queue) ShutDown() {
+	q.setShuttingDown()
+	for _, sq := range q.queues {
+		sq.ShutDown()
+	}
+}
+
+func (q *queue) ShutDownWithDrain() {
+	q.setShuttingDown()
+	for _, sq := range q.queues {
+		sq.ShutDownWithDrain()
+	}
+}
+
+func (q *queue) ShuttingDown() bool {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	return q.shuttingDown
+}
+
+func (q *queue) setShuttingDown() {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	q.shuttingDown = true
+	q.cond.Broadcast()
+}
+
+func (q *queue) signal() {
+	// taking the lock makes sure that a Get is either waiting or has not
+	// looked at the queues yet.
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	q.cond.Broadcast()
+}
+
+// rateLimitingQueue is like the rate limiting queue of client-go, which cannot
+// wrap another queue.
+type rateLimitingQueue struct {
+	workqueue.DelayingInterface
+
+	rateLimiter workqueue.RateLimiter
+}
+
+func (q *rateLimitingQueue) AddRateLimited(item interface{}) {
+	q.DelayingInterface.AddAfter(item, q.rateLimiter.When(item))
+}
+
+func (q *rateLimitingQueue) NumRequeues(item interface{}) int {
+	return q.rateLimiter.NumRequeues(item)
+}
+
+func (q *rateLimitingQueue) Forget(item interface{}) {
+	q.rateLimiter.Forget(item)
+}
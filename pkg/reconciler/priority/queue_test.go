@@ -0,0 +1,155 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package priority
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
+)
+
+func TestByLogicalCluster(t *testing.T) {
+	tests := []struct {
+		item interface{}
+		want Class
+	}{
+		{"root|shard-1", Critical},
+		{"ns/root|export", Critical},
+		{"system:system-crds|foo", Critical},
+		{"root:org|export", Normal},
+		{"ns/root:org:team|foo", Normal},
+		{"foo", Normal},
+		{42, Normal},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.want, ByLogicalCluster(tt.item), "item %v", tt.item)
+	}
+}
+
+func byName(item interface{}) Class {
+	if s := item.(string); s[0] == 'c' {
+		return Critical
+	}
+	return Normal
+}
+
+func get(t *testing.T, q workqueue.Interface) string {
+	t.Helper()
+	item, shutdown := q.Get()
+	require.False(t, shutdown)
+	q.Done(item)
+	return item.(string)
+}
+
+func TestPriority(t *testing.T) {
+	q := NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "test", byName)
+	defer q.ShutDown()
+
+	q.Add("n1")
+	q.Add("c1")
+	q.Add("n2")
+	q.Add("c2")
+	q.Add("c1") // deduplicated
+	require.Equal(t, 4, q.Len())
+
+	var got []string
+	for q.Len() > 0 {
+		got = append(got, get(t, q))
+	}
+	require.Equal(t, []string{"c1", "c2", "n1", "n2"}, got)
+}
+
+func TestStarvationProtection(t *testing.T) {
+	q := NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "test", byName)
+	defer q.ShutDown()
+
+	q.Add("n1")
+	q.Add("n2")
+	for i := 0; i < 2*maxConsecutiveCritical; i++ {
+		q.Add("c" + string(rune('a'+i)))
+	}
+
+	var normal []int
+	for i := 0; q.Len() > 0; i++ {
+		if item := get(t, q); item[0] == 'n' {
+			normal = append(normal, i)
+		}
+	}
+	require.Equal(t, []int{maxConsecutiveCritical, 2*maxConsecutiveCritical + 1}, normal)
+}
+
+func TestRequeueWhileProcessing(t *testing.T) {
+	q := NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "test", byName)
+	defer q.ShutDown()
+
+	q.Add("c1")
+	item, _ := q.Get()
+	q.Add("c1")
+	require.Equal(t, 0, q.Len(), "item being processed must not be handed out twice")
+
+	// Get blocks until the item is done
+	got := make(chan interface{})
+	go func() {
+		item, _ := q.Get()
+		got <- item
+	}()
+	select {
+	case <-got:
+		t.Fatal("Get must block while the item is processed")
+	case <-time.After(50 * time.Millisecond):
+	}
+	q.Done(item)
+	select {
+	case item := <-got:
+		require.Equal(t, "c1", item)
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Fatal("Get did not return the requeued item")
+	}
+}
+
+func TestShutDown(t *testing.T) {
+	q := NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "test", byName)
+
+	done := make(chan bool)
+	go func() {
+		_, shutdown := q.Get()
+		done <- shutdown
+	}()
+	q.ShutDown()
+	select {
+	case shutdown := <-done:
+		require.True(t, shutdown)
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Fatal("Get did not return on shutdown")
+	}
+	require.True(t, q.ShuttingDown())
+}
+
+func TestAddRateLimited(t *testing.T) {
+	q := NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(time.Millisecond, time.Millisecond), "test", byName)
+	defer q.ShutDown()
+
+	q.AddRateLimited("n1")
+	require.Equal(t, 1, q.NumRequeues("n1"))
+	require.Equal(t, "n1", get(t, q))
+	q.Forget("n1")
+	require.Equal(t, 0, q.NumRequeues("n1"))
+}
@@ -0,0 +1,339 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package placement
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	schedulingv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/scheduling/v1alpha1"
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+)
+
+// MaxExtenderScore is the highest score an extender can give a location. Higher
+// scores are capped.
+const MaxExtenderScore = 10
+
+// Extender is consulted by the placement scheduler to veto or re-score the locations
+// a namespace can be placed on, e.g. with data of cost APIs or compliance systems.
+type Extender interface {
+	// Name identifies the extender in logs and errors.
+	Name() string
+	// IsInterested returns whether the extender is consulted for the namespaces of
+	// the given workspace.
+	IsInterested(clusterName logicalcluster.Name) bool
+	// IsIgnorable returns whether scheduling goes on without the extender if it fails.
+	IsIgnorable() bool
+	// Filter returns the locations of the given ones the namespace may be placed on,
+	// and the reasons why the others may not. Nil means all of them.
+	Filter(ctx context.Context, args *ExtenderArgs) (*ExtenderFilterResult, error)
+	// Prioritize returns scores between 0 and MaxExtenderScore for the locations. Nil
+	// means the extender does not score.
+	Prioritize(ctx context.Context, args *ExtenderArgs) (*ExtenderPriorityResult, error)
+	// Weight is the factor the scores of the extender are multiplied with.
+	Weight() int64
+}
+
+// ExtenderArgs is what extenders are called with.
+type ExtenderArgs struct {
+	Namespace ExtenderNamespace  `json:"namespace"`
+	Locations []ExtenderLocation `json:"locations"`
+}
+
+// ExtenderNamespace is the namespace to be placed.
+type ExtenderNamespace struct {
+	ClusterName string            `json:"clusterName"`
+	Name        string            `json:"name"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ExtenderLocation is a location the namespace can be placed on.
+type ExtenderLocation struct {
+	// ClusterName is the workspace of the location.
+	ClusterName string            `json:"clusterName"`
+	Name        string            `json:"name"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	// WorkloadClusters are the names of the ready WorkloadClusters of the location.
+	WorkloadClusters []string `json:"workloadClusters"`
+}
+
+// ExtenderFilterResult is the answer of an extender to a filter call.
+type ExtenderFilterResult struct {
+	// Locations are the names of the locations the namespace may be placed on.
+	Locations []string `json:"locations"`
+	// FailedLocations maps the names of vetoed locations to the reason.
+	FailedLocations map[string]string `json:"failedLocations,omitempty"`
+	// Error is an error of the extender, failing the call.
+	Error string `json:"error,omitempty"`
+}
+
+// ExtenderPriorityResult is the answer of an extender to a prioritize call.
+type ExtenderPriorityResult struct {
+	Scores []LocationScore `json:"scores"`
+	// Error is an error of the extender, failing the call.
+	Error string `json:"error,omitempty"`
+}
+
+// LocationScore is the score of a location.
+type LocationScore struct {
+	Location string `json:"location"`
+	Score    int64  `json:"score"`
+}
+
+// WebhookExtender is an Extender posting ExtenderArgs as JSON to <URLPrefix>/<FilterVerb>
+// and <URLPrefix>/<PrioritizeVerb>, answered with ExtenderFilterResult and
+// ExtenderPriorityResult respectively. Any non-2xx response fails the call.
+type WebhookExtender struct {
+	ExtenderName   string
+	URLPrefix      string
+	FilterVerb     string
+	PrioritizeVerb string
+	ExtenderWeight int64
+	Ignorable      bool
+	// ClusterPrefixes are the workspaces the extender is consulted for, including
+	// their descendants. Empty means all.
+	ClusterPrefixes []logicalcluster.Name
+	Client          *http.Client
+}
+
+var _ Extender = &WebhookExtender{}
+
+// Name implements Extender.
+func (e *WebhookExtender) Name() string {
+	return e.ExtenderName
+}
+
+// IsInterested implements Extender.
+func (e *WebhookExtender) IsInterested(clusterName logicalcluster.Name) bool {
+	if len(e.ClusterPrefixes) == 0 {
+		return true
+	}
+	for _, prefix := range e.ClusterPrefixes {
+		if clusterName == prefix || clusterName.HasPrefix(prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsIgnorable implements Extender.
+func (e *WebhookExtender) IsIgnorable() bool {
+	return e.Ignorable
+}
+
+// Weight implements Extender.
+func (e *WebhookExtender) Weight() int64 {
+	return e.ExtenderWeight
+}
+
+// Filter implements Extender.
+func (e *WebhookExtender) Filter(ctx context.Context, args *ExtenderArgs) (*ExtenderFilterResult, error) {
+	if e.FilterVerb == "" {
+		return nil, nil
+	}
+	var result ExtenderFilterResult
+	if err := e.post(ctx, e.FilterVerb, args, &result); err != nil {
+		return nil, err
+	}
+	if result.Error != "" {
+		return nil, fmt.Errorf("extender %s failed to filter: %s", e.ExtenderName, result.Error)
+	}
+	return &result, nil
+}
+
+// Prioritize implements Extender.
+func (e *WebhookExtender) Prioritize(ctx context.Context, args *ExtenderArgs) (*ExtenderPriorityResult, error) {
+	if e.PrioritizeVerb == "" {
+		return nil, nil
+	}
+	var result ExtenderPriorityResult
+	if err := e.post(ctx, e.PrioritizeVerb, args, &result); err != nil {
+		return nil, err
+	}
+	if result.Error != "" {
+		return nil, fmt.Errorf("extender %s failed to prioritize: %s", e.ExtenderName, result.Error)
+	}
+	return &result, nil
+}
+
+func (e *WebhookExtender) post(ctx context.Context, verb string, args *ExtenderArgs, result interface{}) error {
+	bs, err := json.Marshal(args)
+	if err != nil {
+		return err
+	}
+
+	url := strings.TrimSuffix(e.URLPrefix, "/") + "/" + verb
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(bs))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := e.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("extender %s failed to %s: %w", e.ExtenderName, verb, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s from extender %s for %s", resp.Status, e.ExtenderName, verb)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode %s response of extender %s: %w", verb, e.ExtenderName, err)
+	}
+	return nil
+}
+
+// candidate is a location with ready WorkloadClusters a namespace can be placed on.
+type candidate struct {
+	clusterName logicalcluster.Name
+	location    *schedulingv1alpha1.Location
+	clusters    []*workloadv1alpha1.WorkloadCluster
+	score       int64
+}
+
+// runExtenders consults the extenders interested in the workspace of the namespace.
+// It returns the candidates not vetoed by any of them, scored by all of them.
+// Failing extenders fail scheduling, unless they are ignorable.
+func runExtenders(ctx context.Context, extenders []Extender, clusterName logicalcluster.Name, ns *corev1.Namespace, candidates []*candidate) ([]*candidate, error) {
+	for _, extender := range extenders {
+		if len(candidates) == 0 {
+			return nil, nil
+		}
+		if !extender.IsInterested(clusterName) {
+			continue
+		}
+
+		result, err := extender.Filter(ctx, extenderArgs(clusterName, ns, candidates))
+		if err != nil {
+			if extender.IsIgnorable() {
+				klog.Warningf("Ignoring failed placement extender %s for namespace %s|%s: %v", extender.Name(), clusterName, ns.Name, err)
+				continue
+			}
+			return nil, err
+		}
+		if result == nil {
+			continue
+		}
+		kept := map[string]bool{}
+		for _, name := range result.Locations {
+			kept[name] = true
+		}
+		var filtered []*candidate
+		for _, c := range candidates {
+			if kept[c.location.Name] {
+				filtered = append(filtered, c)
+				continue
+			}
+			reason := result.FailedLocations[c.location.Name]
+			if reason == "" {
+				reason = "not kept"
+			}
+			klog.V(2).Infof("Placement extender %s vetoed location %s|%s for namespace %s|%s: %s", extender.Name(), c.clusterName, c.location.Name, clusterName, ns.Name, reason)
+		}
+		candidates = filtered
+	}
+
+	for _, extender := range extenders {
+		if len(candidates) == 0 {
+			return nil, nil
+		}
+		if !extender.IsInterested(clusterName) {
+			continue
+		}
+
+		result, err := extender.Prioritize(ctx, extenderArgs(clusterName, ns, candidates))
+		if err != nil {
+			if extender.IsIgnorable() {
+				klog.Warningf("Ignoring failed placement extender %s for namespace %s|%s: %v", extender.Name(), clusterName, ns.Name, err)
+				continue
+			}
+			return nil, err
+		}
+		if result == nil {
+			continue
+		}
+		scores := map[string]int64{}
+		for _, s := range result.Scores {
+			scores[s.Location] = s.Score
+		}
+		for _, c := range candidates {
+			score := scores[c.location.Name]
+			if score < 0 {
+				score = 0
+			} else if score > MaxExtenderScore {
+				score = MaxExtenderScore
+			}
+			c.score += extender.Weight() * score
+		}
+	}
+
+	return candidates, nil
+}
+
+func extenderArgs(clusterName logicalcluster.Name, ns *corev1.Namespace, candidates []*candidate) *ExtenderArgs {
+	args := &ExtenderArgs{
+		Namespace: ExtenderNamespace{
+			ClusterName: clusterName.String(),
+			Name:        ns.Name,
+			Labels:      ns.Labels,
+			Annotations: ns.Annotations,
+		},
+	}
+	for _, c := range candidates {
+		l := ExtenderLocation{
+			ClusterName: c.clusterName.String(),
+			Name:        c.location.Name,
+			Labels:      c.location.Labels,
+		}
+		for _, wc := range c.clusters {
+			l.WorkloadClusters = append(l.WorkloadClusters, wc.Name)
+		}
+		args.Locations = append(args.Locations, l)
+	}
+	return args
+}
+
+// pickCandidate returns one of the candidates with the highest score, randomly.
+func pickCandidate(candidates []*candidate) *candidate {
+	var best []*candidate
+	for _, c := range candidates {
+		if len(best) == 0 || c.score > best[0].score {
+			best = []*candidate{c}
+		} else if c.score == best[0].score {
+			best = append(best, c)
+		}
+	}
+	if len(best) == 0 {
+		return nil
+	}
+	return best[rand.Intn(len(best))]
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package placement
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+)
+
+type fakeExtender struct {
+	name      string
+	clusters  []logicalcluster.Name
+	ignorable bool
+	weight    int64
+
+	// keep is the locations kept by Filter. Nil means the extender does not filter.
+	keep []string
+	// scores are returned by Prioritize. Nil means the extender does not score.
+	scores map[string]int64
+	err    error
+}
+
+func (e *fakeExtender) Name() string      { return e.name }
+func (e *fakeExtender) IsIgnorable() bool { return e.ignorable }
+func (e *fakeExtender) Weight() int64     { return e.weight }
+
+func (e *fakeExtender) IsInterested(clusterName logicalcluster.Name) bool {
+	w := &WebhookExtender{ClusterPrefixes: e.clusters}
+	return w.IsInterested(clusterName)
+}
+
+func (e *fakeExtender) Filter(ctx context.Context, args *ExtenderArgs) (*ExtenderFilterResult, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	if e.keep == nil {
+		return nil, nil
+	}
+	return &ExtenderFilterResult{Locations: e.keep}, nil
+}
+
+func (e *fakeExtender) Prioritize(ctx context.Context, args *ExtenderArgs) (*ExtenderPriorityResult, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	if e.scores == nil {
+		return nil, nil
+	}
+	result := &ExtenderPriorityResult{}
+	for l, s := range e.scores {
+		result.Scores = append(result.Scores, LocationScore{Location: l, Score: s})
+	}
+	return result, nil
+}
+
+func candidates(names ...string) []*candidate {
+	var ret []*candidate
+	for _, name := range names {
+		ret = append(ret, &candidate{
+			clusterName: logicalcluster.New("root:org:negotiation"),
+			location:    location(name),
+			clusters:    []*workloadv1alpha1.WorkloadCluster{cluster(name+"-1", "uid-"+name)},
+		})
+	}
+	return ret
+}
+
+func TestRunExtenders(t *testing.T) {
+	tests := []struct {
+		name       string
+		extenders  []Extender
+		wantScores map[string]int64
+		wantErr    bool
+	}{
+		{
+			name:       "no extenders",
+			wantScores: map[string]int64{"a": 0, "b": 0, "c": 0},
+		},
+		{
+			name: "filters in order",
+			extenders: []Extender{
+				&fakeExtender{name: "first", keep: []string{"a", "b"}},
+				&fakeExtender{name: "second", keep: []string{"b", "c"}},
+			},
+			wantScores: map[string]int64{"b": 0},
+		},
+		{
+			name: "weighted and capped scores",
+			extenders: []Extender{
+				&fakeExtender{name: "cost", weight: 2, scores: map[string]int64{"a": 3, "b": 100, "c": -5}},
+				&fakeExtender{name: "latency", weight: 1, scores: map[string]int64{"a": 10}},
+			},
+			wantScores: map[string]int64{"a": 16, "b": 20, "c": 0},
+		},
+		{
+			name: "not interested",
+			extenders: []Extender{
+				&fakeExtender{name: "other-org", clusters: []logicalcluster.Name{logicalcluster.New("root:other")}, keep: []string{}},
+			},
+			wantScores: map[string]int64{"a": 0, "b": 0, "c": 0},
+		},
+		{
+			name: "interested in the org",
+			extenders: []Extender{
+				&fakeExtender{name: "org", clusters: []logicalcluster.Name{logicalcluster.New("root:org")}, keep: []string{"c"}},
+			},
+			wantScores: map[string]int64{"c": 0},
+		},
+		{
+			name: "failing",
+			extenders: []Extender{
+				&fakeExtender{name: "broken", err: errors.New("boom")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "failing but ignorable",
+			extenders: []Extender{
+				&fakeExtender{name: "broken", ignorable: true, err: errors.New("boom")},
+				&fakeExtender{name: "cost", weight: 1, scores: map[string]int64{"a": 1}},
+			},
+			wantScores: map[string]int64{"a": 1, "b": 0, "c": 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test"}}
+			got, err := runExtenders(context.Background(), tt.extenders, logicalcluster.New("root:org:ws"), ns, candidates("a", "b", "c"))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			scores := map[string]int64{}
+			for _, c := range got {
+				scores[c.location.Name] = c.score
+			}
+			require.Equal(t, tt.wantScores, scores)
+		})
+	}
+}
+
+func TestPickCandidate(t *testing.T) {
+	require.Nil(t, pickCandidate(nil))
+
+	cs := candidates("a", "b", "c")
+	cs[0].score, cs[1].score, cs[2].score = 5, 7, 7
+	seen := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		seen[pickCandidate(cs).location.Name] = true
+	}
+	var names []string
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	require.Equal(t, []string{"b", "c"}, names, "ties are broken randomly")
+}
+
+func TestWebhookExtender(t *testing.T) {
+	var gotArgs ExtenderArgs
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		require.Equal(t, http.MethodPost, req.Method)
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&gotArgs))
+
+		switch req.URL.Path {
+		case "/scheduler/filter":
+			json.NewEncoder(w).Encode(&ExtenderFilterResult{Locations: []string{"a"}, FailedLocations: map[string]string{"b": "too expensive"}}) // nolint:errcheck
+		case "/scheduler/prioritize":
+			json.NewEncoder(w).Encode(&ExtenderPriorityResult{Scores: []LocationScore{{Location: "a", Score: 4}}}) // nolint:errcheck
+		case "/scheduler/broken":
+			json.NewEncoder(w).Encode(&ExtenderPriorityResult{Error: "no data"}) // nolint:errcheck
+		default:
+			http.NotFound(w, req)
+		}
+	}))
+	defer server.Close()
+
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test", Labels: map[string]string{"team": "a"}}}
+	args := extenderArgs(logicalcluster.New("root:org:ws"), ns, candidates("a", "b"))
+
+	e := &WebhookExtender{ExtenderName: "cost", URLPrefix: server.URL + "/scheduler/", FilterVerb: "filter", PrioritizeVerb: "prioritize", Client: server.Client()}
+
+	filtered, err := e.Filter(context.Background(), args)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a"}, filtered.Locations)
+	require.Equal(t, "too expensive", filtered.FailedLocations["b"])
+	require.Equal(t, "root:org:ws", gotArgs.Namespace.ClusterName)
+	require.Equal(t, map[string]string{"team": "a"}, gotArgs.Namespace.Labels)
+	require.Equal(t, []ExtenderLocation{
+		{ClusterName: "root:org:negotiation", Name: "a", WorkloadClusters: []string{"a-1"}},
+		{ClusterName: "root:org:negotiation", Name: "b", WorkloadClusters: []string{"b-1"}},
+	}, gotArgs.Locations)
+
+	prioritized, err := e.Prioritize(context.Background(), args)
+	require.NoError(t, err)
+	require.Equal(t, []LocationScore{{Location: "a", Score: 4}}, prioritized.Scores)
+
+	e.PrioritizeVerb = "broken"
+	_, err = e.Prioritize(context.Background(), args)
+	require.Error(t, err)
+
+	e.FilterVerb = "unknown"
+	_, err = e.Filter(context.Background(), args)
+	require.Error(t, err)
+
+	e.FilterVerb = ""
+	filtered, err = e.Filter(context.Background(), args)
+	require.NoError(t, err)
+	require.Nil(t, filtered)
+}
@@ -49,6 +49,7 @@ import (
 	apislisters "github.com/kcp-dev/kcp/pkg/client/listers/apis/v1alpha1"
 	schedulinglisters "github.com/kcp-dev/kcp/pkg/client/listers/scheduling/v1alpha1"
 	workloadlisters "github.com/kcp-dev/kcp/pkg/client/listers/workload/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/reconciler/latency"
 	"github.com/kcp-dev/kcp/third_party/conditions/util/conditions"
 )
 
@@ -68,8 +69,9 @@ func NewController(
 	apiBindingInformer apisinformers.APIBindingInformer,
 	locationInformer schedulinginformers.LocationInformer,
 	workloadClusterInformer workloadinformers.WorkloadClusterInformer,
+	extenders []Extender,
 ) (*controller, error) {
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+	queue := latency.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
 
 	c := &controller{
 		queue: queue,
@@ -92,6 +94,8 @@ func NewController(
 
 		workloadClusterLister:  workloadClusterInformer.Lister(),
 		workloadClusterIndexer: workloadClusterInformer.Informer().GetIndexer(),
+
+		extenders: extenders,
 	}
 
 	if err := apiBindingInformer.Informer().AddIndexers(cache.Indexers{
@@ -182,6 +186,8 @@ type controller struct {
 
 	workloadClusterLister  workloadlisters.WorkloadClusterLister
 	workloadClusterIndexer cache.Indexer
+
+	extenders []Extender
 }
 
 // enqueueLocationDomain enqueues all namespaces.
@@ -59,6 +59,9 @@ type placementReconciler struct {
 	listWorkloadClusters func(clusterName logicalcluster.Name) ([]*workloadv1alpha1.WorkloadCluster, error)
 	patchNamespace       func(ctx context.Context, clusterName logicalcluster.Name, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (*corev1.Namespace, error)
 
+	// extenders veto and score the locations, in this order.
+	extenders []Extender
+
 	enqueueAfter func(logicalcluster.Name, *corev1.Namespace, time.Duration)
 }
 
@@ -134,12 +137,9 @@ func (r *placementReconciler) reconcile(ctx context.Context, ns *corev1.Namespac
 		return reconcileStatusStop, err
 	}
 
-	perm := rand.Perm(len(locationsByWorkspace[negotiationClusterName]))
 	var lastErr error
-	var chosenClusters []*workloadv1alpha1.WorkloadCluster
-	var chosenLocationName string
-	for i := range locations {
-		l := locations[perm[i]]
+	var candidates []*candidate
+	for _, l := range locations {
 		locationClusters, err := locationreconciler.LocationWorkloadClusters(workloadClusters, l)
 		if err != nil {
 			lastErr = fmt.Errorf("failed to get location %s|%s WorkloadClusters: %w", negotiationClusterName, l.Name, err)
@@ -149,20 +149,30 @@ func (r *placementReconciler) reconcile(ctx context.Context, ns *corev1.Namespac
 		if len(ready) == 0 {
 			continue
 		}
-		chosenLocationName = l.Name
-		chosenClusters = ready
-		// We can stop searching as we found ready clusters
-		break
+		candidates = append(candidates, &candidate{clusterName: negotiationClusterName, location: l, clusters: ready})
 	}
-	if chosenLocationName == "" {
+	if len(candidates) == 0 {
 		// TODO(sttts): come up with some both quicker rescheduling initially, but also some backoff when scheduling fails again
 		klog.V(2).Infof("Requeuing after 30s, failed to schedule Namespace %s|%s against locations in %s. No ready clusters: %v", clusterName, ns.Name, negotiationClusterName, lastErr)
 		r.enqueueAfter(clusterName, ns, time.Second*30)
 		return reconcileStatusContinue, nil
 	}
 
+	candidates, err = runExtenders(ctx, r.extenders, clusterName, ns, candidates)
+	if err != nil {
+		klog.Errorf("failed to schedule Namespace %s|%s against locations in %s: %v", clusterName, ns.Name, negotiationClusterName, err)
+		return reconcileStatusStop, err
+	}
+	if len(candidates) == 0 {
+		klog.V(2).Infof("Requeuing after 30s, failed to schedule Namespace %s|%s against locations in %s. All locations vetoed by extenders", clusterName, ns.Name, negotiationClusterName)
+		r.enqueueAfter(clusterName, ns, time.Second*30)
+		return reconcileStatusContinue, nil
+	}
+	chosen := pickCandidate(candidates)
+	chosenLocationName := chosen.location.Name
+
 	// TODO(sttts): be more clever than just random: follow allocable, co-location workspace and workloads, load-balance, etcd.
-	chosenCluster := chosenClusters[rand.Intn(len(chosenClusters))]
+	chosenCluster := chosen.clusters[rand.Intn(len(chosen.clusters))]
 
 	placementUID := fmt.Sprintf("%s+%s", chosenLocationName, chosenCluster.UID)
 	newPlacement := schedulingv1alpha1.PlacementAnnotation{
@@ -199,6 +209,7 @@ func (c *controller) reconcile(ctx context.Context, ns *corev1.Namespace) error
 			listLocations:        c.listLocations,
 			listWorkloadClusters: c.listWorkloadClusters,
 			patchNamespace:       c.patchNamespace,
+			extenders:            c.extenders,
 			enqueueAfter:         c.enqueueAfter,
 		},
 	}
@@ -42,6 +42,7 @@ func TestPlacementReconciler(t *testing.T) {
 		locations        map[logicalcluster.Name][]*schedulingv1alpha1.Location
 		workloadClusters map[logicalcluster.Name][]*workloadv1alpha1.WorkloadCluster
 		namespace        *corev1.Namespace
+		extenders        []Extender
 
 		listLocationsError        error
 		listAPIBindingsError      error
@@ -170,6 +171,84 @@ func TestPlacementReconciler(t *testing.T) {
 			wantPatch:           `{"metadata":{"annotations":{"scheduling.kcp.dev/placement":"{\"us-east1+uid-3\":\"Pending\"}"}}}`,
 			wantReconcileStatus: reconcileStatusContinue,
 		},
+		"extender prefers a location": {
+			namespace: &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test",
+					ClusterName: "root:org:ws",
+				},
+			},
+			apibindings: map[logicalcluster.Name][]*apisv1alpha1.APIBinding{logicalcluster.New("root:org:ws"): {
+				bound(validExport(binding("kubernetes", "negotiation-workspace"))),
+			}},
+			locations: map[logicalcluster.Name][]*schedulingv1alpha1.Location{logicalcluster.New("root:org:negotiation-workspace"): {
+				withInstances(location("us-east1"), map[string]string{"region": "us-east1"}),
+				withInstances(location("us-west1"), map[string]string{"region": "us-west1"}),
+			}},
+			workloadClusters: map[logicalcluster.Name][]*workloadv1alpha1.WorkloadCluster{
+				logicalcluster.New("root:org:negotiation-workspace"): {
+					withLabels(withConditions(cluster("us-east1-1", "uid-1"), conditionsv1alpha1.Condition{Type: "Ready", Status: "True"}), map[string]string{"region": "us-east1"}),
+					withLabels(withConditions(cluster("us-west1-1", "uid-11"), conditionsv1alpha1.Condition{Type: "Ready", Status: "True"}), map[string]string{"region": "us-west1"}),
+				},
+			},
+			extenders: []Extender{
+				&fakeExtender{name: "cost", weight: 1, scores: map[string]int64{"us-east1": 2, "us-west1": 7}},
+			},
+			wantPatch:           `{"metadata":{"annotations":{"scheduling.kcp.dev/placement":"{\"us-west1+uid-11\":\"Pending\"}"}}}`,
+			wantReconcileStatus: reconcileStatusContinue,
+		},
+		"extender vetoes all locations": {
+			namespace: &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test",
+					ClusterName: "root:org:ws",
+				},
+			},
+			apibindings: map[logicalcluster.Name][]*apisv1alpha1.APIBinding{logicalcluster.New("root:org:ws"): {
+				bound(validExport(binding("kubernetes", "negotiation-workspace"))),
+			}},
+			locations: map[logicalcluster.Name][]*schedulingv1alpha1.Location{logicalcluster.New("root:org:negotiation-workspace"): {
+				withInstances(location("us-east1"), map[string]string{"region": "us-east1"}),
+				withInstances(location("us-west1"), map[string]string{"region": "us-west1"}),
+			}},
+			workloadClusters: map[logicalcluster.Name][]*workloadv1alpha1.WorkloadCluster{
+				logicalcluster.New("root:org:negotiation-workspace"): {
+					withLabels(withConditions(cluster("us-east1-1", "uid-1"), conditionsv1alpha1.Condition{Type: "Ready", Status: "True"}), map[string]string{"region": "us-east1"}),
+					withLabels(withConditions(cluster("us-west1-1", "uid-11"), conditionsv1alpha1.Condition{Type: "Ready", Status: "True"}), map[string]string{"region": "us-west1"}),
+				},
+			},
+			extenders: []Extender{
+				&fakeExtender{name: "compliance", keep: []string{}},
+			},
+			wantRequeue:         time.Second * 30,
+			wantReconcileStatus: reconcileStatusContinue,
+		},
+		"extender fails": {
+			namespace: &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test",
+					ClusterName: "root:org:ws",
+				},
+			},
+			apibindings: map[logicalcluster.Name][]*apisv1alpha1.APIBinding{logicalcluster.New("root:org:ws"): {
+				bound(validExport(binding("kubernetes", "negotiation-workspace"))),
+			}},
+			locations: map[logicalcluster.Name][]*schedulingv1alpha1.Location{logicalcluster.New("root:org:negotiation-workspace"): {
+				withInstances(location("us-east1"), map[string]string{"region": "us-east1"}),
+				withInstances(location("us-west1"), map[string]string{"region": "us-west1"}),
+			}},
+			workloadClusters: map[logicalcluster.Name][]*workloadv1alpha1.WorkloadCluster{
+				logicalcluster.New("root:org:negotiation-workspace"): {
+					withLabels(withConditions(cluster("us-east1-1", "uid-1"), conditionsv1alpha1.Condition{Type: "Ready", Status: "True"}), map[string]string{"region": "us-east1"}),
+					withLabels(withConditions(cluster("us-west1-1", "uid-11"), conditionsv1alpha1.Condition{Type: "Ready", Status: "True"}), map[string]string{"region": "us-west1"}),
+				},
+			},
+			extenders: []Extender{
+				&fakeExtender{name: "compliance", err: fmt.Errorf("boom")},
+			},
+			wantError:           true,
+			wantReconcileStatus: reconcileStatusStop,
+		},
 		"patch fails": {
 			namespace: &corev1.Namespace{
 				ObjectMeta: metav1.ObjectMeta{
@@ -224,6 +303,7 @@ func TestPlacementReconciler(t *testing.T) {
 					gotPatch = string(data)
 					return &corev1.Namespace{}, nil
 				},
+				extenders: tc.extenders,
 				enqueueAfter: func(clusterName logicalcluster.Name, ns *corev1.Namespace, duration time.Duration) {
 					requeuedAfter = duration
 				},
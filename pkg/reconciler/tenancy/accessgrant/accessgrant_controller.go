@@ -0,0 +1,293 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accessgrant
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/kcp-dev/logicalcluster"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	rbacinformers "k8s.io/client-go/informers/rbac/v1"
+	"k8s.io/client-go/kubernetes"
+	rbaclisters "k8s.io/client-go/listers/rbac/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clusters"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	kcpclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
+	tenancyinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/tenancy/v1alpha1"
+	tenancylisters "github.com/kcp-dev/kcp/pkg/client/listers/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/reconciler/latency"
+)
+
+const (
+	controllerName = "kcp-accessgrant"
+
+	// AccessGrantLabel is set on the bindings created for an AccessGrant, with the
+	// name of the grant as value.
+	AccessGrantLabel = "tenancy.kcp.dev/access-grant"
+	// AccessGrantExpiresAtAnnotation is set on the bindings created for an AccessGrant
+	// with the expiry of the grant in RFC3339 format.
+	AccessGrantExpiresAtAnnotation = "tenancy.kcp.dev/access-grant-expires-at"
+	// AccessGrantReasonAnnotation is set on the bindings created for an AccessGrant
+	// with the reason of the grant, such that it shows up in the audit log of the binding.
+	AccessGrantReasonAnnotation = "tenancy.kcp.dev/access-grant-reason"
+	// AccessGrantFinalizer makes sure the binding of an AccessGrant is removed
+	// before the grant is deleted.
+	AccessGrantFinalizer = "tenancy.kcp.dev/access-grant"
+)
+
+// NewController returns a new controller that creates the RoleBindings and ClusterRoleBindings
+// of AccessGrants, and removes them when the grants expire or are deleted.
+func NewController(
+	kubeClusterClient kubernetes.ClusterInterface,
+	kcpClusterClient kcpclient.ClusterInterface,
+	accessGrantInformer tenancyinformers.AccessGrantInformer,
+	clusterRoleBindingInformer rbacinformers.ClusterRoleBindingInformer,
+	roleBindingInformer rbacinformers.RoleBindingInformer,
+) (*controller, error) {
+	queue := latency.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+
+	c := &controller{
+		queue: queue,
+		enqueueAfter: func(grant *tenancyv1alpha1.AccessGrant, duration time.Duration) {
+			key := clusters.ToClusterAwareKey(logicalcluster.From(grant), grant.Name)
+			queue.AddAfter(key, duration)
+		},
+		kubeClusterClient:        kubeClusterClient,
+		kcpClusterClient:         kcpClusterClient,
+		accessGrantLister:        accessGrantInformer.Lister(),
+		clusterRoleBindingLister: clusterRoleBindingInformer.Lister(),
+		roleBindingLister:        roleBindingInformer.Lister(),
+	}
+
+	accessGrantInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueueAccessGrant(obj) },
+		UpdateFunc: func(_, obj interface{}) { c.enqueueAccessGrant(obj) },
+		DeleteFunc: func(obj interface{}) { c.enqueueAccessGrant(obj) },
+	})
+
+	// bring back bindings which are modified or deleted behind our back.
+	bindingHandler := cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj interface{}) bool {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			metaObj, ok := obj.(metav1.Object)
+			if !ok {
+				return false
+			}
+			_, found := metaObj.GetLabels()[AccessGrantLabel]
+			return found
+		},
+		Handler: cache.ResourceEventHandlerFuncs{
+			UpdateFunc: func(_, obj interface{}) { c.enqueueBinding(obj) },
+			DeleteFunc: func(obj interface{}) { c.enqueueBinding(obj) },
+		},
+	}
+	clusterRoleBindingInformer.Informer().AddEventHandler(bindingHandler)
+	roleBindingInformer.Informer().AddEventHandler(bindingHandler)
+
+	return c, nil
+}
+
+// controller reconciles AccessGrants into RoleBindings and ClusterRoleBindings.
+type controller struct {
+	queue        workqueue.RateLimitingInterface
+	enqueueAfter func(*tenancyv1alpha1.AccessGrant, time.Duration)
+
+	kubeClusterClient kubernetes.ClusterInterface
+	kcpClusterClient  kcpclient.ClusterInterface
+
+	accessGrantLister        tenancylisters.AccessGrantLister
+	clusterRoleBindingLister rbaclisters.ClusterRoleBindingLister
+	roleBindingLister        rbaclisters.RoleBindingLister
+}
+
+func (c *controller) enqueueAccessGrant(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	klog.Infof("Queueing AccessGrant %q", key)
+	c.queue.Add(key)
+}
+
+// enqueueBinding maps a binding to the AccessGrant it was created for.
+func (c *controller) enqueueBinding(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	metaObj, ok := obj.(metav1.Object)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("unexpected object type %T", obj))
+		return
+	}
+
+	key := clusters.ToClusterAwareKey(logicalcluster.From(metaObj), metaObj.GetLabels()[AccessGrantLabel])
+	klog.Infof("Queueing AccessGrant %q because of binding %s|%s/%s", key, logicalcluster.From(metaObj), metaObj.GetNamespace(), metaObj.GetName())
+	c.queue.Add(key)
+}
+
+// Start starts the controller, which stops when ctx.Done() is closed.
+func (c *controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	klog.Infof("Starting %s controller", controllerName)
+	defer klog.Infof("Shutting down %s controller", controllerName)
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+
+	<-ctx.Done()
+}
+
+func (c *controller) startWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	if err := c.process(ctx, key); err != nil {
+		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", controllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *controller) process(ctx context.Context, key string) error {
+	_, clusterAwareName, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		klog.Errorf("invalid key: %q: %v", key, err)
+		return nil
+	}
+	clusterName, name := clusters.SplitClusterAwareKey(clusterAwareName)
+
+	obj, err := c.accessGrantLister.Get(key)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil // object deleted before we handled it
+		}
+		return err
+	}
+	old := obj
+	obj = obj.DeepCopy()
+
+	if err := c.reconcile(ctx, obj); err != nil {
+		return err
+	}
+
+	// If the object being reconciled changed as a result, update it.
+	if !equality.Semantic.DeepEqual(old.Status, obj.Status) {
+		oldData, err := json.Marshal(tenancyv1alpha1.AccessGrant{
+			Status: old.Status,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to Marshal old data for AccessGrant %s|%s: %w", clusterName, name, err)
+		}
+
+		newData, err := json.Marshal(tenancyv1alpha1.AccessGrant{
+			ObjectMeta: metav1.ObjectMeta{
+				UID:             obj.UID,
+				ResourceVersion: obj.ResourceVersion,
+			}, // to ensure they appear in the patch as preconditions
+			Status: obj.Status,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to Marshal new data for AccessGrant %s|%s: %w", clusterName, name, err)
+		}
+
+		patchBytes, err := jsonpatch.CreateMergePatch(oldData, newData)
+		if err != nil {
+			return fmt.Errorf("failed to create patch for AccessGrant %s|%s: %w", clusterName, name, err)
+		}
+		_, uerr := c.kcpClusterClient.Cluster(clusterName).TenancyV1alpha1().AccessGrants().Patch(ctx, obj.Name, types.MergePatchType, patchBytes, metav1.PatchOptions{}, "status")
+		return uerr
+	}
+
+	return nil
+}
+
+func (c *controller) getClusterRoleBinding(clusterName logicalcluster.Name, name string) (*rbacv1.ClusterRoleBinding, error) {
+	return c.clusterRoleBindingLister.Get(clusters.ToClusterAwareKey(clusterName, name))
+}
+
+func (c *controller) createClusterRoleBinding(ctx context.Context, clusterName logicalcluster.Name, binding *rbacv1.ClusterRoleBinding) error {
+	_, err := c.kubeClusterClient.Cluster(clusterName).RbacV1().ClusterRoleBindings().Create(ctx, binding, metav1.CreateOptions{})
+	return err
+}
+
+func (c *controller) updateClusterRoleBinding(ctx context.Context, clusterName logicalcluster.Name, binding *rbacv1.ClusterRoleBinding) error {
+	_, err := c.kubeClusterClient.Cluster(clusterName).RbacV1().ClusterRoleBindings().Update(ctx, binding, metav1.UpdateOptions{})
+	return err
+}
+
+func (c *controller) deleteClusterRoleBinding(ctx context.Context, clusterName logicalcluster.Name, name string) error {
+	return c.kubeClusterClient.Cluster(clusterName).RbacV1().ClusterRoleBindings().Delete(ctx, name, metav1.DeleteOptions{})
+}
+
+func (c *controller) getRoleBinding(clusterName logicalcluster.Name, namespace, name string) (*rbacv1.RoleBinding, error) {
+	return c.roleBindingLister.RoleBindings(namespace).Get(clusters.ToClusterAwareKey(clusterName, name))
+}
+
+func (c *controller) createRoleBinding(ctx context.Context, clusterName logicalcluster.Name, binding *rbacv1.RoleBinding) error {
+	_, err := c.kubeClusterClient.Cluster(clusterName).RbacV1().RoleBindings(binding.Namespace).Create(ctx, binding, metav1.CreateOptions{})
+	return err
+}
+
+func (c *controller) updateRoleBinding(ctx context.Context, clusterName logicalcluster.Name, binding *rbacv1.RoleBinding) error {
+	_, err := c.kubeClusterClient.Cluster(clusterName).RbacV1().RoleBindings(binding.Namespace).Update(ctx, binding, metav1.UpdateOptions{})
+	return err
+}
+
+func (c *controller) deleteRoleBinding(ctx context.Context, clusterName logicalcluster.Name, namespace, name string) error {
+	return c.kubeClusterClient.Cluster(clusterName).RbacV1().RoleBindings(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+}
+
+func (c *controller) updateAccessGrant(ctx context.Context, grant *tenancyv1alpha1.AccessGrant) (*tenancyv1alpha1.AccessGrant, error) {
+	return c.kcpClusterClient.Cluster(logicalcluster.From(grant)).TenancyV1alpha1().AccessGrants().Update(ctx, grant, metav1.UpdateOptions{})
+}
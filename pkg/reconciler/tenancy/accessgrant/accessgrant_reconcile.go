@@ -0,0 +1,318 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accessgrant
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilserrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/third_party/conditions/util/conditions"
+)
+
+type reconcileStatus int
+
+const (
+	reconcileStatusStop reconcileStatus = iota
+	reconcileStatusContinue
+)
+
+type reconciler interface {
+	reconcile(ctx context.Context, grant *tenancyv1alpha1.AccessGrant) (reconcileStatus, error)
+}
+
+// BindingName returns the name of the RoleBinding or ClusterRoleBinding created for the grant.
+func BindingName(grant *tenancyv1alpha1.AccessGrant) string {
+	return "accessgrant-" + grant.Name
+}
+
+// finalizerReconciler removes the binding of deleted grants and adds the finalizer to all others.
+type finalizerReconciler struct {
+	deleteBinding     func(ctx context.Context, grant *tenancyv1alpha1.AccessGrant) error
+	updateAccessGrant func(ctx context.Context, grant *tenancyv1alpha1.AccessGrant) (*tenancyv1alpha1.AccessGrant, error)
+}
+
+func (r *finalizerReconciler) reconcile(ctx context.Context, grant *tenancyv1alpha1.AccessGrant) (reconcileStatus, error) {
+	hasFinalizer := false
+	var otherFinalizers []string
+	for _, f := range grant.Finalizers {
+		if f == AccessGrantFinalizer {
+			hasFinalizer = true
+			continue
+		}
+		otherFinalizers = append(otherFinalizers, f)
+	}
+
+	if !grant.DeletionTimestamp.IsZero() {
+		if !hasFinalizer {
+			return reconcileStatusStop, nil
+		}
+		if err := r.deleteBinding(ctx, grant); err != nil {
+			return reconcileStatusStop, err
+		}
+		klog.Infof("Removed binding %s of deleted AccessGrant %s|%s", BindingName(grant), logicalcluster.From(grant), grant.Name)
+
+		grant.Finalizers = otherFinalizers
+		_, err := r.updateAccessGrant(ctx, grant)
+		return reconcileStatusStop, err
+	}
+
+	if hasFinalizer {
+		return reconcileStatusContinue, nil
+	}
+
+	grant.Finalizers = append(grant.Finalizers, AccessGrantFinalizer)
+	updated, err := r.updateAccessGrant(ctx, grant)
+	if err != nil {
+		return reconcileStatusStop, err
+	}
+	updated.Status = grant.Status
+	*grant = *updated
+
+	return reconcileStatusContinue, nil
+}
+
+// bindingReconciler creates or updates the binding of active grants, and removes it when the grant expires.
+type bindingReconciler struct {
+	getClusterRoleBinding    func(clusterName logicalcluster.Name, name string) (*rbacv1.ClusterRoleBinding, error)
+	createClusterRoleBinding func(ctx context.Context, clusterName logicalcluster.Name, binding *rbacv1.ClusterRoleBinding) error
+	updateClusterRoleBinding func(ctx context.Context, clusterName logicalcluster.Name, binding *rbacv1.ClusterRoleBinding) error
+	getRoleBinding           func(clusterName logicalcluster.Name, namespace, name string) (*rbacv1.RoleBinding, error)
+	createRoleBinding        func(ctx context.Context, clusterName logicalcluster.Name, binding *rbacv1.RoleBinding) error
+	updateRoleBinding        func(ctx context.Context, clusterName logicalcluster.Name, binding *rbacv1.RoleBinding) error
+	deleteBinding            func(ctx context.Context, grant *tenancyv1alpha1.AccessGrant) error
+
+	enqueueAfter func(*tenancyv1alpha1.AccessGrant, time.Duration)
+	now          func() time.Time
+}
+
+func (r *bindingReconciler) reconcile(ctx context.Context, grant *tenancyv1alpha1.AccessGrant) (reconcileStatus, error) {
+	clusterName := logicalcluster.From(grant)
+	now := r.now()
+
+	if !now.Before(grant.Spec.ExpiresAt.Time) {
+		if err := r.deleteBinding(ctx, grant); err != nil {
+			return reconcileStatusStop, err
+		}
+		if grant.Status.Phase != tenancyv1alpha1.AccessGrantPhaseExpired {
+			klog.Infof("AccessGrant %s|%s expired at %s, removed binding %s", clusterName, grant.Name, grant.Spec.ExpiresAt.UTC().Format(time.RFC3339), BindingName(grant))
+		}
+		grant.Status.Phase = tenancyv1alpha1.AccessGrantPhaseExpired
+		conditions.MarkFalse(grant, tenancyv1alpha1.AccessGrantBound, tenancyv1alpha1.AccessGrantExpiredReason, conditionsv1alpha1.ConditionSeverityInfo,
+			"The grant expired at %s", grant.Spec.ExpiresAt.UTC().Format(time.RFC3339))
+		return reconcileStatusContinue, nil
+	}
+
+	var err error
+	if grant.Spec.Namespace == "" {
+		err = r.ensureClusterRoleBinding(ctx, clusterName, grant)
+	} else {
+		err = r.ensureRoleBinding(ctx, clusterName, grant)
+	}
+	if err != nil {
+		conditions.MarkFalse(grant, tenancyv1alpha1.AccessGrantBound, tenancyv1alpha1.AccessGrantBindingFailedReason, conditionsv1alpha1.ConditionSeverityError,
+			"Failed to create binding %s: %v", BindingName(grant), err)
+		return reconcileStatusStop, err
+	}
+
+	grant.Status.Phase = tenancyv1alpha1.AccessGrantPhaseActive
+	grant.Status.BindingName = BindingName(grant)
+	conditions.MarkTrue(grant, tenancyv1alpha1.AccessGrantBound)
+
+	// come back when the grant expires
+	r.enqueueAfter(grant, grant.Spec.ExpiresAt.Sub(now))
+
+	return reconcileStatusContinue, nil
+}
+
+func bindingObjectMeta(grant *tenancyv1alpha1.AccessGrant) metav1.ObjectMeta {
+	meta := metav1.ObjectMeta{
+		Name:      BindingName(grant),
+		Namespace: grant.Spec.Namespace,
+		Labels: map[string]string{
+			AccessGrantLabel: grant.Name,
+		},
+		Annotations: map[string]string{
+			AccessGrantExpiresAtAnnotation: grant.Spec.ExpiresAt.UTC().Format(time.RFC3339),
+		},
+	}
+	if grant.Spec.Reason != "" {
+		meta.Annotations[AccessGrantReasonAnnotation] = grant.Spec.Reason
+	}
+	return meta
+}
+
+func (r *bindingReconciler) ensureClusterRoleBinding(ctx context.Context, clusterName logicalcluster.Name, grant *tenancyv1alpha1.AccessGrant) error {
+	desired := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: bindingObjectMeta(grant),
+		Subjects:   grant.Spec.Subjects,
+		RoleRef:    grant.Spec.RoleRef,
+	}
+
+	existing, err := r.getClusterRoleBinding(clusterName, desired.Name)
+	if errors.IsNotFound(err) {
+		return r.createClusterRoleBinding(ctx, clusterName, desired)
+	} else if err != nil {
+		return err
+	}
+
+	if existing.Labels[AccessGrantLabel] != grant.Name {
+		return fmt.Errorf("ClusterRoleBinding %s exists and does not belong to the grant", desired.Name)
+	}
+	if existing.RoleRef != desired.RoleRef {
+		return fmt.Errorf("ClusterRoleBinding %s references a different role", desired.Name)
+	}
+	if equality.Semantic.DeepEqual(existing.Subjects, desired.Subjects) &&
+		existing.Annotations[AccessGrantExpiresAtAnnotation] == desired.Annotations[AccessGrantExpiresAtAnnotation] &&
+		existing.Annotations[AccessGrantReasonAnnotation] == desired.Annotations[AccessGrantReasonAnnotation] {
+		return nil
+	}
+
+	updated := existing.DeepCopy()
+	updated.Subjects = desired.Subjects
+	if updated.Annotations == nil {
+		updated.Annotations = map[string]string{}
+	}
+	updated.Annotations[AccessGrantExpiresAtAnnotation] = desired.Annotations[AccessGrantExpiresAtAnnotation]
+	if reason, ok := desired.Annotations[AccessGrantReasonAnnotation]; ok {
+		updated.Annotations[AccessGrantReasonAnnotation] = reason
+	} else {
+		delete(updated.Annotations, AccessGrantReasonAnnotation)
+	}
+	return r.updateClusterRoleBinding(ctx, clusterName, updated)
+}
+
+func (r *bindingReconciler) ensureRoleBinding(ctx context.Context, clusterName logicalcluster.Name, grant *tenancyv1alpha1.AccessGrant) error {
+	desired := &rbacv1.RoleBinding{
+		ObjectMeta: bindingObjectMeta(grant),
+		Subjects:   grant.Spec.Subjects,
+		RoleRef:    grant.Spec.RoleRef,
+	}
+
+	existing, err := r.getRoleBinding(clusterName, desired.Namespace, desired.Name)
+	if errors.IsNotFound(err) {
+		return r.createRoleBinding(ctx, clusterName, desired)
+	} else if err != nil {
+		return err
+	}
+
+	if existing.Labels[AccessGrantLabel] != grant.Name {
+		return fmt.Errorf("RoleBinding %s/%s exists and does not belong to the grant", desired.Namespace, desired.Name)
+	}
+	if existing.RoleRef != desired.RoleRef {
+		return fmt.Errorf("RoleBinding %s/%s references a different role", desired.Namespace, desired.Name)
+	}
+	if equality.Semantic.DeepEqual(existing.Subjects, desired.Subjects) &&
+		existing.Annotations[AccessGrantExpiresAtAnnotation] == desired.Annotations[AccessGrantExpiresAtAnnotation] &&
+		existing.Annotations[AccessGrantReasonAnnotation] == desired.Annotations[AccessGrantReasonAnnotation] {
+		return nil
+	}
+
+	updated := existing.DeepCopy()
+	updated.Subjects = desired.Subjects
+	if updated.Annotations == nil {
+		updated.Annotations = map[string]string{}
+	}
+	updated.Annotations[AccessGrantExpiresAtAnnotation] = desired.Annotations[AccessGrantExpiresAtAnnotation]
+	if reason, ok := desired.Annotations[AccessGrantReasonAnnotation]; ok {
+		updated.Annotations[AccessGrantReasonAnnotation] = reason
+	} else {
+		delete(updated.Annotations, AccessGrantReasonAnnotation)
+	}
+	return r.updateRoleBinding(ctx, clusterName, updated)
+}
+
+func (c *controller) reconcile(ctx context.Context, grant *tenancyv1alpha1.AccessGrant) error {
+	reconcilers := []reconciler{
+		&finalizerReconciler{
+			deleteBinding:     c.deleteBinding,
+			updateAccessGrant: c.updateAccessGrant,
+		},
+		&bindingReconciler{
+			getClusterRoleBinding:    c.getClusterRoleBinding,
+			createClusterRoleBinding: c.createClusterRoleBinding,
+			updateClusterRoleBinding: c.updateClusterRoleBinding,
+			getRoleBinding:           c.getRoleBinding,
+			createRoleBinding:        c.createRoleBinding,
+			updateRoleBinding:        c.updateRoleBinding,
+			deleteBinding:            c.deleteBinding,
+			enqueueAfter:             c.enqueueAfter,
+			now:                      time.Now,
+		},
+	}
+
+	var errs []error
+
+	for _, r := range reconcilers {
+		status, err := r.reconcile(ctx, grant)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if status == reconcileStatusStop {
+			break
+		}
+	}
+
+	return utilserrors.NewAggregate(errs)
+}
+
+// deleteBinding removes the binding of the grant, if it exists and belongs to the grant.
+func (c *controller) deleteBinding(ctx context.Context, grant *tenancyv1alpha1.AccessGrant) error {
+	clusterName := logicalcluster.From(grant)
+	name := BindingName(grant)
+
+	if grant.Spec.Namespace == "" {
+		existing, err := c.getClusterRoleBinding(clusterName, name)
+		if errors.IsNotFound(err) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if existing.Labels[AccessGrantLabel] != grant.Name {
+			return nil
+		}
+		if err := c.deleteClusterRoleBinding(ctx, clusterName, name); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	existing, err := c.getRoleBinding(clusterName, grant.Spec.Namespace, name)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if existing.Labels[AccessGrantLabel] != grant.Name {
+		return nil
+	}
+	if err := c.deleteRoleBinding(ctx, clusterName, grant.Spec.Namespace, name); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
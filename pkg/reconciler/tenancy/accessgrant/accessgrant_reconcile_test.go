@@ -0,0 +1,205 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accessgrant
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/third_party/conditions/util/conditions"
+)
+
+func TestBindingReconciler(t *testing.T) {
+	now := time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	grant := func(namespace string, expiresAt time.Time) *tenancyv1alpha1.AccessGrant {
+		return &tenancyv1alpha1.AccessGrant{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "support",
+				ClusterName: "root:org:ws",
+			},
+			Spec: tenancyv1alpha1.AccessGrantSpec{
+				Subjects:  []rbacv1.Subject{{Kind: "User", Name: "alice"}},
+				RoleRef:   rbacv1.RoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "ClusterRole", Name: "admin"},
+				Namespace: namespace,
+				ExpiresAt: metav1.NewTime(expiresAt),
+				Reason:    "TICKET-123",
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		grant                *tenancyv1alpha1.AccessGrant
+		clusterRoleBindings  map[string]*rbacv1.ClusterRoleBinding
+		roleBindings         map[string]*rbacv1.RoleBinding
+		wantPhase            tenancyv1alpha1.AccessGrantPhaseType
+		wantBound            bool
+		wantErr              bool
+		wantClusterRoleBound bool
+		wantRoleBound        bool
+		wantRequeue          time.Duration
+	}{
+		"active grant creates cluster role binding": {
+			grant:                grant("", now.Add(time.Hour)),
+			wantPhase:            tenancyv1alpha1.AccessGrantPhaseActive,
+			wantBound:            true,
+			wantClusterRoleBound: true,
+			wantRequeue:          time.Hour,
+		},
+		"active grant with namespace creates role binding": {
+			grant:         grant("default", now.Add(time.Minute)),
+			wantPhase:     tenancyv1alpha1.AccessGrantPhaseActive,
+			wantBound:     true,
+			wantRoleBound: true,
+			wantRequeue:   time.Minute,
+		},
+		"expired grant removes binding": {
+			grant: grant("", now.Add(-time.Second)),
+			clusterRoleBindings: map[string]*rbacv1.ClusterRoleBinding{
+				"accessgrant-support": {ObjectMeta: metav1.ObjectMeta{Name: "accessgrant-support", Labels: map[string]string{AccessGrantLabel: "support"}}},
+			},
+			wantPhase: tenancyv1alpha1.AccessGrantPhaseExpired,
+		},
+		"foreign binding is not overwritten": {
+			grant: grant("", now.Add(time.Hour)),
+			clusterRoleBindings: map[string]*rbacv1.ClusterRoleBinding{
+				"accessgrant-support": {ObjectMeta: metav1.ObjectMeta{Name: "accessgrant-support"}},
+			},
+			wantErr:              true,
+			wantClusterRoleBound: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if tc.clusterRoleBindings == nil {
+				tc.clusterRoleBindings = map[string]*rbacv1.ClusterRoleBinding{}
+			}
+			if tc.roleBindings == nil {
+				tc.roleBindings = map[string]*rbacv1.RoleBinding{}
+			}
+			var requeue time.Duration
+
+			r := &bindingReconciler{
+				getClusterRoleBinding: func(clusterName logicalcluster.Name, name string) (*rbacv1.ClusterRoleBinding, error) {
+					if b, ok := tc.clusterRoleBindings[name]; ok {
+						return b, nil
+					}
+					return nil, errors.NewNotFound(rbacv1.Resource("clusterrolebindings"), name)
+				},
+				createClusterRoleBinding: func(ctx context.Context, clusterName logicalcluster.Name, binding *rbacv1.ClusterRoleBinding) error {
+					tc.clusterRoleBindings[binding.Name] = binding
+					return nil
+				},
+				updateClusterRoleBinding: func(ctx context.Context, clusterName logicalcluster.Name, binding *rbacv1.ClusterRoleBinding) error {
+					tc.clusterRoleBindings[binding.Name] = binding
+					return nil
+				},
+				getRoleBinding: func(clusterName logicalcluster.Name, namespace, name string) (*rbacv1.RoleBinding, error) {
+					if b, ok := tc.roleBindings[namespace+"/"+name]; ok {
+						return b, nil
+					}
+					return nil, errors.NewNotFound(rbacv1.Resource("rolebindings"), name)
+				},
+				createRoleBinding: func(ctx context.Context, clusterName logicalcluster.Name, binding *rbacv1.RoleBinding) error {
+					tc.roleBindings[binding.Namespace+"/"+binding.Name] = binding
+					return nil
+				},
+				updateRoleBinding: func(ctx context.Context, clusterName logicalcluster.Name, binding *rbacv1.RoleBinding) error {
+					tc.roleBindings[binding.Namespace+"/"+binding.Name] = binding
+					return nil
+				},
+				deleteBinding: func(ctx context.Context, grant *tenancyv1alpha1.AccessGrant) error {
+					delete(tc.clusterRoleBindings, BindingName(grant))
+					delete(tc.roleBindings, grant.Spec.Namespace+"/"+BindingName(grant))
+					return nil
+				},
+				enqueueAfter: func(_ *tenancyv1alpha1.AccessGrant, d time.Duration) { requeue = d },
+				now:          func() time.Time { return now },
+			}
+
+			_, err := r.reconcile(context.Background(), tc.grant)
+			if tc.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.Equal(t, tc.wantPhase, tc.grant.Status.Phase)
+			require.Equal(t, tc.wantBound, conditions.IsTrue(tc.grant, tenancyv1alpha1.AccessGrantBound))
+			require.Equal(t, tc.wantRequeue, requeue)
+			require.Equal(t, tc.wantClusterRoleBound, tc.clusterRoleBindings["accessgrant-support"] != nil)
+			require.Equal(t, tc.wantRoleBound, tc.roleBindings["default/accessgrant-support"] != nil)
+
+			if tc.wantClusterRoleBound && !tc.wantErr {
+				b := tc.clusterRoleBindings["accessgrant-support"]
+				require.Equal(t, tc.grant.Spec.Subjects, b.Subjects)
+				require.Equal(t, tc.grant.Spec.RoleRef, b.RoleRef)
+				require.Equal(t, "support", b.Labels[AccessGrantLabel])
+				require.Equal(t, "TICKET-123", b.Annotations[AccessGrantReasonAnnotation])
+			}
+			if tc.wantPhase == tenancyv1alpha1.AccessGrantPhaseExpired {
+				require.Equal(t, tenancyv1alpha1.AccessGrantExpiredReason, conditions.GetReason(tc.grant, tenancyv1alpha1.AccessGrantBound))
+				require.Equal(t, conditionsv1alpha1.ConditionSeverityInfo, *conditions.GetSeverity(tc.grant, tenancyv1alpha1.AccessGrantBound))
+			}
+		})
+	}
+}
+
+func TestFinalizerReconciler(t *testing.T) {
+	var deleted []string
+	var updated []*tenancyv1alpha1.AccessGrant
+	r := &finalizerReconciler{
+		deleteBinding: func(ctx context.Context, grant *tenancyv1alpha1.AccessGrant) error {
+			deleted = append(deleted, BindingName(grant))
+			return nil
+		},
+		updateAccessGrant: func(ctx context.Context, grant *tenancyv1alpha1.AccessGrant) (*tenancyv1alpha1.AccessGrant, error) {
+			updated = append(updated, grant.DeepCopy())
+			return grant.DeepCopy(), nil
+		},
+	}
+
+	grant := &tenancyv1alpha1.AccessGrant{
+		ObjectMeta: metav1.ObjectMeta{Name: "support", ClusterName: "root:org:ws", Finalizers: []string{"other"}},
+	}
+
+	status, err := r.reconcile(context.Background(), grant)
+	require.NoError(t, err)
+	require.Equal(t, reconcileStatusContinue, status)
+	require.Equal(t, []string{"other", AccessGrantFinalizer}, grant.Finalizers)
+	require.Len(t, updated, 1)
+	require.Empty(t, deleted)
+
+	deletionTimestamp := metav1.Now()
+	grant.DeletionTimestamp = &deletionTimestamp
+	status, err = r.reconcile(context.Background(), grant)
+	require.NoError(t, err)
+	require.Equal(t, reconcileStatusStop, status)
+	require.Equal(t, []string{"accessgrant-support"}, deleted)
+	require.Len(t, updated, 2)
+	require.Equal(t, []string{"other"}, updated[1].Finalizers)
+}
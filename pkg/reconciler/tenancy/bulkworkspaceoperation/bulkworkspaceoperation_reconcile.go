@@ -0,0 +1,129 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bulkworkspaceoperation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/third_party/conditions/util/conditions"
+)
+
+// batchSize is the number of workspaces the operation is executed for per reconciliation.
+// The results are recorded after every batch, such that the progress is visible, and little
+// work is repeated when kcp restarts.
+const batchSize = 50
+
+func (c *controller) reconcile(ctx context.Context, op *tenancyv1alpha1.BulkWorkspaceOperation) {
+	clusterName := logicalcluster.From(op)
+	total := len(op.Spec.Workspaces)
+	done := len(op.Status.Results)
+	if done >= total {
+		return
+	}
+
+	end := done + batchSize
+	if end > total {
+		end = total
+	}
+	for _, name := range op.Spec.Workspaces[done:end] {
+		result := tenancyv1alpha1.BulkWorkspaceOperationResult{Name: name}
+		if err := c.execute(ctx, clusterName, op, name); err != nil {
+			result.Error = err.Error()
+			op.Status.Failed++
+		} else {
+			op.Status.Succeeded++
+		}
+		op.Status.Results = append(op.Status.Results, result)
+	}
+
+	switch {
+	case end < total:
+		conditions.MarkFalse(op, tenancyv1alpha1.BulkWorkspaceOperationSucceeded, tenancyv1alpha1.BulkWorkspaceOperationInProgressReason, conditionsv1alpha1.ConditionSeverityInfo,
+			"Executed for %d of %d workspaces.", end, total)
+	case op.Status.Failed > 0:
+		conditions.MarkFalse(op, tenancyv1alpha1.BulkWorkspaceOperationSucceeded, tenancyv1alpha1.BulkWorkspaceOperationFailedReason, conditionsv1alpha1.ConditionSeverityError,
+			"Failed for %d of %d workspaces.", op.Status.Failed, total)
+	default:
+		conditions.MarkTrue(op, tenancyv1alpha1.BulkWorkspaceOperationSucceeded)
+	}
+}
+
+// execute executes the operation for the named workspace. It is idempotent, as the results of
+// a batch are lost if they cannot be recorded.
+func (c *controller) execute(ctx context.Context, clusterName logicalcluster.Name, op *tenancyv1alpha1.BulkWorkspaceOperation, name string) error {
+	switch op.Spec.Operation {
+	case tenancyv1alpha1.BulkWorkspaceOperationCreate:
+		workspace := &tenancyv1alpha1.ClusterWorkspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Annotations: map[string]string{tenancyv1alpha1.BulkWorkspaceOperationAnnotation: string(op.UID)},
+			},
+			Spec: tenancyv1alpha1.ClusterWorkspaceSpec{
+				Type: op.Spec.Type,
+			},
+		}
+		if len(op.Spec.Labels) > 0 {
+			workspace.Labels = make(map[string]string, len(op.Spec.Labels))
+			for k, v := range op.Spec.Labels {
+				workspace.Labels[k] = v
+			}
+		}
+		err := c.createWorkspace(ctx, clusterName, workspace)
+		if errors.IsAlreadyExists(err) {
+			// created by an earlier execution whose result was not recorded?
+			if existing, getErr := c.getWorkspace(ctx, clusterName, name); getErr == nil && existing.Annotations[tenancyv1alpha1.BulkWorkspaceOperationAnnotation] == string(op.UID) {
+				return nil
+			}
+		}
+		return err
+
+	case tenancyv1alpha1.BulkWorkspaceOperationDelete:
+		if err := c.deleteWorkspace(ctx, clusterName, name); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		return nil
+
+	case tenancyv1alpha1.BulkWorkspaceOperationLabel:
+		labels := map[string]interface{}{}
+		for _, k := range op.Spec.RemoveLabels {
+			labels[k] = nil
+		}
+		for k, v := range op.Spec.Labels {
+			labels[k] = v
+		}
+		patch, err := json.Marshal(map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"labels": labels,
+			},
+		})
+		if err != nil {
+			return err
+		}
+		return c.patchWorkspace(ctx, clusterName, name, patch)
+	}
+
+	return fmt.Errorf("unknown operation %q", op.Spec.Operation)
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bulkworkspaceoperation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/third_party/conditions/util/conditions"
+)
+
+var org = logicalcluster.New("root:org")
+
+func newOperation(operation tenancyv1alpha1.BulkWorkspaceOperationType, workspaces ...string) *tenancyv1alpha1.BulkWorkspaceOperation {
+	return &tenancyv1alpha1.BulkWorkspaceOperation{
+		ObjectMeta: metav1.ObjectMeta{Name: "onboarding", ClusterName: org.String(), UID: "uid"},
+		Spec: tenancyv1alpha1.BulkWorkspaceOperationSpec{
+			Operation:  operation,
+			Workspaces: workspaces,
+		},
+	}
+}
+
+func newWorkspace(name string, annotations map[string]string) *tenancyv1alpha1.ClusterWorkspace {
+	return &tenancyv1alpha1.ClusterWorkspace{
+		ObjectMeta: metav1.ObjectMeta{Name: name, ClusterName: org.String(), Annotations: annotations},
+	}
+}
+
+// newTestController returns a controller operating on the given workspaces of root:org,
+// recording the label patches.
+func newTestController(workspaces map[string]*tenancyv1alpha1.ClusterWorkspace, patches map[string]map[string]interface{}) *controller {
+	notFound := func(name string) error {
+		return errors.NewNotFound(tenancyv1alpha1.Resource("clusterworkspaces"), name)
+	}
+	return &controller{
+		createWorkspace: func(ctx context.Context, clusterName logicalcluster.Name, workspace *tenancyv1alpha1.ClusterWorkspace) error {
+			if _, ok := workspaces[workspace.Name]; ok {
+				return errors.NewAlreadyExists(tenancyv1alpha1.Resource("clusterworkspaces"), workspace.Name)
+			}
+			workspaces[workspace.Name] = workspace
+			return nil
+		},
+		getWorkspace: func(ctx context.Context, clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error) {
+			if ws, ok := workspaces[name]; ok {
+				return ws, nil
+			}
+			return nil, notFound(name)
+		},
+		deleteWorkspace: func(ctx context.Context, clusterName logicalcluster.Name, name string) error {
+			if _, ok := workspaces[name]; !ok {
+				return notFound(name)
+			}
+			delete(workspaces, name)
+			return nil
+		},
+		patchWorkspace: func(ctx context.Context, clusterName logicalcluster.Name, name string, patch []byte) error {
+			if _, ok := workspaces[name]; !ok {
+				return notFound(name)
+			}
+			var p map[string]interface{}
+			if err := json.Unmarshal(patch, &p); err != nil {
+				return err
+			}
+			patches[name] = p
+			return nil
+		},
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	tests := map[string]struct {
+		operation  *tenancyv1alpha1.BulkWorkspaceOperation
+		workspaces map[string]*tenancyv1alpha1.ClusterWorkspace
+
+		wantWorkspaces []string
+		wantPatches    map[string]map[string]interface{}
+		wantResults    []tenancyv1alpha1.BulkWorkspaceOperationResult
+		wantCondition  bool
+		wantReason     string
+	}{
+		"creates workspaces, reporting the existing ones": {
+			operation: func() *tenancyv1alpha1.BulkWorkspaceOperation {
+				op := newOperation(tenancyv1alpha1.BulkWorkspaceOperationCreate, "one", "two", "three")
+				op.Spec.Type = "Team"
+				op.Spec.Labels = map[string]string{"team": "a"}
+				return op
+			}(),
+			workspaces: map[string]*tenancyv1alpha1.ClusterWorkspace{
+				"two":   newWorkspace("two", nil),
+				"three": newWorkspace("three", map[string]string{tenancyv1alpha1.BulkWorkspaceOperationAnnotation: "uid"}),
+			},
+			wantWorkspaces: []string{"one", "three", "two"},
+			wantResults: []tenancyv1alpha1.BulkWorkspaceOperationResult{
+				{Name: "one"},
+				{Name: "two", Error: `clusterworkspaces.tenancy.kcp.dev "two" already exists`},
+				{Name: "three"},
+			},
+			wantReason: tenancyv1alpha1.BulkWorkspaceOperationFailedReason,
+		},
+		"deletes workspaces, ignoring missing ones": {
+			operation: newOperation(tenancyv1alpha1.BulkWorkspaceOperationDelete, "one", "two", "gone"),
+			workspaces: map[string]*tenancyv1alpha1.ClusterWorkspace{
+				"one":   newWorkspace("one", nil),
+				"two":   newWorkspace("two", nil),
+				"three": newWorkspace("three", nil),
+			},
+			wantWorkspaces: []string{"three"},
+			wantResults:    []tenancyv1alpha1.BulkWorkspaceOperationResult{{Name: "one"}, {Name: "two"}, {Name: "gone"}},
+			wantCondition:  true,
+		},
+		"labels workspaces": {
+			operation: func() *tenancyv1alpha1.BulkWorkspaceOperation {
+				op := newOperation(tenancyv1alpha1.BulkWorkspaceOperationLabel, "one", "missing")
+				op.Spec.Labels = map[string]string{"tier": "gold"}
+				op.Spec.RemoveLabels = []string{"trial"}
+				return op
+			}(),
+			workspaces: map[string]*tenancyv1alpha1.ClusterWorkspace{
+				"one": newWorkspace("one", nil),
+			},
+			wantWorkspaces: []string{"one"},
+			wantPatches: map[string]map[string]interface{}{
+				"one": {"metadata": map[string]interface{}{"labels": map[string]interface{}{"tier": "gold", "trial": nil}}},
+			},
+			wantResults: []tenancyv1alpha1.BulkWorkspaceOperationResult{
+				{Name: "one"},
+				{Name: "missing", Error: `clusterworkspaces.tenancy.kcp.dev "missing" not found`},
+			},
+			wantReason: tenancyv1alpha1.BulkWorkspaceOperationFailedReason,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			patches := map[string]map[string]interface{}{}
+			c := newTestController(tt.workspaces, patches)
+
+			c.reconcile(context.Background(), tt.operation)
+
+			var names []string
+			for name := range tt.workspaces {
+				names = append(names, name)
+			}
+			require.ElementsMatch(t, tt.wantWorkspaces, names)
+			if tt.wantPatches == nil {
+				tt.wantPatches = map[string]map[string]interface{}{}
+			}
+			require.Equal(t, tt.wantPatches, patches)
+			require.Equal(t, tt.wantResults, tt.operation.Status.Results)
+
+			var failed int32
+			for _, r := range tt.wantResults {
+				if r.Error != "" {
+					failed++
+				}
+			}
+			require.Equal(t, failed, tt.operation.Status.Failed)
+			require.Equal(t, int32(len(tt.wantResults))-failed, tt.operation.Status.Succeeded)
+
+			require.Equal(t, tt.wantCondition, conditions.IsTrue(tt.operation, tenancyv1alpha1.BulkWorkspaceOperationSucceeded))
+			if tt.wantReason != "" {
+				require.Equal(t, tt.wantReason, conditions.GetReason(tt.operation, tenancyv1alpha1.BulkWorkspaceOperationSucceeded))
+			}
+		})
+	}
+}
+
+func TestReconcileCreatedWorkspace(t *testing.T) {
+	workspaces := map[string]*tenancyv1alpha1.ClusterWorkspace{}
+	c := newTestController(workspaces, nil)
+
+	op := newOperation(tenancyv1alpha1.BulkWorkspaceOperationCreate, "one")
+	op.Spec.Type = "Team"
+	op.Spec.Labels = map[string]string{"team": "a"}
+	c.reconcile(context.Background(), op)
+
+	require.Equal(t, &tenancyv1alpha1.ClusterWorkspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "one",
+			Labels:      map[string]string{"team": "a"},
+			Annotations: map[string]string{tenancyv1alpha1.BulkWorkspaceOperationAnnotation: "uid"},
+		},
+		Spec: tenancyv1alpha1.ClusterWorkspaceSpec{Type: "Team"},
+	}, workspaces["one"])
+}
+
+func TestReconcileBatches(t *testing.T) {
+	workspaces := map[string]*tenancyv1alpha1.ClusterWorkspace{}
+	c := newTestController(workspaces, nil)
+
+	var names []string
+	for i := 0; i < 2*batchSize+10; i++ {
+		names = append(names, fmt.Sprintf("ws-%d", i))
+	}
+	op := newOperation(tenancyv1alpha1.BulkWorkspaceOperationCreate, names...)
+
+	for _, wantDone := range []int{batchSize, 2 * batchSize} {
+		c.reconcile(context.Background(), op)
+		require.Len(t, op.Status.Results, wantDone)
+		require.Len(t, workspaces, wantDone)
+		require.Equal(t, tenancyv1alpha1.BulkWorkspaceOperationInProgressReason, conditions.GetReason(op, tenancyv1alpha1.BulkWorkspaceOperationSucceeded))
+	}
+
+	c.reconcile(context.Background(), op)
+	require.Len(t, op.Status.Results, len(names))
+	require.Equal(t, int32(len(names)), op.Status.Succeeded)
+	require.True(t, conditions.IsTrue(op, tenancyv1alpha1.BulkWorkspaceOperationSucceeded))
+
+	// nothing is executed anymore once all workspaces are done.
+	status := op.Status.DeepCopy()
+	c.reconcile(context.Background(), op)
+	require.Equal(t, status, &op.Status)
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterworkspace
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/third_party/conditions/util/conditions"
+)
+
+// updateConditions sets the conditions summarizing the progress of the workspace
+// for clients, i.e. SchedulingReady, InitializersComplete and APIBindingsReady.
+func (c *Controller) updateConditions(workspace *tenancyv1alpha1.ClusterWorkspace, now metav1.Time) error {
+	updateSchedulingReady(workspace)
+	updateInitializersComplete(workspace, now)
+
+	switch workspace.Status.Phase {
+	case tenancyv1alpha1.ClusterWorkspacePhaseInitializing, tenancyv1alpha1.ClusterWorkspacePhaseReady:
+		bindings, err := c.listAPIBindings(logicalcluster.From(workspace).Join(workspace.Name))
+		if err != nil {
+			return err
+		}
+		updateAPIBindingsReady(workspace, bindings)
+	}
+
+	return nil
+}
+
+// updateSchedulingReady mirrors the first failing scheduling related condition into
+// SchedulingReady, and sets it to true once the workspace left the Scheduling phase.
+func updateSchedulingReady(workspace *tenancyv1alpha1.ClusterWorkspace) {
+	for _, t := range []conditionsv1alpha1.ConditionType{
+		tenancyv1alpha1.WorkspaceScheduled,
+		tenancyv1alpha1.WorkspaceShardValid,
+		tenancyv1alpha1.WorkspaceInitializationAdmitted,
+	} {
+		if !conditions.IsFalse(workspace, t) {
+			continue
+		}
+		severity := conditionsv1alpha1.ConditionSeverityError
+		if s := conditions.GetSeverity(workspace, t); s != nil {
+			severity = *s
+		}
+		conditions.MarkFalse(workspace, tenancyv1alpha1.WorkspaceSchedulingReady, conditions.GetReason(workspace, t), severity, "%s", conditions.GetMessage(workspace, t))
+		return
+	}
+
+	switch workspace.Status.Phase {
+	case "", tenancyv1alpha1.ClusterWorkspacePhaseScheduling:
+		conditions.MarkFalse(workspace, tenancyv1alpha1.WorkspaceSchedulingReady, tenancyv1alpha1.WorkspaceSchedulingReasonPending, conditionsv1alpha1.ConditionSeverityInfo, "Waiting for the workspace to be scheduled.")
+	default:
+		conditions.MarkTrue(workspace, tenancyv1alpha1.WorkspaceSchedulingReady)
+	}
+}
+
+// updateInitializersComplete records every initializer of the workspace in
+// status.initializerStatuses, marks those as completed that got removed, and
+// sets InitializersComplete accordingly.
+func updateInitializersComplete(workspace *tenancyv1alpha1.ClusterWorkspace, now metav1.Time) {
+	switch workspace.Status.Phase {
+	case "", tenancyv1alpha1.ClusterWorkspacePhaseScheduling:
+		conditions.MarkFalse(workspace, tenancyv1alpha1.WorkspaceInitializersComplete, tenancyv1alpha1.WorkspaceInitializersReasonNotStarted, conditionsv1alpha1.ConditionSeverityInfo, "Waiting for the workspace to start initialization.")
+		return
+	}
+
+	pending := sets.NewString()
+	for _, initializer := range workspace.Status.Initializers {
+		pending.Insert(string(initializer))
+	}
+
+	recorded := sets.NewString()
+	for i := range workspace.Status.InitializerStatuses {
+		status := &workspace.Status.InitializerStatuses[i]
+		recorded.Insert(string(status.Name))
+		if !status.Completed && !pending.Has(string(status.Name)) {
+			status.Completed = true
+			status.CompletionTime = now.DeepCopy()
+		}
+	}
+	for _, initializer := range workspace.Status.Initializers {
+		if !recorded.Has(string(initializer)) {
+			workspace.Status.InitializerStatuses = append(workspace.Status.InitializerStatuses, tenancyv1alpha1.ClusterWorkspaceInitializerStatus{
+				Name: initializer,
+			})
+		}
+	}
+
+	if pending.Len() > 0 {
+		conditions.MarkFalse(workspace, tenancyv1alpha1.WorkspaceInitializersComplete, tenancyv1alpha1.WorkspaceInitializersReasonPending, conditionsv1alpha1.ConditionSeverityInfo, "Waiting for initializers: %s.", strings.Join(pending.List(), ", "))
+		return
+	}
+	conditions.MarkTrue(workspace, tenancyv1alpha1.WorkspaceInitializersComplete)
+}
+
+// updateAPIBindingsReady sets APIBindingsReady to true if all the given APIBindings
+// of the workspace have been bound. Bindings being rebound still serve their APIs.
+func updateAPIBindingsReady(workspace *tenancyv1alpha1.ClusterWorkspace, bindings []*apisv1alpha1.APIBinding) {
+	var pending []string
+	for _, binding := range bindings {
+		switch binding.Status.Phase {
+		case apisv1alpha1.APIBindingPhaseBound, apisv1alpha1.APIBindingPhaseRebinding:
+		default:
+			pending = append(pending, binding.Name)
+		}
+	}
+
+	if len(pending) > 0 {
+		sort.Strings(pending)
+		conditions.MarkFalse(workspace, tenancyv1alpha1.WorkspaceAPIBindingsReady, tenancyv1alpha1.WorkspaceAPIBindingsReasonPending, conditionsv1alpha1.ConditionSeverityInfo, "Waiting for APIBindings to be bound: %s.", strings.Join(pending, ", "))
+		return
+	}
+	conditions.MarkTrue(workspace, tenancyv1alpha1.WorkspaceAPIBindingsReady)
+}
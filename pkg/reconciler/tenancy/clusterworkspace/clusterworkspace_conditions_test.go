@@ -0,0 +1,178 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterworkspace
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/third_party/conditions/util/conditions"
+)
+
+func TestUpdateConditions(t *testing.T) {
+	now := metav1.NewTime(time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC))
+	earlier := metav1.NewTime(now.Add(-time.Minute))
+
+	binding := func(name string, phase apisv1alpha1.APIBindingPhaseType) *apisv1alpha1.APIBinding {
+		return &apisv1alpha1.APIBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: name, ClusterName: "root:org:ws"},
+			Status:     apisv1alpha1.APIBindingStatus{Phase: phase},
+		}
+	}
+
+	type condition struct {
+		status corev1.ConditionStatus
+		reason string
+	}
+
+	tests := map[string]struct {
+		status   tenancyv1alpha1.ClusterWorkspaceStatus
+		bindings []*apisv1alpha1.APIBinding
+
+		wantConditions          map[conditionsv1alpha1.ConditionType]condition
+		wantInitializerStatuses []tenancyv1alpha1.ClusterWorkspaceInitializerStatus
+	}{
+		"scheduling": {
+			status: tenancyv1alpha1.ClusterWorkspaceStatus{Phase: tenancyv1alpha1.ClusterWorkspacePhaseScheduling},
+			wantConditions: map[conditionsv1alpha1.ConditionType]condition{
+				tenancyv1alpha1.WorkspaceSchedulingReady:      {corev1.ConditionFalse, tenancyv1alpha1.WorkspaceSchedulingReasonPending},
+				tenancyv1alpha1.WorkspaceInitializersComplete: {corev1.ConditionFalse, tenancyv1alpha1.WorkspaceInitializersReasonNotStarted},
+			},
+		},
+		"unschedulable": {
+			status: tenancyv1alpha1.ClusterWorkspaceStatus{
+				Phase: tenancyv1alpha1.ClusterWorkspacePhaseScheduling,
+				Conditions: conditionsv1alpha1.Conditions{
+					{Type: tenancyv1alpha1.WorkspaceScheduled, Status: corev1.ConditionFalse, Reason: tenancyv1alpha1.WorkspaceReasonUnschedulable, Severity: conditionsv1alpha1.ConditionSeverityError},
+				},
+			},
+			wantConditions: map[conditionsv1alpha1.ConditionType]condition{
+				tenancyv1alpha1.WorkspaceSchedulingReady:      {corev1.ConditionFalse, tenancyv1alpha1.WorkspaceReasonUnschedulable},
+				tenancyv1alpha1.WorkspaceInitializersComplete: {corev1.ConditionFalse, tenancyv1alpha1.WorkspaceInitializersReasonNotStarted},
+			},
+		},
+		"queued for initialization": {
+			status: tenancyv1alpha1.ClusterWorkspaceStatus{
+				Phase: tenancyv1alpha1.ClusterWorkspacePhaseScheduling,
+				Conditions: conditionsv1alpha1.Conditions{
+					{Type: tenancyv1alpha1.WorkspaceScheduled, Status: corev1.ConditionTrue},
+					{Type: tenancyv1alpha1.WorkspaceInitializationAdmitted, Status: corev1.ConditionFalse, Reason: tenancyv1alpha1.WorkspaceInitializationReasonQueued, Severity: conditionsv1alpha1.ConditionSeverityInfo},
+				},
+			},
+			wantConditions: map[conditionsv1alpha1.ConditionType]condition{
+				tenancyv1alpha1.WorkspaceSchedulingReady:      {corev1.ConditionFalse, tenancyv1alpha1.WorkspaceInitializationReasonQueued},
+				tenancyv1alpha1.WorkspaceInitializersComplete: {corev1.ConditionFalse, tenancyv1alpha1.WorkspaceInitializersReasonNotStarted},
+			},
+		},
+		"initializing records initializers": {
+			status: tenancyv1alpha1.ClusterWorkspaceStatus{
+				Phase:        tenancyv1alpha1.ClusterWorkspacePhaseInitializing,
+				Initializers: []tenancyv1alpha1.ClusterWorkspaceInitializer{"b", "a"},
+			},
+			bindings: []*apisv1alpha1.APIBinding{binding("tenancy", apisv1alpha1.APIBindingPhaseBound), binding("scheduling", apisv1alpha1.APIBindingPhaseBinding)},
+			wantConditions: map[conditionsv1alpha1.ConditionType]condition{
+				tenancyv1alpha1.WorkspaceSchedulingReady:      {corev1.ConditionTrue, ""},
+				tenancyv1alpha1.WorkspaceInitializersComplete: {corev1.ConditionFalse, tenancyv1alpha1.WorkspaceInitializersReasonPending},
+				tenancyv1alpha1.WorkspaceAPIBindingsReady:     {corev1.ConditionFalse, tenancyv1alpha1.WorkspaceAPIBindingsReasonPending},
+			},
+			wantInitializerStatuses: []tenancyv1alpha1.ClusterWorkspaceInitializerStatus{{Name: "b"}, {Name: "a"}},
+		},
+		"initializer completes": {
+			status: tenancyv1alpha1.ClusterWorkspaceStatus{
+				Phase:               tenancyv1alpha1.ClusterWorkspacePhaseInitializing,
+				Initializers:        []tenancyv1alpha1.ClusterWorkspaceInitializer{"a"},
+				InitializerStatuses: []tenancyv1alpha1.ClusterWorkspaceInitializerStatus{{Name: "b"}, {Name: "a"}},
+			},
+			wantConditions: map[conditionsv1alpha1.ConditionType]condition{
+				tenancyv1alpha1.WorkspaceSchedulingReady:      {corev1.ConditionTrue, ""},
+				tenancyv1alpha1.WorkspaceInitializersComplete: {corev1.ConditionFalse, tenancyv1alpha1.WorkspaceInitializersReasonPending},
+				tenancyv1alpha1.WorkspaceAPIBindingsReady:     {corev1.ConditionTrue, ""},
+			},
+			wantInitializerStatuses: []tenancyv1alpha1.ClusterWorkspaceInitializerStatus{{Name: "b", Completed: true, CompletionTime: &now}, {Name: "a"}},
+		},
+		"ready keeps completion times": {
+			status: tenancyv1alpha1.ClusterWorkspaceStatus{
+				Phase:               tenancyv1alpha1.ClusterWorkspacePhaseReady,
+				InitializerStatuses: []tenancyv1alpha1.ClusterWorkspaceInitializerStatus{{Name: "b", Completed: true, CompletionTime: &earlier}, {Name: "a"}},
+			},
+			bindings: []*apisv1alpha1.APIBinding{binding("tenancy", apisv1alpha1.APIBindingPhaseRebinding)},
+			wantConditions: map[conditionsv1alpha1.ConditionType]condition{
+				tenancyv1alpha1.WorkspaceSchedulingReady:      {corev1.ConditionTrue, ""},
+				tenancyv1alpha1.WorkspaceInitializersComplete: {corev1.ConditionTrue, ""},
+				tenancyv1alpha1.WorkspaceAPIBindingsReady:     {corev1.ConditionTrue, ""},
+			},
+			wantInitializerStatuses: []tenancyv1alpha1.ClusterWorkspaceInitializerStatus{{Name: "b", Completed: true, CompletionTime: &earlier}, {Name: "a", Completed: true, CompletionTime: &now}},
+		},
+		"ready on deleted shard": {
+			status: tenancyv1alpha1.ClusterWorkspaceStatus{
+				Phase: tenancyv1alpha1.ClusterWorkspacePhaseReady,
+				Conditions: conditionsv1alpha1.Conditions{
+					{Type: tenancyv1alpha1.WorkspaceScheduled, Status: corev1.ConditionTrue},
+					{Type: tenancyv1alpha1.WorkspaceShardValid, Status: corev1.ConditionFalse, Reason: tenancyv1alpha1.WorkspaceShardValidReasonShardNotFound, Severity: conditionsv1alpha1.ConditionSeverityError},
+				},
+			},
+			wantConditions: map[conditionsv1alpha1.ConditionType]condition{
+				tenancyv1alpha1.WorkspaceSchedulingReady:      {corev1.ConditionFalse, tenancyv1alpha1.WorkspaceShardValidReasonShardNotFound},
+				tenancyv1alpha1.WorkspaceInitializersComplete: {corev1.ConditionTrue, ""},
+				tenancyv1alpha1.WorkspaceAPIBindingsReady:     {corev1.ConditionTrue, ""},
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &Controller{
+				listAPIBindings: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
+					require.Equal(t, "root:org:ws", clusterName.String())
+					return tt.bindings, nil
+				},
+			}
+			workspace := &tenancyv1alpha1.ClusterWorkspace{
+				ObjectMeta: metav1.ObjectMeta{Name: "ws", ClusterName: "root:org"},
+				Status:     tt.status,
+			}
+
+			err := c.updateConditions(workspace, now)
+			require.NoError(t, err)
+
+			for _, t2 := range []conditionsv1alpha1.ConditionType{
+				tenancyv1alpha1.WorkspaceSchedulingReady,
+				tenancyv1alpha1.WorkspaceInitializersComplete,
+				tenancyv1alpha1.WorkspaceAPIBindingsReady,
+			} {
+				want, ok := tt.wantConditions[t2]
+				if !ok {
+					require.False(t, conditions.Has(workspace, t2), "unexpected condition %s", t2)
+					continue
+				}
+				got := conditions.Get(workspace, t2)
+				require.NotNil(t, got, "missing condition %s", t2)
+				require.Equal(t, want.status, got.Status, "condition %s", t2)
+				require.Equal(t, want.reason, got.Reason, "condition %s", t2)
+			}
+			require.Equal(t, tt.wantInitializerStatuses, workspace.Status.InitializerStatuses)
+		})
+	}
+}
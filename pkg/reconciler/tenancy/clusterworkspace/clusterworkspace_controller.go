@@ -41,10 +41,13 @@ import (
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
 	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
 	kcpclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
+	apisinformer "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/apis/v1alpha1"
 	tenancyinformer "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/tenancy/v1alpha1"
 	tenancylister "github.com/kcp-dev/kcp/pkg/client/listers/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/reconciler/latency"
 	conditionsv1alpha1 "github.com/kcp-dev/kcp/third_party/conditions/apis/conditions/v1alpha1"
 	"github.com/kcp-dev/kcp/third_party/conditions/util/conditions"
 )
@@ -52,15 +55,20 @@ import (
 const (
 	currentShardIndex  = "shard"
 	unschedulableIndex = "unschedulable"
+	phaseIndex         = "phase"
+	byWorkspaceIndex   = "clusterWorkspace-apiBindingsByWorkspace"
 	controllerName     = "workspace"
 )
 
 func NewController(
 	kcpClient kcpclient.ClusterInterface,
 	workspaceInformer tenancyinformer.ClusterWorkspaceInformer,
+	workspaceTypeInformer tenancyinformer.ClusterWorkspaceTypeInformer,
 	rootWorkspaceShardInformer tenancyinformer.ClusterWorkspaceShardInformer,
+	apiBindingInformer apisinformer.APIBindingInformer,
+	maxConcurrentInitializations int,
 ) (*Controller, error) {
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+	queue := latency.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
 
 	c := &Controller{
 		queue:                     queue,
@@ -69,11 +77,55 @@ func NewController(
 		workspaceLister:           workspaceInformer.Lister(),
 		rootWorkspaceShardIndexer: rootWorkspaceShardInformer.Informer().GetIndexer(),
 		rootWorkspaceShardLister:  rootWorkspaceShardInformer.Lister(),
+		listAPIBindings: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
+			objs, err := apiBindingInformer.Informer().GetIndexer().ByIndex(byWorkspaceIndex, clusterName.String())
+			if err != nil {
+				return nil, err
+			}
+			bindings := make([]*apisv1alpha1.APIBinding, 0, len(objs))
+			for _, obj := range objs {
+				bindings = append(bindings, obj.(*apisv1alpha1.APIBinding))
+			}
+			return bindings, nil
+		},
+	}
+	c.initializationQueue = &initializationQueue{
+		maxConcurrent: maxConcurrentInitializations,
+		listWorkspaces: func(phase tenancyv1alpha1.ClusterWorkspacePhaseType) ([]*tenancyv1alpha1.ClusterWorkspace, error) {
+			objs, err := c.workspaceIndexer.ByIndex(phaseIndex, string(phase))
+			if err != nil {
+				return nil, err
+			}
+			workspaces := make([]*tenancyv1alpha1.ClusterWorkspace, 0, len(objs))
+			for _, obj := range objs {
+				workspaces = append(workspaces, obj.(*tenancyv1alpha1.ClusterWorkspace))
+			}
+			return workspaces, nil
+		},
+		getType: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspaceType, error) {
+			return workspaceTypeInformer.Lister().Get(clusters.ToClusterAwareKey(clusterName, name))
+		},
+		reserved: map[string]bool{},
 	}
 
 	workspaceInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc:    func(obj interface{}) { c.enqueue(obj) },
-		UpdateFunc: func(_, obj interface{}) { c.enqueue(obj) },
+		AddFunc: func(obj interface{}) { c.enqueue(obj) },
+		UpdateFunc: func(oldObj, obj interface{}) {
+			c.enqueue(obj)
+
+			old, ok := oldObj.(*tenancyv1alpha1.ClusterWorkspace)
+			if !ok {
+				return
+			}
+			if workspace, ok := obj.(*tenancyv1alpha1.ClusterWorkspace); ok && old.Status.Phase == tenancyv1alpha1.ClusterWorkspacePhaseInitializing && workspace.Status.Phase != tenancyv1alpha1.ClusterWorkspacePhaseInitializing {
+				c.enqueueInitializationCandidates()
+			}
+		},
+		DeleteFunc: func(obj interface{}) { c.enqueueInitializationCandidates() },
+	})
+	// limits might have been raised
+	workspaceTypeInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(_, _ interface{}) { c.enqueueInitializationCandidates() },
 	})
 	if err := c.workspaceIndexer.AddIndexers(map[string]cache.IndexFunc{
 		currentShardIndex: func(obj interface{}) ([]string, error) {
@@ -90,10 +142,31 @@ func NewController(
 			}
 			return []string{}, nil
 		},
+		phaseIndex: func(obj interface{}) ([]string, error) {
+			if workspace, ok := obj.(*tenancyv1alpha1.ClusterWorkspace); ok {
+				return []string{string(workspace.Status.Phase)}, nil
+			}
+			return []string{}, nil
+		},
 	}); err != nil {
 		return nil, fmt.Errorf("failed to add indexer for ClusterWorkspace: %w", err)
 	}
 
+	if _, found := apiBindingInformer.Informer().GetIndexer().GetIndexers()[byWorkspaceIndex]; !found {
+		if err := apiBindingInformer.Informer().AddIndexers(cache.Indexers{
+			byWorkspaceIndex: func(obj interface{}) ([]string, error) {
+				return []string{logicalcluster.From(obj.(metav1.Object)).String()}, nil
+			},
+		}); err != nil {
+			return nil, fmt.Errorf("failed to add indexer for APIBinding: %w", err)
+		}
+	}
+	apiBindingInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueueAPIBinding(obj) },
+		UpdateFunc: func(_, obj interface{}) { c.enqueueAPIBinding(obj) },
+		DeleteFunc: func(obj interface{}) { c.enqueueAPIBinding(obj) },
+	})
+
 	rootWorkspaceShardInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    func(obj interface{}) { c.enqueueUpsertedShard(obj, "add") },
 		UpdateFunc: func(obj, _ interface{}) { c.enqueueUpsertedShard(obj, "update") },
@@ -114,6 +187,10 @@ type Controller struct {
 
 	rootWorkspaceShardIndexer cache.Indexer
 	rootWorkspaceShardLister  tenancylister.ClusterWorkspaceShardLister
+
+	listAPIBindings func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error)
+
+	initializationQueue *initializationQueue
 }
 
 func (c *Controller) enqueue(obj interface{}) {
@@ -126,6 +203,44 @@ func (c *Controller) enqueue(obj interface{}) {
 	c.queue.Add(key)
 }
 
+// enqueueAPIBinding queues the workspace the APIBinding lives in.
+func (c *Controller) enqueueAPIBinding(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	binding, ok := obj.(*apisv1alpha1.APIBinding)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("got %T when handling APIBinding", obj))
+		return
+	}
+	parent, name := logicalcluster.From(binding).Split()
+	if parent.Empty() {
+		return // the root workspace has no ClusterWorkspace
+	}
+	key := clusters.ToClusterAwareKey(parent, name)
+	klog.V(4).Infof("Queueing workspace %q because of APIBinding %s|%s", key, logicalcluster.From(binding), binding.Name)
+	c.queue.Add(key)
+}
+
+// enqueueInitializationCandidates queues the scheduled workspaces next in the
+// initialization queue, e.g. after an initialization slot became free.
+func (c *Controller) enqueueInitializationCandidates() {
+	workspaces, err := c.initializationQueue.candidates()
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	for _, workspace := range workspaces {
+		key, err := cache.MetaNamespaceKeyFunc(workspace)
+		if err != nil {
+			runtime.HandleError(err)
+			return
+		}
+		klog.V(2).Infof("Queueing workspace %q waiting for initialization", key)
+		c.queue.Add(key)
+	}
+}
+
 func (c *Controller) enqueueUpsertedShard(obj interface{}, verb string) {
 	shard, ok := obj.(*tenancyv1alpha1.ClusterWorkspaceShard)
 	if !ok {
@@ -243,6 +358,9 @@ func (c *Controller) process(ctx context.Context, key string) error {
 	if err := c.reconcile(ctx, obj); err != nil {
 		return err
 	}
+	if err := c.updateConditions(obj, metav1.Now()); err != nil {
+		return err
+	}
 
 	// If the object being reconciled changed as a result, update it.
 	if !equality.Semantic.DeepEqual(previous.Status, obj.Status) {
@@ -399,6 +517,19 @@ func (c *Controller) reconcile(ctx context.Context, workspace *tenancyv1alpha1.C
 				return nil // nolint:nilerr
 			}
 
+			admitted, reason, message, err := c.initializationQueue.admit(workspace)
+			if err != nil {
+				return err
+			}
+			if !admitted {
+				klog.V(2).Infof("Workspace %s|%s is waiting for initialization: %s", workspace.ClusterName, workspace.Name, message)
+				conditions.MarkFalse(workspace, tenancyv1alpha1.WorkspaceInitializationAdmitted, reason, conditionsv1alpha1.ConditionSeverityInfo, "%s", message)
+				return nil
+			}
+			if conditions.Has(workspace, tenancyv1alpha1.WorkspaceInitializationAdmitted) {
+				conditions.MarkTrue(workspace, tenancyv1alpha1.WorkspaceInitializationAdmitted)
+			}
+
 			workspace.Status.Phase = tenancyv1alpha1.ClusterWorkspacePhaseInitializing
 		}
 	case tenancyv1alpha1.ClusterWorkspacePhaseInitializing:
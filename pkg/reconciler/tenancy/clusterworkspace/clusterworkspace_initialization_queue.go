@@ -0,0 +1,265 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterworkspace
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/clusters"
+	"k8s.io/klog/v2"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+// InitializationPriorityAnnotation can be set on a ClusterWorkspace on creation to
+// initialize it before workspaces of lower priority when the initialization queue
+// is full. The value is an integer, the default is 0.
+const InitializationPriorityAnnotation = "tenancy.kcp.dev/initialization-priority"
+
+// initializationQueue limits the number of workspaces moving from the Scheduling to the
+// Initializing phase, such that initializers do not thrash when thousands of workspaces
+// are created at once. Waiting workspaces are admitted by priority, round-robin across
+// organizations, and then in order of creation.
+type initializationQueue struct {
+	// maxConcurrent is the maximum number of initializing workspaces. 0 means unlimited.
+	maxConcurrent int
+
+	listWorkspaces func(phase tenancyv1alpha1.ClusterWorkspacePhaseType) ([]*tenancyv1alpha1.ClusterWorkspace, error)
+	getType        func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspaceType, error)
+
+	lock sync.Mutex
+	// reserved holds the keys of admitted workspaces that are not yet initializing
+	// in the informer, to not admit more than the limits in the meantime.
+	reserved map[string]bool
+}
+
+// admit returns whether the scheduled workspace can move to the Initializing phase, and
+// reserves a slot for it if so. Otherwise, it returns the reason and message for the
+// WorkspaceInitializationAdmitted condition.
+func (q *initializationQueue) admit(workspace *tenancyv1alpha1.ClusterWorkspace) (admitted bool, reason, message string, err error) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	key := clusters.ToClusterAwareKey(logicalcluster.From(workspace), workspace.Name)
+	if q.reserved[key] {
+		return true, "", "", nil
+	}
+
+	candidates, typeFree, err := q.candidatesLocked(workspace)
+	if err != nil {
+		return false, "", "", err
+	}
+	for _, ws := range candidates {
+		if ws.Name == workspace.Name && logicalcluster.From(ws) == logicalcluster.From(workspace) {
+			q.reserved[key] = true
+			return true, "", "", nil
+		}
+	}
+
+	if free, limited := typeFree[typeKey(workspace)]; limited && free <= 0 {
+		return false, tenancyv1alpha1.WorkspaceInitializationReasonTypeConcurrencyLimit,
+			fmt.Sprintf("Waiting for one of the initialization slots of ClusterWorkspaceType %q.", workspace.Spec.Type), nil
+	}
+	return false, tenancyv1alpha1.WorkspaceInitializationReasonQueued,
+		fmt.Sprintf("Waiting for one of the %d initialization slots of the shard.", q.maxConcurrent), nil
+}
+
+// candidates returns the scheduled workspaces that would be admitted next.
+func (q *initializationQueue) candidates() ([]*tenancyv1alpha1.ClusterWorkspace, error) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	candidates, _, err := q.candidatesLocked(nil)
+	return candidates, err
+}
+
+// candidatesLocked returns the scheduled workspaces that fit into the free slots, including
+// the given workspace which might not be scheduled in the informer yet. It also returns the
+// free slots of the limited types.
+func (q *initializationQueue) candidatesLocked(workspace *tenancyv1alpha1.ClusterWorkspace) ([]*tenancyv1alpha1.ClusterWorkspace, map[string]int, error) {
+	initializing, err := q.listWorkspaces(tenancyv1alpha1.ClusterWorkspacePhaseInitializing)
+	if err != nil {
+		return nil, nil, err
+	}
+	scheduling, err := q.listWorkspaces(tenancyv1alpha1.ClusterWorkspacePhaseScheduling)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	waiting := make([]*tenancyv1alpha1.ClusterWorkspace, 0, len(scheduling)+1)
+	if workspace != nil {
+		waiting = append(waiting, workspace)
+	}
+	stillScheduling := map[string]bool{}
+	for _, ws := range scheduling {
+		key := clusters.ToClusterAwareKey(logicalcluster.From(ws), ws.Name)
+		stillScheduling[key] = true
+		if ws.Status.Location.Current == "" || q.reserved[key] {
+			continue
+		}
+		if workspace != nil && ws.Name == workspace.Name && logicalcluster.From(ws) == logicalcluster.From(workspace) {
+			continue
+		}
+		waiting = append(waiting, ws)
+	}
+
+	// forget the reservations of workspaces that are initializing or gone
+	for key := range q.reserved {
+		if !stillScheduling[key] {
+			delete(q.reserved, key)
+		}
+	}
+
+	// count occupied slots
+	occupied := len(initializing) + len(q.reserved)
+	typeOccupied := map[string]int{}
+	for _, ws := range initializing {
+		typeOccupied[typeKey(ws)]++
+	}
+	for _, ws := range scheduling {
+		if q.reserved[clusters.ToClusterAwareKey(logicalcluster.From(ws), ws.Name)] {
+			typeOccupied[typeKey(ws)]++
+		}
+	}
+
+	// free slots of limited types, computed on first use
+	typeFree := map[string]int{}
+	typeUnlimited := map[string]bool{}
+	freeOfType := func(ws *tenancyv1alpha1.ClusterWorkspace) (int, bool, error) {
+		key := typeKey(ws)
+		if typeUnlimited[key] {
+			return 0, false, nil
+		}
+		if free, found := typeFree[key]; found {
+			return free, true, nil
+		}
+		cwt, err := q.getType(logicalcluster.From(ws), strings.ToLower(ws.Spec.Type))
+		if errors.IsNotFound(err) {
+			typeUnlimited[key] = true
+			return 0, false, nil
+		} else if err != nil {
+			return 0, false, err
+		}
+		if cwt.Spec.MaxConcurrentInitializations == nil {
+			typeUnlimited[key] = true
+			return 0, false, nil
+		}
+		typeFree[key] = int(*cwt.Spec.MaxConcurrentInitializations) - typeOccupied[key]
+		return typeFree[key], true, nil
+	}
+
+	free := q.maxConcurrent - occupied
+	var candidates []*tenancyv1alpha1.ClusterWorkspace
+	for _, ws := range orderForInitialization(waiting) {
+		if q.maxConcurrent > 0 && free <= 0 {
+			break
+		}
+		typeSlots, limited, err := freeOfType(ws)
+		if err != nil {
+			return nil, nil, err
+		}
+		if limited && typeSlots <= 0 {
+			continue
+		}
+		candidates = append(candidates, ws)
+		free--
+		if limited {
+			typeFree[typeKey(ws)]--
+		}
+	}
+
+	// make sure the type of the given workspace is known to the caller
+	if workspace != nil {
+		if _, _, err := freeOfType(workspace); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return candidates, typeFree, nil
+}
+
+// orderForInitialization sorts workspaces by priority, then round-robin across
+// organizations, i.e. the first workspace of each organization comes before the
+// second of any organization, and then by creation.
+func orderForInitialization(workspaces []*tenancyv1alpha1.ClusterWorkspace) []*tenancyv1alpha1.ClusterWorkspace {
+	sorted := make([]*tenancyv1alpha1.ClusterWorkspace, len(workspaces))
+	copy(sorted, workspaces)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if pi, pj := initializationPriority(sorted[i]), initializationPriority(sorted[j]); pi != pj {
+			return pi > pj
+		}
+		if ti, tj := sorted[i].CreationTimestamp, sorted[j].CreationTimestamp; !ti.Equal(&tj) {
+			return ti.Before(&tj)
+		}
+		return clusters.ToClusterAwareKey(logicalcluster.From(sorted[i]), sorted[i].Name) < clusters.ToClusterAwareKey(logicalcluster.From(sorted[j]), sorted[j].Name)
+	})
+
+	// rank within the organization and priority
+	type rankKey struct {
+		org      string
+		priority int
+	}
+	ranks := make(map[*tenancyv1alpha1.ClusterWorkspace]int, len(sorted))
+	next := map[rankKey]int{}
+	for _, ws := range sorted {
+		k := rankKey{org: organizationOf(ws), priority: initializationPriority(ws)}
+		ranks[ws] = next[k]
+		next[k]++
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if pi, pj := initializationPriority(sorted[i]), initializationPriority(sorted[j]); pi != pj {
+			return pi > pj
+		}
+		return ranks[sorted[i]] < ranks[sorted[j]]
+	})
+	return sorted
+}
+
+func initializationPriority(workspace *tenancyv1alpha1.ClusterWorkspace) int {
+	value, found := workspace.Annotations[InitializationPriorityAnnotation]
+	if !found {
+		return 0
+	}
+	priority, err := strconv.Atoi(value)
+	if err != nil {
+		klog.V(4).Infof("Ignoring invalid %s annotation on workspace %s|%s: %v", InitializationPriorityAnnotation, logicalcluster.From(workspace), workspace.Name, err)
+		return 0
+	}
+	return priority
+}
+
+// organizationOf returns the organization workspace a workspace belongs to, e.g.
+// root:org for root:org:team. Organizations themselves belong to root.
+func organizationOf(workspace *tenancyv1alpha1.ClusterWorkspace) string {
+	parts := strings.SplitN(logicalcluster.From(workspace).String(), ":", 3)
+	if len(parts) < 2 {
+		return parts[0]
+	}
+	return parts[0] + ":" + parts[1]
+}
+
+func typeKey(workspace *tenancyv1alpha1.ClusterWorkspace) string {
+	return clusters.ToClusterAwareKey(logicalcluster.From(workspace), strings.ToLower(workspace.Spec.Type))
+}
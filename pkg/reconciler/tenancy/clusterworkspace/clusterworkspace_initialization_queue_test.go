@@ -0,0 +1,166 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterworkspace
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clusters"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+func TestInitializationQueue(t *testing.T) {
+	created := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	workspace := func(cluster, name, typ string, phase tenancyv1alpha1.ClusterWorkspacePhaseType, priority string) *tenancyv1alpha1.ClusterWorkspace {
+		created = created.Add(time.Second)
+		ws := &tenancyv1alpha1.ClusterWorkspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				ClusterName:       cluster,
+				CreationTimestamp: metav1.NewTime(created),
+			},
+			Spec: tenancyv1alpha1.ClusterWorkspaceSpec{Type: typ},
+			Status: tenancyv1alpha1.ClusterWorkspaceStatus{
+				Phase:    phase,
+				Location: tenancyv1alpha1.ClusterWorkspaceLocation{Current: "root"},
+			},
+		}
+		if priority != "" {
+			ws.Annotations = map[string]string{InitializationPriorityAnnotation: priority}
+		}
+		return ws
+	}
+	int32Ptr := func(i int32) *int32 { return &i }
+
+	tests := map[string]struct {
+		maxConcurrent  int
+		workspaces     []*tenancyv1alpha1.ClusterWorkspace
+		types          map[string]*tenancyv1alpha1.ClusterWorkspaceType
+		wantCandidates []string
+		admit          string
+		wantAdmitted   bool
+		wantReason     string
+	}{
+		"unlimited admits all": {
+			workspaces: []*tenancyv1alpha1.ClusterWorkspace{
+				workspace("root:a", "one", "Universal", tenancyv1alpha1.ClusterWorkspacePhaseScheduling, ""),
+				workspace("root:a", "two", "Universal", tenancyv1alpha1.ClusterWorkspacePhaseScheduling, ""),
+			},
+			wantCandidates: []string{"root:a|one", "root:a|two"},
+			admit:          "root:a|two",
+			wantAdmitted:   true,
+		},
+		"round-robin across organizations": {
+			maxConcurrent: 3,
+			workspaces: []*tenancyv1alpha1.ClusterWorkspace{
+				workspace("root:a", "one", "Universal", tenancyv1alpha1.ClusterWorkspacePhaseScheduling, ""),
+				workspace("root:a", "two", "Universal", tenancyv1alpha1.ClusterWorkspacePhaseScheduling, ""),
+				workspace("root:a:team", "three", "Universal", tenancyv1alpha1.ClusterWorkspacePhaseScheduling, ""),
+				workspace("root:b", "one", "Universal", tenancyv1alpha1.ClusterWorkspacePhaseScheduling, ""),
+				workspace("root:c", "one", "Universal", tenancyv1alpha1.ClusterWorkspacePhaseScheduling, ""),
+			},
+			wantCandidates: []string{"root:a|one", "root:b|one", "root:c|one"},
+			admit:          "root:a|two",
+			wantReason:     tenancyv1alpha1.WorkspaceInitializationReasonQueued,
+		},
+		"priority first": {
+			maxConcurrent: 2,
+			workspaces: []*tenancyv1alpha1.ClusterWorkspace{
+				workspace("root:a", "one", "Universal", tenancyv1alpha1.ClusterWorkspacePhaseScheduling, ""),
+				workspace("root:b", "one", "Universal", tenancyv1alpha1.ClusterWorkspacePhaseScheduling, ""),
+				workspace("root:a", "urgent", "Universal", tenancyv1alpha1.ClusterWorkspacePhaseScheduling, "10"),
+			},
+			wantCandidates: []string{"root:a|urgent", "root:a|one"},
+			admit:          "root:a|urgent",
+			wantAdmitted:   true,
+		},
+		"initializing workspaces occupy slots": {
+			maxConcurrent: 2,
+			workspaces: []*tenancyv1alpha1.ClusterWorkspace{
+				workspace("root:a", "one", "Universal", tenancyv1alpha1.ClusterWorkspacePhaseInitializing, ""),
+				workspace("root:a", "two", "Universal", tenancyv1alpha1.ClusterWorkspacePhaseInitializing, ""),
+				workspace("root:b", "one", "Universal", tenancyv1alpha1.ClusterWorkspacePhaseScheduling, ""),
+			},
+			admit:      "root:b|one",
+			wantReason: tenancyv1alpha1.WorkspaceInitializationReasonQueued,
+		},
+		"type limit": {
+			workspaces: []*tenancyv1alpha1.ClusterWorkspace{
+				workspace("root:a", "one", "Team", tenancyv1alpha1.ClusterWorkspacePhaseInitializing, ""),
+				workspace("root:a", "two", "Team", tenancyv1alpha1.ClusterWorkspacePhaseScheduling, ""),
+				workspace("root:a", "three", "Universal", tenancyv1alpha1.ClusterWorkspacePhaseScheduling, ""),
+			},
+			types: map[string]*tenancyv1alpha1.ClusterWorkspaceType{
+				"root:a|team": {Spec: tenancyv1alpha1.ClusterWorkspaceTypeSpec{MaxConcurrentInitializations: int32Ptr(1)}},
+			},
+			wantCandidates: []string{"root:a|three"},
+			admit:          "root:a|two",
+			wantReason:     tenancyv1alpha1.WorkspaceInitializationReasonTypeConcurrencyLimit,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			q := &initializationQueue{
+				maxConcurrent: tc.maxConcurrent,
+				listWorkspaces: func(phase tenancyv1alpha1.ClusterWorkspacePhaseType) ([]*tenancyv1alpha1.ClusterWorkspace, error) {
+					var ret []*tenancyv1alpha1.ClusterWorkspace
+					for _, ws := range tc.workspaces {
+						if ws.Status.Phase == phase {
+							ret = append(ret, ws)
+						}
+					}
+					return ret, nil
+				},
+				getType: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspaceType, error) {
+					if cwt, found := tc.types[clusterName.String()+"|"+name]; found {
+						return cwt, nil
+					}
+					return nil, errors.NewNotFound(tenancyv1alpha1.Resource("clusterworkspacetypes"), name)
+				},
+				reserved: map[string]bool{},
+			}
+
+			candidates, err := q.candidates()
+			require.NoError(t, err)
+			var got []string
+			for _, ws := range candidates {
+				got = append(got, ws.ClusterName+"|"+ws.Name)
+			}
+			require.Equal(t, tc.wantCandidates, got)
+
+			for _, ws := range tc.workspaces {
+				if ws.ClusterName+"|"+ws.Name != tc.admit {
+					continue
+				}
+				admitted, reason, _, err := q.admit(ws)
+				require.NoError(t, err)
+				require.Equal(t, tc.wantAdmitted, admitted)
+				require.Equal(t, tc.wantReason, reason)
+				if admitted {
+					require.True(t, q.reserved[clusters.ToClusterAwareKey(logicalcluster.From(ws), ws.Name)], "admitted workspace must reserve a slot")
+				}
+			}
+		})
+	}
+}
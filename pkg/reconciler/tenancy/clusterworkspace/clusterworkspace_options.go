@@ -0,0 +1,43 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterworkspace
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+)
+
+func DefaultOptions() *Options {
+	return &Options{}
+}
+
+func BindOptions(o *Options, fs *pflag.FlagSet) *Options {
+	fs.IntVar(&o.MaxConcurrentInitializations, "workspace-initialization-concurrency", o.MaxConcurrentInitializations, "Maximum number of workspaces initializing at the same time. Further scheduled workspaces wait, ordered by priority and round-robin across organizations. 0 means unlimited")
+	return o
+}
+
+type Options struct {
+	MaxConcurrentInitializations int
+}
+
+func (o *Options) Validate() error {
+	if o.MaxConcurrentInitializations < 0 {
+		return fmt.Errorf("--workspace-initialization-concurrency must be >=0 (%d)", o.MaxConcurrentInitializations)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package defaultnamespaces
+
+import (
+	"context"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+func (c *controller) reconcile(ctx context.Context, workspace *tenancyv1alpha1.ClusterWorkspace) error {
+	if workspace.Status.Phase != tenancyv1alpha1.ClusterWorkspacePhaseInitializing {
+		return nil
+	}
+
+	// have we done our work before?
+	found := false
+	for _, i := range workspace.Status.Initializers {
+		if i == tenancyv1alpha1.DefaultNamespacesInitializer {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil
+	}
+
+	var defaults []tenancyv1alpha1.DefaultNamespace
+	cwt, err := c.getClusterWorkspaceType(logicalcluster.From(workspace), strings.ToLower(workspace.Spec.Type))
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if err == nil && cwt.Spec.Namespaces != nil {
+		defaults = cwt.Spec.Namespaces.Defaults
+	}
+
+	wsClusterName := logicalcluster.From(workspace).Join(workspace.Name)
+	for _, d := range defaults {
+		if err := c.ensureNamespace(ctx, wsClusterName, d); err != nil {
+			return err // requeue
+		}
+	}
+
+	// we are done. remove our initializer
+	newInitializers := make([]tenancyv1alpha1.ClusterWorkspaceInitializer, 0, len(workspace.Status.Initializers))
+	for _, i := range workspace.Status.Initializers {
+		if i != tenancyv1alpha1.DefaultNamespacesInitializer {
+			newInitializers = append(newInitializers, i)
+		}
+	}
+	workspace.Status.Initializers = newInitializers
+
+	return nil
+}
+
+// ensureNamespace creates the given default namespace, or adds its labels if it exists.
+func (c *controller) ensureNamespace(ctx context.Context, clusterName logicalcluster.Name, d tenancyv1alpha1.DefaultNamespace) error {
+	ns, err := c.getNamespace(ctx, clusterName, d.Name)
+	if errors.IsNotFound(err) {
+		klog.Infof("Creating default namespace %q in logical cluster %s", d.Name, clusterName)
+		err := c.createNamespace(ctx, clusterName, &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   d.Name,
+				Labels: d.Labels,
+			},
+		})
+		return err // on conflict, labels are added on requeue
+	} else if err != nil {
+		return err
+	}
+
+	ns = ns.DeepCopy()
+	changed := false
+	for k, v := range d.Labels {
+		if existing, ok := ns.Labels[k]; ok && existing == v {
+			continue
+		}
+		if ns.Labels == nil {
+			ns.Labels = map[string]string{}
+		}
+		ns.Labels[k] = v
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	klog.Infof("Updating labels of default namespace %q in logical cluster %s", d.Name, clusterName)
+	return c.updateNamespace(ctx, clusterName, ns)
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package defaultnamespaces
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+func TestReconcile(t *testing.T) {
+	defaults := []tenancyv1alpha1.DefaultNamespace{
+		{Name: "team", Labels: map[string]string{"owner": "team"}},
+		{Name: "shared"},
+	}
+
+	tests := map[string]struct {
+		phase            tenancyv1alpha1.ClusterWorkspacePhaseType
+		initializers     []tenancyv1alpha1.ClusterWorkspaceInitializer
+		wsType           string
+		existing         []corev1.Namespace
+		wantNamespaces   map[string]map[string]string
+		wantInitializers []tenancyv1alpha1.ClusterWorkspaceInitializer
+	}{
+		"default namespaces are created and the initializer removed": {
+			phase:            tenancyv1alpha1.ClusterWorkspacePhaseInitializing,
+			initializers:     []tenancyv1alpha1.ClusterWorkspaceInitializer{"other", tenancyv1alpha1.DefaultNamespacesInitializer},
+			wantNamespaces:   map[string]map[string]string{"team": {"owner": "team"}, "shared": nil},
+			wantInitializers: []tenancyv1alpha1.ClusterWorkspaceInitializer{"other"},
+		},
+		"labels are added to existing namespaces": {
+			phase:        tenancyv1alpha1.ClusterWorkspacePhaseInitializing,
+			initializers: []tenancyv1alpha1.ClusterWorkspaceInitializer{tenancyv1alpha1.DefaultNamespacesInitializer},
+			existing: []corev1.Namespace{
+				{ObjectMeta: metav1.ObjectMeta{Name: "team", Labels: map[string]string{"env": "dev"}}},
+			},
+			wantNamespaces:   map[string]map[string]string{"team": {"owner": "team", "env": "dev"}, "shared": nil},
+			wantInitializers: []tenancyv1alpha1.ClusterWorkspaceInitializer{},
+		},
+		"initializer of a deleted type is removed": {
+			phase:            tenancyv1alpha1.ClusterWorkspacePhaseInitializing,
+			initializers:     []tenancyv1alpha1.ClusterWorkspaceInitializer{tenancyv1alpha1.DefaultNamespacesInitializer},
+			wsType:           "Deleted",
+			wantNamespaces:   map[string]map[string]string{},
+			wantInitializers: []tenancyv1alpha1.ClusterWorkspaceInitializer{},
+		},
+		"workspace without the initializer is ignored": {
+			phase:            tenancyv1alpha1.ClusterWorkspacePhaseInitializing,
+			initializers:     []tenancyv1alpha1.ClusterWorkspaceInitializer{"other"},
+			wantNamespaces:   map[string]map[string]string{},
+			wantInitializers: []tenancyv1alpha1.ClusterWorkspaceInitializer{"other"},
+		},
+		"ready workspace is ignored": {
+			phase:            tenancyv1alpha1.ClusterWorkspacePhaseReady,
+			wantNamespaces:   map[string]map[string]string{},
+			wantInitializers: nil,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if tc.wsType == "" {
+				tc.wsType = "Team"
+			}
+			namespaces := map[string]*corev1.Namespace{}
+			for i := range tc.existing {
+				namespaces[tc.existing[i].Name] = &tc.existing[i]
+			}
+			changed := map[string]map[string]string{}
+			c := &controller{
+				getClusterWorkspaceType: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspaceType, error) {
+					require.Equal(t, "root:org", clusterName.String())
+					if name != "team" {
+						return nil, errors.NewNotFound(tenancyv1alpha1.Resource("clusterworkspacetypes"), name)
+					}
+					return &tenancyv1alpha1.ClusterWorkspaceType{
+						ObjectMeta: metav1.ObjectMeta{Name: name, ClusterName: clusterName.String()},
+						Spec: tenancyv1alpha1.ClusterWorkspaceTypeSpec{
+							Namespaces: &tenancyv1alpha1.ClusterWorkspaceNamespaces{Defaults: defaults},
+						},
+					}, nil
+				},
+				getNamespace: func(ctx context.Context, clusterName logicalcluster.Name, name string) (*corev1.Namespace, error) {
+					require.Equal(t, "root:org:ws", clusterName.String())
+					if ns, ok := namespaces[name]; ok {
+						return ns, nil
+					}
+					return nil, errors.NewNotFound(corev1.Resource("namespaces"), name)
+				},
+				createNamespace: func(ctx context.Context, clusterName logicalcluster.Name, ns *corev1.Namespace) error {
+					require.Equal(t, "root:org:ws", clusterName.String())
+					changed[ns.Name] = ns.Labels
+					return nil
+				},
+				updateNamespace: func(ctx context.Context, clusterName logicalcluster.Name, ns *corev1.Namespace) error {
+					require.Equal(t, "root:org:ws", clusterName.String())
+					changed[ns.Name] = ns.Labels
+					return nil
+				},
+			}
+
+			ws := &tenancyv1alpha1.ClusterWorkspace{
+				ObjectMeta: metav1.ObjectMeta{Name: "ws", ClusterName: "root:org"},
+				Spec:       tenancyv1alpha1.ClusterWorkspaceSpec{Type: tc.wsType},
+				Status: tenancyv1alpha1.ClusterWorkspaceStatus{
+					Phase:        tc.phase,
+					Initializers: tc.initializers,
+				},
+			}
+			require.NoError(t, c.reconcile(context.Background(), ws))
+			require.Equal(t, tc.wantNamespaces, changed)
+			require.Equal(t, tc.wantInitializers, ws.Status.Initializers)
+		})
+	}
+}
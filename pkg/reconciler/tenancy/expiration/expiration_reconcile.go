@@ -0,0 +1,93 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package expiration
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/third_party/conditions/util/conditions"
+)
+
+func (c *controller) reconcile(ctx context.Context, ws *tenancyv1alpha1.ClusterWorkspace) error {
+	if !ws.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	expires, found := expirationTime(ws)
+	if !found {
+		// the expiration was removed, or never set.
+		conditions.Delete(ws, tenancyv1alpha1.WorkspaceExpiring)
+		return nil
+	}
+
+	now := c.now()
+	if !now.Before(expires) {
+		klog.Infof("Deleting ClusterWorkspace %s|%s, expired at %s", logicalcluster.From(ws), ws.Name, expires.UTC().Format(time.RFC3339))
+		if err := c.deleteWorkspace(ctx, logicalcluster.From(ws), ws); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	if warnAt := expires.Add(-c.warningPeriod); now.Before(warnAt) {
+		// the expiration might have been postponed.
+		conditions.Delete(ws, tenancyv1alpha1.WorkspaceExpiring)
+		c.enqueueAfter(ws, warnAt.Sub(now))
+		return nil
+	}
+
+	conditions.Set(ws, &conditionsv1alpha1.Condition{
+		Type:               tenancyv1alpha1.WorkspaceExpiring,
+		Status:             corev1.ConditionTrue,
+		Severity:           conditionsv1alpha1.ConditionSeverityNone,
+		Reason:             tenancyv1alpha1.WorkspaceExpiringReasonScheduled,
+		Message:            fmt.Sprintf("The workspace expires at %s and will be deleted with its content.", expires.UTC().Format(time.RFC3339)),
+		LastTransitionTime: metav1.NewTime(now),
+	})
+	c.enqueueAfter(ws, expires.Sub(now))
+
+	return nil
+}
+
+// expirationTime returns the time the workspace expires, i.e. the earlier of its creation
+// plus the ttl and the expiration timestamp, and false if it does not expire.
+func expirationTime(ws *tenancyv1alpha1.ClusterWorkspace) (time.Time, bool) {
+	expiration := ws.Spec.Expiration
+	if expiration == nil {
+		return time.Time{}, false
+	}
+
+	var expires time.Time
+	if expiration.TTL != nil {
+		expires = ws.CreationTimestamp.Add(expiration.TTL.Duration)
+	}
+	if expiration.Timestamp != nil && (expires.IsZero() || expiration.Timestamp.Time.Before(expires)) {
+		expires = expiration.Timestamp.Time
+	}
+	return expires, !expires.IsZero()
+}
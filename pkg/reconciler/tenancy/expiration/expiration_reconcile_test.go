@@ -0,0 +1,138 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package expiration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/third_party/conditions/util/conditions"
+)
+
+func TestReconcile(t *testing.T) {
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	created := now.Add(-48 * time.Hour)
+
+	ttl := func(d time.Duration) *tenancyv1alpha1.WorkspaceExpiration {
+		return &tenancyv1alpha1.WorkspaceExpiration{TTL: &metav1.Duration{Duration: d}}
+	}
+	timestamp := func(t time.Time) *tenancyv1alpha1.WorkspaceExpiration {
+		return &tenancyv1alpha1.WorkspaceExpiration{Timestamp: &metav1.Time{Time: t}}
+	}
+	expiring := &conditionsv1alpha1.Condition{
+		Type:               tenancyv1alpha1.WorkspaceExpiring,
+		Status:             corev1.ConditionTrue,
+		Reason:             tenancyv1alpha1.WorkspaceExpiringReasonScheduled,
+		LastTransitionTime: metav1.NewTime(now.Add(-time.Hour)),
+	}
+
+	tests := map[string]struct {
+		expiration   *tenancyv1alpha1.WorkspaceExpiration
+		condition    *conditionsv1alpha1.Condition
+		deleting     bool
+		wantExpiring bool
+		wantDeleted  bool
+		wantRequeue  time.Duration
+	}{
+		"workspace without expiration is ignored": {},
+		"removed expiration clears the condition": {
+			condition: expiring,
+		},
+		"workspace is requeued until the warning period": {
+			expiration:  ttl(96 * time.Hour),
+			wantRequeue: 24 * time.Hour,
+		},
+		"postponed expiration clears the condition": {
+			expiration:  timestamp(now.Add(72 * time.Hour)),
+			condition:   expiring,
+			wantRequeue: 48 * time.Hour,
+		},
+		"workspace in the warning period is marked as expiring": {
+			expiration:   ttl(50 * time.Hour),
+			wantExpiring: true,
+			wantRequeue:  2 * time.Hour,
+		},
+		"earlier of ttl and timestamp applies": {
+			expiration: &tenancyv1alpha1.WorkspaceExpiration{
+				TTL:       &metav1.Duration{Duration: 96 * time.Hour},
+				Timestamp: &metav1.Time{Time: now.Add(time.Hour)},
+			},
+			wantExpiring: true,
+			wantRequeue:  time.Hour,
+		},
+		"expired workspace is deleted": {
+			expiration:   ttl(48 * time.Hour),
+			condition:    expiring,
+			wantExpiring: true,
+			wantDeleted:  true,
+		},
+		"expired workspace is deleted without prior warning": {
+			expiration:  timestamp(now.Add(-time.Minute)),
+			wantDeleted: true,
+		},
+		"deleting workspace is ignored": {
+			expiration:   ttl(time.Hour),
+			condition:    expiring,
+			deleting:     true,
+			wantExpiring: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var requeue time.Duration
+			var deleted bool
+
+			c := &controller{
+				enqueueAfter:  func(_ *tenancyv1alpha1.ClusterWorkspace, d time.Duration) { requeue = d },
+				now:           func() time.Time { return now },
+				warningPeriod: 24 * time.Hour,
+				deleteWorkspace: func(ctx context.Context, clusterName logicalcluster.Name, ws *tenancyv1alpha1.ClusterWorkspace) error {
+					require.Equal(t, logicalcluster.New("root:org"), clusterName)
+					require.Equal(t, "ws", ws.Name)
+					deleted = true
+					return nil
+				},
+			}
+
+			ws := &tenancyv1alpha1.ClusterWorkspace{
+				ObjectMeta: metav1.ObjectMeta{Name: "ws", ClusterName: "root:org", CreationTimestamp: metav1.NewTime(created)},
+				Spec:       tenancyv1alpha1.ClusterWorkspaceSpec{Expiration: tc.expiration},
+			}
+			if tc.deleting {
+				ws.DeletionTimestamp = &metav1.Time{Time: now}
+			}
+			if tc.condition != nil {
+				conditions.Set(ws, tc.condition.DeepCopy())
+			}
+
+			require.NoError(t, c.reconcile(context.Background(), ws))
+
+			require.Equal(t, tc.wantExpiring, conditions.IsTrue(ws, tenancyv1alpha1.WorkspaceExpiring))
+			require.Equal(t, tc.wantDeleted, deleted)
+			require.Equal(t, tc.wantRequeue, requeue)
+		})
+	}
+}
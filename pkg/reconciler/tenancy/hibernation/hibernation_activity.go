@@ -0,0 +1,138 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hibernation
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/apiserver/pkg/authentication/serviceaccount"
+	"k8s.io/apiserver/pkg/authentication/user"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+
+	"github.com/kcp-dev/kcp/pkg/syncer/shared"
+)
+
+// ActivityTracker records the last API activity of logical clusters served by this
+// shard, and wakes hibernated workspaces on their next request.
+type ActivityTracker struct {
+	now     func() time.Time
+	started time.Time
+
+	lock       sync.RWMutex
+	last       map[logicalcluster.Name]time.Time
+	hibernated map[logicalcluster.Name]bool
+	wake       func(logicalcluster.Name)
+}
+
+// NewActivityTracker returns an ActivityTracker without any activity.
+func NewActivityTracker() *ActivityTracker {
+	t := &ActivityTracker{
+		now:        time.Now,
+		last:       map[logicalcluster.Name]time.Time{},
+		hibernated: map[logicalcluster.Name]bool{},
+	}
+	t.started = t.now()
+	return t
+}
+
+// WithActivityTracking records the requests served by handler as activity of their
+// logical cluster. Requests of kcp itself, e.g. of its controllers, and of syncers
+// are not activity. It must run after the authentication filter.
+func (t *ActivityTracker) WithActivityTracking(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		cluster := apirequest.ClusterFrom(req.Context())
+		u, ok := apirequest.UserFrom(req.Context())
+		if cluster != nil && !cluster.Name.Empty() && !cluster.Wildcard && ok && !isSystemUser(u) {
+			t.touch(cluster.Name)
+		}
+		handler.ServeHTTP(w, req)
+	})
+}
+
+func isSystemUser(u user.Info) bool {
+	if u.GetName() == user.APIServerUser || strings.HasPrefix(u.GetName(), "system:kcp:") {
+		return true
+	}
+	if _, name, err := serviceaccount.SplitUsername(u.GetName()); err == nil && strings.HasPrefix(name, shared.SyncerAuthResourcePrefix) {
+		return true
+	}
+	return false
+}
+
+func (t *ActivityTracker) touch(cluster logicalcluster.Name) {
+	t.lock.Lock()
+	t.last[cluster] = t.now()
+	wake := t.hibernated[cluster] && t.wake != nil
+	if wake {
+		// only wake once
+		delete(t.hibernated, cluster)
+	}
+	t.lock.Unlock()
+
+	if wake {
+		t.wake(cluster)
+	}
+}
+
+// LastActivity returns the time of the last activity of the logical cluster, and
+// false if there was none since the tracker was started.
+func (t *ActivityTracker) LastActivity(cluster logicalcluster.Name) (time.Time, bool) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	last, ok := t.last[cluster]
+	return last, ok
+}
+
+// Started returns when the tracker started to record activity.
+func (t *ActivityTracker) Started() time.Time {
+	return t.started
+}
+
+// SetHibernated sets whether the logical cluster is hibernated, i.e. whether the
+// next activity wakes it.
+func (t *ActivityTracker) SetHibernated(cluster logicalcluster.Name, hibernated bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if hibernated {
+		t.hibernated[cluster] = true
+	} else {
+		delete(t.hibernated, cluster)
+	}
+}
+
+// Forget removes the logical cluster from the tracker, e.g. when the workspace is deleted.
+func (t *ActivityTracker) Forget(cluster logicalcluster.Name) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	delete(t.last, cluster)
+	delete(t.hibernated, cluster)
+}
+
+func (t *ActivityTracker) setWakeFunc(wake func(logicalcluster.Name)) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.wake = wake
+}
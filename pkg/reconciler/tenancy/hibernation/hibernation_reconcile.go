@@ -0,0 +1,175 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hibernation
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/third_party/conditions/util/conditions"
+)
+
+func (c *controller) reconcile(ctx context.Context, ws *tenancyv1alpha1.ClusterWorkspace) error {
+	if ws.Status.Phase != tenancyv1alpha1.ClusterWorkspacePhaseReady {
+		return nil
+	}
+
+	clusterName := logicalcluster.From(ws).Join(ws.Name)
+	last, found := c.tracker.LastActivity(clusterName)
+
+	if conditions.IsTrue(ws, tenancyv1alpha1.WorkspaceHibernated) {
+		// only recorded requests wake it up, not our own start.
+		if !found || !last.After(conditions.GetLastTransitionTime(ws, tenancyv1alpha1.WorkspaceHibernated).Time) {
+			// still asleep. This also restores the state after a restart.
+			c.tracker.SetHibernated(clusterName, true)
+			return nil
+		}
+
+		klog.Infof("Waking up hibernated ClusterWorkspace %s|%s", logicalcluster.From(ws), ws.Name)
+		if err := c.scaleUp(ctx, clusterName); err != nil {
+			return err
+		}
+		conditions.Delete(ws, tenancyv1alpha1.WorkspaceHibernated)
+		c.tracker.SetHibernated(clusterName, false)
+		c.enqueueAfter(ws, c.idleTimeout)
+		return nil
+	}
+
+	if !found {
+		// no request since we started, so it is idle at least since then.
+		last = c.tracker.Started()
+	}
+
+	now := c.now()
+	if idle := now.Sub(last); idle < c.idleTimeout {
+		c.enqueueAfter(ws, c.idleTimeout-idle)
+		return nil
+	}
+
+	klog.Infof("Hibernating ClusterWorkspace %s|%s, idle since %s", logicalcluster.From(ws), ws.Name, last.Format(time.RFC3339))
+	if err := c.scaleDown(ctx, clusterName); err != nil {
+		return err
+	}
+	conditions.Set(ws, &conditionsv1alpha1.Condition{
+		Type:               tenancyv1alpha1.WorkspaceHibernated,
+		Status:             corev1.ConditionTrue,
+		Severity:           conditionsv1alpha1.ConditionSeverityNone,
+		Reason:             tenancyv1alpha1.WorkspaceHibernatedReasonIdle,
+		Message:            fmt.Sprintf("No API activity since %s.", last.UTC().Format(time.RFC3339)),
+		LastTransitionTime: metav1.NewTime(now),
+	})
+	c.tracker.SetHibernated(clusterName, true)
+
+	return nil
+}
+
+// scaleDown sets the replicas of the synced Deployments of the workspace to 0,
+// remembering the former replicas in an annotation.
+func (c *controller) scaleDown(ctx context.Context, clusterName logicalcluster.Name) error {
+	deployments, err := c.listDeployments(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+
+	for i := range deployments {
+		d := &deployments[i]
+		if !isSynced(d.GetLabels()) {
+			continue
+		}
+		if _, found := d.GetAnnotations()[HibernatedReplicasAnnotation]; found {
+			continue // already scaled down
+		}
+
+		replicas, found, err := unstructured.NestedInt64(d.Object, "spec", "replicas")
+		if err != nil {
+			return err
+		}
+		if !found {
+			replicas = 1 // the default of Deployments
+		}
+		if replicas == 0 {
+			continue
+		}
+
+		annotations := d.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[HibernatedReplicasAnnotation] = strconv.FormatInt(replicas, 10)
+		d.SetAnnotations(annotations)
+		if err := unstructured.SetNestedField(d.Object, int64(0), "spec", "replicas"); err != nil {
+			return err
+		}
+		if err := c.updateDeployment(ctx, clusterName, d); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// scaleUp restores the replicas of the Deployments scaled down by scaleDown.
+func (c *controller) scaleUp(ctx context.Context, clusterName logicalcluster.Name) error {
+	deployments, err := c.listDeployments(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+
+	for i := range deployments {
+		d := &deployments[i]
+		value, found := d.GetAnnotations()[HibernatedReplicasAnnotation]
+		if !found {
+			continue
+		}
+
+		annotations := d.GetAnnotations()
+		delete(annotations, HibernatedReplicasAnnotation)
+		d.SetAnnotations(annotations)
+		if replicas, err := strconv.ParseInt(value, 10, 32); err != nil {
+			klog.Errorf("Invalid %s annotation on Deployment %s|%s/%s: %v", HibernatedReplicasAnnotation, clusterName, d.GetNamespace(), d.GetName(), err)
+		} else if err := unstructured.SetNestedField(d.Object, replicas, "spec", "replicas"); err != nil {
+			return err
+		}
+		if err := c.updateDeployment(ctx, clusterName, d); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func isSynced(ls map[string]string) bool {
+	for k, v := range ls {
+		if strings.HasPrefix(k, workloadv1alpha1.InternalClusterResourceStateLabelPrefix) && v == string(workloadv1alpha1.ResourceStateSync) {
+			return true
+		}
+	}
+	return false
+}
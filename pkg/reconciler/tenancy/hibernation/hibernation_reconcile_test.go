@@ -0,0 +1,231 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hibernation
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apiserver/pkg/authentication/user"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/third_party/conditions/util/conditions"
+)
+
+func TestReconcile(t *testing.T) {
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	started := now.Add(-2 * time.Hour)
+	wsCluster := logicalcluster.New("root:org:ws")
+
+	deployment := func(name string, replicas int64, labels, annotations map[string]string) unstructured.Unstructured {
+		d := unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"spec":       map[string]interface{}{"replicas": replicas},
+		}}
+		d.SetName(name)
+		d.SetNamespace("default")
+		d.SetLabels(labels)
+		d.SetAnnotations(annotations)
+		return d
+	}
+	synced := map[string]string{"state.internal.workloads.kcp.dev/us-east1": "Sync"}
+	hibernated := &conditionsv1alpha1.Condition{
+		Type:               tenancyv1alpha1.WorkspaceHibernated,
+		Status:             corev1.ConditionTrue,
+		Reason:             tenancyv1alpha1.WorkspaceHibernatedReasonIdle,
+		LastTransitionTime: metav1.NewTime(now.Add(-30 * time.Minute)),
+	}
+
+	tests := map[string]struct {
+		phase           tenancyv1alpha1.ClusterWorkspacePhaseType
+		condition       *conditionsv1alpha1.Condition
+		lastActivity    time.Time
+		restarted       bool
+		deployments     []unstructured.Unstructured
+		wantHibernated  bool
+		wantTracked     bool
+		wantReplicas    map[string]int64
+		wantAnnotations map[string]string
+		wantRequeue     time.Duration
+	}{
+		"active workspace is requeued for the rest of the idle timeout": {
+			phase:        tenancyv1alpha1.ClusterWorkspacePhaseReady,
+			lastActivity: now.Add(-20 * time.Minute),
+			deployments:  []unstructured.Unstructured{deployment("web", 3, synced, nil)},
+			wantReplicas: map[string]int64{"web": 3},
+			wantRequeue:  40 * time.Minute,
+		},
+		"idle workspace is hibernated": {
+			phase:        tenancyv1alpha1.ClusterWorkspacePhaseReady,
+			lastActivity: now.Add(-2 * time.Hour),
+			deployments: []unstructured.Unstructured{
+				deployment("web", 3, synced, nil),
+				deployment("local", 2, nil, nil),
+			},
+			wantHibernated:  true,
+			wantTracked:     true,
+			wantReplicas:    map[string]int64{"web": 0, "local": 2},
+			wantAnnotations: map[string]string{"web": "3"},
+		},
+		"workspace without activity since start is hibernated": {
+			phase:           tenancyv1alpha1.ClusterWorkspacePhaseReady,
+			deployments:     []unstructured.Unstructured{deployment("web", 1, synced, nil)},
+			wantHibernated:  true,
+			wantTracked:     true,
+			wantReplicas:    map[string]int64{"web": 0},
+			wantAnnotations: map[string]string{"web": "1"},
+		},
+		"hibernated workspace without activity stays hibernated": {
+			phase:           tenancyv1alpha1.ClusterWorkspacePhaseReady,
+			condition:       hibernated,
+			lastActivity:    now.Add(-time.Hour),
+			deployments:     []unstructured.Unstructured{deployment("web", 0, synced, map[string]string{HibernatedReplicasAnnotation: "3"})},
+			wantHibernated:  true,
+			wantTracked:     true,
+			wantReplicas:    map[string]int64{"web": 0},
+			wantAnnotations: map[string]string{"web": "3"},
+		},
+		"hibernated workspace stays hibernated after a restart": {
+			phase:           tenancyv1alpha1.ClusterWorkspacePhaseReady,
+			condition:       hibernated,
+			restarted:       true,
+			deployments:     []unstructured.Unstructured{deployment("web", 0, synced, map[string]string{HibernatedReplicasAnnotation: "3"})},
+			wantHibernated:  true,
+			wantTracked:     true,
+			wantReplicas:    map[string]int64{"web": 0},
+			wantAnnotations: map[string]string{"web": "3"},
+		},
+		"active workspace is not hibernated right after a restart": {
+			phase:        tenancyv1alpha1.ClusterWorkspacePhaseReady,
+			restarted:    true,
+			deployments:  []unstructured.Unstructured{deployment("web", 3, synced, nil)},
+			wantReplicas: map[string]int64{"web": 3},
+			wantRequeue:  55 * time.Minute,
+		},
+		"hibernated workspace with activity wakes up": {
+			phase:        tenancyv1alpha1.ClusterWorkspacePhaseReady,
+			condition:    hibernated,
+			lastActivity: now.Add(-time.Minute),
+			deployments:  []unstructured.Unstructured{deployment("web", 0, synced, map[string]string{HibernatedReplicasAnnotation: "3"})},
+			wantReplicas: map[string]int64{"web": 3},
+			wantRequeue:  time.Hour,
+		},
+		"initializing workspace is ignored": {
+			phase:        tenancyv1alpha1.ClusterWorkspacePhaseInitializing,
+			deployments:  []unstructured.Unstructured{deployment("web", 3, synced, nil)},
+			wantReplicas: map[string]int64{"web": 3},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			tracker := NewActivityTracker()
+			tracker.started = started
+			if tc.restarted {
+				// started after the workspace was hibernated, without activity since.
+				tracker.started = now.Add(-5 * time.Minute)
+			}
+			if !tc.lastActivity.IsZero() {
+				tracker.last[wsCluster] = tc.lastActivity
+			}
+
+			deployments := map[string]*unstructured.Unstructured{}
+			for i := range tc.deployments {
+				deployments[tc.deployments[i].GetName()] = &tc.deployments[i]
+			}
+			var requeue time.Duration
+
+			c := &controller{
+				enqueueAfter: func(_ *tenancyv1alpha1.ClusterWorkspace, d time.Duration) { requeue = d },
+				now:          func() time.Time { return now },
+				idleTimeout:  time.Hour,
+				tracker:      tracker,
+				listDeployments: func(ctx context.Context, clusterName logicalcluster.Name) ([]unstructured.Unstructured, error) {
+					require.Equal(t, wsCluster, clusterName)
+					var ret []unstructured.Unstructured
+					for _, d := range deployments {
+						ret = append(ret, *d.DeepCopy())
+					}
+					return ret, nil
+				},
+				updateDeployment: func(ctx context.Context, clusterName logicalcluster.Name, d *unstructured.Unstructured) error {
+					deployments[d.GetName()] = d
+					return nil
+				},
+			}
+
+			ws := &tenancyv1alpha1.ClusterWorkspace{
+				ObjectMeta: metav1.ObjectMeta{Name: "ws", ClusterName: "root:org"},
+				Status:     tenancyv1alpha1.ClusterWorkspaceStatus{Phase: tc.phase},
+			}
+			if tc.condition != nil {
+				conditions.Set(ws, tc.condition.DeepCopy())
+			}
+
+			require.NoError(t, c.reconcile(context.Background(), ws))
+
+			require.Equal(t, tc.wantHibernated, conditions.IsTrue(ws, tenancyv1alpha1.WorkspaceHibernated))
+			require.Equal(t, tc.wantTracked, tracker.hibernated[wsCluster])
+			require.Equal(t, tc.wantRequeue, requeue)
+			for name, want := range tc.wantReplicas {
+				replicas, _, err := unstructured.NestedInt64(deployments[name].Object, "spec", "replicas")
+				require.NoError(t, err)
+				require.Equal(t, want, replicas, "replicas of %s", name)
+				require.Equal(t, tc.wantAnnotations[name], deployments[name].GetAnnotations()[HibernatedReplicasAnnotation], "annotation of %s", name)
+			}
+		})
+	}
+}
+
+func TestActivityTracker(t *testing.T) {
+	tracker := NewActivityTracker()
+	var woken []logicalcluster.Name
+	tracker.setWakeFunc(func(clusterName logicalcluster.Name) { woken = append(woken, clusterName) })
+
+	request := func(clusterName logicalcluster.Name, userName string) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		ctx := apirequest.WithCluster(req.Context(), apirequest.Cluster{Name: clusterName, Wildcard: clusterName == logicalcluster.Wildcard})
+		ctx = apirequest.WithUser(ctx, &user.DefaultInfo{Name: userName})
+		tracker.WithActivityTracking(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})).ServeHTTP(httptest.NewRecorder(), req.WithContext(ctx))
+	}
+
+	ws := logicalcluster.New("root:org:ws")
+	request(ws, user.APIServerUser)
+	request(ws, "system:kcp:logical-cluster-admin")
+	request(ws, "system:serviceaccount:default:syncer-us-east1")
+	request(logicalcluster.Wildcard, "alice")
+	_, found := tracker.LastActivity(ws)
+	require.False(t, found, "requests of kcp itself are no activity")
+
+	tracker.SetHibernated(ws, true)
+	request(ws, "alice")
+	request(ws, "alice")
+	_, found = tracker.LastActivity(ws)
+	require.True(t, found)
+	require.Equal(t, []logicalcluster.Name{ws}, woken, "a hibernated workspace is woken once")
+}
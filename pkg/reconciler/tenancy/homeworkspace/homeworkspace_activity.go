@@ -0,0 +1,126 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package homeworkspace
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	kcpclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
+)
+
+// activityRecorder persists the requests of users to this shard in the last activity
+// annotation of their home workspaces, through a client reaching the workspaces of all
+// shards, e.g. the front-proxy. The home workspace controller only sees the requests
+// to its own shard, and the home workspace may live on another one.
+type activityRecorder struct {
+	listPolicies   func(ctx context.Context) ([]tenancyv1alpha1.HomeWorkspacePolicy, error)
+	getWorkspace   func(ctx context.Context, clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error)
+	patchWorkspace func(ctx context.Context, clusterName logicalcluster.Name, name string, patch []byte) error
+}
+
+func newActivityRecorder(kcpClusterClient kcpclient.ClusterInterface) *activityRecorder {
+	return &activityRecorder{
+		listPolicies: func(ctx context.Context) ([]tenancyv1alpha1.HomeWorkspacePolicy, error) {
+			policies, err := kcpClusterClient.Cluster(tenancyv1alpha1.RootCluster).TenancyV1alpha1().HomeWorkspacePolicies().List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			return policies.Items, nil
+		},
+		getWorkspace: func(ctx context.Context, clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error) {
+			return kcpClusterClient.Cluster(clusterName).TenancyV1alpha1().ClusterWorkspaces().Get(ctx, name, metav1.GetOptions{})
+		},
+		patchWorkspace: func(ctx context.Context, clusterName logicalcluster.Name, name string, patch []byte) error {
+			_, err := kcpClusterClient.Cluster(clusterName).TenancyV1alpha1().ClusterWorkspaces().Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+			return err
+		},
+	}
+}
+
+// record persists the last request of the user in the home workspaces of the user at
+// the locations of all policies, if it is an activity resolution newer than the one
+// persisted.
+func (r *activityRecorder) record(ctx context.Context, userName string, login Login) error {
+	policies, err := r.listPolicies(ctx)
+	if err != nil {
+		return err
+	}
+
+	name := homeName(userName)
+	seen := map[logicalcluster.Name]bool{}
+	for i := range policies {
+		parent := homeParent(&policies[i], userName)
+		if seen[parent] {
+			continue
+		}
+		seen[parent] = true
+
+		ws, err := r.getWorkspace(ctx, parent, name)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		if ws.Labels[tenancyv1alpha1.HomeWorkspaceLabel] != "true" || ws.Annotations[tenancyv1alpha1.HomeWorkspaceOwnerAnnotation] != userName {
+			continue
+		}
+		if login.LastSeen.Sub(persistedActivity(ws)) < activityResolution {
+			continue
+		}
+
+		klog.V(4).Infof("Recording activity of user %q in home ClusterWorkspace %s|%s", userName, parent, name)
+		patch, err := json.Marshal(map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"annotations": map[string]string{
+					tenancyv1alpha1.HomeWorkspaceLastActivityAnnotation: login.LastSeen.UTC().Format(time.RFC3339),
+				},
+			},
+		})
+		if err != nil {
+			return err
+		}
+		if err := r.patchWorkspace(ctx, parent, name, patch); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// persistedActivity returns the last activity persisted in the annotation of a home
+// workspace, or its creation if there is none.
+func persistedActivity(ws *tenancyv1alpha1.ClusterWorkspace) time.Time {
+	value, found := ws.Annotations[tenancyv1alpha1.HomeWorkspaceLastActivityAnnotation]
+	if !found {
+		return ws.CreationTimestamp.Time
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		klog.Errorf("Invalid %s annotation on home ClusterWorkspace %s|%s: %v", tenancyv1alpha1.HomeWorkspaceLastActivityAnnotation, logicalcluster.From(ws), ws.Name, err)
+		return ws.CreationTimestamp.Time
+	}
+	return t
+}
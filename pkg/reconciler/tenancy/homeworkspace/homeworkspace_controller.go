@@ -0,0 +1,276 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package homeworkspace
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clusters"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	kcpclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
+	tenancyinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/reconciler/latency"
+)
+
+const (
+	controllerName = "kcp-home-workspace"
+
+	byOwnerIndex = "homeWorkspace-byOwner"
+
+	// activityResolution is how often the last activity of a home workspace is persisted.
+	activityResolution = time.Hour
+)
+
+// NewController returns a new controller that provisions home workspaces for users on
+// their first request according to the HomeWorkspacePolicies in the root workspace,
+// keeps their limits in sync with the policy, and reaps them when they are unused.
+// The queue is keyed by user name. With activityClusterClient reaching the workspaces
+// of all shards, the requests to this shard are recorded in home workspaces on others.
+func NewController(
+	kubeClusterClient kubernetes.ClusterInterface,
+	kcpClusterClient kcpclient.ClusterInterface,
+	homeWorkspacePolicyInformer tenancyinformers.HomeWorkspacePolicyInformer,
+	workspaceInformer tenancyinformers.ClusterWorkspaceInformer,
+	workspaceTypeInformer tenancyinformers.ClusterWorkspaceTypeInformer,
+	tracker *LoginTracker,
+	activityClusterClient kcpclient.ClusterInterface,
+) (*controller, error) {
+	queue := latency.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+
+	c := &controller{
+		queue: queue,
+		enqueueAfter: func(userName string, duration time.Duration) {
+			queue.AddAfter(userName, duration)
+		},
+		now:      time.Now,
+		getLogin: tracker.Login,
+		listPolicies: func() ([]*tenancyv1alpha1.HomeWorkspacePolicy, error) {
+			policies, err := homeWorkspacePolicyInformer.Lister().List(labels.Everything())
+			if err != nil {
+				return nil, err
+			}
+			var ret []*tenancyv1alpha1.HomeWorkspacePolicy
+			for _, p := range policies {
+				if logicalcluster.From(p) == tenancyv1alpha1.RootCluster {
+					ret = append(ret, p)
+				}
+			}
+			return ret, nil
+		},
+		listHomes: func(userName string) ([]*tenancyv1alpha1.ClusterWorkspace, error) {
+			objs, err := workspaceInformer.Informer().GetIndexer().ByIndex(byOwnerIndex, userName)
+			if err != nil {
+				return nil, err
+			}
+			homes := make([]*tenancyv1alpha1.ClusterWorkspace, 0, len(objs))
+			for _, obj := range objs {
+				homes = append(homes, obj.(*tenancyv1alpha1.ClusterWorkspace))
+			}
+			return homes, nil
+		},
+		getWorkspace: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error) {
+			return workspaceInformer.Lister().Get(clusters.ToClusterAwareKey(clusterName, name))
+		},
+		createWorkspace: func(ctx context.Context, clusterName logicalcluster.Name, ws *tenancyv1alpha1.ClusterWorkspace) error {
+			_, err := kcpClusterClient.Cluster(clusterName).TenancyV1alpha1().ClusterWorkspaces().Create(ctx, ws, metav1.CreateOptions{})
+			return err
+		},
+		updateWorkspace: func(ctx context.Context, ws *tenancyv1alpha1.ClusterWorkspace) error {
+			_, err := kcpClusterClient.Cluster(logicalcluster.From(ws)).TenancyV1alpha1().ClusterWorkspaces().Update(ctx, ws, metav1.UpdateOptions{})
+			return err
+		},
+		deleteWorkspace: func(ctx context.Context, clusterName logicalcluster.Name, name string) error {
+			return kcpClusterClient.Cluster(clusterName).TenancyV1alpha1().ClusterWorkspaces().Delete(ctx, name, metav1.DeleteOptions{})
+		},
+		getWorkspaceType: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspaceType, error) {
+			return workspaceTypeInformer.Lister().Get(clusters.ToClusterAwareKey(clusterName, name))
+		},
+		createWorkspaceType: func(ctx context.Context, clusterName logicalcluster.Name, cwt *tenancyv1alpha1.ClusterWorkspaceType) error {
+			_, err := kcpClusterClient.Cluster(clusterName).TenancyV1alpha1().ClusterWorkspaceTypes().Create(ctx, cwt, metav1.CreateOptions{})
+			return err
+		},
+		createClusterRole: func(ctx context.Context, clusterName logicalcluster.Name, role *rbacv1.ClusterRole) error {
+			_, err := kubeClusterClient.Cluster(clusterName).RbacV1().ClusterRoles().Create(ctx, role, metav1.CreateOptions{})
+			return err
+		},
+		deleteClusterRole: func(ctx context.Context, clusterName logicalcluster.Name, name string) error {
+			return kubeClusterClient.Cluster(clusterName).RbacV1().ClusterRoles().Delete(ctx, name, metav1.DeleteOptions{})
+		},
+		createClusterRoleBinding: func(ctx context.Context, clusterName logicalcluster.Name, binding *rbacv1.ClusterRoleBinding) error {
+			_, err := kubeClusterClient.Cluster(clusterName).RbacV1().ClusterRoleBindings().Create(ctx, binding, metav1.CreateOptions{})
+			return err
+		},
+		deleteClusterRoleBinding: func(ctx context.Context, clusterName logicalcluster.Name, name string) error {
+			return kubeClusterClient.Cluster(clusterName).RbacV1().ClusterRoleBindings().Delete(ctx, name, metav1.DeleteOptions{})
+		},
+		listUsers: tracker.names,
+	}
+
+	if err := workspaceInformer.Informer().AddIndexers(cache.Indexers{
+		byOwnerIndex: func(obj interface{}) ([]string, error) {
+			ws, ok := obj.(*tenancyv1alpha1.ClusterWorkspace)
+			if !ok {
+				return nil, fmt.Errorf("unexpected object type %T", obj)
+			}
+			if owner, found := ws.Annotations[tenancyv1alpha1.HomeWorkspaceOwnerAnnotation]; found && ws.Labels[tenancyv1alpha1.HomeWorkspaceLabel] == "true" {
+				return []string{owner}, nil
+			}
+			return nil, nil
+		},
+	}); err != nil {
+		return nil, fmt.Errorf("failed to add indexer for ClusterWorkspace: %w", err)
+	}
+	if activityClusterClient != nil {
+		c.recordActivity = newActivityRecorder(activityClusterClient).record
+	}
+	c.listOwners = func() []string {
+		return workspaceInformer.Informer().GetIndexer().ListIndexFuncValues(byOwnerIndex)
+	}
+
+	workspaceInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueueWorkspace(obj) },
+		UpdateFunc: func(_, obj interface{}) { c.enqueueWorkspace(obj) },
+		DeleteFunc: func(obj interface{}) { c.enqueueWorkspace(obj) },
+	})
+
+	// policy changes can affect every user.
+	homeWorkspacePolicyInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueueAll("policy added") },
+		UpdateFunc: func(_, obj interface{}) { c.enqueueAll("policy updated") },
+		DeleteFunc: func(obj interface{}) { c.enqueueAll("policy deleted") },
+	})
+
+	tracker.setNotifyFunc(func(userName string) {
+		klog.V(4).Infof("Queueing user %q because of a request", userName)
+		queue.Add(userName)
+	})
+
+	return c, nil
+}
+
+// controller provisions, limits and reaps home workspaces.
+type controller struct {
+	queue        workqueue.RateLimitingInterface
+	enqueueAfter func(userName string, duration time.Duration)
+	now          func() time.Time
+
+	getLogin     func(userName string) (Login, bool)
+	listUsers    func() []string
+	listOwners   func() []string
+	listPolicies func() ([]*tenancyv1alpha1.HomeWorkspacePolicy, error)
+	listHomes    func(userName string) ([]*tenancyv1alpha1.ClusterWorkspace, error)
+
+	// recordActivity persists the requests of users to this shard in their home
+	// workspaces on other shards. It is nil with a single shard.
+	recordActivity func(ctx context.Context, userName string, login Login) error
+
+	getWorkspace             func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error)
+	createWorkspace          func(ctx context.Context, clusterName logicalcluster.Name, ws *tenancyv1alpha1.ClusterWorkspace) error
+	updateWorkspace          func(ctx context.Context, ws *tenancyv1alpha1.ClusterWorkspace) error
+	deleteWorkspace          func(ctx context.Context, clusterName logicalcluster.Name, name string) error
+	getWorkspaceType         func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspaceType, error)
+	createWorkspaceType      func(ctx context.Context, clusterName logicalcluster.Name, cwt *tenancyv1alpha1.ClusterWorkspaceType) error
+	createClusterRole        func(ctx context.Context, clusterName logicalcluster.Name, role *rbacv1.ClusterRole) error
+	deleteClusterRole        func(ctx context.Context, clusterName logicalcluster.Name, name string) error
+	createClusterRoleBinding func(ctx context.Context, clusterName logicalcluster.Name, binding *rbacv1.ClusterRoleBinding) error
+	deleteClusterRoleBinding func(ctx context.Context, clusterName logicalcluster.Name, name string) error
+}
+
+// enqueueWorkspace enqueues the owner of a home workspace.
+func (c *controller) enqueueWorkspace(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	ws, ok := obj.(*tenancyv1alpha1.ClusterWorkspace)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("unexpected object type %T", obj))
+		return
+	}
+	owner, found := ws.Annotations[tenancyv1alpha1.HomeWorkspaceOwnerAnnotation]
+	if !found || ws.Labels[tenancyv1alpha1.HomeWorkspaceLabel] != "true" {
+		return
+	}
+
+	klog.V(4).Infof("Queueing user %q because of home ClusterWorkspace %s|%s", owner, logicalcluster.From(ws), ws.Name)
+	c.queue.Add(owner)
+}
+
+// enqueueAll enqueues all users with a home workspace or a request since the start.
+func (c *controller) enqueueAll(reason string) {
+	klog.V(2).Infof("Queueing all users because %s", reason)
+	for _, name := range c.listOwners() {
+		c.queue.Add(name)
+	}
+	for _, name := range c.listUsers() {
+		c.queue.Add(name)
+	}
+}
+
+// Start starts the controller, which stops when ctx.Done() is closed.
+func (c *controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	klog.Infof("Starting %s controller", controllerName)
+	defer klog.Infof("Shutting down %s controller", controllerName)
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+
+	<-ctx.Done()
+}
+
+func (c *controller) startWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	userName := k.(string)
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(userName)
+
+	if err := c.reconcile(ctx, userName); err != nil {
+		runtime.HandleError(fmt.Errorf("%q controller failed to sync user %q, err: %w", controllerName, userName, err))
+		c.queue.AddRateLimited(userName)
+		return true
+	}
+	c.queue.Forget(userName)
+	return true
+}
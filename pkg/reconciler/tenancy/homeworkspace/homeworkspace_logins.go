@@ -0,0 +1,118 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package homeworkspace
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+)
+
+// loginResolution is the granularity in which the last request of a user is recorded.
+const loginResolution = time.Minute
+
+// Login is the last request of a user.
+type Login struct {
+	Groups   []string
+	LastSeen time.Time
+}
+
+// LoginTracker records the users sending requests to this shard, and notifies the
+// home workspace controller on the first request of a user, and then at most hourly.
+type LoginTracker struct {
+	now func() time.Time
+
+	lock   sync.RWMutex
+	logins map[string]Login
+	notify func(name string)
+}
+
+// NewLoginTracker returns a LoginTracker without any logins.
+func NewLoginTracker() *LoginTracker {
+	return &LoginTracker{
+		now:    time.Now,
+		logins: map[string]Login{},
+	}
+}
+
+// WithLoginTracking records the users of the requests served by handler. System users,
+// e.g. kcp itself, service accounts or anonymous users, are ignored. It must run after
+// the authentication filter.
+func (t *LoginTracker) WithLoginTracking(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if u, ok := apirequest.UserFrom(req.Context()); ok && u.GetName() != "" && !strings.HasPrefix(u.GetName(), "system:") {
+			t.seen(u.GetName(), u.GetGroups())
+		}
+		handler.ServeHTTP(w, req)
+	})
+}
+
+func (t *LoginTracker) seen(name string, groups []string) {
+	now := t.now()
+
+	t.lock.RLock()
+	last, found := t.logins[name]
+	t.lock.RUnlock()
+	if found && now.Sub(last.LastSeen) < loginResolution {
+		return
+	}
+
+	t.lock.Lock()
+	last, found = t.logins[name]
+	t.logins[name] = Login{
+		Groups:   append([]string(nil), groups...),
+		LastSeen: now,
+	}
+	notify := t.notify != nil && (!found || now.Sub(last.LastSeen) >= activityResolution)
+	t.lock.Unlock()
+
+	if notify {
+		t.notify(name)
+	}
+}
+
+// Login returns the last request of the user, and false if there was none since
+// the tracker was started.
+func (t *LoginTracker) Login(name string) (Login, bool) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	login, ok := t.logins[name]
+	return login, ok
+}
+
+// names returns the users seen since the tracker was started.
+func (t *LoginTracker) names() []string {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	names := make([]string, 0, len(t.logins))
+	for name := range t.logins {
+		names = append(names, name)
+	}
+	return names
+}
+
+func (t *LoginTracker) setNotifyFunc(notify func(name string)) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.notify = notify
+}
@@ -0,0 +1,38 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package homeworkspace
+
+import (
+	"github.com/spf13/pflag"
+)
+
+func DefaultOptions() *Options {
+	return &Options{}
+}
+
+func BindOptions(o *Options, fs *pflag.FlagSet) *Options {
+	fs.StringVar(&o.ActivityKubeconfig, "home-workspace-activity-kubeconfig", o.ActivityKubeconfig, "Kubeconfig reaching the workspaces of all shards, e.g. through the front-proxy, with which the requests of users to this shard are recorded in their home workspaces on other shards. Required with multiple shards and HomeWorkspacePolicies with reapAfter, as home workspaces are reaped otherwise although their owners use other shards.")
+	return o
+}
+
+type Options struct {
+	ActivityKubeconfig string
+}
+
+func (o *Options) Validate() error {
+	return nil
+}
@@ -0,0 +1,386 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package homeworkspace
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+const (
+	defaultHomeRoot        = "root:users"
+	universalType          = "Universal"
+	ownerRolePrefix        = "home-owner-"
+	parentNotReadyRetry    = 5 * time.Second
+	maxUnhashedNameLength  = 63
+	hashedNamePrefixLength = 50
+)
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+func (c *controller) reconcile(ctx context.Context, userName string) error {
+	policies, err := c.listPolicies()
+	if err != nil {
+		return err
+	}
+	login, loggedIn := c.getLogin(userName)
+
+	homes, err := c.listHomes(userName)
+	if err != nil {
+		return err
+	}
+	if len(homes) > 0 {
+		for _, ws := range homes {
+			if err := c.reconcileHome(ctx, userName, ws, login, loggedIn, policyByName(policies, ws.Annotations[tenancyv1alpha1.HomeWorkspacePolicyAnnotation])); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if !loggedIn {
+		return nil
+	}
+	if c.recordActivity != nil {
+		// the home workspace is not on this shard, or does not exist yet.
+		if err := c.recordActivity(ctx, userName, login); err != nil {
+			return err
+		}
+	}
+	policy := selectPolicy(policies, login.Groups)
+	if policy == nil {
+		return nil
+	}
+	return c.provision(ctx, userName, policy)
+}
+
+// reconcileHome reaps a home workspace unused for spec.reapAfter of its policy, or
+// otherwise persists the last activity and applies the limits of the policy. Home
+// workspaces of deleted policies are kept as they are.
+func (c *controller) reconcileHome(ctx context.Context, userName string, ws *tenancyv1alpha1.ClusterWorkspace, login Login, loggedIn bool, policy *tenancyv1alpha1.HomeWorkspacePolicy) error {
+	if !ws.DeletionTimestamp.IsZero() || policy == nil {
+		return nil
+	}
+
+	persisted := persistedActivity(ws)
+	last := persisted
+	if loggedIn && login.LastSeen.After(last) {
+		last = login.LastSeen
+	}
+
+	if policy.Spec.ReapAfter != nil && policy.Spec.ReapAfter.Duration > 0 {
+		reapAfter := policy.Spec.ReapAfter.Duration
+		idle := c.now().Sub(last)
+		if idle >= reapAfter {
+			klog.Infof("Reaping home ClusterWorkspace %s|%s of user %q, unused since %s", logicalcluster.From(ws), ws.Name, userName, last.UTC().Format(time.RFC3339))
+			return c.reap(ctx, ws)
+		}
+		c.enqueueAfter(userName, reapAfter-idle)
+	}
+
+	updated := ws.DeepCopy()
+	if last.Sub(persisted) >= activityResolution {
+		updated.Annotations[tenancyv1alpha1.HomeWorkspaceLastActivityAnnotation] = last.UTC().Format(time.RFC3339)
+	}
+	if !equality.Semantic.DeepEqual(updated.Spec.Limits, policy.Spec.Limits) {
+		updated.Spec.Limits = policy.Spec.Limits.DeepCopy()
+	}
+	if equality.Semantic.DeepEqual(ws, updated) {
+		return nil
+	}
+	return c.updateWorkspace(ctx, updated)
+}
+
+// reap deletes a home workspace and the RBAC of its owner.
+func (c *controller) reap(ctx context.Context, ws *tenancyv1alpha1.ClusterWorkspace) error {
+	parent := logicalcluster.From(ws)
+	if err := c.deleteWorkspace(ctx, parent, ws.Name); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if err := c.deleteClusterRoleBinding(ctx, parent, ownerRolePrefix+ws.Name); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if err := c.deleteClusterRole(ctx, parent, ownerRolePrefix+ws.Name); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// provision creates the home workspace of a user, with the bucket workspaces above it,
+// the workspace type if it is not Universal, and the RBAC making the user admin.
+func (c *controller) provision(ctx context.Context, userName string, policy *tenancyv1alpha1.HomeWorkspacePolicy) error {
+	parent := homeParent(policy, userName)
+	name := homeName(userName)
+
+	ready, err := c.ensureWorkspaces(ctx, parent)
+	if err != nil {
+		return err
+	}
+	if !ready {
+		c.enqueueAfter(userName, parentNotReadyRetry)
+		return nil
+	}
+
+	wsType := policy.Spec.WorkspaceType
+	if wsType == "" {
+		wsType = universalType
+	}
+	if wsType != universalType {
+		if ok, err := c.ensureWorkspaceType(ctx, logicalcluster.New(homeRoot(policy)), parent, wsType); err != nil {
+			return err
+		} else if !ok {
+			klog.Errorf("HomeWorkspacePolicy %q references ClusterWorkspaceType %q which does not exist in %s", policy.Name, wsType, homeRoot(policy))
+			return nil // wait for the policy or the type to be fixed
+		}
+	}
+
+	existing, err := c.getWorkspace(parent, name)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if existing != nil && existing.Annotations[tenancyv1alpha1.HomeWorkspaceOwnerAnnotation] != userName {
+		klog.Errorf("Cannot provision home workspace %s|%s for user %q: it exists and belongs to %q", parent, name, userName, existing.Annotations[tenancyv1alpha1.HomeWorkspaceOwnerAnnotation])
+		return nil
+	}
+
+	role, binding := ownerRBAC(userName, name)
+	if err := c.createClusterRole(ctx, parent, role); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+	if err := c.createClusterRoleBinding(ctx, parent, binding); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+	if existing != nil {
+		return nil
+	}
+
+	klog.Infof("Provisioning home ClusterWorkspace %s|%s for user %q by HomeWorkspacePolicy %q", parent, name, userName, policy.Name)
+	ws := &tenancyv1alpha1.ClusterWorkspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				tenancyv1alpha1.HomeWorkspaceLabel: "true",
+			},
+			Annotations: map[string]string{
+				tenancyv1alpha1.HomeWorkspaceOwnerAnnotation:        userName,
+				tenancyv1alpha1.HomeWorkspacePolicyAnnotation:       policy.Name,
+				tenancyv1alpha1.HomeWorkspaceLastActivityAnnotation: c.now().UTC().Format(time.RFC3339),
+			},
+		},
+		Spec: tenancyv1alpha1.ClusterWorkspaceSpec{
+			Type:   wsType,
+			Limits: policy.Spec.Limits.DeepCopy(),
+		},
+	}
+	if err := c.createWorkspace(ctx, parent, ws); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// ensureWorkspaces creates the workspaces of the given path below root, and returns
+// whether all of them are ready.
+func (c *controller) ensureWorkspaces(ctx context.Context, path logicalcluster.Name) (bool, error) {
+	segments := strings.Split(path.String(), ":")
+	parent := tenancyv1alpha1.RootCluster
+	for _, segment := range segments[1:] {
+		ws, err := c.getWorkspace(parent, segment)
+		if errors.IsNotFound(err) {
+			klog.Infof("Creating ClusterWorkspace %s|%s for home workspaces", parent, segment)
+			err := c.createWorkspace(ctx, parent, &tenancyv1alpha1.ClusterWorkspace{
+				ObjectMeta: metav1.ObjectMeta{Name: segment},
+				Spec:       tenancyv1alpha1.ClusterWorkspaceSpec{Type: universalType},
+			})
+			if err != nil && !errors.IsAlreadyExists(err) {
+				return false, err
+			}
+			return false, nil
+		} else if err != nil {
+			return false, err
+		}
+		if ws.Status.Phase != tenancyv1alpha1.ClusterWorkspacePhaseReady {
+			return false, nil
+		}
+		parent = parent.Join(segment)
+	}
+	return true, nil
+}
+
+// ensureWorkspaceType copies the ClusterWorkspaceType of the given name from the home root
+// into the parent of the home workspace, because types are looked up in the parent.
+// It returns false if the type does not exist in the home root.
+func (c *controller) ensureWorkspaceType(ctx context.Context, root, parent logicalcluster.Name, wsType string) (bool, error) {
+	name := strings.ToLower(wsType)
+	cwt, err := c.getWorkspaceType(root, name)
+	if errors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	if parent == root {
+		return true, nil
+	}
+
+	if _, err := c.getWorkspaceType(parent, name); err == nil {
+		return true, nil
+	} else if !errors.IsNotFound(err) {
+		return false, err
+	}
+	copied := &tenancyv1alpha1.ClusterWorkspaceType{
+		ObjectMeta: metav1.ObjectMeta{Name: cwt.Name},
+		Spec:       *cwt.Spec.DeepCopy(),
+	}
+	if err := c.createWorkspaceType(ctx, parent, copied); err != nil && !errors.IsAlreadyExists(err) {
+		return false, err
+	}
+	return true, nil
+}
+
+// ownerRBAC returns the ClusterRole and ClusterRoleBinding making the user admin of
+// their home workspace.
+func ownerRBAC(userName, name string) (*rbacv1.ClusterRole, *rbacv1.ClusterRoleBinding) {
+	role := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   ownerRolePrefix + name,
+			Labels: map[string]string{tenancyv1alpha1.HomeWorkspaceLabel: "true"},
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups:     []string{tenancyv1alpha1.SchemeGroupVersion.Group},
+				Resources:     []string{"clusterworkspaces/workspace"},
+				ResourceNames: []string{name},
+				Verbs:         []string{"get"},
+			},
+			{
+				APIGroups:     []string{tenancyv1alpha1.SchemeGroupVersion.Group},
+				Resources:     []string{"clusterworkspaces/content"},
+				ResourceNames: []string{name},
+				Verbs:         []string{"admin", "access"},
+			},
+		},
+	}
+	binding := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   ownerRolePrefix + name,
+			Labels: map[string]string{tenancyv1alpha1.HomeWorkspaceLabel: "true"},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     role.Name,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				APIGroup: rbacv1.GroupName,
+				Kind:     rbacv1.UserKind,
+				Name:     userName,
+			},
+		},
+	}
+	return role, binding
+}
+
+// selectPolicy returns the policy with the highest priority matching one of the groups,
+// and the first by name for equal priority, or nil if none matches.
+func selectPolicy(policies []*tenancyv1alpha1.HomeWorkspacePolicy, groups []string) *tenancyv1alpha1.HomeWorkspacePolicy {
+	gs := sets.NewString(groups...)
+	var matching []*tenancyv1alpha1.HomeWorkspacePolicy
+	for _, p := range policies {
+		if gs.HasAny(p.Spec.Groups...) {
+			matching = append(matching, p)
+		}
+	}
+	if len(matching) == 0 {
+		return nil
+	}
+	sort.Slice(matching, func(i, j int) bool {
+		if matching[i].Spec.Priority != matching[j].Spec.Priority {
+			return matching[i].Spec.Priority > matching[j].Spec.Priority
+		}
+		return matching[i].Name < matching[j].Name
+	})
+	return matching[0]
+}
+
+func policyByName(policies []*tenancyv1alpha1.HomeWorkspacePolicy, name string) *tenancyv1alpha1.HomeWorkspacePolicy {
+	for _, p := range policies {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+func homeRoot(policy *tenancyv1alpha1.HomeWorkspacePolicy) string {
+	if policy.Spec.HomeRoot == "" {
+		return defaultHomeRoot
+	}
+	return policy.Spec.HomeRoot
+}
+
+// homeParent returns the bucket workspace containing the home workspace of the user,
+// named after the hex encoded hash of the user name.
+func homeParent(policy *tenancyv1alpha1.HomeWorkspacePolicy, userName string) logicalcluster.Name {
+	levels, size := int(policy.Spec.BucketLevels), int(policy.Spec.BucketSize)
+	hash := hashOf(userName)
+	parent := logicalcluster.New(homeRoot(policy))
+	for i := 0; i < levels; i++ {
+		parent = parent.Join("b" + hash[i*size:(i+1)*size])
+	}
+	return parent
+}
+
+// homeName returns the name of the home workspace of the user. User names which are
+// not valid workspace names are sanitized, and suffixed with their hash to avoid
+// collisions.
+func homeName(userName string) string {
+	name := strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(userName), "-"), "-")
+	if name == userName && len(name) <= maxUnhashedNameLength && name[0] >= 'a' && name[0] <= 'z' {
+		return name
+	}
+
+	if len(name) > hashedNamePrefixLength {
+		name = strings.TrimRight(name[:hashedNamePrefixLength], "-")
+	}
+	if name == "" {
+		name = "u"
+	} else if name[0] < 'a' || name[0] > 'z' {
+		name = "u-" + name
+	}
+	return name + "-" + hashOf(userName)[:8]
+}
+
+func hashOf(userName string) string {
+	hash := sha256.Sum256([]byte(userName))
+	return hex.EncodeToString(hash[:])
+}
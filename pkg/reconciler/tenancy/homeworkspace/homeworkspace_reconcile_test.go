@@ -0,0 +1,459 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package homeworkspace
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/authentication/user"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/tools/clusters"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+func int64Ptr(i int64) *int64 {
+	return &i
+}
+
+func TestHomeName(t *testing.T) {
+	tests := map[string]string{
+		"alice":             "alice",
+		"Alice":             "alice-" + hashOf("Alice")[:8],
+		"alice@example.com": "alice-example-com-" + hashOf("alice@example.com")[:8],
+		"42":                "u-42-" + hashOf("42")[:8],
+		"@@@":               "u-" + hashOf("@@@")[:8],
+		"a-very-long-user-name-that-exceeds-the-maximum-length-of-a-workspace-name": "a-very-long-user-name-that-exceeds-the-maximum-len-" + hashOf("a-very-long-user-name-that-exceeds-the-maximum-length-of-a-workspace-name")[:8],
+	}
+	for userName, want := range tests {
+		t.Run(userName, func(t *testing.T) {
+			got := homeName(userName)
+			require.Equal(t, want, got)
+			require.LessOrEqual(t, len(got), 63)
+		})
+	}
+}
+
+func TestHomeParent(t *testing.T) {
+	hash := hashOf("alice")
+	policy := &tenancyv1alpha1.HomeWorkspacePolicy{Spec: tenancyv1alpha1.HomeWorkspacePolicySpec{BucketLevels: 2, BucketSize: 3}}
+	require.Equal(t, "root:users:b"+hash[0:3]+":b"+hash[3:6], homeParent(policy, "alice").String())
+
+	policy.Spec.HomeRoot = "root:homes"
+	policy.Spec.BucketLevels = 0
+	require.Equal(t, "root:homes", homeParent(policy, "alice").String())
+}
+
+func TestSelectPolicy(t *testing.T) {
+	policy := func(name string, priority int32, groups ...string) *tenancyv1alpha1.HomeWorkspacePolicy {
+		return &tenancyv1alpha1.HomeWorkspacePolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       tenancyv1alpha1.HomeWorkspacePolicySpec{Groups: groups, Priority: priority},
+		}
+	}
+	policies := []*tenancyv1alpha1.HomeWorkspacePolicy{
+		policy("everybody", 0, "system:authenticated"),
+		policy("b-staff", 10, "staff"),
+		policy("a-staff", 10, "staff"),
+		policy("admins", 20, "admins"),
+	}
+
+	require.Equal(t, "everybody", selectPolicy(policies, []string{"system:authenticated"}).Name)
+	require.Equal(t, "a-staff", selectPolicy(policies, []string{"system:authenticated", "staff"}).Name)
+	require.Equal(t, "admins", selectPolicy(policies, []string{"staff", "admins"}).Name)
+	require.Nil(t, selectPolicy(policies, []string{"guests"}))
+}
+
+func TestLoginTracker(t *testing.T) {
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	tracker := NewLoginTracker()
+	tracker.now = func() time.Time { return now }
+	var notified []string
+	tracker.setNotifyFunc(func(name string) { notified = append(notified, name) })
+
+	handler := tracker.WithLoginTracking(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}))
+	request := func(u user.Info) {
+		req := httptest.NewRequest(http.MethodGet, "/clusters/root:org/api/v1/namespaces", nil)
+		if u != nil {
+			req = req.WithContext(apirequest.WithUser(req.Context(), u))
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	request(&user.DefaultInfo{Name: "alice", Groups: []string{"staff", "system:authenticated"}})
+	request(&user.DefaultInfo{Name: "system:serviceaccount:default:default"})
+	request(&user.DefaultInfo{Name: "system:anonymous"})
+	request(nil)
+	require.Equal(t, []string{"alice"}, notified)
+	login, found := tracker.Login("alice")
+	require.True(t, found)
+	require.Equal(t, Login{Groups: []string{"staff", "system:authenticated"}, LastSeen: now}, login)
+	_, found = tracker.Login("system:anonymous")
+	require.False(t, found)
+
+	now = now.Add(30 * time.Minute)
+	request(&user.DefaultInfo{Name: "alice"})
+	require.Equal(t, []string{"alice"}, notified, "no notification within the activity resolution")
+	login, _ = tracker.Login("alice")
+	require.Equal(t, now, login.LastSeen)
+
+	now = now.Add(time.Hour)
+	request(&user.DefaultInfo{Name: "alice"})
+	require.Equal(t, []string{"alice", "alice"}, notified)
+}
+
+func TestReconcile(t *testing.T) {
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	hash := hashOf("alice")
+	bucket := logicalcluster.New("root:users:b" + hash[0:2])
+	leaf := bucket.Join("b" + hash[2:4])
+
+	policy := &tenancyv1alpha1.HomeWorkspacePolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "users", ClusterName: "root"},
+		Spec: tenancyv1alpha1.HomeWorkspacePolicySpec{
+			Groups:        []string{"system:authenticated"},
+			HomeRoot:      "root:users",
+			BucketLevels:  2,
+			BucketSize:    2,
+			WorkspaceType: "Universal",
+			Limits:        &tenancyv1alpha1.ClusterWorkspaceLimits{MaxObjectsPerResource: int64Ptr(100)},
+			ReapAfter:     &metav1.Duration{Duration: 30 * 24 * time.Hour},
+		},
+	}
+	readyWorkspace := func(clusterName logicalcluster.Name, name string) *tenancyv1alpha1.ClusterWorkspace {
+		return &tenancyv1alpha1.ClusterWorkspace{
+			ObjectMeta: metav1.ObjectMeta{Name: name, ClusterName: clusterName.String()},
+			Status:     tenancyv1alpha1.ClusterWorkspaceStatus{Phase: tenancyv1alpha1.ClusterWorkspacePhaseReady},
+		}
+	}
+	buckets := []*tenancyv1alpha1.ClusterWorkspace{
+		readyWorkspace(tenancyv1alpha1.RootCluster, "users"),
+		readyWorkspace(logicalcluster.New("root:users"), "b"+hash[0:2]),
+		readyWorkspace(bucket, "b"+hash[2:4]),
+	}
+	home := func(lastActivity time.Time, limits *tenancyv1alpha1.ClusterWorkspaceLimits) *tenancyv1alpha1.ClusterWorkspace {
+		ws := readyWorkspace(leaf, "alice")
+		ws.CreationTimestamp = metav1.NewTime(now.Add(-60 * 24 * time.Hour))
+		ws.Labels = map[string]string{tenancyv1alpha1.HomeWorkspaceLabel: "true"}
+		ws.Annotations = map[string]string{
+			tenancyv1alpha1.HomeWorkspaceOwnerAnnotation:        "alice",
+			tenancyv1alpha1.HomeWorkspacePolicyAnnotation:       "users",
+			tenancyv1alpha1.HomeWorkspaceLastActivityAnnotation: lastActivity.Format(time.RFC3339),
+		}
+		ws.Spec.Limits = limits
+		return ws
+	}
+
+	tests := map[string]struct {
+		login      *Login
+		workspaces []*tenancyv1alpha1.ClusterWorkspace
+		types      []*tenancyv1alpha1.ClusterWorkspaceType
+		policy     func(*tenancyv1alpha1.HomeWorkspacePolicy)
+
+		wantCreated      []string
+		wantCreatedType  string
+		wantRBAC         bool
+		wantUpdated      *tenancyv1alpha1.ClusterWorkspace
+		wantDeleted      []string
+		wantRequeueAfter time.Duration
+	}{
+		"user without request is ignored": {
+			workspaces: buckets,
+		},
+		"user without matching policy is ignored": {
+			login:      &Login{Groups: []string{"guests"}, LastSeen: now},
+			workspaces: buckets,
+			policy: func(p *tenancyv1alpha1.HomeWorkspacePolicy) {
+				p.Spec.Groups = []string{"staff"}
+			},
+		},
+		"missing bucket is created first": {
+			login:            &Login{Groups: []string{"system:authenticated"}, LastSeen: now},
+			workspaces:       buckets[:1],
+			wantCreated:      []string{"root:users|b" + hash[0:2]},
+			wantRequeueAfter: parentNotReadyRetry,
+		},
+		"home is provisioned in the leaf bucket": {
+			login:       &Login{Groups: []string{"system:authenticated"}, LastSeen: now},
+			workspaces:  buckets,
+			wantCreated: []string{clusters.ToClusterAwareKey(leaf, "alice")},
+			wantRBAC:    true,
+		},
+		"non-universal type is copied into the leaf bucket": {
+			login:      &Login{Groups: []string{"system:authenticated"}, LastSeen: now},
+			workspaces: buckets,
+			types: []*tenancyv1alpha1.ClusterWorkspaceType{
+				{ObjectMeta: metav1.ObjectMeta{Name: "personal", ClusterName: "root:users"}},
+			},
+			policy: func(p *tenancyv1alpha1.HomeWorkspacePolicy) {
+				p.Spec.WorkspaceType = "Personal"
+			},
+			wantCreated:     []string{clusters.ToClusterAwareKey(leaf, "alice")},
+			wantCreatedType: clusters.ToClusterAwareKey(leaf, "personal"),
+			wantRBAC:        true,
+		},
+		"missing type is not provisioned": {
+			login:      &Login{Groups: []string{"system:authenticated"}, LastSeen: now},
+			workspaces: buckets,
+			policy: func(p *tenancyv1alpha1.HomeWorkspacePolicy) {
+				p.Spec.WorkspaceType = "Personal"
+			},
+		},
+		"home of somebody else is not taken over": {
+			login: &Login{Groups: []string{"system:authenticated"}, LastSeen: now},
+			workspaces: append(append([]*tenancyv1alpha1.ClusterWorkspace{}, buckets...), func() *tenancyv1alpha1.ClusterWorkspace {
+				ws := readyWorkspace(leaf, "alice")
+				ws.Annotations = map[string]string{tenancyv1alpha1.HomeWorkspaceOwnerAnnotation: "mallory"}
+				return ws
+			}()),
+		},
+		"recent activity is persisted hourly": {
+			login:            &Login{Groups: []string{"system:authenticated"}, LastSeen: now},
+			workspaces:       []*tenancyv1alpha1.ClusterWorkspace{home(now.Add(-2*time.Hour), policy.Spec.Limits)},
+			wantUpdated:      home(now, policy.Spec.Limits),
+			wantRequeueAfter: 30 * 24 * time.Hour,
+		},
+		"limits follow the policy": {
+			workspaces:       []*tenancyv1alpha1.ClusterWorkspace{home(now.Add(-time.Hour), nil)},
+			wantUpdated:      home(now.Add(-time.Hour), policy.Spec.Limits),
+			wantRequeueAfter: 30*24*time.Hour - time.Hour,
+		},
+		"unused home is reaped": {
+			workspaces:  []*tenancyv1alpha1.ClusterWorkspace{home(now.Add(-31*24*time.Hour), policy.Spec.Limits)},
+			wantDeleted: []string{clusters.ToClusterAwareKey(leaf, "alice"), "ClusterRoleBinding " + clusters.ToClusterAwareKey(leaf, "home-owner-alice"), "ClusterRole " + clusters.ToClusterAwareKey(leaf, "home-owner-alice")},
+		},
+		"home used since the last persisted activity is not reaped": {
+			login:            &Login{Groups: []string{"system:authenticated"}, LastSeen: now.Add(-10 * time.Minute)},
+			workspaces:       []*tenancyv1alpha1.ClusterWorkspace{home(now.Add(-31*24*time.Hour), policy.Spec.Limits)},
+			wantUpdated:      home(now.Add(-10*time.Minute), policy.Spec.Limits),
+			wantRequeueAfter: 30*24*time.Hour - 10*time.Minute,
+		},
+		"home is kept without reapAfter": {
+			workspaces: []*tenancyv1alpha1.ClusterWorkspace{home(now.Add(-365*24*time.Hour), policy.Spec.Limits)},
+			policy: func(p *tenancyv1alpha1.HomeWorkspacePolicy) {
+				p.Spec.ReapAfter = nil
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := policy.DeepCopy()
+			if tc.policy != nil {
+				tc.policy(p)
+			}
+
+			var created, deleted []string
+			var createdType string
+			var createdRole *rbacv1.ClusterRole
+			var createdBinding *rbacv1.ClusterRoleBinding
+			var updated *tenancyv1alpha1.ClusterWorkspace
+			var requeueAfter time.Duration
+			var recorded bool
+			c := &controller{
+				enqueueAfter: func(userName string, duration time.Duration) {
+					require.Equal(t, "alice", userName)
+					requeueAfter = duration
+				},
+				now: func() time.Time { return now },
+				getLogin: func(userName string) (Login, bool) {
+					if tc.login == nil {
+						return Login{}, false
+					}
+					return *tc.login, true
+				},
+				listPolicies: func() ([]*tenancyv1alpha1.HomeWorkspacePolicy, error) {
+					return []*tenancyv1alpha1.HomeWorkspacePolicy{p}, nil
+				},
+				listHomes: func(userName string) ([]*tenancyv1alpha1.ClusterWorkspace, error) {
+					var homes []*tenancyv1alpha1.ClusterWorkspace
+					for _, ws := range tc.workspaces {
+						if ws.Labels[tenancyv1alpha1.HomeWorkspaceLabel] == "true" && ws.Annotations[tenancyv1alpha1.HomeWorkspaceOwnerAnnotation] == userName {
+							homes = append(homes, ws)
+						}
+					}
+					return homes, nil
+				},
+				getWorkspace: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error) {
+					for _, ws := range tc.workspaces {
+						if logicalcluster.From(ws) == clusterName && ws.Name == name {
+							return ws, nil
+						}
+					}
+					return nil, errors.NewNotFound(tenancyv1alpha1.Resource("clusterworkspaces"), name)
+				},
+				createWorkspace: func(ctx context.Context, clusterName logicalcluster.Name, ws *tenancyv1alpha1.ClusterWorkspace) error {
+					created = append(created, clusters.ToClusterAwareKey(clusterName, ws.Name))
+					if ws.Labels[tenancyv1alpha1.HomeWorkspaceLabel] == "true" {
+						require.Equal(t, "alice", ws.Annotations[tenancyv1alpha1.HomeWorkspaceOwnerAnnotation])
+						require.Equal(t, "users", ws.Annotations[tenancyv1alpha1.HomeWorkspacePolicyAnnotation])
+						require.Equal(t, now.Format(time.RFC3339), ws.Annotations[tenancyv1alpha1.HomeWorkspaceLastActivityAnnotation])
+						require.Equal(t, p.Spec.WorkspaceType, ws.Spec.Type)
+						require.Equal(t, p.Spec.Limits, ws.Spec.Limits)
+					}
+					return nil
+				},
+				updateWorkspace: func(ctx context.Context, ws *tenancyv1alpha1.ClusterWorkspace) error {
+					updated = ws
+					return nil
+				},
+				deleteWorkspace: func(ctx context.Context, clusterName logicalcluster.Name, name string) error {
+					deleted = append(deleted, clusters.ToClusterAwareKey(clusterName, name))
+					return nil
+				},
+				getWorkspaceType: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspaceType, error) {
+					for _, cwt := range tc.types {
+						if logicalcluster.From(cwt) == clusterName && cwt.Name == name {
+							return cwt, nil
+						}
+					}
+					return nil, errors.NewNotFound(tenancyv1alpha1.Resource("clusterworkspacetypes"), name)
+				},
+				createWorkspaceType: func(ctx context.Context, clusterName logicalcluster.Name, cwt *tenancyv1alpha1.ClusterWorkspaceType) error {
+					createdType = clusters.ToClusterAwareKey(clusterName, cwt.Name)
+					return nil
+				},
+				createClusterRole: func(ctx context.Context, clusterName logicalcluster.Name, role *rbacv1.ClusterRole) error {
+					require.Equal(t, leaf, clusterName)
+					createdRole = role
+					return nil
+				},
+				deleteClusterRole: func(ctx context.Context, clusterName logicalcluster.Name, name string) error {
+					deleted = append(deleted, "ClusterRole "+clusters.ToClusterAwareKey(clusterName, name))
+					return nil
+				},
+				createClusterRoleBinding: func(ctx context.Context, clusterName logicalcluster.Name, binding *rbacv1.ClusterRoleBinding) error {
+					require.Equal(t, leaf, clusterName)
+					createdBinding = binding
+					return nil
+				},
+				deleteClusterRoleBinding: func(ctx context.Context, clusterName logicalcluster.Name, name string) error {
+					deleted = append(deleted, "ClusterRoleBinding "+clusters.ToClusterAwareKey(clusterName, name))
+					return nil
+				},
+				recordActivity: func(ctx context.Context, userName string, login Login) error {
+					require.Equal(t, *tc.login, login)
+					recorded = true
+					return nil
+				},
+			}
+
+			err := c.reconcile(context.Background(), "alice")
+			require.NoError(t, err)
+
+			require.Equal(t, tc.wantCreated, created)
+			require.Equal(t, tc.wantCreatedType, createdType)
+			require.Equal(t, tc.wantDeleted, deleted)
+			require.Equal(t, tc.wantUpdated, updated)
+			require.Equal(t, tc.wantRequeueAfter, requeueAfter)
+			homes, err := c.listHomes("alice")
+			require.NoError(t, err)
+			require.Equal(t, tc.login != nil && len(homes) == 0, recorded, "activity must be recorded exactly for users with a request and without a home on this shard")
+			if tc.wantRBAC {
+				require.NotNil(t, createdRole)
+				require.Equal(t, "home-owner-alice", createdRole.Name)
+				require.Equal(t, []string{"alice"}, createdRole.Rules[1].ResourceNames)
+				require.Equal(t, []string{"admin", "access"}, createdRole.Rules[1].Verbs)
+				require.NotNil(t, createdBinding)
+				require.Equal(t, createdRole.Name, createdBinding.RoleRef.Name)
+				require.Equal(t, "alice", createdBinding.Subjects[0].Name)
+			} else {
+				require.Nil(t, createdRole)
+				require.Nil(t, createdBinding)
+			}
+		})
+	}
+}
+
+func TestActivityRecorder(t *testing.T) {
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	hash := hashOf("alice")
+	policies := []tenancyv1alpha1.HomeWorkspacePolicy{
+		{ObjectMeta: metav1.ObjectMeta{Name: "staff"}, Spec: tenancyv1alpha1.HomeWorkspacePolicySpec{HomeRoot: "root:staff"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "users"}, Spec: tenancyv1alpha1.HomeWorkspacePolicySpec{BucketLevels: 1, BucketSize: 2}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "guests"}, Spec: tenancyv1alpha1.HomeWorkspacePolicySpec{BucketLevels: 1, BucketSize: 2}},
+	}
+	bucket := logicalcluster.New("root:users:b" + hash[0:2])
+	home := func(owner string, lastActivity time.Time) *tenancyv1alpha1.ClusterWorkspace {
+		return &tenancyv1alpha1.ClusterWorkspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "alice",
+				ClusterName: bucket.String(),
+				Labels:      map[string]string{tenancyv1alpha1.HomeWorkspaceLabel: "true"},
+				Annotations: map[string]string{
+					tenancyv1alpha1.HomeWorkspaceOwnerAnnotation:        owner,
+					tenancyv1alpha1.HomeWorkspaceLastActivityAnnotation: lastActivity.Format(time.RFC3339),
+				},
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		home      *tenancyv1alpha1.ClusterWorkspace
+		wantPatch string
+	}{
+		"no home": {},
+		"outdated activity is patched": {
+			home:      home("alice", now.Add(-2*time.Hour)),
+			wantPatch: `{"metadata":{"annotations":{"tenancy.kcp.dev/home-last-activity":"2022-06-01T12:00:00Z"}}}`,
+		},
+		"recent activity is kept": {
+			home: home("alice", now.Add(-30*time.Minute)),
+		},
+		"home of somebody else is kept": {
+			home: home("mallory", now.Add(-2*time.Hour)),
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var gets []string
+			var patch string
+			r := &activityRecorder{
+				listPolicies: func(ctx context.Context) ([]tenancyv1alpha1.HomeWorkspacePolicy, error) {
+					return policies, nil
+				},
+				getWorkspace: func(ctx context.Context, clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error) {
+					gets = append(gets, clusters.ToClusterAwareKey(clusterName, name))
+					if tc.home != nil && logicalcluster.From(tc.home) == clusterName && tc.home.Name == name {
+						return tc.home, nil
+					}
+					return nil, errors.NewNotFound(tenancyv1alpha1.Resource("clusterworkspaces"), name)
+				},
+				patchWorkspace: func(ctx context.Context, clusterName logicalcluster.Name, name string, p []byte) error {
+					require.Equal(t, bucket, clusterName)
+					require.Equal(t, "alice", name)
+					patch = string(p)
+					return nil
+				},
+			}
+
+			err := r.record(context.Background(), "alice", Login{LastSeen: now})
+			require.NoError(t, err)
+			require.Equal(t, []string{clusters.ToClusterAwareKey(logicalcluster.New("root:staff"), "alice"), clusters.ToClusterAwareKey(bucket, "alice")}, gets, "every location must be looked up once")
+			require.Equal(t, tc.wantPatch, patch)
+		})
+	}
+}
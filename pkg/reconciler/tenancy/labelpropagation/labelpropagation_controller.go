@@ -0,0 +1,234 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package labelpropagation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clusters"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	tenancyinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/tenancy/v1alpha1"
+	tenancylisters "github.com/kcp-dev/kcp/pkg/client/listers/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/informer"
+	"github.com/kcp-dev/kcp/pkg/reconciler/latency"
+)
+
+const (
+	controllerName = "kcp-workspace-label-propagation"
+)
+
+// NewController returns a new controller that propagates the labels of ClusterWorkspaces
+// selected by the label propagation policy of their ClusterWorkspaceType onto the
+// namespaces in the workspaces. New namespaces get the labels at admission already.
+func NewController(
+	kubeClusterClient kubernetes.ClusterInterface,
+	namespaceInformer coreinformers.NamespaceInformer,
+	workspaceInformer tenancyinformers.ClusterWorkspaceInformer,
+	workspaceTypeInformer tenancyinformers.ClusterWorkspaceTypeInformer,
+) (*controller, error) {
+	queue := latency.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+
+	workspaceLister := workspaceInformer.Lister()
+	workspaceTypeLister := workspaceTypeInformer.Lister()
+	c := &controller{
+		queue:            queue,
+		namespaceLister:  namespaceInformer.Lister(),
+		namespaceIndexer: namespaceInformer.Informer().GetIndexer(),
+		workspaceLister:  workspaceLister,
+		getClusterWorkspace: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error) {
+			return workspaceLister.Get(clusters.ToClusterAwareKey(clusterName, name))
+		},
+		getClusterWorkspaceType: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspaceType, error) {
+			return workspaceTypeLister.Get(clusters.ToClusterAwareKey(clusterName, name))
+		},
+		updateNamespace: func(ctx context.Context, clusterName logicalcluster.Name, ns *corev1.Namespace) error {
+			_, err := kubeClusterClient.Cluster(clusterName).CoreV1().Namespaces().Update(ctx, ns, metav1.UpdateOptions{})
+			return err
+		},
+		syncChecks: []cache.InformerSynced{
+			namespaceInformer.Informer().HasSynced,
+			workspaceInformer.Informer().HasSynced,
+			workspaceTypeInformer.Informer().HasSynced,
+		},
+	}
+
+	if err := namespaceInformer.Informer().AddIndexers(cache.Indexers{
+		informer.ByLogicalClusterIndexName: informer.IndexByLogicalCluster,
+	}); err != nil {
+		return nil, err
+	}
+
+	namespaceInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueueNamespace(obj) },
+		UpdateFunc: func(_, obj interface{}) { c.enqueueNamespace(obj) },
+	})
+	workspaceInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueueWorkspace(obj) },
+		UpdateFunc: func(_, obj interface{}) { c.enqueueWorkspace(obj) },
+	})
+	workspaceTypeInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueueWorkspaceType(obj) },
+		UpdateFunc: func(_, obj interface{}) { c.enqueueWorkspaceType(obj) },
+		DeleteFunc: func(obj interface{}) { c.enqueueWorkspaceType(obj) },
+	})
+
+	return c, nil
+}
+
+// controller propagates labels of ClusterWorkspaces onto their namespaces.
+type controller struct {
+	queue workqueue.RateLimitingInterface
+
+	namespaceLister  corelisters.NamespaceLister
+	namespaceIndexer cache.Indexer
+	workspaceLister  tenancylisters.ClusterWorkspaceLister
+
+	getClusterWorkspace     func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error)
+	getClusterWorkspaceType func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspaceType, error)
+	updateNamespace         func(ctx context.Context, clusterName logicalcluster.Name, ns *corev1.Namespace) error
+
+	syncChecks []cache.InformerSynced
+}
+
+func (c *controller) enqueueNamespace(obj interface{}) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	klog.V(4).Infof("Queueing Namespace %q", key)
+	c.queue.Add(key)
+}
+
+// enqueueWorkspace queues the namespaces in the workspace.
+func (c *controller) enqueueWorkspace(obj interface{}) {
+	workspace, ok := obj.(*tenancyv1alpha1.ClusterWorkspace)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("unexpected object type %T", obj))
+		return
+	}
+
+	namespaces, err := c.namespaceIndexer.ByIndex(informer.ByLogicalClusterIndexName, logicalcluster.From(workspace).Join(workspace.Name).String())
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	for _, ns := range namespaces {
+		c.enqueueNamespace(ns)
+	}
+}
+
+// enqueueWorkspaceType queues the namespaces in the workspaces of the type.
+func (c *controller) enqueueWorkspaceType(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	cwt, ok := obj.(*tenancyv1alpha1.ClusterWorkspaceType)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("unexpected object type %T", obj))
+		return
+	}
+
+	workspaces, err := c.workspaceLister.List(labels.Everything())
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	clusterName := logicalcluster.From(cwt)
+	for _, workspace := range workspaces {
+		if logicalcluster.From(workspace) == clusterName && strings.ToLower(workspace.Spec.Type) == cwt.Name {
+			c.enqueueWorkspace(workspace)
+		}
+	}
+}
+
+// Start starts the controller, which stops when ctx.Done() is closed.
+func (c *controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	klog.Infof("Starting %s controller", controllerName)
+	defer klog.Infof("Shutting down %s controller", controllerName)
+
+	if !cache.WaitForNamedCacheSync(controllerName, ctx.Done(), c.syncChecks...) {
+		klog.Warning("Failed to wait for caches to sync")
+		return
+	}
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+
+	<-ctx.Done()
+}
+
+func (c *controller) startWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	if err := c.process(ctx, key); err != nil {
+		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", controllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *controller) process(ctx context.Context, key string) error {
+	ns, err := c.namespaceLister.Get(key)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil // object deleted before we handled it
+		}
+		return err
+	}
+
+	return c.reconcile(ctx, ns)
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package labelpropagation
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+func (c *controller) reconcile(ctx context.Context, ns *corev1.Namespace) error {
+	clusterName := logicalcluster.From(ns)
+	workspace, cwt, err := c.workspaceFor(clusterName)
+	if err != nil {
+		return err
+	}
+	var policy *tenancyv1alpha1.ClusterWorkspaceLabelPropagation
+	if cwt != nil {
+		policy = cwt.Spec.LabelPropagation
+	}
+
+	ns = ns.DeepCopy()
+	changed := Propagate(ns, workspace, policy)
+	defaultsChanged, err := PropagateWorkloadDefaults(ns, WorkloadDefaultsFor(workspace, cwt))
+	if err != nil {
+		return err
+	}
+	if !changed && !defaultsChanged {
+		return nil
+	}
+	klog.Infof("Updating propagated labels of namespace %q in logical cluster %s", ns.Name, clusterName)
+	return c.updateNamespace(ctx, clusterName, ns)
+}
+
+// workspaceFor returns the ClusterWorkspace of the logical cluster and its ClusterWorkspaceType,
+// both nil if there is none.
+func (c *controller) workspaceFor(clusterName logicalcluster.Name) (*tenancyv1alpha1.ClusterWorkspace, *tenancyv1alpha1.ClusterWorkspaceType, error) {
+	parent, name := clusterName.Split()
+	if parent.Empty() {
+		return nil, nil, nil // root has no ClusterWorkspace
+	}
+
+	workspace, err := c.getClusterWorkspace(parent, name)
+	if errors.IsNotFound(err) {
+		return nil, nil, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	cwt, err := c.getClusterWorkspaceType(parent, strings.ToLower(workspace.Spec.Type))
+	if errors.IsNotFound(err) {
+		return workspace, nil, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	return workspace, cwt, nil
+}
+
+// Propagate sets the labels of the workspace selected by the policy on the given namespace,
+// and removes formerly propagated labels that are not selected or set anymore. Workspace and
+// policy can be nil. It returns whether the namespace changed.
+func Propagate(ns metav1.Object, workspace *tenancyv1alpha1.ClusterWorkspace, policy *tenancyv1alpha1.ClusterWorkspaceLabelPropagation) bool {
+	desired := map[string]string{}
+	if workspace != nil && policy != nil {
+		for _, key := range policy.Labels {
+			if value, ok := workspace.Labels[key]; ok {
+				desired[key] = value
+			}
+		}
+	}
+	keys := make([]string, 0, len(desired))
+	for key := range desired {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	changed := false
+	labels := ns.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	annotations := ns.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	for _, key := range splitKeys(annotations[tenancyv1alpha1.PropagatedLabelsAnnotation]) {
+		if _, ok := desired[key]; ok {
+			continue
+		}
+		if _, ok := labels[key]; ok {
+			delete(labels, key)
+			changed = true
+		}
+	}
+	for key, value := range desired {
+		if existing, ok := labels[key]; !ok || existing != value {
+			labels[key] = value
+			changed = true
+		}
+	}
+
+	downstream := false
+	if policy != nil {
+		downstream = policy.Downstream
+	}
+	changed = setKeys(annotations, tenancyv1alpha1.PropagatedLabelsAnnotation, keys) || changed
+	if downstream {
+		changed = setKeys(annotations, tenancyv1alpha1.DownstreamLabelsAnnotation, keys) || changed
+	} else {
+		changed = setKeys(annotations, tenancyv1alpha1.DownstreamLabelsAnnotation, nil) || changed
+	}
+
+	if !changed {
+		return false
+	}
+	if len(labels) == 0 {
+		labels = nil
+	}
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	ns.SetLabels(labels)
+	ns.SetAnnotations(annotations)
+	return true
+}
+
+// setKeys sets the annotation to the comma separated keys, or removes it if there are none.
+// It returns whether the annotation changed.
+func setKeys(annotations map[string]string, annotation string, keys []string) bool {
+	existing, ok := annotations[annotation]
+	if len(keys) == 0 {
+		delete(annotations, annotation)
+		return ok
+	}
+	value := strings.Join(keys, ",")
+	annotations[annotation] = value
+	return !ok || existing != value
+}
+
+func splitKeys(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// WorkloadDefaultsFor returns the workload defaults of the workspace, falling back to those of
+// its type. Workspace and type can be nil.
+func WorkloadDefaultsFor(workspace *tenancyv1alpha1.ClusterWorkspace, cwt *tenancyv1alpha1.ClusterWorkspaceType) *tenancyv1alpha1.WorkloadDefaults {
+	if workspace == nil {
+		return nil
+	}
+	if workspace.Spec.WorkloadDefaults != nil {
+		return workspace.Spec.WorkloadDefaults
+	}
+	if cwt != nil {
+		return cwt.Spec.WorkloadDefaults
+	}
+	return nil
+}
+
+// PropagateWorkloadDefaults sets the JSON encoded workload defaults as annotation on the given
+// namespace, or removes the annotation if defaults is nil. It returns whether the namespace changed.
+func PropagateWorkloadDefaults(ns metav1.Object, defaults *tenancyv1alpha1.WorkloadDefaults) (bool, error) {
+	annotations := ns.GetAnnotations()
+	existing, ok := annotations[tenancyv1alpha1.WorkloadDefaultsAnnotation]
+
+	if defaults == nil {
+		if !ok {
+			return false, nil
+		}
+		delete(annotations, tenancyv1alpha1.WorkloadDefaultsAnnotation)
+		if len(annotations) == 0 {
+			annotations = nil
+		}
+		ns.SetAnnotations(annotations)
+		return true, nil
+	}
+
+	bs, err := json.Marshal(defaults)
+	if err != nil {
+		return false, err
+	}
+	if ok && existing == string(bs) {
+		return false, nil
+	}
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[tenancyv1alpha1.WorkloadDefaultsAnnotation] = string(bs)
+	ns.SetAnnotations(annotations)
+	return true, nil
+}
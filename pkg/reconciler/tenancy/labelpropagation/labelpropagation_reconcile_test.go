@@ -0,0 +1,191 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package labelpropagation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+func TestPropagate(t *testing.T) {
+	workspace := &tenancyv1alpha1.ClusterWorkspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws", Labels: map[string]string{"team": "a", "env": "prod", "other": "x"}},
+	}
+
+	tests := []struct {
+		name            string
+		workspace       *tenancyv1alpha1.ClusterWorkspace
+		policy          *tenancyv1alpha1.ClusterWorkspaceLabelPropagation
+		labels          map[string]string
+		annotations     map[string]string
+		wantChanged     bool
+		wantLabels      map[string]string
+		wantAnnotations map[string]string
+	}{
+		{
+			name:      "no policy, nothing to do",
+			workspace: workspace,
+			labels:    map[string]string{"a": "b"},
+			wantLabels: map[string]string{
+				"a": "b",
+			},
+		},
+		{
+			name:        "selected labels are added",
+			workspace:   workspace,
+			policy:      &tenancyv1alpha1.ClusterWorkspaceLabelPropagation{Labels: []string{"team", "env"}},
+			labels:      map[string]string{"a": "b"},
+			wantChanged: true,
+			wantLabels: map[string]string{
+				"a":    "b",
+				"team": "a",
+				"env":  "prod",
+			},
+			wantAnnotations: map[string]string{
+				tenancyv1alpha1.PropagatedLabelsAnnotation: "env,team",
+			},
+		},
+		{
+			name:      "up to date namespace is unchanged",
+			workspace: workspace,
+			policy:    &tenancyv1alpha1.ClusterWorkspaceLabelPropagation{Labels: []string{"team"}, Downstream: true},
+			labels:    map[string]string{"team": "a"},
+			annotations: map[string]string{
+				tenancyv1alpha1.PropagatedLabelsAnnotation: "team",
+				tenancyv1alpha1.DownstreamLabelsAnnotation: "team",
+			},
+			wantLabels: map[string]string{
+				"team": "a",
+			},
+			wantAnnotations: map[string]string{
+				tenancyv1alpha1.PropagatedLabelsAnnotation: "team",
+				tenancyv1alpha1.DownstreamLabelsAnnotation: "team",
+			},
+		},
+		{
+			name:      "labels not selected anymore are removed, others are kept",
+			workspace: workspace,
+			policy:    &tenancyv1alpha1.ClusterWorkspaceLabelPropagation{Labels: []string{"team"}},
+			labels:    map[string]string{"team": "a", "env": "prod", "a": "b"},
+			annotations: map[string]string{
+				tenancyv1alpha1.PropagatedLabelsAnnotation: "env,team",
+				tenancyv1alpha1.DownstreamLabelsAnnotation: "env,team",
+			},
+			wantChanged: true,
+			wantLabels: map[string]string{
+				"team": "a",
+				"a":    "b",
+			},
+			wantAnnotations: map[string]string{
+				tenancyv1alpha1.PropagatedLabelsAnnotation: "team",
+			},
+		},
+		{
+			name:   "labels are removed without workspace",
+			labels: map[string]string{"team": "a"},
+			annotations: map[string]string{
+				tenancyv1alpha1.PropagatedLabelsAnnotation: "team",
+			},
+			wantChanged: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default", Labels: tc.labels, Annotations: tc.annotations}}
+			changed := Propagate(ns, tc.workspace, tc.policy)
+			require.Equal(t, tc.wantChanged, changed)
+			require.Equal(t, tc.wantLabels, ns.Labels)
+			require.Equal(t, tc.wantAnnotations, ns.Annotations)
+		})
+	}
+}
+
+func TestPropagateWorkloadDefaults(t *testing.T) {
+	workspaceDefaults := &tenancyv1alpha1.WorkloadDefaults{RuntimeClassName: "gvisor"}
+	typeDefaults := &tenancyv1alpha1.WorkloadDefaults{
+		NodeSelector: map[string]string{"pool": "tenants"},
+		Tolerations:  []corev1.Toleration{{Key: "tenants", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}},
+	}
+
+	tests := []struct {
+		name            string
+		workspace       *tenancyv1alpha1.ClusterWorkspace
+		cwt             *tenancyv1alpha1.ClusterWorkspaceType
+		annotations     map[string]string
+		wantChanged     bool
+		wantAnnotations map[string]string
+	}{
+		{
+			name:      "no defaults, nothing to do",
+			workspace: &tenancyv1alpha1.ClusterWorkspace{},
+			cwt:       &tenancyv1alpha1.ClusterWorkspaceType{},
+		},
+		{
+			name:        "defaults of the type are set",
+			workspace:   &tenancyv1alpha1.ClusterWorkspace{},
+			cwt:         &tenancyv1alpha1.ClusterWorkspaceType{Spec: tenancyv1alpha1.ClusterWorkspaceTypeSpec{WorkloadDefaults: typeDefaults}},
+			annotations: map[string]string{"a": "b"},
+			wantChanged: true,
+			wantAnnotations: map[string]string{
+				"a": "b",
+				tenancyv1alpha1.WorkloadDefaultsAnnotation: `{"nodeSelector":{"pool":"tenants"},"tolerations":[{"key":"tenants","operator":"Exists","effect":"NoSchedule"}]}`,
+			},
+		},
+		{
+			name:        "defaults of the workspace replace those of the type",
+			workspace:   &tenancyv1alpha1.ClusterWorkspace{Spec: tenancyv1alpha1.ClusterWorkspaceSpec{WorkloadDefaults: workspaceDefaults}},
+			cwt:         &tenancyv1alpha1.ClusterWorkspaceType{Spec: tenancyv1alpha1.ClusterWorkspaceTypeSpec{WorkloadDefaults: typeDefaults}},
+			wantChanged: true,
+			wantAnnotations: map[string]string{
+				tenancyv1alpha1.WorkloadDefaultsAnnotation: `{"runtimeClassName":"gvisor"}`,
+			},
+		},
+		{
+			name:      "up to date namespace is unchanged",
+			workspace: &tenancyv1alpha1.ClusterWorkspace{Spec: tenancyv1alpha1.ClusterWorkspaceSpec{WorkloadDefaults: workspaceDefaults}},
+			annotations: map[string]string{
+				tenancyv1alpha1.WorkloadDefaultsAnnotation: `{"runtimeClassName":"gvisor"}`,
+			},
+			wantAnnotations: map[string]string{
+				tenancyv1alpha1.WorkloadDefaultsAnnotation: `{"runtimeClassName":"gvisor"}`,
+			},
+		},
+		{
+			name: "defaults are removed without workspace",
+			cwt:  &tenancyv1alpha1.ClusterWorkspaceType{Spec: tenancyv1alpha1.ClusterWorkspaceTypeSpec{WorkloadDefaults: typeDefaults}},
+			annotations: map[string]string{
+				tenancyv1alpha1.WorkloadDefaultsAnnotation: `{"runtimeClassName":"gvisor"}`,
+			},
+			wantChanged: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default", Annotations: tc.annotations}}
+			changed, err := PropagateWorkloadDefaults(ns, WorkloadDefaultsFor(tc.workspace, tc.cwt))
+			require.NoError(t, err)
+			require.Equal(t, tc.wantChanged, changed)
+			require.Equal(t, tc.wantAnnotations, ns.Annotations)
+		})
+	}
+}
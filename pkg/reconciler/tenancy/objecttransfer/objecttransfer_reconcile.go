@@ -0,0 +1,377 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package objecttransfer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/third_party/conditions/util/conditions"
+)
+
+// maxRecordedObjects is the maximum number of transferred objects recorded in the status.
+const maxRecordedObjects = 1000
+
+// terminalReasons are the reasons of a false Transferred condition after which the transfer
+// is not retried.
+var terminalReasons = sets.NewString(
+	tenancyv1alpha1.ObjectTransferSourceNotFoundReason,
+	tenancyv1alpha1.ObjectTransferConflictReason,
+	tenancyv1alpha1.ObjectTransferRejectedReason,
+)
+
+// object is an object of a transfer with its resource.
+type object struct {
+	gvr schema.GroupVersionResource
+	*unstructured.Unstructured
+}
+
+func (c *controller) reconcile(ctx context.Context, transfer *tenancyv1alpha1.ObjectTransfer) error {
+	if conditions.IsTrue(transfer, tenancyv1alpha1.ObjectTransferTransferred) ||
+		(conditions.IsFalse(transfer, tenancyv1alpha1.ObjectTransferTransferred) && terminalReasons.Has(conditions.GetReason(transfer, tenancyv1alpha1.ObjectTransferTransferred))) {
+		return nil
+	}
+
+	source := logicalcluster.From(transfer)
+	destination := logicalcluster.New(transfer.Spec.Destination)
+	gvr := schema.GroupVersionResource{
+		Group:    transfer.Spec.Resource.Group,
+		Version:  transfer.Spec.Resource.Version,
+		Resource: transfer.Spec.Resource.Resource,
+	}
+
+	root, err := c.getObject(ctx, source, gvr, transfer.Spec.Namespace, transfer.Spec.Name)
+	if errors.IsNotFound(err) {
+		return c.reconcileMissingSource(ctx, transfer, gvr, destination)
+	} else if err != nil {
+		return err
+	}
+	if !root.GetDeletionTimestamp().IsZero() {
+		conditions.MarkFalse(transfer, tenancyv1alpha1.ObjectTransferTransferred, tenancyv1alpha1.ObjectTransferSourceNotFoundReason, conditionsv1alpha1.ConditionSeverityError, "%s is being deleted in workspace %s", objectKey(gvr, root), source)
+		return nil
+	}
+
+	objects := []object{{gvr: gvr, Unstructured: root}}
+	if isNamespace(gvr) {
+		contents, err := c.namespaceContents(ctx, source, root.GetName())
+		if err != nil {
+			return err
+		}
+		objects = append(objects, contents...)
+	}
+
+	// create the objects in the destination, owners first such that owner references can
+	// be updated to the new UIDs.
+	uids := map[types.UID]types.UID{}
+	var created []object
+	var transferred []tenancyv1alpha1.TransferredObject
+	for _, obj := range orderByOwners(objects) {
+		result, err := c.createObject(ctx, destination, obj.gvr, destinationObjectOf(source, obj.Unstructured, uids))
+		switch {
+		case errors.IsAlreadyExists(err):
+			existing, err := c.getObject(ctx, destination, obj.gvr, obj.GetNamespace(), obj.GetName())
+			if err != nil {
+				return err
+			}
+			if lastLineageEntry(existing) != lineageEntry(source, obj.GetUID()) {
+				if err := c.rollback(ctx, destination, created); err != nil {
+					return err
+				}
+				conditions.MarkFalse(transfer, tenancyv1alpha1.ObjectTransferTransferred, tenancyv1alpha1.ObjectTransferConflictReason, conditionsv1alpha1.ConditionSeverityError, "%s already exists in workspace %s", objectKey(obj.gvr, obj.Unstructured), destination)
+				return nil
+			}
+			result = existing // created by an earlier attempt
+		case isRejection(err):
+			if err := c.rollback(ctx, destination, created); err != nil {
+				return err
+			}
+			conditions.MarkFalse(transfer, tenancyv1alpha1.ObjectTransferTransferred, tenancyv1alpha1.ObjectTransferRejectedReason, conditionsv1alpha1.ConditionSeverityError, "Workspace %s rejected %s: %v", destination, objectKey(obj.gvr, obj.Unstructured), err)
+			return nil
+		case err != nil:
+			return err
+		}
+
+		uids[obj.GetUID()] = result.GetUID()
+		created = append(created, object{gvr: obj.gvr, Unstructured: result})
+		transferred = append(transferred, tenancyv1alpha1.TransferredObject{
+			Group:          obj.gvr.Group,
+			Resource:       obj.gvr.Resource,
+			Namespace:      obj.GetNamespace(),
+			Name:           obj.GetName(),
+			SourceUID:      string(obj.GetUID()),
+			DestinationUID: string(result.GetUID()),
+		})
+	}
+
+	// the contents of a namespace go with it. Dependents of a single object which were not
+	// transferred are kept.
+	propagation := metav1.DeletePropagationOrphan
+	if isNamespace(gvr) {
+		propagation = metav1.DeletePropagationBackground
+	}
+	if err := c.deleteObject(ctx, source, gvr, root.GetNamespace(), root.GetName(), root.GetUID(), propagation); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	klog.Infof("Transferred %d objects of ObjectTransfer %s|%s to workspace %s", len(transferred), source, transfer.Name, destination)
+
+	c.markTransferred(transfer, transferred)
+	return nil
+}
+
+// reconcileMissingSource completes a transfer whose source was deleted after the objects were
+// created in the destination, and fails it otherwise.
+func (c *controller) reconcileMissingSource(ctx context.Context, transfer *tenancyv1alpha1.ObjectTransfer, gvr schema.GroupVersionResource, destination logicalcluster.Name) error {
+	source := logicalcluster.From(transfer)
+
+	existing, err := c.getObject(ctx, destination, gvr, transfer.Spec.Namespace, transfer.Spec.Name)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if err == nil {
+		if parts := strings.SplitN(lastLineageEntry(existing), "|", 2); len(parts) == 2 && parts[0] == source.String() {
+			c.markTransferred(transfer, []tenancyv1alpha1.TransferredObject{{
+				Group:          gvr.Group,
+				Resource:       gvr.Resource,
+				Namespace:      existing.GetNamespace(),
+				Name:           existing.GetName(),
+				SourceUID:      parts[1],
+				DestinationUID: string(existing.GetUID()),
+			}})
+			return nil
+		}
+	}
+
+	conditions.MarkFalse(transfer, tenancyv1alpha1.ObjectTransferTransferred, tenancyv1alpha1.ObjectTransferSourceNotFoundReason, conditionsv1alpha1.ConditionSeverityError, "%s not found in workspace %s", objectDescription(gvr, transfer.Spec.Namespace, transfer.Spec.Name), source)
+	return nil
+}
+
+func (c *controller) markTransferred(transfer *tenancyv1alpha1.ObjectTransfer, transferred []tenancyv1alpha1.TransferredObject) {
+	transfer.Status.TransferredObjects = int32(len(transferred))
+	if len(transferred) > maxRecordedObjects {
+		transferred = transferred[:maxRecordedObjects]
+	}
+	transfer.Status.Objects = transferred
+	now := metav1.NewTime(c.now())
+	transfer.Status.CompletionTime = &now
+	conditions.MarkTrue(transfer, tenancyv1alpha1.ObjectTransferTransferred)
+}
+
+// NamespacedResources returns the namespaced resources whose objects are transferred with a
+// namespace: those which can be listed, created and deleted, except events.
+func NamespacedResources(resourceLists []*metav1.APIResourceList) ([]schema.GroupVersionResource, error) {
+	var gvrs []schema.GroupVersionResource
+	for _, resourceList := range resourceLists {
+		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil {
+			return nil, err
+		}
+		for _, resource := range resourceList.APIResources {
+			if !resource.Namespaced || strings.Contains(resource.Name, "/") || !sets.NewString(resource.Verbs...).HasAll("list", "create", "delete") {
+				continue
+			}
+			gvr := gv.WithResource(resource.Name)
+			if gvr.Resource == "events" {
+				continue
+			}
+			gvrs = append(gvrs, gvr)
+		}
+	}
+	return gvrs, nil
+}
+
+// namespaceContents returns the objects in the namespace of all resources which can be listed,
+// created and deleted, except those which are created by controllers for every namespace.
+func (c *controller) namespaceContents(ctx context.Context, clusterName logicalcluster.Name, namespace string) ([]object, error) {
+	resourceLists, err := c.discoverResources(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	gvrs, err := NamespacedResources(resourceLists)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []object
+	for _, gvr := range gvrs {
+		items, err := c.listObjects(ctx, clusterName, gvr, namespace)
+		if err != nil {
+			return nil, err
+		}
+		for i := range items {
+			if !items[i].GetDeletionTimestamp().IsZero() || isCreatedPerNamespace(gvr, &items[i]) {
+				continue
+			}
+			objects = append(objects, object{gvr: gvr, Unstructured: &items[i]})
+		}
+	}
+
+	return objects, nil
+}
+
+// isCreatedPerNamespace returns true for objects which controllers create in every namespace,
+// and which are created again in the destination.
+func isCreatedPerNamespace(gvr schema.GroupVersionResource, obj *unstructured.Unstructured) bool {
+	if gvr.Group != "" {
+		return false
+	}
+	switch gvr.Resource {
+	case "serviceaccounts":
+		return obj.GetName() == "default"
+	case "configmaps":
+		return obj.GetName() == "kube-root-ca.crt"
+	case "secrets":
+		secretType, _, _ := unstructured.NestedString(obj.Object, "type")
+		return secretType == "kubernetes.io/service-account-token"
+	}
+	return false
+}
+
+// rollback deletes the objects created in the destination, in reverse order.
+func (c *controller) rollback(ctx context.Context, destination logicalcluster.Name, created []object) error {
+	for i := len(created) - 1; i >= 0; i-- {
+		obj := created[i]
+		if err := c.deleteObject(ctx, destination, obj.gvr, obj.GetNamespace(), obj.GetName(), obj.GetUID(), metav1.DeletePropagationBackground); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// orderByOwners returns the objects such that owners come before their dependents,
+// keeping the order otherwise. Cyclic owner references are kept in their order.
+func orderByOwners(objects []object) []object {
+	pending := sets.NewString()
+	for _, obj := range objects {
+		pending.Insert(string(obj.GetUID()))
+	}
+
+	ordered := make([]object, 0, len(objects))
+	remaining := objects
+	for len(remaining) > 0 {
+		var next []object
+		for _, obj := range remaining {
+			ready := true
+			for _, ref := range obj.GetOwnerReferences() {
+				if ref.UID != obj.GetUID() && pending.Has(string(ref.UID)) {
+					ready = false
+					break
+				}
+			}
+			if !ready {
+				next = append(next, obj)
+				continue
+			}
+			ordered = append(ordered, obj)
+			pending.Delete(string(obj.GetUID()))
+		}
+		if len(next) == len(remaining) {
+			return append(ordered, next...)
+		}
+		remaining = next
+	}
+	return ordered
+}
+
+// destinationObjectOf returns the object to create in the destination workspace, without the
+// metadata maintained by the server and without status. The source is appended to the UID
+// lineage, and owner references are updated to the UIDs of the transferred owners or removed.
+func destinationObjectOf(source logicalcluster.Name, obj *unstructured.Unstructured, uids map[types.UID]types.UID) *unstructured.Unstructured {
+	ret := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	for k, v := range obj.Object {
+		if k == "metadata" || k == "status" {
+			continue
+		}
+		ret.Object[k] = runtime.DeepCopyJSONValue(v)
+	}
+	ret.SetName(obj.GetName())
+	if obj.GetNamespace() != "" {
+		ret.SetNamespace(obj.GetNamespace())
+	}
+	if len(obj.GetLabels()) > 0 {
+		ret.SetLabels(obj.GetLabels())
+	}
+
+	annotations := map[string]string{}
+	for k, v := range obj.GetAnnotations() {
+		annotations[k] = v
+	}
+	entry := lineageEntry(source, obj.GetUID())
+	if lineage := annotations[tenancyv1alpha1.ObjectTransferUIDLineageAnnotation]; lineage != "" {
+		entry = lineage + "," + entry
+	}
+	annotations[tenancyv1alpha1.ObjectTransferUIDLineageAnnotation] = entry
+	ret.SetAnnotations(annotations)
+
+	var refs []metav1.OwnerReference
+	for _, ref := range obj.GetOwnerReferences() {
+		if uid, found := uids[ref.UID]; found {
+			ref.UID = uid
+			refs = append(refs, ref)
+		}
+	}
+	if len(refs) > 0 {
+		ret.SetOwnerReferences(refs)
+	}
+
+	return ret
+}
+
+// isRejection returns true for errors after which creating the object will not succeed on retry.
+func isRejection(err error) bool {
+	return errors.IsInvalid(err) || errors.IsForbidden(err) || errors.IsBadRequest(err) ||
+		errors.IsNotFound(err) || errors.IsMethodNotSupported(err) || errors.IsRequestEntityTooLargeError(err)
+}
+
+func lineageEntry(clusterName logicalcluster.Name, uid types.UID) string {
+	return clusterName.String() + "|" + string(uid)
+}
+
+func lastLineageEntry(obj *unstructured.Unstructured) string {
+	lineage := obj.GetAnnotations()[tenancyv1alpha1.ObjectTransferUIDLineageAnnotation]
+	return lineage[strings.LastIndex(lineage, ",")+1:]
+}
+
+func isNamespace(gvr schema.GroupVersionResource) bool {
+	return gvr.Group == "" && gvr.Resource == "namespaces"
+}
+
+func objectKey(gvr schema.GroupVersionResource, obj *unstructured.Unstructured) string {
+	return objectDescription(gvr, obj.GetNamespace(), obj.GetName())
+}
+
+// objectDescription returns the object as <resource>[.<group>] [<namespace>/]<name>.
+func objectDescription(gvr schema.GroupVersionResource, namespace, name string) string {
+	resource := gvr.GroupResource().String()
+	if namespace == "" {
+		return fmt.Sprintf("%s %s", resource, name)
+	}
+	return fmt.Sprintf("%s %s/%s", resource, namespace, name)
+}
@@ -0,0 +1,373 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package objecttransfer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/third_party/conditions/util/conditions"
+)
+
+var (
+	namespacesGVR  = schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+	configMapsGVR  = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	podsGVR        = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	replicaSetsGVR = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "replicasets"}
+
+	source      = logicalcluster.New("root:org")
+	destination = logicalcluster.New("root:org:team")
+)
+
+type storedObject struct {
+	gvr schema.GroupVersionResource
+	*unstructured.Unstructured
+}
+
+func newObject(gvr schema.GroupVersionResource, kind, namespace, name string, annotations map[string]string, owners ...*unstructured.Unstructured) storedObject {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": schema.GroupVersion{Group: gvr.Group, Version: gvr.Version}.String(),
+		"kind":       kind,
+	}}
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	obj.SetUID(types.UID("uid-" + name))
+	obj.SetResourceVersion("1")
+	obj.SetAnnotations(annotations)
+	var refs []metav1.OwnerReference
+	for _, owner := range owners {
+		refs = append(refs, metav1.OwnerReference{APIVersion: owner.GetAPIVersion(), Kind: owner.GetKind(), Name: owner.GetName(), UID: owner.GetUID()})
+	}
+	obj.SetOwnerReferences(refs)
+	return storedObject{gvr: gvr, Unstructured: obj}
+}
+
+func lineage(entries ...string) map[string]string {
+	value := ""
+	for i, entry := range entries {
+		if i > 0 {
+			value += ","
+		}
+		value += entry
+	}
+	return map[string]string{tenancyv1alpha1.ObjectTransferUIDLineageAnnotation: value}
+}
+
+func storeKey(gvr schema.GroupVersionResource, namespace, name string) string {
+	return gvr.Resource + " " + namespace + "/" + name
+}
+
+func TestReconcile(t *testing.T) {
+	ns := newObject(namespacesGVR, "Namespace", "", "web", nil)
+	settings := newObject(configMapsGVR, "ConfigMap", "web", "settings", nil)
+	rootCA := newObject(configMapsGVR, "ConfigMap", "web", "kube-root-ca.crt", nil)
+	rs := newObject(replicaSetsGVR, "ReplicaSet", "web", "frontend", nil)
+	pod := newObject(podsGVR, "Pod", "web", "frontend-abc", nil, rs.Unstructured)
+
+	transfer := func(mutate func(*tenancyv1alpha1.ObjectTransfer)) *tenancyv1alpha1.ObjectTransfer {
+		t := &tenancyv1alpha1.ObjectTransfer{
+			ObjectMeta: metav1.ObjectMeta{Name: "move", ClusterName: source.String()},
+			Spec: tenancyv1alpha1.ObjectTransferSpec{
+				Resource:    tenancyv1alpha1.ReplicationResource{Version: "v1", Resource: "configmaps"},
+				Namespace:   "web",
+				Name:        "settings",
+				Destination: destination.String(),
+			},
+		}
+		if mutate != nil {
+			mutate(t)
+		}
+		return t
+	}
+	namespaceTransfer := func(t *tenancyv1alpha1.ObjectTransfer) {
+		t.Spec.Resource.Resource = "namespaces"
+		t.Spec.Namespace = ""
+		t.Spec.Name = "web"
+	}
+
+	tests := map[string]struct {
+		transfer  *tenancyv1alpha1.ObjectTransfer
+		objects   map[logicalcluster.Name][]storedObject
+		rejectKey string
+
+		wantObjects     map[logicalcluster.Name][]string
+		wantLineage     map[string]string
+		wantOwners      map[string][]types.UID
+		wantCreateOrder []string
+		wantCondition   bool
+		wantReason      string
+		wantTransferred int32
+	}{
+		"moves a single object": {
+			transfer: transfer(nil),
+			objects: map[logicalcluster.Name][]storedObject{
+				source:      {ns, settings},
+				destination: {ns},
+			},
+			wantObjects: map[logicalcluster.Name][]string{
+				source:      {"namespaces /web"},
+				destination: {"configmaps web/settings", "namespaces /web"},
+			},
+			wantLineage:     map[string]string{"configmaps web/settings": "root:org|uid-settings"},
+			wantCreateOrder: []string{"configmaps web/settings"},
+			wantCondition:   true,
+			wantTransferred: 1,
+		},
+		"extends the lineage of objects transferred before": {
+			transfer: transfer(nil),
+			objects: map[logicalcluster.Name][]storedObject{
+				source:      {newObject(configMapsGVR, "ConfigMap", "web", "settings", lineage("root:other|uid-0"))},
+				destination: {ns},
+			},
+			wantObjects: map[logicalcluster.Name][]string{
+				destination: {"configmaps web/settings", "namespaces /web"},
+			},
+			wantLineage:     map[string]string{"configmaps web/settings": "root:other|uid-0,root:org|uid-settings"},
+			wantCreateOrder: []string{"configmaps web/settings"},
+			wantCondition:   true,
+			wantTransferred: 1,
+		},
+		"moves a namespace with its contents, owners first": {
+			transfer: transfer(namespaceTransfer),
+			objects: map[logicalcluster.Name][]storedObject{
+				source: {ns, settings, rootCA, pod, rs},
+			},
+			wantObjects: map[logicalcluster.Name][]string{
+				destination: {"configmaps web/settings", "namespaces /web", "pods web/frontend-abc", "replicasets web/frontend"},
+			},
+			wantLineage: map[string]string{
+				"namespaces /web":          "root:org|uid-web",
+				"pods web/frontend-abc":    "root:org|uid-frontend-abc",
+				"replicasets web/frontend": "root:org|uid-frontend",
+			},
+			wantOwners:      map[string][]types.UID{"pods web/frontend-abc": {"new-frontend"}},
+			wantCreateOrder: []string{"namespaces /web", "configmaps web/settings", "replicasets web/frontend", "pods web/frontend-abc"},
+			wantCondition:   true,
+			wantTransferred: 4,
+		},
+		"adopts objects created by an earlier attempt": {
+			transfer: transfer(nil),
+			objects: map[logicalcluster.Name][]storedObject{
+				source:      {settings},
+				destination: {ns, newObject(configMapsGVR, "ConfigMap", "web", "settings", lineage("root:org|uid-settings"))},
+			},
+			wantObjects: map[logicalcluster.Name][]string{
+				destination: {"configmaps web/settings", "namespaces /web"},
+			},
+			wantCreateOrder: []string{"configmaps web/settings"},
+			wantCondition:   true,
+			wantTransferred: 1,
+		},
+		"conflicts with existing objects": {
+			transfer: transfer(namespaceTransfer),
+			objects: map[logicalcluster.Name][]storedObject{
+				source:      {ns, settings},
+				destination: {ns},
+			},
+			wantObjects: map[logicalcluster.Name][]string{
+				source:      {"configmaps web/settings", "namespaces /web"},
+				destination: {"namespaces /web"},
+			},
+			wantCreateOrder: []string{"namespaces /web"},
+			wantReason:      tenancyv1alpha1.ObjectTransferConflictReason,
+		},
+		"rejected objects roll back": {
+			transfer: transfer(namespaceTransfer),
+			objects: map[logicalcluster.Name][]storedObject{
+				source: {ns, settings, pod},
+			},
+			rejectKey: "pods web/frontend-abc",
+			wantObjects: map[logicalcluster.Name][]string{
+				source: {"configmaps web/settings", "namespaces /web", "pods web/frontend-abc"},
+			},
+			wantCreateOrder: []string{"namespaces /web", "configmaps web/settings", "pods web/frontend-abc"},
+			wantReason:      tenancyv1alpha1.ObjectTransferRejectedReason,
+		},
+		"completes when the source was deleted after the transfer": {
+			transfer: transfer(nil),
+			objects: map[logicalcluster.Name][]storedObject{
+				destination: {newObject(configMapsGVR, "ConfigMap", "web", "settings", lineage("root:org|uid-settings"))},
+			},
+			wantObjects: map[logicalcluster.Name][]string{
+				destination: {"configmaps web/settings"},
+			},
+			wantCondition:   true,
+			wantTransferred: 1,
+		},
+		"fails without source": {
+			transfer: transfer(nil),
+			objects: map[logicalcluster.Name][]storedObject{
+				destination: {newObject(configMapsGVR, "ConfigMap", "web", "settings", lineage("root:other|uid-settings"))},
+			},
+			wantObjects: map[logicalcluster.Name][]string{
+				destination: {"configmaps web/settings"},
+			},
+			wantReason: tenancyv1alpha1.ObjectTransferSourceNotFoundReason,
+		},
+		"finished transfers are not retried": {
+			transfer: transfer(func(t *tenancyv1alpha1.ObjectTransfer) {
+				conditions.MarkFalse(t, tenancyv1alpha1.ObjectTransferTransferred, tenancyv1alpha1.ObjectTransferConflictReason, conditionsv1alpha1.ConditionSeverityError, "")
+			}),
+			objects: map[logicalcluster.Name][]storedObject{
+				source: {settings},
+			},
+			wantObjects: map[logicalcluster.Name][]string{
+				source: {"configmaps web/settings"},
+			},
+			wantReason: tenancyv1alpha1.ObjectTransferConflictReason,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			objects := map[logicalcluster.Name]map[string]storedObject{}
+			for clusterName, objs := range tc.objects {
+				objects[clusterName] = map[string]storedObject{}
+				for _, obj := range objs {
+					objects[clusterName][storeKey(obj.gvr, obj.GetNamespace(), obj.GetName())] = storedObject{gvr: obj.gvr, Unstructured: obj.DeepCopy()}
+				}
+			}
+
+			now := time.Now()
+			var createOrder []string
+			c := &controller{
+				now: func() time.Time { return now },
+				discoverResources: func(clusterName logicalcluster.Name) ([]*metav1.APIResourceList, error) {
+					verbs := metav1.Verbs{"create", "delete", "get", "list"}
+					return []*metav1.APIResourceList{
+						{GroupVersion: "v1", APIResources: []metav1.APIResource{
+							{Name: "configmaps", Namespaced: true, Verbs: verbs},
+							{Name: "events", Namespaced: true, Verbs: verbs},
+							{Name: "pods", Namespaced: true, Verbs: verbs},
+							{Name: "pods/log", Namespaced: true, Verbs: metav1.Verbs{"get"}},
+						}},
+						{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{
+							{Name: "replicasets", Namespaced: true, Verbs: verbs},
+						}},
+					}, nil
+				},
+				getObject: func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, error) {
+					obj, found := objects[clusterName][storeKey(gvr, namespace, name)]
+					if !found {
+						return nil, errors.NewNotFound(gvr.GroupResource(), name)
+					}
+					return obj.DeepCopy(), nil
+				},
+				listObjects: func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, namespace string) ([]unstructured.Unstructured, error) {
+					var ret []unstructured.Unstructured
+					for _, key := range sortedKeys(objects[clusterName]) {
+						obj := objects[clusterName][key]
+						if obj.gvr == gvr && obj.GetNamespace() == namespace {
+							ret = append(ret, *obj.DeepCopy())
+						}
+					}
+					return ret, nil
+				},
+				createObject: func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+					require.Equal(t, destination, clusterName)
+					key := storeKey(gvr, obj.GetNamespace(), obj.GetName())
+					createOrder = append(createOrder, key)
+					if key == tc.rejectKey {
+						return nil, errors.NewForbidden(gvr.GroupResource(), obj.GetName(), fmt.Errorf("denied by admission"))
+					}
+					if _, found := objects[clusterName][key]; found {
+						return nil, errors.NewAlreadyExists(gvr.GroupResource(), obj.GetName())
+					}
+					if objects[clusterName] == nil {
+						objects[clusterName] = map[string]storedObject{}
+					}
+					obj = obj.DeepCopy()
+					obj.SetUID(types.UID("new-" + obj.GetName()))
+					objects[clusterName][key] = storedObject{gvr: gvr, Unstructured: obj}
+					return obj.DeepCopy(), nil
+				},
+				deleteObject: func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, namespace, name string, uid types.UID, propagation metav1.DeletionPropagation) error {
+					key := storeKey(gvr, namespace, name)
+					obj, found := objects[clusterName][key]
+					if !found {
+						return errors.NewNotFound(gvr.GroupResource(), name)
+					}
+					require.Equal(t, obj.GetUID(), uid, "delete precondition of %s", key)
+					delete(objects[clusterName], key)
+					if isNamespace(gvr) {
+						for k, obj := range objects[clusterName] {
+							if obj.GetNamespace() == name {
+								delete(objects[clusterName], k)
+							}
+						}
+					}
+					return nil
+				},
+			}
+
+			transfer := tc.transfer.DeepCopy()
+			err := c.reconcile(context.Background(), transfer)
+			require.NoError(t, err)
+
+			for _, clusterName := range []logicalcluster.Name{source, destination} {
+				require.Equal(t, tc.wantObjects[clusterName], sortedKeys(objects[clusterName]), "objects in %s", clusterName)
+			}
+			for key, want := range tc.wantLineage {
+				require.Equal(t, want, objects[destination][key].GetAnnotations()[tenancyv1alpha1.ObjectTransferUIDLineageAnnotation], "lineage of %s", key)
+			}
+			for key, want := range tc.wantOwners {
+				var got []types.UID
+				for _, ref := range objects[destination][key].GetOwnerReferences() {
+					got = append(got, ref.UID)
+				}
+				require.Equal(t, want, got, "owners of %s", key)
+			}
+			require.Equal(t, tc.wantCreateOrder, createOrder)
+
+			require.Equal(t, tc.wantCondition, conditions.IsTrue(transfer, tenancyv1alpha1.ObjectTransferTransferred))
+			if tc.wantReason != "" {
+				require.Equal(t, tc.wantReason, conditions.GetReason(transfer, tenancyv1alpha1.ObjectTransferTransferred))
+			}
+			require.Equal(t, tc.wantTransferred, transfer.Status.TransferredObjects)
+			require.Len(t, transfer.Status.Objects, int(tc.wantTransferred))
+			if tc.wantCondition {
+				require.NotNil(t, transfer.Status.CompletionTime)
+			}
+		})
+	}
+}
+
+func sortedKeys(objs map[string]storedObject) []string {
+	if len(objs) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(objs))
+	for key := range objs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,284 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policybundle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clusters"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	kcpclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
+	tenancyinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/tenancy/v1alpha1"
+	tenancylisters "github.com/kcp-dev/kcp/pkg/client/listers/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/reconciler/latency"
+)
+
+const (
+	controllerName = "kcp-policy-bundle"
+
+	// PolicyBundleFinalizer makes sure the objects of a PolicyBundle are removed
+	// before the PolicyBundle is deleted.
+	PolicyBundleFinalizer = "tenancy.kcp.dev/policy-bundle"
+
+	// resyncPeriod is how often the objects of a PolicyBundle are checked for drift.
+	// PolicyBundles are not informed about changes of the objects in the workspaces.
+	resyncPeriod = time.Minute
+)
+
+// NewController returns a new controller that applies PolicyBundles to the workspaces below
+// their workspace, and reports their compliance.
+func NewController(
+	dynamicClusterClient dynamic.ClusterInterface,
+	kcpClusterClient kcpclient.ClusterInterface,
+	policyBundleInformer tenancyinformers.PolicyBundleInformer,
+	workspaceInformer tenancyinformers.ClusterWorkspaceInformer,
+) (*controller, error) {
+	queue := latency.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+
+	c := &controller{
+		queue: queue,
+		enqueueAfter: func(bundle *tenancyv1alpha1.PolicyBundle, duration time.Duration) {
+			key := clusters.ToClusterAwareKey(logicalcluster.From(bundle), bundle.Name)
+			queue.AddAfter(key, duration)
+		},
+		now:                time.Now,
+		kcpClusterClient:   kcpClusterClient,
+		policyBundleLister: policyBundleInformer.Lister(),
+		listWorkspaces: func(selector labels.Selector) ([]*tenancyv1alpha1.ClusterWorkspace, error) {
+			return workspaceInformer.Lister().List(selector)
+		},
+		updatePolicyBundle: func(ctx context.Context, bundle *tenancyv1alpha1.PolicyBundle) (*tenancyv1alpha1.PolicyBundle, error) {
+			return kcpClusterClient.Cluster(logicalcluster.From(bundle)).TenancyV1alpha1().PolicyBundles().Update(ctx, bundle, metav1.UpdateOptions{})
+		},
+		listObjects: func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource) ([]unstructured.Unstructured, error) {
+			list, err := dynamicClusterClient.Cluster(clusterName).Resource(gvr).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		},
+		createObject: func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) error {
+			_, err := dynamicClusterClient.Cluster(clusterName).Resource(gvr).Namespace(obj.GetNamespace()).Create(ctx, obj, metav1.CreateOptions{})
+			return err
+		},
+		updateObject: func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) error {
+			_, err := dynamicClusterClient.Cluster(clusterName).Resource(gvr).Namespace(obj.GetNamespace()).Update(ctx, obj, metav1.UpdateOptions{})
+			return err
+		},
+		deleteObject: func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, namespace, name string) error {
+			return dynamicClusterClient.Cluster(clusterName).Resource(gvr).Namespace(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+		},
+	}
+
+	policyBundleInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueuePolicyBundle(obj) },
+		UpdateFunc: func(_, obj interface{}) { c.enqueuePolicyBundle(obj) },
+		DeleteFunc: func(obj interface{}) { c.enqueuePolicyBundle(obj) },
+	})
+
+	// workspaces becoming ready or changing their labels change the targets.
+	workspaceInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueueWorkspace(obj) },
+		UpdateFunc: func(_, obj interface{}) { c.enqueueWorkspace(obj) },
+		DeleteFunc: func(obj interface{}) { c.enqueueWorkspace(obj) },
+	})
+
+	return c, nil
+}
+
+// controller applies PolicyBundles to the workspaces below their workspace.
+type controller struct {
+	queue        workqueue.RateLimitingInterface
+	enqueueAfter func(*tenancyv1alpha1.PolicyBundle, time.Duration)
+	now          func() time.Time
+
+	kcpClusterClient kcpclient.ClusterInterface
+
+	policyBundleLister tenancylisters.PolicyBundleLister
+
+	listWorkspaces     func(selector labels.Selector) ([]*tenancyv1alpha1.ClusterWorkspace, error)
+	updatePolicyBundle func(ctx context.Context, bundle *tenancyv1alpha1.PolicyBundle) (*tenancyv1alpha1.PolicyBundle, error)
+	listObjects        func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource) ([]unstructured.Unstructured, error)
+	createObject       func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) error
+	updateObject       func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) error
+	deleteObject       func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, namespace, name string) error
+}
+
+func (c *controller) enqueuePolicyBundle(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	klog.Infof("Queueing PolicyBundle %q", key)
+	c.queue.Add(key)
+}
+
+// enqueueWorkspace enqueues the PolicyBundles of all ancestors of a ClusterWorkspace.
+func (c *controller) enqueueWorkspace(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	ws, ok := obj.(*tenancyv1alpha1.ClusterWorkspace)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("unexpected object type %T", obj))
+		return
+	}
+
+	bundles, err := c.policyBundleLister.List(labels.Everything())
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	parent := logicalcluster.From(ws)
+	for _, bundle := range bundles {
+		if !isBelow(parent, logicalcluster.From(bundle)) {
+			continue
+		}
+		key := clusters.ToClusterAwareKey(logicalcluster.From(bundle), bundle.Name)
+		klog.V(4).Infof("Queueing PolicyBundle %q because of ClusterWorkspace %s|%s", key, parent, ws.Name)
+		c.queue.Add(key)
+	}
+}
+
+// isBelow returns true if the parent of a workspace is the given ancestor, or below it.
+func isBelow(parent, ancestor logicalcluster.Name) bool {
+	return parent == ancestor || strings.HasPrefix(parent.String(), ancestor.String()+":")
+}
+
+// Start starts the controller, which stops when ctx.Done() is closed.
+func (c *controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	klog.Infof("Starting %s controller", controllerName)
+	defer klog.Infof("Shutting down %s controller", controllerName)
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+
+	<-ctx.Done()
+}
+
+func (c *controller) startWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	if err := c.process(ctx, key); err != nil {
+		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", controllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *controller) process(ctx context.Context, key string) error {
+	_, clusterAwareName, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		klog.Errorf("invalid key: %q: %v", key, err)
+		return nil
+	}
+	clusterName, name := clusters.SplitClusterAwareKey(clusterAwareName)
+
+	obj, err := c.policyBundleLister.Get(key)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil // object deleted before we handled it
+		}
+		return err
+	}
+	old := obj
+	obj = obj.DeepCopy()
+
+	if err := c.reconcile(ctx, obj); err != nil {
+		return err
+	}
+	if !obj.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	// If the object being reconciled changed as a result, update it.
+	if !equality.Semantic.DeepEqual(old.Status, obj.Status) {
+		oldData, err := json.Marshal(tenancyv1alpha1.PolicyBundle{
+			Status: old.Status,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to Marshal old data for PolicyBundle %s|%s: %w", clusterName, name, err)
+		}
+
+		newData, err := json.Marshal(tenancyv1alpha1.PolicyBundle{
+			ObjectMeta: metav1.ObjectMeta{
+				UID:             obj.UID,
+				ResourceVersion: obj.ResourceVersion,
+			}, // to ensure they appear in the patch as preconditions
+			Status: obj.Status,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to Marshal new data for PolicyBundle %s|%s: %w", clusterName, name, err)
+		}
+
+		patchBytes, err := jsonpatch.CreateMergePatch(oldData, newData)
+		if err != nil {
+			return fmt.Errorf("failed to create patch for PolicyBundle %s|%s: %w", clusterName, name, err)
+		}
+		if _, err := c.kcpClusterClient.Cluster(clusterName).TenancyV1alpha1().PolicyBundles().Patch(ctx, obj.Name, types.MergePatchType, patchBytes, metav1.PatchOptions{}, "status"); err != nil {
+			return err
+		}
+	}
+
+	// objects in the workspaces are not informed about, check them for drift regularly.
+	c.enqueueAfter(obj, resyncPeriod)
+
+	return nil
+}
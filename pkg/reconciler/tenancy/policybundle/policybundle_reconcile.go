@@ -0,0 +1,404 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policybundle
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/clusters"
+	"k8s.io/klog/v2"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/third_party/conditions/util/conditions"
+)
+
+var (
+	denyPoliciesGVR        = tenancyv1alpha1.SchemeGroupVersion.WithResource("denypolicies")
+	clusterRolesGVR        = rbacv1.SchemeGroupVersion.WithResource("clusterroles")
+	clusterRoleBindingsGVR = rbacv1.SchemeGroupVersion.WithResource("clusterrolebindings")
+	resourceQuotasGVR      = corev1.SchemeGroupVersion.WithResource("resourcequotas")
+	namespacesGVR          = corev1.SchemeGroupVersion.WithResource("namespaces")
+
+	// bundleResources are the resources of the objects of a PolicyBundle, in the order they are applied.
+	bundleResources = []schema.GroupVersionResource{denyPoliciesGVR, clusterRolesGVR, clusterRoleBindingsGVR, resourceQuotasGVR}
+)
+
+// maxReportedWorkspaces is the number of non-compliant workspaces listed in the Compliant condition.
+const maxReportedWorkspaces = 5
+
+func (c *controller) reconcile(ctx context.Context, bundle *tenancyv1alpha1.PolicyBundle) error {
+	clusterName := logicalcluster.From(bundle)
+
+	if !bundle.DeletionTimestamp.IsZero() {
+		return c.reconcileDeletion(ctx, bundle)
+	}
+	if !sets.NewString(bundle.Finalizers...).Has(PolicyBundleFinalizer) {
+		bundle.Finalizers = append(bundle.Finalizers, PolicyBundleFinalizer)
+		updated, err := c.updatePolicyBundle(ctx, bundle)
+		if err != nil {
+			return err
+		}
+		updated.Status = bundle.Status
+		*bundle = *updated
+	}
+
+	workspaceSelector := labels.Everything()
+	if bundle.Spec.WorkspaceSelector != nil {
+		var err error
+		if workspaceSelector, err = metav1.LabelSelectorAsSelector(bundle.Spec.WorkspaceSelector); err != nil {
+			conditions.MarkFalse(bundle, tenancyv1alpha1.PolicyBundleCompliant, tenancyv1alpha1.PolicyBundleInvalidSelectorReason, conditionsv1alpha1.ConditionSeverityError, "Invalid workspace selector: %v", err)
+			return nil
+		}
+	}
+
+	workspaces, err := c.listWorkspaces(workspaceSelector)
+	if err != nil {
+		return err
+	}
+	targets := sets.NewString()
+	for _, ws := range workspaces {
+		parent := logicalcluster.From(ws)
+		if isBelow(parent, clusterName) && ws.Status.Phase == tenancyv1alpha1.ClusterWorkspacePhaseReady {
+			targets.Insert(parent.Join(ws.Name).String())
+		}
+	}
+
+	previous := map[string]tenancyv1alpha1.PolicyBundleWorkspaceStatus{}
+	for _, report := range bundle.Status.Workspaces {
+		previous[report.Workspace] = report
+	}
+
+	var reports []tenancyv1alpha1.PolicyBundleWorkspaceStatus
+	for _, target := range targets.List() {
+		reports = append(reports, c.apply(ctx, bundle, logicalcluster.New(target), previous[target]))
+	}
+
+	// remove the objects from workspaces which are not targeted anymore. Keep reporting
+	// the workspaces until that succeeded.
+	for _, report := range bundle.Status.Workspaces {
+		if targets.Has(report.Workspace) {
+			continue
+		}
+		if _, _, err := c.sync(ctx, bundle, logicalcluster.New(report.Workspace), nil, true); err != nil {
+			klog.Errorf("Failed to remove objects of PolicyBundle %s|%s from workspace %s: %v", clusterName, bundle.Name, report.Workspace, err)
+			report.Compliant = false
+			report.Message = fmt.Sprintf("Failed to remove objects: %v", err)
+			reports = append(reports, report)
+		}
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].Workspace < reports[j].Workspace })
+
+	var nonCompliant []string
+	for _, report := range reports {
+		if !report.Compliant {
+			nonCompliant = append(nonCompliant, report.Workspace)
+		}
+	}
+	bundle.Status.Workspaces = reports
+	bundle.Status.TargetedWorkspaces = int32(len(reports))
+	bundle.Status.CompliantWorkspaces = int32(len(reports) - len(nonCompliant))
+
+	if len(nonCompliant) > 0 {
+		listed := nonCompliant
+		if len(listed) > maxReportedWorkspaces {
+			listed = append(listed[:maxReportedWorkspaces:maxReportedWorkspaces], "...")
+		}
+		conditions.MarkFalse(bundle, tenancyv1alpha1.PolicyBundleCompliant, tenancyv1alpha1.PolicyBundleNonCompliantReason, conditionsv1alpha1.ConditionSeverityWarning, "%d of %d workspaces do not comply: %s", len(nonCompliant), len(reports), strings.Join(listed, ", "))
+	} else {
+		conditions.MarkTrue(bundle, tenancyv1alpha1.PolicyBundleCompliant)
+	}
+
+	return nil
+}
+
+// reconcileDeletion removes the objects from all targeted workspaces, and then the finalizer.
+func (c *controller) reconcileDeletion(ctx context.Context, bundle *tenancyv1alpha1.PolicyBundle) error {
+	finalizers := sets.NewString(bundle.Finalizers...)
+	if !finalizers.Has(PolicyBundleFinalizer) {
+		return nil
+	}
+
+	for _, report := range bundle.Status.Workspaces {
+		if _, _, err := c.sync(ctx, bundle, logicalcluster.New(report.Workspace), nil, true); err != nil {
+			return err
+		}
+	}
+	klog.Infof("Removed objects of deleted PolicyBundle %s|%s", logicalcluster.From(bundle), bundle.Name)
+
+	bundle.Finalizers = finalizers.Delete(PolicyBundleFinalizer).List()
+	_, err := c.updatePolicyBundle(ctx, bundle)
+	return err
+}
+
+// apply checks the objects of the bundle in the target workspace, restores them with Enforce,
+// and returns the compliance report of the workspace.
+func (c *controller) apply(ctx context.Context, bundle *tenancyv1alpha1.PolicyBundle, target logicalcluster.Name, previous tenancyv1alpha1.PolicyBundleWorkspaceStatus) tenancyv1alpha1.PolicyBundleWorkspaceStatus {
+	enforce := bundle.Spec.Enforcement != tenancyv1alpha1.PolicyBundleAudit
+	report := tenancyv1alpha1.PolicyBundleWorkspaceStatus{
+		Workspace:          target.String(),
+		ObservedGeneration: previous.ObservedGeneration,
+		Drifted:            previous.Drifted,
+		LastDriftTime:      previous.LastDriftTime,
+	}
+
+	desired, err := c.desiredObjects(ctx, bundle, target)
+	if err != nil {
+		report.Message = err.Error()
+		return report
+	}
+	differences, conflicts, err := c.sync(ctx, bundle, target, desired, enforce)
+	if err != nil {
+		klog.Errorf("Failed to apply PolicyBundle %s|%s to workspace %s: %v", logicalcluster.From(bundle), bundle.Name, target, err)
+		report.Message = err.Error()
+		return report
+	}
+	report.Conflicts = conflicts
+
+	// differences are expected when the bundle changed since it was last applied. Otherwise,
+	// the objects drifted in the workspace.
+	switch {
+	case !enforce:
+		if len(differences) > 0 && !equality.Semantic.DeepEqual(differences, previous.Drifted) {
+			report.LastDriftTime = &metav1.Time{Time: c.now()}
+		}
+		report.Drifted = differences
+	case len(differences) > 0 && previous.ObservedGeneration == bundle.Generation && previous.Message == "":
+		klog.V(2).Infof("Restored drifted objects of PolicyBundle %s|%s in workspace %s: %s", logicalcluster.From(bundle), bundle.Name, target, strings.Join(differences, ", "))
+		report.Drifted = differences
+		report.LastDriftTime = &metav1.Time{Time: c.now()}
+	}
+
+	report.ObservedGeneration = bundle.Generation
+	report.Compliant = len(conflicts) == 0 && (enforce || len(differences) == 0)
+	return report
+}
+
+// desiredObjects returns the objects of the bundle for the target workspace by resource.
+func (c *controller) desiredObjects(ctx context.Context, bundle *tenancyv1alpha1.PolicyBundle, target logicalcluster.Name) (map[schema.GroupVersionResource][]*unstructured.Unstructured, error) {
+	var objs []runtime.Object
+	for _, policy := range bundle.Spec.AdmissionPolicies {
+		objs = append(objs, &tenancyv1alpha1.DenyPolicy{
+			TypeMeta:   metav1.TypeMeta{APIVersion: tenancyv1alpha1.SchemeGroupVersion.String(), Kind: "DenyPolicy"},
+			ObjectMeta: metav1.ObjectMeta{Name: policy.Name},
+			Spec:       policy.Spec,
+		})
+	}
+	for _, role := range bundle.Spec.ClusterRoles {
+		objs = append(objs, &rbacv1.ClusterRole{
+			TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRole"},
+			ObjectMeta: metav1.ObjectMeta{Name: role.Name},
+			Rules:      role.Rules,
+		})
+	}
+	for _, binding := range bundle.Spec.ClusterRoleBindings {
+		subjects := make([]rbacv1.Subject, 0, len(binding.Subjects))
+		for _, subject := range binding.Subjects {
+			// the API group is defaulted for users and groups, and would be detected as drift otherwise.
+			if subject.APIGroup == "" && (subject.Kind == rbacv1.UserKind || subject.Kind == rbacv1.GroupKind) {
+				subject.APIGroup = rbacv1.GroupName
+			}
+			subjects = append(subjects, subject)
+		}
+		objs = append(objs, &rbacv1.ClusterRoleBinding{
+			TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRoleBinding"},
+			ObjectMeta: metav1.ObjectMeta{Name: binding.Name},
+			RoleRef:    binding.RoleRef,
+			Subjects:   subjects,
+		})
+	}
+	if len(bundle.Spec.ResourceQuotas) > 0 {
+		namespaces, err := c.listObjects(ctx, target, namespacesGVR)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list namespaces: %w", err)
+		}
+		for _, ns := range namespaces {
+			if ns.GetDeletionTimestamp() != nil {
+				continue
+			}
+			for _, quota := range bundle.Spec.ResourceQuotas {
+				objs = append(objs, &corev1.ResourceQuota{
+					TypeMeta:   metav1.TypeMeta{APIVersion: corev1.SchemeGroupVersion.String(), Kind: "ResourceQuota"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: ns.GetName(), Name: quota.Name},
+					Spec:       corev1.ResourceQuotaSpec{Hard: quota.Hard},
+				})
+			}
+		}
+	}
+
+	owner := clusters.ToClusterAwareKey(logicalcluster.From(bundle), bundle.Name)
+	ret := map[schema.GroupVersionResource][]*unstructured.Unstructured{}
+	for _, obj := range objs {
+		raw, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil, err
+		}
+		u := &unstructured.Unstructured{Object: raw}
+		u.SetLabels(map[string]string{tenancyv1alpha1.PolicyBundleManagedLabel: "true"})
+		u.SetAnnotations(map[string]string{tenancyv1alpha1.PolicyBundleAnnotation: owner})
+
+		var gvr schema.GroupVersionResource
+		switch obj.(type) {
+		case *tenancyv1alpha1.DenyPolicy:
+			gvr = denyPoliciesGVR
+		case *rbacv1.ClusterRole:
+			gvr = clusterRolesGVR
+		case *rbacv1.ClusterRoleBinding:
+			gvr = clusterRoleBindingsGVR
+		case *corev1.ResourceQuota:
+			gvr = resourceQuotasGVR
+		}
+		ret[gvr] = append(ret[gvr], u)
+	}
+	return ret, nil
+}
+
+// sync compares the objects of the bundle in the target workspace with the desired objects, and
+// returns the missing and differing objects, and the objects with the names of desired objects
+// which are not managed by the bundle. With write, missing and differing objects are restored,
+// and objects of the bundle which are not desired anymore are deleted.
+func (c *controller) sync(ctx context.Context, bundle *tenancyv1alpha1.PolicyBundle, target logicalcluster.Name, desired map[schema.GroupVersionResource][]*unstructured.Unstructured, write bool) ([]string, []string, error) {
+	owner := clusters.ToClusterAwareKey(logicalcluster.From(bundle), bundle.Name)
+
+	var differences, conflicts []string
+	for _, gvr := range bundleResources {
+		if len(desired[gvr]) == 0 && !write {
+			continue
+		}
+
+		existing, err := c.listObjects(ctx, target, gvr)
+		if err != nil {
+			if len(desired[gvr]) == 0 && (errors.IsNotFound(err) || errors.IsMethodNotSupported(err)) {
+				continue // e.g. DenyPolicies are not served in the workspace.
+			}
+			return nil, nil, err
+		}
+		owned := map[string]*unstructured.Unstructured{}
+		foreign := sets.NewString()
+		for i := range existing {
+			key := objectKey(&existing[i])
+			if isOwnedBy(&existing[i], owner) {
+				owned[key] = &existing[i]
+			} else {
+				foreign.Insert(key)
+			}
+		}
+
+		for _, obj := range desired[gvr] {
+			key := objectKey(obj)
+			name := gvr.Resource + "/" + key
+
+			current, found := owned[key]
+			delete(owned, key)
+			switch {
+			case !found && foreign.Has(key):
+				conflicts = append(conflicts, name)
+			case !found:
+				differences = append(differences, name)
+				if write {
+					if err := c.createObject(ctx, target, gvr, obj); err != nil && !errors.IsAlreadyExists(err) {
+						return nil, nil, err
+					}
+				}
+			case !matches(current, obj):
+				differences = append(differences, name)
+				if write {
+					if err := c.updateObject(ctx, target, gvr, restored(current, obj)); err != nil {
+						return nil, nil, err
+					}
+				}
+			}
+		}
+
+		// delete objects which are not part of the bundle anymore.
+		if write {
+			for key, obj := range owned {
+				if err := c.deleteObject(ctx, target, gvr, obj.GetNamespace(), obj.GetName()); err != nil && !errors.IsNotFound(err) {
+					return nil, nil, err
+				}
+				klog.V(2).Infof("Deleted %s %s of PolicyBundle %s in workspace %s", gvr.Resource, key, owner, target)
+			}
+		}
+	}
+
+	sort.Strings(differences)
+	sort.Strings(conflicts)
+	return differences, conflicts, nil
+}
+
+func isOwnedBy(obj *unstructured.Unstructured, owner string) bool {
+	_, managed := obj.GetLabels()[tenancyv1alpha1.PolicyBundleManagedLabel]
+	return managed && obj.GetAnnotations()[tenancyv1alpha1.PolicyBundleAnnotation] == owner
+}
+
+// contentOf returns the top-level fields of an object besides its type, metadata and status.
+func contentOf(obj *unstructured.Unstructured) map[string]interface{} {
+	ret := map[string]interface{}{}
+	for k, v := range obj.Object {
+		switch k {
+		case "apiVersion", "kind", "metadata", "status":
+			continue
+		}
+		if v != nil {
+			ret[k] = v
+		}
+	}
+	return ret
+}
+
+// matches returns true if the content of the existing object equals the desired object.
+// Additional labels and annotations are no drift.
+func matches(existing, desired *unstructured.Unstructured) bool {
+	return equality.Semantic.DeepEqual(contentOf(existing), contentOf(desired))
+}
+
+// restored returns the existing object with the content of the desired object.
+func restored(existing, desired *unstructured.Unstructured) *unstructured.Unstructured {
+	ret := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	for k, v := range existing.Object {
+		switch k {
+		case "apiVersion", "kind", "metadata", "status":
+			ret.Object[k] = runtime.DeepCopyJSONValue(v)
+		}
+	}
+	for k, v := range contentOf(desired) {
+		ret.Object[k] = runtime.DeepCopyJSONValue(v)
+	}
+	return ret
+}
+
+func objectKey(obj *unstructured.Unstructured) string {
+	if obj.GetNamespace() == "" {
+		return obj.GetName()
+	}
+	return obj.GetNamespace() + "/" + obj.GetName()
+}
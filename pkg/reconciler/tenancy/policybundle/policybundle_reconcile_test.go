@@ -0,0 +1,419 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policybundle
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/third_party/conditions/util/conditions"
+)
+
+const owner = "root:org|baseline"
+
+func clusterRole(name, owner string, verbs ...string) *unstructured.Unstructured {
+	var vs []interface{}
+	for _, verb := range verbs {
+		vs = append(vs, verb)
+	}
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "rbac.authorization.k8s.io/v1",
+		"kind":       "ClusterRole",
+		"rules": []interface{}{
+			map[string]interface{}{
+				"apiGroups": []interface{}{""},
+				"resources": []interface{}{"configmaps"},
+				"verbs":     vs,
+			},
+		},
+	}}
+	obj.SetName(name)
+	if owner != "" {
+		obj.SetLabels(map[string]string{tenancyv1alpha1.PolicyBundleManagedLabel: "true"})
+		obj.SetAnnotations(map[string]string{tenancyv1alpha1.PolicyBundleAnnotation: owner})
+	}
+	return obj
+}
+
+func namespace(name string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Namespace",
+	}}
+	obj.SetName(name)
+	return obj
+}
+
+func workspace(parent, name string, phase tenancyv1alpha1.ClusterWorkspacePhaseType, wsLabels map[string]string) *tenancyv1alpha1.ClusterWorkspace {
+	return &tenancyv1alpha1.ClusterWorkspace{
+		ObjectMeta: metav1.ObjectMeta{Name: name, ClusterName: parent, Labels: wsLabels},
+		Status:     tenancyv1alpha1.ClusterWorkspaceStatus{Phase: phase},
+	}
+}
+
+type objects map[logicalcluster.Name]map[schema.GroupVersionResource][]*unstructured.Unstructured
+
+func TestReconcile(t *testing.T) {
+	now := time.Date(2022, 7, 1, 12, 0, 0, 0, time.UTC)
+	one := logicalcluster.New("root:org:one")
+	two := logicalcluster.New("root:org:two")
+
+	bundle := func(mutate func(*tenancyv1alpha1.PolicyBundle)) *tenancyv1alpha1.PolicyBundle {
+		b := &tenancyv1alpha1.PolicyBundle{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "baseline",
+				ClusterName: "root:org",
+				Generation:  1,
+				Finalizers:  []string{PolicyBundleFinalizer},
+			},
+			Spec: tenancyv1alpha1.PolicyBundleSpec{
+				ClusterRoles: []tenancyv1alpha1.PolicyBundleClusterRole{
+					{Name: "viewer", Rules: []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"configmaps"}, Verbs: []string{"get"}}}},
+				},
+			},
+		}
+		if mutate != nil {
+			mutate(b)
+		}
+		return b
+	}
+	applied := func(b *tenancyv1alpha1.PolicyBundle) {
+		b.Status.Workspaces = []tenancyv1alpha1.PolicyBundleWorkspaceStatus{{Workspace: "root:org:one", Compliant: true, ObservedGeneration: 1}}
+	}
+
+	tests := map[string]struct {
+		bundle     *tenancyv1alpha1.PolicyBundle
+		workspaces []*tenancyv1alpha1.ClusterWorkspace
+		objects    objects
+
+		wantObjects          map[logicalcluster.Name][]string
+		wantVerbs            []interface{}
+		wantReports          []tenancyv1alpha1.PolicyBundleWorkspaceStatus
+		wantCondition        bool
+		wantReason           string
+		wantFinalizerRemoved bool
+	}{
+		"applies the bundle to ready workspaces below the workspace": {
+			bundle: bundle(func(b *tenancyv1alpha1.PolicyBundle) {
+				b.Spec.ClusterRoleBindings = []tenancyv1alpha1.PolicyBundleClusterRoleBinding{{
+					Name:     "viewers",
+					RoleRef:  rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "viewer"},
+					Subjects: []rbacv1.Subject{{Kind: rbacv1.GroupKind, Name: "org:viewers"}},
+				}}
+				b.Spec.ResourceQuotas = []tenancyv1alpha1.PolicyBundleResourceQuota{{
+					Name: "default",
+					Hard: corev1.ResourceList{"pods": resource.MustParse("10")},
+				}}
+			}),
+			workspaces: []*tenancyv1alpha1.ClusterWorkspace{
+				workspace("root:org", "one", tenancyv1alpha1.ClusterWorkspacePhaseReady, nil),
+				workspace("root:org", "two", tenancyv1alpha1.ClusterWorkspacePhaseInitializing, nil),
+				workspace("root:org:one", "nested", tenancyv1alpha1.ClusterWorkspacePhaseReady, nil),
+				workspace("root:other", "foreign", tenancyv1alpha1.ClusterWorkspacePhaseReady, nil),
+			},
+			objects: objects{
+				one: {namespacesGVR: {namespace("default")}},
+			},
+			wantObjects: map[logicalcluster.Name][]string{
+				one: {"clusterrolebindings/viewers", "clusterroles/viewer", "resourcequotas/default/default"},
+				logicalcluster.New("root:org:one:nested"): {"clusterrolebindings/viewers", "clusterroles/viewer"},
+			},
+			wantReports: []tenancyv1alpha1.PolicyBundleWorkspaceStatus{
+				{Workspace: "root:org:one", Compliant: true, ObservedGeneration: 1},
+				{Workspace: "root:org:one:nested", Compliant: true, ObservedGeneration: 1},
+			},
+			wantCondition: true,
+		},
+		"restores drifted objects": {
+			bundle: bundle(applied),
+			workspaces: []*tenancyv1alpha1.ClusterWorkspace{
+				workspace("root:org", "one", tenancyv1alpha1.ClusterWorkspacePhaseReady, nil),
+			},
+			objects: objects{
+				one: {clusterRolesGVR: {clusterRole("viewer", owner, "get", "delete")}},
+			},
+			wantObjects: map[logicalcluster.Name][]string{
+				one: {"clusterroles/viewer"},
+			},
+			wantVerbs: []interface{}{"get"},
+			wantReports: []tenancyv1alpha1.PolicyBundleWorkspaceStatus{
+				{Workspace: "root:org:one", Compliant: true, ObservedGeneration: 1, Drifted: []string{"clusterroles/viewer"}, LastDriftTime: &metav1.Time{Time: now}},
+			},
+			wantCondition: true,
+		},
+		"changes of the bundle are no drift": {
+			bundle: bundle(func(b *tenancyv1alpha1.PolicyBundle) {
+				applied(b)
+				b.Generation = 2
+			}),
+			workspaces: []*tenancyv1alpha1.ClusterWorkspace{
+				workspace("root:org", "one", tenancyv1alpha1.ClusterWorkspacePhaseReady, nil),
+			},
+			objects: objects{
+				one: {clusterRolesGVR: {clusterRole("viewer", owner, "get", "delete"), clusterRole("removed", owner, "get")}},
+			},
+			wantObjects: map[logicalcluster.Name][]string{
+				one: {"clusterroles/viewer"},
+			},
+			wantVerbs: []interface{}{"get"},
+			wantReports: []tenancyv1alpha1.PolicyBundleWorkspaceStatus{
+				{Workspace: "root:org:one", Compliant: true, ObservedGeneration: 2},
+			},
+			wantCondition: true,
+		},
+		"objects not managed by the bundle are conflicts": {
+			bundle: bundle(nil),
+			workspaces: []*tenancyv1alpha1.ClusterWorkspace{
+				workspace("root:org", "one", tenancyv1alpha1.ClusterWorkspacePhaseReady, nil),
+			},
+			objects: objects{
+				one: {clusterRolesGVR: {clusterRole("viewer", "", "*")}},
+			},
+			wantObjects: map[logicalcluster.Name][]string{
+				one: {"clusterroles/viewer"},
+			},
+			wantVerbs: []interface{}{"*"},
+			wantReports: []tenancyv1alpha1.PolicyBundleWorkspaceStatus{
+				{Workspace: "root:org:one", Compliant: false, ObservedGeneration: 1, Conflicts: []string{"clusterroles/viewer"}},
+			},
+			wantCondition: false,
+			wantReason:    tenancyv1alpha1.PolicyBundleNonCompliantReason,
+		},
+		"audit reports drift without restoring it": {
+			bundle: bundle(func(b *tenancyv1alpha1.PolicyBundle) {
+				b.Spec.Enforcement = tenancyv1alpha1.PolicyBundleAudit
+			}),
+			workspaces: []*tenancyv1alpha1.ClusterWorkspace{
+				workspace("root:org", "one", tenancyv1alpha1.ClusterWorkspacePhaseReady, nil),
+			},
+			wantObjects: map[logicalcluster.Name][]string{},
+			wantReports: []tenancyv1alpha1.PolicyBundleWorkspaceStatus{
+				{Workspace: "root:org:one", Compliant: false, ObservedGeneration: 1, Drifted: []string{"clusterroles/viewer"}, LastDriftTime: &metav1.Time{Time: now}},
+			},
+			wantCondition: false,
+			wantReason:    tenancyv1alpha1.PolicyBundleNonCompliantReason,
+		},
+		"objects are removed from workspaces not targeted anymore": {
+			bundle: bundle(func(b *tenancyv1alpha1.PolicyBundle) {
+				b.Spec.WorkspaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}}
+				b.Status.Workspaces = []tenancyv1alpha1.PolicyBundleWorkspaceStatus{
+					{Workspace: "root:org:one", Compliant: true, ObservedGeneration: 1},
+					{Workspace: "root:org:two", Compliant: true, ObservedGeneration: 1},
+				}
+			}),
+			workspaces: []*tenancyv1alpha1.ClusterWorkspace{
+				workspace("root:org", "one", tenancyv1alpha1.ClusterWorkspacePhaseReady, map[string]string{"team": "a"}),
+				workspace("root:org", "two", tenancyv1alpha1.ClusterWorkspacePhaseReady, map[string]string{"team": "b"}),
+			},
+			objects: objects{
+				one: {clusterRolesGVR: {clusterRole("viewer", owner, "get")}},
+				two: {clusterRolesGVR: {clusterRole("viewer", owner, "get"), clusterRole("other", "root:other|baseline", "get")}},
+			},
+			wantObjects: map[logicalcluster.Name][]string{
+				one: {"clusterroles/viewer"},
+				two: {"clusterroles/other"},
+			},
+			wantReports: []tenancyv1alpha1.PolicyBundleWorkspaceStatus{
+				{Workspace: "root:org:one", Compliant: true, ObservedGeneration: 1},
+			},
+			wantCondition: true,
+		},
+		"invalid selector": {
+			bundle: bundle(func(b *tenancyv1alpha1.PolicyBundle) {
+				b.Spec.WorkspaceSelector = &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "team", Operator: "Bogus"}}}
+			}),
+			wantCondition: false,
+			wantReason:    tenancyv1alpha1.PolicyBundleInvalidSelectorReason,
+		},
+		"deletion removes the objects and the finalizer": {
+			bundle: bundle(func(b *tenancyv1alpha1.PolicyBundle) {
+				deleted := metav1.Now()
+				b.DeletionTimestamp = &deleted
+				applied(b)
+			}),
+			objects: objects{
+				one: {clusterRolesGVR: {clusterRole("viewer", owner, "get")}},
+			},
+			wantObjects:          map[logicalcluster.Name][]string{},
+			wantReports:          []tenancyv1alpha1.PolicyBundleWorkspaceStatus{{Workspace: "root:org:one", Compliant: true, ObservedGeneration: 1}},
+			wantFinalizerRemoved: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			store := objects{}
+			for clusterName, byResource := range tc.objects {
+				store[clusterName] = map[schema.GroupVersionResource][]*unstructured.Unstructured{}
+				for gvr, objs := range byResource {
+					for _, obj := range objs {
+						store[clusterName][gvr] = append(store[clusterName][gvr], obj.DeepCopy())
+					}
+				}
+			}
+			find := func(clusterName logicalcluster.Name, gvr schema.GroupVersionResource, key string) int {
+				for i, obj := range store[clusterName][gvr] {
+					if objectKey(obj) == key {
+						return i
+					}
+				}
+				return -1
+			}
+
+			var updated *tenancyv1alpha1.PolicyBundle
+			c := &controller{
+				now: func() time.Time { return now },
+				listWorkspaces: func(selector labels.Selector) ([]*tenancyv1alpha1.ClusterWorkspace, error) {
+					var ret []*tenancyv1alpha1.ClusterWorkspace
+					for _, ws := range tc.workspaces {
+						if selector.Matches(labels.Set(ws.Labels)) {
+							ret = append(ret, ws)
+						}
+					}
+					return ret, nil
+				},
+				updatePolicyBundle: func(ctx context.Context, bundle *tenancyv1alpha1.PolicyBundle) (*tenancyv1alpha1.PolicyBundle, error) {
+					updated = bundle.DeepCopy()
+					return bundle.DeepCopy(), nil
+				},
+				listObjects: func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource) ([]unstructured.Unstructured, error) {
+					var ret []unstructured.Unstructured
+					for _, obj := range store[clusterName][gvr] {
+						ret = append(ret, *obj.DeepCopy())
+					}
+					return ret, nil
+				},
+				createObject: func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) error {
+					if find(clusterName, gvr, objectKey(obj)) >= 0 {
+						return errors.NewAlreadyExists(gvr.GroupResource(), obj.GetName())
+					}
+					if store[clusterName] == nil {
+						store[clusterName] = map[schema.GroupVersionResource][]*unstructured.Unstructured{}
+					}
+					store[clusterName][gvr] = append(store[clusterName][gvr], obj.DeepCopy())
+					return nil
+				},
+				updateObject: func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) error {
+					i := find(clusterName, gvr, objectKey(obj))
+					if i < 0 {
+						return errors.NewNotFound(gvr.GroupResource(), obj.GetName())
+					}
+					store[clusterName][gvr][i] = obj.DeepCopy()
+					return nil
+				},
+				deleteObject: func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, namespace, name string) error {
+					key := name
+					if namespace != "" {
+						key = namespace + "/" + name
+					}
+					i := find(clusterName, gvr, key)
+					if i < 0 {
+						return errors.NewNotFound(gvr.GroupResource(), name)
+					}
+					store[clusterName][gvr] = append(store[clusterName][gvr][:i], store[clusterName][gvr][i+1:]...)
+					return nil
+				},
+			}
+
+			bundle := tc.bundle.DeepCopy()
+			err := c.reconcile(context.Background(), bundle)
+			require.NoError(t, err)
+
+			if tc.wantObjects != nil {
+				got := map[logicalcluster.Name][]string{}
+				for clusterName, byResource := range store {
+					for _, gvr := range bundleResources {
+						for _, obj := range byResource[gvr] {
+							got[clusterName] = append(got[clusterName], gvr.Resource+"/"+objectKey(obj))
+						}
+					}
+					sort.Strings(got[clusterName])
+				}
+				for clusterName, keys := range got {
+					if len(keys) == 0 {
+						delete(got, clusterName)
+					}
+				}
+				require.Equal(t, tc.wantObjects, got)
+			}
+			if tc.wantVerbs != nil {
+				i := find(one, clusterRolesGVR, "viewer")
+				require.GreaterOrEqual(t, i, 0)
+				rules, _, err := unstructured.NestedSlice(store[one][clusterRolesGVR][i].Object, "rules")
+				require.NoError(t, err)
+				require.Equal(t, tc.wantVerbs, rules[0].(map[string]interface{})["verbs"])
+			}
+
+			require.Equal(t, tc.wantReports, bundle.Status.Workspaces)
+
+			if tc.wantFinalizerRemoved {
+				require.NotNil(t, updated)
+				require.NotContains(t, updated.Finalizers, PolicyBundleFinalizer)
+				return
+			}
+			require.Equal(t, tc.wantCondition, conditions.IsTrue(bundle, tenancyv1alpha1.PolicyBundleCompliant))
+			if tc.wantReason != "" {
+				require.Equal(t, tc.wantReason, conditions.GetReason(bundle, tenancyv1alpha1.PolicyBundleCompliant))
+			}
+		})
+	}
+}
+
+func TestDesiredObjectsDefaultSubjectAPIGroup(t *testing.T) {
+	c := &controller{}
+	bundle := &tenancyv1alpha1.PolicyBundle{
+		ObjectMeta: metav1.ObjectMeta{Name: "baseline", ClusterName: "root:org"},
+		Spec: tenancyv1alpha1.PolicyBundleSpec{
+			ClusterRoleBindings: []tenancyv1alpha1.PolicyBundleClusterRoleBinding{{
+				Name:    "viewers",
+				RoleRef: rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "viewer"},
+				Subjects: []rbacv1.Subject{
+					{Kind: rbacv1.GroupKind, Name: "org:viewers"},
+					{Kind: rbacv1.ServiceAccountKind, Name: "default", Namespace: "default"},
+				},
+			}},
+		},
+	}
+
+	desired, err := c.desiredObjects(context.Background(), bundle, logicalcluster.New("root:org:one"))
+	require.NoError(t, err)
+	require.Len(t, desired[clusterRoleBindingsGVR], 1)
+
+	binding := desired[clusterRoleBindingsGVR][0]
+	require.Equal(t, owner, binding.GetAnnotations()[tenancyv1alpha1.PolicyBundleAnnotation])
+	subjects, _, err := unstructured.NestedSlice(binding.Object, "subjects")
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{
+		map[string]interface{}{"kind": "Group", "apiGroup": rbacv1.GroupName, "name": "org:viewers"},
+		map[string]interface{}{"kind": "ServiceAccount", "name": "default", "namespace": "default"},
+	}, subjects)
+}
@@ -0,0 +1,295 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replication
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clusters"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/authorization/delegated"
+	kcpclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
+	tenancyinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/tenancy/v1alpha1"
+	tenancylisters "github.com/kcp-dev/kcp/pkg/client/listers/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/reconciler/latency"
+)
+
+const (
+	controllerName = "kcp-replication"
+
+	// ReplicationFinalizer makes sure the replicas of a Replication are removed
+	// before the Replication is deleted.
+	ReplicationFinalizer = "tenancy.kcp.dev/replication"
+
+	// resyncPeriod is how often the source objects of a Replication are compared
+	// with their replicas. Replications are not informed about changes of their
+	// source objects, which can be of any resource.
+	resyncPeriod = time.Minute
+)
+
+// NewController returns a new controller that replicates the objects selected by Replications
+// into the child workspaces of their workspace.
+func NewController(
+	kubeClusterClient kubernetes.ClusterInterface,
+	dynamicClusterClient dynamic.ClusterInterface,
+	kcpClusterClient kcpclient.ClusterInterface,
+	replicationInformer tenancyinformers.ReplicationInformer,
+	workspaceInformer tenancyinformers.ClusterWorkspaceInformer,
+) (*controller, error) {
+	queue := latency.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+
+	c := &controller{
+		queue: queue,
+		enqueueAfter: func(replication *tenancyv1alpha1.Replication, duration time.Duration) {
+			key := clusters.ToClusterAwareKey(logicalcluster.From(replication), replication.Name)
+			queue.AddAfter(key, duration)
+		},
+		kcpClusterClient:  kcpClusterClient,
+		replicationLister: replicationInformer.Lister(),
+		createAuthorizer: func(clusterName logicalcluster.Name) (authorizer.Authorizer, error) {
+			return delegated.NewDelegatedAuthorizer(clusterName, kubeClusterClient)
+		},
+		listWorkspaces: func(clusterName logicalcluster.Name, selector labels.Selector) ([]*tenancyv1alpha1.ClusterWorkspace, error) {
+			workspaces, err := workspaceInformer.Lister().List(selector)
+			if err != nil {
+				return nil, err
+			}
+			var ret []*tenancyv1alpha1.ClusterWorkspace
+			for _, ws := range workspaces {
+				if logicalcluster.From(ws) == clusterName {
+					ret = append(ret, ws)
+				}
+			}
+			return ret, nil
+		},
+		updateReplication: func(ctx context.Context, replication *tenancyv1alpha1.Replication) (*tenancyv1alpha1.Replication, error) {
+			return kcpClusterClient.Cluster(logicalcluster.From(replication)).TenancyV1alpha1().Replications().Update(ctx, replication, metav1.UpdateOptions{})
+		},
+		listObjects: func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, namespace string, selector labels.Selector) ([]unstructured.Unstructured, error) {
+			list, err := dynamicClusterClient.Cluster(clusterName).Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		},
+		createObject: func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) error {
+			_, err := dynamicClusterClient.Cluster(clusterName).Resource(gvr).Namespace(obj.GetNamespace()).Create(ctx, obj, metav1.CreateOptions{})
+			return err
+		},
+		updateObject: func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) error {
+			_, err := dynamicClusterClient.Cluster(clusterName).Resource(gvr).Namespace(obj.GetNamespace()).Update(ctx, obj, metav1.UpdateOptions{})
+			return err
+		},
+		deleteObject: func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, namespace, name string) error {
+			return dynamicClusterClient.Cluster(clusterName).Resource(gvr).Namespace(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+		},
+	}
+
+	replicationInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueueReplication(obj) },
+		UpdateFunc: func(_, obj interface{}) { c.enqueueReplication(obj) },
+		DeleteFunc: func(obj interface{}) { c.enqueueReplication(obj) },
+	})
+
+	// child workspaces becoming ready or changing their labels change the targets.
+	workspaceInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueueWorkspace(obj) },
+		UpdateFunc: func(_, obj interface{}) { c.enqueueWorkspace(obj) },
+		DeleteFunc: func(obj interface{}) { c.enqueueWorkspace(obj) },
+	})
+
+	return c, nil
+}
+
+// controller reconciles Replications into replicas in their target workspaces.
+type controller struct {
+	queue        workqueue.RateLimitingInterface
+	enqueueAfter func(*tenancyv1alpha1.Replication, time.Duration)
+
+	kcpClusterClient kcpclient.ClusterInterface
+
+	replicationLister tenancylisters.ReplicationLister
+
+	createAuthorizer  func(clusterName logicalcluster.Name) (authorizer.Authorizer, error)
+	listWorkspaces    func(clusterName logicalcluster.Name, selector labels.Selector) ([]*tenancyv1alpha1.ClusterWorkspace, error)
+	updateReplication func(ctx context.Context, replication *tenancyv1alpha1.Replication) (*tenancyv1alpha1.Replication, error)
+	listObjects       func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, namespace string, selector labels.Selector) ([]unstructured.Unstructured, error)
+	createObject      func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) error
+	updateObject      func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) error
+	deleteObject      func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, namespace, name string) error
+}
+
+func (c *controller) enqueueReplication(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	klog.Infof("Queueing Replication %q", key)
+	c.queue.Add(key)
+}
+
+// enqueueWorkspace enqueues the Replications of the parent of a ClusterWorkspace.
+func (c *controller) enqueueWorkspace(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	ws, ok := obj.(*tenancyv1alpha1.ClusterWorkspace)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("unexpected object type %T", obj))
+		return
+	}
+
+	replications, err := c.replicationLister.List(labels.Everything())
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	clusterName := logicalcluster.From(ws)
+	for _, replication := range replications {
+		if logicalcluster.From(replication) != clusterName {
+			continue
+		}
+		key := clusters.ToClusterAwareKey(clusterName, replication.Name)
+		klog.V(4).Infof("Queueing Replication %q because of ClusterWorkspace %s|%s", key, clusterName, ws.Name)
+		c.queue.Add(key)
+	}
+}
+
+// Start starts the controller, which stops when ctx.Done() is closed.
+func (c *controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	klog.Infof("Starting %s controller", controllerName)
+	defer klog.Infof("Shutting down %s controller", controllerName)
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+
+	<-ctx.Done()
+}
+
+func (c *controller) startWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	if err := c.process(ctx, key); err != nil {
+		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", controllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *controller) process(ctx context.Context, key string) error {
+	_, clusterAwareName, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		klog.Errorf("invalid key: %q: %v", key, err)
+		return nil
+	}
+	clusterName, name := clusters.SplitClusterAwareKey(clusterAwareName)
+
+	obj, err := c.replicationLister.Get(key)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil // object deleted before we handled it
+		}
+		return err
+	}
+	old := obj
+	obj = obj.DeepCopy()
+
+	if err := c.reconcile(ctx, obj); err != nil {
+		return err
+	}
+	if !obj.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	// If the object being reconciled changed as a result, update it.
+	if !equality.Semantic.DeepEqual(old.Status, obj.Status) {
+		oldData, err := json.Marshal(tenancyv1alpha1.Replication{
+			Status: old.Status,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to Marshal old data for Replication %s|%s: %w", clusterName, name, err)
+		}
+
+		newData, err := json.Marshal(tenancyv1alpha1.Replication{
+			ObjectMeta: metav1.ObjectMeta{
+				UID:             obj.UID,
+				ResourceVersion: obj.ResourceVersion,
+			}, // to ensure they appear in the patch as preconditions
+			Status: obj.Status,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to Marshal new data for Replication %s|%s: %w", clusterName, name, err)
+		}
+
+		patchBytes, err := jsonpatch.CreateMergePatch(oldData, newData)
+		if err != nil {
+			return fmt.Errorf("failed to create patch for Replication %s|%s: %w", clusterName, name, err)
+		}
+		if _, err := c.kcpClusterClient.Cluster(clusterName).TenancyV1alpha1().Replications().Patch(ctx, obj.Name, types.MergePatchType, patchBytes, metav1.PatchOptions{}, "status"); err != nil {
+			return err
+		}
+	}
+
+	// source objects are not informed about, compare them with the replicas regularly.
+	c.enqueueAfter(obj, resyncPeriod)
+
+	return nil
+}
@@ -0,0 +1,321 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replication
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/client-go/tools/clusters"
+	"k8s.io/klog/v2"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/authorization/delegated"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/third_party/conditions/util/conditions"
+)
+
+// replicaSelector selects the replicas of all Replications.
+var replicaSelector = func() labels.Selector {
+	req, err := labels.NewRequirement(tenancyv1alpha1.ReplicaLabel, selection.Exists, nil)
+	if err != nil {
+		panic(err)
+	}
+	return labels.NewSelector().Add(*req)
+}()
+
+func (c *controller) reconcile(ctx context.Context, replication *tenancyv1alpha1.Replication) error {
+	clusterName := logicalcluster.From(replication)
+	gvr := schema.GroupVersionResource{
+		Group:    replication.Spec.Resource.Group,
+		Version:  replication.Spec.Resource.Version,
+		Resource: replication.Spec.Resource.Resource,
+	}
+
+	if !replication.DeletionTimestamp.IsZero() {
+		return c.reconcileDeletion(ctx, replication, gvr)
+	}
+	if !sets.NewString(replication.Finalizers...).Has(ReplicationFinalizer) {
+		replication.Finalizers = append(replication.Finalizers, ReplicationFinalizer)
+		updated, err := c.updateReplication(ctx, replication)
+		if err != nil {
+			return err
+		}
+		updated.Status = replication.Status
+		*replication = *updated
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&replication.Spec.Selector)
+	if err != nil {
+		conditions.MarkFalse(replication, tenancyv1alpha1.ReplicationReplicated, tenancyv1alpha1.ReplicationInvalidSelectorReason, conditionsv1alpha1.ConditionSeverityError, "Invalid selector: %v", err)
+		return nil
+	}
+	workspaceSelector := labels.Everything()
+	if replication.Spec.WorkspaceSelector != nil {
+		if workspaceSelector, err = metav1.LabelSelectorAsSelector(replication.Spec.WorkspaceSelector); err != nil {
+			conditions.MarkFalse(replication, tenancyv1alpha1.ReplicationReplicated, tenancyv1alpha1.ReplicationInvalidSelectorReason, conditionsv1alpha1.ConditionSeverityError, "Invalid workspace selector: %v", err)
+			return nil
+		}
+	}
+
+	sources, err := c.listObjects(ctx, clusterName, gvr, replication.Spec.Namespace, selector)
+	if err != nil {
+		conditions.MarkFalse(replication, tenancyv1alpha1.ReplicationReplicated, tenancyv1alpha1.ReplicationSourceFailedReason, conditionsv1alpha1.ConditionSeverityError, "Failed to list %s: %v", gvr, err)
+		return nil
+	}
+
+	workspaces, err := c.listWorkspaces(clusterName, workspaceSelector)
+	if err != nil {
+		return err
+	}
+	targets := sets.NewString()
+	for _, ws := range workspaces {
+		if ws.Status.Phase == tenancyv1alpha1.ClusterWorkspacePhaseReady {
+			targets.Insert(ws.Name)
+		}
+	}
+
+	formerTargets := sets.NewString(replication.Status.TargetWorkspaces...)
+	var failed, forbidden, conflicting []string
+	replicated := 0
+	for _, target := range targets.List() {
+		// kcp writes the replicas with its own privileges. Only replicate into workspaces where
+		// the creator could do that, checked when the workspace becomes a target.
+		if !formerTargets.Has(target) {
+			if err := c.checkTargetAccess(ctx, replication, gvr, clusterName.Join(target)); err != nil {
+				klog.V(2).Infof("Not replicating %s of Replication %s|%s into workspace %s: %v", gvr, clusterName, replication.Name, target, err)
+				forbidden = append(forbidden, target)
+				targets.Delete(target)
+				continue
+			}
+		}
+
+		conflicts, err := c.replicate(ctx, replication, gvr, clusterName.Join(target), sources)
+		switch {
+		case err != nil:
+			klog.Errorf("Failed to replicate %s of Replication %s|%s into workspace %s: %v", gvr, clusterName, replication.Name, target, err)
+			failed = append(failed, target)
+		case len(conflicts) > 0:
+			conflicting = append(conflicting, fmt.Sprintf("%s (%s)", target, strings.Join(conflicts, ", ")))
+		default:
+			replicated++
+		}
+	}
+
+	// remove the replicas from workspaces which are not targeted anymore. Keep the
+	// workspaces as targets until that succeeded.
+	for _, former := range replication.Status.TargetWorkspaces {
+		if targets.Has(former) {
+			continue
+		}
+		if _, err := c.replicate(ctx, replication, gvr, clusterName.Join(former), nil); err != nil {
+			klog.Errorf("Failed to remove replicas of Replication %s|%s from workspace %s: %v", clusterName, replication.Name, former, err)
+			targets.Insert(former)
+			failed = append(failed, former)
+		}
+	}
+
+	replication.Status.TargetWorkspaces = targets.List()
+	replication.Status.ReplicatedWorkspaces = int32(replicated)
+	replication.Status.ReplicatedObjects = int32(len(sources))
+
+	switch {
+	case len(failed) > 0:
+		conditions.MarkFalse(replication, tenancyv1alpha1.ReplicationReplicated, tenancyv1alpha1.ReplicationFailedReason, conditionsv1alpha1.ConditionSeverityWarning, "Failed to replicate into workspaces: %s", strings.Join(failed, ", "))
+	case len(forbidden) > 0:
+		conditions.MarkFalse(replication, tenancyv1alpha1.ReplicationReplicated, tenancyv1alpha1.ReplicationForbiddenReason, conditionsv1alpha1.ConditionSeverityWarning, "The creator of the Replication may not create, update and delete %s in workspaces: %s", gvr.GroupResource(), strings.Join(forbidden, ", "))
+	case len(conflicting) > 0:
+		conditions.MarkFalse(replication, tenancyv1alpha1.ReplicationReplicated, tenancyv1alpha1.ReplicationConflictReason, conditionsv1alpha1.ConditionSeverityWarning, "Objects exist that are no replicas: %s", strings.Join(conflicting, "; "))
+	default:
+		conditions.MarkTrue(replication, tenancyv1alpha1.ReplicationReplicated)
+	}
+
+	return nil
+}
+
+// checkTargetAccess checks that the creator of the Replication can create, update and delete
+// the replicated resource in the target workspace.
+func (c *controller) checkTargetAccess(ctx context.Context, replication *tenancyv1alpha1.Replication, gvr schema.GroupVersionResource, target logicalcluster.Name) error {
+	if gvr.Group == rbacv1.GroupName {
+		return fmt.Errorf("RBAC resources cannot be replicated")
+	}
+	creator, err := delegated.DecodeUser(replication.Annotations[tenancyv1alpha1.ReplicationCreatorAnnotation])
+	if err != nil {
+		return fmt.Errorf("invalid %s annotation: %w", tenancyv1alpha1.ReplicationCreatorAnnotation, err)
+	}
+	authz, err := c.createAuthorizer(target)
+	if err != nil {
+		return err
+	}
+
+	for _, verb := range []string{"create", "update", "delete"} {
+		attr := authorizer.AttributesRecord{
+			User:            creator,
+			Verb:            verb,
+			Namespace:       replication.Spec.Namespace,
+			APIGroup:        gvr.Group,
+			APIVersion:      gvr.Version,
+			Resource:        gvr.Resource,
+			ResourceRequest: true,
+		}
+		if decision, _, err := authz.Authorize(ctx, attr); err != nil {
+			return fmt.Errorf("unable to determine access to %s: %w", gvr.GroupResource(), err)
+		} else if decision != authorizer.DecisionAllow {
+			return fmt.Errorf("missing verb=%q permission on %s for user %q", verb, gvr.GroupResource(), creator.GetName())
+		}
+	}
+	return nil
+}
+
+// reconcileDeletion removes the replicas from all target workspaces, and then the finalizer.
+func (c *controller) reconcileDeletion(ctx context.Context, replication *tenancyv1alpha1.Replication, gvr schema.GroupVersionResource) error {
+	finalizers := sets.NewString(replication.Finalizers...)
+	if !finalizers.Has(ReplicationFinalizer) {
+		return nil
+	}
+
+	clusterName := logicalcluster.From(replication)
+	for _, target := range replication.Status.TargetWorkspaces {
+		if _, err := c.replicate(ctx, replication, gvr, clusterName.Join(target), nil); err != nil {
+			return err
+		}
+	}
+	klog.Infof("Removed replicas of deleted Replication %s|%s", clusterName, replication.Name)
+
+	replication.Finalizers = finalizers.Delete(ReplicationFinalizer).List()
+	_, err := c.updateReplication(ctx, replication)
+	return err
+}
+
+// replicate makes the replicas of the Replication in the target logical cluster match the
+// sources, and returns the names of objects which exist in the target but are no replicas.
+func (c *controller) replicate(ctx context.Context, replication *tenancyv1alpha1.Replication, gvr schema.GroupVersionResource, target logicalcluster.Name, sources []unstructured.Unstructured) ([]string, error) {
+	owner := clusters.ToClusterAwareKey(logicalcluster.From(replication), replication.Name)
+
+	existing, err := c.listObjects(ctx, target, gvr, replication.Spec.Namespace, replicaSelector)
+	if err != nil {
+		return nil, err
+	}
+	replicas := map[string]*unstructured.Unstructured{}
+	for i := range existing {
+		if existing[i].GetAnnotations()[tenancyv1alpha1.ReplicationAnnotation] != owner {
+			continue
+		}
+		replicas[objectKey(&existing[i])] = &existing[i]
+	}
+
+	var conflicts []string
+	for i := range sources {
+		desired := replicaOf(replication, &sources[i])
+		key := objectKey(desired)
+
+		replica, found := replicas[key]
+		delete(replicas, key)
+		if !found {
+			if err := c.createObject(ctx, target, gvr, desired); errors.IsAlreadyExists(err) {
+				conflicts = append(conflicts, key)
+			} else if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if equality.Semantic.DeepEqual(normalize(replica).Object, desired.Object) {
+			continue
+		}
+		desired.SetResourceVersion(replica.GetResourceVersion())
+		if err := c.updateObject(ctx, target, gvr, desired); err != nil {
+			return nil, err
+		}
+	}
+
+	// delete replicas whose source is gone or not selected anymore.
+	for key, replica := range replicas {
+		if err := c.deleteObject(ctx, target, gvr, replica.GetNamespace(), replica.GetName()); err != nil && !errors.IsNotFound(err) {
+			return nil, err
+		}
+		klog.V(2).Infof("Deleted replica %s of Replication %s in workspace %s", key, owner, target)
+	}
+
+	sort.Strings(conflicts)
+	return conflicts, nil
+}
+
+// normalize returns a copy of the object without the metadata fields maintained by the server, and without status.
+func normalize(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	ret := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	for k, v := range obj.Object {
+		if k == "metadata" || k == "status" {
+			continue
+		}
+		ret.Object[k] = runtime.DeepCopyJSONValue(v)
+	}
+	ret.SetName(obj.GetName())
+	if obj.GetNamespace() != "" {
+		ret.SetNamespace(obj.GetNamespace())
+	}
+	if len(obj.GetLabels()) > 0 {
+		ret.SetLabels(obj.GetLabels())
+	}
+	if len(obj.GetAnnotations()) > 0 {
+		ret.SetAnnotations(obj.GetAnnotations())
+	}
+	return ret
+}
+
+// replicaOf returns the replica of the source object for the Replication.
+func replicaOf(replication *tenancyv1alpha1.Replication, source *unstructured.Unstructured) *unstructured.Unstructured {
+	replica := normalize(source)
+
+	replicaLabels := replica.GetLabels()
+	if replicaLabels == nil {
+		replicaLabels = map[string]string{}
+	}
+	replicaLabels[tenancyv1alpha1.ReplicaLabel] = "true"
+	replica.SetLabels(replicaLabels)
+
+	annotations := replica.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[tenancyv1alpha1.ReplicationAnnotation] = clusters.ToClusterAwareKey(logicalcluster.From(replication), replication.Name)
+	replica.SetAnnotations(annotations)
+
+	return replica
+}
+
+func objectKey(obj *unstructured.Unstructured) string {
+	if obj.GetNamespace() == "" {
+		return obj.GetName()
+	}
+	return obj.GetNamespace() + "/" + obj.GetName()
+}
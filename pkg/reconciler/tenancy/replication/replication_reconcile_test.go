@@ -0,0 +1,398 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replication
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/third_party/conditions/util/conditions"
+)
+
+var configMapsGVR = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+
+func configMap(name string, objLabels map[string]string, data string, annotations map[string]string) unstructured.Unstructured {
+	obj := unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"data":       map[string]interface{}{"key": data},
+	}}
+	obj.SetNamespace("default")
+	obj.SetName(name)
+	obj.SetLabels(objLabels)
+	obj.SetAnnotations(annotations)
+	obj.SetResourceVersion("1")
+	return obj
+}
+
+func replicaAnnotations() map[string]string {
+	return map[string]string{tenancyv1alpha1.ReplicationAnnotation: "root:org|policies"}
+}
+
+func replicaLabels(objLabels map[string]string) map[string]string {
+	ret := map[string]string{tenancyv1alpha1.ReplicaLabel: "true"}
+	for k, v := range objLabels {
+		ret[k] = v
+	}
+	return ret
+}
+
+func workspace(name string, phase tenancyv1alpha1.ClusterWorkspacePhaseType, wsLabels map[string]string) *tenancyv1alpha1.ClusterWorkspace {
+	return &tenancyv1alpha1.ClusterWorkspace{
+		ObjectMeta: metav1.ObjectMeta{Name: name, ClusterName: "root:org", Labels: wsLabels},
+		Status:     tenancyv1alpha1.ClusterWorkspaceStatus{Phase: phase},
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	policy := map[string]string{"policy": "true"}
+
+	replication := func(mutate func(*tenancyv1alpha1.Replication)) *tenancyv1alpha1.Replication {
+		r := &tenancyv1alpha1.Replication{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "policies",
+				ClusterName: "root:org",
+				Finalizers:  []string{ReplicationFinalizer},
+				Annotations: map[string]string{tenancyv1alpha1.ReplicationCreatorAnnotation: `{"username":"alice"}`},
+			},
+			Spec: tenancyv1alpha1.ReplicationSpec{
+				Resource:  tenancyv1alpha1.ReplicationResource{Version: "v1", Resource: "configmaps"},
+				Namespace: "default",
+				Selector:  metav1.LabelSelector{MatchLabels: policy},
+			},
+		}
+		if mutate != nil {
+			mutate(r)
+		}
+		return r
+	}
+
+	tests := map[string]struct {
+		replication *tenancyv1alpha1.Replication
+		workspaces  []*tenancyv1alpha1.ClusterWorkspace
+		objects     map[logicalcluster.Name][]unstructured.Unstructured
+		forbidden   []string
+
+		wantObjects          map[logicalcluster.Name][]unstructured.Unstructured
+		wantTargets          []string
+		wantReplicated       int32
+		wantCondition        bool
+		wantReason           string
+		wantFinalizerRemoved bool
+	}{
+		"replicates selected objects into ready selected child workspaces": {
+			replication: replication(func(r *tenancyv1alpha1.Replication) {
+				r.Spec.WorkspaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}}
+			}),
+			workspaces: []*tenancyv1alpha1.ClusterWorkspace{
+				workspace("one", tenancyv1alpha1.ClusterWorkspacePhaseReady, map[string]string{"team": "a"}),
+				workspace("two", tenancyv1alpha1.ClusterWorkspacePhaseInitializing, map[string]string{"team": "a"}),
+				workspace("three", tenancyv1alpha1.ClusterWorkspacePhaseReady, map[string]string{"team": "b"}),
+			},
+			objects: map[logicalcluster.Name][]unstructured.Unstructured{
+				logicalcluster.New("root:org"): {
+					configMap("quota", policy, "10", nil),
+					configMap("other", nil, "x", nil),
+				},
+			},
+			wantObjects: map[logicalcluster.Name][]unstructured.Unstructured{
+				logicalcluster.New("root:org:one"): {
+					configMap("quota", replicaLabels(policy), "10", replicaAnnotations()),
+				},
+			},
+			wantTargets:    []string{"one"},
+			wantReplicated: 1,
+			wantCondition:  true,
+		},
+		"updates changed replicas and deletes replicas of removed sources": {
+			replication: replication(nil),
+			workspaces: []*tenancyv1alpha1.ClusterWorkspace{
+				workspace("one", tenancyv1alpha1.ClusterWorkspacePhaseReady, nil),
+			},
+			objects: map[logicalcluster.Name][]unstructured.Unstructured{
+				logicalcluster.New("root:org"): {
+					configMap("quota", policy, "20", nil),
+				},
+				logicalcluster.New("root:org:one"): {
+					configMap("quota", replicaLabels(policy), "10", replicaAnnotations()),
+					configMap("removed", replicaLabels(policy), "10", replicaAnnotations()),
+					configMap("foreign", replicaLabels(policy), "10", map[string]string{tenancyv1alpha1.ReplicationAnnotation: "root:other|policies"}),
+				},
+			},
+			wantObjects: map[logicalcluster.Name][]unstructured.Unstructured{
+				logicalcluster.New("root:org:one"): {
+					configMap("foreign", replicaLabels(policy), "10", map[string]string{tenancyv1alpha1.ReplicationAnnotation: "root:other|policies"}),
+					configMap("quota", replicaLabels(policy), "20", replicaAnnotations()),
+				},
+			},
+			wantTargets:    []string{"one"},
+			wantReplicated: 1,
+			wantCondition:  true,
+		},
+		"existing objects are not overwritten": {
+			replication: replication(nil),
+			workspaces: []*tenancyv1alpha1.ClusterWorkspace{
+				workspace("one", tenancyv1alpha1.ClusterWorkspacePhaseReady, nil),
+			},
+			objects: map[logicalcluster.Name][]unstructured.Unstructured{
+				logicalcluster.New("root:org"): {
+					configMap("quota", policy, "10", nil),
+				},
+				logicalcluster.New("root:org:one"): {
+					configMap("quota", nil, "mine", nil),
+				},
+			},
+			wantObjects: map[logicalcluster.Name][]unstructured.Unstructured{
+				logicalcluster.New("root:org:one"): {
+					configMap("quota", nil, "mine", nil),
+				},
+			},
+			wantTargets:    []string{"one"},
+			wantReplicated: 1,
+			wantReason:     tenancyv1alpha1.ReplicationConflictReason,
+			wantCondition:  false,
+		},
+		"replicas are removed from workspaces not targeted anymore": {
+			replication: replication(func(r *tenancyv1alpha1.Replication) {
+				r.Spec.WorkspaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}}
+				r.Status.TargetWorkspaces = []string{"one", "two"}
+			}),
+			workspaces: []*tenancyv1alpha1.ClusterWorkspace{
+				workspace("one", tenancyv1alpha1.ClusterWorkspacePhaseReady, map[string]string{"team": "a"}),
+				workspace("two", tenancyv1alpha1.ClusterWorkspacePhaseReady, map[string]string{"team": "b"}),
+			},
+			objects: map[logicalcluster.Name][]unstructured.Unstructured{
+				logicalcluster.New("root:org"): {
+					configMap("quota", policy, "10", nil),
+				},
+				logicalcluster.New("root:org:one"): {
+					configMap("quota", replicaLabels(policy), "10", replicaAnnotations()),
+				},
+				logicalcluster.New("root:org:two"): {
+					configMap("quota", replicaLabels(policy), "10", replicaAnnotations()),
+				},
+			},
+			wantObjects: map[logicalcluster.Name][]unstructured.Unstructured{
+				logicalcluster.New("root:org:one"): {
+					configMap("quota", replicaLabels(policy), "10", replicaAnnotations()),
+				},
+			},
+			wantTargets:    []string{"one"},
+			wantReplicated: 1,
+			wantCondition:  true,
+		},
+		"does not replicate into new targets where the creator may not write": {
+			replication: replication(func(r *tenancyv1alpha1.Replication) {
+				r.Status.TargetWorkspaces = []string{"one"}
+			}),
+			workspaces: []*tenancyv1alpha1.ClusterWorkspace{
+				workspace("one", tenancyv1alpha1.ClusterWorkspacePhaseReady, nil),
+				workspace("two", tenancyv1alpha1.ClusterWorkspacePhaseReady, nil),
+				workspace("three", tenancyv1alpha1.ClusterWorkspacePhaseReady, nil),
+			},
+			objects: map[logicalcluster.Name][]unstructured.Unstructured{
+				logicalcluster.New("root:org"): {
+					configMap("quota", policy, "10", nil),
+				},
+			},
+			forbidden: []string{"root:org:one", "root:org:two"},
+			wantObjects: map[logicalcluster.Name][]unstructured.Unstructured{
+				logicalcluster.New("root:org:one"): {
+					configMap("quota", replicaLabels(policy), "10", replicaAnnotations()),
+				},
+				logicalcluster.New("root:org:three"): {
+					configMap("quota", replicaLabels(policy), "10", replicaAnnotations()),
+				},
+			},
+			wantTargets:    []string{"one", "three"},
+			wantReplicated: 1,
+			wantReason:     tenancyv1alpha1.ReplicationForbiddenReason,
+			wantCondition:  false,
+		},
+		"does not replicate into new targets without a known creator": {
+			replication: replication(func(r *tenancyv1alpha1.Replication) {
+				r.Annotations = nil
+			}),
+			workspaces: []*tenancyv1alpha1.ClusterWorkspace{
+				workspace("one", tenancyv1alpha1.ClusterWorkspacePhaseReady, nil),
+			},
+			objects: map[logicalcluster.Name][]unstructured.Unstructured{
+				logicalcluster.New("root:org"): {
+					configMap("quota", policy, "10", nil),
+				},
+			},
+			wantObjects:    map[logicalcluster.Name][]unstructured.Unstructured{},
+			wantTargets:    []string{},
+			wantReplicated: 1,
+			wantReason:     tenancyv1alpha1.ReplicationForbiddenReason,
+			wantCondition:  false,
+		},
+		"invalid selector": {
+			replication: replication(func(r *tenancyv1alpha1.Replication) {
+				r.Spec.Selector = metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "policy", Operator: "Bogus"}}}
+			}),
+			wantReason:    tenancyv1alpha1.ReplicationInvalidSelectorReason,
+			wantCondition: false,
+		},
+		"deletion removes replicas and the finalizer": {
+			replication: replication(func(r *tenancyv1alpha1.Replication) {
+				now := metav1.Now()
+				r.DeletionTimestamp = &now
+				r.Status.TargetWorkspaces = []string{"one"}
+			}),
+			objects: map[logicalcluster.Name][]unstructured.Unstructured{
+				logicalcluster.New("root:org"): {
+					configMap("quota", policy, "10", nil),
+				},
+				logicalcluster.New("root:org:one"): {
+					configMap("quota", replicaLabels(policy), "10", replicaAnnotations()),
+				},
+			},
+			wantObjects:          map[logicalcluster.Name][]unstructured.Unstructured{},
+			wantTargets:          []string{"one"},
+			wantFinalizerRemoved: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			objects := map[logicalcluster.Name]map[string]*unstructured.Unstructured{}
+			for clusterName, objs := range tc.objects {
+				objects[clusterName] = map[string]*unstructured.Unstructured{}
+				for i := range objs {
+					objects[clusterName][objectKey(&objs[i])] = objs[i].DeepCopy()
+				}
+			}
+
+			var updated *tenancyv1alpha1.Replication
+			c := &controller{
+				createAuthorizer: func(clusterName logicalcluster.Name) (authorizer.Authorizer, error) {
+					return authorizer.AuthorizerFunc(func(ctx context.Context, attr authorizer.Attributes) (authorizer.Decision, string, error) {
+						require.Equal(t, "alice", attr.GetUser().GetName())
+						require.Equal(t, "default", attr.GetNamespace())
+						require.Equal(t, "configmaps", attr.GetResource())
+						for _, forbidden := range tc.forbidden {
+							if clusterName == logicalcluster.New(forbidden) {
+								return authorizer.DecisionNoOpinion, "", nil
+							}
+						}
+						return authorizer.DecisionAllow, "", nil
+					}), nil
+				},
+				listWorkspaces: func(clusterName logicalcluster.Name, selector labels.Selector) ([]*tenancyv1alpha1.ClusterWorkspace, error) {
+					var ret []*tenancyv1alpha1.ClusterWorkspace
+					for _, ws := range tc.workspaces {
+						if logicalcluster.From(ws) == clusterName && selector.Matches(labels.Set(ws.Labels)) {
+							ret = append(ret, ws)
+						}
+					}
+					return ret, nil
+				},
+				updateReplication: func(ctx context.Context, replication *tenancyv1alpha1.Replication) (*tenancyv1alpha1.Replication, error) {
+					updated = replication.DeepCopy()
+					return replication.DeepCopy(), nil
+				},
+				listObjects: func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, namespace string, selector labels.Selector) ([]unstructured.Unstructured, error) {
+					require.Equal(t, configMapsGVR, gvr)
+					var ret []unstructured.Unstructured
+					for _, obj := range objects[clusterName] {
+						if obj.GetNamespace() == namespace && selector.Matches(labels.Set(obj.GetLabels())) {
+							ret = append(ret, *obj.DeepCopy())
+						}
+					}
+					return ret, nil
+				},
+				createObject: func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) error {
+					if _, found := objects[clusterName][objectKey(obj)]; found {
+						return errors.NewAlreadyExists(gvr.GroupResource(), obj.GetName())
+					}
+					if objects[clusterName] == nil {
+						objects[clusterName] = map[string]*unstructured.Unstructured{}
+					}
+					obj = obj.DeepCopy()
+					obj.SetResourceVersion("1")
+					objects[clusterName][objectKey(obj)] = obj
+					return nil
+				},
+				updateObject: func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) error {
+					existing, found := objects[clusterName][objectKey(obj)]
+					if !found {
+						return errors.NewNotFound(gvr.GroupResource(), obj.GetName())
+					}
+					require.Equal(t, existing.GetResourceVersion(), obj.GetResourceVersion())
+					objects[clusterName][objectKey(obj)] = obj.DeepCopy()
+					return nil
+				},
+				deleteObject: func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, namespace, name string) error {
+					delete(objects[clusterName], namespace+"/"+name)
+					return nil
+				},
+			}
+
+			replication := tc.replication.DeepCopy()
+			err := c.reconcile(context.Background(), replication)
+			require.NoError(t, err)
+
+			if tc.wantObjects != nil {
+				for clusterName, objs := range objects {
+					if clusterName == logicalcluster.New("root:org") {
+						continue
+					}
+					var got []unstructured.Unstructured
+					for _, key := range sortedKeys(objs) {
+						got = append(got, *objs[key])
+					}
+					require.Equal(t, tc.wantObjects[clusterName], got, "objects in %s", clusterName)
+				}
+			}
+
+			require.Equal(t, tc.wantTargets, replication.Status.TargetWorkspaces)
+			require.Equal(t, tc.wantReplicated, replication.Status.ReplicatedObjects)
+
+			if tc.wantFinalizerRemoved {
+				require.NotNil(t, updated)
+				require.NotContains(t, updated.Finalizers, ReplicationFinalizer)
+				return
+			}
+			require.Equal(t, tc.wantCondition, conditions.IsTrue(replication, tenancyv1alpha1.ReplicationReplicated))
+			if tc.wantReason != "" {
+				require.Equal(t, tc.wantReason, conditions.GetReason(replication, tenancyv1alpha1.ReplicationReplicated))
+			}
+		})
+	}
+}
+
+func sortedKeys(objs map[string]*unstructured.Unstructured) []string {
+	keys := make([]string, 0, len(objs))
+	for key := range objs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shardversion
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsv1client "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/typed/apiextensions/v1"
+	apiextensionsinformers "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions/apiextensions/v1"
+	apiextensionslisters "k8s.io/apiextensions-apiserver/pkg/client/listers/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	kcpclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
+	tenancyinformer "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/tenancy/v1alpha1"
+	tenancylister "github.com/kcp-dev/kcp/pkg/client/listers/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/reconciler/latency"
+)
+
+const (
+	controllerName = "kcp-shard-version"
+
+	// resyncPeriod makes sure that a restarted shard, whose system CRDs were reset to their
+	// preferred storage versions, is reconciled even if no shard changed.
+	resyncPeriod = 5 * time.Minute
+)
+
+// NewController returns a controller publishing the version info of the given shard, and
+// switching the storage versions of the system CRDs of the shard to the versions all shards
+// can read.
+func NewController(
+	shardName string,
+	version string,
+	features []string,
+	systemCRDCluster logicalcluster.Name,
+	rootKcpClient kcpclient.Interface,
+	systemCRDClient apiextensionsv1client.CustomResourceDefinitionInterface,
+	rootWorkspaceShardInformer tenancyinformer.ClusterWorkspaceShardInformer,
+	crdInformer apiextensionsinformers.CustomResourceDefinitionInformer,
+	preferredStorageVersion func(gr metav1.GroupResource) (string, error),
+) (*Controller, error) {
+	queue := latency.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+
+	c := &Controller{
+		queue:                   queue,
+		shardName:               shardName,
+		version:                 version,
+		features:                features,
+		systemCRDCluster:        systemCRDCluster,
+		kcpClient:               rootKcpClient,
+		crdClient:               systemCRDClient,
+		shardLister:             rootWorkspaceShardInformer.Lister(),
+		crdLister:               crdInformer.Lister(),
+		preferredStorageVersion: preferredStorageVersion,
+	}
+
+	// every shard and every system CRD can change the negotiation, which is about this
+	// shard only, i.e. there is only a single key.
+	rootWorkspaceShardInformer.Informer().AddEventHandlerWithResyncPeriod(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueue() },
+		UpdateFunc: func(_, obj interface{}) { c.enqueue() },
+		DeleteFunc: func(obj interface{}) { c.enqueue() },
+	}, resyncPeriod)
+	crdInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj interface{}) bool {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			crd, ok := obj.(*apiextensionsv1.CustomResourceDefinition)
+			return ok && logicalcluster.From(crd) == systemCRDCluster
+		},
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { c.enqueue() },
+			UpdateFunc: func(_, obj interface{}) { c.enqueue() },
+			DeleteFunc: func(obj interface{}) { c.enqueue() },
+		},
+	})
+
+	return c, nil
+}
+
+// Controller keeps the version info in the ClusterWorkspaceShard of its shard up to date, and
+// the storage versions of the system CRDs of its shard negotiated with the other shards.
+type Controller struct {
+	queue workqueue.RateLimitingInterface
+
+	shardName        string
+	version          string
+	features         []string
+	systemCRDCluster logicalcluster.Name
+
+	kcpClient kcpclient.Interface
+	crdClient apiextensionsv1client.CustomResourceDefinitionInterface
+
+	shardLister tenancylister.ClusterWorkspaceShardLister
+	crdLister   apiextensionslisters.CustomResourceDefinitionLister
+
+	preferredStorageVersion func(gr metav1.GroupResource) (string, error)
+}
+
+func (c *Controller) enqueue() {
+	c.queue.Add(c.shardName)
+}
+
+func (c *Controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	klog.Infof("Starting %s controller", controllerName)
+	defer klog.Infof("Shutting down %s controller", controllerName)
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+
+	<-ctx.Done()
+}
+
+func (c *Controller) startWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	if err := c.process(ctx); err != nil {
+		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", controllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *Controller) process(ctx context.Context) error {
+	shards, err := c.shardLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	crds, err := c.crdLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	var systemCRDs []*apiextensionsv1.CustomResourceDefinition
+	for _, crd := range crds {
+		if logicalcluster.From(crd) == c.systemCRDCluster {
+			systemCRDs = append(systemCRDs, crd)
+		}
+	}
+
+	return c.reconcile(ctx, shards, systemCRDs)
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shardversion
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/shardversion"
+)
+
+func (c *Controller) reconcile(ctx context.Context, shards []*tenancyv1alpha1.ClusterWorkspaceShard, systemCRDs []*apiextensionsv1.CustomResourceDefinition) error {
+	var own *tenancyv1alpha1.ClusterWorkspaceShard
+	for _, shard := range shards {
+		if shard.Name == c.shardName {
+			own = shard
+		}
+	}
+	if own == nil {
+		// created by the bootstrapping of the root workspace
+		klog.V(4).Infof("ClusterWorkspaceShard %q not found", c.shardName)
+		return nil
+	}
+
+	info := c.versionInfo(systemCRDs)
+
+	// negotiate with what is about to be published for this shard
+	negotiated := make([]*tenancyv1alpha1.ClusterWorkspaceShard, 0, len(shards))
+	for _, shard := range shards {
+		if shard.Name == c.shardName {
+			shard = shard.DeepCopy()
+			shard.Status.VersionInfo = info
+		}
+		negotiated = append(negotiated, shard)
+	}
+	n := shardversion.Negotiate(negotiated)
+
+	var errs []error
+	updated := false
+	for _, crd := range systemCRDs {
+		gr := metav1.GroupResource{Group: crd.Spec.Group, Resource: crd.Spec.Names.Plural}
+		preferred, err := c.preferredStorageVersion(gr)
+		if err != nil {
+			klog.V(4).Infof("Not negotiating the storage version of %s: %v", gr.String(), err)
+			continue
+		}
+		crd, changed := withStorageVersion(crd, n.StorageVersion(schema.GroupResource(gr), preferred))
+		if !changed {
+			continue
+		}
+
+		klog.Infof("Storing %s in version %s, which is readable by all shards running %v", gr.String(), storageVersion(crd), n.Versions())
+		if _, err := c.crdClient.Update(ctx, crd, metav1.UpdateOptions{}); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		updated = true
+	}
+	if len(errs) > 0 {
+		return utilerrors.NewAggregate(errs)
+	}
+	if updated {
+		// the version info is published when the updated CRDs are seen
+		return nil
+	}
+
+	if equality.Semantic.DeepEqual(own.Status.VersionInfo, info) {
+		return nil
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{
+			"versionInfo": info,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = c.kcpClient.TenancyV1alpha1().ClusterWorkspaceShards().Patch(ctx, c.shardName, types.MergePatchType, patch, metav1.PatchOptions{}, "status")
+	return err
+}
+
+// versionInfo returns the version info of this shard with the given system CRDs.
+func (c *Controller) versionInfo(systemCRDs []*apiextensionsv1.CustomResourceDefinition) *tenancyv1alpha1.ShardVersionInfo {
+	info := &tenancyv1alpha1.ShardVersionInfo{
+		Version:  c.version,
+		Features: c.features,
+	}
+	for _, crd := range systemCRDs {
+		r := tenancyv1alpha1.ShardResource{
+			Group:          crd.Spec.Group,
+			Resource:       crd.Spec.Names.Plural,
+			StorageVersion: storageVersion(crd),
+		}
+		for _, v := range crd.Spec.Versions {
+			if v.Served {
+				r.Versions = append(r.Versions, v.Name)
+			}
+		}
+		info.Resources = append(info.Resources, r)
+	}
+	sort.Slice(info.Resources, func(i, j int) bool {
+		if info.Resources[i].Group != info.Resources[j].Group {
+			return info.Resources[i].Group < info.Resources[j].Group
+		}
+		return info.Resources[i].Resource < info.Resources[j].Resource
+	})
+	return info
+}
+
+// withStorageVersion returns a copy of the CRD storing objects in the given version, and
+// whether that is different from the CRD. Unknown versions are ignored.
+func withStorageVersion(crd *apiextensionsv1.CustomResourceDefinition, version string) (*apiextensionsv1.CustomResourceDefinition, bool) {
+	if version == "" || version == storageVersion(crd) {
+		return crd, false
+	}
+	found := false
+	for _, v := range crd.Spec.Versions {
+		found = found || v.Name == version
+	}
+	if !found {
+		return crd, false
+	}
+
+	crd = crd.DeepCopy()
+	for i := range crd.Spec.Versions {
+		crd.Spec.Versions[i].Storage = crd.Spec.Versions[i].Name == version
+	}
+	return crd, true
+}
+
+func storageVersion(crd *apiextensionsv1.CustomResourceDefinition) string {
+	for _, v := range crd.Spec.Versions {
+		if v.Storage {
+			return v.Name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shardversion
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	kcpfake "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/fake"
+)
+
+func widgetsCRD(storage string, versions ...string) *apiextensionsv1.CustomResourceDefinition {
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "widgets.tenancy.kcp.dev"},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: "tenancy.kcp.dev",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets"},
+		},
+	}
+	for _, v := range versions {
+		crd.Spec.Versions = append(crd.Spec.Versions, apiextensionsv1.CustomResourceDefinitionVersion{Name: v, Served: true, Storage: v == storage})
+	}
+	return crd
+}
+
+func shardWith(name string, info *tenancyv1alpha1.ShardVersionInfo) *tenancyv1alpha1.ClusterWorkspaceShard {
+	return &tenancyv1alpha1.ClusterWorkspaceShard{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status:     tenancyv1alpha1.ClusterWorkspaceShardStatus{VersionInfo: info},
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	oldInfo := &tenancyv1alpha1.ShardVersionInfo{
+		Version:   "v0.5.0",
+		Resources: []tenancyv1alpha1.ShardResource{{Group: "tenancy.kcp.dev", Resource: "widgets", Versions: []string{"v1alpha1"}, StorageVersion: "v1alpha1"}},
+	}
+	newInfo := func(storage string) *tenancyv1alpha1.ShardVersionInfo {
+		return &tenancyv1alpha1.ShardVersionInfo{
+			Version:   "v0.6.0",
+			Features:  []string{"KCPFoo"},
+			Resources: []tenancyv1alpha1.ShardResource{{Group: "tenancy.kcp.dev", Resource: "widgets", Versions: []string{"v1alpha1", "v1beta1"}, StorageVersion: storage}},
+		}
+	}
+
+	tests := map[string]struct {
+		shards []*tenancyv1alpha1.ClusterWorkspaceShard
+		crd    *apiextensionsv1.CustomResourceDefinition
+
+		wantStorageVersion string
+		wantVersionInfo    *tenancyv1alpha1.ShardVersionInfo
+	}{
+		"the new version is not stored while another shard cannot read it": {
+			shards:             []*tenancyv1alpha1.ClusterWorkspaceShard{shardWith("own", nil), shardWith("other", oldInfo)},
+			crd:                widgetsCRD("v1beta1", "v1alpha1", "v1beta1"),
+			wantStorageVersion: "v1alpha1",
+		},
+		"the version info is published": {
+			shards:             []*tenancyv1alpha1.ClusterWorkspaceShard{shardWith("own", nil), shardWith("other", oldInfo)},
+			crd:                widgetsCRD("v1alpha1", "v1alpha1", "v1beta1"),
+			wantStorageVersion: "v1alpha1",
+			wantVersionInfo:    newInfo("v1alpha1"),
+		},
+		"the new version is stored when all shards can read it": {
+			shards:             []*tenancyv1alpha1.ClusterWorkspaceShard{shardWith("own", newInfo("v1alpha1")), shardWith("other", newInfo("v1alpha1"))},
+			crd:                widgetsCRD("v1alpha1", "v1alpha1", "v1beta1"),
+			wantStorageVersion: "v1beta1",
+			wantVersionInfo:    newInfo("v1alpha1"),
+		},
+		"nothing is done without the own shard": {
+			shards:             []*tenancyv1alpha1.ClusterWorkspaceShard{shardWith("other", oldInfo)},
+			crd:                widgetsCRD("v1beta1", "v1alpha1", "v1beta1"),
+			wantStorageVersion: "v1beta1",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var objects []runtime.Object
+			for _, shard := range tt.shards {
+				objects = append(objects, shard)
+			}
+			kcpClient := kcpfake.NewSimpleClientset(objects...)
+			crdClient := apiextensionsfake.NewSimpleClientset(tt.crd)
+
+			c := &Controller{
+				shardName: "own",
+				version:   "v0.6.0",
+				features:  []string{"KCPFoo"},
+				kcpClient: kcpClient,
+				crdClient: crdClient.ApiextensionsV1().CustomResourceDefinitions(),
+				preferredStorageVersion: func(gr metav1.GroupResource) (string, error) {
+					return "v1beta1", nil
+				},
+			}
+			err := c.reconcile(context.Background(), tt.shards, []*apiextensionsv1.CustomResourceDefinition{tt.crd})
+			require.NoError(t, err)
+
+			crd, err := crdClient.ApiextensionsV1().CustomResourceDefinitions().Get(context.Background(), tt.crd.Name, metav1.GetOptions{})
+			require.NoError(t, err)
+			require.Equal(t, tt.wantStorageVersion, storageVersion(crd))
+
+			if own, err := kcpClient.TenancyV1alpha1().ClusterWorkspaceShards().Get(context.Background(), "own", metav1.GetOptions{}); err == nil {
+				want := tt.wantVersionInfo
+				if want == nil {
+					want = tt.shards[0].Status.VersionInfo // unchanged
+				}
+				require.Equal(t, want, own.Status.VersionInfo)
+			}
+		})
+	}
+}
+
+func TestWithStorageVersion(t *testing.T) {
+	crd := widgetsCRD("v1alpha1", "v1alpha1", "v1beta1")
+
+	_, changed := withStorageVersion(crd, "")
+	require.False(t, changed, "unknown storage version")
+	_, changed = withStorageVersion(crd, "v1alpha1")
+	require.False(t, changed, "same storage version")
+	_, changed = withStorageVersion(crd, "v1")
+	require.False(t, changed, "version not in the CRD")
+
+	updated, changed := withStorageVersion(crd, "v1beta1")
+	require.True(t, changed)
+	require.Equal(t, "v1beta1", storageVersion(updated))
+	require.Equal(t, "v1alpha1", storageVersion(crd), "the original CRD must not be modified")
+}
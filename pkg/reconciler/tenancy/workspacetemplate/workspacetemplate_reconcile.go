@@ -0,0 +1,138 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacetemplate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/workspacetemplate"
+)
+
+func (c *controller) reconcile(ctx context.Context, workspace *tenancyv1alpha1.ClusterWorkspace) error {
+	if workspace.Status.Phase != tenancyv1alpha1.ClusterWorkspacePhaseInitializing {
+		return nil
+	}
+
+	// have we done our work before?
+	found := false
+	for _, i := range workspace.Status.Initializers {
+		if i == tenancyv1alpha1.TemplateInitializer {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil
+	}
+
+	var template *tenancyv1alpha1.ClusterWorkspaceTemplate
+	cwt, err := c.getClusterWorkspaceType(logicalcluster.From(workspace), strings.ToLower(workspace.Spec.Type))
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if err == nil {
+		template = cwt.Spec.Template
+	}
+
+	if template != nil {
+		if err := c.createObjects(ctx, workspace, template); err != nil {
+			return err // requeue
+		}
+	}
+
+	// we are done. remove our initializer
+	newInitializers := make([]tenancyv1alpha1.ClusterWorkspaceInitializer, 0, len(workspace.Status.Initializers))
+	for _, i := range workspace.Status.Initializers {
+		if i != tenancyv1alpha1.TemplateInitializer {
+			newInitializers = append(newInitializers, i)
+		}
+	}
+	workspace.Status.Initializers = newInitializers
+
+	return nil
+}
+
+// createObjects renders the template for the given workspace and creates the objects.
+func (c *controller) createObjects(ctx context.Context, workspace *tenancyv1alpha1.ClusterWorkspace, template *tenancyv1alpha1.ClusterWorkspaceTemplate) error {
+	// the parameters were validated and defaulted at admission. Check them again
+	// as the schema might have changed since.
+	params, errs := workspacetemplate.Parameters(template, workspace.Spec.TemplateParameters, field.NewPath("spec", "templateParameters"))
+	if len(errs) > 0 {
+		return errs.ToAggregate()
+	}
+
+	wsClusterName := logicalcluster.From(workspace).Join(workspace.Name)
+	objs, err := workspacetemplate.Render(template, workspacetemplate.Workspace{
+		Name: workspace.Name,
+		Path: wsClusterName.String(),
+		Type: workspace.Spec.Type,
+	}, params)
+	if err != nil {
+		return err
+	}
+	if len(objs) == 0 {
+		return nil
+	}
+
+	mapper := c.newRESTMapper(wsClusterName)
+	for _, obj := range objs {
+		if err := c.ensureObject(ctx, wsClusterName, mapper, obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ensureObject creates the given object. Existing objects are left untouched, the
+// template only seeds the workspace.
+func (c *controller) ensureObject(ctx context.Context, clusterName logicalcluster.Name, mapper meta.RESTMapper, obj *unstructured.Unstructured) error {
+	gvk := obj.GroupVersionKind()
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		// the resource might be created by an earlier object, e.g. a CRD. We will retry.
+		return fmt.Errorf("could not get REST mapping for %s in logical cluster %s: %w", gvk, clusterName, err)
+	}
+
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		if obj.GetNamespace() == "" {
+			obj.SetNamespace("default")
+		}
+	} else {
+		obj.SetNamespace("")
+	}
+
+	err = c.createObject(ctx, clusterName, mapping.Resource, obj)
+	if errors.IsAlreadyExists(err) {
+		klog.V(4).Infof("Skipping existing %s %s|%s/%s of workspace template", gvk.Kind, clusterName, obj.GetNamespace(), obj.GetName())
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to create %s %s|%s/%s: %w", gvk.Kind, clusterName, obj.GetNamespace(), obj.GetName(), err)
+	}
+	klog.Infof("Created %s %s|%s/%s of workspace template", gvk.Kind, clusterName, obj.GetNamespace(), obj.GetName())
+	return nil
+}
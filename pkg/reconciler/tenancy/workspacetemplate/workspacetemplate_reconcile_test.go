@@ -0,0 +1,154 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacetemplate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+func TestReconcile(t *testing.T) {
+	template := &tenancyv1alpha1.ClusterWorkspaceTemplate{
+		ParameterSchema: &runtime.RawExtension{Raw: []byte(`{"type":"object","properties":{"env":{"type":"string","default":"dev"}}}`)},
+		Manifests: []tenancyv1alpha1.ClusterWorkspaceTemplateManifest{
+			{
+				Name:     "namespace",
+				Template: "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: {{ .Parameters.env }}\n  namespace: ignored\n",
+			},
+			{
+				Name:     "configmap",
+				When:     `{{ eq .Parameters.env "prod" }}`,
+				Template: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: workspace\ndata:\n  path: {{ .Workspace.Path }}\n",
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		phase            tenancyv1alpha1.ClusterWorkspacePhaseType
+		initializers     []tenancyv1alpha1.ClusterWorkspaceInitializer
+		wsType           string
+		params           string
+		existing         []string
+		wantCreated      []string
+		wantInitializers []tenancyv1alpha1.ClusterWorkspaceInitializer
+	}{
+		"objects are created and the initializer removed": {
+			phase:            tenancyv1alpha1.ClusterWorkspacePhaseInitializing,
+			initializers:     []tenancyv1alpha1.ClusterWorkspaceInitializer{"other", tenancyv1alpha1.TemplateInitializer},
+			wantCreated:      []string{"namespaces /dev"},
+			wantInitializers: []tenancyv1alpha1.ClusterWorkspaceInitializer{"other"},
+		},
+		"conditional objects are created": {
+			phase:            tenancyv1alpha1.ClusterWorkspacePhaseInitializing,
+			initializers:     []tenancyv1alpha1.ClusterWorkspaceInitializer{tenancyv1alpha1.TemplateInitializer},
+			params:           `{"env":"prod"}`,
+			wantCreated:      []string{"namespaces /prod", "configmaps default/workspace"},
+			wantInitializers: []tenancyv1alpha1.ClusterWorkspaceInitializer{},
+		},
+		"existing objects are left alone": {
+			phase:            tenancyv1alpha1.ClusterWorkspacePhaseInitializing,
+			initializers:     []tenancyv1alpha1.ClusterWorkspaceInitializer{tenancyv1alpha1.TemplateInitializer},
+			params:           `{"env":"prod"}`,
+			existing:         []string{"namespaces /prod"},
+			wantCreated:      []string{"configmaps default/workspace"},
+			wantInitializers: []tenancyv1alpha1.ClusterWorkspaceInitializer{},
+		},
+		"initializer of a deleted type is removed": {
+			phase:            tenancyv1alpha1.ClusterWorkspacePhaseInitializing,
+			initializers:     []tenancyv1alpha1.ClusterWorkspaceInitializer{tenancyv1alpha1.TemplateInitializer},
+			wsType:           "Deleted",
+			params:           `{"env":"prod"}`,
+			wantInitializers: []tenancyv1alpha1.ClusterWorkspaceInitializer{},
+		},
+		"workspace without the initializer is ignored": {
+			phase:            tenancyv1alpha1.ClusterWorkspacePhaseInitializing,
+			initializers:     []tenancyv1alpha1.ClusterWorkspaceInitializer{"other"},
+			wantInitializers: []tenancyv1alpha1.ClusterWorkspaceInitializer{"other"},
+		},
+		"ready workspace is ignored": {
+			phase:            tenancyv1alpha1.ClusterWorkspacePhaseReady,
+			wantInitializers: nil,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if tc.wsType == "" {
+				tc.wsType = "Team"
+			}
+			existing := map[string]bool{}
+			for _, key := range tc.existing {
+				existing[key] = true
+			}
+			var created []string
+			c := &controller{
+				getClusterWorkspaceType: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspaceType, error) {
+					require.Equal(t, "root:org", clusterName.String())
+					if name != "team" {
+						return nil, errors.NewNotFound(tenancyv1alpha1.Resource("clusterworkspacetypes"), name)
+					}
+					return &tenancyv1alpha1.ClusterWorkspaceType{
+						ObjectMeta: metav1.ObjectMeta{Name: name, ClusterName: clusterName.String()},
+						Spec:       tenancyv1alpha1.ClusterWorkspaceTypeSpec{Template: template},
+					}, nil
+				},
+				newRESTMapper: func(clusterName logicalcluster.Name) meta.RESTMapper {
+					require.Equal(t, "root:org:ws", clusterName.String())
+					mapper := meta.NewDefaultRESTMapper(nil)
+					mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, meta.RESTScopeRoot)
+					mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+					return mapper
+				},
+				createObject: func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) error {
+					require.Equal(t, "root:org:ws", clusterName.String())
+					key := gvr.Resource + " " + obj.GetNamespace() + "/" + obj.GetName()
+					if existing[key] {
+						return errors.NewAlreadyExists(gvr.GroupResource(), obj.GetName())
+					}
+					created = append(created, key)
+					return nil
+				},
+			}
+
+			ws := &tenancyv1alpha1.ClusterWorkspace{
+				ObjectMeta: metav1.ObjectMeta{Name: "ws", ClusterName: "root:org"},
+				Spec:       tenancyv1alpha1.ClusterWorkspaceSpec{Type: tc.wsType},
+				Status: tenancyv1alpha1.ClusterWorkspaceStatus{
+					Phase:        tc.phase,
+					Initializers: tc.initializers,
+				},
+			}
+			if tc.params != "" {
+				ws.Spec.TemplateParameters = &runtime.RawExtension{Raw: []byte(tc.params)}
+			}
+			require.NoError(t, c.reconcile(context.Background(), ws))
+			require.Equal(t, tc.wantCreated, created)
+			require.Equal(t, tc.wantInitializers, ws.Status.Initializers)
+		})
+	}
+}
@@ -0,0 +1,401 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/kcp-dev/logicalcluster"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clusters"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/certificates"
+	kcpclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
+	workloadinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/workload/v1alpha1"
+	workloadlisters "github.com/kcp-dev/kcp/pkg/client/listers/workload/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/dns"
+	"github.com/kcp-dev/kcp/pkg/reconciler/latency"
+	"github.com/kcp-dev/kcp/pkg/reconciler/workload/dnsrecord"
+)
+
+const (
+	controllerName = "kcp-certificate"
+)
+
+// NewController returns a new controller that issues the certificates of Certificates through
+// the issuer, into secrets in their namespaces, and renews them before they expire. The issuer
+// is nil if kcp is not configured to issue certificates.
+func NewController(
+	kubeClusterClient kubernetes.ClusterInterface,
+	kcpClusterClient kcpclient.ClusterInterface,
+	certificateInformer workloadinformers.CertificateInformer,
+	dnsRecordInformer workloadinformers.DNSRecordInformer,
+	dnsZoneInformer workloadinformers.DNSZoneInformer,
+	secretInformer coreinformers.SecretInformer,
+	providers dns.Providers,
+	issuer certificates.Issuer,
+) (*controller, error) {
+	queue := latency.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+
+	if _, found := certificateInformer.Informer().GetIndexer().GetIndexers()[IndexCertificatesByDNSName]; !found {
+		if err := certificateInformer.Informer().AddIndexers(cache.Indexers{
+			IndexCertificatesByDNSName: IndexCertificatesByDNSNameFunc,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	if _, found := dnsRecordInformer.Informer().GetIndexer().GetIndexers()[dnsrecord.IndexDNSRecordsByName]; !found {
+		if err := dnsRecordInformer.Informer().AddIndexers(cache.Indexers{
+			dnsrecord.IndexDNSRecordsByName: dnsrecord.IndexDNSRecordsByNameFunc,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	recordIndexer := dnsRecordInformer.Informer().GetIndexer()
+	zoneLister := dnsZoneInformer.Lister()
+	secretLister := secretInformer.Lister()
+	c := &controller{
+		queue:              queue,
+		kcpClusterClient:   kcpClusterClient,
+		certificateLister:  certificateInformer.Lister(),
+		certificateIndexer: certificateInformer.Informer().GetIndexer(),
+		providers:          providers,
+		issuer:             issuer,
+		now:                time.Now,
+		listZones: func() ([]*workloadv1alpha1.DNSZone, error) {
+			return zoneLister.List(labels.Everything())
+		},
+		listRecordsByName: func(name string) ([]*workloadv1alpha1.DNSRecord, error) {
+			objs, err := recordIndexer.ByIndex(dnsrecord.IndexDNSRecordsByName, name)
+			if err != nil {
+				return nil, err
+			}
+			records := make([]*workloadv1alpha1.DNSRecord, 0, len(objs))
+			for _, obj := range objs {
+				records = append(records, obj.(*workloadv1alpha1.DNSRecord))
+			}
+			return records, nil
+		},
+		getSecret: func(clusterName logicalcluster.Name, namespace, name string) (*corev1.Secret, error) {
+			return secretLister.Secrets(namespace).Get(clusters.ToClusterAwareKey(clusterName, name))
+		},
+		listSecrets: func(clusterName logicalcluster.Name, namespace, certificateName string) ([]*corev1.Secret, error) {
+			secrets, err := secretLister.Secrets(namespace).List(labels.SelectorFromSet(labels.Set{workloadv1alpha1.CertificateLabel: certificateName}))
+			if err != nil {
+				return nil, err
+			}
+			var ret []*corev1.Secret
+			for _, secret := range secrets {
+				if logicalcluster.From(secret) == clusterName {
+					ret = append(ret, secret)
+				}
+			}
+			return ret, nil
+		},
+		createSecret: func(ctx context.Context, clusterName logicalcluster.Name, secret *corev1.Secret) error {
+			_, err := kubeClusterClient.Cluster(clusterName).CoreV1().Secrets(secret.Namespace).Create(ctx, secret, metav1.CreateOptions{})
+			return err
+		},
+		updateSecret: func(ctx context.Context, clusterName logicalcluster.Name, secret *corev1.Secret) error {
+			_, err := kubeClusterClient.Cluster(clusterName).CoreV1().Secrets(secret.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
+			return err
+		},
+		deleteSecret: func(ctx context.Context, clusterName logicalcluster.Name, namespace, name string) error {
+			return kubeClusterClient.Cluster(clusterName).CoreV1().Secrets(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+		},
+		enqueueAfter: func(certificate *workloadv1alpha1.Certificate, duration time.Duration) {
+			key, err := cache.MetaNamespaceKeyFunc(certificate)
+			if err != nil {
+				runtime.HandleError(err)
+				return
+			}
+			queue.AddAfter(key, duration)
+		},
+		syncChecks: []cache.InformerSynced{
+			certificateInformer.Informer().HasSynced,
+			dnsRecordInformer.Informer().HasSynced,
+			dnsZoneInformer.Informer().HasSynced,
+			secretInformer.Informer().HasSynced,
+		},
+	}
+
+	certificateInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueueCertificate(obj) },
+		UpdateFunc: func(_, obj interface{}) { c.enqueueCertificate(obj) },
+		DeleteFunc: func(obj interface{}) { c.enqueueCertificate(obj) },
+	})
+
+	dnsRecordInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueueDNSRecord(obj) },
+		UpdateFunc: func(_, obj interface{}) { c.enqueueDNSRecord(obj) },
+		DeleteFunc: func(obj interface{}) { c.enqueueDNSRecord(obj) },
+	})
+
+	dnsZoneInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueueDNSZone(obj) },
+		UpdateFunc: func(_, obj interface{}) { c.enqueueDNSZone(obj) },
+		DeleteFunc: func(obj interface{}) { c.enqueueDNSZone(obj) },
+	})
+
+	secretInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueueSecret(obj) },
+		UpdateFunc: func(_, obj interface{}) { c.enqueueSecret(obj) },
+		DeleteFunc: func(obj interface{}) { c.enqueueSecret(obj) },
+	})
+
+	return c, nil
+}
+
+// controller issues and renews the certificates of Certificates.
+type controller struct {
+	queue workqueue.RateLimitingInterface
+
+	kcpClusterClient   kcpclient.ClusterInterface
+	certificateLister  workloadlisters.CertificateLister
+	certificateIndexer cache.Indexer
+	providers          dns.Providers
+	issuer             certificates.Issuer
+
+	now func() time.Time
+
+	listZones         func() ([]*workloadv1alpha1.DNSZone, error)
+	listRecordsByName func(name string) ([]*workloadv1alpha1.DNSRecord, error)
+	getSecret         func(clusterName logicalcluster.Name, namespace, name string) (*corev1.Secret, error)
+	listSecrets       func(clusterName logicalcluster.Name, namespace, certificateName string) ([]*corev1.Secret, error)
+	createSecret      func(ctx context.Context, clusterName logicalcluster.Name, secret *corev1.Secret) error
+	updateSecret      func(ctx context.Context, clusterName logicalcluster.Name, secret *corev1.Secret) error
+	deleteSecret      func(ctx context.Context, clusterName logicalcluster.Name, namespace, name string) error
+	enqueueAfter      func(certificate *workloadv1alpha1.Certificate, duration time.Duration)
+
+	syncChecks []cache.InformerSynced
+}
+
+func (c *controller) enqueueCertificate(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	klog.V(2).Infof("Queueing Certificate %q", key)
+	c.queue.Add(key)
+}
+
+// enqueueDNSRecord enqueues the Certificates of the name of the record, in all workspaces, as
+// the record can change which workspace owns the name.
+func (c *controller) enqueueDNSRecord(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	record, ok := obj.(*workloadv1alpha1.DNSRecord)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("obj is supposed to be a DNSRecord, but is %T", obj))
+		return
+	}
+
+	c.enqueueByDNSName(record.Spec.Name, fmt.Sprintf("DNSRecord %s|%s/%s", logicalcluster.From(record), record.Namespace, record.Name))
+}
+
+// enqueueDNSZone enqueues all Certificates with DNS names below the domain of the zone.
+func (c *controller) enqueueDNSZone(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	zone, ok := obj.(*workloadv1alpha1.DNSZone)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("obj is supposed to be a DNSZone, but is %T", obj))
+		return
+	}
+
+	for _, name := range c.certificateIndexer.ListIndexFuncValues(IndexCertificatesByDNSName) {
+		if strings.HasSuffix(name, "."+zone.Spec.Domain) {
+			c.enqueueByDNSName(name, fmt.Sprintf("DNSZone %s|%s", logicalcluster.From(zone), zone.Name))
+		}
+	}
+}
+
+func (c *controller) enqueueByDNSName(name string, reason string) {
+	objs, err := c.certificateIndexer.ByIndex(IndexCertificatesByDNSName, name)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	for _, obj := range objs {
+		key, err := cache.MetaNamespaceKeyFunc(obj)
+		if err != nil {
+			runtime.HandleError(err)
+			continue
+		}
+		klog.V(2).Infof("Queueing Certificate %q because of %s", key, reason)
+		c.queue.Add(key)
+	}
+}
+
+// enqueueSecret enqueues the Certificate of the secret, if any.
+func (c *controller) enqueueSecret(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("obj is supposed to be a Secret, but is %T", obj))
+		return
+	}
+
+	if certificateName, ok := secret.Labels[workloadv1alpha1.CertificateLabel]; ok {
+		key := certificateKey(logicalcluster.From(secret), secret.Namespace, certificateName)
+		klog.V(2).Infof("Queueing Certificate %q because of its secret %s", key, secret.Name)
+		c.queue.Add(key)
+	}
+}
+
+// Start starts the controller, which stops when ctx.Done() is closed.
+func (c *controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	klog.Infof("Starting %s controller", controllerName)
+	defer klog.Infof("Shutting down %s controller", controllerName)
+
+	if !cache.WaitForNamedCacheSync(controllerName, ctx.Done(), c.syncChecks...) {
+		klog.Warning("Failed to wait for caches to sync")
+		return
+	}
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+
+	<-ctx.Done()
+}
+
+func (c *controller) startWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	if err := c.process(ctx, key); err != nil {
+		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", controllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *controller) process(ctx context.Context, key string) error {
+	namespace, clusterAwareName, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		runtime.HandleError(err)
+		return nil
+	}
+
+	obj, err := c.certificateLister.Certificates(namespace).Get(clusterAwareName)
+	if errors.IsNotFound(err) {
+		clusterName, name := clusters.SplitClusterAwareKey(clusterAwareName)
+		return c.deleteSecrets(ctx, clusterName, namespace, name, "")
+	} else if err != nil {
+		return err
+	}
+	old := obj
+	obj = obj.DeepCopy()
+
+	reconcileErr := c.reconcile(ctx, obj)
+
+	// If the object being reconciled changed as a result, update it.
+	if !equality.Semantic.DeepEqual(old.Status, obj.Status) {
+		clusterName := logicalcluster.From(obj)
+
+		oldData, err := json.Marshal(workloadv1alpha1.Certificate{
+			Status: old.Status,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to Marshal old data for Certificate %s|%s/%s: %w", clusterName, obj.Namespace, obj.Name, err)
+		}
+
+		newData, err := json.Marshal(workloadv1alpha1.Certificate{
+			ObjectMeta: metav1.ObjectMeta{
+				UID:             obj.UID,
+				ResourceVersion: obj.ResourceVersion,
+			}, // to ensure they appear in the patch as preconditions
+			Status: obj.Status,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to Marshal new data for Certificate %s|%s/%s: %w", clusterName, obj.Namespace, obj.Name, err)
+		}
+
+		patchBytes, err := jsonpatch.CreateMergePatch(oldData, newData)
+		if err != nil {
+			return fmt.Errorf("failed to create patch for Certificate %s|%s/%s: %w", clusterName, obj.Namespace, obj.Name, err)
+		}
+		if _, err := c.kcpClusterClient.Cluster(clusterName).WorkloadV1alpha1().Certificates(obj.Namespace).Patch(ctx, obj.Name, types.MergePatchType, patchBytes, metav1.PatchOptions{}, "status"); err != nil {
+			return err
+		}
+	}
+
+	return reconcileErr
+}
+
+// deleteSecrets deletes the secrets of the Certificate, except the one of the given name.
+func (c *controller) deleteSecrets(ctx context.Context, clusterName logicalcluster.Name, namespace, certificateName, except string) error {
+	secrets, err := c.listSecrets(clusterName, namespace, certificateName)
+	if err != nil {
+		return err
+	}
+	for _, secret := range secrets {
+		if secret.Name == except {
+			continue
+		}
+		klog.Infof("Deleting secret %s|%s/%s of Certificate %s", clusterName, namespace, secret.Name, certificateName)
+		if err := c.deleteSecret(ctx, clusterName, namespace, secret.Name); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
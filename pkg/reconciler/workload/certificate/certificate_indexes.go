@@ -0,0 +1,43 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"fmt"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/client-go/tools/clusters"
+
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+)
+
+const IndexCertificatesByDNSName = "certificatesByDNSName"
+
+// IndexCertificatesByDNSNameFunc is an index function that maps a Certificate to its DNS names.
+func IndexCertificatesByDNSNameFunc(obj interface{}) ([]string, error) {
+	certificate, ok := obj.(*workloadv1alpha1.Certificate)
+	if !ok {
+		return []string{}, fmt.Errorf("obj is supposed to be a Certificate, but is %T", obj)
+	}
+
+	return certificate.Spec.DNSNames, nil
+}
+
+func certificateKey(clusterName logicalcluster.Name, namespace, name string) string {
+	return namespace + "/" + clusters.ToClusterAwareKey(clusterName, name)
+}
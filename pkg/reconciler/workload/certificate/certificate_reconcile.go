@@ -0,0 +1,271 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/certificates"
+	"github.com/kcp-dev/kcp/pkg/reconciler/workload/dnsrecord"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/third_party/conditions/util/conditions"
+)
+
+func (c *controller) reconcile(ctx context.Context, certificate *workloadv1alpha1.Certificate) error {
+	clusterName := logicalcluster.From(certificate)
+
+	zones, err := c.listZones()
+	if err != nil {
+		return err
+	}
+
+	// only names owned by the workspace are certified
+	domains := make([]certificates.Domain, 0, len(certificate.Spec.DNSNames))
+	for _, name := range certificate.Spec.DNSNames {
+		claimants, err := c.listRecordsByName(name)
+		if err != nil {
+			return err
+		}
+		if owner := dnsrecord.Owner(claimants, zones); owner == nil || logicalcluster.From(owner) != clusterName {
+			conditions.MarkFalse(
+				certificate,
+				workloadv1alpha1.CertificateReady,
+				workloadv1alpha1.CertificateDNSNameNotClaimedReason,
+				conditionsv1alpha1.ConditionSeverityError,
+				"%q is not claimed by an accepted DNSRecord of the workspace",
+				name,
+			)
+			return nil
+		}
+
+		zone := dnsrecord.ZoneFor(zones, clusterName, name)
+		provider, found := c.providers[zone.Spec.Provider]
+		if !found {
+			conditions.MarkFalse(
+				certificate,
+				workloadv1alpha1.CertificateReady,
+				workloadv1alpha1.DNSProviderNotFoundReason,
+				conditionsv1alpha1.ConditionSeverityError,
+				"DNS provider %q of DNSZone %s is not configured",
+				zone.Spec.Provider,
+				zone.Name,
+			)
+			return nil
+		}
+		domains = append(domains, certificates.Domain{Name: name, Zone: zone.Spec.Domain, Provider: provider})
+	}
+
+	existing, err := c.getSecret(clusterName, certificate.Namespace, certificate.Spec.SecretName)
+	if errors.IsNotFound(err) {
+		existing = nil
+	} else if err != nil {
+		return err
+	} else if existing.Labels[workloadv1alpha1.CertificateLabel] != certificate.Name {
+		conditions.MarkFalse(
+			certificate,
+			workloadv1alpha1.CertificateReady,
+			workloadv1alpha1.CertificateSecretConflictReason,
+			conditionsv1alpha1.ConditionSeverityError,
+			"Secret %s already exists and does not belong to the Certificate",
+			certificate.Spec.SecretName,
+		)
+		return nil
+	}
+
+	// the secret name changed
+	if err := c.deleteSecrets(ctx, clusterName, certificate.Namespace, certificate.Name, certificate.Spec.SecretName); err != nil {
+		return err
+	}
+
+	now := c.now()
+	var current *x509.Certificate
+	if existing != nil {
+		current = validCertificate(existing, certificate.Spec.DNSNames, now)
+	}
+	if current != nil {
+		setValidity(certificate, current)
+		conditions.MarkTrue(certificate, workloadv1alpha1.CertificateReady)
+		if renewal := renewalTime(current); now.Before(renewal) {
+			c.enqueueAfter(certificate, renewal.Sub(now))
+			return nil
+		}
+	}
+
+	if c.issuer == nil {
+		if current == nil {
+			conditions.MarkFalse(
+				certificate,
+				workloadv1alpha1.CertificateReady,
+				workloadv1alpha1.CertificateIssuerNotConfiguredReason,
+				conditionsv1alpha1.ConditionSeverityError,
+				"kcp is not configured to issue certificates",
+			)
+		}
+		return nil
+	}
+
+	klog.Infof("Issuing certificate for Certificate %s|%s/%s", clusterName, certificate.Namespace, certificate.Name)
+	desired, issued, err := c.issue(ctx, certificate, domains)
+	if err != nil {
+		// keep using a valid certificate, and retry
+		if current == nil {
+			conditions.MarkFalse(
+				certificate,
+				workloadv1alpha1.CertificateReady,
+				workloadv1alpha1.CertificateIssuingFailedReason,
+				conditionsv1alpha1.ConditionSeverityError,
+				"Failed to issue certificate: %v",
+				err,
+			)
+		}
+		return err
+	}
+
+	switch {
+	case existing == nil:
+		klog.Infof("Creating secret %s|%s/%s for Certificate %s", clusterName, desired.Namespace, desired.Name, certificate.Name)
+		if err := c.createSecret(ctx, clusterName, desired); err != nil {
+			return err
+		}
+	case existing.Type != desired.Type:
+		// the type of secrets is immutable
+		klog.Infof("Recreating secret %s|%s/%s for Certificate %s because it is not of type %s", clusterName, desired.Namespace, desired.Name, certificate.Name, desired.Type)
+		if err := c.deleteSecret(ctx, clusterName, existing.Namespace, existing.Name); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		if err := c.createSecret(ctx, clusterName, desired); err != nil {
+			return err
+		}
+	default:
+		klog.Infof("Updating secret %s|%s/%s for Certificate %s", clusterName, desired.Namespace, desired.Name, certificate.Name)
+		updated := existing.DeepCopy()
+		updated.Data = desired.Data
+		if err := c.updateSecret(ctx, clusterName, updated); err != nil {
+			return err
+		}
+	}
+
+	setValidity(certificate, issued)
+	conditions.MarkTrue(certificate, workloadv1alpha1.CertificateReady)
+	c.enqueueAfter(certificate, renewalTime(issued).Sub(now))
+
+	return nil
+}
+
+// issue issues a certificate with a new private key, and returns the secret holding them.
+func (c *controller) issue(ctx context.Context, certificate *workloadv1alpha1.Certificate, domains []certificates.Domain) (*corev1.Secret, *x509.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	template := &x509.CertificateRequest{DNSNames: certificate.Spec.DNSNames}
+	if len(certificate.Spec.DNSNames[0]) <= 64 {
+		template.Subject = pkix.Name{CommonName: certificate.Spec.DNSNames[0]}
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, template, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	chain, err := c.issuer.Issue(ctx, csr, domains)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(chain) == 0 {
+		return nil, nil, fmt.Errorf("issuer returned no certificate")
+	}
+	leaf, err := x509.ParseCertificate(chain[0])
+	if err != nil {
+		return nil, nil, fmt.Errorf("issuer returned an invalid certificate: %w", err)
+	}
+
+	var certPEM []byte
+	for _, der := range chain {
+		certPEM = append(certPEM, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      certificate.Spec.SecretName,
+			Namespace: certificate.Namespace,
+			Labels: map[string]string{
+				workloadv1alpha1.CertificateLabel: certificate.Name,
+			},
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       certPEM,
+			corev1.TLSPrivateKeyKey: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+		},
+	}, leaf, nil
+}
+
+// validCertificate returns the certificate of the secret if it matches its private key, is
+// valid at the given time, and is for exactly the DNS names. It returns nil otherwise.
+func validCertificate(secret *corev1.Secret, dnsNames []string, now time.Time) *x509.Certificate {
+	if secret.Type != corev1.SecretTypeTLS {
+		return nil
+	}
+	pair, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return nil
+	}
+	leaf, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return nil
+	}
+	if now.Before(leaf.NotBefore) || !now.Before(leaf.NotAfter) {
+		return nil
+	}
+	if !sets.NewString(leaf.DNSNames...).Equal(sets.NewString(dnsNames...)) {
+		return nil
+	}
+	return leaf
+}
+
+// renewalTime returns when the certificate is renewed, after two thirds of its validity.
+func renewalTime(cert *x509.Certificate) time.Time {
+	return cert.NotBefore.Add(cert.NotAfter.Sub(cert.NotBefore) * 2 / 3)
+}
+
+func setValidity(certificate *workloadv1alpha1.Certificate, cert *x509.Certificate) {
+	certificate.Status.NotBefore = &metav1.Time{Time: cert.NotBefore}
+	certificate.Status.NotAfter = &metav1.Time{Time: cert.NotAfter}
+	certificate.Status.RenewalTime = &metav1.Time{Time: renewalTime(cert)}
+}
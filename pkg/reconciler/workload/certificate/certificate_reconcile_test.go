@@ -0,0 +1,352 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/certificates"
+	"github.com/kcp-dev/kcp/pkg/dns"
+	"github.com/kcp-dev/kcp/third_party/conditions/util/conditions"
+)
+
+type fakeIssuer struct {
+	err       error
+	notBefore time.Time
+	notAfter  time.Time
+
+	issued  int
+	domains []certificates.Domain
+}
+
+func (i *fakeIssuer) Issue(_ context.Context, csr []byte, domains []certificates.Domain) ([][]byte, error) {
+	if i.err != nil {
+		return nil, i.err
+	}
+	req, err := x509.ParseCertificateRequest(csr)
+	if err != nil {
+		return nil, err
+	}
+	i.issued++
+	i.domains = domains
+	der, err := sign(req.PublicKey, req.DNSNames, i.notBefore, i.notAfter)
+	if err != nil {
+		return nil, err
+	}
+	return [][]byte{der}, nil
+}
+
+func sign(pub interface{}, dnsNames []string, notBefore, notAfter time.Time) ([]byte, error) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		DNSNames:     dnsNames,
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+	return x509.CreateCertificate(rand.Reader, template, template, pub, caKey)
+}
+
+type nopProvider struct{}
+
+func (nopProvider) Ensure(context.Context, dns.Record) error { return nil }
+func (nopProvider) Delete(context.Context, dns.Record) error { return nil }
+
+func TestReconcile(t *testing.T) {
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	earlier := metav1.NewTime(now.Add(-time.Hour))
+
+	zones := []*workloadv1alpha1.DNSZone{{
+		ObjectMeta: metav1.ObjectMeta{Name: "apps", ClusterName: "root", CreationTimestamp: earlier},
+		Spec:       workloadv1alpha1.DNSZoneSpec{Domain: "apps.example.com", Provider: "webhook"},
+	}}
+	record := func(clusterName string) *workloadv1alpha1.DNSRecord {
+		return &workloadv1alpha1.DNSRecord{
+			ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "default", ClusterName: clusterName, CreationTimestamp: earlier},
+			Spec:       workloadv1alpha1.DNSRecordSpec{Name: "shop.apps.example.com", Targets: []string{"10.0.0.1"}},
+		}
+	}
+	certificate := func(dnsNames ...string) *workloadv1alpha1.Certificate {
+		return &workloadv1alpha1.Certificate{
+			ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "default", ClusterName: "root:org:ws"},
+			Spec:       workloadv1alpha1.CertificateSpec{DNSNames: dnsNames, SecretName: "shop-tls"},
+		}
+	}
+	secret := func(name string, dnsNames []string, notBefore, notAfter time.Time) *corev1.Secret {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		der, err := sign(key.Public(), dnsNames, notBefore, notAfter)
+		require.NoError(t, err)
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		require.NoError(t, err)
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "default",
+				ClusterName: "root:org:ws",
+				Labels:      map[string]string{workloadv1alpha1.CertificateLabel: "shop"},
+			},
+			Type: corev1.SecretTypeTLS,
+			Data: map[string][]byte{
+				corev1.TLSCertKey:       pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+				corev1.TLSPrivateKeyKey: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+			},
+		}
+	}
+	shop := []string{"shop.apps.example.com"}
+	foreign := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "shop-tls", Namespace: "default", ClusterName: "root:org:ws"}}
+
+	tests := map[string]struct {
+		certificate *workloadv1alpha1.Certificate
+		records     []*workloadv1alpha1.DNSRecord
+		providers   dns.Providers
+		noIssuer    bool
+		issuerErr   error
+		secrets     []*corev1.Secret
+
+		wantErr       bool
+		wantReady     corev1.ConditionStatus
+		wantReason    string
+		wantIssued    bool
+		wantCreated   bool
+		wantUpdated   bool
+		wantDeleted   []string
+		wantNotAfter  time.Time
+		wantRequeueIn time.Duration
+	}{
+		"name not claimed": {
+			certificate: certificate("shop.apps.example.com"),
+			wantReady:   corev1.ConditionFalse,
+			wantReason:  workloadv1alpha1.CertificateDNSNameNotClaimedReason,
+		},
+		"name claimed by another workspace": {
+			certificate: certificate("shop.apps.example.com"),
+			records:     []*workloadv1alpha1.DNSRecord{record("root:other:ws")},
+			wantReady:   corev1.ConditionFalse,
+			wantReason:  workloadv1alpha1.CertificateDNSNameNotClaimedReason,
+		},
+		"provider not configured": {
+			certificate: certificate("shop.apps.example.com"),
+			records:     []*workloadv1alpha1.DNSRecord{record("root:org:ws")},
+			providers:   dns.Providers{},
+			wantReady:   corev1.ConditionFalse,
+			wantReason:  workloadv1alpha1.DNSProviderNotFoundReason,
+		},
+		"secret of another owner": {
+			certificate: certificate("shop.apps.example.com"),
+			records:     []*workloadv1alpha1.DNSRecord{record("root:org:ws")},
+			secrets:     []*corev1.Secret{foreign},
+			wantReady:   corev1.ConditionFalse,
+			wantReason:  workloadv1alpha1.CertificateSecretConflictReason,
+		},
+		"issuer not configured": {
+			certificate: certificate("shop.apps.example.com"),
+			records:     []*workloadv1alpha1.DNSRecord{record("root:org:ws")},
+			noIssuer:    true,
+			wantReady:   corev1.ConditionFalse,
+			wantReason:  workloadv1alpha1.CertificateIssuerNotConfiguredReason,
+		},
+		"certificate is issued": {
+			certificate:   certificate("shop.apps.example.com"),
+			records:       []*workloadv1alpha1.DNSRecord{record("root:org:ws")},
+			wantReady:     corev1.ConditionTrue,
+			wantIssued:    true,
+			wantCreated:   true,
+			wantNotAfter:  now.Add(90 * 24 * time.Hour),
+			wantRequeueIn: 60 * 24 * time.Hour,
+		},
+		"valid certificate is kept": {
+			certificate:   certificate("shop.apps.example.com"),
+			records:       []*workloadv1alpha1.DNSRecord{record("root:org:ws")},
+			secrets:       []*corev1.Secret{secret("shop-tls", shop, now.Add(-30*24*time.Hour), now.Add(60*24*time.Hour))},
+			wantReady:     corev1.ConditionTrue,
+			wantNotAfter:  now.Add(60 * 24 * time.Hour),
+			wantRequeueIn: 30 * 24 * time.Hour,
+		},
+		"certificate is renewed": {
+			certificate:   certificate("shop.apps.example.com"),
+			records:       []*workloadv1alpha1.DNSRecord{record("root:org:ws")},
+			secrets:       []*corev1.Secret{secret("shop-tls", shop, now.Add(-70*24*time.Hour), now.Add(20*24*time.Hour))},
+			wantReady:     corev1.ConditionTrue,
+			wantIssued:    true,
+			wantUpdated:   true,
+			wantNotAfter:  now.Add(90 * 24 * time.Hour),
+			wantRequeueIn: 60 * 24 * time.Hour,
+		},
+		"expired certificate is replaced": {
+			certificate:   certificate("shop.apps.example.com"),
+			records:       []*workloadv1alpha1.DNSRecord{record("root:org:ws")},
+			secrets:       []*corev1.Secret{secret("shop-tls", shop, now.Add(-90*24*time.Hour), now.Add(-time.Hour))},
+			wantReady:     corev1.ConditionTrue,
+			wantIssued:    true,
+			wantUpdated:   true,
+			wantNotAfter:  now.Add(90 * 24 * time.Hour),
+			wantRequeueIn: 60 * 24 * time.Hour,
+		},
+		"certificate of other names is replaced": {
+			certificate:   certificate("shop.apps.example.com"),
+			records:       []*workloadv1alpha1.DNSRecord{record("root:org:ws")},
+			secrets:       []*corev1.Secret{secret("shop-tls", []string{"old.apps.example.com"}, now.Add(-time.Hour), now.Add(60*24*time.Hour))},
+			wantReady:     corev1.ConditionTrue,
+			wantIssued:    true,
+			wantUpdated:   true,
+			wantNotAfter:  now.Add(90 * 24 * time.Hour),
+			wantRequeueIn: 60 * 24 * time.Hour,
+		},
+		"issuing fails": {
+			certificate: certificate("shop.apps.example.com"),
+			records:     []*workloadv1alpha1.DNSRecord{record("root:org:ws")},
+			issuerErr:   errors.New("boom"),
+			wantErr:     true,
+			wantReady:   corev1.ConditionFalse,
+			wantReason:  workloadv1alpha1.CertificateIssuingFailedReason,
+		},
+		"renewal fails with a valid certificate": {
+			certificate:  certificate("shop.apps.example.com"),
+			records:      []*workloadv1alpha1.DNSRecord{record("root:org:ws")},
+			secrets:      []*corev1.Secret{secret("shop-tls", shop, now.Add(-70*24*time.Hour), now.Add(20*24*time.Hour))},
+			issuerErr:    errors.New("boom"),
+			wantErr:      true,
+			wantReady:    corev1.ConditionTrue,
+			wantNotAfter: now.Add(20 * 24 * time.Hour),
+		},
+		"secret of former name is deleted": {
+			certificate:   certificate("shop.apps.example.com"),
+			records:       []*workloadv1alpha1.DNSRecord{record("root:org:ws")},
+			secrets:       []*corev1.Secret{secret("old-tls", shop, now.Add(-time.Hour), now.Add(60*24*time.Hour))},
+			wantReady:     corev1.ConditionTrue,
+			wantIssued:    true,
+			wantCreated:   true,
+			wantDeleted:   []string{"old-tls"},
+			wantNotAfter:  now.Add(90 * 24 * time.Hour),
+			wantRequeueIn: 60 * 24 * time.Hour,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			issuer := &fakeIssuer{err: tc.issuerErr, notBefore: now, notAfter: now.Add(90 * 24 * time.Hour)}
+			providers := tc.providers
+			if providers == nil {
+				providers = dns.Providers{"webhook": nopProvider{}}
+			}
+			var created, updated *corev1.Secret
+			var deleted []string
+			var requeueIn time.Duration
+			c := &controller{
+				providers: providers,
+				issuer:    issuer,
+				now:       func() time.Time { return now },
+				listZones: func() ([]*workloadv1alpha1.DNSZone, error) {
+					return zones, nil
+				},
+				listRecordsByName: func(name string) ([]*workloadv1alpha1.DNSRecord, error) {
+					require.Equal(t, "shop.apps.example.com", name)
+					return tc.records, nil
+				},
+				getSecret: func(clusterName logicalcluster.Name, namespace, name string) (*corev1.Secret, error) {
+					for _, secret := range tc.secrets {
+						if secret.Name == name {
+							return secret, nil
+						}
+					}
+					return nil, apierrors.NewNotFound(corev1.Resource("secrets"), name)
+				},
+				listSecrets: func(clusterName logicalcluster.Name, namespace, certificateName string) ([]*corev1.Secret, error) {
+					var ret []*corev1.Secret
+					for _, secret := range tc.secrets {
+						if secret.Labels[workloadv1alpha1.CertificateLabel] == certificateName {
+							ret = append(ret, secret)
+						}
+					}
+					return ret, nil
+				},
+				createSecret: func(_ context.Context, _ logicalcluster.Name, secret *corev1.Secret) error {
+					created = secret
+					return nil
+				},
+				updateSecret: func(_ context.Context, _ logicalcluster.Name, secret *corev1.Secret) error {
+					updated = secret
+					return nil
+				},
+				deleteSecret: func(_ context.Context, _ logicalcluster.Name, _, name string) error {
+					deleted = append(deleted, name)
+					return nil
+				},
+				enqueueAfter: func(_ *workloadv1alpha1.Certificate, duration time.Duration) {
+					requeueIn = duration
+				},
+			}
+			if tc.noIssuer {
+				c.issuer = nil
+			}
+
+			certificate := tc.certificate.DeepCopy()
+			err := c.reconcile(context.Background(), certificate)
+			if tc.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.Equal(t, tc.wantReady, conditions.Get(certificate, workloadv1alpha1.CertificateReady).Status)
+			if tc.wantReason != "" {
+				require.Equal(t, tc.wantReason, conditions.GetReason(certificate, workloadv1alpha1.CertificateReady))
+			}
+			require.Equal(t, tc.wantIssued, issuer.issued > 0)
+			if tc.wantIssued {
+				require.Equal(t, []certificates.Domain{{Name: "shop.apps.example.com", Zone: "apps.example.com", Provider: nopProvider{}}}, issuer.domains)
+			}
+			require.Equal(t, tc.wantCreated, created != nil)
+			require.Equal(t, tc.wantUpdated, updated != nil)
+			for _, s := range []*corev1.Secret{created, updated} {
+				if s == nil {
+					continue
+				}
+				require.Equal(t, "shop-tls", s.Name)
+				require.Equal(t, corev1.SecretTypeTLS, s.Type)
+				require.NotNil(t, validCertificate(s, shop, now))
+			}
+			require.Equal(t, tc.wantDeleted, deleted)
+			if tc.wantNotAfter.IsZero() {
+				require.Nil(t, certificate.Status.NotAfter)
+			} else {
+				require.True(t, tc.wantNotAfter.Equal(certificate.Status.NotAfter.Time), "got %v", certificate.Status.NotAfter)
+			}
+			require.Equal(t, tc.wantRequeueIn, requeueIn)
+		})
+	}
+}
@@ -0,0 +1,263 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dnsrecord
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+	kcpclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
+	workloadinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/workload/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/dns"
+	"github.com/kcp-dev/kcp/pkg/reconciler/latency"
+)
+
+const (
+	controllerName = "kcp-dnsrecord"
+)
+
+// NewController returns a new controller that decides which DNSRecord owns a DNS name across all
+// workspaces, and programs the records of the owners through the DNS providers of their zones.
+func NewController(
+	kcpClusterClient kcpclient.ClusterInterface,
+	dnsRecordInformer workloadinformers.DNSRecordInformer,
+	dnsZoneInformer workloadinformers.DNSZoneInformer,
+	providers dns.Providers,
+) (*controller, error) {
+	queue := latency.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+
+	if _, found := dnsRecordInformer.Informer().GetIndexer().GetIndexers()[IndexDNSRecordsByName]; !found {
+		if err := dnsRecordInformer.Informer().AddIndexers(cache.Indexers{
+			IndexDNSRecordsByName: IndexDNSRecordsByNameFunc,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	recordIndexer := dnsRecordInformer.Informer().GetIndexer()
+	zoneLister := dnsZoneInformer.Lister()
+	c := &controller{
+		queue:         queue,
+		recordIndexer: recordIndexer,
+		providers:     providers,
+		listZones: func() ([]*workloadv1alpha1.DNSZone, error) {
+			return zoneLister.List(labels.Everything())
+		},
+		listRecordsByName: func(name string) ([]*workloadv1alpha1.DNSRecord, error) {
+			objs, err := recordIndexer.ByIndex(IndexDNSRecordsByName, name)
+			if err != nil {
+				return nil, err
+			}
+			records := make([]*workloadv1alpha1.DNSRecord, 0, len(objs))
+			for _, obj := range objs {
+				records = append(records, obj.(*workloadv1alpha1.DNSRecord))
+			}
+			return records, nil
+		},
+		updateRecord: func(ctx context.Context, record *workloadv1alpha1.DNSRecord) (*workloadv1alpha1.DNSRecord, error) {
+			return kcpClusterClient.Cluster(logicalcluster.From(record)).WorkloadV1alpha1().DNSRecords(record.Namespace).Update(ctx, record, metav1.UpdateOptions{})
+		},
+		updateRecordStatus: func(ctx context.Context, record *workloadv1alpha1.DNSRecord) error {
+			_, err := kcpClusterClient.Cluster(logicalcluster.From(record)).WorkloadV1alpha1().DNSRecords(record.Namespace).UpdateStatus(ctx, record, metav1.UpdateOptions{})
+			return err
+		},
+		syncChecks: []cache.InformerSynced{
+			dnsRecordInformer.Informer().HasSynced,
+			dnsZoneInformer.Informer().HasSynced,
+		},
+	}
+
+	dnsRecordInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueueDNSRecord(obj) },
+		UpdateFunc: func(_, obj interface{}) { c.enqueueDNSRecord(obj) },
+		DeleteFunc: func(obj interface{}) { c.enqueueDNSRecord(obj) },
+	})
+
+	dnsZoneInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueueDNSZone(obj) },
+		UpdateFunc: func(_, obj interface{}) { c.enqueueDNSZone(obj) },
+		DeleteFunc: func(obj interface{}) { c.enqueueDNSZone(obj) },
+	})
+
+	return c, nil
+}
+
+// controller programs the DNSRecords owning their names.
+type controller struct {
+	queue workqueue.RateLimitingInterface
+
+	recordIndexer cache.Indexer
+	providers     dns.Providers
+
+	listZones          func() ([]*workloadv1alpha1.DNSZone, error)
+	listRecordsByName  func(name string) ([]*workloadv1alpha1.DNSRecord, error)
+	updateRecord       func(ctx context.Context, record *workloadv1alpha1.DNSRecord) (*workloadv1alpha1.DNSRecord, error)
+	updateRecordStatus func(ctx context.Context, record *workloadv1alpha1.DNSRecord) error
+
+	syncChecks []cache.InformerSynced
+}
+
+// enqueueDNSRecord enqueues all DNSRecords claiming the name of the record, as the
+// record can change which of them owns the name.
+func (c *controller) enqueueDNSRecord(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	record, ok := obj.(*workloadv1alpha1.DNSRecord)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("obj is supposed to be a DNSRecord, but is %T", obj))
+		return
+	}
+
+	key, err := cache.MetaNamespaceKeyFunc(record)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	klog.V(2).Infof("Queueing DNSRecord %q", key)
+	c.queue.Add(key)
+
+	c.enqueueByName(record.Spec.Name, key)
+}
+
+func (c *controller) enqueueByName(name string, reason string) {
+	records, err := c.recordIndexer.ByIndex(IndexDNSRecordsByName, name)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	for _, obj := range records {
+		key, err := cache.MetaNamespaceKeyFunc(obj)
+		if err != nil {
+			runtime.HandleError(err)
+			continue
+		}
+		klog.V(2).Infof("Queueing DNSRecord %q because of %s", key, reason)
+		c.queue.Add(key)
+	}
+}
+
+// enqueueDNSZone enqueues all DNSRecords with names below the domain of the zone.
+func (c *controller) enqueueDNSZone(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	zone, ok := obj.(*workloadv1alpha1.DNSZone)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("obj is supposed to be a DNSZone, but is %T", obj))
+		return
+	}
+
+	for _, name := range c.recordIndexer.ListIndexFuncValues(IndexDNSRecordsByName) {
+		if strings.HasSuffix(name, "."+zone.Spec.Domain) {
+			c.enqueueByName(name, fmt.Sprintf("DNSZone %s|%s", logicalcluster.From(zone), zone.Name))
+		}
+	}
+}
+
+// Start starts the controller, which stops when ctx.Done() is closed.
+func (c *controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	klog.Infof("Starting %s controller", controllerName)
+	defer klog.Infof("Shutting down %s controller", controllerName)
+
+	if !cache.WaitForNamedCacheSync(controllerName, ctx.Done(), c.syncChecks...) {
+		klog.Warning("Failed to wait for caches to sync")
+		return
+	}
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+
+	<-ctx.Done()
+}
+
+func (c *controller) startWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	if err := c.process(ctx, key); err != nil {
+		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", controllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *controller) process(ctx context.Context, key string) error {
+	obj, exists, err := c.recordIndexer.GetByKey(key)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return nil
+	}
+	old := obj.(*workloadv1alpha1.DNSRecord)
+	record := old.DeepCopy()
+
+	reconcileErr := c.reconcile(ctx, record)
+
+	if !equality.Semantic.DeepEqual(old.Finalizers, record.Finalizers) {
+		updated, err := c.updateRecord(ctx, record)
+		if err != nil {
+			return err
+		}
+		if updated.DeletionTimestamp != nil && len(updated.Finalizers) == 0 {
+			return reconcileErr // about to be gone
+		}
+		record.ResourceVersion = updated.ResourceVersion
+	}
+
+	if !equality.Semantic.DeepEqual(old.Status, record.Status) {
+		if err := c.updateRecordStatus(ctx, record); err != nil {
+			return err
+		}
+	}
+
+	return reconcileErr
+}
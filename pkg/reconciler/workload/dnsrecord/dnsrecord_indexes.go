@@ -0,0 +1,104 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dnsrecord
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+)
+
+const IndexDNSRecordsByName = "dnsRecordsByName"
+
+// IndexDNSRecordsByNameFunc is an index function that maps a DNSRecord to its DNS name.
+func IndexDNSRecordsByNameFunc(obj interface{}) ([]string, error) {
+	record, ok := obj.(*workloadv1alpha1.DNSRecord)
+	if !ok {
+		return []string{}, fmt.Errorf("obj is supposed to be a DNSRecord, but is %T", obj)
+	}
+
+	return []string{record.Spec.Name}, nil
+}
+
+// ZoneFor returns the DNSZone of the root workspace with the longest domain that the name
+// is below of, and in which the workspace can claim names. It returns nil if there is none.
+func ZoneFor(zones []*workloadv1alpha1.DNSZone, clusterName logicalcluster.Name, name string) *workloadv1alpha1.DNSZone {
+	var found *workloadv1alpha1.DNSZone
+	for _, zone := range zones {
+		if logicalcluster.From(zone) != tenancyv1alpha1.RootCluster {
+			continue
+		}
+		if !strings.HasSuffix(name, "."+zone.Spec.Domain) || !CanClaim(zone, clusterName) {
+			continue
+		}
+		if found == nil || len(zone.Spec.Domain) > len(found.Spec.Domain) || (zone.Spec.Domain == found.Spec.Domain && olderZone(zone, found)) {
+			found = zone
+		}
+	}
+	return found
+}
+
+// CanClaim returns whether the workspace can claim names in the zone.
+func CanClaim(zone *workloadv1alpha1.DNSZone, clusterName logicalcluster.Name) bool {
+	if len(zone.Spec.Workspaces) == 0 {
+		return true
+	}
+	for _, ws := range zone.Spec.Workspaces {
+		if clusterName.String() == ws || strings.HasPrefix(clusterName.String(), ws+":") {
+			return true
+		}
+	}
+	return false
+}
+
+func olderZone(a, b *workloadv1alpha1.DNSZone) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	return a.Name < b.Name
+}
+
+// Owner returns the DNSRecord owning the name among the records claiming it, i.e. the
+// oldest one that has a zone. Records being deleted keep owning the name until they are
+// gone, such that they can remove the name from the DNS provider first.
+func Owner(records []*workloadv1alpha1.DNSRecord, zones []*workloadv1alpha1.DNSZone) *workloadv1alpha1.DNSRecord {
+	var owner *workloadv1alpha1.DNSRecord
+	for _, record := range records {
+		if ZoneFor(zones, logicalcluster.From(record), record.Spec.Name) == nil {
+			continue
+		}
+		if owner == nil || olderRecord(record, owner) {
+			owner = record
+		}
+	}
+	return owner
+}
+
+func olderRecord(a, b *workloadv1alpha1.DNSRecord) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	return recordKey(a) < recordKey(b)
+}
+
+func recordKey(record *workloadv1alpha1.DNSRecord) string {
+	return logicalcluster.From(record).String() + "|" + record.Namespace + "/" + record.Name
+}
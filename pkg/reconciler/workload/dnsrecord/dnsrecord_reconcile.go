@@ -0,0 +1,210 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dnsrecord
+
+import (
+	"context"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/dns"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/third_party/conditions/util/conditions"
+)
+
+func (c *controller) reconcile(ctx context.Context, record *workloadv1alpha1.DNSRecord) error {
+	clusterName := logicalcluster.From(record)
+	finalizers := sets.NewString(record.Finalizers...)
+
+	zones, err := c.listZones()
+	if err != nil {
+		return err
+	}
+
+	if record.DeletionTimestamp != nil {
+		if !finalizers.Has(workloadv1alpha1.DNSRecordFinalizer) {
+			return nil
+		}
+		if err := c.unprogram(ctx, record, zones); err != nil {
+			return err
+		}
+		record.Finalizers = finalizers.Delete(workloadv1alpha1.DNSRecordFinalizer).List()
+		return nil
+	}
+
+	zone := ZoneFor(zones, clusterName, record.Spec.Name)
+	if zone == nil {
+		conditions.MarkFalse(
+			record,
+			workloadv1alpha1.DNSRecordAccepted,
+			workloadv1alpha1.DNSZoneNotFoundReason,
+			conditionsv1alpha1.ConditionSeverityError,
+			"No DNSZone for %q allows workspace %s to claim names",
+			record.Spec.Name,
+			clusterName,
+		)
+		return c.release(ctx, record, zones)
+	}
+
+	claimants, err := c.listRecordsByName(record.Spec.Name)
+	if err != nil {
+		return err
+	}
+	if owner := Owner(claimants, zones); owner != nil && recordKey(owner) != recordKey(record) {
+		// do not reveal the other workspace
+		conditions.MarkFalse(
+			record,
+			workloadv1alpha1.DNSRecordAccepted,
+			workloadv1alpha1.DNSNameConflictReason,
+			conditionsv1alpha1.ConditionSeverityError,
+			"%q is claimed by another DNSRecord",
+			record.Spec.Name,
+		)
+		return c.release(ctx, record, zones)
+	}
+	conditions.MarkTrue(record, workloadv1alpha1.DNSRecordAccepted)
+
+	provider, found := c.providers[zone.Spec.Provider]
+	if !found {
+		conditions.MarkFalse(
+			record,
+			workloadv1alpha1.DNSRecordProgrammed,
+			workloadv1alpha1.DNSProviderNotFoundReason,
+			conditionsv1alpha1.ConditionSeverityError,
+			"DNS provider %q of DNSZone %s is not configured",
+			zone.Spec.Provider,
+			zone.Name,
+		)
+		return nil
+	}
+
+	// make sure the record is removed from the provider before the DNSRecord is gone
+	if !finalizers.Has(workloadv1alpha1.DNSRecordFinalizer) {
+		record.Finalizers = finalizers.Insert(workloadv1alpha1.DNSRecordFinalizer).List()
+		return nil
+	}
+
+	// moved to a more specific zone
+	if record.Status.Zone != "" && record.Status.Zone != zone.Name {
+		if err := c.unprogram(ctx, record, zones); err != nil {
+			return err
+		}
+	}
+
+	if err := provider.Ensure(ctx, recordFor(zone, record)); err != nil {
+		conditions.MarkFalse(
+			record,
+			workloadv1alpha1.DNSRecordProgrammed,
+			workloadv1alpha1.DNSProviderErrorReason,
+			conditionsv1alpha1.ConditionSeverityError,
+			"DNS provider %q failed: %v",
+			zone.Spec.Provider,
+			err,
+		)
+		return err
+	}
+	record.Status.Zone = zone.Name
+	conditions.MarkTrue(record, workloadv1alpha1.DNSRecordProgrammed)
+
+	return nil
+}
+
+// release removes the record from the DNS provider if it was programmed, and removes the finalizer.
+func (c *controller) release(ctx context.Context, record *workloadv1alpha1.DNSRecord, zones []*workloadv1alpha1.DNSZone) error {
+	conditions.MarkFalse(
+		record,
+		workloadv1alpha1.DNSRecordProgrammed,
+		workloadv1alpha1.DNSRecordNotAcceptedReason,
+		conditionsv1alpha1.ConditionSeverityInfo,
+		"The DNSRecord is not accepted",
+	)
+
+	finalizers := sets.NewString(record.Finalizers...)
+	if !finalizers.Has(workloadv1alpha1.DNSRecordFinalizer) {
+		record.Status.Zone = ""
+		return nil
+	}
+
+	// the record is replaced by the programming of the new owner, if any
+	claimants, err := c.listRecordsByName(record.Spec.Name)
+	if err != nil {
+		return err
+	}
+	if Owner(claimants, zones) == nil {
+		if err := c.unprogram(ctx, record, zones); err != nil {
+			return err
+		}
+	}
+
+	record.Status.Zone = ""
+	record.Finalizers = finalizers.Delete(workloadv1alpha1.DNSRecordFinalizer).List()
+	return nil
+}
+
+// unprogram removes the record from the DNS provider of the zone it was programmed in. If the
+// zone or the provider is gone, the record is left to the operator of the DNS provider.
+func (c *controller) unprogram(ctx context.Context, record *workloadv1alpha1.DNSRecord, zones []*workloadv1alpha1.DNSZone) error {
+	if record.Status.Zone == "" {
+		return nil
+	}
+
+	var zone *workloadv1alpha1.DNSZone
+	for _, z := range zones {
+		if logicalcluster.From(z) == tenancyv1alpha1.RootCluster && z.Name == record.Status.Zone {
+			zone = z
+			break
+		}
+	}
+	if zone == nil {
+		klog.Warningf("Not removing %s record %q of DNSRecord %s: DNSZone %s not found", record.Spec.RecordType, record.Spec.Name, recordKey(record), record.Status.Zone)
+		return nil
+	}
+	provider, found := c.providers[zone.Spec.Provider]
+	if !found {
+		klog.Warningf("Not removing %s record %q of DNSRecord %s: DNS provider %q not configured", record.Spec.RecordType, record.Spec.Name, recordKey(record), zone.Spec.Provider)
+		return nil
+	}
+
+	if err := provider.Delete(ctx, recordFor(zone, record)); err != nil {
+		return err
+	}
+	klog.Infof("Removed %s record %q of DNSRecord %s from DNS provider %q", record.Spec.RecordType, record.Spec.Name, recordKey(record), zone.Spec.Provider)
+	return nil
+}
+
+func recordFor(zone *workloadv1alpha1.DNSZone, record *workloadv1alpha1.DNSRecord) dns.Record {
+	recordType := record.Spec.RecordType
+	if recordType == "" {
+		recordType = workloadv1alpha1.DNSRecordTypeA
+	}
+	ttl := record.Spec.TTL
+	if ttl == 0 {
+		ttl = 300
+	}
+	return dns.Record{
+		Zone:    zone.Spec.Domain,
+		Name:    record.Spec.Name,
+		Type:    string(recordType),
+		Targets: record.Spec.Targets,
+		TTL:     ttl,
+	}
+}
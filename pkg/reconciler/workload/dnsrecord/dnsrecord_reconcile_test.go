@@ -0,0 +1,234 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dnsrecord
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/dns"
+	"github.com/kcp-dev/kcp/third_party/conditions/util/conditions"
+)
+
+type fakeProvider struct {
+	err     error
+	ensured []dns.Record
+	deleted []dns.Record
+}
+
+func (p *fakeProvider) Ensure(_ context.Context, record dns.Record) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.ensured = append(p.ensured, record)
+	return nil
+}
+
+func (p *fakeProvider) Delete(_ context.Context, record dns.Record) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.deleted = append(p.deleted, record)
+	return nil
+}
+
+func TestReconcile(t *testing.T) {
+	now := metav1.NewTime(time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC))
+	earlier := metav1.NewTime(now.Add(-time.Hour))
+
+	zone := func(name, domain, provider string, workspaces ...string) *workloadv1alpha1.DNSZone {
+		return &workloadv1alpha1.DNSZone{
+			ObjectMeta: metav1.ObjectMeta{Name: name, ClusterName: "root", CreationTimestamp: earlier},
+			Spec:       workloadv1alpha1.DNSZoneSpec{Domain: domain, Provider: provider, Workspaces: workspaces},
+		}
+	}
+	record := func(clusterName string, created metav1.Time, finalizers []string, zone string) *workloadv1alpha1.DNSRecord {
+		return &workloadv1alpha1.DNSRecord{
+			ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "default", ClusterName: clusterName, CreationTimestamp: created, Finalizers: finalizers},
+			Spec: workloadv1alpha1.DNSRecordSpec{
+				Name:       "shop.apps.example.com",
+				RecordType: workloadv1alpha1.DNSRecordTypeA,
+				Targets:    []string{"10.0.0.1"},
+				TTL:        60,
+			},
+			Status: workloadv1alpha1.DNSRecordStatus{Zone: zone},
+		}
+	}
+	finalizer := []string{workloadv1alpha1.DNSRecordFinalizer}
+	ensured := func(zone string) []dns.Record {
+		return []dns.Record{{Zone: zone, Name: "shop.apps.example.com", Type: "A", Targets: []string{"10.0.0.1"}, TTL: 60}}
+	}
+	deleting := record("root:org:ws", now, finalizer, "apps")
+	deleting.DeletionTimestamp = &now
+
+	tests := map[string]struct {
+		record        *workloadv1alpha1.DNSRecord
+		others        []*workloadv1alpha1.DNSRecord
+		zones         []*workloadv1alpha1.DNSZone
+		providerErr   error
+		wantErr       bool
+		wantAccepted  corev1.ConditionStatus
+		wantReason    string
+		wantProgram   corev1.ConditionStatus
+		wantFinalizer bool
+		wantZone      string
+		wantEnsured   []dns.Record
+		wantDeleted   []dns.Record
+	}{
+		"no zone": {
+			record:       record("root:org:ws", now, nil, ""),
+			zones:        []*workloadv1alpha1.DNSZone{zone("other", "other.example.com", "webhook")},
+			wantAccepted: corev1.ConditionFalse,
+			wantReason:   workloadv1alpha1.DNSZoneNotFoundReason,
+			wantProgram:  corev1.ConditionFalse,
+		},
+		"zone of another workspace": {
+			record:       record("root:org:ws", now, nil, ""),
+			zones:        []*workloadv1alpha1.DNSZone{zone("apps", "apps.example.com", "webhook", "root:other")},
+			wantAccepted: corev1.ConditionFalse,
+			wantReason:   workloadv1alpha1.DNSZoneNotFoundReason,
+			wantProgram:  corev1.ConditionFalse,
+		},
+		"zone outside of root is ignored": {
+			record: record("root:org:ws", now, nil, ""),
+			zones: []*workloadv1alpha1.DNSZone{{
+				ObjectMeta: metav1.ObjectMeta{Name: "apps", ClusterName: "root:org"},
+				Spec:       workloadv1alpha1.DNSZoneSpec{Domain: "apps.example.com", Provider: "webhook"},
+			}},
+			wantAccepted: corev1.ConditionFalse,
+			wantReason:   workloadv1alpha1.DNSZoneNotFoundReason,
+			wantProgram:  corev1.ConditionFalse,
+		},
+		"finalizer is added first": {
+			record:        record("root:org:ws", now, nil, ""),
+			zones:         []*workloadv1alpha1.DNSZone{zone("apps", "apps.example.com", "webhook", "root:org")},
+			wantAccepted:  corev1.ConditionTrue,
+			wantFinalizer: true,
+		},
+		"record is programmed": {
+			record:        record("root:org:ws", now, finalizer, ""),
+			zones:         []*workloadv1alpha1.DNSZone{zone("apps", "apps.example.com", "webhook", "root:org")},
+			wantAccepted:  corev1.ConditionTrue,
+			wantProgram:   corev1.ConditionTrue,
+			wantFinalizer: true,
+			wantZone:      "apps",
+			wantEnsured:   ensured("apps.example.com"),
+		},
+		"most specific zone wins": {
+			record:        record("root:org:ws", now, finalizer, ""),
+			zones:         []*workloadv1alpha1.DNSZone{zone("example", "example.com", "webhook"), zone("apps", "apps.example.com", "webhook")},
+			wantAccepted:  corev1.ConditionTrue,
+			wantProgram:   corev1.ConditionTrue,
+			wantFinalizer: true,
+			wantZone:      "apps",
+			wantEnsured:   ensured("apps.example.com"),
+		},
+		"older record owns the name": {
+			record:       record("root:org:ws", now, nil, ""),
+			others:       []*workloadv1alpha1.DNSRecord{record("root:other:ws", earlier, finalizer, "apps")},
+			zones:        []*workloadv1alpha1.DNSZone{zone("apps", "apps.example.com", "webhook")},
+			wantAccepted: corev1.ConditionFalse,
+			wantReason:   workloadv1alpha1.DNSNameConflictReason,
+			wantProgram:  corev1.ConditionFalse,
+		},
+		"older record without zone does not own the name": {
+			record:        record("root:org:ws", now, finalizer, ""),
+			others:        []*workloadv1alpha1.DNSRecord{record("root:other:ws", earlier, nil, "")},
+			zones:         []*workloadv1alpha1.DNSZone{zone("apps", "apps.example.com", "webhook", "root:org")},
+			wantAccepted:  corev1.ConditionTrue,
+			wantProgram:   corev1.ConditionTrue,
+			wantFinalizer: true,
+			wantZone:      "apps",
+			wantEnsured:   ensured("apps.example.com"),
+		},
+		"record losing its zone is removed": {
+			record:       record("root:org:ws", now, finalizer, "apps"),
+			zones:        []*workloadv1alpha1.DNSZone{zone("apps", "apps.example.com", "webhook", "root:other")},
+			wantAccepted: corev1.ConditionFalse,
+			wantReason:   workloadv1alpha1.DNSZoneNotFoundReason,
+			wantProgram:  corev1.ConditionFalse,
+			wantDeleted:  ensured("apps.example.com"),
+		},
+		"provider not configured": {
+			record:        record("root:org:ws", now, finalizer, ""),
+			zones:         []*workloadv1alpha1.DNSZone{zone("apps", "apps.example.com", "unknown")},
+			wantAccepted:  corev1.ConditionTrue,
+			wantProgram:   corev1.ConditionFalse,
+			wantFinalizer: true,
+		},
+		"provider error": {
+			record:        record("root:org:ws", now, finalizer, ""),
+			zones:         []*workloadv1alpha1.DNSZone{zone("apps", "apps.example.com", "webhook")},
+			providerErr:   errors.New("boom"),
+			wantErr:       true,
+			wantAccepted:  corev1.ConditionTrue,
+			wantProgram:   corev1.ConditionFalse,
+			wantFinalizer: true,
+		},
+		"deleted record is removed": {
+			record:      deleting,
+			zones:       []*workloadv1alpha1.DNSZone{zone("apps", "apps.example.com", "webhook")},
+			wantZone:    "apps",
+			wantDeleted: ensured("apps.example.com"),
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			provider := &fakeProvider{err: tc.providerErr}
+			c := &controller{
+				providers: dns.Providers{"webhook": provider},
+				listZones: func() ([]*workloadv1alpha1.DNSZone, error) {
+					return tc.zones, nil
+				},
+				listRecordsByName: func(name string) ([]*workloadv1alpha1.DNSRecord, error) {
+					require.Equal(t, "shop.apps.example.com", name)
+					return append([]*workloadv1alpha1.DNSRecord{tc.record}, tc.others...), nil
+				},
+			}
+
+			record := tc.record.DeepCopy()
+			err := c.reconcile(context.Background(), record)
+			if tc.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			if tc.wantAccepted != "" {
+				require.Equal(t, tc.wantAccepted, conditions.Get(record, workloadv1alpha1.DNSRecordAccepted).Status)
+			}
+			if tc.wantReason != "" {
+				require.Equal(t, tc.wantReason, conditions.GetReason(record, workloadv1alpha1.DNSRecordAccepted))
+			}
+			if tc.wantProgram != "" {
+				require.Equal(t, tc.wantProgram, conditions.Get(record, workloadv1alpha1.DNSRecordProgrammed).Status)
+			}
+			require.Equal(t, tc.wantFinalizer, len(record.Finalizers) > 0)
+			require.Equal(t, tc.wantZone, record.Status.Zone)
+			require.Equal(t, tc.wantEnsured, provider.ensured)
+			require.Equal(t, tc.wantDeleted, provider.deleted)
+		})
+	}
+}
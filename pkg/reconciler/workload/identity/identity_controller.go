@@ -0,0 +1,240 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identity
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clusters"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/reconciler/latency"
+)
+
+const (
+	controllerName = "kcp-workload-identity"
+)
+
+// NewController returns a new controller that keeps short-lived tokens of the ServiceAccounts
+// synced to workload clusters in secrets next to them. The secrets are synced along with the
+// ServiceAccounts, so that pods on the workload clusters can authenticate with the identity of
+// their ServiceAccount in the workspace, against kcp or against anything trusting its issuer.
+func NewController(
+	kubeClusterClient kubernetes.ClusterInterface,
+	serviceAccountInformer coreinformers.ServiceAccountInformer,
+	secretInformer coreinformers.SecretInformer,
+	audiences []string,
+	expiration time.Duration,
+) (*controller, error) {
+	queue := latency.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+
+	secretLister := secretInformer.Lister()
+	c := &controller{
+		queue:                queue,
+		serviceAccountLister: serviceAccountInformer.Lister(),
+		audiences:            audiences,
+		expiration:           expiration,
+		now:                  time.Now,
+		getSecret: func(clusterName logicalcluster.Name, namespace, name string) (*corev1.Secret, error) {
+			return secretLister.Secrets(namespace).Get(clusters.ToClusterAwareKey(clusterName, name))
+		},
+		createSecret: func(ctx context.Context, clusterName logicalcluster.Name, secret *corev1.Secret) error {
+			_, err := kubeClusterClient.Cluster(clusterName).CoreV1().Secrets(secret.Namespace).Create(ctx, secret, metav1.CreateOptions{})
+			return err
+		},
+		updateSecret: func(ctx context.Context, clusterName logicalcluster.Name, secret *corev1.Secret) error {
+			_, err := kubeClusterClient.Cluster(clusterName).CoreV1().Secrets(secret.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
+			return err
+		},
+		deleteSecret: func(ctx context.Context, clusterName logicalcluster.Name, namespace, name string) error {
+			return kubeClusterClient.Cluster(clusterName).CoreV1().Secrets(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+		},
+		createToken: func(ctx context.Context, clusterName logicalcluster.Name, namespace, name string, audiences []string, expiration time.Duration) (string, time.Time, error) {
+			expirationSeconds := int64(expiration.Seconds())
+			tr, err := kubeClusterClient.Cluster(clusterName).CoreV1().ServiceAccounts(namespace).CreateToken(ctx, name, &authenticationv1.TokenRequest{
+				Spec: authenticationv1.TokenRequestSpec{
+					Audiences:         audiences,
+					ExpirationSeconds: &expirationSeconds,
+				},
+			}, metav1.CreateOptions{})
+			if err != nil {
+				return "", time.Time{}, err
+			}
+			return tr.Status.Token, tr.Status.ExpirationTimestamp.Time, nil
+		},
+		enqueueAfter: func(sa *corev1.ServiceAccount, duration time.Duration) {
+			key, err := cache.MetaNamespaceKeyFunc(sa)
+			if err != nil {
+				runtime.HandleError(err)
+				return
+			}
+			queue.AddAfter(key, duration)
+		},
+		syncChecks: []cache.InformerSynced{
+			serviceAccountInformer.Informer().HasSynced,
+			secretInformer.Informer().HasSynced,
+		},
+	}
+
+	serviceAccountInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueueServiceAccount(obj) },
+		UpdateFunc: func(_, obj interface{}) { c.enqueueServiceAccount(obj) },
+		DeleteFunc: func(obj interface{}) { c.enqueueServiceAccount(obj) },
+	})
+
+	secretInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueueSecret(obj) },
+		UpdateFunc: func(_, obj interface{}) { c.enqueueSecret(obj) },
+		DeleteFunc: func(obj interface{}) { c.enqueueSecret(obj) },
+	})
+
+	return c, nil
+}
+
+// controller maintains the workload identity secrets of ServiceAccounts.
+type controller struct {
+	queue workqueue.RateLimitingInterface
+
+	serviceAccountLister corelisters.ServiceAccountLister
+	audiences            []string
+	expiration           time.Duration
+
+	now func() time.Time
+
+	getSecret    func(clusterName logicalcluster.Name, namespace, name string) (*corev1.Secret, error)
+	createSecret func(ctx context.Context, clusterName logicalcluster.Name, secret *corev1.Secret) error
+	updateSecret func(ctx context.Context, clusterName logicalcluster.Name, secret *corev1.Secret) error
+	deleteSecret func(ctx context.Context, clusterName logicalcluster.Name, namespace, name string) error
+	createToken  func(ctx context.Context, clusterName logicalcluster.Name, namespace, name string, audiences []string, expiration time.Duration) (string, time.Time, error)
+	enqueueAfter func(sa *corev1.ServiceAccount, duration time.Duration)
+
+	syncChecks []cache.InformerSynced
+}
+
+func (c *controller) enqueueServiceAccount(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	klog.V(2).Infof("Queueing ServiceAccount %q", key)
+	c.queue.Add(key)
+}
+
+// enqueueSecret enqueues the ServiceAccount of a workload identity secret, e.g. to recreate
+// the secret when it is deleted.
+func (c *controller) enqueueSecret(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("obj is supposed to be a Secret, but is %T", obj))
+		return
+	}
+
+	if serviceAccountName, ok := secret.Labels[workloadv1alpha1.WorkloadIdentityLabel]; ok {
+		key := serviceAccountKey(logicalcluster.From(secret), secret.Namespace, serviceAccountName)
+		klog.V(2).Infof("Queueing ServiceAccount %q because of its workload identity secret %s", key, secret.Name)
+		c.queue.Add(key)
+	}
+}
+
+func serviceAccountKey(clusterName logicalcluster.Name, namespace, name string) string {
+	return namespace + "/" + clusters.ToClusterAwareKey(clusterName, name)
+}
+
+// Start starts the controller, which stops when ctx.Done() is closed.
+func (c *controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	klog.Infof("Starting %s controller", controllerName)
+	defer klog.Infof("Shutting down %s controller", controllerName)
+
+	if !cache.WaitForNamedCacheSync(controllerName, ctx.Done(), c.syncChecks...) {
+		klog.Warning("Failed to wait for caches to sync")
+		return
+	}
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+
+	<-ctx.Done()
+}
+
+func (c *controller) startWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	if err := c.process(ctx, key); err != nil {
+		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", controllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *controller) process(ctx context.Context, key string) error {
+	namespace, clusterAwareName, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		runtime.HandleError(err)
+		return nil
+	}
+
+	sa, err := c.serviceAccountLister.ServiceAccounts(namespace).Get(clusterAwareName)
+	if errors.IsNotFound(err) {
+		clusterName, name := clusters.SplitClusterAwareKey(clusterAwareName)
+		return c.deleteIdentitySecret(ctx, clusterName, namespace, name)
+	} else if err != nil {
+		return err
+	}
+
+	return c.reconcile(ctx, sa)
+}
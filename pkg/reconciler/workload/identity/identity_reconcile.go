@@ -0,0 +1,181 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identity
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+)
+
+// refreshFraction is the fraction of the lifetime of a token after which it is replaced.
+const refreshFraction = 0.8
+
+func (c *controller) reconcile(ctx context.Context, sa *corev1.ServiceAccount) error {
+	clusterName := logicalcluster.From(sa)
+
+	if !isSynced(sa) || sa.DeletionTimestamp != nil {
+		return c.deleteIdentitySecret(ctx, clusterName, sa.Namespace, sa.Name)
+	}
+
+	secretName := workloadv1alpha1.WorkloadIdentitySecretName(sa.Name)
+	existing, err := c.getSecret(clusterName, sa.Namespace, secretName)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if errors.IsNotFound(err) {
+		existing = nil
+	}
+	if existing != nil && existing.Labels[workloadv1alpha1.WorkloadIdentityLabel] != sa.Name {
+		return fmt.Errorf("secret %s|%s/%s is not the workload identity secret of ServiceAccount %s", clusterName, sa.Namespace, secretName, sa.Name)
+	}
+
+	if existing != nil {
+		if refreshAt, ok := c.refreshAt(existing); ok && c.now().Before(refreshAt) {
+			c.enqueueAfter(sa, refreshAt.Sub(c.now()))
+			return nil
+		}
+	}
+
+	token, expiration, err := c.createToken(ctx, clusterName, sa.Namespace, sa.Name, c.audiences, c.expiration)
+	if err != nil {
+		return fmt.Errorf("failed to request token of ServiceAccount %s|%s/%s: %w", clusterName, sa.Namespace, sa.Name, err)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName,
+			Namespace: sa.Namespace,
+			Labels: map[string]string{
+				workloadv1alpha1.WorkloadIdentityLabel: sa.Name,
+			},
+			Annotations: map[string]string{
+				workloadv1alpha1.WorkloadIdentityExpirationAnnotation: expiration.UTC().Format(time.RFC3339),
+				workloadv1alpha1.WorkloadIdentityAudiencesAnnotation:  strings.Join(c.audiences, ","),
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: "v1",
+					Kind:       "ServiceAccount",
+					Name:       sa.Name,
+					UID:        sa.UID,
+				},
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			workloadv1alpha1.WorkloadIdentityTokenKey:     []byte(token),
+			workloadv1alpha1.WorkloadIdentityNamespaceKey: []byte(sa.Namespace),
+		},
+	}
+
+	if existing == nil {
+		klog.Infof("Creating workload identity secret %s|%s/%s of ServiceAccount %s", clusterName, sa.Namespace, secretName, sa.Name)
+		err = c.createSecret(ctx, clusterName, secret)
+	} else {
+		klog.V(2).Infof("Refreshing workload identity secret %s|%s/%s of ServiceAccount %s", clusterName, sa.Namespace, secretName, sa.Name)
+		// keep the labels and annotations of others, e.g. those scheduling the secret.
+		updated := existing.DeepCopy()
+		if updated.Labels == nil {
+			updated.Labels = map[string]string{}
+		}
+		if updated.Annotations == nil {
+			updated.Annotations = map[string]string{}
+		}
+		for k, v := range secret.Labels {
+			updated.Labels[k] = v
+		}
+		for k, v := range secret.Annotations {
+			updated.Annotations[k] = v
+		}
+		updated.OwnerReferences = secret.OwnerReferences
+		updated.Data = secret.Data
+		err = c.updateSecret(ctx, clusterName, updated)
+	}
+	if err != nil {
+		return err
+	}
+
+	c.enqueueAfter(sa, refreshAfter(c.now(), expiration))
+	return nil
+}
+
+// refreshAt returns when the token of the secret has to be replaced, and false if that is now,
+// because the secret is invalid or was issued for other audiences.
+func (c *controller) refreshAt(secret *corev1.Secret) (time.Time, bool) {
+	if len(secret.Data[workloadv1alpha1.WorkloadIdentityTokenKey]) == 0 {
+		return time.Time{}, false
+	}
+	if secret.Annotations[workloadv1alpha1.WorkloadIdentityAudiencesAnnotation] != strings.Join(c.audiences, ",") {
+		return time.Time{}, false
+	}
+	expiration, err := time.Parse(time.RFC3339, secret.Annotations[workloadv1alpha1.WorkloadIdentityExpirationAnnotation])
+	if err != nil {
+		return time.Time{}, false
+	}
+	// tokens are replaced after the given fraction of the configured lifetime, independent of
+	// when they were issued, so that a changed expiration takes effect eventually.
+	return expiration.Add(-time.Duration(float64(c.expiration) * (1 - refreshFraction))), true
+}
+
+// refreshAfter returns how long to wait until replacing a token expiring at the given time.
+func refreshAfter(now, expiration time.Time) time.Duration {
+	return time.Duration(float64(expiration.Sub(now)) * refreshFraction)
+}
+
+// deleteIdentitySecret deletes the workload identity secret of the ServiceAccount, if any.
+func (c *controller) deleteIdentitySecret(ctx context.Context, clusterName logicalcluster.Name, namespace, serviceAccountName string) error {
+	secretName := workloadv1alpha1.WorkloadIdentitySecretName(serviceAccountName)
+	secret, err := c.getSecret(clusterName, namespace, secretName)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if secret.Labels[workloadv1alpha1.WorkloadIdentityLabel] != serviceAccountName {
+		return nil
+	}
+
+	klog.Infof("Deleting workload identity secret %s|%s/%s of ServiceAccount %s", clusterName, namespace, secretName, serviceAccountName)
+	if err := c.deleteSecret(ctx, clusterName, namespace, secretName); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// isSynced returns whether the ServiceAccount is synced to at least one workload cluster.
+func isSynced(sa *corev1.ServiceAccount) bool {
+	for k := range sa.Labels {
+		if !strings.HasPrefix(k, workloadv1alpha1.InternalClusterResourceStateLabelPrefix) {
+			continue
+		}
+		if _, valid := workloadv1alpha1.GetResourceState(sa, strings.TrimPrefix(k, workloadv1alpha1.InternalClusterResourceStateLabelPrefix)); valid {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identity
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+)
+
+func TestReconcile(t *testing.T) {
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	serviceAccount := func(labels map[string]string) *corev1.ServiceAccount {
+		return &corev1.ServiceAccount{
+			ObjectMeta: metav1.ObjectMeta{Name: "builder", Namespace: "default", ClusterName: "root:org:ws", UID: "uid", Labels: labels},
+		}
+	}
+	synced := map[string]string{workloadv1alpha1.InternalClusterResourceStateLabelPrefix + "us-east1": string(workloadv1alpha1.ResourceStateSync)}
+	secret := func(expiration time.Time, audiences string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "kcp-identity-builder",
+				Namespace:   "default",
+				ClusterName: "root:org:ws",
+				Labels: map[string]string{
+					workloadv1alpha1.WorkloadIdentityLabel:                                "builder",
+					workloadv1alpha1.InternalClusterResourceStateLabelPrefix + "us-east1": string(workloadv1alpha1.ResourceStateSync),
+				},
+				Annotations: map[string]string{
+					workloadv1alpha1.WorkloadIdentityExpirationAnnotation: expiration.Format(time.RFC3339),
+					workloadv1alpha1.WorkloadIdentityAudiencesAnnotation:  audiences,
+				},
+			},
+			Type: corev1.SecretTypeOpaque,
+			Data: map[string][]byte{"token": []byte("old"), "namespace": []byte("default")},
+		}
+	}
+	foreign := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "kcp-identity-builder", Namespace: "default", ClusterName: "root:org:ws"}}
+
+	tests := map[string]struct {
+		serviceAccount *corev1.ServiceAccount
+		secret         *corev1.Secret
+		tokenErr       error
+
+		wantErr          bool
+		wantTokenRequest bool
+		wantCreated      bool
+		wantUpdated      bool
+		wantDeleted      bool
+		wantRequeue      time.Duration
+	}{
+		"not synced without secret": {
+			serviceAccount: serviceAccount(nil),
+		},
+		"not synced deletes secret": {
+			serviceAccount: serviceAccount(nil),
+			secret:         secret(now.Add(time.Hour), "kcp"),
+			wantDeleted:    true,
+		},
+		"pending state is not synced": {
+			serviceAccount: serviceAccount(map[string]string{workloadv1alpha1.InternalClusterResourceStateLabelPrefix + "us-east1": "Pending"}),
+		},
+		"synced creates secret": {
+			serviceAccount:   serviceAccount(synced),
+			wantTokenRequest: true,
+			wantCreated:      true,
+			wantRequeue:      48 * time.Minute,
+		},
+		"fresh secret is kept": {
+			serviceAccount: serviceAccount(synced),
+			secret:         secret(now.Add(30*time.Minute), "kcp"),
+			wantRequeue:    18 * time.Minute,
+		},
+		"secret about to expire is refreshed": {
+			serviceAccount:   serviceAccount(synced),
+			secret:           secret(now.Add(10*time.Minute), "kcp"),
+			wantTokenRequest: true,
+			wantUpdated:      true,
+			wantRequeue:      48 * time.Minute,
+		},
+		"secret of other audiences is refreshed": {
+			serviceAccount:   serviceAccount(synced),
+			secret:           secret(now.Add(50*time.Minute), "vault"),
+			wantTokenRequest: true,
+			wantUpdated:      true,
+			wantRequeue:      48 * time.Minute,
+		},
+		"foreign secret is not touched": {
+			serviceAccount: serviceAccount(synced),
+			secret:         foreign,
+			wantErr:        true,
+		},
+		"token request fails": {
+			serviceAccount:   serviceAccount(synced),
+			tokenErr:         errors.New("no issuer"),
+			wantTokenRequest: true,
+			wantErr:          true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var tokenRequested, deleted bool
+			var created, updated *corev1.Secret
+			var requeue time.Duration
+			c := &controller{
+				audiences:  []string{"kcp"},
+				expiration: time.Hour,
+				now:        func() time.Time { return now },
+				getSecret: func(clusterName logicalcluster.Name, namespace, name string) (*corev1.Secret, error) {
+					if tc.secret == nil || tc.secret.Name != name {
+						return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, name)
+					}
+					return tc.secret, nil
+				},
+				createSecret: func(ctx context.Context, clusterName logicalcluster.Name, secret *corev1.Secret) error {
+					created = secret
+					return nil
+				},
+				updateSecret: func(ctx context.Context, clusterName logicalcluster.Name, secret *corev1.Secret) error {
+					updated = secret
+					return nil
+				},
+				deleteSecret: func(ctx context.Context, clusterName logicalcluster.Name, namespace, name string) error {
+					deleted = true
+					return nil
+				},
+				createToken: func(ctx context.Context, clusterName logicalcluster.Name, namespace, name string, audiences []string, expiration time.Duration) (string, time.Time, error) {
+					tokenRequested = true
+					require.Equal(t, logicalcluster.New("root:org:ws"), clusterName)
+					require.Equal(t, "builder", name)
+					require.Equal(t, []string{"kcp"}, audiences)
+					if tc.tokenErr != nil {
+						return "", time.Time{}, tc.tokenErr
+					}
+					return "new", now.Add(expiration), nil
+				},
+				enqueueAfter: func(sa *corev1.ServiceAccount, duration time.Duration) {
+					requeue = duration
+				},
+			}
+
+			err := c.reconcile(context.Background(), tc.serviceAccount)
+			if tc.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tc.wantTokenRequest, tokenRequested, "token request")
+			require.Equal(t, tc.wantCreated, created != nil, "created")
+			require.Equal(t, tc.wantUpdated, updated != nil, "updated")
+			require.Equal(t, tc.wantDeleted, deleted, "deleted")
+			require.Equal(t, tc.wantRequeue, requeue, "requeue")
+
+			for _, s := range []*corev1.Secret{created, updated} {
+				if s == nil {
+					continue
+				}
+				require.Equal(t, "new", string(s.Data[workloadv1alpha1.WorkloadIdentityTokenKey]))
+				require.Equal(t, "default", string(s.Data[workloadv1alpha1.WorkloadIdentityNamespaceKey]))
+				require.Equal(t, "builder", s.Labels[workloadv1alpha1.WorkloadIdentityLabel])
+				require.Equal(t, now.Add(time.Hour).Format(time.RFC3339), s.Annotations[workloadv1alpha1.WorkloadIdentityExpirationAnnotation])
+				require.Equal(t, "kcp", s.Annotations[workloadv1alpha1.WorkloadIdentityAudiencesAnnotation])
+				require.Len(t, s.OwnerReferences, 1)
+			}
+			if updated != nil {
+				require.Equal(t, string(workloadv1alpha1.ResourceStateSync), updated.Labels[workloadv1alpha1.InternalClusterResourceStateLabelPrefix+"us-east1"], "scheduling labels must be kept")
+			}
+		})
+	}
+}
+
+func TestWorkloadIdentitySecretName(t *testing.T) {
+	require.Equal(t, "kcp-identity-default", workloadv1alpha1.WorkloadIdentitySecretName("default"))
+
+	long := workloadv1alpha1.WorkloadIdentitySecretName(strings.Repeat("a", 250))
+	require.LessOrEqual(t, len(long), 253)
+	require.NotEqual(t, long, workloadv1alpha1.WorkloadIdentitySecretName(strings.Repeat("a", 249)+"b"))
+}
@@ -38,6 +38,8 @@ import (
 	"k8s.io/client-go/tools/clusters"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
+
+	"github.com/kcp-dev/kcp/pkg/reconciler/latency"
 )
 
 const controllerName = "kcp-ingress-splitter"
@@ -56,7 +58,7 @@ func NewController(
 	aggregateLeaveStatus bool) *Controller {
 
 	c := &Controller{
-		queue:   workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName),
+		queue:   latency.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName),
 		client:  kubeClient,
 		domain:  domain,
 		tracker: newTracker(),
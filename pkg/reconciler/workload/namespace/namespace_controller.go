@@ -40,6 +40,7 @@ import (
 	workloadinformer "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/workload/v1alpha1"
 	tenancylisters "github.com/kcp-dev/kcp/pkg/client/listers/tenancy/v1alpha1"
 	workloadlisters "github.com/kcp-dev/kcp/pkg/client/listers/workload/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/reconciler/latency"
 )
 
 const controllerName = "kcp-workload-namespace"
@@ -53,9 +54,9 @@ func NewController(
 	namespaceInformer coreinformers.NamespaceInformer,
 	namespaceLister corelisters.NamespaceLister,
 ) *Controller {
-	namespaceQueue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName+"-namespace")
-	clusterQueue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName+"-cluster")
-	workspaceQueue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName+"-workspace")
+	namespaceQueue := latency.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName+"-namespace")
+	clusterQueue := latency.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName+"-cluster")
+	workspaceQueue := latency.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName+"-workspace")
 
 	workspaceLister := workspaceInformer.Lister()
 
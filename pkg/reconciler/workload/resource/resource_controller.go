@@ -46,6 +46,7 @@ import (
 	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
 	tenancyinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/tenancy/v1alpha1"
 	"github.com/kcp-dev/kcp/pkg/informer"
+	"github.com/kcp-dev/kcp/pkg/reconciler/latency"
 	"github.com/kcp-dev/kcp/pkg/reconciler/workload/namespace"
 )
 
@@ -65,8 +66,8 @@ func NewController(
 	namespaceInformer coreinformers.NamespaceInformer,
 	pollInterval time.Duration,
 ) *Controller {
-	resourceQueue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "kcp-namespace-resource")
-	gvrQueue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "kcp-namespace-gvr")
+	resourceQueue := latency.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "kcp-namespace-resource")
+	gvrQueue := latency.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "kcp-namespace-gvr")
 
 	c := &Controller{
 		resourceQueue: resourceQueue,
@@ -0,0 +1,214 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clusters"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+	kcpclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
+	workloadinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/workload/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/reconciler/latency"
+)
+
+const (
+	controllerName = "kcp-workload-usage"
+
+	byReportIndex = "byReport"
+)
+
+// NewController returns a new controller that copies the WorkspaceWorkloadUsage reports of syncers
+// from the workspaces of their WorkloadClusters into the workspaces of the workloads.
+func NewController(
+	kcpClusterClient kcpclient.ClusterInterface,
+	usageInformer workloadinformers.WorkspaceWorkloadUsageInformer,
+) (*controller, error) {
+	queue := latency.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+
+	if err := usageInformer.Informer().AddIndexers(cache.Indexers{
+		byReportIndex: indexByReport,
+	}); err != nil {
+		return nil, err
+	}
+
+	usageLister := usageInformer.Lister()
+	usageIndexer := usageInformer.Informer().GetIndexer()
+	c := &controller{
+		queue:        queue,
+		usageIndexer: usageIndexer,
+		getUsage: func(clusterName logicalcluster.Name, name string) (*workloadv1alpha1.WorkspaceWorkloadUsage, error) {
+			return usageLister.Get(clusters.ToClusterAwareKey(clusterName, name))
+		},
+		listCopies: func(reportKey string) ([]*workloadv1alpha1.WorkspaceWorkloadUsage, error) {
+			objs, err := usageIndexer.ByIndex(byReportIndex, reportKey)
+			if err != nil {
+				return nil, err
+			}
+			copies := make([]*workloadv1alpha1.WorkspaceWorkloadUsage, 0, len(objs))
+			for _, obj := range objs {
+				copies = append(copies, obj.(*workloadv1alpha1.WorkspaceWorkloadUsage))
+			}
+			return copies, nil
+		},
+		createUsage: func(ctx context.Context, clusterName logicalcluster.Name, usage *workloadv1alpha1.WorkspaceWorkloadUsage) (*workloadv1alpha1.WorkspaceWorkloadUsage, error) {
+			return kcpClusterClient.Cluster(clusterName).WorkloadV1alpha1().WorkspaceWorkloadUsages().Create(ctx, usage, metav1.CreateOptions{})
+		},
+		updateUsageStatus: func(ctx context.Context, clusterName logicalcluster.Name, usage *workloadv1alpha1.WorkspaceWorkloadUsage) error {
+			_, err := kcpClusterClient.Cluster(clusterName).WorkloadV1alpha1().WorkspaceWorkloadUsages().UpdateStatus(ctx, usage, metav1.UpdateOptions{})
+			return err
+		},
+		deleteUsage: func(ctx context.Context, clusterName logicalcluster.Name, name string) error {
+			return kcpClusterClient.Cluster(clusterName).WorkloadV1alpha1().WorkspaceWorkloadUsages().Delete(ctx, name, metav1.DeleteOptions{})
+		},
+		syncChecks: []cache.InformerSynced{
+			usageInformer.Informer().HasSynced,
+		},
+	}
+
+	usageInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueue(obj) },
+		UpdateFunc: func(_, obj interface{}) { c.enqueue(obj) },
+		DeleteFunc: func(obj interface{}) { c.enqueue(obj) },
+	})
+
+	return c, nil
+}
+
+// controller copies WorkspaceWorkloadUsage reports into the workspaces of the workloads.
+type controller struct {
+	queue workqueue.RateLimitingInterface
+
+	usageIndexer cache.Indexer
+
+	getUsage          func(clusterName logicalcluster.Name, name string) (*workloadv1alpha1.WorkspaceWorkloadUsage, error)
+	listCopies        func(reportKey string) ([]*workloadv1alpha1.WorkspaceWorkloadUsage, error)
+	createUsage       func(ctx context.Context, clusterName logicalcluster.Name, usage *workloadv1alpha1.WorkspaceWorkloadUsage) (*workloadv1alpha1.WorkspaceWorkloadUsage, error)
+	updateUsageStatus func(ctx context.Context, clusterName logicalcluster.Name, usage *workloadv1alpha1.WorkspaceWorkloadUsage) error
+	deleteUsage       func(ctx context.Context, clusterName logicalcluster.Name, name string) error
+
+	syncChecks []cache.InformerSynced
+}
+
+// indexByReport indexes copies of reports by the key of their report.
+func indexByReport(obj interface{}) ([]string, error) {
+	usage, ok := obj.(*workloadv1alpha1.WorkspaceWorkloadUsage)
+	if !ok {
+		return []string{}, fmt.Errorf("obj is supposed to be a WorkspaceWorkloadUsage, but is %T", obj)
+	}
+	if key, ok := usage.Annotations[workloadv1alpha1.WorkloadUsageReportAnnotation]; ok {
+		return []string{key}, nil
+	}
+	return []string{}, nil
+}
+
+// enqueue queues the key of the report, for reports and their copies.
+func (c *controller) enqueue(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	usage, ok := obj.(*workloadv1alpha1.WorkspaceWorkloadUsage)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("unexpected object type %T", obj))
+		return
+	}
+
+	var key string
+	if reportKey, ok := usage.Annotations[workloadv1alpha1.WorkloadUsageReportAnnotation]; ok {
+		key = reportKey
+	} else if _, ok := usage.Labels[workloadv1alpha1.WorkloadClusterLabel]; ok {
+		var err error
+		if key, err = cache.MetaNamespaceKeyFunc(usage); err != nil {
+			runtime.HandleError(err)
+			return
+		}
+	} else {
+		return
+	}
+
+	klog.V(4).Infof("Queueing WorkspaceWorkloadUsage report %q", key)
+	c.queue.Add(key)
+}
+
+// Start starts the controller, which stops when ctx.Done() is closed.
+func (c *controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	klog.Infof("Starting %s controller", controllerName)
+	defer klog.Infof("Shutting down %s controller", controllerName)
+
+	if !cache.WaitForNamedCacheSync(controllerName, ctx.Done(), c.syncChecks...) {
+		klog.Warning("Failed to wait for caches to sync")
+		return
+	}
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+
+	<-ctx.Done()
+}
+
+func (c *controller) startWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	if err := c.process(ctx, key); err != nil {
+		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", controllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *controller) process(ctx context.Context, key string) error {
+	obj, exists, err := c.usageIndexer.GetByKey(key)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return c.deleteCopies(ctx, key)
+	}
+
+	return c.reconcile(ctx, key, obj.(*workloadv1alpha1.WorkspaceWorkloadUsage))
+}
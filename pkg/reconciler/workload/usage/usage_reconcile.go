@@ -0,0 +1,87 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usage
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+)
+
+// reconcile copies the report into the workspace of the workloads, named like the WorkloadCluster.
+// Copies of other reports with the same name, i.e. of another WorkloadCluster with the same name,
+// are not overwritten.
+func (c *controller) reconcile(ctx context.Context, key string, report *workloadv1alpha1.WorkspaceWorkloadUsage) error {
+	if report.Status.Workspace == "" || report.Status.WorkloadCluster.Name == "" {
+		return nil // not reported yet
+	}
+	workspace := logicalcluster.New(report.Status.Workspace)
+	if workspace == logicalcluster.From(report) {
+		return nil // nothing to copy
+	}
+	name := report.Status.WorkloadCluster.Name
+
+	existing, err := c.getUsage(workspace, name)
+	if errors.IsNotFound(err) {
+		existing, err = c.createUsage(ctx, workspace, &workloadv1alpha1.WorkspaceWorkloadUsage{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Annotations: map[string]string{workloadv1alpha1.WorkloadUsageReportAnnotation: key},
+			},
+		})
+		if err != nil {
+			return err
+		}
+		klog.Infof("Created WorkspaceWorkloadUsage %s|%s for report %s", workspace, name, key)
+	} else if err != nil {
+		return err
+	}
+
+	if existing.Annotations[workloadv1alpha1.WorkloadUsageReportAnnotation] != key {
+		klog.Warningf("Not copying WorkspaceWorkloadUsage report %s to %s|%s: it belongs to another report", key, workspace, name)
+		return nil
+	}
+	if reflect.DeepEqual(existing.Status, report.Status) {
+		return nil
+	}
+
+	copied := existing.DeepCopy()
+	copied.Status = *report.Status.DeepCopy()
+	return c.updateUsageStatus(ctx, workspace, copied)
+}
+
+// deleteCopies deletes the copies of a deleted report.
+func (c *controller) deleteCopies(ctx context.Context, key string) error {
+	copies, err := c.listCopies(key)
+	if err != nil {
+		return err
+	}
+	for _, copied := range copies {
+		if err := c.deleteUsage(ctx, logicalcluster.From(copied), copied.Name); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		klog.Infof("Deleted WorkspaceWorkloadUsage %s|%s of deleted report %s", logicalcluster.From(copied), copied.Name, key)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+)
+
+func TestReconcile(t *testing.T) {
+	const reportKey = "root:org:clusters|east-1a2b3c4d"
+	status := workloadv1alpha1.WorkspaceWorkloadUsageStatus{
+		WorkloadCluster: workloadv1alpha1.WorkloadClusterReference{Path: "root:org:clusters", Name: "east"},
+		Workspace:       "root:org:ws",
+		Pods:            3,
+	}
+
+	tests := map[string]struct {
+		status      workloadv1alpha1.WorkspaceWorkloadUsageStatus
+		existing    *workloadv1alpha1.WorkspaceWorkloadUsage
+		wantCreated bool
+		wantStatus  *workloadv1alpha1.WorkspaceWorkloadUsageStatus
+	}{
+		"copy is created": {
+			status:      status,
+			wantCreated: true,
+			wantStatus:  &status,
+		},
+		"copy is updated": {
+			status: status,
+			existing: &workloadv1alpha1.WorkspaceWorkloadUsage{
+				ObjectMeta: metav1.ObjectMeta{Name: "east", Annotations: map[string]string{workloadv1alpha1.WorkloadUsageReportAnnotation: reportKey}},
+				Status:     workloadv1alpha1.WorkspaceWorkloadUsageStatus{Pods: 1},
+			},
+			wantStatus: &status,
+		},
+		"unchanged copy is not updated": {
+			status: status,
+			existing: &workloadv1alpha1.WorkspaceWorkloadUsage{
+				ObjectMeta: metav1.ObjectMeta{Name: "east", Annotations: map[string]string{workloadv1alpha1.WorkloadUsageReportAnnotation: reportKey}},
+				Status:     status,
+			},
+		},
+		"copy of another report is not overwritten": {
+			status: status,
+			existing: &workloadv1alpha1.WorkspaceWorkloadUsage{
+				ObjectMeta: metav1.ObjectMeta{Name: "east", Annotations: map[string]string{workloadv1alpha1.WorkloadUsageReportAnnotation: "root:other|east-1a2b3c4d"}},
+			},
+		},
+		"report without status is ignored": {},
+		"report of the own workspace is not copied": {
+			status: workloadv1alpha1.WorkspaceWorkloadUsageStatus{
+				WorkloadCluster: workloadv1alpha1.WorkloadClusterReference{Path: "root:org:clusters", Name: "east"},
+				Workspace:       "root:org:clusters",
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var created bool
+			var updated *workloadv1alpha1.WorkspaceWorkloadUsageStatus
+			c := &controller{
+				getUsage: func(clusterName logicalcluster.Name, name string) (*workloadv1alpha1.WorkspaceWorkloadUsage, error) {
+					require.Equal(t, "root:org:ws", clusterName.String())
+					require.Equal(t, "east", name)
+					if tc.existing == nil {
+						return nil, errors.NewNotFound(workloadv1alpha1.Resource("workspaceworkloadusages"), name)
+					}
+					return tc.existing, nil
+				},
+				createUsage: func(ctx context.Context, clusterName logicalcluster.Name, usage *workloadv1alpha1.WorkspaceWorkloadUsage) (*workloadv1alpha1.WorkspaceWorkloadUsage, error) {
+					require.Equal(t, "root:org:ws", clusterName.String())
+					require.Equal(t, reportKey, usage.Annotations[workloadv1alpha1.WorkloadUsageReportAnnotation])
+					created = true
+					return usage, nil
+				},
+				updateUsageStatus: func(ctx context.Context, clusterName logicalcluster.Name, usage *workloadv1alpha1.WorkspaceWorkloadUsage) error {
+					require.Equal(t, "root:org:ws", clusterName.String())
+					updated = &usage.Status
+					return nil
+				},
+			}
+
+			report := &workloadv1alpha1.WorkspaceWorkloadUsage{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "east-1a2b3c4d",
+					ClusterName: "root:org:clusters",
+					Labels:      map[string]string{workloadv1alpha1.WorkloadClusterLabel: "east"},
+				},
+				Status: tc.status,
+			}
+			require.NoError(t, c.reconcile(context.Background(), reportKey, report))
+			require.Equal(t, tc.wantCreated, created)
+			require.Equal(t, tc.wantStatus, updated)
+		})
+	}
+}
+
+func TestDeleteCopies(t *testing.T) {
+	const reportKey = "root:org:clusters|east-1a2b3c4d"
+	var deleted []string
+	c := &controller{
+		listCopies: func(key string) ([]*workloadv1alpha1.WorkspaceWorkloadUsage, error) {
+			require.Equal(t, reportKey, key)
+			return []*workloadv1alpha1.WorkspaceWorkloadUsage{
+				{ObjectMeta: metav1.ObjectMeta{Name: "east", ClusterName: "root:org:ws"}},
+			}, nil
+		},
+		deleteUsage: func(ctx context.Context, clusterName logicalcluster.Name, name string) error {
+			deleted = append(deleted, clusterName.String()+"|"+name)
+			return errors.NewNotFound(workloadv1alpha1.Resource("workspaceworkloadusages"), name)
+		},
+	}
+	require.NoError(t, c.deleteCopies(context.Background(), reportKey))
+	require.Equal(t, []string{"root:org:ws|east"}, deleted)
+}
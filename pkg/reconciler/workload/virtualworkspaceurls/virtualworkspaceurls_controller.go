@@ -40,6 +40,7 @@ import (
 	workspaceinformer "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/tenancy/v1alpha1"
 	"github.com/kcp-dev/kcp/pkg/client/informers/externalversions/workload/v1alpha1"
 	v1alpha12 "github.com/kcp-dev/kcp/pkg/client/listers/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/reconciler/latency"
 )
 
 const controllerName = "kcp-workloadcluster-controller"
@@ -51,7 +52,7 @@ func NewController(
 ) *Controller {
 
 	c := &Controller{
-		queue:                  workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName),
+		queue:                  latency.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName),
 		kcpClusterClient:       kcpClusterClient,
 		workloadClusterIndexer: workloadClusterInformer.Informer().GetIndexer(),
 		workspaceShardLister:   workspaceShardInformer.Lister(),
@@ -0,0 +1,318 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package referencedby
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/cache"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/informer"
+)
+
+// Referrer is an object referencing another object.
+type Referrer struct {
+	Cluster   string `json:"cluster"`
+	Group     string `json:"group,omitempty"`
+	Version   string `json:"version"`
+	Resource  string `json:"resource"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	// Field is the path of the reference in the referrer, e.g. metadata.ownerReferences.
+	Field string `json:"field"`
+}
+
+// target identifies a referenced object. Owner references identify their target by
+// UID, all other references by resource and name.
+type target struct {
+	cluster   logicalcluster.Name
+	group     string
+	resource  string
+	namespace string
+	name      string
+	uid       types.UID
+}
+
+// source identifies the references of an object indexed by one of the handlers.
+type source struct {
+	cluster   logicalcluster.Name
+	group     string
+	resource  string
+	namespace string
+	name      string
+	// handler tells apart the references of an object indexed by different handlers.
+	handler string
+}
+
+type edge struct {
+	target   target
+	referrer Referrer
+}
+
+const (
+	ownerReferencesHandler = "ownerReferences"
+	apiBindingsHandler     = "apiBindings"
+	apiExportsHandler      = "apiExports"
+)
+
+// Index is a reverse index of object references. It is fed by event handlers, and
+// answers which objects reference a given object.
+type Index struct {
+	lock      sync.RWMutex
+	referrers map[target]map[Referrer]struct{}
+	edges     map[source][]edge
+}
+
+// NewIndex returns an empty Index.
+func NewIndex() *Index {
+	return &Index{
+		referrers: map[target]map[Referrer]struct{}{},
+		edges:     map[source][]edge{},
+	}
+}
+
+// set replaces the references of the source.
+func (i *Index) set(src source, edges []edge) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	i.removeLocked(src)
+	if len(edges) == 0 {
+		return
+	}
+	i.edges[src] = edges
+	for _, e := range edges {
+		referrers, found := i.referrers[e.target]
+		if !found {
+			referrers = map[Referrer]struct{}{}
+			i.referrers[e.target] = referrers
+		}
+		referrers[e.referrer] = struct{}{}
+	}
+}
+
+// remove drops the references of the source.
+func (i *Index) remove(src source) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	i.removeLocked(src)
+}
+
+func (i *Index) removeLocked(src source) {
+	for _, e := range i.edges[src] {
+		referrers := i.referrers[e.target]
+		delete(referrers, e.referrer)
+		if len(referrers) == 0 {
+			delete(i.referrers, e.target)
+		}
+	}
+	delete(i.edges, src)
+}
+
+// lookup returns the referrers of the given targets, sorted.
+func (i *Index) lookup(targets ...target) []Referrer {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	seen := map[Referrer]struct{}{}
+	var ret []Referrer
+	for _, t := range targets {
+		for r := range i.referrers[t] {
+			if _, found := seen[r]; found {
+				continue
+			}
+			seen[r] = struct{}{}
+			ret = append(ret, r)
+		}
+	}
+	sortReferrers(ret)
+	return ret
+}
+
+// OwnerReferencesHandler returns a handler for a DynamicDiscoverySharedInformerFactory
+// indexing the owner references of objects.
+func (i *Index) OwnerReferencesHandler() informer.GVREventHandler {
+	return informer.GVREventHandlerFuncs{
+		AddFunc:    func(gvr schema.GroupVersionResource, obj interface{}) { i.indexOwnerReferences(gvr, obj) },
+		UpdateFunc: func(gvr schema.GroupVersionResource, _, obj interface{}) { i.indexOwnerReferences(gvr, obj) },
+		DeleteFunc: func(gvr schema.GroupVersionResource, obj interface{}) {
+			if src, _, ok := sourceOf(gvr, obj, ownerReferencesHandler); ok {
+				i.remove(src)
+			}
+		},
+	}
+}
+
+func (i *Index) indexOwnerReferences(gvr schema.GroupVersionResource, obj interface{}) {
+	src, referrer, ok := sourceOf(gvr, obj, ownerReferencesHandler)
+	if !ok {
+		return
+	}
+	metaObj, err := meta.Accessor(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+
+	referrer.Field = "metadata.ownerReferences"
+	var edges []edge
+	for _, ref := range metaObj.GetOwnerReferences() {
+		edges = append(edges, edge{
+			target:   target{cluster: src.cluster, uid: ref.UID},
+			referrer: referrer,
+		})
+	}
+	i.set(src, edges)
+}
+
+// APIBindingHandler returns a handler for an APIBinding informer indexing the APIExports
+// referenced by APIBindings.
+func (i *Index) APIBindingHandler() cache.ResourceEventHandler {
+	gvr := apisv1alpha1.SchemeGroupVersion.WithResource("apibindings")
+	return i.typedHandler(gvr, apiBindingsHandler, func(obj interface{}, referrer Referrer) []edge {
+		binding, ok := obj.(*apisv1alpha1.APIBinding)
+		if !ok || binding.Spec.Reference.Workspace == nil {
+			return nil
+		}
+		parent, hasParent := logicalcluster.From(binding).Parent()
+		if !hasParent {
+			return nil
+		}
+		referrer.Field = "spec.reference.workspace"
+		return []edge{{
+			target: target{
+				cluster:  parent.Join(binding.Spec.Reference.Workspace.WorkspaceName),
+				group:    apisv1alpha1.SchemeGroupVersion.Group,
+				resource: "apiexports",
+				name:     binding.Spec.Reference.Workspace.ExportName,
+			},
+			referrer: referrer,
+		}}
+	})
+}
+
+// APIExportHandler returns a handler for an APIExport informer indexing the
+// APIResourceSchemas, the identity secret and the documentation ConfigMap referenced by
+// APIExports.
+func (i *Index) APIExportHandler() cache.ResourceEventHandler {
+	gvr := apisv1alpha1.SchemeGroupVersion.WithResource("apiexports")
+	return i.typedHandler(gvr, apiExportsHandler, func(obj interface{}, referrer Referrer) []edge {
+		export, ok := obj.(*apisv1alpha1.APIExport)
+		if !ok {
+			return nil
+		}
+		clusterName := logicalcluster.From(export)
+
+		var edges []edge
+		add := func(field string, t target) {
+			r := referrer
+			r.Field = field
+			t.cluster = clusterName
+			edges = append(edges, edge{target: t, referrer: r})
+		}
+		for _, name := range export.Spec.LatestResourceSchemas {
+			add("spec.latestResourceSchemas", target{group: apisv1alpha1.SchemeGroupVersion.Group, resource: "apiresourceschemas", name: name})
+		}
+		if export.Spec.Identity != nil && export.Spec.Identity.SecretRef != nil && export.Spec.Identity.SecretRef.Name != "" {
+			add("spec.identity.secretRef", target{resource: "secrets", namespace: export.Spec.Identity.SecretRef.Namespace, name: export.Spec.Identity.SecretRef.Name})
+		}
+		if export.Spec.Documentation != nil {
+			add("spec.documentation", target{resource: "configmaps", namespace: export.Spec.Documentation.Namespace, name: export.Spec.Documentation.Name})
+		}
+		return edges
+	})
+}
+
+func (i *Index) typedHandler(gvr schema.GroupVersionResource, handler string, edgesOf func(obj interface{}, referrer Referrer) []edge) cache.ResourceEventHandler {
+	update := func(obj interface{}) {
+		src, referrer, ok := sourceOf(gvr, obj, handler)
+		if !ok {
+			return
+		}
+		i.set(src, edgesOf(obj, referrer))
+	}
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc:    update,
+		UpdateFunc: func(_, obj interface{}) { update(obj) },
+		DeleteFunc: func(obj interface{}) {
+			if src, _, ok := sourceOf(gvr, obj, handler); ok {
+				i.remove(src)
+			}
+		},
+	}
+}
+
+// sourceOf returns the source and referrer of an object, without field.
+func sourceOf(gvr schema.GroupVersionResource, obj interface{}, handler string) (source, Referrer, bool) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	metaObj, err := meta.Accessor(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return source{}, Referrer{}, false
+	}
+	clusterName := logicalcluster.From(metaObj)
+	src := source{
+		cluster:   clusterName,
+		group:     gvr.Group,
+		resource:  gvr.Resource,
+		namespace: metaObj.GetNamespace(),
+		name:      metaObj.GetName(),
+		handler:   handler,
+	}
+	referrer := Referrer{
+		Cluster:   clusterName.String(),
+		Group:     gvr.Group,
+		Version:   gvr.Version,
+		Resource:  gvr.Resource,
+		Namespace: metaObj.GetNamespace(),
+		Name:      metaObj.GetName(),
+	}
+	return src, referrer, true
+}
+
+func sortReferrers(referrers []Referrer) {
+	sort.Slice(referrers, func(i, j int) bool {
+		a, b := referrers[i], referrers[j]
+		if a.Cluster != b.Cluster {
+			return a.Cluster < b.Cluster
+		}
+		if a.Group != b.Group {
+			return a.Group < b.Group
+		}
+		if a.Resource != b.Resource {
+			return a.Resource < b.Resource
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Field < b.Field
+	})
+}
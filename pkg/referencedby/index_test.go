@@ -0,0 +1,121 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package referencedby
+
+import (
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+)
+
+func TestOwnerReferences(t *testing.T) {
+	index := NewIndex()
+	handler := index.OwnerReferencesHandler()
+	gvr := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "replicasets"}
+
+	rs := &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{
+		ClusterName: "root:org",
+		Namespace:   "default",
+		Name:        "web-1",
+		OwnerReferences: []metav1.OwnerReference{
+			{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", UID: "uid-web"},
+		},
+	}}
+	handler.OnAdd(gvr, rs)
+
+	owner := target{cluster: logicalcluster.New("root:org"), uid: "uid-web"}
+	require.Equal(t, []Referrer{
+		{Cluster: "root:org", Group: "apps", Version: "v1", Resource: "replicasets", Namespace: "default", Name: "web-1", Field: "metadata.ownerReferences"},
+	}, index.lookup(owner))
+	require.Empty(t, index.lookup(target{cluster: logicalcluster.New("root:other"), uid: "uid-web"}), "owner references do not cross workspaces")
+
+	updated := rs.DeepCopy()
+	updated.OwnerReferences[0].UID = "uid-other"
+	handler.OnUpdate(gvr, rs, updated)
+	require.Empty(t, index.lookup(owner), "updates replace the references")
+	require.Len(t, index.lookup(target{cluster: logicalcluster.New("root:org"), uid: "uid-other"}), 1)
+
+	handler.OnDelete(gvr, cache.DeletedFinalStateUnknown{Key: "default/web-1", Obj: updated})
+	require.Empty(t, index.lookup(target{cluster: logicalcluster.New("root:org"), uid: "uid-other"}))
+	require.Empty(t, index.referrers)
+	require.Empty(t, index.edges)
+}
+
+func TestAPIBindingHandler(t *testing.T) {
+	index := NewIndex()
+	handler := index.APIBindingHandler()
+
+	binding := &apisv1alpha1.APIBinding{
+		ObjectMeta: metav1.ObjectMeta{ClusterName: "root:org:consumer", Name: "kubernetes"},
+		Spec: apisv1alpha1.APIBindingSpec{
+			Reference: apisv1alpha1.ExportReference{
+				Workspace: &apisv1alpha1.WorkspaceExportReference{WorkspaceName: "provider", ExportName: "kubernetes"},
+			},
+		},
+	}
+	handler.OnAdd(binding)
+
+	export := target{cluster: logicalcluster.New("root:org:provider"), group: "apis.kcp.dev", resource: "apiexports", name: "kubernetes"}
+	require.Equal(t, []Referrer{
+		{Cluster: "root:org:consumer", Group: "apis.kcp.dev", Version: "v1alpha1", Resource: "apibindings", Name: "kubernetes", Field: "spec.reference.workspace"},
+	}, index.lookup(export))
+
+	handler.OnDelete(binding)
+	require.Empty(t, index.lookup(export))
+}
+
+func TestAPIExportHandler(t *testing.T) {
+	index := NewIndex()
+	handler := index.APIExportHandler()
+
+	export := &apisv1alpha1.APIExport{
+		ObjectMeta: metav1.ObjectMeta{ClusterName: "root:org:provider", Name: "kubernetes"},
+		Spec: apisv1alpha1.APIExportSpec{
+			LatestResourceSchemas: []string{"today.deployments.apps"},
+			Identity: &apisv1alpha1.Identity{
+				SecretRef: &corev1.SecretReference{Namespace: "kcp-system", Name: "kubernetes"},
+			},
+			Documentation: &apisv1alpha1.ConfigMapReference{Namespace: "docs", Name: "kubernetes"},
+		},
+	}
+	handler.OnAdd(export)
+
+	provider := logicalcluster.New("root:org:provider")
+	referrer := Referrer{Cluster: "root:org:provider", Group: "apis.kcp.dev", Version: "v1alpha1", Resource: "apiexports", Name: "kubernetes"}
+	for field, target := range map[string]target{
+		"spec.latestResourceSchemas": {cluster: provider, group: "apis.kcp.dev", resource: "apiresourceschemas", name: "today.deployments.apps"},
+		"spec.identity.secretRef":    {cluster: provider, resource: "secrets", namespace: "kcp-system", name: "kubernetes"},
+		"spec.documentation":         {cluster: provider, resource: "configmaps", namespace: "docs", name: "kubernetes"},
+	} {
+		want := referrer
+		want.Field = field
+		require.Equal(t, []Referrer{want}, index.lookup(target), field)
+	}
+
+	updated := export.DeepCopy()
+	updated.Spec.Documentation = nil
+	handler.OnUpdate(export, updated)
+	require.Empty(t, index.lookup(target{cluster: provider, resource: "configmaps", namespace: "docs", name: "kubernetes"}))
+}
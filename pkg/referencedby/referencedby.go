@@ -0,0 +1,299 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package referencedby
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/clusters"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	apisinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/references"
+)
+
+// Path is where the Server serves the referrers of an object in the workspace of the
+// request, e.g. /clusters/root:org/referenced-by?group=apis.kcp.dev&version=v1alpha1&resource=apiexports&name=today.
+const Path = "/referenced-by"
+
+// Report lists the referrers of an object.
+type Report struct {
+	Group     string `json:"group,omitempty"`
+	Version   string `json:"version"`
+	Resource  string `json:"resource"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	// Referrers are the objects referencing the object that the user is allowed to get.
+	Referrers []Referrer `json:"referrers"`
+	// HiddenReferrers is the number of objects referencing the object that the user is
+	// not allowed to get.
+	HiddenReferrers int `json:"hiddenReferrers,omitempty"`
+}
+
+// Server answers which objects reference a given object, from the owner references and
+// well-known reference fields in the Index, and from the references declared with
+// x-kcp-reference in the APIResourceSchemas of the resources bound in the workspace of
+// the object.
+type Server struct {
+	index *Index
+
+	getObject            func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, error)
+	listObjects          func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource) ([]unstructured.Unstructured, error)
+	listAPIBindings      func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error)
+	getAPIResourceSchema func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error)
+	authorizer           authorizer.Authorizer
+}
+
+// NewServer returns a Server answering from the given index. Objects are read with the
+// given client, and referrers are filtered by the given authorizer.
+func NewServer(index *Index, dynamicClusterClient dynamic.ClusterInterface, apiBindingInformer apisinformers.APIBindingInformer, apiResourceSchemaInformer apisinformers.APIResourceSchemaInformer, authz authorizer.Authorizer) *Server {
+	return &Server{
+		index: index,
+		getObject: func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, error) {
+			return dynamicClusterClient.Cluster(clusterName).Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+		},
+		listObjects: func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource) ([]unstructured.Unstructured, error) {
+			list, err := dynamicClusterClient.Cluster(clusterName).Resource(gvr).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		},
+		listAPIBindings: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
+			list, err := apiBindingInformer.Lister().List(labels.Everything())
+			if err != nil {
+				return nil, err
+			}
+			var ret []*apisv1alpha1.APIBinding
+			for _, b := range list {
+				if logicalcluster.From(b) == clusterName {
+					ret = append(ret, b)
+				}
+			}
+			return ret, nil
+		},
+		getAPIResourceSchema: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error) {
+			return apiResourceSchemaInformer.Lister().Get(clusters.ToClusterAwareKey(clusterName, name))
+		},
+		authorizer: authz,
+	}
+}
+
+// ReferencedBy returns the referrers of the given object. The user must be allowed to
+// get the object. Referrers the user is not allowed to get are only counted.
+func (s *Server) ReferencedBy(ctx context.Context, clusterName logicalcluster.Name, u user.Info, gvr schema.GroupVersionResource, namespace, name string) (*Report, error) {
+	gr := gvr.GroupResource()
+	if allowed, err := s.canGet(ctx, clusterName, u, gvr, namespace, name); err != nil {
+		return nil, err
+	} else if !allowed {
+		return nil, apierrors.NewForbidden(gr, name, fmt.Errorf("missing verb='get' permission in workspace %q", clusterName))
+	}
+
+	obj, err := s.getObject(ctx, clusterName, gvr, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	referrers := s.index.lookup(
+		target{cluster: clusterName, uid: obj.GetUID()},
+		target{cluster: clusterName, group: gr.Group, resource: gr.Resource, namespace: namespace, name: name},
+	)
+	declared, err := s.declaredReferrers(ctx, clusterName, gr, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	referrers = append(referrers, declared...)
+	sortReferrers(referrers)
+
+	report := &Report{
+		Group:     gvr.Group,
+		Version:   gvr.Version,
+		Resource:  gvr.Resource,
+		Namespace: namespace,
+		Name:      name,
+		Referrers: []Referrer{},
+	}
+	for _, r := range referrers {
+		allowed, err := s.canGet(ctx, logicalcluster.New(r.Cluster), u, schema.GroupVersionResource{Group: r.Group, Version: r.Version, Resource: r.Resource}, r.Namespace, r.Name)
+		if err != nil {
+			return nil, err
+		}
+		if allowed {
+			report.Referrers = append(report.Referrers, r)
+		} else {
+			report.HiddenReferrers++
+		}
+	}
+
+	return report, nil
+}
+
+// declaredReferrers returns the objects of the resources bound in the given workspace whose
+// APIResourceSchema declares references to the given object. The informers feeding the
+// Index only see the metadata of objects, hence declared references are resolved on demand.
+// References from other workspaces, designated with workspace or workspaceProperty, are not
+// found.
+func (s *Server) declaredReferrers(ctx context.Context, clusterName logicalcluster.Name, gr schema.GroupResource, namespace, name string) ([]Referrer, error) {
+	parent, hasParent := clusterName.Parent()
+	if !hasParent {
+		return nil, nil // APIBindings in root are not possible
+	}
+
+	bindings, err := s.listAPIBindings(clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []Referrer
+	for _, binding := range bindings {
+		if binding.Status.BoundAPIExport == nil || binding.Status.BoundAPIExport.Workspace == nil {
+			continue
+		}
+		exportClusterName := parent.Join(binding.Status.BoundAPIExport.Workspace.WorkspaceName)
+		for _, br := range binding.Status.BoundResources {
+			apiResourceSchema, err := s.getAPIResourceSchema(exportClusterName, br.Schema.Name)
+			if apierrors.IsNotFound(err) {
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+
+			for _, version := range apiResourceSchema.Spec.Versions {
+				if !version.Served {
+					continue
+				}
+				refs, err := references.Extract(version.Schema.Raw)
+				if err != nil {
+					return nil, err
+				}
+				var matching []references.Reference
+				for _, ref := range refs {
+					if ref.Target.Group == gr.Group && ref.Target.Resource == gr.Resource && ref.Target.Namespaced == (namespace != "") {
+						matching = append(matching, ref)
+					}
+				}
+				if len(matching) == 0 {
+					break
+				}
+
+				gvr := schema.GroupVersionResource{Group: br.Group, Version: version.Name, Resource: br.Resource}
+				objs, err := s.listObjects(ctx, clusterName, gvr)
+				if err != nil {
+					return nil, fmt.Errorf("failed to list %s: %w", gvr.GroupResource(), err)
+				}
+				for i := range objs {
+					obj := &objs[i]
+					if namespace != "" && obj.GetNamespace() != namespace {
+						continue
+					}
+					for j := range matching {
+						for _, v := range matching[j].Values(obj.Object) {
+							if v.Name != name || (v.Workspace != "" && v.Workspace != clusterName.String()) {
+								continue
+							}
+							ret = append(ret, Referrer{
+								Cluster:   clusterName.String(),
+								Group:     gvr.Group,
+								Version:   gvr.Version,
+								Resource:  gvr.Resource,
+								Namespace: obj.GetNamespace(),
+								Name:      obj.GetName(),
+								Field:     v.Path.String(),
+							})
+						}
+					}
+				}
+				break // the references of the first served version are used
+			}
+		}
+	}
+
+	return ret, nil
+}
+
+func (s *Server) canGet(ctx context.Context, clusterName logicalcluster.Name, u user.Info, gvr schema.GroupVersionResource, namespace, name string) (bool, error) {
+	ctx = genericapirequest.WithCluster(ctx, genericapirequest.Cluster{Name: clusterName})
+	decision, _, err := s.authorizer.Authorize(ctx, authorizer.AttributesRecord{
+		User:            u,
+		Verb:            "get",
+		APIGroup:        gvr.Group,
+		APIVersion:      gvr.Version,
+		Resource:        gvr.Resource,
+		Namespace:       namespace,
+		Name:            name,
+		ResourceRequest: true,
+	})
+	if err != nil {
+		return false, fmt.Errorf("unable to determine access to %s %s|%s: %w", gvr.GroupResource(), clusterName, name, err)
+	}
+	return decision == authorizer.DecisionAllow, nil
+}
+
+// ServeHTTP serves the referrers of the object given by the group, version, resource,
+// namespace and name query parameters in the workspace of the request.
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "only GET is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	cluster := genericapirequest.ClusterFrom(req.Context())
+	if cluster == nil || cluster.Name.Empty() || cluster.Wildcard {
+		http.Error(w, "referrers are only available for a single workspace", http.StatusBadRequest)
+		return
+	}
+	u, ok := genericapirequest.UserFrom(req.Context())
+	if !ok {
+		http.Error(w, "no user found for request", http.StatusUnauthorized)
+		return
+	}
+
+	query := req.URL.Query()
+	gvr := schema.GroupVersionResource{Group: query.Get("group"), Version: query.Get("version"), Resource: query.Get("resource")}
+	name := query.Get("name")
+	if gvr.Version == "" || gvr.Resource == "" || name == "" {
+		http.Error(w, "the version, resource and name query parameters are required", http.StatusBadRequest)
+		return
+	}
+
+	report, err := s.ReferencedBy(req.Context(), cluster.Name, u, gvr, query.Get("namespace"), name)
+	if status, ok := err.(apierrors.APIStatus); ok {
+		http.Error(w, err.Error(), int(status.Status().Code))
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(report)
+}
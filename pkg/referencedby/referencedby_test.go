@@ -0,0 +1,215 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package referencedby
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+)
+
+const widgetSchema = `{"type":"object","properties":{"spec":{"type":"object","properties":{
+	"secretRef":{"type":"string","x-kcp-reference":{"version":"v1","resource":"secrets","namespaced":true}},
+	"locationRef":{"type":"string","x-kcp-reference":{"group":"scheduling.kcp.dev","version":"v1alpha1","resource":"locations"}}
+}}}}`
+
+var secretsGVR = schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+
+func widget(namespace, name, secretRef string) unstructured.Unstructured {
+	u := unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "example.com/v1",
+		"kind":       "Widget",
+		"spec":       map[string]interface{}{"secretRef": secretRef},
+	}}
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	return u
+}
+
+func newTestServer(t *testing.T, allowed func(clusterName logicalcluster.Name, attr authorizer.Attributes) bool) *Server {
+	index := NewIndex()
+	index.OwnerReferencesHandler().OnAdd(schema.GroupVersionResource{Version: "v1", Resource: "pods"}, &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{
+		ClusterName:     "root:org:consumer",
+		Namespace:       "default",
+		Name:            "owned",
+		OwnerReferences: []metav1.OwnerReference{{APIVersion: "v1", Kind: "Secret", Name: "creds", UID: "uid-creds"}},
+	}})
+	index.APIExportHandler().OnAdd(&apisv1alpha1.APIExport{
+		ObjectMeta: metav1.ObjectMeta{ClusterName: "root:org:consumer", Name: "widgets"},
+		Spec: apisv1alpha1.APIExportSpec{
+			Identity: &apisv1alpha1.Identity{SecretRef: &corev1.SecretReference{Namespace: "default", Name: "creds"}},
+		},
+	})
+
+	return &Server{
+		index: index,
+		getObject: func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, error) {
+			if clusterName != logicalcluster.New("root:org:consumer") || gvr != secretsGVR || namespace != "default" || name != "creds" {
+				return nil, apierrors.NewNotFound(gvr.GroupResource(), name)
+			}
+			u := &unstructured.Unstructured{Object: map[string]interface{}{"apiVersion": "v1", "kind": "Secret"}}
+			u.SetNamespace(namespace)
+			u.SetName(name)
+			u.SetUID("uid-creds")
+			return u, nil
+		},
+		listObjects: func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource) ([]unstructured.Unstructured, error) {
+			require.Equal(t, schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}, gvr)
+			return []unstructured.Unstructured{
+				widget("default", "a", "creds"),
+				widget("default", "b", "other"),
+				widget("other", "c", "creds"),
+			}, nil
+		},
+		listAPIBindings: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
+			return []*apisv1alpha1.APIBinding{{
+				ObjectMeta: metav1.ObjectMeta{ClusterName: clusterName.String(), Name: "widgets"},
+				Status: apisv1alpha1.APIBindingStatus{
+					BoundAPIExport: &apisv1alpha1.ExportReference{
+						Workspace: &apisv1alpha1.WorkspaceExportReference{WorkspaceName: "provider", ExportName: "widgets"},
+					},
+					BoundResources: []apisv1alpha1.BoundAPIResource{{
+						Group:    "example.com",
+						Resource: "widgets",
+						Schema:   apisv1alpha1.BoundAPIResourceSchema{Name: "today.widgets.example.com"},
+					}},
+				},
+			}}, nil
+		},
+		getAPIResourceSchema: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error) {
+			require.Equal(t, logicalcluster.New("root:org:provider"), clusterName)
+			return &apisv1alpha1.APIResourceSchema{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Spec: apisv1alpha1.APIResourceSchemaSpec{
+					Group: "example.com",
+					Versions: []apisv1alpha1.APIResourceVersion{
+						{Name: "v2", Served: false, Schema: runtime.RawExtension{Raw: []byte(`{"type":"object"}`)}},
+						{Name: "v1", Served: true, Schema: runtime.RawExtension{Raw: []byte(widgetSchema)}},
+					},
+				},
+			}, nil
+		},
+		authorizer: authorizer.AuthorizerFunc(func(ctx context.Context, attr authorizer.Attributes) (authorizer.Decision, string, error) {
+			cluster := genericapirequest.ClusterFrom(ctx)
+			if allowed(cluster.Name, attr) {
+				return authorizer.DecisionAllow, "", nil
+			}
+			return authorizer.DecisionNoOpinion, "", nil
+		}),
+	}
+}
+
+func TestReferencedBy(t *testing.T) {
+	consumer := logicalcluster.New("root:org:consumer")
+
+	tests := map[string]struct {
+		allowed   func(clusterName logicalcluster.Name, attr authorizer.Attributes) bool
+		name      string
+		want      *Report
+		wantError string
+	}{
+		"all referrers": {
+			allowed: func(logicalcluster.Name, authorizer.Attributes) bool { return true },
+			name:    "creds",
+			want: &Report{
+				Version: "v1", Resource: "secrets", Namespace: "default", Name: "creds",
+				Referrers: []Referrer{
+					{Cluster: "root:org:consumer", Version: "v1", Resource: "pods", Namespace: "default", Name: "owned", Field: "metadata.ownerReferences"},
+					{Cluster: "root:org:consumer", Group: "apis.kcp.dev", Version: "v1alpha1", Resource: "apiexports", Name: "widgets", Field: "spec.identity.secretRef"},
+					{Cluster: "root:org:consumer", Group: "example.com", Version: "v1", Resource: "widgets", Namespace: "default", Name: "a", Field: "spec.secretRef"},
+				},
+			},
+		},
+		"referrers the user cannot get are counted": {
+			allowed: func(_ logicalcluster.Name, attr authorizer.Attributes) bool {
+				return attr.GetResource() == "secrets" || attr.GetResource() == "pods"
+			},
+			name: "creds",
+			want: &Report{
+				Version: "v1", Resource: "secrets", Namespace: "default", Name: "creds",
+				Referrers: []Referrer{
+					{Cluster: "root:org:consumer", Version: "v1", Resource: "pods", Namespace: "default", Name: "owned", Field: "metadata.ownerReferences"},
+				},
+				HiddenReferrers: 2,
+			},
+		},
+		"forbidden without access to the object": {
+			allowed:   func(logicalcluster.Name, authorizer.Attributes) bool { return false },
+			name:      "creds",
+			wantError: `secrets "creds" is forbidden: missing verb='get' permission in workspace "root:org:consumer"`,
+		},
+		"object not found": {
+			allowed:   func(logicalcluster.Name, authorizer.Attributes) bool { return true },
+			name:      "missing",
+			wantError: `secrets "missing" not found`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := newTestServer(t, tt.allowed)
+			got, err := s.ReferencedBy(context.Background(), consumer, &user.DefaultInfo{Name: "alice"}, secretsGVR, "default", tt.name)
+			if tt.wantError != "" {
+				require.EqualError(t, err, tt.wantError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	tests := map[string]struct {
+		method   string
+		query    string
+		allowed  bool
+		wantCode int
+	}{
+		"ok":               {method: http.MethodGet, query: "version=v1&resource=secrets&namespace=default&name=creds", allowed: true, wantCode: http.StatusOK},
+		"missing name":     {method: http.MethodGet, query: "version=v1&resource=secrets", allowed: true, wantCode: http.StatusBadRequest},
+		"forbidden":        {method: http.MethodGet, query: "version=v1&resource=secrets&namespace=default&name=creds", wantCode: http.StatusForbidden},
+		"not found":        {method: http.MethodGet, query: "version=v1&resource=secrets&namespace=default&name=missing", allowed: true, wantCode: http.StatusNotFound},
+		"only GET allowed": {method: http.MethodPost, query: "version=v1&resource=secrets&namespace=default&name=creds", allowed: true, wantCode: http.StatusMethodNotAllowed},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := newTestServer(t, func(logicalcluster.Name, authorizer.Attributes) bool { return tt.allowed })
+			req := httptest.NewRequest(tt.method, Path+"?"+tt.query, nil)
+			ctx := genericapirequest.WithCluster(req.Context(), genericapirequest.Cluster{Name: logicalcluster.New("root:org:consumer")})
+			ctx = genericapirequest.WithUser(ctx, &user.DefaultInfo{Name: "alice"})
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, req.WithContext(ctx))
+			require.Equal(t, tt.wantCode, rec.Code, rec.Body.String())
+		})
+	}
+}
@@ -0,0 +1,30 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package references implements the x-kcp-reference marker of APIResourceSchemas.
+//
+// API authors mark string properties of the schema of an APIResourceSchema as references
+// to other objects with
+//
+//	x-kcp-reference:
+//	  group: scheduling.kcp.dev
+//	  version: v1alpha1
+//	  resource: locations
+//
+// The references of objects of bound resources are resolved on admission in the
+// workspace of the object, or in a designated workspace, such that providers do not
+// need a webhook for simple referential integrity.
+package references
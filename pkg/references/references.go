@@ -0,0 +1,321 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package references
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// Extension is the OpenAPI extension marking a property as a reference to another object.
+const Extension = "x-kcp-reference"
+
+// rootProperties are the properties of the root of the schema that cannot be marked.
+var rootProperties = sets.NewString("apiVersion", "kind", "metadata")
+
+var reClusterName = regexp.MustCompile(`^([a-z]([a-z0-9-]{0,61}[a-z0-9])?:)*[a-z]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// Target is the value of the extension, describing the referenced objects.
+type Target struct {
+	// Group is the API group of the referenced resource. Empty for the core group.
+	Group string `json:"group,omitempty"`
+	// Version is a served version of the referenced resource, used to look up the object.
+	Version string `json:"version"`
+	// Resource is the plural resource name of the referenced objects.
+	Resource string `json:"resource"`
+	// Namespaced is whether the referenced object lives in the namespace of the referencing
+	// object. Otherwise, the referenced resource is cluster scoped.
+	Namespaced bool `json:"namespaced,omitempty"`
+	// Workspace is the logical cluster the referenced objects live in, e.g. root:org:catalog.
+	// Defaults to the workspace of the referencing object.
+	Workspace string `json:"workspace,omitempty"`
+	// WorkspaceProperty is the name of a string property of the object containing the
+	// reference, which holds the logical cluster the referenced object lives in. If the
+	// property is empty, the workspace of the referencing object is used.
+	WorkspaceProperty string `json:"workspaceProperty,omitempty"`
+	// Verb is the verb the user creating or updating the referencing object must be allowed
+	// on the referenced object, e.g. bind. Defaults to get.
+	Verb string `json:"verb,omitempty"`
+}
+
+// Step is an element of the path of a reference in an object.
+type Step struct {
+	// Property is the name of the property of an object to descend into.
+	Property string
+	// Items is true when descending into every item of a list.
+	Items bool
+}
+
+// Reference is a marked property of a schema.
+type Reference struct {
+	// Path leads from the root of an object to the referencing values.
+	Path []Step
+	// Target describes the referenced objects.
+	Target Target
+}
+
+// Value is a reference found in an object.
+type Value struct {
+	// Name is the name of the referenced object.
+	Name string
+	// Workspace is the logical cluster of the referenced object, or empty for the
+	// workspace of the referencing object.
+	Workspace string
+	// Path is the field path of the reference in the object.
+	Path *field.Path
+}
+
+// Validate checks the markers in the given JSON schema. Only string properties, and string
+// items of lists, can be marked, not below additionalProperties, and not in the value
+// validations of allOf, anyOf, oneOf and not.
+func Validate(raw []byte, fldPath *field.Path) field.ErrorList {
+	var schema map[string]interface{}
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		return field.ErrorList{field.Invalid(fldPath, string(raw), fmt.Sprintf("invalid JSON: %v", err))}
+	}
+	if _, found := schema[Extension]; found {
+		return field.ErrorList{field.Forbidden(fldPath.Child(Extension), "cannot mark the whole object as a reference")}
+	}
+	return validate(schema, schema, fldPath, true, true)
+}
+
+// validate checks the markers below the given schema. parent is the innermost object schema
+// containing it, which holds the properties a workspaceProperty can name.
+func validate(schema, parent map[string]interface{}, fldPath *field.Path, allowed, root bool) field.ErrorList {
+	var allErrs field.ErrorList
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	for _, name := range sortedKeys(properties) {
+		prop, ok := properties[name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		propPath := fldPath.Child("properties").Key(name)
+		if root && rootProperties.Has(name) {
+			if _, found := prop[Extension]; found {
+				allErrs = append(allErrs, field.Forbidden(propPath.Child(Extension), fmt.Sprintf("cannot be set on %s", name)))
+				continue
+			}
+		}
+		allErrs = append(allErrs, validateMarker(prop, schema, propPath, allowed)...)
+		allErrs = append(allErrs, validate(prop, schema, propPath, allowed, false)...)
+	}
+
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		allErrs = append(allErrs, validateMarker(items, parent, fldPath.Child("items"), allowed)...)
+		allErrs = append(allErrs, validate(items, parent, fldPath.Child("items"), allowed, false)...)
+	}
+	for _, key := range []string{"additionalProperties", "not"} {
+		if sub, ok := schema[key].(map[string]interface{}); ok {
+			allErrs = append(allErrs, validateMarker(sub, parent, fldPath.Child(key), false)...)
+			allErrs = append(allErrs, validate(sub, parent, fldPath.Child(key), false, false)...)
+		}
+	}
+	for _, key := range []string{"allOf", "anyOf", "oneOf"} {
+		subs, _ := schema[key].([]interface{})
+		for i, s := range subs {
+			if sub, ok := s.(map[string]interface{}); ok {
+				allErrs = append(allErrs, validateMarker(sub, parent, fldPath.Child(key).Index(i), false)...)
+				allErrs = append(allErrs, validate(sub, parent, fldPath.Child(key).Index(i), false, false)...)
+			}
+		}
+	}
+
+	return allErrs
+}
+
+func validateMarker(schema, parent map[string]interface{}, fldPath *field.Path, allowed bool) field.ErrorList {
+	v, found := schema[Extension]
+	if !found {
+		return nil
+	}
+	extPath := fldPath.Child(Extension)
+	if !allowed {
+		return field.ErrorList{field.Forbidden(extPath, "can only be set on properties and items of lists, not below additionalProperties or in allOf, anyOf, oneOf and not")}
+	}
+	if t, _ := schema["type"].(string); t != "string" {
+		return field.ErrorList{field.Forbidden(extPath, "can only be set on properties of type string")}
+	}
+
+	target, err := parseTarget(v)
+	if err != nil {
+		return field.ErrorList{field.Invalid(extPath, v, err.Error())}
+	}
+	allErrs := validateTarget(target, extPath)
+
+	if target.WorkspaceProperty != "" {
+		properties, _ := parent["properties"].(map[string]interface{})
+		prop, _ := properties[target.WorkspaceProperty].(map[string]interface{})
+		if t, _ := prop["type"].(string); t != "string" {
+			allErrs = append(allErrs, field.Invalid(extPath.Child("workspaceProperty"), target.WorkspaceProperty, "must name a string property of the object containing the reference"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateTarget(target *Target, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if target.Group != "" {
+		for _, msg := range validation.IsDNS1123Subdomain(target.Group) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("group"), target.Group, msg))
+		}
+	}
+	if target.Version == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("version"), ""))
+	} else {
+		for _, msg := range validation.IsDNS1035Label(target.Version) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("version"), target.Version, msg))
+		}
+	}
+	if target.Resource == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("resource"), ""))
+	} else {
+		for _, msg := range validation.IsDNS1035Label(target.Resource) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("resource"), target.Resource, msg))
+		}
+	}
+	if target.Workspace != "" && target.WorkspaceProperty != "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("workspaceProperty"), "cannot be set together with workspace"))
+	}
+	if target.Workspace != "" && !reClusterName.MatchString(target.Workspace) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("workspace"), target.Workspace, "must be a logical cluster name like root:org:ws"))
+	}
+	if strings.ContainsAny(target.Verb, " \t\n") {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("verb"), target.Verb, "must not contain whitespace"))
+	}
+
+	return allErrs
+}
+
+func parseTarget(v interface{}) (*Target, error) {
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(bs))
+	decoder.DisallowUnknownFields()
+	var target Target
+	if err := decoder.Decode(&target); err != nil {
+		return nil, fmt.Errorf("must be an object with group, version, resource, namespaced, workspace, workspaceProperty and verb: %v", err)
+	}
+	return &target, nil
+}
+
+// Extract returns the references marked in the given JSON schema. Invalid markers, which are
+// rejected on creation of APIResourceSchemas, are ignored.
+func Extract(raw []byte) ([]Reference, error) {
+	var schema map[string]interface{}
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		return nil, err
+	}
+	var refs []Reference
+	extract(schema, nil, true, &refs)
+	return refs, nil
+}
+
+func extract(schema map[string]interface{}, path []Step, root bool, refs *[]Reference) {
+	properties, _ := schema["properties"].(map[string]interface{})
+	for _, name := range sortedKeys(properties) {
+		prop, ok := properties[name].(map[string]interface{})
+		if !ok || (root && rootProperties.Has(name)) {
+			continue
+		}
+		propPath := append(append([]Step(nil), path...), Step{Property: name})
+		extractMarker(prop, propPath, refs)
+		extract(prop, propPath, false, refs)
+	}
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		itemsPath := append(append([]Step(nil), path...), Step{Items: true})
+		extractMarker(items, itemsPath, refs)
+		extract(items, itemsPath, false, refs)
+	}
+}
+
+func extractMarker(schema map[string]interface{}, path []Step, refs *[]Reference) {
+	v, found := schema[Extension]
+	if !found {
+		return
+	}
+	if t, _ := schema["type"].(string); t != "string" {
+		return
+	}
+	target, err := parseTarget(v)
+	if err != nil || len(validateTarget(target, field.NewPath(Extension))) > 0 {
+		return
+	}
+	*refs = append(*refs, Reference{Path: path, Target: *target})
+}
+
+// Values returns the non-empty values of the reference in the given object.
+func (r *Reference) Values(obj map[string]interface{}) []Value {
+	var values []Value
+	r.collect(obj, r.Path, nil, nil, &values)
+	return values
+}
+
+// collect descends into v along the given steps. parent is the innermost object containing v.
+func (r *Reference) collect(v interface{}, steps []Step, parent map[string]interface{}, fldPath *field.Path, values *[]Value) {
+	if len(steps) == 0 {
+		name, ok := v.(string)
+		if !ok || name == "" {
+			return
+		}
+		workspace := r.Target.Workspace
+		if r.Target.WorkspaceProperty != "" {
+			workspace, _ = parent[r.Target.WorkspaceProperty].(string)
+		}
+		*values = append(*values, Value{Name: name, Workspace: workspace, Path: fldPath})
+		return
+	}
+
+	step := steps[0]
+	if step.Items {
+		items, _ := v.([]interface{})
+		for i, item := range items {
+			r.collect(item, steps[1:], parent, fldPath.Index(i), values)
+		}
+		return
+	}
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return
+	}
+	childPath := field.NewPath(step.Property)
+	if fldPath != nil {
+		childPath = fldPath.Child(step.Property)
+	}
+	r.collect(obj[step.Property], steps[1:], obj, childPath, values)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package references
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestValidate(t *testing.T) {
+	tests := map[string]struct {
+		schema  string
+		wantErr []string
+	}{
+		"no markers": {
+			schema: `{"type":"object","properties":{"spec":{"type":"object"}}}`,
+		},
+		"properties and items": {
+			schema: `{"type":"object","properties":{"spec":{"type":"object","properties":{
+				"locationRef":{"type":"string","x-kcp-reference":{"group":"scheduling.kcp.dev","version":"v1alpha1","resource":"locations"}},
+				"secretRefs":{"type":"array","items":{"type":"string","x-kcp-reference":{"version":"v1","resource":"secrets","namespaced":true}}},
+				"exportRef":{"type":"object","properties":{
+					"path":{"type":"string"},
+					"name":{"type":"string","x-kcp-reference":{"group":"apis.kcp.dev","version":"v1alpha1","resource":"apiexports","workspaceProperty":"path","verb":"bind"}}
+				}},
+				"catalogRef":{"type":"string","x-kcp-reference":{"group":"catalog.example.com","version":"v1","resource":"entries","workspace":"root:catalog"}}
+			}}}}`,
+		},
+		"whole object": {
+			schema:  `{"type":"object","x-kcp-reference":{"version":"v1","resource":"secrets"}}`,
+			wantErr: []string{`schema.x-kcp-reference: Forbidden: cannot mark the whole object as a reference`},
+		},
+		"metadata": {
+			schema:  `{"type":"object","properties":{"metadata":{"type":"string","x-kcp-reference":{"version":"v1","resource":"secrets"}}}}`,
+			wantErr: []string{`schema.properties[metadata].x-kcp-reference: Forbidden: cannot be set on metadata`},
+		},
+		"not a string": {
+			schema:  `{"type":"object","properties":{"spec":{"type":"object","x-kcp-reference":{"version":"v1","resource":"secrets"}}}}`,
+			wantErr: []string{`schema.properties[spec].x-kcp-reference: Forbidden: can only be set on properties of type string`},
+		},
+		"not an object": {
+			schema:  `{"type":"object","properties":{"ref":{"type":"string","x-kcp-reference":"secrets"}}}`,
+			wantErr: []string{`schema.properties[ref].x-kcp-reference: Invalid value: "secrets": must be an object with group, version, resource, namespaced, workspace, workspaceProperty and verb: json: cannot unmarshal string into Go value of type references.Target`},
+		},
+		"missing version and resource": {
+			schema: `{"type":"object","properties":{"ref":{"type":"string","x-kcp-reference":{"group":"example.com"}}}}`,
+			wantErr: []string{
+				`schema.properties[ref].x-kcp-reference.version: Required value`,
+				`schema.properties[ref].x-kcp-reference.resource: Required value`,
+			},
+		},
+		"invalid workspace": {
+			schema:  `{"type":"object","properties":{"ref":{"type":"string","x-kcp-reference":{"version":"v1","resource":"secrets","workspace":"root:"}}}}`,
+			wantErr: []string{`schema.properties[ref].x-kcp-reference.workspace: Invalid value: "root:": must be a logical cluster name like root:org:ws`},
+		},
+		"workspace and workspaceProperty": {
+			schema:  `{"type":"object","properties":{"ws":{"type":"string"},"ref":{"type":"string","x-kcp-reference":{"version":"v1","resource":"secrets","workspace":"root","workspaceProperty":"ws"}}}}`,
+			wantErr: []string{`schema.properties[ref].x-kcp-reference.workspaceProperty: Forbidden: cannot be set together with workspace`},
+		},
+		"unknown workspaceProperty": {
+			schema:  `{"type":"object","properties":{"ref":{"type":"string","x-kcp-reference":{"version":"v1","resource":"secrets","workspaceProperty":"ws"}}}}`,
+			wantErr: []string{`schema.properties[ref].x-kcp-reference.workspaceProperty: Invalid value: "ws": must name a string property of the object containing the reference`},
+		},
+		"verb with whitespace": {
+			schema:  `{"type":"object","properties":{"ref":{"type":"string","x-kcp-reference":{"version":"v1","resource":"secrets","verb":"get list"}}}}`,
+			wantErr: []string{`schema.properties[ref].x-kcp-reference.verb: Invalid value: "get list": must not contain whitespace`},
+		},
+		"additional properties": {
+			schema:  `{"type":"object","properties":{"spec":{"type":"object","additionalProperties":{"type":"string","x-kcp-reference":{"version":"v1","resource":"secrets"}}}}}`,
+			wantErr: []string{`schema.properties[spec].additionalProperties.x-kcp-reference: Forbidden: can only be set on properties and items of lists, not below additionalProperties or in allOf, anyOf, oneOf and not`},
+		},
+		"invalid JSON": {
+			schema:  `{`,
+			wantErr: []string{`schema: Invalid value: "{": invalid JSON: unexpected end of JSON input`},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			errs := Validate([]byte(tt.schema), field.NewPath("schema"))
+			var got []string
+			for _, err := range errs {
+				got = append(got, err.Error())
+			}
+			require.Equal(t, tt.wantErr, got)
+		})
+	}
+}
+
+func TestExtract(t *testing.T) {
+	schema := `{"type":"object","properties":{
+		"metadata":{"type":"object"},
+		"spec":{"type":"object","properties":{
+			"locationRef":{"type":"string","x-kcp-reference":{"group":"scheduling.kcp.dev","version":"v1alpha1","resource":"locations"}},
+			"secretRefs":{"type":"array","items":{"type":"string","x-kcp-reference":{"version":"v1","resource":"secrets","namespaced":true}}},
+			"invalid":{"type":"string","x-kcp-reference":{"resource":"secrets"}}
+		}}
+	}}`
+
+	refs, err := Extract([]byte(schema))
+	require.NoError(t, err)
+	require.Equal(t, []Reference{
+		{
+			Path:   []Step{{Property: "spec"}, {Property: "locationRef"}},
+			Target: Target{Group: "scheduling.kcp.dev", Version: "v1alpha1", Resource: "locations"},
+		},
+		{
+			Path:   []Step{{Property: "spec"}, {Property: "secretRefs"}, {Items: true}},
+			Target: Target{Version: "v1", Resource: "secrets", Namespaced: true},
+		},
+	}, refs)
+}
+
+func TestValues(t *testing.T) {
+	tests := map[string]struct {
+		ref    Reference
+		obj    string
+		wanted []Value
+	}{
+		"property": {
+			ref: Reference{Path: []Step{{Property: "spec"}, {Property: "locationRef"}}},
+			obj: `{"spec":{"locationRef":"us-east"}}`,
+			wanted: []Value{
+				{Name: "us-east", Path: field.NewPath("spec", "locationRef")},
+			},
+		},
+		"empty or missing": {
+			ref: Reference{Path: []Step{{Property: "spec"}, {Property: "locationRef"}}},
+			obj: `{"spec":{"locationRef":""}}`,
+		},
+		"wrong type": {
+			ref: Reference{Path: []Step{{Property: "spec"}, {Property: "locationRef"}}},
+			obj: `{"spec":{"locationRef":42}}`,
+		},
+		"items": {
+			ref: Reference{Path: []Step{{Property: "spec"}, {Property: "secretRefs"}, {Items: true}}},
+			obj: `{"spec":{"secretRefs":["a","","b"]}}`,
+			wanted: []Value{
+				{Name: "a", Path: field.NewPath("spec", "secretRefs").Index(0)},
+				{Name: "b", Path: field.NewPath("spec", "secretRefs").Index(2)},
+			},
+		},
+		"fixed workspace": {
+			ref: Reference{Path: []Step{{Property: "catalogRef"}}, Target: Target{Workspace: "root:catalog"}},
+			obj: `{"catalogRef":"db"}`,
+			wanted: []Value{
+				{Name: "db", Workspace: "root:catalog", Path: field.NewPath("catalogRef")},
+			},
+		},
+		"workspace property": {
+			ref: Reference{
+				Path:   []Step{{Property: "spec"}, {Property: "exports"}, {Items: true}, {Property: "name"}},
+				Target: Target{WorkspaceProperty: "path"},
+			},
+			obj: `{"spec":{"exports":[{"path":"root:org","name":"kubernetes"},{"name":"local"}]}}`,
+			wanted: []Value{
+				{Name: "kubernetes", Workspace: "root:org", Path: field.NewPath("spec", "exports").Index(0).Child("name")},
+				{Name: "local", Path: field.NewPath("spec", "exports").Index(1).Child("name")},
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var obj map[string]interface{}
+			require.NoError(t, json.Unmarshal([]byte(tt.obj), &obj))
+			require.Equal(t, tt.wanted, tt.ref.Values(obj))
+		})
+	}
+}
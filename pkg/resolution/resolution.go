@@ -0,0 +1,200 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resolution
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/tools/clusters"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	tenancyinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/tenancy/v1alpha1"
+)
+
+// Path is where the Resolver serves workspace resolutions.
+const Path = "/workspace-resolution"
+
+var reClusterName = regexp.MustCompile(`^([a-z]([a-z0-9-]{0,61}[a-z0-9])?:)*[a-z]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// Resolution describes where a workspace lives.
+type Resolution struct {
+	// Path is the canonical path of the workspace, e.g. root:org:team.
+	Path string `json:"path"`
+	// ClusterName is the logical cluster name of the workspace.
+	ClusterName string `json:"clusterName"`
+	// Shard is the ClusterWorkspaceShard the workspace is scheduled to. It
+	// is empty for the root workspace and for unscheduled workspaces.
+	Shard string `json:"shard,omitempty"`
+	// Phase is the phase of the workspace.
+	Phase tenancyv1alpha1.ClusterWorkspacePhaseType `json:"phase"`
+	// URL is the base URL of the workspace. It is empty for unscheduled
+	// workspaces.
+	URL string `json:"url,omitempty"`
+}
+
+// Resolver resolves workspace paths and logical cluster names to Resolutions
+// in one request, instead of clients walking the workspace hierarchy.
+type Resolver struct {
+	getClusterWorkspace func(parent logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error)
+	externalAddress     func() string
+	authorizer          authorizer.Authorizer
+}
+
+// NewResolver returns a Resolver looking up ClusterWorkspaces in the informer. A
+// workspace is only resolved for users allowed to get its ClusterWorkspace, or to
+// access it.
+func NewResolver(workspaceInformer tenancyinformers.ClusterWorkspaceInformer, authz authorizer.Authorizer, externalAddress func() string) *Resolver {
+	return &Resolver{
+		getClusterWorkspace: func(parent logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error) {
+			return workspaceInformer.Lister().Get(clusters.ToClusterAwareKey(parent, name))
+		},
+		externalAddress: externalAddress,
+		authorizer:      authz,
+	}
+}
+
+// Canonical returns the canonical path of a workspace path or logical cluster name.
+// Like for /clusters/<name> URLs, names not starting with "root" are taken
+// relative to the root workspace. Logical cluster names are canonical paths of
+// their workspace.
+func Canonical(name string) (logicalcluster.Name, error) {
+	if !reClusterName.MatchString(name) {
+		return logicalcluster.Name{}, fmt.Errorf("invalid workspace path %q", name)
+	}
+	if name != tenancyv1alpha1.RootCluster.String() && !strings.HasPrefix(name, tenancyv1alpha1.RootCluster.String()+":") {
+		name = tenancyv1alpha1.RootCluster.Join(name).String()
+	}
+	return logicalcluster.New(name), nil
+}
+
+// Resolve returns the Resolution of the workspace with the given canonical path.
+// It returns a NotFound error if the workspace does not exist or if the user is
+// not allowed to see it.
+func (r *Resolver) Resolve(ctx context.Context, path logicalcluster.Name, u user.Info) (*Resolution, error) {
+	if path == tenancyv1alpha1.RootCluster {
+		return &Resolution{
+			Path:        path.String(),
+			ClusterName: path.String(),
+			Phase:       tenancyv1alpha1.ClusterWorkspacePhaseReady,
+			URL:         strings.TrimSuffix(r.externalAddress(), "/") + path.Path(),
+		}, nil
+	}
+
+	notFound := apierrors.NewNotFound(tenancyv1alpha1.Resource("workspaces"), path.String())
+	parent, name := path.Split()
+	workspace, err := r.getClusterWorkspace(parent, name)
+	if apierrors.IsNotFound(err) {
+		return nil, notFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	allowed, err := r.canSee(ctx, parent, name, u)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		// don't reveal the existence of the workspace
+		return nil, notFound
+	}
+
+	return &Resolution{
+		Path:        path.String(),
+		ClusterName: parent.Join(workspace.Name).String(),
+		Shard:       workspace.Status.Location.Current,
+		Phase:       workspace.Status.Phase,
+		URL:         workspace.Status.BaseURL,
+	}, nil
+}
+
+// canSee checks that the user could get the ClusterWorkspace in the parent, or
+// has access to its content.
+func (r *Resolver) canSee(ctx context.Context, parent logicalcluster.Name, name string, u user.Info) (bool, error) {
+	ctx = genericapirequest.WithCluster(ctx, genericapirequest.Cluster{Name: parent})
+	for _, attr := range []authorizer.AttributesRecord{
+		{Verb: "get", Resource: "clusterworkspaces"},
+		{Verb: "access", Resource: "clusterworkspaces", Subresource: "content"},
+	} {
+		attr.User = u
+		attr.APIGroup = tenancyv1alpha1.SchemeGroupVersion.Group
+		attr.APIVersion = tenancyv1alpha1.SchemeGroupVersion.Version
+		attr.Name = name
+		attr.ResourceRequest = true
+		decision, _, err := r.authorizer.Authorize(ctx, attr)
+		if err != nil {
+			return false, fmt.Errorf("unable to determine access to workspace %s|%s: %w", parent, name, err)
+		}
+		if decision == authorizer.DecisionAllow {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ServeHTTP resolves the workspace given by the "path" query parameter, or by the
+// "cluster" query parameter for reverse resolution of a logical cluster name.
+func (r *Resolver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	u, ok := genericapirequest.UserFrom(req.Context())
+	if !ok {
+		http.Error(w, "no user found for request", http.StatusUnauthorized)
+		return
+	}
+
+	query := req.URL.Query()
+	name := query.Get("path")
+	if cluster := query.Get("cluster"); cluster != "" {
+		if name != "" {
+			http.Error(w, "only one of the path and cluster parameters can be given", http.StatusBadRequest)
+			return
+		}
+		name = cluster
+	}
+	if name == "" {
+		http.Error(w, "one of the path and cluster parameters is required", http.StatusBadRequest)
+		return
+	}
+	path, err := Canonical(name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resolution, err := r.Resolve(req.Context(), path, u)
+	if apierrors.IsNotFound(err) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(resolution)
+}
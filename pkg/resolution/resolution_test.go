@@ -0,0 +1,164 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resolution
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+func TestCanonical(t *testing.T) {
+	tests := map[string]struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		"root":                    {name: "root", want: "root"},
+		"canonical path":          {name: "root:org:team", want: "root:org:team"},
+		"relative to root":        {name: "org:team", want: "root:org:team"},
+		"root prefix of a name":   {name: "rooted:team", want: "root:rooted:team"},
+		"invalid characters":      {name: "root:Org", wantErr: true},
+		"empty segment":           {name: "root::team", wantErr: true},
+		"trailing separator":      {name: "root:org:", wantErr: true},
+		"wildcard is not allowed": {name: "*", wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := Canonical(tt.name)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got.String())
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	workspaces := map[string]*tenancyv1alpha1.ClusterWorkspace{
+		"root|org": {
+			ObjectMeta: metav1.ObjectMeta{Name: "org", ClusterName: "root"},
+			Status: tenancyv1alpha1.ClusterWorkspaceStatus{
+				Phase:    tenancyv1alpha1.ClusterWorkspacePhaseReady,
+				BaseURL:  "https://shard-1.example.com/clusters/root:org",
+				Location: tenancyv1alpha1.ClusterWorkspaceLocation{Current: "shard-1"},
+			},
+		},
+		"root:org|team": {
+			ObjectMeta: metav1.ObjectMeta{Name: "team", ClusterName: "root:org"},
+			Status: tenancyv1alpha1.ClusterWorkspaceStatus{
+				Phase:    tenancyv1alpha1.ClusterWorkspacePhaseInitializing,
+				BaseURL:  "https://shard-2.example.com/clusters/root:org:team",
+				Location: tenancyv1alpha1.ClusterWorkspaceLocation{Current: "shard-2"},
+			},
+		},
+		"root:org|scheduling": {
+			ObjectMeta: metav1.ObjectMeta{Name: "scheduling", ClusterName: "root:org"},
+			Status:     tenancyv1alpha1.ClusterWorkspaceStatus{Phase: tenancyv1alpha1.ClusterWorkspacePhaseScheduling},
+		},
+	}
+
+	r := &Resolver{
+		getClusterWorkspace: func(parent logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error) {
+			if ws, ok := workspaces[parent.String()+"|"+name]; ok {
+				return ws, nil
+			}
+			return nil, apierrors.NewNotFound(tenancyv1alpha1.Resource("clusterworkspaces"), name)
+		},
+		externalAddress: func() string { return "https://kcp.example.com/" },
+		authorizer: authorizer.AuthorizerFunc(func(ctx context.Context, attr authorizer.Attributes) (authorizer.Decision, string, error) {
+			cluster := genericapirequest.ClusterFrom(ctx)
+			require.NotNil(t, cluster)
+			require.Equal(t, tenancyv1alpha1.SchemeGroupVersion.Group, attr.GetAPIGroup())
+			require.Equal(t, "clusterworkspaces", attr.GetResource())
+			switch {
+			case attr.GetUser().GetName() == "admin" && attr.GetVerb() == "get":
+				return authorizer.DecisionAllow, "", nil
+			case attr.GetUser().GetName() == "member" && attr.GetVerb() == "access" && attr.GetSubresource() == "content" &&
+				cluster.Name.String() == "root:org" && attr.GetName() == "team":
+				return authorizer.DecisionAllow, "", nil
+			}
+			return authorizer.DecisionNoOpinion, "", nil
+		}),
+	}
+
+	tests := map[string]struct {
+		path         string
+		user         string
+		want         *Resolution
+		wantNotFound bool
+	}{
+		"root": {
+			path: "root",
+			user: "member",
+			want: &Resolution{Path: "root", ClusterName: "root", Phase: tenancyv1alpha1.ClusterWorkspacePhaseReady, URL: "https://kcp.example.com/clusters/root"},
+		},
+		"organization": {
+			path: "root:org",
+			user: "admin",
+			want: &Resolution{Path: "root:org", ClusterName: "root:org", Shard: "shard-1", Phase: tenancyv1alpha1.ClusterWorkspacePhaseReady, URL: "https://shard-1.example.com/clusters/root:org"},
+		},
+		"nested workspace with access to its content": {
+			path: "root:org:team",
+			user: "member",
+			want: &Resolution{Path: "root:org:team", ClusterName: "root:org:team", Shard: "shard-2", Phase: tenancyv1alpha1.ClusterWorkspacePhaseInitializing, URL: "https://shard-2.example.com/clusters/root:org:team"},
+		},
+		"unscheduled workspace": {
+			path: "root:org:scheduling",
+			user: "admin",
+			want: &Resolution{Path: "root:org:scheduling", ClusterName: "root:org:scheduling", Phase: tenancyv1alpha1.ClusterWorkspacePhaseScheduling},
+		},
+		"forbidden looks like not found": {
+			path:         "root:org",
+			user:         "member",
+			wantNotFound: true,
+		},
+		"not found": {
+			path:         "root:org:unknown",
+			user:         "admin",
+			wantNotFound: true,
+		},
+		"parent not found": {
+			path:         "root:unknown:team",
+			user:         "admin",
+			wantNotFound: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := r.Resolve(context.Background(), logicalcluster.New(tt.path), &user.DefaultInfo{Name: tt.user})
+			if tt.wantNotFound {
+				require.True(t, apierrors.IsNotFound(err), "expected NotFound, got %v", err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
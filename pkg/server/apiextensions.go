@@ -76,6 +76,14 @@ func newSystemCRDProvider(
 			clusters.ToClusterAwareKey(SystemCRDLogicalCluster, "clusterworkspaces.tenancy.kcp.dev"),
 			clusters.ToClusterAwareKey(SystemCRDLogicalCluster, "clusterworkspacetypes.tenancy.kcp.dev"),
 			clusters.ToClusterAwareKey(SystemCRDLogicalCluster, "clusterworkspaceshards.tenancy.kcp.dev"),
+			clusters.ToClusterAwareKey(SystemCRDLogicalCluster, "accessgrants.tenancy.kcp.dev"),
+			clusters.ToClusterAwareKey(SystemCRDLogicalCluster, "denypolicies.tenancy.kcp.dev"),
+			clusters.ToClusterAwareKey(SystemCRDLogicalCluster, "replications.tenancy.kcp.dev"),
+			clusters.ToClusterAwareKey(SystemCRDLogicalCluster, "bulkworkspaceoperations.tenancy.kcp.dev"),
+			clusters.ToClusterAwareKey(SystemCRDLogicalCluster, "policybundles.tenancy.kcp.dev"),
+			clusters.ToClusterAwareKey(SystemCRDLogicalCluster, "homeworkspacepolicies.tenancy.kcp.dev"),
+			clusters.ToClusterAwareKey(SystemCRDLogicalCluster, "virtualworkspaces.tenancy.kcp.dev"),
+			clusters.ToClusterAwareKey(SystemCRDLogicalCluster, "dnszones.workload.kcp.dev"),
 
 			// the following is installed to get discovery and OpenAPI right. But it is actually
 			// served by a native rest storage, projecting the clusterworkspaces.
@@ -84,6 +92,12 @@ func newSystemCRDProvider(
 		orgCRDs: sets.NewString(
 			clusters.ToClusterAwareKey(SystemCRDLogicalCluster, "clusterworkspaces.tenancy.kcp.dev"),
 			clusters.ToClusterAwareKey(SystemCRDLogicalCluster, "clusterworkspacetypes.tenancy.kcp.dev"),
+			clusters.ToClusterAwareKey(SystemCRDLogicalCluster, "accessgrants.tenancy.kcp.dev"),
+			clusters.ToClusterAwareKey(SystemCRDLogicalCluster, "denypolicies.tenancy.kcp.dev"),
+			clusters.ToClusterAwareKey(SystemCRDLogicalCluster, "replications.tenancy.kcp.dev"),
+			clusters.ToClusterAwareKey(SystemCRDLogicalCluster, "bulkworkspaceoperations.tenancy.kcp.dev"),
+			clusters.ToClusterAwareKey(SystemCRDLogicalCluster, "policybundles.tenancy.kcp.dev"),
+			clusters.ToClusterAwareKey(SystemCRDLogicalCluster, "objecttransfers.tenancy.kcp.dev"),
 
 			// the following is installed to get discovery and OpenAPI right. But it is actually
 			// served by a native rest storage, projecting the clusterworkspaces.
@@ -93,9 +107,19 @@ func newSystemCRDProvider(
 			clusters.ToClusterAwareKey(SystemCRDLogicalCluster, "apiresourceimports.apiresource.kcp.dev"),
 			clusters.ToClusterAwareKey(SystemCRDLogicalCluster, "negotiatedapiresources.apiresource.kcp.dev"),
 			clusters.ToClusterAwareKey(SystemCRDLogicalCluster, "workloadclusters.workload.kcp.dev"),
+			clusters.ToClusterAwareKey(SystemCRDLogicalCluster, "workspaceworkloadusages.workload.kcp.dev"),
+			clusters.ToClusterAwareKey(SystemCRDLogicalCluster, "dnsrecords.workload.kcp.dev"),
+			clusters.ToClusterAwareKey(SystemCRDLogicalCluster, "certificates.workload.kcp.dev"),
 			clusters.ToClusterAwareKey(SystemCRDLogicalCluster, "apiexports.apis.kcp.dev"),
 			clusters.ToClusterAwareKey(SystemCRDLogicalCluster, "apibindings.apis.kcp.dev"),
 			clusters.ToClusterAwareKey(SystemCRDLogicalCluster, "apiresourceschemas.apis.kcp.dev"),
+			clusters.ToClusterAwareKey(SystemCRDLogicalCluster, "catalogentries.apis.kcp.dev"),
+			clusters.ToClusterAwareKey(SystemCRDLogicalCluster, "sharedsecrets.apis.kcp.dev"),
+			clusters.ToClusterAwareKey(SystemCRDLogicalCluster, "secretclaims.apis.kcp.dev"),
+			clusters.ToClusterAwareKey(SystemCRDLogicalCluster, "accessgrants.tenancy.kcp.dev"),
+			clusters.ToClusterAwareKey(SystemCRDLogicalCluster, "denypolicies.tenancy.kcp.dev"),
+			clusters.ToClusterAwareKey(SystemCRDLogicalCluster, "replications.tenancy.kcp.dev"),
+			clusters.ToClusterAwareKey(SystemCRDLogicalCluster, "objecttransfers.tenancy.kcp.dev"),
 		),
 		getClusterWorkspace: getClusterWorkspace,
 		getCRD:              getCRD,
@@ -114,6 +138,14 @@ func newSystemCRDProvider(
 		p.universalCRDs.Insert(clusters.ToClusterAwareKey(SystemCRDLogicalCluster, "locations.scheduling.kcp.dev"))
 	}
 
+	if utilfeature.DefaultFeatureGate.Enabled(kcpfeatures.EventSubscriptions) {
+		p.universalCRDs.Insert(clusters.ToClusterAwareKey(SystemCRDLogicalCluster, "eventsubscriptions.apis.kcp.dev"))
+	}
+
+	if utilfeature.DefaultFeatureGate.Enabled(kcpfeatures.TTLPolicies) {
+		p.universalCRDs.Insert(clusters.ToClusterAwareKey(SystemCRDLogicalCluster, "ttlpolicies.apis.kcp.dev"))
+	}
+
 	return p
 }
 
@@ -254,6 +286,12 @@ func (c *apiBindingAwareCRDLister) List(ctx context.Context, selector labels.Sel
 				continue
 			}
 
+			// with the CRD conflict policy, CRDs from the local workspace take priority over the APIBinding.
+			if c.shadowedByLocalCRD(clusterName, apiBinding, boundResource) {
+				klog.V(4).Infof("Skipping APIBinding CRD %s|%s because APIBinding %s|%s prefers the local CRD", crd.ClusterName, crd.Name, clusterName, apiBinding.Name)
+				continue
+			}
+
 			// Priority 2: Add APIBinding CRDs. These take priority over those from the local workspace.
 
 			// Add the APIExport identity hash as an annotation to the CRD so the RESTOptionsGetter can assign
@@ -347,7 +385,7 @@ func (c *apiBindingAwareCRDLister) Get(ctx context.Context, name string) (*apiex
 
 		if identity := IdentityFromContext(ctx); identity != "" {
 			// Priority 2: identity request
-			crd, err = c.getForIdentity(name, identity)
+			crd, err = c.getForIdentity(clusterName, name, identity)
 		} else if isPartialMetadataRequest(ctx) {
 			// Priority 3: partial metadata
 			crd, err = c.getForPartialMetadata(name)
@@ -425,9 +463,11 @@ func (c *apiBindingAwareCRDLister) getWildcard(name string) (*apiextensionsv1.Cu
 	return crd, nil
 }
 
-// getForIdentity handles finding the right CRD for an incoming wildcard request with identity, such as
-// /clusters/*/apis/$group/$version/$resource:$identity.
-func (c *apiBindingAwareCRDLister) getForIdentity(name, identity string) (*apiextensionsv1.CustomResourceDefinition, error) {
+// getForIdentity handles finding the right CRD for an incoming request with identity, such as
+// /clusters/*/apis/$group/$version/$resource:$identity. Requests to a single workspace, like
+// /clusters/$cluster/apis/$group/$version/$resource:$identity, are only served if the workspace
+// binds the resource with that identity.
+func (c *apiBindingAwareCRDLister) getForIdentity(clusterName logicalcluster.Name, name, identity string) (*apiextensionsv1.CustomResourceDefinition, error) {
 	group, resource := crdNameToGroupResource(name)
 
 	indexKey := apibinding.IdentityGroupResourceKeyFunc(identity, group, resource)
@@ -437,6 +477,16 @@ func (c *apiBindingAwareCRDLister) getForIdentity(name, identity string) (*apiex
 		return nil, err
 	}
 
+	if clusterName != logicalcluster.Wildcard {
+		var inCluster []interface{}
+		for _, obj := range apiBindings {
+			if logicalcluster.From(obj.(*apisv1alpha1.APIBinding)) == clusterName {
+				inCluster = append(inCluster, obj)
+			}
+		}
+		apiBindings = inCluster
+	}
+
 	if len(apiBindings) == 0 {
 		return nil, errors.NewNotFound(apiextensionsv1.Resource("customresourcedefinitions"), name)
 	}
@@ -529,6 +579,10 @@ func (c *apiBindingAwareCRDLister) get(clusterName logicalcluster.Name, name str
 
 		for _, boundResource := range apiBinding.Status.BoundResources {
 			if boundResource.Group == group && boundResource.Resource == resource {
+				if c.shadowedByLocalCRD(clusterName, apiBinding, boundResource) {
+					break
+				}
+
 				crdKey := clusters.ToClusterAwareKey(apibinding.ShadowWorkspaceName, boundResource.Schema.UID)
 				crd, err = c.crdLister.Get(crdKey)
 				if err != nil && apierrors.IsNotFound(err) {
@@ -565,6 +619,16 @@ func (c *apiBindingAwareCRDLister) get(clusterName logicalcluster.Name, name str
 	return nil, apierrors.NewNotFound(schema.GroupResource{Group: apiextensionsv1.SchemeGroupVersion.Group, Resource: "customresourcedefinitions"}, name)
 }
 
+// shadowedByLocalCRD returns whether a bound resource is not served because the APIBinding prefers a CRD
+// in the workspace defining the same group and resource.
+func (c *apiBindingAwareCRDLister) shadowedByLocalCRD(clusterName logicalcluster.Name, apiBinding *apisv1alpha1.APIBinding, boundResource apisv1alpha1.BoundAPIResource) bool {
+	if apibinding.ConflictPolicy(apiBinding) != apisv1alpha1.CRDConflictPolicyCRD {
+		return false
+	}
+	_, err := c.crdLister.Get(clusters.ToClusterAwareKey(clusterName, apibinding.CRDNameForGroupResource(boundResource.Group, boundResource.Resource)))
+	return err == nil
+}
+
 // findCRD tries to locate a CRD named crdName in crds. It returns the located CRD, if any, and a bool
 // indicating that if there were multiple matches, they all have the same spec (true) or not (false).
 func findCRD(name string, crds []*apiextensionsv1.CustomResourceDefinition) (*apiextensionsv1.CustomResourceDefinition, bool) {
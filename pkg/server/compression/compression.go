@@ -0,0 +1,277 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compression
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+const (
+	// Gzip is the only encoding responses are compressed with. zstd is not offered
+	// because there is no zstd implementation vendored.
+	Gzip = "gzip"
+
+	// minSize is the size below which responses are not compressed, unless they are
+	// streamed.
+	minSize = 1024
+
+	// the same level as the generic apiserver compresses large lists with, which
+	// trades compression ratio for latency.
+	gzipLevel = gzip.BestSpeed
+)
+
+var gzipWriters = sync.Pool{
+	New: func() interface{} {
+		w, err := gzip.NewWriterLevel(nil, gzipLevel)
+		if err != nil {
+			panic(err)
+		}
+		return w
+	},
+}
+
+// WithCompression compresses responses, including watch streams, for clients that
+// accept gzip, e.g. client-go and reverse proxies based on net/http by default.
+// Responses that are smaller than 1 KiB, already encoded, or upgraded are not
+// compressed. server names the server in the metrics.
+//
+// The encoding is negotiated here for all responses, i.e. the generic apiserver
+// does not compress large lists itself, such that the metrics cover all of them.
+func WithCompression(handler http.Handler, server string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		encoding := negotiate(req.Header.Values("Accept-Encoding"))
+		if encoding == "" || httpstream.IsUpgradeRequest(req) || req.Method == http.MethodHead {
+			handler.ServeHTTP(w, req)
+			return
+		}
+
+		req = req.Clone(req.Context())
+		req.Header.Del("Accept-Encoding")
+
+		cw := &compressingWriter{
+			ResponseWriter: w,
+			server:         server,
+			encoding:       encoding,
+			stream:         isWatch(req),
+		}
+		defer cw.close()
+		w.Header().Add("Vary", "Accept-Encoding")
+		handler.ServeHTTP(cw, req)
+	})
+}
+
+// negotiate returns the encoding to compress with for the given Accept-Encoding
+// headers, or the empty string if the response is not to be compressed.
+func negotiate(acceptEncodings []string) string {
+	for _, header := range acceptEncodings {
+		for _, part := range strings.Split(header, ",") {
+			coding, q := parseCoding(part)
+			if q > 0 && (coding == Gzip || coding == "*") {
+				return Gzip
+			}
+		}
+	}
+	return ""
+}
+
+func parseCoding(part string) (string, float64) {
+	params := strings.Split(part, ";")
+	coding := strings.ToLower(strings.TrimSpace(params[0]))
+	q := 1.0
+	for _, param := range params[1:] {
+		param = strings.TrimSpace(param)
+		if !strings.HasPrefix(param, "q=") {
+			continue
+		}
+		v, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64)
+		if err != nil {
+			return coding, 0
+		}
+		q = v
+	}
+	return coding, q
+}
+
+func isWatch(req *http.Request) bool {
+	if info, ok := request.RequestInfoFrom(req.Context()); ok && info.IsResourceRequest {
+		return info.Verb == "watch"
+	}
+	watch := req.URL.Query().Get("watch")
+	return watch == "true" || watch == "1"
+}
+
+// compressingWriter buffers the beginning of a response to decide whether to
+// compress it. Streams are compressed from the start, and every flush flushes
+// the compressed stream too.
+type compressingWriter struct {
+	http.ResponseWriter
+	server   string
+	encoding string
+	stream   bool
+
+	status  int
+	decided bool
+	buf     []byte
+
+	gz           *gzip.Writer
+	compressed   countingWriter
+	uncompressed int64
+	// reportedCompressed and reportedUncompressed are the bytes in the metrics
+	// already, which streams update on every flush.
+	reportedCompressed, reportedUncompressed int64
+}
+
+func (w *compressingWriter) WriteHeader(status int) {
+	if w.status != 0 {
+		return
+	}
+	w.status = status
+
+	switch {
+	case w.Header().Get("Content-Encoding") != "", status < http.StatusOK, status == http.StatusNoContent, status == http.StatusNotModified:
+		w.decide(false)
+	case w.stream:
+		w.decide(true)
+	}
+}
+
+func (w *compressingWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if !w.decided {
+		w.buf = append(w.buf, p...)
+		if len(w.buf) < minSize {
+			return len(p), nil
+		}
+		buffered := w.buf
+		w.buf = nil
+		w.decide(true)
+		if _, err := w.write(buffered); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+
+	return w.write(p)
+}
+
+func (w *compressingWriter) write(p []byte) (int, error) {
+	if w.gz == nil {
+		return w.ResponseWriter.Write(p)
+	}
+	w.uncompressed += int64(len(p))
+	return w.gz.Write(p)
+}
+
+// decide writes the header, compressing the body or not.
+func (w *compressingWriter) decide(compress bool) {
+	w.decided = true
+	if compress {
+		h := w.Header()
+		h.Set("Content-Encoding", w.encoding)
+		h.Del("Content-Length")
+		w.compressed.w = w.ResponseWriter
+		w.gz = gzipWriters.Get().(*gzip.Writer)
+		w.gz.Reset(&w.compressed)
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+}
+
+func (w *compressingWriter) Flush() {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	if !w.decided {
+		// flushing means streaming, which is worth compressing.
+		buffered := w.buf
+		w.buf = nil
+		w.decide(true)
+		w.write(buffered) // nolint:errcheck
+	}
+	if w.gz != nil {
+		w.gz.Flush() // nolint:errcheck
+		w.report()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// CloseNotify is deprecated, but still asserted by some handlers.
+func (w *compressingWriter) CloseNotify() <-chan bool {
+	if cn, ok := w.ResponseWriter.(http.CloseNotifier); ok { // nolint:staticcheck
+		return cn.CloseNotify()
+	}
+	return make(chan bool)
+}
+
+func (w *compressingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// close writes what is left of the response once the handler returned.
+func (w *compressingWriter) close() {
+	if !w.decided {
+		if w.status == 0 && len(w.buf) == 0 {
+			// nothing was written, net/http answers 200 OK.
+			return
+		}
+		if w.status == 0 {
+			w.status = http.StatusOK
+		}
+		buffered := w.buf
+		w.buf = nil
+		w.decide(false)
+		w.write(buffered) // nolint:errcheck
+		return
+	}
+
+	if w.gz == nil {
+		return
+	}
+	w.gz.Close() // nolint:errcheck
+	w.gz.Reset(nil)
+	gzipWriters.Put(w.gz)
+	w.gz = nil
+	w.report()
+}
+
+func (w *compressingWriter) report() {
+	uncompressedBytes.WithLabelValues(w.server, w.encoding).Add(float64(w.uncompressed - w.reportedUncompressed))
+	compressedBytes.WithLabelValues(w.server, w.encoding).Add(float64(w.compressed.n - w.reportedCompressed))
+	w.reportedUncompressed, w.reportedCompressed = w.uncompressed, w.compressed.n
+}
+
+type countingWriter struct {
+	w http.ResponseWriter
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
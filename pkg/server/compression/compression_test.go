@@ -0,0 +1,166 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compression
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers []string
+		want    string
+	}{
+		{name: "none"},
+		{name: "gzip", headers: []string{"gzip"}, want: Gzip},
+		{name: "gzip among others", headers: []string{"zstd, br;q=0.9", "GZIP;q=0.5"}, want: Gzip},
+		{name: "wildcard", headers: []string{"*"}, want: Gzip},
+		{name: "zstd only", headers: []string{"zstd"}},
+		{name: "gzip refused", headers: []string{"gzip;q=0"}},
+		{name: "invalid q", headers: []string{"gzip;q=x"}},
+		{name: "identity", headers: []string{"identity"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, negotiate(tt.headers))
+		})
+	}
+}
+
+func TestWithCompression(t *testing.T) {
+	large := strings.Repeat("a compressible response body, ", 100)
+
+	tests := []struct {
+		name           string
+		path           string
+		acceptEncoding string
+		handler        http.HandlerFunc
+		wantEncoding   string
+		wantBody       string
+	}{
+		{
+			name:     "not accepted",
+			handler:  write(large),
+			wantBody: large,
+		},
+		{
+			name:           "large response",
+			acceptEncoding: "gzip",
+			handler:        write(large),
+			wantEncoding:   Gzip,
+			wantBody:       large,
+		},
+		{
+			name:           "small response",
+			acceptEncoding: "gzip",
+			handler:        write("small"),
+			wantBody:       "small",
+		},
+		{
+			name:           "small response in chunks adding up",
+			acceptEncoding: "gzip",
+			handler:        write(large[:600], large[600:]),
+			wantEncoding:   Gzip,
+			wantBody:       large,
+		},
+		{
+			name:           "already encoded",
+			acceptEncoding: "gzip",
+			handler: func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Set("Content-Encoding", "br")
+				w.Write([]byte(large)) // nolint:errcheck
+			},
+			wantEncoding: "br",
+			wantBody:     large,
+		},
+		{
+			name:           "small watch",
+			path:           "/api/v1/pods?watch=true",
+			acceptEncoding: "gzip",
+			handler:        write("event"),
+			wantEncoding:   Gzip,
+			wantBody:       "event",
+		},
+		{
+			name:           "small flushed response",
+			acceptEncoding: "gzip",
+			handler: func(w http.ResponseWriter, req *http.Request) {
+				w.Write([]byte("first")) // nolint:errcheck
+				w.(http.Flusher).Flush()
+				w.Write([]byte("second")) // nolint:errcheck
+			},
+			wantEncoding: Gzip,
+			wantBody:     "firstsecond",
+		},
+		{
+			name:           "no content",
+			acceptEncoding: "gzip",
+			handler: func(w http.ResponseWriter, req *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			},
+		},
+		{
+			name:           "accept encoding hidden from the handler",
+			acceptEncoding: "gzip",
+			handler: func(w http.ResponseWriter, req *http.Request) {
+				w.Write([]byte(req.Header.Get("Accept-Encoding"))) // nolint:errcheck
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := tt.path
+			if path == "" {
+				path = "/api/v1/pods"
+			}
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			rec := httptest.NewRecorder()
+			WithCompression(tt.handler, "test").ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantEncoding, rec.Header().Get("Content-Encoding"))
+			body := rec.Body.Bytes()
+			if tt.wantEncoding == Gzip {
+				require.Empty(t, rec.Header().Get("Content-Length"))
+				r, err := gzip.NewReader(bytes.NewReader(body))
+				require.NoError(t, err)
+				body, err = ioutil.ReadAll(r)
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantBody, string(body))
+		})
+	}
+}
+
+func write(chunks ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		for _, chunk := range chunks {
+			w.Write([]byte(chunk)) // nolint:errcheck
+		}
+	}
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compression
+
+import (
+	"sync"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+var (
+	// the bytes saved are the difference of both.
+	uncompressedBytes = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace:      "kcp",
+			Name:           "response_compression_uncompressed_bytes_total",
+			Help:           "Bytes of compressed responses before compression, by server and encoding.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"server", "encoding"},
+	)
+	compressedBytes = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace:      "kcp",
+			Name:           "response_compression_compressed_bytes_total",
+			Help:           "Bytes of compressed responses as sent, by server and encoding.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"server", "encoding"},
+	)
+
+	registerMetrics sync.Once
+)
+
+// RegisterMetrics registers the response compression metrics.
+func RegisterMetrics() {
+	registerMetrics.Do(func() {
+		legacyregistry.MustRegister(uncompressedBytes)
+		legacyregistry.MustRegister(compressedBytes)
+	})
+}
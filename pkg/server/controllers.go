@@ -18,9 +18,11 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	_ "net/http/pprof"
 	"os"
 	"time"
@@ -36,8 +38,10 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/metadata"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
 	certutil "k8s.io/client-go/util/cert"
 	"k8s.io/client-go/util/keyutil"
+	componentbaseversion "k8s.io/component-base/version"
 	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/pkg/controller/certificates/rootcacertpublisher"
 	"k8s.io/kubernetes/pkg/controller/clusterroleaggregation"
@@ -45,26 +49,54 @@ import (
 	serviceaccountcontroller "k8s.io/kubernetes/pkg/controller/serviceaccount"
 	"k8s.io/kubernetes/pkg/serviceaccount"
 
+	configcrds "github.com/kcp-dev/kcp/config/crds"
 	configorganization "github.com/kcp-dev/kcp/config/organization"
 	configteam "github.com/kcp-dev/kcp/config/team"
 	configuniversal "github.com/kcp-dev/kcp/config/universal"
+	"github.com/kcp-dev/kcp/pkg/apiexportidentity"
 	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
 	kcpclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
+	kcpfeatures "github.com/kcp-dev/kcp/pkg/features"
 	metadataclient "github.com/kcp-dev/kcp/pkg/metadata"
 	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apibinding"
 	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apiexport"
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apiexportusage"
 	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apiresource"
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/catalogentry"
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/deprecatedusage"
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/eventsubscription"
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/schemacompatibility"
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/secretclaim"
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/ttlpolicy"
+	"github.com/kcp-dev/kcp/pkg/reconciler/coordination/leasegc"
 	schedulinglocationstatus "github.com/kcp-dev/kcp/pkg/reconciler/scheduling/location"
 	schedulingplacement "github.com/kcp-dev/kcp/pkg/reconciler/scheduling/placement"
+	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/accessgrant"
 	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/bootstrap"
+	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/bulkworkspaceoperation"
 	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/clusterworkspace"
 	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/clusterworkspacedeletion"
 	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/clusterworkspaceshard"
+	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/defaultnamespaces"
+	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/expiration"
+	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/hibernation"
+	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/homeworkspace"
+	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/labelpropagation"
+	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/objecttransfer"
+	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/policybundle"
+	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/replication"
+	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/shardversion"
+	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/workspacetemplate"
 	workloadsapiexport "github.com/kcp-dev/kcp/pkg/reconciler/workload/apiexport"
+	"github.com/kcp-dev/kcp/pkg/reconciler/workload/certificate"
+	"github.com/kcp-dev/kcp/pkg/reconciler/workload/dnsrecord"
 	"github.com/kcp-dev/kcp/pkg/reconciler/workload/heartbeat"
+	workloadidentity "github.com/kcp-dev/kcp/pkg/reconciler/workload/identity"
 	workloadnamespace "github.com/kcp-dev/kcp/pkg/reconciler/workload/namespace"
 	workloadresource "github.com/kcp-dev/kcp/pkg/reconciler/workload/resource"
+	workloadusage "github.com/kcp-dev/kcp/pkg/reconciler/workload/usage"
 	virtualworkspaceurlscontroller "github.com/kcp-dev/kcp/pkg/reconciler/workload/virtualworkspaceurls"
+	kcpserveroptions "github.com/kcp-dev/kcp/pkg/server/options"
 )
 
 func (s *Server) installClusterRoleAggregationController(ctx context.Context, config *rest.Config) error {
@@ -408,7 +440,10 @@ func (s *Server) installWorkspaceScheduler(ctx context.Context, config *rest.Con
 	workspaceController, err := clusterworkspace.NewController(
 		kcpClusterClient,
 		s.kcpSharedInformerFactory.Tenancy().V1alpha1().ClusterWorkspaces(),
+		s.kcpSharedInformerFactory.Tenancy().V1alpha1().ClusterWorkspaceTypes(),
 		s.rootKcpSharedInformerFactory.Tenancy().V1alpha1().ClusterWorkspaceShards(),
+		s.kcpSharedInformerFactory.Apis().V1alpha1().APIBindings(),
+		s.options.Controllers.WorkspaceScheduler.MaxConcurrentInitializations,
 	)
 	if err != nil {
 		return err
@@ -569,6 +604,41 @@ func (s *Server) installAPIBindingController(ctx context.Context, config *rest.C
 		return err
 	}
 
+	// the APIBinding controller only upgrades bindings to new schemas after this controller
+	// has validated the existing objects against them.
+	dynamicClusterClient, err := dynamic.NewClusterForConfig(config)
+	if err != nil {
+		return err
+	}
+	compatibilityController, err := schemacompatibility.NewController(
+		kcpClusterClient,
+		dynamicClusterClient,
+		s.kcpSharedInformerFactory.Apis().V1alpha1().APIBindings(),
+		s.kcpSharedInformerFactory.Apis().V1alpha1().APIExports(),
+		s.kcpSharedInformerFactory.Apis().V1alpha1().APIResourceSchemas(),
+		s.apiextensionsSharedInformerFactory.Apiextensions().V1().CustomResourceDefinitions(),
+	)
+	if err != nil {
+		return err
+	}
+
+	// the usage controller counts objects through the APIBindings of an APIExport, which relies
+	// on the index of the APIBinding controller.
+	var usageController interface{ Start(context.Context, int) }
+	if interval := s.options.Controllers.APIExportUsage.Interval; interval > 0 {
+		usageController, err = apiexportusage.NewController(
+			kcpClusterClient,
+			dynamicClusterClient,
+			s.kcpSharedInformerFactory.Apis().V1alpha1().APIBindings(),
+			s.kcpSharedInformerFactory.Apis().V1alpha1().APIExports(),
+			s.apiextensionsSharedInformerFactory.Apiextensions().V1().CustomResourceDefinitions(),
+			interval,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
 	if err := server.AddPostStartHook("kcp-install-apibinding-controller", func(hookContext genericapiserver.PostStartHookContext) error {
 		if err := s.waitForSync(hookContext.StopCh); err != nil {
 			klog.Errorf("failed to finish post-start-hook kcp-install-apibinding-controller: %v", err)
@@ -577,6 +647,10 @@ func (s *Server) installAPIBindingController(ctx context.Context, config *rest.C
 		}
 
 		go c.Start(goContext(hookContext), 2)
+		go compatibilityController.Start(goContext(hookContext), 2)
+		if usageController != nil {
+			go usageController.Start(goContext(hookContext), 1)
+		}
 
 		return nil
 	}); err != nil {
@@ -586,6 +660,40 @@ func (s *Server) installAPIBindingController(ctx context.Context, config *rest.C
 	return nil
 }
 
+func (s *Server) newAPIExportIdentityBackend(kubeClusterClient kubernetes.ClusterInterface) (apiexportidentity.Backend, error) {
+	opts := s.options.APIExportIdentity
+	if opts.Backend != kcpserveroptions.APIExportIdentityBackendVault {
+		return apiexportidentity.NewSecretBackend(
+			kubeClusterClient,
+			s.kubeSharedInformerFactory.Core().V1().Namespaces(),
+			s.kubeSharedInformerFactory.Core().V1().Secrets(),
+		), nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if opts.VaultCAFile != "" {
+		pool, err := certutil.NewPool(opts.VaultCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading --apiexport-identity-vault-ca-file: %w", err)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	backend, err := apiexportidentity.NewVaultBackend(apiexportidentity.VaultConfig{
+		Address:    opts.VaultAddress,
+		TokenFile:  opts.VaultTokenFile,
+		Mount:      opts.VaultMount,
+		PathPrefix: opts.VaultPathPrefix,
+		Client: &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig, Proxy: http.ProxyFromEnvironment},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return apiexportidentity.NewCachingBackend(backend, opts.CacheTTL), nil
+}
+
 func (s *Server) installAPIExportController(ctx context.Context, config *rest.Config, server *genericapiserver.GenericAPIServer) error {
 	config = rest.AddUserAgent(rest.CopyConfig(config), "kcp-apiexport-controller")
 
@@ -599,13 +707,16 @@ func (s *Server) installAPIExportController(ctx context.Context, config *rest.Co
 		return err
 	}
 
+	identityBackend, err := s.newAPIExportIdentityBackend(kubeClusterClient)
+	if err != nil {
+		return err
+	}
+
 	c, err := apiexport.NewController(
 		kcpClusterClient,
 		s.kcpSharedInformerFactory.Apis().V1alpha1().APIExports(),
 		s.kcpSharedInformerFactory.Apis().V1alpha1().APIResourceSchemas(),
-		kubeClusterClient,
-		s.kubeSharedInformerFactory.Core().V1().Namespaces(),
-		s.kubeSharedInformerFactory.Core().V1().Secrets(),
+		identityBackend,
 	)
 	if err != nil {
 		return err
@@ -626,6 +737,81 @@ func (s *Server) installAPIExportController(ctx context.Context, config *rest.Co
 	return nil
 }
 
+func (s *Server) installCatalogEntryController(ctx context.Context, config *rest.Config, server *genericapiserver.GenericAPIServer) error {
+	config = rest.AddUserAgent(rest.CopyConfig(config), "kcp-catalogentry-controller")
+
+	kcpClusterClient, err := kcpclient.NewClusterForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	c, err := catalogentry.NewController(
+		kcpClusterClient,
+		s.kcpSharedInformerFactory.Apis().V1alpha1().CatalogEntries(),
+		s.kcpSharedInformerFactory.Apis().V1alpha1().APIExports(),
+		s.kcpSharedInformerFactory.Apis().V1alpha1().APIResourceSchemas(),
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := server.AddPostStartHook("kcp-install-catalogentry-controller", func(hookContext genericapiserver.PostStartHookContext) error {
+		if err := s.waitForSync(hookContext.StopCh); err != nil {
+			klog.Errorf("failed to finish post-start-hook kcp-install-catalogentry-controller: %v", err)
+			// nolint:nilerr
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		go c.Start(goContext(hookContext), 2)
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Server) installSecretClaimController(ctx context.Context, config *rest.Config, server *genericapiserver.GenericAPIServer) error {
+	config = rest.AddUserAgent(rest.CopyConfig(config), "kcp-secretclaim-controller")
+
+	kubeClusterClient, err := kubernetes.NewClusterForConfig(config)
+	if err != nil {
+		return err
+	}
+	kcpClusterClient, err := kcpclient.NewClusterForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	c, err := secretclaim.NewController(
+		kubeClusterClient,
+		kcpClusterClient,
+		s.kcpSharedInformerFactory.Apis().V1alpha1().SecretClaims(),
+		s.kcpSharedInformerFactory.Apis().V1alpha1().SharedSecrets(),
+		s.kubeSharedInformerFactory.Core().V1().Secrets(),
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := server.AddPostStartHook("kcp-install-secretclaim-controller", func(hookContext genericapiserver.PostStartHookContext) error {
+		if err := s.waitForSync(hookContext.StopCh); err != nil {
+			klog.Errorf("failed to finish post-start-hook kcp-install-secretclaim-controller: %v", err)
+			// nolint:nilerr
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		go c.Start(goContext(hookContext), 2)
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (s *Server) installSchedulingLocationStatusController(ctx context.Context, config *rest.Config, server *genericapiserver.GenericAPIServer) error {
 	controllerName := "kcp-scheduling-location-status-controller"
 	config = rest.AddUserAgent(rest.CopyConfig(config), controllerName)
@@ -672,6 +858,11 @@ func (s *Server) installSchedulingPlacementController(ctx context.Context, confi
 		return err
 	}
 
+	extenders, err := s.options.Placement.Extenders()
+	if err != nil {
+		return err
+	}
+
 	c, err := schedulingplacement.NewController(
 		kubeClusterClient,
 		kcpClusterClient,
@@ -679,6 +870,7 @@ func (s *Server) installSchedulingPlacementController(ctx context.Context, confi
 		s.kcpSharedInformerFactory.Apis().V1alpha1().APIBindings(),
 		s.kcpSharedInformerFactory.Scheduling().V1alpha1().Locations(),
 		s.kcpSharedInformerFactory.Workload().V1alpha1().WorkloadClusters(),
+		extenders,
 	)
 	if err != nil {
 		return err
@@ -770,14 +962,824 @@ func (s *Server) installVirtualWorkspaceURLsController(ctx context.Context, conf
 	return nil
 }
 
-func (s *Server) waitForSync(stop <-chan struct{}) error {
-	// Wait for shared informer factories to by synced.
-	// factory. Otherwise, informer list calls may go into backoff (before the CRDs are ready) and
-	// take ~10 seconds to succeed.
-	select {
-	case <-stop:
-		return errors.New("timed out waiting for informers to sync")
-	case <-s.syncedCh:
+func (s *Server) installAccessGrantController(ctx context.Context, config *rest.Config, server *genericapiserver.GenericAPIServer) error {
+	controllerName := "kcp-accessgrant-controller"
+	config = rest.AddUserAgent(rest.CopyConfig(config), controllerName)
+	kubeClusterClient, err := kubernetes.NewClusterForConfig(config)
+	if err != nil {
+		return err
+	}
+	kcpClusterClient, err := kcpclient.NewClusterForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	c, err := accessgrant.NewController(
+		kubeClusterClient,
+		kcpClusterClient,
+		s.kcpSharedInformerFactory.Tenancy().V1alpha1().AccessGrants(),
+		s.kubeSharedInformerFactory.Rbac().V1().ClusterRoleBindings(),
+		s.kubeSharedInformerFactory.Rbac().V1().RoleBindings(),
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := server.AddPostStartHook(controllerName, func(hookContext genericapiserver.PostStartHookContext) error {
+		if err := s.waitForSync(hookContext.StopCh); err != nil {
+			klog.Errorf("failed to finish post-start-hook %s: %v", controllerName, err)
+			// nolint:nilerr
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		go c.Start(goContext(hookContext), 2)
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Server) installReplicationController(ctx context.Context, config *rest.Config, server *genericapiserver.GenericAPIServer) error {
+	controllerName := "kcp-replication-controller"
+	config = rest.AddUserAgent(rest.CopyConfig(config), controllerName)
+	kubeClusterClient, err := kubernetes.NewClusterForConfig(config)
+	if err != nil {
+		return err
+	}
+	dynamicClusterClient, err := dynamic.NewClusterForConfig(config)
+	if err != nil {
+		return err
+	}
+	kcpClusterClient, err := kcpclient.NewClusterForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	c, err := replication.NewController(
+		kubeClusterClient,
+		dynamicClusterClient,
+		kcpClusterClient,
+		s.kcpSharedInformerFactory.Tenancy().V1alpha1().Replications(),
+		s.kcpSharedInformerFactory.Tenancy().V1alpha1().ClusterWorkspaces(),
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := server.AddPostStartHook(controllerName, func(hookContext genericapiserver.PostStartHookContext) error {
+		if err := s.waitForSync(hookContext.StopCh); err != nil {
+			klog.Errorf("failed to finish post-start-hook %s: %v", controllerName, err)
+			// nolint:nilerr
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		go c.Start(goContext(hookContext), 2)
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Server) installPolicyBundleController(ctx context.Context, config *rest.Config, server *genericapiserver.GenericAPIServer) error {
+	controllerName := "kcp-policy-bundle-controller"
+	config = rest.AddUserAgent(rest.CopyConfig(config), controllerName)
+	dynamicClusterClient, err := dynamic.NewClusterForConfig(config)
+	if err != nil {
+		return err
+	}
+	kcpClusterClient, err := kcpclient.NewClusterForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	c, err := policybundle.NewController(
+		dynamicClusterClient,
+		kcpClusterClient,
+		s.kcpSharedInformerFactory.Tenancy().V1alpha1().PolicyBundles(),
+		s.kcpSharedInformerFactory.Tenancy().V1alpha1().ClusterWorkspaces(),
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := server.AddPostStartHook(controllerName, func(hookContext genericapiserver.PostStartHookContext) error {
+		if err := s.waitForSync(hookContext.StopCh); err != nil {
+			klog.Errorf("failed to finish post-start-hook %s: %v", controllerName, err)
+			// nolint:nilerr
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		go c.Start(goContext(hookContext), 2)
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Server) installBulkWorkspaceOperationController(ctx context.Context, config *rest.Config, server *genericapiserver.GenericAPIServer) error {
+	controllerName := "kcp-bulk-workspace-operation-controller"
+	config = rest.AddUserAgent(rest.CopyConfig(config), controllerName)
+	kcpClusterClient, err := kcpclient.NewClusterForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	c, err := bulkworkspaceoperation.NewController(
+		kcpClusterClient,
+		s.kcpSharedInformerFactory.Tenancy().V1alpha1().BulkWorkspaceOperations(),
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := server.AddPostStartHook(controllerName, func(hookContext genericapiserver.PostStartHookContext) error {
+		if err := s.waitForSync(hookContext.StopCh); err != nil {
+			klog.Errorf("failed to finish post-start-hook %s: %v", controllerName, err)
+			// nolint:nilerr
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		go c.Start(goContext(hookContext), 2)
+
 		return nil
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Server) installDefaultNamespacesController(ctx context.Context, config *rest.Config, server *genericapiserver.GenericAPIServer) error {
+	controllerName := "kcp-workspace-default-namespaces-controller"
+	config = rest.AddUserAgent(rest.CopyConfig(config), controllerName)
+	kubeClusterClient, err := kubernetes.NewClusterForConfig(config)
+	if err != nil {
+		return err
 	}
+	kcpClusterClient, err := kcpclient.NewClusterForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	c, err := defaultnamespaces.NewController(
+		kubeClusterClient,
+		kcpClusterClient,
+		s.kcpSharedInformerFactory.Tenancy().V1alpha1().ClusterWorkspaces(),
+		s.kcpSharedInformerFactory.Tenancy().V1alpha1().ClusterWorkspaceTypes(),
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := server.AddPostStartHook(controllerName, func(hookContext genericapiserver.PostStartHookContext) error {
+		if err := s.waitForSync(hookContext.StopCh); err != nil {
+			klog.Errorf("failed to finish post-start-hook %s: %v", controllerName, err)
+			// nolint:nilerr
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		go c.Start(goContext(hookContext), 2)
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Server) installWorkspaceTemplateController(ctx context.Context, config *rest.Config, server *genericapiserver.GenericAPIServer) error {
+	controllerName := "kcp-workspace-template-controller"
+	config = rest.AddUserAgent(rest.CopyConfig(config), controllerName)
+	kubeClusterClient, err := kubernetes.NewClusterForConfig(config)
+	if err != nil {
+		return err
+	}
+	dynamicClusterClient, err := dynamic.NewClusterForConfig(config)
+	if err != nil {
+		return err
+	}
+	kcpClusterClient, err := kcpclient.NewClusterForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	c, err := workspacetemplate.NewController(
+		kubeClusterClient,
+		dynamicClusterClient,
+		kcpClusterClient,
+		s.kcpSharedInformerFactory.Tenancy().V1alpha1().ClusterWorkspaces(),
+		s.kcpSharedInformerFactory.Tenancy().V1alpha1().ClusterWorkspaceTypes(),
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := server.AddPostStartHook(controllerName, func(hookContext genericapiserver.PostStartHookContext) error {
+		if err := s.waitForSync(hookContext.StopCh); err != nil {
+			klog.Errorf("failed to finish post-start-hook %s: %v", controllerName, err)
+			// nolint:nilerr
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		go c.Start(goContext(hookContext), 2)
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Server) installShardVersionController(ctx context.Context, config *rest.Config, server *genericapiserver.GenericAPIServer) error {
+	controllerName := "kcp-shard-version-controller"
+	config = rest.AddUserAgent(rest.CopyConfig(config), controllerName)
+	kcpClusterClient, err := kcpclient.NewClusterForConfig(config)
+	if err != nil {
+		return err
+	}
+	crdClusterClient, err := apiextensionsclient.NewClusterForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	c, err := shardversion.NewController(
+		s.options.Extra.ShardName,
+		componentbaseversion.Get().GitVersion,
+		kcpfeatures.Enabled(),
+		SystemCRDLogicalCluster,
+		kcpClusterClient.Cluster(tenancyv1alpha1.RootCluster),
+		crdClusterClient.Cluster(SystemCRDLogicalCluster).ApiextensionsV1().CustomResourceDefinitions(),
+		s.rootKcpSharedInformerFactory.Tenancy().V1alpha1().ClusterWorkspaceShards(),
+		s.apiextensionsSharedInformerFactory.Apiextensions().V1().CustomResourceDefinitions(),
+		configcrds.StorageVersion,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := server.AddPostStartHook(controllerName, func(hookContext genericapiserver.PostStartHookContext) error {
+		if err := s.waitForSync(hookContext.StopCh); err != nil {
+			klog.Errorf("failed to finish post-start-hook %s: %v", controllerName, err)
+			// nolint:nilerr
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		go c.Start(goContext(hookContext), 1)
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Server) installLabelPropagationController(ctx context.Context, config *rest.Config, server *genericapiserver.GenericAPIServer) error {
+	controllerName := "kcp-workspace-label-propagation-controller"
+	config = rest.AddUserAgent(rest.CopyConfig(config), controllerName)
+	kubeClusterClient, err := kubernetes.NewClusterForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	c, err := labelpropagation.NewController(
+		kubeClusterClient,
+		s.kubeSharedInformerFactory.Core().V1().Namespaces(),
+		s.kcpSharedInformerFactory.Tenancy().V1alpha1().ClusterWorkspaces(),
+		s.kcpSharedInformerFactory.Tenancy().V1alpha1().ClusterWorkspaceTypes(),
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := server.AddPostStartHook(controllerName, func(hookContext genericapiserver.PostStartHookContext) error {
+		if err := s.waitForSync(hookContext.StopCh); err != nil {
+			klog.Errorf("failed to finish post-start-hook %s: %v", controllerName, err)
+			// nolint:nilerr
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		go c.Start(goContext(hookContext), 2)
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Server) installDNSRecordController(ctx context.Context, config *rest.Config, server *genericapiserver.GenericAPIServer) error {
+	controllerName := "kcp-dnsrecord-controller"
+	config = rest.AddUserAgent(rest.CopyConfig(config), controllerName)
+	kcpClusterClient, err := kcpclient.NewClusterForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	c, err := dnsrecord.NewController(
+		kcpClusterClient,
+		s.kcpSharedInformerFactory.Workload().V1alpha1().DNSRecords(),
+		s.kcpSharedInformerFactory.Workload().V1alpha1().DNSZones(),
+		s.options.DNS.Providers(),
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := server.AddPostStartHook(controllerName, func(hookContext genericapiserver.PostStartHookContext) error {
+		if err := s.waitForSync(hookContext.StopCh); err != nil {
+			klog.Errorf("failed to finish post-start-hook %s: %v", controllerName, err)
+			// nolint:nilerr
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		go c.Start(goContext(hookContext), 2)
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Server) installCertificateController(ctx context.Context, config *rest.Config, server *genericapiserver.GenericAPIServer) error {
+	controllerName := "kcp-certificate-controller"
+	config = rest.AddUserAgent(rest.CopyConfig(config), controllerName)
+	kubeClusterClient, err := kubernetes.NewClusterForConfig(config)
+	if err != nil {
+		return err
+	}
+	kcpClusterClient, err := kcpclient.NewClusterForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	issuer, err := s.options.ACME.Issuer()
+	if err != nil {
+		return err
+	}
+
+	c, err := certificate.NewController(
+		kubeClusterClient,
+		kcpClusterClient,
+		s.kcpSharedInformerFactory.Workload().V1alpha1().Certificates(),
+		s.kcpSharedInformerFactory.Workload().V1alpha1().DNSRecords(),
+		s.kcpSharedInformerFactory.Workload().V1alpha1().DNSZones(),
+		s.kubeSharedInformerFactory.Core().V1().Secrets(),
+		s.options.DNS.Providers(),
+		issuer,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := server.AddPostStartHook(controllerName, func(hookContext genericapiserver.PostStartHookContext) error {
+		if err := s.waitForSync(hookContext.StopCh); err != nil {
+			klog.Errorf("failed to finish post-start-hook %s: %v", controllerName, err)
+			// nolint:nilerr
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		go c.Start(goContext(hookContext), 2)
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Server) installWorkloadIdentityController(ctx context.Context, config *rest.Config, server *genericapiserver.GenericAPIServer) error {
+	controllerName := "kcp-workload-identity-controller"
+	config = rest.AddUserAgent(rest.CopyConfig(config), controllerName)
+	kubeClusterClient, err := kubernetes.NewClusterForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	c, err := workloadidentity.NewController(
+		kubeClusterClient,
+		s.kubeSharedInformerFactory.Core().V1().ServiceAccounts(),
+		s.kubeSharedInformerFactory.Core().V1().Secrets(),
+		s.options.WorkloadIdentity.Audiences,
+		s.options.WorkloadIdentity.TokenExpiration,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := server.AddPostStartHook(controllerName, func(hookContext genericapiserver.PostStartHookContext) error {
+		if err := s.waitForSync(hookContext.StopCh); err != nil {
+			klog.Errorf("failed to finish post-start-hook %s: %v", controllerName, err)
+			// nolint:nilerr
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		go c.Start(goContext(hookContext), 2)
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Server) installWorkloadUsageController(ctx context.Context, config *rest.Config, server *genericapiserver.GenericAPIServer) error {
+	controllerName := "kcp-workload-usage-controller"
+	config = rest.AddUserAgent(rest.CopyConfig(config), controllerName)
+	kcpClusterClient, err := kcpclient.NewClusterForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	c, err := workloadusage.NewController(
+		kcpClusterClient,
+		s.kcpSharedInformerFactory.Workload().V1alpha1().WorkspaceWorkloadUsages(),
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := server.AddPostStartHook(controllerName, func(hookContext genericapiserver.PostStartHookContext) error {
+		if err := s.waitForSync(hookContext.StopCh); err != nil {
+			klog.Errorf("failed to finish post-start-hook %s: %v", controllerName, err)
+			// nolint:nilerr
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		go c.Start(goContext(hookContext), 2)
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Server) installHibernationController(ctx context.Context, config *rest.Config, server *genericapiserver.GenericAPIServer) error {
+	controllerName := "kcp-workspace-hibernation-controller"
+	config = rest.AddUserAgent(rest.CopyConfig(config), controllerName)
+	dynamicClusterClient, err := dynamic.NewClusterForConfig(config)
+	if err != nil {
+		return err
+	}
+	kcpClusterClient, err := kcpclient.NewClusterForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	c, err := hibernation.NewController(
+		dynamicClusterClient,
+		kcpClusterClient,
+		s.kcpSharedInformerFactory.Tenancy().V1alpha1().ClusterWorkspaces(),
+		s.workspaceActivity,
+		s.options.Controllers.WorkspaceHibernation.IdleTimeout,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := server.AddPostStartHook(controllerName, func(hookContext genericapiserver.PostStartHookContext) error {
+		if err := s.waitForSync(hookContext.StopCh); err != nil {
+			klog.Errorf("failed to finish post-start-hook %s: %v", controllerName, err)
+			// nolint:nilerr
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		go c.Start(goContext(hookContext), 2)
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Server) installWorkspaceExpirationController(ctx context.Context, config *rest.Config, server *genericapiserver.GenericAPIServer) error {
+	controllerName := "kcp-workspace-expiration-controller"
+	config = rest.AddUserAgent(rest.CopyConfig(config), controllerName)
+	kcpClusterClient, err := kcpclient.NewClusterForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	c, err := expiration.NewController(
+		kcpClusterClient,
+		s.kcpSharedInformerFactory.Tenancy().V1alpha1().ClusterWorkspaces(),
+		s.options.Controllers.WorkspaceExpiration.WarningPeriod,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := server.AddPostStartHook(controllerName, func(hookContext genericapiserver.PostStartHookContext) error {
+		if err := s.waitForSync(hookContext.StopCh); err != nil {
+			klog.Errorf("failed to finish post-start-hook %s: %v", controllerName, err)
+			// nolint:nilerr
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		go c.Start(goContext(hookContext), 2)
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Server) installObjectTransferController(ctx context.Context, config *rest.Config, server *genericapiserver.GenericAPIServer) error {
+	controllerName := "kcp-object-transfer-controller"
+	config = rest.AddUserAgent(rest.CopyConfig(config), controllerName)
+	dynamicClusterClient, err := dynamic.NewClusterForConfig(config)
+	if err != nil {
+		return err
+	}
+	kcpClusterClient, err := kcpclient.NewClusterForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	discoverResourcesFn := func(clusterName logicalcluster.Name) ([]*metav1.APIResourceList, error) {
+		logicalClusterConfig := rest.CopyConfig(config)
+		logicalClusterConfig.Host += clusterName.Path()
+		discoveryClient, err := discovery.NewDiscoveryClientForConfig(logicalClusterConfig)
+		if err != nil {
+			return nil, err
+		}
+		return discoveryClient.ServerPreferredNamespacedResources()
+	}
+
+	c, err := objecttransfer.NewController(
+		dynamicClusterClient,
+		kcpClusterClient,
+		s.kcpSharedInformerFactory.Tenancy().V1alpha1().ObjectTransfers(),
+		discoverResourcesFn,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := server.AddPostStartHook(controllerName, func(hookContext genericapiserver.PostStartHookContext) error {
+		if err := s.waitForSync(hookContext.StopCh); err != nil {
+			klog.Errorf("failed to finish post-start-hook %s: %v", controllerName, err)
+			// nolint:nilerr
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		go c.Start(goContext(hookContext), 2)
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Server) installHomeWorkspaceController(ctx context.Context, config *rest.Config, server *genericapiserver.GenericAPIServer) error {
+	controllerName := "kcp-home-workspace-controller"
+	config = rest.AddUserAgent(rest.CopyConfig(config), controllerName)
+	kubeClusterClient, err := kubernetes.NewClusterForConfig(config)
+	if err != nil {
+		return err
+	}
+	kcpClusterClient, err := kcpclient.NewClusterForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	var activityClusterClient kcpclient.ClusterInterface
+	if kubeconfig := s.options.Controllers.HomeWorkspace.ActivityKubeconfig; kubeconfig != "" {
+		activityConfig, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+		if err != nil {
+			return fmt.Errorf("failed to load --home-workspace-activity-kubeconfig: %w", err)
+		}
+		activityClusterClient, err = kcpclient.NewClusterForConfig(rest.AddUserAgent(activityConfig, controllerName))
+		if err != nil {
+			return err
+		}
+	}
+
+	c, err := homeworkspace.NewController(
+		kubeClusterClient,
+		kcpClusterClient,
+		s.kcpSharedInformerFactory.Tenancy().V1alpha1().HomeWorkspacePolicies(),
+		s.kcpSharedInformerFactory.Tenancy().V1alpha1().ClusterWorkspaces(),
+		s.kcpSharedInformerFactory.Tenancy().V1alpha1().ClusterWorkspaceTypes(),
+		s.homeWorkspaceLogins,
+		activityClusterClient,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := server.AddPostStartHook(controllerName, func(hookContext genericapiserver.PostStartHookContext) error {
+		if err := s.waitForSync(hookContext.StopCh); err != nil {
+			klog.Errorf("failed to finish post-start-hook %s: %v", controllerName, err)
+			// nolint:nilerr
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		go c.Start(goContext(hookContext), 2)
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Server) installDeprecatedAPIUsageController(ctx context.Context, config *rest.Config, server *genericapiserver.GenericAPIServer) error {
+	controllerName := "kcp-deprecated-api-usage-controller"
+	config = rest.AddUserAgent(rest.CopyConfig(config), controllerName)
+	kcpClusterClient, err := kcpclient.NewClusterForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	c, err := deprecatedusage.NewController(
+		kcpClusterClient,
+		s.kcpSharedInformerFactory.Apis().V1alpha1().APIBindings(),
+		s.apiextensionsSharedInformerFactory.Apiextensions().V1().CustomResourceDefinitions(),
+		s.deprecatedAPIUsage,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := server.AddPostStartHook(controllerName, func(hookContext genericapiserver.PostStartHookContext) error {
+		if err := s.waitForSync(hookContext.StopCh); err != nil {
+			klog.Errorf("failed to finish post-start-hook %s: %v", controllerName, err)
+			// nolint:nilerr
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		go c.Start(goContext(hookContext), 2)
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Server) installLeaseGCController(ctx context.Context, config *rest.Config, server *genericapiserver.GenericAPIServer) error {
+	controllerName := "kcp-lease-gc-controller"
+	config = rest.AddUserAgent(rest.CopyConfig(config), controllerName)
+	kubeClusterClient, err := kubernetes.NewClusterForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	c, err := leasegc.NewController(
+		kubeClusterClient,
+		s.kubeSharedInformerFactory.Coordination().V1().Leases(),
+		s.options.Controllers.LeaseGC.TTL,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := server.AddPostStartHook(controllerName, func(hookContext genericapiserver.PostStartHookContext) error {
+		if err := s.waitForSync(hookContext.StopCh); err != nil {
+			klog.Errorf("failed to finish post-start-hook %s: %v", controllerName, err)
+			// nolint:nilerr
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		go c.Start(goContext(hookContext), 1)
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Server) waitForSync(stop <-chan struct{}) error {
+	// Wait for shared informer factories to by synced.
+	// factory. Otherwise, informer list calls may go into backoff (before the CRDs are ready) and
+	// take ~10 seconds to succeed.
+	select {
+	case <-stop:
+		return errors.New("timed out waiting for informers to sync")
+	case <-s.syncedCh:
+		return nil
+	}
+}
+
+func (s *Server) installEventSubscriptionController(ctx context.Context, config *rest.Config, server *genericapiserver.GenericAPIServer) error {
+	controllerName := "kcp-eventsubscription-controller"
+	config = rest.AddUserAgent(rest.CopyConfig(config), controllerName)
+	kubeClusterClient, err := kubernetes.NewClusterForConfig(config)
+	if err != nil {
+		return err
+	}
+	kcpClusterClient, err := kcpclient.NewClusterForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	// only the metadata of objects is emitted. For these we can do wildcard requests with
+	// different schemas without risking data loss.
+	metadataClusterClient, err := metadataclient.NewDynamicMetadataClusterClientForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	c, err := eventsubscription.NewController(
+		kcpClusterClient,
+		metadataClusterClient,
+		kubeClusterClient.DiscoveryClient,
+		s.kcpSharedInformerFactory.Tenancy().V1alpha1().ClusterWorkspaces(),
+		s.kcpSharedInformerFactory.Apis().V1alpha1().EventSubscriptions(),
+		s.kcpSharedInformerFactory.Apis().V1alpha1().APIBindings(),
+		server.ExternalAddress,
+		s.options.Extra.DiscoveryPollInterval,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := server.AddPostStartHook(controllerName, func(hookContext genericapiserver.PostStartHookContext) error {
+		if err := s.waitForSync(hookContext.StopCh); err != nil {
+			klog.Errorf("failed to finish post-start-hook %s: %v", controllerName, err)
+			// nolint:nilerr
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		go c.Start(goContext(hookContext), 2)
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Server) installTTLPolicyController(ctx context.Context, config *rest.Config, server *genericapiserver.GenericAPIServer) error {
+	controllerName := "kcp-ttlpolicy-controller"
+	config = rest.AddUserAgent(rest.CopyConfig(config), controllerName)
+	kubeClusterClient, err := kubernetes.NewClusterForConfig(config)
+	if err != nil {
+		return err
+	}
+	kcpClusterClient, err := kcpclient.NewClusterForConfig(config)
+	if err != nil {
+		return err
+	}
+	dynamicClusterClient, err := dynamic.NewClusterForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	c, err := ttlpolicy.NewController(
+		kubeClusterClient,
+		kcpClusterClient,
+		dynamicClusterClient,
+		kubeClusterClient.DiscoveryClient,
+		s.kcpSharedInformerFactory.Apis().V1alpha1().TTLPolicies(),
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := server.AddPostStartHook(controllerName, func(hookContext genericapiserver.PostStartHookContext) error {
+		if err := s.waitForSync(hookContext.StopCh); err != nil {
+			klog.Errorf("failed to finish post-start-hook %s: %v", controllerName, err)
+			// nolint:nilerr
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		go c.Start(goContext(hookContext), 2)
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return nil
 }
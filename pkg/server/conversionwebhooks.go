@@ -0,0 +1,63 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/util/webhook"
+)
+
+// newConversionWebhookServiceResolver returns the resolver of the services of CRD conversion
+// webhooks. The CRDs of any workspace can reference any service, hence services are only
+// resolved in the given namespaces of the cluster kcp runs in. Without namespaces, all
+// service references are rejected, and only conversion webhooks with a URL can be used.
+func newConversionWebhookServiceResolver(namespaces []string) webhook.ServiceResolver {
+	if len(namespaces) == 0 {
+		return &unimplementedServiceResolver{}
+	}
+	return &allowedNamespacesServiceResolver{
+		namespaces: sets.NewString(namespaces...),
+		delegate:   webhook.NewDefaultServiceResolver(),
+	}
+}
+
+// unimplementedServiceResolver is a webhook.ServiceResolver that always returns an error. As a
+// result, CRD conversion webhooks referencing a service are not supported.
+type unimplementedServiceResolver struct{}
+
+// ResolveEndpoint always returns an error that this is not supported.
+func (r *unimplementedServiceResolver) ResolveEndpoint(namespace string, name string, port int32) (*url.URL, error) {
+	return nil, errors.New("CRD conversion webhooks referencing a service are not supported in kcp, use a URL instead")
+}
+
+// allowedNamespacesServiceResolver resolves the services in the allowed namespaces with the
+// delegate, and rejects all others.
+type allowedNamespacesServiceResolver struct {
+	namespaces sets.String
+	delegate   webhook.ServiceResolver
+}
+
+func (r *allowedNamespacesServiceResolver) ResolveEndpoint(namespace string, name string, port int32) (*url.URL, error) {
+	if !r.namespaces.Has(namespace) {
+		return nil, fmt.Errorf("CRD conversion webhooks cannot reference services in namespace %q, only in %v", namespace, r.namespaces.List())
+	}
+	return r.delegate.ResolveEndpoint(namespace, name, port)
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConversionWebhookServiceResolver(t *testing.T) {
+	tests := map[string]struct {
+		namespaces []string
+		namespace  string
+		wantURL    string
+	}{
+		"services are rejected by default":      {namespace: "webhooks"},
+		"services in allowed namespaces":        {namespaces: []string{"kcp-webhooks", "webhooks"}, namespace: "webhooks", wantURL: "https://converter.webhooks.svc:8443"},
+		"services in other namespaces rejected": {namespaces: []string{"kcp-webhooks"}, namespace: "kube-system"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			u, err := newConversionWebhookServiceResolver(tt.namespaces).ResolveEndpoint(tt.namespace, "converter", 8443)
+			if tt.wantURL == "" {
+				require.Error(t, err)
+				require.Nil(t, u)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantURL, u.String())
+		})
+	}
+}
@@ -18,11 +18,9 @@ package server
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"net/http"
 	_ "net/http/pprof"
-	"net/url"
 	"path"
 	"regexp"
 	"sort"
@@ -249,14 +247,16 @@ func WithInClusterServiceAccountRequestRewrite(handler http.Handler, unsafeServi
 	})
 }
 
-// WithWildcardIdentity checks wildcard list/watch requests for an APIExport identity for the resource in the path.
-// If it finds one (e.g. /api/v1/services:identityabcd1234/default/my-service), it places the identity from the path
-// to the context, updates the request to remove the identity from the path, and updates requestInfo.Resource to also
-// remove the identity. Finally, it hands off to the passed in handler to handle the request.
-func WithWildcardIdentity(handler http.Handler) http.Handler {
+// WithResourceIdentity checks requests for an APIExport identity for the resource in the path, both wildcard requests
+// and requests to a single workspace. If it finds one (e.g. /api/v1/services:identityabcd1234/default/my-service), it
+// places the identity from the path to the context, updates the request to remove the identity from the path, and
+// updates requestInfo.Resource to also remove the identity. Finally, it hands off to the passed in handler to handle
+// the request. It runs after authorization, such that requests are authorized for the resource including the identity,
+// e.g. services:identityabcd1234.
+func WithResourceIdentity(handler http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		cluster := request.ClusterFrom(req.Context())
-		if cluster == nil || !cluster.Wildcard {
+		if cluster == nil {
 			handler.ServeHTTP(w, req)
 			return
 		}
@@ -272,7 +272,7 @@ func WithWildcardIdentity(handler http.Handler) http.Handler {
 
 		updatedReq, err := processResourceIdentity(req, requestInfo)
 		if err != nil {
-			klog.Errorf("WithWildcardIdentity: unable to determine resource from path %s", req.URL.Path)
+			klog.Errorf("WithResourceIdentity: unable to determine resource from path %s", req.URL.Path)
 
 			responsewriters.ErrorNegotiated(
 				apierrors.NewInternalError(err),
@@ -483,13 +483,3 @@ func (r *inMemoryResponseWriter) String() string {
 	}
 	return s
 }
-
-// unimplementedServiceResolver is a webhook.ServiceResolver that always returns an error, because
-// we have not implemented support for this yet. As a result, CRD webhook conversions are not
-// supported.
-type unimplementedServiceResolver struct{}
-
-// ResolveEndpoint always returns an error that this is not yet supported.
-func (r *unimplementedServiceResolver) ResolveEndpoint(namespace string, name string, port int32) (*url.URL, error) {
-	return nil, errors.New("CRD webhook conversions are not yet supported in kcp")
-}
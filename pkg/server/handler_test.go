@@ -17,19 +17,25 @@ limitations under the License.
 package server
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
 
+	"github.com/kcp-dev/logicalcluster"
 	"github.com/stretchr/testify/require"
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapifilters "k8s.io/apiserver/pkg/endpoints/filters"
 	"k8s.io/apiserver/pkg/endpoints/request"
 	"sigs.k8s.io/yaml"
 )
@@ -223,3 +229,98 @@ func TestProcessResourceIdentity(t *testing.T) {
 		})
 	}
 }
+
+func TestWithResourceIdentityAuthorization(t *testing.T) {
+	// only grants access to the resources named in the rules, like RBAC does.
+	authz := func(resources ...string) authorizer.Authorizer {
+		return authorizer.AuthorizerFunc(func(ctx context.Context, a authorizer.Attributes) (authorizer.Decision, string, error) {
+			if sets.NewString(resources...).Has(a.GetResource()) {
+				return authorizer.DecisionAllow, "", nil
+			}
+			return authorizer.DecisionNoOpinion, "", nil
+		})
+	}
+
+	tests := map[string]struct {
+		cluster          string
+		path             string
+		authorizer       authorizer.Authorizer
+		expectedCode     int
+		expectedResource string
+		expectedIdentity string
+	}{
+		"single workspace with identity": {
+			cluster:          "root:org:consumer",
+			path:             "/apis/example.dev/v1/widgets:abcd",
+			authorizer:       authz("widgets:abcd"),
+			expectedCode:     http.StatusOK,
+			expectedResource: "widgets",
+			expectedIdentity: "abcd",
+		},
+		"single workspace with identity, only authorized for the resource without identity": {
+			cluster:      "root:org:consumer",
+			path:         "/apis/example.dev/v1/widgets:abcd",
+			authorizer:   authz("widgets"),
+			expectedCode: http.StatusForbidden,
+		},
+		"single workspace with identity, authorized for another identity": {
+			cluster:      "root:org:consumer",
+			path:         "/apis/example.dev/v1/widgets:abcd",
+			authorizer:   authz("widgets:ef01"),
+			expectedCode: http.StatusForbidden,
+		},
+		"single workspace without identity": {
+			cluster:          "root:org:consumer",
+			path:             "/apis/example.dev/v1/widgets",
+			authorizer:       authz("widgets"),
+			expectedCode:     http.StatusOK,
+			expectedResource: "widgets",
+		},
+		"wildcard with identity": {
+			cluster:          "*",
+			path:             "/apis/example.dev/v1/widgets:abcd",
+			authorizer:       authz("widgets:abcd"),
+			expectedCode:     http.StatusOK,
+			expectedResource: "widgets",
+			expectedIdentity: "abcd",
+		},
+		"wildcard with identity, only authorized for the resource without identity": {
+			cluster:      "*",
+			path:         "/apis/example.dev/v1/widgets:abcd",
+			authorizer:   authz("widgets"),
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var served bool
+			var resource, identity string
+			handler := genericapifilters.WithAuthorization(WithResourceIdentity(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				served = true
+				requestInfo, ok := request.RequestInfoFrom(req.Context())
+				require.True(t, ok, "missing requestInfo")
+				resource = requestInfo.Resource
+				identity = IdentityFromContext(req.Context())
+			})), test.authorizer, errorCodecs)
+
+			req := httptest.NewRequest(http.MethodGet, test.path, nil)
+			requestInfoFactory := &request.RequestInfoFactory{
+				APIPrefixes:          sets.NewString("api", "apis"),
+				GrouplessAPIPrefixes: sets.NewString("api"),
+			}
+			requestInfo, err := requestInfoFactory.NewRequestInfo(req)
+			require.NoError(t, err, "error creating requestInfo")
+			ctx := request.WithRequestInfo(req.Context(), requestInfo)
+			ctx = request.WithCluster(ctx, request.Cluster{Name: logicalcluster.New(test.cluster), Wildcard: test.cluster == "*"})
+			ctx = request.WithUser(ctx, &user.DefaultInfo{Name: "provider", Groups: []string{user.AllAuthenticated}})
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req.WithContext(ctx))
+			require.Equal(t, test.expectedCode, rw.Code, "unexpected response code: %s", rw.Body.String())
+			require.Equal(t, test.expectedCode == http.StatusOK, served, "request should only be served if authorized")
+			require.Equal(t, test.expectedResource, resource, "unexpected requestInfo.Resource")
+			require.Equal(t, test.expectedIdentity, identity, "unexpected identity")
+		})
+	}
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package indexes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apiserver/pkg/registry/generic"
+	"k8s.io/apiserver/pkg/storage"
+	"k8s.io/apiserver/pkg/storage/storagebackend"
+	"k8s.io/apiserver/pkg/storage/storagebackend/factory"
+	"k8s.io/client-go/tools/cache"
+)
+
+// LabelIndexes maps resources to the label keys their objects are indexed by in the
+// watch cache.
+type LabelIndexes map[schema.GroupResource][]string
+
+// ParseLabelIndexes parses entries of the form <resource>[.<group>]=<label key>.
+func ParseLabelIndexes(entries []string) (LabelIndexes, error) {
+	indexes := LabelIndexes{}
+	for _, entry := range entries {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid label index %q, expected <resource>[.<group>]=<label key>", entry)
+		}
+		if errs := validation.IsQualifiedName(parts[1]); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid label key in label index %q: %s", entry, strings.Join(errs, ", "))
+		}
+		gr := schema.ParseGroupResource(parts[0])
+		indexes[gr] = append(indexes[gr], parts[1])
+	}
+	for gr := range indexes {
+		sort.Strings(indexes[gr])
+	}
+	return indexes, nil
+}
+
+// IndexName returns the name of the index of a label key, as the watch cache
+// looks it up for the label selectors of list requests.
+func IndexName(key string) string {
+	return "l:" + key
+}
+
+// IndexFunc indexes objects by the value of the label key. Objects without the label
+// are not indexed.
+func IndexFunc(key string) cache.IndexFunc {
+	return func(obj interface{}) ([]string, error) {
+		o, err := meta.Accessor(obj)
+		if err != nil {
+			return nil, err
+		}
+		value, ok := o.GetLabels()[key]
+		if !ok {
+			return nil, nil
+		}
+		return []string{value}, nil
+	}
+}
+
+// WithLabelIndexes wraps a RESTOptionsGetter such that the watch cache of the configured
+// resources maintains indexes by the label keys. Lists served from the watch cache
+// with a label selector requiring an exact value of an indexed key are answered from
+// the index instead of scanning all objects of the resource.
+func WithLabelIndexes(delegate generic.RESTOptionsGetter, indexes LabelIndexes) generic.RESTOptionsGetter {
+	if len(indexes) == 0 {
+		return delegate
+	}
+	return &labelIndexingRESTOptionsGetter{
+		delegate: delegate,
+		indexes:  indexes,
+	}
+}
+
+type labelIndexingRESTOptionsGetter struct {
+	delegate generic.RESTOptionsGetter
+	indexes  LabelIndexes
+}
+
+func (g *labelIndexingRESTOptionsGetter) GetRESTOptions(resource schema.GroupResource) (generic.RESTOptions, error) {
+	options, err := g.delegate.GetRESTOptions(resource)
+	if err != nil {
+		return options, err
+	}
+	keys, ok := g.indexes[resource]
+	if !ok || options.Decorator == nil {
+		return options, nil
+	}
+
+	decorator := options.Decorator
+	options.Decorator = func(
+		config *storagebackend.ConfigForResource,
+		resourcePrefix string,
+		keyFunc func(obj runtime.Object) (string, error),
+		newFunc func() runtime.Object,
+		newListFunc func() runtime.Object,
+		getAttrsFunc storage.AttrFunc,
+		trigger storage.IndexerFuncs,
+		indexers *cache.Indexers,
+	) (storage.Interface, factory.DestroyFunc, error) {
+		merged := cache.Indexers{}
+		if indexers != nil {
+			for name, indexFunc := range *indexers {
+				merged[name] = indexFunc
+			}
+		}
+		for _, key := range keys {
+			merged[IndexName(key)] = IndexFunc(key)
+		}
+		s, destroy, err := decorator(config, resourcePrefix, keyFunc, newFunc, newListFunc, getAttrsFunc, trigger, &merged)
+		if err != nil {
+			return s, destroy, err
+		}
+		return &labelIndexedStorage{Interface: s, keys: keys}, destroy, nil
+	}
+	return options, nil
+}
+
+// labelIndexedStorage marks the label keys as indexed in the predicates of lists, such
+// that the watch cache uses the indexes.
+type labelIndexedStorage struct {
+	storage.Interface
+	keys []string
+}
+
+func (s *labelIndexedStorage) GetToList(ctx context.Context, key string, opts storage.ListOptions, listObj runtime.Object) error {
+	opts.Predicate = s.withIndexLabels(opts.Predicate)
+	return s.Interface.GetToList(ctx, key, opts, listObj)
+}
+
+func (s *labelIndexedStorage) List(ctx context.Context, key string, opts storage.ListOptions, listObj runtime.Object) error {
+	opts.Predicate = s.withIndexLabels(opts.Predicate)
+	return s.Interface.List(ctx, key, opts, listObj)
+}
+
+func (s *labelIndexedStorage) withIndexLabels(pred storage.SelectionPredicate) storage.SelectionPredicate {
+	indexLabels := make([]string, 0, len(pred.IndexLabels)+len(s.keys))
+	indexLabels = append(indexLabels, pred.IndexLabels...)
+	indexLabels = append(indexLabels, s.keys...)
+	pred.IndexLabels = indexLabels
+	return pred
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package indexes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/registry/generic"
+	"k8s.io/apiserver/pkg/storage"
+	"k8s.io/apiserver/pkg/storage/storagebackend"
+	"k8s.io/apiserver/pkg/storage/storagebackend/factory"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestParseLabelIndexes(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []string
+		want    LabelIndexes
+		wantErr bool
+	}{
+		{name: "none", want: LabelIndexes{}},
+		{
+			name:    "core and grouped resources",
+			entries: []string{"deployments.apps=b.kcp.dev/x", "configmaps=a", "deployments.apps=a.kcp.dev/y"},
+			want: LabelIndexes{
+				{Group: "apps", Resource: "deployments"}: {"a.kcp.dev/y", "b.kcp.dev/x"},
+				{Resource: "configmaps"}:                 {"a"},
+			},
+		},
+		{name: "missing key", entries: []string{"configmaps"}, wantErr: true},
+		{name: "missing resource", entries: []string{"=a"}, wantErr: true},
+		{name: "invalid key", entries: []string{"configmaps=a b"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLabelIndexes(tt.entries)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestIndexFunc(t *testing.T) {
+	values, err := IndexFunc("a")(&metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"a": "x"}}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"x"}, values)
+
+	values, err = IndexFunc("a")(&metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"b": "x"}}})
+	require.NoError(t, err)
+	require.Empty(t, values)
+}
+
+type fakeStorage struct {
+	storage.Interface
+	predicate storage.SelectionPredicate
+}
+
+func (s *fakeStorage) List(_ context.Context, _ string, opts storage.ListOptions, _ runtime.Object) error {
+	s.predicate = opts.Predicate
+	return nil
+}
+
+type fakeRESTOptionsGetter struct {
+	indexers *cache.Indexers
+	storage  *fakeStorage
+}
+
+func (g *fakeRESTOptionsGetter) GetRESTOptions(resource schema.GroupResource) (generic.RESTOptions, error) {
+	return generic.RESTOptions{
+		Decorator: func(_ *storagebackend.ConfigForResource, _ string, _ func(obj runtime.Object) (string, error), _ func() runtime.Object, _ func() runtime.Object, _ storage.AttrFunc, _ storage.IndexerFuncs, indexers *cache.Indexers) (storage.Interface, factory.DestroyFunc, error) {
+			g.indexers = indexers
+			return g.storage, func() {}, nil
+		},
+	}, nil
+}
+
+func TestWithLabelIndexes(t *testing.T) {
+	delegate := &fakeRESTOptionsGetter{storage: &fakeStorage{}}
+	getter := WithLabelIndexes(delegate, LabelIndexes{{Group: "apps", Resource: "deployments"}: {"a"}})
+
+	options, err := getter.GetRESTOptions(schema.GroupResource{Resource: "configmaps"})
+	require.NoError(t, err)
+	s, _, err := options.Decorator(nil, "", nil, nil, nil, nil, nil, &cache.Indexers{"f:spec.nodeName": nil})
+	require.NoError(t, err)
+	require.Equal(t, delegate.storage, s, "resources without indexes are not wrapped")
+	require.Equal(t, &cache.Indexers{"f:spec.nodeName": nil}, delegate.indexers)
+
+	options, err = getter.GetRESTOptions(schema.GroupResource{Group: "apps", Resource: "deployments"})
+	require.NoError(t, err)
+	s, _, err = options.Decorator(nil, "", nil, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+	require.Contains(t, *delegate.indexers, "l:a")
+
+	require.NoError(t, s.List(context.Background(), "/", storage.ListOptions{Predicate: storage.SelectionPredicate{IndexFields: []string{"spec.nodeName"}}}, nil))
+	require.Equal(t, []string{"a"}, delegate.storage.predicate.IndexLabels)
+	require.Equal(t, []string{"spec.nodeName"}, delegate.storage.predicate.IndexFields)
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package longrunning
+
+import (
+	"sync"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+var (
+	longRunningRequests = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Namespace:      "kcp",
+			Name:           "long_running_requests",
+			Help:           "Number of active long-running requests, like watches, by verb.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"verb"},
+	)
+
+	// the logical cluster itself is not a label to bound the cardinality,
+	// the debug endpoint tells which one it is.
+	maxLongRunningRequestsPerCluster = metrics.NewGauge(
+		&metrics.GaugeOpts{
+			Namespace:      "kcp",
+			Name:           "long_running_requests_max_per_logical_cluster",
+			Help:           "Highest number of active long-running requests held by a single logical cluster.",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+
+	registerMetrics sync.Once
+)
+
+// RegisterMetrics registers the long-running request metrics.
+func RegisterMetrics() {
+	registerMetrics.Do(func() {
+		legacyregistry.MustRegister(longRunningRequests)
+		legacyregistry.MustRegister(maxLongRunningRequestsPerCluster)
+	})
+}
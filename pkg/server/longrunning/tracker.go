@@ -0,0 +1,251 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package longrunning
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+)
+
+// DebugPath is where the Tracker serves the active long-running requests.
+const DebugPath = "/debug/kcp/long-running-requests"
+
+// defaultLimit is the number of logical clusters and users listed by default.
+const defaultLimit = 100
+
+// Tracker tracks the active long-running requests of a server, like watches,
+// by logical cluster and user, such that operators can find the tenants
+// holding most of them.
+type Tracker struct {
+	now func() time.Time
+
+	lock      sync.Mutex
+	requests  map[*request]struct{}
+	byCluster map[logicalcluster.Name]int
+	// maxPerCluster is the highest count in byCluster.
+	maxPerCluster int
+}
+
+type request struct {
+	cluster  logicalcluster.Name
+	user     string
+	verb     string
+	resource string
+	started  time.Time
+}
+
+// NewTracker returns a Tracker without active requests.
+func NewTracker() *Tracker {
+	return &Tracker{
+		now:       time.Now,
+		requests:  map[*request]struct{}{},
+		byCluster: map[logicalcluster.Name]int{},
+	}
+}
+
+// WithTracking tracks the requests served by handler that longRunning
+// considers long-running. It must run after the RequestInfo and
+// authentication filters.
+func (t *Tracker) WithTracking(handler http.Handler, longRunning apirequest.LongRunningRequestCheck) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		info, ok := apirequest.RequestInfoFrom(req.Context())
+		if !ok || !longRunning(req, info) {
+			handler.ServeHTTP(w, req)
+			return
+		}
+
+		r := &request{
+			verb:    info.Verb,
+			started: t.now(),
+		}
+		if cluster := apirequest.ClusterFrom(req.Context()); cluster != nil {
+			r.cluster = cluster.Name
+		}
+		if user, ok := apirequest.UserFrom(req.Context()); ok {
+			r.user = user.GetName()
+		}
+		if info.IsResourceRequest {
+			r.resource = schema.GroupResource{Group: info.APIGroup, Resource: info.Resource}.String()
+			if info.Subresource != "" {
+				r.resource += "/" + info.Subresource
+			}
+		} else {
+			r.resource = info.Path
+		}
+
+		t.add(r)
+		defer t.remove(r)
+
+		handler.ServeHTTP(w, req)
+	})
+}
+
+func (t *Tracker) add(r *request) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.requests[r] = struct{}{}
+	t.byCluster[r.cluster]++
+	if count := t.byCluster[r.cluster]; count > t.maxPerCluster {
+		t.setMaxPerClusterLocked(count)
+	}
+	longRunningRequests.WithLabelValues(r.verb).Inc()
+}
+
+func (t *Tracker) remove(r *request) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	delete(t.requests, r)
+	count := t.byCluster[r.cluster] - 1
+	if count == 0 {
+		delete(t.byCluster, r.cluster)
+	} else {
+		t.byCluster[r.cluster] = count
+	}
+	if count+1 == t.maxPerCluster {
+		// the cluster might have been the only one at the maximum
+		max := 0
+		for _, count := range t.byCluster {
+			if count > max {
+				max = count
+			}
+		}
+		t.setMaxPerClusterLocked(max)
+	}
+	longRunningRequests.WithLabelValues(r.verb).Dec()
+}
+
+func (t *Tracker) setMaxPerClusterLocked(max int) {
+	t.maxPerCluster = max
+	maxLongRunningRequestsPerCluster.Set(float64(max))
+}
+
+// Summary lists the active long-running requests of a server.
+type Summary struct {
+	// Total is the number of active long-running requests.
+	Total int `json:"total"`
+	// Clusters are the logical clusters with the most active long-running
+	// requests, in descending order.
+	Clusters []Holder `json:"clusters"`
+	// Users are the users with the most active long-running requests, in
+	// descending order.
+	Users []Holder `json:"users"`
+}
+
+// Holder are the active long-running requests of a logical cluster or a user.
+type Holder struct {
+	Name string `json:"name"`
+	// Count is the number of active long-running requests.
+	Count int `json:"count"`
+	// OldestAge is the age of the oldest active long-running request.
+	OldestAge Age `json:"oldestAge"`
+	// Resources are the numbers of active long-running requests by verb and
+	// resource, e.g. "watch configmaps".
+	Resources map[string]int `json:"resources"`
+}
+
+// Age is a duration serialized like "1h2m3s".
+type Age time.Duration
+
+func (d Age) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).Round(time.Second).String())
+}
+
+// Summarize returns the limit logical clusters and users with the most
+// active long-running requests. A non-positive limit lists all of them.
+func (t *Tracker) Summarize(limit int) *Summary {
+	t.lock.Lock()
+	requests := make([]*request, 0, len(t.requests))
+	for r := range t.requests {
+		requests = append(requests, r)
+	}
+	t.lock.Unlock()
+
+	now := t.now()
+	clusters := map[string]*Holder{}
+	users := map[string]*Holder{}
+	for _, r := range requests {
+		for _, h := range []*Holder{holder(clusters, r.cluster.String()), holder(users, r.user)} {
+			h.Count++
+			h.Resources[r.verb+" "+r.resource]++
+			if age := Age(now.Sub(r.started)); age > h.OldestAge {
+				h.OldestAge = age
+			}
+		}
+	}
+
+	return &Summary{
+		Total:    len(requests),
+		Clusters: topHolders(clusters, limit),
+		Users:    topHolders(users, limit),
+	}
+}
+
+func holder(holders map[string]*Holder, name string) *Holder {
+	h, ok := holders[name]
+	if !ok {
+		h = &Holder{Name: name, Resources: map[string]int{}}
+		holders[name] = h
+	}
+	return h
+}
+
+func topHolders(holders map[string]*Holder, limit int) []Holder {
+	sorted := make([]Holder, 0, len(holders))
+	for _, h := range holders {
+		sorted = append(sorted, *h)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Count != sorted[j].Count {
+			return sorted[i].Count > sorted[j].Count
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	if limit > 0 && len(sorted) > limit {
+		sorted = sorted[:limit]
+	}
+	return sorted
+}
+
+// ServeHTTP serves the Summary of the active long-running requests as JSON.
+// The limit query parameter sets the number of logical clusters and users
+// listed, 0 for all of them.
+func (t *Tracker) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	limit := defaultLimit
+	if s := req.URL.Query().Get("limit"); s != "" {
+		var err error
+		if limit, err = strconv.Atoi(s); err != nil {
+			http.Error(w, "invalid limit: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(t.Summarize(limit))
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package longrunning
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/authentication/user"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	genericfilters "k8s.io/apiserver/pkg/server/filters"
+)
+
+func TestTracker(t *testing.T) {
+	now := time.Now()
+	tracker := NewTracker()
+	tracker.now = func() time.Time { return now }
+
+	var started, release sync.WaitGroup
+	release.Add(1)
+	handler := tracker.WithTracking(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Query().Get("watch") == "true" {
+			started.Done()
+			release.Wait()
+		}
+	}), genericfilters.BasicLongRunningRequestCheck(sets.NewString("watch"), sets.NewString()))
+
+	serve := func(cluster, userName, verb, resource string) {
+		info := &apirequest.RequestInfo{IsResourceRequest: true, Verb: verb, APIVersion: "v1", Resource: resource}
+		ctx := apirequest.WithRequestInfo(apirequest.NewContext(), info)
+		ctx = apirequest.WithCluster(ctx, apirequest.Cluster{Name: logicalcluster.New(cluster)})
+		ctx = apirequest.WithUser(ctx, &user.DefaultInfo{Name: userName})
+		url := "/api/v1/" + resource
+		if verb == "watch" {
+			url += "?watch=true"
+		}
+		req := httptest.NewRequest(http.MethodGet, url, nil).WithContext(ctx)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	var done sync.WaitGroup
+	for _, r := range []struct{ cluster, user, resource string }{
+		{"root:org:noisy", "alice", "configmaps"},
+		{"root:org:noisy", "alice", "configmaps"},
+		{"root:org:noisy", "bob", "secrets"},
+		{"root:org:quiet", "bob", "configmaps"},
+	} {
+		started.Add(1)
+		done.Add(1)
+		go func(cluster, user, resource string) {
+			defer done.Done()
+			serve(cluster, user, "watch", resource)
+		}(r.cluster, r.user, r.resource)
+		started.Wait()
+		now = now.Add(time.Minute)
+	}
+	serve("root:org:quiet", "carol", "list", "configmaps")
+
+	summary := tracker.Summarize(0)
+	require.Equal(t, 4, summary.Total)
+	require.Equal(t, []Holder{
+		{Name: "root:org:noisy", Count: 3, OldestAge: Age(4 * time.Minute), Resources: map[string]int{"watch configmaps": 2, "watch secrets": 1}},
+		{Name: "root:org:quiet", Count: 1, OldestAge: Age(time.Minute), Resources: map[string]int{"watch configmaps": 1}},
+	}, summary.Clusters)
+	require.Equal(t, []Holder{
+		{Name: "alice", Count: 2, OldestAge: Age(4 * time.Minute), Resources: map[string]int{"watch configmaps": 2}},
+		{Name: "bob", Count: 2, OldestAge: Age(2 * time.Minute), Resources: map[string]int{"watch secrets": 1, "watch configmaps": 1}},
+	}, summary.Users)
+	require.Equal(t, 3, tracker.maxPerCluster)
+
+	rec := httptest.NewRecorder()
+	tracker.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, DebugPath+"?limit=1", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var served struct {
+		Total    int `json:"total"`
+		Clusters []struct {
+			Name      string `json:"name"`
+			OldestAge string `json:"oldestAge"`
+		} `json:"clusters"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &served))
+	require.Equal(t, 4, served.Total)
+	require.Len(t, served.Clusters, 1)
+	require.Equal(t, "root:org:noisy", served.Clusters[0].Name)
+	require.Equal(t, "4m0s", served.Clusters[0].OldestAge)
+
+	release.Done()
+	done.Wait()
+	require.Equal(t, 0, tracker.Summarize(0).Total)
+	require.Equal(t, 0, tracker.maxPerCluster)
+}
@@ -0,0 +1,275 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapicache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	openapi_v2 "github.com/googleapis/gnostic/openapiv2"
+	"google.golang.org/protobuf/proto"
+
+	apiextensionshelpers "k8s.io/apiextensions-apiserver/pkg/apihelpers"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/controller/openapi/builder"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// defaultTTL is how long chunks and documents are kept after their last use.
+const defaultTTL = 10 * time.Minute
+
+// Cache assembles the OpenAPI v2 documents of logical clusters from chunks, one per
+// served version of a CRD. Chunks are addressed by the content they are built from,
+// i.e. the parts of the CRD spec relevant for that version, such that a schema bound
+// into many logical clusters, e.g. through the same APIExport, is built once per shard.
+// Documents are addressed by the chunks they consist of, such that logical clusters
+// with the same APIs share one document.
+type Cache struct {
+	now   func() time.Time
+	ttl   time.Duration
+	build func(crd *apiextensionsv1.CustomResourceDefinition, version string) (*spec.Swagger, error)
+
+	sourcesLock sync.RWMutex
+	staticSpecs func() []*spec.Swagger
+	listCRDs    func(ctx context.Context) ([]*apiextensionsv1.CustomResourceDefinition, error)
+
+	lock      sync.Mutex
+	chunks    map[string]*chunk
+	documents map[string]*document
+	lastPrune time.Time
+}
+
+type chunk struct {
+	once    sync.Once
+	swagger *spec.Swagger
+	err     error
+
+	// lastUsed is guarded by Cache.lock.
+	lastUsed time.Time
+}
+
+type document struct {
+	etag string
+
+	jsonOnce sync.Once
+	json     []byte
+	jsonErr  error
+
+	protobufOnce sync.Once
+	protobuf     []byte
+	protobufErr  error
+
+	// lastUsed is guarded by Cache.lock.
+	lastUsed time.Time
+}
+
+// NewCache returns an empty Cache. It serves nothing until SetSources is called.
+func NewCache() *Cache {
+	return &Cache{
+		now:       time.Now,
+		ttl:       defaultTTL,
+		build:     buildChunk,
+		chunks:    map[string]*chunk{},
+		documents: map[string]*document{},
+	}
+}
+
+// SetSources sets where the documents are built from: the static specs of the
+// built-in APIs, which are merged in the given order with precedence for the first,
+// and the CRDs of the logical cluster in the context. The static specs are only
+// available after the server has prepared to run, hence they are passed as a func.
+func (c *Cache) SetSources(staticSpecs func() []*spec.Swagger, listCRDs func(ctx context.Context) ([]*apiextensionsv1.CustomResourceDefinition, error)) {
+	c.sourcesLock.Lock()
+	defer c.sourcesLock.Unlock()
+
+	c.staticSpecs = staticSpecs
+	c.listCRDs = listCRDs
+}
+
+func (c *Cache) sources() (func() []*spec.Swagger, func(ctx context.Context) ([]*apiextensionsv1.CustomResourceDefinition, error)) {
+	c.sourcesLock.RLock()
+	defer c.sourcesLock.RUnlock()
+
+	return c.staticSpecs, c.listCRDs
+}
+
+func buildChunk(crd *apiextensionsv1.CustomResourceDefinition, version string) (*spec.Swagger, error) {
+	// the same options as the OpenAPI controller of the apiextensions-apiserver
+	return builder.BuildOpenAPIV2(crd, version, builder.Options{V2: true, StripValueValidation: true, StripNullable: true, AllowNonStructural: false})
+}
+
+type chunkSource struct {
+	key     string
+	crd     *apiextensionsv1.CustomResourceDefinition
+	version string
+}
+
+// chunkSources returns the sources of the chunks of the established CRDs, ordered by
+// CRD name and version. The CRDs are normalized to what the chunks are built from,
+// such that equal content is addressed by the same key independent of the logical
+// cluster or the metadata of the CRD.
+func chunkSources(crds []*apiextensionsv1.CustomResourceDefinition) ([]chunkSource, error) {
+	var sources []chunkSource
+	for _, crd := range crds {
+		if !apiextensionshelpers.IsCRDConditionTrue(crd, apiextensionsv1.Established) {
+			continue
+		}
+		for _, v := range crd.Spec.Versions {
+			if !v.Served {
+				continue
+			}
+
+			normalized := &apiextensionsv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: crd.Spec.Names.Plural + "." + crd.Spec.Group},
+				Spec:       crd.Spec,
+			}
+			normalized.Spec.Versions = []apiextensionsv1.CustomResourceDefinitionVersion{v}
+			normalized.Spec.Conversion = nil
+
+			bs, err := json.Marshal(normalized.Spec)
+			if err != nil {
+				return nil, fmt.Errorf("failed to hash CRD %s version %s: %w", normalized.Name, v.Name, err)
+			}
+			sum := sha256.Sum256(bs)
+			sources = append(sources, chunkSource{key: hex.EncodeToString(sum[:]), crd: normalized, version: v.Name})
+		}
+	}
+
+	sort.Slice(sources, func(i, j int) bool {
+		if sources[i].crd.Name != sources[j].crd.Name {
+			return sources[i].crd.Name < sources[j].crd.Name
+		}
+		return sources[i].version < sources[j].version
+	})
+
+	return sources, nil
+}
+
+// document returns the document consisting of the static specs and the chunks of the
+// given CRDs, building what is not cached yet. Concurrent callers for the same content
+// wait for a single build.
+func (c *Cache) document(staticSpecs []*spec.Swagger, crds []*apiextensionsv1.CustomResourceDefinition) (*document, error) {
+	sources, err := chunkSources(crds)
+	if err != nil {
+		return nil, err
+	}
+
+	h := sha256.New()
+	for _, s := range sources {
+		h.Write([]byte(s.key)) // nolint:errcheck
+	}
+	key := hex.EncodeToString(h.Sum(nil))
+
+	c.lock.Lock()
+	now := c.now()
+	c.pruneLocked(now)
+	doc, found := c.documents[key]
+	if !found {
+		doc = &document{etag: key}
+		c.documents[key] = doc
+	}
+	doc.lastUsed = now
+	chunks := make([]*chunk, 0, len(sources))
+	for _, s := range sources {
+		ch, found := c.chunks[s.key]
+		if !found {
+			ch = &chunk{}
+			c.chunks[s.key] = ch
+		}
+		ch.lastUsed = now
+		chunks = append(chunks, ch)
+	}
+	c.lock.Unlock()
+
+	doc.jsonOnce.Do(func() {
+		specs := make([]*spec.Swagger, 0, len(staticSpecs)-1+len(chunks))
+		specs = append(specs, staticSpecs[1:]...)
+		for i, ch := range chunks {
+			ch.once.Do(func() {
+				ch.swagger, ch.err = c.build(sources[i].crd, sources[i].version)
+			})
+			if ch.err != nil {
+				doc.jsonErr = fmt.Errorf("failed to build OpenAPI of CRD %s version %s: %w", sources[i].crd.Name, sources[i].version, ch.err)
+				return
+			}
+			specs = append(specs, ch.swagger)
+		}
+
+		merged, err := builder.MergeSpecs(staticSpecs[0], specs...)
+		if err != nil {
+			doc.jsonErr = fmt.Errorf("failed to merge OpenAPI specs: %w", err)
+			return
+		}
+		doc.json, doc.jsonErr = json.Marshal(merged)
+	})
+	if doc.jsonErr != nil {
+		// don't keep failures, the next request tries again.
+		c.lock.Lock()
+		if c.documents[key] == doc {
+			delete(c.documents, key)
+		}
+		for i, ch := range chunks {
+			if ch.err != nil && c.chunks[sources[i].key] == ch {
+				delete(c.chunks, sources[i].key)
+			}
+		}
+		c.lock.Unlock()
+		return nil, doc.jsonErr
+	}
+
+	return doc, nil
+}
+
+// Protobuf returns the document in the protobuf encoding of gnostic.
+func (d *document) Protobuf() ([]byte, error) {
+	d.protobufOnce.Do(func() {
+		parsed, err := openapi_v2.ParseDocument(d.json)
+		if err != nil {
+			d.protobufErr = err
+			return
+		}
+		d.protobuf, d.protobufErr = proto.Marshal(parsed)
+	})
+	return d.protobuf, d.protobufErr
+}
+
+// pruneLocked drops the chunks and documents which were not used within the TTL, at
+// most once per TTL.
+func (c *Cache) pruneLocked(now time.Time) {
+	if now.Sub(c.lastPrune) < c.ttl {
+		return
+	}
+	c.lastPrune = now
+
+	for key, ch := range c.chunks {
+		if now.Sub(ch.lastUsed) >= c.ttl {
+			delete(c.chunks, key)
+		}
+	}
+	for key, doc := range c.documents {
+		if now.Sub(doc.lastUsed) >= c.ttl {
+			delete(c.documents, key)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapicache
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func newCRD(cluster, group, plural string, schemaType string, versions ...string) *apiextensionsv1.CustomResourceDefinition {
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        plural + "." + group,
+			ClusterName: cluster,
+		},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: group,
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: plural},
+			Scope: apiextensionsv1.NamespaceScoped,
+		},
+		Status: apiextensionsv1.CustomResourceDefinitionStatus{
+			Conditions: []apiextensionsv1.CustomResourceDefinitionCondition{{Type: apiextensionsv1.Established, Status: apiextensionsv1.ConditionTrue}},
+		},
+	}
+	for _, v := range versions {
+		crd.Spec.Versions = append(crd.Spec.Versions, apiextensionsv1.CustomResourceDefinitionVersion{
+			Name:   v,
+			Served: true,
+			Schema: &apiextensionsv1.CustomResourceValidation{OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{Type: schemaType}},
+		})
+	}
+	return crd
+}
+
+func swagger(path, definition string) *spec.Swagger {
+	return &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+		Swagger:     "2.0",
+		Info:        &spec.Info{InfoProps: spec.InfoProps{Title: "kcp", Version: "v1"}},
+		Paths:       &spec.Paths{Paths: map[string]spec.PathItem{path: {}}},
+		Definitions: spec.Definitions{definition: spec.Schema{}},
+	}}
+}
+
+type fakeBuilder struct {
+	lock   sync.Mutex
+	builds map[string]int
+}
+
+func (b *fakeBuilder) build(crd *apiextensionsv1.CustomResourceDefinition, version string) (*spec.Swagger, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.builds[crd.Name+"/"+version]++
+	return swagger("/apis/"+crd.Spec.Group+"/"+version+"/"+crd.Spec.Names.Plural, crd.Spec.Group+"."+version+"."+crd.Spec.Names.Plural), nil
+}
+
+func newTestCache() (*Cache, *fakeBuilder) {
+	b := &fakeBuilder{builds: map[string]int{}}
+	c := NewCache()
+	c.build = b.build
+	return c, b
+}
+
+func TestDocument(t *testing.T) {
+	static := []*spec.Swagger{swagger("/api/v1/pods", "io.k8s.api.core.v1.Pod")}
+
+	c, b := newTestCache()
+
+	docA, err := c.document(static, []*apiextensionsv1.CustomResourceDefinition{
+		newCRD("root:a", "example.dev", "widgets", "object", "v1", "v2"),
+		newCRD("root:a", "example.dev", "gadgets", "object", "v1"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"widgets.example.dev/v1": 1, "widgets.example.dev/v2": 1, "gadgets.example.dev/v1": 1}, b.builds)
+
+	var merged spec.Swagger
+	require.NoError(t, json.Unmarshal(docA.json, &merged))
+	require.Contains(t, merged.Definitions, "io.k8s.api.core.v1.Pod")
+	require.Contains(t, merged.Definitions, "example.dev.v2.widgets")
+	require.Contains(t, merged.Paths.Paths, "/apis/example.dev/v1/gadgets")
+
+	// the same APIs in another logical cluster, in another order, share the document
+	docB, err := c.document(static, []*apiextensionsv1.CustomResourceDefinition{
+		newCRD("root:b", "example.dev", "gadgets", "object", "v1"),
+		newCRD("root:b", "example.dev", "widgets", "object", "v1", "v2"),
+	})
+	require.NoError(t, err)
+	require.Same(t, docA, docB)
+
+	// another document shares the chunk of the equal widgets, but not of the different gadgets
+	docC, err := c.document(static, []*apiextensionsv1.CustomResourceDefinition{
+		newCRD("root:c", "example.dev", "widgets", "object", "v1", "v2"),
+		newCRD("root:c", "example.dev", "gadgets", "string", "v1"),
+	})
+	require.NoError(t, err)
+	require.NotEqual(t, docA.etag, docC.etag)
+	require.Equal(t, map[string]int{"widgets.example.dev/v1": 1, "widgets.example.dev/v2": 1, "gadgets.example.dev/v1": 2}, b.builds)
+	require.Len(t, c.chunks, 4)
+
+	// not established CRDs and not served versions are left out
+	notEstablished := newCRD("root:d", "example.dev", "gadgets", "object", "v1")
+	notEstablished.Status.Conditions = nil
+	notServed := newCRD("root:d", "example.dev", "widgets", "object", "v1", "v2")
+	notServed.Spec.Versions[1].Served = false
+	docD, err := c.document(static, []*apiextensionsv1.CustomResourceDefinition{notEstablished, notServed})
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(docD.json, &merged))
+	require.NotContains(t, merged.Paths.Paths, "/apis/example.dev/v1/gadgets")
+	require.Contains(t, merged.Paths.Paths, "/apis/example.dev/v1/widgets")
+	require.NotContains(t, merged.Paths.Paths, "/apis/example.dev/v2/widgets")
+}
+
+func TestPrune(t *testing.T) {
+	static := []*spec.Swagger{swagger("/api/v1/pods", "io.k8s.api.core.v1.Pod")}
+
+	c, b := newTestCache()
+	now := time.Unix(0, 0)
+	c.now = func() time.Time { return now }
+	c.lastPrune = now
+
+	widgets := []*apiextensionsv1.CustomResourceDefinition{newCRD("root:a", "example.dev", "widgets", "object", "v1")}
+	gadgets := []*apiextensionsv1.CustomResourceDefinition{newCRD("root:a", "example.dev", "gadgets", "object", "v1")}
+
+	_, err := c.document(static, widgets)
+	require.NoError(t, err)
+	now = now.Add(c.ttl / 2)
+	_, err = c.document(static, gadgets)
+	require.NoError(t, err)
+	require.Len(t, c.documents, 2)
+
+	// widgets are unused for the TTL, gadgets only for half of it
+	now = now.Add(c.ttl / 2)
+	_, err = c.document(static, gadgets)
+	require.NoError(t, err)
+	require.Len(t, c.documents, 1)
+	require.Len(t, c.chunks, 1)
+
+	_, err = c.document(static, widgets)
+	require.NoError(t, err)
+	require.Equal(t, 2, b.builds["widgets.example.dev/v1"], "pruned chunk is built again")
+	require.Equal(t, 1, b.builds["gadgets.example.dev/v1"])
+}
+
+func TestWithOpenAPI(t *testing.T) {
+	c, _ := newTestCache()
+
+	delegated := 0
+	handler := c.WithOpenAPI(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		delegated++
+	}))
+
+	serve := func(cluster request.Cluster, path string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		req = req.WithContext(request.WithCluster(req.Context(), cluster))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+	orgA := request.Cluster{Name: logicalcluster.New("root:a")}
+
+	serve(orgA, Path, nil)
+	require.Equal(t, 1, delegated, "requests before SetSources must be delegated")
+
+	var static *spec.Swagger
+	c.SetSources(
+		func() []*spec.Swagger { return []*spec.Swagger{static} },
+		func(ctx context.Context) ([]*apiextensionsv1.CustomResourceDefinition, error) {
+			return []*apiextensionsv1.CustomResourceDefinition{newCRD(request.ClusterFrom(ctx).Name.String(), "example.dev", "widgets", "object", "v1")}, nil
+		},
+	)
+	serve(orgA, Path, nil)
+	require.Equal(t, 2, delegated, "requests before the static spec is prepared must be delegated")
+
+	static = swagger("/api/v1/pods", "io.k8s.api.core.v1.Pod")
+	serve(request.Cluster{Name: logicalcluster.Wildcard, Wildcard: true}, Path, nil)
+	serve(orgA, "/api/v1", nil)
+	require.Equal(t, 4, delegated)
+
+	rec := serve(orgA, Path, nil)
+	require.Equal(t, 4, delegated)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, mimeJSON, rec.Header().Get("Content-Type"))
+	require.Contains(t, rec.Body.String(), "/apis/example.dev/v1/widgets")
+	etag := rec.Header().Get("Etag")
+	require.NotEmpty(t, etag)
+
+	rec = serve(request.Cluster{Name: logicalcluster.New("root:b")}, Path, http.Header{"If-None-Match": []string{etag}})
+	require.Equal(t, http.StatusNotModified, rec.Code, "the same APIs in another logical cluster have the same document")
+
+	rec = serve(orgA, Path, http.Header{"Accept": []string{mimeProtobuf + ", " + mimeJSON}})
+	require.Equal(t, mimeProtobuf, rec.Header().Get("Content-Type"))
+	require.NotEqual(t, etag, rec.Header().Get("Etag"))
+}
+
+func TestAccepts(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   bool
+	}{
+		{"", false},
+		{mimeJSON, false},
+		{mimeProtobuf, true},
+		{mimeProtobuf + ", application/json", true},
+		{"application/json;q=0.9, " + mimeProtobuf, false},
+		{"*/*", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			req := httptest.NewRequest("GET", Path, nil)
+			req.Header.Set("Accept", tt.accept)
+			require.Equal(t, tt.want, accepts(req, mimeProtobuf))
+		})
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapicache
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+const (
+	// Path is where the OpenAPI v2 document of a logical cluster is served.
+	Path = "/openapi/v2"
+
+	mimeJSON     = "application/json"
+	mimeProtobuf = "application/com.github.proto-openapi.spec.v2@v1.0+protobuf"
+)
+
+// WithOpenAPI serves the OpenAPI v2 documents of logical clusters from the cache. Other
+// requests, requests for the wildcard cluster and requests before SetSources is called
+// are passed to handler. It must run after the cluster, authentication and authorization
+// filters.
+func (c *Cache) WithOpenAPI(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != Path || (req.Method != http.MethodGet && req.Method != http.MethodHead) {
+			handler.ServeHTTP(w, req)
+			return
+		}
+		cluster := request.ClusterFrom(req.Context())
+		if cluster == nil || cluster.Wildcard || cluster.Name.Empty() {
+			handler.ServeHTTP(w, req)
+			return
+		}
+		staticSpecsFunc, listCRDs := c.sources()
+		if staticSpecsFunc == nil {
+			handler.ServeHTTP(w, req)
+			return
+		}
+		staticSpecs := staticSpecsFunc()
+		if len(staticSpecs) == 0 {
+			handler.ServeHTTP(w, req)
+			return
+		}
+		for _, s := range staticSpecs {
+			if s == nil {
+				// not prepared to run yet
+				handler.ServeHTTP(w, req)
+				return
+			}
+		}
+
+		crds, err := listCRDs(req.Context())
+		if err != nil {
+			responsewriters.InternalError(w, req, fmt.Errorf("failed to list CRDs: %w", err))
+			return
+		}
+		doc, err := c.document(staticSpecs, crds)
+		if err != nil {
+			responsewriters.InternalError(w, req, err)
+			return
+		}
+
+		data, contentType, etag := doc.json, mimeJSON, doc.etag
+		if accepts(req, mimeProtobuf) {
+			data, err = doc.Protobuf()
+			if err != nil {
+				responsewriters.InternalError(w, req, fmt.Errorf("failed to encode OpenAPI document as protobuf: %w", err))
+				return
+			}
+			contentType, etag = mimeProtobuf, etag+"-pb"
+		}
+
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Etag", `"`+etag+`"`)
+		w.Header().Add("Vary", "Accept")
+		// ServeContent answers If-None-Match with the Etag.
+		http.ServeContent(w, req, Path, time.Time{}, bytes.NewReader(data))
+	})
+}
+
+// accepts returns whether the Accept header of req lists the mime type before
+// JSON, or without JSON.
+func accepts(req *http.Request, mimeType string) bool {
+	for _, accept := range strings.Split(req.Header.Get("Accept"), ",") {
+		accept = strings.TrimSpace(strings.SplitN(accept, ";", 2)[0])
+		switch accept {
+		case mimeType:
+			return true
+		case mimeJSON, "*/*":
+			return false
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"crypto"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"k8s.io/client-go/util/keyutil"
+
+	"github.com/kcp-dev/kcp/pkg/certificates"
+)
+
+// ACME configures issuing the certificates of Certificates through an ACME server.
+type ACME struct {
+	// DirectoryURL is the directory of the ACME server. Certificates are not issued if empty.
+	DirectoryURL string
+	// Email is the contact of the ACME account.
+	Email string
+	// AccountKeyFile holds the PEM encoded private key of the ACME account.
+	AccountKeyFile string
+	// DNSPropagationDelay is waited for after programming challenge records.
+	DNSPropagationDelay time.Duration
+}
+
+func NewACME() *ACME {
+	return &ACME{}
+}
+
+func (s *ACME) Validate() []error {
+	if s == nil {
+		return nil
+	}
+
+	var errs []error
+
+	if s.DirectoryURL != "" {
+		parsed, err := url.Parse(s.DirectoryURL)
+		if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+			errs = append(errs, fmt.Errorf("--acme-directory-url must be a http or https URL"))
+		}
+		if s.AccountKeyFile == "" {
+			errs = append(errs, fmt.Errorf("--acme-account-key-file is required with --acme-directory-url"))
+		}
+	}
+	if s.DNSPropagationDelay < 0 {
+		errs = append(errs, fmt.Errorf("--acme-dns-propagation-delay must not be negative"))
+	}
+
+	return errs
+}
+
+func (s *ACME) AddFlags(fs *pflag.FlagSet) {
+	if s == nil {
+		return
+	}
+
+	fs.StringVar(&s.DirectoryURL, "acme-directory-url", s.DirectoryURL,
+		"Directory URL of an ACME server, e.g. https://acme-v02.api.letsencrypt.org/directory, to issue the certificates of Certificates through. Certificates are not issued if empty.")
+	fs.StringVar(&s.Email, "acme-email", s.Email,
+		"Contact email address of the ACME account.")
+	fs.StringVar(&s.AccountKeyFile, "acme-account-key-file", s.AccountKeyFile,
+		"File holding the PEM encoded RSA or ECDSA private key of the ACME account. If relative, it is relative to --root-directory.")
+	fs.DurationVar(&s.DNSPropagationDelay, "acme-dns-propagation-delay", s.DNSPropagationDelay,
+		"Duration to wait after programming DNS-01 challenge records through the DNS providers before the ACME server validates them.")
+}
+
+// Issuer returns the ACME issuer, or nil if none is configured.
+func (s *ACME) Issuer() (certificates.Issuer, error) {
+	if s.DirectoryURL == "" {
+		return nil, nil
+	}
+
+	key, err := keyutil.PrivateKeyFromFile(s.AccountKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ACME account key: %w", err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("ACME account key in %s is not a signing key", s.AccountKeyFile)
+	}
+	return certificates.NewACMEIssuer(s.DirectoryURL, signer, s.Email, s.DNSPropagationDelay), nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	APIExportIdentityBackendSecret = "secret"
+	APIExportIdentityBackendVault  = "vault"
+)
+
+// APIExportIdentity configures where the identity keys of APIExports are stored.
+type APIExportIdentity struct {
+	// Backend is either "secret" or "vault".
+	Backend  string
+	CacheTTL time.Duration
+
+	VaultAddress    string
+	VaultCAFile     string
+	VaultTokenFile  string
+	VaultMount      string
+	VaultPathPrefix string
+}
+
+func NewAPIExportIdentity() *APIExportIdentity {
+	return &APIExportIdentity{
+		Backend:         APIExportIdentityBackendSecret,
+		CacheTTL:        5 * time.Minute,
+		VaultMount:      "secret",
+		VaultPathPrefix: "kcp/apiexport-identities",
+	}
+}
+
+func (s *APIExportIdentity) AddFlags(fs *pflag.FlagSet) {
+	if s == nil {
+		return
+	}
+
+	fs.StringVar(&s.Backend, "apiexport-identity-backend", s.Backend,
+		"Where the identity keys of APIExports are stored: 'secret' for Secrets in the workspaces of the APIExports, 'vault' for a Vault KV version 2 secrets engine.")
+	fs.DurationVar(&s.CacheTTL, "apiexport-identity-cache-ttl", s.CacheTTL,
+		"Duration identity keys read from Vault are cached. Changed keys are detected when they are read again. Not used with the secret backend.")
+	fs.StringVar(&s.VaultAddress, "apiexport-identity-vault-address", s.VaultAddress,
+		"URL of the Vault server storing the identity keys of APIExports, e.g. https://vault.example.com:8200.")
+	fs.StringVar(&s.VaultCAFile, "apiexport-identity-vault-ca-file", s.VaultCAFile,
+		"File holding the CA bundle to verify the Vault server with. The system roots are used if empty.")
+	fs.StringVar(&s.VaultTokenFile, "apiexport-identity-vault-token-file", s.VaultTokenFile,
+		"File holding the Vault token. It is read on every request, such that it can be renewed without restarting kcp.")
+	fs.StringVar(&s.VaultMount, "apiexport-identity-vault-mount", s.VaultMount,
+		"Mount path of the Vault KV version 2 secrets engine storing the identity keys of APIExports.")
+	fs.StringVar(&s.VaultPathPrefix, "apiexport-identity-vault-path-prefix", s.VaultPathPrefix,
+		"Path under the Vault mount the identity keys of APIExports are stored under, by logical cluster, namespace and name.")
+}
+
+func (s *APIExportIdentity) Validate() []error {
+	if s == nil {
+		return nil
+	}
+
+	var errs []error
+	switch s.Backend {
+	case APIExportIdentityBackendSecret:
+	case APIExportIdentityBackendVault:
+		if s.VaultAddress == "" {
+			errs = append(errs, fmt.Errorf("--apiexport-identity-vault-address is required with --apiexport-identity-backend=%s", APIExportIdentityBackendVault))
+		}
+		if s.VaultTokenFile == "" {
+			errs = append(errs, fmt.Errorf("--apiexport-identity-vault-token-file is required with --apiexport-identity-backend=%s", APIExportIdentityBackendVault))
+		}
+		if s.VaultMount == "" {
+			errs = append(errs, fmt.Errorf("--apiexport-identity-vault-mount must not be empty"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("--apiexport-identity-backend must be %q or %q", APIExportIdentityBackendSecret, APIExportIdentityBackendVault))
+	}
+	if s.CacheTTL < 0 {
+		errs = append(errs, fmt.Errorf("--apiexport-identity-cache-ttl must not be negative"))
+	}
+	return errs
+}
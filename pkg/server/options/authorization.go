@@ -27,6 +27,8 @@ import (
 	coreexternalversions "k8s.io/client-go/informers"
 
 	"github.com/kcp-dev/kcp/pkg/authorization"
+	apisinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/apis/v1alpha1"
+	tenancyinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/tenancy/v1alpha1"
 	"github.com/kcp-dev/kcp/pkg/client/listers/tenancy/v1alpha1"
 )
 
@@ -80,7 +82,7 @@ func (s *Authorization) AddFlags(fs *pflag.FlagSet) {
 			"contacting the 'core' kubernetes server.")
 }
 
-func (s *Authorization) ApplyTo(config *genericapiserver.Config, informer coreexternalversions.SharedInformerFactory, workspaceLister v1alpha1.ClusterWorkspaceLister) error {
+func (s *Authorization) ApplyTo(config *genericapiserver.Config, informer coreexternalversions.SharedInformerFactory, workspaceLister v1alpha1.ClusterWorkspaceLister, denyPolicyInformer tenancyinformers.DenyPolicyInformer, apiBindingInformer apisinformers.APIBindingInformer, apiExportInformer apisinformers.APIExportInformer) error {
 	var authorizers []authorizer.Authorizer
 
 	// group authorizer
@@ -97,6 +99,12 @@ func (s *Authorization) ApplyTo(config *genericapiserver.Config, informer coreex
 		authorizers = append(authorizers, a)
 	}
 
+	// deny policies are evaluated before RBAC, and can only deny or have no opinion
+	authorizers = append(authorizers, authorization.NewDenyPolicyAuthorizer(denyPolicyInformer, workspaceLister))
+
+	// status of resources fenced by their APIExport is only written with the identity of the APIExport
+	authorizers = append(authorizers, authorization.NewStatusFencingAuthorizer(apiBindingInformer, apiExportInformer))
+
 	// kcp authorizers
 	bootstrapAuth, bootstrapRules := authorization.NewBootstrapPolicyAuthorizer(informer)
 	localAuth, localResolver := authorization.NewLocalAuthorizer(informer)
@@ -0,0 +1,72 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// Bootstrap configures the reconciliation of the content of the root workspace.
+type Bootstrap struct {
+	// RootManifestsDir holds manifests overriding or extending the embedded ones.
+	RootManifestsDir string
+	ResyncPeriod     time.Duration
+	Prune            bool
+}
+
+func NewBootstrap() *Bootstrap {
+	return &Bootstrap{
+		ResyncPeriod: 10 * time.Minute,
+		Prune:        true,
+	}
+}
+
+func (s *Bootstrap) AddFlags(fs *pflag.FlagSet) {
+	if s == nil {
+		return
+	}
+
+	fs.StringVar(&s.RootManifestsDir, "root-bootstrap-manifests-dir", s.RootManifestsDir,
+		"Directory with manifests of the root workspace. Objects in them replace embedded ones of the same kind, namespace and name.")
+	fs.DurationVar(&s.ResyncPeriod, "root-bootstrap-resync-period", s.ResyncPeriod,
+		"How often the content of the root workspace is reconciled against its manifests. 0 reconciles only once on start.")
+	fs.BoolVar(&s.Prune, "root-bootstrap-prune", s.Prune,
+		"Delete objects of the root workspace that were created from manifests which no longer exist.")
+}
+
+func (s *Bootstrap) Validate() []error {
+	if s == nil {
+		return nil
+	}
+
+	var errs []error
+	if s.ResyncPeriod < 0 {
+		errs = append(errs, fmt.Errorf("--root-bootstrap-resync-period must not be negative"))
+	}
+	if s.RootManifestsDir != "" {
+		if info, err := os.Stat(s.RootManifestsDir); err != nil {
+			errs = append(errs, fmt.Errorf("--root-bootstrap-manifests-dir: %w", err))
+		} else if !info.IsDir() {
+			errs = append(errs, fmt.Errorf("--root-bootstrap-manifests-dir %q is not a directory", s.RootManifestsDir))
+		}
+	}
+	return errs
+}
@@ -29,7 +29,13 @@ import (
 	"k8s.io/klog/v2"
 	kcmoptions "k8s.io/kubernetes/cmd/kube-controller-manager/app/options"
 
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apiexportusage"
 	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apiresource"
+	"github.com/kcp-dev/kcp/pkg/reconciler/coordination/leasegc"
+	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/clusterworkspace"
+	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/expiration"
+	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/hibernation"
+	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/homeworkspace"
 	"github.com/kcp-dev/kcp/pkg/reconciler/workload/heartbeat"
 )
 
@@ -37,12 +43,24 @@ type Controllers struct {
 	EnableAll                bool
 	IndividuallyEnabled      []string
 	ApiResource              ApiResourceController
+	APIExportUsage           APIExportUsageController
+	LeaseGC                  LeaseGCController
 	WorkloadClusterHeartbeat WorkloadClusterHeartbeatController
+	WorkspaceExpiration      WorkspaceExpirationController
+	WorkspaceHibernation     WorkspaceHibernationController
+	WorkspaceScheduler       WorkspaceSchedulerController
+	HomeWorkspace            HomeWorkspaceController
 	SAController             kcmoptions.SAControllerOptions
 }
 
 type ApiResourceController = apiresource.Options
+type APIExportUsageController = apiexportusage.Options
+type LeaseGCController = leasegc.Options
 type WorkloadClusterHeartbeatController = heartbeat.Options
+type WorkspaceExpirationController = expiration.Options
+type WorkspaceHibernationController = hibernation.Options
+type WorkspaceSchedulerController = clusterworkspace.Options
+type HomeWorkspaceController = homeworkspace.Options
 
 var kcmDefaults *kcmoptions.KubeControllerManagerOptions
 
@@ -60,7 +78,13 @@ func NewControllers() *Controllers {
 		EnableAll: true,
 
 		ApiResource:              *apiresource.DefaultOptions(),
+		APIExportUsage:           *apiexportusage.DefaultOptions(),
+		LeaseGC:                  *leasegc.DefaultOptions(),
 		WorkloadClusterHeartbeat: *heartbeat.DefaultOptions(),
+		WorkspaceExpiration:      *expiration.DefaultOptions(),
+		WorkspaceHibernation:     *hibernation.DefaultOptions(),
+		WorkspaceScheduler:       *clusterworkspace.DefaultOptions(),
+		HomeWorkspace:            *homeworkspace.DefaultOptions(),
 		SAController:             *kcmDefaults.SAController,
 	}
 }
@@ -72,7 +96,13 @@ func (c *Controllers) AddFlags(fs *pflag.FlagSet) {
 	fs.MarkHidden("unsupported-run-individual-controllers") //nolint:errcheck
 
 	apiresource.BindOptions(&c.ApiResource, fs)
+	apiexportusage.BindOptions(&c.APIExportUsage, fs)
+	leasegc.BindOptions(&c.LeaseGC, fs)
 	heartbeat.BindOptions(&c.WorkloadClusterHeartbeat, fs)
+	expiration.BindOptions(&c.WorkspaceExpiration, fs)
+	hibernation.BindOptions(&c.WorkspaceHibernation, fs)
+	clusterworkspace.BindOptions(&c.WorkspaceScheduler, fs)
+	homeworkspace.BindOptions(&c.HomeWorkspace, fs)
 
 	c.SAController.AddFlags(fs)
 }
@@ -109,9 +139,27 @@ func (c *Controllers) Validate() []error {
 	if err := c.ApiResource.Validate(); err != nil {
 		errs = append(errs, err)
 	}
+	if err := c.APIExportUsage.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.LeaseGC.Validate(); err != nil {
+		errs = append(errs, err)
+	}
 	if err := c.WorkloadClusterHeartbeat.Validate(); err != nil {
 		errs = append(errs, err)
 	}
+	if err := c.WorkspaceExpiration.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.WorkspaceHibernation.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.WorkspaceScheduler.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.HomeWorkspace.Validate(); err != nil {
+		errs = append(errs, err)
+	}
 	if saErrs := c.SAController.Validate(); saErrs != nil {
 		errs = append(errs, saErrs...)
 	}
@@ -0,0 +1,78 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/kcp-dev/kcp/pkg/dns"
+)
+
+// DNS configures the DNS providers programming the DNSRecords of DNSZones.
+type DNS struct {
+	// ProviderWebhooks maps provider names to the URLs of webhooks programming the records.
+	ProviderWebhooks map[string]string
+}
+
+func NewDNS() *DNS {
+	return &DNS{
+		ProviderWebhooks: map[string]string{},
+	}
+}
+
+func (s *DNS) Validate() []error {
+	if s == nil {
+		return nil
+	}
+
+	var errs []error
+
+	for name, u := range s.ProviderWebhooks {
+		if name == "" {
+			errs = append(errs, fmt.Errorf("--dns-provider-webhook must have a non-empty provider name"))
+		}
+		parsed, err := url.Parse(u)
+		if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+			errs = append(errs, fmt.Errorf("--dns-provider-webhook of provider %q must be a http or https URL", name))
+		}
+	}
+
+	return errs
+}
+
+func (s *DNS) AddFlags(fs *pflag.FlagSet) {
+	if s == nil {
+		return
+	}
+
+	fs.StringToStringVar(&s.ProviderWebhooks, "dns-provider-webhook", s.ProviderWebhooks,
+		"A DNS provider of the form <name>=<url>, referenced by DNSZones. The records of the zones are posted as JSON to the URL. Can be repeated.")
+}
+
+// Providers returns the configured DNS providers by name.
+func (s *DNS) Providers() dns.Providers {
+	providers := dns.Providers{}
+	for name, u := range s.ProviderWebhooks {
+		providers[name] = &dns.WebhookProvider{URL: u, Client: &http.Client{Timeout: 30 * time.Second}}
+	}
+	return providers
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 
 	"github.com/spf13/pflag"
+	"go.etcd.io/etcd/server/v3/embed"
 )
 
 type EmbeddedEtcd struct {
@@ -29,6 +30,13 @@ type EmbeddedEtcd struct {
 	PeerPort     string
 	ClientPort   string
 	WalSizeBytes int64
+
+	Name           string
+	PeerHost       string
+	ClientHost     string
+	InitialCluster string
+	ClusterState   string
+	JoinEndpoints  []string
 }
 
 func NewEmbeddedEtcd() *EmbeddedEtcd {
@@ -44,6 +52,13 @@ func (e *EmbeddedEtcd) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&e.PeerPort, "embedded-etcd-peer-port", e.PeerPort, "Port for embedded etcd peer")
 	fs.StringVar(&e.ClientPort, "embedded-etcd-client-port", e.ClientPort, "Port for embedded etcd client")
 	fs.Int64Var(&e.WalSizeBytes, "embedded-etcd-wal-size-bytes", e.WalSizeBytes, "Size of embedded etcd WAL")
+
+	fs.StringVar(&e.Name, "embedded-etcd-name", e.Name, "Member name of the embedded etcd, unique within an embedded etcd cluster")
+	fs.StringVar(&e.PeerHost, "embedded-etcd-peer-host", e.PeerHost, "Host name other embedded etcd members use to reach this member. If empty, embedded etcd only listens on localhost.")
+	fs.StringVar(&e.ClientHost, "embedded-etcd-client-host", e.ClientHost, "Host name other embedded etcd members use to reach the client port of this member, e.g. to join the cluster through it. If empty, the embedded etcd client port only listens on localhost.")
+	fs.StringVar(&e.InitialCluster, "embedded-etcd-initial-cluster", e.InitialCluster, "Initial embedded etcd cluster configuration, e.g. kcp-0=https://kcp-0:2380,kcp-1=https://kcp-1:2380. All members must share the CA in <embedded-etcd-directory>/secrets/ca.")
+	fs.StringVar(&e.ClusterState, "embedded-etcd-initial-cluster-state", e.ClusterState, "Initial embedded etcd cluster state, one of new or existing")
+	fs.StringSliceVar(&e.JoinEndpoints, "embedded-etcd-join-endpoints", e.JoinEndpoints, "Client URLs of an existing embedded etcd cluster to join as learner, i.e. of members started with --embedded-etcd-client-host. The member is promoted to a voting member once it has caught up.")
 }
 
 func (e *EmbeddedEtcd) Validate() []error {
@@ -56,6 +71,18 @@ func (e *EmbeddedEtcd) Validate() []error {
 		if e.ClientPort == "" {
 			errs = append(errs, fmt.Errorf("--embedded-etcd-client-port must be specified"))
 		}
+		if e.ClusterState != "" && e.ClusterState != embed.ClusterStateFlagNew && e.ClusterState != embed.ClusterStateFlagExisting {
+			errs = append(errs, fmt.Errorf("--embedded-etcd-initial-cluster-state must be %q or %q", embed.ClusterStateFlagNew, embed.ClusterStateFlagExisting))
+		}
+		if (e.InitialCluster != "" || len(e.JoinEndpoints) > 0) && e.PeerHost == "" {
+			errs = append(errs, fmt.Errorf("--embedded-etcd-peer-host must be specified for an embedded etcd cluster"))
+		}
+		if (e.InitialCluster != "" || len(e.JoinEndpoints) > 0) && e.Name == "" {
+			errs = append(errs, fmt.Errorf("--embedded-etcd-name must be specified for an embedded etcd cluster"))
+		}
+		if e.InitialCluster != "" && len(e.JoinEndpoints) > 0 {
+			errs = append(errs, fmt.Errorf("--embedded-etcd-initial-cluster and --embedded-etcd-join-endpoints are mutually exclusive"))
+		}
 	}
 
 	return errs
@@ -0,0 +1,80 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmbeddedEtcdValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(e *EmbeddedEtcd)
+		wantErr bool
+	}{
+		{name: "single node", modify: func(e *EmbeddedEtcd) {}},
+		{name: "disabled ignores everything", modify: func(e *EmbeddedEtcd) {
+			e.Enabled = false
+			e.PeerPort = ""
+			e.ClusterState = "bogus"
+		}},
+		{name: "initial cluster", modify: func(e *EmbeddedEtcd) {
+			e.Name = "kcp-0"
+			e.PeerHost = "kcp-0"
+			e.InitialCluster = "kcp-0=https://kcp-0:2380,kcp-1=https://kcp-1:2380"
+			e.ClusterState = "new"
+		}},
+		{name: "join", modify: func(e *EmbeddedEtcd) {
+			e.Name = "kcp-2"
+			e.PeerHost = "kcp-2"
+			e.JoinEndpoints = []string{"https://kcp-0:2379"}
+		}},
+		{name: "missing peer port", wantErr: true, modify: func(e *EmbeddedEtcd) { e.PeerPort = "" }},
+		{name: "missing client port", wantErr: true, modify: func(e *EmbeddedEtcd) { e.ClientPort = "" }},
+		{name: "invalid cluster state", wantErr: true, modify: func(e *EmbeddedEtcd) { e.ClusterState = "bogus" }},
+		{name: "cluster without peer host", wantErr: true, modify: func(e *EmbeddedEtcd) {
+			e.Name = "kcp-0"
+			e.InitialCluster = "kcp-0=https://kcp-0:2380"
+		}},
+		{name: "join without name", wantErr: true, modify: func(e *EmbeddedEtcd) {
+			e.PeerHost = "kcp-2"
+			e.JoinEndpoints = []string{"https://kcp-0:2379"}
+		}},
+		{name: "initial cluster and join", wantErr: true, modify: func(e *EmbeddedEtcd) {
+			e.Name = "kcp-2"
+			e.PeerHost = "kcp-2"
+			e.InitialCluster = "kcp-0=https://kcp-0:2380"
+			e.JoinEndpoints = []string{"https://kcp-0:2379"}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewEmbeddedEtcd()
+			e.Enabled = true
+			tt.modify(e)
+
+			errs := e.Validate()
+			if tt.wantErr {
+				require.NotEmpty(t, errs)
+			} else {
+				require.Empty(t, errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kcp-dev/kcp/pkg/eventbus"
+)
+
+// EventSinks configures streaming audit and workspace lifecycle events to event buses.
+type EventSinks struct {
+	// Config is the path of a file with the sinks.
+	Config string
+	// DrainTimeout is how long buffered events are still delivered on shutdown.
+	DrainTimeout time.Duration
+}
+
+// EventSinksConfiguration is the content of the --event-sinks-config file.
+type EventSinksConfiguration struct {
+	// Source is the CloudEvents source of the events. Defaults to the external address of kcp.
+	Source string      `json:"source,omitempty"`
+	Sinks  []EventSink `json:"sinks"`
+}
+
+// EventSink is an event bus events are delivered to.
+type EventSink struct {
+	// Name identifies the sink in logs and metrics.
+	Name string `json:"name"`
+	// Type is cloudevents or kafka-rest.
+	Type string `json:"type"`
+	// URL is the http or https URL of a CloudEvents receiver, or of a Kafka REST proxy.
+	URL string `json:"url"`
+	// Topic is the Kafka topic events are produced to.
+	Topic string `json:"topic,omitempty"`
+	// Batch sends a CloudEvents batch per request instead of an event per request.
+	Batch bool `json:"batch,omitempty"`
+	// Headers are added to the requests of the sink.
+	Headers map[string]string `json:"headers,omitempty"`
+	// TokenFile is the path of a bearer token sent to the sink.
+	TokenFile string `json:"tokenFile,omitempty"`
+	// CAFile is the PEM encoded CA bundle to verify the sink with. The system roots are
+	// used if empty.
+	CAFile string `json:"caFile,omitempty"`
+	// Timeout of a delivery. Defaults to 10s.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// BufferSize is the number of events buffered for the sink. Defaults to 1000.
+	BufferSize int `json:"bufferSize,omitempty"`
+	// BatchSize is the maximum number of events delivered at once. Defaults to 100.
+	BatchSize int             `json:"batchSize,omitempty"`
+	Filter    EventSinkFilter `json:"filter,omitempty"`
+}
+
+// EventSinkFilter selects the events of a sink. Empty fields select all.
+type EventSinkFilter struct {
+	// Types are patterns of event types, e.g. dev.kcp.workspace.*.
+	Types []string `json:"types,omitempty"`
+	// Workspaces are the workspaces, including their descendants, whose events are
+	// selected, e.g. root:acme for an organization.
+	Workspaces []string `json:"workspaces,omitempty"`
+}
+
+func NewEventSinks() *EventSinks {
+	return &EventSinks{
+		DrainTimeout: 10 * time.Second,
+	}
+}
+
+// Enabled returns whether any sink is configured.
+func (s *EventSinks) Enabled() bool {
+	return s.Config != ""
+}
+
+func (s *EventSinks) AddFlags(fs *pflag.FlagSet) {
+	if s == nil {
+		return
+	}
+
+	fs.StringVar(&s.Config, "event-sinks-config", s.Config,
+		"Path to a file with CloudEvents HTTP or Kafka REST proxy sinks that audit and workspace lifecycle events are streamed to.")
+	fs.DurationVar(&s.DrainTimeout, "event-sinks-drain-timeout", s.DrainTimeout,
+		"How long buffered events are still delivered to the event sinks on shutdown.")
+}
+
+func (s *EventSinks) Validate() []error {
+	if s == nil || s.Config == "" {
+		return nil
+	}
+
+	var errs []error
+	if s.DrainTimeout < 0 {
+		errs = append(errs, fmt.Errorf("--event-sinks-drain-timeout must not be negative"))
+	}
+	if _, err := s.NewBus("kcp"); err != nil {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// NewBus returns a bus delivering to the configured sinks. The source of the events
+// defaults to the given one.
+func (s *EventSinks) NewBus(defaultSource string) (*eventbus.Bus, error) {
+	bs, err := ioutil.ReadFile(s.Config)
+	if err != nil {
+		return nil, err
+	}
+	var cfg EventSinksConfiguration
+	if err := yaml.UnmarshalStrict(bs, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", s.Config, err)
+	}
+	if len(cfg.Sinks) == 0 {
+		return nil, fmt.Errorf("%s: sinks must not be empty", s.Config)
+	}
+	source := cfg.Source
+	if source == "" {
+		source = defaultSource
+	}
+
+	var sinks []eventbus.SinkConfig
+	seen := map[string]bool{}
+	for i, e := range cfg.Sinks {
+		if e.Name == "" {
+			return nil, fmt.Errorf("%s: sinks[%d].name is required", s.Config, i)
+		}
+		if seen[e.Name] {
+			return nil, fmt.Errorf("%s: sinks[%d].name %q is not unique", s.Config, i, e.Name)
+		}
+		seen[e.Name] = true
+		if e.BufferSize < 0 || e.BatchSize < 0 {
+			return nil, fmt.Errorf("%s: sinks[%d].bufferSize and batchSize must not be negative", s.Config, i)
+		}
+
+		timeout := 10 * time.Second
+		if e.Timeout != nil {
+			timeout = e.Timeout.Duration
+		}
+		var token string
+		if e.TokenFile != "" {
+			bs, err := ioutil.ReadFile(e.TokenFile)
+			if err != nil {
+				return nil, fmt.Errorf("%s: sinks[%d].tokenFile: %w", s.Config, i, err)
+			}
+			token = strings.TrimSpace(string(bs))
+		}
+		var tlsConfig *tls.Config
+		if e.CAFile != "" {
+			ca, err := ioutil.ReadFile(e.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("%s: sinks[%d].caFile: %w", s.Config, i, err)
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(ca) {
+				return nil, fmt.Errorf("%s: sinks[%d].caFile has no valid certificates", s.Config, i)
+			}
+			tlsConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+		}
+		headers := map[string]string{}
+		for k, v := range e.Headers {
+			headers[k] = v
+		}
+		if token != "" {
+			headers["Authorization"] = "Bearer " + token
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		client := &http.Client{Timeout: timeout, Transport: transport}
+
+		parsed, err := url.Parse(e.URL)
+		if err != nil || parsed.Host == "" {
+			return nil, fmt.Errorf("%s: sinks[%d].url is invalid", s.Config, i)
+		}
+
+		var sink eventbus.Sink
+		switch e.Type {
+		case "cloudevents":
+			if parsed.Scheme != "https" && parsed.Scheme != "http" {
+				return nil, fmt.Errorf("%s: sinks[%d].url must be a http or https URL", s.Config, i)
+			}
+			sink = &eventbus.CloudEventsSink{SinkName: e.Name, URL: e.URL, Batch: e.Batch, Headers: headers, Client: client}
+		case "kafka-rest":
+			if parsed.Scheme != "https" && parsed.Scheme != "http" {
+				return nil, fmt.Errorf("%s: sinks[%d].url must be the http or https URL of a Kafka REST proxy", s.Config, i)
+			}
+			if e.Topic == "" {
+				return nil, fmt.Errorf("%s: sinks[%d].topic is required", s.Config, i)
+			}
+			sink = &eventbus.KafkaRESTSink{SinkName: e.Name, URL: e.URL, Topic: e.Topic, Headers: headers, Client: client}
+		default:
+			return nil, fmt.Errorf("%s: sinks[%d].type must be cloudevents or kafka-rest", s.Config, i)
+		}
+
+		sinks = append(sinks, eventbus.SinkConfig{
+			Sink:       sink,
+			Filter:     eventbus.Filter{Types: e.Filter.Types, Workspaces: e.Filter.Workspaces},
+			BufferSize: e.BufferSize,
+			BatchSize:  e.BatchSize,
+		})
+	}
+	return eventbus.NewBus(source, sinks), nil
+}
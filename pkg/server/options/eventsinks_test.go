@@ -0,0 +1,108 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventSinksNewBus(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{
+			name: "all types",
+			config: `
+source: https://kcp.example.com
+sinks:
+- name: receiver
+  type: cloudevents
+  url: https://events.example.com
+  batch: true
+  headers:
+    X-Tenant: acme
+- name: kafka
+  type: kafka-rest
+  url: http://kafka-rest.example.com:8082
+  topic: kcp-audit
+  bufferSize: 10000
+  batchSize: 500
+  timeout: 30s
+  filter:
+    types: ["dev.kcp.audit.event"]
+    workspaces: ["root:acme"]
+`,
+		},
+		{
+			name:    "no sinks",
+			config:  "sinks: []\n",
+			wantErr: "sinks must not be empty",
+		},
+		{
+			name:    "unknown field",
+			config:  "sinks:\n- name: a\n  type: kafka-rest\n  url: https://kafka.example.com\n  topics: [a]\n",
+			wantErr: "unknown field",
+		},
+		{
+			name:    "unknown type",
+			config:  "sinks:\n- name: a\n  type: amqp\n  url: amqp://rabbit.example.com\n",
+			wantErr: "type must be cloudevents or kafka-rest",
+		},
+		{
+			name:    "kafka-rest with kafka url",
+			config:  "sinks:\n- name: a\n  type: kafka-rest\n  url: kafka://kafka.example.com:9092\n  topic: kcp\n",
+			wantErr: "must be the http or https URL of a Kafka REST proxy",
+		},
+		{
+			name:    "kafka without topic",
+			config:  "sinks:\n- name: a\n  type: kafka-rest\n  url: https://kafka.example.com\n",
+			wantErr: "topic is required",
+		},
+		{
+			name:    "duplicate name",
+			config:  "sinks:\n- name: a\n  type: cloudevents\n  url: https://a.example.com\n- name: a\n  type: cloudevents\n  url: https://b.example.com\n",
+			wantErr: "not unique",
+		},
+		{
+			name:    "missing token file",
+			config:  "sinks:\n- name: a\n  type: cloudevents\n  url: https://a.example.com\n  tokenFile: /does/not/exist\n",
+			wantErr: "tokenFile",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "sinks.yaml")
+			require.NoError(t, ioutil.WriteFile(path, []byte(tt.config), 0600))
+
+			bus, err := (&EventSinks{Config: path}).NewBus("kcp")
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, bus.Wants("dev.kcp.workspace.created", "root:other"))
+			require.True(t, bus.Wants("dev.kcp.audit.event", "root:acme:team"))
+		})
+	}
+}
@@ -103,6 +103,18 @@ var (
 		"authentication-admin-token-path", // Path to which the administrative token hash should be written at startup. If this is relative, it is relative to --root-directory.
 		"kubeconfig-path",                 // Path to which the administrative kubeconfig should be written at startup.
 
+		// KCP Group Resolution flags
+		"group-resolution-cache-size",      // Maximum number of users whose resolved groups are cached.
+		"group-resolution-cache-ttl",       // Duration resolved groups are cached. Changes of group membership in the provider take up to this long to take effect.
+		"group-resolution-prefix",          // Prefix prepended to the resolved groups, e.g. 'scim:'. Resolved groups starting with 'system:' are ignored.
+		"group-resolution-scim-token-file", // File holding the bearer token to authenticate against the SCIM service provider.
+		"group-resolution-scim-url",        // Base URL of a SCIM 2.0 service provider, e.g. https://idp.example.com/scim/v2, to resolve the groups of authenticated users from.
+
+		// KCP Token Exchange flags
+		"token-exchange-audiences", // Audiences of which the internal tokens of the front-proxy must carry one.
+		"token-exchange-issuer",    // Issuer of the internal tokens the front-proxy exchanges external bearer tokens for. Internal tokens are not accepted if empty.
+		"token-exchange-key-files", // Files holding the PEM encoded public keys the internal tokens of the front-proxy are verified with.
+
 		// Kubernetes ServiceAccount Token Controller
 		"concurrent-serviceaccount-token-syncs", // The number of service account token objects that are allowed to sync concurrently. Larger number = more responsive token generation, but more CPU (and network) load
 		"service-account-private-key-file",      // Filename containing a PEM-encoded private RSA or ECDSA key used to sign service account tokens.
@@ -127,12 +139,45 @@ var (
 		"tracing-config-file", // File with apiserver tracing configuration.
 
 		// KCP flags
-		"discovery-poll-interval",     // Polling interval for dynamic discovery informers.
-		"enable-sharding",             // Enable delegating to peer kcp shards.
-		"profiler-address",            // [Address]:port to bind the profiler to
-		"root-directory",              // Root directory.
-		"shard-kubeconfig-file",       // Kubeconfig holding admin(!) credentials to peer kcp shards.
-		"experimental-bind-free-port", // Bind to a free port. --secure-bind-port must be 0. Use the admin.kubeconfig to extract the chosen port.
+		"acme-account-key-file",                 // File holding the PEM encoded RSA or ECDSA private key of the ACME account. If relative, it is relative to --root-directory.
+		"acme-directory-url",                    // Directory URL of an ACME server, e.g. https://acme-v02.api.letsencrypt.org/directory, to issue the certificates of Certificates through. Certificates are not issued if empty.
+		"acme-dns-propagation-delay",            // Duration to wait after programming DNS-01 challenge records through the DNS providers before the ACME server validates them.
+		"acme-email",                            // Contact email address of the ACME account.
+		"admission-plugin-order",                // Relative order of the given admission plugins, e.g. a,b to run a before b. The given plugins take the positions they have among each other in the default order.
+		"apiexport-identity-backend",            // Where the identity keys of APIExports are stored: 'secret' for Secrets in the workspaces of the APIExports, 'vault' for a Vault KV version 2 secrets engine.
+		"apiexport-identity-cache-ttl",          // Duration identity keys read from Vault are cached. Changed keys are detected when they are read again. Not used with the secret backend.
+		"apiexport-identity-vault-address",      // URL of the Vault server storing the identity keys of APIExports, e.g. https://vault.example.com:8200.
+		"apiexport-identity-vault-ca-file",      // File holding the CA bundle to verify the Vault server with. The system roots are used if empty.
+		"apiexport-identity-vault-mount",        // Mount path of the Vault KV version 2 secrets engine storing the identity keys of APIExports.
+		"apiexport-identity-vault-path-prefix",  // Path under the Vault mount the identity keys of APIExports are stored under, by logical cluster, namespace and name.
+		"apiexport-identity-vault-token-file",   // File holding the Vault token. It is read on every request, such that it can be renewed without restarting kcp.
+		"certificate-secret",                    // A secret of the form <namespace>/<name>=<directory>, whose keys (e.g. tls.crt, tls.key, ca.crt) are written into the directory before start and kept up to date.
+		"certificate-secret-kubeconfig",         // Kubeconfig of the cluster holding the --certificate-secret secrets. In-cluster configuration is used if empty.
+		"conversion-webhook-service-namespaces", // Namespaces of the cluster kcp runs in whose services CRD conversion webhooks of all workspaces can reference. Conversion webhooks referencing a service are rejected if empty, only those with a URL are called.
+		"discovery-poll-interval",               // Polling interval for dynamic discovery informers.
+		"dns-provider-webhook",                  // A DNS provider of the form <name>=<url>, referenced by DNSZones. The records of the zones are posted as JSON to the URL. Can be repeated.
+		"enable-fault-injection",                // Developer mode: serve /debug/kcp/faults to delay or fail storage operations and drop watch events on demand, for resilience testing. Never enable in production.
+		"enable-sharding",                       // Enable delegating to peer kcp shards.
+		"event-sinks-config",                    // Path to a file with CloudEvents HTTP or Kafka REST proxy sinks that audit and workspace lifecycle events are streamed to.
+		"event-sinks-drain-timeout",             // How long buffered events are still delivered to the event sinks on shutdown.
+		"metering-csv-directory",                // Directory hourly workspace usage records are appended to, in a CSV file per day. If relative, it is relative to --root-directory.
+		"metering-prometheus",                   // Expose the workspace usage records of the last hour as metrics, labeled by workspace.
+		"metering-remote-url",                   // URL hourly workspace usage records are posted to as JSON.
+		"metering-sample-interval",              // How often the number of objects of all workspaces is sampled for metering. The highest sample of an hour is recorded.
+		"placement-extenders-config",            // Path to a file with extender webhooks that veto or score the locations namespaces are placed on, in the order they are consulted.
+		"profiler-address",                      // [Address]:port to bind the profiler to
+		"provider-identity-burst",               // Burst of the requests of API providers allowed per APIExport identity on top of --provider-identity-qps.
+		"provider-identity-qps",                 // Rate of the requests of API providers for the resources of their APIExport, by its identity or to a workspace binding them, allowed per APIExport identity. Requests above the rate are rejected with 429 Too Many Requests. 0 disables the limit.
+		"root-bootstrap-manifests-dir",          // Directory with manifests of the root workspace. Objects in them replace embedded ones of the same kind, namespace and name.
+		"root-bootstrap-prune",                  // Delete objects of the root workspace that were created from manifests which no longer exist.
+		"root-bootstrap-resync-period",          // How often the content of the root workspace is reconciled against its manifests. 0 reconciles only once on start.
+		"root-directory",                        // Root directory.
+		"shard-kubeconfig-file",                 // Kubeconfig holding admin(!) credentials to peer kcp shards.
+		"shard-name",                            // Name of the ClusterWorkspaceShard of this kcp instance, which its version, features and system resources are published in.
+		"watch-cache-label-indexes",             // Label keys the watch cache indexes objects of a resource by, in the form <resource>[.<group>]=<label key>, e.g. deployments.apps=example.dev/team. Lists served from the watch cache with a selector requiring a value of an indexed key use the index.
+		"workload-identity-audiences",           // Audiences of the tokens of ServiceAccounts synced to workload clusters. The API audiences of kcp are used if empty.
+		"workload-identity-token-expiration",    // Lifetime of the tokens of ServiceAccounts synced to workload clusters. They are replaced after 80% of it.
+		"experimental-bind-free-port",           // Bind to a free port. --secure-bind-port must be 0. Use the admin.kubeconfig to extract the chosen port.
 
 		// secure serving flags
 		"bind-address",                     // The IP address on which to listen for the --secure-port port. The associated interface(s) must be reachable by the rest of the cluster, and by CLI/web clients. If blank or an unspecified address (0.0.0.0 or ::), all interfaces will be used.
@@ -148,18 +193,30 @@ var (
 		"tls-sni-cert-key",                 // A pair of x509 certificate and private key file paths, optionally suffixed with a list of domain patterns which are fully qualified domain names, possibly with prefixed wildcard segments. The domain patterns also allow IP addresses, but IPs should only be used if the apiserver has visibility to the IP address requested by a client. If no domain patterns are provided, the names of the certificate are extracted. Non-wildcard matches trump over wildcard matches, explicit domain patterns trump over extracted names. For multiple key/certificate pairs, use the --tls-sni-cert-key multiple times. Examples: "example.crt,example.key" or "foo.crt,foo.key:*.foo.com,foo.com".
 
 		// Embedded etcd flags
-		"embedded-etcd-client-port",    // Port for embedded etcd client
-		"embedded-etcd-directory",      // Directory for embedded etcd
-		"embedded-etcd-peer-port",      // Port for embedded etcd peer
-		"embedded-etcd-wal-size-bytes", // Size of embedded etcd WAL
+		"embedded-etcd-client-host",           // Host name other embedded etcd members use to reach the client port of this member, e.g. to join the cluster through it. If empty, the embedded etcd client port only listens on localhost.
+		"embedded-etcd-client-port",           // Port for embedded etcd client
+		"embedded-etcd-directory",             // Directory for embedded etcd
+		"embedded-etcd-initial-cluster",       // Initial embedded etcd cluster configuration, e.g. kcp-0=https://kcp-0:2380,kcp-1=https://kcp-1:2380. All members must share the CA in <embedded-etcd-directory>/secrets/ca.
+		"embedded-etcd-initial-cluster-state", // Initial embedded etcd cluster state, one of new or existing
+		"embedded-etcd-join-endpoints",        // Client URLs of an existing embedded etcd cluster to join as learner, i.e. of members started with --embedded-etcd-client-host. The member is promoted to a voting member once it has caught up.
+		"embedded-etcd-name",                  // Member name of the embedded etcd, unique within an embedded etcd cluster
+		"embedded-etcd-peer-host",             // Host name other embedded etcd members use to reach this member. If empty, embedded etcd only listens on localhost.
+		"embedded-etcd-peer-port",             // Port for embedded etcd peer
+		"embedded-etcd-wal-size-bytes",        // Size of embedded etcd WAL
 
 		// KCP Controllers flags
 		"auto-publish-apis",                      // If true, the APIs imported from physical clusters will be published automatically as CRDs
+		"apiexport-usage-interval",               // How often the objects of the resources of every APIExport are counted across all binding workspaces, for the APIExport status and metrics. 0 disables counting.
 		"apiresource-controller-threads",         // Number of threads to use for the apiresource controller.
+		"home-workspace-activity-kubeconfig",     // Kubeconfig reaching the workspaces of all shards, e.g. through the front-proxy, with which the requests of users to this shard are recorded in their home workspaces on other shards. Required with multiple shards and HomeWorkspacePolicies with reapAfter, as home workspaces are reaped otherwise although their owners use other shards.
+		"lease-gc-ttl",                           // Amount of time after their expiry after which the leases of per-workspace leader elections are deleted. 0 disables deletion.
 		"run-controllers",                        // Run the controllers in-process
 		"run-virtual-workspaces",                 // Run the virtual workspaces apiservers in-process
 		"unsupported-run-individual-controllers", // Run individual controllers in-process. The controller names can change at any time.
 		"workload-cluster-heartbeat-threshold",   // Amount of time to wait for a successful heartbeat before marking the cluster as not ready.
+		"workspace-expiration-warning-period",    // Amount of time before their expiration that workspaces are marked with the WorkspaceExpiring condition.
+		"workspace-hibernation-idle-timeout",     // Amount of time without API activity after which the synced workloads of a workspace are scaled down. 0 disables hibernation.
+		"workspace-initialization-concurrency",   // Maximum number of workspaces initializing at the same time. Further scheduled workspaces wait, ordered by priority and round-robin across organizations. 0 means unlimited.
 
 		// generic flags
 		"cors-allowed-origins",                 // List of allowed origins for CORS, comma separated.  An allowed origin can be a regular expression to support subdomain matching. If this list is empty CORS will not be enabled.
@@ -0,0 +1,119 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	genericapiserver "k8s.io/apiserver/pkg/server"
+
+	"github.com/kcp-dev/kcp/pkg/authentication/groups"
+)
+
+// GroupResolution configures resolving the groups of authenticated users from an
+// external provider, in addition to the groups the authenticators return.
+type GroupResolution struct {
+	// SCIMURL is the base URL of a SCIM 2.0 service provider. Group resolution is
+	// disabled if empty.
+	SCIMURL string
+	// SCIMTokenFile holds the bearer token to authenticate against the SCIM service provider.
+	SCIMTokenFile string
+	// GroupPrefix is prepended to all resolved groups.
+	GroupPrefix string
+	// CacheTTL is how long resolved groups are cached.
+	CacheTTL time.Duration
+	// CacheSize is the maximum number of users whose groups are cached.
+	CacheSize int
+}
+
+func NewGroupResolution() *GroupResolution {
+	return &GroupResolution{
+		CacheTTL:  5 * time.Minute,
+		CacheSize: 10000,
+	}
+}
+
+func (s *GroupResolution) Validate() []error {
+	if s == nil {
+		return nil
+	}
+
+	var errs []error
+
+	if s.SCIMURL != "" && !strings.HasPrefix(s.SCIMURL, "https://") && !strings.HasPrefix(s.SCIMURL, "http://") {
+		errs = append(errs, fmt.Errorf("--group-resolution-scim-url must be a http or https URL"))
+	}
+	if s.SCIMURL == "" && s.SCIMTokenFile != "" {
+		errs = append(errs, fmt.Errorf("--group-resolution-scim-token-file requires --group-resolution-scim-url"))
+	}
+	if s.CacheTTL <= 0 {
+		errs = append(errs, fmt.Errorf("--group-resolution-cache-ttl must be positive"))
+	}
+	if s.CacheSize <= 0 {
+		errs = append(errs, fmt.Errorf("--group-resolution-cache-size must be positive"))
+	}
+
+	return errs
+}
+
+func (s *GroupResolution) AddFlags(fs *pflag.FlagSet) {
+	if s == nil {
+		return
+	}
+
+	fs.StringVar(&s.SCIMURL, "group-resolution-scim-url", s.SCIMURL,
+		"Base URL of a SCIM 2.0 service provider, e.g. https://idp.example.com/scim/v2, to resolve the groups of authenticated users from. "+
+			"The resolved groups are added to the groups of the user.")
+	fs.StringVar(&s.SCIMTokenFile, "group-resolution-scim-token-file", s.SCIMTokenFile,
+		"File holding the bearer token to authenticate against the SCIM service provider.")
+	fs.StringVar(&s.GroupPrefix, "group-resolution-prefix", s.GroupPrefix,
+		"Prefix prepended to the resolved groups, e.g. 'scim:'. Resolved groups starting with 'system:' are ignored.")
+	fs.DurationVar(&s.CacheTTL, "group-resolution-cache-ttl", s.CacheTTL,
+		"Duration resolved groups are cached. Changes of group membership in the provider take up to this long to take effect.")
+	fs.IntVar(&s.CacheSize, "group-resolution-cache-size", s.CacheSize,
+		"Maximum number of users whose resolved groups are cached.")
+}
+
+func (s *GroupResolution) ApplyTo(config *genericapiserver.Config) error {
+	if s.SCIMURL == "" {
+		return nil
+	}
+
+	var token string
+	if s.SCIMTokenFile != "" {
+		bs, err := ioutil.ReadFile(s.SCIMTokenFile)
+		if err != nil {
+			return err
+		}
+		token = strings.TrimSpace(string(bs))
+	}
+
+	resolver, err := groups.NewSCIMResolver(s.SCIMURL, token, 10*time.Second)
+	if err != nil {
+		return err
+	}
+	resolver = groups.NewCachingResolver(resolver, s.CacheTTL, s.CacheSize)
+
+	config.Authentication.Authenticator = groups.WithResolvedGroups(config.Authentication.Authenticator, resolver, s.GroupPrefix)
+
+	return nil
+}
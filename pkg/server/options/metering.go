@@ -0,0 +1,103 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/kcp-dev/kcp/pkg/metering"
+)
+
+// Metering configures aggregating the usage of workspaces into hourly records
+// and exporting them for chargeback.
+type Metering struct {
+	// SampleInterval is how often object counts are sampled.
+	SampleInterval time.Duration
+	// CSVDirectory is the directory usage records are written to as CSV files.
+	CSVDirectory string
+	// RemoteURL is an endpoint usage records are posted to as JSON.
+	RemoteURL string
+	// Prometheus enables exposing usage records as metrics.
+	Prometheus bool
+}
+
+func NewMetering() *Metering {
+	return &Metering{
+		SampleInterval: 5 * time.Minute,
+	}
+}
+
+// Enabled returns whether any exporter is configured.
+func (s *Metering) Enabled() bool {
+	return s.CSVDirectory != "" || s.RemoteURL != "" || s.Prometheus
+}
+
+func (s *Metering) Validate() []error {
+	if s == nil {
+		return nil
+	}
+
+	var errs []error
+
+	if s.SampleInterval <= 0 || s.SampleInterval > metering.Period {
+		errs = append(errs, fmt.Errorf("--metering-sample-interval must be positive and at most %s", metering.Period))
+	}
+	if s.RemoteURL != "" && !strings.HasPrefix(s.RemoteURL, "https://") && !strings.HasPrefix(s.RemoteURL, "http://") {
+		errs = append(errs, fmt.Errorf("--metering-remote-url must be a http or https URL"))
+	}
+
+	return errs
+}
+
+func (s *Metering) AddFlags(fs *pflag.FlagSet) {
+	if s == nil {
+		return
+	}
+
+	fs.DurationVar(&s.SampleInterval, "metering-sample-interval", s.SampleInterval,
+		"How often the number of objects of all workspaces is sampled for metering. The highest sample of an hour is recorded.")
+	fs.StringVar(&s.CSVDirectory, "metering-csv-directory", s.CSVDirectory,
+		"Directory hourly workspace usage records are appended to, in a CSV file per day. If relative, it is relative to --root-directory.")
+	fs.StringVar(&s.RemoteURL, "metering-remote-url", s.RemoteURL,
+		"URL hourly workspace usage records are posted to as JSON.")
+	fs.BoolVar(&s.Prometheus, "metering-prometheus", s.Prometheus,
+		"Expose the workspace usage records of the last hour as metrics, labeled by workspace.")
+}
+
+// NewMeter returns a Meter exporting to the configured exporters.
+func (s *Metering) NewMeter() (*metering.Meter, error) {
+	var exporters []metering.Exporter
+	if s.CSVDirectory != "" {
+		if err := os.MkdirAll(s.CSVDirectory, 0700); err != nil {
+			return nil, err
+		}
+		exporters = append(exporters, &metering.CSVExporter{Directory: s.CSVDirectory})
+	}
+	if s.RemoteURL != "" {
+		exporters = append(exporters, &metering.RemoteExporter{URL: s.RemoteURL, Client: &http.Client{Timeout: 30 * time.Second}})
+	}
+	if s.Prometheus {
+		exporters = append(exporters, metering.NewPrometheusExporter())
+	}
+	return metering.NewMeter(exporters...), nil
+}
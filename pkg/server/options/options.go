@@ -28,6 +28,7 @@ import (
 	"github.com/spf13/pflag"
 
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/admission"
 	genericapiserveroptions "k8s.io/apiserver/pkg/server/options"
 	cliflag "k8s.io/component-base/cli/flag"
 	"k8s.io/klog/v2"
@@ -35,8 +36,12 @@ import (
 	kubeoptions "k8s.io/kubernetes/pkg/kubeapiserver/options"
 
 	kcpadmission "github.com/kcp-dev/kcp/pkg/admission"
+	admissionchain "github.com/kcp-dev/kcp/pkg/admission/chain"
+	certsoptions "github.com/kcp-dev/kcp/pkg/certs/options"
+	"github.com/kcp-dev/kcp/pkg/faultinjection"
 	_ "github.com/kcp-dev/kcp/pkg/features"
 	kcpfeatures "github.com/kcp-dev/kcp/pkg/features"
+	"github.com/kcp-dev/kcp/pkg/server/indexes"
 )
 
 type Options struct {
@@ -45,18 +50,37 @@ type Options struct {
 	Controllers         Controllers
 	Authorization       Authorization
 	AdminAuthentication AdminAuthentication
+	GroupResolution     GroupResolution
+	TokenExchange       TokenExchange
+	Metering            Metering
+	DNS                 DNS
+	Placement           Placement
+	WorkloadIdentity    WorkloadIdentity
+	APIExportIdentity   APIExportIdentity
+	EventSinks          EventSinks
+	ACME                ACME
+	Bootstrap           Bootstrap
 	Virtual             Virtual
+	CertificateSecrets  certsoptions.CertificateSecrets
 
 	Extra ExtraOptions
 }
 
 type ExtraOptions struct {
 	RootDirectory            string
+	ShardName                string
 	ProfilerAddress          string
 	ShardKubeconfigFile      string
 	EnableSharding           bool
 	DiscoveryPollInterval    time.Duration
 	ExperimentalBindFreePort bool
+	WatchCacheLabelIndexes   []string
+	AdmissionPluginOrder     []string
+	EnableFaultInjection     bool
+	ProviderIdentityQPS      float32
+	ProviderIdentityBurst    int
+
+	ConversionWebhookServiceNamespaces []string
 }
 
 type completedOptions struct {
@@ -65,7 +89,18 @@ type completedOptions struct {
 	Controllers         Controllers
 	Authorization       Authorization
 	AdminAuthentication AdminAuthentication
+	GroupResolution     GroupResolution
+	TokenExchange       TokenExchange
+	Metering            Metering
+	DNS                 DNS
+	Placement           Placement
+	WorkloadIdentity    WorkloadIdentity
+	APIExportIdentity   APIExportIdentity
+	EventSinks          EventSinks
+	ACME                ACME
+	Bootstrap           Bootstrap
 	Virtual             Virtual
+	CertificateSecrets  certsoptions.CertificateSecrets
 
 	Extra ExtraOptions
 }
@@ -84,14 +119,27 @@ func NewOptions() *Options {
 		Controllers:         *NewControllers(),
 		Authorization:       *NewAuthorization(),
 		AdminAuthentication: *NewAdminAuthentication(),
+		GroupResolution:     *NewGroupResolution(),
+		TokenExchange:       *NewTokenExchange(),
+		Metering:            *NewMetering(),
+		DNS:                 *NewDNS(),
+		Placement:           *NewPlacement(),
+		WorkloadIdentity:    *NewWorkloadIdentity(),
+		APIExportIdentity:   *NewAPIExportIdentity(),
+		EventSinks:          *NewEventSinks(),
+		ACME:                *NewACME(),
+		Bootstrap:           *NewBootstrap(),
 		Virtual:             *NewVirtual(),
+		CertificateSecrets:  *certsoptions.NewCertificateSecrets(),
 
 		Extra: ExtraOptions{
 			RootDirectory:            ".kcp",
+			ShardName:                "root",
 			ProfilerAddress:          "",
 			ShardKubeconfigFile:      "",
 			EnableSharding:           false,
 			DiscoveryPollInterval:    60 * time.Second,
+			ProviderIdentityBurst:    100,
 			ExperimentalBindFreePort: false,
 		},
 	}
@@ -114,6 +162,7 @@ func NewOptions() *Options {
 	kcpadmission.RegisterAllKcpAdmissionPlugins(o.GenericControlPlane.Admission.Plugins)
 	o.GenericControlPlane.Admission.DisablePlugins = kcpadmission.DefaultOffAdmissionPlugins().List()
 	o.GenericControlPlane.Admission.RecommendedPluginOrder = kcpadmission.AllOrderedPlugins
+	o.GenericControlPlane.Admission.Decorators = append(o.GenericControlPlane.Admission.Decorators, admission.DecoratorFunc(admissionchain.DefaultChain.Decorate))
 
 	return o
 }
@@ -142,14 +191,32 @@ func (o *Options) rawFlags() cliflag.NamedFlagSets {
 	o.Controllers.AddFlags(fss.FlagSet("KCP Controllers"))
 	o.Authorization.AddFlags(fss.FlagSet("KCP Authorization"))
 	o.AdminAuthentication.AddFlags(fss.FlagSet("KCP Authentication"))
+	o.GroupResolution.AddFlags(fss.FlagSet("KCP Authentication"))
+	o.TokenExchange.AddFlags(fss.FlagSet("KCP Authentication"))
+	o.Metering.AddFlags(fss.FlagSet("KCP"))
+	o.DNS.AddFlags(fss.FlagSet("KCP"))
+	o.Placement.AddFlags(fss.FlagSet("KCP"))
+	o.WorkloadIdentity.AddFlags(fss.FlagSet("KCP"))
+	o.APIExportIdentity.AddFlags(fss.FlagSet("KCP"))
+	o.EventSinks.AddFlags(fss.FlagSet("KCP"))
+	o.ACME.AddFlags(fss.FlagSet("KCP"))
+	o.Bootstrap.AddFlags(fss.FlagSet("KCP"))
 	o.Virtual.AddFlags(fss.FlagSet("KCP Virtual Workspaces"))
+	o.CertificateSecrets.AddFlags(fss.FlagSet("KCP"))
 
 	fs := fss.FlagSet("KCP")
 	fs.StringVar(&o.Extra.ProfilerAddress, "profiler-address", o.Extra.ProfilerAddress, "[Address]:port to bind the profiler to")
 	fs.StringVar(&o.Extra.ShardKubeconfigFile, "shard-kubeconfig-file", o.Extra.ShardKubeconfigFile, "Kubeconfig holding admin(!) credentials to peer kcp shards.")
 	fs.BoolVar(&o.Extra.EnableSharding, "enable-sharding", o.Extra.EnableSharding, "Enable delegating to peer kcp shards.")
 	fs.StringVar(&o.Extra.RootDirectory, "root-directory", o.Extra.RootDirectory, "Root directory.")
+	fs.StringVar(&o.Extra.ShardName, "shard-name", o.Extra.ShardName, "Name of the ClusterWorkspaceShard of this kcp instance, which its version, features and system resources are published in.")
 	fs.DurationVar(&o.Extra.DiscoveryPollInterval, "discovery-poll-interval", o.Extra.DiscoveryPollInterval, "Polling interval for dynamic discovery informers.")
+	fs.StringSliceVar(&o.Extra.AdmissionPluginOrder, "admission-plugin-order", o.Extra.AdmissionPluginOrder, "Relative order of the given admission plugins, e.g. a,b to run a before b. The given plugins take the positions they have among each other in the default order.")
+	fs.StringSliceVar(&o.Extra.WatchCacheLabelIndexes, "watch-cache-label-indexes", o.Extra.WatchCacheLabelIndexes, "Label keys the watch cache indexes objects of a resource by, in the form <resource>[.<group>]=<label key>, e.g. deployments.apps=example.dev/team. Lists served from the watch cache with a selector requiring a value of an indexed key use the index.")
+	fs.StringSliceVar(&o.Extra.ConversionWebhookServiceNamespaces, "conversion-webhook-service-namespaces", o.Extra.ConversionWebhookServiceNamespaces, "Namespaces of the cluster kcp runs in whose services CRD conversion webhooks of all workspaces can reference. Conversion webhooks referencing a service are rejected if empty, only those with a URL are called.")
+	fs.Float32Var(&o.Extra.ProviderIdentityQPS, "provider-identity-qps", o.Extra.ProviderIdentityQPS, "Rate of the requests of API providers for the resources of their APIExport, by its identity or to a workspace binding them, allowed per APIExport identity. Requests above the rate are rejected with 429 Too Many Requests. 0 disables the limit.")
+	fs.IntVar(&o.Extra.ProviderIdentityBurst, "provider-identity-burst", o.Extra.ProviderIdentityBurst, "Burst of the requests of API providers allowed per APIExport identity on top of --provider-identity-qps.")
+	fs.BoolVar(&o.Extra.EnableFaultInjection, "enable-fault-injection", o.Extra.EnableFaultInjection, "Developer mode: serve "+faultinjection.DebugPath+" to delay or fail storage operations and drop watch events on demand, for resilience testing. Never enable in production.")
 
 	fs.BoolVar(&o.Extra.ExperimentalBindFreePort, "experimental-bind-free-port", o.Extra.ExperimentalBindFreePort, "Bind to a free port. --secure-port must be 0. Use the admin.kubeconfig to extract the chosen port.")
 	fs.MarkHidden("experimental-bind-free-port") // nolint:errcheck
@@ -171,11 +238,34 @@ func (o *CompletedOptions) Validate() []error {
 	errs = append(errs, o.EmbeddedEtcd.Validate()...)
 	errs = append(errs, o.Authorization.Validate()...)
 	errs = append(errs, o.AdminAuthentication.Validate()...)
+	errs = append(errs, o.GroupResolution.Validate()...)
+	errs = append(errs, o.TokenExchange.Validate()...)
+	errs = append(errs, o.Metering.Validate()...)
+	errs = append(errs, o.DNS.Validate()...)
+	errs = append(errs, o.Placement.Validate()...)
+	errs = append(errs, o.WorkloadIdentity.Validate()...)
+	errs = append(errs, o.APIExportIdentity.Validate()...)
+	errs = append(errs, o.EventSinks.Validate()...)
+	errs = append(errs, o.ACME.Validate()...)
+	errs = append(errs, o.Bootstrap.Validate()...)
 	errs = append(errs, o.Virtual.Validate()...)
+	errs = append(errs, o.CertificateSecrets.Validate()...)
 
+	if o.Extra.ShardName == "" {
+		errs = append(errs, fmt.Errorf("--shard-name must not be empty"))
+	}
 	if o.Extra.DiscoveryPollInterval == 0 {
 		errs = append(errs, fmt.Errorf("--discovery-poll-interval not set"))
 	}
+	if o.Extra.ProviderIdentityQPS < 0 {
+		errs = append(errs, fmt.Errorf("--provider-identity-qps must not be negative"))
+	}
+	if o.Extra.ProviderIdentityQPS > 0 && o.Extra.ProviderIdentityBurst < 1 {
+		errs = append(errs, fmt.Errorf("--provider-identity-burst must be positive with --provider-identity-qps"))
+	}
+	if _, err := indexes.ParseLabelIndexes(o.Extra.WatchCacheLabelIndexes); err != nil {
+		errs = append(errs, fmt.Errorf("--watch-cache-label-indexes: %w", err))
+	}
 
 	return errs
 }
@@ -216,6 +306,12 @@ func (o *Options) Complete() (*CompletedOptions, error) {
 	if !filepath.IsAbs(o.AdminAuthentication.KubeConfigPath) {
 		o.AdminAuthentication.KubeConfigPath = filepath.Join(o.Extra.RootDirectory, o.AdminAuthentication.KubeConfigPath)
 	}
+	if o.Metering.CSVDirectory != "" && !filepath.IsAbs(o.Metering.CSVDirectory) {
+		o.Metering.CSVDirectory = filepath.Join(o.Extra.RootDirectory, o.Metering.CSVDirectory)
+	}
+	if o.ACME.AccountKeyFile != "" && !filepath.IsAbs(o.ACME.AccountKeyFile) {
+		o.ACME.AccountKeyFile = filepath.Join(o.Extra.RootDirectory, o.ACME.AccountKeyFile)
+	}
 
 	if o.Extra.ExperimentalBindFreePort {
 		listener, _, err := genericapiserveroptions.CreateListener("tcp", fmt.Sprintf("%s:0", o.GenericControlPlane.SecureServing.BindAddress), net.ListenConfig{})
@@ -225,6 +321,14 @@ func (o *Options) Complete() (*CompletedOptions, error) {
 		o.GenericControlPlane.SecureServing.Listener = listener
 	}
 
+	if len(o.Extra.AdmissionPluginOrder) > 0 {
+		order, err := kcpadmission.Reorder(kcpadmission.AllOrderedPlugins, o.Extra.AdmissionPluginOrder)
+		if err != nil {
+			return nil, fmt.Errorf("--admission-plugin-order: %w", err)
+		}
+		o.GenericControlPlane.Admission.RecommendedPluginOrder = order
+	}
+
 	if err := o.Controllers.Complete(o.Extra.RootDirectory); err != nil {
 		return nil, err
 	}
@@ -258,7 +362,18 @@ func (o *Options) Complete() (*CompletedOptions, error) {
 			Controllers:         o.Controllers,
 			Authorization:       o.Authorization,
 			AdminAuthentication: o.AdminAuthentication,
+			GroupResolution:     o.GroupResolution,
+			TokenExchange:       o.TokenExchange,
+			Metering:            o.Metering,
+			DNS:                 o.DNS,
+			Placement:           o.Placement,
+			WorkloadIdentity:    o.WorkloadIdentity,
+			APIExportIdentity:   o.APIExportIdentity,
+			EventSinks:          o.EventSinks,
+			ACME:                o.ACME,
+			Bootstrap:           o.Bootstrap,
 			Virtual:             o.Virtual,
+			CertificateSecrets:  o.CertificateSecrets,
 			Extra:               o.Extra,
 		},
 	}, nil
@@ -0,0 +1,171 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/spf13/pflag"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kcp-dev/kcp/pkg/reconciler/scheduling/placement"
+)
+
+// Placement configures the placement scheduler.
+type Placement struct {
+	// ExtendersConfig is the path of a file with the extender webhooks of the scheduler.
+	ExtendersConfig string
+}
+
+// PlacementExtendersConfiguration is the content of the --placement-extenders-config file.
+type PlacementExtendersConfiguration struct {
+	Extenders []PlacementExtender `json:"extenders"`
+}
+
+// PlacementExtender is a webhook vetoing or scoring the locations namespaces can be placed on.
+type PlacementExtender struct {
+	// Name identifies the extender in logs and errors.
+	Name string `json:"name"`
+	// URLPrefix is the http or https URL the verbs are appended to.
+	URLPrefix string `json:"urlPrefix"`
+	// FilterVerb is the path segment the locations are posted to for vetoing. The
+	// extender does not veto if empty.
+	FilterVerb string `json:"filterVerb,omitempty"`
+	// PrioritizeVerb is the path segment the locations are posted to for scoring. The
+	// extender does not score if empty.
+	PrioritizeVerb string `json:"prioritizeVerb,omitempty"`
+	// Weight is the factor the scores of the extender are multiplied with. Defaults to 1.
+	Weight int64 `json:"weight,omitempty"`
+	// Timeout of a call. Defaults to 5s.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// Ignorable means that namespaces are placed without the extender if it fails.
+	Ignorable bool `json:"ignorable,omitempty"`
+	// Clusters are the workspaces, including their descendants, whose namespaces the
+	// extender is consulted for, e.g. root:acme for an organization. Empty means all.
+	Clusters []string `json:"clusters,omitempty"`
+	// CAFile is the PEM encoded CA bundle to verify the extender with. The system
+	// roots are used if empty.
+	CAFile string `json:"caFile,omitempty"`
+}
+
+func NewPlacement() *Placement {
+	return &Placement{}
+}
+
+func (s *Placement) AddFlags(fs *pflag.FlagSet) {
+	if s == nil {
+		return
+	}
+
+	fs.StringVar(&s.ExtendersConfig, "placement-extenders-config", s.ExtendersConfig,
+		"Path to a file with extender webhooks that veto or score the locations namespaces are placed on, in the order they are consulted.")
+}
+
+func (s *Placement) Validate() []error {
+	if s == nil || s.ExtendersConfig == "" {
+		return nil
+	}
+
+	if _, err := s.Extenders(); err != nil {
+		return []error{err}
+	}
+	return nil
+}
+
+// Extenders returns the configured extenders, in the order they are consulted.
+func (s *Placement) Extenders() ([]placement.Extender, error) {
+	if s.ExtendersConfig == "" {
+		return nil, nil
+	}
+
+	bs, err := ioutil.ReadFile(s.ExtendersConfig)
+	if err != nil {
+		return nil, err
+	}
+	var cfg PlacementExtendersConfiguration
+	if err := yaml.UnmarshalStrict(bs, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", s.ExtendersConfig, err)
+	}
+
+	var extenders []placement.Extender
+	seen := map[string]bool{}
+	for i, e := range cfg.Extenders {
+		if e.Name == "" {
+			return nil, fmt.Errorf("%s: extenders[%d].name is required", s.ExtendersConfig, i)
+		}
+		if seen[e.Name] {
+			return nil, fmt.Errorf("%s: extenders[%d].name %q is not unique", s.ExtendersConfig, i, e.Name)
+		}
+		seen[e.Name] = true
+		parsed, err := url.Parse(e.URLPrefix)
+		if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+			return nil, fmt.Errorf("%s: extenders[%d].urlPrefix must be a http or https URL", s.ExtendersConfig, i)
+		}
+		if e.FilterVerb == "" && e.PrioritizeVerb == "" {
+			return nil, fmt.Errorf("%s: extenders[%d] needs a filterVerb or a prioritizeVerb", s.ExtendersConfig, i)
+		}
+		if e.Weight < 0 {
+			return nil, fmt.Errorf("%s: extenders[%d].weight must not be negative", s.ExtendersConfig, i)
+		}
+
+		weight := e.Weight
+		if weight == 0 {
+			weight = 1
+		}
+		timeout := 5 * time.Second
+		if e.Timeout != nil {
+			timeout = e.Timeout.Duration
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		if e.CAFile != "" {
+			ca, err := ioutil.ReadFile(e.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("%s: extenders[%d].caFile: %w", s.ExtendersConfig, i, err)
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(ca) {
+				return nil, fmt.Errorf("%s: extenders[%d].caFile has no valid certificates", s.ExtendersConfig, i)
+			}
+			transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+		}
+		var prefixes []logicalcluster.Name
+		for _, c := range e.Clusters {
+			prefixes = append(prefixes, logicalcluster.New(c))
+		}
+
+		extenders = append(extenders, &placement.WebhookExtender{
+			ExtenderName:    e.Name,
+			URLPrefix:       e.URLPrefix,
+			FilterVerb:      e.FilterVerb,
+			PrioritizeVerb:  e.PrioritizeVerb,
+			ExtenderWeight:  weight,
+			Ignorable:       e.Ignorable,
+			ClusterPrefixes: prefixes,
+			Client:          &http.Client{Timeout: timeout, Transport: transport},
+		})
+	}
+	return extenders, nil
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kcp-dev/kcp/pkg/reconciler/scheduling/placement"
+)
+
+func TestPlacementExtenders(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		want    []*placement.WebhookExtender
+		wantErr string
+	}{
+		{
+			name: "defaulted",
+			config: `
+extenders:
+- name: cost
+  urlPrefix: https://cost.example.com/scheduler
+  prioritizeVerb: prioritize
+`,
+			want: []*placement.WebhookExtender{{ExtenderName: "cost", URLPrefix: "https://cost.example.com/scheduler", PrioritizeVerb: "prioritize", ExtenderWeight: 1}},
+		},
+		{
+			name: "complete",
+			config: `
+extenders:
+- name: compliance
+  urlPrefix: http://compliance.example.com
+  filterVerb: filter
+  prioritizeVerb: prioritize
+  weight: 3
+  timeout: 2s
+  ignorable: true
+  clusters: ["root:acme"]
+`,
+			want: []*placement.WebhookExtender{{ExtenderName: "compliance", URLPrefix: "http://compliance.example.com", FilterVerb: "filter", PrioritizeVerb: "prioritize",
+				ExtenderWeight: 3, Ignorable: true, ClusterPrefixes: []logicalcluster.Name{logicalcluster.New("root:acme")}}},
+		},
+		{
+			name:    "unknown field",
+			config:  "extenders:\n- name: a\n  url: https://a.example.com\n",
+			wantErr: "unknown field",
+		},
+		{
+			name:    "no verb",
+			config:  "extenders:\n- name: a\n  urlPrefix: https://a.example.com\n",
+			wantErr: "needs a filterVerb or a prioritizeVerb",
+		},
+		{
+			name:    "invalid url",
+			config:  "extenders:\n- name: a\n  urlPrefix: a.example.com\n  filterVerb: filter\n",
+			wantErr: "must be a http or https URL",
+		},
+		{
+			name:    "duplicate name",
+			config:  "extenders:\n- name: a\n  urlPrefix: https://a.example.com\n  filterVerb: filter\n- name: a\n  urlPrefix: https://b.example.com\n  filterVerb: filter\n",
+			wantErr: "not unique",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "extenders.yaml")
+			require.NoError(t, ioutil.WriteFile(path, []byte(tt.config), 0600))
+
+			got, err := (&Placement{ExtendersConfig: path}).Extenders()
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, got, len(tt.want))
+			for i := range got {
+				w := got[i].(*placement.WebhookExtender)
+				require.NotNil(t, w.Client)
+				if tt.name == "complete" {
+					require.Equal(t, 2*time.Second, w.Client.Timeout)
+				} else {
+					require.Equal(t, 5*time.Second, w.Client.Timeout)
+				}
+				w.Client = nil
+				require.Equal(t, tt.want[i], w)
+			}
+		})
+	}
+}
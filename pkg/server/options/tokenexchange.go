@@ -0,0 +1,104 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	"k8s.io/apiserver/pkg/authentication/request/bearertoken"
+	unionauth "k8s.io/apiserver/pkg/authentication/request/union"
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/client-go/util/keyutil"
+
+	"github.com/kcp-dev/kcp/pkg/authentication/exchange"
+)
+
+// TokenExchange configures the authentication of the internal tokens the front-proxy
+// exchanges external bearer tokens for.
+type TokenExchange struct {
+	// Issuer of the internal tokens. Internal tokens are not accepted if empty.
+	Issuer string
+	// KeyFiles hold the public keys the internal tokens are verified with.
+	KeyFiles []string
+	// Audiences of which the internal tokens must carry one.
+	Audiences []string
+}
+
+func NewTokenExchange() *TokenExchange {
+	return &TokenExchange{
+		Audiences: []string{"kcp"},
+	}
+}
+
+func (s *TokenExchange) Validate() []error {
+	if s == nil {
+		return nil
+	}
+
+	var errs []error
+	if s.Issuer != "" && len(s.KeyFiles) == 0 {
+		errs = append(errs, fmt.Errorf("--token-exchange-key-files is required with --token-exchange-issuer"))
+	}
+	if s.Issuer == "" && len(s.KeyFiles) > 0 {
+		errs = append(errs, fmt.Errorf("--token-exchange-key-files requires --token-exchange-issuer"))
+	}
+	if s.Issuer != "" && len(s.Audiences) == 0 {
+		errs = append(errs, fmt.Errorf("--token-exchange-audiences must not be empty"))
+	}
+	return errs
+}
+
+func (s *TokenExchange) AddFlags(fs *pflag.FlagSet) {
+	if s == nil {
+		return
+	}
+
+	fs.StringVar(&s.Issuer, "token-exchange-issuer", s.Issuer,
+		"Issuer of the internal tokens the front-proxy exchanges external bearer tokens for. Internal tokens are not accepted if empty.")
+	fs.StringSliceVar(&s.KeyFiles, "token-exchange-key-files", s.KeyFiles,
+		"Files holding the PEM encoded public keys the internal tokens of the front-proxy are verified with.")
+	fs.StringSliceVar(&s.Audiences, "token-exchange-audiences", s.Audiences,
+		"Audiences of which the internal tokens of the front-proxy must carry one.")
+}
+
+func (s *TokenExchange) ApplyTo(config *genericapiserver.Config) error {
+	if s.Issuer == "" {
+		return nil
+	}
+
+	var publicKeys []interface{}
+	for _, keyFile := range s.KeyFiles {
+		keys, err := keyutil.PublicKeysFromFile(keyFile)
+		if err != nil {
+			return fmt.Errorf("failed to read --token-exchange-key-files: %w", err)
+		}
+		publicKeys = append(publicKeys, keys...)
+	}
+
+	// internal tokens take precedence, as requests carrying them are also authenticated
+	// by the client certificate of the front-proxy.
+	tokenAuth := bearertoken.New(exchange.NewAuthenticator(s.Issuer, s.Audiences, publicKeys))
+	if config.Authentication.Authenticator == nil {
+		config.Authentication.Authenticator = tokenAuth
+	} else {
+		config.Authentication.Authenticator = unionauth.New(tokenAuth, config.Authentication.Authenticator)
+	}
+
+	return nil
+}
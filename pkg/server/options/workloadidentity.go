@@ -0,0 +1,68 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// WorkloadIdentity configures the tokens of ServiceAccounts synced to workload clusters.
+type WorkloadIdentity struct {
+	// Audiences of the tokens. The audiences of kcp are used if empty.
+	Audiences []string
+	// TokenExpiration is the lifetime of the tokens. They are replaced after 80% of it.
+	TokenExpiration time.Duration
+}
+
+func NewWorkloadIdentity() *WorkloadIdentity {
+	return &WorkloadIdentity{
+		TokenExpiration: time.Hour,
+	}
+}
+
+func (s *WorkloadIdentity) AddFlags(fs *pflag.FlagSet) {
+	if s == nil {
+		return
+	}
+
+	fs.StringSliceVar(&s.Audiences, "workload-identity-audiences", s.Audiences,
+		"Audiences of the tokens of ServiceAccounts synced to workload clusters. The API audiences of kcp are used if empty.")
+	fs.DurationVar(&s.TokenExpiration, "workload-identity-token-expiration", s.TokenExpiration,
+		"Lifetime of the tokens of ServiceAccounts synced to workload clusters. They are replaced after 80% of it.")
+}
+
+func (s *WorkloadIdentity) Validate() []error {
+	if s == nil {
+		return nil
+	}
+
+	var errs []error
+	// TokenRequests must not be shorter than 10 minutes.
+	if s.TokenExpiration < 10*time.Minute {
+		errs = append(errs, fmt.Errorf("--workload-identity-token-expiration must be at least 10m"))
+	}
+	for _, audience := range s.Audiences {
+		if audience == "" {
+			errs = append(errs, fmt.Errorf("--workload-identity-audiences must not contain empty audiences"))
+			break
+		}
+	}
+	return errs
+}
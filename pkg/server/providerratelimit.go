@@ -0,0 +1,223 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/klog/v2"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	apisinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/informer"
+)
+
+const (
+	// providerRateLimitRetryAfterSeconds is how long providers are asked to wait before retrying
+	// rate limited requests.
+	providerRateLimitRetryAfterSeconds = 1
+
+	// providerRateLimitSweepInterval is how often token buckets which are not used anymore are
+	// removed.
+	providerRateLimitSweepInterval = time.Minute
+)
+
+// providerRateLimiter limits the rate of the requests of API providers for the resources of their
+// APIExport in the workspaces binding it. These are identity requests, and requests to a single
+// workspace for a resource it binds, which count for the identity of the binding no matter whether
+// the path names it: the requests of the provider cannot be told apart from those of other clients
+// with access to the workspace. There are two kinds of limits, each with its own token bucket:
+//
+//   - the limit kcp is started with applies per identity to all these requests, such that a
+//     misbehaving provider does not affect the others.
+//   - the limit set in the APIBinding of a consumer workspace applies per identity and workspace
+//     to the requests to that workspace, such that consumers protect themselves.
+//
+// Token buckets which have not been used for longer than it takes to refill them are removed,
+// as they would not limit the next request anyway.
+type providerRateLimiter struct {
+	qps   float32
+	burst int
+
+	// getBoundResource returns the identity with which the given workspace binds the resource,
+	// and the rate limit of the binding. The identity is empty if the resource is not bound.
+	getBoundResource func(clusterName logicalcluster.Name, gr schema.GroupResource) (string, *apisv1alpha1.ProviderRateLimit, error)
+	now              func() time.Time
+
+	lock      sync.Mutex
+	limiters  map[string]*providerLimiter
+	lastSweep time.Time
+}
+
+type providerLimiter struct {
+	flowcontrol.RateLimiter
+	qps      float32
+	burst    int
+	lastUsed time.Time
+}
+
+// newProviderRateLimiter returns a rate limiter with the given limit per identity, none if qps is
+// zero, and the limits of the APIBindings of consumer workspaces.
+func newProviderRateLimiter(qps float32, burst int, apiBindingInformer apisinformers.APIBindingInformer) *providerRateLimiter {
+	l := &providerRateLimiter{
+		qps:      qps,
+		burst:    burst,
+		now:      time.Now,
+		limiters: map[string]*providerLimiter{},
+		getBoundResource: func(clusterName logicalcluster.Name, gr schema.GroupResource) (string, *apisv1alpha1.ProviderRateLimit, error) {
+			return "", nil, nil
+		},
+	}
+	if apiBindingInformer == nil {
+		return l
+	}
+
+	if _, found := apiBindingInformer.Informer().GetIndexer().GetIndexers()[informer.ByLogicalClusterIndexName]; !found {
+		if err := apiBindingInformer.Informer().AddIndexers(cache.Indexers{
+			informer.ByLogicalClusterIndexName: informer.IndexByLogicalCluster,
+		}); err != nil {
+			// nothing we can do here. But this should also never happen. We check for existence before.
+			klog.Errorf("failed to add indexer for APIBindings: %v", err)
+		}
+	}
+	apiBindingIndexer := apiBindingInformer.Informer().GetIndexer()
+	l.getBoundResource = func(clusterName logicalcluster.Name, gr schema.GroupResource) (string, *apisv1alpha1.ProviderRateLimit, error) {
+		objs, err := apiBindingIndexer.ByIndex(informer.ByLogicalClusterIndexName, clusterName.String())
+		if err != nil {
+			return "", nil, err
+		}
+		for _, obj := range objs {
+			binding := obj.(*apisv1alpha1.APIBinding)
+			for _, r := range binding.Status.BoundResources {
+				if r.Group == gr.Group && r.Resource == gr.Resource {
+					return r.Schema.IdentityHash, binding.Spec.ProviderRateLimit, nil
+				}
+			}
+		}
+		return "", nil, nil
+	}
+	return l
+}
+
+// tryAccept takes a token from the bucket of the key with the given limit, creating or
+// replacing the bucket if the limit changed.
+func (l *providerRateLimiter) tryAccept(key string, qps float32, burst int) bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := l.now()
+	if now.Sub(l.lastSweep) > providerRateLimitSweepInterval {
+		l.sweep(now)
+	}
+
+	limiter, ok := l.limiters[key]
+	if !ok || limiter.qps != qps || limiter.burst != burst {
+		limiter = &providerLimiter{
+			RateLimiter: flowcontrol.NewTokenBucketRateLimiter(qps, burst),
+			qps:         qps,
+			burst:       burst,
+		}
+		l.limiters[key] = limiter
+	}
+	limiter.lastUsed = now
+	return limiter.TryAccept()
+}
+
+// sweep removes the buckets which are full again, i.e. which have not been used for longer than
+// it takes to refill them. It must be called with the lock held.
+func (l *providerRateLimiter) sweep(now time.Time) {
+	for key, limiter := range l.limiters {
+		refill := time.Duration(float64(limiter.burst) / float64(limiter.qps) * float64(time.Second))
+		if now.Sub(limiter.lastUsed) > refill {
+			delete(l.limiters, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+// WithRateLimiting rejects the requests of API providers above the rate limit of their identity, or
+// of their identity in the requested workspace, with 429 Too Many Requests. Requests to a single
+// workspace for a resource it binds count for the identity of the binding also when the path does
+// not name it. Watches count once when they are established. The limits apply to all users,
+// including privileged ones, as providers usually reach the workspaces of their consumers with
+// privileged credentials. It must run after WithResourceIdentity, which puts the identity into the
+// context.
+func (l *providerRateLimiter) WithRateLimiting(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		identity := IdentityFromContext(req.Context())
+
+		var limit *apisv1alpha1.ProviderRateLimit
+		cluster := request.ClusterFrom(req.Context())
+		requestInfo, ok := request.RequestInfoFrom(req.Context())
+		if cluster != nil && !cluster.Wildcard && !cluster.Name.Empty() && ok && requestInfo.IsResourceRequest {
+			boundIdentity, bindingLimit, err := l.getBoundResource(cluster.Name, schema.GroupResource{Group: requestInfo.APIGroup, Resource: requestInfo.Resource})
+			if err != nil {
+				responsewriters.ErrorNegotiated(
+					apierrors.NewInternalError(fmt.Errorf("unable to determine the provider rate limit: %w", err)),
+					errorCodecs, schema.GroupVersion{}, w, req,
+				)
+				return
+			}
+			if identity == "" {
+				identity = boundIdentity
+			}
+			if identity == boundIdentity {
+				limit = bindingLimit
+			}
+		}
+		if identity == "" {
+			handler.ServeHTTP(w, req)
+			return
+		}
+
+		if l.qps > 0 && !l.tryAccept(identity, l.qps, l.burst) {
+			l.reject(w, req, fmt.Sprintf("rate limit of %v requests per second for APIExport identity %s exceeded", l.qps, identity))
+			return
+		}
+
+		if limit != nil && limit.QPS > 0 {
+			burst := int(limit.Burst)
+			if burst == 0 {
+				burst = int(limit.QPS)
+			}
+			if !l.tryAccept(identity+"|"+cluster.Name.String(), float32(limit.QPS), burst) {
+				l.reject(w, req, fmt.Sprintf("rate limit of %v requests per second for APIExport identity %s in workspace %s exceeded", limit.QPS, identity, cluster.Name))
+				return
+			}
+		}
+
+		handler.ServeHTTP(w, req)
+	})
+}
+
+func (l *providerRateLimiter) reject(w http.ResponseWriter, req *http.Request, message string) {
+	responsewriters.ErrorNegotiated(
+		apierrors.NewTooManyRequests(message, providerRateLimitRetryAfterSeconds),
+		errorCodecs, schema.GroupVersion{}, w, req,
+	)
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+)
+
+func TestProviderRateLimiter(t *testing.T) {
+	// no refill during the test, only the burst is available.
+	l := newProviderRateLimiter(0.001, 2, nil)
+	handler := l.WithRateLimiting(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}))
+
+	serve := func(identity string, u user.Info) int {
+		req := httptest.NewRequest(http.MethodGet, "/clusters/*/apis/example.dev/v1/widgets", nil)
+		ctx := req.Context()
+		if identity != "" {
+			ctx = WithIdentity(ctx, identity)
+		}
+		if u != nil {
+			ctx = request.WithUser(ctx, u)
+		}
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, req.WithContext(ctx))
+		return rw.Code
+	}
+
+	provider := &user.DefaultInfo{Name: "provider"}
+	require.Equal(t, http.StatusOK, serve("abcd", provider))
+	require.Equal(t, http.StatusOK, serve("abcd", provider))
+	require.Equal(t, http.StatusTooManyRequests, serve("abcd", provider), "burst of the identity is used up")
+	require.Equal(t, http.StatusTooManyRequests, serve("abcd", &user.DefaultInfo{Name: "other"}), "limits are per identity, not per user")
+
+	require.Equal(t, http.StatusOK, serve("ef01", provider), "other identities have their own limit")
+	require.Equal(t, http.StatusOK, serve("", provider), "requests without identity are not limited")
+	require.Equal(t, http.StatusTooManyRequests, serve("abcd", &user.DefaultInfo{Name: "admin", Groups: []string{user.SystemPrivilegedGroup}}), "system:masters is limited too")
+	require.Equal(t, http.StatusOK, serve("ef01", &user.DefaultInfo{Name: "admin", Groups: []string{user.SystemPrivilegedGroup}}))
+	require.Equal(t, http.StatusTooManyRequests, serve("ef01", &user.DefaultInfo{Name: "admin", Groups: []string{user.SystemPrivilegedGroup}}), "system:masters uses up the burst of the identity like everybody")
+}
+
+func TestProviderRateLimiterRetryAfter(t *testing.T) {
+	l := newProviderRateLimiter(0.001, 1, nil)
+	handler := l.WithRateLimiting(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}))
+
+	for i, want := range []int{http.StatusOK, http.StatusTooManyRequests} {
+		req := httptest.NewRequest(http.MethodGet, "/clusters/*/apis/example.dev/v1/widgets", nil)
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, req.WithContext(WithIdentity(req.Context(), "abcd")))
+		require.Equal(t, want, rw.Code, "request %d", i)
+		if want == http.StatusTooManyRequests {
+			require.Equal(t, "1", rw.Header().Get("Retry-After"))
+		}
+	}
+}
+
+// widgetsBoundWith returns a lookup of bound resources for which the given workspaces bind
+// widgets.example.dev with identity abcd and the given limit.
+func widgetsBoundWith(limit *apisv1alpha1.ProviderRateLimit, clusterNames ...string) func(logicalcluster.Name, schema.GroupResource) (string, *apisv1alpha1.ProviderRateLimit, error) {
+	return func(clusterName logicalcluster.Name, gr schema.GroupResource) (string, *apisv1alpha1.ProviderRateLimit, error) {
+		if gr != (schema.GroupResource{Group: "example.dev", Resource: "widgets"}) {
+			return "", nil, nil
+		}
+		for _, name := range clusterNames {
+			if clusterName.String() == name {
+				return "abcd", limit, nil
+			}
+		}
+		return "", nil, nil
+	}
+}
+
+// serveResource serves a request for the resource in the cluster, with the identity in the
+// context as WithResourceIdentity puts it there.
+func serveResource(handler http.Handler, cluster, resource, identity string) int {
+	path := "/clusters/" + cluster + "/apis/example.dev/v1/" + resource
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	ctx := request.WithCluster(req.Context(), request.Cluster{Name: logicalcluster.New(cluster), Wildcard: cluster == "*"})
+	ctx = request.WithRequestInfo(ctx, &request.RequestInfo{
+		IsResourceRequest: true,
+		Path:              path,
+		Verb:              "list",
+		APIPrefix:         "apis",
+		APIGroup:          "example.dev",
+		APIVersion:        "v1",
+		Resource:          resource,
+	})
+	if identity != "" {
+		ctx = WithIdentity(ctx, identity)
+	}
+	ctx = request.WithUser(ctx, &user.DefaultInfo{Name: "provider"})
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, req.WithContext(ctx))
+	return rw.Code
+}
+
+func TestProviderRateLimiterOfConsumers(t *testing.T) {
+	// no limit per identity, only those of the consumers.
+	l := newProviderRateLimiter(0, 0, nil)
+	l.getBoundResource = widgetsBoundWith(&apisv1alpha1.ProviderRateLimit{QPS: 1, Burst: 2}, "root:org:consumer", "root:org:second")
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	l.now = func() time.Time { return now }
+	handler := l.WithRateLimiting(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}))
+	serve := func(cluster, identity string) int {
+		return serveResource(handler, cluster, "widgets", identity)
+	}
+
+	require.Equal(t, http.StatusOK, serve("root:org:consumer", "abcd"))
+	require.Equal(t, http.StatusOK, serve("root:org:consumer", "abcd"))
+	require.Equal(t, http.StatusTooManyRequests, serve("root:org:consumer", "abcd"), "burst of the consumer is used up")
+	require.Equal(t, http.StatusOK, serve("root:org:other", "abcd"), "other workspaces have their own limit")
+	require.Equal(t, http.StatusOK, serve("root:org:consumer", "ef01"), "other identities are not limited by the consumer")
+	require.Equal(t, http.StatusOK, serve("*", "abcd"), "wildcard requests are not limited by consumers")
+
+	require.Len(t, l.limiters, 1)
+	now = now.Add(2 * providerRateLimitSweepInterval)
+	require.Equal(t, http.StatusOK, serve("root:org:second", "abcd"))
+	require.Len(t, l.limiters, 1, "refilled buckets are removed")
+	require.Contains(t, l.limiters, "abcd|root:org:second")
+}
+
+func TestProviderRateLimiterWithoutIdentity(t *testing.T) {
+	// no refill during the test, only the burst is available.
+	l := newProviderRateLimiter(0.001, 3, nil)
+	l.getBoundResource = widgetsBoundWith(&apisv1alpha1.ProviderRateLimit{QPS: 1, Burst: 1}, "root:org:consumer", "root:org:second")
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	l.now = func() time.Time { return now }
+	handler := l.WithRateLimiting(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}))
+
+	require.Equal(t, http.StatusOK, serveResource(handler, "root:org:consumer", "widgets", ""))
+	require.Equal(t, http.StatusTooManyRequests, serveResource(handler, "root:org:consumer", "widgets", "abcd"), "requests without identity use up the burst of the consumer")
+	require.Equal(t, http.StatusOK, serveResource(handler, "root:org:second", "widgets", ""))
+	require.Equal(t, http.StatusTooManyRequests, serveResource(handler, "root:org:other", "widgets", "abcd"), "requests without identity use up the burst of the identity")
+
+	require.Equal(t, http.StatusOK, serveResource(handler, "root:org:consumer", "gadgets", ""), "resources which are not bound are not limited")
+	require.Equal(t, http.StatusOK, serveResource(handler, "root:org:other", "widgets", ""), "resources of workspaces not binding them are not limited")
+	require.Equal(t, http.StatusOK, serveResource(handler, "*", "widgets", ""), "wildcard requests without identity are not limited")
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+
+	"github.com/kcp-dev/kcp/pkg/informer"
+	metadataclient "github.com/kcp-dev/kcp/pkg/metadata"
+	"github.com/kcp-dev/kcp/pkg/referencedby"
+)
+
+func (s *Server) installReferencedByIndex(ctx context.Context, config *rest.Config, index *referencedby.Index) error {
+	config = rest.AddUserAgent(rest.CopyConfig(config), "kcp-referenced-by")
+	kubeClient, err := kubernetes.NewClusterForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	// owner references are part of the metadata, which is all the wildcard informers see.
+	metadataClusterClient, err := metadataclient.NewDynamicMetadataClusterClientForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	ddsif := informer.NewDynamicDiscoverySharedInformerFactory(
+		s.kcpSharedInformerFactory.Tenancy().V1alpha1().ClusterWorkspaces().Lister(),
+		kubeClient.DiscoveryClient,
+		metadataClusterClient.Cluster(logicalcluster.Wildcard),
+		func(obj interface{}) bool { return true },
+		index.OwnerReferencesHandler(),
+		s.options.Extra.DiscoveryPollInterval,
+	)
+
+	s.kcpSharedInformerFactory.Apis().V1alpha1().APIBindings().Informer().AddEventHandler(index.APIBindingHandler())
+	s.kcpSharedInformerFactory.Apis().V1alpha1().APIExports().Informer().AddEventHandler(index.APIExportHandler())
+
+	s.AddPostStartHook("kcp-start-referenced-by-index", func(hookContext genericapiserver.PostStartHookContext) error {
+		if err := s.waitForSync(hookContext.StopCh); err != nil {
+			klog.Errorf("failed to finish post-start-hook kcp-start-referenced-by-index: %v", err)
+			// nolint:nilerr
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		ddsif.Start(ctx)
+		return nil
+	})
+	return nil
+}
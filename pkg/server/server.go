@@ -25,12 +25,15 @@ import (
 
 	"github.com/kcp-dev/logicalcluster"
 
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	apiextensionsexternalversions "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/audit"
 	"k8s.io/apiserver/pkg/endpoints/filters"
 	genericapiserver "k8s.io/apiserver/pkg/server"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
@@ -41,21 +44,43 @@ import (
 	"k8s.io/client-go/rest"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 	"k8s.io/client-go/tools/clusters"
+	_ "k8s.io/component-base/metrics/prometheus/workqueue" // for workqueue metric registration
 	"k8s.io/klog/v2"
+	"k8s.io/kube-openapi/pkg/validation/spec"
 	"k8s.io/kubernetes/pkg/genericcontrolplane"
 
 	configroot "github.com/kcp-dev/kcp/config/root"
 	systemcrds "github.com/kcp-dev/kcp/config/system-crds"
+	admissionchain "github.com/kcp-dev/kcp/pkg/admission/chain"
 	kcpadmissioninitializers "github.com/kcp-dev/kcp/pkg/admission/initializers"
+	"github.com/kcp-dev/kcp/pkg/apidocs"
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
 	"github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
 	"github.com/kcp-dev/kcp/pkg/authentication"
+	"github.com/kcp-dev/kcp/pkg/authorization"
 	bootstrappolicy "github.com/kcp-dev/kcp/pkg/authorization/bootstrap"
+	"github.com/kcp-dev/kcp/pkg/catalog"
 	kcpclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
 	kcpexternalversions "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
 	"github.com/kcp-dev/kcp/pkg/etcd"
+	"github.com/kcp-dev/kcp/pkg/eventbus"
+	"github.com/kcp-dev/kcp/pkg/faultinjection"
 	kcpfeatures "github.com/kcp-dev/kcp/pkg/features"
+	"github.com/kcp-dev/kcp/pkg/metering"
+	"github.com/kcp-dev/kcp/pkg/preflight"
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/deprecatedusage"
+	"github.com/kcp-dev/kcp/pkg/reconciler/latency"
+	"github.com/kcp-dev/kcp/pkg/reconciler/priority"
+	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/hibernation"
+	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/homeworkspace"
+	"github.com/kcp-dev/kcp/pkg/referencedby"
+	"github.com/kcp-dev/kcp/pkg/resolution"
+	"github.com/kcp-dev/kcp/pkg/server/compression"
+	"github.com/kcp-dev/kcp/pkg/server/indexes"
+	"github.com/kcp-dev/kcp/pkg/server/longrunning"
+	"github.com/kcp-dev/kcp/pkg/server/openapicache"
 	kcpserveroptions "github.com/kcp-dev/kcp/pkg/server/options"
+	"github.com/kcp-dev/kcp/pkg/server/shutdown"
 	"github.com/kcp-dev/kcp/pkg/sharding"
 )
 
@@ -83,6 +108,38 @@ type Server struct {
 
 	syncedCh chan struct{}
 
+	// watchTerminator terminates the watches of the shard and of the in-process
+	// virtual workspaces on shutdown.
+	watchTerminator *shutdown.WatchTerminator
+
+	// longRunningRequests tracks the watches and other long-running requests
+	// by logical cluster and user, served on longrunning.DebugPath.
+	longRunningRequests *longrunning.Tracker
+
+	// meter aggregates the usage of workspaces for chargeback. It is nil
+	// if metering is disabled.
+	meter *metering.Meter
+
+	// eventBus streams audit and workspace lifecycle events to event sinks.
+	// It is nil if no sinks are configured.
+	eventBus *eventbus.Bus
+
+	// workspaceActivity records the API activity of workspaces for hibernation.
+	// It is nil if hibernation is disabled.
+	workspaceActivity *hibernation.ActivityTracker
+
+	// homeWorkspaceLogins records the users sending requests for the provisioning
+	// of home workspaces. It is nil if the home workspace controller is disabled.
+	homeWorkspaceLogins *homeworkspace.LoginTracker
+
+	// deprecatedAPIUsage records the requests to deprecated versions of bound resources.
+	// It is nil if the deprecated API usage controller is disabled.
+	deprecatedAPIUsage *deprecatedusage.UsageTracker
+
+	// openAPICache serves the OpenAPI documents of workspaces. It is nil if the
+	// KCPOpenAPICache feature is disabled.
+	openAPICache *openapicache.Cache
+
 	kcpSharedInformerFactory           kcpexternalversions.SharedInformerFactory
 	kubeSharedInformerFactory          coreexternalversions.SharedInformerFactory
 	apiextensionsSharedInformerFactory apiextensionsexternalversions.SharedInformerFactory
@@ -94,10 +151,25 @@ type Server struct {
 
 // NewServer creates a new instance of Server which manages the KCP api-server.
 func NewServer(o *kcpserveroptions.CompletedOptions) (*Server, error) {
-	return &Server{
-		options:  o,
-		syncedCh: make(chan struct{}),
-	}, nil
+	s := &Server{
+		options:             o,
+		syncedCh:            make(chan struct{}),
+		watchTerminator:     shutdown.NewWatchTerminator(),
+		longRunningRequests: longrunning.NewTracker(),
+	}
+	if o.Controllers.WorkspaceHibernation.IdleTimeout > 0 {
+		s.workspaceActivity = hibernation.NewActivityTracker()
+	}
+	if o.Controllers.EnableAll || sets.NewString(o.Controllers.IndividuallyEnabled...).Has("home-workspace") {
+		s.homeWorkspaceLogins = homeworkspace.NewLoginTracker()
+	}
+	if o.Controllers.EnableAll || sets.NewString(o.Controllers.IndividuallyEnabled...).Has("deprecated-api-usage") {
+		s.deprecatedAPIUsage = deprecatedusage.NewUsageTracker()
+	}
+	if utilfeature.DefaultFeatureGate.Enabled(kcpfeatures.OpenAPICache) {
+		s.openAPICache = openapicache.NewCache()
+	}
+	return s, nil
 }
 
 // postStartHookEntry groups a PostStartHookFunc with a name. We're not storing these hooks
@@ -123,7 +195,13 @@ func (s *Server) Run(ctx context.Context) error {
 	}
 	if s.options.EmbeddedEtcd.Enabled {
 		es := &etcd.Server{
-			Dir: s.options.EmbeddedEtcd.Directory,
+			Dir:            s.options.EmbeddedEtcd.Directory,
+			Name:           s.options.EmbeddedEtcd.Name,
+			PeerHost:       s.options.EmbeddedEtcd.PeerHost,
+			ClientHost:     s.options.EmbeddedEtcd.ClientHost,
+			InitialCluster: s.options.EmbeddedEtcd.InitialCluster,
+			ClusterState:   s.options.EmbeddedEtcd.ClusterState,
+			JoinEndpoints:  s.options.EmbeddedEtcd.JoinEndpoints,
 		}
 		embeddedClientInfo, err := es.Run(ctx, s.options.EmbeddedEtcd.PeerPort, s.options.EmbeddedEtcd.ClientPort, s.options.EmbeddedEtcd.WalSizeBytes)
 		if err != nil {
@@ -175,7 +253,13 @@ func (s *Server) Run(ctx context.Context) error {
 		return err
 	}
 
-	if err := s.options.Authorization.ApplyTo(genericConfig, s.kubeSharedInformerFactory, s.kcpSharedInformerFactory.Tenancy().V1alpha1().ClusterWorkspaces().Lister()); err != nil {
+	if err := s.options.Authorization.ApplyTo(genericConfig, s.kubeSharedInformerFactory, s.kcpSharedInformerFactory.Tenancy().V1alpha1().ClusterWorkspaces().Lister(), s.kcpSharedInformerFactory.Tenancy().V1alpha1().DenyPolicies(), s.kcpSharedInformerFactory.Apis().V1alpha1().APIBindings(), s.kcpSharedInformerFactory.Apis().V1alpha1().APIExports()); err != nil {
+		return err
+	}
+	if err := s.options.TokenExchange.ApplyTo(genericConfig); err != nil {
+		return err
+	}
+	if err := s.options.GroupResolution.ApplyTo(genericConfig); err != nil {
 		return err
 	}
 	newTokenOrEmpty, tokenHash, err := s.options.AdminAuthentication.ApplyTo(genericConfig)
@@ -187,6 +271,18 @@ func (s *Server) Run(ctx context.Context) error {
 		return err
 	}
 
+	if s.options.EventSinks.Enabled() {
+		if s.eventBus, err = s.options.EventSinks.NewBus(genericConfig.ExternalAddress); err != nil {
+			return err
+		}
+		// audit events are streamed in addition to the configured audit backends.
+		if genericConfig.AuditBackend != nil {
+			genericConfig.AuditBackend = audit.Union(genericConfig.AuditBackend, eventbus.NewAuditBackend(s.eventBus))
+		} else {
+			genericConfig.AuditBackend = eventbus.NewAuditBackend(s.eventBus)
+		}
+	}
+
 	// create service-account-only authenticator without any lookup for objects, just to extract the logical cluster name from the JWT.
 	// If the request hits us at a non-/clusters URL, we will re-add the /clusters/<cluster-name> prefix to the request. This is necessary
 	// because a service account used by a InCluster client does not support the /clusters/<cluster-name> prefix.
@@ -195,23 +291,52 @@ func (s *Server) Run(ctx context.Context) error {
 		return err
 	}
 
+	if s.options.Metering.Enabled() {
+		if s.meter, err = s.options.Metering.NewMeter(); err != nil {
+			return err
+		}
+	}
+
 	// preHandlerChainMux is called before the actual handler chain. Note that BuildHandlerChainFunc below
 	// is called multiple times, but only one of the handler chain will actually be used. Hence, we wrap it
 	// to give handlers below one mux.Handle func to call.
 	var preHandlerChainMux handlerChainMuxes
+	providerRateLimits := newProviderRateLimiter(s.options.Extra.ProviderIdentityQPS, s.options.Extra.ProviderIdentityBurst, s.kcpSharedInformerFactory.Apis().V1alpha1().APIBindings())
 	genericConfig.BuildHandlerChainFunc = func(apiHandler http.Handler, c *genericapiserver.Config) (secure http.Handler) {
 		// we want a request to hit the chain like:
 		// - lcluster handler (this package's ServeHTTP)
 		// - shard proxy (sharding.ServeHTTP)
 		// - original handler chain
 		// the lcluster handler is a pass-through, not a delegate, so the wrapping looks weird
+		if s.openAPICache != nil {
+			apiHandler = s.openAPICache.WithOpenAPI(apiHandler)
+		}
 		if s.options.Extra.EnableSharding {
 			clientLoader := sharding.NewClientLoader()
 			clientLoader.Add(genericConfig.ExternalAddress, genericConfig.LoopbackClientConfig)
 			apiHandler = sharding.WithSharding(apiHandler, clientLoader)
 		}
 		apiHandler = WithWildcardListWatchGuard(apiHandler)
-		apiHandler = WithWildcardIdentity(apiHandler)
+		apiHandler = providerRateLimits.WithRateLimiting(apiHandler)
+		apiHandler = WithResourceIdentity(apiHandler)
+		apiHandler = s.watchTerminator.WithWatchTermination(apiHandler)
+		apiHandler = s.longRunningRequests.WithTracking(apiHandler, c.LongRunningFunc)
+		if s.meter != nil {
+			apiHandler = s.meter.WithRequestCounting(apiHandler)
+		}
+		if s.workspaceActivity != nil {
+			apiHandler = s.workspaceActivity.WithActivityTracking(apiHandler)
+		}
+		if s.homeWorkspaceLogins != nil {
+			apiHandler = s.homeWorkspaceLogins.WithLoginTracking(apiHandler)
+		}
+		if s.deprecatedAPIUsage != nil {
+			apiHandler = s.deprecatedAPIUsage.WithUsageTracking(apiHandler)
+		}
+		if utilfeature.DefaultFeatureGate.Enabled(kcpfeatures.ResponseCompression) {
+			// outside of the watch termination, which appends to the stream
+			apiHandler = compression.WithCompression(apiHandler, "shard")
+		}
 		apiHandler = genericapiserver.DefaultBuildHandlerChain(apiHandler, c)
 
 		// this will be replaced in DefaultBuildHandlerChain. So at worst we get twice as many warning.
@@ -235,8 +360,10 @@ func (s *Server) Run(ctx context.Context) error {
 
 	admissionPluginInitializers := []admission.PluginInitializer{
 		kcpadmissioninitializers.NewKcpInformersInitializer(s.kcpSharedInformerFactory),
+		kcpadmissioninitializers.NewApiExtensionsInformersInitializer(s.apiextensionsSharedInformerFactory),
 		kcpadmissioninitializers.NewKubeClusterClientInitializer(kubeClusterClient),
 		kcpadmissioninitializers.NewKcpClusterClientInitializer(kcpClusterClient),
+		kcpadmissioninitializers.NewDynamicClusterClientInitializer(dynamicClusterClient),
 		// The external address is provided as a function, as its value may be updated
 		// with the default secure port, when the config is later completed.
 		kcpadmissioninitializers.NewExternalAddressInitializer(func() string { return genericConfig.ExternalAddress }),
@@ -256,10 +383,9 @@ func (s *Server) Run(ctx context.Context) error {
 		admissionPluginInitializers,
 		s.options.GenericControlPlane,
 
-		// Wire in a ServiceResolver that always returns an error that ResolveEndpoint is not yet
-		// supported. The effect is that CRD webhook conversions are not supported and will always get an
-		// error.
-		&unimplementedServiceResolver{},
+		// Services of CRD conversion webhooks are only resolved in the allowed namespaces, to
+		// <name>.<namespace>.svc. Webhooks with a URL are called directly.
+		newConversionWebhookServiceResolver(s.options.Extra.ConversionWebhookServiceNamespaces),
 
 		webhook.NewDefaultAuthenticationInfoResolverWrapper(
 			nil,
@@ -272,6 +398,25 @@ func (s *Server) Run(ctx context.Context) error {
 		return fmt.Errorf("configure api extensions: %w", err)
 	}
 
+	labelIndexes, err := indexes.ParseLabelIndexes(s.options.Extra.WatchCacheLabelIndexes)
+	if err != nil {
+		return err
+	}
+	apisConfig.GenericConfig.RESTOptionsGetter = indexes.WithLabelIndexes(apisConfig.GenericConfig.RESTOptionsGetter, labelIndexes)
+	apiExtensionsConfig.ExtraConfig.CRDRESTOptionsGetter = indexes.WithLabelIndexes(apiExtensionsConfig.ExtraConfig.CRDRESTOptionsGetter, labelIndexes)
+
+	var faultInjector *faultinjection.Injector
+	if s.options.Extra.EnableFaultInjection {
+		klog.Warningf("Fault injection is enabled, storage operations can be delayed or failed through %s", faultinjection.DebugPath)
+		faultInjector = faultinjection.NewInjector()
+	}
+	apisConfig.GenericConfig.RESTOptionsGetter = faultinjection.WithFaultInjection(apisConfig.GenericConfig.RESTOptionsGetter, faultInjector)
+	apiExtensionsConfig.ExtraConfig.CRDRESTOptionsGetter = faultinjection.WithFaultInjection(apiExtensionsConfig.ExtraConfig.CRDRESTOptionsGetter, faultInjector)
+
+	// watches terminated on shutdown end with a bookmark of the watch cache
+	apisConfig.GenericConfig.RESTOptionsGetter = shutdown.WithTerminationBookmarks(apisConfig.GenericConfig.RESTOptionsGetter)
+	apiExtensionsConfig.ExtraConfig.CRDRESTOptionsGetter = shutdown.WithTerminationBookmarks(apiExtensionsConfig.ExtraConfig.CRDRESTOptionsGetter)
+
 	apiBindingAwareCRDLister := &apiBindingAwareCRDLister{
 		kcpClusterClient:  kcpClusterClient,
 		crdLister:         s.apiextensionsSharedInformerFactory.Apiextensions().V1().CustomResourceDefinitions().Lister(),
@@ -311,6 +456,57 @@ func (s *Server) Run(ctx context.Context) error {
 		return err
 	}
 	server := serverChain.MiniAggregator.GenericAPIServer
+	if s.openAPICache != nil {
+		s.openAPICache.SetSources(
+			func() []*spec.Swagger {
+				// only set when the servers are prepared to run
+				return []*spec.Swagger{
+					serverChain.GenericControlPlane.GenericAPIServer.StaticOpenAPISpec,
+					serverChain.CustomResourceDefinitions.GenericAPIServer.StaticOpenAPISpec,
+				}
+			},
+			func(ctx context.Context) ([]*apiextensionsv1.CustomResourceDefinition, error) {
+				return apiBindingAwareCRDLister.List(ctx, labels.Everything())
+			},
+		)
+	}
+	server.Handler.NonGoRestfulMux.Handle(longrunning.DebugPath, s.longRunningRequests)
+	server.Handler.NonGoRestfulMux.Handle(latency.DebugPath, latency.DefaultTracker)
+	server.Handler.NonGoRestfulMux.Handle(admissionchain.DebugPath, admissionchain.DefaultChain)
+	server.Handler.NonGoRestfulMux.Handle(authorization.AccessReportPath, authorization.NewAccessReporter(s.kubeSharedInformerFactory, s.kcpSharedInformerFactory.Tenancy().V1alpha1().DenyPolicies()))
+	server.Handler.NonGoRestfulMux.Handle(catalog.Path, catalog.NewCatalog(s.kcpSharedInformerFactory.Apis().V1alpha1().CatalogEntries(), genericConfig.Authorization.Authorizer))
+	server.Handler.NonGoRestfulMux.Handle(apidocs.Path, apidocs.NewServer(
+		s.kcpSharedInformerFactory.Apis().V1alpha1().APIBindings(),
+		s.kcpSharedInformerFactory.Apis().V1alpha1().APIExports(),
+		s.kcpSharedInformerFactory.Apis().V1alpha1().APIResourceSchemas(),
+		s.kcpSharedInformerFactory.Apis().V1alpha1().CatalogEntries(),
+		s.kubeSharedInformerFactory.Core().V1().ConfigMaps(),
+	))
+	server.Handler.NonGoRestfulMux.Handle(preflight.Path, preflight.NewValidator(
+		genericConfig.LoopbackClientConfig,
+		kubeClusterClient,
+		s.kubeSharedInformerFactory.Core().V1().ResourceQuotas(),
+		genericConfig.Authorization.Authorizer,
+	))
+	referencedByIndex := referencedby.NewIndex()
+	server.Handler.NonGoRestfulMux.Handle(referencedby.Path, referencedby.NewServer(
+		referencedByIndex,
+		dynamicClusterClient,
+		s.kcpSharedInformerFactory.Apis().V1alpha1().APIBindings(),
+		s.kcpSharedInformerFactory.Apis().V1alpha1().APIResourceSchemas(),
+		genericConfig.Authorization.Authorizer,
+	))
+	server.Handler.NonGoRestfulMux.Handle(resolution.Path, resolution.NewResolver(s.kcpSharedInformerFactory.Tenancy().V1alpha1().ClusterWorkspaces(), genericConfig.Authorization.Authorizer, func() string { return genericConfig.ExternalAddress }))
+	if faultInjector != nil {
+		server.Handler.NonGoRestfulMux.Handle(faultinjection.DebugPath, faultInjector)
+		faultinjection.RegisterMetrics()
+	}
+	longrunning.RegisterMetrics()
+	latency.RegisterMetrics()
+	priority.RegisterMetrics()
+	admissionchain.RegisterMetrics()
+	compression.RegisterMetrics()
+	eventbus.RegisterMetrics()
 	serverChain.GenericControlPlane.GenericAPIServer.Handler.GoRestfulContainer.Filter(
 		mergeCRDsIntoCoreGroup(
 			apiBindingAwareCRDLister,
@@ -352,10 +548,10 @@ func (s *Server) Run(ctx context.Context) error {
 
 		// bootstrap root workspace with workspace shard
 		servingCert, _ := server.SecureServingInfo.Cert.CurrentCertKeyContent()
-		if err := configroot.Bootstrap(goContext(ctx),
+		rootReconciler, err := configroot.NewReconciler(
 			apiextensionsClusterClient.Cluster(v1alpha1.RootCluster).Discovery(),
 			dynamicClusterClient.Cluster(v1alpha1.RootCluster),
-			"root",
+			s.options.Extra.ShardName,
 
 			// TODO(sttts): move away from loopback, use external advertise address, an external CA and an access header enabled client servingCert for authentication
 			clientcmdapi.Config{
@@ -370,10 +566,21 @@ func (s *Server) Run(ctx context.Context) error {
 					"shard": {Cluster: "shard"},
 				},
 				CurrentContext: "shard",
-			}); err != nil {
+			},
+			s.options.Bootstrap.RootManifestsDir,
+			s.options.Bootstrap.Prune,
+		)
+		if err != nil {
+			klog.Errorf("failed to create root workspace reconciler: %v", err)
+			return nil // nolint:nilerr
+		}
+		if err := rootReconciler.Converge(goContext(ctx)); err != nil {
 			// nolint:nilerr
 			return nil // don't klog.Fatal. This only happens when context is cancelled.
 		}
+		if s.options.Bootstrap.ResyncPeriod > 0 {
+			go rootReconciler.Start(goContext(ctx), s.options.Bootstrap.ResyncPeriod)
+		}
 
 		klog.Infof("Bootstrapped resources and synced all informers. Ready to start controllers")
 		close(s.syncedCh)
@@ -406,6 +613,20 @@ func (s *Server) Run(ctx context.Context) error {
 		return err
 	}
 
+	if s.meter != nil {
+		if err := s.installMetering(ctx, controllerConfig); err != nil {
+			return err
+		}
+	}
+
+	if err := s.installReferencedByIndex(ctx, controllerConfig, referencedByIndex); err != nil {
+		return err
+	}
+
+	if s.eventBus != nil {
+		s.installEventBus(ctx)
+	}
+
 	enabled := sets.NewString(s.options.Controllers.IndividuallyEnabled...)
 	if len(enabled) > 0 {
 		klog.Infof("Starting controllers individually: %v", enabled)
@@ -458,6 +679,138 @@ func (s *Server) Run(ctx context.Context) error {
 		}
 	}
 
+	if s.options.Controllers.EnableAll || enabled.Has("catalogentry") {
+		if err := s.installCatalogEntryController(ctx, controllerConfig, server); err != nil {
+			return err
+		}
+	}
+
+	if s.options.Controllers.EnableAll || enabled.Has("secretclaim") {
+		if err := s.installSecretClaimController(ctx, controllerConfig, server); err != nil {
+			return err
+		}
+	}
+
+	if s.options.Controllers.EnableAll || enabled.Has("accessgrant") {
+		if err := s.installAccessGrantController(ctx, controllerConfig, server); err != nil {
+			return err
+		}
+	}
+
+	if s.options.Controllers.EnableAll || enabled.Has("replication") {
+		if err := s.installReplicationController(ctx, controllerConfig, server); err != nil {
+			return err
+		}
+	}
+
+	if s.options.Controllers.EnableAll || enabled.Has("policy-bundle") {
+		if err := s.installPolicyBundleController(ctx, controllerConfig, server); err != nil {
+			return err
+		}
+	}
+
+	if s.options.Controllers.EnableAll || enabled.Has("bulk-workspace-operation") {
+		if err := s.installBulkWorkspaceOperationController(ctx, controllerConfig, server); err != nil {
+			return err
+		}
+	}
+
+	if s.options.Controllers.EnableAll || enabled.Has("default-namespaces") {
+		if err := s.installDefaultNamespacesController(ctx, controllerConfig, server); err != nil {
+			return err
+		}
+	}
+
+	if s.options.Controllers.EnableAll || enabled.Has("workspace-template") {
+		if err := s.installWorkspaceTemplateController(ctx, controllerConfig, server); err != nil {
+			return err
+		}
+	}
+
+	if s.options.Controllers.EnableAll || enabled.Has("shard-version") {
+		if err := s.installShardVersionController(ctx, controllerConfig, server); err != nil {
+			return err
+		}
+	}
+
+	if s.options.Controllers.EnableAll || enabled.Has("label-propagation") {
+		if err := s.installLabelPropagationController(ctx, controllerConfig, server); err != nil {
+			return err
+		}
+	}
+
+	if s.options.Controllers.EnableAll || enabled.Has("workload-usage") {
+		if err := s.installWorkloadUsageController(ctx, controllerConfig, server); err != nil {
+			return err
+		}
+	}
+
+	if s.options.Controllers.EnableAll || enabled.Has("dnsrecord") {
+		if err := s.installDNSRecordController(ctx, controllerConfig, server); err != nil {
+			return err
+		}
+	}
+
+	if s.options.Controllers.EnableAll || enabled.Has("certificate") {
+		if err := s.installCertificateController(ctx, controllerConfig, server); err != nil {
+			return err
+		}
+	}
+
+	if utilfeature.DefaultFeatureGate.Enabled(kcpfeatures.WorkloadIdentity) && (s.options.Controllers.EnableAll || enabled.Has("workload-identity")) {
+		if err := s.installWorkloadIdentityController(ctx, controllerConfig, server); err != nil {
+			return err
+		}
+	}
+
+	if utilfeature.DefaultFeatureGate.Enabled(kcpfeatures.EventSubscriptions) && (s.options.Controllers.EnableAll || enabled.Has("eventsubscription")) {
+		if err := s.installEventSubscriptionController(ctx, controllerConfig, server); err != nil {
+			return err
+		}
+	}
+
+	if utilfeature.DefaultFeatureGate.Enabled(kcpfeatures.TTLPolicies) && (s.options.Controllers.EnableAll || enabled.Has("ttlpolicy")) {
+		if err := s.installTTLPolicyController(ctx, controllerConfig, server); err != nil {
+			return err
+		}
+	}
+
+	if s.options.Controllers.EnableAll || enabled.Has("workspace-expiration") {
+		if err := s.installWorkspaceExpirationController(ctx, controllerConfig, server); err != nil {
+			return err
+		}
+	}
+
+	if s.options.Controllers.EnableAll || enabled.Has("object-transfer") {
+		if err := s.installObjectTransferController(ctx, controllerConfig, server); err != nil {
+			return err
+		}
+	}
+
+	if s.workspaceActivity != nil && (s.options.Controllers.EnableAll || enabled.Has("workspace-hibernation")) {
+		if err := s.installHibernationController(ctx, controllerConfig, server); err != nil {
+			return err
+		}
+	}
+
+	if s.homeWorkspaceLogins != nil {
+		if err := s.installHomeWorkspaceController(ctx, controllerConfig, server); err != nil {
+			return err
+		}
+	}
+
+	if s.deprecatedAPIUsage != nil {
+		if err := s.installDeprecatedAPIUsageController(ctx, controllerConfig, server); err != nil {
+			return err
+		}
+	}
+
+	if s.options.Controllers.LeaseGC.TTL > 0 && (s.options.Controllers.EnableAll || enabled.Has("lease-gc")) {
+		if err := s.installLeaseGCController(ctx, controllerConfig, server); err != nil {
+			return err
+		}
+	}
+
 	if utilfeature.DefaultFeatureGate.Enabled(kcpfeatures.LocationAPI) {
 		if s.options.Controllers.EnableAll || enabled.Has("scheduling") {
 			if err := s.installSchedulingLocationStatusController(ctx, controllerConfig, server); err != nil {
@@ -480,6 +833,15 @@ func (s *Server) Run(ctx context.Context) error {
 		return err
 	}
 
+	// Terminate watches while the server is still serving during the shutdown delay,
+	// for clients to move to other replicas before the listener is closed.
+	if err := server.AddPreShutdownHook("kcp-terminate-watches", func() error {
+		s.watchTerminator.Terminate(genericConfig.ShutdownDelayDuration)
+		return nil
+	}); err != nil {
+		return err
+	}
+
 	// Add our custom hooks to the underlying api server
 	for _, entry := range s.postStartHooks {
 		err := server.AddPostStartHook(entry.name, entry.hook)
@@ -0,0 +1,172 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shutdown
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/klog/v2"
+)
+
+// bookmarkDeadline is the deadline of the watch re-established to get the final
+// bookmark. The watch cache sends a bookmark to watchers allowing them two seconds
+// before their deadline, i.e. right away, and ends the watch at the deadline.
+const bookmarkDeadline = 3 * time.Second
+
+// WithTerminationBookmark returns a watch that, when terminated by the WatchTerminator
+// serving the watch request of ctx, ends with a bookmark of the current resource version of
+// the watch cache. The watch cache only knows its current resource version, which for
+// filtered watches is usually newer than the one of the last event, within its dispatch
+// loop. Hence, on termination, w is stopped, and the watch is re-established through
+// rewatch from the last resource version sent, with a deadline of bookmarkDeadline. The
+// events up to the bookmark of the watch cache are passed on, and then the watch ends.
+//
+// resourceVersion is the resource version w was started from. Watches not served by a
+// WatchTerminator are returned unchanged. Callers must only use this for watches which
+// asked for bookmarks.
+func WithTerminationBookmark(ctx context.Context, w watch.Interface, resourceVersion string, rewatch func(ctx context.Context, resourceVersion string) (watch.Interface, error)) watch.Interface {
+	bw := &bookmarkingWatch{
+		result:   make(chan watch.Event),
+		stopCh:   make(chan struct{}),
+		finishCh: make(chan struct{}),
+		done:     make(chan struct{}),
+		rewatch:  rewatch,
+	}
+	if !OnTerminate(ctx, bw.finish) {
+		return w
+	}
+	// "" and "0" start with the current state, which must not be sent again.
+	if resourceVersion == "0" {
+		resourceVersion = ""
+	}
+	go bw.run(ctx, w, resourceVersion)
+	return bw
+}
+
+type bookmarkingWatch struct {
+	result   chan watch.Event
+	stopCh   chan struct{}
+	stopOnce sync.Once
+
+	finishCh   chan struct{}
+	finishOnce sync.Once
+	done       chan struct{}
+
+	rewatch func(ctx context.Context, resourceVersion string) (watch.Interface, error)
+}
+
+func (w *bookmarkingWatch) ResultChan() <-chan watch.Event {
+	return w.result
+}
+
+func (w *bookmarkingWatch) Stop() {
+	w.stopOnce.Do(func() { close(w.stopCh) })
+}
+
+// finish makes the watch end with a bookmark, and returns when it has ended.
+func (w *bookmarkingWatch) finish() {
+	w.finishOnce.Do(func() { close(w.finishCh) })
+	select {
+	case <-w.done:
+	case <-time.After(2 * bookmarkDeadline):
+	}
+}
+
+func (w *bookmarkingWatch) run(ctx context.Context, inner watch.Interface, resourceVersion string) {
+	defer close(w.done)
+	defer close(w.result)
+
+	for {
+		select {
+		case e, ok := <-inner.ResultChan():
+			if !ok {
+				inner.Stop()
+				return
+			}
+			if !w.send(e) {
+				inner.Stop()
+				return
+			}
+			if rv := resourceVersionOf(e); rv != "" {
+				resourceVersion = rv
+			}
+		case <-w.finishCh:
+			inner.Stop()
+			w.sendBookmark(ctx, resourceVersion)
+			return
+		case <-w.stopCh:
+			inner.Stop()
+			return
+		}
+	}
+}
+
+// sendBookmark passes on the events from the given resource version up to the next
+// bookmark of a watch re-established with a close deadline.
+func (w *bookmarkingWatch) sendBookmark(ctx context.Context, resourceVersion string) {
+	if resourceVersion == "" {
+		return // nothing sent yet
+	}
+	ctx, cancel := context.WithTimeout(ctx, bookmarkDeadline)
+	defer cancel()
+	inner, err := w.rewatch(ctx, resourceVersion)
+	if err != nil {
+		klog.V(4).Infof("Failed to re-establish watch for the final bookmark: %v", err)
+		return
+	}
+	defer inner.Stop()
+
+	for {
+		select {
+		case e, ok := <-inner.ResultChan():
+			if !ok || e.Type == watch.Error {
+				return // e.g. too old resource version, or the deadline passed without bookmark
+			}
+			if !w.send(e) || e.Type == watch.Bookmark {
+				return
+			}
+		case <-ctx.Done():
+			return
+		case <-w.stopCh:
+			return
+		}
+	}
+}
+
+func (w *bookmarkingWatch) send(e watch.Event) bool {
+	select {
+	case w.result <- e:
+		return true
+	case <-w.stopCh:
+		return false
+	}
+}
+
+func resourceVersionOf(e watch.Event) string {
+	if e.Type == watch.Error || e.Object == nil {
+		return ""
+	}
+	o, err := meta.Accessor(e.Object)
+	if err != nil {
+		return ""
+	}
+	return o.GetResourceVersion()
+}
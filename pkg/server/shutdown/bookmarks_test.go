@@ -0,0 +1,164 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shutdown
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func configMap(rv string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "default", ResourceVersion: rv}}
+}
+
+// serveWatch serves the events of w as JSON, like the watch server of the apiserver.
+func serveWatch(w http.ResponseWriter, req *http.Request, watcher watch.Interface) {
+	defer watcher.Stop()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.(http.Flusher).Flush()
+	enc := json.NewEncoder(w)
+	for {
+		select {
+		case e, ok := <-watcher.ResultChan():
+			if !ok {
+				return
+			}
+			bs, err := json.Marshal(e.Object)
+			if err != nil {
+				panic(err)
+			}
+			if err := enc.Encode(&metav1.WatchEvent{Type: string(e.Type), Object: runtime.RawExtension{Raw: bs}}); err != nil {
+				return
+			}
+			w.(http.Flusher).Flush()
+		case <-req.Context().Done():
+			return
+		}
+	}
+}
+
+func TestTerminationBookmark(t *testing.T) {
+	tests := map[string]struct {
+		startRV     string
+		events      []watch.Event
+		rewatch     []watch.Event
+		wantRewatch string
+		wantEvents  []string
+	}{
+		"bookmark of the watch cache after the last event": {
+			startRV:     "10",
+			events:      []watch.Event{{Type: watch.Added, Object: configMap("11")}},
+			rewatch:     []watch.Event{{Type: watch.Bookmark, Object: configMap("42")}},
+			wantRewatch: "11",
+			wantEvents:  []string{"ADDED:11", "BOOKMARK:42"},
+		},
+		"events not sent before termination are passed on before the bookmark": {
+			startRV:     "10",
+			events:      []watch.Event{{Type: watch.Added, Object: configMap("11")}},
+			rewatch:     []watch.Event{{Type: watch.Modified, Object: configMap("12")}, {Type: watch.Bookmark, Object: configMap("42")}},
+			wantRewatch: "11",
+			wantEvents:  []string{"ADDED:11", "MODIFIED:12", "BOOKMARK:42"},
+		},
+		"bookmark of a filtered watch without events": {
+			startRV:     "10",
+			rewatch:     []watch.Event{{Type: watch.Bookmark, Object: configMap("42")}},
+			wantRewatch: "10",
+			wantEvents:  []string{"BOOKMARK:42"},
+		},
+		"no bookmark when the watch cache does not send one": {
+			startRV:     "10",
+			events:      []watch.Event{{Type: watch.Added, Object: configMap("11")}},
+			wantRewatch: "11",
+			wantEvents:  []string{"ADDED:11"},
+		},
+		"no bookmark before the initial state was sent": {
+			startRV: "0",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			sent := make(chan struct{})
+			var rewatched string
+			var rewatchDeadline bool
+			terminator := NewWatchTerminator()
+			server := httptest.NewServer(terminator.WithWatchTermination(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				inner := watch.NewFakeWithChanSize(len(tt.events), false)
+				for _, e := range tt.events {
+					inner.Action(e.Type, e.Object)
+				}
+				watcher := WithTerminationBookmark(req.Context(), inner, tt.startRV, func(ctx context.Context, resourceVersion string) (watch.Interface, error) {
+					rewatched = resourceVersion
+					_, rewatchDeadline = ctx.Deadline()
+					w := watch.NewFakeWithChanSize(len(tt.rewatch), false)
+					for _, e := range tt.rewatch {
+						w.Action(e.Type, e.Object)
+					}
+					return w, nil
+				})
+				close(sent)
+				serveWatch(w, req, watcher)
+			})))
+			defer server.Close()
+
+			resp, err := http.Get(server.URL + "/api/v1/configmaps?watch=true&allowWatchBookmarks=true")
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			<-sent
+
+			dec := json.NewDecoder(resp.Body)
+			var got []string
+			next := func() metav1.WatchEvent {
+				var event metav1.WatchEvent
+				require.NoError(t, dec.Decode(&event))
+				if event.Type != string(watch.Error) {
+					var cm corev1.ConfigMap
+					require.NoError(t, json.Unmarshal(event.Object.Raw, &cm))
+					got = append(got, event.Type+":"+cm.ResourceVersion)
+				}
+				return event
+			}
+			for range tt.events {
+				next()
+			}
+
+			terminator.Terminate(time.Second)
+
+			for {
+				if event := next(); event.Type == string(watch.Error) {
+					break
+				}
+			}
+			require.Equal(t, tt.wantEvents, got)
+			require.Equal(t, tt.wantRewatch, rewatched)
+			if rewatched != "" {
+				require.True(t, rewatchDeadline, "the watch cache only sends a bookmark right away to watches with a close deadline")
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shutdown
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apiserver/pkg/registry/generic"
+	"k8s.io/apiserver/pkg/storage"
+	"k8s.io/apiserver/pkg/storage/storagebackend"
+	"k8s.io/apiserver/pkg/storage/storagebackend/factory"
+	"k8s.io/client-go/tools/cache"
+)
+
+// WithTerminationBookmarks wraps a RESTOptionsGetter such that the watches of all resources
+// which asked for bookmarks get a final bookmark when terminated by a WatchTerminator. With
+// the watch cache disabled, the storage sends no bookmarks, and watches just end.
+func WithTerminationBookmarks(delegate generic.RESTOptionsGetter) generic.RESTOptionsGetter {
+	return &bookmarkingRESTOptionsGetter{delegate: delegate}
+}
+
+type bookmarkingRESTOptionsGetter struct {
+	delegate generic.RESTOptionsGetter
+}
+
+func (g *bookmarkingRESTOptionsGetter) GetRESTOptions(resource schema.GroupResource) (generic.RESTOptions, error) {
+	options, err := g.delegate.GetRESTOptions(resource)
+	if err != nil {
+		return options, err
+	}
+	if options.Decorator == nil {
+		return options, nil
+	}
+
+	decorator := options.Decorator
+	options.Decorator = func(
+		config *storagebackend.ConfigForResource,
+		resourcePrefix string,
+		keyFunc func(obj runtime.Object) (string, error),
+		newFunc func() runtime.Object,
+		newListFunc func() runtime.Object,
+		getAttrsFunc storage.AttrFunc,
+		trigger storage.IndexerFuncs,
+		indexers *cache.Indexers,
+	) (storage.Interface, factory.DestroyFunc, error) {
+		s, destroy, err := decorator(config, resourcePrefix, keyFunc, newFunc, newListFunc, getAttrsFunc, trigger, indexers)
+		if err != nil {
+			return s, destroy, err
+		}
+		return &bookmarkingStorage{Interface: s}, destroy, nil
+	}
+	return options, nil
+}
+
+// bookmarkingStorage ends the watches of a storage.Interface with a bookmark on termination.
+type bookmarkingStorage struct {
+	storage.Interface
+}
+
+func (s *bookmarkingStorage) Watch(ctx context.Context, key string, opts storage.ListOptions) (watch.Interface, error) {
+	w, err := s.Interface.Watch(ctx, key, opts)
+	if err != nil || !opts.Predicate.AllowWatchBookmarks {
+		return w, err
+	}
+	return WithTerminationBookmark(ctx, w, opts.ResourceVersion, func(ctx context.Context, resourceVersion string) (watch.Interface, error) {
+		opts := opts
+		opts.ResourceVersion = resourceVersion
+		return s.Interface.Watch(ctx, key, opts)
+	}), nil
+}
+
+func (s *bookmarkingStorage) WatchList(ctx context.Context, key string, opts storage.ListOptions) (watch.Interface, error) {
+	w, err := s.Interface.WatchList(ctx, key, opts)
+	if err != nil || !opts.Predicate.AllowWatchBookmarks {
+		return w, err
+	}
+	return WithTerminationBookmark(ctx, w, opts.ResourceVersion, func(ctx context.Context, resourceVersion string) (watch.Interface, error) {
+		opts := opts
+		opts.ResourceVersion = resourceVersion
+		return s.Interface.WatchList(ctx, key, opts)
+	}), nil
+}
@@ -0,0 +1,285 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shutdown
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/endpoints/responsewriter"
+	"k8s.io/klog/v2"
+)
+
+// RetryAfterSeconds is sent to clients whose watch is terminated or rejected
+// during shutdown.
+const RetryAfterSeconds = 1
+
+// WatchTerminator tracks the active watches of a server, and terminates them
+// on shutdown with a retriable error, such that clients re-establish them
+// against another replica, from the last resource version they have seen.
+// Once terminating, all responses carry "Connection: close", which makes
+// HTTP/1 clients close their connections and HTTP/2 servers send a GOAWAY.
+// That signals the front-proxy and other clients to drain their connections
+// to the server, and to send new requests through new ones, e.g. to another
+// replica behind the same service.
+//
+// Terminated watches using JSON get a final ERROR event with a 429 status
+// carrying a retry-after. Other encodings, and proxied streams cut in the
+// middle of an event, just see the stream end. Clients re-establish the watch
+// from the last resource version they have seen, or relist if it is too old.
+// After Terminate is called, new watches are rejected with 429 and a
+// Retry-After header.
+//
+// Watches served from storage wrapped by WithTerminationBookmarks, which asked
+// for bookmarks, get a bookmark of the current resource version of the watch
+// cache before they are terminated, see WithTerminationBookmark. Non-watch
+// requests in flight are not tracked here: the generic apiserver and the HTTP
+// server wait for them to finish when shutting down.
+type WatchTerminator struct {
+	lock        sync.Mutex
+	watches     map[*activeWatch]struct{}
+	terminating bool
+}
+
+type activeWatch struct {
+	cancel     context.CancelFunc
+	terminated bool
+	// finish, if set, ends the watch gracefully before it is canceled.
+	finish func()
+}
+
+type activeWatchKeyType int
+
+const activeWatchKey activeWatchKeyType = iota
+
+// activeWatchContext is stored in the context of watch requests, for their storage to
+// register with OnTerminate.
+type activeWatchContext struct {
+	terminator *WatchTerminator
+	active     *activeWatch
+}
+
+// OnTerminate registers finish to end the watch served with the given context gracefully,
+// e.g. with a final event, when the WatchTerminator serving it terminates it. The watch is
+// canceled when finish returns. It returns false if the watch is not served by a
+// WatchTerminator, or is already being terminated.
+func OnTerminate(ctx context.Context, finish func()) bool {
+	wc, ok := ctx.Value(activeWatchKey).(activeWatchContext)
+	if !ok {
+		return false
+	}
+	wc.terminator.lock.Lock()
+	defer wc.terminator.lock.Unlock()
+	if wc.active.terminated {
+		return false
+	}
+	wc.active.finish = finish
+	return true
+}
+
+// NewWatchTerminator returns a WatchTerminator without active watches.
+func NewWatchTerminator() *WatchTerminator {
+	return &WatchTerminator{
+		watches: map[*activeWatch]struct{}{},
+	}
+}
+
+// WithWatchTermination tracks the watch requests served by handler. Watches
+// are recognized by their RequestInfo if the RequestInfo filter ran before,
+// and by their watch parameter otherwise, e.g. in a proxy.
+func (t *WatchTerminator) WithWatchTermination(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		t.lock.Lock()
+		terminating := t.terminating
+		t.lock.Unlock()
+		if terminating && !httpstream.IsUpgradeRequest(req) {
+			w.Header().Set("Connection", "close")
+		}
+
+		if !isWatch(req) {
+			handler.ServeHTTP(w, req)
+			return
+		}
+
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		active := &activeWatch{cancel: cancel}
+		ctx = context.WithValue(ctx, activeWatchKey, activeWatchContext{terminator: t, active: active})
+
+		t.lock.Lock()
+		if t.terminating {
+			t.lock.Unlock()
+			w.Header().Set("Retry-After", strconv.Itoa(RetryAfterSeconds))
+			writeStatus(w, http.StatusTooManyRequests, tooManyRequests("The server is shutting down, please retry."))
+			return
+		}
+		t.watches[active] = struct{}{}
+		t.lock.Unlock()
+
+		defer func() {
+			t.lock.Lock()
+			delete(t.watches, active)
+			t.lock.Unlock()
+		}()
+
+		sw := &streamWriter{ResponseWriter: w, atEventBoundary: true}
+		t.serveWatch(handler, responsewriter.WrapForHTTP1Or2(sw), req.WithContext(ctx), active)
+
+		t.lock.Lock()
+		terminated := active.terminated
+		t.lock.Unlock()
+
+		// Only append the event if the stream is still open towards the client, and
+		// the last event has been written completely, which a proxy does not guarantee.
+		// Websocket watches have hijacked the connection, and just see it close.
+		if terminated && !httpstream.IsUpgradeRequest(req) && req.Context().Err() == nil && sw.atEventBoundary && strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+			if err := json.NewEncoder(w).Encode(&metav1.WatchEvent{
+				Type:   string(watch.Error),
+				Object: rawStatus(tooManyRequests("The server is shutting down, please re-establish the watch.")),
+			}); err != nil {
+				klog.V(4).Infof("Failed to send termination event to watch %s: %v", req.URL, err)
+			}
+			if f, ok := w.(http.Flusher); ok {
+				f.Flush()
+			}
+		}
+	})
+}
+
+// serveWatch serves the watch, recovering the http.ErrAbortHandler panic of a
+// reverse proxy whose upstream stream ends because the watch was terminated.
+func (t *WatchTerminator) serveWatch(handler http.Handler, w http.ResponseWriter, req *http.Request, active *activeWatch) {
+	defer func() {
+		if r := recover(); r != nil {
+			t.lock.Lock()
+			terminated := active.terminated
+			t.lock.Unlock()
+			if r != http.ErrAbortHandler || !terminated {
+				panic(r)
+			}
+		}
+	}()
+	handler.ServeHTTP(w, req)
+}
+
+// streamWriter records whether the stream written so far ends on a JSON watch
+// event boundary, i.e. with the newline terminating each event.
+type streamWriter struct {
+	http.ResponseWriter
+	atEventBoundary bool
+}
+
+func (w *streamWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+	if n > 0 {
+		w.atEventBoundary = p[n-1] == '\n'
+	}
+	return n, err
+}
+
+func (w *streamWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Terminate rejects new watches, and terminates the active ones, spread
+// evenly over gracePeriod to avoid all clients re-establishing their watches
+// at once. Watches with a finish function registered by OnTerminate are
+// canceled when it returns. Terminate returns when all watches have been
+// terminated.
+func (t *WatchTerminator) Terminate(gracePeriod time.Duration) {
+	t.lock.Lock()
+	t.terminating = true
+	watches := make([]*activeWatch, 0, len(t.watches))
+	for w := range t.watches {
+		watches = append(watches, w)
+	}
+	t.lock.Unlock()
+
+	if len(watches) == 0 {
+		return
+	}
+	klog.Infof("Terminating %d watches within %s", len(watches), gracePeriod)
+
+	interval := gracePeriod / time.Duration(len(watches))
+	var wg sync.WaitGroup
+	for i, w := range watches {
+		if i > 0 && interval > 0 {
+			time.Sleep(interval)
+		}
+		t.lock.Lock()
+		w.terminated = true
+		finish := w.finish
+		t.lock.Unlock()
+
+		wg.Add(1)
+		go func(w *activeWatch) {
+			defer wg.Done()
+			if finish != nil {
+				finish()
+			}
+			w.cancel()
+		}(w)
+	}
+	wg.Wait()
+}
+
+// Active returns the number of active watches.
+func (t *WatchTerminator) Active() int {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return len(t.watches)
+}
+
+func isWatch(req *http.Request) bool {
+	if info, ok := request.RequestInfoFrom(req.Context()); ok && info.IsResourceRequest {
+		return info.Verb == "watch"
+	}
+	watch, _ := strconv.ParseBool(req.URL.Query().Get("watch"))
+	return watch
+}
+
+func tooManyRequests(message string) *metav1.Status {
+	return &metav1.Status{
+		TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+		Status:   metav1.StatusFailure,
+		Message:  message,
+		Reason:   metav1.StatusReasonTooManyRequests,
+		Code:     http.StatusTooManyRequests,
+		Details:  &metav1.StatusDetails{RetryAfterSeconds: RetryAfterSeconds},
+	}
+}
+
+func rawStatus(status *metav1.Status) runtime.RawExtension {
+	bs, _ := json.Marshal(status)
+	return runtime.RawExtension{Raw: bs}
+}
+
+func writeStatus(w http.ResponseWriter, code int, status *metav1.Status) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(status)
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shutdown
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func TestWatchTerminator(t *testing.T) {
+	started := make(chan struct{}, 1)
+	terminator := NewWatchTerminator()
+	server := httptest.NewServer(terminator.WithWatchTermination(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		started <- struct{}{}
+		<-req.Context().Done()
+	})))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/v1/configmaps?watch=true")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	<-started
+	require.Equal(t, 1, terminator.Active())
+
+	terminator.Terminate(time.Second)
+
+	var event metav1.WatchEvent
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&event))
+	require.Equal(t, string(watch.Error), event.Type)
+	var status metav1.Status
+	require.NoError(t, json.Unmarshal(event.Object.Raw, &status))
+	require.Equal(t, metav1.StatusReasonTooManyRequests, status.Reason)
+	require.Equal(t, int32(RetryAfterSeconds), status.Details.RetryAfterSeconds)
+	_, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	rejected, err := http.Get(server.URL + "/api/v1/configmaps?watch=true")
+	require.NoError(t, err)
+	defer rejected.Body.Close()
+	require.Equal(t, http.StatusTooManyRequests, rejected.StatusCode)
+	require.Equal(t, "1", rejected.Header.Get("Retry-After"))
+	require.Eventually(t, func() bool { return terminator.Active() == 0 }, wait.ForeverTestTimeout, 10*time.Millisecond)
+}
+
+func TestWatchTerminatorDrainsConnections(t *testing.T) {
+	terminator := NewWatchTerminator()
+	server := httptest.NewServer(terminator.WithWatchTermination(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/v1/configmaps")
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.False(t, resp.Close, "connections should be kept alive before termination")
+
+	terminator.Terminate(time.Second)
+
+	resp, err = http.Get(server.URL + "/api/v1/configmaps")
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.True(t, resp.Close, "connections should be closed after termination")
+}
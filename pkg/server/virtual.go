@@ -74,6 +74,7 @@ func (s *Server) installVirtualWorkspaces(ctx context.Context, kubeClusterClient
 		return err
 	}
 	rootAPIServerConfig.GenericConfig.ExternalAddress = externalAddress
+	rootAPIServerConfig.ExtraConfig.WatchTerminator = s.watchTerminator
 	completedRootAPIServerConfig := rootAPIServerConfig.Complete()
 	rootAPIServer, err := completedRootAPIServerConfig.New(genericapiserver.NewEmptyDelegate())
 	if err != nil {
@@ -195,8 +195,9 @@ func collapseResourceVersion(complexResourceVersion *string, identifier string)
 	if complexResourceVersion == nil {
 		return "", nil
 	}
-	state, err := NewResourceVersionState(*complexResourceVersion, []string{identifier}, 0)
-	if err != nil {
+	// decode without reconciling, a version of another shard must not become any version
+	state := &ShardedResourceVersions{}
+	if err := state.Decode(*complexResourceVersion); err != nil {
 		return "", fmt.Errorf("failed to parse sharded resource version state: %w", err)
 	}
 	resourceVersion := int64(-1)
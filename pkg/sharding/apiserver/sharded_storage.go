@@ -48,17 +48,24 @@ func (s *shardedStorage) Watch(ctx context.Context, options *internalversion.Lis
 	}
 	state, err := NewResourceVersionState(options.ResourceVersion, shardIdentifiers, s.shardIdentifierResourceVersion)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse sharded resource version state: %w", err)
+		return nil, errors.NewBadRequest(fmt.Sprintf("failed to parse sharded resource version state: %v", err))
 	}
 
 	watchers := map[string]watch.Interface{}
+	stopAll := func() {
+		for _, watcher := range watchers {
+			watcher.Stop()
+		}
+	}
 	for i := range state.ResourceVersions {
 		client, err := s.clientFor(s.shards[state.ResourceVersions[i].Identifier])
 		if err != nil {
+			stopAll()
 			return nil, fmt.Errorf("failed to create sharded client: %w", err)
 		}
 		request, err := s.requestFor(client)
 		if err != nil {
+			stopAll()
 			return nil, fmt.Errorf("failed to create sharded request: %w", err)
 		}
 		request.OverwriteParam("limit", "500")
@@ -66,7 +73,8 @@ func (s *shardedStorage) Watch(ctx context.Context, options *internalversion.Lis
 		request.SetHeader("X-Kubernetes-Cluster", "*")
 		watcher, err := request.Watch(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("error executing watch request: %w", err)
+			stopAll()
+			return nil, shardError(state.ResourceVersions[i].Identifier, err)
 		}
 		watchers[state.ResourceVersions[i].Identifier] = watcher
 	}
@@ -74,66 +82,96 @@ func (s *shardedStorage) Watch(ctx context.Context, options *internalversion.Lis
 	return NewAggregateWatcher(state, watchers), nil
 }
 
+// shardError wraps an error of a shard, keeping the status of expired resource versions
+// intact such that clients know they have to relist.
+func shardError(identifier string, err error) error {
+	if errors.IsResourceExpired(err) || errors.IsGone(err) {
+		return errors.NewResourceExpired(fmt.Sprintf("resource version of shard %q is too old: %v", identifier, err))
+	}
+	return fmt.Errorf("failed to get data from shard %q: %w", identifier, err)
+}
+
 type stopper interface {
 	Stop()
 }
 
+// aggregateWatcher multiplexes the watches of all shards into one, annotating every
+// event with the composite resource version of all shards. When the watch of any shard
+// ends, the aggregate watch ends as well, such that the client resumes all shards from
+// the last composite resource version it has seen instead of silently missing events.
 type aggregateWatcher struct {
 	delegates []stopper
 	wg        *sync.WaitGroup
 	events    chan watch.Event
+	done      chan struct{}
+	stopOnce  *sync.Once
 
 	state *ShardedResourceVersions
 	lock  *sync.Mutex
 }
 
 func (a *aggregateWatcher) Stop() {
-	for i := range a.delegates {
-		a.delegates[i].Stop()
-	}
-	a.wg.Wait()
-	close(a.events)
+	a.stopOnce.Do(func() {
+		close(a.done)
+		for i := range a.delegates {
+			a.delegates[i].Stop()
+		}
+	})
 }
 
 func (a *aggregateWatcher) ResultChan() <-chan watch.Event {
 	return a.events
 }
 
+// send forwards the event to the client, unless the watch is stopped.
+func (a *aggregateWatcher) send(event watch.Event) {
+	select {
+	case a.events <- event:
+	case <-a.done:
+	}
+}
+
 func (a *aggregateWatcher) process(identifier string, event watch.Event) {
+	if event.Type == watch.Error {
+		// errors, e.g. expired resource versions, have to reach the client as they are
+		a.send(event)
+		return
+	}
 	obj, ok := event.Object.(metav1.Common)
 	if !ok {
-		a.events <- watch.Event{
+		a.send(watch.Event{
 			Type:   watch.Error,
 			Object: &errors.NewInternalError(fmt.Errorf("watch event contained a %T which could not cast to metav1.Common", event.Object)).ErrStatus,
-		}
+		})
 		return
 	}
 	a.lock.Lock()
-	err := a.state.UpdateWith(identifier, obj)
-	a.lock.Unlock()
-	if err != nil {
-		a.events <- watch.Event{
+	defer a.lock.Unlock()
+	if err := a.state.UpdateWith(identifier, obj); err != nil {
+		a.send(watch.Event{
 			Type:   watch.Error,
 			Object: &errors.NewInternalError(fmt.Errorf("failed to update resource version vector clock: %w", err)).ErrStatus,
-		}
+		})
 		return
 	}
 	encoded, err := a.state.Encode()
 	if err != nil {
-		a.events <- watch.Event{
+		a.send(watch.Event{
 			Type:   watch.Error,
 			Object: &errors.NewInternalError(fmt.Errorf("failed to encode resource version vector clock: %w", err)).ErrStatus,
-		}
+		})
 		return
 	}
 	obj.SetResourceVersion(encoded)
-	a.events <- event
+	a.send(event)
 }
 
 func NewAggregateWatcher(state *ShardedResourceVersions, delegates map[string]watch.Interface) watch.Interface {
 	w := &aggregateWatcher{
 		delegates: []stopper{},
 		events:    make(chan watch.Event),
+		done:      make(chan struct{}),
+		stopOnce:  &sync.Once{},
 		wg:        &sync.WaitGroup{},
 		state:     state,
 		lock:      &sync.Mutex{},
@@ -143,12 +181,26 @@ func NewAggregateWatcher(state *ShardedResourceVersions, delegates map[string]wa
 		go func(identifier string, events <-chan watch.Event) {
 			defer utilruntime.HandleCrash()
 			defer w.wg.Done()
-			for event := range events {
-				w.process(identifier, event)
+			// the first shard watch to end ends all of them
+			defer w.Stop()
+			for {
+				select {
+				case event, ok := <-events:
+					if !ok {
+						return
+					}
+					w.process(identifier, event)
+				case <-w.done:
+					return
+				}
 			}
 		}(identifier, delegates[identifier].ResultChan())
 		w.delegates = append(w.delegates, delegates[identifier])
 	}
+	go func() {
+		w.wg.Wait()
+		close(w.events)
+	}()
 	return w
 }
 
@@ -166,7 +218,15 @@ func (s *shardedStorage) List(ctx context.Context, options *internalversion.List
 	}
 	state, err := NewChunkedState(options.Continue, shardIdentifiers, s.shardIdentifierResourceVersion)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse sharded chunked state: %w", err)
+		return nil, errors.NewBadRequest(fmt.Sprintf("failed to parse sharded chunked state: %v", err))
+	}
+	if options.Continue == "" && options.ResourceVersionMatch == metav1.ResourceVersionMatchExact {
+		// the client wants the snapshot of a former list, pin every shard to its version
+		snapshot, err := NewResourceVersionState(options.ResourceVersion, shardIdentifiers, s.shardIdentifierResourceVersion)
+		if err != nil {
+			return nil, errors.NewBadRequest(fmt.Sprintf("failed to parse sharded resource version state: %v", err))
+		}
+		state.PinTo(snapshot)
 	}
 	var output *unstructured.UnstructuredList
 	for {
@@ -184,10 +244,17 @@ func (s *shardedStorage) List(ctx context.Context, options *internalversion.List
 		}
 		request.OverwriteParam("limit", strconv.FormatInt(options.Limit, 10))
 		request.OverwriteParam("continue", continueToken)
+		// the composite resource version of the client means nothing to the shard
+		request.OverwriteParam("resourceVersion", "")
+		request.OverwriteParam("resourceVersionMatch", "")
+		if snapshot := state.SnapshotFor(shard); continueToken == "" && snapshot != 0 {
+			request.OverwriteParam("resourceVersion", strconv.FormatInt(snapshot, 10))
+			request.OverwriteParam("resourceVersionMatch", string(metav1.ResourceVersionMatchExact))
+		}
 		request.SetHeader("X-Kubernetes-Cluster", "*")
 		result, err := request.Do(ctx).Get()
 		if err != nil {
-			return nil, fmt.Errorf("failed to get data from shard %q: %w", shard, err)
+			return nil, shardError(shard, err)
 		}
 		var list *unstructured.UnstructuredList
 		switch r := result.(type) {
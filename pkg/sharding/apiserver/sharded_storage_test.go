@@ -0,0 +1,74 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func TestAggregateWatcher(t *testing.T) {
+	first, second := watch.NewFake(), watch.NewFake()
+	w := NewAggregateWatcher(&ShardedResourceVersions{
+		ShardResourceVersion: 1,
+		ResourceVersions:     []ShardedResourceVersion{{Identifier: "first", ResourceVersion: 10}, {Identifier: "second", ResourceVersion: 20}},
+	}, map[string]watch.Interface{"first": first, "second": second})
+
+	next := func() watch.Event {
+		select {
+		case event, ok := <-w.ResultChan():
+			require.True(t, ok, "expected the watch to be open")
+			return event
+		case <-time.After(wait.ForeverTestTimeout):
+			t.Fatal("timed out waiting for event")
+		}
+		return watch.Event{}
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetResourceVersion("11")
+	go first.Add(obj)
+	event := next()
+	require.Equal(t, watch.Added, event.Type)
+	state := &ShardedResourceVersions{}
+	require.NoError(t, state.Decode(event.Object.(metav1.Common).GetResourceVersion()))
+	require.Equal(t, []ShardedResourceVersion{{Identifier: "first", ResourceVersion: 11}, {Identifier: "second", ResourceVersion: 20}}, state.ResourceVersions)
+
+	// errors of a shard reach the client as they are
+	expired := &metav1.Status{Status: metav1.StatusFailure, Code: 410, Reason: metav1.StatusReasonExpired}
+	go second.Error(expired)
+	event = next()
+	require.Equal(t, watch.Error, event.Type)
+	require.Equal(t, expired, event.Object)
+
+	// the end of a shard watch ends the aggregate watch
+	second.Stop()
+	select {
+	case _, ok := <-w.ResultChan():
+		require.False(t, ok, "expected the watch to be closed")
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Fatal("timed out waiting for the watch to close")
+	}
+	require.True(t, first.IsStopped(), "expected the other shard watches to be stopped")
+}
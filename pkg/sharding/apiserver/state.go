@@ -118,6 +118,29 @@ func (s *ShardedChunkedStates) UpdateWith(identifier string, resp metav1.ListInt
 	return nil
 }
 
+// PinTo pins the shards to the resource versions of a former list, such that they are
+// listed at exactly that point in time. Shards without a resource version are listed at
+// the latest version.
+func (s *ShardedChunkedStates) PinTo(versions *ShardedResourceVersions) {
+	for i := range s.ResourceVersions {
+		for _, version := range versions.ResourceVersions {
+			if version.Identifier == s.ResourceVersions[i].Identifier {
+				s.ResourceVersions[i].Snapshot = version.ResourceVersion
+			}
+		}
+	}
+}
+
+// SnapshotFor returns the resource version the shard is pinned to, or 0 if it is not
+func (s *ShardedChunkedStates) SnapshotFor(identifier string) int64 {
+	for _, shard := range s.ResourceVersions {
+		if shard.Identifier == identifier {
+			return shard.Snapshot
+		}
+	}
+	return 0
+}
+
 // NextQuery determines the shard identifier and query parameters we should use for the next query
 func (s *ShardedChunkedStates) NextQuery() (string, string, error) {
 	index := -1
@@ -153,6 +176,9 @@ type ShardedChunkedState struct {
 	ResourceVersion int64 `json:"rv,omitempty"`
 	// StartKey is set when we are chunking from this shard
 	StartKey string `json:"start,omitempty"`
+	// Snapshot is the resource version the first chunk of this shard is listed at, if
+	// the client asked for an exact snapshot
+	Snapshot int64 `json:"snapshot,omitempty"`
 }
 
 // Pending determines if this shard has not been chunked yet
@@ -286,9 +312,11 @@ func NewChunkedState(encodedContinueTokens string, identifiers []string, shardRe
 }
 
 // NewResourceVersionState parses state from a user query or initializes it if the client did not
-// request anything specific.
+// request anything specific. A resource version of "0" means any version, like for a single shard.
+// Versions resolved against another set of shards are reconciled with the current shards: new shards
+// start at any version, and shards that are gone are dropped.
 func NewResourceVersionState(encodedResourceVersion string, identifiers []string, shardResourceVersion int64) (*ShardedResourceVersions, error) {
-	if encodedResourceVersion == "" {
+	if encodedResourceVersion == "" || encodedResourceVersion == "0" {
 		var shards []ShardedResourceVersion
 		for _, identifier := range identifiers {
 			shards = append(shards, ShardedResourceVersion{Identifier: identifier})
@@ -297,29 +325,29 @@ func NewResourceVersionState(encodedResourceVersion string, identifiers []string
 			ShardResourceVersion: shardResourceVersion,
 			ResourceVersions:     shards,
 		}, nil
-	} else {
-		state := &ShardedResourceVersions{}
-		err := state.Decode(encodedResourceVersion)
-		if err != nil {
-			return nil, err
-		}
-		// TODO: is this really sane to have semi-provided RVs?
-		if state.ShardResourceVersion == 0 {
-			// the client gave us some partial state, we fill it im
-			state.ShardResourceVersion = shardResourceVersion
-			for _, identifier := range identifiers {
-				found := false
-				for _, provided := range state.ResourceVersions {
-					if provided.Identifier == identifier {
-						found = true
-						break
-					}
-				}
-				if !found {
-					state.ResourceVersions = append(state.ResourceVersions, ShardedResourceVersion{Identifier: identifier})
-				}
-			}
-		}
-		return state, err
 	}
+
+	state := &ShardedResourceVersions{}
+	if err := state.Decode(encodedResourceVersion); err != nil {
+		return nil, err
+	}
+	if state.ShardResourceVersion != shardResourceVersion {
+		state.Reconcile(identifiers, shardResourceVersion)
+	}
+	return state, nil
+}
+
+// Reconcile updates the state to the given set of shards, keeping the versions of the
+// shards that are still present.
+func (s *ShardedResourceVersions) Reconcile(identifiers []string, shardResourceVersion int64) {
+	provided := map[string]int64{}
+	for _, shard := range s.ResourceVersions {
+		provided[shard.Identifier] = shard.ResourceVersion
+	}
+	var shards []ShardedResourceVersion
+	for _, identifier := range identifiers {
+		shards = append(shards, ShardedResourceVersion{Identifier: identifier, ResourceVersion: provided[identifier]})
+	}
+	s.ShardResourceVersion = shardResourceVersion
+	s.ResourceVersions = shards
 }
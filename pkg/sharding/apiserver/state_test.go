@@ -143,3 +143,104 @@ func TestShardedChunkedStates_UpdateWith(t *testing.T) {
 		}
 	}
 }
+
+func TestNewResourceVersionState(t *testing.T) {
+	encode := func(state *ShardedResourceVersions, t *testing.T) string {
+		encoded, err := state.Encode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return encoded
+	}
+
+	for _, testCase := range []struct {
+		name            string
+		resourceVersion func(t *testing.T) string
+		expected        *ShardedResourceVersions
+	}{
+		{
+			name:            "no resource version starts all shards at any version",
+			resourceVersion: func(t *testing.T) string { return "" },
+			expected: &ShardedResourceVersions{
+				ShardResourceVersion: 2,
+				ResourceVersions:     []ShardedResourceVersion{{Identifier: "first"}, {Identifier: "second"}},
+			},
+		},
+		{
+			name:            "zero resource version starts all shards at any version",
+			resourceVersion: func(t *testing.T) string { return "0" },
+			expected: &ShardedResourceVersions{
+				ShardResourceVersion: 2,
+				ResourceVersions:     []ShardedResourceVersion{{Identifier: "first"}, {Identifier: "second"}},
+			},
+		},
+		{
+			name: "resource version of the current shards is used as is",
+			resourceVersion: func(t *testing.T) string {
+				return encode(&ShardedResourceVersions{
+					ShardResourceVersion: 2,
+					ResourceVersions:     []ShardedResourceVersion{{Identifier: "second", ResourceVersion: 20}, {Identifier: "first", ResourceVersion: 10}},
+				}, t)
+			},
+			expected: &ShardedResourceVersions{
+				ShardResourceVersion: 2,
+				ResourceVersions:     []ShardedResourceVersion{{Identifier: "second", ResourceVersion: 20}, {Identifier: "first", ResourceVersion: 10}},
+			},
+		},
+		{
+			name: "resource version of former shards is reconciled",
+			resourceVersion: func(t *testing.T) string {
+				return encode(&ShardedResourceVersions{
+					ShardResourceVersion: 1,
+					ResourceVersions:     []ShardedResourceVersion{{Identifier: "first", ResourceVersion: 10}, {Identifier: "gone", ResourceVersion: 30}},
+				}, t)
+			},
+			expected: &ShardedResourceVersions{
+				ShardResourceVersion: 2,
+				ResourceVersions:     []ShardedResourceVersion{{Identifier: "first", ResourceVersion: 10}, {Identifier: "second"}},
+			},
+		},
+	} {
+		state, err := NewResourceVersionState(testCase.resourceVersion(t), []string{"first", "second"}, 2)
+		if err != nil {
+			t.Fatalf("%s: could not parse state: %v", testCase.name, err)
+		}
+		if diff := cmp.Diff(state, testCase.expected); diff != "" {
+			t.Fatalf("%s: got incorrect state: %v", testCase.name, diff)
+		}
+	}
+
+	if _, err := NewResourceVersionState("123", []string{"first"}, 2); err == nil {
+		t.Fatal("expected a plain resource version to be invalid")
+	}
+}
+
+func TestShardedChunkedStates_PinTo(t *testing.T) {
+	state, err := NewChunkedState("", []string{"first", "second"}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.PinTo(&ShardedResourceVersions{
+		ShardResourceVersion: 1,
+		ResourceVersions:     []ShardedResourceVersion{{Identifier: "first", ResourceVersion: 10}},
+	})
+
+	identifier, continueToken, err := state.NextQuery()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if identifier != "first" || continueToken != "" || state.SnapshotFor(identifier) != 10 {
+		t.Fatalf("expected to list the first shard at its snapshot, got %q at %d", identifier, state.SnapshotFor(identifier))
+	}
+	if err := state.UpdateWith(identifier, &metav1.ListMeta{ResourceVersion: "10"}); err != nil {
+		t.Fatal(err)
+	}
+
+	identifier, _, err = state.NextQuery()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if identifier != "second" || state.SnapshotFor(identifier) != 0 {
+		t.Fatalf("expected to list the second shard at the latest version, got %q at %d", identifier, state.SnapshotFor(identifier))
+	}
+}
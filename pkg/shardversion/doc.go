@@ -0,0 +1,26 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package shardversion negotiates what shards of different kcp versions have in common,
+// such that a sharded kcp keeps working while its shards are upgraded one by one.
+//
+// Every shard publishes its version, its enabled features and the versions of the system
+// resources it serves in the status of its ClusterWorkspaceShard. From these, a Negotiation
+// tells which features are enabled on all shards, i.e. can be used across shards, which
+// version of a system resource to store objects in, such that all shards can read them, and
+// which shards serve a group version, such that requests for new APIs are only routed to
+// upgraded shards.
+package shardversion
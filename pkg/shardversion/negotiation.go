@@ -0,0 +1,154 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shardversion
+
+import (
+	"sort"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/version"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+// Negotiation is what a set of shards has in common.
+type Negotiation struct {
+	shards    sets.String
+	published sets.String
+	versions  sets.String
+	features  sets.String
+
+	resources     map[schema.GroupResource]*resource
+	groupVersions map[schema.GroupVersion]sets.String
+}
+
+type resource struct {
+	// common are the versions served by all shards serving the resource.
+	common sets.String
+}
+
+// Negotiate returns what the given shards have in common. Shards that have not published
+// their version info, e.g. because they predate it, are assumed to enable no features.
+func Negotiate(shards []*tenancyv1alpha1.ClusterWorkspaceShard) *Negotiation {
+	n := &Negotiation{
+		shards:        sets.NewString(),
+		published:     sets.NewString(),
+		versions:      sets.NewString(),
+		resources:     map[schema.GroupResource]*resource{},
+		groupVersions: map[schema.GroupVersion]sets.String{},
+	}
+
+	for _, shard := range shards {
+		n.shards.Insert(shard.Name)
+
+		info := shard.Status.VersionInfo
+		if info == nil {
+			n.features = sets.NewString()
+			continue
+		}
+		n.published.Insert(shard.Name)
+		if info.Version != "" {
+			n.versions.Insert(info.Version)
+		}
+
+		if n.features == nil {
+			n.features = sets.NewString(info.Features...)
+		} else {
+			n.features = n.features.Intersection(sets.NewString(info.Features...))
+		}
+
+		for _, r := range info.Resources {
+			gr := schema.GroupResource{Group: r.Group, Resource: r.Resource}
+			if existing, ok := n.resources[gr]; ok {
+				existing.common = existing.common.Intersection(sets.NewString(r.Versions...))
+			} else {
+				n.resources[gr] = &resource{common: sets.NewString(r.Versions...)}
+			}
+
+			for _, v := range r.Versions {
+				gv := schema.GroupVersion{Group: r.Group, Version: v}
+				if _, ok := n.groupVersions[gv]; !ok {
+					n.groupVersions[gv] = sets.NewString()
+				}
+				n.groupVersions[gv].Insert(shard.Name)
+			}
+		}
+	}
+	if n.features == nil {
+		n.features = sets.NewString()
+	}
+
+	return n
+}
+
+// Complete returns whether all shards have published their version info.
+func (n *Negotiation) Complete() bool {
+	return n.published.Len() == n.shards.Len()
+}
+
+// Published returns whether the given shard has published its version info.
+func (n *Negotiation) Published(shard string) bool {
+	return n.published.Has(shard)
+}
+
+// Skewed returns whether the shards run different versions, or it is not known.
+func (n *Negotiation) Skewed() bool {
+	return !n.Complete() || n.versions.Len() > 1
+}
+
+// Versions returns the kcp versions the shards run, sorted.
+func (n *Negotiation) Versions() []string {
+	return n.versions.List()
+}
+
+// FeatureEnabled returns whether the given feature is enabled on all shards.
+func (n *Negotiation) FeatureEnabled(feature string) bool {
+	return n.shards.Len() > 0 && n.features.Has(feature)
+}
+
+// ServedBy returns the names of the shards serving any resource of the given group
+// version.
+func (n *Negotiation) ServedBy(gv schema.GroupVersion) sets.String {
+	if shards, ok := n.groupVersions[gv]; ok {
+		return sets.NewString(shards.UnsortedList()...)
+	}
+	return sets.NewString()
+}
+
+// StorageVersion returns the version to store objects of the given resource in. This is
+// the preferred version if all shards serving the resource can read it, and otherwise the
+// highest version all of them can read. An empty string is returned if that is not known,
+// i.e. the storage version should not be changed.
+func (n *Negotiation) StorageVersion(gr schema.GroupResource, preferred string) string {
+	if !n.Complete() {
+		return ""
+	}
+	r, ok := n.resources[gr]
+	if !ok || r.common.Has(preferred) {
+		return preferred
+	}
+	if r.common.Len() == 0 {
+		return ""
+	}
+
+	common := r.common.List()
+	sort.Slice(common, func(i, j int) bool {
+		return version.CompareKubeAwareVersionStrings(common[i], common[j]) > 0
+	})
+	return common[0]
+}
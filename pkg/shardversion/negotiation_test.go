@@ -0,0 +1,139 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shardversion
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+func shard(name string, info *tenancyv1alpha1.ShardVersionInfo) *tenancyv1alpha1.ClusterWorkspaceShard {
+	return &tenancyv1alpha1.ClusterWorkspaceShard{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status:     tenancyv1alpha1.ClusterWorkspaceShardStatus{VersionInfo: info},
+	}
+}
+
+var widgets = schema.GroupResource{Group: "tenancy.kcp.dev", Resource: "widgets"}
+
+func widgetsIn(storage string, versions ...string) []tenancyv1alpha1.ShardResource {
+	return []tenancyv1alpha1.ShardResource{{Group: widgets.Group, Resource: widgets.Resource, Versions: versions, StorageVersion: storage}}
+}
+
+func TestNegotiate(t *testing.T) {
+	old := shard("old", &tenancyv1alpha1.ShardVersionInfo{
+		Version:   "v0.5.0",
+		Features:  []string{"A"},
+		Resources: widgetsIn("v1alpha1", "v1alpha1"),
+	})
+	upgrading := shard("upgrading", &tenancyv1alpha1.ShardVersionInfo{
+		Version:   "v0.6.0",
+		Features:  []string{"A", "B"},
+		Resources: widgetsIn("v1alpha1", "v1alpha1", "v1beta1"),
+	})
+	upgraded := shard("upgraded", &tenancyv1alpha1.ShardVersionInfo{
+		Version:   "v0.6.0",
+		Features:  []string{"A", "B"},
+		Resources: widgetsIn("v1beta1", "v1alpha1", "v1beta1"),
+	})
+	unpublished := shard("unpublished", nil)
+
+	tests := []struct {
+		name   string
+		shards []*tenancyv1alpha1.ClusterWorkspaceShard
+
+		wantComplete       bool
+		wantSkewed         bool
+		wantVersions       []string
+		wantFeatures       map[string]bool
+		wantStorageVersion string
+		wantServedBy       []string
+	}{
+		{
+			name:               "no shards",
+			wantComplete:       true,
+			wantFeatures:       map[string]bool{"A": false},
+			wantVersions:       []string{},
+			wantStorageVersion: "v1beta1",
+			wantServedBy:       []string{},
+		},
+		{
+			name:               "mixed versions",
+			shards:             []*tenancyv1alpha1.ClusterWorkspaceShard{old, upgraded},
+			wantComplete:       true,
+			wantSkewed:         true,
+			wantVersions:       []string{"v0.5.0", "v0.6.0"},
+			wantFeatures:       map[string]bool{"A": true, "B": false},
+			wantStorageVersion: "v1alpha1",
+			wantServedBy:       []string{"upgraded"},
+		},
+		{
+			name:               "all upgraded",
+			shards:             []*tenancyv1alpha1.ClusterWorkspaceShard{upgrading, upgraded},
+			wantComplete:       true,
+			wantVersions:       []string{"v0.6.0"},
+			wantFeatures:       map[string]bool{"A": true, "B": true},
+			wantStorageVersion: "v1beta1",
+			wantServedBy:       []string{"upgraded", "upgrading"},
+		},
+		{
+			name:               "unpublished shard",
+			shards:             []*tenancyv1alpha1.ClusterWorkspaceShard{upgraded, unpublished},
+			wantSkewed:         true,
+			wantVersions:       []string{"v0.6.0"},
+			wantFeatures:       map[string]bool{"A": false, "B": false},
+			wantStorageVersion: "",
+			wantServedBy:       []string{"upgraded"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := Negotiate(tt.shards)
+			require.Equal(t, tt.wantComplete, n.Complete(), "complete")
+			require.Equal(t, tt.wantSkewed, n.Skewed(), "skewed")
+			require.Equal(t, tt.wantVersions, n.Versions())
+			for feature, want := range tt.wantFeatures {
+				require.Equal(t, want, n.FeatureEnabled(feature), "feature %s", feature)
+			}
+			require.Equal(t, tt.wantStorageVersion, n.StorageVersion(widgets, "v1beta1"))
+			require.Equal(t, tt.wantServedBy, n.ServedBy(schema.GroupVersion{Group: widgets.Group, Version: "v1beta1"}).List())
+		})
+	}
+}
+
+func TestStorageVersionFallback(t *testing.T) {
+	n := Negotiate([]*tenancyv1alpha1.ClusterWorkspaceShard{
+		shard("a", &tenancyv1alpha1.ShardVersionInfo{Resources: widgetsIn("v1beta1", "v1alpha1", "v1beta1", "v1")}),
+		shard("b", &tenancyv1alpha1.ShardVersionInfo{Resources: widgetsIn("v1beta1", "v1alpha1", "v1beta1")}),
+	})
+	require.Equal(t, "v1beta1", n.StorageVersion(widgets, "v1"), "the highest version all shards read")
+
+	n = Negotiate([]*tenancyv1alpha1.ClusterWorkspaceShard{
+		shard("a", &tenancyv1alpha1.ShardVersionInfo{Resources: widgetsIn("v2", "v2")}),
+		shard("b", &tenancyv1alpha1.ShardVersionInfo{Resources: widgetsIn("v1", "v1")}),
+	})
+	require.Equal(t, "", n.StorageVersion(widgets, "v2"), "no version all shards read")
+
+	other := schema.GroupResource{Group: "tenancy.kcp.dev", Resource: "gadgets"}
+	require.Equal(t, "v1", n.StorageVersion(other, "v1"), "resources no shard serves yet are stored in the preferred version")
+}
@@ -0,0 +1,331 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/klog/v2"
+)
+
+const (
+	opRecord = "record"
+	opRemove = "remove"
+
+	// minCompactionRecords is the number of records below which the journal file
+	// is never compacted.
+	minCompactionRecords = 100
+)
+
+// Entry is a downstream object whose status could not be written upstream
+// because kcp was unreachable.
+type Entry struct {
+	Group    string `json:"group,omitempty"`
+	Version  string `json:"version"`
+	Resource string `json:"resource"`
+	// Key is the meta namespace key of the downstream object.
+	Key string `json:"key"`
+	// Recorded is when the object was journaled first. Later failures of the
+	// same object do not change it.
+	Recorded time.Time `json:"recorded"`
+}
+
+// GVR returns the resource of the entry.
+func (e Entry) GVR() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: e.Group, Version: e.Version, Resource: e.Resource}
+}
+
+type entryKey struct {
+	gvr schema.GroupVersionResource
+	key string
+}
+
+type record struct {
+	Op    string `json:"op"`
+	Entry Entry  `json:"entry"`
+}
+
+// Journal keeps the entries pending upsync, persisted as an append-only file of
+// records such that they survive restarts of the syncer. Every object is journaled
+// at most once, i.e. the latest downstream state of an object is upsynced when it
+// is replayed. The zero path keeps the journal in memory only.
+type Journal struct {
+	path string
+	now  func() time.Time
+
+	lock    sync.Mutex
+	file    *os.File
+	entries map[entryKey]Entry
+	// records is the number of records in the file.
+	records int
+}
+
+// Open loads the journal at the given path, creating it if it does not exist. The
+// file is compacted on open.
+func Open(path string) (*Journal, error) {
+	j := &Journal{
+		path:    path,
+		now:     time.Now,
+		entries: map[entryKey]Entry{},
+	}
+	if path == "" {
+		return j, nil
+	}
+
+	if err := j.load(); err != nil {
+		return nil, err
+	}
+	if err := j.compact(); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+func (j *Journal) load() error {
+	f, err := os.Open(j.path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var r record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			// a torn write of a crashed syncer. Any object it was about is
+			// enqueued anyway when the informers start.
+			klog.Warningf("Skipping invalid record in line %d of syncer journal %s: %v", line, j.path, err)
+			continue
+		}
+		j.apply(r)
+	}
+	return scanner.Err()
+}
+
+func (j *Journal) apply(r record) {
+	k := entryKey{gvr: r.Entry.GVR(), key: r.Entry.Key}
+	switch r.Op {
+	case opRecord:
+		if _, found := j.entries[k]; !found {
+			j.entries[k] = r.Entry
+		}
+	case opRemove:
+		delete(j.entries, k)
+	}
+}
+
+// compact rewrites the file with the current entries only. It must be called
+// with the lock held.
+func (j *Journal) compact() error {
+	if j.file != nil {
+		if err := j.file.Close(); err != nil {
+			return err
+		}
+		j.file = nil
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(j.path), ".tmp-"+filepath.Base(j.path))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // nolint:errcheck
+
+	w := bufio.NewWriter(tmp)
+	enc := json.NewEncoder(w)
+	for _, e := range j.sortedEntries() {
+		if err := enc.Encode(record{Op: opRecord, Entry: e}); err != nil {
+			tmp.Close() // nolint:errcheck
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close() // nolint:errcheck
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close() // nolint:errcheck
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), j.path); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(j.path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	j.file = f
+	j.records = len(j.entries)
+	return nil
+}
+
+// append persists a record. It must be called with the lock held.
+func (j *Journal) append(r record) error {
+	if j.file == nil {
+		return nil
+	}
+
+	bs, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	if _, err := j.file.Write(append(bs, '\n')); err != nil {
+		return err
+	}
+	if err := j.file.Sync(); err != nil {
+		return err
+	}
+	j.records++
+
+	if j.records > minCompactionRecords && j.records > 2*len(j.entries) {
+		return j.compact()
+	}
+	return nil
+}
+
+// Record journals the given downstream object. It is a noop if the object is
+// journaled already.
+func (j *Journal) Record(gvr schema.GroupVersionResource, key string) error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	k := entryKey{gvr: gvr, key: key}
+	if _, found := j.entries[k]; found {
+		return nil
+	}
+
+	e := Entry{Group: gvr.Group, Version: gvr.Version, Resource: gvr.Resource, Key: key, Recorded: j.now()}
+	if err := j.append(record{Op: opRecord, Entry: e}); err != nil {
+		return fmt.Errorf("failed to journal %s %s: %w", gvr, key, err)
+	}
+	j.entries[k] = e
+	return nil
+}
+
+// Remove removes the given downstream object from the journal. It is a noop if
+// the object is not journaled.
+func (j *Journal) Remove(gvr schema.GroupVersionResource, key string) error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	k := entryKey{gvr: gvr, key: key}
+	e, found := j.entries[k]
+	if !found {
+		return nil
+	}
+
+	delete(j.entries, k)
+	if err := j.append(record{Op: opRemove, Entry: Entry{Group: e.Group, Version: e.Version, Resource: e.Resource, Key: e.Key}}); err != nil {
+		return fmt.Errorf("failed to remove %s %s from journal: %w", gvr, key, err)
+	}
+	return nil
+}
+
+// Get returns the entry of the given downstream object, if it is journaled.
+func (j *Journal) Get(gvr schema.GroupVersionResource, key string) (Entry, bool) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	e, found := j.entries[entryKey{gvr: gvr, key: key}]
+	return e, found
+}
+
+// Entries returns the journaled entries, the oldest first.
+func (j *Journal) Entries() []Entry {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	return j.sortedEntries()
+}
+
+// Len returns the number of journaled entries.
+func (j *Journal) Len() int {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	return len(j.entries)
+}
+
+func (j *Journal) sortedEntries() []Entry {
+	entries := make([]Entry, 0, len(j.entries))
+	for _, e := range j.entries {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, k int) bool {
+		if !entries[i].Recorded.Equal(entries[k].Recorded) {
+			return entries[i].Recorded.Before(entries[k].Recorded)
+		}
+		if entries[i].GVR() != entries[k].GVR() {
+			return entries[i].GVR().String() < entries[k].GVR().String()
+		}
+		return entries[i].Key < entries[k].Key
+	})
+	return entries
+}
+
+// Close closes the journal file. The entries are kept on disk.
+func (j *Journal) Close() error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	if j.file == nil {
+		return nil
+	}
+	err := j.file.Close()
+	j.file = nil
+	return err
+}
+
+// IsConnectionError returns whether the given error means that kcp is unreachable,
+// as opposed to kcp rejecting a request.
+func IsConnectionError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if apierrors.IsServerTimeout(err) || apierrors.IsTimeout(err) || apierrors.IsServiceUnavailable(err) {
+		return true
+	}
+	if utilnet.IsConnectionRefused(err) || utilnet.IsConnectionReset(err) || utilnet.IsProbableEOF(err) {
+		return true
+	}
+	// IsProbableEOF only matches unwrapped EOFs.
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package journal
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	deployments = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	services    = schema.GroupVersionResource{Version: "v1", Resource: "services"}
+)
+
+func openAt(t *testing.T, path string, start time.Time) *Journal {
+	j, err := Open(path)
+	require.NoError(t, err)
+	now := start
+	j.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+	return j
+}
+
+func keys(entries []Entry) []string {
+	var ks []string
+	for _, e := range entries {
+		ks = append(ks, e.GVR().Resource+" "+e.Key)
+	}
+	return ks
+}
+
+func TestJournal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "syncer.journal")
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	j := openAt(t, path, start)
+	require.NoError(t, j.Record(deployments, "ns/a"))
+	require.NoError(t, j.Record(services, "ns/b"))
+	require.NoError(t, j.Record(deployments, "ns/c"))
+	require.NoError(t, j.Record(deployments, "ns/a"), "journaling twice is a noop")
+	require.NoError(t, j.Remove(deployments, "ns/c"))
+	require.NoError(t, j.Remove(deployments, "ns/unknown"))
+	require.Equal(t, []string{"deployments ns/a", "services ns/b"}, keys(j.Entries()))
+
+	e, found := j.Get(deployments, "ns/a")
+	require.True(t, found)
+	require.Equal(t, start.Add(time.Second), e.Recorded, "the first failure is kept")
+	_, found = j.Get(deployments, "ns/c")
+	require.False(t, found)
+
+	require.NoError(t, j.Close())
+
+	// a torn write at the end of the file is skipped.
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"op":"record","entry":{"vers`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	j = openAt(t, path, start.Add(time.Hour))
+	defer j.Close()
+	require.Equal(t, []string{"deployments ns/a", "services ns/b"}, keys(j.Entries()))
+	e, found = j.Get(deployments, "ns/a")
+	require.True(t, found)
+	require.True(t, start.Add(time.Second).Equal(e.Recorded))
+
+	bs, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, 2, strings.Count(string(bs), "\n"), "the journal is compacted on open")
+}
+
+func TestJournalCompaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "syncer.journal")
+	j := openAt(t, path, time.Now())
+	defer j.Close()
+
+	require.NoError(t, j.Record(services, "ns/kept"))
+	for i := 0; i < 200; i++ {
+		key := fmt.Sprintf("ns/%d", i)
+		require.NoError(t, j.Record(deployments, key))
+		require.NoError(t, j.Remove(deployments, key))
+	}
+
+	bs, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.LessOrEqual(t, strings.Count(string(bs), "\n"), minCompactionRecords+1)
+
+	j2, err := Open(path)
+	require.NoError(t, err)
+	defer j2.Close()
+	require.Equal(t, []string{"services ns/kept"}, keys(j2.Entries()))
+}
+
+func TestInMemoryJournal(t *testing.T) {
+	j, err := Open("")
+	require.NoError(t, err)
+	require.NoError(t, j.Record(deployments, "ns/a"))
+	require.Equal(t, 1, j.Len())
+	require.NoError(t, j.Remove(deployments, "ns/a"))
+	require.Equal(t, 0, j.Len())
+	require.NoError(t, j.Close())
+}
+
+func TestIsConnectionError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil"},
+		{name: "not found", err: apierrors.NewNotFound(deployments.GroupResource(), "a")},
+		{name: "conflict", err: apierrors.NewConflict(deployments.GroupResource(), "a", errors.New("changed"))},
+		{name: "forbidden", err: apierrors.NewForbidden(deployments.GroupResource(), "a", errors.New("denied"))},
+		{name: "service unavailable", err: apierrors.NewServiceUnavailable("shutting down"), want: true},
+		{name: "server timeout", err: apierrors.NewServerTimeout(deployments.GroupResource(), "get", 1), want: true},
+		{name: "dial error", err: fmt.Errorf("get failed: %w", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("no route to host")}), want: true},
+		{name: "eof", err: io.EOF, want: true},
+		{name: "wrapped unexpected eof", err: fmt.Errorf("watch failed: %w", io.ErrUnexpectedEOF), want: true},
+		{name: "url error", err: &url.Error{Op: "Get", URL: "https://kcp", Err: io.ErrUnexpectedEOF}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, IsConnectionError(tt.err))
+		})
+	}
+}
@@ -34,6 +34,10 @@ const (
 	// SyncerFinalizerNamePrefix is the finalizer put onto resources by the syncer to claim ownership,
 	// *before* a downstream object is created. It is only removed when the downstream object is deleted.
 	SyncerFinalizerNamePrefix = "workloads.kcp.dev/syncer-"
+
+	// SyncerAuthResourcePrefix is the prefix of the names of the ServiceAccount and RBAC
+	// resources in kcp a syncer authenticates and is authorized with.
+	SyncerAuthResourcePrefix = "syncer-"
 )
 
 func EnsureUpstreamFinalizerRemoved(ctx context.Context, gvr schema.GroupVersionResource, upstreamClient dynamic.ClusterInterface, upstreamNamespace, workloadClusterName string, logicalClusterName logicalcluster.Name, resourceName string) error {
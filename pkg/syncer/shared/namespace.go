@@ -17,11 +17,18 @@ limitations under the License.
 package shared
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"text/template"
 
 	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
 )
 
 const (
@@ -57,3 +64,98 @@ func PhysicalClusterNamespaceName(l NamespaceLocator) (string, error) {
 	hash := sha256.Sum224(b)
 	return fmt.Sprintf("kcp%x", hash), nil
 }
+
+// NamespaceNamer maps NamespaceLocators to namespace names on a physical cluster,
+// following the NamespaceNaming of a WorkloadCluster.
+type NamespaceNamer struct {
+	strategy workloadv1alpha1.NamespaceNamingStrategy
+	prefix   string
+	template *template.Template
+}
+
+// NewNamespaceNamer returns a NamespaceNamer for the given naming. A nil naming
+// names namespaces by hash.
+func NewNamespaceNamer(naming *workloadv1alpha1.NamespaceNaming) (*NamespaceNamer, error) {
+	n := &NamespaceNamer{strategy: workloadv1alpha1.NamespaceNamingHash, prefix: "kcp"}
+	if naming == nil {
+		return n, nil
+	}
+	if naming.Strategy != "" {
+		n.strategy = naming.Strategy
+	}
+	if naming.Prefix != "" {
+		n.prefix = naming.Prefix
+	}
+
+	switch n.strategy {
+	case workloadv1alpha1.NamespaceNamingHash, workloadv1alpha1.NamespaceNamingReadable, workloadv1alpha1.NamespaceNamingVerbatim:
+	case workloadv1alpha1.NamespaceNamingTemplate:
+		if naming.Template == "" {
+			return nil, fmt.Errorf("namespace naming strategy %q requires a template", n.strategy)
+		}
+		tmpl, err := template.New("namespace").Option("missingkey=error").Parse(naming.Template)
+		if err != nil {
+			return nil, fmt.Errorf("invalid namespace naming template: %w", err)
+		}
+		n.template = tmpl
+	default:
+		return nil, fmt.Errorf("unknown namespace naming strategy %q", n.strategy)
+	}
+	return n, nil
+}
+
+// MayConflict returns whether different NamespaceLocators can be mapped to the same
+// namespace name, i.e. whether the owner of existing namespaces must be checked.
+func (n *NamespaceNamer) MayConflict() bool {
+	return n.strategy != workloadv1alpha1.NamespaceNamingHash
+}
+
+// Name returns the namespace name on the physical cluster for the given NamespaceLocator.
+func (n *NamespaceNamer) Name(l NamespaceLocator) (string, error) {
+	var name string
+	switch n.strategy {
+	case workloadv1alpha1.NamespaceNamingHash:
+		return PhysicalClusterNamespaceName(l)
+	case workloadv1alpha1.NamespaceNamingVerbatim:
+		name = l.Namespace
+	case workloadv1alpha1.NamespaceNamingReadable:
+		name = fmt.Sprintf("%s-%s-%s", n.prefix, strings.ReplaceAll(l.LogicalCluster.String(), ":", "-"), l.Namespace)
+		if len(name) > validation.DNS1123LabelMaxLength {
+			hash, err := shortHash(l)
+			if err != nil {
+				return "", err
+			}
+			name = strings.TrimRight(name[:validation.DNS1123LabelMaxLength-len(hash)-1], "-") + "-" + hash
+		}
+	case workloadv1alpha1.NamespaceNamingTemplate:
+		hash, err := shortHash(l)
+		if err != nil {
+			return "", err
+		}
+		_, workspaceName := l.LogicalCluster.Split()
+		var buf bytes.Buffer
+		if err := n.template.Execute(&buf, map[string]string{
+			"Workspace":     l.LogicalCluster.String(),
+			"WorkspaceName": workspaceName,
+			"Namespace":     l.Namespace,
+			"Hash":          hash,
+		}); err != nil {
+			return "", fmt.Errorf("failed to execute namespace naming template: %w", err)
+		}
+		name = buf.String()
+	}
+
+	if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+		return "", fmt.Errorf("invalid namespace name %q for %s|%s: %s", name, l.LogicalCluster, l.Namespace, strings.Join(errs, ", "))
+	}
+	return name, nil
+}
+
+// shortHash returns a short, repeatable hash of the NamespaceLocator.
+func shortHash(l NamespaceLocator) (string, error) {
+	name, err := PhysicalClusterNamespaceName(l)
+	if err != nil {
+		return "", err
+	}
+	return name[len("kcp") : len("kcp")+10], nil
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shared
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+)
+
+func TestNamespaceNamer(t *testing.T) {
+	locator := NamespaceLocator{LogicalCluster: logicalcluster.New("root:org:ws"), Namespace: "test"}
+	long := NamespaceLocator{LogicalCluster: logicalcluster.New("root:" + strings.Repeat("organization", 4) + ":ws"), Namespace: "test"}
+
+	tests := map[string]struct {
+		naming       *workloadv1alpha1.NamespaceNaming
+		locator      NamespaceLocator
+		want         string
+		wantErr      bool
+		wantConflict bool
+	}{
+		"hash by default": {
+			locator: locator,
+			want:    "kcp0124d7647eb6a00b1fcb6f2252201601634989dd79deb7375c373973",
+		},
+		"readable": {
+			naming:       &workloadv1alpha1.NamespaceNaming{Strategy: workloadv1alpha1.NamespaceNamingReadable},
+			locator:      locator,
+			want:         "kcp-root-org-ws-test",
+			wantConflict: true,
+		},
+		"readable with prefix": {
+			naming:       &workloadv1alpha1.NamespaceNaming{Strategy: workloadv1alpha1.NamespaceNamingReadable, Prefix: "tenant"},
+			locator:      locator,
+			want:         "tenant-root-org-ws-test",
+			wantConflict: true,
+		},
+		"readable too long is truncated with hash": {
+			naming:       &workloadv1alpha1.NamespaceNaming{Strategy: workloadv1alpha1.NamespaceNamingReadable},
+			locator:      long,
+			want:         "kcp-root-organizationorganizationorganizationorganiz-9719df67e1",
+			wantConflict: true,
+		},
+		"template": {
+			naming:       &workloadv1alpha1.NamespaceNaming{Strategy: workloadv1alpha1.NamespaceNamingTemplate, Template: "{{ .WorkspaceName }}-{{ .Namespace }}-{{ .Hash }}"},
+			locator:      locator,
+			want:         "ws-test-0124d7647e",
+			wantConflict: true,
+		},
+		"template with invalid result": {
+			naming:       &workloadv1alpha1.NamespaceNaming{Strategy: workloadv1alpha1.NamespaceNamingTemplate, Template: "{{ .Workspace }}-{{ .Namespace }}"},
+			locator:      locator,
+			wantErr:      true,
+			wantConflict: true,
+		},
+		"verbatim": {
+			naming:       &workloadv1alpha1.NamespaceNaming{Strategy: workloadv1alpha1.NamespaceNamingVerbatim},
+			locator:      locator,
+			want:         "test",
+			wantConflict: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			n, err := NewNamespaceNamer(tc.naming)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantConflict, n.MayConflict())
+
+			got, err := n.Name(tc.locator)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestNewNamespaceNamerErrors(t *testing.T) {
+	_, err := NewNamespaceNamer(&workloadv1alpha1.NamespaceNaming{Strategy: workloadv1alpha1.NamespaceNamingTemplate})
+	require.Error(t, err, "template strategy without template")
+
+	_, err = NewNamespaceNamer(&workloadv1alpha1.NamespaceNaming{Strategy: workloadv1alpha1.NamespaceNamingTemplate, Template: "{{ .Namespace"})
+	require.Error(t, err, "unparseable template")
+
+	_, err = NewNamespaceNamer(&workloadv1alpha1.NamespaceNaming{Strategy: "Random"})
+	require.Error(t, err, "unknown strategy")
+}
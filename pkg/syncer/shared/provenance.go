@@ -0,0 +1,78 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shared
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+)
+
+// ProvenanceSource identifies the upstream resource a downstream resource was synced from.
+type ProvenanceSource struct {
+	LogicalCluster logicalcluster.Name `json:"logical-cluster"`
+	Namespace      string              `json:"namespace,omitempty"`
+	Name           string              `json:"name"`
+	UID            string              `json:"uid"`
+	Generation     int64               `json:"generation,omitempty"`
+}
+
+// HasProvenance returns whether the annotations reference supply-chain metadata, i.e. contain
+// provenance annotations other than the source annotation.
+func HasProvenance(annotations map[string]string) bool {
+	for key := range annotations {
+		if strings.HasPrefix(key, workloadv1alpha1.ProvenanceAnnotationPrefix) && key != workloadv1alpha1.ProvenanceSourceAnnotation {
+			return true
+		}
+	}
+	return false
+}
+
+// SetProvenanceSource sets the provenance source annotation of the downstream object to the
+// upstream object if the latter has provenance annotations, and removes it otherwise.
+func SetProvenanceSource(downstreamObj, upstreamObj metav1.Object) error {
+	annotations := downstreamObj.GetAnnotations()
+	if !HasProvenance(upstreamObj.GetAnnotations()) {
+		if _, ok := annotations[workloadv1alpha1.ProvenanceSourceAnnotation]; ok {
+			delete(annotations, workloadv1alpha1.ProvenanceSourceAnnotation)
+			downstreamObj.SetAnnotations(annotations)
+		}
+		return nil
+	}
+
+	bs, err := json.Marshal(ProvenanceSource{
+		LogicalCluster: logicalcluster.From(upstreamObj),
+		Namespace:      upstreamObj.GetNamespace(),
+		Name:           upstreamObj.GetName(),
+		UID:            string(upstreamObj.GetUID()),
+		Generation:     upstreamObj.GetGeneration(),
+	})
+	if err != nil {
+		return err
+	}
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[workloadv1alpha1.ProvenanceSourceAnnotation] = string(bs)
+	downstreamObj.SetAnnotations(annotations)
+	return nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shared
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestSetProvenanceSource(t *testing.T) {
+	tests := []struct {
+		name            string
+		upstream        map[string]string
+		downstream      map[string]string
+		wantAnnotations map[string]string
+	}{
+		{
+			name:       "without provenance nothing is set",
+			upstream:   map[string]string{"a": "b"},
+			downstream: map[string]string{"a": "b"},
+			wantAnnotations: map[string]string{
+				"a": "b",
+			},
+		},
+		{
+			name:       "source is set with provenance",
+			upstream:   map[string]string{"provenance.workloads.kcp.dev/sbom": "https://sbom.example.com/app"},
+			downstream: map[string]string{"provenance.workloads.kcp.dev/sbom": "https://sbom.example.com/app"},
+			wantAnnotations: map[string]string{
+				"provenance.workloads.kcp.dev/sbom":   "https://sbom.example.com/app",
+				"provenance.workloads.kcp.dev/source": `{"logical-cluster":"root:org:ws","namespace":"ns","name":"app","uid":"uid","generation":3}`,
+			},
+		},
+		{
+			name:       "source copied from upstream is overridden",
+			upstream:   map[string]string{"provenance.workloads.kcp.dev/sbom": "x", "provenance.workloads.kcp.dev/source": "spoofed"},
+			downstream: map[string]string{"provenance.workloads.kcp.dev/sbom": "x", "provenance.workloads.kcp.dev/source": "spoofed"},
+			wantAnnotations: map[string]string{
+				"provenance.workloads.kcp.dev/sbom":   "x",
+				"provenance.workloads.kcp.dev/source": `{"logical-cluster":"root:org:ws","namespace":"ns","name":"app","uid":"uid","generation":3}`,
+			},
+		},
+		{
+			name:            "source copied from upstream without provenance is removed",
+			upstream:        map[string]string{"provenance.workloads.kcp.dev/source": "spoofed"},
+			downstream:      map[string]string{"provenance.workloads.kcp.dev/source": "spoofed"},
+			wantAnnotations: map[string]string{},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			upstream := &unstructured.Unstructured{}
+			upstream.SetClusterName("root:org:ws")
+			upstream.SetNamespace("ns")
+			upstream.SetName("app")
+			upstream.SetUID("uid")
+			upstream.SetGeneration(3)
+			upstream.SetAnnotations(tc.upstream)
+			downstream := &metav1.ObjectMeta{Annotations: tc.downstream}
+
+			err := SetProvenanceSource(downstream, upstream)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantAnnotations, downstream.Annotations)
+		})
+	}
+}
@@ -0,0 +1,238 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+)
+
+// applyManager is the field manager of the spec syncer. The fake client does not
+// pass the patch options to reactors.
+const applyManager = "syncer"
+
+var namespacesGVR = schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+
+// Cluster is an in-memory workload cluster. It accepts the resources a syncer
+// syncs to it and fabricates plausible status for the workloads among them, e.g.
+// deployments become Available with all their replicas ready right away. Nothing
+// is actually run.
+type Cluster struct {
+	client *dynamicfake.FakeDynamicClient
+	now    func() time.Time
+}
+
+// NewCluster returns an empty Cluster serving the given resources. Their kinds are
+// looked up with the discovery client, e.g. of the kcp workspace they are synced from.
+func NewCluster(discoveryClient discovery.DiscoveryInterface, gvrs []schema.GroupVersionResource) (*Cluster, error) {
+	listKinds, err := listKinds(discoveryClient, gvrs)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Cluster{
+		client: dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds),
+		now:    time.Now,
+	}
+	c.client.PrependReactor("create", "*", c.create)
+	c.client.PrependReactor("update", "*", c.update)
+	c.client.PrependReactor("patch", "*", c.apply)
+
+	return c, nil
+}
+
+// DynamicClient returns a client of the cluster.
+func (c *Cluster) DynamicClient() dynamic.Interface {
+	return c.client
+}
+
+func listKinds(discoveryClient discovery.DiscoveryInterface, gvrs []schema.GroupVersionResource) (map[schema.GroupVersionResource]string, error) {
+	listKinds := map[schema.GroupVersionResource]string{
+		namespacesGVR: "NamespaceList",
+	}
+	resourceLists := map[schema.GroupVersion]*metav1.APIResourceList{}
+	for _, gvr := range gvrs {
+		if _, ok := listKinds[gvr]; ok {
+			continue
+		}
+		resourceList, ok := resourceLists[gvr.GroupVersion()]
+		if !ok {
+			var err error
+			resourceList, err = discoveryClient.ServerResourcesForGroupVersion(gvr.GroupVersion().String())
+			if err != nil {
+				return nil, fmt.Errorf("failed to discover the kind of %s: %w", gvr, err)
+			}
+			resourceLists[gvr.GroupVersion()] = resourceList
+		}
+		for _, resource := range resourceList.APIResources {
+			if resource.Name == gvr.Resource {
+				listKinds[gvr] = resource.Kind + "List"
+				break
+			}
+		}
+		if _, ok := listKinds[gvr]; !ok {
+			return nil, fmt.Errorf("failed to discover the kind of %s", gvr)
+		}
+	}
+	return listKinds, nil
+}
+
+// create fabricates the metadata and status of created objects. The default reaction
+// stores the mutated object.
+func (c *Cluster) create(action clienttesting.Action) (bool, runtime.Object, error) {
+	create := action.(clienttesting.CreateAction)
+	obj, ok := create.GetObject().(*unstructured.Unstructured)
+	if !ok || create.GetSubresource() != "" {
+		return false, nil, nil
+	}
+	return false, nil, c.admit(action.GetResource(), obj, nil)
+}
+
+// update fabricates the metadata and status of updated objects. The default reaction
+// stores the mutated object.
+func (c *Cluster) update(action clienttesting.Action) (bool, runtime.Object, error) {
+	update := action.(clienttesting.UpdateAction)
+	obj, ok := update.GetObject().(*unstructured.Unstructured)
+	if !ok || update.GetSubresource() != "" {
+		return false, nil, nil
+	}
+	existing, err := c.get(action.GetResource(), action.GetNamespace(), obj.GetName())
+	if err != nil {
+		return true, nil, err
+	}
+	return false, nil, c.admit(action.GetResource(), obj, existing)
+}
+
+// apply serves server-side apply patches, which the fake client does not support.
+// The applied object replaces the existing one but for the metadata and status
+// owned by the cluster, and the applied fields are recorded as managed fields of
+// the spec syncer, such that it skips applying unchanged objects.
+func (c *Cluster) apply(action clienttesting.Action) (bool, runtime.Object, error) {
+	patch := action.(clienttesting.PatchAction)
+	if patch.GetPatchType() != types.ApplyPatchType || patch.GetSubresource() != "" {
+		return false, nil, nil
+	}
+	gvr := action.GetResource()
+
+	obj := &unstructured.Unstructured{}
+	if err := json.Unmarshal(patch.GetPatch(), &obj.Object); err != nil {
+		return true, nil, apierrors.NewBadRequest(fmt.Sprintf("invalid apply patch: %v", err))
+	}
+	if obj.GetName() == "" {
+		obj.SetName(patch.GetName())
+	}
+	if obj.GetNamespace() == "" {
+		obj.SetNamespace(patch.GetNamespace())
+	}
+	delete(obj.Object, "status")
+
+	value, err := typed.DeducedParseableType.FromUnstructured(obj.Object)
+	if err != nil {
+		return true, nil, apierrors.NewBadRequest(err.Error())
+	}
+	fields, err := value.ToFieldSet()
+	if err != nil {
+		return true, nil, apierrors.NewBadRequest(err.Error())
+	}
+	raw, err := fields.ToJSON()
+	if err != nil {
+		return true, nil, err
+	}
+	obj.SetManagedFields([]metav1.ManagedFieldsEntry{{
+		Manager:    applyManager,
+		Operation:  metav1.ManagedFieldsOperationApply,
+		APIVersion: obj.GetAPIVersion(),
+		FieldsType: "FieldsV1",
+		FieldsV1:   &metav1.FieldsV1{Raw: raw},
+	}})
+
+	existing, err := c.get(gvr, patch.GetNamespace(), patch.GetName())
+	if err != nil && !apierrors.IsNotFound(err) {
+		return true, nil, err
+	}
+	if err := c.admit(gvr, obj, existing); err != nil {
+		return true, nil, err
+	}
+
+	tracker := c.client.Tracker()
+	if existing == nil {
+		err = tracker.Create(gvr, obj, obj.GetNamespace())
+	} else {
+		err = tracker.Update(gvr, obj, obj.GetNamespace())
+	}
+	if err != nil {
+		return true, nil, err
+	}
+	return true, obj, nil
+}
+
+func (c *Cluster) get(gvr schema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, error) {
+	obj, err := c.client.Tracker().Get(gvr, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	existing, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("unexpected object of type %T", obj)
+	}
+	return existing, nil
+}
+
+// admit sets the metadata owned by the cluster on an object created or replacing
+// existing, and fabricates its status.
+func (c *Cluster) admit(gvr schema.GroupVersionResource, obj, existing *unstructured.Unstructured) error {
+	if existing == nil {
+		obj.SetUID(uuid.NewUUID())
+		obj.SetCreationTimestamp(metav1.NewTime(c.now()))
+		obj.SetGeneration(1)
+	} else {
+		obj.SetUID(existing.GetUID())
+		obj.SetCreationTimestamp(existing.GetCreationTimestamp())
+		obj.SetGeneration(existing.GetGeneration())
+		if !equality.Semantic.DeepEqual(obj.Object["spec"], existing.Object["spec"]) {
+			obj.SetGeneration(existing.GetGeneration() + 1)
+		}
+		if status, ok := existing.Object["status"]; ok {
+			obj.Object["status"] = status
+		}
+	}
+
+	fabricate, ok := statusFabricators[gvr.GroupResource()]
+	if !ok {
+		return nil
+	}
+	if existing != nil && obj.GetGeneration() == existing.GetGeneration() && existing.Object["status"] != nil {
+		// keep the status, and with it the condition timestamps, until the spec changes.
+		return nil
+	}
+	return fabricate(obj, metav1.NewTime(c.now()))
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	discoveryfake "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/utils/pointer"
+)
+
+var deploymentsGVR = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+
+func newCluster(t *testing.T) *Cluster {
+	discoveryClient := &discoveryfake.FakeDiscovery{Fake: &clienttesting.Fake{Resources: []*metav1.APIResourceList{
+		{GroupVersion: "v1", APIResources: []metav1.APIResource{{Name: "configmaps", Kind: "ConfigMap", Namespaced: true}}},
+		{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{{Name: "deployments", Kind: "Deployment", Namespaced: true}}},
+	}}}
+	c, err := NewCluster(discoveryClient, []schema.GroupVersionResource{
+		{Version: "v1", Resource: "configmaps"},
+		deploymentsGVR,
+	})
+	require.NoError(t, err)
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+	return c
+}
+
+func applyDeployment(t *testing.T, c *Cluster, replicas int64) *unstructured.Unstructured {
+	data, err := json.Marshal(map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "web", "namespace": "kcp-abc"},
+		"spec":       map[string]interface{}{"replicas": replicas},
+	})
+	require.NoError(t, err)
+	obj, err := c.DynamicClient().Resource(deploymentsGVR).Namespace("kcp-abc").Patch(context.Background(), "web", types.ApplyPatchType, data, metav1.PatchOptions{FieldManager: applyManager, Force: pointer.Bool(true)})
+	require.NoError(t, err)
+	return obj
+}
+
+func TestCluster(t *testing.T) {
+	c := newCluster(t)
+
+	t.Log("Applied deployments become available right away")
+	obj := applyDeployment(t, c, 3)
+	require.NotEmpty(t, obj.GetUID())
+	require.Equal(t, int64(1), obj.GetGeneration())
+	require.Len(t, obj.GetManagedFields(), 1)
+	require.Equal(t, applyManager, obj.GetManagedFields()[0].Manager)
+
+	stored, err := c.DynamicClient().Resource(deploymentsGVR).Namespace("kcp-abc").Get(context.Background(), "web", metav1.GetOptions{})
+	require.NoError(t, err)
+	available, _, err := unstructured.NestedInt64(stored.Object, "status", "availableReplicas")
+	require.NoError(t, err)
+	require.Equal(t, int64(3), available)
+	conditions, _, err := unstructured.NestedSlice(stored.Object, "status", "conditions")
+	require.NoError(t, err)
+	require.Equal(t, "Available", conditions[0].(map[string]interface{})["type"])
+	require.Equal(t, "True", conditions[0].(map[string]interface{})["status"])
+
+	t.Log("Re-applying the same spec keeps generation and status")
+	c.now = func() time.Time { return time.Date(2022, 6, 1, 13, 0, 0, 0, time.UTC) }
+	obj = applyDeployment(t, c, 3)
+	require.Equal(t, stored.GetUID(), obj.GetUID())
+	require.Equal(t, int64(1), obj.GetGeneration())
+	require.Equal(t, stored.Object["status"], obj.Object["status"])
+
+	t.Log("Changing the spec bumps the generation and refreshes the status")
+	obj = applyDeployment(t, c, 5)
+	require.Equal(t, int64(2), obj.GetGeneration())
+	observed, _, err := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	require.NoError(t, err)
+	require.Equal(t, int64(2), observed)
+	ready, _, err := unstructured.NestedInt64(obj.Object, "status", "readyReplicas")
+	require.NoError(t, err)
+	require.Equal(t, int64(5), ready)
+
+	t.Log("Other resources are accepted without status")
+	ns := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Namespace",
+		"metadata":   map[string]interface{}{"name": "kcp-abc"},
+	}}
+	created, err := c.DynamicClient().Resource(namespacesGVR).Create(context.Background(), ns, metav1.CreateOptions{})
+	require.NoError(t, err)
+	require.NotEmpty(t, created.GetUID())
+	require.Nil(t, created.Object["status"])
+}
+
+func TestNewClusterUnknownResource(t *testing.T) {
+	discoveryClient := &discoveryfake.FakeDiscovery{Fake: &clienttesting.Fake{Resources: []*metav1.APIResourceList{
+		{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{{Name: "deployments", Kind: "Deployment", Namespaced: true}}},
+	}}}
+	_, err := NewCluster(discoveryClient, []schema.GroupVersionResource{{Group: "apps", Version: "v1", Resource: "statefulsets"}})
+	require.Error(t, err)
+}
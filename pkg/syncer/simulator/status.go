@@ -0,0 +1,94 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// statusFabricator sets the status of a workload as if all of its replicas were
+// running and ready.
+type statusFabricator func(obj *unstructured.Unstructured, now metav1.Time) error
+
+var statusFabricators = map[schema.GroupResource]statusFabricator{
+	{Group: "apps", Resource: "deployments"}:  deploymentStatus,
+	{Group: "apps", Resource: "statefulsets"}: statefulSetStatus,
+}
+
+func deploymentStatus(obj *unstructured.Unstructured, now metav1.Time) error {
+	replicas, err := desiredReplicas(obj)
+	if err != nil {
+		return err
+	}
+	return unstructured.SetNestedField(obj.Object, map[string]interface{}{
+		"observedGeneration": obj.GetGeneration(),
+		"replicas":           replicas,
+		"updatedReplicas":    replicas,
+		"readyReplicas":      replicas,
+		"availableReplicas":  replicas,
+		"conditions": []interface{}{
+			condition("Available", "MinimumReplicasAvailable", "Deployment has minimum availability.", now),
+			condition("Progressing", "NewReplicaSetAvailable", fmt.Sprintf("ReplicaSet %q has successfully progressed.", obj.GetName()+"-simulated"), now),
+		},
+	}, "status")
+}
+
+func statefulSetStatus(obj *unstructured.Unstructured, now metav1.Time) error {
+	replicas, err := desiredReplicas(obj)
+	if err != nil {
+		return err
+	}
+	return unstructured.SetNestedField(obj.Object, map[string]interface{}{
+		"observedGeneration": obj.GetGeneration(),
+		"replicas":           replicas,
+		"currentReplicas":    replicas,
+		"updatedReplicas":    replicas,
+		"readyReplicas":      replicas,
+		"availableReplicas":  replicas,
+		"currentRevision":    obj.GetName() + "-simulated",
+		"updateRevision":     obj.GetName() + "-simulated",
+	}, "status")
+}
+
+// desiredReplicas returns the replicas of the spec of a workload, defaulting to one.
+func desiredReplicas(obj *unstructured.Unstructured) (int64, error) {
+	replicas, found, err := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+	if err != nil {
+		return 0, err
+	}
+	if !found {
+		return 1, nil
+	}
+	return replicas, nil
+}
+
+func condition(conditionType, reason, message string, now metav1.Time) map[string]interface{} {
+	timestamp := now.UTC().Format(time.RFC3339)
+	return map[string]interface{}{
+		"type":               conditionType,
+		"status":             "True",
+		"reason":             reason,
+		"message":            message,
+		"lastUpdateTime":     timestamp,
+		"lastTransitionTime": timestamp,
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"bytes"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+)
+
+// stripForApply removes what the syncer must not own downstream from the object to
+// apply: the status and the metadata set by the downstream API server, which are owned
+// by the downstream cluster, and the status annotations, which change with every status
+// update upstream and would cause a write downstream each time.
+func stripForApply(obj *unstructured.Unstructured) {
+	delete(obj.Object, "status")
+	for _, field := range []string{"creationTimestamp", "generation", "selfLink"} {
+		unstructured.RemoveNestedField(obj.Object, "metadata", field)
+	}
+
+	annotations := obj.GetAnnotations()
+	for key := range annotations {
+		if strings.HasPrefix(key, workloadv1alpha1.InternalClusterStatusAnnotationPrefix) {
+			delete(annotations, key)
+		}
+	}
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	obj.SetAnnotations(annotations)
+}
+
+// appliedFieldsUpToDate returns whether applying desired with the given field manager
+// would not change the existing object, i.e. whether the fields the manager owns are
+// exactly those of desired, with the same values. The fields are read from the managed
+// fields of the existing object. Lists are compared as a whole, as the schema of the
+// object is not known.
+func appliedFieldsUpToDate(existing, desired *unstructured.Unstructured, manager string) (bool, error) {
+	var fields *metav1.FieldsV1
+	for _, entry := range existing.GetManagedFields() {
+		if entry.Manager == manager && entry.Operation == metav1.ManagedFieldsOperationApply && entry.Subresource == "" {
+			fields = entry.FieldsV1
+			break
+		}
+	}
+	if fields == nil {
+		return false, nil
+	}
+
+	owned := &fieldpath.Set{}
+	if err := owned.FromJSON(bytes.NewReader(fields.Raw)); err != nil {
+		return false, err
+	}
+	existingValue, err := typed.DeducedParseableType.FromUnstructured(existing.Object)
+	if err != nil {
+		return false, err
+	}
+	desiredValue, err := typed.DeducedParseableType.FromUnstructured(desired.Object)
+	if err != nil {
+		return false, err
+	}
+
+	got, _ := existingValue.ExtractItems(owned).AsValue().Unstructured().(map[string]interface{})
+	want, _ := desiredValue.AsValue().Unstructured().(map[string]interface{})
+	return equality.Semantic.DeepEqual(withoutUntrackedFields(got), withoutUntrackedFields(want)), nil
+}
+
+// withoutUntrackedFields removes the fields which are not tracked in managed fields,
+// and nulls, which mean that a field is not set when applied.
+func withoutUntrackedFields(obj map[string]interface{}) map[string]interface{} {
+	obj = withoutNulls(obj)
+	delete(obj, "apiVersion")
+	delete(obj, "kind")
+	if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+		delete(metadata, "name")
+		delete(metadata, "namespace")
+		if len(metadata) == 0 {
+			delete(obj, "metadata")
+		}
+	}
+	return obj
+}
+
+func withoutNulls(obj map[string]interface{}) map[string]interface{} {
+	ret := make(map[string]interface{}, len(obj))
+	for key, value := range obj {
+		switch value := value.(type) {
+		case nil:
+			continue
+		case map[string]interface{}:
+			ret[key] = withoutNulls(value)
+		default:
+			ret[key] = value
+		}
+	}
+	return ret
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestStripForApply(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":              "foo",
+			"creationTimestamp": "2022-01-01T00:00:00Z",
+			"generation":        int64(3),
+			"annotations": map[string]interface{}{
+				"experimental.status.workloads.kcp.dev/us-west1": "{}",
+				"keep": "me",
+			},
+		},
+		"spec":   map[string]interface{}{"replicas": int64(1)},
+		"status": map[string]interface{}{"replicas": int64(1)},
+	}}
+	stripForApply(obj)
+	require.Equal(t, map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":        "foo",
+			"annotations": map[string]interface{}{"keep": "me"},
+		},
+		"spec": map[string]interface{}{"replicas": int64(1)},
+	}, obj.Object)
+}
+
+func TestAppliedFieldsUpToDate(t *testing.T) {
+	existing := func(manager string, operation metav1.ManagedFieldsOperationType, fields string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]interface{}{
+				"name":      "foo",
+				"namespace": "kcp-abc",
+				"labels": map[string]interface{}{
+					"internal.workloads.kcp.dev/cluster": "us-west1",
+					"added-by":                           "webhook",
+				},
+			},
+			"spec": map[string]interface{}{
+				"replicas":   int64(1),
+				"paused":     true,
+				"containers": []interface{}{map[string]interface{}{"name": "a", "image": "busybox"}},
+			},
+			"status": map[string]interface{}{"replicas": int64(1)},
+		}}
+		obj.SetManagedFields([]metav1.ManagedFieldsEntry{
+			{Manager: "webhook", Operation: metav1.ManagedFieldsOperationUpdate, FieldsType: "FieldsV1", FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:labels":{"f:added-by":{}}}}`)}},
+			{Manager: manager, Operation: operation, FieldsType: "FieldsV1", FieldsV1: &metav1.FieldsV1{Raw: []byte(fields)}},
+		})
+		return obj
+	}
+	desired := func(spec map[string]interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]interface{}{
+				"name":      "foo",
+				"namespace": "kcp-abc",
+				"labels":    map[string]interface{}{"internal.workloads.kcp.dev/cluster": "us-west1"},
+			},
+			"spec": spec,
+		}}
+	}
+	const ownedFields = `{"f:metadata":{"f:labels":{"f:internal.workloads.kcp.dev/cluster":{}}},"f:spec":{"f:replicas":{},"f:containers":{}}}`
+	containers := []interface{}{map[string]interface{}{"name": "a", "image": "busybox"}}
+
+	tests := map[string]struct {
+		existing *unstructured.Unstructured
+		desired  *unstructured.Unstructured
+		want     bool
+	}{
+		"up to date": {
+			existing: existing(syncerApplyManager, metav1.ManagedFieldsOperationApply, ownedFields),
+			desired:  desired(map[string]interface{}{"replicas": int64(1), "containers": containers}),
+			want:     true,
+		},
+		"nulls are not applied": {
+			existing: existing(syncerApplyManager, metav1.ManagedFieldsOperationApply, ownedFields),
+			desired:  desired(map[string]interface{}{"replicas": int64(1), "containers": containers, "selector": nil}),
+			want:     true,
+		},
+		"changed value": {
+			existing: existing(syncerApplyManager, metav1.ManagedFieldsOperationApply, ownedFields),
+			desired:  desired(map[string]interface{}{"replicas": int64(2), "containers": containers}),
+		},
+		"changed list": {
+			existing: existing(syncerApplyManager, metav1.ManagedFieldsOperationApply, ownedFields),
+			desired:  desired(map[string]interface{}{"replicas": int64(1), "containers": []interface{}{map[string]interface{}{"name": "a", "image": "nginx"}}}),
+		},
+		"added field": {
+			existing: existing(syncerApplyManager, metav1.ManagedFieldsOperationApply, ownedFields),
+			desired:  desired(map[string]interface{}{"replicas": int64(1), "containers": containers, "paused": true}),
+		},
+		"removed field": {
+			existing: existing(syncerApplyManager, metav1.ManagedFieldsOperationApply, ownedFields),
+			desired:  desired(map[string]interface{}{"containers": containers}),
+		},
+		"not applied by the syncer": {
+			existing: existing("kubectl", metav1.ManagedFieldsOperationApply, ownedFields),
+			desired:  desired(map[string]interface{}{"replicas": int64(1), "containers": containers}),
+		},
+		"updated by the syncer": {
+			existing: existing(syncerApplyManager, metav1.ManagedFieldsOperationUpdate, ownedFields),
+			desired:  desired(map[string]interface{}{"replicas": int64(1), "containers": containers}),
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := appliedFieldsUpToDate(tt.existing, tt.desired, syncerApplyManager)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -25,10 +25,13 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilspointer "k8s.io/utils/pointer"
+
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
 )
 
 type DeploymentMutator struct {
-	upstreamURL *url.URL
+	upstreamURL      *url.URL
+	workloadIdentity bool
 }
 
 func (dm *DeploymentMutator) GVR() schema.GroupVersionResource {
@@ -39,9 +42,12 @@ func (dm *DeploymentMutator) GVR() schema.GroupVersionResource {
 	}
 }
 
-func NewDeploymentMutator(upstreamURL *url.URL) *DeploymentMutator {
+// NewDeploymentMutator returns a mutator pointing the pods of deployments to kcp. If workloadIdentity
+// is true, the workload identity token of the ServiceAccount is mounted into the pods too.
+func NewDeploymentMutator(upstreamURL *url.URL, workloadIdentity bool) *DeploymentMutator {
 	return &DeploymentMutator{
-		upstreamURL: upstreamURL,
+		upstreamURL:      upstreamURL,
+		workloadIdentity: workloadIdentity,
 	}
 }
 
@@ -64,6 +70,11 @@ func (dm *DeploymentMutator) Mutate(downstreamObj *unstructured.Unstructured) er
 	// Alias for the template.spec to improve readability.
 	templateSpec := &deployment.Spec.Template.Spec
 
+	serviceAccountName := templateSpec.ServiceAccountName
+	if serviceAccountName == "" {
+		serviceAccountName = "default"
+	}
+
 	if templateSpec.ServiceAccountName == "" || templateSpec.ServiceAccountName == "default" {
 		templateSpec.ServiceAccountName = "kcp-default"
 	}
@@ -136,40 +147,90 @@ func (dm *DeploymentMutator) Mutate(downstreamObj *unstructured.Unstructured) er
 		},
 	}
 
-	// Override Envs and add the VolumeMount to all the containers
+	volumeMounts := []corev1.VolumeMount{serviceAccountMount}
+	volumes := []corev1.Volume{serviceAccountVolume}
+
+	if dm.workloadIdentity {
+		// The token kcp keeps for the ServiceAccount in the workspace. The secret is synced along
+		// with the ServiceAccount, but might arrive after the deployment, hence optional. The
+		// kubelet updates the files when the secret is refreshed.
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      "kcp-workload-identity",
+			MountPath: "/var/run/secrets/kcp.dev/workload-identity",
+			ReadOnly:  true,
+		})
+		volumes = append(volumes, corev1.Volume{
+			Name: "kcp-workload-identity",
+			VolumeSource: corev1.VolumeSource{
+				Projected: &corev1.ProjectedVolumeSource{
+					DefaultMode: utilspointer.Int32Ptr(420),
+					Sources: []corev1.VolumeProjection{
+						{
+							Secret: &corev1.SecretProjection{
+								LocalObjectReference: corev1.LocalObjectReference{
+									Name: workloadv1alpha1.WorkloadIdentitySecretName(serviceAccountName),
+								},
+								Items: []corev1.KeyToPath{
+									{
+										Key:  workloadv1alpha1.WorkloadIdentityTokenKey,
+										Path: "token",
+									},
+									{
+										Key:  workloadv1alpha1.WorkloadIdentityNamespaceKey,
+										Path: "namespace",
+									},
+								},
+								Optional: utilspointer.BoolPtr(true),
+							},
+						},
+					},
+				},
+			},
+		})
+	}
+
+	// Override Envs and add the VolumeMounts to all the containers
 	for i := range deployment.Spec.Template.Spec.Containers {
 		for _, overrideEnv := range overrideEnvs {
 			templateSpec.Containers[i].Env = updateEnv(templateSpec.Containers[i].Env, overrideEnv)
 		}
-		templateSpec.Containers[i].VolumeMounts = updateVolumeMount(templateSpec.Containers[i].VolumeMounts, serviceAccountMount)
+		for _, volumeMount := range volumeMounts {
+			templateSpec.Containers[i].VolumeMounts = updateVolumeMount(templateSpec.Containers[i].VolumeMounts, volumeMount)
+		}
 	}
 
-	// Override Envs and add the VolumeMount to all the Init containers
+	// Override Envs and add the VolumeMounts to all the Init containers
 	for i := range templateSpec.InitContainers {
 		for _, overrideEnv := range overrideEnvs {
 			templateSpec.InitContainers[i].Env = updateEnv(templateSpec.InitContainers[i].Env, overrideEnv)
 		}
-		templateSpec.InitContainers[i].VolumeMounts = updateVolumeMount(templateSpec.InitContainers[i].VolumeMounts, serviceAccountMount)
+		for _, volumeMount := range volumeMounts {
+			templateSpec.InitContainers[i].VolumeMounts = updateVolumeMount(templateSpec.InitContainers[i].VolumeMounts, volumeMount)
+		}
 	}
 
-	// Override Envs and add the VolumeMount to all the Ephemeral containers
+	// Override Envs and add the VolumeMounts to all the Ephemeral containers
 	for i := range templateSpec.EphemeralContainers {
 		for _, overrideEnv := range overrideEnvs {
 			templateSpec.EphemeralContainers[i].Env = updateEnv(templateSpec.EphemeralContainers[i].Env, overrideEnv)
 		}
-		templateSpec.EphemeralContainers[i].VolumeMounts = updateVolumeMount(templateSpec.EphemeralContainers[i].VolumeMounts, serviceAccountMount)
+		for _, volumeMount := range volumeMounts {
+			templateSpec.EphemeralContainers[i].VolumeMounts = updateVolumeMount(templateSpec.EphemeralContainers[i].VolumeMounts, volumeMount)
+		}
 	}
 
-	// Add the ServiceAccount volume with our overrides.
-	found := false
-	for i := range templateSpec.Volumes {
-		if templateSpec.Volumes[i].Name == "kcp-api-access" {
-			templateSpec.Volumes[i] = serviceAccountVolume
-			found = true
+	// Add the volumes with our overrides.
+	for _, volume := range volumes {
+		found := false
+		for i := range templateSpec.Volumes {
+			if templateSpec.Volumes[i].Name == volume.Name {
+				templateSpec.Volumes[i] = volume
+				found = true
+			}
+		}
+		if !found {
+			templateSpec.Volumes = append(templateSpec.Volumes, volume)
 		}
-	}
-	if !found {
-		templateSpec.Volumes = append(templateSpec.Volumes, serviceAccountVolume)
 	}
 
 	unstructured, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&deployment)
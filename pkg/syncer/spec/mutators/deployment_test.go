@@ -86,6 +86,7 @@ func TestMutate(t *testing.T) {
 		desc                                   string
 		originalDeployment, expectedDeployment *appsv1.Deployment
 		config                                 *rest.Config
+		workloadIdentity                       bool
 	}{{
 		desc: "Deployment without Envs or volumes is mutated.",
 		originalDeployment: &appsv1.Deployment{
@@ -492,12 +493,119 @@ func TestMutate(t *testing.T) {
 			config: &rest.Config{
 				Host: "https://4.5.6.7:12345",
 			}},
+		{
+			desc: "Deployment with a ServiceAccount gets its workload identity token mounted.",
+			originalDeployment: &appsv1.Deployment{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "Deployment",
+					APIVersion: "apps/v1",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-deployment",
+				},
+				Spec: appsv1.DeploymentSpec{
+					Replicas: new(int32),
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							ServiceAccountName: "builder",
+							Containers: []corev1.Container{
+								{
+									Name:  "test-container",
+									Image: "test-image",
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedDeployment: &appsv1.Deployment{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "Deployment",
+					APIVersion: "apps/v1",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-deployment",
+				},
+				Spec: appsv1.DeploymentSpec{
+					Replicas: new(int32),
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							AutomountServiceAccountToken: utilspointer.BoolPtr(false),
+							ServiceAccountName:           "builder",
+							Containers: []corev1.Container{
+								{
+									Name:  "test-container",
+									Image: "test-image",
+									Env: []corev1.EnvVar{
+										{
+											Name:  "KUBERNETES_SERVICE_PORT",
+											Value: "12345",
+										},
+										{
+											Name:  "KUBERNETES_SERVICE_PORT_HTTPS",
+											Value: "12345",
+										},
+										{
+											Name:  "KUBERNETES_SERVICE_HOST",
+											Value: "4.5.6.7",
+										},
+									},
+									VolumeMounts: []corev1.VolumeMount{
+										kcpApiAccessVolumeMount,
+										{
+											Name:      "kcp-workload-identity",
+											MountPath: "/var/run/secrets/kcp.dev/workload-identity",
+											ReadOnly:  true,
+										},
+									},
+								},
+							},
+							Volumes: []corev1.Volume{
+								kcpApiAccessVolume,
+								{
+									Name: "kcp-workload-identity",
+									VolumeSource: corev1.VolumeSource{
+										Projected: &corev1.ProjectedVolumeSource{
+											DefaultMode: utilspointer.Int32Ptr(420),
+											Sources: []corev1.VolumeProjection{
+												{
+													Secret: &corev1.SecretProjection{
+														LocalObjectReference: corev1.LocalObjectReference{
+															Name: "kcp-identity-builder",
+														},
+														Items: []corev1.KeyToPath{
+															{
+																Key:  "token",
+																Path: "token",
+															},
+															{
+																Key:  "namespace",
+																Path: "namespace",
+															},
+														},
+														Optional: utilspointer.BoolPtr(true),
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			config: &rest.Config{
+				Host: "https://4.5.6.7:12345",
+			},
+			workloadIdentity: true,
+		},
 	} {
 		{
 			t.Run(c.desc, func(t *testing.T) {
 				upstreamURL, err := url.Parse(c.config.Host)
 				require.NoError(t, err)
-				dm := NewDeploymentMutator(upstreamURL)
+				dm := NewDeploymentMutator(upstreamURL, c.workloadIdentity)
 				unstrOriginalDeployment, err := toUnstructured(c.originalDeployment)
 				require.NoError(t, err, "toRuntimeObject() = %v", err)
 
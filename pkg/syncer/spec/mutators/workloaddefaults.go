@@ -0,0 +1,129 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutators
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+// podSpecPaths are the paths of the pod specs in the workload resources.
+var podSpecPaths = map[schema.GroupResource][]string{
+	{Group: "", Resource: "pods"}:             {"spec"},
+	{Group: "apps", Resource: "deployments"}:  {"spec", "template", "spec"},
+	{Group: "apps", Resource: "replicasets"}:  {"spec", "template", "spec"},
+	{Group: "apps", Resource: "statefulsets"}: {"spec", "template", "spec"},
+	{Group: "apps", Resource: "daemonsets"}:   {"spec", "template", "spec"},
+	{Group: "batch", Resource: "jobs"}:        {"spec", "template", "spec"},
+	{Group: "batch", Resource: "cronjobs"}:    {"spec", "jobTemplate", "spec", "template", "spec"},
+}
+
+// ApplyWorkloadDefaults applies the workload defaults of a workspace to the pod spec of the
+// downstream object, if it is a workload. The entries of the default node selector override
+// those of the pod, default tolerations are added unless the pod has them already, and the
+// default runtime class is set if the pod has none. Only these fields are touched.
+func ApplyWorkloadDefaults(gvr schema.GroupVersionResource, downstreamObj *unstructured.Unstructured, defaults *tenancyv1alpha1.WorkloadDefaults) error {
+	if defaults == nil {
+		return nil
+	}
+	path, ok := podSpecPaths[gvr.GroupResource()]
+	if !ok {
+		return nil
+	}
+	podSpec, found, err := unstructured.NestedMap(downstreamObj.Object, path...)
+	if err != nil {
+		return err
+	} else if !found {
+		return nil
+	}
+
+	if len(defaults.NodeSelector) > 0 {
+		nodeSelector, _, err := unstructured.NestedStringMap(podSpec, "nodeSelector")
+		if err != nil {
+			return err
+		}
+		if nodeSelector == nil {
+			nodeSelector = map[string]string{}
+		}
+		for key, value := range defaults.NodeSelector {
+			nodeSelector[key] = value
+		}
+		if err := unstructured.SetNestedStringMap(podSpec, nodeSelector, "nodeSelector"); err != nil {
+			return err
+		}
+	}
+
+	if len(defaults.Tolerations) > 0 {
+		tolerations, _, err := unstructured.NestedSlice(podSpec, "tolerations")
+		if err != nil {
+			return err
+		}
+		existing := make([]corev1.Toleration, len(tolerations))
+		for i := range tolerations {
+			u, ok := tolerations[i].(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("toleration %d is not an object", i)
+			}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u, &existing[i]); err != nil {
+				return err
+			}
+		}
+		for i := range defaults.Tolerations {
+			toleration := defaults.Tolerations[i]
+			if hasToleration(existing, toleration) {
+				continue
+			}
+			u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&toleration)
+			if err != nil {
+				return err
+			}
+			tolerations = append(tolerations, u)
+		}
+		if err := unstructured.SetNestedSlice(podSpec, tolerations, "tolerations"); err != nil {
+			return err
+		}
+	}
+
+	if defaults.RuntimeClassName != "" {
+		runtimeClassName, _, err := unstructured.NestedString(podSpec, "runtimeClassName")
+		if err != nil {
+			return err
+		}
+		if runtimeClassName == "" {
+			podSpec["runtimeClassName"] = defaults.RuntimeClassName
+		}
+	}
+
+	return unstructured.SetNestedMap(downstreamObj.Object, podSpec, path...)
+}
+
+// hasToleration returns whether the toleration is in the list of tolerations.
+func hasToleration(tolerations []corev1.Toleration, toleration corev1.Toleration) bool {
+	for i := range tolerations {
+		if equality.Semantic.DeepEqual(tolerations[i], toleration) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+func TestApplyWorkloadDefaults(t *testing.T) {
+	defaults := &tenancyv1alpha1.WorkloadDefaults{
+		NodeSelector:     map[string]string{"pool": "tenants"},
+		Tolerations:      []corev1.Toleration{{Key: "tenants", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}},
+		RuntimeClassName: "gvisor",
+	}
+
+	tests := []struct {
+		name     string
+		gvr      schema.GroupVersionResource
+		obj      map[string]interface{}
+		defaults *tenancyv1alpha1.WorkloadDefaults
+		want     map[string]interface{}
+	}{
+		{
+			name: "defaults are set on the pod template of a deployment",
+			gvr:  schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"},
+			obj: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"replicas": int64(1),
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"containers": []interface{}{map[string]interface{}{"name": "app"}},
+						},
+					},
+				},
+			},
+			defaults: defaults,
+			want: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"replicas": int64(1),
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"containers":       []interface{}{map[string]interface{}{"name": "app"}},
+							"nodeSelector":     map[string]interface{}{"pool": "tenants"},
+							"tolerations":      []interface{}{map[string]interface{}{"key": "tenants", "operator": "Exists", "effect": "NoSchedule"}},
+							"runtimeClassName": "gvisor",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "node selector entries override those of the pod, existing tolerations and runtime class are kept",
+			gvr:  schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"},
+			obj: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"nodeSelector": map[string]interface{}{"pool": "gpu", "zone": "a"},
+					"tolerations": []interface{}{
+						map[string]interface{}{"key": "tenants", "operator": "Exists", "effect": "NoSchedule"},
+						map[string]interface{}{"key": "gpu", "operator": "Exists"},
+					},
+					"runtimeClassName": "kata",
+				},
+			},
+			defaults: defaults,
+			want: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"nodeSelector": map[string]interface{}{"pool": "tenants", "zone": "a"},
+					"tolerations": []interface{}{
+						map[string]interface{}{"key": "tenants", "operator": "Exists", "effect": "NoSchedule"},
+						map[string]interface{}{"key": "gpu", "operator": "Exists"},
+					},
+					"runtimeClassName": "kata",
+				},
+			},
+		},
+		{
+			name: "defaults are set on the pod template of a cronjob",
+			gvr:  schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "cronjobs"},
+			obj: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"jobTemplate": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"template": map[string]interface{}{
+								"spec": map[string]interface{}{},
+							},
+						},
+					},
+				},
+			},
+			defaults: &tenancyv1alpha1.WorkloadDefaults{RuntimeClassName: "gvisor"},
+			want: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"jobTemplate": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"template": map[string]interface{}{
+								"spec": map[string]interface{}{
+									"runtimeClassName": "gvisor",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "other resources are unchanged",
+			gvr:  schema.GroupVersionResource{Group: "", Version: "v1", Resource: "configmaps"},
+			obj: map[string]interface{}{
+				"data": map[string]interface{}{"spec": "x"},
+			},
+			defaults: defaults,
+			want: map[string]interface{}{
+				"data": map[string]interface{}{"spec": "x"},
+			},
+		},
+		{
+			name: "workloads without pod template are unchanged",
+			gvr:  schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"},
+			obj: map[string]interface{}{
+				"spec": map[string]interface{}{"replicas": int64(1)},
+			},
+			defaults: defaults,
+			want: map[string]interface{}{
+				"spec": map[string]interface{}{"replicas": int64(1)},
+			},
+		},
+		{
+			name: "without defaults nothing is changed",
+			gvr:  schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"},
+			obj: map[string]interface{}{
+				"spec": map[string]interface{}{},
+			},
+			want: map[string]interface{}{
+				"spec": map[string]interface{}{},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := &unstructured.Unstructured{Object: tc.obj}
+			err := ApplyWorkloadDefaults(tc.gvr, obj, tc.defaults)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, obj.Object)
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"reflect"
 	"time"
 
 	"github.com/kcp-dev/logicalcluster"
@@ -34,6 +35,8 @@ import (
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/syncer/shared"
 	specmutators "github.com/kcp-dev/kcp/pkg/syncer/spec/mutators"
 )
 
@@ -53,11 +56,12 @@ type Controller struct {
 	workloadClusterName               string
 	workloadClusterLogicalClusterName logicalcluster.Name
 	advancedSchedulingEnabled         bool
+	namespaceNamer                    *shared.NamespaceNamer
 }
 
-func NewSpecSyncer(gvrs []schema.GroupVersionResource, workloadClusterLogicalClusterName logicalcluster.Name, workloadClusterName string, upstreamURL *url.URL, advancedSchedulingEnabled bool,
+func NewSpecSyncer(gvrs []schema.GroupVersionResource, workloadClusterLogicalClusterName logicalcluster.Name, workloadClusterName string, upstreamURL *url.URL, advancedSchedulingEnabled, workloadIdentity bool, namespaceNamer *shared.NamespaceNamer,
 	upstreamClient dynamic.ClusterInterface, downstreamClient dynamic.Interface, upstreamInformers, downstreamInformers dynamicinformer.DynamicSharedInformerFactory) (*Controller, error) {
-	deploymentMutator := specmutators.NewDeploymentMutator(upstreamURL)
+	deploymentMutator := specmutators.NewDeploymentMutator(upstreamURL, workloadIdentity)
 	secretMutator := specmutators.NewSecretMutator()
 
 	c := Controller{
@@ -76,6 +80,7 @@ func NewSpecSyncer(gvrs []schema.GroupVersionResource, workloadClusterLogicalClu
 		workloadClusterName:               workloadClusterName,
 		workloadClusterLogicalClusterName: workloadClusterLogicalClusterName,
 		advancedSchedulingEnabled:         advancedSchedulingEnabled,
+		namespaceNamer:                    namespaceNamer,
 	}
 
 	for _, gvr := range gvrs {
@@ -100,6 +105,19 @@ func NewSpecSyncer(gvrs []schema.GroupVersionResource, workloadClusterLogicalClu
 		klog.InfoS("Set up informer", "clusterName", workloadClusterLogicalClusterName, "pcluster", workloadClusterName, "gvr", gvr.String())
 	}
 
+	// resync the objects of a namespace when the labels to propagate downstream or the workload defaults change.
+	upstreamInformers.ForResource(namespacesGVR).Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldNamespace := oldObj.(*unstructured.Unstructured)
+			newNamespace := newObj.(*unstructured.Unstructured)
+
+			if !reflect.DeepEqual(downstreamLabels(oldNamespace), downstreamLabels(newNamespace)) ||
+				oldNamespace.GetAnnotations()[tenancyv1alpha1.WorkloadDefaultsAnnotation] != newNamespace.GetAnnotations()[tenancyv1alpha1.WorkloadDefaultsAnnotation] {
+				c.enqueueNamespaceObjects(gvrs, newNamespace)
+			}
+		},
+	})
+
 	return &c, nil
 }
 
@@ -124,6 +142,23 @@ func (c *Controller) AddToQueue(gvr schema.GroupVersionResource, obj interface{}
 	)
 }
 
+// enqueueNamespaceObjects queues the objects of all the given resources in the upstream namespace.
+func (c *Controller) enqueueNamespaceObjects(gvrs []schema.GroupVersionResource, ns *unstructured.Unstructured) {
+	clusterName := logicalcluster.From(ns)
+	for _, gvr := range gvrs {
+		if gvr == namespacesGVR {
+			continue
+		}
+		for _, obj := range c.upstreamInformers.ForResource(gvr).Informer().GetIndexer().List() {
+			u, ok := obj.(*unstructured.Unstructured)
+			if !ok || u.GetNamespace() != ns.GetName() || logicalcluster.From(u) != clusterName {
+				continue
+			}
+			c.AddToQueue(gvr, u)
+		}
+	}
+}
+
 // Start starts N worker processes processing work items.
 func (c *Controller) Start(ctx context.Context, numThreads int) {
 	defer runtime.HandleCrash()
@@ -38,14 +38,18 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/utils/pointer"
 
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
 	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
 	"github.com/kcp-dev/kcp/pkg/syncer/shared"
+	specmutators "github.com/kcp-dev/kcp/pkg/syncer/spec/mutators"
 )
 
 const (
 	syncerApplyManager = "syncer"
 )
 
+var namespacesGVR = schema.GroupVersionResource{Group: "", Version: "v1", Resource: "namespaces"}
+
 type mutatorGvrMap map[schema.GroupVersionResource]func(obj *unstructured.Unstructured) error
 
 func deepEqualApartFromStatus(oldUnstrob, newUnstrob *unstructured.Unstructured) bool {
@@ -114,13 +118,14 @@ func (c *Controller) process(ctx context.Context, gvr schema.GroupVersionResourc
 	clusterName, name := clusters.SplitClusterAwareKey(clusterAwareName)
 
 	// to downstream
-	downstreamNamespace, err := shared.PhysicalClusterNamespaceName(shared.NamespaceLocator{
+	locator := shared.NamespaceLocator{
 		LogicalCluster: clusterName,
 		Namespace:      upstreamNamespace,
-	})
+	}
+	downstreamNamespace, err := c.namespaceNamer.Name(locator)
 	if err != nil {
-		klog.Errorf("Error hashing namespace %s|%s: %v", clusterName, upstreamNamespace, err)
-		return nil // ignore error, shouldn't happen
+		klog.Errorf("Error naming downstream namespace for %s|%s: %v", clusterName, upstreamNamespace, err)
+		return nil // ignore error, retrying does not help
 	}
 
 	// get the upstream object
@@ -130,6 +135,12 @@ func (c *Controller) process(ctx context.Context, gvr schema.GroupVersionResourc
 	}
 	if !exists {
 		// deleted upstream => delete downstream
+		if owned, err := c.downstreamNamespaceOwnedBy(ctx, downstreamNamespace, locator); err != nil {
+			return err
+		} else if !owned {
+			klog.Warningf("Not deleting downstream GVR %q object %s/%s for upstream cluster %q: namespace %q belongs to another workspace", gvr.String(), upstreamNamespace, name, clusterName, downstreamNamespace)
+			return nil
+		}
 		klog.Infof("Deleting downstream GVR %q object %s/%s for upstream cluster %q", gvr.String(), upstreamNamespace, name, clusterName)
 		if err := c.downstreamClient.Resource(gvr).Namespace(downstreamNamespace).Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
 			return err
@@ -174,10 +185,16 @@ func (c *Controller) ensureDownstreamNamespaceExists(ctx context.Context, downst
 	})
 
 	if upstreamObj.GetLabels() != nil {
-		newNamespace.SetLabels(map[string]string{
-			// TODO: this should be set once at syncer startup and propagated around everywhere.
-			workloadv1alpha1.InternalDownstreamClusterLabel: c.workloadClusterName,
-		})
+		labels, err := c.upstreamNamespaceDownstreamLabels(l)
+		if err != nil {
+			return err
+		}
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		// TODO: this should be set once at syncer startup and propagated around everywhere.
+		labels[workloadv1alpha1.InternalDownstreamClusterLabel] = c.workloadClusterName
+		newNamespace.SetLabels(labels)
 	}
 
 	// TODO(sttts): check that namespace exists in lister before using the client
@@ -189,6 +206,12 @@ func (c *Controller) ensureDownstreamNamespaceExists(ctx context.Context, downst
 			klog.Errorf("Error while creating namespace %q: %v", downstreamNamespace, err)
 			return err
 		}
+		// With names that are not unique by construction, the namespace might belong to someone else.
+		if owned, err := c.downstreamNamespaceOwnedBy(ctx, downstreamNamespace, l); err != nil {
+			return err
+		} else if !owned {
+			return fmt.Errorf("downstream namespace %q for upstream namespace %s|%s already exists and belongs to another workspace or was not created by the syncer", downstreamNamespace, l.LogicalCluster, l.Namespace)
+		}
 	} else {
 		klog.Infof("Created downstream namespace %s for upstream namespace %s|%s", downstreamNamespace, l.LogicalCluster, l.Namespace)
 	}
@@ -196,6 +219,90 @@ func (c *Controller) ensureDownstreamNamespaceExists(ctx context.Context, downst
 	return nil
 }
 
+// downstreamNamespaceOwnedBy returns whether the downstream namespace does not exist or
+// belongs to the given NamespaceLocator. It is always true if the namespace naming
+// cannot conflict.
+func (c *Controller) downstreamNamespaceOwnedBy(ctx context.Context, downstreamNamespace string, l shared.NamespaceLocator) (bool, error) {
+	if !c.namespaceNamer.MayConflict() {
+		return true, nil
+	}
+
+	ns, err := c.downstreamClient.Resource(schema.GroupVersionResource{
+		Group:    "",
+		Version:  "v1",
+		Resource: "namespaces",
+	}).Get(ctx, downstreamNamespace, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return true, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	owner, err := shared.LocatorFromAnnotations(ns.GetAnnotations())
+	if err != nil {
+		return false, nil // not a valid locator, i.e. not ours
+	}
+	return owner != nil && *owner == l, nil
+}
+
+// upstreamNamespaceDownstreamLabels returns the labels of the upstream namespace that are
+// propagated from its workspace to downstream objects.
+func (c *Controller) upstreamNamespaceDownstreamLabels(l shared.NamespaceLocator) (map[string]string, error) {
+	obj, exists, err := c.upstreamInformers.ForResource(namespacesGVR).Informer().GetIndexer().GetByKey(clusters.ToClusterAwareKey(l.LogicalCluster, l.Namespace))
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, nil
+	}
+	ns, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("namespace is expected to be Unstructured, but is %T", obj)
+	}
+	return downstreamLabels(ns), nil
+}
+
+// upstreamNamespaceWorkloadDefaults returns the workload defaults propagated from its workspace
+// to the upstream namespace, or nil if there are none.
+func (c *Controller) upstreamNamespaceWorkloadDefaults(l shared.NamespaceLocator) (*tenancyv1alpha1.WorkloadDefaults, error) {
+	obj, exists, err := c.upstreamInformers.ForResource(namespacesGVR).Informer().GetIndexer().GetByKey(clusters.ToClusterAwareKey(l.LogicalCluster, l.Namespace))
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, nil
+	}
+	ns, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("namespace is expected to be Unstructured, but is %T", obj)
+	}
+	value := ns.GetAnnotations()[tenancyv1alpha1.WorkloadDefaultsAnnotation]
+	if value == "" {
+		return nil, nil
+	}
+	var defaults tenancyv1alpha1.WorkloadDefaults
+	if err := json.Unmarshal([]byte(value), &defaults); err != nil {
+		return nil, fmt.Errorf("failed to decode workload defaults of namespace %s|%s: %w", l.LogicalCluster, l.Namespace, err)
+	}
+	return &defaults, nil
+}
+
+// downstreamLabels returns the labels of the namespace that are listed in its
+// downstream labels annotation.
+func downstreamLabels(ns *unstructured.Unstructured) map[string]string {
+	value := ns.GetAnnotations()[tenancyv1alpha1.DownstreamLabelsAnnotation]
+	if value == "" {
+		return nil
+	}
+	labels := map[string]string{}
+	for _, key := range strings.Split(value, ",") {
+		if v, ok := ns.GetLabels()[key]; ok {
+			labels[key] = v
+		}
+	}
+	return labels
+}
+
 func (c *Controller) ensureSyncerFinalizer(ctx context.Context, gvr schema.GroupVersionResource, upstreamObj *unstructured.Unstructured) error {
 	upstreamFinalizers := upstreamObj.GetFinalizers()
 	hasFinalizer := false
@@ -256,8 +363,21 @@ func (c *Controller) applyToDownstream(ctx context.Context, gvr schema.GroupVers
 	labels := downstreamObj.GetLabels()
 	delete(labels, workloadv1alpha1.InternalClusterResourceStateLabelPrefix+c.workloadClusterName)
 	labels[workloadv1alpha1.InternalDownstreamClusterLabel] = c.workloadClusterName
+	// add the workspace labels propagated to the namespace, e.g. for cost attribution downstream.
+	propagated, err := c.upstreamNamespaceDownstreamLabels(shared.NamespaceLocator{LogicalCluster: upstreamObjLogicalCluster, Namespace: upstreamObj.GetNamespace()})
+	if err != nil {
+		return err
+	}
+	for key, value := range propagated {
+		labels[key] = value
+	}
 	downstreamObj.SetLabels(labels)
 
+	// identify the upstream object of workloads with provenance annotations, for supply-chain tooling downstream.
+	if err := shared.SetProvenanceSource(downstreamObj, upstreamObj); err != nil {
+		return err
+	}
+
 	// Run name transformations on the downstreamObj.
 	transformName(downstreamObj)
 
@@ -328,6 +448,33 @@ func (c *Controller) applyToDownstream(ctx context.Context, gvr schema.GroupVers
 		}
 	}
 
+	// Apply the workload defaults of the workspace last, such that neither mutators nor spec diffs override them.
+	defaults, err := c.upstreamNamespaceWorkloadDefaults(shared.NamespaceLocator{LogicalCluster: upstreamObjLogicalCluster, Namespace: upstreamObj.GetNamespace()})
+	if err != nil {
+		return err
+	}
+	if err := specmutators.ApplyWorkloadDefaults(gvr, downstreamObj, defaults); err != nil {
+		return err
+	}
+
+	stripForApply(downstreamObj)
+
+	// Skip the write if the fields the syncer owns downstream have the desired values already.
+	existing, err := c.downstreamInformers.ForResource(gvr).Lister().ByNamespace(downstreamNamespace).Get(downstreamObj.GetName())
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if err == nil {
+		upToDate, err := appliedFieldsUpToDate(existing.(*unstructured.Unstructured), downstreamObj, syncerApplyManager)
+		if err != nil {
+			// apply anyway
+			klog.Warningf("Failed to compare managed fields of %s %s/%s: %v", gvr.Resource, downstreamNamespace, downstreamObj.GetName(), err)
+		} else if upToDate {
+			klog.V(4).Infof("Skipping upsert of %s %s/%s from upstream %s|%s/%s: up to date", gvr.Resource, downstreamObj.GetNamespace(), downstreamObj.GetName(), upstreamObj.GetClusterName(), upstreamObj.GetNamespace(), upstreamObj.GetName())
+			return nil
+		}
+	}
+
 	// Marshalling the unstructured object is good enough as SSA patch
 	data, err := json.Marshal(downstreamObj)
 	if err != nil {
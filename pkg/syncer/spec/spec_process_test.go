@@ -473,6 +473,7 @@ func TestSyncerProcess(t *testing.T) {
 		upstreamLogicalCluster    string
 		workloadClusterName       string
 		advancedSchedulingEnabled bool
+		namespaceNaming           *workloadv1alpha1.NamespaceNaming
 
 		expectError         bool
 		expectActionsOnFrom []clienttesting.Action
@@ -515,13 +516,107 @@ func TestSyncerProcess(t *testing.T) {
 							toUnstructured(t, deployment("theDeployment", "kcp0124d7647eb6a00b1fcb6f2252201601634989dd79deb7375c373973", "", map[string]string{
 								"internal.workloads.kcp.dev/cluster": "us-west1",
 							}, nil, nil)),
-							setNestedField(map[string]interface{}{}, "status"),
+							removeStrippedFields,
 							setPodSpecServiceAccount("spec", "template", "spec"),
 						),
 					),
 				),
 			},
 		},
+		"SpecSyncer upsert with labels propagated from the workspace": {
+			upstreamLogicalCluster: "root:org:ws",
+			fromNamespace: namespace("test", "root:org:ws", map[string]string{
+				"state.internal.workloads.kcp.dev/us-west1": "Sync",
+				"cost-center": "42",
+				"team":        "a",
+			}, map[string]string{
+				"tenancy.kcp.dev/downstream-labels": "cost-center",
+			}),
+			gvr: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"},
+			fromResource: deployment("theDeployment", "test", "root:org:ws", map[string]string{
+				"state.internal.workloads.kcp.dev/us-west1": "Sync",
+			}, nil, nil),
+			resourceToProcessLogicalClusterName: "root:org:ws",
+			resourceToProcessName:               "theDeployment",
+			workloadClusterName:                 "us-west1",
+
+			expectActionsOnFrom: []clienttesting.Action{},
+			expectActionsOnTo: []clienttesting.Action{
+				createNamespaceAction(
+					"",
+					changeUnstructured(
+						toUnstructured(t, namespace("kcp0124d7647eb6a00b1fcb6f2252201601634989dd79deb7375c373973", "",
+							map[string]string{
+								"internal.workloads.kcp.dev/cluster": "us-west1",
+								"cost-center":                        "42",
+							},
+							map[string]string{
+								"kcp.dev/namespace-locator": `{"logical-cluster":"root:org:ws","namespace":"test"}`,
+							})),
+						removeNilOrEmptyFields,
+					),
+				),
+				patchDeploymentAction(
+					"theDeployment",
+					"kcp0124d7647eb6a00b1fcb6f2252201601634989dd79deb7375c373973",
+					types.ApplyPatchType,
+					toJson(t,
+						changeUnstructured(
+							toUnstructured(t, deployment("theDeployment", "kcp0124d7647eb6a00b1fcb6f2252201601634989dd79deb7375c373973", "", map[string]string{
+								"internal.workloads.kcp.dev/cluster": "us-west1",
+								"cost-center":                        "42",
+							}, nil, nil)),
+							removeStrippedFields,
+							setPodSpecServiceAccount("spec", "template", "spec"),
+						),
+					),
+				),
+			},
+		},
+		"SpecSyncer with Verbatim naming, namespace of another workspace": {
+			upstreamLogicalCluster: "root:org:ws",
+			fromNamespace: namespace("test", "root:org:ws", map[string]string{
+				"state.internal.workloads.kcp.dev/us-west1": "Sync",
+			}, nil),
+			gvr: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"},
+			fromResource: deployment("theDeployment", "test", "root:org:ws", map[string]string{
+				"state.internal.workloads.kcp.dev/us-west1": "Sync",
+			}, nil, nil),
+			toResources: []runtime.Object{
+				namespace("test", "", map[string]string{
+					"internal.workloads.kcp.dev/cluster": "us-west1",
+				},
+					map[string]string{
+						"kcp.dev/namespace-locator": `{"logical-cluster":"root:org:other","namespace":"test"}`,
+					}),
+			},
+			resourceToProcessLogicalClusterName: "root:org:ws",
+			resourceToProcessName:               "theDeployment",
+			workloadClusterName:                 "us-west1",
+			namespaceNaming:                     &workloadv1alpha1.NamespaceNaming{Strategy: workloadv1alpha1.NamespaceNamingVerbatim},
+
+			expectError:         true,
+			expectActionsOnFrom: []clienttesting.Action{},
+			expectActionsOnTo: []clienttesting.Action{
+				createNamespaceAction(
+					"",
+					changeUnstructured(
+						toUnstructured(t, namespace("test", "",
+							map[string]string{
+								"internal.workloads.kcp.dev/cluster": "us-west1",
+							},
+							map[string]string{
+								"kcp.dev/namespace-locator": `{"logical-cluster":"root:org:ws","namespace":"test"}`,
+							})),
+						removeNilOrEmptyFields,
+					),
+				),
+				clienttesting.GetActionImpl{
+					ActionImpl: namespaceAction("get"),
+					Name:       "test",
+				},
+			},
+		},
 		"SpecSyncer upstream deletion": {
 			upstreamLogicalCluster: "root:org:ws",
 			fromNamespace: namespace("test", "root:org:ws", map[string]string{
@@ -586,7 +681,7 @@ func TestSyncerProcess(t *testing.T) {
 							toUnstructured(t, deployment("theDeployment", "kcp0124d7647eb6a00b1fcb6f2252201601634989dd79deb7375c373973", "", map[string]string{
 								"internal.workloads.kcp.dev/cluster": "us-west1",
 							}, nil, nil)),
-							setNestedField(map[string]interface{}{}, "status"),
+							removeStrippedFields,
 							setPodSpecServiceAccount("spec", "template", "spec"),
 						),
 					),
@@ -653,7 +748,7 @@ func TestSyncerProcess(t *testing.T) {
 									"containers": nil,
 								},
 							}, "spec", "template"),
-							setNestedField(map[string]interface{}{}, "status"),
+							removeStrippedFields,
 						),
 					),
 				),
@@ -833,7 +928,7 @@ func TestSyncerProcess(t *testing.T) {
 									"containers": nil,
 								},
 							}, "spec", "template"),
-							setNestedField(map[string]interface{}{}, "status"),
+							removeStrippedFields,
 							setPodSpecServiceAccount("spec", "template", "spec"),
 						),
 					),
@@ -878,7 +973,9 @@ func TestSyncerProcess(t *testing.T) {
 			}
 			upstreamURL, err := url.Parse("https://kcp.dev:6443")
 			require.NoError(t, err)
-			controller, err := NewSpecSyncer(gvrs, kcpLogicalCluster, tc.workloadClusterName, upstreamURL, tc.advancedSchedulingEnabled, fromClusterClient, toClient, fromInformers, toInformers)
+			namespaceNamer, err := shared.NewNamespaceNamer(tc.namespaceNaming)
+			require.NoError(t, err)
+			controller, err := NewSpecSyncer(gvrs, kcpLogicalCluster, tc.workloadClusterName, upstreamURL, tc.advancedSchedulingEnabled, false, namespaceNamer, fromClusterClient, toClient, fromInformers, toInformers)
 			require.NoError(t, err)
 
 			fromInformers.Start(ctx.Done())
@@ -1006,6 +1103,12 @@ func removeNilOrEmptyFields(in *unstructured.Unstructured) {
 	}
 }
 
+// removeStrippedFields removes the fields the syncer does not apply downstream.
+func removeStrippedFields(in *unstructured.Unstructured) {
+	unstructured.RemoveNestedField(in.UnstructuredContent(), "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(in.UnstructuredContent(), "status")
+}
+
 func setNestedField(value interface{}, fields ...string) unstructuredChange {
 	return func(d *unstructured.Unstructured) {
 		_ = unstructured.SetNestedField(d.UnstructuredContent(), value, fields...)
@@ -19,6 +19,7 @@ package status
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/kcp-dev/logicalcluster"
@@ -32,10 +33,15 @@ import (
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
+
+	"github.com/kcp-dev/kcp/pkg/syncer/journal"
 )
 
 const (
 	controllerName = "kcp-workload-syncer-status"
+
+	// journalReplayInterval is how often journaled objects are enqueued again.
+	journalReplayInterval = 10 * time.Second
 )
 
 type Controller struct {
@@ -49,10 +55,17 @@ type Controller struct {
 	workloadClusterName               string
 	workloadClusterLogicalClusterName logicalcluster.Name
 	advancedSchedulingEnabled         bool
+
+	// journal keeps the objects whose status could not be upsynced because kcp
+	// was unreachable. Nil means they are retried with backoff instead.
+	journal *journal.Journal
+	// offline is 1 if the last upsync of a journaled object failed because kcp
+	// was unreachable.
+	offline int32
 }
 
 func NewStatusSyncer(gvrs []schema.GroupVersionResource, workloadClusterLogicalClusterName logicalcluster.Name, workloadClusterName string, advancedSchedulingEnabled bool,
-	upstreamClient dynamic.ClusterInterface, downstreamClient dynamic.Interface, upstreamInformers, downstreamInformers dynamicinformer.DynamicSharedInformerFactory) (*Controller, error) {
+	upstreamClient dynamic.ClusterInterface, downstreamClient dynamic.Interface, upstreamInformers, downstreamInformers dynamicinformer.DynamicSharedInformerFactory, j *journal.Journal) (*Controller, error) {
 
 	c := &Controller{
 		queue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName),
@@ -66,6 +79,8 @@ func NewStatusSyncer(gvrs []schema.GroupVersionResource, workloadClusterLogicalC
 		workloadClusterName:               workloadClusterName,
 		workloadClusterLogicalClusterName: workloadClusterLogicalClusterName,
 		advancedSchedulingEnabled:         advancedSchedulingEnabled,
+
+		journal: j,
 	}
 
 	for _, gvr := range gvrs {
@@ -124,6 +139,9 @@ func (c *Controller) Start(ctx context.Context, numThreads int) {
 	for i := 0; i < numThreads; i++ {
 		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
 	}
+	if c.journal != nil {
+		go wait.UntilWithContext(ctx, c.replayJournal, journalReplayInterval)
+	}
 
 	<-ctx.Done()
 }
@@ -147,12 +165,54 @@ func (c *Controller) processNextWorkItem(ctx context.Context) bool {
 	defer c.queue.Done(key)
 
 	if err := c.process(ctx, qk.gvr, qk.key); err != nil {
+		if c.journal != nil && journal.IsConnectionError(err) {
+			// kcp is unreachable. Instead of retrying with backoff, the object is
+			// journaled and replayed when kcp is reachable again.
+			if atomic.CompareAndSwapInt32(&c.offline, 0, 1) {
+				klog.Warningf("%s: kcp is unreachable, journaling status updates: %v", controllerName, err)
+			}
+			if err := c.journal.Record(qk.gvr, qk.key); err != nil {
+				runtime.HandleError(err)
+				c.queue.AddRateLimited(key)
+				return true
+			}
+			c.queue.Forget(key)
+			return true
+		}
+
 		runtime.HandleError(fmt.Errorf("%s failed to sync %q, err: %w", controllerName, key, err))
 		c.queue.AddRateLimited(key)
 		return true
 	}
 
+	if c.journal != nil {
+		if _, found := c.journal.Get(qk.gvr, qk.key); found {
+			if atomic.CompareAndSwapInt32(&c.offline, 1, 0) {
+				klog.Infof("%s: kcp is reachable again, replaying %d journaled status updates", controllerName, c.journal.Len())
+				c.replayJournal(ctx)
+			}
+			if err := c.journal.Remove(qk.gvr, qk.key); err != nil {
+				runtime.HandleError(err)
+			}
+		}
+	}
+
 	c.queue.Forget(key)
 
 	return true
 }
+
+// replayJournal enqueues the journaled objects. While kcp is unreachable, only the
+// oldest is enqueued to probe whether it is reachable again.
+func (c *Controller) replayJournal(ctx context.Context) {
+	entries := c.journal.Entries()
+	if len(entries) == 0 {
+		return
+	}
+	if atomic.LoadInt32(&c.offline) == 1 {
+		entries = entries[:1]
+	}
+	for _, e := range entries {
+		c.queue.Add(queueKey{gvr: e.GVR(), key: e.Key})
+	}
+}
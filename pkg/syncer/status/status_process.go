@@ -22,10 +22,12 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/kcp-dev/logicalcluster"
 
 	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -135,6 +137,16 @@ func (c *Controller) updateStatusInUpstream(ctx context.Context, gvr schema.Grou
 	}
 
 	existing, err := c.upstreamClient.Cluster(upstreamLogicalCluster).Resource(gvr).Namespace(upstreamNamespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		klog.Errorf("Getting resource %s/%s: %v", upstreamNamespace, name, err)
+		return err
+	}
+	if c.journal != nil {
+		if stale, reason := c.journaledStatusStale(gvr, downstreamObj, existing, err); stale {
+			klog.Infof("Dropping journaled status of resource %q %s|%s/%s from pcluster namespace %s: %s", gvr.String(), upstreamLogicalCluster, upstreamNamespace, name, downstreamObj.GetNamespace(), reason)
+			return nil
+		}
+	}
 	if err != nil {
 		klog.Errorf("Getting resource %s/%s: %v", upstreamNamespace, name, err)
 		return err
@@ -184,6 +196,30 @@ func (c *Controller) updateStatusInUpstream(ctx context.Context, gvr schema.Grou
 	return nil
 }
 
+// journaledStatusStale returns whether the status of a journaled downstream object
+// must not be written upstream on replay, because the upstream object it belongs to
+// was deleted or recreated while kcp was unreachable.
+func (c *Controller) journaledStatusStale(gvr schema.GroupVersionResource, downstreamObj, existing *unstructured.Unstructured, getErr error) (bool, string) {
+	key, err := cache.MetaNamespaceKeyFunc(downstreamObj)
+	if err != nil {
+		return false, ""
+	}
+	entry, found := c.journal.Get(gvr, key)
+	if !found {
+		return false, ""
+	}
+	if apierrors.IsNotFound(getErr) {
+		return true, "deleted upstream"
+	}
+	if getErr != nil {
+		return false, ""
+	}
+	if created := existing.GetCreationTimestamp(); created.Time.After(entry.Recorded) {
+		return true, fmt.Sprintf("recreated upstream at %s, after it was journaled at %s", created.UTC().Format(time.RFC3339), entry.Recorded.UTC().Format(time.RFC3339))
+	}
+	return false, ""
+}
+
 // TransformName changes the object name into the desired one upstream.
 func transformName(syncedObject *unstructured.Unstructured) {
 	configMapGVR := schema.GroupVersionKind{Group: "", Version: "v1", Kind: "ConfigMap"}
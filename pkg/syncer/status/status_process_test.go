@@ -28,6 +28,7 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -39,9 +40,11 @@ import (
 	"k8s.io/client-go/dynamic/fake"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
 	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clusters"
 
 	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/syncer/journal"
 )
 
 var scheme *runtime.Scheme
@@ -535,7 +538,7 @@ func TestSyncerProcess(t *testing.T) {
 				{Group: "", Version: "v1", Resource: "namespaces"},
 				tc.gvr,
 			}
-			controller, err := NewStatusSyncer(gvrs, kcpLogicalCluster, tc.workloadClusterName, tc.advancedSchedulingEnabled, toClusterClient, fromClient, toInformers, fromInformers)
+			controller, err := NewStatusSyncer(gvrs, kcpLogicalCluster, tc.workloadClusterName, tc.advancedSchedulingEnabled, toClusterClient, fromClient, toInformers, fromInformers, nil)
 			require.NoError(t, err)
 
 			fromInformers.Start(ctx.Done())
@@ -570,6 +573,47 @@ func TestSyncerProcess(t *testing.T) {
 	}
 }
 
+func TestJournaledStatusStale(t *testing.T) {
+	gvr := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	recorded := time.Now()
+	downstream := toUnstructured(t, deployment("theDeployment", "kcp0124d7647eb6a00b1fcb6f2252201601634989dd79deb7375c373973", "", nil, nil, nil))
+	upstreamCreatedAt := func(created time.Time) *unstructured.Unstructured {
+		d := deployment("theDeployment", "test", "root:org:ws", nil, nil, nil)
+		d.CreationTimestamp = metav1.NewTime(created)
+		return toUnstructured(t, d)
+	}
+
+	tests := []struct {
+		name      string
+		journaled bool
+		upstream  *unstructured.Unstructured
+		getErr    error
+		wantStale bool
+	}{
+		{name: "not journaled", upstream: upstreamCreatedAt(recorded.Add(time.Hour))},
+		{name: "not journaled and deleted upstream", getErr: apierrors.NewNotFound(gvr.GroupResource(), "theDeployment")},
+		{name: "unchanged upstream", journaled: true, upstream: upstreamCreatedAt(recorded.Add(-time.Hour))},
+		{name: "deleted upstream", journaled: true, getErr: apierrors.NewNotFound(gvr.GroupResource(), "theDeployment"), wantStale: true},
+		{name: "recreated upstream", journaled: true, upstream: upstreamCreatedAt(recorded.Add(time.Hour)), wantStale: true},
+		{name: "other error", journaled: true, getErr: apierrors.NewServiceUnavailable("down")},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			j, err := journal.Open("")
+			require.NoError(t, err)
+			if tc.journaled {
+				key, err := cache.MetaNamespaceKeyFunc(downstream)
+				require.NoError(t, err)
+				require.NoError(t, j.Record(gvr, key))
+			}
+
+			c := &Controller{journal: j}
+			stale, _ := c.journaledStatusStale(gvr, downstream, tc.upstream, tc.getErr)
+			require.Equal(t, tc.wantStale, stale)
+		})
+	}
+}
+
 func setupServersideApplyPatchReactor(toClient *dynamicfake.FakeDynamicClient) {
 	toClient.PrependReactor("patch", "*", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
 		patchAction := action.(clienttesting.PatchAction)
@@ -20,6 +20,8 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -33,6 +35,7 @@ import (
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/pkg/version"
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
@@ -40,8 +43,12 @@ import (
 	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
 	kcpclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
 	workloadcliplugin "github.com/kcp-dev/kcp/pkg/cliplugins/workload/plugin"
+	"github.com/kcp-dev/kcp/pkg/syncer/journal"
+	"github.com/kcp-dev/kcp/pkg/syncer/shared"
+	"github.com/kcp-dev/kcp/pkg/syncer/simulator"
 	"github.com/kcp-dev/kcp/pkg/syncer/spec"
 	"github.com/kcp-dev/kcp/pkg/syncer/status"
+	"github.com/kcp-dev/kcp/pkg/syncer/usage"
 )
 
 const (
@@ -54,6 +61,8 @@ const (
 
 	// TODO(marun) Ensure backoff rather than using a constant to avoid thundering herds
 	gvrQueryInterval = 1 * time.Second
+
+	usageReportInterval = 1 * time.Minute
 )
 
 // SyncerConfig defines the syncer configuration that is guaranteed to
@@ -65,6 +74,16 @@ type SyncerConfig struct {
 	ResourcesToSync     sets.String
 	KCPClusterName      logicalcluster.Name
 	WorkloadClusterName string
+	// JournalDir is the directory of the journal of status updates that could not
+	// be written while kcp was unreachable. If empty, they are retried with backoff
+	// and kept in memory only.
+	JournalDir string
+	// WorkloadIdentity mounts the workload identity tokens kcp keeps for ServiceAccounts
+	// into the pods of deployments.
+	WorkloadIdentity bool
+	// Simulated syncs to an in-memory cluster fabricating the status of workloads
+	// instead of to DownstreamConfig. APIs are not imported from it.
+	Simulated bool
 }
 
 func (sc *SyncerConfig) ID() string {
@@ -118,26 +137,24 @@ func StartSyncer(ctx context.Context, cfg *SyncerConfig, numSyncerThreads int, i
 	// Start api import first because spec and status syncers are blocked by
 	// gvr discovery finding all the configured resource types in the kcp
 	// workspace.
-	apiImporter, err := NewAPIImporter(cfg.UpstreamConfig, cfg.DownstreamConfig, resources, cfg.KCPClusterName, cfg.WorkloadClusterName)
-	if err != nil {
-		return err
+	if cfg.Simulated {
+		klog.Infof("Simulating WorkloadCluster %s|%s, APIs are not imported", cfg.KCPClusterName, cfg.WorkloadClusterName)
+	} else {
+		apiImporter, err := NewAPIImporter(cfg.UpstreamConfig, cfg.DownstreamConfig, resources, cfg.KCPClusterName, cfg.WorkloadClusterName)
+		if err != nil {
+			return err
+		}
+		go apiImporter.Start(ctx, importPollInterval)
 	}
-	go apiImporter.Start(ctx, importPollInterval)
 
 	upstreamConfig := rest.CopyConfig(cfg.UpstreamConfig)
 	upstreamConfig.Host = syncerVirtualWorkspaceURL
 	upstreamConfig.UserAgent = "kcp#spec-syncer/" + kcpVersion
-	downstreamConfig := rest.CopyConfig(cfg.DownstreamConfig)
-	downstreamConfig.UserAgent = "kcp#status-syncer/" + kcpVersion
 
 	upstreamDynamicClient, err := dynamic.NewClusterForConfig(upstreamConfig)
 	if err != nil {
 		return err
 	}
-	downstreamDynamicClient, err := dynamic.NewForConfig(downstreamConfig)
-	if err != nil {
-		return err
-	}
 	upstreamDiscoveryClient, err := discovery.NewDiscoveryClientForConfig(upstreamConfig)
 	if err != nil {
 		return err
@@ -146,9 +163,6 @@ func StartSyncer(ctx context.Context, cfg *SyncerConfig, numSyncerThreads int, i
 	upstreamInformers := dynamicinformer.NewFilteredDynamicSharedInformerFactory(upstreamDynamicClient.Cluster(logicalcluster.Wildcard), resyncPeriod, metav1.NamespaceAll, func(o *metav1.ListOptions) {
 		o.LabelSelector = workloadv1alpha1.InternalClusterResourceStateLabelPrefix + cfg.WorkloadClusterName + "=" + string(workloadv1alpha1.ResourceStateSync)
 	})
-	downstreamInformers := dynamicinformer.NewFilteredDynamicSharedInformerFactory(downstreamDynamicClient, resyncPeriod, metav1.NamespaceAll, func(o *metav1.ListOptions) {
-		o.LabelSelector = workloadv1alpha1.InternalDownstreamClusterLabel + "=" + cfg.WorkloadClusterName
-	})
 
 	// TODO(ncdc): we need to provide user-facing details if this polling goes on forever. Blocking here is a bad UX.
 	// TODO(ncdc): Also, any regressions in our code will make any e2e test that starts a syncer (at least in-process)
@@ -177,6 +191,27 @@ func StartSyncer(ctx context.Context, cfg *SyncerConfig, numSyncerThreads int, i
 		return err
 	}
 
+	var downstreamConfig *rest.Config
+	var downstreamDynamicClient dynamic.Interface
+	if cfg.Simulated {
+		// the simulated cluster serves the kinds the resources have upstream.
+		simulated, err := simulator.NewCluster(upstreamDiscoveryClient, gvrs)
+		if err != nil {
+			return err
+		}
+		downstreamDynamicClient = simulated.DynamicClient()
+	} else {
+		downstreamConfig = rest.CopyConfig(cfg.DownstreamConfig)
+		downstreamConfig.UserAgent = "kcp#status-syncer/" + kcpVersion
+		downstreamDynamicClient, err = dynamic.NewForConfig(downstreamConfig)
+		if err != nil {
+			return err
+		}
+	}
+	downstreamInformers := dynamicinformer.NewFilteredDynamicSharedInformerFactory(downstreamDynamicClient, resyncPeriod, metav1.NamespaceAll, func(o *metav1.ListOptions) {
+		o.LabelSelector = workloadv1alpha1.InternalDownstreamClusterLabel + "=" + cfg.WorkloadClusterName
+	})
+
 	// Check whether we're in the Advanced Scheduling feature-gated mode.
 	workloadCluster, err := kcpClusterClient.Cluster(cfg.KCPClusterName).WorkloadV1alpha1().WorkloadClusters().Get(ctx, cfg.WorkloadClusterName, metav1.GetOptions{})
 	if err != nil {
@@ -187,21 +222,44 @@ func StartSyncer(ctx context.Context, cfg *SyncerConfig, numSyncerThreads int, i
 		klog.Infof("Advanced Scheduling feature is enabled for workloadCluster %s", cfg.WorkloadClusterName)
 		advancedSchedulingEnabled = true
 	}
+	namespaceNamer, err := shared.NewNamespaceNamer(workloadCluster.Spec.NamespaceNaming)
+	if err != nil {
+		return fmt.Errorf("invalid namespace naming of WorkloadCluster %s|%s: %w", cfg.KCPClusterName, cfg.WorkloadClusterName, err)
+	}
 
 	klog.Infof("Creating spec syncer for clusterName %s to pcluster %s, resources %v", cfg.KCPClusterName, cfg.WorkloadClusterName, resources)
 	upstreamURL, err := url.Parse(cfg.UpstreamConfig.Host)
 	if err != nil {
 		return err
 	}
-	specSyncer, err := spec.NewSpecSyncer(gvrs, cfg.KCPClusterName, cfg.WorkloadClusterName, upstreamURL, advancedSchedulingEnabled,
+	specSyncer, err := spec.NewSpecSyncer(gvrs, cfg.KCPClusterName, cfg.WorkloadClusterName, upstreamURL, advancedSchedulingEnabled, cfg.WorkloadIdentity, namespaceNamer,
 		upstreamDynamicClient, downstreamDynamicClient, upstreamInformers, downstreamInformers)
 	if err != nil {
 		return err
 	}
 
+	var statusJournal *journal.Journal
+	if cfg.JournalDir != "" {
+		if err := os.MkdirAll(cfg.JournalDir, 0700); err != nil {
+			return err
+		}
+		journalPath := filepath.Join(cfg.JournalDir, cfg.ID()+".journal")
+		statusJournal, err = journal.Open(journalPath)
+		if err != nil {
+			return fmt.Errorf("failed to open journal %s: %w", journalPath, err)
+		}
+		go func() {
+			<-ctx.Done()
+			if err := statusJournal.Close(); err != nil {
+				klog.Errorf("Failed to close journal %s: %v", journalPath, err)
+			}
+		}()
+		klog.Infof("Journaling status updates for WorkloadCluster %s|%s in %s, %d pending", cfg.KCPClusterName, cfg.WorkloadClusterName, journalPath, statusJournal.Len())
+	}
+
 	klog.Infof("Creating status syncer for clusterName %s from pcluster %s, resources %v", cfg.KCPClusterName, cfg.WorkloadClusterName, resources)
 	statusSyncer, err := status.NewStatusSyncer(gvrs, cfg.KCPClusterName, cfg.WorkloadClusterName, advancedSchedulingEnabled,
-		upstreamDynamicClient, downstreamDynamicClient, upstreamInformers, downstreamInformers)
+		upstreamDynamicClient, downstreamDynamicClient, upstreamInformers, downstreamInformers, statusJournal)
 	if err != nil {
 		return err
 	}
@@ -215,6 +273,16 @@ func StartSyncer(ctx context.Context, cfg *SyncerConfig, numSyncerThreads int, i
 	go specSyncer.Start(ctx, numSyncerThreads)
 	go statusSyncer.Start(ctx, numSyncerThreads)
 
+	// nothing runs in a simulated cluster, hence there is no usage to report.
+	if !cfg.Simulated {
+		downstreamKubeClient, err := kubernetes.NewForConfig(downstreamConfig)
+		if err != nil {
+			return err
+		}
+		usageReporter := usage.NewReporter(cfg.KCPClusterName, workloadCluster, kcpClusterClient, downstreamKubeClient, downstreamDynamicClient)
+		go usageReporter.Start(ctx, usageReportInterval)
+	}
+
 	// Attempt to heartbeat every interval
 	go wait.UntilWithContext(ctx, func(ctx context.Context) {
 		var heartbeatTime time.Time
@@ -241,6 +309,43 @@ func StartSyncer(ctx context.Context, cfg *SyncerConfig, numSyncerThreads int, i
 	return nil
 }
 
+// StartSyncers starts a syncer for each of the given configurations, sharing the
+// downstream cluster. Each syncer has its own credentials and informers. A syncer
+// failing to start is retried with backoff, without affecting the others.
+func StartSyncers(ctx context.Context, cfgs []*SyncerConfig, numSyncerThreads int, importPollInterval time.Duration) {
+	startSyncers(ctx, cfgs, func(ctx context.Context, cfg *SyncerConfig) error {
+		return StartSyncer(ctx, cfg, numSyncerThreads, importPollInterval)
+	}, wait.Backoff{Duration: time.Second, Factor: 2, Steps: 10, Cap: 5 * time.Minute})
+}
+
+func startSyncers(ctx context.Context, cfgs []*SyncerConfig, start func(ctx context.Context, cfg *SyncerConfig) error, initialBackoff wait.Backoff) {
+	for _, cfg := range cfgs {
+		cfg := cfg
+		go func() {
+			backoff := initialBackoff
+			for {
+				// each attempt gets its own context to stop what it started when it fails.
+				attemptCtx, cancel := context.WithCancel(ctx)
+				err := start(attemptCtx, cfg)
+				if err == nil {
+					<-ctx.Done()
+					cancel()
+					return
+				}
+				cancel()
+
+				delay := backoff.Step()
+				klog.Errorf("Failed to start syncer for WorkloadCluster %s|%s, retrying in %s: %v", cfg.KCPClusterName, cfg.WorkloadClusterName, delay, err)
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(delay):
+				}
+			}
+		}()
+	}
+}
+
 func contains(ss []string, s string) bool {
 	for _, n := range ss {
 		if n == s {
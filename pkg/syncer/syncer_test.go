@@ -0,0 +1,103 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package syncer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+func TestStartSyncers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	east := &SyncerConfig{WorkloadClusterName: "east"}
+	west := &SyncerConfig{WorkloadClusterName: "west"}
+	broken := &SyncerConfig{WorkloadClusterName: "broken"}
+
+	var lock sync.Mutex
+	attempts := map[string]int{}
+	started := map[string]context.Context{}
+	start := func(ctx context.Context, cfg *SyncerConfig) error {
+		lock.Lock()
+		defer lock.Unlock()
+
+		attempts[cfg.WorkloadClusterName]++
+		if cfg == broken && attempts[cfg.WorkloadClusterName] < 3 {
+			return errors.New("kcp is unreachable")
+		}
+		started[cfg.WorkloadClusterName] = ctx
+		return nil
+	}
+
+	startSyncers(ctx, []*SyncerConfig{east, broken, west}, start, wait.Backoff{Duration: time.Millisecond, Factor: 1, Steps: 10})
+
+	require.Eventually(t, func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return len(started) == 3
+	}, wait.ForeverTestTimeout, 10*time.Millisecond, "all syncers should eventually be started")
+
+	lock.Lock()
+	require.Equal(t, map[string]int{"east": 1, "west": 1, "broken": 3}, attempts, "only the failing syncer should be retried")
+	startedCtxs := []context.Context{started["east"], started["west"], started["broken"]}
+	lock.Unlock()
+
+	for _, c := range startedCtxs {
+		require.NoError(t, c.Err(), "started syncers should keep running")
+	}
+
+	cancel()
+	for _, c := range startedCtxs {
+		select {
+		case <-c.Done():
+		case <-time.After(wait.ForeverTestTimeout):
+			t.Fatal("started syncers should be stopped with the context")
+		}
+	}
+}
+
+func TestStartSyncersStopsRetrying(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var lock sync.Mutex
+	attempts := 0
+	start := func(ctx context.Context, cfg *SyncerConfig) error {
+		lock.Lock()
+		defer lock.Unlock()
+		attempts++
+		if attempts == 2 {
+			cancel()
+		}
+		return errors.New("kcp is unreachable")
+	}
+
+	startSyncers(ctx, []*SyncerConfig{{WorkloadClusterName: "broken"}}, start, wait.Backoff{Duration: time.Millisecond, Factor: 1, Steps: 10})
+
+	<-ctx.Done()
+	time.Sleep(50 * time.Millisecond)
+	lock.Lock()
+	defer lock.Unlock()
+	require.Equal(t, 2, attempts, "no retries after the context is done")
+}
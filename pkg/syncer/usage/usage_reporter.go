@@ -0,0 +1,306 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usage
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	resourcehelper "k8s.io/kubernetes/pkg/api/v1/resource"
+
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+	kcpclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
+	workloadclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/typed/workload/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/syncer/shared"
+)
+
+var podMetricsGVR = schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "pods"}
+
+// Reporter periodically reports the resources requested, limited and used by the pods of
+// the synced workloads on a workload cluster, per workspace, as WorkspaceWorkloadUsage
+// objects in the workspace of the WorkloadCluster.
+type Reporter struct {
+	workloadClusterLogicalClusterName logicalcluster.Name
+	workloadCluster                   *workloadv1alpha1.WorkloadCluster
+
+	usages                  workloadclient.WorkspaceWorkloadUsageInterface
+	downstreamKubeClient    kubernetes.Interface
+	downstreamDynamicClient dynamic.Interface
+
+	now func() time.Time
+}
+
+// NewReporter returns a Reporter for the given WorkloadCluster.
+func NewReporter(workloadClusterLogicalClusterName logicalcluster.Name, workloadCluster *workloadv1alpha1.WorkloadCluster,
+	kcpClusterClient kcpclient.ClusterInterface, downstreamKubeClient kubernetes.Interface, downstreamDynamicClient dynamic.Interface) *Reporter {
+	return &Reporter{
+		workloadClusterLogicalClusterName: workloadClusterLogicalClusterName,
+		workloadCluster:                   workloadCluster,
+
+		usages:                  kcpClusterClient.Cluster(workloadClusterLogicalClusterName).WorkloadV1alpha1().WorkspaceWorkloadUsages(),
+		downstreamKubeClient:    downstreamKubeClient,
+		downstreamDynamicClient: downstreamDynamicClient,
+
+		now: time.Now,
+	}
+}
+
+// Start reports the usage every interval until ctx is done.
+func (r *Reporter) Start(ctx context.Context, interval time.Duration) {
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := r.report(ctx); err != nil {
+			klog.Errorf("Failed to report workload usage of WorkloadCluster %s|%s: %v", r.workloadClusterLogicalClusterName, r.workloadCluster.Name, err)
+		}
+	}, interval)
+}
+
+func (r *Reporter) report(ctx context.Context) error {
+	statuses, err := r.collect(ctx)
+	if err != nil {
+		return err
+	}
+
+	existing, err := r.usages.List(ctx, metav1.ListOptions{LabelSelector: workloadv1alpha1.WorkloadClusterLabel + "=" + r.workloadCluster.Name})
+	if err != nil {
+		return err
+	}
+	existingByName := map[string]*workloadv1alpha1.WorkspaceWorkloadUsage{}
+	for i := range existing.Items {
+		existingByName[existing.Items[i].Name] = &existing.Items[i]
+	}
+
+	now := metav1.NewTime(r.now())
+	var errs []error
+	for workspace, status := range statuses {
+		status := status
+		status.LastUpdateTime = &now
+		name := ReportName(r.workloadCluster.Name, workspace)
+
+		usage, found := existingByName[name]
+		delete(existingByName, name)
+		if !found {
+			usage, err = r.usages.Create(ctx, &workloadv1alpha1.WorkspaceWorkloadUsage{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   name,
+					Labels: map[string]string{workloadv1alpha1.WorkloadClusterLabel: r.workloadCluster.Name},
+					OwnerReferences: []metav1.OwnerReference{{
+						APIVersion: workloadv1alpha1.SchemeGroupVersion.String(),
+						Kind:       "WorkloadCluster",
+						Name:       r.workloadCluster.Name,
+						UID:        r.workloadCluster.UID,
+					}},
+				},
+			}, metav1.CreateOptions{})
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+		}
+
+		usage = usage.DeepCopy()
+		usage.Status = status
+		if _, err := r.usages.UpdateStatus(ctx, usage, metav1.UpdateOptions{}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// workspaces without workloads anymore
+	for name := range existingByName {
+		if err := r.usages.Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to report usage: %v", errs)
+	}
+	return nil
+}
+
+// collect returns the usage of the workloads on the workload cluster by workspace.
+func (r *Reporter) collect(ctx context.Context) (map[logicalcluster.Name]workloadv1alpha1.WorkspaceWorkloadUsageStatus, error) {
+	namespaces, err := r.downstreamKubeClient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{LabelSelector: workloadv1alpha1.InternalDownstreamClusterLabel + "=" + r.workloadCluster.Name})
+	if err != nil {
+		return nil, err
+	}
+
+	namespaceUsages := map[logicalcluster.Name][]workloadv1alpha1.NamespaceWorkloadUsage{}
+	for _, ns := range namespaces.Items {
+		locator, err := shared.LocatorFromAnnotations(ns.Annotations)
+		if err != nil || locator == nil {
+			continue // not synced by us
+		}
+
+		pods, err := r.downstreamKubeClient.CoreV1().Pods(ns.Name).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		metrics, err := r.podMetrics(ctx, ns.Name)
+		if err != nil {
+			klog.V(2).Infof("Failed to get pod metrics of downstream namespace %s: %v", ns.Name, err)
+			metrics = nil
+		}
+
+		usage := namespaceUsage(locator.Namespace, pods.Items, metrics)
+		if usage.Pods == 0 {
+			continue
+		}
+		namespaceUsages[locator.LogicalCluster] = append(namespaceUsages[locator.LogicalCluster], usage)
+	}
+
+	statuses := make(map[logicalcluster.Name]workloadv1alpha1.WorkspaceWorkloadUsageStatus, len(namespaceUsages))
+	for workspace, usages := range namespaceUsages {
+		status := workspaceUsage(usages)
+		status.WorkloadCluster = workloadv1alpha1.WorkloadClusterReference{
+			Path: r.workloadClusterLogicalClusterName.String(),
+			Name: r.workloadCluster.Name,
+		}
+		status.Workspace = workspace.String()
+		statuses[workspace] = status
+	}
+	return statuses, nil
+}
+
+// podMetrics returns the usage of the pods in the namespace as reported by metrics-server,
+// or nil if metrics-server is not available.
+func (r *Reporter) podMetrics(ctx context.Context, namespace string) (map[string]corev1.ResourceList, error) {
+	list, err := r.downstreamDynamicClient.Resource(podMetricsGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	metrics := make(map[string]corev1.ResourceList, len(list.Items))
+	for _, item := range list.Items {
+		containers, _, err := unstructured.NestedSlice(item.Object, "containers")
+		if err != nil {
+			return nil, err
+		}
+		total := corev1.ResourceList{}
+		for _, c := range containers {
+			container, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			usage, _, err := unstructured.NestedStringMap(container, "usage")
+			if err != nil {
+				return nil, err
+			}
+			for name, value := range usage {
+				q, err := resource.ParseQuantity(value)
+				if err != nil {
+					return nil, err
+				}
+				addQuantity(total, corev1.ResourceName(name), q)
+			}
+		}
+		metrics[item.GetName()] = total
+	}
+	return metrics, nil
+}
+
+// namespaceUsage sums up the requests, limits and usage of the pods which are not terminated.
+// With nil metrics, usage is not set.
+func namespaceUsage(name string, pods []corev1.Pod, metrics map[string]corev1.ResourceList) workloadv1alpha1.NamespaceWorkloadUsage {
+	usage := workloadv1alpha1.NamespaceWorkloadUsage{
+		Name:     name,
+		Requests: corev1.ResourceList{},
+		Limits:   corev1.ResourceList{},
+	}
+	if metrics != nil {
+		usage.Usage = corev1.ResourceList{}
+	}
+
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		usage.Pods++
+
+		requests, limits := resourcehelper.PodRequestsAndLimits(pod)
+		addResources(usage.Requests, requests)
+		addResources(usage.Limits, limits)
+		if metrics != nil {
+			addResources(usage.Usage, metrics[pod.Name])
+		}
+	}
+	return usage
+}
+
+// workspaceUsage sums up the usage of the namespaces of a workspace.
+func workspaceUsage(namespaces []workloadv1alpha1.NamespaceWorkloadUsage) workloadv1alpha1.WorkspaceWorkloadUsageStatus {
+	sort.Slice(namespaces, func(i, j int) bool {
+		return namespaces[i].Name < namespaces[j].Name
+	})
+
+	status := workloadv1alpha1.WorkspaceWorkloadUsageStatus{
+		Requests:   corev1.ResourceList{},
+		Limits:     corev1.ResourceList{},
+		Namespaces: namespaces,
+	}
+	for _, ns := range namespaces {
+		status.Pods += ns.Pods
+		addResources(status.Requests, ns.Requests)
+		addResources(status.Limits, ns.Limits)
+		if ns.Usage != nil {
+			if status.Usage == nil {
+				status.Usage = corev1.ResourceList{}
+			}
+			addResources(status.Usage, ns.Usage)
+		}
+	}
+	return status
+}
+
+func addResources(total, add corev1.ResourceList) {
+	for name, q := range add {
+		addQuantity(total, name, q)
+	}
+}
+
+func addQuantity(total corev1.ResourceList, name corev1.ResourceName, q resource.Quantity) {
+	if existing, ok := total[name]; ok {
+		existing.Add(q)
+		total[name] = existing
+	} else {
+		total[name] = q.DeepCopy()
+	}
+}
+
+// ReportName returns the name of the WorkspaceWorkloadUsage reported for the given workspace by the
+// syncer of the given WorkloadCluster.
+func ReportName(workloadClusterName string, workspace logicalcluster.Name) string {
+	hash := sha256.Sum224([]byte(workspace.String()))
+	return fmt.Sprintf("%s-%x", workloadClusterName, hash[:8])
+}
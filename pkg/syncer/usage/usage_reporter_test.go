@@ -0,0 +1,100 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+)
+
+func pod(name string, phase corev1.PodPhase, requests, limits corev1.ResourceList) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:      "main",
+				Resources: corev1.ResourceRequirements{Requests: requests, Limits: limits},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+func resources(cpu, memory string) corev1.ResourceList {
+	return corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse(cpu),
+		corev1.ResourceMemory: resource.MustParse(memory),
+	}
+}
+
+// requireResources compares resource lists by value, ignoring the formatting of the quantities.
+func requireResources(t *testing.T, expected, got corev1.ResourceList) {
+	t.Helper()
+	require.Len(t, got, len(expected), "got %v, expected %v", got, expected)
+	for name, q := range expected {
+		g, ok := got[name]
+		require.True(t, ok, "missing %s in %v", name, got)
+		require.Zero(t, q.Cmp(g), "%s: got %s, expected %s", name, g.String(), q.String())
+	}
+}
+
+func TestNamespaceUsage(t *testing.T) {
+	pods := []corev1.Pod{
+		pod("a", corev1.PodRunning, resources("100m", "128Mi"), resources("200m", "256Mi")),
+		pod("b", corev1.PodPending, resources("250m", "64Mi"), nil),
+		pod("c", corev1.PodSucceeded, resources("1", "1Gi"), resources("1", "1Gi")),
+	}
+
+	t.Run("without metrics", func(t *testing.T) {
+		usage := namespaceUsage("default", pods, nil)
+		require.Equal(t, "default", usage.Name)
+		require.Equal(t, int32(2), usage.Pods)
+		requireResources(t, resources("350m", "192Mi"), usage.Requests)
+		requireResources(t, resources("200m", "256Mi"), usage.Limits)
+		require.Nil(t, usage.Usage)
+	})
+
+	t.Run("with metrics", func(t *testing.T) {
+		usage := namespaceUsage("default", pods, map[string]corev1.ResourceList{
+			"a": resources("50m", "100Mi"),
+			"c": resources("1", "1Gi"),
+		})
+		require.Equal(t, int32(2), usage.Pods)
+		requireResources(t, resources("50m", "100Mi"), usage.Usage)
+	})
+}
+
+func TestWorkspaceUsage(t *testing.T) {
+	status := workspaceUsage([]workloadv1alpha1.NamespaceWorkloadUsage{
+		{Name: "b", Pods: 1, Requests: resources("1", "1Gi"), Limits: corev1.ResourceList{}},
+		{Name: "a", Pods: 2, Requests: resources("500m", "512Mi"), Limits: resources("1", "1Gi"), Usage: resources("100m", "10Mi")},
+	})
+
+	require.Equal(t, int32(3), status.Pods)
+	require.Equal(t, "a", status.Namespaces[0].Name)
+	require.Equal(t, "b", status.Namespaces[1].Name)
+	requireResources(t, resources("1500m", "1536Mi"), status.Requests)
+	requireResources(t, resources("1", "1Gi"), status.Limits)
+	requireResources(t, resources("100m", "10Mi"), status.Usage)
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/client-go/kubernetes"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/authorization/delegated"
+	"github.com/kcp-dev/kcp/pkg/catalog"
+	kcpinformer "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
+	kcpopenapi "github.com/kcp-dev/kcp/pkg/openapi"
+	"github.com/kcp-dev/kcp/pkg/virtual/catalog/registry"
+	"github.com/kcp-dev/kcp/pkg/virtual/framework"
+	"github.com/kcp-dev/kcp/pkg/virtual/framework/fixedgvs"
+)
+
+const CatalogVirtualWorkspaceName string = "catalog"
+
+// AccessPolicy lets anonymous users read the catalog. They only see the entries whose
+// APIExport they are allowed to bind to, like any other user, i.e. those published to
+// the system:unauthenticated group.
+var AccessPolicy = framework.AccessPolicy{
+	Anonymous: framework.AnonymousAccessReadOnly,
+}
+
+// BuildVirtualWorkspace returns a read-only virtual workspace serving the service provider
+// catalog of a workspace under <rootPathPrefix>/<logical-cluster>, i.e. the CatalogEntries
+// of its organization the user is allowed to bind to. Anonymous users can read it.
+func BuildVirtualWorkspace(rootPathPrefix string, wildcardKcpInformers kcpinformer.SharedInformerFactory, kubeClusterClient kubernetes.ClusterInterface) framework.VirtualWorkspace {
+	informerHealth := framework.NewInformerHealth(framework.DefaultWatchFailureTolerance)
+	catalogEntryInformer := wildcardKcpInformers.Apis().V1alpha1().CatalogEntries()
+	informerHealth.AddInformer("catalogentries", catalogEntryInformer.Informer())
+
+	if !strings.HasSuffix(rootPathPrefix, "/") {
+		rootPathPrefix += "/"
+	}
+
+	return &fixedgvs.FixedGroupVersionsVirtualWorkspace{
+		Name:         CatalogVirtualWorkspaceName,
+		Ready:        informerHealth.Ready,
+		Live:         informerHealth.Live,
+		AccessPolicy: AccessPolicy,
+		RootPathResolver: func(urlPath string, requestContext context.Context) (accepted bool, prefixToStrip string, completedContext context.Context) {
+			completedContext = requestContext
+			if path := urlPath; strings.HasPrefix(path, rootPathPrefix) {
+				path = strings.TrimPrefix(path, rootPathPrefix)
+				segments := strings.SplitN(path, "/", 2)
+				if segments[0] == "" {
+					return
+				}
+				clusterName := segments[0]
+
+				return true, rootPathPrefix + clusterName,
+					context.WithValue(requestContext, registry.WorkspaceKey, logicalcluster.New(clusterName))
+			}
+			return
+		},
+		GroupVersionAPISets: []fixedgvs.GroupVersionAPISet{
+			{
+				GroupVersion:       apisv1alpha1.SchemeGroupVersion,
+				AddToScheme:        apisv1alpha1.AddToScheme,
+				OpenAPIDefinitions: kcpopenapi.GetOpenAPIDefinitions,
+				BootstrapRestResources: func(mainConfig genericapiserver.CompletedConfig) (map[string]fixedgvs.RestStorageBuilder, error) {
+					c := catalog.NewCatalog(catalogEntryInformer, newDelegatedAuthorizer(kubeClusterClient))
+					catalogEntriesRest := registry.NewREST(c.Entries)
+					return map[string]fixedgvs.RestStorageBuilder{
+						"catalogentries": func(apiGroupAPIServerConfig genericapiserver.CompletedConfig) (rest.Storage, error) {
+							return catalogEntriesRest, nil
+						},
+					}, nil
+				},
+			},
+		},
+	}
+}
+
+// newDelegatedAuthorizer returns an authorizer asking kcp about the logical cluster in
+// the context, where the catalog checks the permission to bind to an APIExport.
+func newDelegatedAuthorizer(kubeClusterClient kubernetes.ClusterInterface) authorizer.Authorizer {
+	return authorizer.AuthorizerFunc(func(ctx context.Context, a authorizer.Attributes) (authorizer.Decision, string, error) {
+		cluster := genericapirequest.ClusterFrom(ctx)
+		if cluster == nil || cluster.Name.Empty() {
+			return authorizer.DecisionNoOpinion, "", fmt.Errorf("no logical cluster in the context")
+		}
+		authz, err := delegated.NewDelegatedAuthorizer(cluster.Name, kubeClusterClient)
+		if err != nil {
+			return authorizer.DecisionNoOpinion, "", err
+		}
+		return authz.Authorize(ctx, a)
+	})
+}
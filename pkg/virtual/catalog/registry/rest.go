@@ -0,0 +1,107 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metainternal "k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/authentication/user"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+)
+
+type CatalogKeyType string
+
+// WorkspaceKey is the context key of the workspace whose catalog is served.
+const WorkspaceKey CatalogKeyType = "VirtualWorkspaceCatalogWorkspace"
+
+// REST lists the CatalogEntries of the catalog of a workspace, i.e. those the user is
+// allowed to bind to, see catalog.Catalog.
+type REST struct {
+	entries func(ctx context.Context, workspace logicalcluster.Name, u user.Info) ([]apisv1alpha1.CatalogEntry, error)
+
+	rest.TableConvertor
+}
+
+var _ rest.Lister = &REST{}
+var _ rest.Scoper = &REST{}
+
+// NewREST returns a REST storage listing the given entries of the catalog of a workspace.
+func NewREST(entries func(ctx context.Context, workspace logicalcluster.Name, u user.Info) ([]apisv1alpha1.CatalogEntry, error)) *REST {
+	return &REST{
+		entries:        entries,
+		TableConvertor: rest.NewDefaultTableConvertor(apisv1alpha1.Resource("catalogentries")),
+	}
+}
+
+// New returns a new CatalogEntry
+func (s *REST) New() runtime.Object {
+	return &apisv1alpha1.CatalogEntry{}
+}
+
+// Destroy implements rest.Storage
+func (s *REST) Destroy() {
+	// Do nothing
+}
+
+// NewList returns a new CatalogEntryList
+func (*REST) NewList() runtime.Object {
+	return &apisv1alpha1.CatalogEntryList{}
+}
+
+func (s *REST) NamespaceScoped() bool {
+	return false
+}
+
+// List retrieves the CatalogEntries of the catalog of the workspace of the request that
+// match label.
+func (s *REST) List(ctx context.Context, options *metainternal.ListOptions) (runtime.Object, error) {
+	userInfo, ok := apirequest.UserFrom(ctx)
+	if !ok {
+		return nil, kerrors.NewForbidden(apisv1alpha1.Resource("catalogentries"), "", fmt.Errorf("unable to list catalogentries without a user on the context"))
+	}
+	workspace, _ := ctx.Value(WorkspaceKey).(logicalcluster.Name)
+	if _, hasParent := workspace.Parent(); !hasParent {
+		return nil, kerrors.NewBadRequest(fmt.Sprintf("workspace %q has no catalog", workspace))
+	}
+
+	entries, err := s.entries(ctx, workspace, userInfo)
+	if err != nil {
+		return nil, kerrors.NewInternalError(err)
+	}
+
+	selector := labels.Everything()
+	if options != nil && options.LabelSelector != nil {
+		selector = options.LabelSelector
+	}
+	list := &apisv1alpha1.CatalogEntryList{}
+	for _, entry := range entries {
+		if selector.Matches(labels.Set(entry.Labels)) {
+			list.Items = append(list.Items, entry)
+		}
+	}
+	return list, nil
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metainternal "k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apiserver/pkg/authentication/user"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+)
+
+func TestList(t *testing.T) {
+	public := apisv1alpha1.CatalogEntry{ObjectMeta: metav1.ObjectMeta{Name: "widgets", ClusterName: "root:org:provider", Labels: map[string]string{"tier": "free"}}}
+	private := apisv1alpha1.CatalogEntry{ObjectMeta: metav1.ObjectMeta{Name: "gadgets", ClusterName: "root:org:provider"}}
+
+	tests := map[string]struct {
+		workspace string
+		user      user.Info
+		selector  labels.Selector
+		wantErr   func(error) bool
+		want      []string
+	}{
+		"anonymous user": {
+			workspace: "root:org:team",
+			user:      &user.DefaultInfo{Name: user.Anonymous, Groups: []string{user.AllUnauthenticated}},
+			want:      []string{"widgets"},
+		},
+		"authenticated user": {
+			workspace: "root:org:team",
+			user:      &user.DefaultInfo{Name: "alice", Groups: []string{user.AllAuthenticated}},
+			want:      []string{"widgets", "gadgets"},
+		},
+		"with labels": {
+			workspace: "root:org:team",
+			user:      &user.DefaultInfo{Name: "alice", Groups: []string{user.AllAuthenticated}},
+			selector:  labels.SelectorFromSet(labels.Set{"tier": "free"}),
+			want:      []string{"widgets"},
+		},
+		"root workspace": {
+			workspace: "root",
+			user:      &user.DefaultInfo{Name: "alice", Groups: []string{user.AllAuthenticated}},
+			wantErr:   kerrors.IsBadRequest,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := NewREST(func(ctx context.Context, workspace logicalcluster.Name, u user.Info) ([]apisv1alpha1.CatalogEntry, error) {
+				require.Equal(t, tt.workspace, workspace.String())
+				if u.GetName() == user.Anonymous {
+					return []apisv1alpha1.CatalogEntry{public}, nil
+				}
+				return []apisv1alpha1.CatalogEntry{public, private}, nil
+			})
+
+			ctx := apirequest.WithUser(context.Background(), tt.user)
+			ctx = context.WithValue(ctx, WorkspaceKey, logicalcluster.New(tt.workspace))
+
+			obj, err := s.List(ctx, &metainternal.ListOptions{LabelSelector: tt.selector})
+			if tt.wantErr != nil {
+				require.True(t, tt.wantErr(err), "unexpected error: %v", err)
+				return
+			}
+			require.NoError(t, err)
+
+			var got []string
+			for _, entry := range obj.(*apisv1alpha1.CatalogEntryList).Items {
+				got = append(got, entry.Name)
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+// AnonymousAccess defines which requests of anonymous users a virtual workspace serves.
+type AnonymousAccess string
+
+const (
+	// AnonymousAccessDenied rejects anonymous requests as unauthorized. This is the default.
+	AnonymousAccessDenied AnonymousAccess = ""
+	// AnonymousAccessReadOnly serves anonymous get, list and watch requests, and
+	// rejects other anonymous requests as forbidden.
+	AnonymousAccessReadOnly AnonymousAccess = "ReadOnly"
+	// AnonymousAccessAllowed serves all anonymous requests.
+	AnonymousAccessAllowed AnonymousAccess = "Allowed"
+)
+
+// AccessPolicy defines how the root API server treats the users of a virtual workspace,
+// before forwarding their requests to it. The virtual workspace is still responsible for
+// authorizing the requests it serves.
+type AccessPolicy struct {
+	// Anonymous defines which anonymous requests are served. By default, none are.
+	Anonymous AnonymousAccess
+
+	// Groups are added to the groups of every user of the virtual workspace, including
+	// anonymous users, e.g. to grant them access to a public view through RBAC.
+	Groups []string
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authentication
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	authserviceaccount "k8s.io/apiserver/pkg/authentication/serviceaccount"
+	"k8s.io/apiserver/pkg/authentication/user"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// TokenCache caches the successful authentication of requests by bearer token,
+// such that a virtual workspace server does not validate the token against kcp
+// on every request. Cached tokens are invalidated when their ServiceAccount or
+// service account token Secret is deleted.
+type TokenCache struct {
+	delegate authenticator.Request
+	ttl      time.Duration
+	now      func() time.Time
+
+	lock    sync.Mutex
+	entries map[cacheKey]*cacheEntry
+	// nextSweep is when expired entries are removed next.
+	nextSweep time.Time
+}
+
+type cacheKey struct {
+	token     [sha256.Size]byte
+	audiences string
+}
+
+type cacheEntry struct {
+	response *authenticator.Response
+	// subject is the logical cluster and name of the user, in the format <cluster>|<name>.
+	subject string
+	expires time.Time
+}
+
+var _ authenticator.Request = &TokenCache{}
+
+// NewTokenCache returns a TokenCache in front of delegate, which caches successful
+// authentications for ttl.
+func NewTokenCache(delegate authenticator.Request, ttl time.Duration) *TokenCache {
+	return &TokenCache{
+		delegate: delegate,
+		ttl:      ttl,
+		now:      time.Now,
+		entries:  map[cacheKey]*cacheEntry{},
+	}
+}
+
+// AuthenticateRequest authenticates requests with a bearer token and without a
+// client certificate from the cache, and all other requests by the delegate.
+func (c *TokenCache) AuthenticateRequest(req *http.Request) (*authenticator.Response, bool, error) {
+	token := bearerToken(req)
+	if token == "" || (req.TLS != nil && len(req.TLS.PeerCertificates) > 0) {
+		return c.delegate.AuthenticateRequest(req)
+	}
+
+	key := cacheKey{token: sha256.Sum256([]byte(token))}
+	if audiences, ok := authenticator.AudiencesFrom(req.Context()); ok {
+		key.audiences = strings.Join(audiences, ",")
+	}
+
+	c.lock.Lock()
+	entry, ok := c.entries[key]
+	c.lock.Unlock()
+	if ok && c.now().Before(entry.expires) {
+		// like the bearer token authenticator, do not pass the token on
+		req.Header.Del("Authorization")
+		return &authenticator.Response{Audiences: entry.response.Audiences, User: entry.response.User}, true, nil
+	}
+
+	resp, ok, err := c.delegate.AuthenticateRequest(req)
+	if err != nil || !ok || resp.User.GetName() == user.Anonymous {
+		return resp, ok, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := c.now()
+	if now.After(c.nextSweep) {
+		for key, entry := range c.entries {
+			if !now.Before(entry.expires) {
+				delete(c.entries, key)
+			}
+		}
+		c.nextSweep = now.Add(c.ttl)
+	}
+	c.entries[key] = &cacheEntry{
+		response: resp,
+		subject:  subject(resp.User),
+		expires:  now.Add(c.ttl),
+	}
+
+	return resp, ok, err
+}
+
+func bearerToken(req *http.Request) string {
+	auth := strings.TrimSpace(req.Header.Get("Authorization"))
+	parts := strings.SplitN(auth, " ", 3)
+	if len(parts) < 2 || strings.ToLower(parts[0]) != "bearer" {
+		return ""
+	}
+	return parts[1]
+}
+
+func subject(u user.Info) string {
+	var cluster string
+	if clusters := u.GetExtra()[authserviceaccount.ClusterNameKey]; len(clusters) > 0 {
+		cluster = clusters[0]
+	}
+	return cluster + "|" + u.GetName()
+}
+
+// InvalidateToken removes the cached authentications of the token.
+func (c *TokenCache) InvalidateToken(token string) {
+	hash := sha256.Sum256([]byte(token))
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for key := range c.entries {
+		if key.token == hash {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// InvalidateUser removes the cached authentications of the named user of the
+// logical cluster.
+func (c *TokenCache) InvalidateUser(cluster logicalcluster.Name, name string) {
+	subject := cluster.String() + "|" + name
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for key, entry := range c.entries {
+		if entry.subject == subject {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// InvalidateOnRevocation invalidates the cached tokens of ServiceAccounts when
+// they are deleted, and the tokens of service account token Secrets when they are
+// deleted or changed.
+func (c *TokenCache) InvalidateOnRevocation(secrets coreinformers.SecretInformer, serviceAccounts coreinformers.ServiceAccountInformer) {
+	secrets.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldSecret, ok := oldObj.(*corev1.Secret)
+			newSecret, ok2 := newObj.(*corev1.Secret)
+			if ok && ok2 && oldSecret.Type == newSecret.Type && bytes.Equal(oldSecret.Data[corev1.ServiceAccountTokenKey], newSecret.Data[corev1.ServiceAccountTokenKey]) {
+				return
+			}
+			c.invalidateSecret(oldObj)
+		},
+		DeleteFunc: c.invalidateSecret,
+	})
+	serviceAccounts.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			sa, ok := obj.(*corev1.ServiceAccount)
+			if !ok {
+				return
+			}
+			klog.V(4).Infof("Invalidating cached tokens of ServiceAccount %s|%s/%s", logicalcluster.From(sa), sa.Namespace, sa.Name)
+			c.InvalidateUser(logicalcluster.From(sa), authserviceaccount.MakeUsername(sa.Namespace, sa.Name))
+		},
+	})
+}
+
+func (c *TokenCache) invalidateSecret(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	secret, ok := obj.(*corev1.Secret)
+	if !ok || secret.Type != corev1.SecretTypeServiceAccountToken {
+		return
+	}
+	if token := secret.Data[corev1.ServiceAccountTokenKey]; len(token) > 0 {
+		c.InvalidateToken(string(token))
+	}
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authentication
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	authserviceaccount "k8s.io/apiserver/pkg/authentication/serviceaccount"
+	"k8s.io/apiserver/pkg/authentication/user"
+)
+
+type fakeAuthenticator struct {
+	users map[string]user.Info
+	calls int
+}
+
+func (a *fakeAuthenticator) AuthenticateRequest(req *http.Request) (*authenticator.Response, bool, error) {
+	a.calls++
+	token := bearerToken(req)
+	if u, ok := a.users[token]; ok {
+		req.Header.Del("Authorization")
+		return &authenticator.Response{User: u}, true, nil
+	}
+	return &authenticator.Response{User: &user.DefaultInfo{Name: user.Anonymous}}, true, nil
+}
+
+func TestTokenCache(t *testing.T) {
+	now := time.Now()
+	sa := &user.DefaultInfo{
+		Name:  authserviceaccount.MakeUsername("default", "controller"),
+		Extra: map[string][]string{authserviceaccount.ClusterNameKey: {"root:org:team"}},
+	}
+	delegate := &fakeAuthenticator{users: map[string]user.Info{
+		"sa-token":    sa,
+		"alice-token": &user.DefaultInfo{Name: "alice"},
+	}}
+	c := NewTokenCache(delegate, time.Minute)
+	c.now = func() time.Time { return now }
+
+	authenticate := func(token string) string {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, ok, err := c.AuthenticateRequest(req)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Empty(t, req.Header.Get("Authorization"), "token is not passed on")
+		return resp.User.GetName()
+	}
+
+	require.Equal(t, sa.Name, authenticate("sa-token"))
+	require.Equal(t, sa.Name, authenticate("sa-token"))
+	require.Equal(t, 1, delegate.calls, "second authentication is cached")
+
+	require.Equal(t, user.Anonymous, authenticate("unknown"))
+	require.Equal(t, user.Anonymous, authenticate("unknown"))
+	require.Equal(t, 3, delegate.calls, "failed authentications are not cached")
+
+	now = now.Add(time.Minute)
+	authenticate("sa-token")
+	require.Equal(t, 4, delegate.calls, "expired")
+
+	// client certificates are authenticated by the delegate
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer sa-token")
+	req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{}}}
+	_, _, err := c.AuthenticateRequest(req)
+	require.NoError(t, err)
+	require.Equal(t, 5, delegate.calls)
+
+	t.Run("revoked token", func(t *testing.T) {
+		authenticate("alice-token")
+		calls := delegate.calls
+		c.invalidateSecret(&corev1.Secret{
+			Type: corev1.SecretTypeServiceAccountToken,
+			Data: map[string][]byte{corev1.ServiceAccountTokenKey: []byte("alice-token")},
+		})
+		authenticate("alice-token")
+		require.Equal(t, calls+1, delegate.calls)
+	})
+
+	t.Run("deleted service account", func(t *testing.T) {
+		authenticate("sa-token")
+		calls := delegate.calls
+
+		c.InvalidateUser(logicalcluster.New("root:org:other"), sa.Name)
+		authenticate("sa-token")
+		require.Equal(t, calls, delegate.calls, "other workspace")
+
+		c.InvalidateUser(logicalcluster.New("root:org:team"), sa.Name)
+		authenticate("sa-token")
+		require.Equal(t, calls+1, delegate.calls)
+	})
+
+	t.Run("expired entries are removed", func(t *testing.T) {
+		now = now.Add(2 * time.Minute)
+		authenticate("alice-token")
+		require.Len(t, c.entries, 1)
+	})
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package celfilter
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/checker/decls"
+	"github.com/google/cel-go/common/operators"
+	"github.com/google/cel-go/parser"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// QueryParameter is the query parameter of LIST and WATCH requests to virtual
+// workspaces holding a CEL expression that objects must match, e.g.
+// ?celFilter=object.spec.replicas > 3.
+const QueryParameter = "celFilter"
+
+// maxExpressionLength limits the length of expressions, to bound compilation cost.
+const maxExpressionLength = 1024
+
+// Filter is a compiled CEL expression over an object, available as the
+// variable "object" to the expression.
+type Filter struct {
+	expression string
+	program    cel.Program
+}
+
+// Compile compiles a CEL expression to a Filter. Filters are evaluated for every
+// listed object and every watch event on behalf of any user, hence comprehension
+// macros like all, exists, map and filter are not available: without them, the
+// evaluation cost is linear in the length of the expression, which is bounded.
+func Compile(expression string) (*Filter, error) {
+	if len(expression) > maxExpressionLength {
+		return nil, fmt.Errorf("CEL filter is longer than %d characters", maxExpressionLength)
+	}
+
+	env, err := cel.NewEnv(
+		cel.ClearMacros(),
+		cel.Macros(hasMacros()...),
+		cel.Declarations(decls.NewVar("object", decls.Dyn)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("invalid CEL filter %q: %w", expression, issues.Err())
+	}
+	program, err := env.Program(ast)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CEL filter %q: %w", expression, err)
+	}
+
+	return &Filter{expression: expression, program: program}, nil
+}
+
+// hasMacros returns the has() macro, the only macro without comprehension.
+func hasMacros() []parser.Macro {
+	var macros []parser.Macro
+	for _, m := range parser.AllMacros {
+		if m.Function() == operators.Has {
+			macros = append(macros, m)
+		}
+	}
+	return macros
+}
+
+// String returns the expression of the filter.
+func (f *Filter) String() string {
+	return f.expression
+}
+
+// Matches returns whether the object matches the filter. Objects for which the
+// expression fails to evaluate, e.g. because of a missing field, or does not
+// evaluate to a bool, do not match.
+func (f *Filter) Matches(obj *unstructured.Unstructured) bool {
+	out, _, err := f.program.Eval(map[string]interface{}{"object": obj.Object})
+	if err != nil {
+		return false
+	}
+	matches, ok := out.Value().(bool)
+	return ok && matches
+}
+
+// FilterList removes the items of the list not matching the filter.
+func (f *Filter) FilterList(list *unstructured.UnstructuredList) {
+	items := list.Items[:0]
+	for i := range list.Items {
+		if f.Matches(&list.Items[i]) {
+			items = append(items, list.Items[i])
+		}
+	}
+	list.Items = items
+}
+
+// FilterWatch returns a watch passing only the events of objects matching the
+// filter. Objects that stop matching are sent as deleted, objects that start
+// matching as added. Only matching objects are remembered, such that the memory
+// of a watch is bounded by the number of matching objects. Hence, modifications
+// of objects that do not match are sent as deleted, as the client might have
+// seen the objects matching before.
+func (f *Filter) FilterWatch(w watch.Interface) watch.Interface {
+	return watch.Filter(w, (&watchState{filter: f, matching: sets.NewString()}).handle)
+}
+
+type watchState struct {
+	filter *Filter
+
+	lock sync.Mutex
+	// matching holds the keys of the objects last sent as matching the filter.
+	matching sets.String
+}
+
+func (s *watchState) handle(in watch.Event) (watch.Event, bool) {
+	obj, ok := in.Object.(*unstructured.Unstructured)
+	if !ok {
+		// bookmarks without objects and errors
+		return in, true
+	}
+	if in.Type == watch.Bookmark || in.Type == watch.Error {
+		return in, true
+	}
+
+	key := obj.GetClusterName() + "|" + obj.GetNamespace() + "/" + obj.GetName()
+	matches := s.filter.Matches(obj)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	matched := s.matching.Has(key)
+	switch in.Type {
+	case watch.Added:
+		if matches {
+			s.matching.Insert(key)
+		}
+		return in, matches
+	case watch.Modified:
+		switch {
+		case matches && matched:
+			return in, true
+		case matches:
+			s.matching.Insert(key)
+			return watch.Event{Type: watch.Added, Object: obj}, true
+		default:
+			// the client might have seen the object matching, before the watch
+			// started or before it stopped matching
+			s.matching.Delete(key)
+			return watch.Event{Type: watch.Deleted, Object: obj}, true
+		}
+	case watch.Deleted:
+		s.matching.Delete(key)
+		return in, matched || matches
+	}
+	return in, true
+}
+
+type filterKeyType int
+
+const filterKey filterKeyType = iota
+
+// WithExpression returns a context holding the CEL filter expression of the request.
+func WithExpression(ctx context.Context, expression string) context.Context {
+	return context.WithValue(ctx, filterKey, expression)
+}
+
+// FromContext compiles the CEL filter expression of the request. It returns nil
+// if the request has no filter.
+func FromContext(ctx context.Context) (*Filter, error) {
+	expression, _ := ctx.Value(filterKey).(string)
+	if expression == "" {
+		return nil, nil
+	}
+	return Compile(expression)
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package celfilter
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func object(name string, replicas int64) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": name, "namespace": "default"},
+		"spec":       map[string]interface{}{"replicas": replicas},
+	}}
+}
+
+func TestCompile(t *testing.T) {
+	tests := map[string]struct {
+		expression string
+		wantErr    bool
+	}{
+		"valid":            {expression: "object.spec.replicas > 1"},
+		"syntax error":     {expression: "object.spec.replicas >", wantErr: true},
+		"undeclared":       {expression: "foo.spec.replicas > 1", wantErr: true},
+		"too long":         {expression: strings.Repeat("true && ", 200) + "true", wantErr: true},
+		"non-bool allowed": {expression: "object.metadata.name"},
+		"has":              {expression: "has(object.spec.replicas)"},
+		"all":              {expression: "object.spec.containers.all(c, c.name != '')", wantErr: true},
+		"exists":           {expression: "object.spec.containers.exists(c, c.name == 'web')", wantErr: true},
+		"exists_one":       {expression: "object.spec.containers.exists_one(c, c.name == 'web')", wantErr: true},
+		"map":              {expression: "object.spec.containers.map(c, c.name).size() > 1", wantErr: true},
+		"filter":           {expression: "object.spec.containers.filter(c, c.name == 'web').size() > 1", wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Compile(tt.expression)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestMatches(t *testing.T) {
+	tests := map[string]struct {
+		expression string
+		want       bool
+	}{
+		"matching":        {expression: "object.spec.replicas > 1", want: true},
+		"not matching":    {expression: "object.spec.replicas > 3"},
+		"missing field":   {expression: "object.status.replicas > 1"},
+		"not a bool":      {expression: "object.metadata.name"},
+		"string function": {expression: "object.metadata.name.startsWith('web')", want: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			f, err := Compile(tt.expression)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, f.Matches(object("web", 2)))
+		})
+	}
+}
+
+func TestFilterList(t *testing.T) {
+	f, err := Compile("object.spec.replicas > 1")
+	require.NoError(t, err)
+
+	list := &unstructured.UnstructuredList{Items: []unstructured.Unstructured{*object("a", 1), *object("b", 2), *object("c", 3)}}
+	f.FilterList(list)
+
+	var names []string
+	for _, item := range list.Items {
+		names = append(names, item.GetName())
+	}
+	require.Equal(t, []string{"b", "c"}, names)
+}
+
+func TestFilterWatch(t *testing.T) {
+	f, err := Compile("object.spec.replicas > 1")
+	require.NoError(t, err)
+
+	events := []watch.Event{
+		{Type: watch.Added, Object: object("a", 1)},
+		{Type: watch.Added, Object: object("b", 2)},
+		{Type: watch.Modified, Object: object("a", 2)},
+		{Type: watch.Modified, Object: object("a", 3)},
+		{Type: watch.Modified, Object: object("b", 1)},
+		{Type: watch.Modified, Object: object("b", 0)},
+		{Type: watch.Deleted, Object: object("b", 0)},
+		{Type: watch.Deleted, Object: object("a", 3)},
+		{Type: watch.Modified, Object: object("unknown", 1)},
+		{Type: watch.Deleted, Object: object("other", 1)},
+	}
+
+	source := watch.NewFakeWithChanSize(len(events), false)
+	for _, e := range events {
+		source.Action(e.Type, e.Object)
+	}
+	source.Stop()
+
+	type event struct {
+		Type     watch.EventType
+		Name     string
+		Replicas int64
+	}
+	var got []event
+	for e := range f.FilterWatch(source).ResultChan() {
+		obj := e.Object.(*unstructured.Unstructured)
+		replicas, _, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+		got = append(got, event{Type: e.Type, Name: obj.GetName(), Replicas: replicas})
+	}
+	require.Equal(t, []event{
+		{Type: watch.Added, Name: "b", Replicas: 2},
+		{Type: watch.Added, Name: "a", Replicas: 2},
+		{Type: watch.Modified, Name: "a", Replicas: 3},
+		{Type: watch.Deleted, Name: "b", Replicas: 1},
+		{Type: watch.Deleted, Name: "b", Replicas: 0},
+		{Type: watch.Deleted, Name: "a", Replicas: 3},
+		{Type: watch.Deleted, Name: "unknown", Replicas: 1},
+	}, got)
+}
+
+func TestFromContext(t *testing.T) {
+	f, err := FromContext(context.Background())
+	require.NoError(t, err)
+	require.Nil(t, f)
+
+	f, err = FromContext(WithExpression(context.Background(), "object.spec.replicas > 1"))
+	require.NoError(t, err)
+	require.Equal(t, "object.spec.replicas > 1", f.String())
+
+	_, err = FromContext(WithExpression(context.Background(), "object.spec.replicas >"))
+	require.Error(t, err)
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apidefinition
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	dynamiccontext "github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/context"
+)
+
+// identityPaths are the fields identifying an object and its revision. They
+// can always be read and set, independently of the allowed paths.
+var identityPaths = []string{
+	"apiVersion",
+	"kind",
+	"metadata.name",
+	"metadata.generateName",
+	"metadata.namespace",
+	"metadata.uid",
+	"metadata.resourceVersion",
+	"metadata.generation",
+	"metadata.creationTimestamp",
+	"metadata.deletionTimestamp",
+}
+
+// FieldRestriction restricts the fields of the objects of a resource a client
+// can read and write, e.g. a provider accessing claimed resources in consuming
+// workspaces.
+//
+// Reads return only the allowed fields. Writes must not set or change other
+// fields, and those missing in updated objects, e.g. because they were
+// redacted on read, are kept as stored.
+type FieldRestriction struct {
+	// AllowedPaths are the fields the client can read and write, in dot
+	// notation, e.g. "spec.replicas" or "metadata.labels". The fields
+	// identifying the object, like its name and resource version, are always
+	// allowed.
+	AllowedPaths []string
+}
+
+var _ Transformer = FieldRestriction{}
+
+// APIFieldRestrictionsGetter is optionally implemented by APIDefinitionSetGetters
+// to restrict the fields of a resource of an API domain. All returned
+// restrictions apply, i.e. a field must be allowed by all of them.
+type APIFieldRestrictionsGetter interface {
+	GetAPIFieldRestrictions(ctx context.Context, key dynamiccontext.APIDomainKey, gvr schema.GroupVersionResource) []FieldRestriction
+}
+
+func (r FieldRestriction) paths() [][]string {
+	paths := make([][]string, 0, len(identityPaths)+len(r.AllowedPaths))
+	for _, p := range identityPaths {
+		paths = append(paths, strings.Split(p, "."))
+	}
+	for _, p := range r.AllowedPaths {
+		paths = append(paths, strings.Split(p, "."))
+	}
+	return paths
+}
+
+// Transform redacts the fields of obj outside the allowed paths.
+func (r FieldRestriction) Transform(_ context.Context, _ schema.GroupVersionResource, obj *unstructured.Unstructured) error {
+	redacted := map[string]interface{}{}
+	for _, p := range r.paths() {
+		v, found, err := unstructured.NestedFieldNoCopy(obj.Object, p...)
+		if err != nil || !found {
+			continue
+		}
+		if err := unstructured.SetNestedField(redacted, v, p...); err != nil {
+			return err
+		}
+	}
+	obj.Object = redacted
+	return nil
+}
+
+// Restrict checks that obj only sets or changes allowed fields compared to old,
+// which is nil on creation. It returns the paths of the other fields obj sets
+// or changes, sorted. If there are none, the fields outside the allowed paths
+// are restored from old, i.e. fields missing in obj are kept as stored.
+func (r FieldRestriction) Restrict(obj, old *unstructured.Unstructured) ([]string, error) {
+	paths := r.paths()
+	outside := func(u *unstructured.Unstructured) map[string]interface{} {
+		if u == nil {
+			return nil
+		}
+		ret := u.DeepCopy().Object
+		for _, p := range paths {
+			unstructured.RemoveNestedField(ret, p...)
+		}
+		return ret
+	}
+
+	var forbidden []string
+	oldOutside := outside(old)
+	walkLeaves(outside(obj), nil, func(path []string, v interface{}) {
+		oldV, found, err := unstructured.NestedFieldNoCopy(oldOutside, path...)
+		if err != nil || !found || !equality.Semantic.DeepEqual(v, oldV) {
+			forbidden = append(forbidden, strings.Join(path, "."))
+		}
+	})
+	if len(forbidden) > 0 || old == nil {
+		sort.Strings(forbidden)
+		return forbidden, nil
+	}
+
+	restored := old.DeepCopy().Object
+	for _, p := range paths {
+		v, found, err := unstructured.NestedFieldNoCopy(obj.Object, p...)
+		if err != nil || !found {
+			unstructured.RemoveNestedField(restored, p...)
+			continue
+		}
+		if err := unstructured.SetNestedField(restored, v, p...); err != nil {
+			return nil, err
+		}
+	}
+	obj.Object = restored
+	return nil, nil
+}
+
+// walkLeaves calls fn for the fields of m which are not maps, including lists
+// as a whole.
+func walkLeaves(m map[string]interface{}, prefix []string, fn func(path []string, v interface{})) {
+	for k, v := range m {
+		path := append(append([]string(nil), prefix...), k)
+		if nested, ok := v.(map[string]interface{}); ok {
+			walkLeaves(nested, path, fn)
+			continue
+		}
+		fn(path, v)
+	}
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apidefinition
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func newDeployment(mutate func(obj map[string]interface{})) *unstructured.Unstructured {
+	obj := map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name":            "web",
+			"namespace":       "default",
+			"resourceVersion": "42",
+			"labels":          map[string]interface{}{"app": "web"},
+			"annotations":     map[string]interface{}{"secret": "value"},
+		},
+		"spec": map[string]interface{}{
+			"replicas": int64(3),
+			"template": map[string]interface{}{"spec": map[string]interface{}{"serviceAccountName": "web"}},
+		},
+	}
+	if mutate != nil {
+		mutate(obj)
+	}
+	return &unstructured.Unstructured{Object: obj}
+}
+
+var replicasAndLabels = FieldRestriction{AllowedPaths: []string{"spec.replicas", "metadata.labels"}}
+
+func TestFieldRestrictionTransform(t *testing.T) {
+	obj := newDeployment(nil)
+	err := replicasAndLabels.Transform(context.Background(), schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}, obj)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name":            "web",
+			"namespace":       "default",
+			"resourceVersion": "42",
+			"labels":          map[string]interface{}{"app": "web"},
+		},
+		"spec": map[string]interface{}{
+			"replicas": int64(3),
+		},
+	}, obj.Object)
+}
+
+func TestFieldRestrictionRestrict(t *testing.T) {
+	redacted := func(mutate func(obj map[string]interface{})) *unstructured.Unstructured {
+		obj := newDeployment(nil)
+		require.NoError(t, replicasAndLabels.Transform(context.Background(), schema.GroupVersionResource{}, obj))
+		if mutate != nil {
+			mutate(obj.Object)
+		}
+		return obj
+	}
+
+	tests := []struct {
+		name          string
+		obj           *unstructured.Unstructured
+		old           *unstructured.Unstructured
+		wantForbidden []string
+		wantObj       *unstructured.Unstructured
+	}{
+		{
+			name: "create with allowed fields",
+			obj: &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata":   map[string]interface{}{"generateName": "web-", "labels": map[string]interface{}{"app": "web"}},
+				"spec":       map[string]interface{}{"replicas": int64(1)},
+			}},
+		},
+		{
+			name:          "create with other fields",
+			obj:           newDeployment(nil),
+			wantForbidden: []string{"metadata.annotations.secret", "spec.template.spec.serviceAccountName"},
+		},
+		{
+			name: "update of a redacted object restores the other fields",
+			obj: redacted(func(obj map[string]interface{}) {
+				obj["spec"].(map[string]interface{})["replicas"] = int64(5)
+			}),
+			old: newDeployment(nil),
+			wantObj: newDeployment(func(obj map[string]interface{}) {
+				obj["spec"].(map[string]interface{})["replicas"] = int64(5)
+			}),
+		},
+		{
+			name: "update removing an allowed field",
+			obj: redacted(func(obj map[string]interface{}) {
+				delete(obj["metadata"].(map[string]interface{}), "labels")
+			}),
+			old: newDeployment(nil),
+			wantObj: newDeployment(func(obj map[string]interface{}) {
+				delete(obj["metadata"].(map[string]interface{}), "labels")
+			}),
+		},
+		{
+			name:    "update keeping the other fields unchanged",
+			obj:     newDeployment(nil),
+			old:     newDeployment(nil),
+			wantObj: newDeployment(nil),
+		},
+		{
+			name: "update changing other fields",
+			obj: newDeployment(func(obj map[string]interface{}) {
+				obj["metadata"].(map[string]interface{})["annotations"] = map[string]interface{}{"secret": "changed", "new": "value"}
+			}),
+			old:           newDeployment(nil),
+			wantForbidden: []string{"metadata.annotations.new", "metadata.annotations.secret"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forbidden, err := replicasAndLabels.Restrict(tt.obj, tt.old)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantForbidden, forbidden)
+			if tt.wantObj != nil {
+				require.Equal(t, tt.wantObj, tt.obj)
+			}
+		})
+	}
+}
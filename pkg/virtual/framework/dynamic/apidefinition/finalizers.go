@@ -0,0 +1,71 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apidefinition
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kcp-dev/kcp/pkg/finalizers"
+	dynamiccontext "github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/context"
+)
+
+// FinalizerPolicy restricts the finalizers a client can add to and remove from
+// the objects of a resource, e.g. a provider accessing the objects of its
+// APIExport in consuming workspaces must not touch the finalizers of others.
+type FinalizerPolicy struct {
+	// OwnedDomains are the finalizer domains the client can add and remove,
+	// including their subdomains, e.g. "wildwest.dev" owns
+	// "wildwest.dev/cleanup" and "billing.wildwest.dev/cleanup". Finalizers
+	// without domain, like "kubernetes", are never owned.
+	OwnedDomains []string
+}
+
+// APIFinalizerPolicyGetter is optionally implemented by APIDefinitionSetGetters
+// to restrict the finalizers of a resource of an API domain. A nil policy
+// does not restrict the finalizers.
+type APIFinalizerPolicyGetter interface {
+	GetAPIFinalizerPolicy(ctx context.Context, key dynamiccontext.APIDomainKey, gvr schema.GroupVersionResource) *FinalizerPolicy
+}
+
+// Forbidden returns the finalizers obj adds or removes compared to old, which
+// is nil on creation, outside of the owned domains, sorted.
+func (p FinalizerPolicy) Forbidden(obj, old *unstructured.Unstructured) []string {
+	var oldFinalizers []string
+	if old != nil {
+		oldFinalizers = old.GetFinalizers()
+	}
+
+	var forbidden []string
+	for _, f := range finalizers.Changed(oldFinalizers, obj.GetFinalizers()) {
+		if !p.owns(f) {
+			forbidden = append(forbidden, f)
+		}
+	}
+	return forbidden
+}
+
+func (p FinalizerPolicy) owns(finalizer string) bool {
+	for _, domain := range p.OwnedDomains {
+		if finalizers.InDomain(finalizer, domain) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apidefinition
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestFinalizerPolicyForbidden(t *testing.T) {
+	withFinalizers := func(finalizers ...string) *unstructured.Unstructured {
+		obj := newDeployment(nil)
+		obj.SetFinalizers(finalizers)
+		return obj
+	}
+	policy := FinalizerPolicy{OwnedDomains: []string{"wildwest.dev"}}
+
+	tests := []struct {
+		name string
+		obj  *unstructured.Unstructured
+		old  *unstructured.Unstructured
+		want []string
+	}{
+		{
+			name: "create without finalizers",
+			obj:  withFinalizers(),
+		},
+		{
+			name: "create with owned finalizers",
+			obj:  withFinalizers("wildwest.dev/cleanup", "billing.wildwest.dev/cleanup"),
+		},
+		{
+			name: "create with other finalizers",
+			obj:  withFinalizers("wildwest.dev/cleanup", "example.com/cleanup", "kubernetes", "notwildwest.dev/cleanup"),
+			want: []string{"example.com/cleanup", "kubernetes", "notwildwest.dev/cleanup"},
+		},
+		{
+			name: "other finalizers kept",
+			obj:  withFinalizers("example.com/cleanup"),
+			old:  withFinalizers("example.com/cleanup", "wildwest.dev/cleanup"),
+		},
+		{
+			name: "other finalizer removed",
+			obj:  withFinalizers("wildwest.dev/cleanup"),
+			old:  withFinalizers("example.com/cleanup", "wildwest.dev/cleanup"),
+			want: []string{"example.com/cleanup"},
+		},
+		{
+			name: "other finalizer added",
+			obj:  withFinalizers("example.com/cleanup"),
+			old:  withFinalizers(),
+			want: []string{"example.com/cleanup"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, policy.Forbidden(tt.obj, tt.old))
+		})
+	}
+}
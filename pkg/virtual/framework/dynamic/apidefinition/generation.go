@@ -0,0 +1,85 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apidefinition
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	dynamiccontext "github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/context"
+)
+
+// GenerationPolicy enforces the generation convention of Kubernetes controllers
+// for a resource: metadata.generation is incremented on every change of the
+// spec, and controllers report the generation they acted on in
+// status.observedGeneration.
+type GenerationPolicy struct {
+	// RequireObservedGeneration rejects status updates which do not set
+	// status.observedGeneration, or set it beyond the generation of the object.
+	RequireObservedGeneration bool
+}
+
+// APIGenerationPolicyGetter is optionally implemented by APIDefinitionSetGetters
+// to enforce the generation convention for a resource of an API domain, e.g. for
+// bound APIs. A nil policy leaves the generation to the storage.
+type APIGenerationPolicyGetter interface {
+	GetAPIGenerationPolicy(ctx context.Context, key dynamiccontext.APIDomainKey, gvr schema.GroupVersionResource) *GenerationPolicy
+}
+
+// SetGeneration sets the generation of obj compared to old, which is nil on
+// creation: new objects start at generation 1, and updated objects get the next
+// generation if their spec changed, or keep the stored one otherwise. Changes
+// of the metadata or the status never increment the generation, independently
+// of whether the resource has a status subresource.
+func (p GenerationPolicy) SetGeneration(obj, old *unstructured.Unstructured) {
+	if old == nil {
+		obj.SetGeneration(1)
+		return
+	}
+
+	spec, _, _ := unstructured.NestedFieldNoCopy(obj.Object, "spec")
+	oldSpec, _, _ := unstructured.NestedFieldNoCopy(old.Object, "spec")
+	if equality.Semantic.DeepEqual(spec, oldSpec) {
+		obj.SetGeneration(old.GetGeneration())
+		return
+	}
+	obj.SetGeneration(old.GetGeneration() + 1)
+}
+
+// ValidateStatusUpdate checks that a status update of obj reports the
+// generation it observed, if required by the policy.
+func (p GenerationPolicy) ValidateStatusUpdate(obj *unstructured.Unstructured) error {
+	if !p.RequireObservedGeneration {
+		return nil
+	}
+
+	observed, found, err := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	if err != nil {
+		return fmt.Errorf("status.observedGeneration must be an integer: %w", err)
+	}
+	if !found {
+		return fmt.Errorf("status.observedGeneration must be set")
+	}
+	if observed < 0 || observed > obj.GetGeneration() {
+		return fmt.Errorf("status.observedGeneration %d must be between 0 and the generation %d", observed, obj.GetGeneration())
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apidefinition
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestGenerationPolicySetGeneration(t *testing.T) {
+	withGeneration := func(generation int64, mutate func(obj map[string]interface{})) *unstructured.Unstructured {
+		obj := newDeployment(mutate)
+		obj.SetGeneration(generation)
+		return obj
+	}
+
+	tests := []struct {
+		name string
+		obj  *unstructured.Unstructured
+		old  *unstructured.Unstructured
+		want int64
+	}{
+		{
+			name: "create starts at 1",
+			obj:  withGeneration(42, nil),
+			want: 1,
+		},
+		{
+			name: "spec change increments",
+			obj: withGeneration(0, func(obj map[string]interface{}) {
+				obj["spec"].(map[string]interface{})["replicas"] = int64(5)
+			}),
+			old:  withGeneration(3, nil),
+			want: 4,
+		},
+		{
+			name: "metadata change keeps generation",
+			obj: withGeneration(7, func(obj map[string]interface{}) {
+				obj["metadata"].(map[string]interface{})["labels"] = map[string]interface{}{"app": "other"}
+			}),
+			old:  withGeneration(3, nil),
+			want: 3,
+		},
+		{
+			name: "status change keeps generation",
+			obj: withGeneration(3, func(obj map[string]interface{}) {
+				obj["status"] = map[string]interface{}{"readyReplicas": int64(3)}
+			}),
+			old:  withGeneration(3, nil),
+			want: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			GenerationPolicy{}.SetGeneration(tt.obj, tt.old)
+			require.Equal(t, tt.want, tt.obj.GetGeneration())
+		})
+	}
+}
+
+func TestGenerationPolicyValidateStatusUpdate(t *testing.T) {
+	withStatus := func(status map[string]interface{}) *unstructured.Unstructured {
+		obj := newDeployment(func(obj map[string]interface{}) {
+			if status != nil {
+				obj["status"] = status
+			}
+		})
+		obj.SetGeneration(3)
+		return obj
+	}
+
+	tests := []struct {
+		name    string
+		policy  GenerationPolicy
+		obj     *unstructured.Unstructured
+		wantErr bool
+	}{
+		{
+			name: "not required",
+			obj:  withStatus(nil),
+		},
+		{
+			name:   "observed current generation",
+			policy: GenerationPolicy{RequireObservedGeneration: true},
+			obj:    withStatus(map[string]interface{}{"observedGeneration": int64(3)}),
+		},
+		{
+			name:   "observed older generation",
+			policy: GenerationPolicy{RequireObservedGeneration: true},
+			obj:    withStatus(map[string]interface{}{"observedGeneration": int64(2)}),
+		},
+		{
+			name:    "missing",
+			policy:  GenerationPolicy{RequireObservedGeneration: true},
+			obj:     withStatus(map[string]interface{}{"readyReplicas": int64(3)}),
+			wantErr: true,
+		},
+		{
+			name:    "not an integer",
+			policy:  GenerationPolicy{RequireObservedGeneration: true},
+			obj:     withStatus(map[string]interface{}{"observedGeneration": "3"}),
+			wantErr: true,
+		},
+		{
+			name:    "beyond generation",
+			policy:  GenerationPolicy{RequireObservedGeneration: true},
+			obj:     withStatus(map[string]interface{}{"observedGeneration": int64(4)}),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.ValidateStatusUpdate(tt.obj)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apidefinition
+
+import (
+	"context"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	dynamiccontext "github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/context"
+)
+
+// Transformer transforms the objects of a resource served by a dynamic virtual
+// workspace before they are returned to the client, e.g. to redact fields, to
+// rename labels or to inject fields computed for the requesting user, which is
+// available in the context.
+//
+// Transform mutates obj in place. obj is a copy of the stored object, i.e. it
+// can be changed freely. Transform is called for the objects returned by get,
+// list, watch, create, update, patch and delete requests.
+type Transformer interface {
+	Transform(ctx context.Context, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) error
+}
+
+// TransformerFunc is a function implementing Transformer.
+type TransformerFunc func(ctx context.Context, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) error
+
+// Transform calls f.
+func (f TransformerFunc) Transform(ctx context.Context, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) error {
+	return f(ctx, gvr, obj)
+}
+
+// Transformers is a chain of transformers, applied in order. It stops at the
+// first error.
+type Transformers []Transformer
+
+var _ Transformer = Transformers{}
+
+// Transform applies the transformers of the chain in order.
+func (ts Transformers) Transform(ctx context.Context, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) error {
+	for _, t := range ts {
+		if err := t.Transform(ctx, gvr, obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// APITransformersGetter is optionally implemented by APIDefinitionSetGetters to
+// return the transformers for the objects of a resource of an API domain.
+type APITransformersGetter interface {
+	GetAPITransformers(ctx context.Context, key dynamiccontext.APIDomainKey, gvr schema.GroupVersionResource) []Transformer
+}
+
+// RedactFields returns a transformer removing the given fields, in dot notation,
+// e.g. "data" or "spec.credentials".
+func RedactFields(fieldPaths ...string) Transformer {
+	return TransformerFunc(func(_ context.Context, _ schema.GroupVersionResource, obj *unstructured.Unstructured) error {
+		for _, p := range fieldPaths {
+			unstructured.RemoveNestedField(obj.Object, strings.Split(p, ".")...)
+		}
+		return nil
+	})
+}
+
+// RenameLabels returns a transformer renaming the label keys of objects, from
+// the keys to the values of renames. A label existing under the new key is
+// overwritten.
+func RenameLabels(renames map[string]string) Transformer {
+	return TransformerFunc(func(_ context.Context, _ schema.GroupVersionResource, obj *unstructured.Unstructured) error {
+		labels := obj.GetLabels()
+		if len(labels) == 0 {
+			return nil
+		}
+		renamed := make(map[string]string, len(labels))
+		for k, v := range labels {
+			if _, ok := renames[k]; !ok {
+				renamed[k] = v
+			}
+		}
+		for from, to := range renames {
+			if v, ok := labels[from]; ok {
+				renamed[to] = v
+			}
+		}
+		obj.SetLabels(renamed)
+		return nil
+	})
+}
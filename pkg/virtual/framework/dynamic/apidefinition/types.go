@@ -61,3 +61,9 @@ type APIDefinitionSet map[schema.GroupVersionResource]APIDefinition
 type APIDefinitionSetGetter interface {
 	GetAPIDefinitionSet(ctx context.Context, key dynamiccontext.APIDomainKey) (apis APIDefinitionSet, apisExist bool, err error)
 }
+
+// APIDefinitionSetSyncer is optionally implemented by APIDefinitionSetGetters
+// to tell whether their API definitions are up to date.
+type APIDefinitionSetSyncer interface {
+	HasSynced() bool
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apidefinition
+
+import (
+	"context"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	dynamiccontext "github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/context"
+)
+
+// Warning is a warning returned to the clients of an API in a Warning header,
+// e.g. to announce the deprecation of a version or a field before its removal.
+type Warning struct {
+	// Resource is the resource the warning applies to. An empty version matches
+	// all versions of the resource.
+	Resource schema.GroupVersionResource
+
+	// FieldPath restricts the warning to requests creating or updating objects
+	// which set the field, in dot notation, e.g. "spec.template". If empty, the
+	// warning applies to all requests.
+	FieldPath string
+
+	// Message is the text of the warning.
+	Message string
+}
+
+// AppliesTo returns whether the warning applies to requests to the given resource.
+func (w Warning) AppliesTo(gvr schema.GroupVersionResource) bool {
+	return w.Resource.Group == gvr.Group && w.Resource.Resource == gvr.Resource &&
+		(w.Resource.Version == "" || w.Resource.Version == gvr.Version)
+}
+
+// APIWarningsGetter is optionally implemented by APIDefinitionSetGetters to
+// return warnings for the APIs of an API domain, e.g. those of APIExports.
+type APIWarningsGetter interface {
+	GetAPIWarnings(ctx context.Context, key dynamiccontext.APIDomainKey, gvr schema.GroupVersionResource) []Warning
+}
+
+var (
+	builtinWarningsLock sync.RWMutex
+	builtinWarnings     []Warning
+)
+
+// RegisterWarnings registers warnings of kcp itself. They are returned for
+// requests to the resource in all dynamic virtual workspaces.
+func RegisterWarnings(warnings ...Warning) {
+	builtinWarningsLock.Lock()
+	defer builtinWarningsLock.Unlock()
+
+	builtinWarnings = append(builtinWarnings, warnings...)
+}
+
+// BuiltinWarnings returns the registered warnings of kcp itself applying to the
+// given resource.
+func BuiltinWarnings(gvr schema.GroupVersionResource) []Warning {
+	builtinWarningsLock.RLock()
+	defer builtinWarningsLock.RUnlock()
+
+	var ret []Warning
+	for _, w := range builtinWarnings {
+		if w.AppliesTo(gvr) {
+			ret = append(ret, w)
+		}
+	}
+	return ret
+}
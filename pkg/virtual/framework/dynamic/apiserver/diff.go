@@ -0,0 +1,102 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"net/http"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+
+	"github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/apidefinition"
+)
+
+// diffSubresource is served for every resource of the dynamic apiserver. Updates and patches,
+// including server-side apply, of the diff subresource return the object as it would be
+// stored, without persisting it.
+const diffSubresource = "diff"
+
+// serveDiff serves the diff subresource as dry-run request of the resource itself, such
+// that the returned object went through defaulting, admission and the transformers of the
+// virtual workspace like a real request. It requires the permissions of the real request.
+func (r *resourceHandler) serveDiff(w http.ResponseWriter, req *http.Request, requestInfo *apirequest.RequestInfo, apiDef apidefinition.APIDefinition, supportedTypes []string, admit admission.Interface, transformer apidefinition.Transformer) http.HandlerFunc {
+	gv := schema.GroupVersion{Group: requestInfo.APIGroup, Version: requestInfo.APIVersion}
+	if requestInfo.Verb != "update" && requestInfo.Verb != "patch" {
+		responsewriters.ErrorNegotiated(
+			apierrors.NewMethodNotSupported(schema.GroupResource{Group: requestInfo.APIGroup, Resource: requestInfo.Resource + "/" + diffSubresource}, requestInfo.Verb),
+			codecs, gv, w, req,
+		)
+		return nil
+	}
+
+	diffReq := diffRequest(req, requestInfo)
+	ctx := diffReq.Context()
+	resourceInfo, _ := apirequest.RequestInfoFrom(ctx)
+	user, _ := apirequest.UserFrom(ctx)
+	attributes := authorizer.AttributesRecord{
+		User:            user,
+		Verb:            resourceInfo.Verb,
+		Namespace:       resourceInfo.Namespace,
+		APIGroup:        resourceInfo.APIGroup,
+		APIVersion:      resourceInfo.APIVersion,
+		Resource:        resourceInfo.Resource,
+		Name:            resourceInfo.Name,
+		ResourceRequest: true,
+		Path:            resourceInfo.Path,
+	}
+	decision, reason, err := r.authorizer.Authorize(ctx, attributes)
+	if err != nil {
+		responsewriters.ErrorNegotiated(apierrors.NewInternalError(err), codecs, gv, w, req)
+		return nil
+	}
+	if decision != authorizer.DecisionAllow {
+		responsewriters.Forbidden(ctx, attributes, w, req, reason, codecs)
+		return nil
+	}
+
+	handler := r.serveResource(w, diffReq, resourceInfo, apiDef, supportedTypes, admit, transformer)
+	if handler == nil {
+		return nil
+	}
+	return func(w http.ResponseWriter, _ *http.Request) {
+		handler.ServeHTTP(w, diffReq)
+	}
+}
+
+// diffRequest returns a copy of the request of the diff subresource as dry-run request of
+// the resource.
+func diffRequest(req *http.Request, requestInfo *apirequest.RequestInfo) *http.Request {
+	resourceInfo := *requestInfo
+	resourceInfo.Subresource = ""
+	resourceInfo.Path = strings.TrimSuffix(requestInfo.Path, "/"+diffSubresource)
+	if n := len(requestInfo.Parts); n > 0 && requestInfo.Parts[n-1] == diffSubresource {
+		resourceInfo.Parts = append([]string(nil), requestInfo.Parts[:n-1]...)
+	}
+
+	ret := req.Clone(apirequest.WithRequestInfo(req.Context(), &resourceInfo))
+	ret.URL.Path = strings.TrimSuffix(ret.URL.Path, "/"+diffSubresource)
+	query := ret.URL.Query()
+	query.Set("dryRun", metav1.DryRunAll)
+	ret.URL.RawQuery = query.Encode()
+	return ret
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+
+	"github.com/kcp-dev/kcp/pkg/apis/apiresource/v1alpha1"
+	dyncamiccontext "github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/context"
+)
+
+func diffRequestInfo(verb string) *apirequest.RequestInfo {
+	return &apirequest.RequestInfo{
+		IsResourceRequest: true,
+		Path:              "/apis/custom/v1/namespaces/default/customresources/foo/diff",
+		Verb:              verb,
+		APIPrefix:         "apis",
+		APIGroup:          "custom",
+		APIVersion:        "v1",
+		Namespace:         "default",
+		Resource:          "customresources",
+		Subresource:       "diff",
+		Name:              "foo",
+		Parts:             []string{"customresources", "foo", "diff"},
+	}
+}
+
+func TestDiffRequest(t *testing.T) {
+	requestInfo := diffRequestInfo("patch")
+	req := httptest.NewRequest("PATCH", requestInfo.Path+"?fieldManager=pipeline&dryRun=", strings.NewReader("{}"))
+	req = req.WithContext(apirequest.WithRequestInfo(req.Context(), requestInfo))
+
+	diffReq := diffRequest(req, requestInfo)
+
+	require.Equal(t, "/apis/custom/v1/namespaces/default/customresources/foo", diffReq.URL.Path)
+	require.Equal(t, "All", diffReq.URL.Query().Get("dryRun"))
+	require.Equal(t, "pipeline", diffReq.URL.Query().Get("fieldManager"))
+
+	resourceInfo, ok := apirequest.RequestInfoFrom(diffReq.Context())
+	require.True(t, ok)
+	require.Equal(t, "", resourceInfo.Subresource)
+	require.Equal(t, "/apis/custom/v1/namespaces/default/customresources/foo", resourceInfo.Path)
+	require.Equal(t, []string{"customresources", "foo"}, resourceInfo.Parts)
+	require.Equal(t, "foo", resourceInfo.Name)
+
+	t.Log("The original request is unchanged")
+	require.Equal(t, "diff", requestInfo.Subresource)
+	require.Equal(t, []string{"customresources", "foo", "diff"}, requestInfo.Parts)
+	require.Equal(t, "/apis/custom/v1/namespaces/default/customresources/foo/diff", req.URL.Path)
+	require.Equal(t, "", req.URL.Query().Get("dryRun"))
+}
+
+func TestServeDiff(t *testing.T) {
+	apiSetRetriever := mockedAPISetRetriever{
+		schema.GroupVersionResource{Group: "custom", Version: "v1", Resource: "customresources"}: &mockedAPIDefinition{
+			apiResourceSpec: &v1alpha1.CommonAPIResourceSpec{
+				GroupVersion: v1alpha1.GroupVersion{Group: "custom", Version: "v1"},
+				Scope:        apiextensionsv1.NamespaceScoped,
+				CustomResourceDefinitionNames: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural:   "customresources",
+					Singular: "customresource",
+					Kind:     "CustomResource",
+					ListKind: "CustomResourceList",
+				},
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		method  string
+		verb    string
+		allowed bool
+
+		wantStatus     int
+		wantMessage    string
+		wantAuthorized []string
+	}{
+		"only updates and patches are served": {
+			method:      "GET",
+			verb:        "get",
+			wantStatus:  http.StatusMethodNotAllowed,
+			wantMessage: `get is not supported on resources of kind \"customresources/diff.custom\"`,
+		},
+		"previews require the permission of the real request": {
+			method:         "PATCH",
+			verb:           "patch",
+			wantStatus:     http.StatusForbidden,
+			wantAuthorized: []string{"patch customresources default/foo"},
+		},
+		"previews are served by the resource": {
+			method:         "PUT",
+			verb:           "update",
+			allowed:        true,
+			wantAuthorized: []string{"update customresources default/foo"},
+			// the mocked API definition has no storage
+			wantStatus:  http.StatusMethodNotAllowed,
+			wantMessage: `update is not supported on resources of kind \"customresources.custom\"`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var authorized []string
+			handler := &resourceHandler{
+				apiSetRetriever: apiSetRetriever,
+				authorizer: authorizer.AuthorizerFunc(func(ctx context.Context, attr authorizer.Attributes) (authorizer.Decision, string, error) {
+					require.Empty(t, attr.GetSubresource())
+					authorized = append(authorized, attr.GetVerb()+" "+attr.GetResource()+" "+attr.GetNamespace()+"/"+attr.GetName())
+					if tc.allowed {
+						return authorizer.DecisionAllow, "", nil
+					}
+					return authorizer.DecisionNoOpinion, "", nil
+				}),
+			}
+
+			requestInfo := diffRequestInfo(tc.verb)
+			req := httptest.NewRequest(tc.method, requestInfo.Path, strings.NewReader("{}"))
+			req.Header.Set("Accept", "application/json")
+			ctx := dyncamiccontext.WithAPIDomainKey(req.Context(), "domain")
+			ctx = apirequest.WithUser(ctx, &user.DefaultInfo{Name: "pipeline"})
+			req = req.WithContext(apirequest.WithRequestInfo(ctx, requestInfo))
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			result := recorder.Result()
+			content, err := ioutil.ReadAll(result.Body)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantStatus, result.StatusCode, string(content))
+			require.Contains(t, string(content), tc.wantMessage)
+			require.Equal(t, tc.wantAuthorized, authorized)
+		})
+	}
+}
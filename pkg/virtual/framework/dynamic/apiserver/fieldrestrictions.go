@@ -0,0 +1,85 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/admission"
+
+	"github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/apidefinition"
+	dynamiccontext "github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/context"
+)
+
+// fieldRestrictions returns the field restrictions of the API domain for the resource.
+func (r *resourceHandler) fieldRestrictions(ctx context.Context, key dynamiccontext.APIDomainKey, gvr schema.GroupVersionResource) []apidefinition.FieldRestriction {
+	if getter, ok := r.apiSetRetriever.(apidefinition.APIFieldRestrictionsGetter); ok {
+		return getter.GetAPIFieldRestrictions(ctx, key, gvr)
+	}
+	return nil
+}
+
+// fieldRestrictionAdmission wraps the admission of a request to reject objects
+// created or updated with fields outside the allowed paths, and to restore
+// the redacted fields of updated objects.
+type fieldRestrictionAdmission struct {
+	delegate     admission.Interface
+	restrictions []apidefinition.FieldRestriction
+}
+
+var _ admission.MutationInterface = &fieldRestrictionAdmission{}
+var _ admission.ValidationInterface = &fieldRestrictionAdmission{}
+
+func (a *fieldRestrictionAdmission) Handles(operation admission.Operation) bool {
+	return true
+}
+
+// Admit restricts the object before mutating admission, i.e. only the fields
+// set by the client are checked.
+func (a *fieldRestrictionAdmission) Admit(ctx context.Context, attr admission.Attributes, o admission.ObjectInterfaces) error {
+	if attr.GetOperation() == admission.Create || attr.GetOperation() == admission.Update {
+		if obj, ok := attr.GetObject().(*unstructured.Unstructured); ok {
+			old, _ := attr.GetOldObject().(*unstructured.Unstructured)
+			for _, r := range a.restrictions {
+				forbidden, err := r.Restrict(obj, old)
+				if err != nil {
+					return apierrors.NewInternalError(err)
+				}
+				if len(forbidden) > 0 {
+					return admission.NewForbidden(attr, fmt.Errorf("fields outside of the allowed paths cannot be set or changed: %s", strings.Join(forbidden, ", ")))
+				}
+			}
+		}
+	}
+
+	if mutating, ok := a.delegate.(admission.MutationInterface); ok && mutating.Handles(attr.GetOperation()) {
+		return mutating.Admit(ctx, attr, o)
+	}
+	return nil
+}
+
+func (a *fieldRestrictionAdmission) Validate(ctx context.Context, attr admission.Attributes, o admission.ObjectInterfaces) error {
+	if validating, ok := a.delegate.(admission.ValidationInterface); ok && validating.Handles(attr.GetOperation()) {
+		return validating.Validate(ctx, attr, o)
+	}
+	return nil
+}
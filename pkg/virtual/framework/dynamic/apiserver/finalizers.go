@@ -0,0 +1,78 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/admission"
+
+	"github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/apidefinition"
+	dynamiccontext "github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/context"
+)
+
+// finalizerPolicy returns the finalizer policy of the API domain for the resource.
+func (r *resourceHandler) finalizerPolicy(ctx context.Context, key dynamiccontext.APIDomainKey, gvr schema.GroupVersionResource) *apidefinition.FinalizerPolicy {
+	if getter, ok := r.apiSetRetriever.(apidefinition.APIFinalizerPolicyGetter); ok {
+		return getter.GetAPIFinalizerPolicy(ctx, key, gvr)
+	}
+	return nil
+}
+
+// finalizerPolicyAdmission wraps the admission of a request to reject objects
+// created or updated with finalizers added or removed outside of the owned
+// domains.
+type finalizerPolicyAdmission struct {
+	delegate admission.Interface
+	policy   apidefinition.FinalizerPolicy
+}
+
+var _ admission.MutationInterface = &finalizerPolicyAdmission{}
+var _ admission.ValidationInterface = &finalizerPolicyAdmission{}
+
+func (a *finalizerPolicyAdmission) Handles(operation admission.Operation) bool {
+	return true
+}
+
+func (a *finalizerPolicyAdmission) Admit(ctx context.Context, attr admission.Attributes, o admission.ObjectInterfaces) error {
+	if mutating, ok := a.delegate.(admission.MutationInterface); ok && mutating.Handles(attr.GetOperation()) {
+		return mutating.Admit(ctx, attr, o)
+	}
+	return nil
+}
+
+// Validate checks the finalizers after mutating admission, i.e. as they are
+// going to be stored.
+func (a *finalizerPolicyAdmission) Validate(ctx context.Context, attr admission.Attributes, o admission.ObjectInterfaces) error {
+	if attr.GetOperation() == admission.Create || attr.GetOperation() == admission.Update {
+		if obj, ok := attr.GetObject().(*unstructured.Unstructured); ok {
+			old, _ := attr.GetOldObject().(*unstructured.Unstructured)
+			if forbidden := a.policy.Forbidden(obj, old); len(forbidden) > 0 {
+				return admission.NewForbidden(attr, fmt.Errorf("finalizers outside of the owned domains cannot be added or removed: %s", strings.Join(forbidden, ", ")))
+			}
+		}
+	}
+
+	if validating, ok := a.delegate.(admission.ValidationInterface); ok && validating.Handles(attr.GetOperation()) {
+		return validating.Validate(ctx, attr, o)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/admission"
+
+	"github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/apidefinition"
+	dynamiccontext "github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/context"
+)
+
+// generationPolicy returns the generation policy of the API domain for the resource.
+func (r *resourceHandler) generationPolicy(ctx context.Context, key dynamiccontext.APIDomainKey, gvr schema.GroupVersionResource) *apidefinition.GenerationPolicy {
+	if getter, ok := r.apiSetRetriever.(apidefinition.APIGenerationPolicyGetter); ok {
+		return getter.GetAPIGenerationPolicy(ctx, key, gvr)
+	}
+	return nil
+}
+
+// generationAdmission wraps the admission of a request to set the generation of
+// objects created or updated, and to check the observed generation of status
+// updates.
+type generationAdmission struct {
+	delegate admission.Interface
+	policy   apidefinition.GenerationPolicy
+}
+
+var _ admission.MutationInterface = &generationAdmission{}
+var _ admission.ValidationInterface = &generationAdmission{}
+
+func (a *generationAdmission) Handles(operation admission.Operation) bool {
+	return true
+}
+
+// Admit sets the generation after mutating admission, i.e. also spec changes
+// by mutating plugins increment it.
+func (a *generationAdmission) Admit(ctx context.Context, attr admission.Attributes, o admission.ObjectInterfaces) error {
+	if mutating, ok := a.delegate.(admission.MutationInterface); ok && mutating.Handles(attr.GetOperation()) {
+		if err := mutating.Admit(ctx, attr, o); err != nil {
+			return err
+		}
+	}
+
+	if attr.GetSubresource() != "" {
+		return nil
+	}
+	if attr.GetOperation() == admission.Create || attr.GetOperation() == admission.Update {
+		if obj, ok := attr.GetObject().(*unstructured.Unstructured); ok {
+			old, _ := attr.GetOldObject().(*unstructured.Unstructured)
+			a.policy.SetGeneration(obj, old)
+		}
+	}
+	return nil
+}
+
+func (a *generationAdmission) Validate(ctx context.Context, attr admission.Attributes, o admission.ObjectInterfaces) error {
+	if attr.GetSubresource() == "status" && attr.GetOperation() == admission.Update {
+		if obj, ok := attr.GetObject().(*unstructured.Unstructured); ok {
+			if err := a.policy.ValidateStatusUpdate(obj); err != nil {
+				return admission.NewForbidden(attr, err)
+			}
+		}
+	}
+
+	if validating, ok := a.delegate.(admission.ValidationInterface); ok && validating.Handles(attr.GetOperation()) {
+		return validating.Validate(ctx, attr, o)
+	}
+	return nil
+}
@@ -144,16 +144,33 @@ func (r *resourceHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	apiDef, hasAPIDef := apiDefs[schema.GroupVersionResource{
+	gvr := schema.GroupVersionResource{
 		Group:    requestInfo.APIGroup,
 		Version:  requestInfo.APIVersion,
 		Resource: requestInfo.Resource,
-	}]
+	}
+	apiDef, hasAPIDef := apiDefs[gvr]
 	if !hasAPIDef {
 		r.delegate.ServeHTTP(w, req)
 		return
 	}
 
+	admit := r.admission
+	if fieldWarnings := r.addWarnings(ctx, locationKey, gvr); len(fieldWarnings) > 0 {
+		admit = &fieldWarningAdmission{delegate: r.admission, warnings: fieldWarnings}
+	}
+	if policy := r.generationPolicy(ctx, locationKey, gvr); policy != nil {
+		admit = &generationAdmission{delegate: admit, policy: *policy}
+	}
+	restrictions := r.fieldRestrictions(ctx, locationKey, gvr)
+	if len(restrictions) > 0 {
+		admit = &fieldRestrictionAdmission{delegate: admit, restrictions: restrictions}
+	}
+	if policy := r.finalizerPolicy(ctx, locationKey, gvr); policy != nil {
+		admit = &finalizerPolicyAdmission{delegate: admit, policy: *policy}
+	}
+	transformer := r.transformer(ctx, locationKey, gvr, restrictions)
+
 	apiResourceSpec := apiDef.GetAPIResourceSpec()
 
 	verb := strings.ToUpper(requestInfo.Verb)
@@ -185,6 +202,10 @@ func (r *resourceHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 			)
 			return
 		}
+	} else if requestScope := apiDef.GetRequestScope(); requestScope != nil && requestScope.OpenapiModels != nil {
+		// Strategic merge patches of other resources merge the lists according to their
+		// list type, see addPatchStrategies.
+		supportedTypes = append(supportedTypes, string(types.StrategicMergePatchType))
 	}
 
 	if utilfeature.DefaultFeatureGate.Enabled(features.ServerSideApply) {
@@ -195,9 +216,11 @@ func (r *resourceHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	subresources := apiResourceSpec.SubResources
 	switch {
 	case subresource == "status" && subresources != nil && subresources.Contains("status"):
-		handlerFunc = r.serveStatus(w, req, requestInfo, apiDef, supportedTypes)
+		handlerFunc = r.serveStatus(w, req, requestInfo, apiDef, supportedTypes, admit, transformer)
+	case subresource == diffSubresource:
+		handlerFunc = r.serveDiff(w, req, requestInfo, apiDef, supportedTypes, admit, transformer)
 	case len(subresource) == 0:
-		handlerFunc = r.serveResource(w, req, requestInfo, apiDef, supportedTypes)
+		handlerFunc = r.serveResource(w, req, requestInfo, apiDef, supportedTypes, admit, transformer)
 	default:
 		responsewriters.ErrorNegotiated(
 			apierrors.NewNotFound(schema.GroupResource{Group: requestInfo.APIGroup, Resource: requestInfo.Resource}, requestInfo.Name),
@@ -212,50 +235,51 @@ func (r *resourceHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	}
 }
 
-func (r *resourceHandler) serveResource(w http.ResponseWriter, req *http.Request, requestInfo *apirequest.RequestInfo, apiDef apidefinition.APIDefinition, supportedTypes []string) http.HandlerFunc {
+func (r *resourceHandler) serveResource(w http.ResponseWriter, req *http.Request, requestInfo *apirequest.RequestInfo, apiDef apidefinition.APIDefinition, supportedTypes []string, admit admission.Interface, transformer apidefinition.Transformer) http.HandlerFunc {
 	requestScope := apiDef.GetRequestScope()
 	storage := apiDef.GetStorage()
+	gvr := schema.GroupVersionResource{Group: requestInfo.APIGroup, Version: requestInfo.APIVersion, Resource: requestInfo.Resource}
 
 	switch requestInfo.Verb {
 	case "get":
 		if storage, isAble := storage.(rest.Getter); isAble {
-			return handlers.GetResource(storage, requestScope)
+			return handlers.GetResource(transformingGetter(storage, transformer, gvr), requestScope)
 		}
 	case "list":
 		if listerStorage, isAble := storage.(rest.Lister); isAble {
 			if watcherStorage, isAble := storage.(rest.Watcher); isAble {
 				forceWatch := false
-				return handlers.ListResource(listerStorage, watcherStorage, requestScope, forceWatch, r.minRequestTimeout)
+				return handlers.ListResource(transformingLister(listerStorage, transformer, gvr), transformingWatcher(watcherStorage, transformer, gvr), requestScope, forceWatch, r.minRequestTimeout)
 			}
 		}
 	case "watch":
 		if listerStorage, isAble := storage.(rest.Lister); isAble {
 			if watcherStorage, isAble := storage.(rest.Watcher); isAble {
 				forceWatch := true
-				return handlers.ListResource(listerStorage, watcherStorage, requestScope, forceWatch, r.minRequestTimeout)
+				return handlers.ListResource(transformingLister(listerStorage, transformer, gvr), transformingWatcher(watcherStorage, transformer, gvr), requestScope, forceWatch, r.minRequestTimeout)
 			}
 		}
 	case "create":
 		if storage, isAble := storage.(rest.Creater); isAble {
-			return handlers.CreateResource(storage, requestScope, r.admission)
+			return handlers.CreateResource(transformingCreater(storage, transformer, gvr), requestScope, admit)
 		}
 	case "update":
 		if storage, isAble := storage.(rest.Updater); isAble {
-			return handlers.UpdateResource(storage, requestScope, r.admission)
+			return handlers.UpdateResource(transformingUpdater(storage, transformer, gvr), requestScope, admit)
 		}
 	case "patch":
 		if storage, isAble := storage.(rest.Patcher); isAble {
-			return handlers.PatchResource(storage, requestScope, r.admission, supportedTypes)
+			return handlers.PatchResource(transformingPatcher(storage, transformer, gvr), requestScope, admit, supportedTypes)
 		}
 	case "delete":
 		if storage, isAble := storage.(rest.GracefulDeleter); isAble {
 			allowsOptions := true
-			return handlers.DeleteResource(storage, allowsOptions, requestScope, r.admission)
+			return handlers.DeleteResource(transformingDeleter(storage, transformer, gvr), allowsOptions, requestScope, admit)
 		}
 	case "deletecollection":
 		if storage, isAble := storage.(rest.CollectionDeleter); isAble {
 			checkBody := true
-			return handlers.DeleteCollection(storage, checkBody, requestScope, r.admission)
+			return handlers.DeleteCollection(transformingCollectionDeleter(storage, transformer, gvr), checkBody, requestScope, admit)
 		}
 	}
 	responsewriters.ErrorNegotiated(
@@ -265,22 +289,23 @@ func (r *resourceHandler) serveResource(w http.ResponseWriter, req *http.Request
 	return nil
 }
 
-func (r *resourceHandler) serveStatus(w http.ResponseWriter, req *http.Request, requestInfo *apirequest.RequestInfo, apiDef apidefinition.APIDefinition, supportedTypes []string) http.HandlerFunc {
+func (r *resourceHandler) serveStatus(w http.ResponseWriter, req *http.Request, requestInfo *apirequest.RequestInfo, apiDef apidefinition.APIDefinition, supportedTypes []string, admit admission.Interface, transformer apidefinition.Transformer) http.HandlerFunc {
 	requestScope := apiDef.GetSubResourceRequestScope("status")
 	storage := apiDef.GetSubResourceStorage("status")
+	gvr := schema.GroupVersionResource{Group: requestInfo.APIGroup, Version: requestInfo.APIVersion, Resource: requestInfo.Resource}
 
 	switch requestInfo.Verb {
 	case "get":
 		if storage, isAble := storage.(rest.Getter); isAble {
-			return handlers.GetResource(storage, requestScope)
+			return handlers.GetResource(transformingGetter(storage, transformer, gvr), requestScope)
 		}
 	case "update":
 		if storage, isAble := storage.(rest.Updater); isAble {
-			return handlers.UpdateResource(storage, requestScope, r.admission)
+			return handlers.UpdateResource(transformingUpdater(storage, transformer, gvr), requestScope, admit)
 		}
 	case "patch":
 		if storage, isAble := storage.(rest.Patcher); isAble {
-			return handlers.PatchResource(storage, requestScope, r.admission, supportedTypes)
+			return handlers.PatchResource(transformingPatcher(storage, transformer, gvr), requestScope, admit, supportedTypes)
 		}
 	}
 	responsewriters.ErrorNegotiated(
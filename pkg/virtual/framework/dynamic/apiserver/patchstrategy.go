@@ -0,0 +1,77 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+const (
+	listTypeExtension      = "x-kubernetes-list-type"
+	listMapKeysExtension   = "x-kubernetes-list-map-keys"
+	patchStrategyExtension = "x-kubernetes-patch-strategy"
+	patchMergeKeyExtension = "x-kubernetes-patch-merge-key"
+)
+
+// addPatchStrategies derives the strategic merge patch annotations of the lists in the
+// definitions of s from their list type, such that strategic merge patches merge them
+// the same way server-side apply does, instead of replacing them:
+//
+//   - lists of type set are merged,
+//   - lists of type map with a single key are merged by that key.
+//
+// Maps with several keys cannot be expressed for strategic merge patches and are
+// replaced, like atomic lists. Explicit patch strategies are kept.
+func addPatchStrategies(s *spec.Swagger) {
+	for name, def := range s.Definitions {
+		addPatchStrategiesToSchema(&def)
+		s.Definitions[name] = def
+	}
+}
+
+func addPatchStrategiesToSchema(s *spec.Schema) {
+	if s == nil {
+		return
+	}
+
+	if _, found := s.Extensions.GetString(patchStrategyExtension); !found {
+		listType, _ := s.Extensions.GetString(listTypeExtension)
+		switch listType {
+		case "set":
+			s.AddExtension(patchStrategyExtension, "merge")
+		case "map":
+			if keys, _ := s.Extensions.GetStringSlice(listMapKeysExtension); len(keys) == 1 {
+				s.AddExtension(patchStrategyExtension, "merge")
+				s.AddExtension(patchMergeKeyExtension, keys[0])
+			}
+		}
+	}
+
+	for name, prop := range s.Properties {
+		addPatchStrategiesToSchema(&prop)
+		s.Properties[name] = prop
+	}
+	if s.Items != nil {
+		addPatchStrategiesToSchema(s.Items.Schema)
+		for i := range s.Items.Schemas {
+			addPatchStrategiesToSchema(&s.Items.Schemas[i])
+		}
+	}
+	if s.AdditionalProperties != nil {
+		addPatchStrategiesToSchema(s.AdditionalProperties.Schema)
+	}
+}
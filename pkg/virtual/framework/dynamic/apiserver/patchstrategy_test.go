@@ -0,0 +1,103 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func TestAddPatchStrategies(t *testing.T) {
+	list := func(extensions map[string]interface{}) spec.Schema {
+		s := *spec.ArrayProperty(spec.StringProperty())
+		for k, v := range extensions {
+			s.AddExtension(k, v)
+		}
+		return s
+	}
+	patchExtensions := func(s spec.Schema) map[string]interface{} {
+		ret := map[string]interface{}{}
+		for _, k := range []string{patchStrategyExtension, patchMergeKeyExtension} {
+			if v, found := s.Extensions.GetString(k); found {
+				ret[k] = v
+			}
+		}
+		return ret
+	}
+
+	tests := []struct {
+		name       string
+		extensions map[string]interface{}
+		want       map[string]interface{}
+	}{
+		{
+			name:       "set is merged",
+			extensions: map[string]interface{}{listTypeExtension: "set"},
+			want:       map[string]interface{}{patchStrategyExtension: "merge"},
+		},
+		{
+			name:       "map with a single key is merged by key",
+			extensions: map[string]interface{}{listTypeExtension: "map", listMapKeysExtension: []interface{}{"name"}},
+			want:       map[string]interface{}{patchStrategyExtension: "merge", patchMergeKeyExtension: "name"},
+		},
+		{
+			name:       "map with several keys is replaced",
+			extensions: map[string]interface{}{listTypeExtension: "map", listMapKeysExtension: []interface{}{"name", "protocol"}},
+			want:       map[string]interface{}{},
+		},
+		{
+			name:       "atomic is replaced",
+			extensions: map[string]interface{}{listTypeExtension: "atomic"},
+			want:       map[string]interface{}{},
+		},
+		{
+			name:       "no list type is replaced",
+			extensions: nil,
+			want:       map[string]interface{}{},
+		},
+		{
+			name:       "explicit strategy is kept",
+			extensions: map[string]interface{}{listTypeExtension: "set", patchStrategyExtension: "replace"},
+			want:       map[string]interface{}{patchStrategyExtension: "replace"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := *spec.MapProperty(nil)
+			items.Properties = map[string]spec.Schema{"nested": list(tt.extensions)}
+			s := &spec.Swagger{SwaggerProps: spec.SwaggerProps{Definitions: spec.Definitions{
+				"io.example.v1.Widget": {SchemaProps: spec.SchemaProps{
+					Properties: map[string]spec.Schema{
+						"spec": {SchemaProps: spec.SchemaProps{Properties: map[string]spec.Schema{
+							"list":  list(tt.extensions),
+							"items": *spec.ArrayProperty(&items),
+						}}},
+					},
+				}},
+			}}}
+
+			addPatchStrategies(s)
+
+			widgetSpec := s.Definitions["io.example.v1.Widget"].Properties["spec"]
+			require.Equal(t, tt.want, patchExtensions(widgetSpec.Properties["list"]), "top-level list")
+			require.Equal(t, tt.want, patchExtensions(widgetSpec.Properties["items"].Items.Schema.Properties["nested"]), "nested list")
+		})
+	}
+}
@@ -101,6 +101,7 @@ func CreateServingInfoFor(genericConfig genericapiserver.CompletedConfig, logica
 	if err != nil {
 		return nil, err
 	}
+	addPatchStrategies(s)
 
 	var modelsByGKV openapi.ModelsByGKV
 
@@ -0,0 +1,264 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metainternalversion "k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apiserver/pkg/registry/rest"
+
+	"github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/apidefinition"
+	dynamiccontext "github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/context"
+)
+
+// transformer returns the chain of transformers of the API domain for the
+// resource, or nil if there are none. The field restrictions redact the
+// objects first, i.e. the transformers of the API domain can still add fields.
+func (r *resourceHandler) transformer(ctx context.Context, key dynamiccontext.APIDomainKey, gvr schema.GroupVersionResource, restrictions []apidefinition.FieldRestriction) apidefinition.Transformer {
+	var transformers []apidefinition.Transformer
+	for _, restriction := range restrictions {
+		transformers = append(transformers, restriction)
+	}
+	if getter, ok := r.apiSetRetriever.(apidefinition.APITransformersGetter); ok {
+		transformers = append(transformers, getter.GetAPITransformers(ctx, key, gvr)...)
+	}
+	if len(transformers) == 0 {
+		return nil
+	}
+	return apidefinition.Transformers(transformers)
+}
+
+// transformObject applies the transformer to a copy of the returned object, or
+// to copies of the items of a returned list. Other objects, e.g. the status of
+// a deletion, are returned unchanged.
+func transformObject(ctx context.Context, transformer apidefinition.Transformer, gvr schema.GroupVersionResource, obj runtime.Object) (runtime.Object, error) {
+	switch obj := obj.(type) {
+	case *unstructured.Unstructured:
+		transformed := obj.DeepCopy()
+		if err := transformer.Transform(ctx, gvr, transformed); err != nil {
+			return nil, apierrors.NewInternalError(fmt.Errorf("failed to transform %s %q: %w", gvr.GroupResource(), obj.GetName(), err))
+		}
+		return transformed, nil
+	case *unstructured.UnstructuredList:
+		transformed := obj.DeepCopy()
+		for i := range transformed.Items {
+			if err := transformer.Transform(ctx, gvr, &transformed.Items[i]); err != nil {
+				return nil, apierrors.NewInternalError(fmt.Errorf("failed to transform %s %q: %w", gvr.GroupResource(), transformed.Items[i].GetName(), err))
+			}
+		}
+		return transformed, nil
+	default:
+		return obj, nil
+	}
+}
+
+// transformingGetter returns getter, with the transformer applied to the
+// returned objects if not nil. The same holds for the other transforming*
+// functions.
+func transformingGetter(getter rest.Getter, transformer apidefinition.Transformer, gvr schema.GroupVersionResource) rest.Getter {
+	if transformer == nil {
+		return getter
+	}
+	return &transformedGetter{Getter: getter, transformer: transformer, gvr: gvr}
+}
+
+type transformedGetter struct {
+	rest.Getter
+	transformer apidefinition.Transformer
+	gvr         schema.GroupVersionResource
+}
+
+func (s *transformedGetter) Get(ctx context.Context, name string, options *metav1.GetOptions) (runtime.Object, error) {
+	obj, err := s.Getter.Get(ctx, name, options)
+	if err != nil {
+		return nil, err
+	}
+	return transformObject(ctx, s.transformer, s.gvr, obj)
+}
+
+func transformingLister(lister rest.Lister, transformer apidefinition.Transformer, gvr schema.GroupVersionResource) rest.Lister {
+	if transformer == nil {
+		return lister
+	}
+	return &transformedLister{Lister: lister, transformer: transformer, gvr: gvr}
+}
+
+type transformedLister struct {
+	rest.Lister
+	transformer apidefinition.Transformer
+	gvr         schema.GroupVersionResource
+}
+
+func (s *transformedLister) List(ctx context.Context, options *metainternalversion.ListOptions) (runtime.Object, error) {
+	obj, err := s.Lister.List(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	return transformObject(ctx, s.transformer, s.gvr, obj)
+}
+
+func transformingWatcher(watcher rest.Watcher, transformer apidefinition.Transformer, gvr schema.GroupVersionResource) rest.Watcher {
+	if transformer == nil {
+		return watcher
+	}
+	return &transformedWatcher{Watcher: watcher, transformer: transformer, gvr: gvr}
+}
+
+type transformedWatcher struct {
+	rest.Watcher
+	transformer apidefinition.Transformer
+	gvr         schema.GroupVersionResource
+}
+
+// Watch transforms the objects of added, modified and deleted events. A failed
+// transformation is sent as an error event instead of the object.
+func (s *transformedWatcher) Watch(ctx context.Context, options *metainternalversion.ListOptions) (watch.Interface, error) {
+	w, err := s.Watcher.Watch(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	return watch.Filter(w, func(in watch.Event) (watch.Event, bool) {
+		if in.Type == watch.Bookmark || in.Type == watch.Error {
+			return in, true
+		}
+		obj, err := transformObject(ctx, s.transformer, s.gvr, in.Object)
+		if err != nil {
+			status := apierrors.NewInternalError(err).Status()
+			return watch.Event{Type: watch.Error, Object: &status}, true
+		}
+		in.Object = obj
+		return in, true
+	}), nil
+}
+
+func transformingCreater(creater rest.Creater, transformer apidefinition.Transformer, gvr schema.GroupVersionResource) rest.Creater {
+	if transformer == nil {
+		return creater
+	}
+	return &transformedCreater{Creater: creater, transformer: transformer, gvr: gvr}
+}
+
+type transformedCreater struct {
+	rest.Creater
+	transformer apidefinition.Transformer
+	gvr         schema.GroupVersionResource
+}
+
+func (s *transformedCreater) Create(ctx context.Context, obj runtime.Object, createValidation rest.ValidateObjectFunc, options *metav1.CreateOptions) (runtime.Object, error) {
+	created, err := s.Creater.Create(ctx, obj, createValidation, options)
+	if err != nil {
+		return nil, err
+	}
+	return transformObject(ctx, s.transformer, s.gvr, created)
+}
+
+func transformingUpdater(updater rest.Updater, transformer apidefinition.Transformer, gvr schema.GroupVersionResource) rest.Updater {
+	if transformer == nil {
+		return updater
+	}
+	return &transformedUpdater{Updater: updater, transformer: transformer, gvr: gvr}
+}
+
+type transformedUpdater struct {
+	rest.Updater
+	transformer apidefinition.Transformer
+	gvr         schema.GroupVersionResource
+}
+
+func (s *transformedUpdater) Update(ctx context.Context, name string, objInfo rest.UpdatedObjectInfo, createValidation rest.ValidateObjectFunc, updateValidation rest.ValidateObjectUpdateFunc, forceAllowCreate bool, options *metav1.UpdateOptions) (runtime.Object, bool, error) {
+	obj, created, err := s.Updater.Update(ctx, name, objInfo, createValidation, updateValidation, forceAllowCreate, options)
+	if err != nil {
+		return nil, false, err
+	}
+	obj, err = transformObject(ctx, s.transformer, s.gvr, obj)
+	return obj, created, err
+}
+
+// transformingPatcher only transforms the result of the update. Patches are
+// applied to the stored object, not to the transformed one.
+func transformingPatcher(patcher rest.Patcher, transformer apidefinition.Transformer, gvr schema.GroupVersionResource) rest.Patcher {
+	if transformer == nil {
+		return patcher
+	}
+	return &transformedPatcher{Patcher: patcher, transformer: transformer, gvr: gvr}
+}
+
+type transformedPatcher struct {
+	rest.Patcher
+	transformer apidefinition.Transformer
+	gvr         schema.GroupVersionResource
+}
+
+func (s *transformedPatcher) Update(ctx context.Context, name string, objInfo rest.UpdatedObjectInfo, createValidation rest.ValidateObjectFunc, updateValidation rest.ValidateObjectUpdateFunc, forceAllowCreate bool, options *metav1.UpdateOptions) (runtime.Object, bool, error) {
+	obj, created, err := s.Patcher.Update(ctx, name, objInfo, createValidation, updateValidation, forceAllowCreate, options)
+	if err != nil {
+		return nil, false, err
+	}
+	obj, err = transformObject(ctx, s.transformer, s.gvr, obj)
+	return obj, created, err
+}
+
+func transformingDeleter(deleter rest.GracefulDeleter, transformer apidefinition.Transformer, gvr schema.GroupVersionResource) rest.GracefulDeleter {
+	if transformer == nil {
+		return deleter
+	}
+	return &transformedDeleter{GracefulDeleter: deleter, transformer: transformer, gvr: gvr}
+}
+
+type transformedDeleter struct {
+	rest.GracefulDeleter
+	transformer apidefinition.Transformer
+	gvr         schema.GroupVersionResource
+}
+
+func (s *transformedDeleter) Delete(ctx context.Context, name string, deleteValidation rest.ValidateObjectFunc, options *metav1.DeleteOptions) (runtime.Object, bool, error) {
+	obj, deleted, err := s.GracefulDeleter.Delete(ctx, name, deleteValidation, options)
+	if err != nil {
+		return nil, false, err
+	}
+	obj, err = transformObject(ctx, s.transformer, s.gvr, obj)
+	return obj, deleted, err
+}
+
+func transformingCollectionDeleter(deleter rest.CollectionDeleter, transformer apidefinition.Transformer, gvr schema.GroupVersionResource) rest.CollectionDeleter {
+	if transformer == nil {
+		return deleter
+	}
+	return &transformedCollectionDeleter{CollectionDeleter: deleter, transformer: transformer, gvr: gvr}
+}
+
+type transformedCollectionDeleter struct {
+	rest.CollectionDeleter
+	transformer apidefinition.Transformer
+	gvr         schema.GroupVersionResource
+}
+
+func (s *transformedCollectionDeleter) DeleteCollection(ctx context.Context, deleteValidation rest.ValidateObjectFunc, options *metav1.DeleteOptions, listOptions *metainternalversion.ListOptions) (runtime.Object, error) {
+	obj, err := s.CollectionDeleter.DeleteCollection(ctx, deleteValidation, options, listOptions)
+	if err != nil {
+		return nil, err
+	}
+	return transformObject(ctx, s.transformer, s.gvr, obj)
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	metainternalversion "k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apiserver/pkg/authentication/user"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+
+	"github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/apidefinition"
+	dynamiccontext "github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/context"
+)
+
+var secrets = schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+
+type mockedTransformersGetter struct {
+	mockedAPISetRetriever
+	transformers map[schema.GroupVersionResource][]apidefinition.Transformer
+}
+
+func (g mockedTransformersGetter) GetAPITransformers(_ context.Context, _ dynamiccontext.APIDomainKey, gvr schema.GroupVersionResource) []apidefinition.Transformer {
+	return g.transformers[gvr]
+}
+
+// injectUser sets the name of the requesting user as annotation.
+var injectUser = apidefinition.TransformerFunc(func(ctx context.Context, _ schema.GroupVersionResource, obj *unstructured.Unstructured) error {
+	u, ok := apirequest.UserFrom(ctx)
+	if !ok {
+		return errors.New("no user")
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations["example.io/viewer"] = u.GetName()
+	obj.SetAnnotations(annotations)
+	return nil
+})
+
+func newSecret(name string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata": map[string]interface{}{
+			"name":   name,
+			"labels": map[string]interface{}{"old": "a", "keep": "b"},
+		},
+		"data": map[string]interface{}{"password": "c2VjcmV0"},
+		"type": "Opaque",
+	}}
+}
+
+func TestTransformers(t *testing.T) {
+	r := &resourceHandler{apiSetRetriever: mockedTransformersGetter{transformers: map[schema.GroupVersionResource][]apidefinition.Transformer{
+		secrets: {
+			apidefinition.RedactFields("data", "stringData"),
+			apidefinition.RenameLabels(map[string]string{"old": "new"}),
+			injectUser,
+		},
+	}}}
+	ctx := apirequest.WithUser(context.Background(), &user.DefaultInfo{Name: "alice"})
+
+	require.Nil(t, r.transformer(ctx, "root:org:ws", schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, nil))
+	require.Nil(t, (&resourceHandler{apiSetRetriever: mockedAPISetRetriever{}}).transformer(ctx, "root:org:ws", secrets, nil))
+
+	transformer := r.transformer(ctx, "root:org:ws", secrets, nil)
+	require.NotNil(t, transformer)
+
+	stored := newSecret("creds")
+	obj, err := transformObject(ctx, transformer, secrets, stored)
+	require.NoError(t, err)
+	require.Equal(t, &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata": map[string]interface{}{
+			"name":        "creds",
+			"labels":      map[string]interface{}{"new": "a", "keep": "b"},
+			"annotations": map[string]interface{}{"example.io/viewer": "alice"},
+		},
+		"type": "Opaque",
+	}}, obj)
+	require.Equal(t, newSecret("creds"), stored, "stored object must not be mutated")
+
+	list, err := transformObject(ctx, transformer, secrets, &unstructured.UnstructuredList{Items: []unstructured.Unstructured{*newSecret("a"), *newSecret("b")}})
+	require.NoError(t, err)
+	for _, item := range list.(*unstructured.UnstructuredList).Items {
+		_, found := item.Object["data"]
+		require.False(t, found, "data of %s not redacted", item.GetName())
+	}
+
+	status := &metav1.Status{Status: metav1.StatusSuccess}
+	obj, err = transformObject(ctx, transformer, secrets, status)
+	require.NoError(t, err)
+	require.Same(t, status, obj)
+
+	_, err = transformObject(context.Background(), transformer, secrets, newSecret("creds"))
+	require.Error(t, err, "the chain should stop at the error of injectUser")
+}
+
+type fakeStorage struct {
+	obj     runtime.Object
+	watcher *watch.FakeWatcher
+}
+
+func (s *fakeStorage) Get(context.Context, string, *metav1.GetOptions) (runtime.Object, error) {
+	return s.obj, nil
+}
+
+func (s *fakeStorage) Watch(context.Context, *metainternalversion.ListOptions) (watch.Interface, error) {
+	return s.watcher, nil
+}
+
+func TestTransformingStorage(t *testing.T) {
+	redact := apidefinition.RedactFields("data")
+	ctx := context.Background()
+	storage := &fakeStorage{obj: newSecret("creds"), watcher: watch.NewFakeWithChanSize(3, false)}
+
+	require.Same(t, rest.Getter(storage), transformingGetter(storage, nil, secrets))
+
+	obj, err := transformingGetter(storage, redact, secrets).Get(ctx, "creds", &metav1.GetOptions{})
+	require.NoError(t, err)
+	_, found := obj.(*unstructured.Unstructured).Object["data"]
+	require.False(t, found)
+
+	w, err := transformingWatcher(storage, redact, secrets).Watch(ctx, &metainternalversion.ListOptions{})
+	require.NoError(t, err)
+	defer w.Stop()
+
+	bookmark := &unstructured.Unstructured{Object: map[string]interface{}{"data": "kept"}}
+	storage.watcher.Add(newSecret("creds"))
+	storage.watcher.Action(watch.Bookmark, bookmark)
+	storage.watcher.Delete(newSecret("creds"))
+
+	for _, expected := range []watch.EventType{watch.Added, watch.Bookmark, watch.Deleted} {
+		e := <-w.ResultChan()
+		require.Equal(t, expected, e.Type)
+		_, found := e.Object.(*unstructured.Unstructured).Object["data"]
+		require.Equal(t, expected == watch.Bookmark, found, "unexpected data in %s event", e.Type)
+	}
+}
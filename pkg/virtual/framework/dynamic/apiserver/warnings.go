@@ -0,0 +1,89 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"context"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/warning"
+
+	"github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/apidefinition"
+	dynamiccontext "github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/context"
+)
+
+// addWarnings adds the warnings of kcp itself and of the API domain for the
+// resource to the response. The warnings for fields are returned, to be added
+// when the object of the request is known.
+func (r *resourceHandler) addWarnings(ctx context.Context, key dynamiccontext.APIDomainKey, gvr schema.GroupVersionResource) []apidefinition.Warning {
+	warnings := apidefinition.BuiltinWarnings(gvr)
+	if getter, ok := r.apiSetRetriever.(apidefinition.APIWarningsGetter); ok {
+		warnings = append(warnings, getter.GetAPIWarnings(ctx, key, gvr)...)
+	}
+
+	var fieldWarnings []apidefinition.Warning
+	for _, w := range warnings {
+		if w.FieldPath != "" {
+			fieldWarnings = append(fieldWarnings, w)
+			continue
+		}
+		warning.AddWarning(ctx, "", w.Message)
+	}
+	return fieldWarnings
+}
+
+// fieldWarningAdmission wraps the admission of a request to add the warnings
+// for the fields set in the object created or updated.
+type fieldWarningAdmission struct {
+	delegate admission.Interface
+	warnings []apidefinition.Warning
+}
+
+var _ admission.MutationInterface = &fieldWarningAdmission{}
+var _ admission.ValidationInterface = &fieldWarningAdmission{}
+
+func (a *fieldWarningAdmission) Handles(operation admission.Operation) bool {
+	return true
+}
+
+func (a *fieldWarningAdmission) Admit(ctx context.Context, attr admission.Attributes, o admission.ObjectInterfaces) error {
+	if mutating, ok := a.delegate.(admission.MutationInterface); ok && mutating.Handles(attr.GetOperation()) {
+		return mutating.Admit(ctx, attr, o)
+	}
+	return nil
+}
+
+// Validate adds the warnings after mutating admission, i.e. for the object as persisted.
+func (a *fieldWarningAdmission) Validate(ctx context.Context, attr admission.Attributes, o admission.ObjectInterfaces) error {
+	if attr.GetSubresource() == "" && (attr.GetOperation() == admission.Create || attr.GetOperation() == admission.Update) {
+		if obj, ok := attr.GetObject().(*unstructured.Unstructured); ok {
+			for _, w := range a.warnings {
+				if _, found, _ := unstructured.NestedFieldNoCopy(obj.Object, strings.Split(w.FieldPath, ".")...); found {
+					warning.AddWarning(ctx, "", w.Message)
+				}
+			}
+		}
+	}
+
+	if validating, ok := a.delegate.(admission.ValidationInterface); ok && validating.Handles(attr.GetOperation()) {
+		return validating.Validate(ctx, attr, o)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/warning"
+
+	"github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/apidefinition"
+	dynamiccontext "github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/context"
+)
+
+type recordedWarnings []string
+
+func (r *recordedWarnings) AddWarning(_, text string) {
+	*r = append(*r, text)
+}
+
+type mockedWarningsGetter struct {
+	mockedAPISetRetriever
+	warnings []apidefinition.Warning
+}
+
+func (g mockedWarningsGetter) GetAPIWarnings(_ context.Context, _ dynamiccontext.APIDomainKey, gvr schema.GroupVersionResource) []apidefinition.Warning {
+	var ret []apidefinition.Warning
+	for _, w := range g.warnings {
+		if w.AppliesTo(gvr) {
+			ret = append(ret, w)
+		}
+	}
+	return ret
+}
+
+type validationRecorder struct {
+	called bool
+}
+
+func (v *validationRecorder) Handles(admission.Operation) bool { return true }
+
+func (v *validationRecorder) Validate(context.Context, admission.Attributes, admission.ObjectInterfaces) error {
+	v.called = true
+	return nil
+}
+
+func TestWarnings(t *testing.T) {
+	widgets := schema.GroupVersionResource{Group: "example.io", Version: "v1", Resource: "widgets"}
+	r := &resourceHandler{apiSetRetriever: mockedWarningsGetter{warnings: []apidefinition.Warning{
+		{Resource: schema.GroupVersionResource{Group: "example.io", Resource: "widgets"}, Message: "widgets are going away"},
+		{Resource: widgets, FieldPath: "spec.color", Message: "spec.color is deprecated"},
+		{Resource: widgets, FieldPath: "spec.size", Message: "spec.size is deprecated"},
+		{Resource: schema.GroupVersionResource{Group: "example.io", Version: "v2", Resource: "widgets"}, Message: "v2 is unstable"},
+	}}}
+
+	var recorded recordedWarnings
+	ctx := warning.WithWarningRecorder(context.Background(), &recorded)
+	fieldWarnings := r.addWarnings(ctx, "root:org:ws", widgets)
+	require.Equal(t, recordedWarnings{"widgets are going away"}, recorded)
+	require.Len(t, fieldWarnings, 2)
+
+	delegate := &validationRecorder{}
+	admit := &fieldWarningAdmission{delegate: delegate, warnings: fieldWarnings}
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"color": "blue"},
+	}}
+
+	recorded = nil
+	err := admit.Validate(ctx, admission.NewAttributesRecord(obj, nil, schema.GroupVersionKind{}, "", "a", widgets, "", admission.Create, nil, false, nil), nil)
+	require.NoError(t, err)
+	require.Equal(t, recordedWarnings{"spec.color is deprecated"}, recorded)
+	require.True(t, delegate.called)
+
+	recorded = nil
+	err = admit.Validate(ctx, admission.NewAttributesRecord(obj, nil, schema.GroupVersionKind{}, "", "a", widgets, "status", admission.Update, nil, false, nil), nil)
+	require.NoError(t, err)
+	require.Empty(t, recorded, "subresources do not get field warnings")
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dynamictest
+
+import (
+	"context"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/apidefinition"
+	dynamiccontext "github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/context"
+)
+
+// APIDefinitionSetGetter is a fake apidefinition.APIDefinitionSetGetter serving the same
+// APIDefinitions, transformers and field restrictions for all API domain keys. All of them
+// can be changed at any time.
+type APIDefinitionSetGetter struct {
+	lock               sync.RWMutex
+	apis               apidefinition.APIDefinitionSet
+	transformers       map[schema.GroupVersionResource][]apidefinition.Transformer
+	fieldRestrictions  map[schema.GroupVersionResource][]apidefinition.FieldRestriction
+	finalizerPolicies  map[schema.GroupVersionResource]*apidefinition.FinalizerPolicy
+	generationPolicies map[schema.GroupVersionResource]*apidefinition.GenerationPolicy
+}
+
+var _ apidefinition.APIDefinitionSetGetter = (*APIDefinitionSetGetter)(nil)
+var _ apidefinition.APITransformersGetter = (*APIDefinitionSetGetter)(nil)
+var _ apidefinition.APIFieldRestrictionsGetter = (*APIDefinitionSetGetter)(nil)
+var _ apidefinition.APIFinalizerPolicyGetter = (*APIDefinitionSetGetter)(nil)
+var _ apidefinition.APIGenerationPolicyGetter = (*APIDefinitionSetGetter)(nil)
+
+// NewAPIDefinitionSetGetter returns an APIDefinitionSetGetter serving the given definitions.
+func NewAPIDefinitionSetGetter(apis apidefinition.APIDefinitionSet) *APIDefinitionSetGetter {
+	g := &APIDefinitionSetGetter{
+		apis:               apidefinition.APIDefinitionSet{},
+		transformers:       map[schema.GroupVersionResource][]apidefinition.Transformer{},
+		fieldRestrictions:  map[schema.GroupVersionResource][]apidefinition.FieldRestriction{},
+		finalizerPolicies:  map[schema.GroupVersionResource]*apidefinition.FinalizerPolicy{},
+		generationPolicies: map[schema.GroupVersionResource]*apidefinition.GenerationPolicy{},
+	}
+	for gvr, def := range apis {
+		g.apis[gvr] = def
+	}
+	return g
+}
+
+// GetAPIDefinitionSet implements apidefinition.APIDefinitionSetGetter.
+func (g *APIDefinitionSetGetter) GetAPIDefinitionSet(ctx context.Context, key dynamiccontext.APIDomainKey) (apis apidefinition.APIDefinitionSet, apisExist bool, err error) {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+
+	apis = make(apidefinition.APIDefinitionSet, len(g.apis))
+	for gvr, def := range g.apis {
+		apis[gvr] = def
+	}
+	return apis, true, nil
+}
+
+// Add serves the definition for the resource, replacing and tearing down an existing one.
+func (g *APIDefinitionSetGetter) Add(gvr schema.GroupVersionResource, def apidefinition.APIDefinition) {
+	g.lock.Lock()
+	old, found := g.apis[gvr]
+	g.apis[gvr] = def
+	g.lock.Unlock()
+
+	if found {
+		old.TearDown()
+	}
+}
+
+// Remove stops serving the resource and tears down its definition.
+func (g *APIDefinitionSetGetter) Remove(gvr schema.GroupVersionResource) {
+	g.lock.Lock()
+	old, found := g.apis[gvr]
+	delete(g.apis, gvr)
+	g.lock.Unlock()
+
+	if found {
+		old.TearDown()
+	}
+}
+
+// GetAPITransformers implements apidefinition.APITransformersGetter.
+func (g *APIDefinitionSetGetter) GetAPITransformers(ctx context.Context, key dynamiccontext.APIDomainKey, gvr schema.GroupVersionResource) []apidefinition.Transformer {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	return g.transformers[gvr]
+}
+
+// SetTransformers applies the transformers to the objects of the resource returned to clients.
+func (g *APIDefinitionSetGetter) SetTransformers(gvr schema.GroupVersionResource, transformers ...apidefinition.Transformer) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.transformers[gvr] = transformers
+}
+
+// GetAPIFieldRestrictions implements apidefinition.APIFieldRestrictionsGetter.
+func (g *APIDefinitionSetGetter) GetAPIFieldRestrictions(ctx context.Context, key dynamiccontext.APIDomainKey, gvr schema.GroupVersionResource) []apidefinition.FieldRestriction {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	return g.fieldRestrictions[gvr]
+}
+
+// SetFieldRestrictions restricts the fields of the resource clients can read and write.
+func (g *APIDefinitionSetGetter) SetFieldRestrictions(gvr schema.GroupVersionResource, restrictions ...apidefinition.FieldRestriction) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.fieldRestrictions[gvr] = restrictions
+}
+
+// GetAPIFinalizerPolicy implements apidefinition.APIFinalizerPolicyGetter.
+func (g *APIDefinitionSetGetter) GetAPIFinalizerPolicy(ctx context.Context, key dynamiccontext.APIDomainKey, gvr schema.GroupVersionResource) *apidefinition.FinalizerPolicy {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	return g.finalizerPolicies[gvr]
+}
+
+// SetFinalizerPolicy restricts the finalizers of the resource clients can add and remove.
+func (g *APIDefinitionSetGetter) SetFinalizerPolicy(gvr schema.GroupVersionResource, policy *apidefinition.FinalizerPolicy) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.finalizerPolicies[gvr] = policy
+}
+
+// GetAPIGenerationPolicy implements apidefinition.APIGenerationPolicyGetter.
+func (g *APIDefinitionSetGetter) GetAPIGenerationPolicy(ctx context.Context, key dynamiccontext.APIDomainKey, gvr schema.GroupVersionResource) *apidefinition.GenerationPolicy {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	return g.generationPolicies[gvr]
+}
+
+// SetGenerationPolicy enforces the generation convention for the resource.
+func (g *APIDefinitionSetGetter) SetGenerationPolicy(gvr schema.GroupVersionResource, policy *apidefinition.GenerationPolicy) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.generationPolicies[gvr] = policy
+}
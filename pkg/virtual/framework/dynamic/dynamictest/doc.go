@@ -0,0 +1,31 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dynamictest runs dynamic virtual workspaces in-process for integration tests
+// of virtual workspaces and their REST storages, without a kcp server.
+//
+// StartServer serves the given resources through the same root apiserver, handler chain
+// and dynamic apiserver as a kcp virtual workspace server. Resources without a REST storage
+// of their own are stored in a fake dynamic client, which the test can seed with objects
+// and inject reactors into:
+//
+//	s := dynamictest.StartServer(t, widgets)
+//	client := dynamic.NewForConfigOrDie(s.Config)
+//	_, err := client.Resource(widgetsGVR).Namespace("default").Create(ctx, widget, metav1.CreateOptions{})
+//
+// Requests are served under /services/dynamictest/<api-domain-key>[/clusters/<logical-cluster>],
+// with all API domain keys serving the same APIDefinitionSet.
+package dynamictest
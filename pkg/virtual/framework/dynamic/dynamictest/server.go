@@ -0,0 +1,375 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dynamictest
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	"k8s.io/apiextensions-apiserver/pkg/registry/customresource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/request/bearertoken"
+	"k8s.io/apiserver/pkg/authentication/user"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	genericoptions "k8s.io/apiserver/pkg/server/options"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+
+	apiresourcev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apiresource/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic"
+	"github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/apidefinition"
+	"github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/apiserver"
+	dynamiccontext "github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/context"
+	"github.com/kcp-dev/kcp/pkg/virtual/framework/forwardingregistry"
+	"github.com/kcp-dev/kcp/pkg/virtual/framework/rootapiserver"
+)
+
+const (
+	// Name is the name of the virtual workspace served by the Server.
+	Name = "dynamictest"
+
+	// DefaultAPIDomainKey is the API domain key of Server.Config.
+	DefaultAPIDomainKey dynamiccontext.APIDomainKey = "default"
+
+	rootPathPrefix = "/services/" + Name + "/"
+	token          = "dynamictest-admin-token"
+)
+
+var (
+	// DefaultClusterName is the logical cluster of Server.Config.
+	DefaultClusterName = logicalcluster.New("root:test")
+
+	// Admin is the user authenticated by the token of Server.Config. All requests are authorized.
+	Admin = &user.DefaultInfo{Name: "dynamictest-admin", Groups: []string{user.AllAuthenticated}}
+)
+
+// Resource is a resource served by the Server.
+type Resource struct {
+	// Spec is the specification of the resource, including its OpenAPI v3 schema.
+	Spec *apiresourcev1alpha1.CommonAPIResourceSpec
+
+	// RestProvider provides the REST storage of the resource. If nil, the objects are
+	// stored in a fake dynamic client, see Server.Storage.
+	RestProvider apiserver.RestProviderFunc
+}
+
+// GroupVersionResource returns the resource of the spec.
+func (r Resource) GroupVersionResource() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: r.Spec.GroupVersion.Group, Version: r.Spec.GroupVersion.Version, Resource: r.Spec.Plural}
+}
+
+// ResourceFromCRD returns a Resource for the given version of the CustomResourceDefinition.
+func ResourceFromCRD(crd *apiextensionsv1.CustomResourceDefinition, version string) (Resource, error) {
+	for i := range crd.Spec.Versions {
+		v := &crd.Spec.Versions[i]
+		if v.Name != version {
+			continue
+		}
+
+		spec := &apiresourcev1alpha1.CommonAPIResourceSpec{
+			GroupVersion:                  apiresourcev1alpha1.GroupVersion{Group: crd.Spec.Group, Version: v.Name},
+			Scope:                         crd.Spec.Scope,
+			CustomResourceDefinitionNames: crd.Spec.Names,
+		}
+		if v.Schema != nil && v.Schema.OpenAPIV3Schema != nil {
+			if err := spec.SetSchema(v.Schema.OpenAPIV3Schema); err != nil {
+				return Resource{}, err
+			}
+		}
+		spec.SubResources.ImportFromCRDVersion(v)
+		spec.ColumnDefinitions.ImportFromCRDVersion(v)
+
+		return Resource{Spec: spec}, nil
+	}
+	return Resource{}, fmt.Errorf("version %q not found in CustomResourceDefinition %s", version, crd.Name)
+}
+
+// Server is a dynamic virtual workspace served in-process.
+type Server struct {
+	// Config is a client config of the Admin user for DefaultClusterName in the
+	// DefaultAPIDomainKey of the virtual workspace.
+	Config *restclient.Config
+
+	// APIDefinitions are the API definitions served for all API domain keys.
+	APIDefinitions *APIDefinitionSetGetter
+
+	ctx        context.Context
+	mainConfig genericapiserver.CompletedConfig
+	host       string
+	caFile     string
+
+	lock     sync.Mutex
+	storages map[schema.GroupVersionResource]*fake.FakeDynamicClient
+}
+
+// StartServer starts serving the resources, and stops when the test is cleaned up.
+func StartServer(t testing.TB, resources ...Resource) *Server {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Server{
+		APIDefinitions: NewAPIDefinitionSetGetter(nil),
+		ctx:            ctx,
+		storages:       map[schema.GroupVersionResource]*fake.FakeDynamicClient{},
+	}
+
+	scheme := runtime.NewScheme()
+	metav1.AddToGroupVersion(scheme, schema.GroupVersion{Group: "", Version: "v1"})
+	codecs := serializer.NewCodecFactory(scheme)
+	recommendedConfig := genericapiserver.NewRecommendedConfig(codecs)
+
+	secureServing := genericoptions.NewSecureServingOptions()
+	secureServing.BindAddress = net.ParseIP("127.0.0.1")
+	secureServing.BindPort = 0
+	secureServing.ServerCert.CertDirectory = t.TempDir()
+	require.NoError(t, secureServing.MaybeDefaultWithSelfSignedCerts("localhost", nil, []net.IP{net.ParseIP("127.0.0.1")}))
+	require.NoError(t, secureServing.ApplyTo(&recommendedConfig.Config.SecureServing))
+	s.host = "https://" + recommendedConfig.SecureServing.Listener.Addr().String()
+	s.caFile = secureServing.ServerCert.CertKey.CertFile
+
+	recommendedConfig.Authentication.Authenticator = bearertoken.New(authenticator.TokenFunc(func(ctx context.Context, requestToken string) (*authenticator.Response, bool, error) {
+		if requestToken != token {
+			return nil, false, nil
+		}
+		return &authenticator.Response{User: Admin}, true, nil
+	}))
+
+	vw := &dynamic.DynamicVirtualWorkspace{
+		Name:             Name,
+		RootPathResolver: resolveRootPath,
+		Ready:            func() error { return nil },
+		BootstrapAPISetManagement: func(mainConfig genericapiserver.CompletedConfig) (apidefinition.APIDefinitionSetGetter, error) {
+			s.mainConfig = mainConfig
+			for _, r := range resources {
+				if err := s.AddResource(r); err != nil {
+					return nil, err
+				}
+			}
+			return s.APIDefinitions, nil
+		},
+	}
+
+	rootAPIServerConfig, err := rootapiserver.NewRootAPIConfig(recommendedConfig, nil, vw)
+	require.NoError(t, err)
+	rootAPIServer, err := rootAPIServerConfig.Complete().New(genericapiserver.NewEmptyDelegate())
+	require.NoError(t, err)
+	preparedRootAPIServer := rootAPIServer.GenericAPIServer.PrepareRun()
+
+	stopCh := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		if err := preparedRootAPIServer.Run(stopCh); err != nil {
+			t.Errorf("virtual workspace server failed: %v", err)
+		}
+	}()
+	t.Cleanup(func() {
+		close(stopCh)
+		cancel()
+		<-stopped
+	})
+
+	s.Config = s.ConfigFor(DefaultAPIDomainKey, DefaultClusterName)
+
+	rootConfig := restclient.CopyConfig(s.Config)
+	rootConfig.Host = s.host
+	kubeClient, err := kubernetes.NewForConfig(rootConfig)
+	require.NoError(t, err)
+	err = wait.PollImmediate(100*time.Millisecond, wait.ForeverTestTimeout, func() (bool, error) {
+		return kubeClient.Discovery().RESTClient().Get().AbsPath("/readyz").Do(ctx).Error() == nil, nil
+	})
+	require.NoError(t, err, "virtual workspace server did not get ready")
+
+	return s
+}
+
+// ConfigFor returns a client config of the Admin user for the logical cluster in the
+// given API domain. Pass logicalcluster.Wildcard for wildcard requests.
+func (s *Server) ConfigFor(key dynamiccontext.APIDomainKey, clusterName logicalcluster.Name) *restclient.Config {
+	return &restclient.Config{
+		Host:            s.host + rootPathPrefix + url.PathEscape(string(key)) + clusterName.Path(),
+		BearerToken:     token,
+		TLSClientConfig: restclient.TLSClientConfig{CAFile: s.caFile},
+	}
+}
+
+// AddResource starts serving the resource, replacing a served resource of the same
+// group, version and resource.
+func (s *Server) AddResource(r Resource) error {
+	gvr := r.GroupVersionResource()
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	restProvider := r.RestProvider
+	if restProvider == nil {
+		client := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{gvr: r.Spec.ListKind})
+		restProvider = provideForwardingRestStorage(ctx, &fakeClusterClient{client})
+
+		s.lock.Lock()
+		s.storages[gvr] = client
+		s.lock.Unlock()
+	}
+
+	def, err := apiserver.CreateServingInfoFor(s.mainConfig, DefaultClusterName, r.Spec, restProvider)
+	if err != nil {
+		cancel()
+		return err
+	}
+	s.APIDefinitions.Add(gvr, &apiDefinitionWithCancel{APIDefinition: def, cancelFn: cancel})
+
+	return nil
+}
+
+// RemoveResource stops serving the resource.
+func (s *Server) RemoveResource(gvr schema.GroupVersionResource) {
+	s.APIDefinitions.Remove(gvr)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.storages, gvr)
+}
+
+// Storage returns the fake dynamic client storing the objects of the resource, e.g. to
+// seed objects or to add reactors. It is nil for resources with a RestProvider.
+func (s *Server) Storage(gvr schema.GroupVersionResource) *fake.FakeDynamicClient {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.storages[gvr]
+}
+
+// resolveRootPath accepts requests to /services/dynamictest/<api-domain-key>[/clusters/<logical-cluster>].
+// Requests without a logical cluster are wildcard requests.
+func resolveRootPath(urlPath string, requestContext context.Context) (accepted bool, prefixToStrip string, completedContext context.Context) {
+	completedContext = requestContext
+	if !strings.HasPrefix(urlPath, rootPathPrefix) {
+		return
+	}
+	parts := strings.SplitN(strings.TrimPrefix(urlPath, rootPathPrefix), "/", 2)
+	if parts[0] == "" {
+		return
+	}
+	apiDomainKey := dynamiccontext.APIDomainKey(parts[0])
+
+	realPath := "/"
+	if len(parts) > 1 {
+		realPath += parts[1]
+	}
+
+	cluster := genericapirequest.Cluster{Name: logicalcluster.Wildcard, Wildcard: true}
+	if strings.HasPrefix(realPath, "/clusters/") {
+		parts := strings.SplitN(strings.TrimPrefix(realPath, "/clusters/"), "/", 2)
+		realPath = "/"
+		if len(parts) > 1 {
+			realPath += parts[1]
+		}
+		cluster = genericapirequest.Cluster{Name: logicalcluster.New(parts[0]), Wildcard: parts[0] == "*"}
+	}
+
+	completedContext = genericapirequest.WithCluster(requestContext, cluster)
+	completedContext = dynamiccontext.WithAPIDomainKey(completedContext, apiDomainKey)
+	prefixToStrip = strings.TrimSuffix(urlPath, realPath)
+	accepted = true
+	return
+}
+
+// provideForwardingRestStorage stores the objects through the forwarding registry in the given client.
+func provideForwardingRestStorage(ctx context.Context, clusterClient dynamic.ClusterInterface) apiserver.RestProviderFunc {
+	return func(resource schema.GroupVersionResource, kind schema.GroupVersionKind, listKind schema.GroupVersionKind, typer runtime.ObjectTyper, tableConvertor rest.TableConvertor, namespaceScoped bool, schemaValidator *validate.SchemaValidator, subresourcesSchemaValidator map[string]*validate.SchemaValidator, structuralSchema *structuralschema.Structural) (mainStorage rest.Storage, subresourceStorages map[string]rest.Storage) {
+		statusSchemaValidate, statusEnabled := subresourcesSchemaValidator["status"]
+
+		var statusSpec *apiextensions.CustomResourceSubresourceStatus
+		if statusEnabled {
+			statusSpec = &apiextensions.CustomResourceSubresourceStatus{}
+		}
+
+		strategy := customresource.NewStrategy(
+			typer,
+			namespaceScoped,
+			kind,
+			schemaValidator,
+			statusSchemaValidate,
+			map[string]*structuralschema.Structural{resource.Version: structuralSchema},
+			statusSpec,
+			nil,
+		)
+
+		storage := forwardingregistry.NewStorage(
+			ctx,
+			resource,
+			"",
+			kind,
+			listKind,
+			strategy,
+			nil,
+			tableConvertor,
+			nil,
+			clusterClient,
+			nil,
+			func(_ schema.GroupResource, store customresource.Store) customresource.Store {
+				return store
+			},
+		)
+
+		subresourceStorages = make(map[string]rest.Storage)
+		if statusEnabled {
+			subresourceStorages["status"] = storage.Status
+		}
+
+		return storage.CustomResource, subresourceStorages
+	}
+}
+
+// fakeClusterClient serves all logical clusters from the same fake client.
+type fakeClusterClient struct {
+	client *fake.FakeDynamicClient
+}
+
+func (c *fakeClusterClient) Cluster(_ logicalcluster.Name) dynamic.Interface {
+	return c.client
+}
+
+// apiDefinitionWithCancel calls the cancelFn on tear-down.
+type apiDefinitionWithCancel struct {
+	apidefinition.APIDefinition
+	cancelFn func()
+}
+
+func (d *apiDefinitionWithCancel) TearDown() {
+	d.cancelFn()
+	d.APIDefinition.TearDown()
+}
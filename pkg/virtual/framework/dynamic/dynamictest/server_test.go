@@ -0,0 +1,148 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dynamictest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/apidefinition"
+)
+
+var widgetsGVR = schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+
+func widgetsCRD() *apiextensionsv1.CustomResourceDefinition {
+	return &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "widgets.example.com"},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: "example.com",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets", Singular: "widget", Kind: "Widget", ListKind: "WidgetList"},
+			Scope: apiextensionsv1.NamespaceScoped,
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{
+				Name:    "v1",
+				Served:  true,
+				Storage: true,
+				Schema: &apiextensionsv1.CustomResourceValidation{
+					OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+						Type: "object",
+						Properties: map[string]apiextensionsv1.JSONSchemaProps{
+							"spec": {
+								Type: "object",
+								Properties: map[string]apiextensionsv1.JSONSchemaProps{
+									"size": {Type: "integer"},
+								},
+							},
+							"status": {Type: "object", XPreserveUnknownFields: boolPtr(true)},
+						},
+					},
+				},
+				Subresources: &apiextensionsv1.CustomResourceSubresources{Status: &apiextensionsv1.CustomResourceSubresourceStatus{}},
+			}},
+		},
+	}
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func TestResourceFromCRD(t *testing.T) {
+	r, err := ResourceFromCRD(widgetsCRD(), "v1")
+	require.NoError(t, err)
+	require.Equal(t, widgetsGVR, r.GroupVersionResource())
+	require.True(t, r.Spec.SubResources.Contains("status"))
+
+	s, err := r.Spec.GetSchema()
+	require.NoError(t, err)
+	require.Contains(t, s.Properties, "spec")
+
+	_, err = ResourceFromCRD(widgetsCRD(), "v2")
+	require.Error(t, err)
+}
+
+func TestServer(t *testing.T) {
+	widgets, err := ResourceFromCRD(widgetsCRD(), "v1")
+	require.NoError(t, err)
+
+	s := StartServer(t, widgets)
+	ctx := context.Background()
+
+	client, err := dynamic.NewForConfig(s.Config)
+	require.NoError(t, err)
+
+	t.Log("Create a widget through the virtual workspace")
+	widget := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "example.com/v1",
+		"kind":       "Widget",
+		"metadata":   map[string]interface{}{"name": "blue"},
+		"spec":       map[string]interface{}{"size": int64(3)},
+	}}
+	_, err = client.Resource(widgetsGVR).Namespace("default").Create(ctx, widget, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	t.Log("Get the widget from the virtual workspace and from the fake storage")
+	got, err := client.Resource(widgetsGVR).Namespace("default").Get(ctx, "blue", metav1.GetOptions{})
+	require.NoError(t, err)
+	size, _, err := unstructured.NestedInt64(got.Object, "spec", "size")
+	require.NoError(t, err)
+	require.Equal(t, int64(3), size)
+
+	_, err = s.Storage(widgetsGVR).Resource(widgetsGVR).Namespace("default").Get(ctx, "blue", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	t.Log("List the widgets with a wildcard request")
+	wildcardClient, err := dynamic.NewForConfig(s.ConfigFor(DefaultAPIDomainKey, logicalcluster.Wildcard))
+	require.NoError(t, err)
+	list, err := wildcardClient.Resource(widgetsGVR).List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+
+	t.Log("Widgets are discovered")
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(s.Config)
+	require.NoError(t, err)
+	resources, err := discoveryClient.ServerResourcesForGroupVersion("example.com/v1")
+	require.NoError(t, err)
+	names := []string{}
+	for _, r := range resources.APIResources {
+		names = append(names, r.Name)
+	}
+	require.ElementsMatch(t, []string{"widgets", "widgets/status"}, names)
+
+	t.Log("Transformers apply to the widgets returned")
+	s.APIDefinitions.SetTransformers(widgetsGVR, apidefinition.RedactFields("spec.size"))
+	got, err = client.Resource(widgetsGVR).Namespace("default").Get(ctx, "blue", metav1.GetOptions{})
+	require.NoError(t, err)
+	_, found, err := unstructured.NestedInt64(got.Object, "spec", "size")
+	require.NoError(t, err)
+	require.False(t, found, "expected spec.size to be redacted")
+
+	t.Log("Widgets are not served after removing them")
+	s.RemoveResource(widgetsGVR)
+	_, err = client.Resource(widgetsGVR).Namespace("default").Get(ctx, "blue", metav1.GetOptions{})
+	require.True(t, apierrors.IsNotFound(err), "expected NotFound, got %v", err)
+}
@@ -29,6 +29,7 @@ func (vw *DynamicVirtualWorkspace) Register(rootAPIServerConfig genericapiserver
 	if err != nil {
 		return nil, err
 	}
+	vw.apiSetRetriever = apiSetRetriever
 
 	cfg := &apiserver.DynamicAPIServerConfig{
 		GenericConfig: &genericapiserver.RecommendedConfig{Config: *rootAPIServerConfig.Config, SharedInformerFactory: rootAPIServerConfig.SharedInformerFactory},
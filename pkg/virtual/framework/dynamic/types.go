@@ -18,6 +18,7 @@ package dynamic
 
 import (
 	"context"
+	"errors"
 
 	genericapiserver "k8s.io/apiserver/pkg/server"
 
@@ -33,11 +34,19 @@ type DynamicVirtualWorkspace struct {
 	Name             string
 	RootPathResolver framework.RootPathResolverFunc
 	Ready            framework.ReadyFunc
+	Live             framework.LiveFunc
+
+	// AccessPolicy defines which anonymous requests are served, and which groups are
+	// added to the users of the virtual workspace.
+	AccessPolicy framework.AccessPolicy
 
 	// BootstrapAPISetManagement creates, initializes and returns an apidefinition.APIDefinitionSetGetter.
 	// Usually it would also set up some logic that will call the apiserver.CreateServingInfoFor() method
 	// to add an apidefinition.APIDefinition in the apidefinition.APIDefinitionSetGetter on some event.
 	BootstrapAPISetManagement func(mainConfig genericapiserver.CompletedConfig) (apidefinition.APIDefinitionSetGetter, error)
+
+	// apiSetRetriever is set on Register.
+	apiSetRetriever apidefinition.APIDefinitionSetGetter
 }
 
 func (vw *DynamicVirtualWorkspace) GetName() string {
@@ -45,7 +54,24 @@ func (vw *DynamicVirtualWorkspace) GetName() string {
 }
 
 func (vw *DynamicVirtualWorkspace) IsReady() error {
-	return vw.Ready()
+	if err := vw.Ready(); err != nil {
+		return err
+	}
+	if syncer, ok := vw.apiSetRetriever.(apidefinition.APIDefinitionSetSyncer); ok && !syncer.HasSynced() {
+		return errors.New("API definitions not synced")
+	}
+	return nil
+}
+
+func (vw *DynamicVirtualWorkspace) IsLive() error {
+	if vw.Live == nil {
+		return nil
+	}
+	return vw.Live()
+}
+
+func (vw *DynamicVirtualWorkspace) GetAccessPolicy() framework.AccessPolicy {
+	return vw.AccessPolicy
 }
 
 func (vw *DynamicVirtualWorkspace) ResolveRootPath(urlPath string, context context.Context) (accepted bool, prefixToStrip string, completedContext context.Context) {
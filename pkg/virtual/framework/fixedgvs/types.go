@@ -58,7 +58,12 @@ type FixedGroupVersionsVirtualWorkspace struct {
 	Name                string
 	RootPathResolver    framework.RootPathResolverFunc
 	Ready               framework.ReadyFunc
+	Live                framework.LiveFunc
 	GroupVersionAPISets []GroupVersionAPISet
+
+	// AccessPolicy defines which anonymous requests are served, and which groups are
+	// added to the users of the virtual workspace.
+	AccessPolicy framework.AccessPolicy
 }
 
 func (vw *FixedGroupVersionsVirtualWorkspace) GetName() string {
@@ -69,6 +74,17 @@ func (vw *FixedGroupVersionsVirtualWorkspace) IsReady() error {
 	return vw.Ready()
 }
 
+func (vw *FixedGroupVersionsVirtualWorkspace) IsLive() error {
+	if vw.Live == nil {
+		return nil
+	}
+	return vw.Live()
+}
+
+func (vw *FixedGroupVersionsVirtualWorkspace) GetAccessPolicy() framework.AccessPolicy {
+	return vw.AccessPolicy
+}
+
 func (vw *FixedGroupVersionsVirtualWorkspace) ResolveRootPath(urlPath string, context context.Context) (accepted bool, prefixToStrip string, completedContext context.Context) {
 	return vw.RootPathResolver(urlPath, context)
 }
@@ -0,0 +1,107 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package forwardingregistry
+
+import (
+	"context"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+)
+
+type primaryReadsKeyType int
+
+const primaryReadsKey primaryReadsKeyType = iota
+
+// withPrimaryReads marks reads which must not be served by a read replica, e.g.
+// the read of a read-modify-write cycle, which would conflict on stale objects.
+func withPrimaryReads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryReadsKey, true)
+}
+
+func primaryReadsFrom(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryReadsKey).(bool)
+	return primary
+}
+
+// NewReadReplicaClusterClient returns a cluster client sending get, list and watch
+// requests to the replica, e.g. a cache server, and all other requests to the
+// primary, i.e. the shards. The reads of updates and patches of a Store go to the
+// primary too, such that they do not conflict because of replication lag.
+func NewReadReplicaClusterClient(primary, replica dynamic.ClusterInterface) dynamic.ClusterInterface {
+	return &readReplicaClusterClient{primary: primary, replica: replica}
+}
+
+type readReplicaClusterClient struct {
+	primary, replica dynamic.ClusterInterface
+}
+
+func (c *readReplicaClusterClient) Cluster(name logicalcluster.Name) dynamic.Interface {
+	return &readReplicaClient{primary: c.primary.Cluster(name), replica: c.replica.Cluster(name)}
+}
+
+type readReplicaClient struct {
+	primary, replica dynamic.Interface
+}
+
+func (c *readReplicaClient) Resource(resource schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	primary, replica := c.primary.Resource(resource), c.replica.Resource(resource)
+	return &readReplicaNamespaceableResource{
+		readReplicaResource:  readReplicaResource{ResourceInterface: primary, replica: replica},
+		namespaceablePrimary: primary,
+		namespaceableReplica: replica,
+	}
+}
+
+type readReplicaNamespaceableResource struct {
+	readReplicaResource
+	namespaceablePrimary, namespaceableReplica dynamic.NamespaceableResourceInterface
+}
+
+func (r *readReplicaNamespaceableResource) Namespace(namespace string) dynamic.ResourceInterface {
+	return &readReplicaResource{ResourceInterface: r.namespaceablePrimary.Namespace(namespace), replica: r.namespaceableReplica.Namespace(namespace)}
+}
+
+// readReplicaResource writes through the embedded primary, and reads from the replica.
+type readReplicaResource struct {
+	dynamic.ResourceInterface
+	replica dynamic.ResourceInterface
+}
+
+func (r *readReplicaResource) reader(ctx context.Context) dynamic.ResourceInterface {
+	if primaryReadsFrom(ctx) {
+		return r.ResourceInterface
+	}
+	return r.replica
+}
+
+func (r *readReplicaResource) Get(ctx context.Context, name string, options metav1.GetOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	return r.reader(ctx).Get(ctx, name, options, subresources...)
+}
+
+func (r *readReplicaResource) List(ctx context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	return r.reader(ctx).List(ctx, opts)
+}
+
+func (r *readReplicaResource) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	return r.reader(ctx).Watch(ctx, opts)
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package forwardingregistry_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/client-go/dynamic/fake"
+
+	"github.com/kcp-dev/kcp/pkg/virtual/framework/forwardingregistry"
+)
+
+func TestReadReplica(t *testing.T) {
+	stale := createResource("default", "foo")
+	stale.SetResourceVersion("100")
+	current := createResource("default", "foo")
+	current.SetResourceVersion("101")
+	current.SetLabels(map[string]string{"fresh": "true"})
+
+	primaryClient := fake.NewSimpleDynamicClient(runtime.NewScheme(), current)
+	primaryClient.PrependReactor("update", "noxus", updateReactor(primaryClient))
+	replicaClient := fake.NewSimpleDynamicClient(runtime.NewScheme(), stale)
+
+	storage := newStorage(t, forwardingregistry.NewReadReplicaClusterClient(&mockedClusterClient{primaryClient}, &mockedClusterClient{replicaClient}), "", nil)
+	ctx := request.WithNamespace(context.Background(), "default")
+	ctx = request.WithCluster(ctx, request.Cluster{Name: logicalcluster.New("foo")})
+
+	t.Log("Reads are served by the replica")
+	result, err := storage.CustomResource.Get(ctx, "foo", &metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "100", result.(*unstructured.Unstructured).GetResourceVersion())
+
+	list, err := storage.CustomResource.List(ctx, &internalversion.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, list.(*unstructured.UnstructuredList).Items, 1)
+	require.Equal(t, "100", list.(*unstructured.UnstructuredList).Items[0].GetResourceVersion())
+
+	w, err := storage.CustomResource.Watch(ctx, &internalversion.ListOptions{})
+	require.NoError(t, err)
+	w.Stop()
+
+	require.Empty(t, primaryClient.Actions())
+	require.Len(t, replicaClient.Actions(), 3)
+
+	t.Log("Updates read and write the primary")
+	replicaClient.ClearActions()
+	updated := current.DeepCopy()
+	_ = unstructured.SetNestedField(updated.UnstructuredContent(), int64(8), "spec", "replicas")
+	result, _, err = storage.CustomResource.Update(ctx, "foo", rest.DefaultUpdatedObjectInfo(updated), rest.ValidateAllObjectFunc, rest.ValidateAllObjectUpdateFunc, false, &metav1.UpdateOptions{})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"fresh": "true"}, result.(*unstructured.Unstructured).GetLabels())
+
+	require.Empty(t, replicaClient.Actions())
+	verbs := []string{}
+	for _, action := range primaryClient.Actions() {
+		verbs = append(verbs, action.GetVerb())
+	}
+	require.Equal(t, []string{"get", "update"}, verbs)
+}
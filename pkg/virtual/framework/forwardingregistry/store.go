@@ -35,6 +35,8 @@ import (
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+
+	"github.com/kcp-dev/kcp/pkg/virtual/framework/celfilter"
 )
 
 type Store struct {
@@ -113,12 +115,24 @@ func (s *Store) List(ctx context.Context, options *metainternalversion.ListOptio
 		return nil, err
 	}
 
+	filter, err := celfilter.FromContext(ctx)
+	if err != nil {
+		return nil, kerrors.NewBadRequest(err.Error())
+	}
+
 	delegate, err := s.getClientResource(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	return delegate.List(ctx, v1ListOptions)
+	list, err := delegate.List(ctx, v1ListOptions)
+	if err != nil {
+		return nil, err
+	}
+	if filter != nil {
+		filter.FilterList(list)
+	}
+	return list, nil
 }
 
 // Get implements rest.Getter
@@ -137,6 +151,10 @@ func (s *Store) Watch(ctx context.Context, options *metainternalversion.ListOpti
 	if err := metainternalversion.Convert_internalversion_ListOptions_To_v1_ListOptions(options, &v1ListOptions, nil); err != nil {
 		return nil, err
 	}
+	filter, err := celfilter.FromContext(ctx)
+	if err != nil {
+		return nil, kerrors.NewBadRequest(err.Error())
+	}
 	delegate, err := s.getClientResource(ctx)
 	if err != nil {
 		return nil, err
@@ -152,7 +170,11 @@ func (s *Store) Watch(ctx context.Context, options *metainternalversion.ListOpti
 		}
 	}()
 
-	return delegate.Watch(watchCtx, v1ListOptions)
+	w, err := delegate.Watch(watchCtx, v1ListOptions)
+	if err != nil || filter == nil {
+		return w, err
+	}
+	return filter.FilterWatch(w), nil
 }
 
 // Update implements rest.Updater
@@ -163,7 +185,7 @@ func (s *Store) Update(ctx context.Context, name string, objInfo rest.UpdatedObj
 	}
 
 	doUpdate := func() (*unstructured.Unstructured, error) {
-		oldObj, err := s.getter.Get(ctx, name, &metav1.GetOptions{})
+		oldObj, err := s.getter.Get(withPrimaryReads(ctx), name, &metav1.GetOptions{})
 		if err != nil {
 			return nil, err
 		}
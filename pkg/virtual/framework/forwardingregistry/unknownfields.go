@@ -0,0 +1,136 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package forwardingregistry
+
+import (
+	"context"
+
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/schema/pruning"
+	"k8s.io/apiextensions-apiserver/pkg/registry/customresource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/registry/rest"
+)
+
+// UnknownFieldPolicy determines what happens on update to the fields of stored objects
+// that are unknown to the served schema, e.g. because a newer version of kcp or of the
+// API wrote them before a rollback.
+type UnknownFieldPolicy string
+
+const (
+	// PruneUnknownFields drops the unknown fields, like the API server does for CRDs
+	// without x-kubernetes-preserve-unknown-fields.
+	PruneUnknownFields UnknownFieldPolicy = "Prune"
+
+	// PreserveUnknownFields keeps the unknown fields of the stored object, unless the update
+	// removes the object holding them. Fields in list items are not preserved, because items
+	// cannot be matched reliably after an update.
+	PreserveUnknownFields UnknownFieldPolicy = "Preserve"
+)
+
+// WithUnknownFieldPolicy returns a StorageWrapper applying the given policy with the given
+// schema on updates, and then the given wrapper.
+func WithUnknownFieldPolicy(policy UnknownFieldPolicy, s *structuralschema.Structural, wrapper StorageWrapper) StorageWrapper {
+	if policy != PreserveUnknownFields || s == nil {
+		return wrapper
+	}
+	return func(resource schema.GroupResource, store customresource.Store) customresource.Store {
+		return wrapper(resource, &unknownFieldPreservingStore{Store: store, schema: s})
+	}
+}
+
+type unknownFieldPreservingStore struct {
+	customresource.Store
+
+	schema *structuralschema.Structural
+}
+
+var _ customresource.Store = &unknownFieldPreservingStore{}
+
+// Update implements rest.Updater.
+func (s *unknownFieldPreservingStore) Update(ctx context.Context, name string, objInfo rest.UpdatedObjectInfo, createValidation rest.ValidateObjectFunc, updateValidation rest.ValidateObjectUpdateFunc, forceAllowCreate bool, options *metav1.UpdateOptions) (runtime.Object, bool, error) {
+	return s.Store.Update(ctx, name, &unknownFieldPreservingObjectInfo{UpdatedObjectInfo: objInfo, schema: s.schema}, createValidation, updateValidation, forceAllowCreate, options)
+}
+
+type unknownFieldPreservingObjectInfo struct {
+	rest.UpdatedObjectInfo
+
+	schema *structuralschema.Structural
+}
+
+// UpdatedObject returns the updated object with the unknown fields of the old object, which
+// were pruned when the update was decoded.
+func (i *unknownFieldPreservingObjectInfo) UpdatedObject(ctx context.Context, oldObj runtime.Object) (runtime.Object, error) {
+	obj, err := i.UpdatedObjectInfo.UpdatedObject(ctx, oldObj)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return obj, nil
+	}
+	stored, ok := oldObj.(*unstructured.Unstructured)
+	if !ok {
+		return obj, nil
+	}
+
+	pruned := runtime.DeepCopyJSON(stored.Object)
+	pruning.Prune(pruned, i.schema, true)
+	for k, v := range stored.Object {
+		switch k {
+		case "apiVersion", "kind", "metadata":
+			// owned by the server
+			continue
+		}
+		restoreUnknownField(updated.Object, k, v, pruned)
+	}
+
+	return updated, nil
+}
+
+// restoreUnknownField sets the field k of the stored object in obj if it is unknown, i.e.
+// not in pruned, and not set in obj. Unknown fields of known objects are restored if obj
+// still holds the object.
+func restoreUnknownField(obj map[string]interface{}, k string, stored interface{}, pruned map[string]interface{}) {
+	known, ok := pruned[k]
+	if !ok {
+		if _, set := obj[k]; !set {
+			obj[k] = runtime.DeepCopyJSONValue(stored)
+		}
+		return
+	}
+
+	storedObj, ok := stored.(map[string]interface{})
+	if !ok {
+		return
+	}
+	knownObj, ok := known.(map[string]interface{})
+	if !ok {
+		return
+	}
+	updatedObj, ok := obj[k].(map[string]interface{})
+	if !ok {
+		return
+	}
+	for k, v := range storedObj {
+		restoreUnknownField(updatedObj, k, v, knownObj)
+	}
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package forwardingregistry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	"k8s.io/apiextensions-apiserver/pkg/registry/customresource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/registry/rest"
+)
+
+func TestUnknownFieldPreservingObjectInfo(t *testing.T) {
+	s := &structuralschema.Structural{
+		Generic: structuralschema.Generic{Type: "object"},
+		Properties: map[string]structuralschema.Structural{
+			"spec": {
+				Generic: structuralschema.Generic{Type: "object"},
+				Properties: map[string]structuralschema.Structural{
+					"replicas": {Generic: structuralschema.Generic{Type: "integer"}},
+					"template": {
+						Generic: structuralschema.Generic{Type: "object"},
+						Properties: map[string]structuralschema.Structural{
+							"image": {Generic: structuralschema.Generic{Type: "string"}},
+						},
+					},
+					"ports": {
+						Generic: structuralschema.Generic{Type: "array"},
+						Items: &structuralschema.Structural{
+							Generic: structuralschema.Generic{Type: "object"},
+							Properties: map[string]structuralschema.Structural{
+								"port": {Generic: structuralschema.Generic{Type: "integer"}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	stored := map[string]interface{}{
+		"apiVersion": "example.com/v1",
+		"kind":       "Widget",
+		"metadata":   map[string]interface{}{"name": "a", "resourceVersion": "1"},
+		"spec": map[string]interface{}{
+			"replicas": int64(1),
+			"paused":   true,
+			"template": map[string]interface{}{
+				"image":    "nginx",
+				"strategy": map[string]interface{}{"type": "Rolling"},
+			},
+			"ports": []interface{}{
+				map[string]interface{}{"port": int64(80), "protocol": "TCP"},
+			},
+		},
+		"extension": "value",
+	}
+
+	tests := map[string]struct {
+		updated map[string]interface{}
+		want    map[string]interface{}
+	}{
+		"unknown fields are restored": {
+			updated: map[string]interface{}{
+				"apiVersion": "example.com/v1",
+				"kind":       "Widget",
+				"metadata":   map[string]interface{}{"name": "a", "resourceVersion": "1"},
+				"spec": map[string]interface{}{
+					"replicas": int64(2),
+					"template": map[string]interface{}{"image": "nginx:2"},
+					"ports":    []interface{}{map[string]interface{}{"port": int64(80)}},
+				},
+			},
+			want: map[string]interface{}{
+				"apiVersion": "example.com/v1",
+				"kind":       "Widget",
+				"metadata":   map[string]interface{}{"name": "a", "resourceVersion": "1"},
+				"spec": map[string]interface{}{
+					"replicas": int64(2),
+					"paused":   true,
+					"template": map[string]interface{}{
+						"image":    "nginx:2",
+						"strategy": map[string]interface{}{"type": "Rolling"},
+					},
+					"ports": []interface{}{map[string]interface{}{"port": int64(80)}},
+				},
+				"extension": "value",
+			},
+		},
+		"unknown fields of removed objects are not restored": {
+			updated: map[string]interface{}{
+				"apiVersion": "example.com/v1",
+				"kind":       "Widget",
+				"metadata":   map[string]interface{}{"name": "a", "resourceVersion": "1"},
+				"spec":       map[string]interface{}{"replicas": int64(2)},
+			},
+			want: map[string]interface{}{
+				"apiVersion": "example.com/v1",
+				"kind":       "Widget",
+				"metadata":   map[string]interface{}{"name": "a", "resourceVersion": "1"},
+				"spec": map[string]interface{}{
+					"replicas": int64(2),
+					"paused":   true,
+				},
+				"extension": "value",
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			info := &unknownFieldPreservingObjectInfo{
+				UpdatedObjectInfo: rest.DefaultUpdatedObjectInfo(&unstructured.Unstructured{Object: tt.updated}),
+				schema:            s,
+			}
+			storedObj := &unstructured.Unstructured{Object: stored}
+			storedCopy := storedObj.DeepCopy()
+
+			got, err := info.UpdatedObject(context.Background(), storedObj)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got.(*unstructured.Unstructured).Object)
+			require.Equal(t, storedCopy, storedObj, "the stored object must not be modified")
+		})
+	}
+}
+
+func TestWithUnknownFieldPolicy(t *testing.T) {
+	s := &structuralschema.Structural{Generic: structuralschema.Generic{Type: "object"}}
+	wrapper := func(_ schema.GroupResource, store customresource.Store) customresource.Store {
+		return store
+	}
+	store := &Store{}
+
+	require.Equal(t, store, WithUnknownFieldPolicy(PruneUnknownFields, s, wrapper)(schema.GroupResource{}, store))
+	require.Equal(t, store, WithUnknownFieldPolicy(PreserveUnknownFields, nil, wrapper)(schema.GroupResource{}, store))
+	require.IsType(t, &unknownFieldPreservingStore{}, WithUnknownFieldPolicy(PreserveUnknownFields, s, wrapper)(schema.GroupResource{}, store))
+}
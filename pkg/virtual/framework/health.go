@@ -0,0 +1,157 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+)
+
+// DefaultWatchFailureTolerance is how long the upstream informers of a virtual
+// workspace may fail to list and watch before the virtual workspace is
+// reported as not live.
+const DefaultWatchFailureTolerance = 2 * time.Minute
+
+// InformerHealth tracks whether the upstream informers of a virtual workspace
+// have synced, and whether they keep failing to list and watch.
+type InformerHealth struct {
+	tolerance time.Duration
+	now       func() time.Time
+
+	lock      sync.Mutex
+	informers map[string]cache.SharedIndexInformer
+	watches   map[string]*watchHealth
+}
+
+type watchHealth struct {
+	// failingSince is the time of the first list or watch error since the
+	// last successful list or watch.
+	failingSince time.Time
+	lastErr      error
+}
+
+// NewInformerHealth returns an InformerHealth without any tracked informers.
+func NewInformerHealth(tolerance time.Duration) *InformerHealth {
+	return &InformerHealth{
+		tolerance: tolerance,
+		now:       time.Now,
+		informers: map[string]cache.SharedIndexInformer{},
+		watches:   map[string]*watchHealth{},
+	}
+}
+
+// AddInformer tracks whether the named informer has synced.
+func (h *InformerHealth) AddInformer(name string, informer cache.SharedIndexInformer) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.informers[name] = informer
+}
+
+// ListerWatcher wraps the ListerWatcher of the named informer to track its
+// list and watch failures. Informers that are not built on top of it, e.g.
+// because they are shared with and were created by another component, only
+// count for readiness.
+func (h *InformerHealth) ListerWatcher(name string, lw cache.ListerWatcher) cache.ListerWatcher {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	tracked := &watchHealth{}
+	h.watches[name] = tracked
+	return &trackingListerWatcher{delegate: lw, health: h, tracked: tracked}
+}
+
+func (h *InformerHealth) observe(tracked *watchHealth, err error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if err == nil {
+		tracked.failingSince = time.Time{}
+		tracked.lastErr = nil
+		return
+	}
+	tracked.lastErr = err
+	if tracked.failingSince.IsZero() {
+		tracked.failingSince = h.now()
+	}
+}
+
+type trackingListerWatcher struct {
+	delegate cache.ListerWatcher
+	health   *InformerHealth
+	tracked  *watchHealth
+}
+
+func (lw *trackingListerWatcher) List(options metav1.ListOptions) (runtime.Object, error) {
+	obj, err := lw.delegate.List(options)
+	lw.health.observe(lw.tracked, err)
+	return obj, err
+}
+
+func (lw *trackingListerWatcher) Watch(options metav1.ListOptions) (watch.Interface, error) {
+	w, err := lw.delegate.Watch(options)
+	lw.health.observe(lw.tracked, err)
+	return w, err
+}
+
+// Ready returns an error if one of the informers has not synced yet.
+func (h *InformerHealth) Ready() error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	names := make([]string, 0, len(h.informers))
+	for name := range h.informers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !h.informers[name].HasSynced() {
+			return fmt.Errorf("%s informer not synced", name)
+		}
+	}
+	return nil
+}
+
+// Live returns an error if one of the informers has been failing to list
+// and watch for longer than the tolerance.
+func (h *InformerHealth) Live() error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	names := make([]string, 0, len(h.watches))
+	for name := range h.watches {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		tracked := h.watches[name]
+		if tracked.failingSince.IsZero() {
+			continue
+		}
+		if since := h.now().Sub(tracked.failingSince); since > h.tolerance {
+			return fmt.Errorf("%s informer failing to watch for %s: %v", name, since.Round(time.Second), tracked.lastErr)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+)
+
+type fakeInformer struct {
+	cache.SharedIndexInformer
+
+	synced bool
+}
+
+func (i *fakeInformer) HasSynced() bool { return i.synced }
+
+type fakeListerWatcher struct {
+	err error
+}
+
+func (lw *fakeListerWatcher) List(options metav1.ListOptions) (runtime.Object, error) {
+	return &metav1.List{}, lw.err
+}
+
+func (lw *fakeListerWatcher) Watch(options metav1.ListOptions) (watch.Interface, error) {
+	if lw.err != nil {
+		return nil, lw.err
+	}
+	return watch.NewEmptyWatch(), nil
+}
+
+func TestInformerHealth(t *testing.T) {
+	now := time.Now()
+	h := NewInformerHealth(time.Minute)
+	h.now = func() time.Time { return now }
+
+	informer := &fakeInformer{}
+	h.AddInformer("things", informer)
+	delegate := &fakeListerWatcher{}
+	lw := h.ListerWatcher("things", delegate)
+
+	require.Error(t, h.Ready(), "informer not synced yet")
+	informer.synced = true
+	require.NoError(t, h.Ready())
+	require.NoError(t, h.Live())
+
+	delegate.err = errors.New("connection refused")
+	_, err := lw.Watch(metav1.ListOptions{})
+	require.Error(t, err)
+	require.NoError(t, h.Live(), "failures within tolerance")
+
+	now = now.Add(30 * time.Second)
+	_, err = lw.List(metav1.ListOptions{})
+	require.Error(t, err)
+	require.NoError(t, h.Live(), "repeated failures do not restart the tolerance period")
+
+	now = now.Add(time.Minute)
+	err = h.Live()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "connection refused")
+
+	delegate.err = nil
+	_, err = lw.List(metav1.ListOptions{})
+	require.NoError(t, err)
+	require.NoError(t, h.Live(), "successful list should reset the failure")
+
+	delegate.err = errors.New("connection refused")
+	_, err = lw.List(metav1.ListOptions{})
+	require.Error(t, err)
+	now = now.Add(2 * time.Minute)
+	require.Error(t, h.Live())
+
+	delegate.err = nil
+	_, err = lw.Watch(metav1.ListOptions{})
+	require.NoError(t, err)
+	require.NoError(t, h.Live(), "successful watch should reset the failure")
+}
+
+func TestInformerHealthUntrackedWatches(t *testing.T) {
+	h := NewInformerHealth(time.Minute)
+	h.AddInformer("shared", &fakeInformer{synced: true})
+
+	require.NoError(t, h.Ready())
+	require.NoError(t, h.Live(), "informers not listing through the health tracker only count for readiness")
+}
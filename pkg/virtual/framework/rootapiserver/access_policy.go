@@ -0,0 +1,104 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rootapiserver
+
+import (
+	"fmt"
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/authentication/user"
+	genericapifilters "k8s.io/apiserver/pkg/endpoints/filters"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+
+	"github.com/kcp-dev/kcp/pkg/virtual/framework"
+)
+
+var readOnlyVerbs = sets.NewString("get", "list", "watch")
+
+// withAccessPolicy enforces the access policy of the named virtual workspace on the
+// requests forwarded to it: anonymous requests not allowed by the policy are rejected,
+// and the groups of the policy are added to the user. It must run after the
+// authentication filter.
+func withAccessPolicy(vwName string, policy framework.AccessPolicy, s runtime.NegotiatedSerializer, handler http.Handler) http.Handler {
+	unauthorized := genericapifilters.Unauthorized(s)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+		u, ok := genericapirequest.UserFrom(ctx)
+		if !ok {
+			unauthorized.ServeHTTP(w, req)
+			return
+		}
+
+		if isAnonymous(u) {
+			switch policy.Anonymous {
+			case framework.AnonymousAccessAllowed:
+			case framework.AnonymousAccessReadOnly:
+				if info, ok := genericapirequest.RequestInfoFrom(ctx); !ok || !readOnlyVerbs.Has(info.Verb) {
+					attributes, err := genericapifilters.GetAuthorizerAttributes(ctx)
+					if err != nil {
+						responsewriters.InternalError(w, req, err)
+						return
+					}
+					responsewriters.Forbidden(ctx, attributes, w, req, fmt.Sprintf("virtual workspace %q is read-only for anonymous users", vwName), s)
+					return
+				}
+			default:
+				unauthorized.ServeHTTP(w, req)
+				return
+			}
+		}
+
+		if len(policy.Groups) > 0 {
+			req = req.WithContext(genericapirequest.WithUser(ctx, withGroups(u, policy.Groups)))
+		}
+
+		handler.ServeHTTP(w, req)
+	})
+}
+
+func isAnonymous(u user.Info) bool {
+	if u.GetName() == user.Anonymous {
+		return true
+	}
+	for _, g := range u.GetGroups() {
+		if g == user.AllUnauthenticated {
+			return true
+		}
+	}
+	return false
+}
+
+func withGroups(u user.Info, groups []string) user.Info {
+	existing := sets.NewString(u.GetGroups()...)
+	merged := append([]string(nil), u.GetGroups()...)
+	for _, g := range groups {
+		if !existing.Has(g) {
+			existing.Insert(g)
+			merged = append(merged, g)
+		}
+	}
+	return &user.DefaultInfo{
+		Name:   u.GetName(),
+		UID:    u.GetUID(),
+		Groups: merged,
+		Extra:  u.GetExtra(),
+	}
+}
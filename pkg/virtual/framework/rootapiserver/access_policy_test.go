@@ -0,0 +1,75 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rootapiserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apiserver/pkg/authentication/user"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"github.com/kcp-dev/kcp/pkg/virtual/framework"
+)
+
+func TestWithAccessPolicy(t *testing.T) {
+	anonymous := &user.DefaultInfo{Name: user.Anonymous, Groups: []string{user.AllUnauthenticated}}
+	alice := &user.DefaultInfo{Name: "alice", Groups: []string{"team", user.AllAuthenticated}}
+
+	tests := map[string]struct {
+		policy     framework.AccessPolicy
+		user       user.Info
+		verb       string
+		wantStatus int
+		wantGroups []string
+	}{
+		"no user":                       {user: nil, verb: "get", wantStatus: http.StatusUnauthorized},
+		"anonymous denied by default":   {user: anonymous, verb: "get", wantStatus: http.StatusUnauthorized},
+		"anonymous read-only get":       {policy: framework.AccessPolicy{Anonymous: framework.AnonymousAccessReadOnly}, user: anonymous, verb: "get", wantStatus: http.StatusOK, wantGroups: []string{user.AllUnauthenticated}},
+		"anonymous read-only watch":     {policy: framework.AccessPolicy{Anonymous: framework.AnonymousAccessReadOnly}, user: anonymous, verb: "watch", wantStatus: http.StatusOK, wantGroups: []string{user.AllUnauthenticated}},
+		"anonymous read-only create":    {policy: framework.AccessPolicy{Anonymous: framework.AnonymousAccessReadOnly}, user: anonymous, verb: "create", wantStatus: http.StatusForbidden},
+		"anonymous allowed create":      {policy: framework.AccessPolicy{Anonymous: framework.AnonymousAccessAllowed}, user: anonymous, verb: "create", wantStatus: http.StatusOK, wantGroups: []string{user.AllUnauthenticated}},
+		"authenticated without policy":  {user: alice, verb: "create", wantStatus: http.StatusOK, wantGroups: []string{"team", user.AllAuthenticated}},
+		"authenticated with groups":     {policy: framework.AccessPolicy{Groups: []string{"catalog:viewers", "team"}}, user: alice, verb: "get", wantStatus: http.StatusOK, wantGroups: []string{"team", user.AllAuthenticated, "catalog:viewers"}},
+		"anonymous read-only w/ groups": {policy: framework.AccessPolicy{Anonymous: framework.AnonymousAccessReadOnly, Groups: []string{"catalog:viewers"}}, user: anonymous, verb: "list", wantStatus: http.StatusOK, wantGroups: []string{user.AllUnauthenticated, "catalog:viewers"}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var gotGroups []string
+			handler := withAccessPolicy("catalog", tt.policy, scheme.Codecs.WithoutConversion(), http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				u, ok := genericapirequest.UserFrom(req.Context())
+				require.True(t, ok)
+				gotGroups = u.GetGroups()
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/configmaps", nil)
+			ctx := genericapirequest.WithRequestInfo(req.Context(), &genericapirequest.RequestInfo{IsResourceRequest: true, Verb: tt.verb, APIVersion: "v1", Resource: "configmaps"})
+			if tt.user != nil {
+				ctx = genericapirequest.WithUser(ctx, tt.user)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req.WithContext(ctx))
+
+			require.Equal(t, tt.wantStatus, rec.Code)
+			require.Equal(t, tt.wantGroups, gotGroups)
+		})
+	}
+}
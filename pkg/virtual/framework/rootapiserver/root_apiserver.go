@@ -29,11 +29,16 @@ import (
 	"k8s.io/apiserver/pkg/authorization/authorizerfactory"
 	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
 	genericapiserver "k8s.io/apiserver/pkg/server"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	"k8s.io/apiserver/pkg/warning"
 	"k8s.io/client-go/rest"
 	componentbaseversion "k8s.io/component-base/version"
 
+	kcpfeatures "github.com/kcp-dev/kcp/pkg/features"
+	"github.com/kcp-dev/kcp/pkg/server/compression"
+	"github.com/kcp-dev/kcp/pkg/server/shutdown"
 	"github.com/kcp-dev/kcp/pkg/virtual/framework"
+	"github.com/kcp-dev/kcp/pkg/virtual/framework/celfilter"
 	virtualcontext "github.com/kcp-dev/kcp/pkg/virtual/framework/context"
 )
 
@@ -45,6 +50,10 @@ type RootAPIExtraConfig struct {
 	informerStart func(stopCh <-chan struct{})
 
 	VirtualWorkspaces []framework.VirtualWorkspace
+
+	// WatchTerminator terminates the watches to the virtual workspaces on shutdown.
+	// If nil, the root API server creates one and runs it in a pre-shutdown hook.
+	WatchTerminator *shutdown.WatchTerminator
 }
 
 // Validate helps ensure that we build this config correctly, because there are lots of bits to remember for now
@@ -93,7 +102,6 @@ func (c *completedConfig) WithOpenAPIAggregationController(delegatedAPIServer *g
 func (c completedConfig) New(delegationTarget genericapiserver.DelegationTarget) (*RootAPIServer, error) {
 	delegateAPIServer := delegationTarget
 
-	var readys []framework.ReadyFunc
 	vwNames := sets.NewString()
 	for _, virtualWorkspace := range c.ExtraConfig.VirtualWorkspaces {
 		name := virtualWorkspace.GetName()
@@ -107,12 +115,22 @@ func (c completedConfig) New(delegationTarget genericapiserver.DelegationTarget)
 		if err != nil {
 			return nil, err
 		}
-		readys = append(readys, virtualWorkspace.IsReady)
+
+		// exposed as /readyz/virtual-workspace-<name> and /livez/virtual-workspace-<name>
+		c.GenericConfig.ReadyzChecks = append(c.GenericConfig.ReadyzChecks, &virtualWorkspaceCheck{name: name, check: virtualWorkspace.IsReady})
+		c.GenericConfig.LivezChecks = append(c.GenericConfig.LivezChecks, &virtualWorkspaceCheck{name: name, check: virtualWorkspace.IsLive})
 	}
 
-	c.GenericConfig.BuildHandlerChainFunc = c.getRootHandlerChain(delegateAPIServer)
+	watchTerminator := c.ExtraConfig.WatchTerminator
+	if watchTerminator == nil {
+		watchTerminator = shutdown.NewWatchTerminator()
+	}
+
+	registerUpgradeMetrics()
+	compression.RegisterMetrics()
+
+	c.GenericConfig.BuildHandlerChainFunc = c.getRootHandlerChain(delegateAPIServer, watchTerminator)
 	c.GenericConfig.RequestInfoResolver = c
-	c.GenericConfig.ReadyzChecks = append(c.GenericConfig.ReadyzChecks, asHealthCheck(readys))
 
 	genericServer, err := c.GenericConfig.New("virtual-workspaces-root-apiserver", delegateAPIServer)
 	if err != nil {
@@ -128,23 +146,28 @@ func (c completedConfig) New(delegationTarget genericapiserver.DelegationTarget)
 		c.ExtraConfig.informerStart(context.StopCh)
 		return nil
 	})
+	if c.ExtraConfig.WatchTerminator == nil {
+		s.GenericAPIServer.AddPreShutdownHookOrDie("virtual-workspace-terminate-watches", func() error {
+			watchTerminator.Terminate(c.GenericConfig.ShutdownDelayDuration)
+			return nil
+		})
+	}
 
 	return s, nil
 }
 
-type asHealthCheck []framework.ReadyFunc
+// virtualWorkspaceCheck is a healthz.HealthChecker for a single virtual workspace.
+type virtualWorkspaceCheck struct {
+	name  string
+	check func() error
+}
 
-func (readys asHealthCheck) Name() string {
-	return "VirtualWotrkspaceAdditionalReadinessChecks"
+func (c *virtualWorkspaceCheck) Name() string {
+	return "virtual-workspace-" + c.name
 }
 
-func (readys asHealthCheck) Check(req *http.Request) error {
-	for _, ready := range readys {
-		if err := ready(); err != nil {
-			return err
-		}
-	}
-	return nil
+func (c *virtualWorkspaceCheck) Check(req *http.Request) error {
+	return c.check()
 }
 
 func (c completedConfig) resolveRootPaths(urlPath string, requestContext context.Context) (accepted bool, prefixToStrip string, completedContext context.Context) {
@@ -157,9 +180,18 @@ func (c completedConfig) resolveRootPaths(urlPath string, requestContext context
 	return
 }
 
-func (c completedConfig) getRootHandlerChain(delegateAPIServer genericapiserver.DelegationTarget) func(http.Handler, *genericapiserver.Config) http.Handler {
+func (c completedConfig) accessPolicy(vwName string) framework.AccessPolicy {
+	for _, virtualWorkspace := range c.ExtraConfig.VirtualWorkspaces {
+		if virtualWorkspace.GetName() == vwName {
+			return virtualWorkspace.GetAccessPolicy()
+		}
+	}
+	return framework.AccessPolicy{}
+}
+
+func (c completedConfig) getRootHandlerChain(delegateAPIServer genericapiserver.DelegationTarget, watchTerminator *shutdown.WatchTerminator) func(http.Handler, *genericapiserver.Config) http.Handler {
 	return func(apiHandler http.Handler, genericConfig *genericapiserver.Config) http.Handler {
-		return genericapiserver.DefaultBuildHandlerChain(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 			// detect old kubectl plugins and inject warning headers
 			if req.UserAgent() == "Go-http-client/2.0" {
 				// TODO(sttts): in the future compare the plugin version to the server version and warn outside of skew compatibility guarantees.
@@ -170,15 +202,25 @@ func (c completedConfig) getRootHandlerChain(delegateAPIServer genericapiserver.
 			if accepted, prefixToStrip, context := c.resolveRootPaths(req.URL.Path, req.Context()); accepted {
 				req.URL.Path = strings.TrimPrefix(req.URL.Path, prefixToStrip)
 				req.URL.RawPath = strings.TrimPrefix(req.URL.RawPath, prefixToStrip)
+				if expression := req.URL.Query().Get(celfilter.QueryParameter); expression != "" {
+					context = celfilter.WithExpression(context, expression)
+				}
 				req = req.WithContext(context)
 				delegatedHandler := delegateAPIServer.UnprotectedHandler()
 				if delegatedHandler != nil {
-					delegatedHandler.ServeHTTP(w, req)
+					vwName, _ := context.Value(virtualcontext.VirtualWorkspaceNameKey).(string)
+					withAccessPolicy(vwName, c.accessPolicy(vwName), c.GenericConfig.Serializer, withUpgradeMetrics(vwName, delegatedHandler)).ServeHTTP(w, req)
 				}
 				return
 			}
 			apiHandler.ServeHTTP(w, req)
-		}), c.GenericConfig.Config)
+		})
+		handler = watchTerminator.WithWatchTermination(handler)
+		if utilfeature.DefaultFeatureGate.Enabled(kcpfeatures.ResponseCompression) {
+			// outside of the watch termination, which appends to the stream
+			handler = compression.WithCompression(handler, "virtual-workspaces")
+		}
+		return genericapiserver.DefaultBuildHandlerChain(handler, c.GenericConfig.Config)
 	}
 }
 
@@ -224,8 +266,13 @@ func NewRootAPIConfig(recommendedConfig *genericapiserver.RecommendedConfig, inf
 
 	// TODO: in the future it would probably be a mix between a delegated authorizer (delegating to some KCP instance)
 	// and a specific authorizer whose rules would be defined by each prefix-based virtual workspace.
+	// For now, anonymous requests are only checked against the access policy of the virtual workspace
+	// they are forwarded to, see withAccessPolicy.
 	recommendedConfig.Authorization.Authorizer = authorizerfactory.NewAlwaysAllowAuthorizer()
 
+	// websocket watches and SPDY streams must not run into the timeout of regular requests.
+	recommendedConfig.LongRunningFunc = isLongRunningRequest
+
 	ret := &RootAPIConfig{
 		GenericConfig: recommendedConfig,
 		ExtraConfig: RootAPIExtraConfig{
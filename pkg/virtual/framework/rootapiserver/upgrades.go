@@ -0,0 +1,112 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rootapiserver
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/apimachinery/pkg/util/sets"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	genericfilters "k8s.io/apiserver/pkg/server/filters"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+var (
+	upgradedRequests = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Namespace:      "kcp",
+			Subsystem:      "virtual_workspace",
+			Name:           "upgraded_requests",
+			Help:           "Number of active upgraded requests, like websocket watches and SPDY streams, by virtual workspace and protocol.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"virtual_workspace", "protocol"},
+	)
+
+	upgradedRequestDuration = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Namespace:      "kcp",
+			Subsystem:      "virtual_workspace",
+			Name:           "upgraded_request_duration_seconds",
+			Help:           "Duration of finished upgraded requests, by virtual workspace and protocol.",
+			Buckets:        []float64{1, 10, 60, 300, 900, 1800, 3600, 4 * 3600},
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"virtual_workspace", "protocol"},
+	)
+
+	registerMetrics sync.Once
+)
+
+func registerUpgradeMetrics() {
+	registerMetrics.Do(func() {
+		legacyregistry.MustRegister(upgradedRequests)
+		legacyregistry.MustRegister(upgradedRequestDuration)
+	})
+}
+
+// basicLongRunningRequestCheck matches the long-running requests of kube-apiserver.
+var basicLongRunningRequestCheck = genericfilters.BasicLongRunningRequestCheck(
+	sets.NewString("watch", "proxy"),
+	sets.NewString("attach", "exec", "proxy", "log", "portforward"),
+)
+
+// isLongRunningRequest treats all upgraded requests as long-running, such that
+// websocket and SPDY streams passed through virtual workspaces do not time out
+// like regular requests.
+func isLongRunningRequest(r *http.Request, requestInfo *genericapirequest.RequestInfo) bool {
+	return httpstream.IsUpgradeRequest(r) || basicLongRunningRequestCheck(r, requestInfo)
+}
+
+// upgradeProtocol returns the protocol an upgrade request asks for, as metric label.
+func upgradeProtocol(r *http.Request) string {
+	protocol := strings.ToLower(r.Header.Get(httpstream.HeaderUpgrade))
+	switch {
+	case protocol == "websocket":
+		return "websocket"
+	case strings.HasPrefix(protocol, "spdy/"):
+		return "spdy"
+	default:
+		return "other"
+	}
+}
+
+// withUpgradeMetrics records the upgraded requests served by handler for the
+// given virtual workspace.
+func withUpgradeMetrics(virtualWorkspaceName string, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !httpstream.IsUpgradeRequest(req) {
+			handler.ServeHTTP(w, req)
+			return
+		}
+
+		protocol := upgradeProtocol(req)
+		start := time.Now()
+		upgradedRequests.WithLabelValues(virtualWorkspaceName, protocol).Inc()
+		defer func() {
+			upgradedRequests.WithLabelValues(virtualWorkspaceName, protocol).Dec()
+			upgradedRequestDuration.WithLabelValues(virtualWorkspaceName, protocol).Observe(time.Since(start).Seconds())
+		}()
+
+		handler.ServeHTTP(w, req)
+	})
+}
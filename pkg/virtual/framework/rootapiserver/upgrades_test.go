@@ -0,0 +1,56 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rootapiserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+)
+
+func TestIsLongRunningRequest(t *testing.T) {
+	tests := map[string]struct {
+		upgrade     string
+		requestInfo *genericapirequest.RequestInfo
+		want        bool
+		wantLabel   string
+	}{
+		"get":           {requestInfo: &genericapirequest.RequestInfo{IsResourceRequest: true, Verb: "get", Resource: "pods"}},
+		"watch":         {requestInfo: &genericapirequest.RequestInfo{IsResourceRequest: true, Verb: "watch", Resource: "pods"}, want: true},
+		"exec":          {requestInfo: &genericapirequest.RequestInfo{IsResourceRequest: true, Verb: "create", Resource: "pods", Subresource: "exec"}, want: true},
+		"websocket":     {upgrade: "websocket", requestInfo: &genericapirequest.RequestInfo{IsResourceRequest: true, Verb: "get", Resource: "pods"}, want: true, wantLabel: "websocket"},
+		"spdy":          {upgrade: "SPDY/3.1", requestInfo: &genericapirequest.RequestInfo{IsResourceRequest: true, Verb: "create", Resource: "pods", Subresource: "attach"}, want: true, wantLabel: "spdy"},
+		"other upgrade": {upgrade: "h2c", requestInfo: &genericapirequest.RequestInfo{IsResourceRequest: true, Verb: "get", Resource: "pods"}, want: true, wantLabel: "other"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/services/syncer/root:org:ws/cluster/api/v1/pods", nil)
+			if tt.upgrade != "" {
+				req.Header.Set("Connection", "Upgrade")
+				req.Header.Set("Upgrade", tt.upgrade)
+			}
+			require.Equal(t, tt.want, isLongRunningRequest(req, tt.requestInfo))
+			if tt.wantLabel != "" {
+				require.Equal(t, tt.wantLabel, upgradeProtocol(req))
+			}
+		})
+	}
+}
@@ -33,6 +33,10 @@ type RootPathResolverFunc func(urlPath string, context context.Context) (accepte
 // implementing the VtualWorkspace interface.
 type ReadyFunc func() error
 
+// LiveFunc is the type of liveness check functions exposed by types
+// implementing the VirtualWorkspace interface.
+type LiveFunc func() error
+
 // VirtualWorkspace is the definition of a virtual workspace
 // that will be registered and made available, at a given prefix,
 // inside a Root API server as a delegated API Server.
@@ -46,5 +50,7 @@ type VirtualWorkspace interface {
 	GetName() string
 	ResolveRootPath(urlPath string, context context.Context) (accepted bool, prefixToStrip string, completedContext context.Context)
 	IsReady() error
+	IsLive() error
+	GetAccessPolicy() AccessPolicy
 	Register(rootAPIServerConfig genericapiserver.CompletedConfig, delegateAPIServer genericapiserver.DelegationTarget) (genericapiserver.DelegationTarget, error)
 }
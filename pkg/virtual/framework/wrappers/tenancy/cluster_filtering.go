@@ -54,6 +54,38 @@ func (i *filteredInterface) ClusterWorkspaceShards() tenancyinformers.ClusterWor
 	return FilterWorkspaceShardInformer(i.clusterName, i.informers.ClusterWorkspaceShards())
 }
 
+func (i *filteredInterface) AccessGrants() tenancyinformers.AccessGrantInformer {
+	return FilterAccessGrantInformer(i.clusterName, i.informers.AccessGrants())
+}
+
+func (i *filteredInterface) DenyPolicies() tenancyinformers.DenyPolicyInformer {
+	return FilterDenyPolicyInformer(i.clusterName, i.informers.DenyPolicies())
+}
+
+func (i *filteredInterface) Replications() tenancyinformers.ReplicationInformer {
+	return FilterReplicationInformer(i.clusterName, i.informers.Replications())
+}
+
+func (i *filteredInterface) BulkWorkspaceOperations() tenancyinformers.BulkWorkspaceOperationInformer {
+	return FilterBulkWorkspaceOperationInformer(i.clusterName, i.informers.BulkWorkspaceOperations())
+}
+
+func (i *filteredInterface) PolicyBundles() tenancyinformers.PolicyBundleInformer {
+	return FilterPolicyBundleInformer(i.clusterName, i.informers.PolicyBundles())
+}
+
+func (i *filteredInterface) HomeWorkspacePolicies() tenancyinformers.HomeWorkspacePolicyInformer {
+	return FilterHomeWorkspacePolicyInformer(i.clusterName, i.informers.HomeWorkspacePolicies())
+}
+
+func (i *filteredInterface) ObjectTransfers() tenancyinformers.ObjectTransferInformer {
+	return FilterObjectTransferInformer(i.clusterName, i.informers.ObjectTransfers())
+}
+
+func (i *filteredInterface) VirtualWorkspaces() tenancyinformers.VirtualWorkspaceInformer {
+	return FilterVirtualWorkspaceInformer(i.clusterName, i.informers.VirtualWorkspaces())
+}
+
 func FilterClusterWorkspaceTypeInformer(clusterName logicalcluster.Name, informer tenancyinformers.ClusterWorkspaceTypeInformer) tenancyinformers.ClusterWorkspaceTypeInformer {
 	return &filteredClusterWorkspaceTypeInformer{
 		clusterName: clusterName,
@@ -206,3 +238,411 @@ func (l *filteredWorkspaceShardLister) Get(name string) (*tenancyapis.ClusterWor
 	}
 	return l.lister.Get(name)
 }
+
+func FilterAccessGrantInformer(clusterName logicalcluster.Name, informer tenancyinformers.AccessGrantInformer) tenancyinformers.AccessGrantInformer {
+	return &filteredAccessGrantInformer{
+		clusterName: clusterName,
+		informer:    informer,
+	}
+}
+
+var _ tenancyinformers.AccessGrantInformer = (*filteredAccessGrantInformer)(nil)
+var _ tenancylisters.AccessGrantLister = (*filteredAccessGrantLister)(nil)
+
+type filteredAccessGrantInformer struct {
+	clusterName logicalcluster.Name
+	informer    tenancyinformers.AccessGrantInformer
+}
+
+type filteredAccessGrantLister struct {
+	clusterName logicalcluster.Name
+	lister      tenancylisters.AccessGrantLister
+}
+
+func (i *filteredAccessGrantInformer) Informer() cache.SharedIndexInformer {
+	return i.informer.Informer()
+}
+
+func (i *filteredAccessGrantInformer) Lister() tenancylisters.AccessGrantLister {
+	return &filteredAccessGrantLister{
+		clusterName: i.clusterName,
+		lister:      i.informer.Lister(),
+	}
+}
+
+func (l *filteredAccessGrantLister) List(selector labels.Selector) (ret []*tenancyapis.AccessGrant, err error) {
+	items, err := l.lister.List(selector)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		if logicalcluster.From(item) == l.clusterName {
+			ret = append(ret, item)
+		}
+	}
+	return
+}
+
+func (l *filteredAccessGrantLister) Get(name string) (*tenancyapis.AccessGrant, error) {
+	if clusterName, _ := clusters.SplitClusterAwareKey(name); clusterName.Empty() {
+		name = clusters.ToClusterAwareKey(l.clusterName, name)
+	}
+	return l.lister.Get(name)
+}
+
+func FilterDenyPolicyInformer(clusterName logicalcluster.Name, informer tenancyinformers.DenyPolicyInformer) tenancyinformers.DenyPolicyInformer {
+	return &filteredDenyPolicyInformer{
+		clusterName: clusterName,
+		informer:    informer,
+	}
+}
+
+var _ tenancyinformers.DenyPolicyInformer = (*filteredDenyPolicyInformer)(nil)
+var _ tenancylisters.DenyPolicyLister = (*filteredDenyPolicyLister)(nil)
+
+type filteredDenyPolicyInformer struct {
+	clusterName logicalcluster.Name
+	informer    tenancyinformers.DenyPolicyInformer
+}
+
+type filteredDenyPolicyLister struct {
+	clusterName logicalcluster.Name
+	lister      tenancylisters.DenyPolicyLister
+}
+
+func (i *filteredDenyPolicyInformer) Informer() cache.SharedIndexInformer {
+	return i.informer.Informer()
+}
+
+func (i *filteredDenyPolicyInformer) Lister() tenancylisters.DenyPolicyLister {
+	return &filteredDenyPolicyLister{
+		clusterName: i.clusterName,
+		lister:      i.informer.Lister(),
+	}
+}
+
+func (l *filteredDenyPolicyLister) List(selector labels.Selector) (ret []*tenancyapis.DenyPolicy, err error) {
+	items, err := l.lister.List(selector)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		if logicalcluster.From(item) == l.clusterName {
+			ret = append(ret, item)
+		}
+	}
+	return
+}
+
+func (l *filteredDenyPolicyLister) Get(name string) (*tenancyapis.DenyPolicy, error) {
+	if clusterName, _ := clusters.SplitClusterAwareKey(name); clusterName.Empty() {
+		name = clusters.ToClusterAwareKey(l.clusterName, name)
+	}
+	return l.lister.Get(name)
+}
+
+func FilterReplicationInformer(clusterName logicalcluster.Name, informer tenancyinformers.ReplicationInformer) tenancyinformers.ReplicationInformer {
+	return &filteredReplicationInformer{
+		clusterName: clusterName,
+		informer:    informer,
+	}
+}
+
+var _ tenancyinformers.ReplicationInformer = (*filteredReplicationInformer)(nil)
+var _ tenancylisters.ReplicationLister = (*filteredReplicationLister)(nil)
+
+type filteredReplicationInformer struct {
+	clusterName logicalcluster.Name
+	informer    tenancyinformers.ReplicationInformer
+}
+
+type filteredReplicationLister struct {
+	clusterName logicalcluster.Name
+	lister      tenancylisters.ReplicationLister
+}
+
+func (i *filteredReplicationInformer) Informer() cache.SharedIndexInformer {
+	return i.informer.Informer()
+}
+
+func (i *filteredReplicationInformer) Lister() tenancylisters.ReplicationLister {
+	return &filteredReplicationLister{
+		clusterName: i.clusterName,
+		lister:      i.informer.Lister(),
+	}
+}
+
+func (l *filteredReplicationLister) List(selector labels.Selector) (ret []*tenancyapis.Replication, err error) {
+	items, err := l.lister.List(selector)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		if logicalcluster.From(item) == l.clusterName {
+			ret = append(ret, item)
+		}
+	}
+	return
+}
+
+func (l *filteredReplicationLister) Get(name string) (*tenancyapis.Replication, error) {
+	if clusterName, _ := clusters.SplitClusterAwareKey(name); clusterName.Empty() {
+		name = clusters.ToClusterAwareKey(l.clusterName, name)
+	}
+	return l.lister.Get(name)
+}
+
+func FilterVirtualWorkspaceInformer(clusterName logicalcluster.Name, informer tenancyinformers.VirtualWorkspaceInformer) tenancyinformers.VirtualWorkspaceInformer {
+	return &filteredVirtualWorkspaceInformer{
+		clusterName: clusterName,
+		informer:    informer,
+	}
+}
+
+var _ tenancyinformers.VirtualWorkspaceInformer = (*filteredVirtualWorkspaceInformer)(nil)
+var _ tenancylisters.VirtualWorkspaceLister = (*filteredVirtualWorkspaceLister)(nil)
+
+type filteredVirtualWorkspaceInformer struct {
+	clusterName logicalcluster.Name
+	informer    tenancyinformers.VirtualWorkspaceInformer
+}
+
+type filteredVirtualWorkspaceLister struct {
+	clusterName logicalcluster.Name
+	lister      tenancylisters.VirtualWorkspaceLister
+}
+
+func (i *filteredVirtualWorkspaceInformer) Informer() cache.SharedIndexInformer {
+	return i.informer.Informer()
+}
+
+func (i *filteredVirtualWorkspaceInformer) Lister() tenancylisters.VirtualWorkspaceLister {
+	return &filteredVirtualWorkspaceLister{
+		clusterName: i.clusterName,
+		lister:      i.informer.Lister(),
+	}
+}
+
+func (l *filteredVirtualWorkspaceLister) List(selector labels.Selector) (ret []*tenancyapis.VirtualWorkspace, err error) {
+	items, err := l.lister.List(selector)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		if logicalcluster.From(item) == l.clusterName {
+			ret = append(ret, item)
+		}
+	}
+	return
+}
+
+func (l *filteredVirtualWorkspaceLister) Get(name string) (*tenancyapis.VirtualWorkspace, error) {
+	if clusterName, _ := clusters.SplitClusterAwareKey(name); clusterName.Empty() {
+		name = clusters.ToClusterAwareKey(l.clusterName, name)
+	}
+	return l.lister.Get(name)
+}
+
+func FilterBulkWorkspaceOperationInformer(clusterName logicalcluster.Name, informer tenancyinformers.BulkWorkspaceOperationInformer) tenancyinformers.BulkWorkspaceOperationInformer {
+	return &filteredBulkWorkspaceOperationInformer{
+		clusterName: clusterName,
+		informer:    informer,
+	}
+}
+
+var _ tenancyinformers.BulkWorkspaceOperationInformer = (*filteredBulkWorkspaceOperationInformer)(nil)
+var _ tenancylisters.BulkWorkspaceOperationLister = (*filteredBulkWorkspaceOperationLister)(nil)
+
+type filteredBulkWorkspaceOperationInformer struct {
+	clusterName logicalcluster.Name
+	informer    tenancyinformers.BulkWorkspaceOperationInformer
+}
+
+type filteredBulkWorkspaceOperationLister struct {
+	clusterName logicalcluster.Name
+	lister      tenancylisters.BulkWorkspaceOperationLister
+}
+
+func (i *filteredBulkWorkspaceOperationInformer) Informer() cache.SharedIndexInformer {
+	return i.informer.Informer()
+}
+
+func (i *filteredBulkWorkspaceOperationInformer) Lister() tenancylisters.BulkWorkspaceOperationLister {
+	return &filteredBulkWorkspaceOperationLister{
+		clusterName: i.clusterName,
+		lister:      i.informer.Lister(),
+	}
+}
+
+func (l *filteredBulkWorkspaceOperationLister) List(selector labels.Selector) (ret []*tenancyapis.BulkWorkspaceOperation, err error) {
+	items, err := l.lister.List(selector)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		if logicalcluster.From(item) == l.clusterName {
+			ret = append(ret, item)
+		}
+	}
+	return
+}
+
+func (l *filteredBulkWorkspaceOperationLister) Get(name string) (*tenancyapis.BulkWorkspaceOperation, error) {
+	if clusterName, _ := clusters.SplitClusterAwareKey(name); clusterName.Empty() {
+		name = clusters.ToClusterAwareKey(l.clusterName, name)
+	}
+	return l.lister.Get(name)
+}
+
+func FilterPolicyBundleInformer(clusterName logicalcluster.Name, informer tenancyinformers.PolicyBundleInformer) tenancyinformers.PolicyBundleInformer {
+	return &filteredPolicyBundleInformer{
+		clusterName: clusterName,
+		informer:    informer,
+	}
+}
+
+var _ tenancyinformers.PolicyBundleInformer = (*filteredPolicyBundleInformer)(nil)
+var _ tenancylisters.PolicyBundleLister = (*filteredPolicyBundleLister)(nil)
+
+type filteredPolicyBundleInformer struct {
+	clusterName logicalcluster.Name
+	informer    tenancyinformers.PolicyBundleInformer
+}
+
+type filteredPolicyBundleLister struct {
+	clusterName logicalcluster.Name
+	lister      tenancylisters.PolicyBundleLister
+}
+
+func (i *filteredPolicyBundleInformer) Informer() cache.SharedIndexInformer {
+	return i.informer.Informer()
+}
+
+func (i *filteredPolicyBundleInformer) Lister() tenancylisters.PolicyBundleLister {
+	return &filteredPolicyBundleLister{
+		clusterName: i.clusterName,
+		lister:      i.informer.Lister(),
+	}
+}
+
+func (l *filteredPolicyBundleLister) List(selector labels.Selector) (ret []*tenancyapis.PolicyBundle, err error) {
+	items, err := l.lister.List(selector)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		if logicalcluster.From(item) == l.clusterName {
+			ret = append(ret, item)
+		}
+	}
+	return
+}
+
+func (l *filteredPolicyBundleLister) Get(name string) (*tenancyapis.PolicyBundle, error) {
+	if clusterName, _ := clusters.SplitClusterAwareKey(name); clusterName.Empty() {
+		name = clusters.ToClusterAwareKey(l.clusterName, name)
+	}
+	return l.lister.Get(name)
+}
+
+func FilterHomeWorkspacePolicyInformer(clusterName logicalcluster.Name, informer tenancyinformers.HomeWorkspacePolicyInformer) tenancyinformers.HomeWorkspacePolicyInformer {
+	return &filteredHomeWorkspacePolicyInformer{
+		clusterName: clusterName,
+		informer:    informer,
+	}
+}
+
+var _ tenancyinformers.HomeWorkspacePolicyInformer = (*filteredHomeWorkspacePolicyInformer)(nil)
+var _ tenancylisters.HomeWorkspacePolicyLister = (*filteredHomeWorkspacePolicyLister)(nil)
+
+type filteredHomeWorkspacePolicyInformer struct {
+	clusterName logicalcluster.Name
+	informer    tenancyinformers.HomeWorkspacePolicyInformer
+}
+
+type filteredHomeWorkspacePolicyLister struct {
+	clusterName logicalcluster.Name
+	lister      tenancylisters.HomeWorkspacePolicyLister
+}
+
+func (i *filteredHomeWorkspacePolicyInformer) Informer() cache.SharedIndexInformer {
+	return i.informer.Informer()
+}
+
+func (i *filteredHomeWorkspacePolicyInformer) Lister() tenancylisters.HomeWorkspacePolicyLister {
+	return &filteredHomeWorkspacePolicyLister{
+		clusterName: i.clusterName,
+		lister:      i.informer.Lister(),
+	}
+}
+
+func (l *filteredHomeWorkspacePolicyLister) List(selector labels.Selector) (ret []*tenancyapis.HomeWorkspacePolicy, err error) {
+	items, err := l.lister.List(selector)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		if logicalcluster.From(item) == l.clusterName {
+			ret = append(ret, item)
+		}
+	}
+	return
+}
+
+func (l *filteredHomeWorkspacePolicyLister) Get(name string) (*tenancyapis.HomeWorkspacePolicy, error) {
+	if clusterName, _ := clusters.SplitClusterAwareKey(name); clusterName.Empty() {
+		name = clusters.ToClusterAwareKey(l.clusterName, name)
+	}
+	return l.lister.Get(name)
+}
+
+func FilterObjectTransferInformer(clusterName logicalcluster.Name, informer tenancyinformers.ObjectTransferInformer) tenancyinformers.ObjectTransferInformer {
+	return &filteredObjectTransferInformer{
+		clusterName: clusterName,
+		informer:    informer,
+	}
+}
+
+var _ tenancyinformers.ObjectTransferInformer = (*filteredObjectTransferInformer)(nil)
+var _ tenancylisters.ObjectTransferLister = (*filteredObjectTransferLister)(nil)
+
+type filteredObjectTransferInformer struct {
+	clusterName logicalcluster.Name
+	informer    tenancyinformers.ObjectTransferInformer
+}
+
+type filteredObjectTransferLister struct {
+	clusterName logicalcluster.Name
+	lister      tenancylisters.ObjectTransferLister
+}
+
+func (i *filteredObjectTransferInformer) Informer() cache.SharedIndexInformer {
+	return i.informer.Informer()
+}
+
+func (i *filteredObjectTransferInformer) Lister() tenancylisters.ObjectTransferLister {
+	return &filteredObjectTransferLister{
+		clusterName: i.clusterName,
+		lister:      i.informer.Lister(),
+	}
+}
+
+func (l *filteredObjectTransferLister) List(selector labels.Selector) (ret []*tenancyapis.ObjectTransfer, err error) {
+	items, err := l.lister.List(selector)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		if logicalcluster.From(item) == l.clusterName {
+			ret = append(ret, item)
+		}
+	}
+	return
+}
+
+func (l *filteredObjectTransferLister) Get(name string) (*tenancyapis.ObjectTransfer, error) {
+	if clusterName, _ := clusters.SplitClusterAwareKey(name); clusterName.Empty() {
+		name = clusters.ToClusterAwareKey(l.clusterName, name)
+	}
+	return l.lister.Get(name)
+}
@@ -25,8 +25,11 @@ import (
 
 	kcpclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
 	kcpinformer "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
+	catalogoptions "github.com/kcp-dev/kcp/pkg/virtual/catalog/options"
 	"github.com/kcp-dev/kcp/pkg/virtual/framework"
 	"github.com/kcp-dev/kcp/pkg/virtual/framework/rootapiserver"
+	rbacoptions "github.com/kcp-dev/kcp/pkg/virtual/rbac/options"
+	sharedsecretsoptions "github.com/kcp-dev/kcp/pkg/virtual/sharedsecrets/options"
 	synceroptions "github.com/kcp-dev/kcp/pkg/virtual/syncer/options"
 	workspacesoptions "github.com/kcp-dev/kcp/pkg/virtual/workspaces/options"
 )
@@ -34,14 +37,20 @@ import (
 const virtualWorkspacesFlagPrefix = "virtual-workspaces-"
 
 type Options struct {
-	Workspaces *workspacesoptions.Workspaces
-	Syncer     *synceroptions.Syncer
+	Workspaces    *workspacesoptions.Workspaces
+	Syncer        *synceroptions.Syncer
+	SharedSecrets *sharedsecretsoptions.SharedSecrets
+	RBAC          *rbacoptions.RBAC
+	Catalog       *catalogoptions.Catalog
 }
 
 func NewOptions() *Options {
 	return &Options{
-		Workspaces: workspacesoptions.NewWorkspaces(),
-		Syncer:     synceroptions.NewSyncer(),
+		Workspaces:    workspacesoptions.NewWorkspaces(),
+		Syncer:        synceroptions.NewSyncer(),
+		SharedSecrets: sharedsecretsoptions.NewSharedSecrets(),
+		RBAC:          rbacoptions.NewRBAC(),
+		Catalog:       catalogoptions.NewCatalog(),
 	}
 }
 
@@ -52,6 +61,9 @@ func (v *Options) Validate() []error {
 
 	errs = append(errs, v.Workspaces.Validate(virtualWorkspacesFlagPrefix)...)
 	errs = append(errs, v.Syncer.Validate(virtualWorkspacesFlagPrefix)...)
+	errs = append(errs, v.SharedSecrets.Validate(virtualWorkspacesFlagPrefix)...)
+	errs = append(errs, v.RBAC.Validate(virtualWorkspacesFlagPrefix)...)
+	errs = append(errs, v.Catalog.Validate(virtualWorkspacesFlagPrefix)...)
 
 	return errs
 }
@@ -59,6 +71,7 @@ func (v *Options) Validate() []error {
 // TODO: possibly add the prefix back here (for nicer stuff on the vw standalone commandline)
 func (v *Options) AddFlags(fs *pflag.FlagSet) {
 	v.Workspaces.AddFlags(fs, virtualWorkspacesFlagPrefix)
+	v.Syncer.AddFlags(fs, virtualWorkspacesFlagPrefix)
 }
 
 func (o *Options) NewVirtualWorkspaces(
@@ -84,5 +97,26 @@ func (o *Options) NewVirtualWorkspaces(
 	extraInformers = append(extraInformers, inf...)
 	workspaces = append(workspaces, vws...)
 
+	inf, vws, err = o.SharedSecrets.NewVirtualWorkspaces(rootPathPrefix, kubeClusterClient, dynamicClusterClient, kcpClusterClient, wildcardKubeInformers, wildcardKcpInformers)
+	if err != nil {
+		return nil, nil, err
+	}
+	extraInformers = append(extraInformers, inf...)
+	workspaces = append(workspaces, vws...)
+
+	inf, vws, err = o.RBAC.NewVirtualWorkspaces(rootPathPrefix, kubeClusterClient, dynamicClusterClient, kcpClusterClient, wildcardKubeInformers, wildcardKcpInformers)
+	if err != nil {
+		return nil, nil, err
+	}
+	extraInformers = append(extraInformers, inf...)
+	workspaces = append(workspaces, vws...)
+
+	inf, vws, err = o.Catalog.NewVirtualWorkspaces(rootPathPrefix, kubeClusterClient, dynamicClusterClient, kcpClusterClient, wildcardKubeInformers, wildcardKcpInformers)
+	if err != nil {
+		return nil, nil, err
+	}
+	extraInformers = append(extraInformers, inf...)
+	workspaces = append(workspaces, vws...)
+
 	return extraInformers, workspaces, nil
 }
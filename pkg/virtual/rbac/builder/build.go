@@ -0,0 +1,155 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder
+
+import (
+	"context"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/registry/rest"
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	generatedopenapi "k8s.io/kubernetes/pkg/generated/openapi"
+	kprinters "k8s.io/kubernetes/pkg/printers"
+	printerstorage "k8s.io/kubernetes/pkg/printers/storage"
+
+	"github.com/kcp-dev/kcp/pkg/virtual/framework"
+	"github.com/kcp-dev/kcp/pkg/virtual/framework/fixedgvs"
+	"github.com/kcp-dev/kcp/pkg/virtual/rbac/printers"
+	"github.com/kcp-dev/kcp/pkg/virtual/rbac/registry"
+)
+
+const RBACVirtualWorkspaceName string = "rbac"
+
+// BuildVirtualWorkspace returns a read-only virtual workspace serving the union of the RBAC
+// objects of a workspace and all its descendants under <rootPathPrefix>/<logical-cluster>, each
+// annotated with the workspace it is defined in, for admins to review permissions across a
+// sub-tree with kubectl.
+func BuildVirtualWorkspace(rootPathPrefix string, wildcardKubeInformers informers.SharedInformerFactory, kubeClusterClient kubernetes.ClusterInterface) framework.VirtualWorkspace {
+	informerHealth := framework.NewInformerHealth(framework.DefaultWatchFailureTolerance)
+	rbacInformers := wildcardKubeInformers.Rbac().V1()
+	roleInformer := rbacInformers.Roles().Informer()
+	roleBindingInformer := rbacInformers.RoleBindings().Informer()
+	clusterRoleInformer := rbacInformers.ClusterRoles().Informer()
+	clusterRoleBindingInformer := rbacInformers.ClusterRoleBindings().Informer()
+	informerHealth.AddInformer("roles", roleInformer)
+	informerHealth.AddInformer("rolebindings", roleBindingInformer)
+	informerHealth.AddInformer("clusterroles", clusterRoleInformer)
+	informerHealth.AddInformer("clusterrolebindings", clusterRoleBindingInformer)
+
+	if !strings.HasSuffix(rootPathPrefix, "/") {
+		rootPathPrefix += "/"
+	}
+
+	return &fixedgvs.FixedGroupVersionsVirtualWorkspace{
+		Name:  RBACVirtualWorkspaceName,
+		Ready: informerHealth.Ready,
+		Live:  informerHealth.Live,
+		RootPathResolver: func(urlPath string, requestContext context.Context) (accepted bool, prefixToStrip string, completedContext context.Context) {
+			completedContext = requestContext
+			if path := urlPath; strings.HasPrefix(path, rootPathPrefix) {
+				path = strings.TrimPrefix(path, rootPathPrefix)
+				segments := strings.SplitN(path, "/", 2)
+				if segments[0] == "" {
+					return
+				}
+				clusterName := segments[0]
+
+				return true, rootPathPrefix + clusterName,
+					context.WithValue(requestContext, registry.RootClusterKey, logicalcluster.New(clusterName))
+			}
+			return
+		},
+		GroupVersionAPISets: []fixedgvs.GroupVersionAPISet{
+			{
+				GroupVersion:       rbacv1.SchemeGroupVersion,
+				AddToScheme:        rbacv1.AddToScheme,
+				OpenAPIDefinitions: generatedopenapi.GetOpenAPIDefinitions,
+				BootstrapRestResources: func(mainConfig genericapiserver.CompletedConfig) (map[string]fixedgvs.RestStorageBuilder, error) {
+					for _, informer := range []cache.SharedIndexInformer{roleInformer, roleBindingInformer, clusterRoleInformer, clusterRoleBindingInformer} {
+						if _, found := informer.GetIndexer().GetIndexers()[registry.IndexBySubtree]; !found {
+							if err := informer.AddIndexers(cache.Indexers{
+								registry.IndexBySubtree: registry.IndexBySubtreeFunc,
+							}); err != nil {
+								return nil, err
+							}
+						}
+					}
+
+					tableConvertor := printerstorage.TableConvertor{TableGenerator: kprinters.NewTableGenerator().With(printers.AddRBACPrintHandlers)}
+					storages := map[string]rest.Storage{
+						"roles": registry.NewREST(rbacv1.Resource("roles"), true,
+							func() runtime.Object { return &rbacv1.Role{} },
+							func(items []runtime.Object) runtime.Object {
+								list := &rbacv1.RoleList{}
+								for _, item := range items {
+									list.Items = append(list.Items, *item.(*rbacv1.Role))
+								}
+								return list
+							},
+							roleInformer.GetIndexer(), kubeClusterClient, tableConvertor),
+						"rolebindings": registry.NewREST(rbacv1.Resource("rolebindings"), true,
+							func() runtime.Object { return &rbacv1.RoleBinding{} },
+							func(items []runtime.Object) runtime.Object {
+								list := &rbacv1.RoleBindingList{}
+								for _, item := range items {
+									list.Items = append(list.Items, *item.(*rbacv1.RoleBinding))
+								}
+								return list
+							},
+							roleBindingInformer.GetIndexer(), kubeClusterClient, tableConvertor),
+						"clusterroles": registry.NewREST(rbacv1.Resource("clusterroles"), false,
+							func() runtime.Object { return &rbacv1.ClusterRole{} },
+							func(items []runtime.Object) runtime.Object {
+								list := &rbacv1.ClusterRoleList{}
+								for _, item := range items {
+									list.Items = append(list.Items, *item.(*rbacv1.ClusterRole))
+								}
+								return list
+							},
+							clusterRoleInformer.GetIndexer(), kubeClusterClient, tableConvertor),
+						"clusterrolebindings": registry.NewREST(rbacv1.Resource("clusterrolebindings"), false,
+							func() runtime.Object { return &rbacv1.ClusterRoleBinding{} },
+							func(items []runtime.Object) runtime.Object {
+								list := &rbacv1.ClusterRoleBindingList{}
+								for _, item := range items {
+									list.Items = append(list.Items, *item.(*rbacv1.ClusterRoleBinding))
+								}
+								return list
+							},
+							clusterRoleBindingInformer.GetIndexer(), kubeClusterClient, tableConvertor),
+					}
+
+					builders := map[string]fixedgvs.RestStorageBuilder{}
+					for resource, storage := range storages {
+						storage := storage
+						builders[resource] = func(apiGroupAPIServerConfig genericapiserver.CompletedConfig) (rest.Storage, error) {
+							return storage, nil
+						}
+					}
+					return builders, nil
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/duration"
+	kprinters "k8s.io/kubernetes/pkg/printers"
+)
+
+// AddRBACPrintHandlers adds the table handlers of RBAC objects of several workspaces, showing
+// the workspace each object is defined in.
+func AddRBACPrintHandlers(h kprinters.PrintHandler) {
+	roleColumnDefinitions := []metav1.TableColumnDefinition{
+		{Name: "Name", Type: "string", Format: "name", Description: metav1.ObjectMeta{}.SwaggerDoc()["name"]},
+		{Name: "Workspace", Type: "string", Description: "Workspace the object is defined in"},
+		{Name: "Created At", Type: "date", Description: metav1.ObjectMeta{}.SwaggerDoc()["creationTimestamp"]},
+	}
+	bindingColumnDefinitions := []metav1.TableColumnDefinition{
+		{Name: "Name", Type: "string", Format: "name", Description: metav1.ObjectMeta{}.SwaggerDoc()["name"]},
+		{Name: "Workspace", Type: "string", Description: "Workspace the object is defined in"},
+		{Name: "Role", Type: "string", Description: rbacv1.RoleBinding{}.SwaggerDoc()["roleRef"]},
+		{Name: "Age", Type: "string", Description: metav1.ObjectMeta{}.SwaggerDoc()["creationTimestamp"]},
+		{Name: "Subjects", Type: "string", Priority: 1, Description: rbacv1.RoleBinding{}.SwaggerDoc()["subjects"]},
+	}
+
+	for _, err := range []error{
+		h.TableHandler(roleColumnDefinitions, printRole),
+		h.TableHandler(roleColumnDefinitions, printRoleList),
+		h.TableHandler(roleColumnDefinitions, printClusterRole),
+		h.TableHandler(roleColumnDefinitions, printClusterRoleList),
+		h.TableHandler(bindingColumnDefinitions, printRoleBinding),
+		h.TableHandler(bindingColumnDefinitions, printRoleBindingList),
+		h.TableHandler(bindingColumnDefinitions, printClusterRoleBinding),
+		h.TableHandler(bindingColumnDefinitions, printClusterRoleBindingList),
+	} {
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+func printRole(obj *rbacv1.Role, options kprinters.GenerateOptions) ([]metav1.TableRow, error) {
+	return []metav1.TableRow{roleRow(obj)}, nil
+}
+
+func printRoleList(list *rbacv1.RoleList, options kprinters.GenerateOptions) ([]metav1.TableRow, error) {
+	rows := make([]metav1.TableRow, 0, len(list.Items))
+	for i := range list.Items {
+		rows = append(rows, roleRow(&list.Items[i]))
+	}
+	return rows, nil
+}
+
+func printClusterRole(obj *rbacv1.ClusterRole, options kprinters.GenerateOptions) ([]metav1.TableRow, error) {
+	return []metav1.TableRow{roleRow(obj)}, nil
+}
+
+func printClusterRoleList(list *rbacv1.ClusterRoleList, options kprinters.GenerateOptions) ([]metav1.TableRow, error) {
+	rows := make([]metav1.TableRow, 0, len(list.Items))
+	for i := range list.Items {
+		rows = append(rows, roleRow(&list.Items[i]))
+	}
+	return rows, nil
+}
+
+func printRoleBinding(obj *rbacv1.RoleBinding, options kprinters.GenerateOptions) ([]metav1.TableRow, error) {
+	return []metav1.TableRow{bindingRow(obj, obj.RoleRef, obj.Subjects, options)}, nil
+}
+
+func printRoleBindingList(list *rbacv1.RoleBindingList, options kprinters.GenerateOptions) ([]metav1.TableRow, error) {
+	rows := make([]metav1.TableRow, 0, len(list.Items))
+	for i := range list.Items {
+		rows = append(rows, bindingRow(&list.Items[i], list.Items[i].RoleRef, list.Items[i].Subjects, options))
+	}
+	return rows, nil
+}
+
+func printClusterRoleBinding(obj *rbacv1.ClusterRoleBinding, options kprinters.GenerateOptions) ([]metav1.TableRow, error) {
+	return []metav1.TableRow{bindingRow(obj, obj.RoleRef, obj.Subjects, options)}, nil
+}
+
+func printClusterRoleBindingList(list *rbacv1.ClusterRoleBindingList, options kprinters.GenerateOptions) ([]metav1.TableRow, error) {
+	rows := make([]metav1.TableRow, 0, len(list.Items))
+	for i := range list.Items {
+		rows = append(rows, bindingRow(&list.Items[i], list.Items[i].RoleRef, list.Items[i].Subjects, options))
+	}
+	return rows, nil
+}
+
+type object interface {
+	runtime.Object
+	metav1.Object
+}
+
+func roleRow(obj object) metav1.TableRow {
+	row := metav1.TableRow{
+		Object: runtime.RawExtension{Object: obj},
+	}
+	row.Cells = append(row.Cells, obj.GetName(), logicalcluster.From(obj).String(), obj.GetCreationTimestamp().UTC().Format(time.RFC3339))
+	return row
+}
+
+func bindingRow(obj object, roleRef rbacv1.RoleRef, subjects []rbacv1.Subject, options kprinters.GenerateOptions) metav1.TableRow {
+	row := metav1.TableRow{
+		Object: runtime.RawExtension{Object: obj},
+	}
+	row.Cells = append(row.Cells, obj.GetName(), logicalcluster.From(obj).String(), roleRef.Kind+"/"+roleRef.Name, age(obj.GetCreationTimestamp()))
+	if options.Wide {
+		names := make([]string, 0, len(subjects))
+		for _, subject := range subjects {
+			if subject.Kind == rbacv1.ServiceAccountKind {
+				names = append(names, fmt.Sprintf("%s:%s/%s", subject.Kind, subject.Namespace, subject.Name))
+			} else {
+				names = append(names, fmt.Sprintf("%s:%s", subject.Kind, subject.Name))
+			}
+		}
+		row.Cells = append(row.Cells, strings.Join(names, ", "))
+	}
+	return row
+}
+
+func age(timestamp metav1.Time) string {
+	if timestamp.IsZero() {
+		return "<unknown>"
+	}
+	return duration.HumanDuration(time.Since(timestamp.Time))
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metainternal "k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"github.com/kcp-dev/kcp/pkg/authorization/delegated"
+)
+
+type RBACKeyType string
+
+const (
+	// RootClusterKey is the context key of the logical cluster whose sub-tree is served.
+	RootClusterKey RBACKeyType = "VirtualWorkspaceRBACRootCluster"
+
+	// OriginWorkspaceAnnotation is set on the served RBAC objects to the logical cluster they
+	// are defined in.
+	OriginWorkspaceAnnotation = "rbac.kcp.dev/origin-workspace"
+
+	// IndexBySubtree is the name of the index of RBAC objects by their logical cluster and
+	// all its ancestors.
+	IndexBySubtree = "rbacBySubtree"
+)
+
+// IndexBySubtreeFunc indexes RBAC objects by their logical cluster and all its ancestors,
+// such that the objects of a sub-tree are those of the index value of its root.
+func IndexBySubtreeFunc(obj interface{}) ([]string, error) {
+	metaObj, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, fmt.Errorf("obj is supposed to be a metav1.Object, but is %T", obj)
+	}
+
+	clusterName := logicalcluster.From(metaObj).String()
+	segments := strings.Split(clusterName, ":")
+	keys := make([]string, 0, len(segments))
+	for i := range segments {
+		keys = append(keys, strings.Join(segments[:i+1], ":"))
+	}
+	return keys, nil
+}
+
+// REST lists the RBAC objects of one resource in a sub-tree of workspaces, for the users
+// that can list them in the root of the sub-tree. It is read-only.
+type REST struct {
+	resource   schema.GroupResource
+	namespaced bool
+	newFunc    func() runtime.Object
+	// newListFunc returns a list of the given objects, which are of the type of newFunc.
+	newListFunc func(items []runtime.Object) runtime.Object
+
+	// indexer must have the IndexBySubtree index.
+	indexer cache.Indexer
+
+	kubeClusterClient kubernetes.ClusterInterface
+
+	// delegatedAuthz implements cluster-aware SubjectAccessReview
+	delegatedAuthz delegated.DelegatedAuthorizerFactory
+
+	rest.TableConvertor
+}
+
+var _ rest.Lister = &REST{}
+var _ rest.Scoper = &REST{}
+
+// NewREST returns a REST storage listing the objects of the indexer.
+func NewREST(
+	resource schema.GroupResource,
+	namespaced bool,
+	newFunc func() runtime.Object,
+	newListFunc func(items []runtime.Object) runtime.Object,
+	indexer cache.Indexer,
+	kubeClusterClient kubernetes.ClusterInterface,
+	tableConvertor rest.TableConvertor,
+) *REST {
+	return &REST{
+		resource:          resource,
+		namespaced:        namespaced,
+		newFunc:           newFunc,
+		newListFunc:       newListFunc,
+		indexer:           indexer,
+		kubeClusterClient: kubeClusterClient,
+		delegatedAuthz:    delegated.NewDelegatedAuthorizer,
+		TableConvertor:    tableConvertor,
+	}
+}
+
+// New returns a new object of the resource.
+func (s *REST) New() runtime.Object {
+	return s.newFunc()
+}
+
+// Destroy implements rest.Storage
+func (s *REST) Destroy() {
+	// Do nothing
+}
+
+// NewList returns a new list of the resource.
+func (s *REST) NewList() runtime.Object {
+	return s.newListFunc(nil)
+}
+
+func (s *REST) NamespaceScoped() bool {
+	return s.namespaced
+}
+
+// List retrieves the objects of the sub-tree of the request that match label and field
+// selector. Every object is annotated with the workspace it is defined in.
+func (s *REST) List(ctx context.Context, options *metainternal.ListOptions) (runtime.Object, error) {
+	userInfo, ok := apirequest.UserFrom(ctx)
+	if !ok {
+		return nil, kerrors.NewForbidden(s.resource, "", fmt.Errorf("unable to list %s without a user on the context", s.resource))
+	}
+	rootClusterName, _ := ctx.Value(RootClusterKey).(logicalcluster.Name)
+	if err := s.authorize(ctx, userInfo, rootClusterName); err != nil {
+		return nil, err
+	}
+
+	labelSelector := labels.Everything()
+	if options != nil && options.LabelSelector != nil {
+		labelSelector = options.LabelSelector
+	}
+	namespace := apirequest.NamespaceValue(ctx)
+
+	objs, err := s.indexer.ByIndex(IndexBySubtree, rootClusterName.String())
+	if err != nil {
+		return nil, kerrors.NewInternalError(err)
+	}
+	items := make([]runtime.Object, 0, len(objs))
+	for _, obj := range objs {
+		metaObj, err := meta.Accessor(obj)
+		if err != nil {
+			return nil, kerrors.NewInternalError(err)
+		}
+		if namespace != "" && metaObj.GetNamespace() != namespace {
+			continue
+		}
+		if !labelSelector.Matches(labels.Set(metaObj.GetLabels())) {
+			continue
+		}
+		if options != nil && options.FieldSelector != nil && !options.FieldSelector.Matches(fieldSet(metaObj)) {
+			continue
+		}
+
+		item := obj.(runtime.Object).DeepCopyObject()
+		itemMeta, _ := meta.Accessor(item)
+		annotations := map[string]string{}
+		for k, v := range itemMeta.GetAnnotations() {
+			annotations[k] = v
+		}
+		annotations[OriginWorkspaceAnnotation] = logicalcluster.From(itemMeta).String()
+		itemMeta.SetAnnotations(annotations)
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		a, _ := meta.Accessor(items[i])
+		b, _ := meta.Accessor(items[j])
+		if ac, bc := logicalcluster.From(a).String(), logicalcluster.From(b).String(); ac != bc {
+			return ac < bc
+		}
+		if a.GetNamespace() != b.GetNamespace() {
+			return a.GetNamespace() < b.GetNamespace()
+		}
+		return a.GetName() < b.GetName()
+	})
+
+	return s.newListFunc(items), nil
+}
+
+// fieldSet returns the fields RBAC objects can be selected by.
+func fieldSet(obj metav1.Object) fields.Set {
+	set := fields.Set{
+		"metadata.name": obj.GetName(),
+	}
+	if obj.GetNamespace() != "" {
+		set["metadata.namespace"] = obj.GetNamespace()
+	}
+	return set
+}
+
+// authorize checks that the user can list the resource in the root of the sub-tree.
+func (s *REST) authorize(ctx context.Context, userInfo user.Info, clusterName logicalcluster.Name) error {
+	authz, err := s.delegatedAuthz(clusterName, s.kubeClusterClient)
+	if err != nil {
+		klog.Errorf("failed to get delegated authorizer for logical cluster %s: %v", clusterName, err)
+		return kerrors.NewForbidden(s.resource, "", fmt.Errorf("access to %s of workspace %s not permitted", s.resource, clusterName))
+	}
+	listAttr := authorizer.AttributesRecord{
+		User:            userInfo,
+		Verb:            "list",
+		APIGroup:        s.resource.Group,
+		APIVersion:      "v1",
+		Resource:        s.resource.Resource,
+		ResourceRequest: true,
+	}
+	if decision, reason, err := authz.Authorize(ctx, listAttr); err != nil {
+		klog.Errorf("failed to authorize user %q to list %s in %s: %v", userInfo.GetName(), s.resource, clusterName, err)
+		return kerrors.NewForbidden(s.resource, "", fmt.Errorf("access to %s of workspace %s not permitted", s.resource, clusterName))
+	} else if decision != authorizer.DecisionAllow {
+		klog.V(4).Infof("user %q lacks list permission on %s in %s: %s", userInfo.GetName(), s.resource, clusterName, reason)
+		return kerrors.NewForbidden(s.resource, "", fmt.Errorf("access to %s of workspace %s not permitted", s.resource, clusterName))
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metainternal "k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+func newRoleBinding(clusterName, namespace, name string, labels map[string]string) *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, ClusterName: clusterName, Labels: labels, Annotations: map[string]string{"owner": "platform"}},
+		RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "admin"},
+	}
+}
+
+func TestIndexBySubtreeFunc(t *testing.T) {
+	keys, err := IndexBySubtreeFunc(newRoleBinding("root:org:team", "default", "admins", nil))
+	require.NoError(t, err)
+	require.Equal(t, []string{"root", "root:org", "root:org:team"}, keys)
+}
+
+func TestList(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{
+		IndexBySubtree: IndexBySubtreeFunc,
+	})
+	for _, binding := range []*rbacv1.RoleBinding{
+		newRoleBinding("root:org:b", "default", "admins", map[string]string{"team": "b"}),
+		newRoleBinding("root:org:a", "ci", "admins", map[string]string{"team": "a"}),
+		newRoleBinding("root:org:a", "default", "admins", map[string]string{"team": "a"}),
+		newRoleBinding("root:org:a:nested", "default", "viewers", nil),
+		newRoleBinding("root:org", "default", "org-admins", nil),
+		newRoleBinding("root:organization", "default", "admins", nil),
+		newRoleBinding("root:other:a", "default", "admins", nil),
+	} {
+		require.NoError(t, indexer.Add(binding))
+	}
+
+	tests := map[string]struct {
+		namespace     string
+		labelSelector labels.Selector
+		fieldSelector fields.Selector
+		decision      authorizer.Decision
+		wantErr       bool
+		want          []string
+	}{
+		"whole sub-tree": {
+			decision: authorizer.DecisionAllow,
+			want: []string{
+				"root:org|default/org-admins",
+				"root:org:a|ci/admins",
+				"root:org:a|default/admins",
+				"root:org:a:nested|default/viewers",
+				"root:org:b|default/admins",
+			},
+		},
+		"in namespace": {
+			namespace: "ci",
+			decision:  authorizer.DecisionAllow,
+			want:      []string{"root:org:a|ci/admins"},
+		},
+		"with labels": {
+			labelSelector: labels.SelectorFromSet(labels.Set{"team": "b"}),
+			decision:      authorizer.DecisionAllow,
+			want:          []string{"root:org:b|default/admins"},
+		},
+		"with name": {
+			fieldSelector: fields.OneTermEqualSelector("metadata.name", "viewers"),
+			decision:      authorizer.DecisionAllow,
+			want:          []string{"root:org:a:nested|default/viewers"},
+		},
+		"not allowed": {
+			decision: authorizer.DecisionNoOpinion,
+			wantErr:  true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var authorized logicalcluster.Name
+			s := &REST{
+				resource:   rbacv1.Resource("rolebindings"),
+				namespaced: true,
+				newFunc:    func() runtime.Object { return &rbacv1.RoleBinding{} },
+				newListFunc: func(items []runtime.Object) runtime.Object {
+					list := &rbacv1.RoleBindingList{}
+					for _, item := range items {
+						list.Items = append(list.Items, *item.(*rbacv1.RoleBinding))
+					}
+					return list
+				},
+				indexer: indexer,
+				delegatedAuthz: func(clusterName logicalcluster.Name, client kubernetes.ClusterInterface) (authorizer.Authorizer, error) {
+					authorized = clusterName
+					return authorizer.AuthorizerFunc(func(ctx context.Context, a authorizer.Attributes) (authorizer.Decision, string, error) {
+						require.Equal(t, "list", a.GetVerb())
+						require.Equal(t, "rolebindings", a.GetResource())
+						return tt.decision, "", nil
+					}), nil
+				},
+				TableConvertor: rest.NewDefaultTableConvertor(rbacv1.Resource("rolebindings")),
+			}
+
+			ctx := apirequest.WithUser(context.Background(), &user.DefaultInfo{Name: "org-admin"})
+			ctx = apirequest.WithNamespace(ctx, tt.namespace)
+			ctx = context.WithValue(ctx, RootClusterKey, logicalcluster.New("root:org"))
+
+			obj, err := s.List(ctx, &metainternal.ListOptions{LabelSelector: tt.labelSelector, FieldSelector: tt.fieldSelector})
+			require.Equal(t, "root:org", authorized.String())
+			if tt.wantErr {
+				require.True(t, kerrors.IsForbidden(err), "expected forbidden, got %v", err)
+				return
+			}
+			require.NoError(t, err)
+
+			var got []string
+			for _, binding := range obj.(*rbacv1.RoleBindingList).Items {
+				got = append(got, binding.ClusterName+"|"+binding.Namespace+"/"+binding.Name)
+				require.Equal(t, binding.ClusterName, binding.Annotations[OriginWorkspaceAnnotation])
+				require.Equal(t, "platform", binding.Annotations["owner"])
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+
+	// the objects of the informer must not be mutated.
+	for _, obj := range indexer.List() {
+		require.NotContains(t, obj.(*rbacv1.RoleBinding).Annotations, OriginWorkspaceAnnotation)
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder
+
+import (
+	"context"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	"k8s.io/apiserver/pkg/registry/rest"
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	kcpinformer "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
+	kcpopenapi "github.com/kcp-dev/kcp/pkg/openapi"
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/secretclaim"
+	"github.com/kcp-dev/kcp/pkg/virtual/framework"
+	"github.com/kcp-dev/kcp/pkg/virtual/framework/fixedgvs"
+	"github.com/kcp-dev/kcp/pkg/virtual/sharedsecrets/registry"
+)
+
+const SharedSecretsVirtualWorkspaceName string = "sharedsecrets"
+
+// BuildVirtualWorkspace returns a virtual workspace serving the SecretClaims of a SharedSecret
+// across all workspaces under <rootPathPrefix>/<logical-cluster>/<shared-secret-name>, for
+// providers to audit who claimed their secrets.
+func BuildVirtualWorkspace(rootPathPrefix string, wildcardKcpInformers kcpinformer.SharedInformerFactory, kubeClusterClient kubernetes.ClusterInterface) framework.VirtualWorkspace {
+	informerHealth := framework.NewInformerHealth(framework.DefaultWatchFailureTolerance)
+	secretClaimInformer := wildcardKcpInformers.Apis().V1alpha1().SecretClaims()
+	informerHealth.AddInformer("secretclaims", secretClaimInformer.Informer())
+
+	if !strings.HasSuffix(rootPathPrefix, "/") {
+		rootPathPrefix += "/"
+	}
+
+	return &fixedgvs.FixedGroupVersionsVirtualWorkspace{
+		Name:  SharedSecretsVirtualWorkspaceName,
+		Ready: informerHealth.Ready,
+		Live:  informerHealth.Live,
+		RootPathResolver: func(urlPath string, requestContext context.Context) (accepted bool, prefixToStrip string, completedContext context.Context) {
+			completedContext = requestContext
+			if path := urlPath; strings.HasPrefix(path, rootPathPrefix) {
+				path = strings.TrimPrefix(path, rootPathPrefix)
+				segments := strings.SplitN(path, "/", 3)
+				if len(segments) < 2 || segments[0] == "" || segments[1] == "" {
+					return
+				}
+				clusterName, name := segments[0], segments[1]
+
+				return true, rootPathPrefix + strings.Join(segments[:2], "/"),
+					context.WithValue(
+						context.WithValue(requestContext, registry.SharedSecretClusterKey, logicalcluster.New(clusterName)),
+						registry.SharedSecretNameKey, name,
+					)
+			}
+			return
+		},
+		GroupVersionAPISets: []fixedgvs.GroupVersionAPISet{
+			{
+				GroupVersion:       apisv1alpha1.SchemeGroupVersion,
+				AddToScheme:        apisv1alpha1.AddToScheme,
+				OpenAPIDefinitions: kcpopenapi.GetOpenAPIDefinitions,
+				BootstrapRestResources: func(mainConfig genericapiserver.CompletedConfig) (map[string]fixedgvs.RestStorageBuilder, error) {
+					if _, found := secretClaimInformer.Informer().GetIndexer().GetIndexers()[secretclaim.IndexSecretClaimsBySharedSecret]; !found {
+						if err := secretClaimInformer.Informer().AddIndexers(cache.Indexers{
+							secretclaim.IndexSecretClaimsBySharedSecret: secretclaim.IndexSecretClaimsBySharedSecretFunc,
+						}); err != nil {
+							return nil, err
+						}
+					}
+
+					secretClaimsRest := registry.NewREST(secretClaimInformer.Informer().GetIndexer(), kubeClusterClient)
+					return map[string]fixedgvs.RestStorageBuilder{
+						"secretclaims": func(apiGroupAPIServerConfig genericapiserver.CompletedConfig) (rest.Storage, error) {
+							return secretClaimsRest, nil
+						},
+					}, nil
+				},
+			},
+		},
+	}
+}
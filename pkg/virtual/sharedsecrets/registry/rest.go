@@ -0,0 +1,170 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/kcp-dev/logicalcluster"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metainternal "k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clusters"
+	"k8s.io/klog/v2"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/authorization/delegated"
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/secretclaim"
+)
+
+type SharedSecretKeyType string
+
+const (
+	// SharedSecretClusterKey is the context key of the logical cluster of the audited SharedSecret.
+	SharedSecretClusterKey SharedSecretKeyType = "VirtualWorkspaceSharedSecretCluster"
+	// SharedSecretNameKey is the context key of the name of the audited SharedSecret.
+	SharedSecretNameKey SharedSecretKeyType = "VirtualWorkspaceSharedSecretName"
+)
+
+// REST lists the SecretClaims of a SharedSecret across all workspaces, for the users
+// that can get the SharedSecret.
+type REST struct {
+	// secretClaimIndexer must have the secretclaim.IndexSecretClaimsBySharedSecret index.
+	secretClaimIndexer cache.Indexer
+
+	kubeClusterClient kubernetes.ClusterInterface
+
+	// delegatedAuthz implements cluster-aware SubjectAccessReview
+	delegatedAuthz delegated.DelegatedAuthorizerFactory
+
+	rest.TableConvertor
+}
+
+var _ rest.Lister = &REST{}
+var _ rest.Scoper = &REST{}
+
+// NewREST returns a REST storage listing the SecretClaims of the indexer.
+func NewREST(secretClaimIndexer cache.Indexer, kubeClusterClient kubernetes.ClusterInterface) *REST {
+	return &REST{
+		secretClaimIndexer: secretClaimIndexer,
+		kubeClusterClient:  kubeClusterClient,
+		delegatedAuthz:     delegated.NewDelegatedAuthorizer,
+		TableConvertor:     rest.NewDefaultTableConvertor(apisv1alpha1.Resource("secretclaims")),
+	}
+}
+
+// New returns a new SecretClaim
+func (s *REST) New() runtime.Object {
+	return &apisv1alpha1.SecretClaim{}
+}
+
+// Destroy implements rest.Storage
+func (s *REST) Destroy() {
+	// Do nothing
+}
+
+// NewList returns a new SecretClaimList
+func (*REST) NewList() runtime.Object {
+	return &apisv1alpha1.SecretClaimList{}
+}
+
+func (s *REST) NamespaceScoped() bool {
+	return true
+}
+
+// List retrieves the SecretClaims of the SharedSecret of the request that match label.
+func (s *REST) List(ctx context.Context, options *metainternal.ListOptions) (runtime.Object, error) {
+	userInfo, ok := apirequest.UserFrom(ctx)
+	if !ok {
+		return nil, kerrors.NewForbidden(apisv1alpha1.Resource("secretclaims"), "", fmt.Errorf("unable to list secretclaims without a user on the context"))
+	}
+	clusterName, _ := ctx.Value(SharedSecretClusterKey).(logicalcluster.Name)
+	name, _ := ctx.Value(SharedSecretNameKey).(string)
+	if err := s.authorize(ctx, userInfo, clusterName, name); err != nil {
+		return nil, err
+	}
+
+	selector := labels.Everything()
+	if options != nil && options.LabelSelector != nil {
+		selector = options.LabelSelector
+	}
+	namespace := apirequest.NamespaceValue(ctx)
+
+	objs, err := s.secretClaimIndexer.ByIndex(secretclaim.IndexSecretClaimsBySharedSecret, clusters.ToClusterAwareKey(clusterName, name))
+	if err != nil {
+		return nil, kerrors.NewInternalError(err)
+	}
+	list := &apisv1alpha1.SecretClaimList{}
+	for _, obj := range objs {
+		claim := obj.(*apisv1alpha1.SecretClaim)
+		if namespace != "" && claim.Namespace != namespace {
+			continue
+		}
+		if !selector.Matches(labels.Set(claim.Labels)) {
+			continue
+		}
+		list.Items = append(list.Items, *claim.DeepCopy())
+	}
+	sort.Slice(list.Items, func(i, j int) bool {
+		a, b := list.Items[i], list.Items[j]
+		if a.ClusterName != b.ClusterName {
+			return a.ClusterName < b.ClusterName
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+
+	return list, nil
+}
+
+// authorize checks that the user can get the SharedSecret in its workspace.
+func (s *REST) authorize(ctx context.Context, userInfo user.Info, clusterName logicalcluster.Name, name string) error {
+	authz, err := s.delegatedAuthz(clusterName, s.kubeClusterClient)
+	if err != nil {
+		klog.Errorf("failed to get delegated authorizer for logical cluster %s: %v", clusterName, err)
+		return kerrors.NewForbidden(apisv1alpha1.Resource("sharedsecrets"), name, fmt.Errorf("access to SharedSecret %s|%s not permitted", clusterName, name))
+	}
+	getAttr := authorizer.AttributesRecord{
+		User:            userInfo,
+		Verb:            "get",
+		APIGroup:        apisv1alpha1.SchemeGroupVersion.Group,
+		APIVersion:      apisv1alpha1.SchemeGroupVersion.Version,
+		Resource:        "sharedsecrets",
+		Name:            name,
+		ResourceRequest: true,
+	}
+	if decision, reason, err := authz.Authorize(ctx, getAttr); err != nil {
+		klog.Errorf("failed to authorize user %q to get sharedsecrets %q in %s: %v", userInfo.GetName(), name, clusterName, err)
+		return kerrors.NewForbidden(apisv1alpha1.Resource("sharedsecrets"), name, fmt.Errorf("access to SharedSecret %s|%s not permitted", clusterName, name))
+	} else if decision != authorizer.DecisionAllow {
+		klog.V(4).Infof("user %q lacks get permission on sharedsecrets %q in %s: %s", userInfo.GetName(), name, clusterName, reason)
+		return kerrors.NewForbidden(apisv1alpha1.Resource("sharedsecrets"), name, fmt.Errorf("access to SharedSecret %s|%s not permitted", clusterName, name))
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metainternal "k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/secretclaim"
+)
+
+func newClaim(clusterName, namespace, name, workspaceName, sharedSecretName string, labels map[string]string) *apisv1alpha1.SecretClaim {
+	return &apisv1alpha1.SecretClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, ClusterName: clusterName, Labels: labels},
+		Spec: apisv1alpha1.SecretClaimSpec{
+			Reference: apisv1alpha1.SharedSecretReference{WorkspaceName: workspaceName, SharedSecretName: sharedSecretName},
+		},
+	}
+}
+
+func TestList(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{
+		secretclaim.IndexSecretClaimsBySharedSecret: secretclaim.IndexSecretClaimsBySharedSecretFunc,
+	})
+	for _, claim := range []*apisv1alpha1.SecretClaim{
+		newClaim("root:org:b", "default", "registry", "provider", "registry", map[string]string{"team": "b"}),
+		newClaim("root:org:a", "ci", "registry", "provider", "registry", map[string]string{"team": "a"}),
+		newClaim("root:org:a", "default", "registry", "provider", "registry", map[string]string{"team": "a"}),
+		newClaim("root:org:a", "default", "other", "provider", "other", nil),
+		newClaim("root:other:a", "default", "registry", "provider", "registry", nil),
+	} {
+		require.NoError(t, indexer.Add(claim))
+	}
+
+	tests := map[string]struct {
+		namespace string
+		selector  labels.Selector
+		decision  authorizer.Decision
+		wantErr   bool
+		want      []string
+	}{
+		"all claims": {
+			decision: authorizer.DecisionAllow,
+			want:     []string{"root:org:a|ci/registry", "root:org:a|default/registry", "root:org:b|default/registry"},
+		},
+		"claims in namespace": {
+			namespace: "default",
+			decision:  authorizer.DecisionAllow,
+			want:      []string{"root:org:a|default/registry", "root:org:b|default/registry"},
+		},
+		"claims with labels": {
+			selector: labels.SelectorFromSet(labels.Set{"team": "b"}),
+			decision: authorizer.DecisionAllow,
+			want:     []string{"root:org:b|default/registry"},
+		},
+		"not allowed": {
+			decision: authorizer.DecisionNoOpinion,
+			wantErr:  true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var authorized logicalcluster.Name
+			s := &REST{
+				secretClaimIndexer: indexer,
+				delegatedAuthz: func(clusterName logicalcluster.Name, client kubernetes.ClusterInterface) (authorizer.Authorizer, error) {
+					authorized = clusterName
+					return authorizer.AuthorizerFunc(func(ctx context.Context, a authorizer.Attributes) (authorizer.Decision, string, error) {
+						require.Equal(t, "get", a.GetVerb())
+						require.Equal(t, "sharedsecrets", a.GetResource())
+						require.Equal(t, "registry", a.GetName())
+						return tt.decision, "", nil
+					}), nil
+				},
+				TableConvertor: rest.NewDefaultTableConvertor(apisv1alpha1.Resource("secretclaims")),
+			}
+
+			ctx := apirequest.WithUser(context.Background(), &user.DefaultInfo{Name: "provider-admin"})
+			ctx = apirequest.WithNamespace(ctx, tt.namespace)
+			ctx = context.WithValue(ctx, SharedSecretClusterKey, logicalcluster.New("root:org:provider"))
+			ctx = context.WithValue(ctx, SharedSecretNameKey, "registry")
+
+			obj, err := s.List(ctx, &metainternal.ListOptions{LabelSelector: tt.selector})
+			require.Equal(t, "root:org:provider", authorized.String())
+			if tt.wantErr {
+				require.True(t, kerrors.IsForbidden(err), "expected forbidden, got %v", err)
+				return
+			}
+			require.NoError(t, err)
+
+			var got []string
+			for _, claim := range obj.(*apisv1alpha1.SecretClaimList).Items {
+				got = append(got, claim.ClusterName+"|"+claim.Namespace+"/"+claim.Name)
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -53,6 +53,9 @@ func BuildVirtualWorkspace(rootPathPrefix string, dynamicClusterClient dynamic.C
 
 	readyCh := make(chan struct{})
 
+	informerHealth := framework.NewInformerHealth(framework.DefaultWatchFailureTolerance)
+	trackInformers(informerHealth, wildcardKcpInformers)
+
 	return &virtualworkspacesdynamic.DynamicVirtualWorkspace{
 		Name: SyncerVirtualWorkspaceName,
 		RootPathResolver: func(urlPath string, requestContext context.Context) (accepted bool, prefixToStrip string, completedContext context.Context) {
@@ -115,11 +118,12 @@ func BuildVirtualWorkspace(rootPathPrefix string, dynamicClusterClient dynamic.C
 		Ready: func() error {
 			select {
 			case <-readyCh:
-				return nil
 			default:
 				return errors.New("syncer virtual workspace controllers are not started")
 			}
+			return informerHealth.Ready()
 		},
+		Live: informerHealth.Live,
 		BootstrapAPISetManagement: func(mainConfig genericapiserver.CompletedConfig) (apidefinition.APIDefinitionSetGetter, error) {
 			apiReconciler, err := apireconciler.NewAPIReconciler(
 				kcpClusterClient,
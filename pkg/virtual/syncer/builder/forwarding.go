@@ -74,7 +74,9 @@ func provideForwardingRestStorage(ctx context.Context, clusterClient dynamic.Clu
 			nil,
 			clusterClient,
 			nil,
-			wrapStorageWithLabelSelector(map[string]string{workloadv1alpha1.InternalClusterResourceStateLabelPrefix + workloadClusterName: string(workloadv1alpha1.ResourceStateSync)}),
+			// preserve fields written by newer syncers or shards, e.g. before a rollback
+			registry.WithUnknownFieldPolicy(registry.PreserveUnknownFields, structuralSchema,
+				wrapStorageWithLabelSelector(map[string]string{workloadv1alpha1.InternalClusterResourceStateLabelPrefix + workloadClusterName: string(workloadv1alpha1.ResourceStateSync)})),
 		)
 
 		subresourceStorages = make(map[string]rest.Storage)
@@ -0,0 +1,87 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder
+
+import (
+	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+
+	apiresourcev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apiresource/v1alpha1"
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+	kcpclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
+	kcpinformer "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
+	"github.com/kcp-dev/kcp/pkg/virtual/framework"
+)
+
+// trackInformers registers the upstream informers of the syncer virtual
+// workspace with the factory, listing and watching through the health
+// tracker. It must be called before the informers are requested from the
+// factory, otherwise the existing informers are only tracked for readiness.
+func trackInformers(health *framework.InformerHealth, factory kcpinformer.SharedInformerFactory) {
+	track := func(name string, obj runtime.Object, newListWatch func(client kcpclient.Interface) *cache.ListWatch) {
+		health.AddInformer(name, factory.InformerFor(obj, func(client kcpclient.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+			indexers := cache.Indexers{}
+			for k, v := range factory.ExtraClusterScopedIndexers() {
+				indexers[k] = v
+			}
+			return cache.NewSharedIndexInformerWithOptions(
+				health.ListerWatcher(name, newListWatch(client)),
+				obj,
+				cache.WithResyncPeriod(resyncPeriod),
+				cache.WithIndexers(indexers),
+				cache.WithKeyFunction(factory.KeyFunction()),
+			)
+		}))
+	}
+
+	track("workloadclusters", &workloadv1alpha1.WorkloadCluster{}, func(client kcpclient.Interface) *cache.ListWatch {
+		return &cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return client.WorkloadV1alpha1().WorkloadClusters().List(context.TODO(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return client.WorkloadV1alpha1().WorkloadClusters().Watch(context.TODO(), options)
+			},
+		}
+	})
+	track("negotiatedapiresources", &apiresourcev1alpha1.NegotiatedAPIResource{}, func(client kcpclient.Interface) *cache.ListWatch {
+		return &cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return client.ApiresourceV1alpha1().NegotiatedAPIResources().List(context.TODO(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return client.ApiresourceV1alpha1().NegotiatedAPIResources().Watch(context.TODO(), options)
+			},
+		}
+	})
+	track("apiexports", &apisv1alpha1.APIExport{}, func(client kcpclient.Interface) *cache.ListWatch {
+		return &cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return client.ApisV1alpha1().APIExports().List(context.TODO(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return client.ApisV1alpha1().APIExports().Watch(context.TODO(), options)
+			},
+		}
+	})
+}
@@ -28,6 +28,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clusters"
@@ -66,7 +67,8 @@ func NewAPIReconciler(
 	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
 
 	c := &APIReconciler{
-		kcpClusterClient: kcpClusterClient,
+		kcpClusterClient:    kcpClusterClient,
+		initialQueueDrained: make(chan struct{}),
 
 		workloadClusterLister:  workloadClusterInformer.Lister(),
 		workloadClusterIndexer: workloadClusterInformer.Informer().GetIndexer(),
@@ -81,6 +83,12 @@ func NewAPIReconciler(
 		createAPIDefinition: createAPIDefinition,
 
 		apiSets: map[dynamiccontext.APIDomainKey]apidefinition.APIDefinitionSet{},
+
+		informersSynced: []cache.InformerSynced{
+			workloadClusterInformer.Informer().HasSynced,
+			negotiatedAPIResourceInformer.Informer().HasSynced,
+			apiExportInformer.Informer().HasSynced,
+		},
 	}
 
 	if err := workloadClusterInformer.Informer().AddIndexers(cache.Indexers{
@@ -156,8 +164,20 @@ type APIReconciler struct {
 
 	mutex   sync.RWMutex // protects the map, not the values!
 	apiSets map[dynamiccontext.APIDomainKey]apidefinition.APIDefinitionSet
+
+	informersSynced []cache.InformerSynced
+
+	initialLock sync.Mutex
+	// initialKeys are the keys of the objects known when the informers have
+	// synced that have not been processed successfully yet. It is nil until
+	// the informers have synced.
+	initialKeys sets.String
+	// initialQueueDrained is closed when all initialKeys have been processed.
+	initialQueueDrained chan struct{}
 }
 
+var _ apidefinition.APIDefinitionSetSyncer = &APIReconciler{}
+
 func (c *APIReconciler) enqueueWorkloadCluster(obj interface{}) {
 	key, err := cache.MetaNamespaceKeyFunc(obj)
 	if err != nil {
@@ -183,27 +203,34 @@ func (c *APIReconciler) enqueueWorkloadCluster(obj interface{}) {
 }
 
 func (c *APIReconciler) enqueueNegotiatedAPIResource(obj interface{}) {
-	key, err := cache.MetaNamespaceKeyFunc(obj)
+	for _, resourceKey := range c.negotiatedAPIResourceKeys(obj) {
+		klog.V(2).Infof("Queueing NegotiatedAPIResource %s", resourceKey)
+		c.queue.Add(resourceKey)
+	}
+}
+
+// negotiatedAPIResourceKeys returns the queue keys of a NegotiatedAPIResource,
+// one per workload cluster of its workspace.
+func (c *APIReconciler) negotiatedAPIResourceKeys(obj interface{}) []string {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
 	if err != nil {
 		runtime.HandleError(err)
-		return
+		return nil
 	}
 
 	clusterName, name := clusters.SplitClusterAwareKey(key)
 	workloadClusters, err := c.workloadClusterIndexer.ByIndex(byWorkspace, clusterName.String())
 	if err != nil {
 		runtime.HandleError(err)
-		return
+		return nil
 	}
 
+	keys := make([]string, 0, len(workloadClusters))
 	for _, obj := range workloadClusters {
 		wc := obj.(*workloadv1alpha1.WorkloadCluster)
-
-		resourceKey := wc.Name + "::" + clusters.ToClusterAwareKey(clusterName, name)
-
-		klog.V(2).Infof("Queueing NegotiatedAPIResource %s|%s for workload cluster %s", clusterName, name, wc.Name)
-		c.queue.Add(resourceKey)
+		keys = append(keys, wc.Name+"::"+clusters.ToClusterAwareKey(clusterName, name))
 	}
+	return keys
 }
 
 func apiExportGroupResources(apiExport *apisv1alpha1.APIExport) map[schema.GroupResource]interface{} {
@@ -270,6 +297,8 @@ func (c *APIReconciler) Start(ctx context.Context) {
 
 	go wait.Until(func() { c.startWorker(ctx) }, time.Second, ctx.Done())
 
+	go c.trackInitialKeys(ctx)
+
 	// stop all watches if the controller is stopped
 	defer func() {
 		c.mutex.Lock()
@@ -284,6 +313,63 @@ func (c *APIReconciler) Start(ctx context.Context) {
 	<-ctx.Done()
 }
 
+// trackInitialKeys waits for the informers to sync, and then queues the keys
+// of all objects known at that time. initialQueueDrained is closed once all of
+// them have been processed successfully.
+func (c *APIReconciler) trackInitialKeys(ctx context.Context) {
+	if !cache.WaitForNamedCacheSync(controllerName, ctx.Done(), c.informersSynced...) {
+		return
+	}
+
+	keys := sets.NewString()
+	for _, obj := range c.negotiatedAPIResourceIndexer.List() {
+		keys.Insert(c.negotiatedAPIResourceKeys(obj)...)
+	}
+
+	c.initialLock.Lock()
+	defer c.initialLock.Unlock()
+	c.initialKeys = keys
+	if keys.Len() == 0 {
+		close(c.initialQueueDrained)
+		return
+	}
+	// the event handlers have queued them already, unless processed meanwhile
+	for _, key := range keys.List() {
+		c.queue.Add(key)
+	}
+}
+
+// initialKeyProcessed marks a key as processed, closing initialQueueDrained
+// with the last initial key.
+func (c *APIReconciler) initialKeyProcessed(key string) {
+	c.initialLock.Lock()
+	defer c.initialLock.Unlock()
+
+	if c.initialKeys == nil || !c.initialKeys.Has(key) {
+		return
+	}
+	c.initialKeys.Delete(key)
+	if c.initialKeys.Len() == 0 {
+		close(c.initialQueueDrained)
+	}
+}
+
+// HasSynced returns true when the informers have synced and the API
+// definitions for the initial state have been reconciled.
+func (c *APIReconciler) HasSynced() bool {
+	for _, synced := range c.informersSynced {
+		if !synced() {
+			return false
+		}
+	}
+	select {
+	case <-c.initialQueueDrained:
+		return true
+	default:
+		return false
+	}
+}
+
 func (c *APIReconciler) ShutDown() {
 	c.queue.ShutDown()
 }
@@ -296,6 +382,15 @@ func (c *APIReconciler) processNextWorkItem(ctx context.Context) bool {
 	}
 	key := k.(string)
 
+	// Runs after Done below, such that an initial key counts as processed
+	// only once the queue is done with it.
+	processed := false
+	defer func() {
+		if processed {
+			c.initialKeyProcessed(key)
+		}
+	}()
+
 	// No matter what, tell the queue we're done with this key, to unblock
 	// other workers.
 	defer c.queue.Done(key)
@@ -307,6 +402,7 @@ func (c *APIReconciler) processNextWorkItem(ctx context.Context) bool {
 	}
 
 	c.queue.Forget(key)
+	processed = true
 	return true
 }
 
@@ -444,6 +540,59 @@ func (c *APIReconciler) GetAPIDefinitionSet(_ context.Context, key dynamiccontex
 	return apiSet, ok, nil
 }
 
+var _ apidefinition.APIWarningsGetter = &APIReconciler{}
+
+// GetAPIWarnings returns the warnings of the APIExports in the workspace of the
+// API domain for the resources they export.
+func (c *APIReconciler) GetAPIWarnings(_ context.Context, key dynamiccontext.APIDomainKey, gvr schema.GroupVersionResource) []apidefinition.Warning {
+	clusterName, _ := clusters.SplitClusterAwareKey(string(key))
+	apiExports, err := c.apiExportIndexer.ByIndex(byWorkspace, clusterName.String())
+	if err != nil {
+		runtime.HandleError(err)
+		return nil
+	}
+
+	var warnings []apidefinition.Warning
+	for _, obj := range apiExports {
+		e := obj.(*apisv1alpha1.APIExport)
+		if _, hasAPIExportGR := apiExportGroupResources(e)[gvr.GroupResource()]; !hasAPIExportGR {
+			continue
+		}
+		for _, w := range e.Spec.Warnings {
+			warning := apidefinition.Warning{
+				Resource:  schema.GroupVersionResource{Group: w.Group, Version: w.Version, Resource: w.Resource},
+				FieldPath: w.FieldPath,
+				Message:   w.Message,
+			}
+			if warning.AppliesTo(gvr) {
+				warnings = append(warnings, warning)
+			}
+		}
+	}
+	return warnings
+}
+
+var _ apidefinition.APIGenerationPolicyGetter = &APIReconciler{}
+
+// GetAPIGenerationPolicy enforces the generation convention for the resources exported
+// by the APIExports in the workspace of the API domain. The observed generation is not
+// required, because the syncer reports the status of the downstream objects.
+func (c *APIReconciler) GetAPIGenerationPolicy(_ context.Context, key dynamiccontext.APIDomainKey, gvr schema.GroupVersionResource) *apidefinition.GenerationPolicy {
+	clusterName, _ := clusters.SplitClusterAwareKey(string(key))
+	apiExports, err := c.apiExportIndexer.ByIndex(byWorkspace, clusterName.String())
+	if err != nil {
+		runtime.HandleError(err)
+		return nil
+	}
+
+	for _, obj := range apiExports {
+		if _, hasAPIExportGR := apiExportGroupResources(obj.(*apisv1alpha1.APIExport))[gvr.GroupResource()]; hasAPIExportGR {
+			return &apidefinition.GenerationPolicy{}
+		}
+	}
+	return nil
+}
+
 func resourceNameToGVR(key string) schema.GroupVersionResource {
 	parts := strings.SplitN(key, ".", 3)
 	resource, version, group := parts[0], parts[1], parts[2]
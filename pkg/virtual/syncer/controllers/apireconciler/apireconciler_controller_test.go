@@ -0,0 +1,153 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apireconciler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	apiresourcev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apiresource/v1alpha1"
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+	kcpfakeclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/fake"
+	kcpinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
+	"github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/apidefinition"
+)
+
+type fakeAPIDefinition struct {
+	apidefinition.APIDefinition
+}
+
+func (fakeAPIDefinition) TearDown() {}
+
+func TestHasSyncedAfterInitialKeysProcessed(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := kcpfakeclient.NewSimpleClientset(
+		&workloadv1alpha1.WorkloadCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster", ClusterName: "root:org:ws"}},
+		&apiresourcev1alpha1.NegotiatedAPIResource{ObjectMeta: metav1.ObjectMeta{Name: "widgets.v1.example.io", ClusterName: "root:org:ws"}},
+	)
+	informers := kcpinformers.NewSharedInformerFactory(client, 0)
+
+	called := make(chan string, 100)
+	release := make(chan struct{})
+	c, err := NewAPIReconciler(nil,
+		informers.Workload().V1alpha1().WorkloadClusters(),
+		informers.Apiresource().V1alpha1().NegotiatedAPIResources(),
+		informers.Apis().V1alpha1().APIExports(),
+		func(_ logicalcluster.Name, _ string, spec *apiresourcev1alpha1.CommonAPIResourceSpec, _ string) (apidefinition.APIDefinition, error) {
+			called <- spec.Plural
+			<-release
+			return fakeAPIDefinition{}, nil
+		},
+	)
+	require.NoError(t, err)
+
+	informers.Start(ctx.Done())
+	go c.Start(ctx)
+
+	t.Log("Block processing of the only key, taking it off the queue")
+	select {
+	case <-called:
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Fatal("no key processed")
+	}
+	require.Zero(t, c.queue.Len())
+	time.Sleep(200 * time.Millisecond)
+	require.False(t, c.HasSynced(), "should not be synced while the initial key is being processed")
+
+	t.Log("Finish processing")
+	close(release)
+	require.Eventually(t, c.HasSynced, wait.ForeverTestTimeout, 10*time.Millisecond)
+
+	set, found, err := c.GetAPIDefinitionSet(ctx, "root:org:ws#$#cluster")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Contains(t, set, resourceNameToGVR("widgets.v1.example.io"))
+}
+
+func TestGetAPIWarnings(t *testing.T) {
+	informers := kcpinformers.NewSharedInformerFactory(kcpfakeclient.NewSimpleClientset(), 0)
+	c, err := NewAPIReconciler(nil,
+		informers.Workload().V1alpha1().WorkloadClusters(),
+		informers.Apiresource().V1alpha1().NegotiatedAPIResources(),
+		informers.Apis().V1alpha1().APIExports(),
+		nil,
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, informers.Apis().V1alpha1().APIExports().Informer().GetIndexer().Add(&apisv1alpha1.APIExport{
+		ObjectMeta: metav1.ObjectMeta{Name: "widgets", ClusterName: "root:org:ws"},
+		Spec: apisv1alpha1.APIExportSpec{
+			LatestResourceSchemas: []string{"today.widgets.example.io"},
+			Warnings: []apisv1alpha1.APIWarning{
+				{Group: "example.io", Resource: "widgets", Message: "widgets are going away"},
+				{Group: "example.io", Resource: "widgets", Version: "v1", FieldPath: "spec.color", Message: "spec.color is deprecated"},
+				{Group: "example.io", Resource: "widgets", Version: "v2", Message: "v2 is unstable"},
+				{Group: "example.io", Resource: "gadgets", Message: "not exported"},
+			},
+		},
+	}))
+	require.NoError(t, informers.Apis().V1alpha1().APIExports().Informer().GetIndexer().Add(&apisv1alpha1.APIExport{
+		ObjectMeta: metav1.ObjectMeta{Name: "gadgets", ClusterName: "root:org:other"},
+		Spec: apisv1alpha1.APIExportSpec{
+			LatestResourceSchemas: []string{"today.gadgets.example.io"},
+			Warnings: []apisv1alpha1.APIWarning{
+				{Group: "example.io", Resource: "gadgets", Message: "other workspace"},
+			},
+		},
+	}))
+
+	warnings := c.GetAPIWarnings(context.Background(), "root:org:ws#$#cluster", resourceNameToGVR("widgets.v1.example.io"))
+	require.Equal(t, []apidefinition.Warning{
+		{Resource: schema.GroupVersionResource{Group: "example.io", Resource: "widgets"}, Message: "widgets are going away"},
+		{Resource: resourceNameToGVR("widgets.v1.example.io"), FieldPath: "spec.color", Message: "spec.color is deprecated"},
+	}, warnings)
+
+	require.Empty(t, c.GetAPIWarnings(context.Background(), "root:org:ws#$#cluster", resourceNameToGVR("gadgets.v1.example.io")))
+}
+
+func TestGetAPIGenerationPolicy(t *testing.T) {
+	informers := kcpinformers.NewSharedInformerFactory(kcpfakeclient.NewSimpleClientset(), 0)
+	c, err := NewAPIReconciler(nil,
+		informers.Workload().V1alpha1().WorkloadClusters(),
+		informers.Apiresource().V1alpha1().NegotiatedAPIResources(),
+		informers.Apis().V1alpha1().APIExports(),
+		nil,
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, informers.Apis().V1alpha1().APIExports().Informer().GetIndexer().Add(&apisv1alpha1.APIExport{
+		ObjectMeta: metav1.ObjectMeta{Name: "widgets", ClusterName: "root:org:ws"},
+		Spec: apisv1alpha1.APIExportSpec{
+			LatestResourceSchemas: []string{"today.widgets.example.io"},
+		},
+	}))
+
+	require.Equal(t, &apidefinition.GenerationPolicy{}, c.GetAPIGenerationPolicy(context.Background(), "root:org:ws#$#cluster", resourceNameToGVR("widgets.v1.example.io")))
+	require.Nil(t, c.GetAPIGenerationPolicy(context.Background(), "root:org:ws#$#cluster", resourceNameToGVR("gadgets.v1.example.io")))
+	require.Nil(t, c.GetAPIGenerationPolicy(context.Background(), "root:org:other#$#cluster", resourceNameToGVR("widgets.v1.example.io")))
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package options
 
 import (
+	"fmt"
 	"path"
 
 	"github.com/spf13/pflag"
@@ -24,17 +25,23 @@ import (
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
 
 	kcpclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
 	kcpinformer "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
 	"github.com/kcp-dev/kcp/pkg/virtual/framework"
+	"github.com/kcp-dev/kcp/pkg/virtual/framework/forwardingregistry"
 	"github.com/kcp-dev/kcp/pkg/virtual/framework/rootapiserver"
 	"github.com/kcp-dev/kcp/pkg/virtual/syncer/builder"
 )
 
 const SyncerVirtualWorkspaceName = "syncer"
 
-type Syncer struct{}
+type Syncer struct {
+	// ReadReplicaKubeconfig points to a read replica of all shards, e.g. a cache server,
+	// serving the get, list and watch requests of syncers. Writes always go to the shards.
+	ReadReplicaKubeconfig string
+}
 
 func NewSyncer() *Syncer {
 	return &Syncer{}
@@ -44,6 +51,10 @@ func (o *Syncer) AddFlags(flags *pflag.FlagSet, prefix string) {
 	if o == nil {
 		return
 	}
+
+	flags.StringVar(&o.ReadReplicaKubeconfig, prefix+"syncer-read-replica-kubeconfig", o.ReadReplicaKubeconfig, ""+
+		"The kubeconfig of a read replica of all shards, e.g. a cache server, serving the get, list and watch "+
+		"requests of the syncer virtual workspace. Writes always go to the shards. If empty, reads go to the shards too.")
 }
 
 func (o *Syncer) Validate(flagPrefix string) []error {
@@ -63,6 +74,18 @@ func (o *Syncer) NewVirtualWorkspaces(
 	wildcardKubeInformers informers.SharedInformerFactory,
 	wildcardKcpInformers kcpinformer.SharedInformerFactory,
 ) (extraInformers []rootapiserver.InformerStart, workspaces []framework.VirtualWorkspace, err error) {
+	if o.ReadReplicaKubeconfig != "" {
+		config, err := clientcmd.BuildConfigFromFlags("", o.ReadReplicaKubeconfig)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load read replica kubeconfig: %w", err)
+		}
+		replicaClusterClient, err := dynamic.NewClusterForConfig(config)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create read replica client: %w", err)
+		}
+		dynamicClusterClient = forwardingregistry.NewReadReplicaClusterClient(dynamicClusterClient, replicaClusterClient)
+	}
+
 	virtualWorkspaces := []framework.VirtualWorkspace{
 		builder.BuildVirtualWorkspace(path.Join(rootPathPrefix, o.Name()), dynamicClusterClient, kcpClusterClient, wildcardKcpInformers),
 	}
@@ -26,14 +26,14 @@ import (
 
 	"k8s.io/apiserver/pkg/registry/rest"
 	genericapiserver "k8s.io/apiserver/pkg/server"
-	rbacinformers "k8s.io/client-go/informers/rbac/v1"
+	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 
 	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
 	tenancyv1beta1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1beta1"
 	kcpclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
-	workspaceinformer "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/tenancy/v1alpha1"
+	kcpinformer "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
 	kcpopenapi "github.com/kcp-dev/kcp/pkg/openapi"
 	"github.com/kcp-dev/kcp/pkg/virtual/framework"
 	"github.com/kcp-dev/kcp/pkg/virtual/framework/fixedgvs"
@@ -47,7 +47,12 @@ import (
 
 const WorkspacesVirtualWorkspaceName string = "workspaces"
 
-func BuildVirtualWorkspace(rootPathPrefix string, wildcardsClusterWorkspaces workspaceinformer.ClusterWorkspaceInformer, wildcardsRbacInformers rbacinformers.Interface, kubeClusterClient kubernetes.ClusterInterface, kcpClusterClient kcpclient.ClusterInterface) framework.VirtualWorkspace {
+func BuildVirtualWorkspace(rootPathPrefix string, wildcardKcpInformers kcpinformer.SharedInformerFactory, wildcardKubeInformers informers.SharedInformerFactory, kubeClusterClient kubernetes.ClusterInterface, kcpClusterClient kcpclient.ClusterInterface) framework.VirtualWorkspace {
+	informerHealth := framework.NewInformerHealth(framework.DefaultWatchFailureTolerance)
+	trackInformers(informerHealth, wildcardKcpInformers, wildcardKubeInformers)
+	wildcardsClusterWorkspaces := wildcardKcpInformers.Tenancy().V1alpha1().ClusterWorkspaces()
+	wildcardsRbacInformers := wildcardKubeInformers.Rbac().V1()
+
 	crbInformer := wildcardsRbacInformers.ClusterRoleBindings()
 	_ = registry.AddNameIndexers(crbInformer)
 
@@ -68,8 +73,9 @@ func BuildVirtualWorkspace(rootPathPrefix string, wildcardsClusterWorkspaces wor
 				return errors.New("WorkspaceAuthorizationCache is not ready for access")
 			}
 
-			return nil
+			return informerHealth.Ready()
 		},
+		Live: informerHealth.Live,
 		RootPathResolver: func(urlPath string, requestContext context.Context) (accepted bool, prefixToStrip string, completedContext context.Context) {
 			completedContext = requestContext
 			if path := urlPath; strings.HasPrefix(path, rootPathPrefix) {
@@ -0,0 +1,126 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder
+
+import (
+	"context"
+	"time"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	kcpclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
+	kcpinformer "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
+	"github.com/kcp-dev/kcp/pkg/virtual/framework"
+)
+
+// trackInformers registers the upstream informers of the workspaces virtual
+// workspace with the factories, listing and watching through the health
+// tracker. It must be called before the informers are requested from the
+// factories, otherwise the existing informers are only tracked for readiness.
+func trackInformers(health *framework.InformerHealth, kcpInformers kcpinformer.SharedInformerFactory, kubeInformers informers.SharedInformerFactory) {
+	newInformer := func(name string, obj runtime.Object, lw *cache.ListWatch, resyncPeriod time.Duration, extraIndexers cache.Indexers, keyFunc cache.KeyFunc) cache.SharedIndexInformer {
+		indexers := cache.Indexers{}
+		for k, v := range extraIndexers {
+			indexers[k] = v
+		}
+		return cache.NewSharedIndexInformerWithOptions(
+			health.ListerWatcher(name, lw),
+			obj,
+			cache.WithResyncPeriod(resyncPeriod),
+			cache.WithIndexers(indexers),
+			cache.WithKeyFunction(keyFunc),
+		)
+	}
+	trackKcp := func(name string, obj runtime.Object, newListWatch func(client kcpclient.Interface) *cache.ListWatch) {
+		health.AddInformer(name, kcpInformers.InformerFor(obj, func(client kcpclient.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+			return newInformer(name, obj, newListWatch(client), resyncPeriod, kcpInformers.ExtraClusterScopedIndexers(), kcpInformers.KeyFunction())
+		}))
+	}
+	trackKube := func(name string, obj runtime.Object, extraIndexers cache.Indexers, newListWatch func(client kubernetes.Interface) *cache.ListWatch) {
+		health.AddInformer(name, kubeInformers.InformerFor(obj, func(client kubernetes.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+			return newInformer(name, obj, newListWatch(client), resyncPeriod, extraIndexers, kubeInformers.KeyFunction())
+		}))
+	}
+
+	trackKcp("clusterworkspaces", &tenancyv1alpha1.ClusterWorkspace{}, func(client kcpclient.Interface) *cache.ListWatch {
+		return &cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return client.TenancyV1alpha1().ClusterWorkspaces().List(context.TODO(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return client.TenancyV1alpha1().ClusterWorkspaces().Watch(context.TODO(), options)
+			},
+		}
+	})
+	trackKube("clusterrolebindings", &rbacv1.ClusterRoleBinding{}, kubeInformers.ExtraClusterScopedIndexers(), func(client kubernetes.Interface) *cache.ListWatch {
+		return &cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return client.RbacV1().ClusterRoleBindings().List(context.TODO(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return client.RbacV1().ClusterRoleBindings().Watch(context.TODO(), options)
+			},
+		}
+	})
+	trackKube("rolebindings", &rbacv1.RoleBinding{}, namespaceScopedIndexers(kubeInformers), func(client kubernetes.Interface) *cache.ListWatch {
+		return &cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return client.RbacV1().RoleBindings(metav1.NamespaceAll).List(context.TODO(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return client.RbacV1().RoleBindings(metav1.NamespaceAll).Watch(context.TODO(), options)
+			},
+		}
+	})
+	trackKube("clusterroles", &rbacv1.ClusterRole{}, kubeInformers.ExtraClusterScopedIndexers(), func(client kubernetes.Interface) *cache.ListWatch {
+		return &cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return client.RbacV1().ClusterRoles().List(context.TODO(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return client.RbacV1().ClusterRoles().Watch(context.TODO(), options)
+			},
+		}
+	})
+	trackKube("roles", &rbacv1.Role{}, namespaceScopedIndexers(kubeInformers), func(client kubernetes.Interface) *cache.ListWatch {
+		return &cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return client.RbacV1().Roles(metav1.NamespaceAll).List(context.TODO(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return client.RbacV1().Roles(metav1.NamespaceAll).Watch(context.TODO(), options)
+			},
+		}
+	})
+}
+
+// namespaceScopedIndexers returns the indexers the factory puts on namespaced
+// informers by default.
+func namespaceScopedIndexers(factory informers.SharedInformerFactory) cache.Indexers {
+	indexers := cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}
+	for k, v := range factory.ExtraNamespaceScopedIndexers() {
+		indexers[k] = v
+	}
+	return indexers
+}
@@ -589,7 +589,8 @@ func (s *REST) Create(ctx context.Context, obj runtime.Object, createValidation
 	clusterWorkspace := &tenancyv1alpha1.ClusterWorkspace{
 		ObjectMeta: workspace.ObjectMeta,
 		Spec: tenancyv1alpha1.ClusterWorkspaceSpec{
-			Type: workspace.Spec.Type,
+			Type:       workspace.Spec.Type,
+			Expiration: workspace.Spec.Expiration,
 		},
 	}
 	createdClusterWorkspace, err := s.kcpClusterClient.Cluster(orgClusterName).TenancyV1alpha1().ClusterWorkspaces().Create(ctx, clusterWorkspace, metav1.CreateOptions{})
@@ -0,0 +1,291 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacetemplate
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	apiextensionsinternal "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	crdvalidation "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/validation"
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	structuraldefaulting "k8s.io/apiextensions-apiserver/pkg/apiserver/schema/defaulting"
+	apiservervalidation "k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	kubeyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+// Workspace is the workspace a template is rendered for, available as .Workspace in templates.
+type Workspace struct {
+	// Name is the name of the ClusterWorkspace.
+	Name string
+	// Path is the logical cluster of the workspace, e.g. root:org:team.
+	Path string
+	// Type is the type of the ClusterWorkspace.
+	Type string
+}
+
+var schemaValidationOpts = crdvalidation.ValidationOptions{
+	AllowDefaults:                      true,
+	RequireOpenAPISchema:               true,
+	RequireValidPropertyType:           true,
+	RequireStructuralSchema:            true,
+	RequirePrunedDefaults:              true,
+	RequireAtomicSetType:               true,
+	RequireMapListKeysMapSetValidation: true,
+}
+
+var funcs = template.FuncMap{
+	"quote": strconv.Quote,
+	"toJSON": func(v interface{}) (string, error) {
+		bs, err := json.Marshal(v)
+		return string(bs), err
+	},
+}
+
+// Validate checks that the parameter schema of the template is a structural schema
+// of type object, and that the manifests are valid templates.
+func Validate(t *tenancyv1alpha1.ClusterWorkspaceTemplate, fldPath *field.Path) field.ErrorList {
+	if t == nil {
+		return nil
+	}
+
+	var errs field.ErrorList
+	if t.ParameterSchema != nil {
+		schemaPath := fldPath.Child("parameterSchema")
+		var v1Validation apiextensionsv1.CustomResourceValidation
+		var internalValidation apiextensionsinternal.CustomResourceValidation
+		if err := json.Unmarshal(t.ParameterSchema.Raw, &v1Validation.OpenAPIV3Schema); err != nil {
+			errs = append(errs, field.Invalid(schemaPath, string(t.ParameterSchema.Raw), fmt.Sprintf("invalid JSON: %v", err)))
+		} else if err := apiextensionsv1.Convert_v1_CustomResourceValidation_To_apiextensions_CustomResourceValidation(&v1Validation, &internalValidation, nil); err != nil {
+			errs = append(errs, field.Invalid(schemaPath, string(t.ParameterSchema.Raw), fmt.Sprintf("invalid schema: %v", err)))
+		} else {
+			errs = append(errs, crdvalidation.ValidateCustomResourceDefinitionValidation(&internalValidation, false, schemaValidationOpts, schemaPath)...)
+		}
+	}
+
+	for i, m := range t.Manifests {
+		manifestPath := fldPath.Child("manifests").Index(i)
+		if m.When != "" {
+			if _, err := parse(m.Name+".when", m.When); err != nil {
+				errs = append(errs, field.Invalid(manifestPath.Child("when"), m.When, err.Error()))
+			}
+		}
+		if _, err := parse(m.Name, m.Template); err != nil {
+			errs = append(errs, field.Invalid(manifestPath.Child("template"), m.Template, err.Error()))
+		}
+	}
+
+	return errs
+}
+
+// Parameters validates the given parameters against the parameter schema of the
+// template, and returns them with the defaults of the schema applied. Without a
+// parameter schema, no parameters are allowed.
+func Parameters(t *tenancyv1alpha1.ClusterWorkspaceTemplate, raw *runtime.RawExtension, fldPath *field.Path) (map[string]interface{}, field.ErrorList) {
+	params := map[string]interface{}{}
+	if raw != nil && len(raw.Raw) > 0 && string(raw.Raw) != "null" {
+		if err := utiljson.Unmarshal(raw.Raw, &params); err != nil {
+			return nil, field.ErrorList{field.Invalid(fldPath, string(raw.Raw), "must be a JSON object")}
+		}
+	}
+
+	if t == nil || t.ParameterSchema == nil {
+		if len(params) > 0 {
+			return nil, field.ErrorList{field.Forbidden(fldPath, "the workspace type has no parameter schema")}
+		}
+		return params, nil
+	}
+
+	internalSchema, structural, err := parameterSchema(t.ParameterSchema)
+	if err != nil {
+		// the schema is validated at admission of the type. So this is unexpected.
+		return nil, field.ErrorList{field.InternalError(fldPath, err)}
+	}
+
+	if errs := unknownFields(params, structural, fldPath); len(errs) > 0 {
+		return nil, errs
+	}
+
+	structuraldefaulting.Default(params, structural)
+
+	validator, _, err := apiservervalidation.NewSchemaValidator(&apiextensionsinternal.CustomResourceValidation{OpenAPIV3Schema: internalSchema})
+	if err != nil {
+		return nil, field.ErrorList{field.InternalError(fldPath, err)}
+	}
+	if errs := apiservervalidation.ValidateCustomResource(fldPath, params, validator); len(errs) > 0 {
+		return nil, errs
+	}
+
+	return params, nil
+}
+
+// Render executes the manifests of the template for the given workspace and
+// parameters, and returns the objects they render to in order.
+func Render(t *tenancyv1alpha1.ClusterWorkspaceTemplate, ws Workspace, params map[string]interface{}) ([]*unstructured.Unstructured, error) {
+	if t == nil {
+		return nil, nil
+	}
+
+	data := map[string]interface{}{
+		"Parameters": params,
+		"Workspace":  ws,
+	}
+
+	var objs []*unstructured.Unstructured
+	for _, m := range t.Manifests {
+		if m.When != "" {
+			cond, err := execute(m.Name+".when", m.When, data)
+			if err != nil {
+				return nil, fmt.Errorf("failed to render condition of manifest %q: %w", m.Name, err)
+			}
+			switch strings.TrimSpace(cond) {
+			case "true":
+			case "false":
+				continue
+			default:
+				return nil, fmt.Errorf("condition of manifest %q must render to \"true\" or \"false\", got %q", m.Name, cond)
+			}
+		}
+
+		out, err := execute(m.Name, m.Template, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render manifest %q: %w", m.Name, err)
+		}
+		rendered, err := decode(out)
+		if err != nil {
+			return nil, fmt.Errorf("manifest %q: %w", m.Name, err)
+		}
+		objs = append(objs, rendered...)
+	}
+
+	return objs, nil
+}
+
+func parse(name, text string) (*template.Template, error) {
+	return template.New(name).Option("missingkey=error").Funcs(funcs).Parse(text)
+}
+
+func execute(name, text string, data interface{}) (string, error) {
+	tmpl, err := parse(name, text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// decode splits the given YAML into documents and decodes each into an object.
+func decode(raw string) ([]*unstructured.Unstructured, error) {
+	d := kubeyaml.NewYAMLReader(bufio.NewReader(strings.NewReader(raw)))
+	var objs []*unstructured.Unstructured
+	for i := 1; ; i++ {
+		doc, err := d.Read()
+		if errors.Is(err, io.EOF) {
+			return objs, nil
+		} else if err != nil {
+			return nil, err
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+
+		obj := map[string]interface{}{}
+		if err := yaml.Unmarshal(doc, &obj); err != nil {
+			return nil, fmt.Errorf("invalid YAML in document %d: %w", i, err)
+		}
+		if len(obj) == 0 {
+			continue // only comments
+		}
+		u := &unstructured.Unstructured{Object: obj}
+		if u.GetAPIVersion() == "" || u.GetKind() == "" {
+			return nil, fmt.Errorf("document %d has no apiVersion or kind", i)
+		}
+		if u.GetName() == "" {
+			return nil, fmt.Errorf("document %d has no metadata.name", i)
+		}
+		if u.GetClusterName() != "" {
+			return nil, fmt.Errorf("document %d must not set metadata.clusterName", i)
+		}
+		objs = append(objs, u)
+	}
+}
+
+func parameterSchema(raw *runtime.RawExtension) (*apiextensionsinternal.JSONSchemaProps, *structuralschema.Structural, error) {
+	var v1Schema apiextensionsv1.JSONSchemaProps
+	if err := json.Unmarshal(raw.Raw, &v1Schema); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode parameter schema: %w", err)
+	}
+	internalSchema := &apiextensionsinternal.JSONSchemaProps{}
+	if err := apiextensionsv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(&v1Schema, internalSchema, nil); err != nil {
+		return nil, nil, fmt.Errorf("failed converting parameter schema to internal version: %w", err)
+	}
+	structural, err := structuralschema.NewStructural(internalSchema)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parameter schema is not structural: %w", err)
+	}
+	return internalSchema, structural, nil
+}
+
+// unknownFields returns errors for all fields of x not specified by the given schema.
+func unknownFields(x interface{}, s *structuralschema.Structural, fldPath *field.Path) field.ErrorList {
+	if s == nil || s.XPreserveUnknownFields {
+		return nil
+	}
+
+	var errs field.ErrorList
+	switch x := x.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(x))
+		for k := range x {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if prop, ok := s.Properties[k]; ok {
+				errs = append(errs, unknownFields(x[k], &prop, fldPath.Child(k))...)
+			} else if s.AdditionalProperties != nil && s.AdditionalProperties.Structural != nil {
+				errs = append(errs, unknownFields(x[k], s.AdditionalProperties.Structural, fldPath.Key(k))...)
+			} else if s.AdditionalProperties == nil || !s.AdditionalProperties.Bool {
+				errs = append(errs, field.Forbidden(fldPath.Child(k), "unknown field"))
+			}
+		}
+	case []interface{}:
+		for i, v := range x {
+			errs = append(errs, unknownFields(v, s.Items, fldPath.Index(i))...)
+		}
+	}
+	return errs
+}
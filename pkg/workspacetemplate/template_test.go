@@ -0,0 +1,247 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacetemplate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+const testSchema = `{"type":"object","required":["env"],"properties":{
+	"env":{"type":"string","enum":["dev","stage","prod"]},
+	"replicas":{"type":"integer","minimum":1,"default":1},
+	"team":{"type":"object","properties":{"name":{"type":"string"}}}
+}}`
+
+func TestValidate(t *testing.T) {
+	tests := map[string]struct {
+		template *tenancyv1alpha1.ClusterWorkspaceTemplate
+		wantErr  bool
+	}{
+		"nil": {},
+		"valid": {
+			template: &tenancyv1alpha1.ClusterWorkspaceTemplate{
+				ParameterSchema: &runtime.RawExtension{Raw: []byte(testSchema)},
+				Manifests: []tenancyv1alpha1.ClusterWorkspaceTemplateManifest{
+					{Name: "cm", When: `{{ eq .Parameters.env "prod" }}`, Template: "kind: ConfigMap"},
+				},
+			},
+		},
+		"invalid JSON schema": {
+			template: &tenancyv1alpha1.ClusterWorkspaceTemplate{
+				ParameterSchema: &runtime.RawExtension{Raw: []byte(`{`)},
+			},
+			wantErr: true,
+		},
+		"non-structural schema": {
+			template: &tenancyv1alpha1.ClusterWorkspaceTemplate{
+				ParameterSchema: &runtime.RawExtension{Raw: []byte(`{"type":"object","properties":{"env":{}}}`)},
+			},
+			wantErr: true,
+		},
+		"invalid template": {
+			template: &tenancyv1alpha1.ClusterWorkspaceTemplate{
+				Manifests: []tenancyv1alpha1.ClusterWorkspaceTemplateManifest{
+					{Name: "cm", Template: "{{ .Parameters.env "},
+				},
+			},
+			wantErr: true,
+		},
+		"invalid condition": {
+			template: &tenancyv1alpha1.ClusterWorkspaceTemplate{
+				Manifests: []tenancyv1alpha1.ClusterWorkspaceTemplateManifest{
+					{Name: "cm", When: "{{ if }}", Template: "kind: ConfigMap"},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			errs := Validate(tt.template, field.NewPath("spec", "template"))
+			if tt.wantErr {
+				require.NotEmpty(t, errs)
+			} else {
+				require.Empty(t, errs)
+			}
+		})
+	}
+}
+
+func TestParameters(t *testing.T) {
+	withSchema := &tenancyv1alpha1.ClusterWorkspaceTemplate{
+		ParameterSchema: &runtime.RawExtension{Raw: []byte(testSchema)},
+	}
+
+	tests := map[string]struct {
+		template *tenancyv1alpha1.ClusterWorkspaceTemplate
+		params   string
+		want     map[string]interface{}
+		wantErr  []string
+	}{
+		"no schema, no parameters": {
+			want: map[string]interface{}{},
+		},
+		"no schema, parameters": {
+			params:  `{"env":"dev"}`,
+			wantErr: []string{"spec.templateParameters: Forbidden: the workspace type has no parameter schema"},
+		},
+		"defaulted": {
+			template: withSchema,
+			params:   `{"env":"dev"}`,
+			want:     map[string]interface{}{"env": "dev", "replicas": int64(1)},
+		},
+		"explicit": {
+			template: withSchema,
+			params:   `{"env":"prod","replicas":3,"team":{"name":"a"}}`,
+			want:     map[string]interface{}{"env": "prod", "replicas": int64(3), "team": map[string]interface{}{"name": "a"}},
+		},
+		"unknown fields": {
+			template: withSchema,
+			params:   `{"env":"dev","foo":1,"team":{"bar":2}}`,
+			wantErr: []string{
+				"spec.templateParameters.foo: Forbidden: unknown field",
+				"spec.templateParameters.team.bar: Forbidden: unknown field",
+			},
+		},
+		"not an object": {
+			template: withSchema,
+			params:   `[]`,
+			wantErr:  []string{`spec.templateParameters: Invalid value: "[]": must be a JSON object`},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var raw *runtime.RawExtension
+			if tt.params != "" {
+				raw = &runtime.RawExtension{Raw: []byte(tt.params)}
+			}
+			got, errs := Parameters(tt.template, raw, field.NewPath("spec", "templateParameters"))
+			if tt.wantErr != nil {
+				var msgs []string
+				for _, err := range errs {
+					msgs = append(msgs, err.Error())
+				}
+				require.Equal(t, tt.wantErr, msgs)
+				return
+			}
+			require.Empty(t, errs)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParametersSchemaViolation(t *testing.T) {
+	template := &tenancyv1alpha1.ClusterWorkspaceTemplate{
+		ParameterSchema: &runtime.RawExtension{Raw: []byte(testSchema)},
+	}
+	for _, params := range []string{`{}`, `{"env":"qa"}`, `{"env":"dev","replicas":0}`} {
+		_, errs := Parameters(template, &runtime.RawExtension{Raw: []byte(params)}, field.NewPath("spec", "templateParameters"))
+		require.NotEmpty(t, errs, "expected %s to be invalid", params)
+	}
+}
+
+func TestRender(t *testing.T) {
+	template := &tenancyv1alpha1.ClusterWorkspaceTemplate{
+		Manifests: []tenancyv1alpha1.ClusterWorkspaceTemplateManifest{
+			{
+				Name: "namespace",
+				Template: `apiVersion: v1
+kind: Namespace
+metadata:
+  name: {{ .Parameters.env }}
+  labels:
+    workspace: {{ .Workspace.Name }}
+`,
+			},
+			{
+				Name: "quota",
+				When: `{{ eq .Parameters.env "prod" }}`,
+				Template: `apiVersion: v1
+kind: ResourceQuota
+metadata:
+  name: quota
+  namespace: {{ .Parameters.env }}
+spec:
+  hard:
+    pods: {{ quote (printf "%d" .Parameters.pods) }}
+---
+# only a comment
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: workspace
+  namespace: {{ .Parameters.env }}
+data:
+  path: {{ .Workspace.Path }}
+  type: {{ .Workspace.Type }}
+`,
+			},
+		},
+	}
+	ws := Workspace{Name: "team", Path: "root:org:team", Type: "Team"}
+
+	t.Run("condition false", func(t *testing.T) {
+		objs, err := Render(template, ws, map[string]interface{}{"env": "dev"})
+		require.NoError(t, err)
+		require.Len(t, objs, 1)
+		require.Equal(t, "Namespace", objs[0].GetKind())
+		require.Equal(t, "dev", objs[0].GetName())
+		require.Equal(t, map[string]string{"workspace": "team"}, objs[0].GetLabels())
+	})
+
+	t.Run("condition true", func(t *testing.T) {
+		objs, err := Render(template, ws, map[string]interface{}{"env": "prod", "pods": int64(10)})
+		require.NoError(t, err)
+		require.Len(t, objs, 3)
+		require.Equal(t, "ResourceQuota", objs[1].GetKind())
+		require.Equal(t, "prod", objs[1].GetNamespace())
+		require.Equal(t, map[string]interface{}{"pods": "10"}, objs[1].Object["spec"].(map[string]interface{})["hard"])
+		require.Equal(t, map[string]interface{}{"path": "root:org:team", "type": "Team"}, objs[2].Object["data"])
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		_, err := Render(template, ws, map[string]interface{}{"env": "prod"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `failed to render manifest "quota"`)
+	})
+
+	t.Run("invalid condition result", func(t *testing.T) {
+		_, err := Render(&tenancyv1alpha1.ClusterWorkspaceTemplate{
+			Manifests: []tenancyv1alpha1.ClusterWorkspaceTemplateManifest{
+				{Name: "cm", When: "{{ .Parameters.env }}", Template: "kind: ConfigMap"},
+			},
+		}, ws, map[string]interface{}{"env": "dev"})
+		require.EqualError(t, err, `condition of manifest "cm" must render to "true" or "false", got "dev"`)
+	})
+
+	t.Run("incomplete object", func(t *testing.T) {
+		_, err := Render(&tenancyv1alpha1.ClusterWorkspaceTemplate{
+			Manifests: []tenancyv1alpha1.ClusterWorkspaceTemplateManifest{
+				{Name: "cm", Template: "apiVersion: v1\nkind: ConfigMap\n"},
+			},
+		}, ws, nil)
+		require.EqualError(t, err, `manifest "cm": document 1 has no metadata.name`)
+	})
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllerruntime
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kubernetesclientset "k8s.io/client-go/kubernetes"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	kcpctrl "github.com/kcp-dev/kcp/pkg/controllerruntime"
+	"github.com/kcp-dev/kcp/test/e2e/framework"
+)
+
+const (
+	watchedLabel         = "e2e.kcp.dev/controller-runtime"
+	reconciledAnnotation = "e2e.kcp.dev/reconciled-in"
+)
+
+func TestClusterAwareManager(t *testing.T) {
+	t.Parallel()
+
+	server := framework.SharedKcpServer(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	orgClusterName := framework.NewOrganizationFixture(t, server)
+	workspaces := []logicalcluster.Name{
+		framework.NewWorkspaceFixture(t, server, orgClusterName, "Universal"),
+		framework.NewWorkspaceFixture(t, server, orgClusterName, "Universal"),
+	}
+
+	cfg := server.DefaultConfig(t)
+
+	kubeClusterClient, err := kubernetesclientset.NewClusterForConfig(cfg)
+	require.NoError(t, err)
+
+	t.Logf("Start a cluster-aware manager")
+	mgr, err := kcpctrl.NewClusterAwareManager(cfg, manager.Options{
+		Scheme:             clientgoscheme.Scheme,
+		MetricsBindAddress: "0",
+	})
+	require.NoError(t, err)
+
+	reconciler := kcpctrl.ReconcilerFunc(func(ctx context.Context, req kcpctrl.Request) (reconcile.Result, error) {
+		var cm corev1.ConfigMap
+		if err := mgr.GetClient().Get(ctx, req.NamespacedName, &cm); err != nil {
+			return reconcile.Result{}, client.IgnoreNotFound(err)
+		}
+		if _, ok := cm.Labels[watchedLabel]; !ok {
+			return reconcile.Result{}, nil
+		}
+		if cm.Annotations[reconciledAnnotation] == req.ClusterName.String() {
+			return reconcile.Result{}, nil
+		}
+
+		patch := client.MergeFrom(cm.DeepCopy())
+		if cm.Annotations == nil {
+			cm.Annotations = map[string]string{}
+		}
+		cm.Annotations[reconciledAnnotation] = req.ClusterName.String()
+		return reconcile.Result{}, mgr.GetClient().Patch(ctx, &cm, patch)
+	})
+	_, err = kcpctrl.NewController("e2e-configmaps", mgr, &corev1.ConfigMap{}, reconciler, controller.Options{})
+	require.NoError(t, err)
+
+	go func() {
+		if err := mgr.Start(ctx); err != nil {
+			t.Errorf("manager failed: %v", err)
+		}
+	}()
+
+	for _, ws := range workspaces {
+		t.Logf("Create a ConfigMap in workspace %q", ws)
+		_, err := kubeClusterClient.Cluster(ws).CoreV1().ConfigMaps("default").Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "reconcile-me",
+				Labels: map[string]string{watchedLabel: "true"},
+			},
+		}, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	for _, ws := range workspaces {
+		t.Logf("Wait for the ConfigMap in workspace %q to be reconciled in its own logical cluster", ws)
+		err := wait.PollImmediateWithContext(ctx, 100*time.Millisecond, wait.ForeverTestTimeout, func(ctx context.Context) (bool, error) {
+			cm, err := kubeClusterClient.Cluster(ws).CoreV1().ConfigMaps("default").Get(ctx, "reconcile-me", metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			return cm.Annotations[reconciledAnnotation] == ws.String(), nil
+		})
+		require.NoError(t, err)
+	}
+}
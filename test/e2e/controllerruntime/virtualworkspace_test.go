@@ -0,0 +1,202 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllerruntime
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kubernetesclientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	kcpclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
+	kcpctrl "github.com/kcp-dev/kcp/pkg/controllerruntime"
+	fixturewildwest "github.com/kcp-dev/kcp/test/e2e/fixtures/wildwest"
+	"github.com/kcp-dev/kcp/test/e2e/fixtures/wildwest/apis/wildwest"
+	wildwestv1alpha1 "github.com/kcp-dev/kcp/test/e2e/fixtures/wildwest/apis/wildwest/v1alpha1"
+	wildwestclientset "github.com/kcp-dev/kcp/test/e2e/fixtures/wildwest/client/clientset/versioned"
+	"github.com/kcp-dev/kcp/test/e2e/framework"
+	"github.com/kcp-dev/kcp/third_party/conditions/util/conditions"
+)
+
+// TestClusterAwareManagerThroughVirtualWorkspace runs a cluster-aware manager
+// against the syncer virtual workspace, which serves an API exported through
+// an APIExport for all workspaces binding it.
+func TestClusterAwareManagerThroughVirtualWorkspace(t *testing.T) {
+	t.Parallel()
+
+	server := framework.SharedKcpServer(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	orgClusterName := framework.NewOrganizationFixture(t, server)
+	providerWorkspace := framework.NewWorkspaceFixture(t, server, orgClusterName, "Universal")
+	consumerWorkspaces := []logicalcluster.Name{
+		framework.NewWorkspaceFixture(t, server, orgClusterName, "Universal"),
+		framework.NewWorkspaceFixture(t, server, orgClusterName, "Universal"),
+	}
+
+	cfg := server.DefaultConfig(t)
+	kcpClusterClient, err := kcpclient.NewClusterForConfig(cfg)
+	require.NoError(t, err)
+	kubeClusterClient, err := kubernetesclientset.NewClusterForConfig(cfg)
+	require.NoError(t, err)
+	wildwestClusterClient, err := wildwestclientset.NewClusterForConfig(cfg)
+	require.NoError(t, err)
+
+	workloadClusterName := fmt.Sprintf("wildwest-%d", rand.Intn(1000000))
+	t.Logf("Start a syncer for cowboys in the provider workspace %q", providerWorkspace)
+	_ = framework.SyncerFixture{
+		ResourcesToSync:      sets.NewString("cowboys.wildwest.dev"),
+		UpstreamServer:       server,
+		WorkspaceClusterName: providerWorkspace,
+		WorkloadClusterName:  workloadClusterName,
+		InstallCRDs: func(config *rest.Config, isLogicalCluster bool) {
+			sinkCrdClient, err := apiextensionsclientset.NewForConfig(config)
+			require.NoError(t, err)
+			fixturewildwest.Create(t, sinkCrdClient.ApiextensionsV1().CustomResourceDefinitions(), metav1.GroupResource{Group: wildwest.GroupName, Resource: "cowboys"})
+		},
+	}.Start(t)
+
+	t.Log("Create an APIExport for the imported cowboys API")
+	export := &apisv1alpha1.APIExport{ObjectMeta: metav1.ObjectMeta{Name: "wildwest"}}
+	_, err = kcpClusterClient.Cluster(providerWorkspace).ApisV1alpha1().APIExports().Create(ctx, export, metav1.CreateOptions{})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		export, err := kcpClusterClient.Cluster(providerWorkspace).ApisV1alpha1().APIExports().Get(ctx, export.Name, metav1.GetOptions{})
+		require.NoError(t, err)
+		return len(export.Spec.LatestResourceSchemas) > 0
+	}, wait.ForeverTestTimeout, 100*time.Millisecond, "APIResourceSchemas not added to the APIExport")
+
+	for _, ws := range consumerWorkspaces {
+		t.Logf("Bind the APIExport in consumer workspace %q", ws)
+		binding := &apisv1alpha1.APIBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "wildwest"},
+			Spec: apisv1alpha1.APIBindingSpec{
+				Reference: apisv1alpha1.ExportReference{
+					Workspace: &apisv1alpha1.WorkspaceExportReference{
+						WorkspaceName: providerWorkspace.Base(),
+						ExportName:    export.Name,
+					},
+				},
+			},
+		}
+		_, err = kcpClusterClient.Cluster(ws).ApisV1alpha1().APIBindings().Create(ctx, binding, metav1.CreateOptions{})
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			binding, err := kcpClusterClient.Cluster(ws).ApisV1alpha1().APIBindings().Get(ctx, binding.Name, metav1.GetOptions{})
+			require.NoError(t, err)
+			return conditions.IsTrue(binding, apisv1alpha1.InitialBindingCompleted)
+		}, wait.ForeverTestTimeout, 100*time.Millisecond, "APIBinding in %q not ready", ws)
+
+		_, err = kubeClusterClient.Cluster(ws).CoreV1().Namespaces().Patch(ctx, "default", types.StrategicMergePatchType, []byte(`{"metadata":{"labels":{"experimental.workloads.kcp.dev/scheduling-disabled":"true"}}}`), metav1.PatchOptions{})
+		require.NoError(t, err)
+
+		t.Logf("Create a cowboy synced to %q in consumer workspace %q", workloadClusterName, ws)
+		require.Eventually(t, func() bool {
+			_, err := wildwestClusterClient.Cluster(ws).WildwestV1alpha1().Cowboys("default").Create(ctx, &wildwestv1alpha1.Cowboy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "luckyluke",
+					Labels: map[string]string{"state.internal.workloads.kcp.dev/" + workloadClusterName: "Sync"},
+				},
+				Spec: wildwestv1alpha1.CowboySpec{Intent: "should catch joe"},
+			}, metav1.CreateOptions{})
+			if err != nil {
+				t.Logf("Failed to create cowboy: %v", err)
+			}
+			return err == nil
+		}, wait.ForeverTestTimeout, 100*time.Millisecond)
+	}
+
+	rawConfig, err := server.RawConfig()
+	require.NoError(t, err)
+	virtualWorkspaceRawConfig := rawConfig.DeepCopy()
+	virtualWorkspaceRawConfig.Clusters["wildwest"] = rawConfig.Clusters["system:admin"].DeepCopy()
+	virtualWorkspaceRawConfig.Clusters["wildwest"].Server += "/services/syncer/" + providerWorkspace.String() + "/" + workloadClusterName
+	virtualWorkspaceRawConfig.Contexts["wildwest"] = rawConfig.Contexts["system:admin"].DeepCopy()
+	virtualWorkspaceRawConfig.Contexts["wildwest"].Cluster = "wildwest"
+	virtualWorkspaceConfig, err := clientcmd.NewNonInteractiveClientConfig(*virtualWorkspaceRawConfig, "wildwest", nil, nil).ClientConfig()
+	require.NoError(t, err)
+
+	t.Logf("Start a cluster-aware manager against the virtual workspace %s", virtualWorkspaceConfig.Host)
+	scheme := runtime.NewScheme()
+	require.NoError(t, wildwestv1alpha1.AddToScheme(scheme))
+	require.Eventually(t, func() bool {
+		// the virtual workspace serves the API once the syncer is ready
+		_, err := wildwestclientset.NewForConfigOrDie(kcpctrl.ConfigForCluster(virtualWorkspaceConfig, logicalcluster.Wildcard)).WildwestV1alpha1().Cowboys("").List(ctx, metav1.ListOptions{})
+		return err == nil
+	}, wait.ForeverTestTimeout, 100*time.Millisecond, "virtual workspace does not serve cowboys")
+
+	mgr, err := kcpctrl.NewClusterAwareManager(virtualWorkspaceConfig, manager.Options{
+		Scheme:             scheme,
+		MetricsBindAddress: "0",
+	})
+	require.NoError(t, err)
+
+	reconciler := kcpctrl.ReconcilerFunc(func(ctx context.Context, req kcpctrl.Request) (reconcile.Result, error) {
+		var cowboy wildwestv1alpha1.Cowboy
+		if err := mgr.GetClient().Get(ctx, req.NamespacedName, &cowboy); err != nil {
+			return reconcile.Result{}, client.IgnoreNotFound(err)
+		}
+		result := "reconciled in " + req.ClusterName.String()
+		if cowboy.Status.Result == result {
+			return reconcile.Result{}, nil
+		}
+		patch := client.MergeFrom(cowboy.DeepCopy())
+		cowboy.Status.Result = result
+		return reconcile.Result{}, mgr.GetClient().Status().Patch(ctx, &cowboy, patch)
+	})
+	_, err = kcpctrl.NewController("e2e-cowboys", mgr, &wildwestv1alpha1.Cowboy{}, reconciler, controller.Options{})
+	require.NoError(t, err)
+
+	go func() {
+		if err := mgr.Start(ctx); err != nil {
+			t.Errorf("manager failed: %v", err)
+		}
+	}()
+
+	for _, ws := range consumerWorkspaces {
+		t.Logf("Wait for the cowboy in consumer workspace %q to be reconciled through the virtual workspace", ws)
+		err := wait.PollImmediateWithContext(ctx, 100*time.Millisecond, wait.ForeverTestTimeout, func(ctx context.Context) (bool, error) {
+			cowboy, err := wildwestClusterClient.Cluster(ws).WildwestV1alpha1().Cowboys("default").Get(ctx, "luckyluke", metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			return cowboy.Status.Result == "reconciled in "+ws.String(), nil
+		})
+		require.NoError(t, err)
+	}
+}
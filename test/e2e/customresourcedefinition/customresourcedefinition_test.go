@@ -19,11 +19,20 @@ package customresourcedefinition
 import (
 	"context"
 	"embed"
+	"encoding/json"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
+	"k8s.io/apiextensions-apiserver/pkg/apihelpers"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/restmapper"
@@ -65,3 +74,100 @@ func TestCustomResourceCreation(t *testing.T) {
 		t.Errorf("Expected an error due to reserved group")
 	}
 }
+
+// TestCustomResourceStructuralFeatures checks that CRDs created directly in a workspace support the
+// features of CRDs in Kubernetes, i.e. validation rules, status and scale subresources, and printer columns.
+func TestCustomResourceStructuralFeatures(t *testing.T) {
+	t.Parallel()
+
+	server := framework.SharedKcpServer(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	orgClusterName := framework.NewOrganizationFixture(t, server)
+	workspace := framework.NewWorkspaceFixture(t, server, orgClusterName, "Universal")
+
+	cfg := server.DefaultConfig(t)
+
+	crdClients, err := clientset.NewClusterForConfig(cfg)
+	require.NoError(t, err, "failed to construct apiextensions cluster client for server")
+
+	dynamicClients, err := dynamic.NewClusterForConfig(cfg)
+	require.NoError(t, err, "failed to construct dynamic cluster client for server")
+
+	t.Logf("Create the widgets CRD in workspace %q", workspace)
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(crdClients.Cluster(workspace).Discovery()))
+	err = helpers.CreateResourceFromFS(ctx, dynamicClients.Cluster(workspace), mapper, "example.dev_widgets.yaml", testFiles)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		crd, err := crdClients.Cluster(workspace).ApiextensionsV1().CustomResourceDefinitions().Get(ctx, "widgets.example.dev", metav1.GetOptions{})
+		if err != nil {
+			return false
+		}
+		return apihelpers.IsCRDConditionTrue(crd, apiextensionsv1.Established)
+	}, wait.ForeverTestTimeout, 100*time.Millisecond, "widgets CRD not established")
+
+	widgets := dynamicClients.Cluster(workspace).Resource(schema.GroupVersionResource{Group: "example.dev", Version: "v1", Resource: "widgets"})
+	newWidget := func(replicas, maxReplicas int64) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "example.dev/v1",
+			"kind":       "Widget",
+			"metadata":   map[string]interface{}{"name": "widget"},
+			"spec":       map[string]interface{}{"replicas": replicas, "maxReplicas": maxReplicas},
+		}}
+	}
+
+	t.Logf("Validation rules are enforced")
+	require.Eventually(t, func() bool {
+		_, err := widgets.Create(ctx, newWidget(3, 2), metav1.CreateOptions{})
+		return err != nil && strings.Contains(err.Error(), "replicas must not exceed maxReplicas")
+	}, wait.ForeverTestTimeout, 100*time.Millisecond, "expected the validation rule to reject the widget")
+
+	widget, err := widgets.Create(ctx, newWidget(1, 2), metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	t.Logf("The status subresource is served")
+	err = unstructured.SetNestedField(widget.Object, "Running", "status", "phase")
+	require.NoError(t, err)
+	err = unstructured.SetNestedField(widget.Object, int64(1), "status", "replicas")
+	require.NoError(t, err)
+	err = unstructured.SetNestedField(widget.Object, int64(2), "spec", "replicas")
+	require.NoError(t, err)
+	widget, err = widgets.UpdateStatus(ctx, widget, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	phase, _, err := unstructured.NestedString(widget.Object, "status", "phase")
+	require.NoError(t, err)
+	require.Equal(t, "Running", phase)
+	replicas, _, err := unstructured.NestedInt64(widget.Object, "spec", "replicas")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), replicas, "spec must not be changed through the status subresource")
+
+	t.Logf("The scale subresource is served")
+	scale, err := widgets.Get(ctx, "widget", metav1.GetOptions{}, "scale")
+	require.NoError(t, err)
+	specReplicas, _, err := unstructured.NestedInt64(scale.Object, "spec", "replicas")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), specReplicas)
+	statusReplicas, _, err := unstructured.NestedInt64(scale.Object, "status", "replicas")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), statusReplicas)
+
+	t.Logf("The printer columns are served")
+	raw, err := crdClients.Cluster(workspace).Discovery().RESTClient().Get().
+		AbsPath("/apis/example.dev/v1/widgets").
+		SetHeader("Accept", "application/json;as=Table;v=v1;g=meta.k8s.io").
+		DoRaw(ctx)
+	require.NoError(t, err)
+	table := &metav1.Table{}
+	err = json.Unmarshal(raw, table)
+	require.NoError(t, err)
+	var columns []string
+	for _, c := range table.ColumnDefinitions {
+		columns = append(columns, c.Name)
+	}
+	require.Contains(t, columns, "Replicas")
+	require.Contains(t, columns, "Phase")
+	require.Len(t, table.Rows, 1)
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package catalog
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	clientset "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
+	"github.com/kcp-dev/kcp/test/e2e/framework"
+)
+
+func TestCatalogVirtualWorkspace(t *testing.T) {
+	t.Parallel()
+
+	server := framework.SharedKcpServer(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	orgClusterName := framework.NewOrganizationFixture(t, server)
+	providerWorkspace := framework.NewWorkspaceFixture(t, server, orgClusterName, "Universal")
+	consumerWorkspace := framework.NewWorkspaceFixture(t, server, orgClusterName, "Universal")
+
+	cfg := server.DefaultConfig(t)
+
+	kcpClients, err := clientset.NewClusterForConfig(cfg)
+	require.NoError(t, err, "failed to construct kcp cluster client for server")
+
+	t.Logf("Publish an APIExport in the catalog of workspace %q", providerWorkspace)
+	_, err = kcpClients.Cluster(providerWorkspace).ApisV1alpha1().APIExports().Create(ctx, &apisv1alpha1.APIExport{
+		ObjectMeta: metav1.ObjectMeta{Name: "widgets"},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	_, err = kcpClients.Cluster(providerWorkspace).ApisV1alpha1().CatalogEntries().Create(ctx, &apisv1alpha1.CatalogEntry{
+		ObjectMeta: metav1.ObjectMeta{Name: "widgets"},
+		Spec:       apisv1alpha1.CatalogEntrySpec{ExportName: "widgets"},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	catalogConfig := func(cfg *rest.Config, workspace logicalcluster.Name) *rest.Config {
+		cfg = rest.CopyConfig(cfg)
+		cfg.Host += "/services/catalog/" + workspace.String()
+		return cfg
+	}
+
+	adminClient, err := clientset.NewForConfig(catalogConfig(cfg, consumerWorkspace))
+	require.NoError(t, err)
+	anonymousClient, err := clientset.NewForConfig(catalogConfig(rest.AnonymousClientConfig(cfg), consumerWorkspace))
+	require.NoError(t, err)
+
+	t.Logf("Make sure the admin sees the entry in the catalog of workspace %q", consumerWorkspace)
+	err = wait.PollImmediateWithContext(ctx, 100*time.Millisecond, wait.ForeverTestTimeout, func(ctx context.Context) (bool, error) {
+		entries, err := adminClient.ApisV1alpha1().CatalogEntries().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			t.Logf("error listing the catalog: %v", err)
+			return false, nil
+		}
+		for _, entry := range entries.Items {
+			if entry.Name == "widgets" && logicalcluster.From(&entry) == providerWorkspace {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	require.NoError(t, err)
+
+	t.Logf("Make sure an anonymous user can read the catalog, without the entries it is not allowed to bind to")
+	entries, err := anonymousClient.ApisV1alpha1().CatalogEntries().List(ctx, metav1.ListOptions{})
+	require.NoError(t, err, "anonymous users should be able to list the catalog")
+	require.Empty(t, entries.Items)
+
+	t.Logf("Make sure an anonymous user cannot write through the catalog")
+	_, err = anonymousClient.ApisV1alpha1().CatalogEntries().Create(ctx, &apisv1alpha1.CatalogEntry{
+		ObjectMeta: metav1.ObjectMeta{Name: "intruder"},
+		Spec:       apisv1alpha1.CatalogEntrySpec{ExportName: "widgets"},
+	}, metav1.CreateOptions{})
+	require.True(t, errors.IsForbidden(err), "expected forbidden, got %v", err)
+
+	t.Logf("Make sure an anonymous user cannot reach the other virtual workspaces")
+	anonymousRBACClient, err := clientset.NewForConfig(func() *rest.Config {
+		cfg := rest.AnonymousClientConfig(cfg)
+		cfg.Host += "/services/rbac/" + consumerWorkspace.String()
+		return cfg
+	}())
+	require.NoError(t, err)
+	_, err = anonymousRBACClient.Discovery().ServerGroups()
+	require.True(t, errors.IsUnauthorized(err), "expected unauthorized, got %v", err)
+}